@@ -42,6 +42,10 @@ type CreateUserRequest struct {
 	// DisableVerificationEmail whether to disable sending
 	// verification email to user after account creation
 	DisableVerificationEmail bool `json:"disable_verification_email"`
+
+	// InviteToken the signed group invitation token from the link
+	// the user followed to sign up, accepted once the user is created
+	InviteToken string `json:"invite_token,omitempty"`
 }
 
 // CreateEmailVerificationTokenRequest holds the data required for a user request
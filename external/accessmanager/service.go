@@ -131,6 +131,7 @@ type GroupService interface {
 	GetParentGroupsWithAutoJoinForEmail(ctx context.Context, email string) (*group.GetParentGroupsWithAutoJoinForEmailResponse, error)
 	AddMember(ctx context.Context, req *group.AddMemberRequest) (*group.AddMemberResponse, error)
 	InviteUser(ctx context.Context, req *group.InviteUserRequest) (*group.InviteUserResponse, error)
+	AcceptInviteByToken(ctx context.Context, req *group.AcceptInviteByTokenRequest) (*group.AcceptInviteByTokenResponse, error)
 }
 
 // Service holds and manages accessmanager service business logic
@@ -1856,6 +1857,21 @@ func (s *Service) CreateUser(ctx context.Context, r *CreateUserRequest) (*Create
 		}
 	}
 
+	// handle accepting the group invitation the user signed up from, failures do not block sign up
+	if s.GroupService != nil && r.InviteToken != "" {
+		logger.Info("accepting-group-invitation-from-signup", zap.String("user-id", newUser.User.ID))
+		acceptResp, acceptErr := s.GroupService.AcceptInviteByToken(ctx, &group.AcceptInviteByTokenRequest{
+			Token:     r.InviteToken,
+			UserID:    newUser.User.ID,
+			UserEmail: newUser.User.Email,
+		})
+		if acceptErr != nil {
+			logger.Error("failed-to-accept-group-invitation-from-signup", zap.String("user-id", newUser.User.ID), zap.Error(acceptErr))
+		} else if acceptResp.Group != nil {
+			logger.Info("successfully-accepted-group-invitation-from-signup", zap.String("user-id", newUser.User.ID), zap.String("group-id", acceptResp.Group.ID))
+		}
+	}
+
 	// handle verification email if not disabled
 	if !r.DisableVerificationEmail {
 		logger.Info("initiate-new-user-verification-email", zap.String("user-id", newUser.User.ID))
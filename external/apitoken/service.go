@@ -125,12 +125,17 @@ func (s *Service) CreateAPIToken(ctx context.Context, r *CreateAPITokenRequest)
 // ExtractValidateUserAPITokenMetadata retrieves data from passed user api token
 // TODO: Create tests
 func (s *Service) ExtractValidateUserAPITokenMetadata(ctx context.Context, r *http.Request) (*APITokenRequester, error) {
+	// secret identifers from headers
+	return s.ValidateAPIToken(ctx, r.Header.Get(common.SystemWideXApiToken))
+}
+
+// ValidateAPIToken checks a full `<nanoId>.<secret>` token against the active tokens
+// created for the nano ID, returning the requester with UserID set to the token's creator.
+// It lets tokens be read from somewhere other than the X-Api-Token header, e.g. a bearer token.
+func (s *Service) ValidateAPIToken(ctx context.Context, userFullToken string) (*APITokenRequester, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/apitoken")
 	var requester APITokenRequester
 
-	// secret identifers from headers
-	userFullToken := r.Header.Get(common.SystemWideXApiToken)
-
 	splittedToken := strings.Split(userFullToken, ".")
 
 	if len(splittedToken) != 2 {
@@ -175,6 +180,7 @@ func (s *Service) ExtractValidateUserAPITokenMetadata(ctx context.Context, r *ht
 		res := bytes.Compare(token.ValueSHA, requester.UserAPITokenEncoded)
 		if res == 0 {
 			if token.Status == UserTokenStatusKeyActive {
+				requester.UserID = token.CreatedByID
 				requester.IsValid = true
 				return &requester, nil
 			}
@@ -20,8 +20,8 @@ const defaultCollectionInitMaxAttemptsLimit = 3
 type MongoDbStore interface {
 	ExecuteInsertOneCommand(ctx context.Context, collection *mongo.Collection, document interface{}, resultObjectName string) (*mongo.InsertOneResult, error)
 	ExecuteCountDocuments(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error)
+	ExecuteFindCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
 	// ExecuteDeleteOneCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, targetObjectName string) error
-	// ExecuteFindCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
 	// ExecuteUpdateOneCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, updateFilter interface{}, resultObjectName string) error
 	// ExecuteDeleteManyCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, targetObjectName string) error
 	// ExecuteFindOneCommandDecodeResult(ctx context.Context, collection *mongo.Collection, filter interface{}, result interface{}, resultObjectName string, logError bool, onFailureErr error) error
@@ -159,6 +159,75 @@ func (r *Repository) GetTotalAuditLogEvents(ctx context.Context, userId string,
 	return r.Store.ExecuteCountDocuments(ctx, collection, auditFilter)
 }
 
+// GetAuditLogEvents returns log entries matching the passed arguments, newest first. Entries
+// are ordered by action_at then id so the last entry returned can be used to fetch the next page
+func (r *Repository) GetAuditLogEvents(ctx context.Context, req *GetAuditLogEventsRequest) ([]AuditLogEntry, error) {
+
+	auditFilter := bson.M{}
+
+	if len(req.Domains) > 0 {
+		auditFilter["domain"] = bson.M{"$in": req.Domains}
+	}
+
+	if len(req.TargetIds) > 0 {
+		auditFilter["target_id"] = bson.M{"$in": req.TargetIds}
+	}
+
+	if req.ActorId != "" {
+		auditFilter["actor_id"] = req.ActorId
+	}
+
+	if len(req.Actions) > 0 {
+		auditFilter["action"] = bson.M{"$in": req.Actions}
+	}
+
+	if req.To != "" || req.From != "" {
+
+		timeRangeFilter := bson.M{}
+
+		if req.From != "" {
+			timeRangeFilter["$gte"] = req.From
+		}
+
+		if req.To != "" {
+			timeRangeFilter["$lt"] = req.To
+		}
+
+		auditFilter["action_at"] = timeRangeFilter
+	}
+
+	// continue after the last entry of the previous page
+	if req.BeforeActionAt != "" {
+		auditFilter["$or"] = bson.A{
+			bson.M{"action_at": bson.M{"$lt": req.BeforeActionAt}},
+			bson.M{"action_at": req.BeforeActionAt, "_id": bson.M{"$lt": req.BeforeId}},
+		}
+	}
+
+	collection, err := r.GetAuditCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "action_at", Value: -1}, {Key: "_id", Value: -1}})
+	if req.Limit > 0 {
+		findOptions.SetLimit(int64(req.Limit))
+	}
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, auditFilter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []AuditLogEntry{}
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &entries, "Audit-Log")
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 // CreateAuditLogEvent creates an log event in the DB
 func (r *Repository) CreateAuditLogEvent(ctx context.Context, event *AuditLogEntry) error {
 
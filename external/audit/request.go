@@ -37,3 +37,32 @@ type GetTotalAuditLogEventsRequest struct {
 	TargetId    string
 	TargetTypes []TargetType
 }
+
+// GetAuditLogEventsRequest is holding attributes needed to list audit log entries
+type GetAuditLogEventsRequest struct {
+	// Domains limits entries to the domains they are sourced from
+	Domains []string
+
+	// TargetIds limits entries to those targeting any of the resources
+	TargetIds []string
+
+	// ActorId limits entries to those carried out by the entity
+	ActorId string
+
+	// Actions limits entries to the actions
+	Actions []AuditAction
+
+	// From is the inclusive lower bound of action_at
+	From string
+
+	// To is the exclusive upper bound of action_at
+	To string
+
+	// BeforeActionAt and BeforeId are the action_at and id of the last entry of the
+	// previous page, only older entries are returned when set
+	BeforeActionAt string
+	BeforeId       string
+
+	// Limit is the maximum number of entries returned, 0 returns every entry
+	Limit int
+}
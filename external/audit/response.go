@@ -0,0 +1,6 @@
+package audit
+
+// GetAuditLogEventsResponse holds the audit log entries matching a request
+type GetAuditLogEventsResponse struct {
+	Entries []AuditLogEntry
+}
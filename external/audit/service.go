@@ -12,6 +12,7 @@ import (
 type AuditRespository interface {
 	CreateAuditLogEvent(ctx context.Context, event *AuditLogEntry) error
 	GetTotalAuditLogEvents(ctx context.Context, userId string, to string, from string, domains string, actions []AuditAction, targetId string, targetTypes []TargetType) (int64, error)
+	GetAuditLogEvents(ctx context.Context, req *GetAuditLogEventsRequest) ([]AuditLogEntry, error)
 }
 
 // Service holds and manages audit business logic
@@ -64,3 +65,17 @@ func (s *Service) GetTotalAuditLogEvents(ctx context.Context, r *GetTotalAuditLo
 
 	return s.AuditRespository.GetTotalAuditLogEvents(ctx, r.UserId, r.To, r.From, r.Domains, r.Actions, r.TargetId, r.TargetTypes)
 }
+
+// GetAuditLogEvents gets the audit log entries matching the passed values, newest first
+func (s *Service) GetAuditLogEvents(ctx context.Context, r *GetAuditLogEventsRequest) (*GetAuditLogEventsResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/audit", "get-audit-log-events")
+	logger.Debug("handling-get-audit-log-events-request")
+
+	entries, err := s.AuditRespository.GetAuditLogEvents(ctx, r)
+	if err != nil {
+		logger.Error("failed-to-get-audit-log-events", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetAuditLogEventsResponse{Entries: entries}, nil
+}
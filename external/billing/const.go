@@ -1,6 +1,10 @@
 package billing
 
-import "github.com/ooaklee/ghatd/external/audit"
+import (
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+)
 
 const (
 	// AuditActionBillingSubscriptionUpdateUserID is used when a subscription's user ID is updated
@@ -91,6 +95,48 @@ const (
 
 	// ErrKeyBillingUpdateUserIDFailed is returned when updating user ID fails
 	ErrKeyBillingUpdateUserIDFailed = "BillingUpdateUserIDFailed"
+
+	// ErrKeyBillingWebhookDeliveryNotFound is returned when a webhook delivery cannot be found in the inbox
+	ErrKeyBillingWebhookDeliveryNotFound = "BillingWebhookDeliveryNotFound"
+
+	// ErrKeyBillingWebhookDeliveryAlreadyExists is returned when a webhook delivery with the same key is already in the inbox
+	ErrKeyBillingWebhookDeliveryAlreadyExists = "BillingWebhookDeliveryAlreadyExists"
+
+	// ErrKeyBillingWebhookDeliveryAlreadyClaimed is returned when a webhook delivery is being processed
+	// by another worker or was already processed
+	ErrKeyBillingWebhookDeliveryAlreadyClaimed = "BillingWebhookDeliveryAlreadyClaimed"
+
+	// ErrKeyBillingWebhookInboxNotConfigured is returned when webhook inbox operations are requested without a repository
+	ErrKeyBillingWebhookInboxNotConfigured = "BillingWebhookInboxNotConfigured"
+
+	// ErrKeyBillingInvalidRevenueMetricsPeriod is returned when revenue metrics are requested for a period
+	// that is not a valid RFC3339 range
+	ErrKeyBillingInvalidRevenueMetricsPeriod = "BillingInvalidRevenueMetricsPeriod"
+)
+
+const (
+	// IntegratorManual is the integrator of subscriptions granted by an admin rather than
+	// bought through a payment provider, such as complimentary subscriptions
+	IntegratorManual = "manual"
+
+	// ScheduledChangeActionCancel is the scheduled change of a subscription cancelled at the period end
+	ScheduledChangeActionCancel = "cancel"
+
+	// ScheduledChangeActionPause is the scheduled change of a subscription paused at the period end
+	ScheduledChangeActionPause = "pause"
+
+	// revenueMetricsPageSize is the number of subscriptions loaded per page while aggregating revenue
+	revenueMetricsPageSize = 100
+
+	// defaultRevenueMetricsPeriod is the period churn is measured over when none is requested
+	defaultRevenueMetricsPeriod = 30 * 24 * time.Hour
+
+	// revenueMetricsUnknownPlanName is the plan active subscriptions without a plan name are counted under
+	revenueMetricsUnknownPlanName = "unknown"
+
+	// webhookDeliveryClaimLease is how long a claimed webhook delivery stays with its worker before
+	// another worker may claim it, so deliveries left processing by a crash are not stuck
+	webhookDeliveryClaimLease = 5 * time.Minute
 )
 
 // Subscription status constants
@@ -119,3 +165,18 @@ const (
 	// StatusUnpaid indicates an unpaid subscription
 	StatusUnpaid = "unpaid"
 )
+
+// Webhook delivery status constants
+const (
+	// WebhookDeliveryStatusReceived indicates a delivery stored but not yet processed
+	WebhookDeliveryStatusReceived = "received"
+
+	// WebhookDeliveryStatusProcessing indicates a delivery claimed by a worker that is processing it
+	WebhookDeliveryStatusProcessing = "processing"
+
+	// WebhookDeliveryStatusProcessed indicates a delivery processed successfully
+	WebhookDeliveryStatusProcessed = "processed"
+
+	// WebhookDeliveryStatusFailed indicates a delivery whose latest processing attempt failed
+	WebhookDeliveryStatusFailed = "failed"
+)
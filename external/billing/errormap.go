@@ -31,4 +31,9 @@ var BillingErrorMap reply.ErrorManifest = reply.ErrorManifest{
 	ErrBillingAssociationFailed:                {Title: "Internal Server Error", Detail: "Failed to associate subscriptions with user", StatusCode: 500, Code: "BIL00-024"},
 	ErrBillingNoUnassociatedSubscriptionsFound: {Title: "Not Found", Detail: "No unassociated subscriptions found", StatusCode: 404, Code: "BIL00-025"},
 	ErrBillingUpdateUserIDFailed:               {Title: "Internal Server Error", Detail: "Failed to update subscription user ID", StatusCode: 500, Code: "BIL00-026"},
+	ErrBillingWebhookDeliveryNotFound:          {Title: "Not Found", Detail: "Webhook delivery not found", StatusCode: 404, Code: "BIL00-027"},
+	ErrBillingWebhookDeliveryAlreadyExists:     {Title: "Conflict", Detail: "Webhook delivery already recorded", StatusCode: 409, Code: "BIL00-028"},
+	ErrBillingWebhookInboxNotConfigured:        {Title: "Internal Server Error", Detail: "Webhook inbox is not configured", StatusCode: 500, Code: "BIL00-029"},
+	ErrBillingInvalidRevenueMetricsPeriod:      {Title: "Bad Request", Detail: "Revenue metrics period must be a valid RFC3339 date range", StatusCode: 400, Code: "BIL00-030"},
+	ErrBillingWebhookDeliveryAlreadyClaimed:    {Title: "Conflict", Detail: "Webhook delivery is already being processed", StatusCode: 409, Code: "BIL00-031"},
 }
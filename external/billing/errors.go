@@ -18,6 +18,7 @@ var (
 	ErrBillingInvalidCurrency                  = errors.New(ErrKeyBillingInvalidCurrency)
 	ErrBillingInvalidEmail                     = errors.New(ErrKeyBillingInvalidEmail)
 	ErrBillingInvalidIntegrator                = errors.New(ErrKeyBillingInvalidIntegrator)
+	ErrBillingInvalidRevenueMetricsPeriod      = errors.New(ErrKeyBillingInvalidRevenueMetricsPeriod)
 	ErrBillingInvalidStatus                    = errors.New(ErrKeyBillingInvalidStatus)
 	ErrBillingInvalidSubscriptionID            = errors.New(ErrKeyBillingInvalidSubscriptionID)
 	ErrBillingInvalidUserID                    = errors.New(ErrKeyBillingInvalidUserID)
@@ -29,4 +30,8 @@ var (
 	ErrBillingSubscriptionNotFound             = errors.New(ErrKeyBillingSubscriptionNotFound)
 	ErrBillingUnauthorisedAccess               = errors.New(ErrKeyBillingUnauthorisedAccess)
 	ErrBillingUpdateUserIDFailed               = errors.New(ErrKeyBillingUpdateUserIDFailed)
+	ErrBillingWebhookDeliveryAlreadyClaimed    = errors.New(ErrKeyBillingWebhookDeliveryAlreadyClaimed)
+	ErrBillingWebhookDeliveryAlreadyExists     = errors.New(ErrKeyBillingWebhookDeliveryAlreadyExists)
+	ErrBillingWebhookDeliveryNotFound          = errors.New(ErrKeyBillingWebhookDeliveryNotFound)
+	ErrBillingWebhookInboxNotConfigured        = errors.New(ErrKeyBillingWebhookInboxNotConfigured)
 )
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitBillingSubscriptionGroupIndexesUp initializes the group index for the billing subscriptions collection
+func InitBillingSubscriptionGroupIndexesUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	const mongoCollectionName = billing.BillingSubscriptionsCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-billing-subscriptions-group-indexes"))
+
+	// Sparse index on group_id for group-owned subscription lookups
+	groupIdIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "group_id", Value: 1}},
+		Options: options.Index().SetName("idx_subscriptions_group_id").SetSparse(true),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateOne(context.Background(), groupIdIndexModel)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-billing-subscriptions-group-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-billing-subscriptions-group-indexes"))
+	return nil
+}
+
+// InitBillingSubscriptionGroupIndexesDown rolls back the billing subscriptions group index
+func InitBillingSubscriptionGroupIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = billing.BillingSubscriptionsCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-billing-subscriptions-group-indexes"))
+
+	err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), "idx_subscriptions_group_id")
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: idx_subscriptions_group_id"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-billing-subscriptions-group-indexes"))
+	return nil
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitBillingSubscriptionPricePlanVersionIndexesUp initializes the price plan version index for the billing subscriptions collection
+func InitBillingSubscriptionPricePlanVersionIndexesUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	const mongoCollectionName = billing.BillingSubscriptionsCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-billing-subscriptions-price-plan-version-indexes"))
+
+	// Sparse index on price_plan_version_id for finding the subscribers of a plan version
+	pricePlanVersionIdIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "price_plan_version_id", Value: 1}},
+		Options: options.Index().SetName("idx_subscriptions_price_plan_version_id").SetSparse(true),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateOne(context.Background(), pricePlanVersionIdIndexModel)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-billing-subscriptions-price-plan-version-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-billing-subscriptions-price-plan-version-indexes"))
+	return nil
+}
+
+// InitBillingSubscriptionPricePlanVersionIndexesDown rolls back the billing subscriptions price plan version index
+func InitBillingSubscriptionPricePlanVersionIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = billing.BillingSubscriptionsCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-billing-subscriptions-price-plan-version-indexes"))
+
+	err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), "idx_subscriptions_price_plan_version_id")
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: idx_subscriptions_price_plan_version_id"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-billing-subscriptions-price-plan-version-indexes"))
+	return nil
+}
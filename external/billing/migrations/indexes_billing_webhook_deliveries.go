@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func InitBillingWebhookDeliveriesIndexesUp(db *mongo.Database) error { //Up
+
+	const mongoCollectionName = billing.BillingWebhookDeliveriesCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-billing-webhook-deliveries-indexes"))
+
+	// The inbox key (integrator + event ID) is the document _id, so duplicates
+	// are already rejected. These indexes support the admin failed-delivery listing.
+	statusCreatedAtIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("idx_billing_webhook_deliveries_status_created_at"),
+	}
+
+	integratorStatusIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "integrator", Value: 1}, {Key: "status", Value: 1}},
+		Options: options.Index().SetName("idx_billing_webhook_deliveries_integrator_status"),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			statusCreatedAtIndexModel,
+			integratorStatusIndexModel,
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-billing-webhook-deliveries-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-billing-webhook-deliveries-indexes"))
+	return nil
+
+}
+
+func InitBillingWebhookDeliveriesIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = billing.BillingWebhookDeliveriesCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-billing-webhook-deliveries-indexes"))
+
+	indexNames := []string{
+		"idx_billing_webhook_deliveries_status_created_at",
+		"idx_billing_webhook_deliveries_integrator_status",
+	}
+
+	for _, indexName := range indexNames {
+		err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), indexName)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: "+indexName))
+			return err
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-billing-webhook-deliveries-indexes"))
+	return nil
+}
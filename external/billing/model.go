@@ -14,6 +14,10 @@ type Subscription struct {
 	// UserID is the platform user's ID
 	UserID string `json:"user_id" bson:"user_id"`
 
+	// GroupID is the platform group's ID when the subscription is owned by a group
+	// rather than an individual user
+	GroupID string `json:"group_id,omitempty" bson:"group_id,omitempty"`
+
 	// Email is the customer's email address
 	Email string `json:"email" bson:"email"`
 
@@ -35,6 +39,19 @@ type Subscription struct {
 	// PlanID is the provider's plan identifier
 	PlanID string `json:"plan_id" bson:"plan_id,omitempty"`
 
+	// PricePlanID is the ID of the pricer plan the subscription was bought on
+	PricePlanID string `json:"price_plan_id,omitempty" bson:"price_plan_id,omitempty"`
+
+	// PricePlanVersionID is the ID of the exact pricer plan version the subscription
+	// was bought on, whose terms the subscription keeps until it is migrated
+	PricePlanVersionID string `json:"price_plan_version_id,omitempty" bson:"price_plan_version_id,omitempty"`
+
+	// PricePlanVersion is the number of the pricer plan version the subscription was bought on
+	PricePlanVersion int `json:"price_plan_version,omitempty" bson:"price_plan_version,omitempty"`
+
+	// PromotionCode is the pricer promotion code redeemed when the subscription was bought
+	PromotionCode string `json:"promotion_code,omitempty" bson:"promotion_code,omitempty"`
+
 	// Amount is the subscription amount (in cents)
 	Amount int64 `json:"amount" bson:"amount"`
 
@@ -44,6 +61,9 @@ type Subscription struct {
 	// BillingInterval is the billing frequency (month, year, etc.)
 	BillingInterval string `json:"billing_interval" bson:"billing_interval,omitempty"`
 
+	// Quantity is the number of units (seats) purchased on the subscription
+	Quantity int64 `json:"quantity,omitempty" bson:"quantity,omitempty"`
+
 	// NextBillingDate is when the next payment will be attempted
 	NextBillingDate *time.Time `json:"next_billing_date,omitempty" bson:"next_billing_date,omitempty"`
 
@@ -62,12 +82,24 @@ type Subscription struct {
 	// ProviderUpdatedAt is when the subscription was last updated
 	ProviderUpdatedAt time.Time `json:"provider_updated_at" bson:"provider_updated_at"`
 
+	// LastProviderEventTime is the provider event time of the latest webhook
+	// applied to the subscription, used to ignore out-of-order deliveries
+	LastProviderEventTime *time.Time `json:"last_provider_event_time,omitempty" bson:"last_provider_event_time,omitempty"`
+
 	// CancelURL is the provider's cancellation URL
 	CancelURL string `json:"cancel_url,omitempty" bson:"cancel_url,omitempty"`
 
 	// UpdateURL is the provider's update payment method URL
 	UpdateURL string `json:"update_url,omitempty" bson:"update_url,omitempty"`
 
+	// Dunning tracks the recovery of a failed payment while the subscription is past due or unpaid.
+	// It is stored as null rather than omitted so clearing it on recovery is persisted
+	Dunning *SubscriptionDunning `json:"dunning,omitempty" bson:"dunning"`
+
+	// ScheduledChange is a cancellation or pause requested for the end of the current billing period.
+	// It is stored as null rather than omitted so clearing it once the change takes effect is persisted
+	ScheduledChange *SubscriptionScheduledChange `json:"scheduled_change,omitempty" bson:"scheduled_change"`
+
 	// Metadata stores additional provider-specific data
 	Metadata map[string]interface{} `json:"metadata,omitempty" bson:"metadata,omitempty"`
 
@@ -78,6 +110,36 @@ type Subscription struct {
 	UpdatedAt string `json:"updated_at" bson:"updated_at"`
 }
 
+// SubscriptionDunning holds the progress of the dunning workflow for a subscription
+type SubscriptionDunning struct {
+	// StartedAt is when dunning started after the subscription's payment failed
+	StartedAt time.Time `json:"started_at" bson:"started_at"`
+
+	// StepsCompleted is the number of steps of the dunning schedule already carried out
+	StepsCompleted int `json:"steps_completed" bson:"steps_completed"`
+
+	// LastStepAt is when the latest dunning step was carried out
+	LastStepAt *time.Time `json:"last_step_at,omitempty" bson:"last_step_at,omitempty"`
+
+	// GracePeriodEndsAt is when access is suspended unless payment recovers
+	GracePeriodEndsAt time.Time `json:"grace_period_ends_at" bson:"grace_period_ends_at"`
+
+	// SuspendedAt is when access was suspended after the grace period ended
+	SuspendedAt *time.Time `json:"suspended_at,omitempty" bson:"suspended_at,omitempty"`
+}
+
+// SubscriptionScheduledChange holds a change that takes effect on a subscription at a later date
+type SubscriptionScheduledChange struct {
+	// Action is the change that is scheduled (cancel or pause)
+	Action string `json:"action" bson:"action"`
+
+	// EffectiveAt is when the change takes effect, when the payment provider said
+	EffectiveAt *time.Time `json:"effective_at,omitempty" bson:"effective_at,omitempty"`
+
+	// RequestedAt is when the change was requested
+	RequestedAt time.Time `json:"requested_at" bson:"requested_at"`
+}
+
 // IsActive returns true if the subscription is currently active
 func (s *Subscription) IsActive() bool {
 	return s.Status == StatusActive || s.Status == StatusTrialing
@@ -93,6 +155,51 @@ func (s *Subscription) IsInGoodStanding() bool {
 	return s.Status == StatusActive || s.Status == StatusTrialing
 }
 
+// IsPaymentFailing returns true if the subscription is past due or unpaid
+func (s *Subscription) IsPaymentFailing() bool {
+	return s.Status == StatusPastDue || s.Status == StatusUnpaid
+}
+
+// IsInDunningGracePeriod returns true if the subscription's payment is failing but dunning
+// has not yet suspended it, so access continues while the customer updates their payment method
+func (s *Subscription) IsInDunningGracePeriod() bool {
+	return s.IsPaymentFailing() && s.Dunning != nil && s.Dunning.SuspendedAt == nil
+}
+
+// IsComplimentary returns true if the subscription was granted by an admin rather than
+// bought through a payment provider
+func (s *Subscription) IsComplimentary() bool {
+	return s.Integrator == IntegratorManual
+}
+
+// IsGroupOwned returns true if the subscription is owned by a group
+func (s *Subscription) IsGroupOwned() bool {
+	return s.GroupID != ""
+}
+
+// Seats returns the number of seats purchased on the subscription, treating
+// subscriptions without an explicit quantity as a single seat
+func (s *Subscription) Seats() int64 {
+	if s.Quantity < 1 {
+		return 1
+	}
+
+	return s.Quantity
+}
+
+// GroupSeats totals the seats purchased across the group-owned subscriptions provided that are
+// active or in their dunning grace period
+func GroupSeats(subscriptions []Subscription) int {
+	var seats int64
+	for _, subscription := range subscriptions {
+		if subscription.IsGroupOwned() && (subscription.IsActive() || subscription.IsInDunningGracePeriod()) {
+			seats += subscription.Seats()
+		}
+	}
+
+	return int(seats)
+}
+
 // DaysUntilNextBilling returns the number of days until the next billing date
 func (s *Subscription) DaysUntilNextBilling() int {
 	if s.NextBillingDate == nil {
@@ -153,6 +260,10 @@ type BillingEvent struct {
 	// IntegratorSubscriptionID is the provider's subscription ID
 	IntegratorSubscriptionID string `json:"integrator_subscription_id" bson:"integrator_subscription_id"`
 
+	// IntegratorTransactionID is the provider's ID for the payment (e.g., Stripe payment intent,
+	// Paddle transaction), used to refund it
+	IntegratorTransactionID string `json:"integrator_transaction_id,omitempty" bson:"integrator_transaction_id,omitempty"`
+
 	// Status is the event status (active, trialing, past_due, etc.)
 	Status string `json:"status" bson:"status"`
 
@@ -226,3 +337,77 @@ func (e *BillingEvent) SetUpdatedAtTimeToNow() *BillingEvent {
 
 	return e
 }
+
+// WebhookDelivery represents a verified payment provider webhook held in the
+// inbox so that provider retries can be deduplicated and failures replayed
+type WebhookDelivery struct {
+	// ID is the inbox key, built from the integrator and its event ID
+	ID string `json:"id" bson:"_id"`
+
+	// Integrator is the payment provider name
+	Integrator string `json:"integrator" bson:"integrator"`
+
+	// IntegratorEventID is the provider's event ID
+	IntegratorEventID string `json:"integrator_event_id" bson:"integrator_event_id"`
+
+	// IntegratorSubscriptionID is the provider's subscription ID, if any
+	IntegratorSubscriptionID string `json:"integrator_subscription_id,omitempty" bson:"integrator_subscription_id,omitempty"`
+
+	// EventType is the normalised event type
+	EventType string `json:"event_type" bson:"event_type"`
+
+	// Status is the processing status (received, processed, failed)
+	Status string `json:"status" bson:"status"`
+
+	// Attempts is the number of times processing has been attempted
+	Attempts int `json:"attempts" bson:"attempts"`
+
+	// LastError is the error returned by the latest failed attempt
+	LastError string `json:"last_error,omitempty" bson:"last_error,omitempty"`
+
+	// Payload is the JSON encoded normalised webhook payload used for replays
+	Payload string `json:"payload" bson:"payload"`
+
+	// ProviderEventTime is when the event occurred at the provider
+	ProviderEventTime time.Time `json:"provider_event_time" bson:"provider_event_time"`
+
+	// ProcessedAt is when the delivery was last processed successfully
+	ProcessedAt string `json:"processed_at,omitempty" bson:"processed_at,omitempty"`
+
+	// CreatedAt is when the delivery was stored in internal system
+	CreatedAt string `json:"created_at" bson:"created_at"`
+
+	// UpdatedAt is when the delivery was last updated in internal system
+	UpdatedAt string `json:"updated_at" bson:"updated_at"`
+}
+
+// WebhookDeliveryID returns the inbox key for a provider event. Events
+// without a provider event ID are given a unique key so they are still kept.
+func WebhookDeliveryID(integrator, integratorEventID string) string {
+	if integratorEventID == "" {
+		return integrator + ":" + toolbox.GenerateUuidV4()
+	}
+
+	return integrator + ":" + integratorEventID
+}
+
+// IsProcessed returns true if the delivery has been processed successfully
+func (d *WebhookDelivery) IsProcessed() bool {
+	return d.Status == WebhookDeliveryStatusProcessed
+}
+
+// SetCreatedAtTimeToNow sets the created at date and time for the delivery to now
+func (d *WebhookDelivery) SetCreatedAtTimeToNow() *WebhookDelivery {
+
+	d.CreatedAt = toolbox.TimeNowUTC()
+
+	return d
+}
+
+// SetUpdatedAtTimeToNow sets the updated at date and time for the delivery to now
+func (d *WebhookDelivery) SetUpdatedAtTimeToNow() *WebhookDelivery {
+
+	d.UpdatedAt = toolbox.TimeNowUTC()
+
+	return d
+}
@@ -2,6 +2,7 @@ package billing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -18,6 +19,9 @@ const BillingEventsCollection string = "billing_events"
 // BillingSubscriptionsCollection collection name for billing subscriptions
 const BillingSubscriptionsCollection string = "billing_subscriptions"
 
+// BillingWebhookDeliveriesCollection collection name for the billing webhook inbox
+const BillingWebhookDeliveriesCollection string = "billing_webhook_deliveries"
+
 const defaultCollectionInitMaxAttemptsLimit = 3
 
 // MongoDbStore represents the datastore to hold resource data
@@ -49,6 +53,9 @@ type Repository struct {
 	billingEventsCollectionMutex sync.Mutex
 	subscriptionsCollection      *mongo.Collection
 	subscriptionsCollectionMutex sync.Mutex
+
+	webhookDeliveriesCollection      *mongo.Collection
+	webhookDeliveriesCollectionMutex sync.Mutex
 }
 
 // NewRepository initiates new instance of repository
@@ -136,6 +143,40 @@ func (r *Repository) GetBillingSubscriptionsCollection(ctx context.Context) (*mo
 	return nil, fmt.Errorf("unable to initialise %s collection after %d attempts: %w", BillingSubscriptionsCollection, collectionInitMaxAttemptsLimit, lastErr)
 }
 
+// GetBillingWebhookDeliveriesCollection returns a collection used for the webhook inbox
+func (r *Repository) GetBillingWebhookDeliveriesCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.webhookDeliveriesCollectionMutex.Lock()
+	defer r.webhookDeliveriesCollectionMutex.Unlock()
+
+	if r.webhookDeliveriesCollection != nil {
+		return r.webhookDeliveriesCollection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.webhookDeliveriesCollection = db.Collection(BillingWebhookDeliveriesCollection)
+		return r.webhookDeliveriesCollection, nil
+	}
+
+	return nil, fmt.Errorf("unable to initialise %s collection after %d attempts: %w", BillingWebhookDeliveriesCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
 // GetTotalSubscriptions handles fetching the total count of subscriptions in repository
 func (r *Repository) GetTotalSubscriptions(ctx context.Context, req *GetTotalSubscriptionsRequest) (int64, error) {
 
@@ -157,6 +198,16 @@ func (r *Repository) GetTotalSubscriptions(ctx context.Context, req *GetTotalSub
 		queryFilter["user_id"] = bson.M{"$in": req.UserIDs}
 	}
 
+	if len(req.GroupIDs) > 0 {
+		queryFilter["group_id"] = bson.M{"$in": req.GroupIDs}
+	} else if req.ExcludeGroupOwned {
+		queryFilter["group_id"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+
+	if len(req.PricePlanVersionIDs) > 0 {
+		queryFilter["price_plan_version_id"] = bson.M{"$in": req.PricePlanVersionIDs}
+	}
+
 	if len(req.Emails) > 0 {
 		standardisedProvidedEmails := standardisedEmails(req.Emails)
 		queryFilter["email"] = bson.M{"$in": standardisedProvidedEmails}
@@ -247,6 +298,17 @@ func (r *Repository) GetSubscriptions(ctx context.Context, req *GetSubscriptions
 		queryFilter = append(queryFilter, bson.E{Key: "user_id", Value: bson.M{"$in": req.ForUserIDs}})
 	}
 
+	if len(req.ForGroupIDs) > 0 {
+		queryFilter = append(queryFilter, bson.E{Key: "group_id", Value: bson.M{"$in": req.ForGroupIDs}})
+	} else if req.ExcludeGroupOwned {
+		// a missing group_id also matches nil
+		queryFilter = append(queryFilter, bson.E{Key: "group_id", Value: bson.M{"$in": bson.A{nil, ""}}})
+	}
+
+	if len(req.ForPricePlanVersionIDs) > 0 {
+		queryFilter = append(queryFilter, bson.E{Key: "price_plan_version_id", Value: bson.M{"$in": req.ForPricePlanVersionIDs}})
+	}
+
 	if len(req.ForEmails) > 0 {
 		queryFilter = append(queryFilter, bson.E{Key: "email", Value: bson.M{"$in": standardisedEmails(req.ForEmails)}})
 	}
@@ -949,6 +1011,196 @@ func (r *Repository) GetUnassociatedBillingEvents(ctx context.Context, req *GetU
 	return events, nil
 }
 
+// CreateWebhookDelivery handles storing a webhook delivery in the inbox. The inbox key
+// is the document ID, so a delivery already in the inbox is reported as
+// ErrBillingWebhookDeliveryAlreadyExists
+func (r *Repository) CreateWebhookDelivery(ctx context.Context, newDelivery *WebhookDelivery) (*WebhookDelivery, error) {
+
+	collection, err := r.GetBillingWebhookDeliveriesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, newDelivery, "webhook_delivery")
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrBillingWebhookDeliveryAlreadyExists
+		}
+		return nil, err
+	}
+
+	return newDelivery, nil
+}
+
+// GetWebhookDeliveryByID handles fetching a webhook delivery by its inbox key
+func (r *Repository) GetWebhookDeliveryByID(ctx context.Context, deliveryID string) (*WebhookDelivery, error) {
+
+	var (
+		result      WebhookDelivery
+		queryFilter = bson.M{"_id": deliveryID}
+	)
+
+	collection, err := r.GetBillingWebhookDeliveriesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Store.ExecuteFindOneCommandDecodeResult(ctx, collection, queryFilter, &result, "webhook_delivery", true, ErrBillingWebhookDeliveryNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ClaimWebhookDelivery atomically moves a received or failed webhook delivery to processing
+// so only one worker processes it. Deliveries left processing since before staleBefore
+// can be claimed again. ErrBillingWebhookDeliveryAlreadyClaimed is returned when the
+// delivery is being processed or was already processed
+func (r *Repository) ClaimWebhookDelivery(ctx context.Context, deliveryID, staleBefore string) (*WebhookDelivery, error) {
+
+	var result WebhookDelivery
+
+	collection, err := r.GetBillingWebhookDeliveriesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"_id": deliveryID,
+		"$or": []bson.M{
+			{"status": bson.M{"$in": []string{WebhookDeliveryStatusReceived, WebhookDeliveryStatusFailed}}},
+			{"status": WebhookDeliveryStatusProcessing, "updated_at": bson.M{"$lt": staleBefore}},
+		},
+	}
+	updateFilter := bson.M{
+		"$set": bson.M{
+			"status":     WebhookDeliveryStatusProcessing,
+			"updated_at": toolbox.TimeNowUTC(),
+		},
+	}
+
+	err = collection.FindOneAndUpdate(ctx, queryFilter, updateFilter, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrBillingWebhookDeliveryAlreadyClaimed
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateWebhookDeliveryOutcome records the outcome of a processing attempt against
+// a webhook delivery, incrementing its attempt count
+func (r *Repository) UpdateWebhookDeliveryOutcome(ctx context.Context, deliveryID, status, lastError string) (*WebhookDelivery, error) {
+
+	collection, err := r.GetBillingWebhookDeliveriesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := toolbox.TimeNowUTC()
+	setFilter := bson.M{
+		"status":     status,
+		"last_error": lastError,
+		"updated_at": now,
+	}
+	if status == WebhookDeliveryStatusProcessed {
+		setFilter["processed_at"] = now
+	}
+
+	updateFilter := bson.M{
+		"$set": setFilter,
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": deliveryID}, updateFilter, "webhook_delivery")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetWebhookDeliveryByID(ctx, deliveryID)
+}
+
+// GetTotalWebhookDeliveries handles fetching the total count of webhook deliveries in repository
+func (r *Repository) GetTotalWebhookDeliveries(ctx context.Context, req *GetTotalWebhookDeliveriesRequest) (int64, error) {
+
+	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
+
+	if req.IntegratorName != "" {
+		queryFilter["integrator"] = req.IntegratorName
+	}
+
+	if len(req.Statuses) > 0 {
+		queryFilter["status"] = bson.M{"$in": req.Statuses}
+	}
+
+	collection, err := r.GetBillingWebhookDeliveriesCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Store.ExecuteCountDocuments(ctx, collection, queryFilter)
+}
+
+// GetWebhookDeliveries handles fetching webhook deliveries from repository
+func (r *Repository) GetWebhookDeliveries(ctx context.Context, req *GetWebhookDeliveriesRequest) ([]WebhookDelivery, error) {
+
+	var (
+		result          []WebhookDelivery
+		queryFilter     bson.D = bson.D{}
+		requestFilter   bson.D = bson.D{}
+		paginationLimit *int64 = repository.GetPaginationLimit(int64(req.PerPage))
+	)
+
+	findOptions := options.Find()
+
+	findOptions.SetLimit(*paginationLimit)
+	findOptions.SetSkip(*repository.GetPaginationSkip(int64(req.Page), paginationLimit))
+
+	if req.IntegratorName != "" {
+		queryFilter = append(queryFilter, bson.E{Key: "integrator", Value: req.IntegratorName})
+	}
+
+	if len(req.Statuses) > 0 {
+		queryFilter = append(queryFilter, bson.E{Key: "status", Value: bson.M{"$in": req.Statuses}})
+	}
+
+	switch req.Order {
+	case "created_at_asc":
+		requestFilter = append(requestFilter, bson.E{Key: "created_at", Value: 1})
+	case "updated_at_asc":
+		requestFilter = append(requestFilter, bson.E{Key: "updated_at", Value: 1})
+	case "updated_at_desc":
+		requestFilter = append(requestFilter, bson.E{Key: "updated_at", Value: -1})
+	case "provider_event_time_asc":
+		requestFilter = append(requestFilter, bson.E{Key: "provider_event_time", Value: 1})
+	case "provider_event_time_desc":
+		requestFilter = append(requestFilter, bson.E{Key: "provider_event_time", Value: -1})
+	default:
+		requestFilter = append(requestFilter, bson.E{Key: "created_at", Value: -1})
+	}
+
+	findOptions.SetSort(requestFilter)
+
+	collection, err := r.GetBillingWebhookDeliveriesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.Store.MapAllInCursorToResult(ctx, c, &result, "webhook_deliveries"); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 ///// Private helper functions
 
 // standardisedEmails takes a slice of email strings and returns a new slice with the emails standardised to lowercase.
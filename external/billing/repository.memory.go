@@ -2,6 +2,7 @@ package billing
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 
@@ -15,6 +16,9 @@ type InMemoryRepositoryStore struct {
 
 	// Events is a map of event ID to Billing Events
 	Events map[string]*BillingEvent
+
+	// WebhookDeliveries is a map of inbox key to Webhook Deliveries
+	WebhookDeliveries map[string]*WebhookDelivery
 }
 
 // InMemoryRepository is an in-memory implementation of the repository interface
@@ -34,6 +38,10 @@ func NewInMemoryRepository(baseStore *InMemoryRepositoryStore) *InMemoryReposito
 		}
 	}
 
+	if baseStore.WebhookDeliveries == nil {
+		baseStore.WebhookDeliveries = make(map[string]*WebhookDelivery)
+	}
+
 	return &InMemoryRepository{
 		store: baseStore,
 	}
@@ -431,6 +439,18 @@ func (m *InMemoryRepository) matchesSubscriptionFilter(sub *Subscription, req *G
 		return false
 	}
 
+	if len(req.GroupIDs) > 0 && !contains(req.GroupIDs, sub.GroupID) {
+		return false
+	}
+
+	if req.ExcludeGroupOwned && sub.IsGroupOwned() {
+		return false
+	}
+
+	if len(req.PricePlanVersionIDs) > 0 && !contains(req.PricePlanVersionIDs, sub.PricePlanVersionID) {
+		return false
+	}
+
 	if len(req.Emails) > 0 && !containsEmail(req.Emails, sub.Email) {
 		return false
 	}
@@ -479,6 +499,18 @@ func (m *InMemoryRepository) matchesSubscriptionFilterFromGetRequest(sub *Subscr
 		return false
 	}
 
+	if len(req.ForGroupIDs) > 0 && !contains(req.ForGroupIDs, sub.GroupID) {
+		return false
+	}
+
+	if req.ExcludeGroupOwned && sub.IsGroupOwned() {
+		return false
+	}
+
+	if len(req.ForPricePlanVersionIDs) > 0 && !contains(req.ForPricePlanVersionIDs, sub.PricePlanVersionID) {
+		return false
+	}
+
 	if len(req.ForEmails) > 0 && !containsEmail(req.ForEmails, sub.Email) {
 		return false
 	}
@@ -611,8 +643,134 @@ func (m *InMemoryRepository) sortBillingEvents(events []BillingEvent, order stri
 	return events
 }
 
+// CreateWebhookDelivery stores a webhook delivery in the inbox
+func (m *InMemoryRepository) CreateWebhookDelivery(ctx context.Context, newDelivery *WebhookDelivery) (*WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.store.WebhookDeliveries[newDelivery.ID]; ok {
+		return nil, ErrBillingWebhookDeliveryAlreadyExists
+	}
+
+	storedDelivery := *newDelivery
+	m.store.WebhookDeliveries[newDelivery.ID] = &storedDelivery
+	return newDelivery, nil
+}
+
+// GetWebhookDeliveryByID retrieves a webhook delivery by its inbox key
+func (m *InMemoryRepository) GetWebhookDeliveryByID(ctx context.Context, deliveryID string) (*WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	delivery, ok := m.store.WebhookDeliveries[deliveryID]
+	if !ok {
+		return nil, ErrBillingWebhookDeliveryNotFound
+	}
+
+	result := *delivery
+	return &result, nil
+}
+
+// ClaimWebhookDelivery moves a received, failed or stale processing webhook delivery to processing
+func (m *InMemoryRepository) ClaimWebhookDelivery(ctx context.Context, deliveryID, staleBefore string) (*WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivery, ok := m.store.WebhookDeliveries[deliveryID]
+	if !ok {
+		return nil, ErrBillingWebhookDeliveryAlreadyClaimed
+	}
+
+	switch delivery.Status {
+	case WebhookDeliveryStatusReceived, WebhookDeliveryStatusFailed:
+	case WebhookDeliveryStatusProcessing:
+		if delivery.UpdatedAt >= staleBefore {
+			return nil, ErrBillingWebhookDeliveryAlreadyClaimed
+		}
+	default:
+		return nil, ErrBillingWebhookDeliveryAlreadyClaimed
+	}
+
+	delivery.Status = WebhookDeliveryStatusProcessing
+	delivery.SetUpdatedAtTimeToNow()
+
+	result := *delivery
+	return &result, nil
+}
+
+// UpdateWebhookDeliveryOutcome records the outcome of a processing attempt against a webhook delivery
+func (m *InMemoryRepository) UpdateWebhookDeliveryOutcome(ctx context.Context, deliveryID, status, lastError string) (*WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivery, ok := m.store.WebhookDeliveries[deliveryID]
+	if !ok {
+		return nil, ErrBillingWebhookDeliveryNotFound
+	}
+
+	delivery.Status = status
+	delivery.LastError = lastError
+	delivery.Attempts++
+	delivery.SetUpdatedAtTimeToNow()
+	if status == WebhookDeliveryStatusProcessed {
+		delivery.ProcessedAt = delivery.UpdatedAt
+	}
+
+	result := *delivery
+	return &result, nil
+}
+
+// GetTotalWebhookDeliveries handles fetching the total count of webhook deliveries that match the filters
+func (m *InMemoryRepository) GetTotalWebhookDeliveries(ctx context.Context, req *GetTotalWebhookDeliveriesRequest) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := int64(0)
+	for _, delivery := range m.store.WebhookDeliveries {
+		if matchesWebhookDeliveryFilter(delivery, req.IntegratorName, req.Statuses) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// GetWebhookDeliveries retrieves webhook deliveries that match the filters, oldest first
+func (m *InMemoryRepository) GetWebhookDeliveries(ctx context.Context, req *GetWebhookDeliveriesRequest) ([]WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var deliveries []WebhookDelivery
+	for _, delivery := range m.store.WebhookDeliveries {
+		if matchesWebhookDeliveryFilter(delivery, req.IntegratorName, req.Statuses) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].ProviderEventTime.Equal(deliveries[j].ProviderEventTime) {
+			return deliveries[i].ID < deliveries[j].ID
+		}
+		return deliveries[i].ProviderEventTime.Before(deliveries[j].ProviderEventTime)
+	})
+
+	return deliveries, nil
+}
+
 // Helper functions
 
+func matchesWebhookDeliveryFilter(delivery *WebhookDelivery, integratorName string, statuses []string) bool {
+	if integratorName != "" && delivery.Integrator != integratorName {
+		return false
+	}
+
+	if len(statuses) > 0 && !contains(statuses, delivery.Status) {
+		return false
+	}
+
+	return true
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
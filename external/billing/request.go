@@ -7,6 +7,7 @@ import (
 // CreateSubscriptionRequest contains data for creating a subscription
 type CreateSubscriptionRequest struct {
 	UserID                   string
+	GroupID                  string
 	Email                    string
 	Status                   string
 	Integrator               string
@@ -17,11 +18,14 @@ type CreateSubscriptionRequest struct {
 	Amount                   int64
 	Currency                 string
 	BillingInterval          string
+	Quantity                 int64
 	NextBillingDate          *time.Time
 	AvailableUntilDate       *time.Time
 	TrialEndsAt              *time.Time
+	LastProviderEventTime    *time.Time
 	CancelURL                string
 	UpdateURL                string
+	PromotionCode            string
 	Metadata                 map[string]interface{}
 }
 
@@ -31,9 +35,13 @@ type UpdateSubscriptionRequest struct {
 	Status             *string
 	PlanName           *string
 	PlanID             *string
+	PricePlanID        *string
+	PricePlanVersionID *string
+	PricePlanVersion   *int
 	Amount             *int64
 	Currency           *string
 	BillingInterval    *string
+	Quantity           *int64
 	NextBillingDate    *time.Time
 	AvailableUntilDate *time.Time
 	TrialEndsAt        *time.Time
@@ -41,6 +49,21 @@ type UpdateSubscriptionRequest struct {
 	CancelURL          *string
 	UpdateURL          *string
 	Metadata           map[string]interface{}
+
+	// Dunning replaces the subscription's dunning progress
+	Dunning *SubscriptionDunning
+
+	// ClearDunning removes the subscription's dunning progress, e.g. once payment recovers
+	ClearDunning bool
+
+	// LastProviderEventTime is the provider event time of the webhook being applied
+	LastProviderEventTime *time.Time
+
+	// ScheduledChange replaces the cancellation or pause scheduled for the period end
+	ScheduledChange *SubscriptionScheduledChange
+
+	// ClearScheduledChange removes the scheduled change, e.g. once it takes effect
+	ClearScheduledChange bool
 }
 
 // GetTotalSubscriptionsRequest holds everything needed to make
@@ -58,6 +81,15 @@ type GetTotalSubscriptionsRequest struct {
 	// UserIDs is the list of user IDs to filter by
 	UserIDs []string
 
+	// GroupIDs is the list of group IDs to filter by
+	GroupIDs []string
+
+	// ExcludeGroupOwned leaves out subscriptions bought on behalf of a group
+	ExcludeGroupOwned bool
+
+	// PricePlanVersionIDs is the list of pricer plan version IDs to filter by
+	PricePlanVersionIDs []string
+
 	// Emails is the list of emails to filter by
 	Emails []string
 
@@ -122,6 +154,18 @@ type GetSubscriptionsRequest struct {
 	// comma-separated list of user IDs
 	ForUserIDs []string `query:"for_user_ids"`
 
+	// ForGroupIDs is the list of group IDs to filter by
+	// comma-separated list of group IDs
+	ForGroupIDs []string `query:"for_group_ids"`
+
+	// ExcludeGroupOwned leaves out subscriptions bought on behalf of a group,
+	// e.g. when resolving what a user holds personally
+	ExcludeGroupOwned bool `query:"exclude_group_owned"`
+
+	// ForPricePlanVersionIDs is the list of pricer plan version IDs to filter by
+	// comma-separated list of price plan version IDs
+	ForPricePlanVersionIDs []string `query:"for_price_plan_version_ids"`
+
 	// ForEmails is the list of emails to filter by
 	// comma-separated list of emails
 	ForEmails []string `query:"for_emails"`
@@ -161,6 +205,27 @@ type GetSubscriptionByIDRequest struct {
 	ID string
 }
 
+// GetBillingEventByIDRequest holds everything needed to make
+// the request to get a billing event by ID
+type GetBillingEventByIDRequest struct {
+	// ID is the internal unique identifier
+	ID string
+}
+
+// GetRevenueMetricsRequest holds everything needed to make
+// the request to aggregate revenue metrics from subscriptions
+type GetRevenueMetricsRequest struct {
+	// IntegratorName is the provider name to filter by
+	IntegratorName string `query:"integrator_name"`
+
+	// PeriodStart is the start of the period churn is measured over (RFC3339).
+	// Default 30 days before the period end
+	PeriodStart string `query:"period_start"`
+
+	// PeriodEnd is the end of the period churn is measured over (RFC3339). Default now
+	PeriodEnd string `query:"period_end"`
+}
+
 // GetSubscriptionByIntegratorIDRequest holds everything needed to make
 // the request to get a subscription by integrator subscription ID
 type GetSubscriptionByIntegratorIDRequest struct {
@@ -395,6 +460,7 @@ type CreateBillingEventRequest struct {
 	Integrator               string
 	IntegratorEventID        string
 	IntegratorSubscriptionID string
+	IntegratorTransactionID  string
 	Status                   string
 	Amount                   int64
 	Currency                 string
@@ -411,3 +477,85 @@ type CreateBillingEventResponse struct {
 	// BillingEvent is the billing event that was created
 	BillingEvent *BillingEvent `json:"billing_event"`
 }
+
+// RecordWebhookDeliveryRequest holds everything needed to make
+// the request to record a verified webhook in the inbox
+type RecordWebhookDeliveryRequest struct {
+	Integrator               string
+	IntegratorEventID        string
+	IntegratorSubscriptionID string
+	EventType                string
+	Payload                  string
+	EventTime                time.Time
+}
+
+// ClaimWebhookDeliveryRequest holds everything needed to make
+// the request to claim a webhook delivery for processing
+type ClaimWebhookDeliveryRequest struct {
+	// ID is the inbox key of the delivery
+	ID string
+}
+
+// UpdateWebhookDeliveryOutcomeRequest holds everything needed to make
+// the request to record the outcome of processing a webhook delivery
+type UpdateWebhookDeliveryOutcomeRequest struct {
+	// ID is the inbox key of the delivery
+	ID string
+
+	// Status is the resulting status (processed or failed)
+	Status string
+
+	// LastError is the processing error, if any
+	LastError string
+}
+
+// GetWebhookDeliveryByIDRequest holds everything needed to make
+// the request to get a webhook delivery by its inbox key
+type GetWebhookDeliveryByIDRequest struct {
+	// ID is the inbox key of the delivery
+	ID string
+}
+
+// GetTotalWebhookDeliveriesRequest holds everything needed to make
+// the request to get the total count of webhook deliveries from repository
+type GetTotalWebhookDeliveriesRequest struct {
+
+	// IntegratorName is the provider name to filter by
+	IntegratorName string
+
+	// Statuses is the list of statuses to filter by
+	Statuses []string
+}
+
+// GetWebhookDeliveriesRequest holds everything needed to make
+// the request to get webhook deliveries
+type GetWebhookDeliveriesRequest struct {
+
+	// Order defines how should response be sorted. Default: newest -> oldest (created_at_desc)
+	// Valid options: created_at_asc, created_at_desc, updated_at_asc, updated_at_desc,
+	// provider_event_time_asc, provider_event_time_desc
+	Order string `query:"order"`
+
+	// Total number of webhook deliveries to return per page, if available. Default 25.
+	// Accepts anything between 1 and 100
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from. Default 1.
+	Page int `query:"page"`
+
+	// TotalCount specifies the total count of all webhook deliveries
+	TotalCount int
+
+	// TotalPages specifies the total pages of results
+	TotalPages int
+
+	// Meta whether response should contain meta information
+	Meta bool `query:"meta"`
+
+	// IntegratorName is the provider name to filter by
+	IntegratorName string `query:"integrator_name"`
+
+	// Statuses is the list of statuses to filter by
+	// comma-separated list of statuses
+	Statuses []string `query:"statuses"`
+}
@@ -57,6 +57,49 @@ type GetSubscriptionByIDResponse struct {
 	Subscription *Subscription `json:"subscription"`
 }
 
+// GetBillingEventByIDResponse holds everything needed to return
+// the response to getting a billing event by ID
+type GetBillingEventByIDResponse struct {
+	// BillingEvent is the billing event that was found
+	BillingEvent *BillingEvent `json:"billing_event"`
+}
+
+// GetRevenueMetricsResponse holds revenue metrics aggregated from subscriptions
+type GetRevenueMetricsResponse struct {
+	// PeriodStart is the start of the period churn was measured over (RFC3339)
+	PeriodStart string `json:"period_start"`
+
+	// PeriodEnd is the end of the period churn was measured over (RFC3339)
+	PeriodEnd string `json:"period_end"`
+
+	// MonthlyRecurringRevenue is the monthly amount of active paying subscriptions in the
+	// smallest currency unit, by currency. Yearly, weekly and daily amounts are normalised to a month
+	MonthlyRecurringRevenue map[string]int64 `json:"monthly_recurring_revenue"`
+
+	// ActiveSubscriptions is the number of active and trialing subscriptions
+	ActiveSubscriptions int64 `json:"active_subscriptions"`
+
+	// TrialingSubscriptions is the number of subscriptions in their trial
+	TrialingSubscriptions int64 `json:"trialing_subscriptions"`
+
+	// ComplimentarySubscriptions is the number of active subscriptions granted by an admin
+	ComplimentarySubscriptions int64 `json:"complimentary_subscriptions"`
+
+	// ActiveSubscriptionsByPlan is the number of active and trialing subscriptions by plan name
+	ActiveSubscriptionsByPlan map[string]int64 `json:"active_subscriptions_by_plan"`
+
+	// SubscriptionsAtPeriodStart is the number of paid subscriptions that existed and had not been
+	// cancelled at the start of the period
+	SubscriptionsAtPeriodStart int64 `json:"subscriptions_at_period_start"`
+
+	// ChurnedSubscriptions is how many of the subscriptions at the period start were cancelled
+	// during the period
+	ChurnedSubscriptions int64 `json:"churned_subscriptions"`
+
+	// ChurnRate is the share of subscriptions at the period start that churned during the period
+	ChurnRate float64 `json:"churn_rate"`
+}
+
 // GetSubscriptionByIntegratorIDResponse holds everything needed to return
 // the response to getting a subscription by integrator subscription ID
 type GetSubscriptionByIntegratorIDResponse struct {
@@ -181,3 +224,68 @@ type GetUnassociatedBillingEventsResponse struct {
 	// Total is the total number of unassociated billing events found
 	Total int `json:"total"`
 }
+
+// RecordWebhookDeliveryResponse holds everything needed to return
+// the response to recording a webhook delivery
+type RecordWebhookDeliveryResponse struct {
+	// Delivery is the inbox entry for the webhook
+	Delivery *WebhookDelivery `json:"delivery"`
+
+	// AlreadyRecorded is true when the provider event was already in the inbox
+	AlreadyRecorded bool `json:"already_recorded"`
+}
+
+// ClaimWebhookDeliveryResponse holds everything needed to return
+// the response to claiming a webhook delivery
+type ClaimWebhookDeliveryResponse struct {
+	// Delivery is the claimed inbox entry
+	Delivery *WebhookDelivery `json:"delivery"`
+}
+
+// UpdateWebhookDeliveryOutcomeResponse holds everything needed to return
+// the response to updating a webhook delivery outcome
+type UpdateWebhookDeliveryOutcomeResponse struct {
+	// Delivery is the updated inbox entry
+	Delivery *WebhookDelivery `json:"delivery"`
+}
+
+// GetWebhookDeliveryByIDResponse holds everything needed to return
+// the response to getting a webhook delivery by its inbox key
+type GetWebhookDeliveryByIDResponse struct {
+	// Delivery is the inbox entry that was found
+	Delivery *WebhookDelivery `json:"delivery"`
+}
+
+// GetWebhookDeliveriesResponse holds everything needed to return
+// the response to get webhook deliveries
+type GetWebhookDeliveriesResponse struct {
+	WebhookDeliveries []WebhookDelivery `json:"webhook_deliveries"`
+
+	// Total number of webhook deliveries found that matched provided
+	// filters
+	Total int
+
+	// TotalPages total pages available, based on the provided
+	// filters and resources per page
+	TotalPages int
+
+	// PerPage number of webhook deliveries set to be returned per page
+	PerPage int
+
+	// Page specifies the page results were taken from. Default 1.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetWebhookDeliveriesResponse,
+// including the number of resources per page, total resources, total pages,
+// and the current page.
+func (g *GetWebhookDeliveriesResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
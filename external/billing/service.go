@@ -32,6 +32,17 @@ type billingEventsRepository interface {
 	GetUnassociatedBillingEvents(ctx context.Context, req *GetUnassociatedBillingEventsRequest) ([]BillingEvent, error)
 }
 
+// webhookDeliveryRepository is the expected methods needed to
+// interact with the webhook inbox in the database
+type webhookDeliveryRepository interface {
+	CreateWebhookDelivery(ctx context.Context, newDelivery *WebhookDelivery) (*WebhookDelivery, error)
+	GetWebhookDeliveryByID(ctx context.Context, deliveryID string) (*WebhookDelivery, error)
+	ClaimWebhookDelivery(ctx context.Context, deliveryID, staleBefore string) (*WebhookDelivery, error)
+	UpdateWebhookDeliveryOutcome(ctx context.Context, deliveryID, status, lastError string) (*WebhookDelivery, error)
+	GetTotalWebhookDeliveries(ctx context.Context, req *GetTotalWebhookDeliveriesRequest) (int64, error)
+	GetWebhookDeliveries(ctx context.Context, req *GetWebhookDeliveriesRequest) ([]WebhookDelivery, error)
+}
+
 // subscriptionRepository is the expected methods needed to
 // interact with the database
 type subscriptionRepository interface {
@@ -53,6 +64,8 @@ type Service struct {
 	billingEventsRepository billingEventsRepository
 	subscriptionRepository  subscriptionRepository
 	AuditService            AuditService
+
+	webhookDeliveryRepository webhookDeliveryRepository
 }
 
 // NewService returns a new instance of the billing service
@@ -69,6 +82,13 @@ func (s *Service) WithAuditService(audit AuditService) *Service {
 	return s
 }
 
+// WithWebhookDeliveryRepository adds the webhook inbox used to deduplicate
+// and replay payment provider webhooks
+func (s *Service) WithWebhookDeliveryRepository(repository webhookDeliveryRepository) *Service {
+	s.webhookDeliveryRepository = repository
+	return s
+}
+
 // CreateSubscription creates a new subscription
 func (s *Service) CreateSubscription(ctx context.Context, req *CreateSubscriptionRequest) (*CreateSubscriptionResponse, error) {
 	var (
@@ -76,6 +96,7 @@ func (s *Service) CreateSubscription(ctx context.Context, req *CreateSubscriptio
 
 		newSubscription = &Subscription{
 			UserID:                   req.UserID,
+			GroupID:                  req.GroupID,
 			Email:                    toolbox.StringStandardisedToLower(req.Email),
 			Status:                   req.Status,
 			Integrator:               req.Integrator,
@@ -86,11 +107,14 @@ func (s *Service) CreateSubscription(ctx context.Context, req *CreateSubscriptio
 			Amount:                   req.Amount,
 			Currency:                 req.Currency,
 			BillingInterval:          req.BillingInterval,
+			Quantity:                 req.Quantity,
 			NextBillingDate:          req.NextBillingDate,
 			AvailableUntilDate:       req.AvailableUntilDate,
 			ProviderTrialEndsAt:      req.TrialEndsAt,
+			LastProviderEventTime:    req.LastProviderEventTime,
 			CancelURL:                req.CancelURL,
 			UpdateURL:                req.UpdateURL,
+			PromotionCode:            req.PromotionCode,
 			Metadata:                 req.Metadata,
 		}
 	)
@@ -138,6 +162,15 @@ func (s *Service) UpdateSubscription(ctx context.Context, req *UpdateSubscriptio
 	if req.PlanID != nil {
 		subscription.PlanID = *req.PlanID
 	}
+	if req.PricePlanID != nil {
+		subscription.PricePlanID = *req.PricePlanID
+	}
+	if req.PricePlanVersionID != nil {
+		subscription.PricePlanVersionID = *req.PricePlanVersionID
+	}
+	if req.PricePlanVersion != nil {
+		subscription.PricePlanVersion = *req.PricePlanVersion
+	}
 	if req.Amount != nil {
 		subscription.Amount = *req.Amount
 	}
@@ -147,6 +180,9 @@ func (s *Service) UpdateSubscription(ctx context.Context, req *UpdateSubscriptio
 	if req.BillingInterval != nil {
 		subscription.BillingInterval = *req.BillingInterval
 	}
+	if req.Quantity != nil {
+		subscription.Quantity = *req.Quantity
+	}
 	if req.NextBillingDate != nil {
 		subscription.NextBillingDate = req.NextBillingDate
 	}
@@ -168,6 +204,21 @@ func (s *Service) UpdateSubscription(ctx context.Context, req *UpdateSubscriptio
 	if req.Metadata != nil {
 		subscription.Metadata = req.Metadata
 	}
+	if req.LastProviderEventTime != nil {
+		subscription.LastProviderEventTime = req.LastProviderEventTime
+	}
+	if req.Dunning != nil {
+		subscription.Dunning = req.Dunning
+	}
+	if req.ClearDunning {
+		subscription.Dunning = nil
+	}
+	if req.ScheduledChange != nil {
+		subscription.ScheduledChange = req.ScheduledChange
+	}
+	if req.ClearScheduledChange {
+		subscription.ScheduledChange = nil
+	}
 
 	subscription.SetUpdatedAtTimeToNow()
 
@@ -209,6 +260,9 @@ func (s *Service) GetSubscriptions(ctx context.Context, req *GetSubscriptionsReq
 		IntegratorSubscriptionID: req.IntegratorSubscriptionID,
 		IntegratorCustomerID:     req.IntegratorCustomerID,
 		UserIDs:                  req.ForUserIDs,
+		GroupIDs:                 req.ForGroupIDs,
+		ExcludeGroupOwned:        req.ExcludeGroupOwned,
+		PricePlanVersionIDs:      req.ForPricePlanVersionIDs,
 		Emails:                   standardisedEmails(req.ForEmails),
 		Statuses:                 req.Statuses,
 		PlanNameContains:         req.PlanNameContains,
@@ -253,6 +307,38 @@ func (s *Service) GetSubscriptions(ctx context.Context, req *GetSubscriptionsReq
 	}, nil
 }
 
+// HasPreviousSubscription returns true if the user has any subscription other than
+// excludeSubscriptionID, whatever its status. It backs first-time customer promotion codes
+func (s *Service) HasPreviousSubscription(ctx context.Context, userID string, excludeSubscriptionID string) (bool, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+	)
+
+	if userID == "" {
+		return false, nil
+	}
+
+	// Two is enough to find one subscription that is not the excluded one
+	subscriptions, err := s.subscriptionRepository.GetSubscriptions(ctx, &GetSubscriptionsRequest{
+		ForUserIDs: []string{userID},
+		Order:      "created_at_desc",
+		PerPage:    2,
+		Page:       1,
+	})
+	if err != nil {
+		logger.Error("failed-to-check-previous-subscriptions", zap.String("user-id", userID), zap.Error(err))
+		return false, err
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.ID != excludeSubscriptionID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // GetSubscriptionByID retrieves a subscription by its internal ID
 func (s *Service) GetSubscriptionByID(ctx context.Context, req *GetSubscriptionByIDRequest) (*GetSubscriptionByIDResponse, error) {
 	var (
@@ -364,6 +450,7 @@ func (s *Service) CreateBillingEvent(ctx context.Context, req *CreateBillingEven
 			Integrator:               req.Integrator,
 			IntegratorEventID:        req.IntegratorEventID,
 			IntegratorSubscriptionID: req.IntegratorSubscriptionID,
+			IntegratorTransactionID:  req.IntegratorTransactionID,
 			Status:                   req.Status,
 			Amount:                   req.Amount,
 			Currency:                 req.Currency,
@@ -392,6 +479,27 @@ func (s *Service) CreateBillingEvent(ctx context.Context, req *CreateBillingEven
 	}, nil
 }
 
+// GetBillingEventByID retrieves a billing event by its internal ID
+func (s *Service) GetBillingEventByID(ctx context.Context, req *GetBillingEventByIDRequest) (*GetBillingEventByIDResponse, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+	)
+
+	logger.Debug("initiating-get-billing-event-by-id-request", zap.Any("request", safeLogValue(req)))
+
+	event, err := s.billingEventsRepository.GetBillingEventByID(ctx, req.ID)
+	if err != nil {
+		logger.Error("failed-to-get-billing-event-by-id-error-getting-event", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetBillingEventByIDResponse{}, err
+	}
+
+	logger.Debug("get-billing-event-by-id-request-successful", zap.Any("request", safeLogValue(req)))
+
+	return &GetBillingEventByIDResponse{
+		BillingEvent: event,
+	}, nil
+}
+
 // GetBillingEvents returns a list of billing events
 func (s *Service) GetBillingEvents(ctx context.Context, req *GetBillingEventsRequest) (*GetBillingEventsResponse, error) {
 	var (
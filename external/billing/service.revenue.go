@@ -0,0 +1,156 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// GetRevenueMetrics aggregates revenue metrics from the stored subscriptions: monthly recurring
+// revenue by currency, active subscriptions by plan and churn over the requested period
+func (s *Service) GetRevenueMetrics(ctx context.Context, req *GetRevenueMetricsRequest) (*GetRevenueMetricsResponse, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+	)
+
+	periodStart, periodEnd, err := revenueMetricsPeriod(req)
+	if err != nil {
+		logger.Warn("failed-to-get-revenue-metrics-invalid-period", zap.String("period-start", req.PeriodStart), zap.String("period-end", req.PeriodEnd))
+		return nil, err
+	}
+
+	metrics := &GetRevenueMetricsResponse{
+		PeriodStart:               periodStart.Format(time.RFC3339),
+		PeriodEnd:                 periodEnd.Format(time.RFC3339),
+		MonthlyRecurringRevenue:   map[string]int64{},
+		ActiveSubscriptionsByPlan: map[string]int64{},
+	}
+
+	seen := map[string]bool{}
+	for page := 1; ; page++ {
+		response, err := s.GetSubscriptions(ctx, &GetSubscriptionsRequest{
+			IntegratorName: req.IntegratorName,
+			Order:          "created_at_asc",
+			PerPage:        revenueMetricsPageSize,
+			Page:           page,
+		})
+		if err != nil {
+			logger.Error("failed-to-get-revenue-metrics-error-getting-subscriptions", zap.Int("page", page), zap.Error(err))
+			return nil, err
+		}
+
+		for i := range response.Subscriptions {
+			subscription := &response.Subscriptions[i]
+			if seen[subscription.ID] {
+				continue
+			}
+			seen[subscription.ID] = true
+
+			addSubscriptionToRevenueMetrics(metrics, subscription, periodStart, periodEnd)
+		}
+
+		if len(response.Subscriptions) == 0 || page >= response.TotalPages {
+			break
+		}
+	}
+
+	if metrics.SubscriptionsAtPeriodStart > 0 {
+		metrics.ChurnRate = float64(metrics.ChurnedSubscriptions) / float64(metrics.SubscriptionsAtPeriodStart)
+	}
+
+	logger.Debug("get-revenue-metrics-request-successful", zap.Int("subscriptions", len(seen)), zap.Int64("active-subscriptions", metrics.ActiveSubscriptions), zap.Int64("churned-subscriptions", metrics.ChurnedSubscriptions))
+
+	return metrics, nil
+}
+
+// revenueMetricsPeriod returns the period churn is measured over, defaulting to the 30 days up to now
+func revenueMetricsPeriod(req *GetRevenueMetricsRequest) (time.Time, time.Time, error) {
+
+	periodEnd := time.Now().UTC()
+	if req.PeriodEnd != "" {
+		parsed, err := time.Parse(time.RFC3339, req.PeriodEnd)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrBillingInvalidRevenueMetricsPeriod
+		}
+		periodEnd = parsed.UTC()
+	}
+
+	periodStart := periodEnd.Add(-defaultRevenueMetricsPeriod)
+	if req.PeriodStart != "" {
+		parsed, err := time.Parse(time.RFC3339, req.PeriodStart)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrBillingInvalidRevenueMetricsPeriod
+		}
+		periodStart = parsed.UTC()
+	}
+
+	if !periodStart.Before(periodEnd) {
+		return time.Time{}, time.Time{}, ErrBillingInvalidRevenueMetricsPeriod
+	}
+
+	return periodStart, periodEnd, nil
+}
+
+// addSubscriptionToRevenueMetrics counts the subscription towards the revenue metrics. Complimentary
+// subscriptions count as active but never towards recurring revenue or churn
+func addSubscriptionToRevenueMetrics(metrics *GetRevenueMetricsResponse, subscription *Subscription, periodStart time.Time, periodEnd time.Time) {
+
+	if subscription.IsActive() {
+		metrics.ActiveSubscriptions++
+
+		planName := subscription.PlanName
+		if planName == "" {
+			planName = revenueMetricsUnknownPlanName
+		}
+		metrics.ActiveSubscriptionsByPlan[planName]++
+
+		switch {
+		case subscription.IsComplimentary():
+			metrics.ComplimentarySubscriptions++
+		case subscription.Status == StatusTrialing:
+			metrics.TrialingSubscriptions++
+		default:
+			if monthlyAmount, ok := monthlyRecurringAmount(subscription); ok {
+				metrics.MonthlyRecurringRevenue[subscription.Currency] += monthlyAmount
+			}
+		}
+	}
+
+	if subscription.IsComplimentary() {
+		return
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, subscription.CreatedAt)
+	if err != nil || !createdAt.Before(periodStart) {
+		return
+	}
+
+	cancelledAt := subscription.ProviderCancelledAt
+	if cancelledAt != nil && cancelledAt.Before(periodStart) {
+		return
+	}
+
+	metrics.SubscriptionsAtPeriodStart++
+	if cancelledAt != nil && cancelledAt.Before(periodEnd) {
+		metrics.ChurnedSubscriptions++
+	}
+}
+
+// monthlyRecurringAmount normalises the subscription's amount to a month using its billing interval.
+// Subscriptions without a recurring interval are assumed to be monthly, one-off purchases do not recur
+func monthlyRecurringAmount(subscription *Subscription) (int64, bool) {
+	switch subscription.BillingInterval {
+	case "", "month", "monthly":
+		return subscription.Amount, true
+	case "year", "yearly", "annual":
+		return subscription.Amount / 12, true
+	case "week", "weekly":
+		return subscription.Amount * 52 / 12, true
+	case "day", "daily":
+		return subscription.Amount * 365 / 12, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,203 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// RecordWebhookDelivery stores a verified webhook in the inbox before it is
+// processed. When the provider event is already in the inbox the existing
+// entry is returned with AlreadyRecorded set, so callers can skip deliveries
+// that were processed before.
+func (s *Service) RecordWebhookDelivery(ctx context.Context, req *RecordWebhookDeliveryRequest) (*RecordWebhookDeliveryResponse, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+
+		newDelivery = &WebhookDelivery{
+			ID:                       WebhookDeliveryID(req.Integrator, req.IntegratorEventID),
+			Integrator:               req.Integrator,
+			IntegratorEventID:        req.IntegratorEventID,
+			IntegratorSubscriptionID: req.IntegratorSubscriptionID,
+			EventType:                req.EventType,
+			Status:                   WebhookDeliveryStatusReceived,
+			Payload:                  req.Payload,
+			ProviderEventTime:        req.EventTime,
+		}
+	)
+
+	if s.webhookDeliveryRepository == nil {
+		return &RecordWebhookDeliveryResponse{}, ErrBillingWebhookInboxNotConfigured
+	}
+
+	if req.Integrator == "" {
+		return &RecordWebhookDeliveryResponse{}, ErrBillingInvalidIntegrator
+	}
+
+	logFields := []zap.Field{zap.String("delivery-id", newDelivery.ID), zap.String("provider", req.Integrator), zap.String("event-type", req.EventType)}
+
+	newDelivery.SetCreatedAtTimeToNow().SetUpdatedAtTimeToNow()
+
+	createdDelivery, err := s.webhookDeliveryRepository.CreateWebhookDelivery(ctx, newDelivery)
+	if err == nil {
+		logger.Debug("record-webhook-delivery-request-successful", logFields...)
+		return &RecordWebhookDeliveryResponse{Delivery: createdDelivery}, nil
+	}
+
+	if !errors.Is(err, ErrBillingWebhookDeliveryAlreadyExists) {
+		logger.Error("failed-to-record-webhook-delivery-error-creating-delivery", append(logFields, zap.Error(err))...)
+		return &RecordWebhookDeliveryResponse{}, err
+	}
+
+	existingDelivery, err := s.webhookDeliveryRepository.GetWebhookDeliveryByID(ctx, newDelivery.ID)
+	if err != nil {
+		logger.Error("failed-to-record-webhook-delivery-error-getting-existing-delivery", append(logFields, zap.Error(err))...)
+		return &RecordWebhookDeliveryResponse{}, err
+	}
+
+	logger.Info("webhook-delivery-already-recorded", append(logFields, zap.String("status", existingDelivery.Status), zap.Int("attempts", existingDelivery.Attempts))...)
+
+	return &RecordWebhookDeliveryResponse{
+		Delivery:        existingDelivery,
+		AlreadyRecorded: true,
+	}, nil
+}
+
+// ClaimWebhookDelivery claims a webhook delivery for processing so concurrent provider
+// retries and replays do not apply it twice. A delivery left processing for longer than
+// the claim lease can be claimed again.
+func (s *Service) ClaimWebhookDelivery(ctx context.Context, req *ClaimWebhookDeliveryRequest) (*ClaimWebhookDeliveryResponse, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+	)
+
+	if s.webhookDeliveryRepository == nil {
+		return &ClaimWebhookDeliveryResponse{}, ErrBillingWebhookInboxNotConfigured
+	}
+
+	staleBefore := time.Now().Add(-webhookDeliveryClaimLease).UTC().Format(common.RFC3339NanoUTC)
+
+	claimedDelivery, err := s.webhookDeliveryRepository.ClaimWebhookDelivery(ctx, req.ID, staleBefore)
+	if err != nil {
+		if !errors.Is(err, ErrBillingWebhookDeliveryAlreadyClaimed) {
+			logger.Error("failed-to-claim-webhook-delivery", zap.String("delivery-id", req.ID), zap.Error(err))
+		}
+		return &ClaimWebhookDeliveryResponse{}, err
+	}
+
+	return &ClaimWebhookDeliveryResponse{
+		Delivery: claimedDelivery,
+	}, nil
+}
+
+// UpdateWebhookDeliveryOutcome records the result of a processing attempt
+// against a webhook delivery and increments its attempt count
+func (s *Service) UpdateWebhookDeliveryOutcome(ctx context.Context, req *UpdateWebhookDeliveryOutcomeRequest) (*UpdateWebhookDeliveryOutcomeResponse, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+	)
+
+	if s.webhookDeliveryRepository == nil {
+		return &UpdateWebhookDeliveryOutcomeResponse{}, ErrBillingWebhookInboxNotConfigured
+	}
+
+	if req.Status != WebhookDeliveryStatusProcessed && req.Status != WebhookDeliveryStatusFailed {
+		return &UpdateWebhookDeliveryOutcomeResponse{}, ErrBillingInvalidStatus
+	}
+
+	updatedDelivery, err := s.webhookDeliveryRepository.UpdateWebhookDeliveryOutcome(ctx, req.ID, req.Status, req.LastError)
+	if err != nil {
+		logger.Error("failed-to-update-webhook-delivery-outcome", zap.String("delivery-id", req.ID), zap.String("status", req.Status), zap.Error(err))
+		return &UpdateWebhookDeliveryOutcomeResponse{}, err
+	}
+
+	return &UpdateWebhookDeliveryOutcomeResponse{
+		Delivery: updatedDelivery,
+	}, nil
+}
+
+// GetWebhookDeliveryByID retrieves a webhook delivery by its inbox key
+func (s *Service) GetWebhookDeliveryByID(ctx context.Context, req *GetWebhookDeliveryByIDRequest) (*GetWebhookDeliveryByIDResponse, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+	)
+
+	if s.webhookDeliveryRepository == nil {
+		return &GetWebhookDeliveryByIDResponse{}, ErrBillingWebhookInboxNotConfigured
+	}
+
+	delivery, err := s.webhookDeliveryRepository.GetWebhookDeliveryByID(ctx, req.ID)
+	if err != nil {
+		logger.Error("failed-to-get-webhook-delivery-by-id", zap.String("delivery-id", req.ID), zap.Error(err))
+		return &GetWebhookDeliveryByIDResponse{}, err
+	}
+
+	return &GetWebhookDeliveryByIDResponse{
+		Delivery: delivery,
+	}, nil
+}
+
+// GetWebhookDeliveries returns a list of webhook deliveries
+func (s *Service) GetWebhookDeliveries(ctx context.Context, req *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error) {
+	var (
+		logger = logger.AcquirePackageFrom(ctx, "external/billing")
+	)
+
+	if s.webhookDeliveryRepository == nil {
+		return &GetWebhookDeliveriesResponse{}, ErrBillingWebhookInboxNotConfigured
+	}
+
+	// Set defaults
+	if req.Order == "" {
+		req.Order = "created_at_desc"
+	}
+
+	if req.PerPage == 0 {
+		req.PerPage = 25
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	getTotalWebhookDeliveriesRequest := &GetTotalWebhookDeliveriesRequest{
+		IntegratorName: req.IntegratorName,
+		Statuses:       req.Statuses,
+	}
+
+	totalDeliveries, err := s.webhookDeliveryRepository.GetTotalWebhookDeliveries(ctx, getTotalWebhookDeliveriesRequest)
+	if err != nil {
+		logger.Error("failed-to-get-webhook-deliveries-request-error-getting-total-deliveries", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetWebhookDeliveriesResponse{}, err
+	}
+
+	req.TotalCount = int(totalDeliveries)
+
+	deliveries, err := s.webhookDeliveryRepository.GetWebhookDeliveries(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-webhook-deliveries-request-error-getting-deliveries", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetWebhookDeliveriesResponse{}, err
+	}
+
+	paginatedResponse, err := toolbox.Paginate(ctx, &toolbox.PaginationRequest{
+		PerPage: req.PerPage,
+		Page:    req.Page,
+	}, deliveries, req.TotalCount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetWebhookDeliveriesResponse{
+		Total:             paginatedResponse.Total,
+		TotalPages:        paginatedResponse.TotalPages,
+		WebhookDeliveries: paginatedResponse.Resources,
+		Page:              paginatedResponse.Page,
+		PerPage:           paginatedResponse.ResourcePerPage,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package billingmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/spf13/cobra"
+)
+
+// PriceCatalogSyncer is the operation run by the catalog sync command
+type PriceCatalogSyncer interface {
+	SyncPriceCatalog(ctx context.Context, req *SyncPriceCatalogRequest) (*CatalogSyncReportResponse, error)
+}
+
+// NewCatalogSyncCommand returns a command that pushes price plans to a payment provider's
+// catalog and prints the sync report as JSON. The syncer is only loaded when the command
+// runs, so hosts can connect to their database inside loadSyncer without slowing --help.
+// The command fails when any product or price could not be checked or changed.
+func NewCatalogSyncCommand(loadSyncer func(ctx context.Context) (PriceCatalogSyncer, error)) *cobra.Command {
+	var (
+		providerName string
+		planSlugs    []string
+		dryRun       bool
+	)
+
+	command := &cobra.Command{
+		Use:           "catalog-sync",
+		Short:         "Push price plans to a payment provider and report drift",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		Args:          cobra.NoArgs,
+		RunE: func(command *cobra.Command, _ []string) error {
+			syncer, err := loadSyncer(command.Context())
+			if err != nil {
+				return fmt.Errorf("billingmanager/unable-to-load-catalog-syncer: %w", err)
+			}
+
+			response, err := syncer.SyncPriceCatalog(command.Context(), &SyncPriceCatalogRequest{
+				ProviderName:     providerName,
+				PlanSlugs:        planSlugs,
+				DryRun:           dryRun,
+				RequestingUserID: audit.AuditActorIdSystem,
+			})
+			if err != nil {
+				return fmt.Errorf("billingmanager/catalog-sync-failed: %w", err)
+			}
+
+			encoder := json.NewEncoder(command.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(response.Report); err != nil {
+				return fmt.Errorf("billingmanager/unable-to-write-catalog-sync-report: %w", err)
+			}
+
+			if response.Report.Failed > 0 {
+				return fmt.Errorf("billingmanager/catalog-sync-had-failures: %d", response.Report.Failed)
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&providerName, "provider", "", "payment provider to sync, e.g. stripe or lemonsqueezy")
+	command.Flags().StringSliceVar(&planSlugs, "plan", nil, "limit the sync to these price plan slugs (repeatable)")
+	command.Flags().BoolVar(&dryRun, "dry-run", false, "report drift without changing the provider or the catalog")
+	_ = command.MarkFlagRequired("provider")
+
+	return command
+}
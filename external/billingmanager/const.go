@@ -1,13 +1,230 @@
 package billingmanager
 
-import "github.com/ooaklee/ghatd/external/audit"
+import (
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+)
 
 const (
 	// AuditActionBillingWebhookProcessed occurs when a billing webhook is processed
 	AuditActionBillingWebhookProcessed audit.AuditAction = "BILLING_WEBHOOK_PROCESSED"
 
+	// AuditActionBillingSubscriptionReconciled occurs when a subscription is corrected to match the payment provider
+	AuditActionBillingSubscriptionReconciled audit.AuditAction = "BILLING_SUBSCRIPTION_RECONCILED"
+
+	// AuditActionBillingDunningStarted occurs when a subscription's failed payment enters dunning
+	AuditActionBillingDunningStarted audit.AuditAction = "BILLING_DUNNING_STARTED"
+
+	// AuditActionBillingDunningReminderSent occurs when a dunning reminder is sent for a subscription
+	AuditActionBillingDunningReminderSent audit.AuditAction = "BILLING_DUNNING_REMINDER_SENT"
+
+	// AuditActionBillingDunningSuspended occurs when a subscription is suspended after its dunning grace period
+	AuditActionBillingDunningSuspended audit.AuditAction = "BILLING_DUNNING_SUSPENDED"
+
+	// AuditActionBillingDunningRecovered occurs when a subscription in dunning is paid and leaves dunning
+	AuditActionBillingDunningRecovered audit.AuditAction = "BILLING_DUNNING_RECOVERED"
+
+	// AuditActionBillingSubscriptionPlanVersionMigrated occurs when a subscription is moved from a superseded
+	// price plan version to a newer one
+	AuditActionBillingSubscriptionPlanVersionMigrated audit.AuditAction = "BILLING_SUBSCRIPTION_PLAN_VERSION_MIGRATED"
+
+	// AuditActionBillingPricePlanCatalogSynced occurs when a price plan's products and prices are pushed to
+	// a payment provider and the provider references are written back to the plan
+	AuditActionBillingPricePlanCatalogSynced audit.AuditAction = "BILLING_PRICE_PLAN_CATALOG_SYNCED"
+
+	// AuditActionBillingInvoiceIssued occurs when an invoice is issued for a successful payment
+	AuditActionBillingInvoiceIssued audit.AuditAction = "BILLING_INVOICE_ISSUED"
+
+	// AuditActionBillingSubscriptionCancelled occurs when an admin cancels a subscription
+	AuditActionBillingSubscriptionCancelled audit.AuditAction = "BILLING_SUBSCRIPTION_CANCELLED"
+
+	// AuditActionBillingSubscriptionPaused occurs when an admin pauses a subscription
+	AuditActionBillingSubscriptionPaused audit.AuditAction = "BILLING_SUBSCRIPTION_PAUSED"
+
+	// AuditActionBillingPaymentRefunded occurs when an admin refunds a payment
+	AuditActionBillingPaymentRefunded audit.AuditAction = "BILLING_PAYMENT_REFUNDED"
+
+	// AuditActionBillingComplimentarySubscriptionGranted occurs when an admin grants a complimentary subscription
+	AuditActionBillingComplimentarySubscriptionGranted audit.AuditAction = "BILLING_COMPLIMENTARY_SUBSCRIPTION_GRANTED"
+
 	// TargetTypeWebhook represents webhook event
 	TargetTypeWebhook audit.TargetType = "WEBHOOK"
+
+	// TargetTypeSubscription represents a billing subscription
+	TargetTypeSubscription audit.TargetType = "SUBSCRIPTION"
+
+	// TargetTypePricePlan represents a price plan
+	TargetTypePricePlan audit.TargetType = "PRICE_PLAN"
+
+	// TargetTypeInvoice represents an invoice
+	TargetTypeInvoice audit.TargetType = "INVOICE"
+
+	// TargetTypeBillingEvent represents a billing event
+	TargetTypeBillingEvent audit.TargetType = "BILLING_EVENT"
+)
+
+const (
+	// DefaultGroupSeatsWithoutSubscription is the member limit applied to a group once it
+	// no longer has an active group-owned subscription
+	DefaultGroupSeatsWithoutSubscription = 1
+
+	// EventTypeSubscriptionReconciled is the billing event type recorded when reconciliation
+	// corrects a subscription to match the payment provider
+	EventTypeSubscriptionReconciled = "subscription.reconciled"
+
+	// EventTypeSubscriptionPlanVersionMigrated is the billing event type recorded when a subscription
+	// is moved from a superseded price plan version to a newer one
+	EventTypeSubscriptionPlanVersionMigrated = "subscription.plan_version_migrated"
+
+	// defaultReconciliationLimit is the number of subscriptions checked per reconciliation run
+	defaultReconciliationLimit = 100
+
+	// maxReconciliationLimit is the largest number of subscriptions checked per reconciliation run
+	maxReconciliationLimit = 1000
+
+	// defaultReconciliationInterval is how often the scheduled reconciliation runs
+	defaultReconciliationInterval = time.Hour
+
+	// reconciliationPageSize is the number of subscriptions loaded per page while
+	// collecting reconciliation candidates
+	reconciliationPageSize = 100
+
+	// catalogSyncPageSize is the number of price plans loaded per page during a catalog sync
+	catalogSyncPageSize = 100
+
+	// CatalogSyncResourceProduct is the catalog sync resource for a price plan's provider product
+	CatalogSyncResourceProduct = "product"
+
+	// CatalogSyncResourcePrice is the catalog sync resource for a plan cost's provider price
+	CatalogSyncResourcePrice = "price"
+
+	// CatalogSyncActionCreate means the product or price is missing on the provider
+	CatalogSyncActionCreate = "create"
+
+	// CatalogSyncActionUpdate means the product differs from the price plan and is updated in place
+	CatalogSyncActionUpdate = "update"
+
+	// CatalogSyncActionReplace means the price differs from the plan cost, so a new price is
+	// created and the old one archived
+	CatalogSyncActionReplace = "replace"
+
+	// CatalogSyncActionManual means the change must be made in the provider's dashboard
+	CatalogSyncActionManual = "manual"
+
+	// CatalogMetadataKeyPricePlanID is the provider metadata key holding the price plan ID
+	CatalogMetadataKeyPricePlanID = "price_plan_id"
+
+	// CatalogMetadataKeyPricePlanSlug is the provider metadata key holding the price plan slug
+	CatalogMetadataKeyPricePlanSlug = "price_plan_slug"
+
+	// CatalogMetadataKeyPriceCostID is the provider metadata key holding the plan cost ID
+	CatalogMetadataKeyPriceCostID = "price_cost_id"
+
+	// defaultDunningGracePeriod is how long a subscription keeps its entitlements after a
+	// failed payment before it is suspended
+	defaultDunningGracePeriod = 14 * 24 * time.Hour
+
+	// defaultDunningLimit is the number of subscriptions progressed per dunning run
+	defaultDunningLimit = 100
+
+	// maxDunningLimit is the largest number of subscriptions progressed per dunning run
+	maxDunningLimit = 1000
+
+	// defaultDunningInterval is how often the scheduled dunning run progresses subscriptions
+	defaultDunningInterval = time.Hour
+
+	// defaultPlanVersionMigrationInterval is how often the scheduled price plan version migration runs
+	defaultPlanVersionMigrationInterval = time.Hour
+
+	// planVersionMigrationPageSize is the number of subscriptions loaded per page while
+	// collecting the subscribers of a price plan version
+	planVersionMigrationPageSize = 100
+
+	// complimentarySubscriptionIDPrefix prefixes the generated subscription ID of complimentary subscriptions
+	complimentarySubscriptionIDPrefix = "comp_"
+)
+
+const (
+	// DunningReminderEmailSubjectTmpl is the subject of a dunning reminder email, formatted with the plan name
+	DunningReminderEmailSubjectTmpl = "Action needed: payment for your %s plan failed"
+
+	// DunningReminderNotificationTitle is the title of a dunning reminder in-app notification
+	DunningReminderNotificationTitle = "Payment failed"
+
+	// DunningReminderMessageTmpl is the plain text of a dunning reminder, formatted with the plan name
+	// and the date access is suspended
+	DunningReminderMessageTmpl = "We could not take payment for your %s plan. Please update your payment details before %s to keep access."
+
+	// DunningSuspendedEmailSubjectTmpl is the subject of the email sent when a subscription is suspended,
+	// formatted with the plan name
+	DunningSuspendedEmailSubjectTmpl = "Your %s plan has been suspended"
+
+	// DunningSuspendedNotificationTitle is the title of the in-app notification sent when a subscription is suspended
+	DunningSuspendedNotificationTitle = "Plan suspended"
+
+	// DunningSuspendedMessageTmpl is the plain text sent when a subscription is suspended, formatted with the plan name
+	DunningSuspendedMessageTmpl = "We still could not take payment for your %s plan so its features have been suspended. Update your payment details to restore access."
+
+	// DunningEmailBodyTmpl is the template for the body of dunning emails, formatted with the message
+	// and the update payment link paragraph (which may be empty)
+	DunningEmailBodyTmpl string = `<td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">
+	<br>
+	<p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 15px;">
+		%s
+	</p>
+	%s
+</td>`
+
+	// DunningEmailUpdatePaymentLinkTmpl is the paragraph linking to the provider's update payment page,
+	// formatted with the update URL
+	DunningEmailUpdatePaymentLinkTmpl string = `<p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 15px;">
+		<a href="%s" target="_blank">Update your payment details</a>
+	</p>`
+)
+
+const (
+	// InvoiceEmailSubjectTmpl is the subject of an invoice email, formatted with the invoice number
+	InvoiceEmailSubjectTmpl = "Your invoice %s"
+
+	// InvoiceEmailMessageTmpl is the plain text of an invoice email, formatted with the amount paid,
+	// what it was for and the date it was issued
+	InvoiceEmailMessageTmpl = "Thank you for your payment of %s for %s. Your invoice was issued on %s and can be downloaded from your account at any time."
+
+	// InvoiceEmailBodyTmpl is the template for the body of invoice emails, formatted with the HTML
+	// escaped message and invoice number
+	InvoiceEmailBodyTmpl string = `<td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">
+	<br>
+	<p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 15px;">
+		%s
+	</p>
+	<p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 15px;">
+		Invoice number: <strong>%s</strong>
+	</p>
+</td>`
+)
+
+const (
+	// BillingManagerURIVariableWebhookDeliveryID is the URI variable holding a webhook delivery ID
+	BillingManagerURIVariableWebhookDeliveryID = "deliveryId"
+
+	// BillingManagerURIVariableEntitlementGrantID is the URI variable holding an entitlement grant ID
+	BillingManagerURIVariableEntitlementGrantID = "grantId"
+
+	// BillingManagerURIVariableEntitlementSubjectType is the URI variable holding an entitlement subject type
+	BillingManagerURIVariableEntitlementSubjectType = "subjectType"
+
+	// BillingManagerURIVariableEntitlementSubjectID is the URI variable holding an entitlement subject ID
+	BillingManagerURIVariableEntitlementSubjectID = "subjectId"
+
+	// BillingManagerURIVariableInvoiceID is the URI variable holding an invoice ID
+	BillingManagerURIVariableInvoiceID = "invoiceId"
+
+	// BillingManagerURIVariableSubscriptionID is the URI variable holding a subscription ID
+	BillingManagerURIVariableSubscriptionID = "subscriptionId"
+
+	// BillingManagerURIVariableBillingEventID is the URI variable holding a billing event ID
+	BillingManagerURIVariableBillingEventID = "eventId"
 )
 
 const (
@@ -52,4 +269,107 @@ const (
 
 	// ErrKeyBillingManagerPricerServiceNotSet is returned when pricing endpoints are used without pricer service wiring.
 	ErrKeyBillingManagerPricerServiceNotSet = "BillingManagerPricerServiceNotSet"
+
+	// ErrKeyBillingManagerWebhookInboxServiceNotSet is returned when webhook delivery endpoints are used without webhook inbox wiring.
+	ErrKeyBillingManagerWebhookInboxServiceNotSet = "BillingManagerWebhookInboxServiceNotSet"
+
+	// ErrKeyBillingManagerUnableToGetWebhookDeliveryIdFromURI is returned when the webhook delivery ID cannot be extracted from the URI
+	ErrKeyBillingManagerUnableToGetWebhookDeliveryIdFromURI = "BillingManagerUnableToGetWebhookDeliveryIdFromURI"
+
+	// ErrKeyBillingManagerWebhookDeliveryAlreadyProcessed is returned when replaying a webhook delivery that was processed successfully
+	ErrKeyBillingManagerWebhookDeliveryAlreadyProcessed = "BillingManagerWebhookDeliveryAlreadyProcessed"
+
+	// ErrKeyBillingManagerInvalidWebhookDeliveryPayload is returned when a stored webhook delivery payload cannot be decoded
+	ErrKeyBillingManagerInvalidWebhookDeliveryPayload = "BillingManagerInvalidWebhookDeliveryPayload"
+
+	// ErrKeyBillingManagerCheckoutNotSupported is returned when the provider registry cannot create checkout or customer portal sessions
+	ErrKeyBillingManagerCheckoutNotSupported = "BillingManagerCheckoutNotSupported"
+
+	// ErrKeyBillingManagerNoProviderPriceForPlan is returned when a plan has no cost linked to a price on the requested payment provider
+	ErrKeyBillingManagerNoProviderPriceForPlan = "BillingManagerNoProviderPriceForPlan"
+
+	// ErrKeyBillingManagerNoProviderCustomerForUser is returned when a user has no subscription holding a payment provider customer ID
+	ErrKeyBillingManagerNoProviderCustomerForUser = "BillingManagerNoProviderCustomerForUser"
+
+	// ErrKeyBillingManagerEntitlementServiceNotSet is returned when entitlement endpoints are used without entitlement service wiring
+	ErrKeyBillingManagerEntitlementServiceNotSet = "BillingManagerEntitlementServiceNotSet"
+
+	// ErrKeyBillingManagerMeteringServiceNotSet is returned when usage endpoints are used without metering service wiring
+	ErrKeyBillingManagerMeteringServiceNotSet = "BillingManagerMeteringServiceNotSet"
+
+	// ErrKeyBillingManagerReconciliationNotSupported is returned when the provider registry cannot look up subscriptions
+	ErrKeyBillingManagerReconciliationNotSupported = "BillingManagerReconciliationNotSupported"
+
+	// ErrKeyBillingManagerReconciliationInProgress is returned when a reconciliation is started while another is repairing subscriptions
+	ErrKeyBillingManagerReconciliationInProgress = "BillingManagerReconciliationInProgress"
+
+	// ErrKeyBillingManagerGroupServiceNotSet is returned when group billing is used without group service wiring
+	ErrKeyBillingManagerGroupServiceNotSet = "BillingManagerGroupServiceNotSet"
+
+	// ErrKeyBillingManagerUserNotGroupBillingAdmin is returned when a user tries to manage billing for a group they are not a billing admin of
+	ErrKeyBillingManagerUserNotGroupBillingAdmin = "BillingManagerUserNotGroupBillingAdmin"
+
+	// ErrKeyBillingManagerDunningNotConfigured is returned when dunning is run without a dunning schedule
+	ErrKeyBillingManagerDunningNotConfigured = "BillingManagerDunningNotConfigured"
+
+	// ErrKeyBillingManagerDunningInProgress is returned when a dunning run is started while another is in progress
+	ErrKeyBillingManagerDunningInProgress = "BillingManagerDunningInProgress"
+
+	// ErrKeyBillingManagerPlanVersioningNotSupported is returned when the pricer service does not keep price plan versions
+	ErrKeyBillingManagerPlanVersioningNotSupported = "BillingManagerPlanVersioningNotSupported"
+
+	// ErrKeyBillingManagerPlanVersionMigrationInProgress is returned when a price plan version migration is started while another is in progress
+	ErrKeyBillingManagerPlanVersionMigrationInProgress = "BillingManagerPlanVersionMigrationInProgress"
+
+	// ErrKeyBillingManagerPriceQuotesNotSupported is returned when the pricer service cannot quote price plans
+	ErrKeyBillingManagerPriceQuotesNotSupported = "BillingManagerPriceQuotesNotSupported"
+
+	// ErrKeyBillingManagerPromotionCodesNotSupported is returned when the pricer service cannot validate or redeem promotion codes
+	ErrKeyBillingManagerPromotionCodesNotSupported = "BillingManagerPromotionCodesNotSupported"
+
+	// ErrKeyBillingManagerPromotionCodeNotMappedToProvider is returned when a promotion code has no coupon on the requested payment provider
+	ErrKeyBillingManagerPromotionCodeNotMappedToProvider = "BillingManagerPromotionCodeNotMappedToProvider"
+
+	// ErrKeyBillingManagerCatalogSyncNotSupported is returned when the provider registry or payment provider cannot read
+	// products and prices, or the pricer service cannot have provider references written back
+	ErrKeyBillingManagerCatalogSyncNotSupported = "BillingManagerCatalogSyncNotSupported"
+
+	// ErrKeyBillingManagerCatalogSyncInProgress is returned when a catalog sync is started while another is changing the provider
+	ErrKeyBillingManagerCatalogSyncInProgress = "BillingManagerCatalogSyncInProgress"
+
+	// ErrKeyBillingManagerInvoiceServiceNotSet is returned when invoice endpoints are used without invoice service wiring
+	ErrKeyBillingManagerInvoiceServiceNotSet = "BillingManagerInvoiceServiceNotSet"
+
+	// ErrKeyBillingManagerUnableToGetInvoiceIdFromURI is returned when the invoice ID cannot be extracted from the URI
+	ErrKeyBillingManagerUnableToGetInvoiceIdFromURI = "BillingManagerUnableToGetInvoiceIdFromURI"
+
+	// ErrKeyBillingManagerEmailManagerNotSet is returned when an invoice is emailed without email manager wiring
+	ErrKeyBillingManagerEmailManagerNotSet = "BillingManagerEmailManagerNotSet"
+
+	// ErrKeyBillingManagerInvoiceHasNoEmail is returned when an invoice is emailed but neither it nor its user has an email address
+	ErrKeyBillingManagerInvoiceHasNoEmail = "BillingManagerInvoiceHasNoEmail"
+
+	// ErrKeyBillingManagerSubscriptionManagementNotSupported is returned when the provider registry cannot cancel or pause subscriptions
+	ErrKeyBillingManagerSubscriptionManagementNotSupported = "BillingManagerSubscriptionManagementNotSupported"
+
+	// ErrKeyBillingManagerRefundsNotSupported is returned when the provider registry cannot refund payments
+	ErrKeyBillingManagerRefundsNotSupported = "BillingManagerRefundsNotSupported"
+
+	// ErrKeyBillingManagerUnableToGetSubscriptionIdFromURI is returned when the subscription ID cannot be extracted from the URI
+	ErrKeyBillingManagerUnableToGetSubscriptionIdFromURI = "BillingManagerUnableToGetSubscriptionIdFromURI"
+
+	// ErrKeyBillingManagerUnableToGetBillingEventIdFromURI is returned when the billing event ID cannot be extracted from the URI
+	ErrKeyBillingManagerUnableToGetBillingEventIdFromURI = "BillingManagerUnableToGetBillingEventIdFromURI"
+
+	// ErrKeyBillingManagerSubscriptionNotChangeable is returned when cancelling or pausing a subscription that is already cancelled or paused
+	ErrKeyBillingManagerSubscriptionNotChangeable = "BillingManagerSubscriptionNotChangeable"
+
+	// ErrKeyBillingManagerBillingEventNotRefundable is returned when refunding a billing event that is not a successful provider payment
+	ErrKeyBillingManagerBillingEventNotRefundable = "BillingManagerBillingEventNotRefundable"
+
+	// ErrKeyBillingManagerInvalidRefundAmount is returned when a refund amount is negative or more than was paid
+	ErrKeyBillingManagerInvalidRefundAmount = "BillingManagerInvalidRefundAmount"
+
+	// ErrKeyBillingManagerInvalidComplimentarySubscription is returned when a complimentary subscription has no subscriber or an invalid end date
+	ErrKeyBillingManagerInvalidComplimentarySubscription = "BillingManagerInvalidComplimentarySubscription"
 )
@@ -20,4 +20,38 @@ var BillingManagerErrorMap reply.ErrorManifest = reply.ErrorManifest{
 	ErrBillingManagerUserUnauthorisedToCarryOutOperation:   {Title: "Forbidden", Detail: "User not authorised to carry out operation", StatusCode: 403, Code: "BM00-011"},
 	ErrBillingManagerNoUserIdentifyingInformationInPayload: {Title: "Bad Request", Detail: "No user identifying information present in payload", StatusCode: 400, Code: "BM00-012"},
 	ErrBillingManagerPricerServiceNotSet:                   {Title: "Internal Server Error", Detail: "Pricing service is not configured", StatusCode: 500, Code: "BM00-013"},
+	ErrBillingManagerWebhookInboxServiceNotSet:             {Title: "Internal Server Error", Detail: "Webhook inbox service is not configured", StatusCode: 500, Code: "BM00-014"},
+	ErrBillingManagerUnableToGetWebhookDeliveryIdFromURI:   {Title: "Bad Request", Detail: "Unable to get webhook delivery ID from URI", StatusCode: 400, Code: "BM00-015"},
+	ErrBillingManagerWebhookDeliveryAlreadyProcessed:       {Title: "Conflict", Detail: "Webhook delivery has already been processed", StatusCode: 409, Code: "BM00-016"},
+	ErrBillingManagerInvalidWebhookDeliveryPayload:         {Title: "Internal Server Error", Detail: "Stored webhook delivery payload is invalid", StatusCode: 500, Code: "BM00-017"},
+	ErrBillingManagerCheckoutNotSupported:                  {Title: "Not Implemented", Detail: "Checkout and customer portal sessions are not supported", StatusCode: 501, Code: "BM00-018"},
+	ErrBillingManagerNoProviderPriceForPlan:                {Title: "Bad Request", Detail: "Plan has no price for the requested payment provider", StatusCode: 400, Code: "BM00-019"},
+	ErrBillingManagerNoProviderCustomerForUser:             {Title: "Not Found", Detail: "No payment provider customer found for user", StatusCode: 404, Code: "BM00-020"},
+	ErrBillingManagerEntitlementServiceNotSet:              {Title: "Internal Server Error", Detail: "Entitlement service is not configured", StatusCode: 500, Code: "BM00-021"},
+	ErrBillingManagerMeteringServiceNotSet:                 {Title: "Internal Server Error", Detail: "Metering service is not configured", StatusCode: 500, Code: "BM00-022"},
+	ErrBillingManagerReconciliationNotSupported:            {Title: "Not Implemented", Detail: "Subscription reconciliation is not supported", StatusCode: 501, Code: "BM00-023"},
+	ErrBillingManagerReconciliationInProgress:              {Title: "Conflict", Detail: "Subscription reconciliation is already in progress", StatusCode: 409, Code: "BM00-024"},
+	ErrBillingManagerGroupServiceNotSet:                    {Title: "Internal Server Error", Detail: "Group service is not configured", StatusCode: 500, Code: "BM00-025"},
+	ErrBillingManagerUserNotGroupBillingAdmin:              {Title: "Forbidden", Detail: "User is not a billing admin of the group", StatusCode: 403, Code: "BM00-026"},
+	ErrBillingManagerDunningNotConfigured:                  {Title: "Not Implemented", Detail: "Dunning is not configured", StatusCode: 501, Code: "BM00-027"},
+	ErrBillingManagerDunningInProgress:                     {Title: "Conflict", Detail: "Dunning is already in progress", StatusCode: 409, Code: "BM00-028"},
+	ErrBillingManagerPlanVersioningNotSupported:            {Title: "Not Implemented", Detail: "Price plan versioning is not supported", StatusCode: 501, Code: "BM00-029"},
+	ErrBillingManagerPlanVersionMigrationInProgress:        {Title: "Conflict", Detail: "Price plan version migration is already in progress", StatusCode: 409, Code: "BM00-030"},
+	ErrBillingManagerPriceQuotesNotSupported:               {Title: "Not Implemented", Detail: "Price quotes are not supported", StatusCode: 501, Code: "BM00-031"},
+	ErrBillingManagerPromotionCodesNotSupported:            {Title: "Not Implemented", Detail: "Promotion codes are not supported", StatusCode: 501, Code: "BM00-032"},
+	ErrBillingManagerPromotionCodeNotMappedToProvider:      {Title: "Bad Request", Detail: "Promotion code cannot be used with the requested payment provider", StatusCode: 400, Code: "BM00-033"},
+	ErrBillingManagerCatalogSyncNotSupported:               {Title: "Not Implemented", Detail: "Catalog sync is not supported for the payment provider", StatusCode: 501, Code: "BM00-034"},
+	ErrBillingManagerCatalogSyncInProgress:                 {Title: "Conflict", Detail: "Catalog sync is already in progress", StatusCode: 409, Code: "BM00-035"},
+	ErrBillingManagerInvoiceServiceNotSet:                  {Title: "Internal Server Error", Detail: "Invoice service is not configured", StatusCode: 500, Code: "BM00-036"},
+	ErrBillingManagerUnableToGetInvoiceIdFromURI:           {Title: "Bad Request", Detail: "Unable to get invoice ID from URI", StatusCode: 400, Code: "BM00-037"},
+	ErrBillingManagerEmailManagerNotSet:                    {Title: "Internal Server Error", Detail: "Email manager is not configured", StatusCode: 500, Code: "BM00-038"},
+	ErrBillingManagerInvoiceHasNoEmail:                     {Title: "Unprocessable Entity", Detail: "Invoice has no email address to send it to", StatusCode: 422, Code: "BM00-039"},
+	ErrBillingManagerSubscriptionManagementNotSupported:    {Title: "Not Implemented", Detail: "Payment provider does not support cancelling or pausing subscriptions", StatusCode: 501, Code: "BM00-040"},
+	ErrBillingManagerRefundsNotSupported:                   {Title: "Not Implemented", Detail: "Payment provider does not support refunds", StatusCode: 501, Code: "BM00-041"},
+	ErrBillingManagerUnableToGetSubscriptionIdFromURI:      {Title: "Bad Request", Detail: "Unable to get subscription ID from URI", StatusCode: 400, Code: "BM00-042"},
+	ErrBillingManagerUnableToGetBillingEventIdFromURI:      {Title: "Bad Request", Detail: "Unable to get billing event ID from URI", StatusCode: 400, Code: "BM00-043"},
+	ErrBillingManagerSubscriptionNotChangeable:             {Title: "Conflict", Detail: "Subscription is already cancelled or paused", StatusCode: 409, Code: "BM00-044"},
+	ErrBillingManagerBillingEventNotRefundable:             {Title: "Unprocessable Entity", Detail: "Billing event is not a refundable payment", StatusCode: 422, Code: "BM00-045"},
+	ErrBillingManagerInvalidRefundAmount:                   {Title: "Bad Request", Detail: "Refund amount must be positive and no more than the amount paid", StatusCode: 400, Code: "BM00-046"},
+	ErrBillingManagerInvalidComplimentarySubscription:      {Title: "Bad Request", Detail: "Complimentary subscription requires a user or group and a valid end date", StatusCode: 400, Code: "BM00-047"},
 }
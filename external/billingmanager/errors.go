@@ -3,17 +3,51 @@ package billingmanager
 import "errors"
 
 var (
+	ErrBillingManagerCatalogSyncInProgress                 = errors.New(ErrKeyBillingManagerCatalogSyncInProgress)
+	ErrBillingManagerCatalogSyncNotSupported               = errors.New(ErrKeyBillingManagerCatalogSyncNotSupported)
+	ErrBillingManagerCheckoutNotSupported                  = errors.New(ErrKeyBillingManagerCheckoutNotSupported)
+	ErrBillingManagerDunningInProgress                     = errors.New(ErrKeyBillingManagerDunningInProgress)
+	ErrBillingManagerDunningNotConfigured                  = errors.New(ErrKeyBillingManagerDunningNotConfigured)
+	ErrBillingManagerEmailManagerNotSet                    = errors.New(ErrKeyBillingManagerEmailManagerNotSet)
+	ErrBillingManagerEntitlementServiceNotSet              = errors.New(ErrKeyBillingManagerEntitlementServiceNotSet)
 	ErrBillingManagerFailedToProcessEvent                  = errors.New(ErrKeyBillingManagerFailedToProcessEvent)
 	ErrBillingManagerFailedToRetrieveBillingEvents         = errors.New(ErrKeyBillingManagerFailedToRetrieveBillingEvents)
 	ErrBillingManagerFailedToRetrieveSubscriptionStatus    = errors.New(ErrKeyBillingManagerFailedToRetrieveSubscriptionStatus)
+	ErrBillingManagerBillingEventNotRefundable             = errors.New(ErrKeyBillingManagerBillingEventNotRefundable)
 	ErrBillingManagerFailedWebhookVerification             = errors.New(ErrKeyBillingManagerFailedWebhookVerification)
+	ErrBillingManagerGroupServiceNotSet                    = errors.New(ErrKeyBillingManagerGroupServiceNotSet)
+	ErrBillingManagerInvalidComplimentarySubscription      = errors.New(ErrKeyBillingManagerInvalidComplimentarySubscription)
+	ErrBillingManagerInvalidRefundAmount                   = errors.New(ErrKeyBillingManagerInvalidRefundAmount)
+	ErrBillingManagerInvalidWebhookDeliveryPayload         = errors.New(ErrKeyBillingManagerInvalidWebhookDeliveryPayload)
+	ErrBillingManagerInvoiceHasNoEmail                     = errors.New(ErrKeyBillingManagerInvoiceHasNoEmail)
+	ErrBillingManagerInvoiceServiceNotSet                  = errors.New(ErrKeyBillingManagerInvoiceServiceNotSet)
+	ErrBillingManagerMeteringServiceNotSet                 = errors.New(ErrKeyBillingManagerMeteringServiceNotSet)
+	ErrBillingManagerNoProviderCustomerForUser             = errors.New(ErrKeyBillingManagerNoProviderCustomerForUser)
+	ErrBillingManagerNoProviderPriceForPlan                = errors.New(ErrKeyBillingManagerNoProviderPriceForPlan)
 	ErrBillingManagerNoUserIdentifyingInformationInPayload = errors.New(ErrKeyBillingManagerNoUserIdentifyingInformationInPayload)
+	ErrBillingManagerPlanVersionMigrationInProgress        = errors.New(ErrKeyBillingManagerPlanVersionMigrationInProgress)
+	ErrBillingManagerPlanVersioningNotSupported            = errors.New(ErrKeyBillingManagerPlanVersioningNotSupported)
+	ErrBillingManagerPriceQuotesNotSupported               = errors.New(ErrKeyBillingManagerPriceQuotesNotSupported)
 	ErrBillingManagerPricerServiceNotSet                   = errors.New(ErrKeyBillingManagerPricerServiceNotSet)
+	ErrBillingManagerPromotionCodeNotMappedToProvider      = errors.New(ErrKeyBillingManagerPromotionCodeNotMappedToProvider)
+	ErrBillingManagerPromotionCodesNotSupported            = errors.New(ErrKeyBillingManagerPromotionCodesNotSupported)
+	ErrBillingManagerReconciliationInProgress              = errors.New(ErrKeyBillingManagerReconciliationInProgress)
+	ErrBillingManagerReconciliationNotSupported            = errors.New(ErrKeyBillingManagerReconciliationNotSupported)
+	ErrBillingManagerRefundsNotSupported                   = errors.New(ErrKeyBillingManagerRefundsNotSupported)
 	ErrBillingManagerRequiresUserIdIsMissing               = errors.New(ErrKeyBillingManagerRequiresUserIdIsMissing)
+	ErrBillingManagerSubscriptionManagementNotSupported    = errors.New(ErrKeyBillingManagerSubscriptionManagementNotSupported)
+	ErrBillingManagerSubscriptionNotChangeable             = errors.New(ErrKeyBillingManagerSubscriptionNotChangeable)
+	ErrBillingManagerUnableToGetBillingEventIdFromURI      = errors.New(ErrKeyBillingManagerUnableToGetBillingEventIdFromURI)
+	ErrBillingManagerUnableToGetInvoiceIdFromURI           = errors.New(ErrKeyBillingManagerUnableToGetInvoiceIdFromURI)
 	ErrBillingManagerUnableToGetProviderNameFromURI        = errors.New(ErrKeyBillingManagerUnableToGetProviderNameFromURI)
+	ErrBillingManagerUnableToGetSubscriptionIdFromURI      = errors.New(ErrKeyBillingManagerUnableToGetSubscriptionIdFromURI)
 	ErrBillingManagerUnableToGetUserIdFromURI              = errors.New(ErrKeyBillingManagerUnableToGetUserIdFromURI)
+	ErrBillingManagerUnableToGetWebhookDeliveryIdFromURI   = errors.New(ErrKeyBillingManagerUnableToGetWebhookDeliveryIdFromURI)
 	ErrBillingManagerUnableToIdentifyUser                  = errors.New(ErrKeyBillingManagerUnableToIdentifyUser)
 	ErrBillingManagerUnableToResolveUserId                 = errors.New(ErrKeyBillingManagerUnableToResolveUserId)
+	ErrBillingManagerUserNotGroupBillingAdmin              = errors.New(ErrKeyBillingManagerUserNotGroupBillingAdmin)
 	ErrBillingManagerUserUnauthorisedToCarryOutOperation   = errors.New(ErrKeyBillingManagerUserUnauthorisedToCarryOutOperation)
+	ErrBillingManagerWebhookDeliveryAlreadyProcessed       = errors.New(ErrKeyBillingManagerWebhookDeliveryAlreadyProcessed)
+	ErrBillingManagerWebhookInboxServiceNotSet             = errors.New(ErrKeyBillingManagerWebhookInboxServiceNotSet)
 	ErrInvalidBillingManagerRequestPayload                 = errors.New(ErrKeyInvalidBillingManagerRequestPayload)
 )
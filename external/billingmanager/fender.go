@@ -1,10 +1,16 @@
 package billingmanager
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/invoice"
 	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/metering"
 	"github.com/ooaklee/ghatd/external/pricer"
 	"github.com/ooaklee/ghatd/external/toolbox"
 	"github.com/ritwickdey/querydecoder"
@@ -138,6 +144,19 @@ func MapRequestToGetPricePlanBySlugRequest(request *http.Request, validator Bill
 	}, nil
 }
 
+// MapRequestToQuotePricePlanRequest maps incoming BMS price plan quote requests.
+func MapRequestToQuotePricePlanRequest(request *http.Request, validator BillingManagerValidator) (*QuotePricePlanRequest, error) {
+	parsedRequest, err := pricer.MapRequestToQuotePricePlanRequest(request, validator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotePricePlanRequest{
+		UserID:                accessmanagerhelpers.AcquireFrom(request.Context()),
+		QuotePricePlanRequest: parsedRequest,
+	}, nil
+}
+
 // MapRequestToGetPriceFeaturesRequest maps incoming BMS price feature list requests.
 func MapRequestToGetPriceFeaturesRequest(request *http.Request, validator BillingManagerValidator) (*GetPriceFeaturesRequest, error) {
 	parsedRequest, err := pricer.MapRequestToGetFeaturesRequest(request, validator)
@@ -150,3 +169,594 @@ func MapRequestToGetPriceFeaturesRequest(request *http.Request, validator Billin
 		GetFeaturesRequest: parsedRequest,
 	}, nil
 }
+
+// mapRequestToGetWebhookDeliveriesRequest maps incoming GetWebhookDeliveries request to correct
+// struct.
+func mapRequestToGetWebhookDeliveriesRequest(request *http.Request, validator BillingManagerValidator) (*GetWebhookDeliveriesRequest, error) {
+	var parsedRequest GetWebhookDeliveriesRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	query := request.URL.Query()
+	err := querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-webhook-deliveries-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	for _, status := range parsedRequest.Statuses {
+		if !isValidWebhookDeliveryStatus(status) {
+			logger.Warn("invalid-webhook-delivery-status-filter", zap.String("status", status))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToReplayWebhookDeliveryRequest maps incoming ReplayWebhookDelivery request to correct
+// struct.
+func mapRequestToReplayWebhookDeliveryRequest(request *http.Request, validator BillingManagerValidator) (*ReplayWebhookDeliveryRequest, error) {
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	deliveryId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableWebhookDeliveryID)
+	if err != nil {
+		logger.Error("unable-get-webhook-delivery-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrBillingManagerUnableToGetWebhookDeliveryIdFromURI
+	}
+
+	return &ReplayWebhookDeliveryRequest{DeliveryID: deliveryId}, nil
+}
+
+// mapRequestToCreateCheckoutSessionRequest maps incoming CreateCheckoutSession request to correct
+// struct.
+func mapRequestToCreateCheckoutSessionRequest(request *http.Request, validator BillingManagerValidator) (*CreateCheckoutSessionRequest, error) {
+	var parsedRequest CreateCheckoutSessionRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil {
+		logger.Error("unable-to-decode-checkout-session-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-checkout-session-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	parsedRequest.UserID = requestingUserId
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToCreateCustomerPortalSessionRequest maps incoming CreateCustomerPortalSession request to correct
+// struct.
+func mapRequestToCreateCustomerPortalSessionRequest(request *http.Request, validator BillingManagerValidator) (*CreateCustomerPortalSessionRequest, error) {
+	var parsedRequest CreateCustomerPortalSessionRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	if request.ContentLength != 0 {
+		if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil {
+			logger.Error("unable-to-decode-customer-portal-session-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	parsedRequest.UserID = requestingUserId
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToGetMyEntitlementsRequest maps incoming GetMyEntitlements request to correct
+// struct.
+func mapRequestToGetMyEntitlementsRequest(request *http.Request, validator BillingManagerValidator) (*GetMyEntitlementsRequest, error) {
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	return &GetMyEntitlementsRequest{UserID: requestingUserId}, nil
+}
+
+// mapRequestToGetSubjectEntitlementsRequest maps incoming GetSubjectEntitlements request to correct
+// struct.
+func mapRequestToGetSubjectEntitlementsRequest(request *http.Request, validator BillingManagerValidator) (*GetSubjectEntitlementsRequest, error) {
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	subjectType, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableEntitlementSubjectType)
+	if err != nil {
+		logger.Error("unable-get-entitlement-subject-type-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, entitlement.ErrInvalidSubjectType
+	}
+
+	subjectId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableEntitlementSubjectID)
+	if err != nil {
+		logger.Error("unable-get-entitlement-subject-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, entitlement.ErrSubjectIDIsRequired
+	}
+
+	return &GetSubjectEntitlementsRequest{SubjectType: subjectType, SubjectID: subjectId}, nil
+}
+
+// mapRequestToGetEntitlementGrantsRequest maps incoming GetEntitlementGrants request to correct
+// struct.
+func mapRequestToGetEntitlementGrantsRequest(request *http.Request, validator BillingManagerValidator) (*GetEntitlementGrantsRequest, error) {
+	var parsedRequest entitlement.GetGrantsRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	query := request.URL.Query()
+	err := querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-entitlement-grants-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	return &GetEntitlementGrantsRequest{GetGrantsRequest: &parsedRequest}, nil
+}
+
+// mapRequestToCreateEntitlementGrantRequest maps incoming CreateEntitlementGrant request to correct
+// struct.
+func mapRequestToCreateEntitlementGrantRequest(request *http.Request, validator BillingManagerValidator) (*CreateEntitlementGrantRequest, error) {
+	var parsedRequest entitlement.CreateGrantRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil {
+		logger.Error("unable-to-decode-entitlement-grant-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-entitlement-grant-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	parsedRequest.CreatedByUserID = requestingUserId
+
+	return &CreateEntitlementGrantRequest{CreateGrantRequest: &parsedRequest}, nil
+}
+
+// mapRequestToRevokeEntitlementGrantRequest maps incoming RevokeEntitlementGrant request to correct
+// struct.
+func mapRequestToRevokeEntitlementGrantRequest(request *http.Request, validator BillingManagerValidator) (*RevokeEntitlementGrantRequest, error) {
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	grantId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableEntitlementGrantID)
+	if err != nil {
+		logger.Error("unable-get-entitlement-grant-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, entitlement.ErrGrantIDIsRequired
+	}
+
+	return &RevokeEntitlementGrantRequest{GrantID: grantId, RequestingUserID: requestingUserId}, nil
+}
+
+// mapRequestToGetMyUsageRequest maps incoming GetMyUsage request to correct
+// struct.
+func mapRequestToGetMyUsageRequest(request *http.Request, validator BillingManagerValidator) (*GetMyUsageRequest, error) {
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	return &GetMyUsageRequest{UserID: requestingUserId}, nil
+}
+
+// mapRequestToGetSubjectUsageRequest maps incoming GetSubjectUsage request to correct
+// struct.
+func mapRequestToGetSubjectUsageRequest(request *http.Request, validator BillingManagerValidator) (*GetSubjectUsageRequest, error) {
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	subjectType, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableEntitlementSubjectType)
+	if err != nil {
+		logger.Error("unable-get-usage-subject-type-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, entitlement.ErrInvalidSubjectType
+	}
+
+	subjectId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableEntitlementSubjectID)
+	if err != nil {
+		logger.Error("unable-get-usage-subject-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, entitlement.ErrSubjectIDIsRequired
+	}
+
+	return &GetSubjectUsageRequest{SubjectType: subjectType, SubjectID: subjectId}, nil
+}
+
+// mapRequestToGetUsageEventsRequest maps incoming GetUsageEvents request to correct
+// struct.
+func mapRequestToGetUsageEventsRequest(request *http.Request, validator BillingManagerValidator) (*GetUsageEventsRequest, error) {
+	var parsedRequest metering.GetUsageEventsRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	query := request.URL.Query()
+	err := querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-usage-events-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	return &GetUsageEventsRequest{GetUsageEventsRequest: &parsedRequest}, nil
+}
+
+// mapRequestToRecordUsageRequest maps incoming RecordUsage request to correct
+// struct.
+func mapRequestToRecordUsageRequest(request *http.Request, validator BillingManagerValidator) (*RecordUsageRequest, error) {
+	var parsedRequest metering.RecordUsageRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil {
+		logger.Error("unable-to-decode-record-usage-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-record-usage-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	parsedRequest.RecordedByUserID = requestingUserId
+
+	return &RecordUsageRequest{RecordUsageRequest: &parsedRequest}, nil
+}
+
+// mapRequestToReconcileSubscriptionsRequest maps incoming ReconcileSubscriptions request to correct
+// struct. The body is optional, an empty body reconciles with the defaults.
+func mapRequestToReconcileSubscriptionsRequest(request *http.Request, validator BillingManagerValidator) (*ReconcileSubscriptionsRequest, error) {
+	var parsedRequest ReconcileSubscriptionsRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("unable-to-decode-reconcile-subscriptions-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-reconcile-subscriptions-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToSyncPriceCatalogRequest maps incoming SyncPriceCatalog request to correct
+// struct.
+func mapRequestToSyncPriceCatalogRequest(request *http.Request, validator BillingManagerValidator) (*SyncPriceCatalogRequest, error) {
+	var parsedRequest SyncPriceCatalogRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil {
+		logger.Error("unable-to-decode-sync-price-catalog-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-sync-price-catalog-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	parsedRequest.RequestingUserID = accessmanagerhelpers.AcquireFrom(request.Context())
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToProcessDunningRequest maps incoming ProcessDunning request to correct
+// struct.
+func mapRequestToProcessDunningRequest(request *http.Request, validator BillingManagerValidator) (*ProcessDunningRequest, error) {
+	var parsedRequest ProcessDunningRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("unable-to-decode-process-dunning-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-process-dunning-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToGetSubscriptionDriftReportRequest maps incoming GetSubscriptionDriftReport request to correct
+// struct.
+func mapRequestToGetSubscriptionDriftReportRequest(request *http.Request, validator BillingManagerValidator) (*GetSubscriptionDriftReportRequest, error) {
+	var parsedRequest GetSubscriptionDriftReportRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	query := request.URL.Query()
+	err := querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-subscription-drift-report-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if parsedRequest.Limit < 0 || parsedRequest.Limit > maxReconciliationLimit {
+		logger.Warn("invalid-subscription-drift-report-limit", zap.Int("limit", parsedRequest.Limit))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToMigratePricePlanVersionsRequest maps incoming MigratePricePlanVersions request to correct
+// struct. The body is optional, an empty body migrates every due price plan version.
+func mapRequestToMigratePricePlanVersionsRequest(request *http.Request, validator BillingManagerValidator) (*MigratePricePlanVersionsRequest, error) {
+	var parsedRequest MigratePricePlanVersionsRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("unable-to-decode-migrate-price-plan-versions-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-migrate-price-plan-versions-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToGetMyInvoicesRequest maps incoming GetMyInvoices request to correct
+// struct.
+func mapRequestToGetMyInvoicesRequest(request *http.Request, validator BillingManagerValidator) (*GetMyInvoicesRequest, error) {
+	var parsedRequest invoice.GetInvoicesRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	query := request.URL.Query()
+	err := querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-invoices-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	return &GetMyInvoicesRequest{GetInvoicesRequest: &parsedRequest, UserID: requestingUserId}, nil
+}
+
+// mapRequestToDownloadMyInvoiceRequest maps incoming DownloadMyInvoice request to correct
+// struct.
+func mapRequestToDownloadMyInvoiceRequest(request *http.Request, validator BillingManagerValidator) (*DownloadMyInvoiceRequest, error) {
+	var parsedRequest DownloadMyInvoiceRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	invoiceId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableInvoiceID)
+	if err != nil {
+		logger.Error("unable-get-invoice-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrBillingManagerUnableToGetInvoiceIdFromURI
+	}
+
+	query := request.URL.Query()
+	err = querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-download-invoice-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	parsedRequest.UserID = requestingUserId
+	parsedRequest.InvoiceID = invoiceId
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToEmailMyInvoiceRequest maps incoming EmailMyInvoice request to correct
+// struct.
+func mapRequestToEmailMyInvoiceRequest(request *http.Request, validator BillingManagerValidator) (*EmailMyInvoiceRequest, error) {
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	invoiceId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableInvoiceID)
+	if err != nil {
+		logger.Error("unable-get-invoice-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrBillingManagerUnableToGetInvoiceIdFromURI
+	}
+
+	return &EmailMyInvoiceRequest{UserID: requestingUserId, InvoiceID: invoiceId}, nil
+}
+
+// mapRequestToSearchSubscriptionsRequest maps incoming SearchSubscriptions request to correct
+// struct.
+func mapRequestToSearchSubscriptionsRequest(request *http.Request, validator BillingManagerValidator) (*SearchSubscriptionsRequest, error) {
+	var parsedRequest billing.GetSubscriptionsRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	query := request.URL.Query()
+	err := querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-search-subscriptions-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	return &SearchSubscriptionsRequest{GetSubscriptionsRequest: &parsedRequest}, nil
+}
+
+// mapRequestToChangeSubscriptionRequest maps incoming CancelSubscription and PauseSubscription
+// requests to correct struct.
+func mapRequestToChangeSubscriptionRequest(request *http.Request, validator BillingManagerValidator) (*ChangeSubscriptionRequest, error) {
+	var parsedRequest ChangeSubscriptionRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	subscriptionId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableSubscriptionID)
+	if err != nil {
+		logger.Error("unable-get-subscription-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrBillingManagerUnableToGetSubscriptionIdFromURI
+	}
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("unable-to-decode-change-subscription-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	parsedRequest.SubscriptionID = subscriptionId
+	parsedRequest.RequestingUserID = requestingUserId
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToRefundBillingEventRequest maps incoming RefundBillingEvent request to correct
+// struct.
+func mapRequestToRefundBillingEventRequest(request *http.Request, validator BillingManagerValidator) (*RefundBillingEventRequest, error) {
+	var parsedRequest RefundBillingEventRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	billingEventId, err := toolbox.GetVariableValueFromUri(request, BillingManagerURIVariableBillingEventID)
+	if err != nil {
+		logger.Error("unable-get-billing-event-id-from-uri", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrBillingManagerUnableToGetBillingEventIdFromURI
+	}
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("unable-to-decode-refund-billing-event-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	parsedRequest.BillingEventID = billingEventId
+	parsedRequest.RequestingUserID = requestingUserId
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToGrantComplimentarySubscriptionRequest maps incoming GrantComplimentarySubscription
+// request to correct struct.
+func mapRequestToGrantComplimentarySubscriptionRequest(request *http.Request, validator BillingManagerValidator) (*GrantComplimentarySubscriptionRequest, error) {
+	var parsedRequest GrantComplimentarySubscriptionRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+	requestingUserId := accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if requestingUserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrBillingManagerUnableToIdentifyUser
+	}
+
+	if err := toolbox.DecodeRequestBody(request, &parsedRequest); err != nil {
+		logger.Error("unable-to-decode-grant-complimentary-subscription-request-body", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if validator != nil {
+		if err := validator.Validate(&parsedRequest); err != nil {
+			logger.Warn("invalid-grant-complimentary-subscription-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+			return nil, ErrInvalidBillingManagerRequestPayload
+		}
+	}
+
+	parsedRequest.RequestingUserID = requestingUserId
+
+	return &parsedRequest, nil
+}
+
+// mapRequestToGetRevenueMetricsRequest maps incoming GetRevenueMetrics request to correct
+// struct.
+func mapRequestToGetRevenueMetricsRequest(request *http.Request, validator BillingManagerValidator) (*GetRevenueMetricsRequest, error) {
+	var parsedRequest billing.GetRevenueMetricsRequest
+	requestPath := logger.RequestPath(request)
+	logger := logger.AcquirePackageFrom(request.Context(), "external/billingmanager")
+
+	query := request.URL.Query()
+	err := querydecoder.New(query).Decode(&parsedRequest)
+	if err != nil {
+		logger.Error("unable-to-decode-query-to-revenue-metrics-request", zap.String("method", request.Method), zap.String("path", requestPath), zap.Error(err))
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	return &GetRevenueMetricsRequest{GetRevenueMetricsRequest: &parsedRequest}, nil
+}
@@ -18,7 +18,35 @@ type BillingManagerService interface {
 	GetUserBillingEvents(ctx context.Context, r *GetUserBillingEventsRequest) (*GetUserBillingEventsResponse, error)
 	GetPricingPlans(ctx context.Context, r *GetPricingPlansRequest) (*GetPricingPlansResponse, error)
 	GetPricePlanBySlug(ctx context.Context, r *GetPricePlanBySlugRequest) (*GetPricePlanBySlugResponse, error)
+	QuotePricePlan(ctx context.Context, r *QuotePricePlanRequest) (*QuotePricePlanResponse, error)
 	GetPricingFeatures(ctx context.Context, r *GetPriceFeaturesRequest) (*GetPriceFeaturesResponse, error)
+	GetWebhookDeliveries(ctx context.Context, r *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error)
+	ReplayWebhookDelivery(ctx context.Context, r *ReplayWebhookDeliveryRequest) (*ReplayWebhookDeliveryResponse, error)
+	CreateCheckoutSession(ctx context.Context, r *CreateCheckoutSessionRequest) (*CreateCheckoutSessionResponse, error)
+	CreateCustomerPortalSession(ctx context.Context, r *CreateCustomerPortalSessionRequest) (*CreateCustomerPortalSessionResponse, error)
+	GetMyEntitlements(ctx context.Context, r *GetMyEntitlementsRequest) (*GetEntitlementsResponse, error)
+	GetSubjectEntitlements(ctx context.Context, r *GetSubjectEntitlementsRequest) (*GetEntitlementsResponse, error)
+	GetEntitlementGrants(ctx context.Context, r *GetEntitlementGrantsRequest) (*GetEntitlementGrantsResponse, error)
+	CreateEntitlementGrant(ctx context.Context, r *CreateEntitlementGrantRequest) (*EntitlementGrantResponse, error)
+	RevokeEntitlementGrant(ctx context.Context, r *RevokeEntitlementGrantRequest) (*EntitlementGrantResponse, error)
+	GetMyUsage(ctx context.Context, r *GetMyUsageRequest) (*GetUsageResponse, error)
+	GetSubjectUsage(ctx context.Context, r *GetSubjectUsageRequest) (*GetUsageResponse, error)
+	GetUsageEvents(ctx context.Context, r *GetUsageEventsRequest) (*GetUsageEventsResponse, error)
+	RecordUsage(ctx context.Context, r *RecordUsageRequest) (*RecordUsageResponse, error)
+	ReconcileSubscriptions(ctx context.Context, r *ReconcileSubscriptionsRequest) (*ReconciliationReportResponse, error)
+	GetSubscriptionDriftReport(ctx context.Context, r *GetSubscriptionDriftReportRequest) (*ReconciliationReportResponse, error)
+	ProcessDunning(ctx context.Context, r *ProcessDunningRequest) (*DunningReportResponse, error)
+	MigratePricePlanVersions(ctx context.Context, r *MigratePricePlanVersionsRequest) (*PlanVersionMigrationReportResponse, error)
+	SyncPriceCatalog(ctx context.Context, r *SyncPriceCatalogRequest) (*CatalogSyncReportResponse, error)
+	GetMyInvoices(ctx context.Context, r *GetMyInvoicesRequest) (*GetInvoicesResponse, error)
+	DownloadMyInvoice(ctx context.Context, r *DownloadMyInvoiceRequest) (*DownloadInvoiceResponse, error)
+	EmailMyInvoice(ctx context.Context, r *EmailMyInvoiceRequest) (*InvoiceResponse, error)
+	SearchSubscriptions(ctx context.Context, r *SearchSubscriptionsRequest) (*SearchSubscriptionsResponse, error)
+	CancelSubscription(ctx context.Context, r *ChangeSubscriptionRequest) (*SubscriptionResponse, error)
+	PauseSubscription(ctx context.Context, r *ChangeSubscriptionRequest) (*SubscriptionResponse, error)
+	RefundBillingEvent(ctx context.Context, r *RefundBillingEventRequest) (*RefundResponse, error)
+	GrantComplimentarySubscription(ctx context.Context, r *GrantComplimentarySubscriptionRequest) (*SubscriptionResponse, error)
+	GetRevenueMetrics(ctx context.Context, r *GetRevenueMetricsRequest) (*RevenueMetricsResponse, error)
 }
 
 // BillingManagerValidator expected methods of a valid
@@ -187,6 +215,26 @@ func (h *Handler) GetPricePlanBySlug(w http.ResponseWriter, r *http.Request) {
 	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PricePlan)
 }
 
+// QuotePricePlan handles request to quote a pricing plan.
+func (h *Handler) QuotePricePlan(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-quote-price-plan")
+	request, err := MapRequestToQuotePricePlanRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.QuotePricePlan(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Quote)
+}
+
 // GetPricingFeatures handles request to get pricing feature catalog items.
 func (h *Handler) GetPricingFeatures(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-pricing-features")
@@ -212,6 +260,196 @@ func (h *Handler) GetPricingFeatures(w http.ResponseWriter, r *http.Request) {
 	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Features)
 }
 
+// GetWebhookDeliveries handles admin request to list webhook deliveries in the inbox
+func (h *Handler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-webhook-deliveries")
+	request, err := mapRequestToGetWebhookDeliveriesRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetWebhookDeliveries(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Deliveries, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Deliveries)
+}
+
+// ReplayWebhookDelivery handles admin request to reprocess a webhook delivery
+func (h *Handler) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-replay-webhook-delivery")
+	request, err := mapRequestToReplayWebhookDeliveryRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ReplayWebhookDelivery(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Delivery)
+}
+
+// CreateCheckoutSession handles request to create a checkout session for the signed-in user
+func (h *Handler) CreateCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-create-checkout-session")
+	request, err := mapRequestToCreateCheckoutSessionRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.CreateCheckoutSession(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.CheckoutSession)
+}
+
+// CreateCustomerPortalSession handles request to create a customer portal session for the signed-in user
+func (h *Handler) CreateCustomerPortalSession(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-create-customer-portal-session")
+	request, err := mapRequestToCreateCustomerPortalSessionRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.CreateCustomerPortalSession(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.PortalSession)
+}
+
+// GetMyEntitlements handles request to get the signed-in user's entitlements
+func (h *Handler) GetMyEntitlements(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-my-entitlements")
+	request, err := mapRequestToGetMyEntitlementsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetMyEntitlements(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.EntitlementSet)
+}
+
+// GetSubjectEntitlements handles admin request to get the entitlements of a user or group
+func (h *Handler) GetSubjectEntitlements(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-subject-entitlements")
+	request, err := mapRequestToGetSubjectEntitlementsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetSubjectEntitlements(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.EntitlementSet)
+}
+
+// GetEntitlementGrants handles admin request to list entitlement grants
+func (h *Handler) GetEntitlementGrants(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-entitlement-grants")
+	request, err := mapRequestToGetEntitlementGrantsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetEntitlementGrants(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Grants, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Grants)
+}
+
+// CreateEntitlementGrant handles admin request to issue an entitlement grant
+func (h *Handler) CreateEntitlementGrant(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-create-entitlement-grant")
+	request, err := mapRequestToCreateEntitlementGrantRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.CreateEntitlementGrant(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.Grant)
+}
+
+// RevokeEntitlementGrant handles admin request to revoke an entitlement grant
+func (h *Handler) RevokeEntitlementGrant(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-revoke-entitlement-grant")
+	request, err := mapRequestToRevokeEntitlementGrantRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.RevokeEntitlementGrant(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Grant)
+}
+
 // getBaseResponseHandler returns response handler with BillingManagerErrorMap
 // as the base layer and caller-supplied maps as overrides.
 func (h *Handler) getBaseResponseHandler() *reply.Replier {
@@ -222,3 +460,392 @@ func (h *Handler) getBaseResponseHandler() *reply.Replier {
 			Build(),
 	)
 }
+
+// GetMyUsage handles request to get the signed-in user's usage against their quotas
+func (h *Handler) GetMyUsage(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-my-usage")
+	request, err := mapRequestToGetMyUsageRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetMyUsage(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Usage)
+}
+
+// GetSubjectUsage handles admin request to get the usage of a user or group
+func (h *Handler) GetSubjectUsage(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-subject-usage")
+	request, err := mapRequestToGetSubjectUsageRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetSubjectUsage(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Usage)
+}
+
+// GetUsageEvents handles admin request to list recorded usage events
+func (h *Handler) GetUsageEvents(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-usage-events")
+	request, err := mapRequestToGetUsageEventsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetUsageEvents(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.UsageEvents, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.UsageEvents)
+}
+
+// RecordUsage handles admin request to record usage on behalf of a user or group
+func (h *Handler) RecordUsage(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-record-usage")
+	request, err := mapRequestToRecordUsageRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.RecordUsage(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if response.Duplicate {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.RecordUsageResponse)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.RecordUsageResponse)
+}
+
+// ReconcileSubscriptions handles admin request to compare subscriptions with their payment
+// providers and repair any drift
+func (h *Handler) ReconcileSubscriptions(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-reconcile-subscriptions")
+	request, err := mapRequestToReconcileSubscriptionsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ReconcileSubscriptions(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Report)
+}
+
+// SyncPriceCatalog handles admin request to push price plans to a payment provider's
+// catalog and report drift between them
+func (h *Handler) SyncPriceCatalog(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-sync-price-catalog")
+	request, err := mapRequestToSyncPriceCatalogRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.SyncPriceCatalog(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Report)
+}
+
+// GetSubscriptionDriftReport handles admin request to report how subscriptions differ from
+// their payment providers
+func (h *Handler) GetSubscriptionDriftReport(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-subscription-drift-report")
+	request, err := mapRequestToGetSubscriptionDriftReportRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetSubscriptionDriftReport(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Report)
+}
+
+// ProcessDunning handles admin request to progress past due and unpaid subscriptions
+// through dunning
+func (h *Handler) ProcessDunning(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-process-dunning")
+	request, err := mapRequestToProcessDunningRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ProcessDunning(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Report)
+}
+
+// MigratePricePlanVersions handles admin request to move the subscribers of superseded
+// price plan versions whose migration is due
+func (h *Handler) MigratePricePlanVersions(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-migrate-price-plan-versions")
+	request, err := mapRequestToMigratePricePlanVersionsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.MigratePricePlanVersions(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Report)
+}
+
+// GetMyInvoices handles request to list the signed-in user's invoices
+func (h *Handler) GetMyInvoices(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-my-invoices")
+	request, err := mapRequestToGetMyInvoicesRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetMyInvoices(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Invoices, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Invoices)
+}
+
+// DownloadMyInvoice handles request to download one of the signed-in user's invoices
+// as a PDF or HTML document
+func (h *Handler) DownloadMyInvoice(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-download-my-invoice")
+	request, err := mapRequestToDownloadMyInvoiceRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.DownloadMyInvoice(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", response.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+response.FileName+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(response.Content)
+}
+
+// EmailMyInvoice handles request to email one of the signed-in user's invoices to them
+func (h *Handler) EmailMyInvoice(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-email-my-invoice")
+	request, err := mapRequestToEmailMyInvoiceRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.EmailMyInvoice(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Invoice)
+}
+
+// SearchSubscriptions handles admin request to search subscriptions across users
+func (h *Handler) SearchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-search-subscriptions")
+	request, err := mapRequestToSearchSubscriptionsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.SearchSubscriptions(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Subscriptions, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Subscriptions)
+}
+
+// CancelSubscription handles admin request to cancel a subscription now or at the end of its billing period
+func (h *Handler) CancelSubscription(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-cancel-subscription")
+	request, err := mapRequestToChangeSubscriptionRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.CancelSubscription(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Subscription)
+}
+
+// PauseSubscription handles admin request to pause a subscription now or at the end of its billing period
+func (h *Handler) PauseSubscription(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-pause-subscription")
+	request, err := mapRequestToChangeSubscriptionRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.PauseSubscription(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Subscription)
+}
+
+// RefundBillingEvent handles admin request to refund the payment recorded by a billing event
+func (h *Handler) RefundBillingEvent(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-refund-billing-event")
+	request, err := mapRequestToRefundBillingEventRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.RefundBillingEvent(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Refund)
+}
+
+// GrantComplimentarySubscription handles admin request to grant a user or group a complimentary subscription
+func (h *Handler) GrantComplimentarySubscription(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-grant-complimentary-subscription")
+	request, err := mapRequestToGrantComplimentarySubscriptionRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GrantComplimentarySubscription(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.Subscription)
+}
+
+// GetRevenueMetrics handles admin request to get revenue metrics
+func (h *Handler) GetRevenueMetrics(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/billingmanager", "handle-get-revenue-metrics")
+	request, err := mapRequestToGetRevenueMetricsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetRevenueMetrics(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.GetRevenueMetricsResponse)
+}
@@ -73,3 +73,289 @@ type AuditEvent struct {
 	// ProviderPayload contains the webhook payload from the payment provider (if billing event creation failed)
 	ProviderPayload *paymentprovider.WebhookPayload `json:"provider_payload,omitempty" bson:"provider_payload,omitempty"`
 }
+
+// ReconciliationReport summarises a reconciliation run comparing stored subscriptions
+// with the payment providers' view of them
+type ReconciliationReport struct {
+	// DryRun is true when drift was only reported and no subscription was changed
+	DryRun bool `json:"dry_run"`
+
+	// ProviderName is the payment provider the run was limited to, if any
+	ProviderName string `json:"provider_name,omitempty"`
+
+	// StartedAt is when the run started
+	StartedAt time.Time `json:"started_at"`
+
+	// CompletedAt is when the run finished
+	CompletedAt time.Time `json:"completed_at"`
+
+	// Checked is the number of subscriptions compared with their provider
+	Checked int `json:"checked"`
+
+	// Drifted is the number of subscriptions that differ from their provider
+	Drifted int `json:"drifted"`
+
+	// Repaired is the number of drifted subscriptions that were corrected
+	Repaired int `json:"repaired"`
+
+	// Skipped is the number of subscriptions whose provider has no subscription API
+	Skipped int `json:"skipped"`
+
+	// Failed is the number of subscriptions that could not be checked or repaired
+	Failed int `json:"failed"`
+
+	// Drifts lists every subscription that differs from its provider
+	Drifts []SubscriptionDrift `json:"drifts"`
+
+	// Failures lists every subscription that could not be checked
+	Failures []SubscriptionReconciliationFailure `json:"failures,omitempty"`
+}
+
+// SubscriptionDrift describes how a stored subscription differs from its provider
+type SubscriptionDrift struct {
+	// SubscriptionID is the internal subscription ID
+	SubscriptionID string `json:"subscription_id"`
+
+	// UserID is the platform user the subscription belongs to
+	UserID string `json:"user_id,omitempty"`
+
+	// Integrator is the payment provider name
+	Integrator string `json:"integrator"`
+
+	// IntegratorSubscriptionID is the provider's subscription ID
+	IntegratorSubscriptionID string `json:"integrator_subscription_id"`
+
+	// Fields lists each field that differs
+	Fields []SubscriptionDriftField `json:"fields"`
+
+	// Repaired is true when the subscription was corrected to match the provider
+	Repaired bool `json:"repaired"`
+
+	// BillingEventID is the billing event recording the correction
+	BillingEventID string `json:"billing_event_id,omitempty"`
+
+	// Error describes why the repair failed, if it did
+	Error string `json:"error,omitempty"`
+}
+
+// SubscriptionDriftField holds the stored and provider values of a field that differs
+type SubscriptionDriftField struct {
+	Field    string `json:"field"`
+	Stored   string `json:"stored"`
+	Provider string `json:"provider"`
+}
+
+// SubscriptionReconciliationFailure describes a subscription that could not be checked
+type SubscriptionReconciliationFailure struct {
+	SubscriptionID           string `json:"subscription_id"`
+	Integrator               string `json:"integrator"`
+	IntegratorSubscriptionID string `json:"integrator_subscription_id"`
+	Error                    string `json:"error"`
+}
+
+// CatalogSyncReport summarises a catalog sync comparing price plans and their costs with
+// the products and prices on a payment provider
+type CatalogSyncReport struct {
+	// DryRun is true when changes were only reported and nothing was changed
+	DryRun bool `json:"dry_run"`
+
+	// ProviderName is the payment provider synced
+	ProviderName string `json:"provider_name"`
+
+	// StartedAt is when the sync started
+	StartedAt time.Time `json:"started_at"`
+
+	// CompletedAt is when the sync finished
+	CompletedAt time.Time `json:"completed_at"`
+
+	// Checked is the number of products and prices compared with the provider
+	Checked int `json:"checked"`
+
+	// InSync is the number of products and prices that match the provider
+	InSync int `json:"in_sync"`
+
+	// Changed is the number of products and prices that are missing or differ on the provider
+	Changed int `json:"changed"`
+
+	// Applied is the number of changes made on the provider and written back to the catalog
+	Applied int `json:"applied"`
+
+	// Manual is the number of changes that must be made in the provider's dashboard
+	Manual int `json:"manual"`
+
+	// Failed is the number of products and prices that could not be checked or changed
+	Failed int `json:"failed"`
+
+	// Changes lists every product and price that is missing or differs on the provider
+	Changes []CatalogSyncChange `json:"changes"`
+}
+
+// CatalogSyncChange describes a product or price that is missing or differs on the provider
+type CatalogSyncChange struct {
+	// PricePlanID is the price plan the product or price belongs to
+	PricePlanID string `json:"price_plan_id"`
+
+	// PricePlanSlug is the slug of the price plan
+	PricePlanSlug string `json:"price_plan_slug"`
+
+	// CostID is the plan cost the price belongs to. Empty for products
+	CostID string `json:"cost_id,omitempty"`
+
+	// Resource is either product or price
+	Resource string `json:"resource"`
+
+	// Action is create, update, replace or manual
+	Action string `json:"action"`
+
+	// ProviderID is the provider's current identifier, if the resource exists
+	ProviderID string `json:"provider_id,omitempty"`
+
+	// NewProviderID is the identifier of the product or price created by the sync
+	NewProviderID string `json:"new_provider_id,omitempty"`
+
+	// Fields lists each field that differs
+	Fields []CatalogDriftField `json:"fields,omitempty"`
+
+	// Applied is true when the change was made and written back to the catalog
+	Applied bool `json:"applied"`
+
+	// Error describes why the check or change failed, if it did
+	Error string `json:"error,omitempty"`
+}
+
+// CatalogDriftField holds the catalog and provider values of a field that differs
+type CatalogDriftField struct {
+	Field    string `json:"field"`
+	Catalog  string `json:"catalog"`
+	Provider string `json:"provider"`
+}
+
+// DunningConfig defines how subscriptions with a failed payment are chased before they
+// are suspended
+type DunningConfig struct {
+	// Steps are the reminders sent while a subscription is past due or unpaid, ordered by
+	// when they are due
+	Steps []DunningStep
+
+	// GracePeriod is how long a subscription keeps its entitlements after its payment first
+	// fails. Once it has passed the subscription is suspended
+	GracePeriod time.Duration
+}
+
+// DunningStep is a reminder sent once a subscription has been in dunning for a period
+type DunningStep struct {
+	// After is how long after dunning started the reminder is sent
+	After time.Duration
+
+	// Email sends the reminder to the subscription's email address
+	Email bool
+
+	// InApp sends the reminder as an in-app notification to the subscription's user
+	InApp bool
+}
+
+// DefaultDunningConfig returns a schedule that reminds by email and in-app notification
+// when the payment fails, after 3 days and after 7 days, and suspends after 14 days
+func DefaultDunningConfig() DunningConfig {
+	return DunningConfig{
+		Steps: []DunningStep{
+			{After: 0, Email: true, InApp: true},
+			{After: 3 * 24 * time.Hour, Email: true, InApp: true},
+			{After: 7 * 24 * time.Hour, Email: true, InApp: true},
+		},
+		GracePeriod: defaultDunningGracePeriod,
+	}
+}
+
+// DunningReport summarises a dunning run progressing subscriptions with a failed payment
+type DunningReport struct {
+	// StartedAt is when the run started
+	StartedAt time.Time `json:"started_at"`
+
+	// CompletedAt is when the run finished
+	CompletedAt time.Time `json:"completed_at"`
+
+	// Checked is the number of past due or unpaid subscriptions progressed
+	Checked int `json:"checked"`
+
+	// Started is the number of subscriptions that entered dunning
+	Started int `json:"started"`
+
+	// RemindersSent is the number of dunning reminders sent
+	RemindersSent int `json:"reminders_sent"`
+
+	// Suspended is the number of subscriptions suspended after their grace period
+	Suspended int `json:"suspended"`
+
+	// Failed is the number of subscriptions that could not be progressed
+	Failed int `json:"failed"`
+
+	// Failures lists every subscription that could not be progressed
+	Failures []DunningFailure `json:"failures,omitempty"`
+}
+
+// DunningFailure describes a subscription that could not be progressed through dunning
+type DunningFailure struct {
+	SubscriptionID string `json:"subscription_id"`
+	UserID         string `json:"user_id,omitempty"`
+	Error          string `json:"error"`
+}
+
+// DunningAuditEvent is the audit record of a dunning step taken for a subscription
+type DunningAuditEvent struct {
+	// SubscriptionID is the internal subscription ID
+	SubscriptionID string `json:"subscription_id"`
+
+	// UserID is the platform user the subscription belongs to
+	UserID string `json:"user_id,omitempty"`
+
+	// GroupID is the group the subscription was bought for, if any
+	GroupID string `json:"group_id,omitempty"`
+
+	// Status is the subscription's status when the step was taken
+	Status string `json:"status"`
+
+	// Step is the number of the reminder sent, starting at 1
+	Step int `json:"step,omitempty"`
+
+	// EmailSent is true when an email was sent for the step
+	EmailSent bool `json:"email_sent,omitempty"`
+
+	// NotificationSent is true when an in-app notification was sent for the step
+	NotificationSent bool `json:"notification_sent,omitempty"`
+
+	// GracePeriodEndsAt is when the subscription is, or was, suspended
+	GracePeriodEndsAt time.Time `json:"grace_period_ends_at"`
+
+	// UpdateURL is the provider's update payment page linked in reminders
+	UpdateURL string `json:"update_url,omitempty"`
+}
+
+// PlanVersionMigrationReport summarises a run moving the subscribers of superseded price plan
+// versions onto the versions chosen for them
+type PlanVersionMigrationReport struct {
+	// StartedAt is when the run started
+	StartedAt time.Time `json:"started_at"`
+
+	// CompletedAt is when the run finished
+	CompletedAt time.Time `json:"completed_at"`
+
+	// Versions is the number of price plan versions due to be migrated
+	Versions int `json:"versions"`
+
+	// Migrated is the number of subscriptions moved to a newer version
+	Migrated int `json:"migrated"`
+
+	// Failed is the number of subscriptions or versions that could not be migrated
+	Failed int `json:"failed"`
+
+	// Failures lists every subscription or version that could not be migrated
+	Failures []PlanVersionMigrationFailure `json:"failures,omitempty"`
+}
+
+// PlanVersionMigrationFailure describes a subscription or price plan version that could not be migrated
+type PlanVersionMigrationFailure struct {
+	PricePlanVersionID string `json:"price_plan_version_id"`
+	SubscriptionID     string `json:"subscription_id,omitempty"`
+	Error              string `json:"error"`
+}
@@ -2,7 +2,12 @@ package billingmanager
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/metering"
 	"github.com/ooaklee/ghatd/external/pricer"
 )
 
@@ -82,6 +87,14 @@ type GetPricePlanBySlugRequest struct {
 	*pricer.GetPricePlanBySlugRequest
 }
 
+// QuotePricePlanRequest wraps the pricer request used to quote a price plan through BMS.
+type QuotePricePlanRequest struct {
+	// UserID is the user making the request (for permission checks)
+	UserID string
+
+	*pricer.QuotePricePlanRequest
+}
+
 // GetPriceFeaturesRequest wraps the pricer request used to list price features through BMS.
 type GetPriceFeaturesRequest struct {
 	// UserID is the user making the request (for permission checks)
@@ -89,3 +102,329 @@ type GetPriceFeaturesRequest struct {
 
 	*pricer.GetFeaturesRequest
 }
+
+// GetWebhookDeliveriesRequest represents an admin request to list webhook deliveries held in the inbox
+type GetWebhookDeliveriesRequest struct {
+	// Order defines how should response be sorted. Default: newest -> oldest (created_at_desc)
+	// Valid options: created_at_asc, created_at_desc, updated_at_asc, updated_at_desc,
+	// provider_event_time_asc, provider_event_time_desc
+	Order string `query:"order"`
+
+	// Total number of deliveries to return per page, if available. Default 25.
+	// Accepts anything between 1 and 100
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from. Default 1.
+	Page int `query:"page"`
+
+	// Meta whether response should contain meta information
+	Meta bool `query:"meta"`
+
+	// ProviderName is the payment provider to filter by
+	ProviderName string `query:"provider_name"`
+
+	// Statuses is the list of delivery statuses to filter by. Defaults to failed deliveries.
+	// comma-separated list of statuses
+	Statuses []string `query:"statuses"`
+}
+
+// ReplayWebhookDeliveryRequest represents an admin request to reprocess a webhook delivery
+type ReplayWebhookDeliveryRequest struct {
+	// DeliveryID is the inbox key of the delivery to replay
+	DeliveryID string
+}
+
+// CreateCheckoutSessionRequest represents a request by the signed-in user to start a
+// hosted checkout for a price plan
+type CreateCheckoutSessionRequest struct {
+	// UserID is the signed-in user the checkout is created for
+	UserID string `json:"-"`
+
+	// ProviderName is the payment provider to check out with (e.g., "stripe", "lemonsqueezy")
+	ProviderName string `json:"provider_name" validate:"required"`
+
+	// PlanSlug is the slug of the published price plan being purchased
+	PlanSlug string `json:"plan_slug" validate:"required"`
+
+	// CostID optionally selects a specific cost on the plan
+	CostID string `json:"cost_id,omitempty"`
+
+	// BillingCadence optionally selects the plan cost by cadence (e.g., "month", "year")
+	BillingCadence string `json:"billing_cadence,omitempty"`
+
+	// Quantity is the number of units being purchased. Defaults to 1, or to the group's
+	// current user member count when buying seats for a group
+	Quantity int64 `json:"quantity,omitempty"`
+
+	// GroupID optionally buys the plan on behalf of a group the user is a billing admin of,
+	// making the resulting subscription group-owned with its quantity as the group's seats
+	GroupID string `json:"group_id,omitempty"`
+
+	// SuccessURL is where the user is sent after completing the checkout
+	SuccessURL string `json:"success_url" validate:"required"`
+
+	// CancelURL is where the user is sent if they abandon the checkout
+	CancelURL string `json:"cancel_url,omitempty"`
+
+	// CouponCode is the provider coupon or discount code to apply
+	CouponCode string `json:"coupon_code,omitempty"`
+
+	// PromotionCode is a pricer promotion code to apply. It is checked against the user, plan and
+	// cadence, then applied as the provider coupon it is mapped to. Cannot be used with CouponCode
+	PromotionCode string `json:"promotion_code,omitempty"`
+}
+
+// CreateCustomerPortalSessionRequest represents a request by the signed-in user to open
+// their payment provider's customer portal
+type CreateCustomerPortalSessionRequest struct {
+	// UserID is the signed-in user the portal session is created for
+	UserID string `json:"-"`
+
+	// ProviderName optionally selects the payment provider, otherwise the provider
+	// of the user's most recent subscription is used
+	ProviderName string `json:"provider_name,omitempty"`
+
+	// ReturnURL is where the user is sent when leaving the portal
+	ReturnURL string `json:"return_url,omitempty"`
+}
+
+// GetMyEntitlementsRequest represents a request by the signed-in user for their entitlements
+type GetMyEntitlementsRequest struct {
+	// UserID is the signed-in user
+	UserID string
+}
+
+// GetSubjectEntitlementsRequest represents an admin request for the entitlements of a user or group
+type GetSubjectEntitlementsRequest struct {
+	// SubjectType is the kind of subject (user or group)
+	SubjectType string
+
+	// SubjectID is the user or group ID
+	SubjectID string
+}
+
+// GetEntitlementGrantsRequest wraps the entitlement request used to list grants through BMS.
+type GetEntitlementGrantsRequest struct {
+	*entitlement.GetGrantsRequest
+}
+
+// CreateEntitlementGrantRequest wraps the entitlement request used to issue a grant through BMS.
+type CreateEntitlementGrantRequest struct {
+	*entitlement.CreateGrantRequest
+}
+
+// GetMyUsageRequest represents a request by the signed-in user for their usage against quotas
+type GetMyUsageRequest struct {
+	// UserID is the signed-in user
+	UserID string
+}
+
+// GetSubjectUsageRequest represents an admin request for the usage of a user or group
+type GetSubjectUsageRequest struct {
+	// SubjectType is the kind of subject (user or group)
+	SubjectType string
+
+	// SubjectID is the user or group ID
+	SubjectID string
+}
+
+// GetUsageEventsRequest wraps the metering request used to list usage events through BMS.
+type GetUsageEventsRequest struct {
+	*metering.GetUsageEventsRequest
+}
+
+// RecordUsageRequest wraps the metering request used to record usage through BMS.
+type RecordUsageRequest struct {
+	*metering.RecordUsageRequest
+}
+
+// RevokeEntitlementGrantRequest represents an admin request to revoke an entitlement grant
+type RevokeEntitlementGrantRequest struct {
+	// GrantID is the grant to revoke
+	GrantID string
+
+	// RequestingUserID is the admin revoking the grant
+	RequestingUserID string
+}
+
+// ReconcileSubscriptionsRequest represents an admin request to compare subscriptions with their
+// payment providers and repair any drift
+type ReconcileSubscriptionsRequest struct {
+	// ProviderName optionally limits the run to one payment provider
+	ProviderName string `json:"provider_name,omitempty"`
+
+	// Limit is the number of subscriptions to check. Default 100.
+	// Accepts anything between 1 and 1000
+	Limit int `json:"limit,omitempty" validate:"omitempty,min=1,max=1000"`
+
+	// DryRun reports drift without repairing it
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// GetSubscriptionDriftReportRequest represents an admin request to report how subscriptions
+// differ from their payment providers, without repairing them
+type GetSubscriptionDriftReportRequest struct {
+	// ProviderName optionally limits the report to one payment provider
+	ProviderName string `query:"provider_name"`
+
+	// Limit is the number of subscriptions to check. Default 100.
+	// Accepts anything between 1 and 1000
+	Limit int `query:"limit"`
+}
+
+// RunSubscriptionReconciliationRequest holds the schedule of the background reconciliation job
+type RunSubscriptionReconciliationRequest struct {
+	// Interval is the time between runs. Default 1 hour
+	Interval time.Duration
+
+	// ProviderName optionally limits each run to one payment provider
+	ProviderName string
+
+	// Limit is the number of subscriptions checked per run. Default 100
+	Limit int
+}
+
+// SyncPriceCatalogRequest represents a request to push the pricer catalog to a payment
+// provider and report drift between them
+type SyncPriceCatalogRequest struct {
+	// ProviderName is the payment provider to sync
+	ProviderName string `json:"provider_name" validate:"required"`
+
+	// PlanSlugs optionally limits the sync to these price plans
+	PlanSlugs []string `json:"plan_slugs,omitempty"`
+
+	// DryRun reports what would change without changing the provider or the catalog
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// RequestingUserID is the admin running the sync
+	RequestingUserID string `json:"-"`
+}
+
+// ProcessDunningRequest represents an admin request to progress subscriptions with a failed
+// payment through dunning
+type ProcessDunningRequest struct {
+	// Limit is the number of subscriptions to progress. Default 100.
+	// Accepts anything between 1 and 1000
+	Limit int `json:"limit,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+// RunDunningRequest holds the schedule of the background dunning job
+type RunDunningRequest struct {
+	// Interval is the time between runs. Default 1 hour
+	Interval time.Duration
+
+	// Limit is the number of subscriptions progressed per run. Default 100
+	Limit int
+}
+
+// MigratePricePlanVersionsRequest represents an admin request to move the subscribers of
+// superseded price plan versions whose migration is due
+type MigratePricePlanVersionsRequest struct {
+	// PricePlanVersionID limits the run to a single due price plan version
+	PricePlanVersionID string `json:"price_plan_version_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// RunPricePlanVersionMigrationsRequest holds the schedule of the background price plan version migration job
+type RunPricePlanVersionMigrationsRequest struct {
+	// Interval is the time between runs. Default 1 hour
+	Interval time.Duration
+}
+
+// GetMyInvoicesRequest represents a request by the signed-in user to list their invoices
+type GetMyInvoicesRequest struct {
+	*invoice.GetInvoicesRequest
+
+	// UserID is the signed-in user
+	UserID string
+}
+
+// DownloadMyInvoiceRequest represents a request by the signed-in user to download one of their invoices
+type DownloadMyInvoiceRequest struct {
+	// UserID is the signed-in user
+	UserID string
+
+	// InvoiceID is the invoice to download
+	InvoiceID string
+
+	// Format is the document format, pdf or html. Default: pdf
+	Format string `query:"format"`
+}
+
+// EmailMyInvoiceRequest represents a request by the signed-in user to have one of their invoices emailed to them
+type EmailMyInvoiceRequest struct {
+	// UserID is the signed-in user
+	UserID string
+
+	// InvoiceID is the invoice to email
+	InvoiceID string
+}
+
+// SearchSubscriptionsRequest wraps the billing request used by admins to search subscriptions
+// across users by status, provider, plan and date ranges through BMS.
+type SearchSubscriptionsRequest struct {
+	*billing.GetSubscriptionsRequest
+}
+
+// ChangeSubscriptionRequest represents an admin request to cancel or pause a subscription
+type ChangeSubscriptionRequest struct {
+	// SubscriptionID is the subscription to change
+	SubscriptionID string `json:"-"`
+
+	// AtPeriodEnd schedules the change for the end of the current billing period
+	// instead of applying it immediately
+	AtPeriodEnd bool `json:"at_period_end"`
+
+	// Reason is why the subscription is being changed, recorded in the audit log
+	Reason string `json:"reason,omitempty"`
+
+	// RequestingUserID is the admin changing the subscription
+	RequestingUserID string `json:"-"`
+}
+
+// RefundBillingEventRequest represents an admin request to refund the payment recorded by a billing event
+type RefundBillingEventRequest struct {
+	// BillingEventID is the successful payment billing event to refund
+	BillingEventID string `json:"-"`
+
+	// Amount is the amount to refund in the smallest currency unit. Leave empty to refund in full
+	Amount int64 `json:"amount,omitempty"`
+
+	// Reason is why the payment is being refunded
+	Reason string `json:"reason,omitempty"`
+
+	// RequestingUserID is the admin issuing the refund
+	RequestingUserID string `json:"-"`
+}
+
+// GrantComplimentarySubscriptionRequest represents an admin request to give a user or group
+// a subscription that is not billed through a payment provider
+type GrantComplimentarySubscriptionRequest struct {
+	// UserID is the user receiving the subscription
+	UserID string `json:"user_id,omitempty"`
+
+	// GroupID is the group receiving the subscription
+	GroupID string `json:"group_id,omitempty"`
+
+	// PlanName is the plan the subscription entitles to, matching a price plan name or slug
+	PlanName string `json:"plan_name" validate:"required"`
+
+	// PlanID is the optional plan identifier
+	PlanID string `json:"plan_id,omitempty"`
+
+	// Quantity is the number of seats, used for group subscriptions
+	Quantity int64 `json:"quantity,omitempty" validate:"omitempty,min=1"`
+
+	// AvailableUntil is when the subscription ends (RFC3339). Leave empty for no end
+	AvailableUntil string `json:"available_until,omitempty"`
+
+	// Reason is why the subscription is being granted, recorded in the audit log
+	Reason string `json:"reason,omitempty"`
+
+	// RequestingUserID is the admin granting the subscription
+	RequestingUserID string `json:"-"`
+}
+
+// GetRevenueMetricsRequest wraps the billing request used by admins to get revenue metrics through BMS.
+type GetRevenueMetricsRequest struct {
+	*billing.GetRevenueMetricsRequest
+}
@@ -1,6 +1,11 @@
 package billingmanager
 
 import (
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/metering"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
 	"github.com/ooaklee/ghatd/external/pricer"
 	"github.com/ooaklee/ghatd/external/toolbox"
 )
@@ -69,6 +74,11 @@ func (g *GetPricingPlansResponse) GetMetaData() map[string]interface{} {
 	return g.GetPricePlansResponse.GetMetaData()
 }
 
+// QuotePricePlanResponse wraps the pricer response used to quote a price plan through BMS.
+type QuotePricePlanResponse struct {
+	*pricer.QuotePricePlanResponse
+}
+
 // GetPricePlanBySlugResponse wraps the pricer response used to get a price plan by slug through BMS.
 type GetPricePlanBySlugResponse struct {
 	*pricer.GetPricePlanBySlugResponse
@@ -87,3 +97,146 @@ func (g *GetPriceFeaturesResponse) GetMetaData() map[string]interface{} {
 
 	return g.GetFeaturesResponse.GetMetaData()
 }
+
+// GetWebhookDeliveriesResponse represents the response containing webhook deliveries from the inbox
+type GetWebhookDeliveriesResponse struct {
+
+	// Deliveries is the list of webhook deliveries
+	Deliveries []billing.WebhookDelivery `json:"deliveries"`
+
+	// Total number of deliveries found that matched provided
+	// filters
+	Total int
+
+	// TotalPages total pages available, based on the provided
+	// filters and resources per page
+	TotalPages int
+
+	// PerPage number of deliveries set to be returned per page
+	PerPage int
+
+	// Page specifies the page results were taken from. Default 1.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetWebhookDeliveriesResponse,
+// including the number of resources per page, total resources, total pages,
+// and the current page.
+func (g *GetWebhookDeliveriesResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
+
+// ReplayWebhookDeliveryResponse represents the response to replaying a webhook delivery
+type ReplayWebhookDeliveryResponse struct {
+
+	// Delivery is the inbox entry after the replay
+	Delivery *billing.WebhookDelivery `json:"delivery"`
+}
+
+// CreateCheckoutSessionResponse represents the response to creating a checkout session
+type CreateCheckoutSessionResponse struct {
+	// CheckoutSession holds the hosted checkout the user should be redirected to
+	CheckoutSession *paymentprovider.CheckoutSession `json:"checkout_session"`
+}
+
+// CreateCustomerPortalSessionResponse represents the response to creating a customer portal session
+type CreateCustomerPortalSessionResponse struct {
+	// PortalSession holds the customer portal the user should be redirected to
+	PortalSession *paymentprovider.CustomerPortalSession `json:"portal_session"`
+}
+
+// GetEntitlementsResponse holds the resolved entitlements of a user or group
+type GetEntitlementsResponse struct {
+	EntitlementSet *entitlement.EntitlementSet
+}
+
+// GetEntitlementGrantsResponse wraps the entitlement response used to list grants through BMS.
+type GetEntitlementGrantsResponse struct {
+	*entitlement.GetGrantsResponse
+}
+
+// EntitlementGrantResponse holds a single entitlement grant
+type EntitlementGrantResponse struct {
+	Grant *entitlement.Grant
+}
+
+// GetUsageResponse holds the usage of a user or group against their quotas
+type GetUsageResponse struct {
+	Usage []metering.UsageSummary
+}
+
+// GetUsageEventsResponse wraps the metering response used to list usage events through BMS.
+type GetUsageEventsResponse struct {
+	*metering.GetUsageEventsResponse
+}
+
+// RecordUsageResponse wraps the metering response used to record usage through BMS.
+type RecordUsageResponse struct {
+	*metering.RecordUsageResponse
+}
+
+// ReconciliationReportResponse represents the outcome of a reconciliation run or drift report
+type ReconciliationReportResponse struct {
+	// Report summarises the subscriptions checked and any drift found
+	Report *ReconciliationReport `json:"report"`
+}
+
+// CatalogSyncReportResponse represents the outcome of a catalog sync
+type CatalogSyncReportResponse struct {
+	// Report summarises the products and prices checked and any changes made
+	Report *CatalogSyncReport `json:"report"`
+}
+
+// DunningReportResponse represents the outcome of a dunning run
+type DunningReportResponse struct {
+	// Report summarises the subscriptions progressed through dunning
+	Report *DunningReport `json:"report"`
+}
+
+// PlanVersionMigrationReportResponse represents the outcome of a price plan version migration run
+type PlanVersionMigrationReportResponse struct {
+	// Report summarises the run
+	Report *PlanVersionMigrationReport `json:"report"`
+}
+
+// GetInvoicesResponse wraps the invoice response used to list invoices through BMS.
+type GetInvoicesResponse struct {
+	*invoice.GetInvoicesResponse
+}
+
+// DownloadInvoiceResponse wraps a rendered invoice document returned through BMS.
+type DownloadInvoiceResponse struct {
+	*invoice.RenderInvoiceResponse
+}
+
+// InvoiceResponse wraps a single invoice returned through BMS.
+type InvoiceResponse struct {
+	Invoice *invoice.Invoice `json:"invoice"`
+}
+
+// SearchSubscriptionsResponse wraps the subscriptions found by an admin search through BMS.
+type SearchSubscriptionsResponse struct {
+	*billing.GetSubscriptionsResponse
+}
+
+// SubscriptionResponse wraps a single subscription returned through BMS.
+type SubscriptionResponse struct {
+	Subscription *billing.Subscription `json:"subscription"`
+}
+
+// RefundResponse wraps the refund issued through the payment provider.
+type RefundResponse struct {
+	Refund *paymentprovider.Refund `json:"refund"`
+}
+
+// RevenueMetricsResponse wraps the revenue metrics returned through BMS.
+type RevenueMetricsResponse struct {
+	*billing.GetRevenueMetricsResponse
+}
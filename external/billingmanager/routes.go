@@ -15,7 +15,35 @@ type billingmanagerHandler interface {
 	GetUserBillingDetail(w http.ResponseWriter, r *http.Request)
 	GetPricingPlans(w http.ResponseWriter, r *http.Request)
 	GetPricePlanBySlug(w http.ResponseWriter, r *http.Request)
+	QuotePricePlan(w http.ResponseWriter, r *http.Request)
 	GetPricingFeatures(w http.ResponseWriter, r *http.Request)
+	GetWebhookDeliveries(w http.ResponseWriter, r *http.Request)
+	ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request)
+	CreateCheckoutSession(w http.ResponseWriter, r *http.Request)
+	CreateCustomerPortalSession(w http.ResponseWriter, r *http.Request)
+	GetMyEntitlements(w http.ResponseWriter, r *http.Request)
+	GetSubjectEntitlements(w http.ResponseWriter, r *http.Request)
+	GetEntitlementGrants(w http.ResponseWriter, r *http.Request)
+	CreateEntitlementGrant(w http.ResponseWriter, r *http.Request)
+	RevokeEntitlementGrant(w http.ResponseWriter, r *http.Request)
+	GetMyUsage(w http.ResponseWriter, r *http.Request)
+	GetSubjectUsage(w http.ResponseWriter, r *http.Request)
+	GetUsageEvents(w http.ResponseWriter, r *http.Request)
+	RecordUsage(w http.ResponseWriter, r *http.Request)
+	ReconcileSubscriptions(w http.ResponseWriter, r *http.Request)
+	GetSubscriptionDriftReport(w http.ResponseWriter, r *http.Request)
+	ProcessDunning(w http.ResponseWriter, r *http.Request)
+	MigratePricePlanVersions(w http.ResponseWriter, r *http.Request)
+	SyncPriceCatalog(w http.ResponseWriter, r *http.Request)
+	GetMyInvoices(w http.ResponseWriter, r *http.Request)
+	DownloadMyInvoice(w http.ResponseWriter, r *http.Request)
+	EmailMyInvoice(w http.ResponseWriter, r *http.Request)
+	SearchSubscriptions(w http.ResponseWriter, r *http.Request)
+	CancelSubscription(w http.ResponseWriter, r *http.Request)
+	PauseSubscription(w http.ResponseWriter, r *http.Request)
+	RefundBillingEvent(w http.ResponseWriter, r *http.Request)
+	GrantComplimentarySubscription(w http.ResponseWriter, r *http.Request)
+	GetRevenueMetrics(w http.ResponseWriter, r *http.Request)
 }
 
 const (
@@ -47,6 +75,7 @@ func AttachRoutes(request *AttachRoutesRequest) {
 
 	billingmanagerPricingOpenRoutes := httpRouter.PathPrefix(APIBillingManagerV1Prefix + "/pricing").Subrouter()
 	billingmanagerPricingOpenRoutes.HandleFunc("/plans", request.Handler.GetPricingPlans).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerPricingOpenRoutes.HandleFunc("/plans/{slug}/quote", request.Handler.QuotePricePlan).Methods(http.MethodGet, http.MethodOptions)
 	billingmanagerPricingOpenRoutes.HandleFunc("/plans/{slug}", request.Handler.GetPricePlanBySlug).Methods(http.MethodGet, http.MethodOptions)
 	billingmanagerPricingOpenRoutes.HandleFunc("/features", request.Handler.GetPricingFeatures).Methods(http.MethodGet, http.MethodOptions)
 
@@ -55,9 +84,41 @@ func AttachRoutes(request *AttachRoutesRequest) {
 
 	billingmanagerActiveOnlyRoutes := httpRouter.PathPrefix(APIBillingManagerV1Prefix).Subrouter()
 	billingmanagerActiveOnlyRoutes.HandleFunc("/billings/users/{userId}/events", request.Handler.GetUserBillingEvents).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerActiveOnlyRoutes.HandleFunc("/billings/checkout-sessions", request.Handler.CreateCheckoutSession).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerActiveOnlyRoutes.HandleFunc("/billings/portal-sessions", request.Handler.CreateCustomerPortalSession).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerActiveOnlyRoutes.HandleFunc("/me/entitlements", request.Handler.GetMyEntitlements).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerActiveOnlyRoutes.HandleFunc("/me/usage", request.Handler.GetMyUsage).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerActiveOnlyRoutes.HandleFunc("/me/invoices", request.Handler.GetMyInvoices).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerActiveOnlyRoutes.HandleFunc("/me/invoices/{invoiceId}/download", request.Handler.DownloadMyInvoice).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerActiveOnlyRoutes.HandleFunc("/me/invoices/{invoiceId}/email", request.Handler.EmailMyInvoice).Methods(http.MethodPost, http.MethodOptions)
 	billingmanagerActiveOnlyRoutes.HandleFunc("/users/{userId}/details/subscription", request.Handler.GetUserSubscriptionStatus).Methods(http.MethodGet, http.MethodOptions)
 	billingmanagerActiveOnlyRoutes.HandleFunc("/users/{userId}/details/billing", request.Handler.GetUserBillingDetail).Methods(http.MethodGet, http.MethodOptions)
 	if request.MiddlewareActiveValidApiTokenOrJWTMiddleware != nil {
 		billingmanagerActiveOnlyRoutes.Use(request.MiddlewareActiveValidApiTokenOrJWTMiddleware)
 	}
+
+	billingmanagerAdminRoutes := httpRouter.PathPrefix(APIBillingManagerV1Prefix).Subrouter()
+	billingmanagerAdminRoutes.HandleFunc("/billings/webhooks/deliveries", request.Handler.GetWebhookDeliveries).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/webhooks/deliveries/{deliveryId}/replay", request.Handler.ReplayWebhookDelivery).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/subscriptions/drift", request.Handler.GetSubscriptionDriftReport).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/subscriptions/reconcile", request.Handler.ReconcileSubscriptions).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/subscriptions/complimentary", request.Handler.GrantComplimentarySubscription).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/subscriptions", request.Handler.SearchSubscriptions).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/subscriptions/{subscriptionId}/cancel", request.Handler.CancelSubscription).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/subscriptions/{subscriptionId}/pause", request.Handler.PauseSubscription).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/events/{eventId}/refund", request.Handler.RefundBillingEvent).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/revenue", request.Handler.GetRevenueMetrics).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/dunning/process", request.Handler.ProcessDunning).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/plan-versions/migrate", request.Handler.MigratePricePlanVersions).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/billings/catalog/sync", request.Handler.SyncPriceCatalog).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/entitlements/grants", request.Handler.GetEntitlementGrants).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/entitlements/grants", request.Handler.CreateEntitlementGrant).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/entitlements/grants/{grantId}/revoke", request.Handler.RevokeEntitlementGrant).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/entitlements/{subjectType}/{subjectId}", request.Handler.GetSubjectEntitlements).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/usage/events", request.Handler.GetUsageEvents).Methods(http.MethodGet, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/usage/events", request.Handler.RecordUsage).Methods(http.MethodPost, http.MethodOptions)
+	billingmanagerAdminRoutes.HandleFunc("/usage/{subjectType}/{subjectId}", request.Handler.GetSubjectUsage).Methods(http.MethodGet, http.MethodOptions)
+	if request.MiddlewareAdminOnlyMiddleware != nil {
+		billingmanagerAdminRoutes.Use(request.MiddlewareAdminOnlyMiddleware)
+	}
 }
@@ -0,0 +1,324 @@
+package billingmanager
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
+)
+
+// SearchSubscriptions returns subscriptions across all users matching the admin's filters
+func (s *Service) SearchSubscriptions(ctx context.Context, req *SearchSubscriptionsRequest) (*SearchSubscriptionsResponse, error) {
+
+	subscriptionsResp, err := s.BillingService.GetSubscriptions(ctx, req.GetSubscriptionsRequest)
+	if err != nil {
+		logger.AcquirePackageFrom(ctx, "external/billingmanager").Error("failed-to-search-subscriptions", zap.Error(err))
+		return nil, err
+	}
+
+	return &SearchSubscriptionsResponse{GetSubscriptionsResponse: subscriptionsResp}, nil
+}
+
+// CancelSubscription cancels a subscription immediately or at the end of its current billing
+// period. Provider subscriptions are cancelled on the payment provider first, complimentary
+// subscriptions are only changed locally
+func (s *Service) CancelSubscription(ctx context.Context, req *ChangeSubscriptionRequest) (*SubscriptionResponse, error) {
+	return s.changeSubscription(ctx, billing.ScheduledChangeActionCancel, req)
+}
+
+// PauseSubscription pauses a subscription immediately or at the end of its current billing
+// period. Provider subscriptions are paused on the payment provider first, complimentary
+// subscriptions are only changed locally
+func (s *Service) PauseSubscription(ctx context.Context, req *ChangeSubscriptionRequest) (*SubscriptionResponse, error) {
+	return s.changeSubscription(ctx, billing.ScheduledChangeActionPause, req)
+}
+
+// changeSubscription cancels or pauses the subscription. A change scheduled for the period end
+// is recorded on the subscription and applied when the provider's webhook says it took effect
+func (s *Service) changeSubscription(ctx context.Context, action string, req *ChangeSubscriptionRequest) (*SubscriptionResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", action+"-subscription"),
+		zap.String("subscription-id", req.SubscriptionID),
+		zap.Bool("at-period-end", req.AtPeriodEnd),
+	)
+
+	subscriptionResp, err := s.BillingService.GetSubscriptionByID(ctx, &billing.GetSubscriptionByIDRequest{ID: req.SubscriptionID})
+	if err != nil {
+		logger.Warn("failed-to-get-subscription-to-change", zap.Error(err))
+		return nil, err
+	}
+	subscription := subscriptionResp.Subscription
+
+	if subscription.IsCancelled() || (action == billing.ScheduledChangeActionPause && subscription.Status == billing.StatusPaused) {
+		logger.Warn("subscription-not-changeable", zap.String("status", subscription.Status))
+		return nil, ErrBillingManagerSubscriptionNotChangeable
+	}
+
+	now := time.Now().UTC()
+	updateReq := &billing.UpdateSubscriptionRequest{ID: subscription.ID}
+
+	switch {
+	case subscription.IsComplimentary():
+		applySubscriptionChange(updateReq, action, now)
+		if action == billing.ScheduledChangeActionCancel && req.AtPeriodEnd && subscription.AvailableUntilDate != nil && subscription.AvailableUntilDate.After(now) {
+			updateReq.AvailableUntilDate = subscription.AvailableUntilDate
+		}
+
+	default:
+		change, err := s.requestProviderSubscriptionChange(ctx, action, subscription, req.AtPeriodEnd)
+		if err != nil {
+			logger.Error("failed-to-change-subscription-on-provider", zap.String("provider", subscription.Integrator), zap.Error(err))
+			return nil, err
+		}
+
+		if req.AtPeriodEnd {
+			effectiveAt := parseTimeOrNil(change.EffectiveAt)
+			if effectiveAt == nil {
+				effectiveAt = subscription.NextBillingDate
+			}
+			updateReq.ScheduledChange = &billing.SubscriptionScheduledChange{
+				Action:      action,
+				EffectiveAt: effectiveAt,
+				RequestedAt: now,
+			}
+			break
+		}
+
+		applySubscriptionChange(updateReq, action, now)
+	}
+
+	updateResp, err := s.BillingService.UpdateSubscription(ctx, updateReq)
+	if err != nil {
+		logger.Error("failed-to-update-changed-subscription", zap.Error(err))
+		return nil, err
+	}
+	updated := updateResp.Subscription
+
+	auditAction := AuditActionBillingSubscriptionCancelled
+	if action == billing.ScheduledChangeActionPause {
+		auditAction = AuditActionBillingSubscriptionPaused
+	}
+	s.logAdminAuditEvent(ctx, req.RequestingUserID, auditAction, updated.ID, TargetTypeSubscription, map[string]string{
+		"at_period_end": strconv.FormatBool(req.AtPeriodEnd),
+		"provider":      updated.Integrator,
+		"reason":        req.Reason,
+	})
+
+	s.refreshSubscriptionAccess(ctx, updated)
+
+	logger.Info("subscription-changed", zap.String("status", updated.Status))
+
+	return &SubscriptionResponse{Subscription: updated}, nil
+}
+
+// requestProviderSubscriptionChange asks the subscription's payment provider to cancel or pause it
+func (s *Service) requestProviderSubscriptionChange(ctx context.Context, action string, subscription *billing.Subscription, atPeriodEnd bool) (*paymentprovider.SubscriptionChange, error) {
+
+	manager, ok := s.ProviderRegistry.(subscriptionManager)
+	if !ok {
+		return nil, ErrBillingManagerSubscriptionManagementNotSupported
+	}
+
+	changeReq := &paymentprovider.SubscriptionChangeRequest{
+		SubscriptionID: subscription.IntegratorSubscriptionID,
+		AtPeriodEnd:    atPeriodEnd,
+	}
+
+	if action == billing.ScheduledChangeActionPause {
+		return manager.PauseSubscription(ctx, subscription.Integrator, changeReq)
+	}
+
+	return manager.CancelSubscription(ctx, subscription.Integrator, changeReq)
+}
+
+// applySubscriptionChange sets the update needed for a cancellation or pause that takes effect now
+func applySubscriptionChange(updateReq *billing.UpdateSubscriptionRequest, action string, now time.Time) {
+
+	status := billing.StatusPaused
+	if action == billing.ScheduledChangeActionCancel {
+		status = billing.StatusCancelled
+		updateReq.CancelledAt = &now
+		updateReq.AvailableUntilDate = &now
+	}
+
+	updateReq.Status = &status
+	updateReq.ClearScheduledChange = true
+}
+
+// RefundBillingEvent refunds, in full or in part, the payment recorded by a successful payment
+// billing event. The refund itself is recorded when the provider's refund webhook arrives
+func (s *Service) RefundBillingEvent(ctx context.Context, req *RefundBillingEventRequest) (*RefundResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "refund-billing-event"),
+		zap.String("billing-event-id", req.BillingEventID),
+		zap.Int64("amount", req.Amount),
+	)
+
+	refunder, ok := s.ProviderRegistry.(paymentRefunder)
+	if !ok {
+		logger.Error("payment-provider-registry-does-not-support-refunds")
+		return nil, ErrBillingManagerRefundsNotSupported
+	}
+
+	eventResp, err := s.BillingService.GetBillingEventByID(ctx, &billing.GetBillingEventByIDRequest{ID: req.BillingEventID})
+	if err != nil {
+		logger.Warn("failed-to-get-billing-event-to-refund", zap.Error(err))
+		return nil, err
+	}
+	event := eventResp.BillingEvent
+
+	if event.EventType != paymentprovider.EventTypePaymentSucceeded || event.IntegratorTransactionID == "" {
+		logger.Warn("billing-event-not-refundable", zap.String("event-type", event.EventType))
+		return nil, ErrBillingManagerBillingEventNotRefundable
+	}
+
+	if req.Amount < 0 || req.Amount > event.Amount {
+		logger.Warn("invalid-refund-amount", zap.Int64("paid-amount", event.Amount))
+		return nil, ErrBillingManagerInvalidRefundAmount
+	}
+
+	refund, err := refunder.RefundPayment(ctx, event.Integrator, &paymentprovider.RefundRequest{
+		TransactionID: event.IntegratorTransactionID,
+		Amount:        req.Amount,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		logger.Error("failed-to-refund-payment-on-provider", zap.String("provider", event.Integrator), zap.Error(err))
+		return nil, err
+	}
+
+	s.logAdminAuditEvent(ctx, req.RequestingUserID, AuditActionBillingPaymentRefunded, event.ID, TargetTypeBillingEvent, map[string]string{
+		"provider":       event.Integrator,
+		"transaction_id": event.IntegratorTransactionID,
+		"refund_id":      refund.ID,
+		"amount":         strconv.FormatInt(req.Amount, 10),
+		"reason":         req.Reason,
+	})
+
+	logger.Info("payment-refunded", zap.String("refund-id", refund.ID), zap.String("refund-status", refund.Status))
+
+	return &RefundResponse{Refund: refund}, nil
+}
+
+// GrantComplimentarySubscription gives a user or group an active subscription to a plan without
+// billing them through a payment provider. It entitles like any other subscription until it
+// ends or is cancelled
+func (s *Service) GrantComplimentarySubscription(ctx context.Context, req *GrantComplimentarySubscriptionRequest) (*SubscriptionResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "grant-complimentary-subscription"),
+		zap.String("user-id", req.UserID),
+		zap.String("group-id", req.GroupID),
+		zap.String("plan-name", req.PlanName),
+	)
+
+	if (req.UserID == "") == (req.GroupID == "") {
+		logger.Warn("complimentary-subscription-requires-a-user-or-group")
+		return nil, ErrBillingManagerInvalidComplimentarySubscription
+	}
+
+	var availableUntil *time.Time
+	if req.AvailableUntil != "" {
+		availableUntil = parseTimeOrNil(req.AvailableUntil)
+		if availableUntil == nil || !availableUntil.After(time.Now()) {
+			logger.Warn("invalid-complimentary-subscription-end-date", zap.String("available-until", req.AvailableUntil))
+			return nil, ErrBillingManagerInvalidComplimentarySubscription
+		}
+	}
+
+	var email string
+	if req.UserID != "" && s.UserService != nil {
+		userResp, err := s.UserService.GetUserByID(ctx, &user.GetUserByIDRequest{ID: req.UserID})
+		if err != nil {
+			logger.Warn("failed-to-get-user-for-complimentary-subscription", zap.Error(err))
+			return nil, err
+		}
+		email = userResp.User.Email
+	}
+
+	createResp, err := s.BillingService.CreateSubscription(ctx, &billing.CreateSubscriptionRequest{
+		UserID:                   req.UserID,
+		GroupID:                  req.GroupID,
+		Email:                    email,
+		Status:                   billing.StatusActive,
+		Integrator:               billing.IntegratorManual,
+		IntegratorSubscriptionID: complimentarySubscriptionIDPrefix + toolbox.GenerateUuidV4(),
+		PlanName:                 req.PlanName,
+		PlanID:                   req.PlanID,
+		Quantity:                 req.Quantity,
+		AvailableUntilDate:       availableUntil,
+		Metadata:                 map[string]interface{}{"granted_by": req.RequestingUserID, "reason": req.Reason},
+	})
+	if err != nil {
+		logger.Error("failed-to-create-complimentary-subscription", zap.Error(err))
+		return nil, err
+	}
+	subscription := createResp.Subscription
+
+	if _, err := s.BillingService.CreateBillingEvent(ctx, &billing.CreateBillingEventRequest{
+		SubscriptionID:           subscription.ID,
+		UserID:                   subscription.UserID,
+		Email:                    email,
+		EventType:                paymentprovider.EventTypeSubscriptionCreated,
+		Integrator:               billing.IntegratorManual,
+		IntegratorEventID:        subscription.IntegratorSubscriptionID,
+		IntegratorSubscriptionID: subscription.IntegratorSubscriptionID,
+		Status:                   subscription.Status,
+		PlanName:                 subscription.PlanName,
+		EventTime:                time.Now(),
+	}); err != nil {
+		logger.Warn("failed-to-record-complimentary-subscription-billing-event", zap.String("subscription-id", subscription.ID), zap.Error(err))
+	}
+
+	s.logAdminAuditEvent(ctx, req.RequestingUserID, AuditActionBillingComplimentarySubscriptionGranted, subscription.ID, TargetTypeSubscription, map[string]string{
+		"user_id":         subscription.UserID,
+		"group_id":        subscription.GroupID,
+		"plan_name":       subscription.PlanName,
+		"available_until": req.AvailableUntil,
+		"reason":          req.Reason,
+	})
+
+	s.refreshSubscriptionAccess(ctx, subscription)
+
+	logger.Info("complimentary-subscription-granted", zap.String("subscription-id", subscription.ID))
+
+	return &SubscriptionResponse{Subscription: subscription}, nil
+}
+
+// GetRevenueMetrics returns monthly recurring revenue, active subscriptions by plan and churn
+func (s *Service) GetRevenueMetrics(ctx context.Context, req *GetRevenueMetricsRequest) (*RevenueMetricsResponse, error) {
+
+	metricsResp, err := s.BillingService.GetRevenueMetrics(ctx, req.GetRevenueMetricsRequest)
+	if err != nil {
+		logger.AcquirePackageFrom(ctx, "external/billingmanager").Warn("failed-to-get-revenue-metrics", zap.Error(err))
+		return nil, err
+	}
+
+	return &RevenueMetricsResponse{GetRevenueMetricsResponse: metricsResp}, nil
+}
+
+// logAdminAuditEvent records a billing change made by an admin
+func (s *Service) logAdminAuditEvent(ctx context.Context, actorID string, action audit.AuditAction, targetID string, targetType audit.TargetType, details map[string]string) {
+
+	if s.AuditService == nil {
+		return
+	}
+
+	_ = s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+		ActorId:    actorID,
+		Action:     action,
+		TargetId:   targetID,
+		TargetType: targetType,
+		Domain:     "billingmanager",
+		Details:    details,
+	})
+}
@@ -0,0 +1,497 @@
+package billingmanager
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/pricer"
+	"go.uber.org/zap"
+)
+
+// SyncPriceCatalog pushes price plans and their costs to a payment provider as products and
+// prices and writes the provider's identifiers back to the plans, so checkout never depends on
+// IDs copied by hand from a dashboard. Products are updated in place. Prices cannot be changed
+// once created, so a cost whose amount, currency or interval no longer matches gets a new price
+// and the old one is archived, leaving existing subscriptions on it untouched.
+//
+// Where the provider only allows its catalog to be changed in its dashboard the drift is reported
+// as manual. A dry run reports what would change without touching the provider or the catalog.
+func (s *Service) SyncPriceCatalog(ctx context.Context, req *SyncPriceCatalogRequest) (*CatalogSyncReportResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "sync-price-catalog"),
+		zap.String("provider", req.ProviderName),
+		zap.Bool("dry-run", req.DryRun),
+	)
+
+	if req.ProviderName == "" {
+		logger.Warn("missing-provider-for-catalog-sync")
+		return nil, ErrInvalidBillingManagerRequestPayload
+	}
+
+	if s.PricerService == nil {
+		logger.Error("pricer-service-not-set")
+		return nil, ErrBillingManagerPricerServiceNotSet
+	}
+
+	syncer, ok := s.ProviderRegistry.(catalogSyncer)
+	if !ok || !syncer.SupportsCatalog(req.ProviderName) {
+		logger.Error("payment-provider-catalog-cannot-be-read")
+		return nil, ErrBillingManagerCatalogSyncNotSupported
+	}
+
+	updater, ok := s.PricerService.(pricePlanUpdater)
+	if !ok && !req.DryRun {
+		logger.Error("pricer-service-does-not-support-price-plan-updates")
+		return nil, ErrBillingManagerCatalogSyncNotSupported
+	}
+
+	if !req.DryRun {
+		if !s.catalogSyncMu.TryLock() {
+			logger.Warn("catalog-sync-already-in-progress")
+			return nil, ErrBillingManagerCatalogSyncInProgress
+		}
+		defer s.catalogSyncMu.Unlock()
+	}
+
+	sync := &catalogSync{
+		service:      s,
+		syncer:       syncer,
+		updater:      updater,
+		providerName: req.ProviderName,
+		canWrite:     syncer.SupportsCatalogWrites(req.ProviderName),
+		dryRun:       req.DryRun,
+		userID:       req.RequestingUserID,
+		report: &CatalogSyncReport{
+			DryRun:       req.DryRun,
+			ProviderName: req.ProviderName,
+			StartedAt:    time.Now().UTC(),
+			Changes:      []CatalogSyncChange{},
+		},
+	}
+
+	plans, err := s.getCatalogSyncPricePlans(ctx, req.PlanSlugs)
+	if err != nil {
+		logger.Error("failed-to-get-price-plans-to-sync", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range plans {
+		if ctx.Err() != nil {
+			logger.Warn("catalog-sync-interrupted", zap.Error(ctx.Err()))
+			break
+		}
+
+		sync.syncPricePlan(ctx, &plans[i])
+	}
+
+	sync.report.CompletedAt = time.Now().UTC()
+
+	logger.Info("catalog-sync-completed",
+		zap.Int("checked", sync.report.Checked),
+		zap.Int("in-sync", sync.report.InSync),
+		zap.Int("changed", sync.report.Changed),
+		zap.Int("applied", sync.report.Applied),
+		zap.Int("manual", sync.report.Manual),
+		zap.Int("failed", sync.report.Failed),
+	)
+
+	return &CatalogSyncReportResponse{Report: sync.report}, nil
+}
+
+// getCatalogSyncPricePlans returns every price plan that is not deleted or archived, limited
+// to the given slugs when there are any
+func (s *Service) getCatalogSyncPricePlans(ctx context.Context, slugs []string) ([]pricer.PricePlan, error) {
+	var plans []pricer.PricePlan
+
+	for page := 1; ; page++ {
+		response, err := s.PricerService.GetPricePlans(ctx, &pricer.GetPricePlansRequest{
+			Order:            "created_at_asc",
+			PerPage:          catalogSyncPageSize,
+			Page:             page,
+			IncludeCosts:     true,
+			IncludeProviders: true,
+			Slugs:            strings.Join(slugs, ","),
+			IsNotDeleted:     true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, plan := range response.PricePlans {
+			if plan.Status == pricer.PricePlanStatusArchived {
+				continue
+			}
+			plans = append(plans, plan)
+		}
+
+		if page >= response.TotalPages || len(response.PricePlans) == 0 {
+			return plans, nil
+		}
+	}
+}
+
+// catalogSync holds the state of a single catalog sync run
+type catalogSync struct {
+	service      *Service
+	syncer       catalogSyncer
+	updater      pricePlanUpdater
+	providerName string
+	canWrite     bool
+	dryRun       bool
+	userID       string
+	report       *CatalogSyncReport
+}
+
+// syncPricePlan compares a price plan's product and the prices of its costs with the provider,
+// applying and writing back any change unless it is a dry run
+func (c *catalogSync) syncPricePlan(ctx context.Context, plan *pricer.PricePlan) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "sync-price-plan-catalog"),
+		zap.String("provider", c.providerName),
+		zap.String("price-plan-id", plan.ID),
+	)
+
+	var (
+		changes     []CatalogSyncChange
+		refsChanged bool
+	)
+
+	productRefIndex := findCatalogProviderRef(plan.ProviderRefs, c.providerName)
+	productID := ""
+	if productRefIndex >= 0 {
+		productID = catalogProductIDFromRef(&plan.ProviderRefs[productRefIndex])
+	}
+
+	productChange, newProductID := c.syncProduct(ctx, plan, productID)
+	if productChange != nil {
+		changes = append(changes, *productChange)
+	}
+	if newProductID != "" {
+		plan.ProviderRefs = setCatalogProviderRef(plan.ProviderRefs, c.providerName, func(ref *pricer.PriceProviderRef) {
+			ref.ProviderProductID = newProductID
+		})
+		productID = newProductID
+		refsChanged = true
+	}
+
+	for i := range plan.Costs {
+		cost := &plan.Costs[i]
+
+		priceRefIndex := findCatalogProviderRef(cost.ProviderRefs, c.providerName)
+		priceID := ""
+		if priceRefIndex >= 0 {
+			priceID = catalogPriceIDFromRef(&cost.ProviderRefs[priceRefIndex])
+		}
+
+		priceChange, newPriceID := c.syncPrice(ctx, plan, cost, productID, priceID)
+		if priceChange != nil {
+			changes = append(changes, *priceChange)
+		}
+		if newPriceID != "" {
+			cost.ProviderRefs = setCatalogProviderRef(cost.ProviderRefs, c.providerName, func(ref *pricer.PriceProviderRef) {
+				if ref.ProviderID != "" && ref.ProviderID == priceID {
+					ref.ProviderID = newPriceID
+				}
+				ref.ProviderPriceID = newPriceID
+				ref.ProviderProductID = productID
+			})
+			refsChanged = true
+		}
+	}
+
+	if refsChanged {
+		if err := c.writeBackProviderRefs(ctx, plan); err != nil {
+			logger.Error("failed-to-write-back-provider-refs", zap.Error(err))
+			for i := range changes {
+				if changes[i].NewProviderID != "" && changes[i].Applied {
+					changes[i].Applied = false
+					changes[i].Error = "created on provider but not saved to price plan: " + err.Error()
+					c.report.Applied--
+					c.report.Failed++
+				}
+			}
+		}
+	}
+
+	c.report.Changes = append(c.report.Changes, changes...)
+}
+
+// syncProduct compares a price plan with its product on the provider. It returns the change
+// needed, if any, and the ID of a product it created
+func (c *catalogSync) syncProduct(ctx context.Context, plan *pricer.PricePlan, productID string) (*CatalogSyncChange, string) {
+
+	desired := &paymentprovider.CatalogProduct{
+		ID:          productID,
+		Name:        plan.Name,
+		Description: plan.Description,
+		Active:      plan.Status == pricer.PricePlanStatusPublished,
+		Metadata: map[string]string{
+			CatalogMetadataKeyPricePlanID:   plan.ID,
+			CatalogMetadataKeyPricePlanSlug: plan.Slug,
+		},
+	}
+
+	change := &CatalogSyncChange{
+		PricePlanID:   plan.ID,
+		PricePlanSlug: plan.Slug,
+		Resource:      CatalogSyncResourceProduct,
+		ProviderID:    productID,
+	}
+
+	c.report.Checked++
+
+	if productID != "" {
+		current, err := c.syncer.GetCatalogProduct(ctx, c.providerName, productID)
+		switch {
+		case errors.Is(err, paymentprovider.ErrPaymentProviderCatalogItemNotFound):
+			desired.ID = ""
+		case err != nil:
+			return c.failChange(change, err), ""
+		default:
+			change.Fields = catalogProductDrift(desired, current)
+			if len(change.Fields) == 0 {
+				c.report.InSync++
+				return nil, ""
+			}
+		}
+	}
+
+	change.Action = CatalogSyncActionUpdate
+	if desired.ID == "" {
+		change.Action = CatalogSyncActionCreate
+	}
+
+	if !c.canWrite {
+		return c.manualChange(change), ""
+	}
+
+	c.report.Changed++
+	if c.dryRun {
+		return change, ""
+	}
+
+	saved, err := c.syncer.SaveCatalogProduct(ctx, c.providerName, desired)
+	if err != nil {
+		return c.failChange(change, err), ""
+	}
+
+	c.report.Applied++
+	change.Applied = true
+	if change.Action == CatalogSyncActionCreate {
+		change.NewProviderID = saved.ID
+		return change, saved.ID
+	}
+
+	return change, ""
+}
+
+// syncPrice compares a plan cost with its price on the provider. It returns the change needed,
+// if any, and the ID of a price it created
+func (c *catalogSync) syncPrice(ctx context.Context, plan *pricer.PricePlan, cost *pricer.PriceCost, productID string, priceID string) (*CatalogSyncChange, string) {
+
+	desired := &paymentprovider.CatalogPrice{
+		ProductID: productID,
+		Amount:    cost.Amount,
+		Currency:  pricer.NormaliseCurrency(cost.Currency),
+		Active:    true,
+		Metadata: map[string]string{
+			CatalogMetadataKeyPricePlanID: plan.ID,
+			CatalogMetadataKeyPriceCostID: cost.ID,
+		},
+	}
+	if cost.BillingCadence != pricer.PriceBillingCadenceOneTime {
+		desired.Interval = string(cost.BillingCadence)
+		desired.IntervalCount = 1
+	}
+
+	change := &CatalogSyncChange{
+		PricePlanID:   plan.ID,
+		PricePlanSlug: plan.Slug,
+		CostID:        cost.ID,
+		Resource:      CatalogSyncResourcePrice,
+		ProviderID:    priceID,
+		Action:        CatalogSyncActionCreate,
+	}
+
+	c.report.Checked++
+
+	if priceID != "" {
+		current, err := c.syncer.GetCatalogPrice(ctx, c.providerName, priceID)
+		switch {
+		case errors.Is(err, paymentprovider.ErrPaymentProviderCatalogItemNotFound):
+		case err != nil:
+			return c.failChange(change, err), ""
+		default:
+			change.Fields = catalogPriceDrift(desired, current)
+			if len(change.Fields) == 0 {
+				c.report.InSync++
+				return nil, ""
+			}
+			change.Action = CatalogSyncActionReplace
+		}
+	}
+
+	if !c.canWrite {
+		return c.manualChange(change), ""
+	}
+
+	c.report.Changed++
+	if c.dryRun {
+		return change, ""
+	}
+
+	if productID == "" {
+		return c.failChange(change, paymentprovider.ErrPaymentProviderCatalogItemNotFound), ""
+	}
+
+	created, err := c.syncer.CreateCatalogPrice(ctx, c.providerName, desired)
+	if err != nil {
+		return c.failChange(change, err), ""
+	}
+
+	change.NewProviderID = created.ID
+	change.Applied = true
+	c.report.Applied++
+
+	if change.Action == CatalogSyncActionReplace {
+		if err := c.syncer.ArchiveCatalogPrice(ctx, c.providerName, priceID); err != nil {
+			change.Error = "new price created but old price not archived: " + err.Error()
+		}
+	}
+
+	return change, created.ID
+}
+
+// writeBackProviderRefs saves the provider references of a synced price plan
+func (c *catalogSync) writeBackProviderRefs(ctx context.Context, plan *pricer.PricePlan) error {
+	if _, err := c.updater.UpdatePricePlan(ctx, &pricer.UpdatePricePlanRequest{
+		UserID:       c.userID,
+		ID:           plan.ID,
+		Costs:        plan.Costs,
+		ProviderRefs: plan.ProviderRefs,
+	}); err != nil {
+		return err
+	}
+
+	if c.service.AuditService != nil {
+		_ = c.service.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    c.userID,
+			Action:     AuditActionBillingPricePlanCatalogSynced,
+			TargetId:   plan.ID,
+			TargetType: TargetTypePricePlan,
+			Domain:     "billingmanager",
+			Details:    map[string]string{"provider": c.providerName},
+		})
+	}
+
+	return nil
+}
+
+// manualChange records a change that must be made in the provider's dashboard
+func (c *catalogSync) manualChange(change *CatalogSyncChange) *CatalogSyncChange {
+	c.report.Changed++
+	c.report.Manual++
+	change.Action = CatalogSyncActionManual
+	return change
+}
+
+// failChange records a product or price that could not be checked or changed
+func (c *catalogSync) failChange(change *CatalogSyncChange, err error) *CatalogSyncChange {
+	c.report.Failed++
+	change.Error = err.Error()
+	return change
+}
+
+// findCatalogProviderRef returns the index of the first reference to the provider, or -1
+func findCatalogProviderRef(refs []pricer.PriceProviderRef, providerName string) int {
+	for i := range refs {
+		if string(refs[i].Provider) == providerName {
+			return i
+		}
+	}
+	return -1
+}
+
+// setCatalogProviderRef applies set to the reference to the provider, adding one if the
+// plan or cost has none. A new slice is returned so the caller's original is not changed
+func setCatalogProviderRef(refs []pricer.PriceProviderRef, providerName string, set func(ref *pricer.PriceProviderRef)) []pricer.PriceProviderRef {
+	updated := append([]pricer.PriceProviderRef{}, refs...)
+
+	index := findCatalogProviderRef(updated, providerName)
+	if index < 0 {
+		updated = append(updated, pricer.PriceProviderRef{Provider: pricer.PriceProvider(providerName)})
+		index = len(updated) - 1
+	}
+
+	set(&updated[index])
+	return updated
+}
+
+// catalogProductIDFromRef returns the provider product a price plan reference points at
+func catalogProductIDFromRef(ref *pricer.PriceProviderRef) string {
+	if ref.ProviderProductID != "" {
+		return ref.ProviderProductID
+	}
+	return ref.ProviderID
+}
+
+// catalogPriceIDFromRef returns the provider price a cost reference points at, matching
+// how checkout picks the price
+func catalogPriceIDFromRef(ref *pricer.PriceProviderRef) string {
+	if ref.ProviderPriceID != "" {
+		return ref.ProviderPriceID
+	}
+	return ref.ProviderID
+}
+
+// catalogProductDrift returns the fields of a provider product that differ from the price plan
+func catalogProductDrift(desired *paymentprovider.CatalogProduct, current *paymentprovider.CatalogProduct) []CatalogDriftField {
+	var fields []CatalogDriftField
+
+	if desired.Name != current.Name {
+		fields = append(fields, CatalogDriftField{Field: "name", Catalog: desired.Name, Provider: current.Name})
+	}
+	if desired.Description != current.Description {
+		fields = append(fields, CatalogDriftField{Field: "description", Catalog: desired.Description, Provider: current.Description})
+	}
+	if desired.Active != current.Active {
+		fields = append(fields, CatalogDriftField{Field: "active", Catalog: strconv.FormatBool(desired.Active), Provider: strconv.FormatBool(current.Active)})
+	}
+
+	return fields
+}
+
+// catalogPriceDrift returns the fields of a provider price that differ from the plan cost.
+// Currency is only compared when the provider returns one
+func catalogPriceDrift(desired *paymentprovider.CatalogPrice, current *paymentprovider.CatalogPrice) []CatalogDriftField {
+	var fields []CatalogDriftField
+
+	if desired.ProductID != "" && desired.ProductID != current.ProductID {
+		fields = append(fields, CatalogDriftField{Field: "product_id", Catalog: desired.ProductID, Provider: current.ProductID})
+	}
+	if desired.Amount != current.Amount {
+		fields = append(fields, CatalogDriftField{Field: "amount", Catalog: strconv.FormatInt(desired.Amount, 10), Provider: strconv.FormatInt(current.Amount, 10)})
+	}
+	if current.Currency != "" && desired.Currency != strings.ToUpper(current.Currency) {
+		fields = append(fields, CatalogDriftField{Field: "currency", Catalog: desired.Currency, Provider: current.Currency})
+	}
+	if desired.Interval != current.Interval {
+		fields = append(fields, CatalogDriftField{Field: "interval", Catalog: desired.Interval, Provider: current.Interval})
+	}
+	if desired.Interval != "" && desired.IntervalCount != current.IntervalCount {
+		fields = append(fields, CatalogDriftField{Field: "interval_count", Catalog: strconv.FormatInt(desired.IntervalCount, 10), Provider: strconv.FormatInt(current.IntervalCount, 10)})
+	}
+	if !current.Active {
+		fields = append(fields, CatalogDriftField{Field: "active", Catalog: "true", Provider: "false"})
+	}
+
+	return fields
+}
@@ -0,0 +1,268 @@
+package billingmanager
+
+import (
+	"context"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/pricer"
+	"github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
+)
+
+// CreateCheckoutSession creates a hosted checkout with the requested payment provider for a
+// published price plan. The provider price is taken from the plan cost's provider refs and the
+// signed-in user is attached as metadata, so the resulting webhooks resolve to the user. When a
+// group is given the user must be one of its billing admins and the subscription is owned by the group.
+func (s *Service) CreateCheckoutSession(ctx context.Context, req *CreateCheckoutSessionRequest) (*CreateCheckoutSessionResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "create-checkout-session"),
+		zap.String("user-id", req.UserID),
+		zap.String("provider", req.ProviderName),
+		zap.String("plan-slug", req.PlanSlug),
+	)
+
+	if req.UserID == "" {
+		logger.Warn("failed-to-create-checkout-session-user-id-is-missing")
+		return nil, ErrBillingManagerRequiresUserIdIsMissing
+	}
+
+	creator, ok := s.ProviderRegistry.(checkoutSessionCreator)
+	if !ok {
+		logger.Error("provider-registry-does-not-support-checkout-sessions")
+		return nil, ErrBillingManagerCheckoutNotSupported
+	}
+
+	if s.PricerService == nil {
+		logger.Error("pricer-service-not-enabled")
+		return nil, ErrBillingManagerPricerServiceNotSet
+	}
+
+	quantity := req.Quantity
+	if req.GroupID != "" {
+		grp, err := s.getGroupForBillingAdmin(ctx, req.GroupID, req.UserID)
+		if err != nil {
+			logger.Warn("failed-to-authorise-group-checkout-session", zap.String("group-id", req.GroupID), zap.Error(err))
+			return nil, err
+		}
+
+		if quantity < 1 {
+			quantity = int64(max(len(grp.GetUserMemberIDs()), 1))
+		}
+	}
+
+	planResp, err := s.PricerService.GetPricePlanBySlug(ctx, &pricer.GetPricePlanBySlugRequest{
+		Slug:             req.PlanSlug,
+		IncludeCosts:     true,
+		IncludeProviders: true,
+	})
+	if err != nil {
+		logger.Warn("failed-to-get-price-plan-for-checkout-session", zap.Error(err))
+		return nil, err
+	}
+
+	plan := planResp.PricePlan
+	if plan == nil || plan.DeletedAt != "" || !isPricePlanPubliclyVisible(plan.PublishedAt) {
+		logger.Warn("price-plan-not-available-for-checkout")
+		return nil, pricer.ErrPricePlanNotFound
+	}
+
+	cost, priceID := selectCheckoutPrice(plan, req.ProviderName, req.CostID, req.BillingCadence)
+	if cost == nil {
+		logger.Warn("price-plan-has-no-provider-price-for-checkout", zap.String("cost-id", req.CostID), zap.String("billing-cadence", req.BillingCadence))
+		return nil, ErrBillingManagerNoProviderPriceForPlan
+	}
+
+	couponCode := req.CouponCode
+	promotionCode := pricer.NormalisePromotionCode(req.PromotionCode)
+	if promotionCode != "" {
+		couponCode, err = s.getCheckoutPromotionCodeCoupon(ctx, req, promotionCode, plan.ID, cost.BillingCadence)
+		if err != nil {
+			logger.Warn("failed-to-apply-promotion-code-to-checkout-session", zap.String("promotion-code", promotionCode), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	var customerEmail string
+	if s.UserService != nil {
+		userResp, err := s.UserService.GetUserByID(ctx, &user.GetUserByIDRequest{ID: req.UserID})
+		if err != nil {
+			logger.Error("failed-to-get-user-for-checkout-session", zap.Error(err))
+			return nil, err
+		}
+		customerEmail = userResp.User.GetUserEmail()
+	}
+
+	session, err := creator.CreateCheckoutSession(ctx, req.ProviderName, &paymentprovider.CheckoutSessionRequest{
+		PriceID:         priceID,
+		Quantity:        quantity,
+		UserID:          req.UserID,
+		GroupID:         req.GroupID,
+		CustomerEmail:   customerEmail,
+		SuccessURL:      req.SuccessURL,
+		CancelURL:       req.CancelURL,
+		TrialPeriodDays: cost.TrialPeriodDays,
+		CouponCode:      couponCode,
+		PromotionCode:   promotionCode,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-checkout-session", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("checkout-session-created", zap.String("checkout-session-id", session.ID), zap.String("cost-id", cost.ID), zap.String("group-id", req.GroupID))
+
+	return &CreateCheckoutSessionResponse{CheckoutSession: session}, nil
+}
+
+// CreateCustomerPortalSession creates a customer portal session for the signed-in user using the
+// provider customer recorded against their most recent subscription
+func (s *Service) CreateCustomerPortalSession(ctx context.Context, req *CreateCustomerPortalSessionRequest) (*CreateCustomerPortalSessionResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "create-customer-portal-session"),
+		zap.String("user-id", req.UserID),
+		zap.String("provider", req.ProviderName),
+	)
+
+	if req.UserID == "" {
+		logger.Warn("failed-to-create-customer-portal-session-user-id-is-missing")
+		return nil, ErrBillingManagerRequiresUserIdIsMissing
+	}
+
+	creator, ok := s.ProviderRegistry.(checkoutSessionCreator)
+	if !ok {
+		logger.Error("provider-registry-does-not-support-customer-portal-sessions")
+		return nil, ErrBillingManagerCheckoutNotSupported
+	}
+
+	subscriptionsResp, err := s.BillingService.GetSubscriptions(ctx, &billing.GetSubscriptionsRequest{
+		ForUserIDs:     []string{req.UserID},
+		IntegratorName: req.ProviderName,
+		PerPage:        1,
+		Page:           1,
+		Order:          "created_at_desc",
+	})
+	if err != nil {
+		logger.Error("failed-to-get-subscriptions-for-customer-portal-session", zap.Error(err))
+		return nil, err
+	}
+
+	if len(subscriptionsResp.Subscriptions) == 0 || subscriptionsResp.Subscriptions[0].IntegratorCustomerID == "" {
+		logger.Warn("no-provider-customer-found-for-customer-portal-session")
+		return nil, ErrBillingManagerNoProviderCustomerForUser
+	}
+
+	subscription := subscriptionsResp.Subscriptions[0]
+
+	session, err := creator.CreateCustomerPortalSession(ctx, subscription.Integrator, &paymentprovider.CustomerPortalSessionRequest{
+		CustomerID: subscription.IntegratorCustomerID,
+		ReturnURL:  req.ReturnURL,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-customer-portal-session", zap.String("subscription-id", subscription.ID), zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("customer-portal-session-created", zap.String("subscription-id", subscription.ID))
+
+	return &CreateCustomerPortalSessionResponse{PortalSession: session}, nil
+}
+
+// getCheckoutPromotionCodeCoupon checks the promotion code can be redeemed by the user for the
+// plan cost and returns the provider coupon it is mapped to
+func (s *Service) getCheckoutPromotionCodeCoupon(ctx context.Context, req *CreateCheckoutSessionRequest, promotionCode string, pricePlanID string, cadence pricer.PriceBillingCadence) (string, error) {
+
+	if req.CouponCode != "" {
+		return "", ErrInvalidBillingManagerRequestPayload
+	}
+
+	redeemer, ok := s.PricerService.(promotionCodeRedeemer)
+	if !ok {
+		return "", ErrBillingManagerPromotionCodesNotSupported
+	}
+
+	validateResp, err := redeemer.ValidatePromotionCode(ctx, &pricer.ValidatePromotionCodeRequest{
+		Code:           promotionCode,
+		UserID:         req.UserID,
+		PricePlanID:    pricePlanID,
+		BillingCadence: cadence,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !validateResp.Valid || validateResp.PromotionCode == nil {
+		return "", pricer.ErrPricePromotionCodeNotRedeemable
+	}
+
+	couponCode := validateResp.PromotionCode.GetProviderID(req.ProviderName)
+	if couponCode == "" {
+		return "", ErrBillingManagerPromotionCodeNotMappedToProvider
+	}
+
+	return couponCode, nil
+}
+
+// redeemSubscriptionPromotionCode records the promotion code used at checkout against the
+// subscription it bought. Redeeming is idempotent, so repeated webhooks for the subscription are safe
+func (s *Service) redeemSubscriptionPromotionCode(ctx context.Context, subscription *billing.Subscription, promotionCode string) error {
+
+	if subscription == nil || s.PricerService == nil {
+		return nil
+	}
+
+	redeemer, ok := s.PricerService.(promotionCodeRedeemer)
+	if !ok {
+		return ErrBillingManagerPromotionCodesNotSupported
+	}
+
+	var cadence pricer.PriceBillingCadence
+	if pricer.IsValidPriceBillingCadence(subscription.BillingInterval) {
+		cadence = pricer.PriceBillingCadence(subscription.BillingInterval)
+	}
+
+	_, err := redeemer.RedeemPromotionCode(ctx, &pricer.RedeemPromotionCodeRequest{
+		Code:           promotionCode,
+		UserID:         subscription.UserID,
+		SubscriptionID: subscription.ID,
+		PricePlanID:    subscription.PricePlanID,
+		BillingCadence: cadence,
+	})
+
+	return err
+}
+
+// selectCheckoutPrice finds the first plan cost matching the optional cost ID and cadence
+// that is linked to a price on the provider, returning the cost and provider price ID
+func selectCheckoutPrice(plan *pricer.PricePlan, providerName, costID, billingCadence string) (*pricer.PriceCost, string) {
+	for i := range plan.Costs {
+		cost := &plan.Costs[i]
+
+		if costID != "" && cost.ID != costID {
+			continue
+		}
+
+		if billingCadence != "" && string(cost.BillingCadence) != billingCadence {
+			continue
+		}
+
+		for _, ref := range cost.ProviderRefs {
+			if string(ref.Provider) != providerName {
+				continue
+			}
+
+			if ref.ProviderPriceID != "" {
+				return cost, ref.ProviderPriceID
+			}
+
+			if ref.ProviderID != "" {
+				return cost, ref.ProviderID
+			}
+		}
+	}
+
+	return nil, ""
+}
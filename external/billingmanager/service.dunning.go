@@ -0,0 +1,420 @@
+package billingmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/emailmanager"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/notifier"
+	"go.uber.org/zap"
+)
+
+// dunningSubscriptionStatuses lists the statuses of subscriptions whose payment has failed
+var dunningSubscriptionStatuses = []string{
+	billing.StatusPastDue,
+	billing.StatusUnpaid,
+}
+
+// dunningProgress describes what advancing a subscription through dunning did
+type dunningProgress struct {
+	Started      bool
+	ReminderSent bool
+	Suspended    bool
+	Recovered    bool
+}
+
+// ProcessDunning progresses past due and unpaid subscriptions through the dunning schedule,
+// sending the reminders that are due and suspending subscriptions whose grace period has
+// passed. Suspended subscriptions lose their entitlements and group seats until the payment
+// is recovered, which is picked up from the provider's webhook or by reconciliation.
+func (s *Service) ProcessDunning(ctx context.Context, req *ProcessDunningRequest) (*DunningReportResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "process-dunning"))
+
+	if s.DunningConfig == nil {
+		logger.Error("dunning-not-configured")
+		return nil, ErrBillingManagerDunningNotConfigured
+	}
+
+	if !s.dunningMu.TryLock() {
+		logger.Warn("dunning-already-in-progress")
+		return nil, ErrBillingManagerDunningInProgress
+	}
+	defer s.dunningMu.Unlock()
+
+	report := &DunningReport{
+		StartedAt: time.Now().UTC(),
+	}
+
+	candidates, err := s.getDunningCandidates(ctx, normaliseDunningLimit(req.Limit))
+	if err != nil {
+		logger.Error("failed-to-get-subscriptions-in-dunning", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range candidates {
+		if ctx.Err() != nil {
+			logger.Warn("dunning-interrupted", zap.Error(ctx.Err()))
+			break
+		}
+
+		subscription := &candidates[i]
+
+		progress, err := s.advanceSubscriptionDunning(ctx, subscription, time.Now().UTC())
+		if err != nil {
+			report.Failed++
+			report.Failures = append(report.Failures, DunningFailure{
+				SubscriptionID: subscription.ID,
+				UserID:         subscription.UserID,
+				Error:          err.Error(),
+			})
+			continue
+		}
+
+		report.Checked++
+
+		if progress.Started {
+			report.Started++
+		}
+		if progress.ReminderSent {
+			report.RemindersSent++
+		}
+		if progress.Suspended {
+			report.Suspended++
+			s.refreshSubscriptionAccess(ctx, subscription)
+		}
+	}
+
+	report.CompletedAt = time.Now().UTC()
+
+	logger.Info("dunning-completed",
+		zap.Int("checked", report.Checked),
+		zap.Int("started", report.Started),
+		zap.Int("reminders-sent", report.RemindersSent),
+		zap.Int("suspended", report.Suspended),
+		zap.Int("failed", report.Failed),
+	)
+
+	return &DunningReportResponse{Report: report}, nil
+}
+
+// RunDunning progresses subscriptions through dunning straight away and then on every
+// interval until the context is cancelled. It blocks, so it is usually started in its own
+// goroutine. Runs that fail are logged and retried on the next interval.
+func (s *Service) RunDunning(ctx context.Context, req *RunDunningRequest) error {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "run-dunning"))
+
+	interval := req.Interval
+	if interval <= 0 {
+		interval = defaultDunningInterval
+	}
+
+	logger.Info("starting-scheduled-dunning", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.ProcessDunning(ctx, &ProcessDunningRequest{Limit: req.Limit})
+		if errors.Is(err, ErrBillingManagerDunningNotConfigured) {
+			return err
+		}
+		if err != nil {
+			logger.Error("scheduled-dunning-failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("stopping-scheduled-dunning")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// getDunningCandidates returns up to limit past due or unpaid subscriptions that have not
+// been suspended, oldest first
+func (s *Service) getDunningCandidates(ctx context.Context, limit int) ([]billing.Subscription, error) {
+
+	var (
+		candidates []billing.Subscription
+		seen       = map[string]bool{}
+	)
+
+	for page := 1; len(candidates) < limit; page++ {
+		response, err := s.BillingService.GetSubscriptions(ctx, &billing.GetSubscriptionsRequest{
+			Statuses: dunningSubscriptionStatuses,
+			Order:    "created_at_asc",
+			PerPage:  reconciliationPageSize,
+			Page:     page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subscription := range response.Subscriptions {
+			if seen[subscription.ID] || (subscription.Dunning != nil && subscription.Dunning.SuspendedAt != nil) {
+				continue
+			}
+			seen[subscription.ID] = true
+
+			candidates = append(candidates, subscription)
+		}
+
+		if len(response.Subscriptions) == 0 || page >= response.TotalPages {
+			break
+		}
+	}
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return candidates, nil
+}
+
+// advanceSubscriptionDunning moves a subscription to its next dunning state. A subscription
+// whose payment has failed enters dunning, is sent the latest reminder that is due and is
+// suspended once its grace period has passed. A subscription in dunning that is no longer
+// failing leaves it. Reminders skipped while no run took place are not sent late.
+func (s *Service) advanceSubscriptionDunning(ctx context.Context, subscription *billing.Subscription, now time.Time) (*dunningProgress, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "advance-subscription-dunning"),
+		zap.String("subscription-id", subscription.ID),
+		zap.String("status", subscription.Status),
+	)
+
+	progress := &dunningProgress{}
+
+	if !subscription.IsPaymentFailing() {
+		if subscription.Dunning == nil {
+			return progress, nil
+		}
+
+		recovered := *subscription.Dunning
+
+		if _, err := s.BillingService.UpdateSubscription(ctx, &billing.UpdateSubscriptionRequest{ID: subscription.ID, ClearDunning: true}); err != nil {
+			logger.Error("failed-to-clear-subscription-dunning", zap.Error(err))
+			return nil, err
+		}
+
+		s.logDunningAuditEvent(ctx, AuditActionBillingDunningRecovered, subscription, &recovered, &DunningAuditEvent{})
+		subscription.Dunning = nil
+		progress.Recovered = true
+
+		logger.Info("subscription-recovered-from-dunning")
+		return progress, nil
+	}
+
+	if subscription.Dunning != nil && subscription.Dunning.SuspendedAt != nil {
+		return progress, nil
+	}
+
+	var dunning billing.SubscriptionDunning
+	if subscription.Dunning != nil {
+		dunning = *subscription.Dunning
+	} else {
+		dunning = billing.SubscriptionDunning{
+			StartedAt:         now,
+			GracePeriodEndsAt: now.Add(s.DunningConfig.GracePeriod),
+		}
+		progress.Started = true
+	}
+
+	var reminder *DunningAuditEvent
+	if dueSteps := s.DunningConfig.dueSteps(now.Sub(dunning.StartedAt)); dueSteps > dunning.StepsCompleted && now.Before(dunning.GracePeriodEndsAt) {
+		step := s.DunningConfig.Steps[dueSteps-1]
+		reminder = &DunningAuditEvent{Step: dueSteps}
+		reminder.EmailSent, reminder.NotificationSent = s.sendDunningMessage(ctx, subscription, step.Email, step.InApp,
+			fmt.Sprintf(DunningReminderEmailSubjectTmpl, subscription.PlanName),
+			DunningReminderNotificationTitle,
+			fmt.Sprintf(DunningReminderMessageTmpl, subscription.PlanName, dunning.GracePeriodEndsAt.Format("02 January, 2006")),
+		)
+
+		dunning.StepsCompleted = dueSteps
+		dunning.LastStepAt = &now
+		progress.ReminderSent = true
+	}
+
+	var suspension *DunningAuditEvent
+	if !now.Before(dunning.GracePeriodEndsAt) {
+		suspension = &DunningAuditEvent{}
+		suspension.EmailSent, suspension.NotificationSent = s.sendDunningMessage(ctx, subscription, true, true,
+			fmt.Sprintf(DunningSuspendedEmailSubjectTmpl, subscription.PlanName),
+			DunningSuspendedNotificationTitle,
+			fmt.Sprintf(DunningSuspendedMessageTmpl, subscription.PlanName),
+		)
+
+		dunning.SuspendedAt = &now
+		progress.Suspended = true
+	}
+
+	if !progress.Started && !progress.ReminderSent && !progress.Suspended {
+		return progress, nil
+	}
+
+	if _, err := s.BillingService.UpdateSubscription(ctx, &billing.UpdateSubscriptionRequest{ID: subscription.ID, Dunning: &dunning}); err != nil {
+		logger.Error("failed-to-update-subscription-dunning", zap.Error(err))
+		return nil, err
+	}
+	subscription.Dunning = &dunning
+
+	if progress.Started {
+		s.logDunningAuditEvent(ctx, AuditActionBillingDunningStarted, subscription, &dunning, &DunningAuditEvent{})
+	}
+	if reminder != nil {
+		s.logDunningAuditEvent(ctx, AuditActionBillingDunningReminderSent, subscription, &dunning, reminder)
+	}
+	if suspension != nil {
+		s.logDunningAuditEvent(ctx, AuditActionBillingDunningSuspended, subscription, &dunning, suspension)
+	}
+
+	logger.Info("subscription-dunning-advanced",
+		zap.Bool("started", progress.Started),
+		zap.Int("steps-completed", dunning.StepsCompleted),
+		zap.Bool("suspended", progress.Suspended),
+	)
+
+	return progress, nil
+}
+
+// sendDunningMessage emails and notifies the subscription's owner, linking to the provider's
+// update payment page when one is known. Failures are logged rather than returned so one
+// unreachable channel does not hold up dunning, and it reports which channels were sent.
+func (s *Service) sendDunningMessage(ctx context.Context, subscription *billing.Subscription, withEmail bool, withInApp bool, subject string, title string, message string) (emailSent bool, notificationSent bool) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("subscription-id", subscription.ID))
+
+	if withEmail && s.EmailManager != nil && subscription.Email != "" {
+		updatePaymentLink := ""
+		if subscription.UpdateURL != "" {
+			updatePaymentLink = fmt.Sprintf(DunningEmailUpdatePaymentLinkTmpl, subscription.UpdateURL)
+		}
+
+		err := s.EmailManager.SendCustomEmail(ctx, &emailmanager.SendCustomEmailRequest{
+			EmailSubject:  subject,
+			EmailPreview:  message,
+			EmailBody:     fmt.Sprintf(DunningEmailBodyTmpl, message, updatePaymentLink),
+			EmailTo:       subscription.Email,
+			WithFooter:    true,
+			UserId:        subscription.UserID,
+			RecipientType: string(audit.User),
+		})
+		if err != nil {
+			logger.Warn("failed-to-send-dunning-email", zap.Error(err))
+		} else {
+			emailSent = true
+		}
+	}
+
+	if withInApp && s.NotifierService != nil && subscription.UserID != "" {
+		data := map[string]interface{}{
+			"subscription_id": subscription.ID,
+		}
+		if subscription.UpdateURL != "" {
+			data["update_url"] = subscription.UpdateURL
+		}
+
+		_, err := s.NotifierService.NotifyUser(ctx, &notifier.NotifyUserRequest{
+			UserID:  subscription.UserID,
+			Title:   title,
+			Message: message,
+			Data:    data,
+		})
+		if err != nil {
+			logger.Warn("failed-to-send-dunning-notification", zap.Error(err))
+		} else {
+			notificationSent = true
+		}
+	}
+
+	return emailSent, notificationSent
+}
+
+// refreshSubscriptionAccess drops the cached entitlements and resyncs the group seats of a
+// subscription whose access changed outside of a webhook or repair
+func (s *Service) refreshSubscriptionAccess(ctx context.Context, subscription *billing.Subscription) {
+
+	s.invalidateSubscriptionEntitlements(ctx, subscription)
+
+	if subscription.IsGroupOwned() && s.GroupService != nil {
+		if err := s.syncGroupSeats(ctx, subscription.GroupID); err != nil {
+			logger.AcquirePackageFrom(ctx, "external/billingmanager").Warn("failed-to-sync-group-seats-after-dunning", zap.String("subscription-id", subscription.ID), zap.String("group-id", subscription.GroupID), zap.Error(err))
+		}
+	}
+}
+
+// invalidateSubscriptionEntitlements drops the cached entitlements of the subscription's
+// buyer and, for group-owned subscriptions, of the group it was bought for
+func (s *Service) invalidateSubscriptionEntitlements(ctx context.Context, subscription *billing.Subscription) {
+	if s.EntitlementService == nil {
+		return
+	}
+
+	if subscription.UserID != "" {
+		s.EntitlementService.InvalidateEntitlements(ctx, &entitlement.InvalidateEntitlementsRequest{SubjectID: subscription.UserID})
+	}
+
+	if subscription.IsGroupOwned() {
+		s.EntitlementService.InvalidateEntitlements(ctx, &entitlement.InvalidateEntitlementsRequest{SubjectType: entitlement.SubjectTypeGroup, SubjectID: subscription.GroupID})
+	}
+}
+
+// logDunningAuditEvent records a dunning step taken for a subscription
+func (s *Service) logDunningAuditEvent(ctx context.Context, action audit.AuditAction, subscription *billing.Subscription, dunning *billing.SubscriptionDunning, event *DunningAuditEvent) {
+
+	if s.AuditService == nil {
+		return
+	}
+
+	event.SubscriptionID = subscription.ID
+	event.UserID = subscription.UserID
+	event.GroupID = subscription.GroupID
+	event.Status = subscription.Status
+	event.GracePeriodEndsAt = dunning.GracePeriodEndsAt
+	event.UpdateURL = subscription.UpdateURL
+
+	_ = s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+		ActorId:    audit.AuditActorIdSystem,
+		Action:     action,
+		TargetId:   subscription.ID,
+		TargetType: TargetTypeSubscription,
+		Domain:     "billingmanager",
+		Details:    event,
+	})
+}
+
+// dueSteps returns how many of the schedule's steps are due once a subscription has been in
+// dunning for the elapsed duration
+func (c *DunningConfig) dueSteps(elapsed time.Duration) int {
+	due := 0
+	for _, step := range c.Steps {
+		if step.After > elapsed {
+			break
+		}
+		due++
+	}
+
+	return due
+}
+
+// normaliseDunningLimit applies the default and maximum number of subscriptions per run
+func normaliseDunningLimit(limit int) int {
+	if limit <= 0 {
+		return defaultDunningLimit
+	}
+
+	if limit > maxDunningLimit {
+		return maxDunningLimit
+	}
+
+	return limit
+}
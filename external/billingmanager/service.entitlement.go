@@ -0,0 +1,135 @@
+package billingmanager
+
+import (
+	"context"
+
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// GetMyEntitlements resolves the signed-in user's entitlements from their subscriptions,
+// trials and admin grants
+func (s *Service) GetMyEntitlements(ctx context.Context, req *GetMyEntitlementsRequest) (*GetEntitlementsResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "get-my-entitlements"),
+		zap.String("user-id", req.UserID),
+	)
+
+	if s.EntitlementService == nil {
+		logger.Error("entitlement-service-not-enabled")
+		return nil, ErrBillingManagerEntitlementServiceNotSet
+	}
+
+	if req.UserID == "" {
+		logger.Warn("failed-to-get-entitlements-user-id-is-missing")
+		return nil, ErrBillingManagerRequiresUserIdIsMissing
+	}
+
+	entitlementsResp, err := s.EntitlementService.GetEntitlements(ctx, &entitlement.GetEntitlementsRequest{
+		SubjectType: entitlement.SubjectTypeUser,
+		SubjectID:   req.UserID,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-entitlements", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetEntitlementsResponse{EntitlementSet: entitlementsResp.EntitlementSet}, nil
+}
+
+// GetSubjectEntitlements resolves the entitlements of any user or group for admins. The
+// entitlements are always resolved again so admins never see a stale cache entry
+func (s *Service) GetSubjectEntitlements(ctx context.Context, req *GetSubjectEntitlementsRequest) (*GetEntitlementsResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "get-subject-entitlements"),
+		zap.String("subject-type", req.SubjectType),
+		zap.String("subject-id", req.SubjectID),
+	)
+
+	if s.EntitlementService == nil {
+		logger.Error("entitlement-service-not-enabled")
+		return nil, ErrBillingManagerEntitlementServiceNotSet
+	}
+
+	entitlementsResp, err := s.EntitlementService.GetEntitlements(ctx, &entitlement.GetEntitlementsRequest{
+		SubjectType: entitlement.SubjectType(req.SubjectType),
+		SubjectID:   req.SubjectID,
+		SkipCache:   true,
+	})
+	if err != nil {
+		logger.Warn("failed-to-get-entitlements", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetEntitlementsResponse{EntitlementSet: entitlementsResp.EntitlementSet}, nil
+}
+
+// GetEntitlementGrants lists admin entitlement grants
+func (s *Service) GetEntitlementGrants(ctx context.Context, req *GetEntitlementGrantsRequest) (*GetEntitlementGrantsResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "get-entitlement-grants"))
+
+	if s.EntitlementService == nil {
+		logger.Error("entitlement-service-not-enabled")
+		return nil, ErrBillingManagerEntitlementServiceNotSet
+	}
+
+	if req.GetGrantsRequest == nil {
+		req.GetGrantsRequest = &entitlement.GetGrantsRequest{}
+	}
+
+	grantsResp, err := s.EntitlementService.GetGrants(ctx, req.GetGrantsRequest)
+	if err != nil {
+		logger.Warn("failed-to-get-entitlement-grants", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetEntitlementGrantsResponse{GetGrantsResponse: grantsResp}, nil
+}
+
+// CreateEntitlementGrant issues an admin entitlement grant to a user or group
+func (s *Service) CreateEntitlementGrant(ctx context.Context, req *CreateEntitlementGrantRequest) (*EntitlementGrantResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "create-entitlement-grant"))
+
+	if s.EntitlementService == nil {
+		logger.Error("entitlement-service-not-enabled")
+		return nil, ErrBillingManagerEntitlementServiceNotSet
+	}
+
+	grantResp, err := s.EntitlementService.CreateGrant(ctx, req.CreateGrantRequest)
+	if err != nil {
+		logger.Warn("failed-to-create-entitlement-grant", zap.Error(err))
+		return nil, err
+	}
+
+	return &EntitlementGrantResponse{Grant: grantResp.Grant}, nil
+}
+
+// RevokeEntitlementGrant revokes an admin entitlement grant
+func (s *Service) RevokeEntitlementGrant(ctx context.Context, req *RevokeEntitlementGrantRequest) (*EntitlementGrantResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "revoke-entitlement-grant"),
+		zap.String("grant-id", req.GrantID),
+	)
+
+	if s.EntitlementService == nil {
+		logger.Error("entitlement-service-not-enabled")
+		return nil, ErrBillingManagerEntitlementServiceNotSet
+	}
+
+	grantResp, err := s.EntitlementService.RevokeGrant(ctx, &entitlement.RevokeGrantRequest{
+		ID:              req.GrantID,
+		RevokedByUserID: req.RequestingUserID,
+	})
+	if err != nil {
+		logger.Warn("failed-to-revoke-entitlement-grant", zap.Error(err))
+		return nil, err
+	}
+
+	return &EntitlementGrantResponse{Grant: grantResp.Grant}, nil
+}
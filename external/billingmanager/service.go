@@ -6,11 +6,18 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ooaklee/ghatd/external/audit"
 	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/emailmanager"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/invoice"
 	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/metering"
+	"github.com/ooaklee/ghatd/external/notifier"
 	"github.com/ooaklee/ghatd/external/paymentprovider"
 	"github.com/ooaklee/ghatd/external/pricer"
 	"github.com/ooaklee/ghatd/external/user/v2"
@@ -39,11 +46,81 @@ type userByEmailFinder interface {
 	FindUserByEmail(ctx context.Context, req *user.GetUserByEmailRequest) (*user.GetUserByEmailResponse, error)
 }
 
+// checkoutSessionCreator is an optional capability implemented by the payment provider
+// registry for creating hosted checkout and customer portal sessions.
+type checkoutSessionCreator interface {
+	CreateCheckoutSession(ctx context.Context, providerName string, req *paymentprovider.CheckoutSessionRequest) (*paymentprovider.CheckoutSession, error)
+	CreateCustomerPortalSession(ctx context.Context, providerName string, req *paymentprovider.CustomerPortalSessionRequest) (*paymentprovider.CustomerPortalSession, error)
+}
+
+// subscriptionInfoGetter is an optional capability implemented by the payment provider
+// registry for looking up the provider's current view of a subscription.
+type subscriptionInfoGetter interface {
+	GetSubscriptionInfo(ctx context.Context, providerName string, subscriptionID string) (*paymentprovider.SubscriptionInfo, error)
+}
+
+// pricePlanVersioner is an optional capability implemented by the pricer service for
+// linking subscriptions to the immutable price plan version they were bought on and
+// migrating them between versions.
+type pricePlanVersioner interface {
+	FindPricePlanVersionByProviderPrice(ctx context.Context, req *pricer.FindPricePlanVersionByProviderPriceRequest) (*pricer.GetPricePlanVersionResponse, error)
+	GetPricePlanVersionByID(ctx context.Context, req *pricer.GetPricePlanVersionByIDRequest) (*pricer.GetPricePlanVersionResponse, error)
+	GetDuePricePlanVersionMigrations(ctx context.Context, req *pricer.GetDuePricePlanVersionMigrationsRequest) (*pricer.GetDuePricePlanVersionMigrationsResponse, error)
+	CompletePricePlanVersionMigration(ctx context.Context, req *pricer.CompletePricePlanVersionMigrationRequest) (*pricer.GetPricePlanVersionResponse, error)
+}
+
+// priceQuoter is an optional capability implemented by the pricer service for quoting
+// what a customer pays for a plan.
+type priceQuoter interface {
+	QuotePricePlan(ctx context.Context, req *pricer.QuotePricePlanRequest) (*pricer.QuotePricePlanResponse, error)
+}
+
+// promotionCodeRedeemer is an optional capability implemented by the pricer service for
+// checking promotion codes at checkout and redeeming them once the subscription exists.
+type promotionCodeRedeemer interface {
+	ValidatePromotionCode(ctx context.Context, req *pricer.ValidatePromotionCodeRequest) (*pricer.ValidatePromotionCodeResponse, error)
+	RedeemPromotionCode(ctx context.Context, req *pricer.RedeemPromotionCodeRequest) (*pricer.RedeemPromotionCodeResponse, error)
+}
+
+// catalogSyncer is an optional capability implemented by the payment provider registry for
+// reading and, where the provider allows it, changing provider products and prices.
+type catalogSyncer interface {
+	SupportsCatalog(providerName string) bool
+	SupportsCatalogWrites(providerName string) bool
+	GetCatalogProduct(ctx context.Context, providerName string, productID string) (*paymentprovider.CatalogProduct, error)
+	GetCatalogPrice(ctx context.Context, providerName string, priceID string) (*paymentprovider.CatalogPrice, error)
+	SaveCatalogProduct(ctx context.Context, providerName string, product *paymentprovider.CatalogProduct) (*paymentprovider.CatalogProduct, error)
+	CreateCatalogPrice(ctx context.Context, providerName string, price *paymentprovider.CatalogPrice) (*paymentprovider.CatalogPrice, error)
+	ArchiveCatalogPrice(ctx context.Context, providerName string, priceID string) error
+}
+
+// subscriptionManager is an optional capability implemented by the payment provider registry
+// for cancelling and pausing subscriptions on the provider.
+type subscriptionManager interface {
+	CancelSubscription(ctx context.Context, providerName string, req *paymentprovider.SubscriptionChangeRequest) (*paymentprovider.SubscriptionChange, error)
+	PauseSubscription(ctx context.Context, providerName string, req *paymentprovider.SubscriptionChangeRequest) (*paymentprovider.SubscriptionChange, error)
+}
+
+// paymentRefunder is an optional capability implemented by the payment provider registry
+// for refunding payments.
+type paymentRefunder interface {
+	RefundPayment(ctx context.Context, providerName string, req *paymentprovider.RefundRequest) (*paymentprovider.Refund, error)
+}
+
+// pricePlanUpdater is an optional capability implemented by the pricer service for writing
+// provider references back to price plans.
+type pricePlanUpdater interface {
+	UpdatePricePlan(ctx context.Context, req *pricer.UpdatePricePlanRequest) (*pricer.UpdatePricePlanResponse, error)
+}
+
 // BillingService interface for valid billing service
 type BillingService interface {
 	GetSubscriptions(ctx context.Context, req *billing.GetSubscriptionsRequest) (*billing.GetSubscriptionsResponse, error)
 	GetBillingEvents(ctx context.Context, req *billing.GetBillingEventsRequest) (*billing.GetBillingEventsResponse, error)
 	GetSubscriptionByIntegratorID(ctx context.Context, req *billing.GetSubscriptionByIntegratorIDRequest) (*billing.GetSubscriptionByIntegratorIDResponse, error)
+	GetSubscriptionByID(ctx context.Context, req *billing.GetSubscriptionByIDRequest) (*billing.GetSubscriptionByIDResponse, error)
+	GetBillingEventByID(ctx context.Context, req *billing.GetBillingEventByIDRequest) (*billing.GetBillingEventByIDResponse, error)
+	GetRevenueMetrics(ctx context.Context, req *billing.GetRevenueMetricsRequest) (*billing.GetRevenueMetricsResponse, error)
 	CreateSubscription(ctx context.Context, req *billing.CreateSubscriptionRequest) (*billing.CreateSubscriptionResponse, error)
 	UpdateSubscription(ctx context.Context, req *billing.UpdateSubscriptionRequest) (*billing.UpdateSubscriptionResponse, error)
 	CreateBillingEvent(ctx context.Context, req *billing.CreateBillingEventRequest) (*billing.CreateBillingEventResponse, error)
@@ -58,6 +135,57 @@ type PricerService interface {
 	GetFeatures(ctx context.Context, req *pricer.GetFeaturesRequest) (*pricer.GetFeaturesResponse, error)
 }
 
+// WebhookInboxService defines the webhook inbox operations used to deduplicate
+// and replay payment provider webhooks (optional)
+type WebhookInboxService interface {
+	RecordWebhookDelivery(ctx context.Context, req *billing.RecordWebhookDeliveryRequest) (*billing.RecordWebhookDeliveryResponse, error)
+	ClaimWebhookDelivery(ctx context.Context, req *billing.ClaimWebhookDeliveryRequest) (*billing.ClaimWebhookDeliveryResponse, error)
+	UpdateWebhookDeliveryOutcome(ctx context.Context, req *billing.UpdateWebhookDeliveryOutcomeRequest) (*billing.UpdateWebhookDeliveryOutcomeResponse, error)
+	GetWebhookDeliveryByID(ctx context.Context, req *billing.GetWebhookDeliveryByIDRequest) (*billing.GetWebhookDeliveryByIDResponse, error)
+	GetWebhookDeliveries(ctx context.Context, req *billing.GetWebhookDeliveriesRequest) (*billing.GetWebhookDeliveriesResponse, error)
+}
+
+// EntitlementService defines the entitlement operations exposed through billing manager (optional)
+type EntitlementService interface {
+	GetEntitlements(ctx context.Context, req *entitlement.GetEntitlementsRequest) (*entitlement.GetEntitlementsResponse, error)
+	InvalidateEntitlements(ctx context.Context, req *entitlement.InvalidateEntitlementsRequest)
+	CreateGrant(ctx context.Context, req *entitlement.CreateGrantRequest) (*entitlement.CreateGrantResponse, error)
+	GetGrants(ctx context.Context, req *entitlement.GetGrantsRequest) (*entitlement.GetGrantsResponse, error)
+	RevokeGrant(ctx context.Context, req *entitlement.RevokeGrantRequest) (*entitlement.RevokeGrantResponse, error)
+}
+
+// MeteringService defines the usage metering operations exposed through billing manager (optional)
+type MeteringService interface {
+	RecordUsage(ctx context.Context, req *metering.RecordUsageRequest) (*metering.RecordUsageResponse, error)
+	GetUsage(ctx context.Context, req *metering.GetUsageRequest) (*metering.GetUsageResponse, error)
+	GetUsageEvents(ctx context.Context, req *metering.GetUsageEventsRequest) (*metering.GetUsageEventsResponse, error)
+}
+
+// GroupService defines the group operations used to manage group-owned subscriptions (optional)
+type GroupService interface {
+	GetGroupByID(ctx context.Context, req *group.GetGroupByIDRequest) (*group.GetGroupByIDResponse, error)
+	UpdateGroup(ctx context.Context, req *group.UpdateGroupRequest) (*group.UpdateGroupResponse, error)
+}
+
+// InvoiceService defines the invoice operations used to issue, list, render and email invoices (optional)
+type InvoiceService interface {
+	CreateInvoiceFromBillingEvent(ctx context.Context, req *invoice.CreateInvoiceFromBillingEventRequest) (*invoice.CreateInvoiceFromBillingEventResponse, error)
+	GetInvoices(ctx context.Context, req *invoice.GetInvoicesRequest) (*invoice.GetInvoicesResponse, error)
+	GetInvoiceByID(ctx context.Context, req *invoice.GetInvoiceByIDRequest) (*invoice.GetInvoiceByIDResponse, error)
+	RenderInvoice(ctx context.Context, req *invoice.RenderInvoiceRequest) (*invoice.RenderInvoiceResponse, error)
+	MarkInvoiceEmailed(ctx context.Context, req *invoice.MarkInvoiceEmailedRequest) (*invoice.MarkInvoiceEmailedResponse, error)
+}
+
+// EmailManager defines the email operations used to send dunning reminders and invoices (optional)
+type EmailManager interface {
+	SendCustomEmail(ctx context.Context, req *emailmanager.SendCustomEmailRequest) error
+}
+
+// NotifierService defines the notification operations used to send dunning reminders (optional)
+type NotifierService interface {
+	NotifyUser(ctx context.Context, req *notifier.NotifyUserRequest) (*notifier.NotifyUserResponse, error)
+}
+
 // Service orchestrates webhook processing and billing operations
 // It uses paymentprovider for webhook verification and billingstore for persistence
 type Service struct {
@@ -66,6 +194,52 @@ type Service struct {
 	AuditService     AuditService // Optional audit logging
 	UserService      UserService  // Optional user service integration
 	PricerService    PricerService
+
+	// WebhookInboxService is optional; when set every verified webhook is
+	// recorded before processing so duplicates are skipped and failures can be replayed
+	WebhookInboxService WebhookInboxService
+
+	// EntitlementService is optional; when set it serves entitlement endpoints and
+	// has its cache invalidated whenever a webhook changes a user's subscription
+	EntitlementService EntitlementService
+
+	// MeteringService is optional; when set it serves usage and quota endpoints
+	MeteringService MeteringService
+
+	// GroupService is optional; when set users can buy plans for groups and the seats
+	// purchased by group-owned subscriptions are applied to the group's member limit
+	GroupService GroupService
+
+	// DunningConfig is optional; when set subscriptions with a failed payment are reminded,
+	// kept entitled for a grace period and then suspended
+	DunningConfig *DunningConfig
+
+	// InvoiceService is optional; when set every successful payment is invoiced and users
+	// can list and download their invoices
+	InvoiceService InvoiceService
+
+	// EmailInvoices is whether newly issued invoices are emailed to the customer, it
+	// requires the EmailManager
+	EmailInvoices bool
+
+	// EmailManager is optional; when set dunning reminders are emailed, as are invoices
+	// when EmailInvoices is enabled
+	EmailManager EmailManager
+
+	// NotifierService is optional; when set dunning reminders are sent as in-app notifications
+	NotifierService NotifierService
+
+	// reconciliationMu ensures only one reconciliation repairs subscriptions at a time
+	reconciliationMu sync.Mutex
+
+	// dunningMu ensures only one dunning run progresses subscriptions at a time
+	dunningMu sync.Mutex
+
+	// planVersionMigrationMu ensures only one run migrates price plan version subscribers at a time
+	planVersionMigrationMu sync.Mutex
+
+	// catalogSyncMu ensures only one catalog sync changes provider products and prices at a time
+	catalogSyncMu sync.Mutex
 }
 
 // NewService creates a new billing manager service
@@ -94,14 +268,68 @@ func (s *Service) WithPricerService(pricerSvc PricerService) *Service {
 	return s
 }
 
+// WithWebhookInboxService adds the webhook inbox used for idempotent processing and replays
+func (s *Service) WithWebhookInboxService(inbox WebhookInboxService) *Service {
+	s.WebhookInboxService = inbox
+	return s
+}
+
+// WithEntitlementService adds entitlement resolution and admin grants
+func (s *Service) WithEntitlementService(entitlementSvc EntitlementService) *Service {
+	s.EntitlementService = entitlementSvc
+	return s
+}
+
+// WithMeteringService adds usage metering and quota reporting
+func (s *Service) WithMeteringService(meteringSvc MeteringService) *Service {
+	s.MeteringService = meteringSvc
+	return s
+}
+
+// WithGroupService adds group-owned subscriptions and seat management
+func (s *Service) WithGroupService(groupSvc GroupService) *Service {
+	s.GroupService = groupSvc
+	return s
+}
+
+// WithDunning adds dunning of past due and unpaid subscriptions using the given schedule
+func (s *Service) WithDunning(config DunningConfig) *Service {
+	s.DunningConfig = &config
+	return s
+}
+
+// WithInvoiceService adds invoices for successful payments
+func (s *Service) WithInvoiceService(invoiceSvc InvoiceService) *Service {
+	s.InvoiceService = invoiceSvc
+	return s
+}
+
+// WithInvoiceEmails emails invoices to customers as soon as they are issued, it requires
+// an email manager
+func (s *Service) WithInvoiceEmails() *Service {
+	s.EmailInvoices = true
+	return s
+}
+
+// WithEmailManager adds emailed dunning reminders and invoices
+func (s *Service) WithEmailManager(emailManager EmailManager) *Service {
+	s.EmailManager = emailManager
+	return s
+}
+
+// WithNotifierService adds in-app dunning reminders
+func (s *Service) WithNotifierService(notifierSvc NotifierService) *Service {
+	s.NotifierService = notifierSvc
+	return s
+}
+
 // ProcessBillingProviderWebhooks handles incoming webhooks from payment providers
-// This is the main entry point for webhook processing
+// This is the main entry point for webhook processing. When a webhook inbox is
+// configured the verified payload is recorded first, and provider retries of an
+// event that is already being processed or was processed are skipped.
 func (s *Service) ProcessBillingProviderWebhooks(ctx context.Context, req *ProcessBillingProviderWebhooksRequest) error {
 
 	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager")
-	var (
-		subscriptionId string
-	)
 
 	payload, err := s.ProviderRegistry.VerifyAndParseWebhookPayload(ctx, req.ProviderName, req.Request)
 	if err != nil {
@@ -109,32 +337,89 @@ func (s *Service) ProcessBillingProviderWebhooks(ctx context.Context, req *Proce
 		return err
 	}
 
+	if s.WebhookInboxService == nil {
+		return s.processWebhookPayload(ctx, req.ProviderName, payload)
+	}
+
+	delivery, skip, err := s.recordWebhookDelivery(ctx, req.ProviderName, payload)
+	if err != nil || skip {
+		return err
+	}
+
+	_, err = s.processWebhookDelivery(ctx, delivery, payload)
+	if errors.Is(err, billing.ErrBillingWebhookDeliveryAlreadyClaimed) {
+		logger.Info("skipping-claimed-webhook-delivery", append(webhookPayloadFieldsForLog(req.ProviderName, "", payload), zap.String("delivery-id", delivery.ID))...)
+		return nil
+	}
+	return err
+}
+
+// processWebhookPayload applies a verified webhook payload to the billing records
+func (s *Service) processWebhookPayload(ctx context.Context, providerName string, payload *paymentprovider.WebhookPayload) error {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager")
+	var (
+		subscriptionId string
+	)
+
 	userID, err := s.resolveUserID(ctx, payload)
 	if err != nil {
-		logger.Error("failed-to-resolve-user-id", zap.String("provider", req.ProviderName), zap.Error(err))
+		logger.Error("failed-to-resolve-user-id", zap.String("provider", providerName), zap.Error(err))
 		return err
 	}
 
 	if payload.IsSubscription() {
 
-		subscription, err := s.findOrCreateSubscription(ctx, req.ProviderName, payload, userID)
+		subscription, err := s.findOrCreateSubscription(ctx, providerName, payload, userID)
 		if err != nil {
-			logger.Error("failed-to-find-or-create-subscription", append(webhookPayloadFieldsForLog(req.ProviderName, userID, payload), zap.Error(err))...)
+			logger.Error("failed-to-find-or-create-subscription", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.Error(err))...)
 			return err
 		}
 
-		if err := s.updateSubscriptionFromPayload(ctx, subscription, payload); err != nil {
-			logger.Error("failed-to-update-subscription-from-payload", append(webhookPayloadFieldsForLog(req.ProviderName, userID, payload), zap.String("subscription-id", subscription.ID), zap.Error(err))...)
-			return err
+		if isStaleSubscriptionEvent(subscription, payload) {
+			logger.Info("skipping-out-of-order-subscription-update", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.String("subscription-id", subscription.ID), zap.String("event-time", payload.EventTime))...)
+		} else {
+			updated, err := s.updateSubscriptionFromPayload(ctx, subscription, payload)
+			if err != nil {
+				logger.Error("failed-to-update-subscription-from-payload", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.String("subscription-id", subscription.ID), zap.Error(err))...)
+				return err
+			}
+
+			if payload.PromotionCode != "" {
+				if err := s.redeemSubscriptionPromotionCode(ctx, updated, payload.PromotionCode); err != nil {
+					logger.Warn("failed-to-redeem-subscription-promotion-code", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.String("subscription-id", subscription.ID), zap.String("promotion-code", payload.PromotionCode), zap.Error(err))...)
+				}
+			}
+
+			if s.DunningConfig != nil {
+				if _, err := s.advanceSubscriptionDunning(ctx, updated, time.Now().UTC()); err != nil {
+					logger.Warn("failed-to-advance-subscription-dunning", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.String("subscription-id", subscription.ID), zap.Error(err))...)
+				}
+			}
 		}
 
 		subscriptionId = subscription.ID
+
+		s.invalidateSubscriptionEntitlements(ctx, subscription)
+
+		if subscription.IsGroupOwned() && s.GroupService != nil {
+			if err := s.syncGroupSeats(ctx, subscription.GroupID); err != nil {
+				logger.Warn("failed-to-sync-group-seats", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.String("subscription-id", subscription.ID), zap.String("group-id", subscription.GroupID), zap.Error(err))...)
+			}
+		}
 	}
 
 	billingEventSuccessfullyCreated := true
-	if err := s.createBillingEvent(ctx, subscriptionId, userID, req.ProviderName, payload); err != nil {
+	billingEvent, err := s.createBillingEvent(ctx, subscriptionId, userID, providerName, payload)
+	if err != nil {
 		billingEventSuccessfullyCreated = false
-		logger.Warn("failed-to-create-billing-event", append(webhookPayloadFieldsForLog(req.ProviderName, userID, payload), zap.String("subscription-id", subscriptionId), zap.Error(err))...)
+		logger.Warn("failed-to-create-billing-event", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.String("subscription-id", subscriptionId), zap.Error(err))...)
+	}
+
+	if billingEvent != nil && s.InvoiceService != nil && billingEvent.EventType == paymentprovider.EventTypePaymentSucceeded {
+		if _, err := s.issueInvoice(ctx, billingEvent); err != nil {
+			logger.Warn("failed-to-issue-invoice", append(webhookPayloadFieldsForLog(providerName, userID, payload), zap.String("billing-event-id", billingEvent.ID), zap.Error(err))...)
+		}
 	}
 
 	// Optional audit logging
@@ -142,9 +427,9 @@ func (s *Service) ProcessBillingProviderWebhooks(ctx context.Context, req *Proce
 
 		eventMessageDetails := ""
 		if payload.IsSubscription() {
-			eventMessageDetails = fmt.Sprintf("Processed %s webhook for subscription %s", req.ProviderName, payload.SubscriptionID)
+			eventMessageDetails = fmt.Sprintf("Processed %s webhook for subscription %s", providerName, payload.SubscriptionID)
 		} else {
-			eventMessageDetails = fmt.Sprintf("Processed %s webhook for non-subscription event", req.ProviderName)
+			eventMessageDetails = fmt.Sprintf("Processed %s webhook for non-subscription event", providerName)
 		}
 
 		event := &AuditEvent{
@@ -153,7 +438,7 @@ func (s *Service) ProcessBillingProviderWebhooks(ctx context.Context, req *Proce
 			Details:                         eventMessageDetails,
 			OccurredAt:                      time.Now(),
 			BillingSubscriptionId:           subscriptionId,
-			Provider:                        req.ProviderName,
+			Provider:                        providerName,
 			BillingEventSuccessfullyCreated: billingEventSuccessfullyCreated,
 		}
 
@@ -234,6 +519,37 @@ func (s *Service) GetPricePlanBySlug(ctx context.Context, req *GetPricePlanBySlu
 	return &GetPricePlanBySlugResponse{GetPricePlanBySlugResponse: response}, nil
 }
 
+// QuotePricePlan returns a line-itemised quote for a pricing plan for external BMS clients.
+// Non-admin users can only quote plans that are publicly visible.
+func (s *Service) QuotePricePlan(ctx context.Context, req *QuotePricePlanRequest) (*QuotePricePlanResponse, error) {
+	var logger *zap.Logger = logger.AcquirePackageFrom(ctx, "external/billingmanager")
+
+	if s.PricerService == nil {
+		logger.Error("pricer-service-not-enabled", zap.String("user-id", req.UserID))
+		return nil, ErrBillingManagerPricerServiceNotSet
+	}
+
+	quoter, ok := s.PricerService.(priceQuoter)
+	if !ok {
+		logger.Error("pricer-service-does-not-support-price-quotes", zap.String("user-id", req.UserID))
+		return nil, ErrBillingManagerPriceQuotesNotSupported
+	}
+
+	if !s.isRequesterAdmin(ctx, req.UserID, logger) {
+		req.QuotePricePlanRequest.PublishedOnly = true
+	}
+
+	// Promotion codes passed as the coupon are checked against the requester's limits
+	req.QuotePricePlanRequest.UserID = req.UserID
+
+	response, err := quoter.QuotePricePlan(ctx, req.QuotePricePlanRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotePricePlanResponse{QuotePricePlanResponse: response}, nil
+}
+
 // GetPricingFeatures retrieves pricing feature catalog items for external BMS clients.
 func (s *Service) GetPricingFeatures(ctx context.Context, req *GetPriceFeaturesRequest) (*GetPriceFeaturesResponse, error) {
 	var logger *zap.Logger = logger.AcquirePackageFrom(ctx, "external/billingmanager")
@@ -545,6 +861,12 @@ func (s *Service) resolveUserID(ctx context.Context, payload *paymentprovider.We
 
 	logger.Info("unable-to-find-existing-subscription-using-event-type-and-subscription-id", zap.String("event-type", payload.EventType))
 
+	// checkouts created through billing manager carry the platform user ID as metadata
+	if payload.UserID != "" {
+		logger.Info("found-user-id-in-payload-metadata", zap.String("user-id", payload.UserID))
+		return payload.UserID, nil
+	}
+
 	if s.UserService != nil && payload.CustomerEmail != "" {
 		userResp, userErr := findUserByEmail(ctx, s.UserService, &user.GetUserByEmailRequest{Email: payload.CustomerEmail})
 		if userErr == nil {
@@ -609,18 +931,23 @@ func (s *Service) findOrCreateSubscription(ctx context.Context, providerName str
 
 	createReq := &billing.CreateSubscriptionRequest{
 		UserID:                   userID,
+		GroupID:                  payload.GroupID,
 		Email:                    payload.CustomerEmail,
 		Status:                   payload.Status,
 		Integrator:               providerName,
 		IntegratorSubscriptionID: payload.SubscriptionID,
 		IntegratorCustomerID:     payload.CustomerID,
 		PlanName:                 payload.PlanName,
+		PlanID:                   payload.PlanID,
 		Amount:                   payload.Amount,
 		Currency:                 payload.Currency,
+		Quantity:                 payload.Quantity,
 		NextBillingDate:          nextBillingDate,
 		AvailableUntilDate:       availableUntilDate,
 		CancelURL:                payload.CancelURL,
 		UpdateURL:                payload.UpdateURL,
+		PromotionCode:            payload.PromotionCode,
+		LastProviderEventTime:    parseTimeOrNil(payload.EventTime),
 	}
 
 	logFields := []zap.Field{
@@ -637,6 +964,10 @@ func (s *Service) findOrCreateSubscription(ctx context.Context, providerName str
 		logFields = append(logFields, zap.String("user-id", "email-only-subscription"))
 	}
 
+	if payload.GroupID != "" {
+		logFields = append(logFields, zap.String("group-id", payload.GroupID), zap.Int64("quantity", payload.Quantity))
+	}
+
 	if nextBillingDate != nil {
 		logFields = append(logFields, zap.String("next-billing-date", nextBillingDate.Format(time.RFC3339)))
 	} else {
@@ -657,8 +988,9 @@ func (s *Service) findOrCreateSubscription(ctx context.Context, providerName str
 	return createResp.Subscription, nil
 }
 
-// updateSubscriptionFromPayload updates a subscription based on webhook data
-func (s *Service) updateSubscriptionFromPayload(ctx context.Context, subscription *billing.Subscription, payload *paymentprovider.WebhookPayload) error {
+// updateSubscriptionFromPayload updates a subscription based on webhook data, returning the
+// updated subscription
+func (s *Service) updateSubscriptionFromPayload(ctx context.Context, subscription *billing.Subscription, payload *paymentprovider.WebhookPayload) (*billing.Subscription, error) {
 
 	var (
 		logger    = logger.AcquirePackageFrom(ctx, "external/billingmanager")
@@ -668,8 +1000,9 @@ func (s *Service) updateSubscriptionFromPayload(ctx context.Context, subscriptio
 	)
 
 	updateReq := &billing.UpdateSubscriptionRequest{
-		ID:     subscription.ID,
-		Status: &payload.Status,
+		ID:                    subscription.ID,
+		Status:                &payload.Status,
+		LastProviderEventTime: parseTimeOrNil(payload.EventTime),
 	}
 
 	// Update dates if present
@@ -691,6 +1024,23 @@ func (s *Service) updateSubscriptionFromPayload(ctx context.Context, subscriptio
 		updateReq.PlanName = &payload.PlanName
 	}
 
+	// Update plan ID if present
+	if payload.PlanID != "" {
+		logger.Debug("updating-plan-id", append(logFields, zap.String("plan-id", payload.PlanID))...)
+		updateReq.PlanID = &payload.PlanID
+	}
+
+	// Link the subscription to the price plan version it was bought on, or moved to
+	if payload.PlanID != "" && (subscription.PricePlanVersionID == "" || payload.PlanID != subscription.PlanID) {
+		s.linkSubscriptionToPricePlanVersion(ctx, updateReq, subscription.Integrator, payload.PlanID)
+	}
+
+	// Update seat quantity if present
+	if payload.Quantity > 0 {
+		logger.Debug("updating-quantity", append(logFields, zap.Int64("quantity", payload.Quantity))...)
+		updateReq.Quantity = &payload.Quantity
+	}
+
 	// Update URLs if present
 	if payload.CancelURL != "" {
 		logger.Debug("updating-cancel-url", append(logFields, zap.String("cancel-url", payload.CancelURL))...)
@@ -708,12 +1058,22 @@ func (s *Service) updateSubscriptionFromPayload(ctx context.Context, subscriptio
 		updateReq.CancelledAt = &now
 	}
 
-	_, err := s.BillingService.UpdateSubscription(ctx, updateReq)
-	return err
+	// A cancellation or pause scheduled for the period end has taken effect
+	if subscription.ScheduledChange != nil && (payload.Status == billing.StatusCancelled || payload.Status == billing.StatusPaused) {
+		logger.Debug("clearing-scheduled-change", append(logFields, zap.String("action", subscription.ScheduledChange.Action))...)
+		updateReq.ClearScheduledChange = true
+	}
+
+	updateResp, err := s.BillingService.UpdateSubscription(ctx, updateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateResp.Subscription, nil
 }
 
 // createBillingEvent creates an audit trail event
-func (s *Service) createBillingEvent(ctx context.Context, subscriptionID, userID, providerName string, payload *paymentprovider.WebhookPayload) error {
+func (s *Service) createBillingEvent(ctx context.Context, subscriptionID, userID, providerName string, payload *paymentprovider.WebhookPayload) (*billing.BillingEvent, error) {
 
 	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager")
 	logFields := []zap.Field{
@@ -734,6 +1094,7 @@ func (s *Service) createBillingEvent(ctx context.Context, subscriptionID, userID
 		Integrator:               providerName,
 		IntegratorEventID:        payload.EventID,
 		IntegratorSubscriptionID: payload.SubscriptionID,
+		IntegratorTransactionID:  payload.TransactionID,
 		Status:                   payload.Status,
 		Amount:                   payload.Amount,
 		Currency:                 payload.Currency,
@@ -744,15 +1105,15 @@ func (s *Service) createBillingEvent(ctx context.Context, subscriptionID, userID
 		EventTime:                *eventTime,
 	}
 
-	_, err := s.BillingService.CreateBillingEvent(ctx, createReq)
+	createResp, err := s.BillingService.CreateBillingEvent(ctx, createReq)
 
 	if err != nil {
 		logger.Error("failed-to-create-billing-event", append(logFields, zap.Error(err))...)
-		return err
+		return nil, err
 	}
 
 	logger.Info("billing-event-created", logFields...)
-	return nil
+	return createResp.BillingEvent, nil
 }
 
 // generateSubscriptionSummary creates a human-readable summary of the subscription
@@ -0,0 +1,100 @@
+package billingmanager
+
+import (
+	"context"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// getGroupForBillingAdmin retrieves the group and confirms the user can manage its billing,
+// either as a platform admin or as one of the group's billing admins
+func (s *Service) getGroupForBillingAdmin(ctx context.Context, groupID, userID string) (*group.UniversalGroup, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("group-id", groupID))
+		return nil, ErrBillingManagerGroupServiceNotSet
+	}
+
+	groupResp, err := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: groupID})
+	if err != nil {
+		logger.Warn("failed-to-get-group-for-billing", zap.String("group-id", groupID), zap.Error(err))
+		return nil, err
+	}
+
+	if !groupResp.Group.IsBillingAdmin(userID) && !s.isRequesterAdmin(ctx, userID, logger) {
+		logger.Warn("user-is-not-group-billing-admin", zap.String("group-id", groupID), zap.String("user-id", userID))
+		return nil, ErrBillingManagerUserNotGroupBillingAdmin
+	}
+
+	return groupResp.Group, nil
+}
+
+// syncGroupSeats sets the group's member limit to the seats purchased across its paying
+// group-owned subscriptions, including those in their dunning grace period. Groups left without
+// one fall back to DefaultGroupSeatsWithoutSubscription, members above the limit are kept but no
+// more can join.
+func (s *Service) syncGroupSeats(ctx context.Context, groupID string) error {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "sync-group-seats"),
+		zap.String("group-id", groupID),
+	)
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled")
+		return ErrBillingManagerGroupServiceNotSet
+	}
+
+	subscriptionsResp, err := s.BillingService.GetSubscriptions(ctx, &billing.GetSubscriptionsRequest{
+		ForGroupIDs: []string{groupID},
+		Statuses:    []string{billing.StatusActive, billing.StatusTrialing, billing.StatusPastDue, billing.StatusUnpaid},
+		PerPage:     100,
+		Page:        1,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-group-subscriptions", zap.Error(err))
+		return err
+	}
+
+	seats := billing.GroupSeats(subscriptionsResp.Subscriptions)
+	if seats == 0 {
+		seats = DefaultGroupSeatsWithoutSubscription
+	}
+
+	groupResp, err := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: groupID})
+	if err != nil {
+		logger.Error("failed-to-get-group-for-seat-sync", zap.Error(err))
+		return err
+	}
+
+	grp := groupResp.Group
+	if grp.Settings == nil {
+		grp.Settings = &group.GroupSettings{}
+	}
+
+	if grp.Settings.MaxMembers == seats {
+		logger.Debug("group-seats-already-in-sync", zap.Int("seats", seats))
+		return nil
+	}
+
+	previousSeats := grp.Settings.MaxMembers
+	grp.Settings.MaxMembers = seats
+
+	if _, err := s.GroupService.UpdateGroup(ctx, &group.UpdateGroupRequest{ID: groupID, Group: grp}); err != nil {
+		logger.Error("failed-to-update-group-seats", zap.Int("seats", seats), zap.Error(err))
+		return err
+	}
+
+	if members := grp.GetMemberCount(); members > seats {
+		logger.Warn("group-has-more-members-than-purchased-seats", zap.Int("seats", seats), zap.Int("members", members))
+	}
+
+	logger.Info("group-seats-synced", zap.Int("previous-seats", previousSeats), zap.Int("seats", seats))
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package billingmanager
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/emailmanager"
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
+)
+
+// GetMyInvoices lists the signed-in user's invoices
+func (s *Service) GetMyInvoices(ctx context.Context, req *GetMyInvoicesRequest) (*GetInvoicesResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "get-my-invoices"),
+		zap.String("user-id", req.UserID),
+	)
+
+	if s.InvoiceService == nil {
+		logger.Error("invoice-service-not-enabled")
+		return nil, ErrBillingManagerInvoiceServiceNotSet
+	}
+
+	if req.UserID == "" {
+		logger.Warn("failed-to-get-invoices-user-id-is-missing")
+		return nil, ErrBillingManagerRequiresUserIdIsMissing
+	}
+
+	if req.GetInvoicesRequest == nil {
+		req.GetInvoicesRequest = &invoice.GetInvoicesRequest{}
+	}
+	req.GetInvoicesRequest.UserID = req.UserID
+
+	invoicesResp, err := s.InvoiceService.GetInvoices(ctx, req.GetInvoicesRequest)
+	if err != nil {
+		logger.Error("failed-to-get-invoices", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetInvoicesResponse{GetInvoicesResponse: invoicesResp}, nil
+}
+
+// DownloadMyInvoice renders one of the signed-in user's invoices as a PDF or HTML document
+func (s *Service) DownloadMyInvoice(ctx context.Context, req *DownloadMyInvoiceRequest) (*DownloadInvoiceResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "download-my-invoice"),
+		zap.String("user-id", req.UserID),
+		zap.String("invoice-id", req.InvoiceID),
+	)
+
+	if s.InvoiceService == nil {
+		logger.Error("invoice-service-not-enabled")
+		return nil, ErrBillingManagerInvoiceServiceNotSet
+	}
+
+	userInvoice, err := s.getUserInvoice(ctx, req.UserID, req.InvoiceID)
+	if err != nil {
+		logger.Warn("failed-to-get-user-invoice", zap.Error(err))
+		return nil, err
+	}
+
+	renderResp, err := s.InvoiceService.RenderInvoice(ctx, &invoice.RenderInvoiceRequest{
+		Invoice: userInvoice,
+		Format:  invoice.RenderFormat(req.Format),
+	})
+	if err != nil {
+		logger.Warn("failed-to-render-invoice", zap.String("format", req.Format), zap.Error(err))
+		return nil, err
+	}
+
+	return &DownloadInvoiceResponse{RenderInvoiceResponse: renderResp}, nil
+}
+
+// EmailMyInvoice sends one of the signed-in user's invoices to them again
+func (s *Service) EmailMyInvoice(ctx context.Context, req *EmailMyInvoiceRequest) (*InvoiceResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "email-my-invoice"),
+		zap.String("user-id", req.UserID),
+		zap.String("invoice-id", req.InvoiceID),
+	)
+
+	if s.InvoiceService == nil {
+		logger.Error("invoice-service-not-enabled")
+		return nil, ErrBillingManagerInvoiceServiceNotSet
+	}
+
+	if s.EmailManager == nil {
+		logger.Error("email-manager-not-enabled")
+		return nil, ErrBillingManagerEmailManagerNotSet
+	}
+
+	userInvoice, err := s.getUserInvoice(ctx, req.UserID, req.InvoiceID)
+	if err != nil {
+		logger.Warn("failed-to-get-user-invoice", zap.Error(err))
+		return nil, err
+	}
+
+	emailedInvoice, err := s.emailInvoice(ctx, userInvoice)
+	if err != nil {
+		logger.Warn("failed-to-email-invoice", zap.Error(err))
+		return nil, err
+	}
+
+	return &InvoiceResponse{Invoice: emailedInvoice}, nil
+}
+
+// getUserInvoice returns the invoice if it was issued to the user. Invoices issued to anyone
+// else are reported as not found so their existence is not revealed.
+func (s *Service) getUserInvoice(ctx context.Context, userID string, invoiceID string) (*invoice.Invoice, error) {
+
+	if userID == "" {
+		return nil, ErrBillingManagerRequiresUserIdIsMissing
+	}
+
+	invoiceResp, err := s.InvoiceService.GetInvoiceByID(ctx, &invoice.GetInvoiceByIDRequest{ID: invoiceID})
+	if err != nil {
+		return nil, err
+	}
+
+	if invoiceResp.Invoice.UserID != userID {
+		return nil, invoice.ErrInvoiceNotFound
+	}
+
+	return invoiceResp.Invoice, nil
+}
+
+// issueInvoice invoices a successful payment, billing the user's current name and billing
+// address, and emails the invoice when invoice emails are enabled. Replayed webhooks return
+// the invoice already issued for the billing event without emailing it again.
+func (s *Service) issueInvoice(ctx context.Context, billingEvent *billing.BillingEvent) (*invoice.Invoice, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "issue-invoice"),
+		zap.String("billing-event-id", billingEvent.ID),
+		zap.String("user-id", billingEvent.UserID),
+	)
+
+	createResp, err := s.InvoiceService.CreateInvoiceFromBillingEvent(ctx, &invoice.CreateInvoiceFromBillingEventRequest{
+		BillingEvent: billingEvent,
+		Customer:     s.getInvoiceCustomer(ctx, billingEvent),
+	})
+	if err != nil {
+		logger.Error("failed-to-create-invoice", zap.Error(err))
+		return nil, err
+	}
+
+	issuedInvoice := createResp.Invoice
+	if createResp.Duplicate {
+		logger.Info("invoice-already-issued-for-billing-event", zap.String("invoice-id", issuedInvoice.ID))
+		return issuedInvoice, nil
+	}
+
+	logger.Info("invoice-issued", zap.String("invoice-id", issuedInvoice.ID), zap.String("invoice-number", issuedInvoice.Number))
+
+	if s.AuditService != nil {
+		_ = s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    audit.AuditActorIdSystem,
+			Action:     AuditActionBillingInvoiceIssued,
+			TargetId:   issuedInvoice.ID,
+			TargetType: TargetTypeInvoice,
+			Domain:     "billingmanager",
+			Details: map[string]interface{}{
+				"number":           issuedInvoice.Number,
+				"user_id":          issuedInvoice.UserID,
+				"billing_event_id": issuedInvoice.BillingEventID,
+				"total":            issuedInvoice.Total,
+				"currency":         issuedInvoice.Currency,
+			},
+		})
+	}
+
+	if s.EmailInvoices && s.EmailManager != nil {
+		emailedInvoice, err := s.emailInvoice(ctx, issuedInvoice)
+		if err != nil {
+			logger.Warn("failed-to-email-invoice", zap.String("invoice-id", issuedInvoice.ID), zap.Error(err))
+			return issuedInvoice, nil
+		}
+		issuedInvoice = emailedInvoice
+	}
+
+	return issuedInvoice, nil
+}
+
+// getInvoiceCustomer returns who a billing event's invoice is addressed to, using the user's
+// name and billing address when the user is known. Nil is returned when no user details are
+// available so the invoice falls back to the billing event's email.
+func (s *Service) getInvoiceCustomer(ctx context.Context, billingEvent *billing.BillingEvent) *invoice.Customer {
+
+	if s.UserService == nil || billingEvent.UserID == "" {
+		return nil
+	}
+
+	userResp, err := s.UserService.GetUserByID(ctx, &user.GetUserByIDRequest{ID: billingEvent.UserID})
+	if err != nil || userResp.User == nil {
+		logger.AcquirePackageFrom(ctx, "external/billingmanager").Warn("failed-to-get-invoice-customer", zap.String("user-id", billingEvent.UserID), zap.Error(err))
+		return nil
+	}
+
+	customer := &invoice.Customer{Email: billingEvent.Email}
+	if customer.Email == "" {
+		customer.Email = userResp.User.Email
+	}
+
+	if userResp.User.PersonalInfo != nil {
+		customer.Name = userResp.User.PersonalInfo.FullName
+	}
+
+	if address := userResp.User.BillingAddress; address != nil {
+		if address.Name != "" {
+			customer.Name = address.Name
+		}
+		customer.TaxID = address.TaxID
+		customer.Address = &invoice.Address{
+			Line1:      address.Line1,
+			Line2:      address.Line2,
+			City:       address.City,
+			Region:     address.Region,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		}
+	}
+
+	return customer
+}
+
+// emailInvoice sends the invoice to the customer and records when it was sent
+func (s *Service) emailInvoice(ctx context.Context, invoiceToEmail *invoice.Invoice) (*invoice.Invoice, error) {
+
+	if invoiceToEmail.Customer.Email == "" {
+		return nil, ErrBillingManagerInvoiceHasNoEmail
+	}
+
+	description := "your subscription"
+	if len(invoiceToEmail.LineItems) > 0 && invoiceToEmail.LineItems[0].Description != "" {
+		description = invoiceToEmail.LineItems[0].Description
+	}
+
+	issuedDate := invoiceToEmail.IssuedAt
+	if issuedAt, err := time.Parse(common.RFC3339NanoUTC, invoiceToEmail.IssuedAt); err == nil {
+		issuedDate = issuedAt.Format("02 January 2006")
+	}
+
+	message := fmt.Sprintf(InvoiceEmailMessageTmpl, invoice.FormatAmount(invoiceToEmail.Total, invoiceToEmail.Currency), description, issuedDate)
+
+	err := s.EmailManager.SendCustomEmail(ctx, &emailmanager.SendCustomEmailRequest{
+		EmailSubject:  fmt.Sprintf(InvoiceEmailSubjectTmpl, invoiceToEmail.Number),
+		EmailPreview:  message,
+		EmailBody:     fmt.Sprintf(InvoiceEmailBodyTmpl, html.EscapeString(message), html.EscapeString(invoiceToEmail.Number)),
+		EmailTo:       invoiceToEmail.Customer.Email,
+		WithFooter:    true,
+		UserId:        invoiceToEmail.UserID,
+		RecipientType: string(audit.User),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	markedResp, err := s.InvoiceService.MarkInvoiceEmailed(ctx, &invoice.MarkInvoiceEmailedRequest{ID: invoiceToEmail.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return markedResp.Invoice, nil
+}
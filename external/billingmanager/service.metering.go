@@ -0,0 +1,110 @@
+package billingmanager
+
+import (
+	"context"
+
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/metering"
+	"go.uber.org/zap"
+)
+
+// GetMyUsage returns the signed-in user's usage of their quota limited features in the
+// current billing period
+func (s *Service) GetMyUsage(ctx context.Context, req *GetMyUsageRequest) (*GetUsageResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "get-my-usage"),
+		zap.String("user-id", req.UserID),
+	)
+
+	if s.MeteringService == nil {
+		logger.Error("metering-service-not-enabled")
+		return nil, ErrBillingManagerMeteringServiceNotSet
+	}
+
+	if req.UserID == "" {
+		logger.Warn("failed-to-get-usage-user-id-is-missing")
+		return nil, ErrBillingManagerRequiresUserIdIsMissing
+	}
+
+	usageResp, err := s.MeteringService.GetUsage(ctx, &metering.GetUsageRequest{
+		SubjectType: entitlement.SubjectTypeUser,
+		SubjectID:   req.UserID,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-usage", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetUsageResponse{Usage: usageResp.Usage}, nil
+}
+
+// GetSubjectUsage returns the usage of any user or group in the current billing period for admins
+func (s *Service) GetSubjectUsage(ctx context.Context, req *GetSubjectUsageRequest) (*GetUsageResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "get-subject-usage"),
+		zap.String("subject-type", req.SubjectType),
+		zap.String("subject-id", req.SubjectID),
+	)
+
+	if s.MeteringService == nil {
+		logger.Error("metering-service-not-enabled")
+		return nil, ErrBillingManagerMeteringServiceNotSet
+	}
+
+	usageResp, err := s.MeteringService.GetUsage(ctx, &metering.GetUsageRequest{
+		SubjectType: entitlement.SubjectType(req.SubjectType),
+		SubjectID:   req.SubjectID,
+	})
+	if err != nil {
+		logger.Warn("failed-to-get-usage", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetUsageResponse{Usage: usageResp.Usage}, nil
+}
+
+// GetUsageEvents lists recorded usage events for admins
+func (s *Service) GetUsageEvents(ctx context.Context, req *GetUsageEventsRequest) (*GetUsageEventsResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "get-usage-events"))
+
+	if s.MeteringService == nil {
+		logger.Error("metering-service-not-enabled")
+		return nil, ErrBillingManagerMeteringServiceNotSet
+	}
+
+	if req.GetUsageEventsRequest == nil {
+		req.GetUsageEventsRequest = &metering.GetUsageEventsRequest{}
+	}
+
+	eventsResp, err := s.MeteringService.GetUsageEvents(ctx, req.GetUsageEventsRequest)
+	if err != nil {
+		logger.Warn("failed-to-get-usage-events", zap.Error(err))
+		return nil, err
+	}
+
+	return &GetUsageEventsResponse{GetUsageEventsResponse: eventsResp}, nil
+}
+
+// RecordUsage records usage of a metered feature on behalf of a user or group, for
+// example to backfill usage or record work done outside of the API
+func (s *Service) RecordUsage(ctx context.Context, req *RecordUsageRequest) (*RecordUsageResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "record-usage"))
+
+	if s.MeteringService == nil {
+		logger.Error("metering-service-not-enabled")
+		return nil, ErrBillingManagerMeteringServiceNotSet
+	}
+
+	recordResp, err := s.MeteringService.RecordUsage(ctx, req.RecordUsageRequest)
+	if err != nil {
+		logger.Warn("failed-to-record-usage", zap.Error(err))
+		return nil, err
+	}
+
+	return &RecordUsageResponse{RecordUsageResponse: recordResp}, nil
+}
@@ -0,0 +1,298 @@
+package billingmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/pricer"
+	"go.uber.org/zap"
+)
+
+// MigratePricePlanVersions moves the subscribers of superseded price plan versions whose
+// migration is due onto the version chosen for them, so they get its features and limits.
+// Only the internal record changes; the price charged by the payment provider is left as is
+// and must be changed with the provider. Each move is recorded as a billing event. A version
+// is marked as migrated once all of its subscribers have been moved, so failed moves are
+// retried on the next run.
+func (s *Service) MigratePricePlanVersions(ctx context.Context, req *MigratePricePlanVersionsRequest) (*PlanVersionMigrationReportResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "migrate-price-plan-versions"))
+
+	versioner, ok := s.PricerService.(pricePlanVersioner)
+	if !ok {
+		logger.Error("pricer-service-does-not-support-price-plan-versions")
+		return nil, ErrBillingManagerPlanVersioningNotSupported
+	}
+
+	if !s.planVersionMigrationMu.TryLock() {
+		logger.Warn("price-plan-version-migration-already-in-progress")
+		return nil, ErrBillingManagerPlanVersionMigrationInProgress
+	}
+	defer s.planVersionMigrationMu.Unlock()
+
+	report := &PlanVersionMigrationReport{
+		StartedAt: time.Now().UTC(),
+	}
+
+	dueResp, err := versioner.GetDuePricePlanVersionMigrations(ctx, &pricer.GetDuePricePlanVersionMigrationsRequest{})
+	if err != nil {
+		logger.Error("failed-to-get-due-price-plan-version-migrations", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range dueResp.PricePlanVersions {
+		if ctx.Err() != nil {
+			logger.Warn("price-plan-version-migration-interrupted", zap.Error(ctx.Err()))
+			break
+		}
+
+		fromVersion := &dueResp.PricePlanVersions[i]
+		if req.PricePlanVersionID != "" && fromVersion.ID != req.PricePlanVersionID {
+			continue
+		}
+
+		report.Versions++
+
+		toVersionResp, err := versioner.GetPricePlanVersionByID(ctx, &pricer.GetPricePlanVersionByIDRequest{ID: fromVersion.Grandfathering.MigrateToVersionID})
+		if err != nil {
+			logger.Warn("failed-to-get-price-plan-version-to-migrate-to", zap.String("price-plan-version-id", fromVersion.ID), zap.Error(err))
+			report.Failed++
+			report.Failures = append(report.Failures, PlanVersionMigrationFailure{PricePlanVersionID: fromVersion.ID, Error: err.Error()})
+			continue
+		}
+
+		migrated, failed := s.migratePricePlanVersionSubscribers(ctx, fromVersion, toVersionResp.PricePlanVersion, report)
+		if failed > 0 {
+			continue
+		}
+
+		_, err = versioner.CompletePricePlanVersionMigration(ctx, &pricer.CompletePricePlanVersionMigrationRequest{
+			ID:            fromVersion.ID,
+			MigratedCount: migrated,
+		})
+		if err != nil {
+			logger.Warn("failed-to-complete-price-plan-version-migration", zap.String("price-plan-version-id", fromVersion.ID), zap.Error(err))
+			report.Failed++
+			report.Failures = append(report.Failures, PlanVersionMigrationFailure{PricePlanVersionID: fromVersion.ID, Error: err.Error()})
+		}
+	}
+
+	report.CompletedAt = time.Now().UTC()
+
+	logger.Info("price-plan-version-migration-completed",
+		zap.Int("versions", report.Versions),
+		zap.Int("migrated", report.Migrated),
+		zap.Int("failed", report.Failed),
+	)
+
+	return &PlanVersionMigrationReportResponse{Report: report}, nil
+}
+
+// RunPricePlanVersionMigrations migrates due price plan versions straight away and then on every
+// interval until the context is cancelled. It blocks, so it is usually started in its own
+// goroutine. Runs that fail are logged and retried on the next interval.
+func (s *Service) RunPricePlanVersionMigrations(ctx context.Context, req *RunPricePlanVersionMigrationsRequest) error {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "run-price-plan-version-migrations"))
+
+	interval := req.Interval
+	if interval <= 0 {
+		interval = defaultPlanVersionMigrationInterval
+	}
+
+	logger.Info("starting-scheduled-price-plan-version-migrations", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.MigratePricePlanVersions(ctx, &MigratePricePlanVersionsRequest{})
+		if errors.Is(err, ErrBillingManagerPlanVersioningNotSupported) {
+			return err
+		}
+		if err != nil {
+			logger.Error("scheduled-price-plan-version-migration-failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("stopping-scheduled-price-plan-version-migrations")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// migratePricePlanVersionSubscribers moves every subscription on the superseded version to the
+// newer version, returning how many were moved and how many failed
+func (s *Service) migratePricePlanVersionSubscribers(ctx context.Context, fromVersion *pricer.PricePlanVersion, toVersion *pricer.PricePlanVersion, report *PlanVersionMigrationReport) (int, int) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "migrate-price-plan-version-subscribers"),
+		zap.String("price-plan-version-id", fromVersion.ID),
+	)
+
+	subscriptions, err := s.getPricePlanVersionSubscribers(ctx, fromVersion.ID)
+	if err != nil {
+		logger.Error("failed-to-get-price-plan-version-subscribers", zap.Error(err))
+		report.Failed++
+		report.Failures = append(report.Failures, PlanVersionMigrationFailure{PricePlanVersionID: fromVersion.ID, Error: err.Error()})
+		return 0, 1
+	}
+
+	var migrated, failed int
+	for i := range subscriptions {
+		if err := s.migrateSubscriptionPricePlanVersion(ctx, &subscriptions[i], fromVersion, toVersion); err != nil {
+			logger.Warn("failed-to-migrate-subscription-price-plan-version", zap.String("subscription-id", subscriptions[i].ID), zap.Error(err))
+			failed++
+			report.Failed++
+			report.Failures = append(report.Failures, PlanVersionMigrationFailure{
+				PricePlanVersionID: fromVersion.ID,
+				SubscriptionID:     subscriptions[i].ID,
+				Error:              err.Error(),
+			})
+			continue
+		}
+
+		migrated++
+		report.Migrated++
+	}
+
+	return migrated, failed
+}
+
+// getPricePlanVersionSubscribers returns every subscription linked to the price plan version
+func (s *Service) getPricePlanVersionSubscribers(ctx context.Context, pricePlanVersionID string) ([]billing.Subscription, error) {
+
+	var subscriptions []billing.Subscription
+
+	for page := 1; ; page++ {
+		response, err := s.BillingService.GetSubscriptions(ctx, &billing.GetSubscriptionsRequest{
+			ForPricePlanVersionIDs: []string{pricePlanVersionID},
+			Order:                  "created_at_asc",
+			PerPage:                planVersionMigrationPageSize,
+			Page:                   page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, response.Subscriptions...)
+
+		if len(response.Subscriptions) == 0 || page >= response.TotalPages {
+			break
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// migrateSubscriptionPricePlanVersion links the subscription to the newer version and records a
+// billing event explaining the move
+func (s *Service) migrateSubscriptionPricePlanVersion(ctx context.Context, subscription *billing.Subscription, fromVersion *pricer.PricePlanVersion, toVersion *pricer.PricePlanVersion) error {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "migrate-subscription-price-plan-version"),
+		zap.String("subscription-id", subscription.ID),
+	)
+
+	migratedAt := time.Now().UTC()
+
+	updateResp, err := s.BillingService.UpdateSubscription(ctx, &billing.UpdateSubscriptionRequest{
+		ID:                 subscription.ID,
+		PricePlanID:        &toVersion.PricePlanID,
+		PricePlanVersionID: &toVersion.ID,
+		PricePlanVersion:   &toVersion.Version,
+	})
+	if err != nil {
+		return err
+	}
+	updated := updateResp.Subscription
+
+	details := map[string]interface{}{
+		"reason":                     fmt.Sprintf("subscription moved from version %d to version %d of the %s plan", fromVersion.Version, toVersion.Version, toVersion.Name),
+		"price_plan_id":              toVersion.PricePlanID,
+		"from_price_plan_version_id": fromVersion.ID,
+		"from_price_plan_version":    fromVersion.Version,
+		"to_price_plan_version_id":   toVersion.ID,
+		"to_price_plan_version":      toVersion.Version,
+	}
+
+	explanation, err := json.Marshal(details)
+	if err != nil {
+		logger.Error("failed-to-marshal-price-plan-version-migration-explanation", zap.Error(err))
+		return err
+	}
+
+	_, err = s.BillingService.CreateBillingEvent(ctx, &billing.CreateBillingEventRequest{
+		SubscriptionID:           updated.ID,
+		UserID:                   updated.UserID,
+		Email:                    updated.Email,
+		EventType:                EventTypeSubscriptionPlanVersionMigrated,
+		Integrator:               updated.Integrator,
+		IntegratorEventID:        fmt.Sprintf("plan-version-migration-%s-%s", updated.ID, toVersion.ID),
+		IntegratorSubscriptionID: updated.IntegratorSubscriptionID,
+		Status:                   updated.Status,
+		Amount:                   updated.Amount,
+		Currency:                 updated.Currency,
+		PlanName:                 updated.PlanName,
+		RawPayload:               string(explanation),
+		EventTime:                migratedAt,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-price-plan-version-migration-billing-event", zap.Error(err))
+		return err
+	}
+
+	s.invalidateSubscriptionEntitlements(ctx, updated)
+
+	if s.AuditService != nil {
+		_ = s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    audit.AuditActorIdSystem,
+			Action:     AuditActionBillingSubscriptionPlanVersionMigrated,
+			TargetId:   updated.ID,
+			TargetType: TargetTypeSubscription,
+			Domain:     "billingmanager",
+			Details:    details,
+		})
+	}
+
+	logger.Info("subscription-price-plan-version-migrated", zap.Int("from-version", fromVersion.Version), zap.Int("to-version", toVersion.Version))
+
+	return nil
+}
+
+// linkSubscriptionToPricePlanVersion adds the newest price plan version linked to the provider-side
+// plan to the subscription update, when the pricer service keeps price plan versions. A plan that
+// is not in the catalog leaves the subscription's version unchanged.
+func (s *Service) linkSubscriptionToPricePlanVersion(ctx context.Context, update *billing.UpdateSubscriptionRequest, providerName string, providerPlanID string) {
+
+	versioner, ok := s.PricerService.(pricePlanVersioner)
+	if !ok || providerPlanID == "" {
+		return
+	}
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager")
+
+	versionResp, err := versioner.FindPricePlanVersionByProviderPrice(ctx, &pricer.FindPricePlanVersionByProviderPriceRequest{
+		Provider:        providerName,
+		ProviderPriceID: providerPlanID,
+	})
+	if err != nil {
+		if !errors.Is(err, pricer.ErrPricePlanVersionNotFound) {
+			logger.Warn("failed-to-find-price-plan-version-for-provider-plan", zap.String("subscription-id", update.ID), zap.String("provider", providerName), zap.String("plan-id", providerPlanID), zap.Error(err))
+		}
+		return
+	}
+
+	version := versionResp.PricePlanVersion
+	update.PricePlanID = &version.PricePlanID
+	update.PricePlanVersionID = &version.ID
+	update.PricePlanVersion = &version.Version
+}
@@ -0,0 +1,427 @@
+package billingmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"go.uber.org/zap"
+)
+
+// reconcilableSubscriptionStatuses lists the statuses of subscriptions that are compared
+// with their provider. Cancelled subscriptions are only compared while access remains.
+var reconcilableSubscriptionStatuses = []string{
+	billing.StatusActive,
+	billing.StatusTrialing,
+	billing.StatusPastDue,
+	billing.StatusPaused,
+	billing.StatusUnpaid,
+	billing.StatusIncomplete,
+	billing.StatusCancelled,
+}
+
+// ReconcileSubscriptions compares stored subscriptions with the payment providers' view of them
+// and, unless it is a dry run, repairs any drift so a missed webhook does not leave a subscription
+// stale. Subscriptions due to renew or lose access soonest are checked first, so a limited run
+// covers those most likely to have changed. Each repair is recorded as a billing event explaining
+// the correction.
+func (s *Service) ReconcileSubscriptions(ctx context.Context, req *ReconcileSubscriptionsRequest) (*ReconciliationReportResponse, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "reconcile-subscriptions"),
+		zap.String("provider", req.ProviderName),
+		zap.Bool("dry-run", req.DryRun),
+	)
+
+	getter, ok := s.ProviderRegistry.(subscriptionInfoGetter)
+	if !ok {
+		logger.Error("provider-registry-does-not-support-subscription-lookups")
+		return nil, ErrBillingManagerReconciliationNotSupported
+	}
+
+	if !req.DryRun {
+		if !s.reconciliationMu.TryLock() {
+			logger.Warn("subscription-reconciliation-already-in-progress")
+			return nil, ErrBillingManagerReconciliationInProgress
+		}
+		defer s.reconciliationMu.Unlock()
+	}
+
+	report := &ReconciliationReport{
+		DryRun:       req.DryRun,
+		ProviderName: req.ProviderName,
+		StartedAt:    time.Now().UTC(),
+		Drifts:       []SubscriptionDrift{},
+	}
+
+	candidates, err := s.getReconciliationCandidates(ctx, req.ProviderName, normaliseReconciliationLimit(req.Limit))
+	if err != nil {
+		logger.Error("failed-to-get-subscriptions-to-reconcile", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range candidates {
+		if ctx.Err() != nil {
+			logger.Warn("subscription-reconciliation-interrupted", zap.Error(ctx.Err()))
+			break
+		}
+
+		subscription := &candidates[i]
+
+		info, err := getter.GetSubscriptionInfo(ctx, subscription.Integrator, subscription.IntegratorSubscriptionID)
+		if errors.Is(err, paymentprovider.ErrPaymentProviderKofiNoSubscriptionAPI) {
+			report.Skipped++
+			continue
+		}
+		if err != nil {
+			logger.Warn("failed-to-get-provider-subscription-info", zap.String("subscription-id", subscription.ID), zap.String("integrator", subscription.Integrator), zap.Error(err))
+			report.Failed++
+			report.Failures = append(report.Failures, SubscriptionReconciliationFailure{
+				SubscriptionID:           subscription.ID,
+				Integrator:               subscription.Integrator,
+				IntegratorSubscriptionID: subscription.IntegratorSubscriptionID,
+				Error:                    err.Error(),
+			})
+			continue
+		}
+
+		report.Checked++
+
+		fields, update := subscriptionDriftFromProvider(subscription, info)
+		if len(fields) == 0 {
+			continue
+		}
+
+		report.Drifted++
+		drift := SubscriptionDrift{
+			SubscriptionID:           subscription.ID,
+			UserID:                   subscription.UserID,
+			Integrator:               subscription.Integrator,
+			IntegratorSubscriptionID: subscription.IntegratorSubscriptionID,
+			Fields:                   fields,
+		}
+
+		if !req.DryRun {
+			billingEventID, err := s.repairSubscriptionDrift(ctx, subscription, update, fields)
+			if err != nil {
+				report.Failed++
+				drift.Error = err.Error()
+			} else {
+				report.Repaired++
+				drift.Repaired = true
+				drift.BillingEventID = billingEventID
+			}
+		}
+
+		report.Drifts = append(report.Drifts, drift)
+	}
+
+	report.CompletedAt = time.Now().UTC()
+
+	logger.Info("subscription-reconciliation-completed",
+		zap.Int("checked", report.Checked),
+		zap.Int("drifted", report.Drifted),
+		zap.Int("repaired", report.Repaired),
+		zap.Int("skipped", report.Skipped),
+		zap.Int("failed", report.Failed),
+	)
+
+	return &ReconciliationReportResponse{Report: report}, nil
+}
+
+// GetSubscriptionDriftReport reports how subscriptions differ from their payment providers
+// without repairing them
+func (s *Service) GetSubscriptionDriftReport(ctx context.Context, req *GetSubscriptionDriftReportRequest) (*ReconciliationReportResponse, error) {
+	return s.ReconcileSubscriptions(ctx, &ReconcileSubscriptionsRequest{
+		ProviderName: req.ProviderName,
+		Limit:        req.Limit,
+		DryRun:       true,
+	})
+}
+
+// RunSubscriptionReconciliation reconciles subscriptions straight away and then on every
+// interval until the context is cancelled. It blocks, so it is usually started in its own
+// goroutine. Runs that fail are logged and retried on the next interval.
+func (s *Service) RunSubscriptionReconciliation(ctx context.Context, req *RunSubscriptionReconciliationRequest) error {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(zap.String("operation", "run-subscription-reconciliation"))
+
+	interval := req.Interval
+	if interval <= 0 {
+		interval = defaultReconciliationInterval
+	}
+
+	logger.Info("starting-scheduled-subscription-reconciliation", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.ReconcileSubscriptions(ctx, &ReconcileSubscriptionsRequest{
+			ProviderName: req.ProviderName,
+			Limit:        req.Limit,
+		})
+		if errors.Is(err, ErrBillingManagerReconciliationNotSupported) {
+			return err
+		}
+		if err != nil {
+			logger.Error("scheduled-subscription-reconciliation-failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("stopping-scheduled-subscription-reconciliation")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// getReconciliationCandidates returns up to limit provider-billed subscriptions to compare with
+// their provider, ordered by when they are next due to renew or lose access
+func (s *Service) getReconciliationCandidates(ctx context.Context, providerName string, limit int) ([]billing.Subscription, error) {
+
+	var (
+		candidates []billing.Subscription
+		seen       = map[string]bool{}
+		now        = time.Now()
+	)
+
+	for page := 1; ; page++ {
+		response, err := s.BillingService.GetSubscriptions(ctx, &billing.GetSubscriptionsRequest{
+			IntegratorName: providerName,
+			Statuses:       reconcilableSubscriptionStatuses,
+			Order:          "created_at_asc",
+			PerPage:        reconciliationPageSize,
+			Page:           page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subscription := range response.Subscriptions {
+			// Complimentary subscriptions are granted by an admin and have no provider to compare with
+			if seen[subscription.ID] || subscription.IntegratorSubscriptionID == "" || subscription.IsComplimentary() {
+				continue
+			}
+			seen[subscription.ID] = true
+
+			if subscription.Status == billing.StatusCancelled && (subscription.AvailableUntilDate == nil || subscription.AvailableUntilDate.Before(now)) {
+				continue
+			}
+
+			candidates = append(candidates, subscription)
+		}
+
+		if len(response.Subscriptions) == 0 || page >= response.TotalPages {
+			break
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return reconciliationDueAt(&candidates[i]).Before(reconciliationDueAt(&candidates[j]))
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return candidates, nil
+}
+
+// repairSubscriptionDrift applies the provider's view to the subscription and records a billing
+// event explaining the correction, returning the billing event's ID
+func (s *Service) repairSubscriptionDrift(ctx context.Context, subscription *billing.Subscription, update *billing.UpdateSubscriptionRequest, fields []SubscriptionDriftField) (string, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "repair-subscription-drift"),
+		zap.String("subscription-id", subscription.ID),
+		zap.String("integrator", subscription.Integrator),
+	)
+
+	// The provider's current view supersedes every event sent before now, so webhooks
+	// delivered late with older state are skipped as out of order
+	reconciledAt := time.Now().UTC()
+	update.LastProviderEventTime = &reconciledAt
+
+	if update.PlanID != nil {
+		s.linkSubscriptionToPricePlanVersion(ctx, update, subscription.Integrator, *update.PlanID)
+	}
+
+	updateResp, err := s.BillingService.UpdateSubscription(ctx, update)
+	if err != nil {
+		logger.Error("failed-to-repair-subscription", zap.Error(err))
+		return "", err
+	}
+	updated := updateResp.Subscription
+
+	if s.DunningConfig != nil {
+		if _, err := s.advanceSubscriptionDunning(ctx, updated, reconciledAt); err != nil {
+			logger.Warn("failed-to-advance-subscription-dunning-after-repair", zap.Error(err))
+		}
+	}
+
+	explanation, err := json.Marshal(map[string]interface{}{
+		"reason": fmt.Sprintf("subscription corrected to match %s", subscription.Integrator),
+		"fields": fields,
+	})
+	if err != nil {
+		logger.Error("failed-to-marshal-reconciliation-explanation", zap.Error(err))
+		return "", err
+	}
+
+	eventResp, err := s.BillingService.CreateBillingEvent(ctx, &billing.CreateBillingEventRequest{
+		SubscriptionID:           updated.ID,
+		UserID:                   updated.UserID,
+		Email:                    updated.Email,
+		EventType:                EventTypeSubscriptionReconciled,
+		Integrator:               updated.Integrator,
+		IntegratorEventID:        fmt.Sprintf("reconciliation-%s-%d", updated.ID, reconciledAt.UnixNano()),
+		IntegratorSubscriptionID: updated.IntegratorSubscriptionID,
+		Status:                   updated.Status,
+		Amount:                   updated.Amount,
+		Currency:                 updated.Currency,
+		PlanName:                 updated.PlanName,
+		RawPayload:               string(explanation),
+		EventTime:                reconciledAt,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-reconciliation-billing-event", zap.Error(err))
+		return "", err
+	}
+
+	s.invalidateSubscriptionEntitlements(ctx, updated)
+
+	if updated.IsGroupOwned() && s.GroupService != nil {
+		if err := s.syncGroupSeats(ctx, updated.GroupID); err != nil {
+			logger.Warn("failed-to-sync-group-seats-after-repair", zap.String("group-id", updated.GroupID), zap.Error(err))
+		}
+	}
+
+	if s.AuditService != nil {
+		_ = s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    audit.AuditActorIdSystem,
+			Action:     AuditActionBillingSubscriptionReconciled,
+			TargetId:   updated.ID,
+			TargetType: TargetTypeSubscription,
+			Domain:     "billingmanager",
+			Details:    fields,
+		})
+	}
+
+	logger.Info("subscription-drift-repaired", zap.Int("fields", len(fields)))
+
+	return eventResp.BillingEvent.ID, nil
+}
+
+// subscriptionDriftFromProvider compares a stored subscription with the provider's view of it,
+// returning the fields that differ and the update that brings the subscription in line.
+// Values the provider does not return are not treated as drift.
+func subscriptionDriftFromProvider(subscription *billing.Subscription, info *paymentprovider.SubscriptionInfo) ([]SubscriptionDriftField, *billing.UpdateSubscriptionRequest) {
+
+	var (
+		fields []SubscriptionDriftField
+		update = &billing.UpdateSubscriptionRequest{ID: subscription.ID}
+	)
+
+	if info.Status != "" && info.Status != subscription.Status {
+		fields = append(fields, SubscriptionDriftField{Field: "status", Stored: subscription.Status, Provider: info.Status})
+		update.Status = &info.Status
+	}
+
+	if info.PlanID != "" && info.PlanID != subscription.PlanID {
+		fields = append(fields, SubscriptionDriftField{Field: "plan_id", Stored: subscription.PlanID, Provider: info.PlanID})
+		update.PlanID = &info.PlanID
+
+		if info.PlanName != "" {
+			update.PlanName = &info.PlanName
+		}
+	}
+
+	if info.BillingInterval != "" && info.BillingInterval != subscription.BillingInterval {
+		fields = append(fields, SubscriptionDriftField{Field: "billing_interval", Stored: subscription.BillingInterval, Provider: info.BillingInterval})
+		update.BillingInterval = &info.BillingInterval
+	}
+
+	if info.Quantity > 0 && info.Quantity != subscription.Quantity {
+		fields = append(fields, SubscriptionDriftField{Field: "quantity", Stored: strconv.FormatInt(subscription.Quantity, 10), Provider: strconv.FormatInt(info.Quantity, 10)})
+		update.Quantity = &info.Quantity
+	}
+
+	// Providers keep reporting the last period end for ended subscriptions, which is not a billing date
+	if info.Status != billing.StatusCancelled && info.Status != billing.StatusExpired {
+		if nextBillingDate := parseTimeOrNil(info.NextBillingDate); nextBillingDate != nil && !isSameSecond(subscription.NextBillingDate, nextBillingDate) {
+			fields = append(fields, SubscriptionDriftField{Field: "next_billing_date", Stored: formatDriftTime(subscription.NextBillingDate), Provider: formatDriftTime(nextBillingDate)})
+			update.NextBillingDate = nextBillingDate
+		}
+	}
+
+	if info.Status == billing.StatusCancelled {
+		if availableUntilDate := parseTimeOrNil(info.CurrentPeriodEnd); availableUntilDate != nil && !isSameSecond(subscription.AvailableUntilDate, availableUntilDate) {
+			fields = append(fields, SubscriptionDriftField{Field: "available_until_date", Stored: formatDriftTime(subscription.AvailableUntilDate), Provider: formatDriftTime(availableUntilDate)})
+			update.AvailableUntilDate = availableUntilDate
+		}
+	}
+
+	if cancelledAt := parseTimeOrNil(info.CancelledAt); cancelledAt != nil && !isSameSecond(subscription.ProviderCancelledAt, cancelledAt) {
+		fields = append(fields, SubscriptionDriftField{Field: "cancelled_at", Stored: formatDriftTime(subscription.ProviderCancelledAt), Provider: formatDriftTime(cancelledAt)})
+		update.CancelledAt = cancelledAt
+	}
+
+	return fields, update
+}
+
+// reconciliationDueAt returns the earliest of a subscription's next billing and access end
+// dates. Subscriptions with neither are checked last.
+func reconciliationDueAt(subscription *billing.Subscription) time.Time {
+	dueAt := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	if subscription.NextBillingDate != nil && subscription.NextBillingDate.Before(dueAt) {
+		dueAt = *subscription.NextBillingDate
+	}
+
+	if subscription.AvailableUntilDate != nil && subscription.AvailableUntilDate.Before(dueAt) {
+		dueAt = *subscription.AvailableUntilDate
+	}
+
+	return dueAt
+}
+
+// normaliseReconciliationLimit applies the default and maximum number of subscriptions per run
+func normaliseReconciliationLimit(limit int) int {
+	if limit <= 0 {
+		return defaultReconciliationLimit
+	}
+
+	if limit > maxReconciliationLimit {
+		return maxReconciliationLimit
+	}
+
+	return limit
+}
+
+// isSameSecond reports whether a stored time matches the provider's, ignoring sub-second precision
+func isSameSecond(stored *time.Time, provider *time.Time) bool {
+	if stored == nil || provider == nil {
+		return stored == provider
+	}
+
+	return stored.Truncate(time.Second).Equal(provider.Truncate(time.Second))
+}
+
+// formatDriftTime formats a time for a drift report, returning empty when it is not set
+func formatDriftTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,188 @@
+package billingmanager
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"go.uber.org/zap"
+)
+
+// GetWebhookDeliveries lists webhook deliveries held in the inbox. Failed
+// deliveries are returned unless other statuses are requested.
+func (s *Service) GetWebhookDeliveries(ctx context.Context, req *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error) {
+	if s.WebhookInboxService == nil {
+		return nil, ErrBillingManagerWebhookInboxServiceNotSet
+	}
+
+	statuses := req.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{billing.WebhookDeliveryStatusFailed}
+	}
+
+	deliveriesResp, err := s.WebhookInboxService.GetWebhookDeliveries(ctx, &billing.GetWebhookDeliveriesRequest{
+		Order:          req.Order,
+		PerPage:        req.PerPage,
+		Page:           req.Page,
+		IntegratorName: req.ProviderName,
+		Statuses:       statuses,
+	})
+	if err != nil {
+		logger.AcquireOperationFrom(ctx, "external/billingmanager", "get-webhook-deliveries").Error("failed-to-get-webhook-deliveries", zap.Strings("statuses", statuses), zap.Error(err))
+		return nil, err
+	}
+
+	return &GetWebhookDeliveriesResponse{
+		Deliveries: deliveriesResp.WebhookDeliveries,
+		Total:      deliveriesResp.Total,
+		TotalPages: deliveriesResp.TotalPages,
+		PerPage:    deliveriesResp.PerPage,
+		Page:       deliveriesResp.Page,
+	}, nil
+}
+
+// ReplayWebhookDelivery reprocesses a webhook delivery from its stored payload.
+// Deliveries that were already processed successfully are not replayed.
+func (s *Service) ReplayWebhookDelivery(ctx context.Context, req *ReplayWebhookDeliveryRequest) (*ReplayWebhookDeliveryResponse, error) {
+	if s.WebhookInboxService == nil {
+		return nil, ErrBillingManagerWebhookInboxServiceNotSet
+	}
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager").With(
+		zap.String("operation", "replay-webhook-delivery"),
+		zap.String("delivery-id", req.DeliveryID),
+	)
+
+	deliveryResp, err := s.WebhookInboxService.GetWebhookDeliveryByID(ctx, &billing.GetWebhookDeliveryByIDRequest{ID: req.DeliveryID})
+	if err != nil {
+		logger.Warn("failed-to-get-webhook-delivery-for-replay", zap.Error(err))
+		return nil, err
+	}
+
+	delivery := deliveryResp.Delivery
+	if delivery.IsProcessed() {
+		return nil, ErrBillingManagerWebhookDeliveryAlreadyProcessed
+	}
+
+	var payload paymentprovider.WebhookPayload
+	if err := json.Unmarshal([]byte(delivery.Payload), &payload); err != nil {
+		logger.Error("failed-to-decode-webhook-delivery-payload", zap.Error(err))
+		return nil, ErrBillingManagerInvalidWebhookDeliveryPayload
+	}
+
+	logger.Info("replaying-webhook-delivery", zap.String("provider", delivery.Integrator), zap.Int("attempts", delivery.Attempts))
+
+	updatedDelivery, err := s.processWebhookDelivery(ctx, delivery, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayWebhookDeliveryResponse{Delivery: updatedDelivery}, nil
+}
+
+// recordWebhookDelivery stores a verified webhook in the inbox. skip is true
+// when the provider event was already processed successfully, so provider
+// retries do not apply it twice.
+func (s *Service) recordWebhookDelivery(ctx context.Context, providerName string, payload *paymentprovider.WebhookPayload) (delivery *billing.WebhookDelivery, skip bool, err error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager")
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error("failed-to-encode-webhook-payload-for-inbox", append(webhookPayloadFieldsForLog(providerName, "", payload), zap.Error(err))...)
+		return nil, false, err
+	}
+
+	eventTime := parseTimeOrNil(payload.EventTime)
+	if eventTime == nil {
+		now := time.Now()
+		eventTime = &now
+	}
+
+	recordResp, err := s.WebhookInboxService.RecordWebhookDelivery(ctx, &billing.RecordWebhookDeliveryRequest{
+		Integrator:               providerName,
+		IntegratorEventID:        payload.EventID,
+		IntegratorSubscriptionID: payload.SubscriptionID,
+		EventType:                payload.EventType,
+		Payload:                  string(encodedPayload),
+		EventTime:                *eventTime,
+	})
+	if err != nil {
+		logger.Error("failed-to-record-webhook-delivery", append(webhookPayloadFieldsForLog(providerName, "", payload), zap.Error(err))...)
+		return nil, false, err
+	}
+
+	if recordResp.AlreadyRecorded && recordResp.Delivery.IsProcessed() {
+		logger.Info("skipping-duplicate-webhook-delivery", append(webhookPayloadFieldsForLog(providerName, "", payload), zap.String("delivery-id", recordResp.Delivery.ID))...)
+		return recordResp.Delivery, true, nil
+	}
+
+	return recordResp.Delivery, false, nil
+}
+
+// processWebhookDelivery claims an inbox delivery, processes its webhook payload and
+// records the outcome against it. billing.ErrBillingWebhookDeliveryAlreadyClaimed is
+// returned when another worker holds the delivery or already processed it, otherwise
+// the processing error, if any, is returned.
+func (s *Service) processWebhookDelivery(ctx context.Context, delivery *billing.WebhookDelivery, payload *paymentprovider.WebhookPayload) (*billing.WebhookDelivery, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/billingmanager")
+
+	if _, err := s.WebhookInboxService.ClaimWebhookDelivery(ctx, &billing.ClaimWebhookDeliveryRequest{ID: delivery.ID}); err != nil {
+		return nil, err
+	}
+
+	outcomeReq := &billing.UpdateWebhookDeliveryOutcomeRequest{
+		ID:     delivery.ID,
+		Status: billing.WebhookDeliveryStatusProcessed,
+	}
+
+	processErr := s.processWebhookPayload(ctx, delivery.Integrator, payload)
+	if processErr != nil {
+		outcomeReq.Status = billing.WebhookDeliveryStatusFailed
+		outcomeReq.LastError = processErr.Error()
+	}
+
+	outcomeResp, err := s.WebhookInboxService.UpdateWebhookDeliveryOutcome(ctx, outcomeReq)
+	if err != nil {
+		logger.Error("failed-to-update-webhook-delivery-outcome", zap.String("delivery-id", delivery.ID), zap.String("status", outcomeReq.Status), zap.Error(err))
+		if processErr != nil {
+			return nil, processErr
+		}
+		return nil, err
+	}
+
+	return outcomeResp.Delivery, processErr
+}
+
+// isStaleSubscriptionEvent reports whether the payload happened at the provider
+// before the latest event already applied to the subscription
+func isStaleSubscriptionEvent(subscription *billing.Subscription, payload *paymentprovider.WebhookPayload) bool {
+	if subscription.LastProviderEventTime == nil {
+		return false
+	}
+
+	eventTime := parseTimeOrNil(payload.EventTime)
+	if eventTime == nil {
+		return false
+	}
+
+	return eventTime.Before(*subscription.LastProviderEventTime)
+}
+
+// validWebhookDeliveryStatuses lists the statuses accepted when filtering the inbox
+var validWebhookDeliveryStatuses = []string{
+	billing.WebhookDeliveryStatusReceived,
+	billing.WebhookDeliveryStatusProcessing,
+	billing.WebhookDeliveryStatusProcessed,
+	billing.WebhookDeliveryStatusFailed,
+}
+
+// isValidWebhookDeliveryStatus checks a requested inbox status filter
+func isValidWebhookDeliveryStatus(status string) bool {
+	return slices.Contains(validWebhookDeliveryStatuses, status)
+}
@@ -0,0 +1,290 @@
+package billingmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+type adminTestRegistry struct {
+	staticWebhookRegistry
+	changes []*paymentprovider.SubscriptionChangeRequest
+	refunds []*paymentprovider.RefundRequest
+}
+
+func (r *adminTestRegistry) CancelSubscription(ctx context.Context, providerName string, req *paymentprovider.SubscriptionChangeRequest) (*paymentprovider.SubscriptionChange, error) {
+	r.changes = append(r.changes, req)
+	return &paymentprovider.SubscriptionChange{SubscriptionID: req.SubscriptionID, Status: billing.StatusActive, EffectiveAt: "2026-05-01T00:00:00Z"}, nil
+}
+
+func (r *adminTestRegistry) PauseSubscription(ctx context.Context, providerName string, req *paymentprovider.SubscriptionChangeRequest) (*paymentprovider.SubscriptionChange, error) {
+	r.changes = append(r.changes, req)
+	return &paymentprovider.SubscriptionChange{SubscriptionID: req.SubscriptionID, Status: billing.StatusPaused}, nil
+}
+
+func (r *adminTestRegistry) RefundPayment(ctx context.Context, providerName string, req *paymentprovider.RefundRequest) (*paymentprovider.Refund, error) {
+	r.refunds = append(r.refunds, req)
+	return &paymentprovider.Refund{ID: "re_1", TransactionID: req.TransactionID, Amount: req.Amount, Status: "pending"}, nil
+}
+
+// invalidationRecordingEntitlementService records the subjects whose entitlements are invalidated
+type invalidationRecordingEntitlementService struct {
+	invalidated []entitlement.InvalidateEntitlementsRequest
+}
+
+func (e *invalidationRecordingEntitlementService) GetEntitlements(ctx context.Context, req *entitlement.GetEntitlementsRequest) (*entitlement.GetEntitlementsResponse, error) {
+	return &entitlement.GetEntitlementsResponse{}, nil
+}
+
+func (e *invalidationRecordingEntitlementService) InvalidateEntitlements(ctx context.Context, req *entitlement.InvalidateEntitlementsRequest) {
+	e.invalidated = append(e.invalidated, *req)
+}
+
+func (e *invalidationRecordingEntitlementService) CreateGrant(ctx context.Context, req *entitlement.CreateGrantRequest) (*entitlement.CreateGrantResponse, error) {
+	return &entitlement.CreateGrantResponse{}, nil
+}
+
+func (e *invalidationRecordingEntitlementService) GetGrants(ctx context.Context, req *entitlement.GetGrantsRequest) (*entitlement.GetGrantsResponse, error) {
+	return &entitlement.GetGrantsResponse{}, nil
+}
+
+func (e *invalidationRecordingEntitlementService) RevokeGrant(ctx context.Context, req *entitlement.RevokeGrantRequest) (*entitlement.RevokeGrantResponse, error) {
+	return &entitlement.RevokeGrantResponse{}, nil
+}
+
+// invalidatedGroup reports whether the group's entitlements were invalidated
+func (e *invalidationRecordingEntitlementService) invalidatedGroup(groupID string) bool {
+	for _, req := range e.invalidated {
+		if req.SubjectType == entitlement.SubjectTypeGroup && req.SubjectID == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+func newAdminTestService(t *testing.T) (*Service, *adminTestRegistry, *billing.InMemoryRepositoryStore) {
+	t.Helper()
+
+	service, _, store := newWebhookInboxTestService(t)
+	registry := &adminTestRegistry{}
+	service.ProviderRegistry = registry
+
+	return service, registry, store
+}
+
+func TestCancelSubscriptionAtPeriodEndSchedulesTheChangeUntilTheProviderConfirms(t *testing.T) {
+	service, registry, store := newAdminTestService(t)
+
+	store.Subscriptions["sub-1"] = &billing.Subscription{
+		ID:                       "sub-1",
+		UserID:                   "user-1",
+		Integrator:               "stripe",
+		IntegratorSubscriptionID: "sub_123",
+		Status:                   billing.StatusActive,
+		PlanName:                 "Pro",
+	}
+
+	response, err := service.CancelSubscription(context.Background(), &ChangeSubscriptionRequest{SubscriptionID: "sub-1", AtPeriodEnd: true, RequestingUserID: "admin-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(registry.changes) != 1 || registry.changes[0].SubscriptionID != "sub_123" || !registry.changes[0].AtPeriodEnd {
+		t.Fatalf("expected the provider to be asked to cancel at period end, got %+v", registry.changes)
+	}
+
+	scheduled := response.Subscription.ScheduledChange
+	if response.Subscription.Status != billing.StatusActive || scheduled == nil || scheduled.Action != billing.ScheduledChangeActionCancel || scheduled.EffectiveAt == nil {
+		t.Fatalf("expected an active subscription with a scheduled cancellation, got %+v", response.Subscription)
+	}
+
+	registry.payload = subscriptionWebhookPayload("evt-1", "2026-05-01T00:00:00Z", billing.StatusCancelled)
+	registry.payload.SubscriptionID = "sub_123"
+	registry.payload.EventType = paymentprovider.EventTypeSubscriptionCancelled
+	registry.payload.UserID = "user-1"
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if stored := store.Subscriptions["sub-1"]; stored.Status != billing.StatusCancelled || stored.ScheduledChange != nil {
+		t.Fatalf("expected the scheduled cancellation to be cleared once applied, got %+v", stored)
+	}
+
+	_, err = service.PauseSubscription(context.Background(), &ChangeSubscriptionRequest{SubscriptionID: "sub-1"})
+	if !errors.Is(err, ErrBillingManagerSubscriptionNotChangeable) {
+		t.Fatalf("expected %v, got %v", ErrBillingManagerSubscriptionNotChangeable, err)
+	}
+}
+
+func TestCancelSubscriptionRequiresProviderSupport(t *testing.T) {
+	service, _, store := newWebhookInboxTestService(t)
+
+	store.Subscriptions["sub-1"] = &billing.Subscription{ID: "sub-1", Integrator: "stripe", IntegratorSubscriptionID: "sub_123", Status: billing.StatusActive}
+
+	_, err := service.CancelSubscription(context.Background(), &ChangeSubscriptionRequest{SubscriptionID: "sub-1"})
+	if !errors.Is(err, ErrBillingManagerSubscriptionManagementNotSupported) {
+		t.Fatalf("expected %v, got %v", ErrBillingManagerSubscriptionManagementNotSupported, err)
+	}
+}
+
+func TestRefundBillingEvent(t *testing.T) {
+	service, registry, store := newAdminTestService(t)
+
+	store.Events["evt-paid"] = &billing.BillingEvent{
+		ID:                      "evt-paid",
+		EventType:               paymentprovider.EventTypePaymentSucceeded,
+		Integrator:              "stripe",
+		IntegratorTransactionID: "pi_123",
+		Amount:                  1200,
+		Currency:                "GBP",
+	}
+	store.Events["evt-created"] = &billing.BillingEvent{
+		ID:         "evt-created",
+		EventType:  paymentprovider.EventTypeSubscriptionCreated,
+		Integrator: "stripe",
+	}
+
+	tests := []struct {
+		name    string
+		request *RefundBillingEventRequest
+		wantErr error
+	}{
+		{
+			name:    "Failed - not a payment",
+			request: &RefundBillingEventRequest{BillingEventID: "evt-created"},
+			wantErr: ErrBillingManagerBillingEventNotRefundable,
+		},
+		{
+			name:    "Failed - more than was paid",
+			request: &RefundBillingEventRequest{BillingEventID: "evt-paid", Amount: 1500},
+			wantErr: ErrBillingManagerInvalidRefundAmount,
+		},
+		{
+			name:    "Success - partial refund",
+			request: &RefundBillingEventRequest{BillingEventID: "evt-paid", Amount: 500, Reason: "Goodwill"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := service.RefundBillingEvent(context.Background(), test.request)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("expected %v, got %v", test.wantErr, err)
+			}
+			if test.wantErr != nil {
+				return
+			}
+
+			if response.Refund.ID != "re_1" || response.Refund.TransactionID != "pi_123" {
+				t.Fatalf("unexpected refund %+v", response.Refund)
+			}
+		})
+	}
+
+	if len(registry.refunds) != 1 || registry.refunds[0].Amount != 500 || registry.refunds[0].Reason != "Goodwill" {
+		t.Fatalf("expected a single partial refund to be requested, got %+v", registry.refunds)
+	}
+}
+
+func TestRefundBillingEventRequiresProviderSupport(t *testing.T) {
+	service, _, _ := newWebhookInboxTestService(t)
+
+	_, err := service.RefundBillingEvent(context.Background(), &RefundBillingEventRequest{BillingEventID: "evt-paid"})
+	if !errors.Is(err, ErrBillingManagerRefundsNotSupported) {
+		t.Fatalf("expected %v, got %v", ErrBillingManagerRefundsNotSupported, err)
+	}
+}
+
+func TestGrantComplimentarySubscriptionCountsTowardsRevenueMetricsWithoutRevenue(t *testing.T) {
+	service, registry, store := newAdminTestService(t)
+
+	store.Subscriptions["sub-paid"] = &billing.Subscription{
+		ID:              "sub-paid",
+		UserID:          "user-2",
+		Integrator:      "stripe",
+		Status:          billing.StatusActive,
+		PlanName:        "Pro",
+		Amount:          12000,
+		Currency:        "GBP",
+		BillingInterval: "year",
+		CreatedAt:       time.Now().Add(-90 * 24 * time.Hour).Format(time.RFC3339Nano),
+	}
+
+	_, err := service.GrantComplimentarySubscription(context.Background(), &GrantComplimentarySubscriptionRequest{})
+	if !errors.Is(err, ErrBillingManagerInvalidComplimentarySubscription) {
+		t.Fatalf("expected %v without a subscriber, got %v", ErrBillingManagerInvalidComplimentarySubscription, err)
+	}
+
+	response, err := service.GrantComplimentarySubscription(context.Background(), &GrantComplimentarySubscriptionRequest{
+		UserID:           "user-1",
+		PlanName:         "Pro",
+		AvailableUntil:   time.Now().Add(30 * 24 * time.Hour).Format(time.RFC3339),
+		RequestingUserID: "admin-1",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	granted := response.Subscription
+	if !granted.IsComplimentary() || granted.Status != billing.StatusActive || granted.AvailableUntilDate == nil {
+		t.Fatalf("unexpected complimentary subscription %+v", granted)
+	}
+
+	metrics, err := service.GetRevenueMetrics(context.Background(), &GetRevenueMetricsRequest{GetRevenueMetricsRequest: &billing.GetRevenueMetricsRequest{}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if metrics.ActiveSubscriptions != 2 || metrics.ComplimentarySubscriptions != 1 || metrics.ActiveSubscriptionsByPlan["Pro"] != 2 {
+		t.Fatalf("unexpected active subscription counts %+v", metrics.GetRevenueMetricsResponse)
+	}
+	if metrics.MonthlyRecurringRevenue["GBP"] != 1000 {
+		t.Fatalf("expected only the paid subscription to count towards MRR, got %v", metrics.MonthlyRecurringRevenue)
+	}
+
+	if _, err := service.CancelSubscription(context.Background(), &ChangeSubscriptionRequest{SubscriptionID: granted.ID, AtPeriodEnd: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(registry.changes) != 0 {
+		t.Fatalf("expected complimentary subscriptions not to be changed on a provider, got %+v", registry.changes)
+	}
+	if stored := store.Subscriptions[granted.ID]; stored.Status != billing.StatusCancelled || !stored.AvailableUntilDate.Equal(*granted.AvailableUntilDate) {
+		t.Fatalf("expected the cancelled complimentary subscription to keep access until it ends, got %+v", stored)
+	}
+}
+
+func TestGrantComplimentarySubscriptionToGroupInvalidatesGroupEntitlements(t *testing.T) {
+	service, _, _ := newAdminTestService(t)
+	entitlements := &invalidationRecordingEntitlementService{}
+	service.EntitlementService = entitlements
+
+	response, err := service.GrantComplimentarySubscription(context.Background(), &GrantComplimentarySubscriptionRequest{
+		GroupID:          "group-1",
+		PlanName:         "Team",
+		Quantity:         5,
+		RequestingUserID: "admin-1",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !response.Subscription.IsGroupOwned() {
+		t.Fatalf("expected a group-owned complimentary subscription, got %+v", response.Subscription)
+	}
+	if !entitlements.invalidatedGroup("group-1") {
+		t.Fatalf("expected the group's entitlements to be invalidated on grant, got %+v", entitlements.invalidated)
+	}
+
+	entitlements.invalidated = nil
+	if _, err := service.CancelSubscription(context.Background(), &ChangeSubscriptionRequest{SubscriptionID: response.Subscription.ID}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !entitlements.invalidatedGroup("group-1") {
+		t.Fatalf("expected the group's entitlements to be invalidated on cancel, got %+v", entitlements.invalidated)
+	}
+}
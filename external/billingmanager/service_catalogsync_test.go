@@ -0,0 +1,278 @@
+package billingmanager_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ooaklee/ghatd/external/billingmanager"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+// fakeStripeCatalog serves Stripe's product and price endpoints from memory
+type fakeStripeCatalog struct {
+	mu       sync.Mutex
+	products map[string]map[string]interface{}
+	prices   map[string]map[string]interface{}
+	writes   []string
+}
+
+func (f *fakeStripeCatalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		f.writes = append(f.writes, r.URL.Path)
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	collection := f.products
+	if len(parts) > 1 && parts[1] == "prices" {
+		collection = f.prices
+	}
+
+	var object map[string]interface{}
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 3:
+		object = collection[parts[2]]
+	case r.Method == http.MethodPost && len(parts) == 3:
+		object = collection[parts[2]]
+		if object != nil && r.PostForm.Get("active") != "" {
+			object["active"] = r.PostForm.Get("active") == "true"
+		}
+		if object != nil && r.PostForm.Get("name") != "" {
+			object["name"] = r.PostForm.Get("name")
+			object["description"] = r.PostForm.Get("description")
+		}
+	case r.Method == http.MethodPost && parts[1] == "products":
+		object = map[string]interface{}{
+			"id":          fmt.Sprintf("prod_%d", len(f.products)+1),
+			"name":        r.PostForm.Get("name"),
+			"description": r.PostForm.Get("description"),
+			"active":      r.PostForm.Get("active") == "true",
+		}
+		collection[object["id"].(string)] = object
+	case r.Method == http.MethodPost && parts[1] == "prices":
+		var amount int64
+		fmt.Sscan(r.PostForm.Get("unit_amount"), &amount)
+		object = map[string]interface{}{
+			"id":          fmt.Sprintf("price_%d", len(f.prices)+1),
+			"product":     r.PostForm.Get("product"),
+			"unit_amount": amount,
+			"currency":    r.PostForm.Get("currency"),
+			"active":      true,
+		}
+		if interval := r.PostForm.Get("recurring[interval]"); interval != "" {
+			object["recurring"] = map[string]interface{}{"interval": interval, "interval_count": 1}
+		}
+		collection[object["id"].(string)] = object
+	}
+
+	if object == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(object)
+}
+
+// catalogSyncTestPricer serves a fixed price plan and records provider references written back
+type catalogSyncTestPricer struct {
+	mockBillingManagerPricerService
+	plans   []pricer.PricePlan
+	updates []*pricer.UpdatePricePlanRequest
+}
+
+func (p *catalogSyncTestPricer) GetPricePlans(ctx context.Context, req *pricer.GetPricePlansRequest) (*pricer.GetPricePlansResponse, error) {
+	return &pricer.GetPricePlansResponse{PricePlans: p.plans, Total: len(p.plans), TotalPages: 1, Page: 1, PerPage: req.PerPage}, nil
+}
+
+func (p *catalogSyncTestPricer) UpdatePricePlan(ctx context.Context, req *pricer.UpdatePricePlanRequest) (*pricer.UpdatePricePlanResponse, error) {
+	p.updates = append(p.updates, req)
+	return &pricer.UpdatePricePlanResponse{}, nil
+}
+
+func newCatalogSyncTestService(t *testing.T, catalog *fakeStripeCatalog, plans ...pricer.PricePlan) (*billingmanager.Service, *catalogSyncTestPricer) {
+	t.Helper()
+
+	server := httptest.NewServer(catalog)
+	t.Cleanup(server.Close)
+
+	registry, err := paymentprovider.CreateRegistryFromConfigs([]*paymentprovider.Config{{
+		ProviderName:  "stripe",
+		APIKey:        "sk_test_123",
+		WebhookSecret: "whsec_123",
+		APIBaseURL:    server.URL,
+	}})
+	if err != nil {
+		t.Fatalf("expected no error creating registry, got %v", err)
+	}
+
+	pricerService := &catalogSyncTestPricer{plans: plans}
+	service := billingmanager.NewService(registry, nil)
+	service.PricerService = pricerService
+
+	return service, pricerService
+}
+
+func catalogSyncTestPlan() pricer.PricePlan {
+	return pricer.PricePlan{
+		ID:     "plan-1",
+		Slug:   "pro",
+		Name:   "Pro",
+		Status: pricer.PricePlanStatusPublished,
+		ProviderRefs: []pricer.PriceProviderRef{
+			{Provider: pricer.PriceProviderStripe, ProviderProductID: "prod_pro"},
+		},
+		Costs: []pricer.PriceCost{
+			{
+				ID:             "cost-monthly",
+				Amount:         2000,
+				Currency:       "GBP",
+				BillingCadence: pricer.PriceBillingCadenceMonthly,
+				ProviderRefs: []pricer.PriceProviderRef{
+					{Provider: pricer.PriceProviderStripe, ProviderPriceID: "price_pro_monthly"},
+				},
+			},
+			{
+				ID:             "cost-yearly",
+				Amount:         20000,
+				Currency:       "GBP",
+				BillingCadence: pricer.PriceBillingCadenceYearly,
+			},
+		},
+	}
+}
+
+func newCatalogSyncTestCatalog() *fakeStripeCatalog {
+	return &fakeStripeCatalog{
+		products: map[string]map[string]interface{}{
+			"prod_pro": {"id": "prod_pro", "name": "Pro", "description": "", "active": true},
+		},
+		prices: map[string]map[string]interface{}{
+			"price_pro_monthly": {
+				"id": "price_pro_monthly", "product": "prod_pro", "unit_amount": 1500, "currency": "gbp", "active": true,
+				"recurring": map[string]interface{}{"interval": "month", "interval_count": 1},
+			},
+		},
+	}
+}
+
+func TestServiceSyncPriceCatalogDryRunReportsDrift(t *testing.T) {
+	catalog := newCatalogSyncTestCatalog()
+	service, pricerService := newCatalogSyncTestService(t, catalog, catalogSyncTestPlan())
+
+	response, err := service.SyncPriceCatalog(context.Background(), &billingmanager.SyncPriceCatalogRequest{
+		ProviderName: "stripe",
+		DryRun:       true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	report := response.Report
+	if report.Checked != 3 || report.InSync != 1 || report.Changed != 2 || report.Applied != 0 {
+		t.Fatalf("unexpected report counts %+v", report)
+	}
+
+	if report.Changes[0].Action != billingmanager.CatalogSyncActionReplace || report.Changes[0].Fields[0].Field != "amount" {
+		t.Fatalf("expected monthly price to be replaced for its amount, got %+v", report.Changes[0])
+	}
+	if report.Changes[1].Action != billingmanager.CatalogSyncActionCreate || report.Changes[1].CostID != "cost-yearly" {
+		t.Fatalf("expected yearly price to be created, got %+v", report.Changes[1])
+	}
+
+	if len(catalog.writes) != 0 || len(pricerService.updates) != 0 {
+		t.Fatalf("expected dry run to change nothing, got writes %v and %d updates", catalog.writes, len(pricerService.updates))
+	}
+}
+
+func TestServiceSyncPriceCatalogAppliesChangesAndWritesBackRefs(t *testing.T) {
+	catalog := newCatalogSyncTestCatalog()
+	service, pricerService := newCatalogSyncTestService(t, catalog, catalogSyncTestPlan())
+
+	response, err := service.SyncPriceCatalog(context.Background(), &billingmanager.SyncPriceCatalogRequest{
+		ProviderName:     "stripe",
+		RequestingUserID: "admin-1",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if response.Report.Applied != 2 || response.Report.Failed != 0 {
+		t.Fatalf("unexpected report counts %+v", response.Report)
+	}
+
+	if active := catalog.prices["price_pro_monthly"]["active"]; active != false {
+		t.Fatalf("expected old monthly price to be archived, got active=%v", active)
+	}
+
+	if len(pricerService.updates) != 1 {
+		t.Fatalf("expected provider refs to be written back once, got %d", len(pricerService.updates))
+	}
+
+	update := pricerService.updates[0]
+	if update.ID != "plan-1" || update.UserID != "admin-1" {
+		t.Fatalf("unexpected update request %+v", update)
+	}
+
+	monthlyRef := update.Costs[0].ProviderRefs[0]
+	if monthlyRef.ProviderPriceID == "price_pro_monthly" || catalog.prices[monthlyRef.ProviderPriceID]["unit_amount"] != int64(2000) {
+		t.Fatalf("expected monthly cost to point at a new 2000 price, got %+v", monthlyRef)
+	}
+
+	yearlyRefs := update.Costs[1].ProviderRefs
+	if len(yearlyRefs) != 1 || yearlyRefs[0].Provider != pricer.PriceProviderStripe || yearlyRefs[0].ProviderProductID != "prod_pro" {
+		t.Fatalf("expected yearly cost to gain a stripe ref, got %+v", yearlyRefs)
+	}
+	if recurring := catalog.prices[yearlyRefs[0].ProviderPriceID]["recurring"].(map[string]interface{}); recurring["interval"] != "year" {
+		t.Fatalf("expected yearly price to recur yearly, got %v", recurring)
+	}
+}
+
+func TestServiceSyncPriceCatalogRequiresCatalogSupport(t *testing.T) {
+	service := billingmanager.NewService(&webhookOnlyRegistry{}, nil)
+	service.PricerService = &catalogSyncTestPricer{}
+
+	_, err := service.SyncPriceCatalog(context.Background(), &billingmanager.SyncPriceCatalogRequest{ProviderName: "stripe"})
+	if !errors.Is(err, billingmanager.ErrBillingManagerCatalogSyncNotSupported) {
+		t.Fatalf("expected %v, got %v", billingmanager.ErrBillingManagerCatalogSyncNotSupported, err)
+	}
+}
+
+func TestCatalogSyncCommandPrintsReport(t *testing.T) {
+	service, _ := newCatalogSyncTestService(t, newCatalogSyncTestCatalog(), catalogSyncTestPlan())
+
+	command := billingmanager.NewCatalogSyncCommand(func(ctx context.Context) (billingmanager.PriceCatalogSyncer, error) {
+		return service, nil
+	})
+
+	var output bytes.Buffer
+	command.SetOut(&output)
+	command.SetArgs([]string{"--provider", "stripe", "--plan", "pro", "--dry-run"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var report billingmanager.CatalogSyncReport
+	if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("expected json report, got %v: %s", err, output.String())
+	}
+	if !report.DryRun || report.ProviderName != "stripe" || report.Changed != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,403 @@
+package billingmanager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/billingmanager"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+// checkoutTestRegistry records checkout requests and returns a fixed webhook payload
+type checkoutTestRegistry struct {
+	payload          *paymentprovider.WebhookPayload
+	checkoutProvider string
+	checkoutRequest  *paymentprovider.CheckoutSessionRequest
+	portalProvider   string
+	portalRequest    *paymentprovider.CustomerPortalSessionRequest
+}
+
+func (r *checkoutTestRegistry) VerifyAndParseWebhookPayload(ctx context.Context, providerName string, req *http.Request) (*paymentprovider.WebhookPayload, error) {
+	payload := *r.payload
+	return &payload, nil
+}
+
+func (r *checkoutTestRegistry) CreateCheckoutSession(ctx context.Context, providerName string, req *paymentprovider.CheckoutSessionRequest) (*paymentprovider.CheckoutSession, error) {
+	r.checkoutProvider = providerName
+	r.checkoutRequest = req
+	return &paymentprovider.CheckoutSession{ID: "cs_1", URL: "https://checkout.example.com/cs_1"}, nil
+}
+
+func (r *checkoutTestRegistry) CreateCustomerPortalSession(ctx context.Context, providerName string, req *paymentprovider.CustomerPortalSessionRequest) (*paymentprovider.CustomerPortalSession, error) {
+	r.portalProvider = providerName
+	r.portalRequest = req
+	return &paymentprovider.CustomerPortalSession{URL: "https://portal.example.com/" + req.CustomerID}, nil
+}
+
+func newCheckoutTestService(registry billingmanager.ProviderRegistry, plan *pricer.PricePlan) (*billingmanager.Service, *billing.InMemoryRepositoryStore) {
+	store := &billing.InMemoryRepositoryStore{
+		Subscriptions: map[string]*billing.Subscription{},
+		Events:        map[string]*billing.BillingEvent{},
+	}
+	repository := billing.NewInMemoryRepository(store)
+
+	service := billingmanager.NewService(registry, billing.NewService(repository, repository)).WithPricerService(&mockBillingManagerPricerService{
+		getPricePlanBySlugFunc: func(ctx context.Context, req *pricer.GetPricePlanBySlugRequest) (*pricer.GetPricePlanBySlugResponse, error) {
+			if plan == nil || req.Slug != plan.Slug {
+				return nil, pricer.ErrPricePlanNotFound
+			}
+			return &pricer.GetPricePlanBySlugResponse{GetPricePlanResponse: &pricer.GetPricePlanResponse{PricePlan: plan}}, nil
+		},
+	})
+
+	return service, store
+}
+
+func publishedCheckoutPlan() *pricer.PricePlan {
+	return &pricer.PricePlan{
+		Slug:        "pro",
+		PublishedAt: "2026-01-01T00:00:00Z",
+		Costs: []pricer.PriceCost{
+			{
+				ID:             "cost-monthly",
+				BillingCadence: pricer.PriceBillingCadenceMonthly,
+				ProviderRefs: []pricer.PriceProviderRef{
+					{Provider: pricer.PriceProviderStripe, ProviderPriceID: "price_monthly"},
+				},
+			},
+			{
+				ID:              "cost-yearly",
+				BillingCadence:  pricer.PriceBillingCadenceYearly,
+				TrialPeriodDays: 14,
+				ProviderRefs: []pricer.PriceProviderRef{
+					{Provider: pricer.PriceProviderStripe, ProviderPriceID: "price_yearly"},
+					{Provider: pricer.PriceProviderLemonSqueezy, ProviderID: "1001"},
+				},
+			},
+		},
+	}
+}
+
+func TestServiceCreateCheckoutSessionUsesPlanProviderPrice(t *testing.T) {
+	t.Parallel()
+
+	registry := &checkoutTestRegistry{}
+	service, _ := newCheckoutTestService(registry, publishedCheckoutPlan())
+
+	response, err := service.CreateCheckoutSession(context.Background(), &billingmanager.CreateCheckoutSessionRequest{
+		UserID:         "user-1",
+		ProviderName:   "stripe",
+		PlanSlug:       "pro",
+		BillingCadence: "year",
+		SuccessURL:     "https://app.example.com/success",
+		CouponCode:     "LAUNCH",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if response.CheckoutSession.URL != "https://checkout.example.com/cs_1" {
+		t.Fatalf("unexpected checkout session %#v", response.CheckoutSession)
+	}
+	if registry.checkoutProvider != "stripe" {
+		t.Fatalf("expected stripe provider, got %q", registry.checkoutProvider)
+	}
+
+	got := registry.checkoutRequest
+	if got.PriceID != "price_yearly" || got.UserID != "user-1" || got.TrialPeriodDays != 14 || got.CouponCode != "LAUNCH" {
+		t.Fatalf("unexpected checkout request %#v", got)
+	}
+
+	_, err = service.CreateCheckoutSession(context.Background(), &billingmanager.CreateCheckoutSessionRequest{
+		UserID:       "user-1",
+		ProviderName: "lemonsqueezy",
+		PlanSlug:     "pro",
+		SuccessURL:   "https://app.example.com/success",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if registry.checkoutRequest.PriceID != "1001" {
+		t.Fatalf("expected lemon squeezy variant from provider ID, got %q", registry.checkoutRequest.PriceID)
+	}
+}
+
+func TestServiceCreateCheckoutSessionRejectsUnavailablePlans(t *testing.T) {
+	t.Parallel()
+
+	draftPlan := publishedCheckoutPlan()
+	draftPlan.PublishedAt = ""
+
+	tests := []struct {
+		name     string
+		registry billingmanager.ProviderRegistry
+		plan     *pricer.PricePlan
+		provider string
+		wantErr  error
+	}{
+		{
+			name:     "Unpublished plan",
+			registry: &checkoutTestRegistry{},
+			plan:     draftPlan,
+			provider: "stripe",
+			wantErr:  pricer.ErrPricePlanNotFound,
+		},
+		{
+			name:     "No price on provider",
+			registry: &checkoutTestRegistry{},
+			plan:     publishedCheckoutPlan(),
+			provider: "kofi",
+			wantErr:  billingmanager.ErrBillingManagerNoProviderPriceForPlan,
+		},
+		{
+			name:     "Registry without checkout support",
+			registry: &webhookOnlyRegistry{},
+			plan:     publishedCheckoutPlan(),
+			provider: "stripe",
+			wantErr:  billingmanager.ErrBillingManagerCheckoutNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newCheckoutTestService(tt.registry, tt.plan)
+
+			_, err := service.CreateCheckoutSession(context.Background(), &billingmanager.CreateCheckoutSessionRequest{
+				UserID:       "user-1",
+				ProviderName: tt.provider,
+				PlanSlug:     "pro",
+				SuccessURL:   "https://app.example.com/success",
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestServiceCreateCustomerPortalSessionUsesLatestSubscriptionCustomer(t *testing.T) {
+	t.Parallel()
+
+	registry := &checkoutTestRegistry{}
+	service, store := newCheckoutTestService(registry, nil)
+
+	_, err := service.CreateCustomerPortalSession(context.Background(), &billingmanager.CreateCustomerPortalSessionRequest{UserID: "user-1"})
+	if !errors.Is(err, billingmanager.ErrBillingManagerNoProviderCustomerForUser) {
+		t.Fatalf("expected %v, got %v", billingmanager.ErrBillingManagerNoProviderCustomerForUser, err)
+	}
+
+	store.Subscriptions["sub-1"] = &billing.Subscription{
+		ID:                   "sub-1",
+		UserID:               "user-1",
+		Integrator:           "stripe",
+		IntegratorCustomerID: "cus_123",
+	}
+
+	response, err := service.CreateCustomerPortalSession(context.Background(), &billingmanager.CreateCustomerPortalSessionRequest{
+		UserID:    "user-1",
+		ReturnURL: "https://app.example.com/billing",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if response.PortalSession.URL != "https://portal.example.com/cus_123" || registry.portalProvider != "stripe" {
+		t.Fatalf("unexpected portal session %#v for provider %q", response.PortalSession, registry.portalProvider)
+	}
+	if registry.portalRequest.ReturnURL != "https://app.example.com/billing" {
+		t.Fatalf("expected return url to be passed, got %q", registry.portalRequest.ReturnURL)
+	}
+}
+
+func TestProcessBillingProviderWebhooksUsesCheckoutUserMetadata(t *testing.T) {
+	t.Parallel()
+
+	registry := &checkoutTestRegistry{
+		payload: &paymentprovider.WebhookPayload{
+			EventType:      paymentprovider.EventTypeSubscriptionCreated,
+			EventID:        "evt-1",
+			EventTime:      "2026-01-01T10:00:00Z",
+			PaymentType:    paymentprovider.PaymentTypeSubscription,
+			SubscriptionID: "sub_1",
+			CustomerID:     "cus_123",
+			CustomerEmail:  "billing@example.com",
+			UserID:         "user-1",
+			Status:         paymentprovider.SubscriptionStatusActive,
+			PlanName:       "Pro",
+		},
+	}
+	service, store := newCheckoutTestService(registry, nil)
+
+	err := service.ProcessBillingProviderWebhooks(context.Background(), &billingmanager.ProcessBillingProviderWebhooksRequest{
+		ProviderName: "stripe",
+		Request:      httptest.NewRequest(http.MethodPost, "/api/v1/bms/billings/stripe/webhooks", nil),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(store.Subscriptions) != 1 {
+		t.Fatalf("expected 1 subscription, got %d", len(store.Subscriptions))
+	}
+	for _, subscription := range store.Subscriptions {
+		if subscription.UserID != "user-1" {
+			t.Fatalf("expected subscription linked to user from metadata, got %q", subscription.UserID)
+		}
+	}
+}
+
+// webhookOnlyRegistry only supports webhook processing
+type webhookOnlyRegistry struct{}
+
+func (r *webhookOnlyRegistry) VerifyAndParseWebhookPayload(ctx context.Context, providerName string, req *http.Request) (*paymentprovider.WebhookPayload, error) {
+	return nil, paymentprovider.ErrPaymentProviderInvalidPayload
+}
+
+// promotionCodeTestPricerService is a pricer service that validates and redeems a single promotion code
+type promotionCodeTestPricerService struct {
+	*mockBillingManagerPricerService
+	promotionCode   *pricer.PricePromotionCode
+	validateRequest *pricer.ValidatePromotionCodeRequest
+	redeemRequests  []*pricer.RedeemPromotionCodeRequest
+}
+
+func (m *promotionCodeTestPricerService) ValidatePromotionCode(ctx context.Context, req *pricer.ValidatePromotionCodeRequest) (*pricer.ValidatePromotionCodeResponse, error) {
+	m.validateRequest = req
+	if req.Code != m.promotionCode.Code {
+		return &pricer.ValidatePromotionCodeResponse{Code: req.Code, Reason: pricer.PricePromotionCodeReasonNotFound}, nil
+	}
+	return &pricer.ValidatePromotionCodeResponse{Code: req.Code, Valid: true, PromotionCode: m.promotionCode}, nil
+}
+
+func (m *promotionCodeTestPricerService) RedeemPromotionCode(ctx context.Context, req *pricer.RedeemPromotionCodeRequest) (*pricer.RedeemPromotionCodeResponse, error) {
+	m.redeemRequests = append(m.redeemRequests, req)
+	return &pricer.RedeemPromotionCodeResponse{Redemption: &pricer.PricePromotionCodeRedemption{Code: req.Code, SubscriptionID: req.SubscriptionID}}, nil
+}
+
+func newPromotionCodeTestService(registry billingmanager.ProviderRegistry, plan *pricer.PricePlan) (*billingmanager.Service, *promotionCodeTestPricerService) {
+	pricerService := &promotionCodeTestPricerService{
+		mockBillingManagerPricerService: &mockBillingManagerPricerService{
+			getPricePlanBySlugFunc: func(ctx context.Context, req *pricer.GetPricePlanBySlugRequest) (*pricer.GetPricePlanBySlugResponse, error) {
+				return &pricer.GetPricePlanBySlugResponse{GetPricePlanResponse: &pricer.GetPricePlanResponse{PricePlan: plan}}, nil
+			},
+		},
+		promotionCode: &pricer.PricePromotionCode{
+			ID:   "promo-1",
+			Code: "SPRING25",
+			ProviderRefs: []pricer.PriceProviderRef{
+				{Provider: pricer.PriceProviderStripe, ProviderID: "coupon_spring"},
+			},
+		},
+	}
+
+	repository := billing.NewInMemoryRepository(&billing.InMemoryRepositoryStore{
+		Subscriptions: map[string]*billing.Subscription{},
+		Events:        map[string]*billing.BillingEvent{},
+	})
+
+	return billingmanager.NewService(registry, billing.NewService(repository, repository)).WithPricerService(pricerService), pricerService
+}
+
+func TestServiceCreateCheckoutSessionAppliesPromotionCode(t *testing.T) {
+	t.Parallel()
+
+	plan := publishedCheckoutPlan()
+	plan.ID = "plan-pro"
+
+	tests := []struct {
+		name          string
+		provider      string
+		promotionCode string
+		couponCode    string
+		wantErr       error
+	}{
+		{name: "Success - code is applied as the provider coupon", provider: "stripe", promotionCode: " spring25 "},
+		{name: "Failure - code cannot be redeemed", provider: "stripe", promotionCode: "UNKNOWN", wantErr: pricer.ErrPricePromotionCodeNotRedeemable},
+		{name: "Failure - code is not mapped to the provider", provider: "lemonsqueezy", promotionCode: "SPRING25", wantErr: billingmanager.ErrBillingManagerPromotionCodeNotMappedToProvider},
+		{name: "Failure - code used with a coupon code", provider: "stripe", promotionCode: "SPRING25", couponCode: "LAUNCH", wantErr: billingmanager.ErrInvalidBillingManagerRequestPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := &checkoutTestRegistry{}
+			service, pricerService := newPromotionCodeTestService(registry, plan)
+
+			_, err := service.CreateCheckoutSession(context.Background(), &billingmanager.CreateCheckoutSessionRequest{
+				UserID:         "user-1",
+				ProviderName:   tt.provider,
+				PlanSlug:       "pro",
+				BillingCadence: "year",
+				SuccessURL:     "https://app.example.com/success",
+				CouponCode:     tt.couponCode,
+				PromotionCode:  tt.promotionCode,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if registry.checkoutRequest != nil {
+					t.Fatalf("expected no checkout session to be created, got %#v", registry.checkoutRequest)
+				}
+				return
+			}
+
+			validated := pricerService.validateRequest
+			if validated.UserID != "user-1" || validated.PricePlanID != "plan-pro" || validated.BillingCadence != pricer.PriceBillingCadenceYearly {
+				t.Fatalf("unexpected validate request %#v", validated)
+			}
+
+			got := registry.checkoutRequest
+			if got.CouponCode != "coupon_spring" || got.PromotionCode != "SPRING25" {
+				t.Fatalf("unexpected checkout request %#v", got)
+			}
+		})
+	}
+}
+
+func TestProcessBillingProviderWebhooksRedeemsPromotionCode(t *testing.T) {
+	t.Parallel()
+
+	registry := &checkoutTestRegistry{
+		payload: &paymentprovider.WebhookPayload{
+			EventType:      paymentprovider.EventTypeSubscriptionCreated,
+			EventID:        "evt-1",
+			EventTime:      "2026-01-01T10:00:00Z",
+			PaymentType:    paymentprovider.PaymentTypeSubscription,
+			SubscriptionID: "sub_1",
+			CustomerID:     "cus_123",
+			UserID:         "user-1",
+			PromotionCode:  "SPRING25",
+			Status:         paymentprovider.SubscriptionStatusActive,
+		},
+	}
+	service, pricerService := newPromotionCodeTestService(registry, nil)
+
+	for i := 0; i < 2; i++ {
+		err := service.ProcessBillingProviderWebhooks(context.Background(), &billingmanager.ProcessBillingProviderWebhooksRequest{
+			ProviderName: "stripe",
+			Request:      httptest.NewRequest(http.MethodPost, "/api/v1/bms/billings/stripe/webhooks", nil),
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if len(pricerService.redeemRequests) != 2 {
+		t.Fatalf("expected a redeem request per webhook, got %d", len(pricerService.redeemRequests))
+	}
+
+	first, second := pricerService.redeemRequests[0], pricerService.redeemRequests[1]
+	if first.Code != "SPRING25" || first.UserID != "user-1" || first.SubscriptionID == "" {
+		t.Fatalf("unexpected redeem request %#v", first)
+	}
+	if second.SubscriptionID != first.SubscriptionID {
+		t.Fatalf("expected retried webhook to redeem for the same subscription, got %q and %q", first.SubscriptionID, second.SubscriptionID)
+	}
+}
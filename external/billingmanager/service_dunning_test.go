@@ -0,0 +1,176 @@
+package billingmanager_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/billingmanager"
+	"github.com/ooaklee/ghatd/external/emailmanager"
+	"github.com/ooaklee/ghatd/external/notifier"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+type dunningTestEmailManager struct {
+	sent []*emailmanager.SendCustomEmailRequest
+}
+
+func (m *dunningTestEmailManager) SendCustomEmail(ctx context.Context, req *emailmanager.SendCustomEmailRequest) error {
+	m.sent = append(m.sent, req)
+	return nil
+}
+
+type dunningTestNotifierService struct {
+	sent []*notifier.NotifyUserRequest
+}
+
+func (m *dunningTestNotifierService) NotifyUser(ctx context.Context, req *notifier.NotifyUserRequest) (*notifier.NotifyUserResponse, error) {
+	m.sent = append(m.sent, req)
+	return &notifier.NotifyUserResponse{}, nil
+}
+
+type dunningTestAuditService struct {
+	actions []audit.AuditAction
+}
+
+func (m *dunningTestAuditService) LogAuditEvent(ctx context.Context, r *audit.LogAuditEventRequest) error {
+	m.actions = append(m.actions, r.Action)
+	return nil
+}
+
+func newDunningTestService(subscriptions ...*billing.Subscription) (*billingmanager.Service, *billing.InMemoryRepositoryStore, *dunningTestEmailManager, *dunningTestNotifierService, *dunningTestAuditService) {
+	service, store := newReconciliationTestService(&reconciliationTestRegistry{}, subscriptions...)
+
+	emailManager := &dunningTestEmailManager{}
+	notifierService := &dunningTestNotifierService{}
+	auditService := &dunningTestAuditService{}
+
+	service.WithDunning(billingmanager.DefaultDunningConfig()).
+		WithEmailManager(emailManager).
+		WithNotifierService(notifierService).
+		WithAuditService(auditService)
+
+	return service, store, emailManager, notifierService, auditService
+}
+
+func TestServiceProcessDunningStartsDunningAndSendsFirstReminder(t *testing.T) {
+	subscription := reconciliationTestSubscription("sub-1", "stripe", billing.StatusPastDue, time.Now().Add(24*time.Hour))
+	subscription.UpdateURL = "https://billing.example.com/update"
+	service, store, emailManager, notifierService, auditService := newDunningTestService(subscription)
+
+	response, err := service.ProcessDunning(context.Background(), &billingmanager.ProcessDunningRequest{})
+	if err != nil {
+		t.Fatalf("ProcessDunning() error = %v", err)
+	}
+
+	if response.Report.Checked != 1 || response.Report.Started != 1 || response.Report.RemindersSent != 1 || response.Report.Suspended != 0 {
+		t.Fatalf("unexpected report: %+v", response.Report)
+	}
+
+	dunning := store.Subscriptions["sub-1"].Dunning
+	if dunning == nil || dunning.StepsCompleted != 1 || dunning.SuspendedAt != nil {
+		t.Fatalf("unexpected dunning state: %+v", dunning)
+	}
+	if !store.Subscriptions["sub-1"].IsInDunningGracePeriod() {
+		t.Fatalf("expected subscription to be in its grace period")
+	}
+
+	if len(emailManager.sent) != 1 || emailManager.sent[0].EmailTo != "sub-1@example.com" || !strings.Contains(emailManager.sent[0].EmailBody, subscription.UpdateURL) {
+		t.Fatalf("expected one reminder email linking to the update URL, got %+v", emailManager.sent)
+	}
+	if len(notifierService.sent) != 1 || notifierService.sent[0].UserID != "user-sub-1" {
+		t.Fatalf("expected one in-app reminder, got %+v", notifierService.sent)
+	}
+	if len(auditService.actions) != 2 || auditService.actions[0] != billingmanager.AuditActionBillingDunningStarted || auditService.actions[1] != billingmanager.AuditActionBillingDunningReminderSent {
+		t.Fatalf("unexpected audit actions: %v", auditService.actions)
+	}
+
+	// A second run straight away has no further reminder due
+	response, err = service.ProcessDunning(context.Background(), &billingmanager.ProcessDunningRequest{})
+	if err != nil {
+		t.Fatalf("ProcessDunning() error = %v", err)
+	}
+	if response.Report.RemindersSent != 0 || len(emailManager.sent) != 1 {
+		t.Fatalf("expected no repeated reminder, got report %+v and %d emails", response.Report, len(emailManager.sent))
+	}
+}
+
+func TestServiceProcessDunningSuspendsAfterGracePeriod(t *testing.T) {
+	startedAt := time.Now().Add(-15 * 24 * time.Hour)
+	lastStepAt := startedAt.Add(7 * 24 * time.Hour)
+	subscription := reconciliationTestSubscription("sub-1", "stripe", billing.StatusUnpaid, time.Now())
+	subscription.Dunning = &billing.SubscriptionDunning{
+		StartedAt:         startedAt,
+		StepsCompleted:    3,
+		LastStepAt:        &lastStepAt,
+		GracePeriodEndsAt: startedAt.Add(14 * 24 * time.Hour),
+	}
+	service, store, emailManager, _, auditService := newDunningTestService(subscription)
+
+	response, err := service.ProcessDunning(context.Background(), &billingmanager.ProcessDunningRequest{})
+	if err != nil {
+		t.Fatalf("ProcessDunning() error = %v", err)
+	}
+
+	if response.Report.Suspended != 1 || response.Report.RemindersSent != 0 {
+		t.Fatalf("unexpected report: %+v", response.Report)
+	}
+
+	stored := store.Subscriptions["sub-1"]
+	if stored.Dunning == nil || stored.Dunning.SuspendedAt == nil || stored.IsInDunningGracePeriod() {
+		t.Fatalf("expected subscription to be suspended, got %+v", stored.Dunning)
+	}
+	if len(emailManager.sent) != 1 || !strings.Contains(emailManager.sent[0].EmailSubject, "suspended") {
+		t.Fatalf("expected a suspension email, got %+v", emailManager.sent)
+	}
+	if len(auditService.actions) != 1 || auditService.actions[0] != billingmanager.AuditActionBillingDunningSuspended {
+		t.Fatalf("unexpected audit actions: %v", auditService.actions)
+	}
+
+	// Suspended subscriptions are not progressed again
+	response, err = service.ProcessDunning(context.Background(), &billingmanager.ProcessDunningRequest{})
+	if err != nil {
+		t.Fatalf("ProcessDunning() error = %v", err)
+	}
+	if response.Report.Checked != 0 {
+		t.Fatalf("expected suspended subscription to be skipped, got %+v", response.Report)
+	}
+}
+
+func TestServiceReconcileSubscriptionsEndsDunningWhenPaymentRecovers(t *testing.T) {
+	subscription := reconciliationTestSubscription("sub-1", "stripe", billing.StatusPastDue, time.Now().Add(24*time.Hour))
+	subscription.Dunning = &billing.SubscriptionDunning{
+		StartedAt:         time.Now().Add(-2 * 24 * time.Hour),
+		StepsCompleted:    1,
+		GracePeriodEndsAt: time.Now().Add(12 * 24 * time.Hour),
+	}
+	service, store, _, _, auditService := newDunningTestService(subscription)
+	service.ProviderRegistry = &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{
+		"provider-sub-1": {Status: paymentprovider.SubscriptionStatusActive},
+	}}
+
+	if _, err := service.ReconcileSubscriptions(context.Background(), &billingmanager.ReconcileSubscriptionsRequest{}); err != nil {
+		t.Fatalf("ReconcileSubscriptions() error = %v", err)
+	}
+
+	stored := store.Subscriptions["sub-1"]
+	if stored.Status != billing.StatusActive || stored.Dunning != nil {
+		t.Fatalf("expected active subscription without dunning, got status %q and dunning %+v", stored.Status, stored.Dunning)
+	}
+	if len(auditService.actions) == 0 || auditService.actions[0] != billingmanager.AuditActionBillingDunningRecovered {
+		t.Fatalf("expected dunning recovery to be audited, got %v", auditService.actions)
+	}
+}
+
+func TestServiceProcessDunningRequiresConfiguration(t *testing.T) {
+	service, _ := newReconciliationTestService(&reconciliationTestRegistry{})
+
+	_, err := service.ProcessDunning(context.Background(), &billingmanager.ProcessDunningRequest{})
+	if !errors.Is(err, billingmanager.ErrBillingManagerDunningNotConfigured) {
+		t.Fatalf("ProcessDunning() error = %v, want %v", err, billingmanager.ErrBillingManagerDunningNotConfigured)
+	}
+}
@@ -0,0 +1,181 @@
+package billingmanager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ooaklee/ghatd/external/billingmanager"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+// groupTestService keeps groups in memory for group billing tests
+type groupTestService struct {
+	groups map[string]*group.UniversalGroup
+}
+
+func (g *groupTestService) GetGroupByID(ctx context.Context, req *group.GetGroupByIDRequest) (*group.GetGroupByIDResponse, error) {
+	grp, ok := g.groups[req.ID]
+	if !ok {
+		return nil, group.ErrResourceNotFound
+	}
+	copied := *grp
+	if grp.Settings != nil {
+		settings := *grp.Settings
+		copied.Settings = &settings
+	}
+	return &group.GetGroupByIDResponse{Group: &copied}, nil
+}
+
+func (g *groupTestService) UpdateGroup(ctx context.Context, req *group.UpdateGroupRequest) (*group.UpdateGroupResponse, error) {
+	g.groups[req.ID] = req.Group
+	return &group.UpdateGroupResponse{Group: req.Group}, nil
+}
+
+func newGroupTestService() *groupTestService {
+	return &groupTestService{
+		groups: map[string]*group.UniversalGroup{
+			"group-1": {
+				ID:      "group-1",
+				OwnerID: "owner-1",
+				Members: []group.Member{
+					{ID: "owner-1", Type: group.MemberTypeUser, Role: group.MemberRoleOwner},
+					{ID: "billing-1", Type: group.MemberTypeUser, Role: group.MemberRoleBillingAdmin},
+					{ID: "member-1", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+				},
+			},
+		},
+	}
+}
+
+func TestServiceCreateCheckoutSessionForGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		userID           string
+		quantity         int64
+		expectedQuantity int64
+		expectedErr      error
+	}{
+		{name: "Success - billing admin defaults quantity to member count", userID: "billing-1", expectedQuantity: 3},
+		{name: "Success - owner keeps requested quantity", userID: "owner-1", quantity: 10, expectedQuantity: 10},
+		{name: "Failure - regular member", userID: "member-1", expectedErr: billingmanager.ErrBillingManagerUserNotGroupBillingAdmin},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := &checkoutTestRegistry{}
+			service, _ := newCheckoutTestService(registry, publishedCheckoutPlan())
+			service.WithGroupService(newGroupTestService())
+
+			_, err := service.CreateCheckoutSession(context.Background(), &billingmanager.CreateCheckoutSessionRequest{
+				UserID:       test.userID,
+				GroupID:      "group-1",
+				ProviderName: "stripe",
+				PlanSlug:     "pro",
+				Quantity:     test.quantity,
+				SuccessURL:   "https://app.example.com/success",
+			})
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			got := registry.checkoutRequest
+			if got.GroupID != "group-1" || got.Quantity != test.expectedQuantity {
+				t.Fatalf("unexpected checkout request %#v", got)
+			}
+		})
+	}
+}
+
+func TestServiceCreateCheckoutSessionForGroupRequiresGroupService(t *testing.T) {
+	t.Parallel()
+
+	service, _ := newCheckoutTestService(&checkoutTestRegistry{}, publishedCheckoutPlan())
+
+	_, err := service.CreateCheckoutSession(context.Background(), &billingmanager.CreateCheckoutSessionRequest{
+		UserID:       "owner-1",
+		GroupID:      "group-1",
+		ProviderName: "stripe",
+		PlanSlug:     "pro",
+		SuccessURL:   "https://app.example.com/success",
+	})
+	if !errors.Is(err, billingmanager.ErrBillingManagerGroupServiceNotSet) {
+		t.Fatalf("expected group service not set error, got %v", err)
+	}
+}
+
+func TestProcessBillingProviderWebhooksSyncsGroupSeats(t *testing.T) {
+	t.Parallel()
+
+	registry := &checkoutTestRegistry{
+		payload: &paymentprovider.WebhookPayload{
+			EventType:      paymentprovider.EventTypeSubscriptionCreated,
+			EventID:        "evt-1",
+			EventTime:      "2026-01-01T10:00:00Z",
+			PaymentType:    paymentprovider.PaymentTypeSubscription,
+			SubscriptionID: "sub_1",
+			CustomerID:     "cus_123",
+			CustomerEmail:  "billing@example.com",
+			UserID:         "billing-1",
+			GroupID:        "group-1",
+			Quantity:       5,
+			Status:         paymentprovider.SubscriptionStatusActive,
+			PlanName:       "Team",
+		},
+	}
+	service, store := newCheckoutTestService(registry, nil)
+	groups := newGroupTestService()
+	service.WithGroupService(groups)
+
+	processWebhook := func() {
+		t.Helper()
+		err := service.ProcessBillingProviderWebhooks(context.Background(), &billingmanager.ProcessBillingProviderWebhooksRequest{
+			ProviderName: "stripe",
+			Request:      httptest.NewRequest(http.MethodPost, "/api/v1/bms/billings/stripe/webhooks", nil),
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	processWebhook()
+
+	for _, subscription := range store.Subscriptions {
+		if subscription.GroupID != "group-1" || subscription.Quantity != 5 {
+			t.Fatalf("expected group-owned subscription with 5 seats, got %#v", subscription)
+		}
+	}
+	if seats := groups.groups["group-1"].Settings.MaxMembers; seats != 5 {
+		t.Fatalf("expected group max members of 5, got %d", seats)
+	}
+
+	// Seat changes on the provider are applied to the group
+	registry.payload.EventType = paymentprovider.EventTypeSubscriptionUpdated
+	registry.payload.EventID = "evt-2"
+	registry.payload.EventTime = "2026-01-02T10:00:00Z"
+	registry.payload.Quantity = 8
+	processWebhook()
+
+	if seats := groups.groups["group-1"].Settings.MaxMembers; seats != 8 {
+		t.Fatalf("expected group max members of 8, got %d", seats)
+	}
+
+	// Cancelling the only subscription falls back to the unpaid seat limit
+	registry.payload.EventType = paymentprovider.EventTypeSubscriptionCancelled
+	registry.payload.EventID = "evt-3"
+	registry.payload.EventTime = "2026-01-03T10:00:00Z"
+	registry.payload.Status = paymentprovider.SubscriptionStatusCancelled
+	processWebhook()
+
+	if seats := groups.groups["group-1"].Settings.MaxMembers; seats != billingmanager.DefaultGroupSeatsWithoutSubscription {
+		t.Fatalf("expected group max members of %d, got %d", billingmanager.DefaultGroupSeatsWithoutSubscription, seats)
+	}
+}
@@ -0,0 +1,152 @@
+package billingmanager
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ooaklee/ghatd/external/emailmanager"
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	user "github.com/ooaklee/ghatd/external/user/v2"
+)
+
+type invoiceTestEmailManager struct {
+	sent []*emailmanager.SendCustomEmailRequest
+}
+
+func (m *invoiceTestEmailManager) SendCustomEmail(ctx context.Context, req *emailmanager.SendCustomEmailRequest) error {
+	m.sent = append(m.sent, req)
+	return nil
+}
+
+type invoiceTestUserService struct {
+	users map[string]*user.UniversalUser
+}
+
+func (s *invoiceTestUserService) GetUserByEmail(context.Context, *user.GetUserByEmailRequest) (*user.GetUserByEmailResponse, error) {
+	return nil, user.ErrUserNotFound
+}
+
+func (s *invoiceTestUserService) GetUserByID(ctx context.Context, req *user.GetUserByIDRequest) (*user.GetUserByIDResponse, error) {
+	if found, ok := s.users[req.ID]; ok {
+		return &user.GetUserByIDResponse{User: found}, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func newInvoiceTestService(t *testing.T) (*Service, *staticWebhookRegistry, *invoiceTestEmailManager) {
+	t.Helper()
+
+	service, registry, _ := newWebhookInboxTestService(t)
+	emailManager := &invoiceTestEmailManager{}
+	userService := &invoiceTestUserService{users: map[string]*user.UniversalUser{
+		"user-1": {
+			ID:           "user-1",
+			Email:        "jane@example.com",
+			PersonalInfo: &user.PersonalInfo{FullName: "Jane Doe"},
+			BillingAddress: &user.BillingAddress{
+				Line1:   "2 Low Road",
+				City:    "Leeds",
+				Country: "GB",
+				TaxID:   "GB987654321",
+			},
+		},
+	}}
+
+	service.WithUserService(userService).
+		WithEmailManager(emailManager).
+		WithInvoiceService(invoice.NewService(invoice.NewInMemoryRepository(), invoice.BusinessEntity{Name: "Acme Ltd", InvoicePrefix: "ACME"})).
+		WithInvoiceEmails()
+
+	return service, registry, emailManager
+}
+
+func paymentSucceededWebhookPayload(eventID string, userID string) *paymentprovider.WebhookPayload {
+	return &paymentprovider.WebhookPayload{
+		EventType:     paymentprovider.EventTypePaymentSucceeded,
+		EventID:       eventID,
+		EventTime:     "2026-03-20T12:00:00Z",
+		PaymentType:   paymentprovider.PaymentTypeShopOrder,
+		UserID:        userID,
+		CustomerEmail: "jane@example.com",
+		Amount:        1200,
+		Currency:      "GBP",
+		PlanName:      "Pro (Monthly)",
+	}
+}
+
+func TestProcessBillingProviderWebhooksIssuesAndEmailsInvoiceForPayments(t *testing.T) {
+	service, registry, emailManager := newInvoiceTestService(t)
+
+	registry.payload = paymentSucceededWebhookPayload("evt-1", "user-1")
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	registry.payload = paymentSucceededWebhookPayload("evt-1", "user-1")
+	registry.payload.EventType = paymentprovider.EventTypePaymentFailed
+	registry.payload.EventID = "evt-2"
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	listResponse, err := service.GetMyInvoices(context.Background(), &GetMyInvoicesRequest{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(listResponse.Invoices) != 1 {
+		t.Fatalf("expected one invoice for the successful payment, got %d", len(listResponse.Invoices))
+	}
+
+	issued := listResponse.Invoices[0]
+	if issued.Number != "ACME-000001" || issued.Customer.Name != "Jane Doe" || issued.Customer.TaxID != "GB987654321" || issued.Customer.Address.City != "Leeds" {
+		t.Fatalf("expected invoice billed to the user's billing address, got %+v", issued)
+	}
+	if issued.EmailedAt == "" {
+		t.Fatalf("expected invoice to be marked as emailed")
+	}
+
+	if len(emailManager.sent) != 1 {
+		t.Fatalf("expected one invoice email, got %d", len(emailManager.sent))
+	}
+	sent := emailManager.sent[0]
+	if sent.EmailTo != "jane@example.com" || sent.EmailSubject != "Your invoice ACME-000001" || !strings.Contains(sent.EmailBody, "GBP 12.00") {
+		t.Fatalf("unexpected invoice email %+v", sent)
+	}
+}
+
+func TestDownloadMyInvoiceOnlyReturnsTheUsersOwnInvoices(t *testing.T) {
+	service, registry, _ := newInvoiceTestService(t)
+
+	registry.payload = paymentSucceededWebhookPayload("evt-1", "user-1")
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	listResponse, _ := service.GetMyInvoices(context.Background(), &GetMyInvoicesRequest{UserID: "user-1"})
+	invoiceID := listResponse.Invoices[0].ID
+
+	downloadResponse, err := service.DownloadMyInvoice(context.Background(), &DownloadMyInvoiceRequest{UserID: "user-1", InvoiceID: invoiceID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if downloadResponse.ContentType != "application/pdf" || downloadResponse.FileName != "invoice-ACME-000001.pdf" {
+		t.Fatalf("expected a pdf download, got %q %q", downloadResponse.ContentType, downloadResponse.FileName)
+	}
+
+	_, err = service.DownloadMyInvoice(context.Background(), &DownloadMyInvoiceRequest{UserID: "user-2", InvoiceID: invoiceID})
+	if !errors.Is(err, invoice.ErrInvoiceNotFound) {
+		t.Fatalf("expected %v for another user's invoice, got %v", invoice.ErrInvoiceNotFound, err)
+	}
+}
+
+func TestGetMyInvoicesRequiresInvoiceService(t *testing.T) {
+	service, _, _ := newWebhookInboxTestService(t)
+
+	_, err := service.GetMyInvoices(context.Background(), &GetMyInvoicesRequest{UserID: "user-1"})
+	if !errors.Is(err, ErrBillingManagerInvoiceServiceNotSet) {
+		t.Fatalf("expected %v, got %v", ErrBillingManagerInvoiceServiceNotSet, err)
+	}
+}
@@ -0,0 +1,177 @@
+package billingmanager_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/billingmanager"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+// planVersionTestPricer keeps a fixed set of price plan versions
+type planVersionTestPricer struct {
+	versions  map[string]*pricer.PricePlanVersion
+	completed map[string]int
+}
+
+func (p *planVersionTestPricer) GetPricePlans(ctx context.Context, req *pricer.GetPricePlansRequest) (*pricer.GetPricePlansResponse, error) {
+	return &pricer.GetPricePlansResponse{}, nil
+}
+
+func (p *planVersionTestPricer) GetPricePlanBySlug(ctx context.Context, req *pricer.GetPricePlanBySlugRequest) (*pricer.GetPricePlanBySlugResponse, error) {
+	return nil, pricer.ErrPricePlanNotFound
+}
+
+func (p *planVersionTestPricer) GetFeatures(ctx context.Context, req *pricer.GetFeaturesRequest) (*pricer.GetFeaturesResponse, error) {
+	return &pricer.GetFeaturesResponse{}, nil
+}
+
+func (p *planVersionTestPricer) FindPricePlanVersionByProviderPrice(ctx context.Context, req *pricer.FindPricePlanVersionByProviderPriceRequest) (*pricer.GetPricePlanVersionResponse, error) {
+	var found *pricer.PricePlanVersion
+	for _, version := range p.versions {
+		if version.HasProviderPrice(req.Provider, req.ProviderPriceID) && (found == nil || version.Version > found.Version) {
+			found = version
+		}
+	}
+	if found == nil {
+		return nil, pricer.ErrPricePlanVersionNotFound
+	}
+
+	return &pricer.GetPricePlanVersionResponse{PricePlanVersion: found}, nil
+}
+
+func (p *planVersionTestPricer) GetPricePlanVersionByID(ctx context.Context, req *pricer.GetPricePlanVersionByIDRequest) (*pricer.GetPricePlanVersionResponse, error) {
+	version, ok := p.versions[req.ID]
+	if !ok {
+		return nil, pricer.ErrPricePlanVersionNotFound
+	}
+
+	return &pricer.GetPricePlanVersionResponse{PricePlanVersion: version}, nil
+}
+
+func (p *planVersionTestPricer) GetDuePricePlanVersionMigrations(ctx context.Context, req *pricer.GetDuePricePlanVersionMigrationsRequest) (*pricer.GetDuePricePlanVersionMigrationsResponse, error) {
+	response := &pricer.GetDuePricePlanVersionMigrationsResponse{}
+	for _, version := range p.versions {
+		if version.IsMigrationDue(time.Now()) {
+			response.PricePlanVersions = append(response.PricePlanVersions, *version)
+		}
+	}
+
+	return response, nil
+}
+
+func (p *planVersionTestPricer) CompletePricePlanVersionMigration(ctx context.Context, req *pricer.CompletePricePlanVersionMigrationRequest) (*pricer.GetPricePlanVersionResponse, error) {
+	p.completed[req.ID] = req.MigratedCount
+	p.versions[req.ID].Grandfathering.MigratedAt = time.Now().UTC().Format(time.RFC3339)
+
+	return &pricer.GetPricePlanVersionResponse{PricePlanVersion: p.versions[req.ID]}, nil
+}
+
+func newPlanVersionTestPricer() *planVersionTestPricer {
+	return &planVersionTestPricer{
+		versions: map[string]*pricer.PricePlanVersion{
+			"version-1": {
+				ID:           "version-1",
+				PricePlanID:  "plan-pro",
+				Version:      1,
+				Name:         "Pro",
+				ProviderRefs: []pricer.PriceProviderRef{{Provider: pricer.PriceProviderStripe, ProviderPriceID: "price_pro"}},
+				SupersededAt: "2026-01-01T00:00:00",
+				Grandfathering: &pricer.PricePlanGrandfathering{
+					Policy:             pricer.PriceGrandfatherPolicyMigrate,
+					MigrateToVersionID: "version-2",
+					MigrateToVersion:   2,
+				},
+			},
+			"version-2": {
+				ID:           "version-2",
+				PricePlanID:  "plan-pro",
+				Version:      2,
+				Name:         "Pro",
+				ProviderRefs: []pricer.PriceProviderRef{{Provider: pricer.PriceProviderStripe, ProviderPriceID: "price_pro_2026"}},
+			},
+		},
+		completed: map[string]int{},
+	}
+}
+
+func TestServiceMigratePricePlanVersionsMovesDueSubscribers(t *testing.T) {
+	onOldVersion := reconciliationTestSubscription("sub-1", "stripe", billing.StatusActive, time.Now().Add(24*time.Hour))
+	onOldVersion.PricePlanID = "plan-pro"
+	onOldVersion.PricePlanVersionID = "version-1"
+	onOldVersion.PricePlanVersion = 1
+	onNewVersion := reconciliationTestSubscription("sub-2", "stripe", billing.StatusActive, time.Now().Add(24*time.Hour))
+	onNewVersion.PricePlanVersionID = "version-2"
+	onNewVersion.PricePlanVersion = 2
+
+	service, store := newReconciliationTestService(&reconciliationTestRegistry{}, onOldVersion, onNewVersion)
+	pricerService := newPlanVersionTestPricer()
+	service.WithPricerService(pricerService)
+
+	response, err := service.MigratePricePlanVersions(context.Background(), &billingmanager.MigratePricePlanVersionsRequest{})
+	if err != nil {
+		t.Fatalf("MigratePricePlanVersions() error = %v", err)
+	}
+
+	if response.Report.Versions != 1 || response.Report.Migrated != 1 || response.Report.Failed != 0 {
+		t.Fatalf("unexpected report: %+v", response.Report)
+	}
+
+	if stored := store.Subscriptions["sub-1"]; stored.PricePlanVersionID != "version-2" || stored.PricePlanVersion != 2 {
+		t.Fatalf("expected subscription to be moved to version 2, got %q (%d)", stored.PricePlanVersionID, stored.PricePlanVersion)
+	}
+	if pricerService.completed["version-1"] != 1 {
+		t.Fatalf("expected version 1 migration to be completed with 1 subscription, got %v", pricerService.completed)
+	}
+
+	var migratedEvents int
+	for _, event := range store.Events {
+		if event.EventType == billingmanager.EventTypeSubscriptionPlanVersionMigrated && event.SubscriptionID == "sub-1" {
+			migratedEvents++
+		}
+	}
+	if migratedEvents != 1 {
+		t.Fatalf("expected one migration billing event, got %d", migratedEvents)
+	}
+
+	// Completed migrations are not run again
+	response, err = service.MigratePricePlanVersions(context.Background(), &billingmanager.MigratePricePlanVersionsRequest{})
+	if err != nil {
+		t.Fatalf("MigratePricePlanVersions() error = %v", err)
+	}
+	if response.Report.Versions != 0 {
+		t.Fatalf("expected no due versions, got %+v", response.Report)
+	}
+}
+
+func TestServiceReconcileSubscriptionsLinksChangedPlanToPricePlanVersion(t *testing.T) {
+	subscription := reconciliationTestSubscription("sub-1", "stripe", billing.StatusActive, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	subscription.PricePlanVersionID = "version-1"
+	registry := &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{
+		"provider-sub-1": {Status: paymentprovider.SubscriptionStatusActive, PlanID: "price_pro_2026"},
+	}}
+
+	service, store := newReconciliationTestService(registry, subscription)
+	service.WithPricerService(newPlanVersionTestPricer())
+
+	if _, err := service.ReconcileSubscriptions(context.Background(), &billingmanager.ReconcileSubscriptionsRequest{}); err != nil {
+		t.Fatalf("ReconcileSubscriptions() error = %v", err)
+	}
+
+	if stored := store.Subscriptions["sub-1"]; stored.PricePlanVersionID != "version-2" || stored.PricePlanID != "plan-pro" {
+		t.Fatalf("expected subscription to be linked to version 2, got %q on plan %q", stored.PricePlanVersionID, stored.PricePlanID)
+	}
+}
+
+func TestServiceMigratePricePlanVersionsRequiresVersioning(t *testing.T) {
+	service, _ := newReconciliationTestService(&reconciliationTestRegistry{})
+
+	_, err := service.MigratePricePlanVersions(context.Background(), &billingmanager.MigratePricePlanVersionsRequest{})
+	if !errors.Is(err, billingmanager.ErrBillingManagerPlanVersioningNotSupported) {
+		t.Fatalf("MigratePricePlanVersions() error = %v, want %v", err, billingmanager.ErrBillingManagerPlanVersioningNotSupported)
+	}
+}
@@ -0,0 +1,248 @@
+package billingmanager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/billingmanager"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+// reconciliationTestRegistry answers subscription lookups from a fixed set of provider views
+type reconciliationTestRegistry struct {
+	subscriptions map[string]*paymentprovider.SubscriptionInfo
+	lookups       []string
+}
+
+func (r *reconciliationTestRegistry) VerifyAndParseWebhookPayload(ctx context.Context, providerName string, req *http.Request) (*paymentprovider.WebhookPayload, error) {
+	return nil, paymentprovider.ErrPaymentProviderInvalidPayload
+}
+
+func (r *reconciliationTestRegistry) GetSubscriptionInfo(ctx context.Context, providerName string, subscriptionID string) (*paymentprovider.SubscriptionInfo, error) {
+	r.lookups = append(r.lookups, subscriptionID)
+
+	if providerName == "kofi" {
+		return nil, paymentprovider.ErrPaymentProviderKofiNoSubscriptionAPI
+	}
+
+	info, ok := r.subscriptions[subscriptionID]
+	if !ok {
+		return nil, paymentprovider.ErrPaymentProviderSubscriptionNotFound
+	}
+
+	return info, nil
+}
+
+func newReconciliationTestService(registry billingmanager.ProviderRegistry, subscriptions ...*billing.Subscription) (*billingmanager.Service, *billing.InMemoryRepositoryStore) {
+	store := &billing.InMemoryRepositoryStore{
+		Subscriptions: map[string]*billing.Subscription{},
+		Events:        map[string]*billing.BillingEvent{},
+	}
+	for _, subscription := range subscriptions {
+		store.Subscriptions[subscription.ID] = subscription
+	}
+	repository := billing.NewInMemoryRepository(store)
+
+	return billingmanager.NewService(registry, billing.NewService(repository, repository)), store
+}
+
+func reconciliationTestSubscription(id, integrator, status string, nextBillingDate time.Time) *billing.Subscription {
+	return &billing.Subscription{
+		ID:                       id,
+		UserID:                   "user-" + id,
+		Email:                    id + "@example.com",
+		Status:                   status,
+		Integrator:               integrator,
+		IntegratorSubscriptionID: "provider-" + id,
+		PlanID:                   "price_pro",
+		PlanName:                 "Pro",
+		BillingInterval:          "month",
+		NextBillingDate:          &nextBillingDate,
+	}
+}
+
+func TestServiceReconcileSubscriptionsRepairsDrift(t *testing.T) {
+	renewsAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	registry := &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{
+		"provider-sub-1": {
+			Status:           paymentprovider.SubscriptionStatusCancelled,
+			PlanID:           "price_pro",
+			BillingInterval:  "month",
+			NextBillingDate:  "2026-11-01T00:00:00Z",
+			CurrentPeriodEnd: "2026-11-01T00:00:00Z",
+			CancelledAt:      "2026-10-10T12:00:00Z",
+		},
+		"provider-sub-2": {
+			Status:          paymentprovider.SubscriptionStatusActive,
+			PlanID:          "price_pro",
+			BillingInterval: "month",
+			NextBillingDate: "2026-11-01T00:00:00Z",
+		},
+	}}
+	service, store := newReconciliationTestService(registry,
+		reconciliationTestSubscription("sub-1", "stripe", billing.StatusActive, renewsAt),
+		reconciliationTestSubscription("sub-2", "stripe", billing.StatusActive, renewsAt),
+	)
+
+	response, err := service.ReconcileSubscriptions(context.Background(), &billingmanager.ReconcileSubscriptionsRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	report := response.Report
+	if report.DryRun || report.Checked != 2 || report.Drifted != 1 || report.Repaired != 1 || report.Failed != 0 {
+		t.Fatalf("unexpected report %#v", report)
+	}
+
+	drift := report.Drifts[0]
+	if drift.SubscriptionID != "sub-1" || !drift.Repaired || drift.BillingEventID == "" {
+		t.Fatalf("unexpected drift %#v", drift)
+	}
+
+	expectedFields := map[string]string{"status": "cancelled", "available_until_date": "2026-11-01T00:00:00Z", "cancelled_at": "2026-10-10T12:00:00Z"}
+	if len(drift.Fields) != len(expectedFields) {
+		t.Fatalf("expected fields %v, got %#v", expectedFields, drift.Fields)
+	}
+	for _, field := range drift.Fields {
+		if expectedFields[field.Field] != field.Provider {
+			t.Errorf("unexpected drift field %#v", field)
+		}
+	}
+
+	repaired := store.Subscriptions["sub-1"]
+	if repaired.Status != billing.StatusCancelled || repaired.AvailableUntilDate == nil || !repaired.AvailableUntilDate.Equal(renewsAt) {
+		t.Fatalf("expected subscription to be repaired, got %#v", repaired)
+	}
+	if repaired.ProviderCancelledAt == nil || repaired.LastProviderEventTime == nil {
+		t.Fatalf("expected cancellation and reconciliation times to be set, got %#v", repaired)
+	}
+	if store.Subscriptions["sub-2"].LastProviderEventTime != nil {
+		t.Error("expected subscription without drift to be left unchanged")
+	}
+
+	event := store.Events[drift.BillingEventID]
+	if event == nil || event.EventType != billingmanager.EventTypeSubscriptionReconciled || event.Status != billing.StatusCancelled || event.SubscriptionID != "sub-1" || event.RawPayload == "" {
+		t.Fatalf("expected reconciliation billing event, got %#v", event)
+	}
+}
+
+func TestServiceGetSubscriptionDriftReportDoesNotRepair(t *testing.T) {
+	registry := &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{
+		"provider-sub-1": {Status: paymentprovider.SubscriptionStatusPastDue},
+	}}
+	service, store := newReconciliationTestService(registry,
+		reconciliationTestSubscription("sub-1", "stripe", billing.StatusActive, time.Now().Add(24*time.Hour)),
+	)
+
+	response, err := service.GetSubscriptionDriftReport(context.Background(), &billingmanager.GetSubscriptionDriftReportRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !response.Report.DryRun || response.Report.Drifted != 1 || response.Report.Repaired != 0 || response.Report.Drifts[0].Repaired {
+		t.Fatalf("unexpected report %#v", response.Report)
+	}
+	if store.Subscriptions["sub-1"].Status != billing.StatusActive || len(store.Events) != 0 {
+		t.Fatal("expected drift report to leave subscriptions and events unchanged")
+	}
+}
+
+func TestServiceReconcileSubscriptionsPrioritisesDueSubscriptions(t *testing.T) {
+	now := time.Now()
+	expiredAccess := now.Add(-time.Hour)
+	cancelled := reconciliationTestSubscription("sub-cancelled", "stripe", billing.StatusCancelled, now.Add(-48*time.Hour))
+	cancelled.AvailableUntilDate = &expiredAccess
+
+	registry := &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{}}
+	service, _ := newReconciliationTestService(registry,
+		reconciliationTestSubscription("sub-later", "stripe", billing.StatusActive, now.Add(30*24*time.Hour)),
+		reconciliationTestSubscription("sub-soon", "stripe", billing.StatusActive, now.Add(time.Hour)),
+		reconciliationTestSubscription("sub-expired", "stripe", billing.StatusExpired, now.Add(-time.Hour)),
+		cancelled,
+	)
+
+	response, err := service.ReconcileSubscriptions(context.Background(), &billingmanager.ReconcileSubscriptionsRequest{Limit: 1, DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(registry.lookups) != 1 || registry.lookups[0] != "provider-sub-soon" {
+		t.Fatalf("expected only the subscription due soonest to be checked, got %v", registry.lookups)
+	}
+	if response.Report.Failed != 1 || response.Report.Failures[0].SubscriptionID != "sub-soon" {
+		t.Fatalf("expected missing provider subscription to be reported as a failure, got %#v", response.Report)
+	}
+}
+
+func TestServiceReconcileSubscriptionsSkipsComplimentarySubscriptions(t *testing.T) {
+	now := time.Now()
+	registry := &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{}}
+	service, _ := newReconciliationTestService(registry,
+		reconciliationTestSubscription("sub-comp", billing.IntegratorManual, billing.StatusActive, now.Add(time.Hour)),
+		reconciliationTestSubscription("sub-paid", "stripe", billing.StatusActive, now.Add(2*time.Hour)),
+	)
+
+	response, err := service.ReconcileSubscriptions(context.Background(), &billingmanager.ReconcileSubscriptionsRequest{Limit: 1, DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(registry.lookups) != 1 || registry.lookups[0] != "provider-sub-paid" {
+		t.Fatalf("expected complimentary subscription not to be looked up or count toward the limit, got %v", registry.lookups)
+	}
+	if response.Report.Failed != 1 || response.Report.Failures[0].SubscriptionID != "sub-paid" {
+		t.Fatalf("expected only the provider-billed subscription in the report, got %#v", response.Report)
+	}
+}
+
+func TestServiceReconcileSubscriptionsSkipsProvidersWithoutSubscriptionAPI(t *testing.T) {
+	registry := &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{}}
+	service, _ := newReconciliationTestService(registry,
+		reconciliationTestSubscription("sub-1", "kofi", billing.StatusActive, time.Now().Add(time.Hour)),
+	)
+
+	response, err := service.ReconcileSubscriptions(context.Background(), &billingmanager.ReconcileSubscriptionsRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if response.Report.Skipped != 1 || response.Report.Checked != 0 || response.Report.Failed != 0 {
+		t.Fatalf("unexpected report %#v", response.Report)
+	}
+}
+
+func TestServiceReconcileSubscriptionsRequiresSubscriptionLookups(t *testing.T) {
+	service, _ := newReconciliationTestService(&webhookOnlyRegistry{})
+
+	_, err := service.ReconcileSubscriptions(context.Background(), &billingmanager.ReconcileSubscriptionsRequest{})
+	if !errors.Is(err, billingmanager.ErrBillingManagerReconciliationNotSupported) {
+		t.Fatalf("expected %v, got %v", billingmanager.ErrBillingManagerReconciliationNotSupported, err)
+	}
+
+	err = service.RunSubscriptionReconciliation(context.Background(), &billingmanager.RunSubscriptionReconciliationRequest{})
+	if !errors.Is(err, billingmanager.ErrBillingManagerReconciliationNotSupported) {
+		t.Fatalf("expected %v, got %v", billingmanager.ErrBillingManagerReconciliationNotSupported, err)
+	}
+}
+
+func TestServiceRunSubscriptionReconciliationStopsWithContext(t *testing.T) {
+	registry := &reconciliationTestRegistry{subscriptions: map[string]*paymentprovider.SubscriptionInfo{}}
+	service, _ := newReconciliationTestService(registry,
+		reconciliationTestSubscription("sub-1", "stripe", billing.StatusActive, time.Now().Add(time.Hour)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := service.RunSubscriptionReconciliation(ctx, &billingmanager.RunSubscriptionReconciliationRequest{Interval: 10 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if len(registry.lookups) < 2 {
+		t.Fatalf("expected reconciliation to run on every interval, got %d lookups", len(registry.lookups))
+	}
+}
@@ -0,0 +1,181 @@
+package billingmanager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	user "github.com/ooaklee/ghatd/external/user/v2"
+)
+
+// staticWebhookRegistry returns whichever payload is currently set, skipping verification
+type staticWebhookRegistry struct {
+	payload *paymentprovider.WebhookPayload
+}
+
+func (r *staticWebhookRegistry) VerifyAndParseWebhookPayload(ctx context.Context, providerName string, _ *http.Request) (*paymentprovider.WebhookPayload, error) {
+	payload := *r.payload
+	return &payload, nil
+}
+
+func newWebhookInboxTestService(t *testing.T) (*Service, *staticWebhookRegistry, *billing.InMemoryRepositoryStore) {
+	t.Helper()
+
+	store := &billing.InMemoryRepositoryStore{
+		Subscriptions: map[string]*billing.Subscription{},
+		Events:        map[string]*billing.BillingEvent{},
+	}
+	repository := billing.NewInMemoryRepository(store)
+	billingService := billing.NewService(repository, repository).WithWebhookDeliveryRepository(repository)
+	registry := &staticWebhookRegistry{}
+
+	return NewService(registry, billingService).WithWebhookInboxService(billingService), registry, store
+}
+
+func subscriptionWebhookPayload(eventID, eventTime, status string) *paymentprovider.WebhookPayload {
+	return &paymentprovider.WebhookPayload{
+		EventType:      paymentprovider.EventTypeSubscriptionUpdated,
+		EventID:        eventID,
+		EventTime:      eventTime,
+		PaymentType:    paymentprovider.PaymentTypeSubscription,
+		SubscriptionID: "sub-1",
+		CustomerEmail:  "customer@example.com",
+		Status:         status,
+		PlanName:       "Pro",
+	}
+}
+
+func processWebhook(t *testing.T, service *Service) error {
+	t.Helper()
+
+	return service.ProcessBillingProviderWebhooks(context.Background(), &ProcessBillingProviderWebhooksRequest{
+		ProviderName: "stripe",
+		Request:      httptest.NewRequest("POST", "/api/v1/bms/billings/stripe/webhooks", nil),
+	})
+}
+
+func TestProcessBillingProviderWebhooksSkipsProcessedDuplicates(t *testing.T) {
+	service, registry, store := newWebhookInboxTestService(t)
+	registry.payload = subscriptionWebhookPayload("evt-1", "2026-01-01T10:00:00Z", billing.StatusActive)
+
+	for range 2 {
+		if err := processWebhook(t, service); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	delivery := store.WebhookDeliveries["stripe:evt-1"]
+	if delivery == nil || delivery.Status != billing.WebhookDeliveryStatusProcessed || delivery.Attempts != 1 {
+		t.Fatalf("expected delivery processed once, got %#v", delivery)
+	}
+	if len(store.Events) != 1 {
+		t.Fatalf("expected 1 billing event, got %d", len(store.Events))
+	}
+}
+
+func TestProcessBillingProviderWebhooksSkipsDeliveriesClaimedByAnotherWorker(t *testing.T) {
+	service, registry, store := newWebhookInboxTestService(t)
+	registry.payload = subscriptionWebhookPayload("evt-1", "2026-01-01T10:00:00Z", billing.StatusActive)
+
+	claimedDelivery := &billing.WebhookDelivery{ID: "stripe:evt-1", Integrator: "stripe", Status: billing.WebhookDeliveryStatusProcessing}
+	claimedDelivery.SetUpdatedAtTimeToNow()
+	store.WebhookDeliveries[claimedDelivery.ID] = claimedDelivery
+
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(store.Events) != 0 || claimedDelivery.Status != billing.WebhookDeliveryStatusProcessing {
+		t.Fatalf("expected delivery held by another worker to be skipped, got %d events and %#v", len(store.Events), claimedDelivery)
+	}
+
+	// a claim left behind by a worker that stopped can be taken over once its lease ends
+	claimedDelivery.UpdatedAt = "2020-01-01T00:00:00"
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(store.Events) != 1 || claimedDelivery.Status != billing.WebhookDeliveryStatusProcessed || claimedDelivery.Attempts != 1 {
+		t.Fatalf("expected stale claim to be processed, got %d events and %#v", len(store.Events), claimedDelivery)
+	}
+}
+
+func TestProcessBillingProviderWebhooksIgnoresOutOfOrderSubscriptionUpdates(t *testing.T) {
+	service, registry, store := newWebhookInboxTestService(t)
+
+	registry.payload = subscriptionWebhookPayload("evt-2", "2026-01-02T10:00:00Z", billing.StatusCancelled)
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	registry.payload = subscriptionWebhookPayload("evt-1", "2026-01-01T10:00:00Z", billing.StatusActive)
+	if err := processWebhook(t, service); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(store.Subscriptions) != 1 {
+		t.Fatalf("expected 1 subscription, got %d", len(store.Subscriptions))
+	}
+	for _, subscription := range store.Subscriptions {
+		if subscription.Status != billing.StatusCancelled {
+			t.Fatalf("expected older event not to regress status, got %q", subscription.Status)
+		}
+	}
+	if len(store.Events) != 2 {
+		t.Fatalf("expected both events to be recorded, got %d", len(store.Events))
+	}
+}
+
+func TestReplayWebhookDeliveryReprocessesFailedDelivery(t *testing.T) {
+	service, registry, store := newWebhookInboxTestService(t)
+	userService := &billingOptionalEmailFinderStub{findError: errors.New("user store unavailable")}
+	service.WithUserService(userService)
+
+	registry.payload = subscriptionWebhookPayload("evt-1", "2026-01-01T10:00:00Z", billing.StatusActive)
+	if err := processWebhook(t, service); err == nil {
+		t.Fatal("expected processing error")
+	}
+
+	listResponse, err := service.GetWebhookDeliveries(context.Background(), &GetWebhookDeliveriesRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(listResponse.Deliveries) != 1 || listResponse.Deliveries[0].LastError == "" {
+		t.Fatalf("expected 1 failed delivery with an error, got %#v", listResponse.Deliveries)
+	}
+
+	userService.findError = user.ErrUserNotFound
+	replayResponse, err := service.ReplayWebhookDelivery(context.Background(), &ReplayWebhookDeliveryRequest{DeliveryID: listResponse.Deliveries[0].ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if replayResponse.Delivery.Status != billing.WebhookDeliveryStatusProcessed || replayResponse.Delivery.Attempts != 2 {
+		t.Fatalf("expected delivery processed on second attempt, got %#v", replayResponse.Delivery)
+	}
+	if len(store.Subscriptions) != 1 {
+		t.Fatalf("expected replay to create subscription, got %d", len(store.Subscriptions))
+	}
+
+	_, err = service.ReplayWebhookDelivery(context.Background(), &ReplayWebhookDeliveryRequest{DeliveryID: replayResponse.Delivery.ID})
+	if !errors.Is(err, ErrBillingManagerWebhookDeliveryAlreadyProcessed) {
+		t.Fatalf("expected %v, got %v", ErrBillingManagerWebhookDeliveryAlreadyProcessed, err)
+	}
+
+	_, err = service.ReplayWebhookDelivery(context.Background(), &ReplayWebhookDeliveryRequest{DeliveryID: "stripe:missing"})
+	if !errors.Is(err, billing.ErrBillingWebhookDeliveryNotFound) {
+		t.Fatalf("expected %v, got %v", billing.ErrBillingWebhookDeliveryNotFound, err)
+	}
+}
+
+func TestWebhookDeliveryEndpointsRequireInbox(t *testing.T) {
+	service := &Service{}
+
+	if _, err := service.GetWebhookDeliveries(context.Background(), &GetWebhookDeliveriesRequest{}); !errors.Is(err, ErrBillingManagerWebhookInboxServiceNotSet) {
+		t.Fatalf("expected %v, got %v", ErrBillingManagerWebhookInboxServiceNotSet, err)
+	}
+	if _, err := service.ReplayWebhookDelivery(context.Background(), &ReplayWebhookDeliveryRequest{DeliveryID: "stripe:evt-1"}); !errors.Is(err, ErrBillingManagerWebhookInboxServiceNotSet) {
+		t.Fatalf("expected %v, got %v", ErrBillingManagerWebhookInboxServiceNotSet, err)
+	}
+}
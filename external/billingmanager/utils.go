@@ -48,6 +48,8 @@ func formatEventDescription(eventType, planName, status string) string {
 		return fmt.Sprintf("Subscription cancelled: %s", planName)
 	case paymentprovider.EventTypeSubscriptionUpdated:
 		return fmt.Sprintf("Subscription updated: %s", planName)
+	case EventTypeSubscriptionReconciled:
+		return fmt.Sprintf("Subscription corrected to match provider records: %s", planName)
 	default:
 		return fmt.Sprintf("%s - %s", eventType, planName)
 	}
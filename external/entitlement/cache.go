@@ -0,0 +1,92 @@
+package entitlement
+
+import (
+	"sync"
+	"time"
+)
+
+// cacheEntry holds a resolved entitlement set and when it stops being reused
+type cacheEntry struct {
+	set       *EntitlementSet
+	expiresAt time.Time
+}
+
+// entitlementCache is a process-local TTL cache of resolved entitlement sets keyed by subject.
+// Expired entries are dropped when read and swept at most once per TTL when storing, so
+// subjects that are never looked up again do not stay in memory. A TTL of zero or less
+// disables caching.
+type entitlementCache struct {
+	ttl       time.Duration
+	entries   map[string]cacheEntry
+	lastSweep time.Time
+	mu        sync.RWMutex
+}
+
+// newEntitlementCache returns an empty cache that reuses entries for the given TTL
+func newEntitlementCache(ttl time.Duration) *entitlementCache {
+	return &entitlementCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the cached set for the key if it has not expired at now
+func (c *entitlementCache) get(key string, now time.Time) (*EntitlementSet, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	if !now.Before(entry.expiresAt) {
+		c.mu.Lock()
+		if current, ok := c.entries[key]; ok && !now.Before(current.expiresAt) {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+
+		return nil, false
+	}
+
+	return entry.set, true
+}
+
+// set stores the set for the key, replacing any existing entry
+func (c *entitlementCache) set(key string, set *EntitlementSet, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for entryKey, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, entryKey)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	c.entries[key] = cacheEntry{set: set, expiresAt: now.Add(c.ttl)}
+}
+
+// delete drops the entry for the key
+func (c *entitlementCache) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// cacheKey returns the cache key for a subject
+func cacheKey(subjectType SubjectType, subjectID string) string {
+	return string(subjectType) + ":" + subjectID
+}
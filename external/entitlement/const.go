@@ -0,0 +1,63 @@
+// Package entitlement resolves what a user or group may use, and how much of it, from
+// their active subscriptions, trials and admin grants.
+package entitlement
+
+import "time"
+
+// SubjectType identifies the kind of subject entitlements are resolved for.
+type SubjectType string
+
+// SourceType identifies where an entitlement was granted from.
+type SourceType string
+
+const (
+	// SubjectTypeUser resolves entitlements for a platform user.
+	SubjectTypeUser SubjectType = "user"
+	// SubjectTypeGroup resolves entitlements for a group.
+	SubjectTypeGroup SubjectType = "group"
+)
+
+const (
+	// SourceTypeSubscription means the entitlement comes from a paid subscription.
+	SourceTypeSubscription SourceType = "subscription"
+	// SourceTypeTrial means the entitlement comes from a subscription in its trial period.
+	SourceTypeTrial SourceType = "trial"
+	// SourceTypeGrant means the entitlement comes from an admin grant.
+	SourceTypeGrant SourceType = "grant"
+)
+
+const (
+	// GrantCollection is the mongo collection name for entitlement grants.
+	GrantCollection string = "entitlement_grants"
+)
+
+const (
+	// defaultCacheTTL is how long a resolved entitlement set is reused before it is resolved again.
+	defaultCacheTTL = 5 * time.Minute
+
+	// resolvePageSize is the page size used when loading subscriptions and price plans to resolve against.
+	resolvePageSize = 100
+)
+
+const (
+	// ErrKeySubjectIDIsRequired is returned when an entitlement operation has no subject ID.
+	ErrKeySubjectIDIsRequired = "EntitlementSubjectIDIsRequired"
+	// ErrKeyInvalidSubjectType is returned when the subject type is not supported.
+	ErrKeyInvalidSubjectType = "EntitlementInvalidSubjectType"
+	// ErrKeyFeatureSlugIsRequired is returned when a check or grant has no feature slug.
+	ErrKeyFeatureSlugIsRequired = "EntitlementFeatureSlugIsRequired"
+	// ErrKeyFeatureNotEntitled is returned when the subject is not entitled to the requested feature.
+	ErrKeyFeatureNotEntitled = "EntitlementFeatureNotEntitled"
+	// ErrKeyGrantNotFound is returned when an entitlement grant cannot be found.
+	ErrKeyGrantNotFound = "EntitlementGrantNotFound"
+	// ErrKeyGrantIDIsRequired is returned when a grant-scoped operation has no grant ID.
+	ErrKeyGrantIDIsRequired = "EntitlementGrantIDIsRequired"
+	// ErrKeyInvalidGrantExpiry is returned when a grant expiry cannot be parsed or is in the past.
+	ErrKeyInvalidGrantExpiry = "EntitlementInvalidGrantExpiry"
+	// ErrKeyInvalidGrantQuantity is returned when a grant quantity is negative.
+	ErrKeyInvalidGrantQuantity = "EntitlementInvalidGrantQuantity"
+	// ErrKeyUnableToIdentifyUser is returned when a check runs without a signed-in user on the context.
+	ErrKeyUnableToIdentifyUser = "EntitlementUnableToIdentifyUser"
+	// ErrKeyDatabaseError is returned when persistence fails unexpectedly.
+	ErrKeyDatabaseError = "EntitlementDatabaseError"
+)
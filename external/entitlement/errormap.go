@@ -0,0 +1,71 @@
+package entitlement
+
+import (
+	"net/http"
+
+	"github.com/ooaklee/reply/v2"
+)
+
+// EntitlementErrorMap maps entitlement sentinel errors to API response metadata.
+var EntitlementErrorMap reply.ErrorManifest = reply.ErrorManifest{
+	ErrSubjectIDIsRequired: {
+		Title:      "Missing Subject",
+		StatusCode: http.StatusBadRequest,
+		Code:       "ENT00-001",
+		Detail:     "Please provide the user or group ID to resolve entitlements for",
+	},
+	ErrInvalidSubjectType: {
+		Title:      "Invalid Subject Type",
+		StatusCode: http.StatusBadRequest,
+		Code:       "ENT00-002",
+		Detail:     "Entitlements can only be resolved for users or groups",
+	},
+	ErrFeatureSlugIsRequired: {
+		Title:      "Missing Feature",
+		StatusCode: http.StatusBadRequest,
+		Code:       "ENT00-003",
+		Detail:     "Please provide a feature slug",
+	},
+	ErrFeatureNotEntitled: {
+		Title:      "Feature Not Available",
+		StatusCode: http.StatusForbidden,
+		Code:       "ENT00-004",
+		Detail:     "Your current plan does not include this feature",
+	},
+	ErrGrantNotFound: {
+		Title:      "Grant Not Found",
+		StatusCode: http.StatusNotFound,
+		Code:       "ENT00-005",
+		Detail:     "The requested entitlement grant could not be found",
+	},
+	ErrGrantIDIsRequired: {
+		Title:      "Missing Grant ID",
+		StatusCode: http.StatusBadRequest,
+		Code:       "ENT00-006",
+		Detail:     "Please provide an entitlement grant ID",
+	},
+	ErrInvalidGrantExpiry: {
+		Title:      "Invalid Grant Expiry",
+		StatusCode: http.StatusBadRequest,
+		Code:       "ENT00-007",
+		Detail:     "The grant expiry must be a future RFC3339 timestamp",
+	},
+	ErrInvalidGrantQuantity: {
+		Title:      "Invalid Grant Quantity",
+		StatusCode: http.StatusBadRequest,
+		Code:       "ENT00-008",
+		Detail:     "The grant quantity cannot be negative",
+	},
+	ErrUnableToIdentifyUser: {
+		Title:      "Unauthorized",
+		StatusCode: http.StatusUnauthorized,
+		Code:       "ENT00-009",
+		Detail:     "Unable to identify the signed-in user",
+	},
+	ErrDatabaseError: {
+		Title:      "Internal Error",
+		StatusCode: http.StatusInternalServerError,
+		Code:       "ENT00-010",
+		Detail:     "Unable to complete the entitlement operation at this time",
+	},
+}
@@ -0,0 +1,30 @@
+package entitlement
+
+import "errors"
+
+// Sentinel errors for the entitlement package.
+//
+// Callers can use errors.Is() against them, and EntitlementErrorMap turns
+// them into consistent HTTP responses at the handler and middleware layer.
+var (
+	// ErrDatabaseError means persistence failed unexpectedly.
+	ErrDatabaseError = errors.New(ErrKeyDatabaseError)
+	// ErrFeatureNotEntitled means the subject is not entitled to the requested feature.
+	ErrFeatureNotEntitled = errors.New(ErrKeyFeatureNotEntitled)
+	// ErrFeatureSlugIsRequired means a check or grant did not include a feature slug.
+	ErrFeatureSlugIsRequired = errors.New(ErrKeyFeatureSlugIsRequired)
+	// ErrGrantIDIsRequired means a grant-scoped operation did not include a grant ID.
+	ErrGrantIDIsRequired = errors.New(ErrKeyGrantIDIsRequired)
+	// ErrGrantNotFound means the requested entitlement grant could not be found.
+	ErrGrantNotFound = errors.New(ErrKeyGrantNotFound)
+	// ErrInvalidGrantExpiry means the grant expiry could not be accepted.
+	ErrInvalidGrantExpiry = errors.New(ErrKeyInvalidGrantExpiry)
+	// ErrInvalidGrantQuantity means the grant quantity could not be accepted.
+	ErrInvalidGrantQuantity = errors.New(ErrKeyInvalidGrantQuantity)
+	// ErrInvalidSubjectType means the subject type is not supported.
+	ErrInvalidSubjectType = errors.New(ErrKeyInvalidSubjectType)
+	// ErrSubjectIDIsRequired means a request that must be scoped to a subject did not include one.
+	ErrSubjectIDIsRequired = errors.New(ErrKeySubjectIDIsRequired)
+	// ErrUnableToIdentifyUser means no signed-in user was found on the request context.
+	ErrUnableToIdentifyUser = errors.New(ErrKeyUnableToIdentifyUser)
+)
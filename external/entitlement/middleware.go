@@ -0,0 +1,61 @@
+package entitlement
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ooaklee/ghatd/external/errormanifest"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/reply/v2"
+	"go.uber.org/zap"
+)
+
+// entitlementChecker defines the check used to gate routes
+type entitlementChecker interface {
+	Check(ctx context.Context, featureSlug string) (*Entitlement, error)
+}
+
+// Middleware gates routes on the signed-in user's entitlements
+type Middleware struct {
+	checker   entitlementChecker
+	errorMaps []reply.ErrorManifest
+}
+
+// NewMiddleware returns entitlement middleware. EntitlementErrorMap is always the base layer,
+// caller-supplied maps are applied as overrides.
+func NewMiddleware(checker entitlementChecker, errorMaps ...reply.ErrorManifest) *Middleware {
+	return &Middleware{
+		checker:   checker,
+		errorMaps: errorMaps,
+	}
+}
+
+// RequireFeature returns a gorilla/mux middleware function that only lets requests through when
+// the signed-in user is entitled to the feature. It must run after the authentication middleware
+// that places the user on the request context.
+func (m *Middleware) RequireFeature(featureSlug string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if _, err := m.checker.Check(r.Context(), featureSlug); err != nil {
+				logger := logger.AcquirePackageFrom(r.Context(), "external/entitlement")
+				logger.Info("request-blocked-by-entitlement-check", zap.String("feature-slug", featureSlug), zap.String("path", r.URL.Path), zap.Error(err))
+				m.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// getBaseResponseHandler returns a response handler with EntitlementErrorMap as the base layer
+func (m *Middleware) getBaseResponseHandler() *reply.Replier {
+	return reply.NewReplier(
+		errormanifest.NewComposer().
+			Add(EntitlementErrorMap).
+			AddOverrides(m.errorMaps...).
+			Build(),
+	)
+}
@@ -0,0 +1,84 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitEntitlementGrantsIndexesUp creates indexes for entitlement grants.
+func InitEntitlementGrantsIndexesUp(db *mongo.Database) error {
+	log.SetFlags(0)
+	const mongoCollectionName = entitlement.GrantCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-entitlement-grants-indexes"))
+
+	subjectIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "subject_type", Value: 1},
+			{Key: "subject_id", Value: 1},
+			{Key: "revoked_at", Value: 1},
+			{Key: "expires_at", Value: 1},
+		},
+		Options: options.Index().SetName("idx_entitlement_grants_subject_active"),
+	}
+
+	featureIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "feature_slug", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+		Options: options.Index().SetName("idx_entitlement_grants_feature_created"),
+	}
+
+	createdAtIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("idx_entitlement_grants_created_at"),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			subjectIndexModel,
+			featureIndexModel,
+			createdAtIndexModel,
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-entitlement-grants-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-entitlement-grants-indexes"))
+	return nil
+}
+
+// InitEntitlementGrantsIndexesDown drops entitlement grant indexes.
+func InitEntitlementGrantsIndexesDown(db *mongo.Database) error {
+	log.SetFlags(0)
+	const mongoCollectionName = entitlement.GrantCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-entitlement-grants-indexes"))
+
+	indexNames := []string{
+		"idx_entitlement_grants_subject_active",
+		"idx_entitlement_grants_feature_created",
+		"idx_entitlement_grants_created_at",
+	}
+
+	for _, indexName := range indexNames {
+		err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), indexName)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: "+indexName))
+			return err
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-entitlement-grants-indexes"))
+	return nil
+}
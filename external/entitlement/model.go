@@ -0,0 +1,174 @@
+package entitlement
+
+import (
+	"time"
+
+	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/toolbox"
+)
+
+// Grant is an admin-issued entitlement to a feature for a user or group, independent of
+// any subscription. Grants are never hard deleted, revoking a grant records RevokedAt.
+type Grant struct {
+	// ID is the internal unique identifier
+	ID string `json:"id" bson:"_id"`
+
+	// SubjectType is the kind of subject the grant is for (user or group)
+	SubjectType SubjectType `json:"subject_type" bson:"subject_type"`
+
+	// SubjectID is the ID of the user or group the grant is for
+	SubjectID string `json:"subject_id" bson:"subject_id"`
+
+	// FeatureSlug references the pricer feature being granted
+	FeatureSlug string `json:"feature_slug" bson:"feature_slug"`
+
+	// Quantity is the amount granted on top of any plan allowance. Zero grants
+	// unlimited use of the feature
+	Quantity int64 `json:"quantity,omitempty" bson:"quantity,omitempty"`
+
+	// Unit indicates the unit the quantity is measured in
+	Unit string `json:"unit,omitempty" bson:"unit,omitempty"`
+
+	// Reason records why the grant was issued
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+
+	// ExpiresAt is when the grant stops applying. Empty grants never expire
+	ExpiresAt string `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+
+	// RevokedAt is when the grant was revoked by an admin
+	RevokedAt string `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+
+	// RevokedByUserID is the admin who revoked the grant
+	RevokedByUserID string `json:"revoked_by_user_id,omitempty" bson:"revoked_by_user_id,omitempty"`
+
+	// CreatedAt is when the grant was stored in internal system
+	CreatedAt string `json:"created_at" bson:"created_at"`
+
+	// CreatedByUserID is the admin who issued the grant
+	CreatedByUserID string `json:"created_by_user_id,omitempty" bson:"created_by_user_id,omitempty"`
+
+	// UpdatedAt is when the grant was last updated in internal system
+	UpdatedAt string `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// IsActiveAt returns true if the grant has not been revoked and has not expired at the given time
+func (g *Grant) IsActiveAt(now time.Time) bool {
+	if g.RevokedAt != "" {
+		return false
+	}
+
+	if g.ExpiresAt == "" {
+		return true
+	}
+
+	expiresAt, err := time.Parse(common.RFC3339NanoUTC, g.ExpiresAt)
+	if err != nil {
+		return false
+	}
+
+	return now.Before(expiresAt)
+}
+
+// GenerateId generates a new Id for the grant
+func (g *Grant) GenerateId() *Grant {
+	g.ID = toolbox.GenerateUuidV4()
+	return g
+}
+
+// SetCreatedAtTimeToNow sets the created at date and time for the grant to now
+func (g *Grant) SetCreatedAtTimeToNow() *Grant {
+	g.CreatedAt = toolbox.TimeNowUTC()
+	return g
+}
+
+// SetUpdatedAtTimeToNow sets the updated at date and time for the grant to now
+func (g *Grant) SetUpdatedAtTimeToNow() *Grant {
+	g.UpdatedAt = toolbox.TimeNowUTC()
+	return g
+}
+
+// Source describes one subscription, trial or grant contributing to an entitlement
+type Source struct {
+	// Type is where the entitlement comes from
+	Type SourceType `json:"type"`
+
+	// ID is the subscription or grant ID
+	ID string `json:"id"`
+
+	// PlanSlug is the price plan the subscription was matched to, empty for grants
+	PlanSlug string `json:"plan_slug,omitempty"`
+
+	// Quantity is the amount this source contributes, zero when unlimited
+	Quantity int64 `json:"quantity,omitempty"`
+
+	// ExpiresAt is when this source stops applying, if known
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// Entitlement is the effective access a subject has to a single feature
+type Entitlement struct {
+	// FeatureSlug references the pricer feature
+	FeatureSlug string `json:"feature_slug"`
+
+	// FeatureID references the pricer feature ID, if known
+	FeatureID string `json:"feature_id,omitempty"`
+
+	// Label is the display name taken from the plan feature reference
+	Label string `json:"label,omitempty"`
+
+	// Unlimited is true when any source grants the feature without a quantity
+	Unlimited bool `json:"unlimited"`
+
+	// Quantity is the total allowance, meaningful only when Unlimited is false
+	Quantity int64 `json:"quantity,omitempty"`
+
+	// Unit indicates the unit the quantity is measured in
+	Unit string `json:"unit,omitempty"`
+
+	// Sources lists everything contributing to the entitlement
+	Sources []Source `json:"sources"`
+}
+
+// Allows returns true if the entitlement covers the requested amount
+func (e *Entitlement) Allows(amount int64) bool {
+	return e.Unlimited || amount <= e.Quantity
+}
+
+// EntitlementSet is the resolved set of entitlements for a subject
+type EntitlementSet struct {
+	// SubjectType is the kind of subject the set was resolved for
+	SubjectType SubjectType `json:"subject_type"`
+
+	// SubjectID is the user or group the set was resolved for
+	SubjectID string `json:"subject_id"`
+
+	// PlanSlugs lists the price plans matched from the subject's subscriptions
+	PlanSlugs []string `json:"plan_slugs"`
+
+	// Entitlements lists every feature the subject is entitled to
+	Entitlements []Entitlement `json:"entitlements"`
+
+	// ResolvedAt is when the set was resolved
+	ResolvedAt string `json:"resolved_at"`
+}
+
+// Get returns the entitlement for the feature slug, if the subject is entitled to it
+func (s *EntitlementSet) Get(featureSlug string) (*Entitlement, bool) {
+	for i := range s.Entitlements {
+		if s.Entitlements[i].FeatureSlug == featureSlug {
+			return &s.Entitlements[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// IsValidSubjectType returns true if the subject type is supported
+func IsValidSubjectType(subjectType SubjectType) bool {
+	switch subjectType {
+	case SubjectTypeUser, SubjectTypeGroup:
+		return true
+	}
+
+	return false
+}
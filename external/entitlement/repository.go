@@ -0,0 +1,237 @@
+package entitlement
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const defaultCollectionInitMaxAttemptsLimit = 3
+
+// MongoDbStore describes the MongoDB helper operations the entitlement repository uses.
+type MongoDbStore interface {
+	ExecuteCountDocuments(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error)
+	ExecuteFindCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
+	ExecuteInsertOneCommand(ctx context.Context, collection *mongo.Collection, document interface{}, resultObjectName string) (*mongo.InsertOneResult, error)
+	ExecuteFindOneCommandDecodeResult(ctx context.Context, collection *mongo.Collection, filter interface{}, result interface{}, resultObjectName string, logError bool, onFailureErr error) error
+	ExecuteUpdateOneCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, update interface{}, resultObjectName string) error
+
+	GetDatabase(ctx context.Context, dbName string) (*mongo.Database, error)
+	InitialiseClient(ctx context.Context) (*mongo.Client, error)
+	MapAllInCursorToResult(ctx context.Context, cursor *mongo.Cursor, result interface{}, resultObjectName string) error
+}
+
+// Repository manages entitlement grants in MongoDB.
+type Repository struct {
+	Store                          MongoDbStore
+	collectionInitMaxAttemptsLimit int
+
+	collection      *mongo.Collection
+	collectionMutex sync.Mutex
+}
+
+var _ GrantRepository = (*Repository)(nil)
+
+// NewRepository returns an entitlement grant repository backed by the provided MongoDB store.
+func NewRepository(store MongoDbStore) *Repository {
+	return &Repository{
+		Store:                          store,
+		collectionInitMaxAttemptsLimit: defaultCollectionInitMaxAttemptsLimit,
+	}
+}
+
+// WithCollectionInitMaxAttemptsLimit overrides collection initialisation retry attempts.
+func (r *Repository) WithCollectionInitMaxAttemptsLimit(limit int) *Repository {
+	if limit > 0 {
+		r.collectionInitMaxAttemptsLimit = limit
+	}
+	return r
+}
+
+// GetGrantCollection returns the entitlement grants collection, initialising it lazily.
+func (r *Repository) GetGrantCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.collectionMutex.Lock()
+	defer r.collectionMutex.Unlock()
+
+	if r.collection != nil {
+		return r.collection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.collection = db.Collection(GrantCollection)
+		return r.collection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, GrantCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// CreateGrant persists a new entitlement grant, generating missing IDs and timestamps.
+func (r *Repository) CreateGrant(ctx context.Context, grant *Grant) (*Grant, error) {
+	collection, err := r.GetGrantCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if grant.ID == "" {
+		grant.GenerateId()
+	}
+	if grant.CreatedAt == "" {
+		grant.SetCreatedAtTimeToNow()
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, grant, "entitlement-grant")
+	if err != nil {
+		return nil, err
+	}
+
+	return grant, nil
+}
+
+// GetGrantByID retrieves one entitlement grant by its ID.
+func (r *Repository) GetGrantByID(ctx context.Context, id string) (*Grant, error) {
+	collection, err := r.GetGrantCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Grant
+	err = r.Store.ExecuteFindOneCommandDecodeResult(ctx, collection, bson.M{"_id": id}, &result, "entitlement-grant", false, ErrGrantNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateGrant replaces one entitlement grant by ID.
+func (r *Repository) UpdateGrant(ctx context.Context, grant *Grant) (*Grant, error) {
+	collection, err := r.GetGrantCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grant.SetUpdatedAtTimeToNow()
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": grant.ID}, bson.M{"$set": grant}, "entitlement-grant")
+	if err != nil {
+		return nil, err
+	}
+
+	return grant, nil
+}
+
+// GetGrants returns a page of entitlement grants matching the request filters.
+func (r *Repository) GetGrants(ctx context.Context, req *GetGrantsRequest, now string) ([]Grant, error) {
+	collection, err := r.GetGrantCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortDirection := -1
+	if req.Order == "created_at_asc" {
+		sortDirection = 1
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((req.Page - 1) * req.PerPage)).
+		SetLimit(int64(req.PerPage)).
+		SetSort(bson.D{{Key: "created_at", Value: sortDirection}})
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, buildGrantFilter(req, now), findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := []Grant{}
+	if err = r.Store.MapAllInCursorToResult(ctx, cursor, &grants, "entitlement-grants"); err != nil {
+		return nil, err
+	}
+
+	return grants, nil
+}
+
+// GetTotalGrants counts entitlement grants matching the request filters.
+func (r *Repository) GetTotalGrants(ctx context.Context, req *GetGrantsRequest, now string) (int64, error) {
+	collection, err := r.GetGrantCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Store.ExecuteCountDocuments(ctx, collection, buildGrantFilter(req, now))
+}
+
+// GetActiveGrantsForSubject returns every grant for the subject that is neither revoked
+// nor expired at the given time.
+func (r *Repository) GetActiveGrantsForSubject(ctx context.Context, subjectType SubjectType, subjectID string, now string) ([]Grant, error) {
+	collection, err := r.GetGrantCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := buildGrantFilter(&GetGrantsRequest{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		ActiveOnly:  true,
+	}, now)
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := []Grant{}
+	if err = r.Store.MapAllInCursorToResult(ctx, cursor, &grants, "entitlement-grants"); err != nil {
+		return nil, err
+	}
+
+	return grants, nil
+}
+
+// buildGrantFilter converts grant list filters to a Mongo query. Timestamps are stored in
+// the package-wide RFC3339 nano UTC format so they compare correctly as strings.
+func buildGrantFilter(req *GetGrantsRequest, now string) bson.M {
+	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
+
+	if req.SubjectType != "" {
+		queryFilter["subject_type"] = req.SubjectType
+	}
+
+	if req.SubjectID != "" {
+		queryFilter["subject_id"] = req.SubjectID
+	}
+
+	if req.FeatureSlug != "" {
+		queryFilter["feature_slug"] = req.FeatureSlug
+	}
+
+	if req.ActiveOnly {
+		queryFilter["revoked_at"] = bson.M{"$in": bson.A{nil, ""}}
+		queryFilter["$or"] = bson.A{
+			bson.M{"expires_at": bson.M{"$in": bson.A{nil, ""}}},
+			bson.M{"expires_at": bson.M{"$gt": now}},
+		}
+	}
+
+	return queryFilter
+}
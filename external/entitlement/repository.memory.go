@@ -0,0 +1,142 @@
+package entitlement
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/common"
+)
+
+// InMemoryRepository is an in-memory implementation of the grant repository
+// Useful for testing and development
+type InMemoryRepository struct {
+	grants map[string]*Grant
+	mu     sync.RWMutex
+}
+
+var _ GrantRepository = (*InMemoryRepository)(nil)
+
+// NewInMemoryRepository creates a new in-memory grant repository
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		grants: make(map[string]*Grant),
+	}
+}
+
+// CreateGrant stores a new entitlement grant
+func (m *InMemoryRepository) CreateGrant(ctx context.Context, grant *Grant) (*Grant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if grant.ID == "" {
+		grant.GenerateId()
+	}
+	if grant.CreatedAt == "" {
+		grant.SetCreatedAtTimeToNow()
+	}
+
+	stored := *grant
+	m.grants[grant.ID] = &stored
+
+	return grant, nil
+}
+
+// GetGrantByID retrieves one entitlement grant by its ID
+func (m *InMemoryRepository) GetGrantByID(ctx context.Context, id string) (*Grant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	grant, ok := m.grants[id]
+	if !ok {
+		return nil, ErrGrantNotFound
+	}
+
+	result := *grant
+	return &result, nil
+}
+
+// UpdateGrant replaces one entitlement grant by ID
+func (m *InMemoryRepository) UpdateGrant(ctx context.Context, grant *Grant) (*Grant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.grants[grant.ID]; !ok {
+		return nil, ErrGrantNotFound
+	}
+
+	grant.SetUpdatedAtTimeToNow()
+
+	stored := *grant
+	m.grants[grant.ID] = &stored
+
+	return grant, nil
+}
+
+// GetGrants returns a page of entitlement grants matching the request filters
+func (m *InMemoryRepository) GetGrants(ctx context.Context, req *GetGrantsRequest, now string) ([]Grant, error) {
+	grants := m.filterGrants(req, now)
+
+	sort.Slice(grants, func(i, j int) bool {
+		if req.Order == "created_at_asc" {
+			return grants[i].CreatedAt < grants[j].CreatedAt
+		}
+		return grants[i].CreatedAt > grants[j].CreatedAt
+	})
+
+	start := (req.Page - 1) * req.PerPage
+	if start >= len(grants) {
+		return []Grant{}, nil
+	}
+
+	end := start + req.PerPage
+	if end > len(grants) {
+		end = len(grants)
+	}
+
+	return grants[start:end], nil
+}
+
+// GetTotalGrants counts entitlement grants matching the request filters
+func (m *InMemoryRepository) GetTotalGrants(ctx context.Context, req *GetGrantsRequest, now string) (int64, error) {
+	return int64(len(m.filterGrants(req, now))), nil
+}
+
+// GetActiveGrantsForSubject returns every grant for the subject that is neither revoked
+// nor expired at the given time
+func (m *InMemoryRepository) GetActiveGrantsForSubject(ctx context.Context, subjectType SubjectType, subjectID string, now string) ([]Grant, error) {
+	return m.filterGrants(&GetGrantsRequest{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		ActiveOnly:  true,
+	}, now), nil
+}
+
+// filterGrants returns copies of the stored grants matching the request filters
+func (m *InMemoryRepository) filterGrants(req *GetGrantsRequest, now string) []Grant {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	nowTime, _ := time.Parse(common.RFC3339NanoUTC, now)
+
+	grants := []Grant{}
+	for _, grant := range m.grants {
+		if req.SubjectType != "" && grant.SubjectType != req.SubjectType {
+			continue
+		}
+		if req.SubjectID != "" && grant.SubjectID != req.SubjectID {
+			continue
+		}
+		if req.FeatureSlug != "" && grant.FeatureSlug != req.FeatureSlug {
+			continue
+		}
+		if req.ActiveOnly && !grant.IsActiveAt(nowTime) {
+			continue
+		}
+
+		grants = append(grants, *grant)
+	}
+
+	return grants
+}
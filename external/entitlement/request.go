@@ -0,0 +1,108 @@
+package entitlement
+
+// GetEntitlementsRequest holds everything needed to resolve the entitlements of a subject
+type GetEntitlementsRequest struct {
+	// SubjectType is the kind of subject to resolve for. Default: user
+	SubjectType SubjectType
+
+	// SubjectID is the user or group ID
+	SubjectID string
+
+	// SkipCache forces the entitlements to be resolved again rather than read from cache
+	SkipCache bool
+}
+
+// CheckEntitlementRequest holds everything needed to check whether a subject is entitled
+// to a feature
+type CheckEntitlementRequest struct {
+	// SubjectType is the kind of subject to check. Default: user
+	SubjectType SubjectType
+
+	// SubjectID is the user or group ID
+	SubjectID string
+
+	// FeatureSlug is the pricer feature to check
+	FeatureSlug string
+
+	// Amount is the quantity the caller intends to use. Zero only checks the feature is included
+	Amount int64
+}
+
+// InvalidateEntitlementsRequest holds everything needed to drop a subject's cached entitlements
+type InvalidateEntitlementsRequest struct {
+	// SubjectType is the kind of subject. Default: user
+	SubjectType SubjectType
+
+	// SubjectID is the user or group ID
+	SubjectID string
+}
+
+// CreateGrantRequest holds everything needed to issue an entitlement grant
+type CreateGrantRequest struct {
+	// SubjectType is the kind of subject the grant is for (user or group)
+	SubjectType SubjectType `json:"subject_type" validate:"required"`
+
+	// SubjectID is the ID of the user or group the grant is for
+	SubjectID string `json:"subject_id" validate:"required"`
+
+	// FeatureSlug references the pricer feature being granted
+	FeatureSlug string `json:"feature_slug" validate:"required"`
+
+	// Quantity is the amount granted, zero grants unlimited use
+	Quantity int64 `json:"quantity"`
+
+	// Unit indicates the unit the quantity is measured in
+	Unit string `json:"unit"`
+
+	// Reason records why the grant was issued
+	Reason string `json:"reason"`
+
+	// ExpiresAt is an optional RFC3339 timestamp after which the grant stops applying
+	ExpiresAt string `json:"expires_at"`
+
+	// CreatedByUserID is the admin issuing the grant
+	CreatedByUserID string `json:"-"`
+}
+
+// GetGrantsRequest holds everything needed to list entitlement grants
+type GetGrantsRequest struct {
+	// Order defines how should response be sorted. Default: newest -> oldest (created_at_desc)
+	// Valid options: created_at_asc, created_at_desc
+	Order string `query:"order"`
+
+	// Total number of grants to return per page, if available. Default 25.
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from. Default 1.
+	Page int `query:"page"`
+
+	// TotalCount specifies the total count of all grants
+	TotalCount int
+
+	// TotalPages specifies the total pages of results
+	TotalPages int
+
+	// Meta whether response should contain meta information
+	Meta bool `query:"meta"`
+
+	// SubjectType is the subject type to filter by
+	SubjectType SubjectType `query:"subject_type"`
+
+	// SubjectID is the subject ID to filter by
+	SubjectID string `query:"subject_id"`
+
+	// FeatureSlug is the feature slug to filter by
+	FeatureSlug string `query:"feature_slug"`
+
+	// ActiveOnly excludes revoked and expired grants
+	ActiveOnly bool `query:"active_only"`
+}
+
+// RevokeGrantRequest holds everything needed to revoke an entitlement grant
+type RevokeGrantRequest struct {
+	// ID is the grant to revoke
+	ID string
+
+	// RevokedByUserID is the admin revoking the grant
+	RevokedByUserID string
+}
@@ -0,0 +1,57 @@
+package entitlement
+
+import "github.com/ooaklee/ghatd/external/toolbox"
+
+// GetEntitlementsResponse holds the resolved entitlements of a subject
+type GetEntitlementsResponse struct {
+	EntitlementSet *EntitlementSet
+}
+
+// CheckEntitlementResponse holds the entitlement that satisfied a check
+type CheckEntitlementResponse struct {
+	Entitlement *Entitlement
+}
+
+// CreateGrantResponse holds the issued grant
+type CreateGrantResponse struct {
+	Grant *Grant
+}
+
+// RevokeGrantResponse holds the revoked grant
+type RevokeGrantResponse struct {
+	Grant *Grant
+}
+
+// GetGrantsResponse holds everything needed to return
+// the response to get entitlement grants
+type GetGrantsResponse struct {
+	Grants []Grant `json:"grants"`
+
+	// Total number of grants found that matched provided
+	// filters
+	Total int
+
+	// TotalPages total pages available, based on the provided
+	// filters and resources per page
+	TotalPages int
+
+	// PerPage number of grants set to be returned per page
+	PerPage int
+
+	// Page specifies the page results were taken from. Default 1.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetGrantsResponse,
+// including the number of resources per page, total resources, total pages,
+// and the current page.
+func (g *GetGrantsResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
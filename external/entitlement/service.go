@@ -0,0 +1,663 @@
+package entitlement
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/pricer"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// GrantRepository describes the persistence operations needed for entitlement grants.
+type GrantRepository interface {
+	CreateGrant(ctx context.Context, grant *Grant) (*Grant, error)
+	GetGrantByID(ctx context.Context, id string) (*Grant, error)
+	UpdateGrant(ctx context.Context, grant *Grant) (*Grant, error)
+	GetGrants(ctx context.Context, req *GetGrantsRequest, now string) ([]Grant, error)
+	GetTotalGrants(ctx context.Context, req *GetGrantsRequest, now string) (int64, error)
+	GetActiveGrantsForSubject(ctx context.Context, subjectType SubjectType, subjectID string, now string) ([]Grant, error)
+}
+
+// BillingService describes the subscription lookups used to resolve entitlements.
+type BillingService interface {
+	GetSubscriptions(ctx context.Context, req *billing.GetSubscriptionsRequest) (*billing.GetSubscriptionsResponse, error)
+}
+
+// PricerService describes the price plan lookups used to resolve entitlements.
+type PricerService interface {
+	GetPricePlans(ctx context.Context, req *pricer.GetPricePlansRequest) (*pricer.GetPricePlansResponse, error)
+}
+
+// pricePlanVersionGetter is an optional capability implemented by the pricer service for
+// looking up the immutable price plan version a subscription was bought on.
+type pricePlanVersionGetter interface {
+	GetPricePlanVersionByID(ctx context.Context, req *pricer.GetPricePlanVersionByIDRequest) (*pricer.GetPricePlanVersionResponse, error)
+}
+
+// Service resolves, caches and checks entitlements, and manages admin grants.
+type Service struct {
+	GrantRepository GrantRepository
+	BillingService  BillingService
+	PricerService   PricerService
+
+	cache *entitlementCache
+	now   func() time.Time
+}
+
+// NewService returns an entitlement service that caches resolved entitlements for five minutes.
+func NewService(grantRepository GrantRepository, billingService BillingService, pricerService PricerService) *Service {
+	return &Service{
+		GrantRepository: grantRepository,
+		BillingService:  billingService,
+		PricerService:   pricerService,
+		cache:           newEntitlementCache(defaultCacheTTL),
+		now:             func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithCacheTTL overrides how long resolved entitlements are reused. A TTL of zero or
+// less resolves entitlements on every request.
+func (s *Service) WithCacheTTL(ttl time.Duration) *Service {
+	s.cache = newEntitlementCache(ttl)
+	return s
+}
+
+// GetEntitlements resolves the effective entitlements of a user or group from their
+// entitled subscriptions, trials and active admin grants.
+func (s *Service) GetEntitlements(ctx context.Context, req *GetEntitlementsRequest) (*GetEntitlementsResponse, error) {
+	subjectType, err := normaliseSubject(req.SubjectType, req.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	key := cacheKey(subjectType, req.SubjectID)
+
+	if !req.SkipCache {
+		if set, ok := s.cache.get(key, now); ok {
+			return &GetEntitlementsResponse{EntitlementSet: set}, nil
+		}
+	}
+
+	set, err := s.resolve(ctx, subjectType, req.SubjectID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.set(key, set, now)
+
+	return &GetEntitlementsResponse{EntitlementSet: set}, nil
+}
+
+// CheckEntitlement returns the subject's entitlement to the feature, or ErrFeatureNotEntitled
+// when the feature is not included or does not cover the requested amount.
+func (s *Service) CheckEntitlement(ctx context.Context, req *CheckEntitlementRequest) (*CheckEntitlementResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/entitlement").With(
+		zap.String("operation", "check-entitlement"),
+		zap.String("subject-type", string(req.SubjectType)),
+		zap.String("subject-id", req.SubjectID),
+		zap.String("feature-slug", req.FeatureSlug),
+	)
+
+	if req.FeatureSlug == "" {
+		return nil, ErrFeatureSlugIsRequired
+	}
+
+	entitlementsResp, err := s.GetEntitlements(ctx, &GetEntitlementsRequest{
+		SubjectType: req.SubjectType,
+		SubjectID:   req.SubjectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entitlement, ok := entitlementsResp.EntitlementSet.Get(req.FeatureSlug)
+	if !ok {
+		logger.Debug("subject-not-entitled-to-feature")
+		return nil, ErrFeatureNotEntitled
+	}
+
+	if req.Amount > 0 && !entitlement.Allows(req.Amount) {
+		logger.Debug("subject-entitlement-does-not-cover-amount", zap.Int64("amount", req.Amount), zap.Int64("quantity", entitlement.Quantity))
+		return nil, ErrFeatureNotEntitled
+	}
+
+	return &CheckEntitlementResponse{Entitlement: entitlement}, nil
+}
+
+// Check returns the signed-in user's entitlement to the feature, or ErrFeatureNotEntitled
+// when their subscriptions and grants do not include it.
+func (s *Service) Check(ctx context.Context, featureSlug string) (*Entitlement, error) {
+	userID := accessmanagerhelpers.AcquireFrom(ctx)
+	if userID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	checkResp, err := s.CheckEntitlement(ctx, &CheckEntitlementRequest{
+		SubjectType: SubjectTypeUser,
+		SubjectID:   userID,
+		FeatureSlug: featureSlug,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return checkResp.Entitlement, nil
+}
+
+// InvalidateEntitlements drops the cached entitlements of a subject, so the next lookup
+// resolves them again. Call it whenever a subject's subscriptions change.
+func (s *Service) InvalidateEntitlements(ctx context.Context, req *InvalidateEntitlementsRequest) {
+	subjectType := req.SubjectType
+	if subjectType == "" {
+		subjectType = SubjectTypeUser
+	}
+
+	s.cache.delete(cacheKey(subjectType, req.SubjectID))
+}
+
+// CreateGrant issues an admin grant to a feature for a user or group
+func (s *Service) CreateGrant(ctx context.Context, req *CreateGrantRequest) (*CreateGrantResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/entitlement").With(
+		zap.String("operation", "create-grant"),
+		zap.String("subject-type", string(req.SubjectType)),
+		zap.String("subject-id", req.SubjectID),
+		zap.String("feature-slug", req.FeatureSlug),
+	)
+
+	subjectType, err := normaliseSubject(req.SubjectType, req.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	featureSlug := strings.TrimSpace(req.FeatureSlug)
+	if featureSlug == "" {
+		return nil, ErrFeatureSlugIsRequired
+	}
+
+	if req.Quantity < 0 {
+		return nil, ErrInvalidGrantQuantity
+	}
+
+	var expiresAt string
+	if req.ExpiresAt != "" {
+		parsedExpiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil || !parsedExpiresAt.After(s.now()) {
+			logger.Warn("invalid-grant-expiry", zap.String("expires-at", req.ExpiresAt))
+			return nil, ErrInvalidGrantExpiry
+		}
+		expiresAt = parsedExpiresAt.UTC().Format(common.RFC3339NanoUTC)
+	}
+
+	grant, err := s.GrantRepository.CreateGrant(ctx, &Grant{
+		SubjectType:     subjectType,
+		SubjectID:       req.SubjectID,
+		FeatureSlug:     featureSlug,
+		Quantity:        req.Quantity,
+		Unit:            req.Unit,
+		Reason:          req.Reason,
+		ExpiresAt:       expiresAt,
+		CreatedByUserID: req.CreatedByUserID,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-grant", zap.Error(err))
+		return nil, err
+	}
+
+	s.InvalidateEntitlements(ctx, &InvalidateEntitlementsRequest{SubjectType: subjectType, SubjectID: req.SubjectID})
+
+	logger.Info("entitlement-grant-created", zap.String("grant-id", grant.ID))
+
+	return &CreateGrantResponse{Grant: grant}, nil
+}
+
+// GetGrants returns a page of entitlement grants matching the request filters
+func (s *Service) GetGrants(ctx context.Context, req *GetGrantsRequest) (*GetGrantsResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/entitlement")
+
+	if req.SubjectType != "" && !IsValidSubjectType(req.SubjectType) {
+		return nil, ErrInvalidSubjectType
+	}
+
+	// Set defaults
+	if req.Order == "" {
+		req.Order = "created_at_desc"
+	}
+
+	if req.PerPage == 0 {
+		req.PerPage = 25
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	now := s.now().Format(common.RFC3339NanoUTC)
+
+	total, err := s.GrantRepository.GetTotalGrants(ctx, req, now)
+	if err != nil {
+		logger.Error("failed-to-get-grants-total", zap.Error(err))
+		return nil, err
+	}
+	req.TotalCount = int(total)
+
+	grants, err := s.GrantRepository.GetGrants(ctx, req, now)
+	if err != nil {
+		logger.Error("failed-to-get-grants", zap.Error(err))
+		return nil, err
+	}
+
+	paginatedResponse, err := toolbox.Paginate(ctx, &toolbox.PaginationRequest{
+		PerPage: req.PerPage,
+		Page:    req.Page,
+	}, grants, req.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetGrantsResponse{
+		Grants:     paginatedResponse.Resources,
+		Total:      paginatedResponse.Total,
+		TotalPages: paginatedResponse.TotalPages,
+		PerPage:    paginatedResponse.ResourcePerPage,
+		Page:       paginatedResponse.Page,
+	}, nil
+}
+
+// RevokeGrant revokes an entitlement grant. Revoking an already revoked grant is a no-op.
+func (s *Service) RevokeGrant(ctx context.Context, req *RevokeGrantRequest) (*RevokeGrantResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/entitlement").With(
+		zap.String("operation", "revoke-grant"),
+		zap.String("grant-id", req.ID),
+	)
+
+	if req.ID == "" {
+		return nil, ErrGrantIDIsRequired
+	}
+
+	grant, err := s.GrantRepository.GetGrantByID(ctx, req.ID)
+	if err != nil {
+		logger.Warn("failed-to-get-grant", zap.Error(err))
+		return nil, err
+	}
+
+	if grant.RevokedAt != "" {
+		return &RevokeGrantResponse{Grant: grant}, nil
+	}
+
+	grant.RevokedAt = s.now().Format(common.RFC3339NanoUTC)
+	grant.RevokedByUserID = req.RevokedByUserID
+
+	grant, err = s.GrantRepository.UpdateGrant(ctx, grant)
+	if err != nil {
+		logger.Error("failed-to-revoke-grant", zap.Error(err))
+		return nil, err
+	}
+
+	s.InvalidateEntitlements(ctx, &InvalidateEntitlementsRequest{SubjectType: grant.SubjectType, SubjectID: grant.SubjectID})
+
+	logger.Info("entitlement-grant-revoked")
+
+	return &RevokeGrantResponse{Grant: grant}, nil
+}
+
+// resolve builds the entitlement set for a subject from its subscriptions and grants
+func (s *Service) resolve(ctx context.Context, subjectType SubjectType, subjectID string, now time.Time) (*EntitlementSet, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/entitlement").With(
+		zap.String("operation", "resolve-entitlements"),
+		zap.String("subject-type", string(subjectType)),
+		zap.String("subject-id", subjectID),
+	)
+
+	builder := newEntitlementBuilder()
+
+	subscriptions, err := s.getEntitledSubscriptions(ctx, subjectType, subjectID, now)
+	if err != nil {
+		logger.Error("failed-to-get-subscriptions-for-entitlements", zap.Error(err))
+		return nil, err
+	}
+
+	if len(subscriptions) > 0 {
+		plans, err := s.getCandidatePlans(ctx)
+		if err != nil {
+			logger.Error("failed-to-get-price-plans-for-entitlements", zap.Error(err))
+			return nil, err
+		}
+
+		for i := range subscriptions {
+			subscription := &subscriptions[i]
+
+			plan := s.getSubscriptionPricePlanVersion(ctx, subscription)
+			if plan == nil {
+				plan = matchSubscriptionPlan(subscription, plans)
+			}
+			if plan == nil {
+				logger.Warn("no-price-plan-matches-subscription", zap.String("subscription-id", subscription.ID), zap.String("plan-id", subscription.PlanID), zap.String("plan-name", subscription.PlanName))
+				continue
+			}
+
+			builder.addPlan(plan, subscriptionSource(subscription, plan))
+		}
+	}
+
+	grants, err := s.GrantRepository.GetActiveGrantsForSubject(ctx, subjectType, subjectID, now.Format(common.RFC3339NanoUTC))
+	if err != nil {
+		logger.Error("failed-to-get-grants-for-entitlements", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range grants {
+		builder.addGrant(&grants[i])
+	}
+
+	return builder.build(subjectType, subjectID, now), nil
+}
+
+// getEntitledSubscriptions returns the subscriptions owned by the user, or by the group for
+// group subjects, that currently give access to their plan, including cancelled subscriptions still inside their paid period and past due
+// or unpaid subscriptions still inside their dunning grace period
+func (s *Service) getEntitledSubscriptions(ctx context.Context, subjectType SubjectType, subjectID string, now time.Time) ([]billing.Subscription, error) {
+	var entitled []billing.Subscription
+
+	for page := 1; ; page++ {
+		subscriptionsReq := &billing.GetSubscriptionsRequest{
+			Statuses: []string{billing.StatusActive, billing.StatusTrialing, billing.StatusCancelled, billing.StatusPastDue, billing.StatusUnpaid},
+			PerPage:  resolvePageSize,
+			Page:     page,
+		}
+		if subjectType == SubjectTypeGroup {
+			subscriptionsReq.ForGroupIDs = []string{subjectID}
+		} else {
+			// subscriptions the user bought for a group entitle the group, not the user
+			subscriptionsReq.ForUserIDs = []string{subjectID}
+			subscriptionsReq.ExcludeGroupOwned = true
+		}
+
+		subscriptionsResp, err := s.BillingService.GetSubscriptions(ctx, subscriptionsReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subscription := range subscriptionsResp.Subscriptions {
+			if isSubscriptionEntitled(&subscription, now) {
+				entitled = append(entitled, subscription)
+			}
+		}
+
+		if page >= subscriptionsResp.TotalPages {
+			return entitled, nil
+		}
+	}
+}
+
+// getCandidatePlans returns every published or archived price plan, as archived plans still
+// entitle their existing subscribers
+func (s *Service) getCandidatePlans(ctx context.Context) ([]pricer.PricePlan, error) {
+	var plans []pricer.PricePlan
+
+	for page := 1; ; page++ {
+		plansResp, err := s.PricerService.GetPricePlans(ctx, &pricer.GetPricePlansRequest{
+			PerPage:          resolvePageSize,
+			Page:             page,
+			IncludeFeatures:  true,
+			IncludeCosts:     true,
+			IncludeProviders: true,
+			WithStatus:       string(pricer.PricePlanStatusPublished) + "," + string(pricer.PricePlanStatusArchived),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, plan := range plansResp.PricePlans {
+			if plan.DeletedAt == "" {
+				plans = append(plans, plan)
+			}
+		}
+
+		if page >= plansResp.TotalPages {
+			return plans, nil
+		}
+	}
+}
+
+// getSubscriptionPricePlanVersion returns the terms of the price plan version the subscription
+// was bought on, so grandfathered subscribers keep them after the plan is republished. It
+// returns nil when the subscription is not linked to a version or the version cannot be found.
+func (s *Service) getSubscriptionPricePlanVersion(ctx context.Context, subscription *billing.Subscription) *pricer.PricePlan {
+	getter, ok := s.PricerService.(pricePlanVersionGetter)
+	if !ok || subscription.PricePlanVersionID == "" {
+		return nil
+	}
+
+	versionResp, err := getter.GetPricePlanVersionByID(ctx, &pricer.GetPricePlanVersionByIDRequest{ID: subscription.PricePlanVersionID})
+	if err != nil {
+		logger.AcquirePackageFrom(ctx, "external/entitlement").Warn("failed-to-get-subscription-price-plan-version", zap.String("subscription-id", subscription.ID), zap.String("price-plan-version-id", subscription.PricePlanVersionID), zap.Error(err))
+		return nil
+	}
+
+	return versionResp.PricePlanVersion.AsPricePlan()
+}
+
+// isSubscriptionEntitled returns true if the subscription currently gives access to its plan.
+// Subscriptions with a failing payment keep access during their dunning grace period, and
+// complimentary subscriptions lose access once they are no longer available.
+func isSubscriptionEntitled(subscription *billing.Subscription, now time.Time) bool {
+	if subscription.IsComplimentary() && subscription.AvailableUntilDate != nil && !now.Before(*subscription.AvailableUntilDate) {
+		return false
+	}
+
+	if subscription.IsActive() || subscription.IsInDunningGracePeriod() {
+		return true
+	}
+
+	return subscription.Status == billing.StatusCancelled &&
+		subscription.AvailableUntilDate != nil &&
+		now.Before(*subscription.AvailableUntilDate)
+}
+
+// matchSubscriptionPlan finds the price plan for a subscription, first by the provider plan ID
+// against the plan and cost provider refs and then by plan name
+func matchSubscriptionPlan(subscription *billing.Subscription, plans []pricer.PricePlan) *pricer.PricePlan {
+	if subscription.PlanID != "" {
+		for i := range plans {
+			if hasProviderRef(plans[i].ProviderRefs, subscription.Integrator, subscription.PlanID) {
+				return &plans[i]
+			}
+
+			for _, cost := range plans[i].Costs {
+				if hasProviderRef(cost.ProviderRefs, subscription.Integrator, subscription.PlanID) {
+					return &plans[i]
+				}
+			}
+		}
+	}
+
+	if subscription.PlanName == "" {
+		return nil
+	}
+
+	planSlug := pricer.NormalisePriceSlug(subscription.PlanName)
+	for i := range plans {
+		if strings.EqualFold(plans[i].Name, subscription.PlanName) || plans[i].Slug == planSlug {
+			return &plans[i]
+		}
+	}
+
+	return nil
+}
+
+// hasProviderRef returns true if any of the refs for the provider carries the provider-side ID
+func hasProviderRef(refs []pricer.PriceProviderRef, provider string, providerID string) bool {
+	for _, ref := range refs {
+		if string(ref.Provider) != provider {
+			continue
+		}
+
+		if ref.ProviderPriceID == providerID || ref.ProviderID == providerID || ref.ProviderProductID == providerID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subscriptionSource describes the subscription as an entitlement source
+func subscriptionSource(subscription *billing.Subscription, plan *pricer.PricePlan) Source {
+	source := Source{
+		Type:     SourceTypeSubscription,
+		ID:       subscription.ID,
+		PlanSlug: plan.Slug,
+	}
+
+	switch {
+	case subscription.Status == billing.StatusTrialing:
+		source.Type = SourceTypeTrial
+		if subscription.ProviderTrialEndsAt != nil {
+			source.ExpiresAt = subscription.ProviderTrialEndsAt.UTC().Format(common.RFC3339NanoUTC)
+		}
+	case (subscription.Status == billing.StatusCancelled || subscription.IsComplimentary()) && subscription.AvailableUntilDate != nil:
+		source.ExpiresAt = subscription.AvailableUntilDate.UTC().Format(common.RFC3339NanoUTC)
+	}
+
+	return source
+}
+
+// normaliseSubject defaults the subject type to user and validates the subject
+func normaliseSubject(subjectType SubjectType, subjectID string) (SubjectType, error) {
+	if subjectType == "" {
+		subjectType = SubjectTypeUser
+	}
+
+	if !IsValidSubjectType(subjectType) {
+		return "", ErrInvalidSubjectType
+	}
+
+	if strings.TrimSpace(subjectID) == "" {
+		return "", ErrSubjectIDIsRequired
+	}
+
+	return subjectType, nil
+}
+
+// entitlementAccumulator collects the sources of one feature while resolving
+type entitlementAccumulator struct {
+	entitlement   Entitlement
+	planQuantity  int64
+	grantQuantity int64
+}
+
+// entitlementBuilder merges plan features and grants into an entitlement set. Plan allowances
+// do not stack, the largest one applies, while grant quantities are added on top of it.
+type entitlementBuilder struct {
+	planSlugs []string
+	features  map[string]*entitlementAccumulator
+}
+
+// newEntitlementBuilder returns an empty builder
+func newEntitlementBuilder() *entitlementBuilder {
+	return &entitlementBuilder{features: make(map[string]*entitlementAccumulator)}
+}
+
+// accumulatorFor returns the accumulator for the feature, creating it when missing
+func (b *entitlementBuilder) accumulatorFor(featureSlug string) *entitlementAccumulator {
+	accumulator, ok := b.features[featureSlug]
+	if !ok {
+		accumulator = &entitlementAccumulator{entitlement: Entitlement{FeatureSlug: featureSlug, Sources: []Source{}}}
+		b.features[featureSlug] = accumulator
+	}
+
+	return accumulator
+}
+
+// addPlan adds every included feature of the plan
+func (b *entitlementBuilder) addPlan(plan *pricer.PricePlan, source Source) {
+	b.planSlugs = append(b.planSlugs, plan.Slug)
+
+	for _, feature := range plan.Features {
+		if !feature.Included || feature.FeatureSlug == "" {
+			continue
+		}
+
+		accumulator := b.accumulatorFor(feature.FeatureSlug)
+		if accumulator.entitlement.FeatureID == "" {
+			accumulator.entitlement.FeatureID = feature.FeatureID
+		}
+		if accumulator.entitlement.Label == "" {
+			accumulator.entitlement.Label = feature.Label
+		}
+		if accumulator.entitlement.Unit == "" {
+			accumulator.entitlement.Unit = string(feature.Unit)
+		}
+
+		if feature.Quantity == 0 {
+			accumulator.entitlement.Unlimited = true
+		} else if feature.Quantity > accumulator.planQuantity {
+			accumulator.planQuantity = feature.Quantity
+		}
+
+		featureSource := source
+		featureSource.Quantity = feature.Quantity
+		accumulator.entitlement.Sources = append(accumulator.entitlement.Sources, featureSource)
+	}
+}
+
+// addGrant adds an admin grant
+func (b *entitlementBuilder) addGrant(grant *Grant) {
+	accumulator := b.accumulatorFor(grant.FeatureSlug)
+	if accumulator.entitlement.Unit == "" {
+		accumulator.entitlement.Unit = grant.Unit
+	}
+
+	if grant.Quantity == 0 {
+		accumulator.entitlement.Unlimited = true
+	} else {
+		accumulator.grantQuantity += grant.Quantity
+	}
+
+	accumulator.entitlement.Sources = append(accumulator.entitlement.Sources, Source{
+		Type:      SourceTypeGrant,
+		ID:        grant.ID,
+		Quantity:  grant.Quantity,
+		ExpiresAt: grant.ExpiresAt,
+	})
+}
+
+// build returns the merged entitlement set sorted by feature slug
+func (b *entitlementBuilder) build(subjectType SubjectType, subjectID string, now time.Time) *EntitlementSet {
+	set := &EntitlementSet{
+		SubjectType:  subjectType,
+		SubjectID:    subjectID,
+		PlanSlugs:    []string{},
+		Entitlements: []Entitlement{},
+		ResolvedAt:   now.Format(common.RFC3339NanoUTC),
+	}
+
+	seenPlans := make(map[string]bool)
+	for _, planSlug := range b.planSlugs {
+		if !seenPlans[planSlug] {
+			seenPlans[planSlug] = true
+			set.PlanSlugs = append(set.PlanSlugs, planSlug)
+		}
+	}
+
+	for _, accumulator := range b.features {
+		entitlement := accumulator.entitlement
+		if !entitlement.Unlimited {
+			entitlement.Quantity = accumulator.planQuantity + accumulator.grantQuantity
+		}
+		set.Entitlements = append(set.Entitlements, entitlement)
+	}
+
+	sort.Slice(set.Entitlements, func(i, j int) bool {
+		return set.Entitlements[i].FeatureSlug < set.Entitlements[j].FeatureSlug
+	})
+
+	return set
+}
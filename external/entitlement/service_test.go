@@ -0,0 +1,504 @@
+package entitlement
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+// fakeBillingService returns canned subscriptions and counts lookups
+type fakeBillingService struct {
+	subscriptions []billing.Subscription
+	calls         int
+}
+
+func (f *fakeBillingService) GetSubscriptions(ctx context.Context, req *billing.GetSubscriptionsRequest) (*billing.GetSubscriptionsResponse, error) {
+	f.calls++
+
+	var subscriptions []billing.Subscription
+	for _, subscription := range f.subscriptions {
+		if len(req.ForUserIDs) > 0 && subscription.UserID != req.ForUserIDs[0] {
+			continue
+		}
+		if len(req.ForGroupIDs) > 0 && subscription.GroupID != req.ForGroupIDs[0] {
+			continue
+		}
+		if req.ExcludeGroupOwned && subscription.IsGroupOwned() {
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return &billing.GetSubscriptionsResponse{
+		Subscriptions: subscriptions,
+		Total:         len(subscriptions),
+		TotalPages:    1,
+	}, nil
+}
+
+// fakePricerService returns canned price plans
+type fakePricerService struct {
+	plans []pricer.PricePlan
+}
+
+func (f *fakePricerService) GetPricePlans(ctx context.Context, req *pricer.GetPricePlansRequest) (*pricer.GetPricePlansResponse, error) {
+	return &pricer.GetPricePlansResponse{
+		PricePlans: f.plans,
+		Total:      len(f.plans),
+		TotalPages: 1,
+	}, nil
+}
+
+func testPlans() []pricer.PricePlan {
+	return []pricer.PricePlan{
+		{
+			ID:   "plan-pro",
+			Slug: "pro",
+			Name: "Pro",
+			Features: []pricer.PlanFeatureRef{
+				{FeatureSlug: "projects", Included: true, Quantity: 10, Unit: "project"},
+				{FeatureSlug: "exports", Included: true},
+				{FeatureSlug: "sso", Included: false},
+			},
+			Costs: []pricer.PriceCost{
+				{
+					ProviderRefs: []pricer.PriceProviderRef{
+						{Provider: "stripe", ProviderPriceID: "price_pro_monthly"},
+					},
+				},
+			},
+		},
+		{
+			ID:   "plan-starter",
+			Slug: "starter",
+			Name: "Starter",
+			Features: []pricer.PlanFeatureRef{
+				{FeatureSlug: "projects", Included: true, Quantity: 3, Unit: "project"},
+			},
+		},
+	}
+}
+
+func newTestService(subscriptions ...billing.Subscription) (*Service, *fakeBillingService, *InMemoryRepository) {
+	billingService := &fakeBillingService{subscriptions: subscriptions}
+	repository := NewInMemoryRepository()
+
+	return NewService(repository, billingService, &fakePricerService{plans: testPlans()}), billingService, repository
+}
+
+func TestService_GetEntitlements(t *testing.T) {
+	past := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		subscriptions []billing.Subscription
+		grants        []Grant
+		wantPlans     []string
+		assert        func(t *testing.T, set *EntitlementSet)
+	}{
+		{
+			name: "Success - subscription matched by provider price ID",
+			subscriptions: []billing.Subscription{
+				{ID: "sub-1", UserID: "user-1", Integrator: "stripe", PlanID: "price_pro_monthly", Status: billing.StatusActive},
+			},
+			wantPlans: []string{"pro"},
+			assert: func(t *testing.T, set *EntitlementSet) {
+				projects, ok := set.Get("projects")
+				if !ok || projects.Unlimited || projects.Quantity != 10 {
+					t.Fatalf("expected 10 projects, got %#v", projects)
+				}
+				exports, ok := set.Get("exports")
+				if !ok || !exports.Unlimited {
+					t.Fatalf("expected unlimited exports, got %#v", exports)
+				}
+				if _, ok := set.Get("sso"); ok {
+					t.Fatalf("expected excluded feature to be absent")
+				}
+			},
+		},
+		{
+			name: "Success - subscription matched by plan name and trial is a source",
+			subscriptions: []billing.Subscription{
+				{ID: "sub-1", UserID: "user-1", Integrator: "paddle", PlanName: "starter", Status: billing.StatusTrialing, ProviderTrialEndsAt: &future},
+			},
+			wantPlans: []string{"starter"},
+			assert: func(t *testing.T, set *EntitlementSet) {
+				projects, _ := set.Get("projects")
+				if projects == nil || projects.Quantity != 3 {
+					t.Fatalf("expected 3 projects, got %#v", projects)
+				}
+				if projects.Sources[0].Type != SourceTypeTrial || projects.Sources[0].ExpiresAt == "" {
+					t.Fatalf("expected trial source with expiry, got %#v", projects.Sources[0])
+				}
+			},
+		},
+		{
+			name: "Success - largest plan allowance applies and grants add on top",
+			subscriptions: []billing.Subscription{
+				{ID: "sub-1", UserID: "user-1", Integrator: "stripe", PlanID: "price_pro_monthly", Status: billing.StatusActive},
+				{ID: "sub-2", UserID: "user-1", Integrator: "stripe", PlanName: "Starter", Status: billing.StatusActive},
+			},
+			grants: []Grant{
+				{SubjectType: SubjectTypeUser, SubjectID: "user-1", FeatureSlug: "projects", Quantity: 5},
+			},
+			wantPlans: []string{"pro", "starter"},
+			assert: func(t *testing.T, set *EntitlementSet) {
+				projects, _ := set.Get("projects")
+				if projects == nil || projects.Quantity != 15 || len(projects.Sources) != 3 {
+					t.Fatalf("expected 15 projects from three sources, got %#v", projects)
+				}
+			},
+		},
+		{
+			name: "Success - cancelled subscription entitles until paid period ends",
+			subscriptions: []billing.Subscription{
+				{ID: "sub-1", UserID: "user-1", PlanName: "Pro", Status: billing.StatusCancelled, AvailableUntilDate: &future},
+				{ID: "sub-2", UserID: "user-1", PlanName: "Starter", Status: billing.StatusCancelled, AvailableUntilDate: &past},
+			},
+			wantPlans: []string{"pro"},
+		},
+		{
+			name: "Success - complimentary subscription entitles until it ends",
+			subscriptions: []billing.Subscription{
+				{ID: "sub-1", UserID: "user-1", PlanName: "Pro", Status: billing.StatusActive, Integrator: billing.IntegratorManual, AvailableUntilDate: &future},
+				{ID: "sub-2", UserID: "user-1", PlanName: "Starter", Status: billing.StatusActive, Integrator: billing.IntegratorManual, AvailableUntilDate: &past},
+			},
+			wantPlans: []string{"pro"},
+		},
+		{
+			name: "Success - revoked and expired grants are ignored",
+			grants: []Grant{
+				{SubjectType: SubjectTypeUser, SubjectID: "user-1", FeatureSlug: "sso", RevokedAt: "2020-01-01T00:00:00"},
+				{SubjectType: SubjectTypeUser, SubjectID: "user-1", FeatureSlug: "beta", ExpiresAt: "2020-01-01T00:00:00"},
+				{SubjectType: SubjectTypeUser, SubjectID: "user-1", FeatureSlug: "reports"},
+			},
+			wantPlans: []string{},
+			assert: func(t *testing.T, set *EntitlementSet) {
+				if len(set.Entitlements) != 1 || set.Entitlements[0].FeatureSlug != "reports" || !set.Entitlements[0].Unlimited {
+					t.Fatalf("expected only unlimited reports, got %#v", set.Entitlements)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, repository := newTestService(tt.subscriptions...)
+			for i := range tt.grants {
+				if _, err := repository.CreateGrant(context.Background(), &tt.grants[i]); err != nil {
+					t.Fatalf("seeding grant: %v", err)
+				}
+			}
+
+			got, err := service.GetEntitlements(context.Background(), &GetEntitlementsRequest{SubjectID: "user-1"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got.EntitlementSet.PlanSlugs) != len(tt.wantPlans) {
+				t.Fatalf("expected plans %v, got %v", tt.wantPlans, got.EntitlementSet.PlanSlugs)
+			}
+			for i, plan := range tt.wantPlans {
+				if got.EntitlementSet.PlanSlugs[i] != plan {
+					t.Fatalf("expected plans %v, got %v", tt.wantPlans, got.EntitlementSet.PlanSlugs)
+				}
+			}
+
+			if tt.assert != nil {
+				tt.assert(t, got.EntitlementSet)
+			}
+		})
+	}
+}
+
+func TestService_GetEntitlements_GroupSubscription(t *testing.T) {
+	service, _, _ := newTestService(
+		billing.Subscription{ID: "sub-1", UserID: "user-1", GroupID: "group-1", Integrator: "stripe", PlanID: "price_pro_monthly", Status: billing.StatusActive},
+		billing.Subscription{ID: "sub-2", UserID: "user-2", PlanName: "Starter", Status: billing.StatusActive},
+	)
+
+	got, err := service.GetEntitlements(context.Background(), &GetEntitlementsRequest{SubjectType: SubjectTypeGroup, SubjectID: "group-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.EntitlementSet.PlanSlugs) != 1 || got.EntitlementSet.PlanSlugs[0] != "pro" {
+		t.Fatalf("expected the group's pro plan, got %v", got.EntitlementSet.PlanSlugs)
+	}
+	projects, ok := got.EntitlementSet.Get("projects")
+	if !ok || projects.Quantity != 10 || projects.Sources[0].ID != "sub-1" {
+		t.Fatalf("expected 10 projects from the group subscription, got %#v", projects)
+	}
+
+	// the buyer gets nothing personally from the plan they bought for the group
+	got, err = service.GetEntitlements(context.Background(), &GetEntitlementsRequest{SubjectID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.EntitlementSet.PlanSlugs) != 0 {
+		t.Fatalf("expected no personal plans for the buyer, got %v", got.EntitlementSet.PlanSlugs)
+	}
+	if _, ok := got.EntitlementSet.Get("projects"); ok {
+		t.Fatalf("expected no personal projects entitlement for the buyer")
+	}
+}
+
+func TestService_GetEntitlements_Cache(t *testing.T) {
+	service, billingService, _ := newTestService(billing.Subscription{ID: "sub-1", UserID: "user-1", PlanName: "Pro", Status: billing.StatusActive})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.GetEntitlements(ctx, &GetEntitlementsRequest{SubjectID: "user-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if billingService.calls != 1 {
+		t.Fatalf("expected cached lookup, got %d billing calls", billingService.calls)
+	}
+
+	service.InvalidateEntitlements(ctx, &InvalidateEntitlementsRequest{SubjectID: "user-1"})
+	if _, err := service.GetEntitlements(ctx, &GetEntitlementsRequest{SubjectID: "user-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if billingService.calls != 2 {
+		t.Fatalf("expected invalidation to resolve again, got %d billing calls", billingService.calls)
+	}
+
+	if _, err := service.GetEntitlements(ctx, &GetEntitlementsRequest{SubjectID: "user-1", SkipCache: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if billingService.calls != 3 {
+		t.Fatalf("expected skip cache to resolve again, got %d billing calls", billingService.calls)
+	}
+}
+
+func TestEntitlementCache_EvictsExpiredEntries(t *testing.T) {
+	cache := newEntitlementCache(time.Minute)
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	cache.set("user:user-1", &EntitlementSet{}, now)
+	cache.set("user:user-2", &EntitlementSet{}, now)
+
+	if _, ok := cache.get("user:user-1", now.Add(2*time.Minute)); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+	if _, ok := cache.entries["user:user-1"]; ok {
+		t.Fatal("expected expired entry to be dropped when read")
+	}
+
+	cache.set("user:user-3", &EntitlementSet{}, now.Add(2*time.Minute))
+	if _, ok := cache.entries["user:user-2"]; ok {
+		t.Fatal("expected expired entry that was never read again to be swept")
+	}
+	if len(cache.entries) != 1 {
+		t.Fatalf("expected only the fresh entry to remain, got %d", len(cache.entries))
+	}
+}
+
+// fakeVersionedPricerService also returns canned price plan versions
+type fakeVersionedPricerService struct {
+	fakePricerService
+	versions map[string]*pricer.PricePlanVersion
+}
+
+func (f *fakeVersionedPricerService) GetPricePlanVersionByID(ctx context.Context, req *pricer.GetPricePlanVersionByIDRequest) (*pricer.GetPricePlanVersionResponse, error) {
+	version, ok := f.versions[req.ID]
+	if !ok {
+		return nil, pricer.ErrPricePlanVersionNotFound
+	}
+
+	return &pricer.GetPricePlanVersionResponse{PricePlanVersion: version}, nil
+}
+
+func TestService_GetEntitlements_GrandfatheredPricePlanVersion(t *testing.T) {
+	pricerService := &fakeVersionedPricerService{
+		fakePricerService: fakePricerService{plans: testPlans()},
+		versions: map[string]*pricer.PricePlanVersion{
+			"version-1": {
+				ID:            "version-1",
+				PricePlanID:   "plan-pro",
+				Version:       1,
+				PricePlanSlug: "pro",
+				Name:          "Pro",
+				Features: []pricer.PlanFeatureRef{
+					{FeatureSlug: "projects", Included: true, Quantity: 25, Unit: "project"},
+				},
+			},
+		},
+	}
+	billingService := &fakeBillingService{subscriptions: []billing.Subscription{
+		{ID: "sub-1", UserID: "user-1", Integrator: "stripe", PlanID: "price_pro_monthly", PricePlanVersionID: "version-1", Status: billing.StatusActive},
+		{ID: "sub-2", UserID: "user-2", Integrator: "stripe", PlanID: "price_pro_monthly", PricePlanVersionID: "version-missing", Status: billing.StatusActive},
+	}}
+	service := NewService(NewInMemoryRepository(), billingService, pricerService)
+
+	got, err := service.GetEntitlements(context.Background(), &GetEntitlementsRequest{SubjectID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if projects, _ := got.EntitlementSet.Get("projects"); projects == nil || projects.Quantity != 25 {
+		t.Fatalf("expected version terms of 25 projects, got %#v", projects)
+	}
+	if _, ok := got.EntitlementSet.Get("exports"); ok {
+		t.Fatalf("expected features missing from the version to be absent")
+	}
+
+	got, err = service.GetEntitlements(context.Background(), &GetEntitlementsRequest{SubjectID: "user-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if projects, _ := got.EntitlementSet.Get("projects"); projects == nil || projects.Quantity != 10 {
+		t.Fatalf("expected fallback to current plan terms of 10 projects, got %#v", projects)
+	}
+}
+
+func TestService_GetEntitlements_Validation(t *testing.T) {
+	service, _, _ := newTestService()
+
+	tests := []struct {
+		name    string
+		request *GetEntitlementsRequest
+		wantErr error
+	}{
+		{name: "Failure - missing subject ID", request: &GetEntitlementsRequest{}, wantErr: ErrSubjectIDIsRequired},
+		{name: "Failure - invalid subject type", request: &GetEntitlementsRequest{SubjectType: "team", SubjectID: "team-1"}, wantErr: ErrInvalidSubjectType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.GetEntitlements(context.Background(), tt.request); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestService_CheckEntitlement(t *testing.T) {
+	service, _, _ := newTestService(billing.Subscription{ID: "sub-1", UserID: "user-1", PlanName: "Starter", Status: billing.StatusActive})
+
+	tests := []struct {
+		name    string
+		request *CheckEntitlementRequest
+		wantErr error
+	}{
+		{name: "Success - feature within allowance", request: &CheckEntitlementRequest{SubjectID: "user-1", FeatureSlug: "projects", Amount: 3}},
+		{name: "Failure - amount exceeds allowance", request: &CheckEntitlementRequest{SubjectID: "user-1", FeatureSlug: "projects", Amount: 4}, wantErr: ErrFeatureNotEntitled},
+		{name: "Failure - feature not included", request: &CheckEntitlementRequest{SubjectID: "user-1", FeatureSlug: "exports"}, wantErr: ErrFeatureNotEntitled},
+		{name: "Failure - missing feature slug", request: &CheckEntitlementRequest{SubjectID: "user-1"}, wantErr: ErrFeatureSlugIsRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CheckEntitlement(context.Background(), tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestService_Check_RequiresUser(t *testing.T) {
+	service, _, _ := newTestService()
+
+	if _, err := service.Check(context.Background(), "projects"); !errors.Is(err, ErrUnableToIdentifyUser) {
+		t.Fatalf("expected %v, got %v", ErrUnableToIdentifyUser, err)
+	}
+}
+
+func TestService_Grants(t *testing.T) {
+	service, _, _ := newTestService()
+	ctx := context.Background()
+
+	if _, err := service.CreateGrant(ctx, &CreateGrantRequest{SubjectID: "user-1", FeatureSlug: "beta", ExpiresAt: "2020-01-01T00:00:00Z"}); !errors.Is(err, ErrInvalidGrantExpiry) {
+		t.Fatalf("expected %v for past expiry, got %v", ErrInvalidGrantExpiry, err)
+	}
+	if _, err := service.CreateGrant(ctx, &CreateGrantRequest{SubjectID: "user-1", FeatureSlug: "beta", Quantity: -1}); !errors.Is(err, ErrInvalidGrantQuantity) {
+		t.Fatalf("expected %v for negative quantity, got %v", ErrInvalidGrantQuantity, err)
+	}
+
+	// Prime the cache so the grant must invalidate it
+	if _, err := service.Check(accessmanagerhelpers.TransitWith(ctx, "user-1"), "beta"); !errors.Is(err, ErrFeatureNotEntitled) {
+		t.Fatalf("expected %v before grant, got %v", ErrFeatureNotEntitled, err)
+	}
+
+	created, err := service.CreateGrant(ctx, &CreateGrantRequest{
+		SubjectID:       "user-1",
+		FeatureSlug:     "beta",
+		ExpiresAt:       time.Now().Add(time.Hour).Format(time.RFC3339),
+		CreatedByUserID: "admin-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating grant: %v", err)
+	}
+	if created.Grant.SubjectType != SubjectTypeUser || created.Grant.ID == "" {
+		t.Fatalf("expected stored user grant, got %#v", created.Grant)
+	}
+
+	if _, err := service.Check(accessmanagerhelpers.TransitWith(ctx, "user-1"), "beta"); err != nil {
+		t.Fatalf("expected grant to entitle user, got %v", err)
+	}
+
+	grants, err := service.GetGrants(ctx, &GetGrantsRequest{SubjectID: "user-1", ActiveOnly: true})
+	if err != nil || grants.Total != 1 {
+		t.Fatalf("expected one active grant, got %#v (%v)", grants, err)
+	}
+
+	revoked, err := service.RevokeGrant(ctx, &RevokeGrantRequest{ID: created.Grant.ID, RevokedByUserID: "admin-1"})
+	if err != nil || revoked.Grant.RevokedAt == "" {
+		t.Fatalf("expected revoked grant, got %#v (%v)", revoked, err)
+	}
+
+	if _, err := service.RevokeGrant(ctx, &RevokeGrantRequest{ID: created.Grant.ID}); err != nil {
+		t.Fatalf("expected revoking twice to be a no-op, got %v", err)
+	}
+
+	if _, err := service.Check(accessmanagerhelpers.TransitWith(ctx, "user-1"), "beta"); !errors.Is(err, ErrFeatureNotEntitled) {
+		t.Fatalf("expected %v after revoke, got %v", ErrFeatureNotEntitled, err)
+	}
+
+	if _, err := service.RevokeGrant(ctx, &RevokeGrantRequest{ID: "missing"}); !errors.Is(err, ErrGrantNotFound) {
+		t.Fatalf("expected %v, got %v", ErrGrantNotFound, err)
+	}
+}
+
+func TestMiddleware_RequireFeature(t *testing.T) {
+	service, _, _ := newTestService(billing.Subscription{ID: "sub-1", UserID: "user-1", PlanName: "Starter", Status: billing.StatusActive})
+	handler := NewMiddleware(service).RequireFeature("projects")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		userID     string
+		wantStatus int
+	}{
+		{name: "Success - entitled user passes", userID: "user-1", wantStatus: http.StatusNoContent},
+		{name: "Failure - user without plan is forbidden", userID: "user-2", wantStatus: http.StatusForbidden},
+		{name: "Failure - anonymous request is unauthorised", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/projects", nil)
+			if tt.userID != "" {
+				req = req.WithContext(accessmanagerhelpers.TransitWith(req.Context(), tt.userID))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
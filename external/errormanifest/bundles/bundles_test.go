@@ -10,8 +10,11 @@ import (
 	"github.com/ooaklee/ghatd/external/billing"
 	"github.com/ooaklee/ghatd/external/billingmanager"
 	"github.com/ooaklee/ghatd/external/contacter"
+	"github.com/ooaklee/ghatd/external/entitlement"
 	"github.com/ooaklee/ghatd/external/ephemeral"
 	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/metering"
 	"github.com/ooaklee/ghatd/external/notifier"
 	"github.com/ooaklee/ghatd/external/paymentprovider"
 	"github.com/ooaklee/ghatd/external/post"
@@ -72,6 +75,9 @@ func TestBundles(t *testing.T) {
 				pricer.PricerErrorMap,
 				paymentprovider.PaymentProviderErrorMap,
 				billing.BillingErrorMap,
+				entitlement.EntitlementErrorMap,
+				metering.MeteringErrorMap,
+				invoice.InvoiceErrorMap,
 				toolbox.ToolboxErrorMap,
 				user.UserErrorMap,
 			},
@@ -3,6 +3,7 @@ package group
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 // DefaultGroupConfig returns a default configuration
@@ -144,6 +145,40 @@ func (c *GroupConfig) WithMultipleIdentifiers(allow bool) *GroupConfig {
 	return c
 }
 
+// WithPermissions registers permissions in addition to BuiltInPermissions
+func (c *GroupConfig) WithPermissions(permissions ...string) *GroupConfig {
+	c.Permissions = append(c.Permissions, permissions...)
+	return c
+}
+
+// WithRolePermissions sets the permissions a role grants for every group type
+func (c *GroupConfig) WithRolePermissions(role string, permissions ...string) *GroupConfig {
+	if c.RolePermissions == nil {
+		c.RolePermissions = DefaultRolePermissions()
+	}
+	c.RolePermissions[role] = permissions
+	return c
+}
+
+// WithTypeRolePermissions sets the permissions a role grants for a group type,
+// overriding the role's permissions for other types
+func (c *GroupConfig) WithTypeRolePermissions(groupType, role string, permissions ...string) *GroupConfig {
+	if c.TypeToRolePermissions == nil {
+		c.TypeToRolePermissions = map[string]map[string][]string{}
+	}
+	if c.TypeToRolePermissions[groupType] == nil {
+		c.TypeToRolePermissions[groupType] = map[string][]string{}
+	}
+	c.TypeToRolePermissions[groupType][role] = permissions
+	return c
+}
+
+// WithPermissionInheritance sets which permissions apply to descendant groups
+func (c *GroupConfig) WithPermissionInheritance(inheritance string) *GroupConfig {
+	c.PermissionInheritance = inheritance
+	return c
+}
+
 // toGroupConfigCapabilities converts GroupConfig to GroupConfigCapabilities response DTO.
 func (c *GroupConfig) toGroupConfigCapabilities() *GroupConfigCapabilities {
 	if c == nil {
@@ -178,6 +213,12 @@ func (c *GroupConfig) toGroupConfigCapabilities() *GroupConfigCapabilities {
 			Default:       c.DefaultRoles,
 			TypeOverrides: c.TypeToRoleOverrides,
 		},
+		Permissions: Permissions{
+			Registered:    c.RegisteredPermissions(),
+			Roles:         c.getRolePermissions(),
+			TypeOverrides: c.TypeToRolePermissions,
+			Inheritance:   c.GetPermissionInheritance(),
+		},
 	}
 }
 
@@ -197,6 +238,130 @@ func (c *GroupConfig) IsValidType(groupType string) bool {
 	return false
 }
 
+// RegisteredPermissions returns the built-in permissions and those registered by the host, sorted.
+func (c *GroupConfig) RegisteredPermissions() []string {
+	permissions := append([]string{}, BuiltInPermissions...)
+	if c != nil {
+		permissions = append(permissions, c.Permissions...)
+	}
+	return uniqueSortedStrings(permissions)
+}
+
+// IsRegisteredPermission reports whether a permission is built-in or registered by the host.
+func (c *GroupConfig) IsRegisteredPermission(permission string) bool {
+	for _, registeredPermission := range c.RegisteredPermissions() {
+		if registeredPermission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPermissionInheritance returns the configured permission inheritance, defaulting to
+// PermissionInheritanceManagers.
+func (c *GroupConfig) GetPermissionInheritance() string {
+	if c == nil || c.PermissionInheritance == "" {
+		return PermissionInheritanceManagers
+	}
+	return c.PermissionInheritance
+}
+
+// GetRolePermissions returns the permissions a configured role grants in groups of the given
+// type, with PermissionAll expanded. The second value is false when the role is not configured.
+func (c *GroupConfig) GetRolePermissions(groupType, role string) ([]string, bool) {
+	normalisedRole := strings.ToUpper(strings.TrimSpace(role))
+
+	var permissions []string
+	found := false
+	if c != nil {
+		permissions, found = c.TypeToRolePermissions[groupType][normalisedRole]
+	}
+	if !found {
+		permissions, found = c.getRolePermissions()[normalisedRole]
+	}
+	if !found {
+		return nil, false
+	}
+
+	return c.expandPermissions(permissions), true
+}
+
+// HasConfiguredRole reports whether a role is defined by the config for a group type,
+// either through its permissions or as an allowed member role.
+func (c *GroupConfig) HasConfiguredRole(groupType, role string) bool {
+	normalisedRole := strings.ToUpper(strings.TrimSpace(role))
+	if normalisedRole == MemberRoleOwner {
+		return true
+	}
+
+	if _, found := c.GetRolePermissions(groupType, normalisedRole); found {
+		return true
+	}
+
+	if c == nil {
+		return false
+	}
+
+	configuredRoles := append(append([]string{}, c.DefaultRoles...), c.TypeToRoleOverrides[groupType]...)
+	for _, configuredRole := range configuredRoles {
+		if strings.ToUpper(strings.TrimSpace(configuredRole)) == normalisedRole {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidatePermissions checks that roles only grant registered permissions and that the
+// permission inheritance is recognised.
+func (c *GroupConfig) ValidatePermissions() error {
+	if c == nil {
+		return nil
+	}
+
+	switch c.GetPermissionInheritance() {
+	case PermissionInheritanceManagers, PermissionInheritanceAll, PermissionInheritanceNone:
+	default:
+		return fmt.Errorf("group permission inheritance %q is not recognised", c.PermissionInheritance)
+	}
+
+	rolePermissionSets := []map[string][]string{c.getRolePermissions()}
+	for _, typeRolePermissions := range c.TypeToRolePermissions {
+		rolePermissionSets = append(rolePermissionSets, typeRolePermissions)
+	}
+
+	for _, rolePermissions := range rolePermissionSets {
+		for role, permissions := range rolePermissions {
+			for _, permission := range permissions {
+				if permission != PermissionAll && !c.IsRegisteredPermission(permission) {
+					return fmt.Errorf("group role %q grants unregistered permission %q", role, permission)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// getRolePermissions returns the configured role permissions, falling back to
+// DefaultRolePermissions when none are set.
+func (c *GroupConfig) getRolePermissions() map[string][]string {
+	if c == nil || c.RolePermissions == nil {
+		return DefaultRolePermissions()
+	}
+	return c.RolePermissions
+}
+
+// expandPermissions replaces PermissionAll with every registered permission.
+func (c *GroupConfig) expandPermissions(permissions []string) []string {
+	for _, permission := range permissions {
+		if permission == PermissionAll {
+			return c.RegisteredPermissions()
+		}
+	}
+	return uniqueSortedStrings(permissions)
+}
+
 // EffectiveTree returns the hierarchy rules that should be used by callers.
 // When no explicit tree is provided, every valid type can have every valid type as a child.
 func (c *GroupConfig) EffectiveTree() map[string][]string {
@@ -369,3 +534,14 @@ func uniqueSortedStrings(values []string) []string {
 	sort.Strings(unique)
 	return unique
 }
+
+// DefaultInvitationConfig returns the default invitation configuration signed with the passed secret
+func DefaultInvitationConfig(signingSecret, acceptURL string) *InvitationConfig {
+	return &InvitationConfig{
+		SigningSecret:  signingSecret,
+		AcceptURL:      acceptURL,
+		Expiry:         DefaultInvitationExpiry,
+		ResendCooldown: DefaultInvitationResendCooldown,
+		MaxSends:       DefaultInvitationMaxSends,
+	}
+}
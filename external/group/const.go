@@ -1,5 +1,7 @@
 package group
 
+import "time"
+
 // DefaultRoles is the default set of roles that can be assigned to members when a group type does not have specific roles defined in the configuration.
 var DefaultRoles = []string{"MEMBER", "ADMIN"}
 
@@ -39,8 +41,13 @@ const (
 	MemberRoleCoordinator = "COORDINATOR"
 	MemberRoleSuperUser   = "SUPERUSER"
 
+	// MemberRoleBillingAdmin grants a member the ability to manage the group's
+	// subscriptions and seats without full group administration rights
+	MemberRoleBillingAdmin = "BILLING_ADMIN"
+
 	// Invitation state keys for member lifecycle
 	MemberInvitationStateInvited = "INVITED"
+	MemberInvitationStateExpired = "EXPIRED"
 
 	// Member metadata keys
 	MemberMetadataKeyInvitedByID         = "invited_by_id"
@@ -48,10 +55,29 @@ const (
 	MemberMetadataKeyInviteTargetGroupID = "invite_target_group_id"
 	MemberMetadataKeyAutoJoinSource      = "auto_join_source"
 	MemberMetadataKeyAutoInviteSource    = "auto_invite_source"
+	MemberMetadataKeyInviteTokenID       = "invite_token_id"
+	MemberMetadataKeyInviteExpiresAt     = "invite_expires_at"
+	MemberMetadataKeyInviteLastSentAt    = "invite_last_sent_at"
+	MemberMetadataKeyInviteSendCount     = "invite_send_count"
+	MemberMetadataKeyInviteExpiredAt     = "invite_expired_at"
 
 	// Auto-action source values
 	MemberAutoActionSourceSignup = "signup"
 
+	// Join request status keys
+
+	// JoinRequestStatusPending indicates the request is awaiting review by a group admin.
+	JoinRequestStatusPending = "PENDING"
+
+	// JoinRequestStatusApproved indicates the requester was added to the group.
+	JoinRequestStatusApproved = "APPROVED"
+
+	// JoinRequestStatusDenied indicates a group admin declined the request.
+	JoinRequestStatusDenied = "DENIED"
+
+	// JoinRequestStatusCancelled indicates the requester withdrew the request.
+	JoinRequestStatusCancelled = "CANCELLED"
+
 	// Visibility Keys
 
 	// VisibilityPublic indicates that the group is visible to everyone.
@@ -63,8 +89,220 @@ const (
 	// VisibilityInternal indicates that the group is visible only to its members and
 	// to non-members within the same hierarchy or who share the same root, if applicable.
 	VisibilityInternal = "INTERNAL"
+
+	// Integration event keys, used by integrations such as Slack to choose which events a
+	// group is notified about
+
+	// IntegrationEventMemberJoined indicates a member was added to the group.
+	IntegrationEventMemberJoined = "MEMBER_JOINED"
+
+	// IntegrationEventInviteAccepted indicates an invitee accepted their invitation to the group.
+	IntegrationEventInviteAccepted = "INVITE_ACCEPTED"
+
+	// IntegrationEventReminderDue indicates a reminder owned by or targeting the group is due.
+	// The group service never raises it, applications post it to the integration themselves.
+	IntegrationEventReminderDue = "REMINDER_DUE"
+
+	// IntegrationEventVisionStatusChanged indicates a roadmap item followed by the group changed status.
+	// The group service never raises it, applications post it to the integration themselves.
+	IntegrationEventVisionStatusChanged = "VISION_STATUS_CHANGED"
+
+	// Member import status keys
+
+	// MemberImportStatusPending indicates the import is waiting to be processed.
+	MemberImportStatusPending = "PENDING"
+
+	// MemberImportStatusRunning indicates the import's rows are being processed.
+	MemberImportStatusRunning = "RUNNING"
+
+	// MemberImportStatusCompleted indicates every row of the import was processed.
+	MemberImportStatusCompleted = "COMPLETED"
+
+	// MemberImportStatusFailed indicates the import stopped before every row was processed.
+	MemberImportStatusFailed = "FAILED"
+
+	// Member import row status keys
+
+	// MemberImportRowStatusValid indicates the row passed validation and is waiting to be processed.
+	MemberImportRowStatusValid = "VALID"
+
+	// MemberImportRowStatusInvalid indicates the row failed validation and will not be processed.
+	MemberImportRowStatusInvalid = "INVALID"
+
+	// MemberImportRowStatusAdded indicates the row's user was added to the group.
+	MemberImportRowStatusAdded = "ADDED"
+
+	// MemberImportRowStatusInvited indicates the row's email was invited to the group.
+	MemberImportRowStatusInvited = "INVITED"
+
+	// MemberImportRowStatusSkipped indicates the row's user is already a member or invitee.
+	MemberImportRowStatusSkipped = "SKIPPED"
+
+	// MemberImportRowStatusFailed indicates the row could not be added or invited.
+	MemberImportRowStatusFailed = "FAILED"
+
+	// Member import row action keys
+
+	// MemberImportActionAdd indicates the row's email belongs to an account that will be added.
+	MemberImportActionAdd = "ADD"
+
+	// MemberImportActionInvite indicates the row's email has no account and will be invited.
+	MemberImportActionInvite = "INVITE"
+
+	// MemberImportActionSkip indicates the row's user is already a member or invitee.
+	MemberImportActionSkip = "SKIP"
+
+	// Member import issue keys
+
+	// MemberImportIssueInvalidEmail indicates the row's email is missing or malformed.
+	MemberImportIssueInvalidEmail = "INVALID_EMAIL"
+
+	// MemberImportIssueUnknownRole indicates the row's role is not valid for the group.
+	MemberImportIssueUnknownRole = "UNKNOWN_ROLE"
+
+	// MemberImportIssueDuplicate indicates the row's email appears earlier in the import.
+	MemberImportIssueDuplicate = "DUPLICATE"
+
+	// MemberImportIssueAlreadyMember indicates the row's user is already a member or invitee.
+	MemberImportIssueAlreadyMember = "ALREADY_MEMBER"
+
+	// MemberImportIssueOverMaxMembers indicates the row would take the group over its maximum members.
+	MemberImportIssueOverMaxMembers = "OVER_MAX_MEMBERS"
+
+	// MemberImportIssueInviteNotAllowed indicates the row's email has no account and the group
+	// cannot take invitations because it is not a top-level group.
+	MemberImportIssueInviteNotAllowed = "INVITE_NOT_ALLOWED"
+
+	// MemberImportIssueAutoJoinDomainConflict indicates the row's email domain is auto-joined or
+	// auto-invited by the group with a different role to the one imported.
+	MemberImportIssueAutoJoinDomainConflict = "AUTO_JOIN_DOMAIN_CONFLICT"
+
+	// Member import issue severity keys
+
+	// MemberImportSeverityError marks issues that stop the row being imported.
+	MemberImportSeverityError = "ERROR"
+
+	// MemberImportSeverityWarning marks issues that are reported but do not stop the row being imported.
+	MemberImportSeverityWarning = "WARNING"
+
+	// Member import format keys
+
+	// MemberImportFormatCSV reads and writes members as CSV with an email and role column.
+	MemberImportFormatCSV = "csv"
+
+	// MemberImportFormatJSON reads and writes members as a JSON array.
+	MemberImportFormatJSON = "json"
 )
 
+const (
+	// Permission Keys
+
+	// PermissionAll grants every registered permission, it can only be used in role configuration.
+	PermissionAll = "*"
+
+	// PermissionGroupView allows viewing the group, its lineage and its descendants.
+	PermissionGroupView = "group.view"
+
+	// PermissionGroupDelete allows deleting the group.
+	PermissionGroupDelete = "group.delete"
+
+	// PermissionSettingsUpdate allows updating the group's details and settings.
+	PermissionSettingsUpdate = "settings.update"
+
+	// PermissionSubgroupsCreate allows creating groups beneath the group.
+	PermissionSubgroupsCreate = "subgroups.create"
+
+	// PermissionMembersView allows listing the group's members.
+	PermissionMembersView = "members.view"
+
+	// PermissionMembersInvite allows inviting users to the group.
+	PermissionMembersInvite = "members.invite"
+
+	// PermissionMembersManage allows adding and removing members and changing their roles.
+	// Roles granting it are treated as the group's admins.
+	PermissionMembersManage = "members.manage"
+
+	// PermissionOwnerTransfer allows changing the group's owner.
+	PermissionOwnerTransfer = "owner.transfer"
+
+	// PermissionJoinRequestsReview allows approving and denying requests to join the group.
+	PermissionJoinRequestsReview = "join_requests.review"
+
+	// PermissionRolesManage allows creating, updating and deleting the group's custom roles.
+	PermissionRolesManage = "roles.manage"
+
+	// PermissionBillingManage allows managing the group's subscriptions and seats.
+	PermissionBillingManage = "billing.manage"
+
+	// PermissionActivityView allows viewing the group's activity feed.
+	PermissionActivityView = "activity.view"
+
+	// PermissionSCIMManage allows issuing and revoking the group's SCIM provisioning tokens.
+	PermissionSCIMManage = "scim.manage"
+
+	// Permission Inheritance Keys
+
+	// PermissionInheritanceManagers applies permissions granted by roles with
+	// PermissionMembersManage to every descendant group.
+	PermissionInheritanceManagers = "MANAGERS"
+
+	// PermissionInheritanceAll applies permissions granted by any role to every descendant group.
+	PermissionInheritanceAll = "ALL"
+
+	// PermissionInheritanceNone limits permissions to the groups a user holds a role in.
+	PermissionInheritanceNone = "NONE"
+)
+
+// BuiltInPermissions is the set of permissions the group package checks itself. Hosts can
+// register more with GroupConfig.WithPermissions.
+var BuiltInPermissions = []string{
+	PermissionGroupView,
+	PermissionGroupDelete,
+	PermissionSettingsUpdate,
+	PermissionSubgroupsCreate,
+	PermissionMembersView,
+	PermissionMembersInvite,
+	PermissionMembersManage,
+	PermissionOwnerTransfer,
+	PermissionJoinRequestsReview,
+	PermissionRolesManage,
+	PermissionBillingManage,
+	PermissionActivityView,
+	PermissionSCIMManage,
+}
+
+// DefaultRolePermissions returns the permissions granted by each built-in role when
+// a config does not set its own.
+func DefaultRolePermissions() map[string][]string {
+	adminPermissions := []string{
+		PermissionGroupView,
+		PermissionSettingsUpdate,
+		PermissionSubgroupsCreate,
+		PermissionMembersView,
+		PermissionMembersInvite,
+		PermissionMembersManage,
+		PermissionOwnerTransfer,
+		PermissionJoinRequestsReview,
+		PermissionRolesManage,
+		PermissionActivityView,
+		PermissionSCIMManage,
+	}
+	memberPermissions := []string{PermissionGroupView, PermissionMembersView}
+
+	return map[string][]string{
+		MemberRoleOwner:        {PermissionAll},
+		MemberRoleAdmin:        append(append([]string{}, adminPermissions...), PermissionBillingManage),
+		MemberRoleSuperUser:    adminPermissions,
+		MemberRoleBillingAdmin: {PermissionGroupView, PermissionMembersView, PermissionBillingManage},
+		MemberRoleHead:         memberPermissions,
+		MemberRoleLead:         memberPermissions,
+		MemberRoleCoordinator:  memberPermissions,
+		MemberRoleModerator:    memberPermissions,
+		MemberRoleMember:       memberPermissions,
+		MemberRoleGuest:        {PermissionGroupView},
+	}
+}
+
 const (
 	// Error Keys
 	ErrKeyGroupConfigNotSet                = "GroupConfigNotSet"
@@ -107,6 +345,32 @@ const (
 	ErrKeyInvalidEmailDomain               = "InvalidEmailDomain"
 	ErrKeyGroupInvalidEmailFormat          = "GroupInvalidEmailFormat"
 	ErrKeyGroupEmailIsRequired             = "GroupEmailIsRequired"
+	ErrKeyMaxMembersReached                = "MaxMembersReached"
+	ErrKeyJoinRequestsNotEnabled           = "GroupJoinRequestsNotEnabled"
+	ErrKeyJoinRequestNotFound              = "GroupJoinRequestNotFound"
+	ErrKeyJoinRequestAlreadyExists         = "GroupJoinRequestAlreadyExists"
+	ErrKeyJoinRequestNotPending            = "GroupJoinRequestNotPending"
+	ErrKeyGroupNotJoinable                 = "GroupNotJoinable"
+	ErrKeyInvitationsNotEnabled            = "GroupInvitationsNotEnabled"
+	ErrKeyInvalidInvitationToken           = "GroupInvalidInvitationToken"
+	ErrKeyInvitationExpired                = "GroupInvitationExpired"
+	ErrKeyInvitationResendRateLimited      = "GroupInvitationResendRateLimited"
+	ErrKeyInvitationEmailMismatch          = "GroupInvitationEmailMismatch"
+	ErrKeyUnknownPermission                = "GroupUnknownPermission"
+	ErrKeyInvalidCustomRole                = "GroupInvalidCustomRole"
+	ErrKeyCustomRoleConflict               = "GroupCustomRoleConflict"
+	ErrKeyCustomRoleNotFound               = "GroupCustomRoleNotFound"
+	ErrKeyCustomRoleInUse                  = "GroupCustomRoleInUse"
+	ErrKeyInvalidRolePermissions           = "InvalidGroupRolePermissions"
+	ErrKeyActivityFeedNotEnabled           = "GroupActivityFeedNotEnabled"
+	ErrKeyInvalidActivityCursor            = "GroupInvalidActivityCursor"
+	ErrKeyInvalidActivityTimeRange         = "GroupInvalidActivityTimeRange"
+	ErrKeyMemberImportsNotEnabled          = "GroupMemberImportsNotEnabled"
+	ErrKeyMemberImportNotFound             = "GroupMemberImportNotFound"
+	ErrKeyInvalidMemberImport              = "GroupInvalidMemberImport"
+	ErrKeyMemberImportTooLarge             = "GroupMemberImportTooLarge"
+	ErrKeyUnsupportedMemberImportFormat    = "GroupUnsupportedMemberImportFormat"
+	ErrKeyMemberImportAlreadyClaimed       = "GroupMemberImportAlreadyClaimed"
 )
 
 const (
@@ -120,3 +384,73 @@ const (
 	GetGroupOrderMemberCountDesc = "member_count_desc"
 	GetGroupOrderMemberCountAsc  = "member_count_asc"
 )
+
+const (
+	// DefaultInvitationExpiry is how long an invitation stays valid when no expiry is configured
+	DefaultInvitationExpiry = 7 * 24 * time.Hour
+
+	// DefaultInvitationResendCooldown is the minimum time between sends of the same invitation
+	DefaultInvitationResendCooldown = 15 * time.Minute
+
+	// DefaultInvitationMaxSends is how many times an invitation can be sent before it must be recreated
+	DefaultInvitationMaxSends = 5
+
+	// defaultInvitationExpiryInterval is how often stale invitations are swept when no interval is given
+	defaultInvitationExpiryInterval = time.Hour
+
+	// InvitationTokenQueryParam is the query parameter the invitation token is appended to the accept URL with
+	InvitationTokenQueryParam = "token"
+)
+
+const (
+	// DefaultMaxMemberImportRows is the most rows a single member import can hold
+	DefaultMaxMemberImportRows = 5000
+
+	// memberImportProgressInterval is how many rows are processed between saves of an import's progress
+	memberImportProgressInterval = 25
+
+	// memberImportHeartbeatInterval is how often the service processing an import records that it
+	// is still working on it
+	memberImportHeartbeatInterval = time.Minute
+
+	// memberImportStaleAfter is how long an import can go without a heartbeat before it is treated
+	// as abandoned and can be claimed by another service
+	memberImportStaleAfter = 5 * time.Minute
+)
+
+const (
+	// integrationDeliveryWorkers is how many integration events are delivered at the same time
+	integrationDeliveryWorkers = 4
+
+	// integrationDeliveryQueueSize is how many integration events can wait to be delivered,
+	// events raised while the queue is full are dropped
+	integrationDeliveryQueueSize = 256
+
+	// integrationDeliveryTimeout bounds how long a single event's delivery, including its
+	// retries, can take
+	integrationDeliveryTimeout = 2 * time.Minute
+)
+
+const (
+	// InvitationEmailSubjectTmpl is the subject of an invitation email, formatted with the group name
+	InvitationEmailSubjectTmpl = "You have been invited to join %s"
+
+	// InvitationEmailMessageTmpl is the plain text of an invitation email, formatted with the group
+	// name and the date the invitation expires
+	InvitationEmailMessageTmpl = "You have been invited to join %s. The invitation is valid until %s."
+
+	// InvitationEmailBodyTmpl is the template for the body of invitation emails, formatted with the
+	// HTML escaped message and accept link
+	InvitationEmailBodyTmpl string = `<td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">
+	<br>
+	<p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 15px;">
+		%s
+	</p>
+	<p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 15px;">
+		<a href="%s" target="_blank">Accept the invitation</a>
+	</p>
+	<p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 15px;">
+		If you do not have an account yet, you will be asked to create one first.
+	</p>
+</td>`
+)
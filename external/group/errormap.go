@@ -211,4 +211,134 @@ var GroupErrorMap reply.ErrorManifest = reply.ErrorManifest{
 		Code:       "GRP0-038",
 		Detail:     "An email is required to process this request",
 	},
+	ErrMaxMembersReached: {
+		StatusCode: http.StatusConflict,
+		Code:       "GRP0-039",
+		Detail:     "The group has reached its maximum number of members",
+	},
+	ErrJoinRequestsNotEnabled: {
+		StatusCode: http.StatusNotImplemented,
+		Code:       "GRP0-040",
+		Detail:     "Group join requests are not enabled",
+	},
+	ErrJoinRequestNotFound: {
+		StatusCode: http.StatusNotFound,
+		Code:       "GRP0-041",
+		Detail:     "The requested join request could not be found",
+	},
+	ErrJoinRequestAlreadyExists: {
+		StatusCode: http.StatusConflict,
+		Code:       "GRP0-042",
+		Detail:     "A pending join request already exists for this group",
+	},
+	ErrJoinRequestNotPending: {
+		StatusCode: http.StatusConflict,
+		Code:       "GRP0-043",
+		Detail:     "The join request has already been resolved",
+	},
+	ErrGroupNotJoinable: {
+		StatusCode: http.StatusForbidden,
+		Code:       "GRP0-044",
+		Detail:     "The group does not accept join requests",
+	},
+	ErrInvitationsNotEnabled: {
+		StatusCode: http.StatusNotImplemented,
+		Code:       "GRP0-045",
+		Detail:     "Group invitation delivery is not enabled",
+	},
+	ErrInvalidInvitationToken: {
+		StatusCode: http.StatusBadRequest,
+		Code:       "GRP0-046",
+		Detail:     "The invitation link is invalid or has already been used",
+	},
+	ErrInvitationExpired: {
+		StatusCode: http.StatusGone,
+		Code:       "GRP0-047",
+		Detail:     "The invitation has expired",
+	},
+	ErrInvitationResendRateLimited: {
+		StatusCode: http.StatusTooManyRequests,
+		Code:       "GRP0-048",
+		Detail:     "The invitation was sent recently, please wait before resending",
+	},
+	ErrInvitationEmailMismatch: {
+		StatusCode: http.StatusForbidden,
+		Code:       "GRP0-049",
+		Detail:     "The invitation was sent to a different email address",
+	},
+	ErrUnknownPermission: {
+		StatusCode: http.StatusBadRequest,
+		Code:       "GRP0-050",
+		Detail:     "One or more permissions are not registered",
+	},
+	ErrInvalidCustomRole: {
+		StatusCode: http.StatusBadRequest,
+		Code:       "GRP0-051",
+		Detail:     "Custom role names must be letters, numbers or underscores and grant at least one permission",
+	},
+	ErrCustomRoleConflict: {
+		StatusCode: http.StatusConflict,
+		Code:       "GRP0-052",
+		Detail:     "A role with this name is already defined for the group type",
+	},
+	ErrCustomRoleNotFound: {
+		StatusCode: http.StatusNotFound,
+		Code:       "GRP0-053",
+		Detail:     "Custom role not found",
+	},
+	ErrCustomRoleInUse: {
+		StatusCode: http.StatusConflict,
+		Code:       "GRP0-054",
+		Detail:     "The custom role is still assigned to members",
+	},
+	ErrInvalidRolePermissions: {
+		StatusCode: http.StatusInternalServerError,
+		Code:       "GRP0-055",
+		Detail:     "Group role permissions are misconfigured",
+	},
+	ErrActivityFeedNotEnabled: {
+		StatusCode: http.StatusNotImplemented,
+		Code:       "GRP0-056",
+		Detail:     "The group activity feed is not enabled",
+	},
+	ErrInvalidActivityCursor: {
+		StatusCode: http.StatusBadRequest,
+		Code:       "GRP0-057",
+		Detail:     "The activity cursor is invalid",
+	},
+	ErrInvalidActivityTimeRange: {
+		StatusCode: http.StatusBadRequest,
+		Code:       "GRP0-058",
+		Detail:     "Activity time range must be RFC 3339 timestamps with from before to",
+	},
+	ErrMemberImportsNotEnabled: {
+		StatusCode: http.StatusNotImplemented,
+		Code:       "GRP0-059",
+		Detail:     "Bulk member imports are not enabled",
+	},
+	ErrMemberImportNotFound: {
+		StatusCode: http.StatusNotFound,
+		Code:       "GRP0-060",
+		Detail:     "The requested member import could not be found",
+	},
+	ErrInvalidMemberImport: {
+		StatusCode: http.StatusBadRequest,
+		Code:       "GRP0-061",
+		Detail:     "Member import must contain at least one row with an email column",
+	},
+	ErrMemberImportTooLarge: {
+		StatusCode: http.StatusRequestEntityTooLarge,
+		Code:       "GRP0-062",
+		Detail:     "Member import has more rows than are allowed in a single import",
+	},
+	ErrUnsupportedMemberImportFormat: {
+		StatusCode: http.StatusUnsupportedMediaType,
+		Code:       "GRP0-063",
+		Detail:     "Member imports and exports must be CSV or JSON",
+	},
+	ErrMemberImportAlreadyClaimed: {
+		StatusCode: http.StatusConflict,
+		Code:       "GRP0-064",
+		Detail:     "The member import is already being processed",
+	},
 }
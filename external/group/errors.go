@@ -3,35 +3,59 @@ package group
 import "errors"
 
 var (
+	ErrActivityFeedNotEnabled           = errors.New(ErrKeyActivityFeedNotEnabled)
 	ErrBothAutoJoinAndAutoInviteEnabled = errors.New(ErrKeyBothAutoJoinAndAutoInviteEnabled)
 	ErrCircularReferenceDetected        = errors.New(ErrKeyCircularReferenceDetected)
+	ErrCustomRoleConflict               = errors.New(ErrKeyCustomRoleConflict)
+	ErrCustomRoleInUse                  = errors.New(ErrKeyCustomRoleInUse)
+	ErrCustomRoleNotFound               = errors.New(ErrKeyCustomRoleNotFound)
 	ErrDatabaseError                    = errors.New(ErrKeyDatabaseError)
 	ErrGroupConfigNotSet                = errors.New(ErrKeyGroupConfigNotSet)
 	ErrGroupDependedOnByOtherGroups     = errors.New(ErrKeyGroupDependedOnByOtherGroups)
 	ErrGroupEmailIsRequired             = errors.New(ErrKeyGroupEmailIsRequired)
 	ErrGroupInvalidEmailFormat          = errors.New(ErrKeyGroupInvalidEmailFormat)
+	ErrGroupNotJoinable                 = errors.New(ErrKeyGroupNotJoinable)
 	ErrInsufficientPermissions          = errors.New(ErrKeyInsufficientPermissions)
+	ErrInvalidActivityCursor            = errors.New(ErrKeyInvalidActivityCursor)
+	ErrInvalidActivityTimeRange         = errors.New(ErrKeyInvalidActivityTimeRange)
+	ErrInvalidCustomRole                = errors.New(ErrKeyInvalidCustomRole)
 	ErrInvalidEmailDomain               = errors.New(ErrKeyInvalidEmailDomain)
 	ErrInvalidGroupBody                 = errors.New(ErrKeyInvalidGroupBody)
 	ErrInvalidGroupHierarchyTree        = errors.New(ErrKeyInvalidGroupHierarchyTree)
 	ErrInvalidGroupID                   = errors.New(ErrKeyInvalidGroupID)
 	ErrInvalidGroupStatus               = errors.New(ErrKeyInvalidGroupStatus)
 	ErrInvalidGroupType                 = errors.New(ErrKeyInvalidGroupType)
+	ErrInvalidInvitationToken           = errors.New(ErrKeyInvalidInvitationToken)
 	ErrInvalidInvitationState           = errors.New(ErrKeyInvalidInvitationState)
 	ErrInvalidInviteEmail               = errors.New(ErrKeyInvalidInviteEmail)
 	ErrInvalidMemberID                  = errors.New(ErrKeyInvalidMemberID)
+	ErrInvalidMemberImport              = errors.New(ErrKeyInvalidMemberImport)
 	ErrInvalidMemberRole                = errors.New(ErrKeyInvalidMemberRole)
 	ErrInvalidMemberType                = errors.New(ErrKeyInvalidMemberType)
 	ErrInvalidNanoID                    = errors.New(ErrKeyInvalidNanoID)
 	ErrInvalidParentChildRelation       = errors.New(ErrKeyInvalidParentChildRelation)
 	ErrInvalidQueryParam                = errors.New(ErrKeyInvalidQueryParam)
+	ErrInvalidRolePermissions           = errors.New(ErrKeyInvalidRolePermissions)
 	ErrInvalidStatusTransition          = errors.New(ErrKeyInvalidStatusTransition)
 	ErrInvalidUserIDProvided            = errors.New(ErrKeyInvalidUserIDProvided)
 	ErrInvitationAlreadyExists          = errors.New(ErrKeyInvitationAlreadyExists)
+	ErrInvitationEmailMismatch          = errors.New(ErrKeyInvitationEmailMismatch)
+	ErrInvitationExpired                = errors.New(ErrKeyInvitationExpired)
 	ErrInvitationNotFound               = errors.New(ErrKeyInvitationNotFound)
+	ErrInvitationResendRateLimited      = errors.New(ErrKeyInvitationResendRateLimited)
+	ErrInvitationsNotEnabled            = errors.New(ErrKeyInvitationsNotEnabled)
 	ErrInviteRequiresTopLevelGroup      = errors.New(ErrKeyInviteRequiresTopLevelGroup)
+	ErrJoinRequestAlreadyExists         = errors.New(ErrKeyJoinRequestAlreadyExists)
+	ErrJoinRequestNotFound              = errors.New(ErrKeyJoinRequestNotFound)
+	ErrJoinRequestNotPending            = errors.New(ErrKeyJoinRequestNotPending)
+	ErrJoinRequestsNotEnabled           = errors.New(ErrKeyJoinRequestsNotEnabled)
+	ErrMaxMembersReached                = errors.New(ErrKeyMaxMembersReached)
 	ErrMaxDepthExceeded                 = errors.New(ErrKeyMaxDepthExceeded)
 	ErrMemberAlreadyExists              = errors.New(ErrKeyMemberAlreadyExists)
+	ErrMemberImportAlreadyClaimed       = errors.New(ErrKeyMemberImportAlreadyClaimed)
+	ErrMemberImportNotFound             = errors.New(ErrKeyMemberImportNotFound)
+	ErrMemberImportTooLarge             = errors.New(ErrKeyMemberImportTooLarge)
+	ErrMemberImportsNotEnabled          = errors.New(ErrKeyMemberImportsNotEnabled)
 	ErrMemberNotFound                   = errors.New(ErrKeyMemberNotFound)
 	ErrNameAlreadyExists                = errors.New(ErrKeyNameAlreadyExists)
 	ErrNoChangesDetected                = errors.New(ErrKeyNoChangesDetected)
@@ -42,5 +66,7 @@ var (
 	ErrResourceNotFound                 = errors.New(ErrKeyResourceNotFound)
 	ErrUnableToFindGroupWithName        = errors.New(ErrKeyUnableToFindGroupWithName)
 	ErrUnableToIdentifyUser             = errors.New(ErrKeyUnableToIdentifyUser)
+	ErrUnknownPermission                = errors.New(ErrKeyUnknownPermission)
+	ErrUnsupportedMemberImportFormat    = errors.New(ErrKeyUnsupportedMemberImportFormat)
 	ErrValidationFailed                 = errors.New(ErrKeyValidationFailed)
 )
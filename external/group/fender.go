@@ -23,6 +23,8 @@ func MapRequestToCreateGroupRequest(request *http.Request, validator GroupValida
 		return nil, ErrValidationFailed
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
 	return parsedRequest, nil
 }
 
@@ -130,6 +132,8 @@ func MapRequestToUpdateGroupRequest(request *http.Request, validator GroupValida
 		return nil, ErrValidationFailed
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
 	return parsedRequest, nil
 }
 
@@ -247,6 +251,8 @@ func MapRequestToAddMemberRequest(request *http.Request, validator GroupValidato
 		return nil, ErrValidationFailed
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
 	return parsedRequest, nil
 }
 
@@ -272,6 +278,33 @@ func MapRequestToInviteUserRequest(request *http.Request, validator GroupValidat
 	return parsedRequest, nil
 }
 
+// MapRequestToResendInviteRequest maps incoming ResendInvite request to correct struct
+func MapRequestToResendInviteRequest(request *http.Request, validator GroupValidator) (*ResendInviteRequest, error) {
+	var err error
+	parsedRequest := &ResendInviteRequest{}
+
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, "groupID")
+	if err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	err = toolbox.DecodeRequestBody(request, parsedRequest)
+	if err != nil {
+		return nil, ErrInvalidGroupBody
+	}
+
+	parsedRequest.ResentByID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.ResentByID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	return parsedRequest, nil
+}
+
 // MapRequestToUninviteUserRequest maps incoming UninviteUser request to correct struct
 func MapRequestToUninviteUserRequest(request *http.Request, validator GroupValidator) (*UninviteUserRequest, error) {
 	var err error
@@ -371,6 +404,8 @@ func MapRequestToRemoveMemberRequest(request *http.Request, validator GroupValid
 		return nil, ErrInvalidQueryParam
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
 	return parsedRequest, nil
 }
 
@@ -400,6 +435,8 @@ func MapRequestToUpdateMemberRoleRequest(request *http.Request, validator GroupV
 		return nil, ErrValidationFailed
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
 	return parsedRequest, nil
 }
 
@@ -448,6 +485,8 @@ func MapRequestToUpdateOwnerRequest(request *http.Request, validator GroupValida
 		return nil, ErrValidationFailed
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
 	return parsedRequest, nil
 }
 
@@ -466,9 +505,134 @@ func MapRequestToArchiveGroupRequest(request *http.Request, validator GroupValid
 		return nil, ErrInvalidGroupID
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
+	return parsedRequest, nil
+}
+
+// MapRequestToMoveGroupRequest maps incoming MoveGroup request to correct struct
+func MapRequestToMoveGroupRequest(request *http.Request, validator GroupValidator) (*MoveGroupRequest, error) {
+	var err error
+	parsedRequest := &MoveGroupRequest{}
+
+	// get group id from uri
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, "groupID")
+	if err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	err = toolbox.DecodeRequestBody(request, parsedRequest)
+	if err != nil {
+		return nil, ErrInvalidGroupBody
+	}
+
+	parsedRequest.MovedByID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.MovedByID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToGetGroupRolesRequest maps incoming GetGroupRoles request to correct struct
+func MapRequestToGetGroupRolesRequest(request *http.Request, validator GroupValidator) (*GetGroupRolesRequest, error) {
+	var err error
+	parsedRequest := &GetGroupRolesRequest{}
+
+	// get group id from uri
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, "groupID")
+	if err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToSetGroupRoleRequest maps incoming SetGroupRole request to correct struct
+func MapRequestToSetGroupRoleRequest(request *http.Request, validator GroupValidator) (*SetGroupRoleRequest, error) {
+	var err error
+	parsedRequest := &SetGroupRoleRequest{}
+
+	// get group id from uri
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, "groupID")
+	if err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	// get role name from uri
+	parsedRequest.RoleName, err = toolbox.GetVariableValueFromUri(request, "roleName")
+	if err != nil {
+		return nil, ErrInvalidCustomRole
+	}
+
+	err = toolbox.DecodeRequestBody(request, parsedRequest)
+	if err != nil {
+		return nil, ErrInvalidGroupBody
+	}
+
+	parsedRequest.UpdatedByID = accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToDeleteGroupRoleRequest maps incoming DeleteGroupRole request to correct struct
+func MapRequestToDeleteGroupRoleRequest(request *http.Request, validator GroupValidator) (*DeleteGroupRoleRequest, error) {
+	var err error
+	parsedRequest := &DeleteGroupRoleRequest{}
+
+	// get group id from uri
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, "groupID")
+	if err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	// get role name from uri
+	parsedRequest.RoleName, err = toolbox.GetVariableValueFromUri(request, "roleName")
+	if err != nil {
+		return nil, ErrInvalidCustomRole
+	}
+
+	parsedRequest.DeletedByID = accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrValidationFailed
+	}
+
 	return parsedRequest, nil
 }
 
+// AuthorizeRequest confirms the user making the request holds the permission in the group.
+// Requests without an identifiable user are rejected with ErrUnableToIdentifyUser.
+func AuthorizeRequest(request *http.Request, authorizer Authorizer, groupID, permission string) error {
+	userID := accessmanagerhelpers.AcquireFrom(request.Context())
+	if userID == "" {
+		return ErrUnableToIdentifyUser
+	}
+
+	allowed, err := authorizer.Can(request.Context(), userID, groupID, permission)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return ErrInsufficientPermissions
+	}
+
+	return nil
+}
+
 // MapRequestToRestoreGroupRequest maps incoming RestoreGroup request to correct struct
 func MapRequestToRestoreGroupRequest(request *http.Request, validator GroupValidator) (*RestoreGroupRequest, error) {
 	var err error
@@ -484,6 +648,8 @@ func MapRequestToRestoreGroupRequest(request *http.Request, validator GroupValid
 		return nil, ErrInvalidGroupID
 	}
 
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+
 	return parsedRequest, nil
 }
 
@@ -30,12 +30,18 @@ type GroupService interface {
 	UninviteUser(ctx context.Context, r *UninviteUserRequest) (*UninviteUserResponse, error)
 	AcceptInvite(ctx context.Context, r *AcceptInviteRequest) (*AcceptInviteResponse, error)
 	RejectInvite(ctx context.Context, r *RejectInviteRequest) (*RejectInviteResponse, error)
+	ResendInvite(ctx context.Context, r *ResendInviteRequest) (*ResendInviteResponse, error)
+	ExpireStaleInvites(ctx context.Context, r *ExpireStaleInvitesRequest) (*ExpireStaleInvitesResponse, error)
 	RemoveMember(ctx context.Context, r *RemoveMemberRequest) (*RemoveMemberResponse, error)
 	UpdateMemberRole(ctx context.Context, r *UpdateMemberRoleRequest) (*UpdateMemberRoleResponse, error)
 	GetGroupMembers(ctx context.Context, r *GetGroupMembersRequest) (*GetGroupMembersResponse, error)
 	UpdateOwner(ctx context.Context, r *UpdateOwnerRequest) (*UpdateOwnerResponse, error)
 	RepairInvalidMembers(ctx context.Context) (*RepairInvalidMembersResponse, error)
 	ArchiveGroup(ctx context.Context, r *ArchiveGroupRequest) (*ArchiveGroupResponse, error)
+	MoveGroup(ctx context.Context, r *MoveGroupRequest) (*MoveGroupResponse, error)
+	GetGroupRoles(ctx context.Context, r *GetGroupRolesRequest) (*GetGroupRolesResponse, error)
+	SetGroupRole(ctx context.Context, r *SetGroupRoleRequest) (*SetGroupRoleResponse, error)
+	DeleteGroupRole(ctx context.Context, r *DeleteGroupRoleRequest) (*DeleteGroupRoleResponse, error)
 	RestoreGroup(ctx context.Context, r *RestoreGroupRequest) (*RestoreGroupResponse, error)
 	GetGroupStats(ctx context.Context, groupID string) (*GetGroupStatsResponse, error)
 	GetGroupsStats(ctx context.Context, r *GetGroupsStatsRequest) (*GetGroupsStatsResponse, error)
@@ -58,6 +64,9 @@ type Handler struct {
 	Service   GroupService
 	Validator GroupValidator
 	ErrorMaps []reply.ErrorManifest
+
+	// Authorizer checks the requester's permissions on group-scoped requests when set
+	Authorizer Authorizer
 }
 
 // NewHandler returns a new group handler
@@ -69,10 +78,30 @@ func NewHandler(service GroupService, validator GroupValidator, errorMaps ...rep
 	}
 }
 
+// WithAuthorizer enables permission checks on group-scoped requests, for hosts that
+// expose the group routes to users other than platform admins
+func (h *Handler) WithAuthorizer(authorizer Authorizer) *Handler {
+	h.Authorizer = authorizer
+	return h
+}
+
+// authorize confirms the requester holds the permission in the group when an
+// authorizer is set
+func (h *Handler) authorize(r *http.Request, groupID, permission string) error {
+	if h.Authorizer == nil {
+		return nil
+	}
+
+	return AuthorizeRequest(r, h.Authorizer, groupID, permission)
+}
+
 // CreateGroup handles group creation
 func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-create-group")
 	request, err := MapRequestToCreateGroupRequest(r, h.Validator)
+	if err == nil && request.ParentGroupID != "" {
+		err = h.authorize(r, request.ParentGroupID, PermissionSubgroupsCreate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -93,6 +122,9 @@ func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetGroupByID(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-get-group-by-id")
 	request, err := MapRequestToGetGroupByIDRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionGroupView)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -113,6 +145,9 @@ func (h *Handler) GetGroupByID(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetGroupLineage(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-get-group-lineage")
 	request, err := MapRequestToGetGroupLineageRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionGroupView)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -133,6 +168,9 @@ func (h *Handler) GetGroupLineage(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetGroupDescendants(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-get-group-descendants")
 	request, err := MapRequestToGetGroupDescendantsRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionGroupView)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -318,6 +356,9 @@ func (h *Handler) SearchGroupsByExtension(w http.ResponseWriter, r *http.Request
 func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-update-group")
 	request, err := MapRequestToUpdateGroupRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionSettingsUpdate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -338,6 +379,9 @@ func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-delete-group")
 	request, err := MapRequestToDeleteGroupRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionGroupDelete)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -358,6 +402,9 @@ func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-add-member")
 	request, err := MapRequestToAddMemberRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionMembersManage)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -378,6 +425,9 @@ func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) InviteUser(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-invite-user")
 	request, err := MapRequestToInviteUserRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionMembersInvite)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -398,6 +448,9 @@ func (h *Handler) InviteUser(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) UninviteUser(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-uninvite-user")
 	request, err := MapRequestToUninviteUserRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionMembersInvite)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -458,6 +511,9 @@ func (h *Handler) RejectInvite(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-remove-member")
 	request, err := MapRequestToRemoveMemberRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionMembersManage)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -478,6 +534,9 @@ func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-update-member-role")
 	request, err := MapRequestToUpdateMemberRoleRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionMembersManage)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -498,6 +557,9 @@ func (h *Handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-get-group-members")
 	request, err := MapRequestToGetGroupMembersRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionMembersView)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -518,6 +580,9 @@ func (h *Handler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) UpdateOwner(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-update-owner")
 	request, err := MapRequestToUpdateOwnerRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionOwnerTransfer)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -534,6 +599,42 @@ func (h *Handler) UpdateOwner(w http.ResponseWriter, r *http.Request) {
 	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Group)
 }
 
+// ResendInvite handles resending a pending or expired invitation email
+func (h *Handler) ResendInvite(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-resend-invite")
+	request, err := MapRequestToResendInviteRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionMembersInvite)
+	}
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ResendInvite(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response)
+}
+
+// ExpireStaleInvites handles marking invitations past their expiry as expired
+func (h *Handler) ExpireStaleInvites(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-expire-stale-invites")
+	response, err := h.Service.ExpireStaleInvites(r.Context(), &ExpireStaleInvitesRequest{})
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response)
+}
+
 // RepairInvalidMembers handles repairing groups that contain members with empty or null IDs.
 func (h *Handler) RepairInvalidMembers(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-repair-invalid-members")
@@ -551,6 +652,9 @@ func (h *Handler) RepairInvalidMembers(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ArchiveGroup(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-archive-group")
 	request, err := MapRequestToArchiveGroupRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionSettingsUpdate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -567,10 +671,113 @@ func (h *Handler) ArchiveGroup(w http.ResponseWriter, r *http.Request) {
 	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Group)
 }
 
+// MoveGroup handles moving a group and its descendants under a new parent
+func (h *Handler) MoveGroup(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-move-group")
+	request, err := MapRequestToMoveGroupRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionSettingsUpdate)
+	}
+	if err == nil && request.NewParentGroupID != "" {
+		err = h.authorize(r, request.NewParentGroupID, PermissionSubgroupsCreate)
+	}
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.MoveGroup(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response)
+}
+
+// GetGroupRoles handles listing the roles available in a group
+func (h *Handler) GetGroupRoles(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-get-group-roles")
+	request, err := MapRequestToGetGroupRolesRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionGroupView)
+	}
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetGroupRoles(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Roles)
+}
+
+// SetGroupRole handles creating or updating a group's custom role
+func (h *Handler) SetGroupRole(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-set-group-role")
+	request, err := MapRequestToSetGroupRoleRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionRolesManage)
+	}
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.SetGroupRole(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	statusCode := http.StatusOK
+	if response.Created {
+		statusCode = http.StatusCreated
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, statusCode, response.Role)
+}
+
+// DeleteGroupRole handles deleting a group's custom role
+func (h *Handler) DeleteGroupRole(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-delete-group-role")
+	request, err := MapRequestToDeleteGroupRoleRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionRolesManage)
+	}
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	_, err = h.Service.DeleteGroupRole(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusNoContent, nil)
+}
+
 // RestoreGroup handles restoring an archived group
 func (h *Handler) RestoreGroup(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-restore-group")
 	request, err := MapRequestToRestoreGroupRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionSettingsUpdate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -591,6 +798,9 @@ func (h *Handler) RestoreGroup(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetGroupStats(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-get-group-stats")
 	request, err := MapRequestToGetGroupStatsRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.ID, PermissionGroupView)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -658,6 +868,9 @@ func (h *Handler) ValidateGroupName(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) EnableGroupAutoJoinByEmailDomain(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-enable-group-auto-join-by-email-domain")
 	request, err := MapRequestToEnableGroupAutoJoinByEmailDomainRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionSettingsUpdate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -678,6 +891,9 @@ func (h *Handler) EnableGroupAutoJoinByEmailDomain(w http.ResponseWriter, r *htt
 func (h *Handler) DisableGroupAutoJoinByEmailDomain(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-disable-group-auto-join-by-email-domain")
 	request, err := MapRequestToDisableGroupAutoJoinByEmailDomainRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionSettingsUpdate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -698,6 +914,9 @@ func (h *Handler) DisableGroupAutoJoinByEmailDomain(w http.ResponseWriter, r *ht
 func (h *Handler) EnableGroupAutoInviteByEmailDomain(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-enable-group-auto-invite-by-email-domain")
 	request, err := MapRequestToEnableGroupAutoInviteByEmailDomainRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionSettingsUpdate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
@@ -718,6 +937,9 @@ func (h *Handler) EnableGroupAutoInviteByEmailDomain(w http.ResponseWriter, r *h
 func (h *Handler) DisableGroupAutoInviteByEmailDomain(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/group", "handle-disable-group-auto-invite-by-email-domain")
 	request, err := MapRequestToDisableGroupAutoInviteByEmailDomainRequest(r, h.Validator)
+	if err == nil {
+		err = h.authorize(r, request.GroupID, PermissionSettingsUpdate)
+	}
 	if err != nil {
 		logger.Warn("handler-returning-error-response", zap.Error(err))
 		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
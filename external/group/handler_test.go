@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
 	"github.com/ooaklee/ghatd/external/group"
 	"github.com/ooaklee/ghatd/external/toolbox"
 )
@@ -146,6 +147,14 @@ func (m *mockGroupService) UninviteUser(ctx context.Context, r *group.UninviteUs
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockGroupService) ResendInvite(ctx context.Context, r *group.ResendInviteRequest) (*group.ResendInviteResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockGroupService) ExpireStaleInvites(ctx context.Context, r *group.ExpireStaleInvitesRequest) (*group.ExpireStaleInvitesResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockGroupService) AcceptInvite(ctx context.Context, r *group.AcceptInviteRequest) (*group.AcceptInviteResponse, error) {
 	return nil, errors.New("not implemented")
 }
@@ -178,6 +187,22 @@ func (m *mockGroupService) ArchiveGroup(ctx context.Context, r *group.ArchiveGro
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockGroupService) MoveGroup(ctx context.Context, r *group.MoveGroupRequest) (*group.MoveGroupResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockGroupService) GetGroupRoles(ctx context.Context, r *group.GetGroupRolesRequest) (*group.GetGroupRolesResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockGroupService) SetGroupRole(ctx context.Context, r *group.SetGroupRoleRequest) (*group.SetGroupRoleResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockGroupService) DeleteGroupRole(ctx context.Context, r *group.DeleteGroupRoleRequest) (*group.DeleteGroupRoleResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockGroupService) RestoreGroup(ctx context.Context, r *group.RestoreGroupRequest) (*group.RestoreGroupResponse, error) {
 	return nil, errors.New("not implemented")
 }
@@ -390,6 +415,68 @@ func TestHandler_GetGroupByID(t *testing.T) {
 	}
 }
 
+// mockAuthorizer implements group.Authorizer for handler tests
+type mockAuthorizer struct {
+	allowedPermissions map[string]bool
+}
+
+func (m *mockAuthorizer) Can(ctx context.Context, userID, groupID, permission string) (bool, error) {
+	return m.allowedPermissions[permission], nil
+}
+
+func TestHandler_GetGroupByIDWithAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		userID             string
+		allowedPermissions map[string]bool
+		expectStatus       int
+	}{
+		{
+			name:               "Success - requester can view the group",
+			userID:             testUserID,
+			allowedPermissions: map[string]bool{group.PermissionGroupView: true},
+			expectStatus:       http.StatusOK,
+		},
+		{
+			name:               "Failure - requester cannot view the group",
+			userID:             testUserID,
+			allowedPermissions: map[string]bool{group.PermissionMembersView: true},
+			expectStatus:       http.StatusForbidden,
+		},
+		{
+			name:               "Failure - requester cannot be identified",
+			allowedPermissions: map[string]bool{group.PermissionGroupView: true},
+			expectStatus:       http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &mockGroupService{
+				getGroupByIDFunc: func(ctx context.Context, r *group.GetGroupByIDRequest) (*group.GetGroupByIDResponse, error) {
+					return &group.GetGroupByIDResponse{Group: &group.UniversalGroup{ID: r.ID}}, nil
+				},
+			}
+			authorizer := &mockAuthorizer{allowedPermissions: tt.allowedPermissions}
+
+			h := newTestHandler(svc).WithAuthorizer(authorizer)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/groups/"+testGroupID, nil)
+			req = mux.SetURLVars(req, map[string]string{"groupID": testGroupID})
+			req = req.WithContext(accessmanagerhelpers.TransitWith(req.Context(), tt.userID))
+			rec := httptest.NewRecorder()
+
+			h.GetGroupByID(rec, req)
+
+			assert.Equal(t, tt.expectStatus, rec.Code)
+		})
+	}
+}
+
 func TestHandler_GetGroupsConfig(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,222 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitGroupMembershipsUp creates the group memberships collection indexes and
+// moves members embedded in group documents into the memberships collection.
+// It can be re-run safely, memberships are upserted by their deterministic ID.
+func InitGroupMembershipsUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	ctx := context.Background()
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-move-group-members-into-memberships-collection"))
+
+	// Compound index on group_id and position for listing a group's members in order
+	groupPositionIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "group_id", Value: 1},
+			{Key: "position", Value: 1},
+		},
+		Options: options.Index().SetName("idx_group_memberships_group_id_position"),
+	}
+
+	// Compound index on member_id and type for finding the groups a member belongs to
+	memberIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "member_id", Value: 1},
+			{Key: "type", Value: 1},
+		},
+		Options: options.Index().SetName("idx_group_memberships_member_id_type"),
+	}
+
+	// Compound index on group_id and invitation_state for pending invitation queries
+	invitationIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "group_id", Value: 1},
+			{Key: "invitation_state", Value: 1},
+		},
+		Options: options.Index().SetName("idx_group_memberships_group_id_invitation_state"),
+	}
+
+	_, err := db.Collection(group.GroupMembershipCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		groupPositionIndexModel,
+		memberIndexModel,
+		invitationIndexModel,
+	})
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-group-memberships-indexes"))
+		return err
+	}
+
+	// Index on member_count for ordering groups by size
+	_, err = db.Collection(group.GroupCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "member_count", Value: 1}},
+		Options: options.Index().SetName("idx_groups_member_count"),
+	})
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-groups-member-count-index"))
+		return err
+	}
+
+	type embeddedMembersDocument struct {
+		ID      string         `bson:"_id"`
+		Members []group.Member `bson:"members"`
+	}
+
+	cursor, err := db.Collection(group.GroupCollection).Find(ctx,
+		bson.M{"members": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"members": 1}),
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-find-groups-with-embedded-members"))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var document embeddedMembersDocument
+		if err := cursor.Decode(&document); err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-decode-group-with-embedded-members"))
+			return err
+		}
+
+		// members with empty IDs are dropped and only the first entry for a member ID is kept
+		seen := map[string]bool{}
+		writeModels := []mongo.WriteModel{}
+		for position, member := range document.Members {
+			if member.ID == "" || seen[member.ID] {
+				continue
+			}
+			seen[member.ID] = true
+
+			membership := group.NewGroupMembership(document.ID, member, int64(position))
+			writeModels = append(writeModels, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": membership.ID}).
+				SetReplacement(membership).
+				SetUpsert(true))
+		}
+
+		if len(writeModels) > 0 {
+			_, err = db.Collection(group.GroupMembershipCollection).BulkWrite(ctx, writeModels)
+			if err != nil {
+				log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-write-group-memberships"))
+				return err
+			}
+		}
+
+		_, err = db.Collection(group.GroupCollection).UpdateOne(ctx,
+			bson.M{"_id": document.ID},
+			bson.M{
+				"$set":   bson.M{"member_count": len(writeModels)},
+				"$unset": bson.M{"members": ""},
+			},
+		)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-remove-embedded-group-members"))
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-iterating-groups-with-embedded-members"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-move-group-members-into-memberships-collection"))
+	return nil
+}
+
+// InitGroupMembershipsDown moves memberships back into group documents as
+// embedded members and drops the memberships collection
+func InitGroupMembershipsDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	ctx := context.Background()
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-move-group-members-into-memberships-collection"))
+
+	cursor, err := db.Collection(group.GroupMembershipCollection).Find(ctx,
+		bson.M{},
+		options.Find().SetSort(bson.D{{Key: "group_id", Value: 1}, {Key: "position", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-find-group-memberships"))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	embedMembers := func(groupID string, members []group.Member) error {
+		_, err := db.Collection(group.GroupCollection).UpdateOne(ctx,
+			bson.M{"_id": groupID},
+			bson.M{
+				"$set":   bson.M{"members": members},
+				"$unset": bson.M{"member_count": ""},
+			},
+		)
+		return err
+	}
+
+	currentGroupID := ""
+	members := []group.Member{}
+	for cursor.Next(ctx) {
+		var membership group.GroupMembership
+		if err := cursor.Decode(&membership); err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-decode-group-membership"))
+			return err
+		}
+
+		if membership.GroupID != currentGroupID && currentGroupID != "" {
+			if err := embedMembers(currentGroupID, members); err != nil {
+				log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-embed-group-members"))
+				return err
+			}
+			members = []group.Member{}
+		}
+
+		currentGroupID = membership.GroupID
+		members = append(members, membership.ToMember())
+	}
+
+	if err := cursor.Err(); err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-iterating-group-memberships"))
+		return err
+	}
+
+	if currentGroupID != "" {
+		if err := embedMembers(currentGroupID, members); err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-embed-group-members"))
+			return err
+		}
+	}
+
+	// groups without any memberships no longer need a member count
+	_, err = db.Collection(group.GroupCollection).UpdateMany(ctx,
+		bson.M{"member_count": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"member_count": ""}},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-unset-groups-member-count"))
+		return err
+	}
+
+	if err := db.Collection(group.GroupCollection).Indexes().DropOne(ctx, "idx_groups_member_count"); err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-groups-member-count-index"))
+		return err
+	}
+
+	if err := db.Collection(group.GroupMembershipCollection).Drop(ctx); err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-to-drop-group-memberships-collection"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-move-group-members-into-memberships-collection"))
+	return nil
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitAuditGroupActivityIndexUp initializes the audit collection index backing the group activity feed
+func InitAuditGroupActivityIndexUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	const mongoCollectionName = audit.AuditCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-audit-group-activity-index"))
+
+	// Compound index on domain, target_id, action_at and _id for paging a group's events newest first
+	activityIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "domain", Value: 1},
+			{Key: "target_id", Value: 1},
+			{Key: "action_at", Value: -1},
+			{Key: "_id", Value: -1},
+		},
+		Options: options.Index().SetName("idx_audit_domain_target_id_action_at"),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateOne(context.Background(), activityIndexModel)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-audit-group-activity-index"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-audit-group-activity-index"))
+	return nil
+}
+
+// InitAuditGroupActivityIndexDown rolls back the audit group activity index
+func InitAuditGroupActivityIndexDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = audit.AuditCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-audit-group-activity-index"))
+
+	err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), "idx_audit_domain_target_id_action_at")
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-audit-group-activity-index"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-audit-group-activity-index"))
+	return nil
+}
@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitGroupJoinRequestsIndexesUp initializes indexes for the group join requests collection
+func InitGroupJoinRequestsIndexesUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	const mongoCollectionName = group.GroupJoinRequestCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-group-join-requests-indexes"))
+
+	// Compound index on group_id, status and created_at for admin review queues
+	groupStatusIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "group_id", Value: 1},
+			{Key: "status", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+		Options: options.Index().SetName("idx_group_join_requests_group_id_status_created_at"),
+	}
+
+	// Compound index on user_id and created_at for listing a requester's join requests
+	userIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+		Options: options.Index().SetName("idx_group_join_requests_user_id_created_at"),
+	}
+
+	// Unique partial index so a user can only have one pending request per group
+	pendingIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "group_id", Value: 1},
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().
+			SetName("idx_group_join_requests_group_id_user_id_pending").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": group.JoinRequestStatusPending}),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		groupStatusIndexModel,
+		userIndexModel,
+		pendingIndexModel,
+	})
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-group-join-requests-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-group-join-requests-indexes"))
+	return nil
+}
+
+// InitGroupJoinRequestsIndexesDown rolls back the group join requests indexes
+func InitGroupJoinRequestsIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = group.GroupJoinRequestCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-group-join-requests-indexes"))
+
+	for _, indexName := range []string{
+		"idx_group_join_requests_group_id_status_created_at",
+		"idx_group_join_requests_user_id_created_at",
+		"idx_group_join_requests_group_id_user_id_pending",
+	} {
+		err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), indexName)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-group-join-requests-indexes"))
+			return err
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-group-join-requests-indexes"))
+	return nil
+}
@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitGroupMemberImportsIndexesUp initializes indexes for the group member imports collection
+func InitGroupMemberImportsIndexesUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	const mongoCollectionName = group.GroupMemberImportCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-group-member-imports-indexes"))
+
+	// Compound index on group_id and created_at for listing a group's imports newest first
+	groupIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "group_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+		Options: options.Index().SetName("idx_group_member_imports_group_id_created_at"),
+	}
+
+	// Index on status for finding imports left unfinished by a restart
+	statusIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}},
+		Options: options.Index().SetName("idx_group_member_imports_status"),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		groupIndexModel,
+		statusIndexModel,
+	})
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-group-member-imports-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-group-member-imports-indexes"))
+	return nil
+}
+
+// InitGroupMemberImportsIndexesDown rolls back the group member imports indexes
+func InitGroupMemberImportsIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = group.GroupMemberImportCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-group-member-imports-indexes"))
+
+	for _, indexName := range []string{
+		"idx_group_member_imports_group_id_created_at",
+		"idx_group_member_imports_status",
+	} {
+		err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), indexName)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-group-member-imports-indexes"))
+			return err
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-group-member-imports-indexes"))
+	return nil
+}
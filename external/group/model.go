@@ -43,6 +43,29 @@ type GroupConfig struct {
 	// Role configuration: maps group type to allowed roles
 	TypeToRoleOverrides map[string][]string // e.g., "TEAM" -> ["ADMIN", "MEMBER", "READER"]
 	DefaultRoles        []string            // Fallback roles when group type not in TypeToRoleOverrides
+	// Permission configuration: permissions hosts register and the roles that grant them
+	Permissions           []string                       // Registered in addition to BuiltInPermissions, e.g. "reports.export"
+	RolePermissions       map[string][]string            // e.g., "ADMIN" -> ["members.manage", "settings.update"]
+	TypeToRolePermissions map[string]map[string][]string // e.g., "TEAM" -> "LEAD" -> ["members.invite"]
+	PermissionInheritance string                         // MANAGERS (default), ALL or NONE
+}
+
+// InvitationConfig holds the configuration for signed invitation links
+type InvitationConfig struct {
+	// SigningSecret is the HMAC key used to sign invitation tokens
+	SigningSecret string
+
+	// AcceptURL is the page invitees land on, the token is appended as the "token" query parameter
+	AcceptURL string
+
+	// Expiry is how long an invitation stays valid after it is sent
+	Expiry time.Duration
+
+	// ResendCooldown is the minimum time between sends of the same invitation
+	ResendCooldown time.Duration
+
+	// MaxSends caps how many times an invitation can be sent, zero means no limit
+	MaxSends int
 }
 
 // UniversalGroup represents a flexible group/collection model
@@ -71,9 +94,16 @@ type UniversalGroup struct {
 	// Members collection - supports both users and nested groups
 	Members []Member `json:"members,omitempty" bson:"members,omitempty" db:"members"`
 
+	// MemberCount is the denormalised member total kept on the group document
+	// when members are stored in the memberships collection
+	MemberCount int `json:"-" bson:"member_count,omitempty" db:"member_count"`
+
 	// Settings and permissions
 	Settings *GroupSettings `json:"settings,omitempty" bson:"settings,omitempty" db:"settings"`
 
+	// CustomRoles are roles defined by the group in addition to the configured roles
+	CustomRoles []GroupRole `json:"custom_roles,omitempty" bson:"custom_roles,omitempty" db:"custom_roles"`
+
 	// Integration points
 	Integrations *Integrations `json:"integrations,omitempty" bson:"integrations,omitempty" db:"integrations"`
 
@@ -88,6 +118,28 @@ type UniversalGroup struct {
 	idGenerator  IDGenerator  `json:"-" bson:"-" db:"-"`
 	timeProvider TimeProvider `json:"-" bson:"-" db:"-"`
 	stringUtils  StringUtils  `json:"-" bson:"-" db:"-"`
+
+	// membershipSnapshot holds the memberships as they were loaded, keyed by member ID,
+	// so updates only write the members that changed
+	membershipSnapshot map[string]GroupMembership `json:"-" bson:"-" db:"-"`
+}
+
+// GroupRole is a custom role defined by a group and the permissions it grants
+type GroupRole struct {
+	Name        string   `json:"name" bson:"name" db:"name"`
+	Description string   `json:"description,omitempty" bson:"description,omitempty" db:"description"`
+	Permissions []string `json:"permissions" bson:"permissions" db:"permissions"`
+}
+
+// GetCustomRole returns the group's custom role with the given name, or nil if it has none
+func (g *UniversalGroup) GetCustomRole(name string) *GroupRole {
+	normalisedName := strings.ToUpper(strings.TrimSpace(name))
+	for i := range g.CustomRoles {
+		if g.CustomRoles[i].Name == normalisedName {
+			return &g.CustomRoles[i]
+		}
+	}
+	return nil
 }
 
 // DisplayInfo holds display-related information
@@ -268,6 +320,50 @@ type SlackIntegration struct {
 	OnDutyDisplayName    string   `json:"on_duty_display_name,omitempty" bson:"on_duty_display_name,omitempty" db:"on_duty_display_name"`
 	Emoji                string   `json:"emoji,omitempty" bson:"emoji,omitempty" db:"emoji"`
 	AdditionalRecipients []string `json:"additional_recipients,omitempty" bson:"additional_recipients,omitempty" db:"additional_recipients"`
+
+	// Events lists the integration events (IntegrationEvent*) the group is notified
+	// about. The integration's defaults are used when it is empty
+	Events []string `json:"events,omitempty" bson:"events,omitempty" db:"events"`
+}
+
+// IsSubscribedTo reports whether the Slack integration is subscribed to the event,
+// falling back to the default events when no subscriptions are set
+func (s *SlackIntegration) IsSubscribedTo(event string, defaultEvents []string) bool {
+	if s == nil {
+		return false
+	}
+
+	events := s.Events
+	if len(events) == 0 {
+		events = defaultEvents
+	}
+
+	for _, subscribedEvent := range events {
+		if strings.EqualFold(strings.TrimSpace(subscribedEvent), event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IntegrationEvent describes something that happened in a group that the group's
+// integrations can be notified about
+type IntegrationEvent struct {
+	// Type is one of the IntegrationEvent* keys
+	Type string
+
+	// ActorID is the user that caused the event, empty for system events
+	ActorID string
+
+	// Member is the member the event is about
+	Member *Member
+
+	// InviteEmail is the address the accepted invitation was sent to
+	InviteEmail string
+
+	// OccurredAt is when the event happened
+	OccurredAt string
 }
 
 // GroupMetadata holds timestamp and audit information
@@ -282,6 +378,184 @@ type GroupMetadata struct {
 	CustomTimestamps map[string]string `json:"custom_timestamps,omitempty" bson:"custom_timestamps,omitempty" db:"custom_timestamps"`
 }
 
+// GroupJoinRequest represents a user's request to join a group that is
+// reviewed by the group's admins when the group requires approval
+type GroupJoinRequest struct {
+	ID             string `json:"id" bson:"_id" db:"id"`
+	GroupID        string `json:"group_id" bson:"group_id" db:"group_id"`
+	UserID         string `json:"user_id" bson:"user_id" db:"user_id"`
+	Status         string `json:"status" bson:"status" db:"status"` // PENDING, APPROVED, DENIED, CANCELLED
+	Role           string `json:"role,omitempty" bson:"role,omitempty" db:"role"`
+	Message        string `json:"message,omitempty" bson:"message,omitempty" db:"message"`
+	DecisionReason string `json:"decision_reason,omitempty" bson:"decision_reason,omitempty" db:"decision_reason"`
+	ReviewedByID   string `json:"reviewed_by_id,omitempty" bson:"reviewed_by_id,omitempty" db:"reviewed_by_id"`
+	ReviewedAt     string `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt      string `json:"created_at" bson:"created_at" db:"created_at"`
+	UpdatedAt      string `json:"updated_at,omitempty" bson:"updated_at,omitempty" db:"updated_at"`
+}
+
+// IsPending returns true when the join request is still awaiting a decision
+func (r *GroupJoinRequest) IsPending() bool {
+	return r != nil && r.Status == JoinRequestStatusPending
+}
+
+// MemberImportJob is a bulk import of members into a group. Its rows are validated
+// when the import is created and processed in the background, with progress saved
+// as it goes. ClaimedBy identifies the run processing it, which keeps HeartbeatAt
+// current so abandoned imports can be resumed
+type MemberImportJob struct {
+	ID            string               `json:"id" bson:"_id" db:"id"`
+	GroupID       string               `json:"group_id" bson:"group_id" db:"group_id"`
+	Status        string               `json:"status" bson:"status" db:"status"` // PENDING, RUNNING, COMPLETED, FAILED
+	RequestedByID string               `json:"requested_by_id,omitempty" bson:"requested_by_id,omitempty" db:"requested_by_id"`
+	Progress      MemberImportProgress `json:"progress" bson:"progress" db:"progress"`
+	Rows          []MemberImportRow    `json:"rows" bson:"rows" db:"rows"`
+	LastError     string               `json:"last_error,omitempty" bson:"last_error,omitempty" db:"last_error"`
+	ClaimedBy     string               `json:"-" bson:"claimed_by,omitempty" db:"claimed_by"`
+	HeartbeatAt   string               `json:"-" bson:"heartbeat_at,omitempty" db:"heartbeat_at"`
+	CreatedAt     string               `json:"created_at" bson:"created_at" db:"created_at"`
+	UpdatedAt     string               `json:"updated_at,omitempty" bson:"updated_at,omitempty" db:"updated_at"`
+	CompletedAt   string               `json:"completed_at,omitempty" bson:"completed_at,omitempty" db:"completed_at"`
+}
+
+// IsFinished returns true when the import is no longer being processed
+func (j *MemberImportJob) IsFinished() bool {
+	return j != nil && (j.Status == MemberImportStatusCompleted || j.Status == MemberImportStatusFailed)
+}
+
+// MemberImportProgress counts a member import's rows by outcome
+type MemberImportProgress struct {
+	Total     int `json:"total" bson:"total" db:"total"`
+	Processed int `json:"processed" bson:"processed" db:"processed"`
+	Added     int `json:"added" bson:"added" db:"added"`
+	Invited   int `json:"invited" bson:"invited" db:"invited"`
+	Skipped   int `json:"skipped" bson:"skipped" db:"skipped"`
+	Failed    int `json:"failed" bson:"failed" db:"failed"`
+	Invalid   int `json:"invalid" bson:"invalid" db:"invalid"`
+}
+
+// MemberImportRow is one row of a member import with its validation issues and,
+// once processed, its outcome
+type MemberImportRow struct {
+	// Row is the row's position in the import, starting at 1
+	Row      int                 `json:"row" bson:"row" db:"row"`
+	Email    string              `json:"email" bson:"email" db:"email"`
+	Role     string              `json:"role" bson:"role" db:"role"`
+	Action   string              `json:"action,omitempty" bson:"action,omitempty" db:"action"` // ADD, INVITE, SKIP
+	Status   string              `json:"status" bson:"status" db:"status"`                     // VALID, INVALID, ADDED, INVITED, SKIPPED, FAILED
+	MemberID string              `json:"member_id,omitempty" bson:"member_id,omitempty" db:"member_id"`
+	Issues   []MemberImportIssue `json:"issues,omitempty" bson:"issues,omitempty" db:"issues"`
+	Error    string              `json:"error,omitempty" bson:"error,omitempty" db:"error"`
+}
+
+// HasErrors returns true when any of the row's issues stops it being imported
+func (r *MemberImportRow) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == MemberImportSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// MemberImportIssue is a problem found with a member import row
+type MemberImportIssue struct {
+	Code     string `json:"code" bson:"code" db:"code"`
+	Severity string `json:"severity" bson:"severity" db:"severity"` // ERROR, WARNING
+	Message  string `json:"message" bson:"message" db:"message"`
+}
+
+// MemberImportReport summarises the validation of a member import, it is returned
+// for dry runs
+type MemberImportReport struct {
+	GroupID  string            `json:"group_id"`
+	Total    int               `json:"total"`
+	Valid    int               `json:"valid"`
+	Invalid  int               `json:"invalid"`
+	Warnings int               `json:"warnings"`
+	Rows     []MemberImportRow `json:"rows"`
+}
+
+// GroupActivity represents an audit event recorded against a group, as shown in
+// the group's activity feed
+type GroupActivity struct {
+	ID         string                 `json:"id"`
+	Action     string                 `json:"action"`
+	Summary    string                 `json:"summary"`
+	GroupID    string                 `json:"group_id"`
+	GroupName  string                 `json:"group_name,omitempty"`
+	ActorID    string                 `json:"actor_id,omitempty"`
+	SubjectID  string                 `json:"subject_id,omitempty"` // the member, invitee or requester acted on
+	OccurredAt string                 `json:"occurred_at"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// GroupMembership represents a single member of a group stored in the
+// memberships collection rather than embedded in the group document
+type GroupMembership struct {
+	ID              string                 `json:"id" bson:"_id" db:"id"`
+	GroupID         string                 `json:"group_id" bson:"group_id" db:"group_id"`
+	MemberID        string                 `json:"member_id" bson:"member_id" db:"member_id"`
+	Type            string                 `json:"type" bson:"type" db:"type"`
+	Role            string                 `json:"role,omitempty" bson:"role,omitempty" db:"role"`
+	JoinedAt        string                 `json:"joined_at,omitempty" bson:"joined_at,omitempty" db:"joined_at"`
+	InvitedAt       string                 `json:"invited_at,omitempty" bson:"invited_at,omitempty" db:"invited_at"`
+	InvitationState string                 `json:"invitation_state,omitempty" bson:"invitation_state,omitempty" db:"invitation_state"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty" bson:"metadata,omitempty" db:"metadata"`
+
+	// Position preserves the order members were added in
+	Position int64 `json:"position" bson:"position" db:"position"`
+}
+
+// NewGroupMembership creates the membership document for a member of a group
+func NewGroupMembership(groupID string, member Member, position int64) GroupMembership {
+	return GroupMembership{
+		ID:              GenerateGroupMembershipID(groupID, member.ID),
+		GroupID:         groupID,
+		MemberID:        member.ID,
+		Type:            member.Type,
+		Role:            member.Role,
+		JoinedAt:        member.JoinedAt,
+		InvitedAt:       member.InvitedAt,
+		InvitationState: member.InvitationState,
+		Metadata:        copyMemberMetadata(member.Metadata),
+		Position:        position,
+	}
+}
+
+// GenerateGroupMembershipID returns the deterministic ID of a membership, so
+// each member can only be stored once per group
+func GenerateGroupMembershipID(groupID, memberID string) string {
+	return groupID + ":" + memberID
+}
+
+// ToMember converts the membership back to the member embedded in group responses
+func (m *GroupMembership) ToMember() Member {
+	return Member{
+		ID:              m.MemberID,
+		Type:            m.Type,
+		Role:            m.Role,
+		JoinedAt:        m.JoinedAt,
+		InvitedAt:       m.InvitedAt,
+		InvitationState: m.InvitationState,
+		Metadata:        copyMemberMetadata(m.Metadata),
+	}
+}
+
+// copyMemberMetadata returns a shallow copy of member metadata
+func copyMemberMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+
+	copied := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+
+	return copied
+}
+
 // NewUniversalGroup creates a new group with injected dependencies
 func NewUniversalGroup(
 	config *GroupConfig,
@@ -494,7 +768,7 @@ func (g *UniversalGroup) AddMember(memberID, memberType, role string) (*Universa
 
 	// Check max members limit
 	if g.Settings != nil && g.Settings.MaxMembers > 0 && len(g.Members) >= g.Settings.MaxMembers {
-		return g, errors.New("MaxMembersReached")
+		return g, ErrMaxMembersReached
 	}
 
 	member := Member{
@@ -604,6 +878,31 @@ func (g *UniversalGroup) UpdateMemberRole(memberID, newRole string) (*UniversalG
 	return g, ErrMemberNotFound
 }
 
+// IsBillingAdmin checks whether the user can manage the group's billing, i.e. they
+// own the group or hold an owner, admin or billing admin role as a direct member
+func (g *UniversalGroup) IsBillingAdmin(userID string) bool {
+	if userID == "" {
+		return false
+	}
+
+	if g.OwnerID == userID {
+		return true
+	}
+
+	for _, member := range g.Members {
+		if member.ID != userID || member.Type != MemberTypeUser {
+			continue
+		}
+
+		switch member.Role {
+		case MemberRoleOwner, MemberRoleAdmin, MemberRoleBillingAdmin:
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetMemberCount returns the total number of members
 func (g *UniversalGroup) GetMemberCount() int {
 	return len(g.Members)
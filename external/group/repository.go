@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -43,6 +44,16 @@ type Repository struct {
 
 	collection      *mongo.Collection
 	collectionMutex sync.Mutex
+
+	joinRequestCollection      *mongo.Collection
+	joinRequestCollectionMutex sync.Mutex
+
+	membershipsEnabled        bool
+	membershipCollection      *mongo.Collection
+	membershipCollectionMutex sync.Mutex
+
+	memberImportCollection      *mongo.Collection
+	memberImportCollectionMutex sync.Mutex
 }
 
 // NewRepository creates a new group repository
@@ -103,6 +114,10 @@ func (r *Repository) CreateGroup(ctx context.Context, group *UniversalGroup) (*U
 		return nil, err
 	}
 
+	if r.membershipsEnabled {
+		return r.createGroupWithMemberships(ctx, collection, group)
+	}
+
 	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, group, "group")
 	if err != nil {
 		return nil, err
@@ -111,6 +126,31 @@ func (r *Repository) CreateGroup(ctx context.Context, group *UniversalGroup) (*U
 	return group, nil
 }
 
+// createGroupWithMemberships stores the group document without its members,
+// followed by a membership document per member
+func (r *Repository) createGroupWithMemberships(ctx context.Context, collection *mongo.Collection, group *UniversalGroup) (*UniversalGroup, error) {
+	document := *group
+	document.Members = nil
+	document.MemberCount = 0
+
+	_, err := r.Store.ExecuteInsertOneCommand(ctx, collection, &document, "group")
+	if err != nil {
+		return nil, err
+	}
+
+	group.membershipSnapshot = map[string]GroupMembership{}
+	if err := r.saveGroupMemberships(ctx, group); err != nil {
+		return nil, err
+	}
+
+	group.MemberCount, err = r.refreshGroupMemberCount(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
 // GetGroupByID retrieves a group by ID
 func (r *Repository) GetGroupByID(ctx context.Context, id string) (*UniversalGroup, error) {
 	collection, err := r.GetGroupCollection(ctx)
@@ -128,6 +168,10 @@ func (r *Repository) GetGroupByID(ctx context.Context, id string) (*UniversalGro
 		return nil, err
 	}
 
+	if err := r.hydrateGroupMembers(ctx, &result); err != nil {
+		return nil, err
+	}
+
 	return &result, nil
 }
 
@@ -148,6 +192,10 @@ func (r *Repository) GetGroupByNanoID(ctx context.Context, nanoID string) (*Univ
 		return nil, err
 	}
 
+	if err := r.hydrateGroupMembers(ctx, &result); err != nil {
+		return nil, err
+	}
+
 	return &result, nil
 }
 
@@ -172,6 +220,10 @@ func (r *Repository) GetGroupByName(ctx context.Context, name, groupType string,
 		return nil, err
 	}
 
+	if err := r.hydrateGroupMembers(ctx, &result); err != nil {
+		return nil, err
+	}
+
 	return &result, nil
 }
 
@@ -193,6 +245,10 @@ func (r *Repository) GetGroupByNameAndParent(ctx context.Context, name, parentGr
 		return nil, err
 	}
 
+	if err := r.hydrateGroupMembers(ctx, &result); err != nil {
+		return nil, err
+	}
+
 	return &result, nil
 }
 
@@ -207,14 +263,64 @@ func (r *Repository) UpdateGroup(ctx context.Context, group *UniversalGroup) (*U
 		"_id": group.ID,
 	}
 
+	if r.membershipsEnabled {
+		return r.updateGroupWithMemberships(ctx, collection, queryFilter, group)
+	}
+
 	update := bson.M{
 		"$set": group,
 	}
 
+	unset := bson.M{}
 	if len(group.Members) == 0 {
-		update["$unset"] = bson.M{
-			"members": "",
-		}
+		unset["members"] = ""
+	}
+	if len(group.CustomRoles) == 0 {
+		unset["custom_roles"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, queryFilter, update, "group")
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// updateGroupWithMemberships applies member changes to the memberships collection
+// before updating the group document, which never holds the members
+func (r *Repository) updateGroupWithMemberships(ctx context.Context, collection *mongo.Collection, queryFilter bson.M, group *UniversalGroup) (*UniversalGroup, error) {
+	if err := r.saveGroupMemberships(ctx, group); err != nil {
+		return nil, err
+	}
+
+	membershipCollection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberCount, err := r.Store.ExecuteCountDocuments(ctx, membershipCollection, bson.M{"group_id": group.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	document := *group
+	document.Members = nil
+	document.MemberCount = int(memberCount)
+
+	unset := bson.M{
+		"members": "",
+	}
+	if len(group.CustomRoles) == 0 {
+		unset["custom_roles"] = ""
+	}
+
+	update := bson.M{
+		"$set":   &document,
+		"$unset": unset,
 	}
 
 	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, queryFilter, update, "group")
@@ -222,6 +328,7 @@ func (r *Repository) UpdateGroup(ctx context.Context, group *UniversalGroup) (*U
 		return nil, err
 	}
 
+	group.MemberCount = document.MemberCount
 	return group, nil
 }
 
@@ -237,7 +344,16 @@ func (r *Repository) DeleteGroupByID(ctx context.Context, id string) error {
 	}
 
 	err = r.Store.ExecuteDeleteOneCommand(ctx, collection, queryFilter, "group")
-	return err
+	if err != nil || !r.membershipsEnabled {
+		return err
+	}
+
+	membershipCollection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Store.ExecuteDeleteManyCommand(ctx, membershipCollection, bson.M{"group_id": id}, "group-memberships")
 }
 
 // SoftDeleteGroup soft deletes a group by setting deleted timestamp
@@ -271,7 +387,10 @@ func (r *Repository) GetGroups(ctx context.Context, req *GetGroupsRequest) ([]Un
 	}
 
 	// Build query filter
-	queryFilter := r.buildGroupQueryFilter(req)
+	queryFilter, err := r.buildGroupQueryFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build sort options
 	sortOptions := r.buildSortOptions(req.OrderBy)
@@ -296,6 +415,10 @@ func (r *Repository) GetGroups(ctx context.Context, req *GetGroupsRequest) ([]Un
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -306,7 +429,10 @@ func (r *Repository) GetTotalGroups(ctx context.Context, req *GetGroupsRequest)
 		return 0, err
 	}
 
-	queryFilter := r.buildGroupQueryFilter(req)
+	queryFilter, err := r.buildGroupQueryFilter(ctx, req)
+	if err != nil {
+		return 0, err
+	}
 
 	count, err := r.Store.ExecuteCountDocuments(ctx, collection, queryFilter)
 	if err != nil {
@@ -347,6 +473,10 @@ func (r *Repository) GetGroupsByType(ctx context.Context, groupType string, page
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -381,6 +511,10 @@ func (r *Repository) GetGroupsByStatus(ctx context.Context, status string, page,
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -391,16 +525,17 @@ func (r *Repository) GetGroupsByReferencedUserID(ctx context.Context, userID str
 		return nil, err
 	}
 
+	memberFilter, err := r.memberMatchFilter(ctx, bson.M{
+		"id": userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	queryFilter := bson.M{
 		"$or": []bson.M{
 			{"owner_id": userID},
-			{
-				"members": bson.M{
-					"$elemMatch": bson.M{
-						"id": userID,
-					},
-				},
-			},
+			memberFilter,
 		},
 		"metadata.deleted_at": bson.M{"$exists": false},
 	}
@@ -418,6 +553,10 @@ func (r *Repository) GetGroupsByReferencedUserID(ctx context.Context, userID str
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -429,19 +568,18 @@ func (r *Repository) GetGroupsAwaitingAnswerForInvitationsByMemberID(ctx context
 		return nil, err
 	}
 
-	queryFilter := bson.M{
-		"members": bson.M{
-			"$elemMatch": bson.M{
-				"id":   memberID,
-				"type": MemberTypeUser,
-				"$or": []bson.M{
-					{"invitation_state": MemberInvitationStateInvited},
-					{"invited_at": bson.M{"$exists": true, "$ne": ""}},
-				},
-			},
+	queryFilter, err := r.memberMatchFilter(ctx, bson.M{
+		"id":   memberID,
+		"type": MemberTypeUser,
+		"$or": []bson.M{
+			{"invitation_state": MemberInvitationStateInvited},
+			{"invited_at": bson.M{"$exists": true, "$ne": ""}},
 		},
-		"metadata.deleted_at": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return nil, err
 	}
+	queryFilter["metadata.deleted_at"] = bson.M{"$exists": false}
 
 	options := options.Find().SetSort(bson.D{{Key: "metadata.created_at", Value: -1}})
 
@@ -456,6 +594,48 @@ func (r *Repository) GetGroupsAwaitingAnswerForInvitationsByMemberID(ctx context
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetGroupsWithPendingInvitations retrieves groups holding at least one pending invitation
+func (r *Repository) GetGroupsWithPendingInvitations(ctx context.Context) ([]UniversalGroup, error) {
+	collection, err := r.GetGroupCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter, err := r.memberMatchFilter(ctx, bson.M{
+		"type":             MemberTypeUser,
+		"invitation_state": bson.M{"$ne": MemberInvitationStateExpired},
+		"$or": []bson.M{
+			{"invitation_state": MemberInvitationStateInvited},
+			{"invited_at": bson.M{"$exists": true, "$ne": ""}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	queryFilter["metadata.deleted_at"] = bson.M{"$exists": false}
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, options.Find())
+	if err != nil {
+		return nil, err
+	}
+
+	var results []UniversalGroup
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &results, "group")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -466,16 +646,17 @@ func (r *Repository) GetGroupsByMemberID(ctx context.Context, memberID string, m
 		return nil, err
 	}
 
-	queryFilter := bson.M{
-		"members": bson.M{
-			"$elemMatch": bson.M{
-				"id": memberID,
-			},
-		},
+	memberMatch := bson.M{
+		"id": memberID,
 	}
 
 	if memberType != "" {
-		queryFilter["members"].(bson.M)["$elemMatch"].(bson.M)["type"] = memberType
+		memberMatch["type"] = memberType
+	}
+
+	queryFilter, err := r.memberMatchFilter(ctx, memberMatch)
+	if err != nil {
+		return nil, err
 	}
 
 	skip := int64((page - 1) * pageSize)
@@ -497,6 +678,10 @@ func (r *Repository) GetGroupsByMemberID(ctx context.Context, memberID string, m
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -532,6 +717,10 @@ func (r *Repository) GetGroupsByLeaderID(ctx context.Context, leaderID string, p
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -558,9 +747,76 @@ func (r *Repository) GetGroupsByLineageAncestor(ctx context.Context, ancestorGro
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
+// MoveGroupSubtree places a group under a new parent, an empty parent makes it a root group.
+// Descendant lineages are rewritten first, relative to the moved group's position in them,
+// so running the move again after a partial failure completes it.
+func (r *Repository) MoveGroupSubtree(ctx context.Context, groupID, parentGroupID string, lineage []string, updatedAt string) error {
+	collection, err := r.GetGroupCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	if lineage == nil {
+		lineage = []string{}
+	}
+
+	descendantsFilter := bson.M{
+		"lineage": groupID,
+	}
+
+	// lineage = new lineage of the moved group + the descendant's lineage from the moved group onwards
+	descendantsUpdate := bson.A{
+		bson.M{
+			"$set": bson.M{
+				"lineage": bson.M{
+					"$concatArrays": bson.A{
+						bson.M{"$literal": lineage},
+						bson.M{"$slice": bson.A{
+							"$lineage",
+							bson.M{"$indexOfArray": bson.A{"$lineage", groupID}},
+							bson.M{"$size": "$lineage"},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	err = r.Store.ExecuteUpdateManyCommand(ctx, collection, descendantsFilter, descendantsUpdate, "groups")
+	if err != nil {
+		return err
+	}
+
+	groupFilter := bson.M{
+		"_id": groupID,
+	}
+
+	groupUpdate := bson.M{
+		"$set": bson.M{
+			"metadata.updated_at": updatedAt,
+		},
+	}
+
+	if parentGroupID == "" {
+		groupUpdate["$unset"] = bson.M{
+			"parent_group_id": "",
+			"lineage":         "",
+		}
+	} else {
+		groupUpdate["$set"].(bson.M)["parent_group_id"] = parentGroupID
+		groupUpdate["$set"].(bson.M)["lineage"] = lineage
+	}
+
+	return r.Store.ExecuteUpdateOneCommand(ctx, collection, groupFilter, groupUpdate, "group")
+}
+
 // SearchGroupsByExtension searches groups by extension field
 func (r *Repository) SearchGroupsByExtension(ctx context.Context, key string, value interface{}, page, pageSize int) ([]UniversalGroup, error) {
 	collection, err := r.GetGroupCollection(ctx)
@@ -590,6 +846,10 @@ func (r *Repository) SearchGroupsByExtension(ctx context.Context, key string, va
 		return nil, err
 	}
 
+	if err := r.hydrateGroupsMembers(ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -603,17 +863,18 @@ func (r *Repository) HasGroupDependents(ctx context.Context, groupID string) (bo
 		return false, err
 	}
 
+	memberFilter, err := r.memberMatchFilter(ctx, bson.M{
+		"id":   groupID,
+		"type": MemberTypeGroup,
+	})
+	if err != nil {
+		return false, err
+	}
+
 	queryFilter := bson.M{
 		"$or": []bson.M{
 			{"parent_group_id": groupID},
-			{
-				"members": bson.M{
-					"$elemMatch": bson.M{
-						"id":   groupID,
-						"type": MemberTypeGroup,
-					},
-				},
-			},
+			memberFilter,
 		},
 		"metadata.deleted_at": bson.M{"$exists": false},
 	}
@@ -633,6 +894,21 @@ func (r *Repository) AddMemberToGroup(ctx context.Context, groupID string, membe
 		return err
 	}
 
+	if r.membershipsEnabled {
+		membershipCollection, err := r.GetMembershipCollection(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = r.insertMembership(ctx, membershipCollection, NewGroupMembership(groupID, member, time.Now().UnixNano()))
+		if err != nil {
+			return err
+		}
+
+		_, err = r.refreshGroupMemberCount(ctx, groupID)
+		return err
+	}
+
 	queryFilter := bson.M{
 		"_id": groupID,
 	}
@@ -654,6 +930,21 @@ func (r *Repository) RemoveMemberFromGroup(ctx context.Context, groupID, memberI
 		return err
 	}
 
+	if r.membershipsEnabled {
+		membershipCollection, err := r.GetMembershipCollection(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = r.Store.ExecuteDeleteOneCommand(ctx, membershipCollection, bson.M{"_id": GenerateGroupMembershipID(groupID, memberID)}, "group-membership")
+		if err != nil {
+			return err
+		}
+
+		_, err = r.refreshGroupMemberCount(ctx, groupID)
+		return err
+	}
+
 	queryFilter := bson.M{
 		"_id":        groupID,
 		"members.id": memberID,
@@ -700,15 +991,14 @@ func (r *Repository) GetGroupIDsWithInvalidMembers(ctx context.Context) ([]strin
 		return nil, err
 	}
 
-	queryFilter := bson.M{
-		"members": bson.M{
-			"$elemMatch": bson.M{
-				"$or": bson.A{
-					bson.M{"id": ""},
-					bson.M{"id": nil},
-				},
-			},
+	queryFilter, err := r.memberMatchFilter(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"id": ""},
+			bson.M{"id": nil},
 		},
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, options.Find().SetProjection(bson.M{"_id": 1}))
@@ -735,6 +1025,10 @@ func (r *Repository) GetGroupIDsWithInvalidMembers(ctx context.Context) ([]strin
 
 // RepairInvalidMembers removes members with empty or null IDs from all affected groups.
 func (r *Repository) RepairInvalidMembers(ctx context.Context) error {
+	if r.membershipsEnabled {
+		return r.repairInvalidMemberships(ctx)
+	}
+
 	collection, err := r.GetGroupCollection(ctx)
 	if err != nil {
 		return err
@@ -764,6 +1058,39 @@ func (r *Repository) RepairInvalidMembers(ctx context.Context) error {
 	return r.Store.ExecuteUpdateManyCommand(ctx, collection, queryFilter, update, "groups")
 }
 
+// repairInvalidMemberships removes memberships with empty or null member IDs
+// and refreshes the member count of the affected groups.
+func (r *Repository) repairInvalidMemberships(ctx context.Context) error {
+	groupIDs, err := r.GetGroupIDsWithInvalidMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	membershipCollection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	queryFilter := bson.M{
+		"member_id": bson.M{
+			"$in": bson.A{"", nil},
+		},
+	}
+
+	err = r.Store.ExecuteDeleteManyCommand(ctx, membershipCollection, queryFilter, "group-memberships")
+	if err != nil {
+		return err
+	}
+
+	for _, groupID := range groupIDs {
+		if _, err := r.refreshGroupMemberCount(ctx, groupID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // BulkUpdateGroupsStatus updates status for multiple groups
 func (r *Repository) BulkUpdateGroupsStatus(ctx context.Context, groupIDs []string, status string) error {
 	collection, err := r.GetGroupCollection(ctx)
@@ -788,7 +1115,7 @@ func (r *Repository) BulkUpdateGroupsStatus(ctx context.Context, groupIDs []stri
 // Helper methods
 
 // buildGroupQueryFilter builds a query filter for group searches
-func (r *Repository) buildGroupQueryFilter(req *GetGroupsRequest) bson.M {
+func (r *Repository) buildGroupQueryFilter(ctx context.Context, req *GetGroupsRequest) (bson.M, error) {
 	queryFilter := bson.M{}
 
 	// Type filters
@@ -806,15 +1133,15 @@ func (r *Repository) buildGroupQueryFilter(req *GetGroupsRequest) bson.M {
 
 	// Member filters
 	if req.MemberID != "" {
-		memberFilter := bson.M{
-			"members": bson.M{
-				"$elemMatch": bson.M{
-					"id": req.MemberID,
-				},
-			},
+		memberMatch := bson.M{
+			"id": req.MemberID,
 		}
 		if req.MemberType != "" {
-			memberFilter["members"].(bson.M)["$elemMatch"].(bson.M)["type"] = req.MemberType
+			memberMatch["type"] = req.MemberType
+		}
+		memberFilter, err := r.memberMatchFilter(ctx, memberMatch)
+		if err != nil {
+			return nil, err
 		}
 		for key, value := range memberFilter {
 			queryFilter[key] = value
@@ -826,14 +1153,14 @@ func (r *Repository) buildGroupQueryFilter(req *GetGroupsRequest) bson.M {
 		memberConditions := []bson.M{}
 		for memberType, ids := range req.MembersWithIDs {
 			for _, id := range ids {
-				memberConditions = append(memberConditions, bson.M{
-					"members": bson.M{
-						"$elemMatch": bson.M{
-							"id":   id,
-							"type": memberType,
-						},
-					},
+				memberFilter, err := r.memberMatchFilter(ctx, bson.M{
+					"id":   id,
+					"type": memberType,
 				})
+				if err != nil {
+					return nil, err
+				}
+				memberConditions = append(memberConditions, memberFilter)
 			}
 		}
 		if len(memberConditions) > 0 {
@@ -869,7 +1196,7 @@ func (r *Repository) buildGroupQueryFilter(req *GetGroupsRequest) bson.M {
 		queryFilter["metadata.deleted_at"] = bson.M{"$exists": false}
 	}
 
-	return queryFilter
+	return queryFilter, nil
 }
 
 // buildSortOptions builds sort options based on order string
@@ -888,14 +1215,22 @@ func (r *Repository) buildSortOptions(order string) bson.D {
 	case GetGroupOrderNameDesc:
 		return bson.D{{Key: "name", Value: -1}}
 	case GetGroupOrderMemberCountDesc:
-		return bson.D{{Key: "members", Value: -1}}
+		return bson.D{{Key: r.memberCountSortKey(), Value: -1}}
 	case GetGroupOrderMemberCountAsc:
-		return bson.D{{Key: "members", Value: 1}}
+		return bson.D{{Key: r.memberCountSortKey(), Value: 1}}
 	default:
 		return bson.D{{Key: "metadata.created_at", Value: -1}}
 	}
 }
 
+// memberCountSortKey returns the field groups are sorted on when ordering by member count
+func (r *Repository) memberCountSortKey() string {
+	if r.membershipsEnabled {
+		return "member_count"
+	}
+	return "members"
+}
+
 // normaliseGroupName standardises group name
 func normaliseGroupName(name string) string {
 	return strings.TrimSpace(name)
@@ -908,6 +1243,12 @@ func (r *Repository) GetGroupsStatsCounts(ctx context.Context) (*AllGroupsStats,
 		return nil, err
 	}
 
+	// Members are counted from the stored member count when they live in the memberships collection
+	memberCountExpression := bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}
+	if r.membershipsEnabled {
+		memberCountExpression = bson.M{"$ifNull": bson.A{"$member_count", 0}}
+	}
+
 	// Exclude soft-deleted groups
 	baseMatch := bson.D{{Key: "$match", Value: bson.M{"metadata.deleted_at": bson.M{"$exists": false}}}}
 
@@ -946,7 +1287,7 @@ func (r *Repository) GetGroupsStatsCounts(ctx context.Context) (*AllGroupsStats,
 			{Key: "with_owner", Value: countPipeline(bson.M{"owner_id": bson.M{"$exists": true, "$ne": ""}})},
 			// Total members (sum of members array sizes)
 			{Key: "member_totals", Value: bson.A{
-				bson.D{{Key: "$project", Value: bson.M{"member_count": bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}}}},
+				bson.D{{Key: "$project", Value: bson.M{"member_count": memberCountExpression}}},
 				bson.D{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$member_count"}}}},
 			}},
 		}}},
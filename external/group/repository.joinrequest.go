@@ -0,0 +1,135 @@
+package group
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// GroupJoinRequestCollection collection name for group join requests
+const GroupJoinRequestCollection string = "group_join_requests"
+
+// GetJoinRequestCollection returns collection used for group join requests
+func (r *Repository) GetJoinRequestCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.joinRequestCollectionMutex.Lock()
+	defer r.joinRequestCollectionMutex.Unlock()
+
+	if r.joinRequestCollection != nil {
+		return r.joinRequestCollection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.joinRequestCollection = db.Collection(GroupJoinRequestCollection)
+		return r.joinRequestCollection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, GroupJoinRequestCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// CreateJoinRequest stores a new join request
+func (r *Repository) CreateJoinRequest(ctx context.Context, joinRequest *GroupJoinRequest) (*GroupJoinRequest, error) {
+	collection, err := r.GetJoinRequestCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, joinRequest, "group-join-request")
+	if err != nil {
+		return nil, err
+	}
+
+	return joinRequest, nil
+}
+
+// GetJoinRequestByID retrieves a join request by ID
+func (r *Repository) GetJoinRequestByID(ctx context.Context, id string) (*GroupJoinRequest, error) {
+	collection, err := r.GetJoinRequestCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"_id": id,
+	}
+
+	var result GroupJoinRequest
+	err = r.Store.ExecuteFindOneCommandDecodeResult(ctx, collection, queryFilter, &result, "group-join-request", true, ErrJoinRequestNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetJoinRequests retrieves join requests matching the request filters, newest first
+func (r *Repository) GetJoinRequests(ctx context.Context, req *GetJoinRequestsRequest) ([]GroupJoinRequest, error) {
+	collection, err := r.GetJoinRequestCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{}
+	if req.GroupID != "" {
+		queryFilter["group_id"] = req.GroupID
+	}
+	if req.UserID != "" {
+		queryFilter["user_id"] = req.UserID
+	}
+	if req.Status != "" {
+		queryFilter["status"] = req.Status
+	}
+
+	options := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, options)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []GroupJoinRequest{}
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &results, "group-join-request")
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// UpdateJoinRequest replaces a stored join request
+func (r *Repository) UpdateJoinRequest(ctx context.Context, joinRequest *GroupJoinRequest) (*GroupJoinRequest, error) {
+	collection, err := r.GetJoinRequestCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"_id": joinRequest.ID,
+	}
+
+	err = r.Store.ExecuteReplaceOneCommand(ctx, collection, queryFilter, joinRequest, "group-join-request")
+	if err != nil {
+		return nil, err
+	}
+
+	return joinRequest, nil
+}
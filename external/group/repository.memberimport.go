@@ -0,0 +1,211 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// GroupMemberImportCollection collection name for bulk member imports
+const GroupMemberImportCollection string = "group_member_imports"
+
+// GetMemberImportCollection returns collection used for bulk member imports
+func (r *Repository) GetMemberImportCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.memberImportCollectionMutex.Lock()
+	defer r.memberImportCollectionMutex.Unlock()
+
+	if r.memberImportCollection != nil {
+		return r.memberImportCollection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.memberImportCollection = db.Collection(GroupMemberImportCollection)
+		return r.memberImportCollection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, GroupMemberImportCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// CreateMemberImport stores a new member import
+func (r *Repository) CreateMemberImport(ctx context.Context, memberImport *MemberImportJob) (*MemberImportJob, error) {
+	collection, err := r.GetMemberImportCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, memberImport, "group-member-import")
+	if err != nil {
+		return nil, err
+	}
+
+	return memberImport, nil
+}
+
+// GetMemberImportByID retrieves a member import by ID
+func (r *Repository) GetMemberImportByID(ctx context.Context, id string) (*MemberImportJob, error) {
+	collection, err := r.GetMemberImportCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"_id": id,
+	}
+
+	var result MemberImportJob
+	err = r.Store.ExecuteFindOneCommandDecodeResult(ctx, collection, queryFilter, &result, "group-member-import", true, ErrMemberImportNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateMemberImport replaces a stored member import. Claimed imports are only replaced
+// while the claim is still held, so a service that lost its claim cannot overwrite progress
+func (r *Repository) UpdateMemberImport(ctx context.Context, memberImport *MemberImportJob) (*MemberImportJob, error) {
+	collection, err := r.GetMemberImportCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"_id": memberImport.ID,
+	}
+	if memberImport.ClaimedBy != "" {
+		queryFilter["claimed_by"] = memberImport.ClaimedBy
+	}
+
+	err = r.Store.ExecuteReplaceOneCommand(ctx, collection, queryFilter, memberImport, "group-member-import")
+	if err != nil {
+		return nil, err
+	}
+
+	return memberImport, nil
+}
+
+// ClaimMemberImport marks a member import as running for claimedBy, as long as it is pending
+// or its last heartbeat is before staleBefore. The claim is atomic so only one service
+// processes an import at a time
+func (r *Repository) ClaimMemberImport(ctx context.Context, id, claimedBy, staleBefore, claimedAt string) (*MemberImportJob, error) {
+	collection, err := r.GetMemberImportCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"status": MemberImportStatusPending},
+			bson.M{
+				"status": MemberImportStatusRunning,
+				"$or": bson.A{
+					bson.M{"heartbeat_at": bson.M{"$exists": false}},
+					bson.M{"heartbeat_at": bson.M{"$lt": staleBefore}},
+				},
+			},
+		},
+	}
+
+	updateFilter := bson.M{
+		"$set": bson.M{
+			"status":       MemberImportStatusRunning,
+			"claimed_by":   claimedBy,
+			"heartbeat_at": claimedAt,
+			"updated_at":   claimedAt,
+		},
+	}
+
+	var result MemberImportJob
+	err = collection.FindOneAndUpdate(ctx, queryFilter, updateFilter, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrMemberImportAlreadyClaimed
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// HeartbeatMemberImport records that claimedBy is still processing a member import. It
+// returns ErrMemberImportAlreadyClaimed once the import is claimed by someone else
+func (r *Repository) HeartbeatMemberImport(ctx context.Context, id, claimedBy, heartbeatAt string) error {
+	collection, err := r.GetMemberImportCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	queryFilter := bson.M{
+		"_id":        id,
+		"claimed_by": claimedBy,
+		"status":     MemberImportStatusRunning,
+	}
+
+	updateFilter := bson.M{
+		"$set": bson.M{
+			"heartbeat_at": heartbeatAt,
+		},
+	}
+
+	err = collection.FindOneAndUpdate(ctx, queryFilter, updateFilter).Err()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrMemberImportAlreadyClaimed
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetStaleMemberImports retrieves the member imports that are pending or running but have
+// had no heartbeat since staleBefore
+func (r *Repository) GetStaleMemberImports(ctx context.Context, staleBefore string) ([]MemberImportJob, error) {
+	collection, err := r.GetMemberImportCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"status": bson.M{"$in": bson.A{MemberImportStatusPending, MemberImportStatusRunning}},
+		"$or": bson.A{
+			bson.M{"heartbeat_at": bson.M{"$exists": false}},
+			bson.M{"heartbeat_at": bson.M{"$lt": staleBefore}},
+		},
+	}
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []MemberImportJob{}
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &results, "group-member-import")
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,461 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// GroupMembershipCollection collection name for group memberships
+const GroupMembershipCollection string = "group_memberships"
+
+// WithMembershipCollection stores group members in the memberships collection
+// instead of embedding them in the group document. Groups returned by the
+// repository still carry their members, so callers see no difference.
+//
+// Existing data must be moved with the group memberships migration before enabling.
+func (r *Repository) WithMembershipCollection() *Repository {
+	r.membershipsEnabled = true
+	return r
+}
+
+// UsesMembershipCollection returns true when members are stored in the memberships collection
+func (r *Repository) UsesMembershipCollection() bool {
+	return r.membershipsEnabled
+}
+
+// GetMembershipCollection returns collection used for group memberships
+func (r *Repository) GetMembershipCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.membershipCollectionMutex.Lock()
+	defer r.membershipCollectionMutex.Unlock()
+
+	if r.membershipCollection != nil {
+		return r.membershipCollection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.membershipCollection = db.Collection(GroupMembershipCollection)
+		return r.membershipCollection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, GroupMembershipCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// GetGroupMembers retrieves a page of a group's members, filtered by type and role.
+// A zero PerPage returns every matching member.
+func (r *Repository) GetGroupMembers(ctx context.Context, req *GetGroupMembersRequest) ([]Member, int64, error) {
+	if !r.membershipsEnabled {
+		group, err := r.GetGroupByID(ctx, req.GroupID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		members := filterMembers(group.Members, req.MemberType, req.Role)
+		return paginateMembers(members, req.Page, req.PerPage), int64(len(members)), nil
+	}
+
+	groupCollection, err := r.GetGroupCollection(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	groupCount, err := r.Store.ExecuteCountDocuments(ctx, groupCollection, bson.M{"_id": req.GroupID})
+	if err != nil {
+		return nil, 0, err
+	}
+	if groupCount == 0 {
+		return nil, 0, ErrResourceNotFound
+	}
+
+	collection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	queryFilter := bson.M{
+		"group_id": req.GroupID,
+	}
+	if req.MemberType != "" {
+		queryFilter["type"] = req.MemberType
+	}
+	if req.Role != "" {
+		queryFilter["role"] = req.Role
+	}
+
+	total, err := r.Store.ExecuteCountDocuments(ctx, collection, queryFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+	if req.PerPage > 0 {
+		page := req.Page
+		if page < 1 {
+			page = 1
+		}
+		findOptions.SetSkip(int64((page - 1) * req.PerPage)).SetLimit(int64(req.PerPage))
+	}
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var memberships []GroupMembership
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &memberships, "group-membership")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	members := make([]Member, 0, len(memberships))
+	for i := range memberships {
+		members = append(members, memberships[i].ToMember())
+	}
+
+	return members, total, nil
+}
+
+// loadMemberships retrieves the memberships of the provided groups, in the order they were added
+func (r *Repository) loadMemberships(ctx context.Context, groupIDs []string) (map[string][]GroupMembership, error) {
+	collection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter := bson.M{
+		"group_id": bson.M{"$in": groupIDs},
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "group_id", Value: 1}, {Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberships []GroupMembership
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &memberships, "group-membership")
+	if err != nil {
+		return nil, err
+	}
+
+	membershipsByGroupID := make(map[string][]GroupMembership, len(groupIDs))
+	for _, membership := range memberships {
+		membershipsByGroupID[membership.GroupID] = append(membershipsByGroupID[membership.GroupID], membership)
+	}
+
+	return membershipsByGroupID, nil
+}
+
+// hydrateGroupMembers populates a group's members from the memberships collection
+func (r *Repository) hydrateGroupMembers(ctx context.Context, group *UniversalGroup) error {
+	if !r.membershipsEnabled || group == nil {
+		return nil
+	}
+
+	membershipsByGroupID, err := r.loadMemberships(ctx, []string{group.ID})
+	if err != nil {
+		return err
+	}
+
+	applyMemberships(group, membershipsByGroupID[group.ID])
+	return nil
+}
+
+// hydrateGroupsMembers populates the members of every group with a single memberships query
+func (r *Repository) hydrateGroupsMembers(ctx context.Context, groups []UniversalGroup) error {
+	if !r.membershipsEnabled || len(groups) == 0 {
+		return nil
+	}
+
+	groupIDs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		groupIDs = append(groupIDs, group.ID)
+	}
+
+	membershipsByGroupID, err := r.loadMemberships(ctx, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		applyMemberships(&groups[i], membershipsByGroupID[groups[i].ID])
+	}
+
+	return nil
+}
+
+// applyMemberships sets the group's members and remembers what was loaded
+func applyMemberships(group *UniversalGroup, memberships []GroupMembership) {
+	group.Members = nil
+	group.membershipSnapshot = make(map[string]GroupMembership, len(memberships))
+
+	for _, membership := range memberships {
+		group.Members = append(group.Members, membership.ToMember())
+		group.membershipSnapshot[membership.MemberID] = membership
+	}
+}
+
+// saveGroupMemberships writes the members that were added, changed or removed
+// since the group was loaded. Each member is its own document, so concurrent
+// changes to different members of the same group do not overwrite each other.
+func (r *Repository) saveGroupMemberships(ctx context.Context, group *UniversalGroup) error {
+	collection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshot := group.membershipSnapshot
+	if snapshot == nil {
+		membershipsByGroupID, err := r.loadMemberships(ctx, []string{group.ID})
+		if err != nil {
+			return err
+		}
+
+		snapshot = make(map[string]GroupMembership, len(membershipsByGroupID[group.ID]))
+		for _, membership := range membershipsByGroupID[group.ID] {
+			snapshot[membership.MemberID] = membership
+		}
+	}
+
+	// new members are positioned after everything added before them
+	nextPosition := time.Now().UnixNano()
+
+	current := make(map[string]GroupMembership, len(group.Members))
+	for _, member := range group.Members {
+		if member.ID == "" {
+			continue
+		}
+		if _, ok := current[member.ID]; ok {
+			continue
+		}
+
+		previous, ok := snapshot[member.ID]
+		if !ok {
+			membership := NewGroupMembership(group.ID, member, nextPosition)
+			nextPosition++
+
+			if err := r.insertMembership(ctx, collection, membership); err != nil {
+				return err
+			}
+
+			current[member.ID] = membership
+			continue
+		}
+
+		membership := NewGroupMembership(group.ID, member, previous.Position)
+		if !reflect.DeepEqual(membership, previous) {
+			err := r.Store.ExecuteReplaceOneCommand(ctx, collection, bson.M{"_id": membership.ID}, membership, "group-membership")
+			if err != nil {
+				return err
+			}
+		}
+
+		current[member.ID] = membership
+	}
+
+	for memberID, membership := range snapshot {
+		if _, ok := current[memberID]; ok {
+			continue
+		}
+
+		err := r.Store.ExecuteDeleteOneCommand(ctx, collection, bson.M{"_id": membership.ID}, "group-membership")
+		if err != nil {
+			return err
+		}
+	}
+
+	group.membershipSnapshot = current
+	return nil
+}
+
+// insertMembership adds a membership, replacing it when the member was added concurrently
+func (r *Repository) insertMembership(ctx context.Context, collection *mongo.Collection, membership GroupMembership) error {
+	_, err := r.Store.ExecuteInsertOneCommand(ctx, collection, membership, "group-membership")
+	if mongo.IsDuplicateKeyError(err) {
+		return r.Store.ExecuteReplaceOneCommand(ctx, collection, bson.M{"_id": membership.ID}, membership, "group-membership")
+	}
+
+	return err
+}
+
+// refreshGroupMemberCount recalculates the member count stored on the group document
+func (r *Repository) refreshGroupMemberCount(ctx context.Context, groupID string) (int, error) {
+	membershipCollection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := r.Store.ExecuteCountDocuments(ctx, membershipCollection, bson.M{"group_id": groupID})
+	if err != nil {
+		return 0, err
+	}
+
+	collection, err := r.GetGroupCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"member_count": count,
+		},
+	}
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": groupID}, update, "group")
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// memberMatchFilter returns the group filter matching groups with a member that
+// satisfies memberMatch, which is written against the embedded member fields.
+// When memberships are stored separately the matching group IDs are resolved
+// through the memberships collection indexes.
+func (r *Repository) memberMatchFilter(ctx context.Context, memberMatch bson.M) (bson.M, error) {
+	if !r.membershipsEnabled {
+		return bson.M{
+			"members": bson.M{
+				"$elemMatch": memberMatch,
+			},
+		}, nil
+	}
+
+	groupIDs, err := r.getGroupIDsByMembership(ctx, membershipFilterFromMemberMatch(memberMatch))
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{
+		"_id": bson.M{"$in": groupIDs},
+	}, nil
+}
+
+// getGroupIDsByMembership returns the distinct IDs of groups holding memberships matching the filter
+func (r *Repository) getGroupIDsByMembership(ctx context.Context, queryFilter bson.M) ([]string, error) {
+	collection, err := r.GetMembershipCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, options.Find().SetProjection(bson.M{"group_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	type groupIDResult struct {
+		GroupID string `bson:"group_id"`
+	}
+
+	results := []groupIDResult{}
+	if err := r.Store.MapAllInCursorToResult(ctx, cursor, &results, "group-memberships"); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(results))
+	groupIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		if seen[result.GroupID] {
+			continue
+		}
+		seen[result.GroupID] = true
+		groupIDs = append(groupIDs, result.GroupID)
+	}
+
+	return groupIDs, nil
+}
+
+// membershipFilterFromMemberMatch rewrites an embedded member match so it can
+// be run against the memberships collection
+func membershipFilterFromMemberMatch(memberMatch bson.M) bson.M {
+	queryFilter := bson.M{}
+	for key, value := range memberMatch {
+		if key == "id" {
+			key = "member_id"
+		}
+
+		switch conditions := value.(type) {
+		case []bson.M:
+			rewritten := make([]bson.M, 0, len(conditions))
+			for _, condition := range conditions {
+				rewritten = append(rewritten, membershipFilterFromMemberMatch(condition))
+			}
+			value = rewritten
+		case bson.A:
+			rewritten := make(bson.A, 0, len(conditions))
+			for _, condition := range conditions {
+				if conditionMatch, ok := condition.(bson.M); ok {
+					condition = membershipFilterFromMemberMatch(conditionMatch)
+				}
+				rewritten = append(rewritten, condition)
+			}
+			value = rewritten
+		}
+
+		queryFilter[key] = value
+	}
+
+	return queryFilter
+}
+
+// filterMembers returns the members matching the optional type and role
+func filterMembers(members []Member, memberType, role string) []Member {
+	filtered := []Member{}
+	for _, member := range members {
+		if memberType != "" && member.Type != memberType {
+			continue
+		}
+		if role != "" && member.Role != role {
+			continue
+		}
+		filtered = append(filtered, member)
+	}
+
+	return filtered
+}
+
+// paginateMembers returns the requested page of members, a zero perPage returns them all
+func paginateMembers(members []Member, page, perPage int) []Member {
+	if perPage <= 0 {
+		return members
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * perPage
+	if start >= len(members) {
+		return []Member{}
+	}
+
+	end := start + perPage
+	if end > len(members) {
+		end = len(members)
+	}
+
+	return members[start:end]
+}
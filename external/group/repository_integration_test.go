@@ -276,6 +276,101 @@ func TestIntegration_GroupRepository_FullLifecycle(t *testing.T) {
 	})
 }
 
+func TestIntegration_GroupRepository_MembershipCollection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	mongoServer, err := memongo.StartWithOptions(&memongo.Options{MongoVersion: "7.0.14"})
+	if err != nil {
+		t.Skipf("skipping integration test: unable to start memongo: %v", err)
+	}
+	t.Cleanup(func() {
+		mongoServer.Stop()
+	})
+
+	dbName := memongo.RandomDatabase()
+	mongoHandler, err := repositoryhelpers.NewHandler(repositoryhelpers.DefaultConfig(mongoServer.URI(), dbName))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = mongoHandler.Close(ctx)
+	})
+
+	store := repository.NewMongoDbRepositoryWithDefaults(mongoHandler, dbName)
+	repo := group.NewRepository(store).WithMembershipCollection()
+	idGen := group.NewDefaultIDGenerator()
+
+	created, err := repo.CreateGroup(ctx, &group.UniversalGroup{
+		ID:      idGen.GenerateUUID(),
+		Name:    "Memberships Test",
+		RawName: "Memberships Test",
+		Type:    group.GroupTypeTeam,
+		Status:  group.GroupStatusActive,
+		OwnerID: testUserID,
+		Members: []group.Member{
+			{ID: "user-1", Type: group.MemberTypeUser, Role: group.MemberRoleAdmin},
+			{ID: "user-2", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, created.MemberCount)
+
+	t.Run("Group responses still carry members", func(t *testing.T) {
+		retrieved, err := repo.GetGroupByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, retrieved.Members, 2)
+		assert.Equal(t, "user-1", retrieved.Members[0].ID)
+		assert.Equal(t, "user-2", retrieved.Members[1].ID)
+	})
+
+	t.Run("Concurrent member changes do not overwrite each other", func(t *testing.T) {
+		first, err := repo.GetGroupByID(ctx, created.ID)
+		require.NoError(t, err)
+		second, err := repo.GetGroupByID(ctx, created.ID)
+		require.NoError(t, err)
+
+		_, err = first.AddMember("user-3", group.MemberTypeUser, group.MemberRoleMember)
+		require.NoError(t, err)
+		_, err = repo.UpdateGroup(ctx, first)
+		require.NoError(t, err)
+
+		_, err = second.RemoveMember("user-2")
+		require.NoError(t, err)
+		_, err = repo.UpdateGroup(ctx, second)
+		require.NoError(t, err)
+
+		retrieved, err := repo.GetGroupByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.HasMember("user-1"))
+		assert.False(t, retrieved.HasMember("user-2"))
+		assert.True(t, retrieved.HasMember("user-3"))
+	})
+
+	t.Run("Find groups and page through members", func(t *testing.T) {
+		groups, err := repo.GetGroupsByMemberID(ctx, "user-3", group.MemberTypeUser, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, created.ID, groups[0].ID)
+
+		members, total, err := repo.GetGroupMembers(ctx, &group.GetGroupMembersRequest{GroupID: created.ID, Page: 2, PerPage: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, members, 1)
+		assert.Equal(t, "user-3", members[0].ID)
+	})
+
+	t.Run("Deleting a group removes its memberships", func(t *testing.T) {
+		err := repo.DeleteGroupByID(ctx, created.ID)
+		require.NoError(t, err)
+
+		groups, err := repo.GetGroupsByMemberID(ctx, "user-1", "", 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+}
+
 func TestIntegration_GroupService_GetGroupsByUserID_WithSampleDataset(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -1,5 +1,7 @@
 package group
 
+import "time"
+
 // RemoveUserFromAllGroupsRequest represents the request to remove a user from all groups
 type RemoveUserFromAllGroupsRequest struct {
 
@@ -97,6 +99,9 @@ type CreateGroupRequest struct {
 
 	// Initial owner
 	OwnerID string `json:"owner_id,omitempty"`
+
+	// ActorID is the user creating the group
+	ActorID string `json:"-"`
 }
 
 // CreateMemberRequest defines member data for creation
@@ -118,6 +123,9 @@ type UpdateGroupRequest struct {
 	Extensions  map[string]interface{} `json:"extensions,omitempty"`
 	// Group if specified updates entire group object
 	Group *UniversalGroup `json:"group,omitempty"`
+
+	// ActorID is the user updating the group
+	ActorID string `json:"-"`
 }
 
 // AddMemberRequest defines the request for adding a member
@@ -126,6 +134,9 @@ type AddMemberRequest struct {
 	MemberID string `json:"member_id" validate:"required"`
 	Type     string `json:"type" validate:"required"`
 	Role     string `json:"role,omitempty"`
+
+	// ActorID is the user adding the member
+	ActorID string `json:"-"`
 }
 
 // InviteUserRequest defines the request for inviting a user by email
@@ -150,6 +161,33 @@ type AcceptInviteRequest struct {
 	UserID      string `json:"user_id" validate:"required"`
 }
 
+// ResendInviteRequest defines the request for resending an invitation email
+type ResendInviteRequest struct {
+	GroupID     string `path:"groupID"`
+	InviteEmail string `json:"invite_email" validate:"required,email"`
+	ResentByID  string `json:"resent_by_id,omitempty"`
+}
+
+// AcceptInviteByTokenRequest defines the request for accepting an invitation from a signed link
+type AcceptInviteByTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+
+	// UserID is the user accepting the invitation
+	UserID string `json:"user_id" validate:"required"`
+
+	// UserEmail is the accepting user's email, it must match the invited email
+	UserEmail string `json:"user_email" validate:"required,email"`
+}
+
+// ExpireStaleInvitesRequest defines the request for expiring invitations past their expiry
+type ExpireStaleInvitesRequest struct{}
+
+// RunInvitationExpiryRequest defines the request for sweeping stale invitations on a schedule
+type RunInvitationExpiryRequest struct {
+	// Interval is how often stale invitations are swept, defaults to an hour
+	Interval time.Duration
+}
+
 // RejectInviteRequest defines the request for rejecting an invitation
 type RejectInviteRequest struct {
 	GroupID      string `path:"groupID"`
@@ -162,6 +200,9 @@ type RemoveMemberRequest struct {
 	GroupID             string `path:"groupID"`
 	MemberID            string `path:"memberID"`
 	ConfirmOwnerRemoval bool   `query:"confirm_owner_removal"`
+
+	// ActorID is the user removing the member
+	ActorID string `json:"-"`
 }
 
 // UpdateMemberRoleRequest defines the request for updating a member's role
@@ -169,6 +210,12 @@ type UpdateMemberRoleRequest struct {
 	GroupID  string `path:"groupID"`
 	MemberID string `path:"memberID"`
 	NewRole  string `json:"new_role" validate:"required"`
+
+	// ActorID is the user changing the role
+	ActorID string `json:"-"`
+
+	// ActorIsPlatformAdmin skips the role bounds for platform administrators
+	ActorIsPlatformAdmin bool `json:"-"`
 }
 
 // GetGroupMembersRequest defines the request for getting group members
@@ -176,6 +223,10 @@ type GetGroupMembersRequest struct {
 	GroupID    string `path:"groupID"`
 	MemberType string `query:"member_type"`
 	Role       string `query:"role"`
+
+	// Pagination, members are only paginated when per_page is provided
+	Page    int `query:"page"`
+	PerPage int `query:"per_page"`
 }
 
 // UpdateOwnerRequest defines the request for updating ownership.
@@ -186,6 +237,9 @@ type UpdateOwnerRequest struct {
 
 	// OwnerID is the new owner's user ID (nil = no change, empty string = clear)
 	OwnerID *string `json:"owner_id,omitempty"`
+
+	// ActorID is the user changing the owner
+	ActorID string `json:"-"`
 }
 
 // DeleteGroupRequest defines the request for deleting a group
@@ -198,11 +252,60 @@ type DeleteGroupRequest struct {
 // ArchiveGroupRequest defines the request for archiving a group
 type ArchiveGroupRequest struct {
 	ID string `path:"groupID"`
+
+	// ActorID is the user archiving the group
+	ActorID string `json:"-"`
+}
+
+// MoveGroupRequest defines the request for moving a group and its descendants under a new parent
+type MoveGroupRequest struct {
+	GroupID string `path:"groupID"`
+
+	// NewParentGroupID is the group to move under, empty moves the group to the top level
+	NewParentGroupID string `json:"new_parent_group_id"`
+
+	// PropagateMembers adds members of the moved groups that are missing from the new
+	// root group to it, instead of removing them from the moved groups
+	PropagateMembers bool `json:"propagate_members,omitempty"`
+
+	// MovedByID is the user moving the group
+	MovedByID string `json:"moved_by_id,omitempty"`
+}
+
+// GetGroupRolesRequest defines the request for listing the roles available in a group
+type GetGroupRolesRequest struct {
+	GroupID string `path:"groupID"`
+}
+
+// SetGroupRoleRequest defines the request for creating or updating a group's custom role
+type SetGroupRoleRequest struct {
+	GroupID     string   `path:"groupID"`
+	RoleName    string   `path:"roleName"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions" validate:"required"`
+
+	// UpdatedByID is the user defining the role
+	UpdatedByID string `json:"-"`
+
+	// UpdatedByIsPlatformAdmin skips the permission bounds for platform administrators
+	UpdatedByIsPlatformAdmin bool `json:"-"`
+}
+
+// DeleteGroupRoleRequest defines the request for deleting a group's custom role
+type DeleteGroupRoleRequest struct {
+	GroupID  string `path:"groupID"`
+	RoleName string `path:"roleName"`
+
+	// DeletedByID is the user deleting the role
+	DeletedByID string `json:"-"`
 }
 
 // RestoreGroupRequest defines the request for restoring a group
 type RestoreGroupRequest struct {
 	ID string `path:"groupID"`
+
+	// ActorID is the user restoring the group
+	ActorID string `json:"-"`
 }
 
 // GetGroupStatsRequest defines the request for getting group statistics
@@ -289,3 +392,80 @@ type EnableGroupAutoInviteByEmailDomainRequest struct {
 type DisableGroupAutoInviteByEmailDomainRequest struct {
 	GroupID string `path:"groupID"`
 }
+
+// RequestToJoinGroupRequest defines the request for asking to join a group
+type RequestToJoinGroupRequest struct {
+	GroupID string `json:"group_id" validate:"required"`
+	UserID  string `json:"-"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetJoinRequestsRequest defines the request for listing join requests.
+// Empty fields are not used as filters.
+type GetJoinRequestsRequest struct {
+	GroupID string `path:"groupID"`
+	UserID  string `query:"user_id"`
+	Status  string `query:"status"`
+}
+
+// ReviewJoinRequestRequest defines the request for approving or denying a join request
+type ReviewJoinRequestRequest struct {
+	GroupID       string `path:"groupID"`
+	JoinRequestID string `path:"joinRequestID"`
+	Reason        string `json:"reason,omitempty"`
+	Role          string `json:"role,omitempty"`
+	ReviewerID    string `json:"-"`
+
+	// ReviewerIsPlatformAdmin skips the group admin check for platform administrators
+	ReviewerIsPlatformAdmin bool `json:"-"`
+}
+
+// CancelJoinRequestRequest defines the request for a requester withdrawing their join request
+type CancelJoinRequestRequest struct {
+	JoinRequestID string `path:"joinRequestID"`
+	UserID        string `json:"-"`
+}
+
+// GetGroupActivityRequest defines the request for listing a group's activity feed.
+// Empty fields are not used as filters.
+type GetGroupActivityRequest struct {
+	GroupID            string   `path:"groupID"`
+	IncludeDescendants bool     `query:"include_descendants"`
+	ActorID            string   `query:"actor_id"`
+	Actions            []string `json:"-"`
+
+	// From and To bound when the activity occurred, as RFC 3339 timestamps. From is inclusive
+	// and To is exclusive.
+	From string `query:"from"`
+	To   string `query:"to"`
+
+	// Cursor is the next_cursor of the previous page
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+// MemberImportEntry is one member to import, as read from an uploaded CSV or JSON file
+type MemberImportEntry struct {
+	Email string `json:"email"`
+	Role  string `json:"role,omitempty"`
+}
+
+// ImportMembersRequest defines the request for importing members into a group in bulk.
+// Entries without a role are imported as members
+type ImportMembersRequest struct {
+	GroupID string              `path:"groupID"`
+	ActorID string              `json:"-"`
+	Entries []MemberImportEntry `json:"members"`
+
+	// DryRun validates the entries and reports on them without importing anything
+	DryRun bool `query:"dry_run"`
+}
+
+// GetMemberImportRequest defines the request for checking the progress of a member import
+type GetMemberImportRequest struct {
+	GroupID  string `path:"groupID"`
+	ImportID string `path:"importID"`
+}
+
+// ResumeStaleMemberImportsRequest defines the request for resuming member imports abandoned part way through
+type ResumeStaleMemberImportsRequest struct{}
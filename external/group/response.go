@@ -46,6 +46,9 @@ type UserGroupAccessSummary struct {
 
 	// IsAdmin is true when the user has effective admin privileges in that group.
 	IsAdmin bool `json:"is_admin"`
+
+	// Permissions are the user's effective permissions in that group, including inherited ones.
+	Permissions []string `json:"permissions,omitempty"`
 }
 
 // GetMetaData returns metadata in reply.WithMeta format
@@ -115,6 +118,12 @@ type DeleteGroupResponse struct {
 type GetGroupMembersResponse struct {
 	Members []Member `json:"members"`
 	Count   int      `json:"count"`
+
+	// Pagination details, only set when a page of members was requested
+	Total      int `json:"total,omitempty"`
+	TotalPages int `json:"total_pages,omitempty"`
+	Page       int `json:"page,omitempty"`
+	PerPage    int `json:"per_page,omitempty"`
 }
 
 // AddMemberResponse defines the response for adding a member
@@ -126,6 +135,29 @@ type AddMemberResponse struct {
 type InviteUserResponse struct {
 	Group       *UniversalGroup `json:"group"`
 	InviteEmail string          `json:"invite_email"`
+	ExpiresAt   string          `json:"expires_at,omitempty"`
+	EmailSent   bool            `json:"email_sent"`
+}
+
+// ResendInviteResponse defines the response for resending an invitation
+type ResendInviteResponse struct {
+	Group       *UniversalGroup `json:"group"`
+	InviteEmail string          `json:"invite_email"`
+	ExpiresAt   string          `json:"expires_at"`
+	SendCount   int             `json:"send_count"`
+}
+
+// AcceptInviteByTokenResponse defines the response for accepting an invitation from a signed link
+type AcceptInviteByTokenResponse struct {
+	Group       *UniversalGroup `json:"group"`
+	InviteEmail string          `json:"invite_email"`
+	UserID      string          `json:"user_id"`
+}
+
+// ExpireStaleInvitesResponse defines the response for expiring stale invitations
+type ExpireStaleInvitesResponse struct {
+	ExpiredCount int      `json:"expired_count"`
+	GroupIDs     []string `json:"group_ids"`
 }
 
 // UninviteUserResponse defines the response for revoking an invitation
@@ -177,6 +209,48 @@ type ArchiveGroupResponse struct {
 	Group *UniversalGroup `json:"group"`
 }
 
+// GroupRoleSummary describes a role that can be assigned in a group and the permissions it grants
+type GroupRoleSummary struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+	Custom      bool     `json:"custom"`
+}
+
+// GetGroupRolesResponse defines the response for listing the roles available in a group
+type GetGroupRolesResponse struct {
+	Roles []GroupRoleSummary `json:"roles"`
+}
+
+// SetGroupRoleResponse defines the response for creating or updating a custom role
+type SetGroupRoleResponse struct {
+	Group   *UniversalGroup `json:"group"`
+	Role    *GroupRole      `json:"role"`
+	Created bool            `json:"created"`
+}
+
+// DeleteGroupRoleResponse defines the response for deleting a custom role
+type DeleteGroupRoleResponse struct {
+	Group *UniversalGroup `json:"group"`
+}
+
+// MoveGroupResponse defines the response for moving a group
+type MoveGroupResponse struct {
+	Group *UniversalGroup `json:"group"`
+
+	// MovedDescendantIDs are the descendants whose lineage was rewritten
+	MovedDescendantIDs []string `json:"moved_descendant_ids"`
+
+	// RemovedMembers maps group IDs to the members removed because they are not in the new root group
+	RemovedMembers map[string][]string `json:"removed_members,omitempty"`
+
+	// PropagatedMemberIDs are the members added to the new root group
+	PropagatedMemberIDs []string `json:"propagated_member_ids,omitempty"`
+
+	// FailedGroupIDs are groups whose membership could not be updated
+	FailedGroupIDs []string `json:"failed_group_ids,omitempty"`
+}
+
 // RestoreGroupResponse defines the response for restoring a group
 type RestoreGroupResponse struct {
 	Group *UniversalGroup `json:"group"`
@@ -282,6 +356,14 @@ type Roles struct {
 	TypeOverrides map[string][]string `json:"type_overrides"`
 }
 
+// Permissions describes the registered permissions and the roles that grant them.
+type Permissions struct {
+	Registered    []string                       `json:"registered"`
+	Roles         map[string][]string            `json:"roles"`
+	TypeOverrides map[string]map[string][]string `json:"type_overrides,omitempty"`
+	Inheritance   string                         `json:"inheritance"`
+}
+
 // GroupConfigCapabilities describes the capabilities exposed by the group config.
 type GroupConfigCapabilities struct {
 	RequiredFields      []string     `json:"required_fields,omitempty"`
@@ -290,6 +372,7 @@ type GroupConfigCapabilities struct {
 	Types               Types        `json:"types"`
 	GroupNesting        GroupNesting `json:"group_nesting"`
 	Roles               Roles        `json:"roles"`
+	Permissions         Permissions  `json:"permissions"`
 }
 
 // GetGroupsConfigResponse defines the response for the groups config endpoint.
@@ -352,3 +435,52 @@ type EnableGroupAutoInviteByEmailDomainResponse struct {
 type DisableGroupAutoInviteByEmailDomainResponse struct {
 	Group *UniversalGroup `json:"group"`
 }
+
+// RequestToJoinGroupResponse defines the response for asking to join a group.
+// Group is only populated when the requester was added straight away.
+type RequestToJoinGroupResponse struct {
+	JoinRequest *GroupJoinRequest `json:"join_request"`
+	Group       *UniversalGroup   `json:"group,omitempty"`
+}
+
+// GetJoinRequestsResponse defines the response for listing join requests
+type GetJoinRequestsResponse struct {
+	JoinRequests []GroupJoinRequest `json:"join_requests"`
+	Count        int                `json:"count"`
+}
+
+// ReviewJoinRequestResponse defines the response for approving or denying a join request
+type ReviewJoinRequestResponse struct {
+	JoinRequest *GroupJoinRequest `json:"join_request"`
+	Group       *UniversalGroup   `json:"group"`
+}
+
+// CancelJoinRequestResponse defines the response for withdrawing a join request
+type CancelJoinRequestResponse struct {
+	JoinRequest *GroupJoinRequest `json:"join_request"`
+}
+
+// GetGroupActivityResponse defines the response for listing a group's activity feed
+type GetGroupActivityResponse struct {
+	Activities []GroupActivity `json:"activities"`
+
+	// NextCursor fetches the next page of older activity, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ImportMembersResponse defines the response for importing members in bulk. Report is
+// always populated, Import is only populated when the import was started
+type ImportMembersResponse struct {
+	Report *MemberImportReport `json:"report"`
+	Import *MemberImportJob    `json:"import,omitempty"`
+}
+
+// GetMemberImportResponse defines the response for checking the progress of a member import
+type GetMemberImportResponse struct {
+	Import *MemberImportJob `json:"import"`
+}
+
+// ResumeStaleMemberImportsResponse defines the response for resuming abandoned member imports
+type ResumeStaleMemberImportsResponse struct {
+	ImportIDs []string `json:"import_ids"`
+}
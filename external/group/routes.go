@@ -24,12 +24,18 @@ type GroupHandler interface {
 	UninviteUser(w http.ResponseWriter, r *http.Request)
 	AcceptInvite(w http.ResponseWriter, r *http.Request)
 	RejectInvite(w http.ResponseWriter, r *http.Request)
+	ResendInvite(w http.ResponseWriter, r *http.Request)
+	ExpireStaleInvites(w http.ResponseWriter, r *http.Request)
 	RemoveMember(w http.ResponseWriter, r *http.Request)
 	UpdateMemberRole(w http.ResponseWriter, r *http.Request)
 	GetGroupMembers(w http.ResponseWriter, r *http.Request)
 	UpdateOwner(w http.ResponseWriter, r *http.Request)
 	RepairInvalidMembers(w http.ResponseWriter, r *http.Request)
 	ArchiveGroup(w http.ResponseWriter, r *http.Request)
+	MoveGroup(w http.ResponseWriter, r *http.Request)
+	GetGroupRoles(w http.ResponseWriter, r *http.Request)
+	SetGroupRole(w http.ResponseWriter, r *http.Request)
+	DeleteGroupRole(w http.ResponseWriter, r *http.Request)
 	RestoreGroup(w http.ResponseWriter, r *http.Request)
 	GetGroupStats(w http.ResponseWriter, r *http.Request)
 	GetGroupsStats(w http.ResponseWriter, r *http.Request)
@@ -72,6 +78,7 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	groupsAdminOnlyRoutes.HandleFunc("/configs", request.Handler.GetGroupsConfig).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/validate-name", request.Handler.ValidateGroupName).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/repairs/members", request.Handler.RepairInvalidMembers).Methods(http.MethodPost, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/invitations/expire", request.Handler.ExpireStaleInvites).Methods(http.MethodPost, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/invitations/{memberID}", request.Handler.GetGroupsAwaitingAnswerForInvitationsByMemberID).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}", request.Handler.GetGroupByID).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/lineage", request.Handler.GetGroupLineage).Methods(http.MethodGet, http.MethodOptions)
@@ -83,6 +90,7 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	// Group status operations
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/archive", request.Handler.ArchiveGroup).Methods(http.MethodPost, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/restore", request.Handler.RestoreGroup).Methods(http.MethodPost, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/move", request.Handler.MoveGroup).Methods(http.MethodPost, http.MethodOptions)
 
 	// Member management
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/members", request.Handler.GetGroupMembers).Methods(http.MethodGet, http.MethodOptions)
@@ -91,9 +99,15 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/invitations", request.Handler.UninviteUser).Methods(http.MethodDelete, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/invitations/accept", request.Handler.AcceptInvite).Methods(http.MethodPost, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/invitations/reject", request.Handler.RejectInvite).Methods(http.MethodPost, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/invitations/resend", request.Handler.ResendInvite).Methods(http.MethodPost, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/members/{memberID}", request.Handler.RemoveMember).Methods(http.MethodDelete, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/members/{memberID}/role", request.Handler.UpdateMemberRole).Methods(http.MethodPut, http.MethodOptions)
 
+	// Custom roles
+	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/roles", request.Handler.GetGroupRoles).Methods(http.MethodGet, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/roles/{roleName}", request.Handler.SetGroupRole).Methods(http.MethodPut, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/roles/{roleName}", request.Handler.DeleteGroupRole).Methods(http.MethodDelete, http.MethodOptions)
+
 	// Ownership management
 	groupsAdminOnlyRoutes.HandleFunc("/{groupID}/owner", request.Handler.UpdateOwner).Methods(http.MethodPut, http.MethodOptions)
 
@@ -202,6 +202,16 @@ func (m *mockGroupHandler) UninviteUser(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
+func (m *mockGroupHandler) ResendInvite(w http.ResponseWriter, r *http.Request) {
+	*m.callTracker = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockGroupHandler) ExpireStaleInvites(w http.ResponseWriter, r *http.Request) {
+	*m.callTracker = true
+	w.WriteHeader(http.StatusOK)
+}
+
 func (m *mockGroupHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
 	*m.callTracker = true
 	w.WriteHeader(http.StatusOK)
@@ -242,6 +252,26 @@ func (m *mockGroupHandler) ArchiveGroup(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
+func (m *mockGroupHandler) MoveGroup(w http.ResponseWriter, r *http.Request) {
+	*m.callTracker = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockGroupHandler) GetGroupRoles(w http.ResponseWriter, r *http.Request) {
+	*m.callTracker = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockGroupHandler) SetGroupRole(w http.ResponseWriter, r *http.Request) {
+	*m.callTracker = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockGroupHandler) DeleteGroupRole(w http.ResponseWriter, r *http.Request) {
+	*m.callTracker = true
+	w.WriteHeader(http.StatusOK)
+}
+
 func (m *mockGroupHandler) RestoreGroup(w http.ResponseWriter, r *http.Request) {
 	*m.callTracker = true
 	w.WriteHeader(http.StatusOK)
@@ -0,0 +1,376 @@
+package group
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultGroupActivityLimit is the page size used when a request does not set one
+	defaultGroupActivityLimit = 50
+
+	// maxGroupActivityLimit caps the page size of the activity feed
+	maxGroupActivityLimit = 100
+
+	// groupActivityCursorSeparator separates the action time and ID held in a cursor
+	groupActivityCursorSeparator = "|"
+)
+
+// activityActorDetailKeys are the details that recorded the acting user before audit
+// events carried an actor, they are used for older events without one
+var activityActorDetailKeys = []string{"invited_by_id", "resent_by_id", "rejected_by_id", "deleted_by_id", "moved_by_id"}
+
+// GetGroupActivity lists the audit events recorded against a group, and optionally its
+// descendants, newest first. Older pages are fetched with the NextCursor of the previous page.
+func (s *Service) GetGroupActivity(ctx context.Context, req *GetGroupActivityRequest) (*GetGroupActivityResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "get-group-activity")
+	logger.Debug("getting-group-activity", zap.String("group-id", req.GroupID))
+
+	if s.AuditLogReader == nil {
+		logger.Error("audit-log-reader-not-enabled", zap.String("group-id", req.GroupID))
+		return nil, ErrActivityFeedNotEnabled
+	}
+
+	from, to, err := normaliseActivityTimeRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeActionAt, beforeID, err := decodeActivityCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+
+	groupNames := map[string]string{group.ID: group.Name}
+	if req.IncludeDescendants {
+		descendants, descendantsErr := s.GroupRepository.GetGroupsByLineageAncestor(ctx, group.ID)
+		if descendantsErr != nil {
+			logger.Error("failed-to-get-group-descendants", zap.Error(descendantsErr), zap.String("group-id", group.ID))
+			return nil, ErrDatabaseError
+		}
+
+		for _, descendant := range descendants {
+			groupNames[descendant.ID] = descendant.Name
+		}
+	}
+
+	targetIDs := make([]string, 0, len(groupNames))
+	for groupID := range groupNames {
+		targetIDs = append(targetIDs, groupID)
+	}
+	sort.Strings(targetIDs)
+
+	actions := []audit.AuditAction{}
+	for _, action := range req.Actions {
+		if trimmedAction := strings.TrimSpace(action); trimmedAction != "" {
+			actions = append(actions, audit.AuditAction(trimmedAction))
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultGroupActivityLimit
+	}
+	if limit > maxGroupActivityLimit {
+		limit = maxGroupActivityLimit
+	}
+
+	// one extra entry tells us whether there is another page
+	eventsResp, err := s.AuditLogReader.GetAuditLogEvents(ctx, &audit.GetAuditLogEventsRequest{
+		Domains:        []string{"group"},
+		TargetIds:      targetIDs,
+		ActorId:        strings.TrimSpace(req.ActorID),
+		Actions:        actions,
+		From:           from,
+		To:             to,
+		BeforeActionAt: beforeActionAt,
+		BeforeId:       beforeID,
+		Limit:          limit + 1,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-group-audit-events", zap.Error(err), zap.String("group-id", group.ID))
+		return nil, ErrDatabaseError
+	}
+
+	entries := eventsResp.Entries
+	nextCursor := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		nextCursor = encodeActivityCursor(entries[limit-1].ActionAt, entries[limit-1].Id)
+	}
+
+	activities := make([]GroupActivity, 0, len(entries))
+	for _, entry := range entries {
+		activity := newGroupActivity(entry)
+		activity.GroupName = groupNames[activity.GroupID]
+		activity.Summary = DescribeGroupActivity(&activity, nil)
+		activities = append(activities, activity)
+	}
+
+	return &GetGroupActivityResponse{Activities: activities, NextCursor: nextCursor}, nil
+}
+
+// DescribeGroupActivity renders a human-readable summary of the activity. Users and groups
+// are referred to by their display names when present, otherwise by their IDs.
+func DescribeGroupActivity(activity *GroupActivity, displayNames map[string]string) string {
+	nameOf := func(id, fallback string) string {
+		if id == "" {
+			return fallback
+		}
+		if displayName := strings.TrimSpace(displayNames[id]); displayName != "" {
+			return displayName
+		}
+		return id
+	}
+
+	actor := nameOf(activity.ActorID, "Someone")
+	if activity.ActorID == audit.AuditActorIdSystem {
+		actor = "The system"
+	}
+	subject := nameOf(activity.SubjectID, "a member")
+	groupName := activity.GroupName
+	if groupName == "" {
+		groupName = activity.GroupID
+	}
+	role := activityRole(activity.Details)
+
+	switch activity.Action {
+	case "group.created":
+		return fmt.Sprintf("%s created %s", actor, groupName)
+	case "group.updated":
+		return fmt.Sprintf("%s updated %s", actor, groupName)
+	case "group.soft_deleted", "group.hard_deleted":
+		return fmt.Sprintf("%s deleted %s", actor, groupName)
+	case "group.archived":
+		return fmt.Sprintf("%s archived %s", actor, groupName)
+	case "group.restored":
+		return fmt.Sprintf("%s restored %s", actor, groupName)
+	case "group.moved":
+		return fmt.Sprintf("%s moved %s", actor, groupName)
+	case "group.member.added":
+		if role != "" {
+			return fmt.Sprintf("%s added %s to %s as %s", actor, subject, groupName, role)
+		}
+		return fmt.Sprintf("%s added %s to %s", actor, subject, groupName)
+	case "group.member.removed":
+		return fmt.Sprintf("%s removed %s from %s", actor, subject, groupName)
+	case "group.member.removed.cascade":
+		return fmt.Sprintf("%s removed %s from the subgroups of %s", actor, subject, groupName)
+	case "group.member.role_updated":
+		if role != "" {
+			return fmt.Sprintf("%s changed the role of %s in %s to %s", actor, subject, groupName, role)
+		}
+		return fmt.Sprintf("%s changed the role of %s in %s", actor, subject, groupName)
+	case "group.member.invited":
+		return fmt.Sprintf("%s invited %s to %s", actor, subject, groupName)
+	case "group.member.uninvited":
+		return fmt.Sprintf("%s withdrew the invitation for %s to %s", actor, subject, groupName)
+	case "group.member.invite.resent":
+		return fmt.Sprintf("%s resent the invitation for %s to %s", actor, subject, groupName)
+	case "group.member.invite.accepted":
+		return fmt.Sprintf("%s accepted the invitation to %s", subject, groupName)
+	case "group.member.invite.rejected":
+		return fmt.Sprintf("%s declined the invitation to %s", subject, groupName)
+	case "group.member.invite.expired":
+		return fmt.Sprintf("The invitation for %s to %s expired", subject, groupName)
+	case "group.owner.updated":
+		if activity.SubjectID == "" {
+			return fmt.Sprintf("%s removed the owner of %s", actor, groupName)
+		}
+		return fmt.Sprintf("%s made %s the owner of %s", actor, subject, groupName)
+	case "group.join_request.created":
+		return fmt.Sprintf("%s asked to join %s", subject, groupName)
+	case "group.join_request.approved":
+		return fmt.Sprintf("%s approved the request from %s to join %s", actor, subject, groupName)
+	case "group.join_request.denied":
+		return fmt.Sprintf("%s denied the request from %s to join %s", actor, subject, groupName)
+	case "group.join_request.cancelled":
+		return fmt.Sprintf("%s withdrew their request to join %s", subject, groupName)
+	case "group.role.created":
+		return fmt.Sprintf("%s created the %s role in %s", actor, activityRoleName(activity.Details), groupName)
+	case "group.role.updated":
+		return fmt.Sprintf("%s updated the %s role in %s", actor, activityRoleName(activity.Details), groupName)
+	case "group.role.deleted":
+		return fmt.Sprintf("%s deleted the %s role in %s", actor, activityRoleName(activity.Details), groupName)
+	case "group.auto_join_enabled":
+		return fmt.Sprintf("%s enabled auto-join for %s", actor, groupName)
+	case "group.auto_join_disabled":
+		return fmt.Sprintf("%s disabled auto-join for %s", actor, groupName)
+	case "group.auto_invite_enabled":
+		return fmt.Sprintf("%s enabled auto-invite for %s", actor, groupName)
+	case "group.auto_invite_disabled":
+		return fmt.Sprintf("%s disabled auto-invite for %s", actor, groupName)
+	default:
+		return fmt.Sprintf("%s performed %s on %s", actor, activity.Action, groupName)
+	}
+}
+
+// newGroupActivity maps an audit log entry to a group activity without its summary
+func newGroupActivity(entry audit.AuditLogEntry) GroupActivity {
+	details := activityDetails(entry.Details)
+
+	actorID := entry.ActorId
+	for _, key := range activityActorDetailKeys {
+		if actorID != "" {
+			break
+		}
+		actorID = activityDetailString(details, key)
+	}
+
+	return GroupActivity{
+		ID:         entry.Id,
+		Action:     string(entry.Action),
+		GroupID:    entry.TargetId,
+		ActorID:    actorID,
+		SubjectID:  activitySubjectID(details),
+		OccurredAt: entry.ActionAt,
+		Details:    details,
+	}
+}
+
+// activityDetails converts audit event details, which are documents of any shape once
+// read back from the audit store, into a map
+func activityDetails(details interface{}) map[string]interface{} {
+	if details == nil {
+		return nil
+	}
+
+	raw, err := bson.Marshal(details)
+	if err != nil {
+		return nil
+	}
+
+	decoder := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(raw)))
+	decoder.DefaultDocumentMap()
+
+	result := map[string]interface{}{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// activitySubjectID returns the member, invitee or requester the activity is about
+func activitySubjectID(details map[string]interface{}) string {
+	if member, ok := details["member"].(map[string]interface{}); ok {
+		if memberID := activityDetailString(member, "id"); memberID != "" {
+			return memberID
+		}
+	}
+
+	if joinRequest, ok := details["join_request"].(map[string]interface{}); ok {
+		if userID := activityDetailString(joinRequest, "user_id"); userID != "" {
+			return userID
+		}
+	}
+
+	for _, key := range []string{"member_id", "user_id", "owner_id", "invite_email"} {
+		if value := activityDetailString(details, key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// activityRole returns the member role recorded against the activity, if any
+func activityRole(details map[string]interface{}) string {
+	if member, ok := details["member"].(map[string]interface{}); ok {
+		if role := activityDetailString(member, "role"); role != "" {
+			return role
+		}
+	}
+
+	if joinRequest, ok := details["join_request"].(map[string]interface{}); ok {
+		if role := activityDetailString(joinRequest, "role"); role != "" {
+			return role
+		}
+	}
+
+	return activityDetailString(details, "target_member_role")
+}
+
+// activityRoleName returns the custom role name recorded against role activity
+func activityRoleName(details map[string]interface{}) string {
+	if role, ok := details["role"].(map[string]interface{}); ok {
+		if name := activityDetailString(role, "name"); name != "" {
+			return name
+		}
+	}
+
+	return activityDetailString(details, "role_name")
+}
+
+// activityDetailString returns the string value held under the key, empty otherwise
+func activityDetailString(details map[string]interface{}, key string) string {
+	value, _ := details[key].(string)
+	return strings.TrimSpace(value)
+}
+
+// normaliseActivityTimeRange converts RFC 3339 bounds to the format audit events are stored in
+func normaliseActivityTimeRange(from, to string) (string, string, error) {
+	var fromTime, toTime time.Time
+	var err error
+
+	if from != "" {
+		if fromTime, err = time.Parse(time.RFC3339, from); err != nil {
+			return "", "", ErrInvalidActivityTimeRange
+		}
+		from = fromTime.UTC().Format(common.RFC3339NanoUTC)
+	}
+
+	if to != "" {
+		if toTime, err = time.Parse(time.RFC3339, to); err != nil {
+			return "", "", ErrInvalidActivityTimeRange
+		}
+		to = toTime.UTC().Format(common.RFC3339NanoUTC)
+	}
+
+	if from != "" && to != "" && !fromTime.Before(toTime) {
+		return "", "", ErrInvalidActivityTimeRange
+	}
+
+	return from, to, nil
+}
+
+// encodeActivityCursor returns an opaque cursor continuing after the entry
+func encodeActivityCursor(actionAt, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(actionAt + groupActivityCursorSeparator + id))
+}
+
+// decodeActivityCursor returns the action time and ID held in a cursor
+func decodeActivityCursor(cursor string) (string, string, error) {
+	if cursor == "" {
+		return "", "", nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalidActivityCursor
+	}
+
+	actionAt, id, found := strings.Cut(string(decoded), groupActivityCursorSeparator)
+	if !found || actionAt == "" || id == "" {
+		return "", "", ErrInvalidActivityCursor
+	}
+
+	return actionAt, id, nil
+}
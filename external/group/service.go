@@ -12,9 +12,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/ooaklee/ghatd/external/audit"
 	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/emailmanager"
 	"github.com/ooaklee/ghatd/external/logger"
 	"github.com/ooaklee/ghatd/external/toolbox"
 	"go.uber.org/zap"
@@ -26,6 +28,12 @@ type AuditService interface {
 	LogAuditEvent(ctx context.Context, r *audit.LogAuditEventRequest) error
 }
 
+// AuditLogReader defines the interface for reading back audit events,
+// it backs the group activity feed
+type AuditLogReader interface {
+	GetAuditLogEvents(ctx context.Context, r *audit.GetAuditLogEventsRequest) (*audit.GetAuditLogEventsResponse, error)
+}
+
 // GroupRepository defines the interface for group data persistence.
 // Implementations handle storage and retrieval of group data with support
 // for filtering, pagination, and complex queries.
@@ -36,6 +44,7 @@ type GroupRepository interface {
 	GetGroupByName(ctx context.Context, name, groupType string, logError bool) (*UniversalGroup, error)
 	GetGroupByNameAndParent(ctx context.Context, name, parentGroupID string, logError bool) (*UniversalGroup, error)
 	GetGroupsByLineageAncestor(ctx context.Context, ancestorGroupID string) ([]UniversalGroup, error)
+	MoveGroupSubtree(ctx context.Context, groupID, parentGroupID string, lineage []string, updatedAt string) error
 	UpdateGroup(ctx context.Context, group *UniversalGroup) (*UniversalGroup, error)
 	DeleteGroupByID(ctx context.Context, id string) error
 	SoftDeleteGroup(ctx context.Context, id, deletedByID string, deletedAt string) error
@@ -47,17 +56,58 @@ type GroupRepository interface {
 	GetGroupsAwaitingAnswerForInvitationsByMemberID(ctx context.Context, memberID string) ([]UniversalGroup, error)
 	GetGroupsByMemberID(ctx context.Context, memberID string, memberType string, page, pageSize int) ([]UniversalGroup, error)
 	GetGroupsByLeaderID(ctx context.Context, leaderID string, page, pageSize int) ([]UniversalGroup, error)
+	GetGroupMembers(ctx context.Context, req *GetGroupMembersRequest) ([]Member, int64, error)
 	SearchGroupsByExtension(ctx context.Context, key string, value interface{}, page, pageSize int) ([]UniversalGroup, error)
 	HasGroupDependents(ctx context.Context, groupID string) (bool, error)
 	AddMemberToGroup(ctx context.Context, groupID string, member Member) error
 	RemoveMemberFromGroup(ctx context.Context, groupID, memberID string) error
 	ClearOwnerFromGroup(ctx context.Context, groupID, ownerID string) error
 	GetGroupIDsWithInvalidMembers(ctx context.Context) ([]string, error)
+	GetGroupsWithPendingInvitations(ctx context.Context) ([]UniversalGroup, error)
 	RepairInvalidMembers(ctx context.Context) error
 	BulkUpdateGroupsStatus(ctx context.Context, groupIDs []string, status string) error
 	GetGroupsStatsCounts(ctx context.Context) (*AllGroupsStats, error)
 }
 
+// JoinRequestRepository defines the interface for join request persistence.
+// It is optional, join requests are disabled when it is not set.
+type JoinRequestRepository interface {
+	CreateJoinRequest(ctx context.Context, joinRequest *GroupJoinRequest) (*GroupJoinRequest, error)
+	GetJoinRequestByID(ctx context.Context, id string) (*GroupJoinRequest, error)
+	GetJoinRequests(ctx context.Context, req *GetJoinRequestsRequest) ([]GroupJoinRequest, error)
+	UpdateJoinRequest(ctx context.Context, joinRequest *GroupJoinRequest) (*GroupJoinRequest, error)
+}
+
+// MemberImportRepository defines the interface for member import persistence.
+// It is optional, bulk member imports are disabled when it is not set.
+type MemberImportRepository interface {
+	CreateMemberImport(ctx context.Context, memberImport *MemberImportJob) (*MemberImportJob, error)
+	GetMemberImportByID(ctx context.Context, id string) (*MemberImportJob, error)
+	UpdateMemberImport(ctx context.Context, memberImport *MemberImportJob) (*MemberImportJob, error)
+	ClaimMemberImport(ctx context.Context, id, claimedBy, staleBefore, claimedAt string) (*MemberImportJob, error)
+	HeartbeatMemberImport(ctx context.Context, id, claimedBy, heartbeatAt string) error
+	GetStaleMemberImports(ctx context.Context, staleBefore string) ([]MemberImportJob, error)
+}
+
+// MemberImportUserFinder finds the account registered to an email, so imported people
+// who already have an account are added rather than invited (optional). It returns an
+// empty ID when no account uses the email
+type MemberImportUserFinder interface {
+	FindUserIDByEmail(ctx context.Context, email string) (string, error)
+}
+
+// EmailManager defines the email operations used to deliver invitations (optional)
+type EmailManager interface {
+	SendCustomEmail(ctx context.Context, req *emailmanager.SendCustomEmailRequest) error
+}
+
+// IntegrationNotifier delivers group events to the group's external integrations,
+// such as Slack (optional). The group it is given is a copy holding the group's identity
+// and integrations, without its members
+type IntegrationNotifier interface {
+	NotifyGroupEvent(ctx context.Context, group *UniversalGroup, event *IntegrationEvent) error
+}
+
 // Service manages group business logic and orchestrates group operations.
 //
 // The service handles validation, audit logging, and coordination between
@@ -69,6 +119,31 @@ type Service struct {
 	IDGenerator     IDGenerator
 	TimeProvider    TimeProvider
 	StringUtils     StringUtils
+
+	// JoinRequestRepository enables the join request workflow when set
+	JoinRequestRepository JoinRequestRepository
+
+	// InvitationConfig enables signed, expiring invitation links when set
+	InvitationConfig *InvitationConfig
+
+	// EmailManager delivers invitation emails when set alongside InvitationConfig
+	EmailManager EmailManager
+
+	// AuditLogReader enables the group activity feed when set
+	AuditLogReader AuditLogReader
+
+	// IntegrationNotifier delivers membership events to the group's integrations when set
+	IntegrationNotifier IntegrationNotifier
+
+	integrationWorkersOnce sync.Once
+	integrationDeliveries  chan *integrationDelivery
+
+	// MemberImportRepository enables bulk member imports when set
+	MemberImportRepository MemberImportRepository
+
+	// MemberImportUserFinder lets bulk member imports add people with accounts when set,
+	// otherwise everyone imported is invited
+	MemberImportUserFinder MemberImportUserFinder
 }
 
 // NewService creates a new group service
@@ -98,6 +173,14 @@ func NewService(
 		config.TypeToRoleOverrides = map[string][]string{}
 	}
 
+	if config.RolePermissions == nil {
+		config.RolePermissions = DefaultRolePermissions()
+	}
+
+	if err := config.ValidatePermissions(); err != nil {
+		return nil, ErrInvalidRolePermissions
+	}
+
 	return &Service{
 		GroupRepository: groupRepository,
 		AuditService:    auditService,
@@ -108,6 +191,45 @@ func NewService(
 	}, nil
 }
 
+// WithJoinRequestRepository enables users to request to join groups
+func (s *Service) WithJoinRequestRepository(joinRequestRepository JoinRequestRepository) *Service {
+	s.JoinRequestRepository = joinRequestRepository
+	return s
+}
+
+// WithInvitations enables signed invitation links that expire after the configured expiry
+func (s *Service) WithInvitations(config *InvitationConfig) *Service {
+	s.InvitationConfig = config
+	return s
+}
+
+// WithEmailManager enables invitation emails to be sent to invitees
+func (s *Service) WithEmailManager(emailManager EmailManager) *Service {
+	s.EmailManager = emailManager
+	return s
+}
+
+// WithAuditLogReader enables the group activity feed built from the group's audit events
+func (s *Service) WithAuditLogReader(auditLogReader AuditLogReader) *Service {
+	s.AuditLogReader = auditLogReader
+	return s
+}
+
+// WithIntegrationNotifier enables membership events to be delivered to the group's integrations.
+// Events are delivered in the background by a small pool of workers
+func (s *Service) WithIntegrationNotifier(integrationNotifier IntegrationNotifier) *Service {
+	s.IntegrationNotifier = integrationNotifier
+	return s
+}
+
+// WithMemberImports enables members to be imported in bulk. The user finder may be nil,
+// in which case everyone imported is invited by email
+func (s *Service) WithMemberImports(memberImportRepository MemberImportRepository, userFinder MemberImportUserFinder) *Service {
+	s.MemberImportRepository = memberImportRepository
+	s.MemberImportUserFinder = userFinder
+	return s
+}
+
 // validateHierarchyTreeConfig validates the configured group hierarchy tree
 // when hierarchy rules are explicitly defined on the service config.
 // It returns ErrKeyInvalidGroupHierarchyTree when validation fails.
@@ -246,6 +368,7 @@ func (s *Service) CreateGroup(ctx context.Context, req *CreateGroupRequest) (*Cr
 	// Audit log
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.created",
@@ -772,6 +895,7 @@ func (s *Service) UpdateGroup(ctx context.Context, req *UpdateGroupRequest) (*Up
 	// Audit log
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.updated",
@@ -841,6 +965,7 @@ func (s *Service) DeleteGroup(ctx context.Context, req *DeleteGroupRequest) (*De
 
 		if s.AuditService != nil {
 			s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+				ActorId:    req.DeletedByID,
 				TargetType: "group",
 				Domain:     "group",
 				Action: func() audit.AuditAction {
@@ -1069,8 +1194,9 @@ func (s *Service) getPrefixName(ctx context.Context, g *UniversalGroup, prefixNa
 
 // GetUserGroupAccessMap builds an effective group access map for the provided user.
 //
-// Admin privileges propagate from any directly-administered group down to all
-// descendants of that group.
+// Permissions propagate from the user's groups down to all descendants of those
+// groups as configured by GroupConfig.PermissionInheritance. By default only roles
+// granting PermissionMembersManage, i.e. admin roles, propagate.
 func (s *Service) GetUserGroupAccessMap(ctx context.Context, userID string) (map[string]UserGroupAccessSummary, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/group").With(zap.String("operation", "get-user-group-access-map"))
 	trimmedUserID := strings.TrimSpace(userID)
@@ -1094,7 +1220,7 @@ func (s *Service) GetUserGroupAccessMap(ctx context.Context, userID string) (map
 	}
 
 	accessByGroupID := map[string]UserGroupAccessSummary{}
-	adminSourceGroupIDs := map[string]struct{}{}
+	inheritedPermissionsBySourceGroupID := map[string]map[string]struct{}{}
 
 	for _, grp := range userGroupsResp.Groups {
 		if grp == nil || strings.TrimSpace(grp.ID) == "" {
@@ -1102,28 +1228,31 @@ func (s *Service) GetUserGroupAccessMap(ctx context.Context, userID string) (map
 		}
 
 		effectiveRole, isAdmin := s.resolveUserRoleForGroup(grp, trimmedUserID)
+		directPermissions, inheritedPermissions := s.resolveUserPermissionsForGroup(grp, trimmedUserID)
 		accessByGroupID[grp.ID] = UserGroupAccessSummary{
 			MaxRole:      effectiveRole,
 			IsAccessible: true,
 			IsAdmin:      isAdmin,
+			Permissions:  mergePermissions(nil, directPermissions),
 		}
 
-		if isAdmin {
-			adminSourceGroupIDs[grp.ID] = struct{}{}
+		if len(inheritedPermissions) > 0 {
+			inheritedPermissionsBySourceGroupID[grp.ID] = inheritedPermissions
 		}
 	}
 
-	for adminGroupID := range adminSourceGroupIDs {
-		descendants, descendantsErr := s.GroupRepository.GetGroupsByLineageAncestor(ctx, adminGroupID)
+	for sourceGroupID, inheritedPermissions := range inheritedPermissionsBySourceGroupID {
+		descendants, descendantsErr := s.GroupRepository.GetGroupsByLineageAncestor(ctx, sourceGroupID)
 		if descendantsErr != nil {
 			logger.Warn(
-				"failed-to-get-admin-group-descendants",
-				zap.String("admin-group-id", adminGroupID),
+				"failed-to-get-permission-source-group-descendants",
+				zap.String("source-group-id", sourceGroupID),
 				zap.Error(descendantsErr),
 			)
 			continue
 		}
 
+		_, inheritsAdmin := inheritedPermissions[PermissionMembersManage]
 		for i := range descendants {
 			descendant := descendants[i]
 			if strings.TrimSpace(descendant.ID) == "" {
@@ -1131,9 +1260,12 @@ func (s *Service) GetUserGroupAccessMap(ctx context.Context, userID string) (map
 			}
 
 			existing := accessByGroupID[descendant.ID]
-			existing.MaxRole = s.pickHigherRole(existing.MaxRole, MemberRoleAdmin)
+			if inheritsAdmin {
+				existing.MaxRole = s.pickHigherRole(existing.MaxRole, MemberRoleAdmin)
+				existing.IsAdmin = true
+			}
 			existing.IsAccessible = true
-			existing.IsAdmin = true
+			existing.Permissions = mergePermissions(existing.Permissions, inheritedPermissions)
 			accessByGroupID[descendant.ID] = existing
 		}
 	}
@@ -1157,7 +1289,7 @@ func (s *Service) AddMember(ctx context.Context, req *AddMemberRequest) (*AddMem
 	group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
 
 	// Validate role against group type configuration
-	if err := s.isValidMemberRole(group.Type, req.Role); err != nil {
+	if err := s.isValidMemberRole(group, req.Role); err != nil {
 		logger.Error("invalid-member-role", zap.Error(err), zap.String("role", req.Role), zap.String("group-type", group.Type))
 		return nil, err
 	}
@@ -1188,6 +1320,7 @@ func (s *Service) AddMember(ctx context.Context, req *AddMemberRequest) (*AddMem
 	// Audit log
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.member.added",
@@ -1198,6 +1331,12 @@ func (s *Service) AddMember(ctx context.Context, req *AddMemberRequest) (*AddMem
 		})
 	}
 
+	s.notifyIntegrations(ctx, group, &IntegrationEvent{
+		Type:    IntegrationEventMemberJoined,
+		ActorID: req.ActorID,
+		Member:  addedMember,
+	})
+
 	return &AddMemberResponse{
 		Group: updatedGroup,
 	}, nil
@@ -1225,7 +1364,7 @@ func (s *Service) InviteUser(ctx context.Context, req *InviteUserRequest) (*Invi
 	}
 
 	if req.Role != "" {
-		if roleErr := s.isValidMemberRole(targetGroup.Type, req.Role); roleErr != nil {
+		if roleErr := s.isValidMemberRole(targetGroup, req.Role); roleErr != nil {
 			return nil, roleErr
 		}
 	}
@@ -1239,14 +1378,36 @@ func (s *Service) InviteUser(ctx context.Context, req *InviteUserRequest) (*Invi
 		return nil, ErrInvitationAlreadyExists
 	}
 
+	var invitation *issuedInvitation
+	if s.invitationsEnabled() {
+		invitation, err = s.issueInvitation(targetGroup, inviteEmail)
+		if err != nil {
+			logger.Error("failed-to-issue-invitation-token", zap.Error(err), zap.String("group-id", req.GroupID))
+			return nil, err
+		}
+	}
+
 	updatedTargetGroup, err := s.GroupRepository.UpdateGroup(ctx, targetGroup)
 	if err != nil {
 		logger.Error("failed-to-persist-target-group-invite", zap.Error(err), zap.String("group-id", req.GroupID))
 		return nil, ErrDatabaseError
 	}
 
+	response := &InviteUserResponse{Group: updatedTargetGroup, InviteEmail: inviteEmail}
+	if invitation != nil {
+		response.ExpiresAt = invitation.ExpiresAt
+
+		// the invite is kept when delivery fails so it can be resent
+		if sendErr := s.sendInvitationEmail(ctx, updatedTargetGroup, inviteEmail, invitation); sendErr != nil {
+			logger.Warn("failed-to-send-invitation-email", append([]zap.Field{zap.Error(sendErr), zap.String("group-id", req.GroupID)}, emailLogFields("invite-email", inviteEmail)...)...)
+		} else {
+			response.EmailSent = s.EmailManager != nil
+		}
+	}
+
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.InvitedByID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.member.invited",
@@ -1258,11 +1419,12 @@ func (s *Service) InviteUser(ctx context.Context, req *InviteUserRequest) (*Invi
 				"root_group_id":        req.GroupID,
 				"target_member_role":   req.Role,
 				"invitation_lifecycle": "sent",
+				"email_sent":           response.EmailSent,
 			},
 		})
 	}
 
-	return &InviteUserResponse{Group: updatedTargetGroup, InviteEmail: inviteEmail}, nil
+	return response, nil
 }
 
 // UninviteUser removes a pending invitation from a top-level group.
@@ -1298,6 +1460,7 @@ func (s *Service) UninviteUser(ctx context.Context, req *UninviteUserRequest) (*
 
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.UninvitedByID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.member.uninvited",
@@ -1355,6 +1518,7 @@ func (s *Service) AcceptInvite(ctx context.Context, req *AcceptInviteRequest) (*
 
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    userID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.member.invite.accepted",
@@ -1368,6 +1532,8 @@ func (s *Service) AcceptInvite(ctx context.Context, req *AcceptInviteRequest) (*
 		})
 	}
 
+	s.notifyInviteAccepted(ctx, targetGroup, inviteEmail, userID)
+
 	return &AcceptInviteResponse{Group: updatedTargetGroup, InviteEmail: inviteEmail, UserID: userID}, nil
 }
 
@@ -1404,6 +1570,7 @@ func (s *Service) RejectInvite(ctx context.Context, req *RejectInviteRequest) (*
 
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.RejectedByID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.member.invite.rejected",
@@ -1581,6 +1748,7 @@ func (s *Service) RemoveMember(ctx context.Context, req *RemoveMemberRequest) (*
 			}
 
 			s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+				ActorId:    req.ActorID,
 				TargetType: "group",
 				Domain:     "group",
 				Action:     "group.member.removed.cascade",
@@ -1594,6 +1762,7 @@ func (s *Service) RemoveMember(ctx context.Context, req *RemoveMemberRequest) (*
 		}
 
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.member.removed",
@@ -1622,12 +1791,21 @@ func pendingInviteEmail(member Member) string {
 }
 
 // isPendingInviteMember reports whether a member record represents an active invite.
-// Legacy records with invited_at but missing invitation_state are also treated as pending.
+// Legacy records with invited_at but missing invitation_state are also treated as pending,
+// invites marked as expired are not.
 func isPendingInviteMember(member Member) bool {
 	state := strings.ToUpper(strings.TrimSpace(member.InvitationState))
+	if state == MemberInvitationStateExpired {
+		return false
+	}
 	return state == MemberInvitationStateInvited || strings.TrimSpace(member.InvitedAt) != ""
 }
 
+// isExpiredInviteMember reports whether a member record is an invite marked as expired.
+func isExpiredInviteMember(member Member) bool {
+	return strings.ToUpper(strings.TrimSpace(member.InvitationState)) == MemberInvitationStateExpired
+}
+
 // findPendingInviteIndexByEmail returns the index of a pending invite for the email.
 // It returns -1 when the group is nil, the email is empty, or no invite exists.
 func findPendingInviteIndexByEmail(group *UniversalGroup, inviteEmail string) int {
@@ -1663,6 +1841,18 @@ func hasPendingInviteByEmail(group *UniversalGroup, inviteEmail string) bool {
 	return findPendingInviteIndexByEmail(group, inviteEmail) >= 0
 }
 
+// removeExpiredInviteByEmail removes an expired invite matching the email.
+// It returns true when a member was removed.
+func removeExpiredInviteByEmail(group *UniversalGroup, inviteEmail string) bool {
+	index := findExpiredInviteIndexByEmail(group, inviteEmail)
+	if index < 0 {
+		return false
+	}
+
+	group.Members = append(group.Members[:index], group.Members[index+1:]...)
+	return true
+}
+
 // removePendingInviteByEmail removes the pending invite matching the email.
 // It returns true when a member was removed.
 func removePendingInviteByEmail(group *UniversalGroup, inviteEmail string) bool {
@@ -1687,6 +1877,9 @@ func (s *Service) addPendingInviteMember(group *UniversalGroup, inviteEmail, rol
 		return false, nil
 	}
 
+	// an expired invite for the same email is replaced by the new one
+	removeExpiredInviteByEmail(group, inviteEmail)
+
 	if group.HasMember(inviteEmail) {
 		return false, ErrMemberAlreadyExists
 	}
@@ -1727,6 +1920,10 @@ func (s *Service) acceptInviteInGroup(group *UniversalGroup, inviteEmail, userID
 		return "", false, ErrInvitationNotFound
 	}
 
+	if s.isInviteExpired(group.Members[index]) {
+		return "", false, ErrInvitationExpired
+	}
+
 	memberRole := strings.TrimSpace(group.Members[index].Role)
 	if memberRole == "" {
 		memberRole = MemberRoleMember
@@ -1787,11 +1984,16 @@ func (s *Service) UpdateMemberRole(ctx context.Context, req *UpdateMemberRoleReq
 	req.NewRole = strings.TrimSpace(req.NewRole)
 
 	// Validate new role against group type configuration
-	if err := s.isValidMemberRole(group.Type, req.NewRole); err != nil {
+	if err := s.isValidMemberRole(group, req.NewRole); err != nil {
 		logger.Error("invalid-member-role", zap.Error(err), zap.String("new-role", req.NewRole), zap.String("group-type", group.Type))
 		return nil, err
 	}
 
+	if err := s.checkMemberRoleAssignable(ctx, group, req); err != nil {
+		logger.Warn("member-role-not-assignable-by-actor", zap.String("actor-id", req.ActorID), zap.String("new-role", req.NewRole), zap.Error(err))
+		return nil, err
+	}
+
 	// Update role
 	if group, err = group.UpdateMemberRole(req.MemberID, req.NewRole); err != nil {
 		logger.Error("failed-to-update-member-role", zap.Error(err))
@@ -1810,6 +2012,7 @@ func (s *Service) UpdateMemberRole(ctx context.Context, req *UpdateMemberRoleReq
 	// Audit log
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.member.role_updated",
@@ -1823,6 +2026,37 @@ func (s *Service) UpdateMemberRole(ctx context.Context, req *UpdateMemberRoleReq
 	return &UpdateMemberRoleResponse{Group: updatedGroup}, nil
 }
 
+// checkMemberRoleAssignable ensures the actor may give the member the new role. Ownership
+// is never granted through a role change, the actor cannot promote to or change the role
+// of a member above their own, and the new role cannot grant permissions the actor lacks.
+func (s *Service) checkMemberRoleAssignable(ctx context.Context, group *UniversalGroup, req *UpdateMemberRoleRequest) error {
+	if strings.EqualFold(req.NewRole, MemberRoleOwner) {
+		return ErrInvalidMemberRole
+	}
+
+	if req.ActorIsPlatformAdmin {
+		return nil
+	}
+
+	// actors managing the group through an ancestor hold no role here, their permissions
+	// below still bound what they can grant
+	actorRole, _ := s.resolveUserRoleForGroup(group, req.ActorID)
+	if actorRole != "" {
+		roles := []string{req.NewRole}
+		if member, err := group.GetMemberByID(req.MemberID); err == nil {
+			roles = append(roles, member.Role)
+		}
+
+		for _, role := range roles {
+			if s.pickHigherRole(actorRole, role) != actorRole {
+				return ErrInsufficientPermissions
+			}
+		}
+	}
+
+	return s.checkPermissionsHeld(ctx, group.ID, req.ActorID, s.rolePermissions(group, req.NewRole))
+}
+
 // ensureOwnerMembershipAndAdminRole ensures the owner exists as a USER member of the
 // target group and is assigned the ADMIN role. For nested groups it also ensures the
 // owner exists in the root group to preserve hierarchy membership invariants.
@@ -1958,45 +2192,24 @@ func (s *Service) getRootGroupID(lineage []string) (string, error) {
 	return lineage[0], nil
 }
 
-// resolveUserRoleForGroup resolves a user's strongest direct role for a group.
-// Ownership implies OWNER role and admin privileges.
+// resolveUserRoleForGroup resolves a user's strongest direct role for a group and
+// whether any of their roles grant PermissionMembersManage, i.e. admin privileges.
+// Ownership implies the OWNER role.
 func (s *Service) resolveUserRoleForGroup(group *UniversalGroup, userID string) (string, bool) {
-	if group == nil || strings.TrimSpace(userID) == "" {
+	roles := s.userRolesForGroup(group, userID)
+	if len(roles) == 0 {
 		return "", false
 	}
 
 	maxRole := ""
-	if strings.TrimSpace(group.OwnerID) == userID {
-		maxRole = MemberRoleOwner
-	}
-
-	for i := range group.Members {
-		member := group.Members[i]
-		if member.ID != userID || member.Type != MemberTypeUser {
-			continue
-		}
-
-		role := strings.ToUpper(strings.TrimSpace(member.Role))
-		if role == "" {
-			role = MemberRoleMember
-		}
-
+	for _, role := range roles {
 		maxRole = s.pickHigherRole(maxRole, role)
 	}
 
-	if maxRole == "" {
-		return "", false
-	}
-
-	return maxRole, s.isAdminRole(maxRole)
-}
+	directPermissions, _ := s.resolveUserPermissionsForGroup(group, userID)
+	_, isAdmin := directPermissions[PermissionMembersManage]
 
-// isAdminRole reports whether a role grants admin-level access.
-func (s *Service) isAdminRole(role string) bool {
-	normalisedRole := strings.ToUpper(strings.TrimSpace(role))
-	return normalisedRole == MemberRoleOwner ||
-		normalisedRole == MemberRoleAdmin ||
-		normalisedRole == MemberRoleSuperUser
+	return maxRole, isAdmin
 }
 
 // pickHigherRole returns the higher-privilege role between current and candidate.
@@ -2037,14 +2250,21 @@ func (s *Service) pickHigherRole(currentRole, candidateRole string) string {
 	return normalisedCurrent
 }
 
-// isValidMemberRole validates if a role is allowed for a given group type
+// isValidMemberRole validates if a role is allowed for a given group, either as one of
+// the group's custom roles or a role allowed for its type
 // Returns error with ErrKeyInvalidMemberRole if role is not allowed
-func (s *Service) isValidMemberRole(groupType, role string) error {
+func (s *Service) isValidMemberRole(group *UniversalGroup, role string) error {
 	cfg := s.Config
 	if cfg == nil {
 		cfg = DefaultGroupConfig()
 	}
 
+	if group.GetCustomRole(role) != nil {
+		return nil
+	}
+
+	groupType := group.Type
+
 	// If TypeToRoleOverrides exists for this group type, validate against it
 	if allowedRoles, exists := cfg.TypeToRoleOverrides[groupType]; exists {
 		for _, allowedRole := range allowedRoles {
@@ -2074,39 +2294,34 @@ func (s *Service) GetGroupMembers(ctx context.Context, req *GetGroupMembersReque
 	logger := logger.AcquirePackageFrom(ctx, "external/group").With(zap.String("operation", "get-group-members"))
 	logger.Debug("getting-group-members", zap.String("group-id", req.GroupID))
 
-	// Get group
-	group, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if req.PerPage < 0 || req.Page < 0 {
+		return nil, ErrInvalidQueryParam
+	}
+	if req.PerPage > 0 && req.Page == 0 {
+		req.Page = 1
+	}
+
+	// Members are filtered and paginated by the repository so large groups
+	// can be served from the memberships collection indexes
+	members, total, err := s.GroupRepository.GetGroupMembers(ctx, req)
 	if err != nil {
-		logger.Error("failed-to-get-group", zap.Error(err))
+		logger.Error("failed-to-get-group-members", zap.Error(err))
 		return nil, err
 	}
 
-	// Reinject dependencies
-	group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
-
-	// Filter members
-	var members []Member
-	if req.MemberType != "" {
-		members = group.GetMembersByType(req.MemberType)
-	} else {
-		members = group.Members
+	response := &GetGroupMembersResponse{
+		Members: members,
+		Count:   len(members),
 	}
 
-	// Further filter by role if specified
-	if req.Role != "" {
-		filteredMembers := []Member{}
-		for _, member := range members {
-			if member.Role == req.Role {
-				filteredMembers = append(filteredMembers, member)
-			}
-		}
-		members = filteredMembers
+	if req.PerPage > 0 {
+		response.Total = int(total)
+		response.TotalPages = int((total + int64(req.PerPage) - 1) / int64(req.PerPage))
+		response.Page = req.Page
+		response.PerPage = req.PerPage
 	}
 
-	return &GetGroupMembersResponse{
-		Members: members,
-		Count:   len(members),
-	}, nil
+	return response, nil
 }
 
 // UpdateOwner updates group ownership
@@ -2126,6 +2341,7 @@ func (s *Service) UpdateOwner(ctx context.Context, req *UpdateOwnerRequest) (*Up
 
 	// Update owner field
 	hasChanges := false
+	previousOwnerID := group.OwnerID
 	if req.OwnerID != nil && *req.OwnerID != group.OwnerID {
 		nextOwnerID := strings.TrimSpace(*req.OwnerID)
 		if nextOwnerID != "" {
@@ -2179,10 +2395,15 @@ func (s *Service) UpdateOwner(ctx context.Context, req *UpdateOwnerRequest) (*Up
 	// Audit log
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.owner.updated",
 			TargetId:   req.GroupID,
+			Details: map[string]interface{}{
+				"owner_id":          group.OwnerID,
+				"previous_owner_id": previousOwnerID,
+			},
 		})
 	}
 
@@ -2221,6 +2442,7 @@ func (s *Service) ArchiveGroup(ctx context.Context, req *ArchiveGroupRequest) (*
 	// Audit log
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.archived",
@@ -2263,6 +2485,7 @@ func (s *Service) RestoreGroup(ctx context.Context, req *RestoreGroupRequest) (*
 	// Audit log
 	if s.AuditService != nil {
 		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.ActorID,
 			TargetType: "group",
 			Domain:     "group",
 			Action:     "group.restored",
@@ -0,0 +1,130 @@
+package group
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// integrationDelivery is an event waiting to be delivered to a group's integrations
+type integrationDelivery struct {
+	ctx   context.Context
+	group *UniversalGroup
+	event *IntegrationEvent
+}
+
+// notifyIntegrations queues an event for delivery to the group's integrations. Delivery
+// happens in the background so slow integrations never hold up the operation that raised
+// the event, and is best effort: failures are logged and events raised while the queue is
+// full are dropped
+func (s *Service) notifyIntegrations(ctx context.Context, group *UniversalGroup, event *IntegrationEvent) {
+	if s.IntegrationNotifier == nil || group == nil || event == nil {
+		return
+	}
+
+	if group.Integrations == nil || (group.Integrations.Slack == nil && len(group.Integrations.Custom) == 0) {
+		return
+	}
+
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "notify-integrations")
+
+	if event.OccurredAt == "" && s.TimeProvider != nil {
+		event.OccurredAt = s.TimeProvider.NowUTC()
+	}
+
+	// the caller keeps using the group and member once the event is queued
+	groupSnapshot := newIntegrationGroupSnapshot(group)
+	if event.Member != nil {
+		member := *event.Member
+		member.Metadata = maps.Clone(member.Metadata)
+		event.Member = &member
+	}
+
+	s.integrationWorkersOnce.Do(s.startIntegrationWorkers)
+
+	select {
+	case s.integrationDeliveries <- &integrationDelivery{ctx: context.WithoutCancel(ctx), group: groupSnapshot, event: event}:
+	default:
+		logger.Warn("integration-delivery-queue-full", zap.String("group-id", group.ID), zap.String("event-type", event.Type))
+	}
+}
+
+// newIntegrationGroupSnapshot copies the parts of a group integrations are given: its
+// identity and its integrations. Members are left out, as the event carries the member
+// it is about, and the integrations are copied deeply so nothing is shared with the caller
+func newIntegrationGroupSnapshot(group *UniversalGroup) *UniversalGroup {
+	snapshot := &UniversalGroup{
+		ID:            group.ID,
+		Name:          group.Name,
+		RawName:       group.RawName,
+		Type:          group.Type,
+		Status:        group.Status,
+		ParentGroupID: group.ParentGroupID,
+		OwnerID:       group.OwnerID,
+		NanoID:        group.NanoID,
+		MemberCount:   group.MemberCount,
+		Lineage:       slices.Clone(group.Lineage),
+	}
+
+	if group.DisplayInfo != nil {
+		displayInfo := *group.DisplayInfo
+		displayInfo.NameAliases = slices.Clone(displayInfo.NameAliases)
+		snapshot.DisplayInfo = &displayInfo
+	}
+
+	if group.Integrations != nil {
+		snapshot.Integrations = &Integrations{Custom: maps.Clone(group.Integrations.Custom)}
+		if group.Integrations.Slack != nil {
+			slack := *group.Integrations.Slack
+			slack.AdditionalRecipients = slices.Clone(slack.AdditionalRecipients)
+			slack.Events = slices.Clone(slack.Events)
+			snapshot.Integrations.Slack = &slack
+		}
+	}
+
+	return snapshot
+}
+
+// startIntegrationWorkers starts the workers delivering queued integration events
+func (s *Service) startIntegrationWorkers() {
+	s.integrationDeliveries = make(chan *integrationDelivery, integrationDeliveryQueueSize)
+
+	for i := 0; i < integrationDeliveryWorkers; i++ {
+		go func() {
+			for delivery := range s.integrationDeliveries {
+				s.deliverIntegrationEvent(delivery)
+			}
+		}()
+	}
+}
+
+// deliverIntegrationEvent delivers a queued event to the group's integrations
+func (s *Service) deliverIntegrationEvent(delivery *integrationDelivery) {
+	ctx, cancel := context.WithTimeout(delivery.ctx, integrationDeliveryTimeout)
+	defer cancel()
+
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "deliver-integration-event")
+
+	if err := s.IntegrationNotifier.NotifyGroupEvent(ctx, delivery.group, delivery.event); err != nil {
+		logger.Warn("failed-to-notify-group-integrations", zap.Error(err), zap.String("group-id", delivery.group.ID), zap.String("event-type", delivery.event.Type))
+	}
+}
+
+// notifyInviteAccepted delivers an invite accepted event for the user who
+// accepted an invitation to the group
+func (s *Service) notifyInviteAccepted(ctx context.Context, group *UniversalGroup, inviteEmail, userID string) {
+	acceptedMember, err := group.GetMemberByID(userID)
+	if err != nil {
+		return
+	}
+
+	s.notifyIntegrations(ctx, group, &IntegrationEvent{
+		Type:        IntegrationEventInviteAccepted,
+		ActorID:     userID,
+		Member:      acceptedMember,
+		InviteEmail: inviteEmail,
+	})
+}
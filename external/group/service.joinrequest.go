@@ -0,0 +1,409 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// RequestToJoinGroup records a user's request to join a group. Groups that do not
+// require approval add the requester straight away and store the request as approved,
+// otherwise the request stays pending until a group admin reviews it.
+func (s *Service) RequestToJoinGroup(ctx context.Context, req *RequestToJoinGroupRequest) (*RequestToJoinGroupResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "request-to-join-group")
+	logger.Debug("handling-request-to-join-group-request", zap.String("group-id", req.GroupID), zap.String("user-id", req.UserID))
+
+	if s.JoinRequestRepository == nil {
+		return nil, ErrJoinRequestsNotEnabled
+	}
+
+	userID := strings.TrimSpace(req.UserID)
+	if userID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group-for-join-request", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+	group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	joinable, err := s.isGroupJoinableByUser(ctx, group, userID)
+	if err != nil {
+		logger.Error("failed-to-check-if-group-is-joinable", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+	if !joinable {
+		return nil, ErrGroupNotJoinable
+	}
+
+	if group.HasMember(userID) {
+		return nil, ErrMemberAlreadyExists
+	}
+
+	pendingRequests, err := s.JoinRequestRepository.GetJoinRequests(ctx, &GetJoinRequestsRequest{
+		GroupID: group.ID,
+		UserID:  userID,
+		Status:  JoinRequestStatusPending,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-pending-join-requests", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, ErrDatabaseError
+	}
+	if len(pendingRequests) > 0 {
+		return nil, ErrJoinRequestAlreadyExists
+	}
+
+	now := s.TimeProvider.NowUTC()
+	joinRequest := &GroupJoinRequest{
+		ID:        s.IDGenerator.GenerateUUID(),
+		GroupID:   group.ID,
+		UserID:    userID,
+		Status:    JoinRequestStatusPending,
+		Message:   strings.TrimSpace(req.Message),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	var updatedGroup *UniversalGroup
+	if group.Settings == nil || !group.Settings.RequireApproval {
+		joinRequest.Role = s.defaultJoinRequestRole(group)
+		updatedGroup, err = s.addJoinRequestMember(ctx, group, joinRequest)
+		if err != nil {
+			logger.Error("failed-to-add-member-from-join-request", zap.Error(err), zap.String("group-id", req.GroupID))
+			return nil, err
+		}
+
+		joinRequest.Status = JoinRequestStatusApproved
+		joinRequest.ReviewedAt = now
+	}
+
+	createdRequest, err := s.JoinRequestRepository.CreateJoinRequest(ctx, joinRequest)
+	if err != nil {
+		logger.Error("failed-to-create-join-request", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, ErrDatabaseError
+	}
+
+	s.logJoinRequestAuditEvent(ctx, "group.join_request.created", createdRequest)
+
+	return &RequestToJoinGroupResponse{
+		JoinRequest: createdRequest,
+		Group:       updatedGroup,
+	}, nil
+}
+
+// GetJoinRequests lists join requests matching the request filters
+func (s *Service) GetJoinRequests(ctx context.Context, req *GetJoinRequestsRequest) (*GetJoinRequestsResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "get-join-requests")
+	logger.Debug("handling-get-join-requests-request", zap.String("group-id", req.GroupID), zap.String("user-id", req.UserID))
+
+	if s.JoinRequestRepository == nil {
+		return nil, ErrJoinRequestsNotEnabled
+	}
+
+	if req.Status != "" && !isValidJoinRequestStatus(req.Status) {
+		return nil, ErrInvalidQueryParam
+	}
+
+	joinRequests, err := s.JoinRequestRepository.GetJoinRequests(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-join-requests", zap.Error(err))
+		return nil, ErrDatabaseError
+	}
+
+	return &GetJoinRequestsResponse{
+		JoinRequests: joinRequests,
+		Count:        len(joinRequests),
+	}, nil
+}
+
+// ApproveJoinRequest adds the requester to the group, respecting the group's
+// maximum members, and marks the join request as approved
+func (s *Service) ApproveJoinRequest(ctx context.Context, req *ReviewJoinRequestRequest) (*ReviewJoinRequestResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "approve-join-request")
+	logger.Debug("handling-approve-join-request-request", zap.String("join-request-id", req.JoinRequestID))
+
+	joinRequest, group, err := s.getJoinRequestForReview(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	joinRequest.Role = s.defaultJoinRequestRole(group)
+	if strings.TrimSpace(req.Role) != "" {
+		joinRequest.Role = strings.ToUpper(strings.TrimSpace(req.Role))
+
+		if err := s.checkJoinRequestRoleAssignable(ctx, group, req, joinRequest.Role); err != nil {
+			logger.Warn("join-request-role-not-assignable-by-reviewer", zap.String("join-request-id", req.JoinRequestID), zap.String("role", joinRequest.Role), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	updatedGroup, err := s.addJoinRequestMember(ctx, group, joinRequest)
+	if err != nil {
+		logger.Error("failed-to-add-member-from-join-request", zap.Error(err), zap.String("join-request-id", req.JoinRequestID))
+		return nil, err
+	}
+
+	resolvedRequest, err := s.resolveJoinRequest(ctx, joinRequest, JoinRequestStatusApproved, req.ReviewerID, req.Reason)
+	if err != nil {
+		logger.Error("failed-to-approve-join-request", zap.Error(err), zap.String("join-request-id", req.JoinRequestID))
+		return nil, err
+	}
+
+	return &ReviewJoinRequestResponse{
+		JoinRequest: resolvedRequest,
+		Group:       updatedGroup,
+	}, nil
+}
+
+// DenyJoinRequest marks the join request as denied with the reviewer's reason
+func (s *Service) DenyJoinRequest(ctx context.Context, req *ReviewJoinRequestRequest) (*ReviewJoinRequestResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "deny-join-request")
+	logger.Debug("handling-deny-join-request-request", zap.String("join-request-id", req.JoinRequestID))
+
+	joinRequest, group, err := s.getJoinRequestForReview(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedRequest, err := s.resolveJoinRequest(ctx, joinRequest, JoinRequestStatusDenied, req.ReviewerID, req.Reason)
+	if err != nil {
+		logger.Error("failed-to-deny-join-request", zap.Error(err), zap.String("join-request-id", req.JoinRequestID))
+		return nil, err
+	}
+
+	return &ReviewJoinRequestResponse{
+		JoinRequest: resolvedRequest,
+		Group:       group,
+	}, nil
+}
+
+// CancelJoinRequest lets the requester withdraw a pending join request
+func (s *Service) CancelJoinRequest(ctx context.Context, req *CancelJoinRequestRequest) (*CancelJoinRequestResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "cancel-join-request")
+	logger.Debug("handling-cancel-join-request-request", zap.String("join-request-id", req.JoinRequestID))
+
+	if s.JoinRequestRepository == nil {
+		return nil, ErrJoinRequestsNotEnabled
+	}
+
+	joinRequest, err := s.JoinRequestRepository.GetJoinRequestByID(ctx, req.JoinRequestID)
+	if err != nil {
+		logger.Error("failed-to-get-join-request", zap.Error(err), zap.String("join-request-id", req.JoinRequestID))
+		return nil, err
+	}
+
+	// Do not disclose other users' requests
+	if joinRequest.UserID != strings.TrimSpace(req.UserID) {
+		return nil, ErrJoinRequestNotFound
+	}
+
+	if !joinRequest.IsPending() {
+		return nil, ErrJoinRequestNotPending
+	}
+
+	resolvedRequest, err := s.resolveJoinRequest(ctx, joinRequest, JoinRequestStatusCancelled, "", "")
+	if err != nil {
+		logger.Error("failed-to-cancel-join-request", zap.Error(err), zap.String("join-request-id", req.JoinRequestID))
+		return nil, err
+	}
+
+	return &CancelJoinRequestResponse{
+		JoinRequest: resolvedRequest,
+	}, nil
+}
+
+// getJoinRequestForReview loads a pending join request and its group, ensuring the
+// reviewer is an admin of the group unless they are a platform admin
+func (s *Service) getJoinRequestForReview(ctx context.Context, req *ReviewJoinRequestRequest) (*GroupJoinRequest, *UniversalGroup, error) {
+	if s.JoinRequestRepository == nil {
+		return nil, nil, ErrJoinRequestsNotEnabled
+	}
+
+	joinRequest, err := s.JoinRequestRepository.GetJoinRequestByID(ctx, req.JoinRequestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.GroupID != "" && joinRequest.GroupID != req.GroupID {
+		return nil, nil, ErrJoinRequestNotFound
+	}
+
+	if !joinRequest.IsPending() {
+		return nil, nil, ErrJoinRequestNotPending
+	}
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, joinRequest.GroupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	if !req.ReviewerIsPlatformAdmin {
+		canReview, err := s.Can(ctx, req.ReviewerID, group.ID, PermissionJoinRequestsReview)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !canReview {
+			return nil, nil, ErrInsufficientPermissions
+		}
+	}
+
+	return joinRequest, group, nil
+}
+
+// checkJoinRequestRoleAssignable ensures the reviewer may approve a join request with the
+// role. Ownership is never granted through a join request, and roles above the reviewer's
+// own need a reviewer who can manage members or roles.
+func (s *Service) checkJoinRequestRoleAssignable(ctx context.Context, group *UniversalGroup, req *ReviewJoinRequestRequest, role string) error {
+	if role == MemberRoleOwner {
+		return ErrInvalidMemberRole
+	}
+
+	if req.ReviewerIsPlatformAdmin {
+		return nil
+	}
+
+	reviewerRole, _ := s.resolveUserRoleForGroup(group, req.ReviewerID)
+	if s.pickHigherRole(reviewerRole, role) != role || strings.EqualFold(reviewerRole, role) {
+		return nil
+	}
+
+	for _, permission := range []string{PermissionMembersManage, PermissionRolesManage} {
+		allowed, err := s.Can(ctx, req.ReviewerID, group.ID, permission)
+		if err != nil {
+			return err
+		}
+		if allowed {
+			return nil
+		}
+	}
+
+	return ErrInsufficientPermissions
+}
+
+// addJoinRequestMember adds the requester to the group with the join request's role.
+// The members limit is checked up front so root membership is not granted for a
+// group the requester cannot join.
+func (s *Service) addJoinRequestMember(ctx context.Context, group *UniversalGroup, joinRequest *GroupJoinRequest) (*UniversalGroup, error) {
+	if group.Settings != nil && group.Settings.MaxMembers > 0 && len(group.Members) >= group.Settings.MaxMembers {
+		return nil, ErrMaxMembersReached
+	}
+
+	response, err := s.AddMember(ctx, &AddMemberRequest{
+		GroupID:  group.ID,
+		MemberID: joinRequest.UserID,
+		Type:     MemberTypeUser,
+		Role:     joinRequest.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Group, nil
+}
+
+// resolveJoinRequest moves a pending join request to its final status
+func (s *Service) resolveJoinRequest(ctx context.Context, joinRequest *GroupJoinRequest, status, reviewerID, reason string) (*GroupJoinRequest, error) {
+	now := s.TimeProvider.NowUTC()
+
+	joinRequest.Status = status
+	joinRequest.DecisionReason = strings.TrimSpace(reason)
+	joinRequest.UpdatedAt = now
+	if status != JoinRequestStatusCancelled {
+		joinRequest.ReviewedByID = reviewerID
+		joinRequest.ReviewedAt = now
+	}
+
+	updatedRequest, err := s.JoinRequestRepository.UpdateJoinRequest(ctx, joinRequest)
+	if err != nil {
+		return nil, ErrDatabaseError
+	}
+
+	s.logJoinRequestAuditEvent(ctx, audit.AuditAction("group.join_request."+strings.ToLower(status)), updatedRequest)
+
+	return updatedRequest, nil
+}
+
+// isGroupJoinableByUser reports whether a user can ask to join a group. Only active
+// groups the user can see accept requests: PUBLIC groups are open to everyone,
+// INTERNAL groups to members of the same root group, and PRIVATE groups to no one.
+func (s *Service) isGroupJoinableByUser(ctx context.Context, group *UniversalGroup, userID string) (bool, error) {
+	if group.Status != GroupStatusActive {
+		return false, nil
+	}
+
+	switch s.groupVisibility(group) {
+	case VisibilityPublic:
+		return true, nil
+	case VisibilityInternal:
+		if len(group.Lineage) == 0 {
+			return false, nil
+		}
+
+		rootGroupID, err := s.getRootGroupID(group.Lineage)
+		if err != nil {
+			return false, err
+		}
+
+		rootGroup, err := s.GroupRepository.GetGroupByID(ctx, rootGroupID)
+		if err != nil {
+			if errors.Is(err, ErrResourceNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		return rootGroup.HasMember(userID), nil
+	default:
+		return false, nil
+	}
+}
+
+// defaultJoinRequestRole returns the role given to members joining through a request
+func (s *Service) defaultJoinRequestRole(group *UniversalGroup) string {
+	if group.Settings != nil && strings.TrimSpace(group.Settings.AutoActionDefaultMemberRole) != "" {
+		return group.Settings.AutoActionDefaultMemberRole
+	}
+
+	return MemberRoleMember
+}
+
+// logJoinRequestAuditEvent records a join request lifecycle event against its group
+func (s *Service) logJoinRequestAuditEvent(ctx context.Context, action audit.AuditAction, joinRequest *GroupJoinRequest) {
+	if s.AuditService == nil {
+		return
+	}
+
+	// requesters create and cancel their requests, reviewers approve and deny them
+	actorID := joinRequest.UserID
+	if joinRequest.ReviewedByID != "" {
+		actorID = joinRequest.ReviewedByID
+	}
+
+	s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+		ActorId:    actorID,
+		TargetType: "group",
+		Domain:     "group",
+		Action:     action,
+		TargetId:   joinRequest.GroupID,
+		Details: map[string]interface{}{
+			"join_request": joinRequest,
+		},
+	})
+}
+
+// isValidJoinRequestStatus reports whether the status is a known join request status
+func isValidJoinRequestStatus(status string) bool {
+	switch status {
+	case JoinRequestStatusPending, JoinRequestStatusApproved, JoinRequestStatusDenied, JoinRequestStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
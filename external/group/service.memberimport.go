@@ -0,0 +1,569 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// ImportMembers validates members to import into a group and, unless it is a dry run,
+// starts importing the valid rows in the background. People with an account are added
+// and everyone else is invited by email.
+//
+// The response's report lists every row with its issues. Rows with errors are never
+// imported, rows with warnings are. Use GetMemberImport to follow the import's progress
+func (s *Service) ImportMembers(ctx context.Context, req *ImportMembersRequest) (*ImportMembersResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "import-members")
+	logger.Debug("handling-import-members-request", zap.String("group-id", req.GroupID), zap.Int("entries", len(req.Entries)), zap.Bool("dry-run", req.DryRun))
+
+	if !req.DryRun && s.MemberImportRepository == nil {
+		return nil, ErrMemberImportsNotEnabled
+	}
+
+	if len(req.Entries) == 0 {
+		return nil, ErrInvalidMemberImport
+	}
+
+	if len(req.Entries) > DefaultMaxMemberImportRows {
+		return nil, ErrMemberImportTooLarge
+	}
+
+	targetGroup, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group-for-member-import", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+	targetGroup.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	rows, err := s.validateMemberImport(ctx, targetGroup, req.Entries)
+	if err != nil {
+		logger.Error("failed-to-validate-member-import", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+
+	response := &ImportMembersResponse{
+		Report: newMemberImportReport(targetGroup.ID, rows),
+	}
+
+	if req.DryRun {
+		return response, nil
+	}
+
+	now := s.TimeProvider.NowUTC()
+	memberImport := &MemberImportJob{
+		ID:            s.IDGenerator.GenerateUUID(),
+		GroupID:       targetGroup.ID,
+		Status:        MemberImportStatusPending,
+		RequestedByID: req.ActorID,
+		Rows:          rows,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	memberImport.Progress = summariseMemberImportRows(memberImport.Rows)
+
+	createdImport, err := s.MemberImportRepository.CreateMemberImport(ctx, memberImport)
+	if err != nil {
+		logger.Error("failed-to-create-member-import", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, ErrDatabaseError
+	}
+
+	// the response gets its own copy as the import carries on changing in the background
+	response.Import = cloneMemberImportJob(createdImport)
+
+	logger.Info("member-import-started", zap.String("group-id", targetGroup.ID), zap.String("import-id", createdImport.ID), zap.Int("rows", len(rows)))
+
+	go s.claimAndProcessMemberImport(context.WithoutCancel(ctx), createdImport.ID)
+
+	return response, nil
+}
+
+// GetMemberImport returns a member import with its progress and per-row results
+func (s *Service) GetMemberImport(ctx context.Context, req *GetMemberImportRequest) (*GetMemberImportResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "get-member-import")
+	logger.Debug("handling-get-member-import-request", zap.String("group-id", req.GroupID), zap.String("import-id", req.ImportID))
+
+	if s.MemberImportRepository == nil {
+		return nil, ErrMemberImportsNotEnabled
+	}
+
+	memberImport, err := s.MemberImportRepository.GetMemberImportByID(ctx, req.ImportID)
+	if err != nil {
+		return nil, err
+	}
+
+	// imports are only visible through the group they belong to
+	if req.GroupID != "" && memberImport.GroupID != req.GroupID {
+		return nil, ErrMemberImportNotFound
+	}
+
+	return &GetMemberImportResponse{Import: memberImport}, nil
+}
+
+// ResumeMemberImport carries on processing the rows of a member import that stopped part
+// way through, for example because the service processing it stopped. The import is claimed
+// first, so an import still being processed elsewhere returns ErrMemberImportAlreadyClaimed
+// until it has gone without a heartbeat for five minutes. The rows are processed in the
+// background, finished imports are returned as they are
+func (s *Service) ResumeMemberImport(ctx context.Context, req *GetMemberImportRequest) (*GetMemberImportResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "resume-member-import")
+
+	response, err := s.GetMemberImport(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Import.IsFinished() {
+		return response, nil
+	}
+
+	claimedImport, err := s.claimMemberImport(ctx, response.Import.ID)
+	if err != nil {
+		if !errors.Is(err, ErrMemberImportAlreadyClaimed) {
+			logger.Error("failed-to-claim-member-import", zap.Error(err), zap.String("import-id", response.Import.ID))
+		}
+		return nil, err
+	}
+
+	response.Import = cloneMemberImportJob(claimedImport)
+
+	logger.Info("member-import-resumed", zap.String("group-id", claimedImport.GroupID), zap.String("import-id", claimedImport.ID))
+
+	go s.processMemberImport(context.WithoutCancel(ctx), claimedImport)
+
+	return response, nil
+}
+
+// ResumeStaleMemberImports resumes every member import left pending or running without a
+// heartbeat for five minutes, usually because the service processing it stopped. Call it
+// when the service starts. Imports claimed by another service in the meantime are skipped
+func (s *Service) ResumeStaleMemberImports(ctx context.Context, _ *ResumeStaleMemberImportsRequest) (*ResumeStaleMemberImportsResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "resume-stale-member-imports")
+
+	if s.MemberImportRepository == nil {
+		return nil, ErrMemberImportsNotEnabled
+	}
+
+	staleImports, err := s.MemberImportRepository.GetStaleMemberImports(ctx, s.memberImportStaleBefore())
+	if err != nil {
+		logger.Error("failed-to-get-stale-member-imports", zap.Error(err))
+		return nil, ErrDatabaseError
+	}
+
+	response := &ResumeStaleMemberImportsResponse{ImportIDs: []string{}}
+	for _, staleImport := range staleImports {
+		claimedImport, err := s.claimMemberImport(ctx, staleImport.ID)
+		if err != nil {
+			if !errors.Is(err, ErrMemberImportAlreadyClaimed) {
+				logger.Error("failed-to-claim-stale-member-import", zap.Error(err), zap.String("import-id", staleImport.ID))
+			}
+			continue
+		}
+
+		go s.processMemberImport(context.WithoutCancel(ctx), claimedImport)
+		response.ImportIDs = append(response.ImportIDs, claimedImport.ID)
+	}
+
+	logger.Info("resumed-stale-member-imports", zap.Int("resumed-count", len(response.ImportIDs)))
+
+	return response, nil
+}
+
+// claimAndProcessMemberImport claims a newly created member import and processes it
+func (s *Service) claimAndProcessMemberImport(ctx context.Context, importID string) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "claim-member-import")
+
+	claimedImport, err := s.claimMemberImport(ctx, importID)
+	if err != nil {
+		// a stale import sweep or resume got there first
+		logger.Warn("failed-to-claim-new-member-import", zap.Error(err), zap.String("import-id", importID))
+		return
+	}
+
+	s.processMemberImport(ctx, claimedImport)
+}
+
+// claimMemberImport claims a member import for a new processing run, so no other run
+// processes it while its heartbeat is kept up
+func (s *Service) claimMemberImport(ctx context.Context, importID string) (*MemberImportJob, error) {
+	return s.MemberImportRepository.ClaimMemberImport(ctx, importID, s.IDGenerator.GenerateUUID(), s.memberImportStaleBefore(), s.memberImportHeartbeatNow())
+}
+
+// memberImportHeartbeatNow returns the current time as recorded in import heartbeats
+func (s *Service) memberImportHeartbeatNow() string {
+	return s.TimeProvider.Now().UTC().Format(time.RFC3339)
+}
+
+// memberImportStaleBefore returns the heartbeat time before which imports are abandoned
+func (s *Service) memberImportStaleBefore() string {
+	return s.TimeProvider.Now().UTC().Add(-memberImportStaleAfter).Format(time.RFC3339)
+}
+
+// keepMemberImportClaim records a heartbeat for the import on every interval until the
+// context is done. Losing the claim to another run cancels processing
+func (s *Service) keepMemberImportClaim(ctx context.Context, cancel context.CancelFunc, importID, claimedBy string, logger *zap.Logger) {
+	ticker := time.NewTicker(memberImportHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.MemberImportRepository.HeartbeatMemberImport(ctx, importID, claimedBy, s.memberImportHeartbeatNow())
+		if errors.Is(err, ErrMemberImportAlreadyClaimed) {
+			logger.Warn("member-import-claim-lost")
+			cancel()
+			return
+		}
+		if err != nil {
+			logger.Error("failed-to-record-member-import-heartbeat", zap.Error(err))
+		}
+	}
+}
+
+// processMemberImport adds or invites each valid row of a claimed import, saving its progress
+// every few rows. Row failures are recorded against the row and do not stop the import, but
+// losing the claim does
+func (s *Service) processMemberImport(ctx context.Context, memberImport *MemberImportJob) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "process-member-import").With(
+		zap.String("group-id", memberImport.GroupID),
+		zap.String("import-id", memberImport.ID),
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.keepMemberImportClaim(ctx, cancel, memberImport.ID, memberImport.ClaimedBy, logger)
+
+	targetGroup, err := s.GroupRepository.GetGroupByID(ctx, memberImport.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group-for-member-import", zap.Error(err))
+		memberImport.Status = MemberImportStatusFailed
+		memberImport.LastError = err.Error()
+		memberImport.CompletedAt = s.TimeProvider.NowUTC()
+		s.saveMemberImportProgress(ctx, memberImport, logger)
+		return
+	}
+	topLevel := !s.isNonTopLevelGroup(targetGroup)
+
+	sinceSave := 0
+	for i := range memberImport.Rows {
+		row := &memberImport.Rows[i]
+		if row.Status != MemberImportRowStatusValid {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			logger.Warn("stopping-member-import-without-claim", zap.Int("processed", summariseMemberImportRows(memberImport.Rows).Processed))
+			return
+		}
+
+		s.importMemberRow(ctx, memberImport, row, topLevel)
+
+		sinceSave++
+		if sinceSave >= memberImportProgressInterval {
+			s.saveMemberImportProgress(ctx, memberImport, logger)
+			sinceSave = 0
+		}
+	}
+
+	memberImport.Status = MemberImportStatusCompleted
+	memberImport.CompletedAt = s.TimeProvider.NowUTC()
+	s.saveMemberImportProgress(ctx, memberImport, logger)
+
+	if s.AuditService != nil {
+		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    memberImport.RequestedByID,
+			TargetType: "group",
+			Domain:     "group",
+			Action:     "group.members.imported",
+			TargetId:   memberImport.GroupID,
+			Details: map[string]interface{}{
+				"import_id": memberImport.ID,
+				"total":     memberImport.Progress.Total,
+				"added":     memberImport.Progress.Added,
+				"invited":   memberImport.Progress.Invited,
+				"skipped":   memberImport.Progress.Skipped,
+				"failed":    memberImport.Progress.Failed,
+				"invalid":   memberImport.Progress.Invalid,
+			},
+		})
+	}
+
+	logger.Info("member-import-completed",
+		zap.Int("added", memberImport.Progress.Added),
+		zap.Int("invited", memberImport.Progress.Invited),
+		zap.Int("skipped", memberImport.Progress.Skipped),
+		zap.Int("failed", memberImport.Progress.Failed),
+	)
+}
+
+// importMemberRow adds or invites a single row and records the outcome on it. People
+// who joined or were invited since the import was validated are skipped
+func (s *Service) importMemberRow(ctx context.Context, memberImport *MemberImportJob, row *MemberImportRow, topLevel bool) {
+	var err error
+
+	switch row.Action {
+	case MemberImportActionAdd:
+		_, err = s.AddMember(ctx, &AddMemberRequest{
+			GroupID:  memberImport.GroupID,
+			MemberID: row.MemberID,
+			Type:     MemberTypeUser,
+			Role:     row.Role,
+			ActorID:  memberImport.RequestedByID,
+		})
+		row.Status = MemberImportRowStatusAdded
+
+	case MemberImportActionInvite:
+		if !topLevel {
+			err = ErrInviteRequiresTopLevelGroup
+			break
+		}
+		_, err = s.InviteUser(ctx, &InviteUserRequest{
+			GroupID:     memberImport.GroupID,
+			InviteEmail: row.Email,
+			Role:        row.Role,
+			InvitedByID: memberImport.RequestedByID,
+		})
+		row.Status = MemberImportRowStatusInvited
+
+	default:
+		err = ErrInvalidMemberImport
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrMemberAlreadyExists), errors.Is(err, ErrInvitationAlreadyExists):
+		row.Status = MemberImportRowStatusSkipped
+	default:
+		row.Status = MemberImportRowStatusFailed
+		row.Error = err.Error()
+	}
+}
+
+// saveMemberImportProgress recounts the import's progress and saves it. Failures are
+// logged, the import carries on and is saved again at the next interval
+func (s *Service) saveMemberImportProgress(ctx context.Context, memberImport *MemberImportJob, logger *zap.Logger) {
+	memberImport.Progress = summariseMemberImportRows(memberImport.Rows)
+	memberImport.UpdatedAt = s.TimeProvider.NowUTC()
+	memberImport.HeartbeatAt = s.memberImportHeartbeatNow()
+
+	if _, err := s.MemberImportRepository.UpdateMemberImport(ctx, cloneMemberImportJob(memberImport)); err != nil {
+		logger.Error("failed-to-save-member-import-progress", zap.Error(err), zap.Int("processed", memberImport.Progress.Processed))
+	}
+}
+
+// validateMemberImport turns import entries into rows, recording the issues found with
+// each and whether it will be added, invited or skipped
+func (s *Service) validateMemberImport(ctx context.Context, targetGroup *UniversalGroup, entries []MemberImportEntry) ([]MemberImportRow, error) {
+	topLevel := !s.isNonTopLevelGroup(targetGroup)
+
+	// remaining is how many more members the group can take, -1 when it is unlimited
+	remaining := -1
+	if targetGroup.Settings != nil && targetGroup.Settings.MaxMembers > 0 {
+		remaining = max(targetGroup.Settings.MaxMembers-len(targetGroup.Members), 0)
+	}
+
+	autoActionDomains, autoActionRole := s.memberImportAutoActionDomains(targetGroup)
+
+	rowByEmail := map[string]int{}
+	rows := make([]MemberImportRow, 0, len(entries))
+	for i, entry := range entries {
+		row := MemberImportRow{
+			Row:    i + 1,
+			Email:  strings.TrimSpace(entry.Email),
+			Role:   strings.ToUpper(strings.TrimSpace(entry.Role)),
+			Status: MemberImportRowStatusValid,
+		}
+		if row.Role == "" {
+			row.Role = MemberRoleMember
+		}
+
+		if err := s.isValidMemberRole(targetGroup, row.Role); err != nil {
+			row.addIssue(MemberImportIssueUnknownRole, MemberImportSeverityError, fmt.Sprintf("%s is not a role members of this group can have", row.Role))
+		}
+
+		email, err := toolbox.NormaliseEmail(row.Email)
+		if err != nil {
+			row.addIssue(MemberImportIssueInvalidEmail, MemberImportSeverityError, "email is missing or invalid")
+			rows = append(rows, row.markInvalid())
+			continue
+		}
+		row.Email = email
+
+		if firstRow, seen := rowByEmail[email]; seen {
+			row.addIssue(MemberImportIssueDuplicate, MemberImportSeverityError, fmt.Sprintf("email is already imported by row %d", firstRow))
+			rows = append(rows, row.markInvalid())
+			continue
+		}
+		rowByEmail[email] = row.Row
+
+		if domainRole, ok := autoActionDomains[emailDomain(email)]; ok && domainRole != row.Role {
+			row.addIssue(MemberImportIssueAutoJoinDomainConflict, MemberImportSeverityWarning, fmt.Sprintf("people with this email domain join the group as %s, this row imports them as %s", autoActionRole, row.Role))
+		}
+
+		if err := s.planMemberImportRow(ctx, targetGroup, &row, topLevel); err != nil {
+			return nil, err
+		}
+
+		if row.Action != MemberImportActionSkip && !row.HasErrors() && remaining >= 0 {
+			if remaining == 0 {
+				row.addIssue(MemberImportIssueOverMaxMembers, MemberImportSeverityError, fmt.Sprintf("the group is limited to %d members", targetGroup.Settings.MaxMembers))
+			} else {
+				remaining--
+			}
+		}
+
+		if row.HasErrors() {
+			row.markInvalid()
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// planMemberImportRow decides whether a row's person is added, invited or skipped because
+// they are already in the group
+func (s *Service) planMemberImportRow(ctx context.Context, targetGroup *UniversalGroup, row *MemberImportRow, topLevel bool) error {
+	if s.MemberImportUserFinder != nil {
+		userID, err := s.MemberImportUserFinder.FindUserIDByEmail(ctx, row.Email)
+		if err != nil {
+			return err
+		}
+
+		if userID != "" {
+			row.MemberID = userID
+			row.Action = MemberImportActionAdd
+			if targetGroup.HasMember(userID) {
+				row.skip("user is already a member of the group")
+			}
+			return nil
+		}
+	}
+
+	if targetGroup.HasMember(row.Email) || hasPendingInviteByEmail(targetGroup, row.Email) {
+		row.skip("email has already been invited to the group")
+		return nil
+	}
+
+	if !topLevel {
+		row.addIssue(MemberImportIssueInviteNotAllowed, MemberImportSeverityError, "email has no account and only top-level groups can invite by email")
+		return nil
+	}
+
+	row.Action = MemberImportActionInvite
+	return nil
+}
+
+// memberImportAutoActionDomains returns the email domains the group auto-joins or
+// auto-invites, each mapped to the role they join with, and that role
+func (s *Service) memberImportAutoActionDomains(targetGroup *UniversalGroup) (map[string]string, string) {
+	domains := map[string]string{}
+	if targetGroup.Settings == nil || (!targetGroup.Settings.AutoJoinByEmailDomainEnabled && !targetGroup.Settings.AutoInviteByEmailDomainEnabled) {
+		return domains, ""
+	}
+
+	role := strings.ToUpper(s.defaultJoinRequestRole(targetGroup))
+	for _, domain := range targetGroup.Settings.AutoActionEmailDomains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains[domain] = role
+		}
+	}
+
+	return domains, role
+}
+
+// addIssue records an issue found with the row
+func (r *MemberImportRow) addIssue(code, severity, message string) {
+	r.Issues = append(r.Issues, MemberImportIssue{Code: code, Severity: severity, Message: message})
+}
+
+// markInvalid stops the row being imported and returns it
+func (r *MemberImportRow) markInvalid() MemberImportRow {
+	r.Status = MemberImportRowStatusInvalid
+	r.Action = ""
+	return *r
+}
+
+// skip marks the row as already in the group
+func (r *MemberImportRow) skip(message string) {
+	r.Action = MemberImportActionSkip
+	r.Status = MemberImportRowStatusSkipped
+	r.addIssue(MemberImportIssueAlreadyMember, MemberImportSeverityWarning, message)
+}
+
+// newMemberImportReport summarises validated rows
+func newMemberImportReport(groupID string, rows []MemberImportRow) *MemberImportReport {
+	report := &MemberImportReport{GroupID: groupID, Total: len(rows), Rows: rows}
+	for i := range rows {
+		if rows[i].Status == MemberImportRowStatusInvalid {
+			report.Invalid++
+		} else {
+			report.Valid++
+		}
+
+		for _, issue := range rows[i].Issues {
+			if issue.Severity == MemberImportSeverityWarning {
+				report.Warnings++
+				break
+			}
+		}
+	}
+
+	return report
+}
+
+// summariseMemberImportRows counts an import's rows by status
+func summariseMemberImportRows(rows []MemberImportRow) MemberImportProgress {
+	progress := MemberImportProgress{Total: len(rows)}
+	for i := range rows {
+		switch rows[i].Status {
+		case MemberImportRowStatusValid:
+			continue
+		case MemberImportRowStatusAdded:
+			progress.Added++
+		case MemberImportRowStatusInvited:
+			progress.Invited++
+		case MemberImportRowStatusSkipped:
+			progress.Skipped++
+		case MemberImportRowStatusFailed:
+			progress.Failed++
+		case MemberImportRowStatusInvalid:
+			progress.Invalid++
+		}
+		progress.Processed++
+	}
+
+	return progress
+}
+
+// cloneMemberImportJob copies an import so it can be handed out while the original is
+// still being processed
+func cloneMemberImportJob(memberImport *MemberImportJob) *MemberImportJob {
+	clone := *memberImport
+	clone.Rows = make([]MemberImportRow, len(memberImport.Rows))
+	for i, row := range memberImport.Rows {
+		row.Issues = append([]MemberImportIssue(nil), row.Issues...)
+		clone.Rows[i] = row
+	}
+
+	return &clone
+}
+
+// emailDomain returns the domain of a normalised email
+func emailDomain(email string) string {
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		return email[at+1:]
+	}
+
+	return ""
+}
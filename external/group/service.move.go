@@ -0,0 +1,358 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// MoveGroup moves a group and its descendants under a new parent, or to the top level when
+// no parent is given. The hierarchy tree rules, nesting depth and sibling name uniqueness are
+// checked before anything is written, and moving a group under one of its descendants is
+// rejected.
+//
+// Nested groups may only hold members of their root group, so when the move changes root
+// the members of the moved groups that are not in the new root are removed from them, the
+// same way removing a member from a root group cascades. With PropagateMembers they are
+// added to the new root group instead. Membership updates are best-effort, failures are
+// reported in FailedGroupIDs.
+//
+// Descendant lineages are rewritten before the moved group, so a move that fails part way
+// through can be completed by repeating it.
+func (s *Service) MoveGroup(ctx context.Context, req *MoveGroupRequest) (*MoveGroupResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "move-group")
+	logger.Debug("handling-move-group-request", zap.String("group-id", req.GroupID), zap.String("new-parent-group-id", req.NewParentGroupID))
+
+	if err := s.validateHierarchyTreeConfig(logger); err != nil {
+		return nil, err
+	}
+
+	newParentGroupID := strings.TrimSpace(req.NewParentGroupID)
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group-to-move", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+	group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	if newParentGroupID == group.ID {
+		return nil, ErrCircularReferenceDetected
+	}
+
+	descendants, err := s.GroupRepository.GetGroupsByLineageAncestor(ctx, group.ID)
+	if err != nil {
+		logger.Error("failed-to-get-descendants-of-group-to-move", zap.Error(err), zap.String("group-id", group.ID))
+		return nil, ErrDatabaseError
+	}
+
+	// a previous move to the same parent may have stopped before every descendant was updated
+	if strings.TrimSpace(group.ParentGroupID) == newParentGroupID && !hasStaleDescendantLineage(group, descendants) {
+		return nil, ErrNoChangesDetected
+	}
+
+	newLineage := []string{}
+	if newParentGroupID != "" {
+		newParent, err := s.GroupRepository.GetGroupByID(ctx, newParentGroupID)
+		if err != nil {
+			logger.Error("failed-to-get-new-parent-group", zap.Error(err), zap.String("new-parent-group-id", newParentGroupID))
+			return nil, err
+		}
+
+		if newParent.Metadata != nil && newParent.Metadata.DeletedAt != "" {
+			return nil, ErrResourceNotFound
+		}
+
+		for _, lineageID := range newParent.Lineage {
+			if lineageID == group.ID {
+				logger.Warn("move-would-create-circular-reference", zap.String("group-id", group.ID), zap.String("new-parent-group-id", newParentGroupID))
+				return nil, ErrCircularReferenceDetected
+			}
+		}
+
+		if !s.Config.CanHaveChildType(newParent.Type, group.Type) {
+			logger.Warn(
+				"invalid-parent-child-group-relation",
+				zap.String("parent-group-id", newParent.ID),
+				zap.String("parent-group-type", newParent.Type),
+				zap.String("child-group-type", group.Type),
+			)
+			return nil, ErrInvalidParentChildRelation
+		}
+
+		newLineage = newParent.BuildChildLineage()
+	}
+
+	if err := s.validateMoveDepth(group, descendants, newLineage); err != nil {
+		logger.Warn("move-would-exceed-max-nesting-depth", zap.String("group-id", group.ID), zap.String("new-parent-group-id", newParentGroupID))
+		return nil, err
+	}
+
+	if err := s.validateMoveName(ctx, group, newParentGroupID); err != nil {
+		if errors.Is(err, ErrDatabaseError) {
+			logger.Error("failed-to-check-sibling-names-for-move", zap.String("group-id", group.ID), zap.String("new-parent-group-id", newParentGroupID))
+			return nil, err
+		}
+		logger.Warn("move-would-duplicate-sibling-name", zap.String("group-id", group.ID), zap.String("new-parent-group-id", newParentGroupID))
+		return nil, err
+	}
+
+	err = s.GroupRepository.MoveGroupSubtree(ctx, group.ID, newParentGroupID, newLineage, s.TimeProvider.NowUTC())
+	if err != nil {
+		logger.Error("failed-to-move-group-subtree", zap.Error(err), zap.String("group-id", group.ID))
+		return nil, ErrDatabaseError
+	}
+
+	response := &MoveGroupResponse{
+		MovedDescendantIDs: make([]string, 0, len(descendants)),
+	}
+	for _, descendant := range descendants {
+		response.MovedDescendantIDs = append(response.MovedDescendantIDs, descendant.ID)
+	}
+
+	oldRootGroupID := group.ID
+	if len(group.Lineage) > 0 {
+		oldRootGroupID = group.Lineage[0]
+	}
+	newRootGroupID := group.ID
+	if len(newLineage) > 0 {
+		newRootGroupID = newLineage[0]
+	}
+
+	if oldRootGroupID != newRootGroupID {
+		movedGroups := make([]*UniversalGroup, 0, len(descendants)+1)
+		movedGroups = append(movedGroups, group)
+		for i := range descendants {
+			movedGroups = append(movedGroups, &descendants[i])
+		}
+
+		s.reconcileMovedGroupMembers(ctx, movedGroups, newRootGroupID, req.PropagateMembers, strings.TrimSpace(req.MovedByID), response)
+		if len(response.FailedGroupIDs) > 0 {
+			logger.Warn("failed-to-update-members-of-some-moved-groups", zap.Strings("failed-group-ids", response.FailedGroupIDs))
+		}
+	}
+
+	response.Group, err = s.GroupRepository.GetGroupByID(ctx, group.ID)
+	if err != nil {
+		logger.Error("failed-to-get-moved-group", zap.Error(err), zap.String("group-id", group.ID))
+		return nil, err
+	}
+	response.Group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	if s.AuditService != nil {
+		details := map[string]interface{}{
+			"moved_by_id":          req.MovedByID,
+			"old_parent_group_id":  group.ParentGroupID,
+			"new_parent_group_id":  newParentGroupID,
+			"old_lineage":          group.Lineage,
+			"new_lineage":          newLineage,
+			"moved_descendant_ids": response.MovedDescendantIDs,
+		}
+		if len(response.RemovedMembers) > 0 {
+			details["removed_members"] = response.RemovedMembers
+		}
+		if len(response.PropagatedMemberIDs) > 0 {
+			details["propagated_member_ids"] = response.PropagatedMemberIDs
+		}
+		if len(response.FailedGroupIDs) > 0 {
+			details["failed_group_ids"] = response.FailedGroupIDs
+		}
+
+		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.MovedByID,
+			TargetType: "group",
+			Domain:     "group",
+			Action:     "group.moved",
+			TargetId:   group.ID,
+			Details:    details,
+		})
+	}
+
+	return response, nil
+}
+
+// validateMoveDepth checks the deepest group of the moved subtree stays within MaxNestingDepth
+func (s *Service) validateMoveDepth(group *UniversalGroup, descendants []UniversalGroup, newLineage []string) error {
+	if len(newLineage) == 0 {
+		return nil
+	}
+
+	if !s.Config.AllowNestedGroups {
+		return ErrMaxDepthExceeded
+	}
+
+	if s.Config.MaxNestingDepth <= 0 {
+		return nil
+	}
+
+	subtreeDepth := 0
+	for _, descendant := range descendants {
+		for i, lineageID := range descendant.Lineage {
+			if lineageID == group.ID {
+				if depth := len(descendant.Lineage) - i; depth > subtreeDepth {
+					subtreeDepth = depth
+				}
+				break
+			}
+		}
+	}
+
+	if len(newLineage)+subtreeDepth > s.Config.MaxNestingDepth {
+		return ErrMaxDepthExceeded
+	}
+
+	return nil
+}
+
+// validateMoveName checks no other group under the new parent, or no other root group, has the group's name
+func (s *Service) validateMoveName(ctx context.Context, group *UniversalGroup, newParentGroupID string) error {
+	var (
+		existing *UniversalGroup
+		err      error
+	)
+	if newParentGroupID != "" {
+		existing, err = s.GroupRepository.GetGroupByNameAndParent(ctx, group.Name, newParentGroupID, false)
+	} else {
+		existing, err = s.GroupRepository.GetGroupByName(ctx, group.Name, group.Type, false)
+	}
+	if err != nil {
+		if errors.Is(err, ErrUnableToFindGroupWithName) {
+			return nil
+		}
+		return ErrDatabaseError
+	}
+
+	if newParentGroupID == "" && existing != nil && strings.TrimSpace(existing.ParentGroupID) != "" {
+		existing = nil
+	}
+
+	if existing != nil && existing.ID != group.ID {
+		return ErrNameAlreadyExists
+	}
+
+	return nil
+}
+
+// reconcileMovedGroupMembers makes sure every member of the moved groups is a member of
+// their new root group, either by adding them to the root with its default member role or
+// removing them from the moved groups
+func (s *Service) reconcileMovedGroupMembers(ctx context.Context, movedGroups []*UniversalGroup, newRootGroupID string, propagate bool, movedByID string, response *MoveGroupResponse) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "reconcile-moved-group-members")
+
+	// the root is loaded after the move so its lineage is current
+	newRoot, err := s.GroupRepository.GetGroupByID(ctx, newRootGroupID)
+	if err != nil {
+		logger.Warn("failed-to-get-new-root-group", zap.Error(err), zap.String("root-group-id", newRootGroupID))
+		response.FailedGroupIDs = append(response.FailedGroupIDs, newRootGroupID)
+		return
+	}
+	newRoot.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	defaultRole := s.defaultJoinRequestRole(newRoot)
+	propagatedMemberIDs := []string{}
+	for _, movedGroup := range movedGroups {
+		if movedGroup.ID == newRootGroupID {
+			continue
+		}
+
+		for _, member := range movedGroup.Members {
+			if !propagate || isPendingInviteMember(member) || newRoot.HasMember(member.ID) {
+				continue
+			}
+
+			if _, err := newRoot.AddMember(member.ID, member.Type, defaultRole); err != nil {
+				logger.Warn("failed-to-propagate-member-to-new-root-group", zap.Error(err), zap.String("member-id", member.ID))
+				continue
+			}
+			propagatedMemberIDs = append(propagatedMemberIDs, member.ID)
+		}
+	}
+
+	if len(propagatedMemberIDs) > 0 {
+		if _, err := s.GroupRepository.UpdateGroup(ctx, newRoot); err != nil {
+			logger.Warn("failed-to-save-propagated-members-to-new-root-group", zap.Error(err), zap.String("root-group-id", newRootGroupID))
+			response.FailedGroupIDs = append(response.FailedGroupIDs, newRootGroupID)
+			return
+		}
+
+		response.PropagatedMemberIDs = propagatedMemberIDs
+		if s.AuditService != nil {
+			for _, memberID := range propagatedMemberIDs {
+				rootMember, _ := newRoot.GetMemberByID(memberID)
+				s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+					TargetType: "group",
+					Domain:     "group",
+					Action:     "group.member.added",
+					ActorId:    movedByID,
+					TargetId:   newRootGroupID,
+					Details: map[string]interface{}{
+						"member":     rootMember,
+						"propagated": true,
+					},
+				})
+			}
+		}
+	}
+
+	for _, movedGroup := range movedGroups {
+		if movedGroup.ID == newRootGroupID {
+			continue
+		}
+
+		for _, member := range movedGroup.Members {
+			if newRoot.HasMember(member.ID) {
+				continue
+			}
+
+			if err := s.GroupRepository.RemoveMemberFromGroup(ctx, movedGroup.ID, member.ID); err != nil {
+				response.FailedGroupIDs = appendUniqueString(response.FailedGroupIDs, movedGroup.ID)
+				continue
+			}
+
+			if movedGroup.OwnerID == member.ID {
+				if err := s.GroupRepository.ClearOwnerFromGroup(ctx, movedGroup.ID, member.ID); err != nil {
+					response.FailedGroupIDs = appendUniqueString(response.FailedGroupIDs, movedGroup.ID)
+					continue
+				}
+			}
+
+			if response.RemovedMembers == nil {
+				response.RemovedMembers = map[string][]string{}
+			}
+			response.RemovedMembers[movedGroup.ID] = append(response.RemovedMembers[movedGroup.ID], member.ID)
+		}
+	}
+}
+
+// hasStaleDescendantLineage reports whether any descendant lineage does not start with
+// the group's own lineage, which happens when a move did not complete
+func hasStaleDescendantLineage(group *UniversalGroup, descendants []UniversalGroup) bool {
+	expectedPrefix := group.BuildChildLineage()
+	for _, descendant := range descendants {
+		if len(descendant.Lineage) < len(expectedPrefix) {
+			return true
+		}
+		for i, lineageID := range expectedPrefix {
+			if descendant.Lineage[i] != lineageID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// appendUniqueString appends value when it is not already present
+func appendUniqueString(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
@@ -0,0 +1,359 @@
+package group
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// Authorizer decides whether a user holds a permission in a group
+type Authorizer interface {
+	Can(ctx context.Context, userID, groupID, permission string) (bool, error)
+}
+
+// customRoleNamePattern matches normalised custom role names, e.g. "RELEASE_MANAGER"
+var customRoleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+
+// Can reports whether the user holds the permission in the group, either through a role
+// they hold in the group itself or, following GroupConfig.PermissionInheritance, through
+// a role they hold in one of its ancestors. Permissions must be registered, otherwise
+// ErrUnknownPermission is returned.
+func (s *Service) Can(ctx context.Context, userID, groupID, permission string) (bool, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "can")
+
+	trimmedUserID := strings.TrimSpace(userID)
+	if trimmedUserID == "" {
+		return false, ErrInvalidUserIDProvided
+	}
+
+	if !s.Config.IsRegisteredPermission(permission) {
+		logger.Warn("permission-not-registered", zap.String("permission", permission))
+		return false, ErrUnknownPermission
+	}
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, groupID)
+	if err != nil {
+		logger.Warn("failed-to-get-group-for-permission-check", zap.String("group-id", groupID), zap.Error(err))
+		return false, err
+	}
+
+	directPermissions, _ := s.resolveUserPermissionsForGroup(group, trimmedUserID)
+	if _, ok := directPermissions[permission]; ok {
+		return true, nil
+	}
+
+	if s.Config.GetPermissionInheritance() == PermissionInheritanceNone {
+		return false, nil
+	}
+
+	// nearest ancestors first, they are the most likely to grant the permission
+	for i := len(group.Lineage) - 1; i >= 0; i-- {
+		ancestor, ancestorErr := s.GroupRepository.GetGroupByID(ctx, group.Lineage[i])
+		if ancestorErr != nil {
+			logger.Warn("failed-to-get-ancestor-group-for-permission-check", zap.String("ancestor-group-id", group.Lineage[i]), zap.Error(ancestorErr))
+			continue
+		}
+
+		_, inheritedPermissions := s.resolveUserPermissionsForGroup(ancestor, trimmedUserID)
+		if _, ok := inheritedPermissions[permission]; ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetGroupRoles lists the roles that apply in a group, the configured roles allowed for
+// its type, OWNER and the group's custom roles, with the permissions each grants
+func (s *Service) GetGroupRoles(ctx context.Context, req *GetGroupRolesRequest) (*GetGroupRolesResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "get-group-roles")
+	logger.Debug("getting-group-roles", zap.String("group-id", req.GroupID))
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+
+	configuredRoles := []string{MemberRoleOwner}
+	if allowedRoles, exists := s.Config.TypeToRoleOverrides[group.Type]; exists {
+		configuredRoles = append(configuredRoles, allowedRoles...)
+	} else if len(s.Config.DefaultRoles) > 0 {
+		configuredRoles = append(configuredRoles, s.Config.DefaultRoles...)
+	} else {
+		permissionRoles := []string{}
+		for role := range s.Config.getRolePermissions() {
+			permissionRoles = append(permissionRoles, role)
+		}
+		configuredRoles = append(configuredRoles, uniqueSortedStrings(permissionRoles)...)
+	}
+
+	roles := []GroupRoleSummary{}
+	for _, role := range uniqueStrings(configuredRoles) {
+		permissions, _ := s.Config.GetRolePermissions(group.Type, role)
+		if permissions == nil {
+			permissions = []string{}
+		}
+
+		roles = append(roles, GroupRoleSummary{
+			Name:        strings.ToUpper(strings.TrimSpace(role)),
+			Permissions: permissions,
+		})
+	}
+
+	for _, customRole := range group.CustomRoles {
+		roles = append(roles, GroupRoleSummary{
+			Name:        customRole.Name,
+			Description: customRole.Description,
+			Permissions: uniqueSortedStrings(customRole.Permissions),
+			Custom:      true,
+		})
+	}
+
+	return &GetGroupRolesResponse{Roles: roles}, nil
+}
+
+// SetGroupRole creates or updates one of the group's custom roles. Custom roles can only
+// grant registered permissions the actor holds themselves and cannot reuse the name of a
+// role configured for the group's type.
+func (s *Service) SetGroupRole(ctx context.Context, req *SetGroupRoleRequest) (*SetGroupRoleResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "set-group-role")
+	logger.Debug("setting-group-role", zap.String("group-id", req.GroupID), zap.String("role-name", req.RoleName))
+
+	roleName := strings.ToUpper(strings.TrimSpace(req.RoleName))
+	if !customRoleNamePattern.MatchString(roleName) || len(req.Permissions) == 0 {
+		return nil, ErrInvalidCustomRole
+	}
+
+	permissions := uniqueSortedStrings(req.Permissions)
+	for _, permission := range permissions {
+		if !s.Config.IsRegisteredPermission(permission) {
+			logger.Warn("custom-role-permission-not-registered", zap.String("permission", permission))
+			return nil, ErrUnknownPermission
+		}
+	}
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+
+	group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	if s.Config.HasConfiguredRole(group.Type, roleName) {
+		return nil, ErrCustomRoleConflict
+	}
+
+	if !req.UpdatedByIsPlatformAdmin {
+		if err := s.checkPermissionsHeld(ctx, group.ID, req.UpdatedByID, permissions); err != nil {
+			logger.Warn("custom-role-exceeds-actor-permissions", zap.String("actor-id", req.UpdatedByID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	role := GroupRole{
+		Name:        roleName,
+		Description: strings.TrimSpace(req.Description),
+		Permissions: permissions,
+	}
+
+	created := true
+	if existingRole := group.GetCustomRole(roleName); existingRole != nil {
+		*existingRole = role
+		created = false
+	} else {
+		group.CustomRoles = append(group.CustomRoles, role)
+	}
+	group.SetUpdatedAtNow()
+
+	updatedGroup, err := s.GroupRepository.UpdateGroup(ctx, group)
+	if err != nil {
+		logger.Error("failed-to-update-group-in-repository", zap.Error(err))
+		return nil, ErrDatabaseError
+	}
+
+	if s.AuditService != nil {
+		action := "group.role.updated"
+		if created {
+			action = "group.role.created"
+		}
+
+		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.UpdatedByID,
+			TargetType: "group",
+			Domain:     "group",
+			Action:     audit.AuditAction(action),
+			TargetId:   group.ID,
+			Details: map[string]interface{}{
+				"role": role,
+			},
+		})
+	}
+
+	return &SetGroupRoleResponse{Group: updatedGroup, Role: &role, Created: created}, nil
+}
+
+// DeleteGroupRole deletes one of the group's custom roles. Roles still held by members
+// cannot be deleted, their members must be given another role first.
+func (s *Service) DeleteGroupRole(ctx context.Context, req *DeleteGroupRoleRequest) (*DeleteGroupRoleResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/group", "delete-group-role")
+	logger.Debug("deleting-group-role", zap.String("group-id", req.GroupID), zap.String("role-name", req.RoleName))
+
+	group, err := s.GroupRepository.GetGroupByID(ctx, req.GroupID)
+	if err != nil {
+		logger.Error("failed-to-get-group", zap.Error(err), zap.String("group-id", req.GroupID))
+		return nil, err
+	}
+
+	group.SetDependencies(s.Config, s.IDGenerator, s.TimeProvider, s.StringUtils)
+
+	roleName := strings.ToUpper(strings.TrimSpace(req.RoleName))
+	if group.GetCustomRole(roleName) == nil {
+		return nil, ErrCustomRoleNotFound
+	}
+
+	for _, member := range group.Members {
+		if strings.ToUpper(strings.TrimSpace(member.Role)) == roleName {
+			return nil, ErrCustomRoleInUse
+		}
+	}
+
+	remainingRoles := []GroupRole{}
+	for _, customRole := range group.CustomRoles {
+		if customRole.Name != roleName {
+			remainingRoles = append(remainingRoles, customRole)
+		}
+	}
+	group.CustomRoles = remainingRoles
+	group.SetUpdatedAtNow()
+
+	updatedGroup, err := s.GroupRepository.UpdateGroup(ctx, group)
+	if err != nil {
+		logger.Error("failed-to-update-group-in-repository", zap.Error(err))
+		return nil, ErrDatabaseError
+	}
+
+	if s.AuditService != nil {
+		s.AuditService.LogAuditEvent(ctx, &audit.LogAuditEventRequest{
+			ActorId:    req.DeletedByID,
+			TargetType: "group",
+			Domain:     "group",
+			Action:     "group.role.deleted",
+			TargetId:   group.ID,
+			Details: map[string]interface{}{
+				"role_name": roleName,
+			},
+		})
+	}
+
+	return &DeleteGroupRoleResponse{Group: updatedGroup}, nil
+}
+
+// checkPermissionsHeld ensures the actor holds every one of the permissions in the group,
+// so roles cannot be used to hand out more than the actor has themselves.
+func (s *Service) checkPermissionsHeld(ctx context.Context, groupID, actorID string, permissions []string) error {
+	if strings.TrimSpace(actorID) == "" {
+		return ErrInsufficientPermissions
+	}
+
+	for _, permission := range permissions {
+		allowed, err := s.Can(ctx, actorID, groupID, permission)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrInsufficientPermissions
+		}
+	}
+
+	return nil
+}
+
+// userRolesForGroup returns the roles a user holds directly in a group. Owners hold
+// OWNER and members without a role hold MEMBER.
+func (s *Service) userRolesForGroup(group *UniversalGroup, userID string) []string {
+	if group == nil || strings.TrimSpace(userID) == "" {
+		return nil
+	}
+
+	roles := []string{}
+	if strings.TrimSpace(group.OwnerID) == userID {
+		roles = append(roles, MemberRoleOwner)
+	}
+
+	for i := range group.Members {
+		member := group.Members[i]
+		if member.ID != userID || member.Type != MemberTypeUser {
+			continue
+		}
+
+		role := strings.ToUpper(strings.TrimSpace(member.Role))
+		if role == "" {
+			role = MemberRoleMember
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles
+}
+
+// rolePermissions returns the permissions a role grants in a group. Configured roles
+// take precedence over the group's custom roles.
+func (s *Service) rolePermissions(group *UniversalGroup, role string) []string {
+	if permissions, found := s.Config.GetRolePermissions(group.Type, role); found {
+		return permissions
+	}
+
+	if customRole := group.GetCustomRole(role); customRole != nil {
+		return customRole.Permissions
+	}
+
+	return nil
+}
+
+// resolveUserPermissionsForGroup returns the permissions the user holds directly in the
+// group and the subset of them that also applies to the group's descendants
+func (s *Service) resolveUserPermissionsForGroup(group *UniversalGroup, userID string) (map[string]struct{}, map[string]struct{}) {
+	directPermissions := map[string]struct{}{}
+	inheritedPermissions := map[string]struct{}{}
+
+	inheritance := s.Config.GetPermissionInheritance()
+	for _, role := range s.userRolesForGroup(group, userID) {
+		permissions := s.rolePermissions(group, role)
+
+		inherits := inheritance == PermissionInheritanceAll
+		for _, permission := range permissions {
+			directPermissions[permission] = struct{}{}
+			if inheritance == PermissionInheritanceManagers && permission == PermissionMembersManage {
+				inherits = true
+			}
+		}
+
+		if !inherits {
+			continue
+		}
+
+		for _, permission := range permissions {
+			inheritedPermissions[permission] = struct{}{}
+		}
+	}
+
+	return directPermissions, inheritedPermissions
+}
+
+// mergePermissions adds the permissions in the set to the list, returning a sorted list
+// without duplicates
+func mergePermissions(permissions []string, permissionSet map[string]struct{}) []string {
+	merged := append([]string{}, permissions...)
+	for permission := range permissionSet {
+		merged = append(merged, permission)
+	}
+
+	return uniqueSortedStrings(merged)
+}
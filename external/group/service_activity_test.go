@@ -0,0 +1,317 @@
+package group_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// mockAuditLogReader serves audit entries the way the audit repository does, newest first
+type mockAuditLogReader struct {
+	entries      []audit.AuditLogEntry
+	lastRequest  *audit.GetAuditLogEventsRequest
+	returnsError error
+}
+
+func (m *mockAuditLogReader) GetAuditLogEvents(ctx context.Context, r *audit.GetAuditLogEventsRequest) (*audit.GetAuditLogEventsResponse, error) {
+	m.lastRequest = r
+	if m.returnsError != nil {
+		return nil, m.returnsError
+	}
+
+	contains := func(values []string, value string) bool {
+		for _, candidate := range values {
+			if candidate == value {
+				return true
+			}
+		}
+		return len(values) == 0
+	}
+
+	actions := []string{}
+	for _, action := range r.Actions {
+		actions = append(actions, string(action))
+	}
+
+	sorted := append([]audit.AuditLogEntry{}, m.entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ActionAt != sorted[j].ActionAt {
+			return sorted[i].ActionAt > sorted[j].ActionAt
+		}
+		return sorted[i].Id > sorted[j].Id
+	})
+
+	entries := []audit.AuditLogEntry{}
+	for _, entry := range sorted {
+		if !contains(r.Domains, entry.Domain) || !contains(r.TargetIds, entry.TargetId) || !contains(actions, string(entry.Action)) {
+			continue
+		}
+		if r.ActorId != "" && entry.ActorId != r.ActorId {
+			continue
+		}
+		if r.BeforeActionAt != "" && (entry.ActionAt > r.BeforeActionAt || (entry.ActionAt == r.BeforeActionAt && entry.Id >= r.BeforeId)) {
+			continue
+		}
+		if r.Limit > 0 && len(entries) == r.Limit {
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	return &audit.GetAuditLogEventsResponse{Entries: entries}, nil
+}
+
+type activityTestHarness struct {
+	service *group.Service
+	reader  *mockAuditLogReader
+	groups  map[string]*group.UniversalGroup
+}
+
+// newActivityTestHarness builds org-1 (owner-1, member-1) > team-1, recording audit events
+// so they can be read back through the activity feed
+func newActivityTestHarness(t *testing.T) *activityTestHarness {
+	t.Helper()
+
+	harness := &activityTestHarness{
+		reader: &mockAuditLogReader{},
+		groups: map[string]*group.UniversalGroup{
+			"org-1": {
+				ID:      "org-1",
+				Name:    "acme",
+				Type:    group.GroupTypeOrganisation,
+				OwnerID: "owner-1",
+				Members: []group.Member{
+					{ID: "owner-1", Type: group.MemberTypeUser, Role: group.MemberRoleAdmin},
+					{ID: "member-1", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+				},
+			},
+			"team-1": {
+				ID:            "team-1",
+				Name:          "platform",
+				Type:          group.GroupTypeTeam,
+				ParentGroupID: "org-1",
+				Lineage:       []string{"org-1"},
+			},
+		},
+	}
+
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			grp, ok := harness.groups[id]
+			if !ok {
+				return nil, group.ErrResourceNotFound
+			}
+			return grp, nil
+		},
+		getGroupsByLineageAncestorFunc: func(ctx context.Context, ancestorGroupID string) ([]group.UniversalGroup, error) {
+			return []group.UniversalGroup{*harness.groups["team-1"]}, nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			harness.groups[grp.ID] = grp
+			return grp, nil
+		},
+	}
+
+	auditService := &mockAuditService{
+		logAuditEventFunc: func(ctx context.Context, r *audit.LogAuditEventRequest) error {
+			harness.record(r.ActorId, r.Action, r.TargetId, r.Details)
+			return nil
+		},
+	}
+
+	harness.service = newTestService(repo, auditService).WithAuditLogReader(harness.reader)
+	return harness
+}
+
+// record stores an audit entry a second after the previous one
+func (h *activityTestHarness) record(actorID string, action audit.AuditAction, targetID string, details interface{}) {
+	position := len(h.reader.entries) + 1
+	h.reader.entries = append(h.reader.entries, audit.AuditLogEntry{
+		Id:         fmt.Sprintf("event-%d", position),
+		ActorId:    actorID,
+		Action:     action,
+		TargetId:   targetID,
+		TargetType: "group",
+		Domain:     "group",
+		ActionAt:   fmt.Sprintf("2026-01-01T00:00:%02d", position),
+		Details:    details,
+	})
+}
+
+func TestService_GetGroupActivity(t *testing.T) {
+	t.Parallel()
+
+	harness := newActivityTestHarness(t)
+
+	_, err := harness.service.UpdateMemberRole(context.Background(), &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "member-1", NewRole: group.MemberRoleAdmin, ActorID: "owner-1"})
+	require.NoError(t, err)
+	harness.record("owner-1", "group.updated", "team-1", nil)
+
+	// events read back from the audit store hold their details as documents
+	harness.record("", "group.member.invited", "org-1", bson.D{
+		{Key: "invite_email", Value: "new@example.com"},
+		{Key: "invited_by_id", Value: "member-1"},
+	})
+
+	response, err := harness.service.GetGroupActivity(context.Background(), &group.GetGroupActivityRequest{GroupID: "org-1"})
+	require.NoError(t, err)
+	require.Len(t, response.Activities, 2)
+	assert.Empty(t, response.NextCursor)
+
+	invited := response.Activities[0]
+	assert.Equal(t, "group.member.invited", invited.Action)
+	assert.Equal(t, "member-1", invited.ActorID)
+	assert.Equal(t, "new@example.com", invited.SubjectID)
+	assert.Equal(t, "member-1 invited new@example.com to acme", invited.Summary)
+
+	roleUpdated := response.Activities[1]
+	assert.Equal(t, "owner-1", roleUpdated.ActorID)
+	assert.Equal(t, "member-1", roleUpdated.SubjectID)
+	assert.Equal(t, "acme", roleUpdated.GroupName)
+	assert.Equal(t, "owner-1 changed the role of member-1 in acme to ADMIN", roleUpdated.Summary)
+
+	assert.Equal(t, []string{"group"}, harness.reader.lastRequest.Domains)
+	assert.Equal(t, []string{"org-1"}, harness.reader.lastRequest.TargetIds)
+}
+
+func TestService_GetGroupActivityIncludingDescendants(t *testing.T) {
+	t.Parallel()
+
+	harness := newActivityTestHarness(t)
+	harness.record("owner-1", "group.created", "org-1", nil)
+	harness.record("owner-1", "group.updated", "team-1", nil)
+	harness.record("member-1", "group.updated", "org-1", nil)
+
+	response, err := harness.service.GetGroupActivity(context.Background(), &group.GetGroupActivityRequest{
+		GroupID:            "org-1",
+		IncludeDescendants: true,
+		ActorID:            "owner-1",
+		Actions:            []string{"group.updated", " "},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Activities, 1)
+	assert.Equal(t, "team-1", response.Activities[0].GroupID)
+	assert.Equal(t, "owner-1 updated platform", response.Activities[0].Summary)
+
+	assert.Equal(t, []string{"org-1", "team-1"}, harness.reader.lastRequest.TargetIds)
+	assert.Equal(t, []audit.AuditAction{"group.updated"}, harness.reader.lastRequest.Actions)
+}
+
+func TestService_GetGroupActivityPagination(t *testing.T) {
+	t.Parallel()
+
+	harness := newActivityTestHarness(t)
+	for i := 0; i < 3; i++ {
+		harness.record("owner-1", "group.updated", "org-1", nil)
+	}
+
+	firstPage, err := harness.service.GetGroupActivity(context.Background(), &group.GetGroupActivityRequest{GroupID: "org-1", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, firstPage.Activities, 2)
+	assert.Equal(t, "event-3", firstPage.Activities[0].ID)
+	assert.Equal(t, "event-2", firstPage.Activities[1].ID)
+	require.NotEmpty(t, firstPage.NextCursor)
+
+	secondPage, err := harness.service.GetGroupActivity(context.Background(), &group.GetGroupActivityRequest{GroupID: "org-1", Limit: 2, Cursor: firstPage.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, secondPage.Activities, 1)
+	assert.Equal(t, "event-1", secondPage.Activities[0].ID)
+	assert.Empty(t, secondPage.NextCursor)
+}
+
+func TestService_GetGroupActivityFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		request       *group.GetGroupActivityRequest
+		withoutReader bool
+		expectedError error
+	}{
+		{
+			name:          "Failure - activity feed is not enabled",
+			request:       &group.GetGroupActivityRequest{GroupID: "org-1"},
+			withoutReader: true,
+			expectedError: group.ErrActivityFeedNotEnabled,
+		},
+		{
+			name:          "Failure - cursor is not valid",
+			request:       &group.GetGroupActivityRequest{GroupID: "org-1", Cursor: "not-a-cursor"},
+			expectedError: group.ErrInvalidActivityCursor,
+		},
+		{
+			name:          "Failure - time range is not RFC 3339",
+			request:       &group.GetGroupActivityRequest{GroupID: "org-1", From: "yesterday"},
+			expectedError: group.ErrInvalidActivityTimeRange,
+		},
+		{
+			name:          "Failure - time range ends before it starts",
+			request:       &group.GetGroupActivityRequest{GroupID: "org-1", From: "2026-02-01T00:00:00Z", To: "2026-01-01T00:00:00Z"},
+			expectedError: group.ErrInvalidActivityTimeRange,
+		},
+		{
+			name:          "Failure - group not found",
+			request:       &group.GetGroupActivityRequest{GroupID: "missing"},
+			expectedError: group.ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			harness := newActivityTestHarness(t)
+			if tt.withoutReader {
+				harness.service.AuditLogReader = nil
+			}
+
+			_, err := harness.service.GetGroupActivity(context.Background(), tt.request)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestDescribeGroupActivity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		activity group.GroupActivity
+		expected string
+	}{
+		{
+			name:     "Success - uses display names",
+			activity: group.GroupActivity{Action: "group.member.added", ActorID: "owner-1", SubjectID: "member-1", GroupName: "acme", Details: map[string]interface{}{"member": map[string]interface{}{"id": "member-1", "role": "MEMBER"}}},
+			expected: "Ada Lovelace added Grace Hopper to acme as MEMBER",
+		},
+		{
+			name:     "Success - system events",
+			activity: group.GroupActivity{Action: "group.member.invite.expired", ActorID: audit.AuditActorIdSystem, SubjectID: "new@example.com", GroupID: "org-1"},
+			expected: "The invitation for new@example.com to org-1 expired",
+		},
+		{
+			name:     "Success - unknown actions",
+			activity: group.GroupActivity{Action: "group.exported", GroupName: "acme"},
+			expected: "Someone performed group.exported on acme",
+		},
+	}
+
+	displayNames := map[string]string{"owner-1": "Ada Lovelace", "member-1": "Grace Hopper"}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, group.DescribeGroupActivity(&tt.activity, displayNames))
+		})
+	}
+}
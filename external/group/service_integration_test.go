@@ -0,0 +1,239 @@
+package group_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// mockIntegrationNotifier records the events delivered to a group's integrations. When
+// release is set deliveries wait for it to be closed
+type mockIntegrationNotifier struct {
+	mu      sync.Mutex
+	groups  []*group.UniversalGroup
+	events  []*group.IntegrationEvent
+	ctxErrs []error
+	err     error
+	release chan struct{}
+}
+
+func (m *mockIntegrationNotifier) NotifyGroupEvent(ctx context.Context, grp *group.UniversalGroup, event *group.IntegrationEvent) error {
+	if m.release != nil {
+		<-m.release
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups = append(m.groups, grp)
+	m.events = append(m.events, event)
+	m.ctxErrs = append(m.ctxErrs, ctx.Err())
+	return m.err
+}
+
+func (m *mockIntegrationNotifier) delivered() []*group.IntegrationEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*group.IntegrationEvent{}, m.events...)
+}
+
+func TestService_AddMember_NotifiesIntegrations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		integrations   *group.Integrations
+		notifierErr    error
+		expectedEvents int
+	}{
+		{
+			name:           "Success - member joined is delivered to the group's integrations",
+			integrations:   &group.Integrations{Slack: &group.SlackIntegration{DisplayID: "C123"}},
+			expectedEvents: 1,
+		},
+		{
+			name:           "Success - delivery failures do not fail adding the member",
+			integrations:   &group.Integrations{Slack: &group.SlackIntegration{DisplayID: "C123"}},
+			notifierErr:    errors.New("slack unavailable"),
+			expectedEvents: 1,
+		},
+		{
+			name:           "Success - groups without integrations are not notified",
+			expectedEvents: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			existingGroup := &group.UniversalGroup{
+				ID:           testGroupID,
+				Name:         testGroupName,
+				Type:         testGroupType,
+				Status:       group.GroupStatusActive,
+				Integrations: tt.integrations,
+			}
+
+			repo := &mockGroupRepository{
+				getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+					return existingGroup, nil
+				},
+				updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+					return grp, nil
+				},
+			}
+
+			notifier := &mockIntegrationNotifier{err: tt.notifierErr}
+			svc := newTestService(repo, &mockAuditService{}).WithIntegrationNotifier(notifier)
+
+			response, err := svc.AddMember(context.Background(), &group.AddMemberRequest{
+				GroupID:  testGroupID,
+				MemberID: "user-2",
+				Type:     group.MemberTypeUser,
+				Role:     group.MemberRoleMember,
+				ActorID:  testUserID,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, response)
+
+			if tt.expectedEvents == 0 {
+				assert.Empty(t, notifier.delivered())
+				return
+			}
+
+			require.Eventually(t, func() bool { return len(notifier.delivered()) == tt.expectedEvents }, time.Second, 5*time.Millisecond)
+
+			event := notifier.delivered()[0]
+			assert.Equal(t, group.IntegrationEventMemberJoined, event.Type)
+			assert.Equal(t, testUserID, event.ActorID)
+			require.NotNil(t, event.Member)
+			assert.Equal(t, "user-2", event.Member.ID)
+			assert.NotEmpty(t, event.OccurredAt)
+		})
+	}
+}
+
+func TestService_AddMember_DeliversIntegrationEventsInTheBackground(t *testing.T) {
+	t.Parallel()
+
+	existingGroup := &group.UniversalGroup{
+		ID:           testGroupID,
+		Name:         testGroupName,
+		Type:         testGroupType,
+		Status:       group.GroupStatusActive,
+		Integrations: &group.Integrations{Slack: &group.SlackIntegration{DisplayID: "C123"}},
+	}
+
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			return existingGroup, nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			return grp, nil
+		},
+	}
+
+	notifier := &mockIntegrationNotifier{release: make(chan struct{})}
+	svc := newTestService(repo, &mockAuditService{}).WithIntegrationNotifier(notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the notifier is blocked, so adding the member only returns if delivery is not awaited
+	_, err := svc.AddMember(ctx, &group.AddMemberRequest{
+		GroupID:  testGroupID,
+		MemberID: "user-2",
+		Type:     group.MemberTypeUser,
+		Role:     group.MemberRoleMember,
+		ActorID:  testUserID,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, notifier.delivered())
+
+	// delivery outlives the request that raised the event
+	cancel()
+	close(notifier.release)
+
+	require.Eventually(t, func() bool { return len(notifier.delivered()) == 1 }, time.Second, 5*time.Millisecond)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	assert.NoError(t, notifier.ctxErrs[0])
+}
+
+func TestService_AddMember_DeliversACopyOfTheGroup(t *testing.T) {
+	t.Parallel()
+
+	existingGroup := &group.UniversalGroup{
+		ID:     testGroupID,
+		Name:   testGroupName,
+		Type:   testGroupType,
+		Status: group.GroupStatusActive,
+		Integrations: &group.Integrations{
+			Slack:  &group.SlackIntegration{DisplayID: "C123", Events: []string{group.IntegrationEventMemberJoined}},
+			Custom: map[string]interface{}{"webhook": "https://example.com/hook"},
+		},
+	}
+
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			return existingGroup, nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			return grp, nil
+		},
+	}
+
+	notifier := &mockIntegrationNotifier{release: make(chan struct{})}
+	svc := newTestService(repo, &mockAuditService{}).WithIntegrationNotifier(notifier)
+
+	_, err := svc.AddMember(context.Background(), &group.AddMemberRequest{
+		GroupID:  testGroupID,
+		MemberID: "user-2",
+		Type:     group.MemberTypeUser,
+		Role:     group.MemberRoleMember,
+		ActorID:  testUserID,
+	})
+	require.NoError(t, err)
+
+	// the caller keeps changing the group while the event waits to be delivered
+	existingGroup.Members = append(existingGroup.Members, group.Member{ID: "user-3", Type: group.MemberTypeUser})
+	existingGroup.Integrations.Slack.Events[0] = group.IntegrationEventInviteAccepted
+	existingGroup.Integrations.Custom["webhook"] = "https://example.com/changed"
+	close(notifier.release)
+
+	require.Eventually(t, func() bool { return len(notifier.delivered()) == 1 }, time.Second, 5*time.Millisecond)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	delivered := notifier.groups[0]
+	assert.Equal(t, testGroupID, delivered.ID)
+	assert.Empty(t, delivered.Members)
+	require.NotNil(t, delivered.Integrations)
+	require.NotNil(t, delivered.Integrations.Slack)
+	assert.Equal(t, []string{group.IntegrationEventMemberJoined}, delivered.Integrations.Slack.Events)
+	assert.Equal(t, "https://example.com/hook", delivered.Integrations.Custom["webhook"])
+}
+
+func TestSlackIntegration_IsSubscribedTo(t *testing.T) {
+	t.Parallel()
+
+	defaultEvents := []string{group.IntegrationEventMemberJoined}
+
+	var missing *group.SlackIntegration
+	assert.False(t, missing.IsSubscribedTo(group.IntegrationEventMemberJoined, defaultEvents))
+
+	defaults := &group.SlackIntegration{}
+	assert.True(t, defaults.IsSubscribedTo(group.IntegrationEventMemberJoined, defaultEvents))
+	assert.False(t, defaults.IsSubscribedTo(group.IntegrationEventReminderDue, defaultEvents))
+
+	subscribed := &group.SlackIntegration{Events: []string{" reminder_due "}}
+	assert.True(t, subscribed.IsSubscribedTo(group.IntegrationEventReminderDue, defaultEvents))
+	assert.False(t, subscribed.IsSubscribedTo(group.IntegrationEventMemberJoined, defaultEvents))
+}
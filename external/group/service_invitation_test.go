@@ -0,0 +1,298 @@
+package group_test
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/emailmanager"
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// mockInvitationTimeProvider returns a fixed time that tests can move forward
+type mockInvitationTimeProvider struct {
+	now time.Time
+}
+
+func (m *mockInvitationTimeProvider) Now() time.Time {
+	return m.now
+}
+
+func (m *mockInvitationTimeProvider) NowUTC() string {
+	return m.now.UTC().Format(time.RFC3339)
+}
+
+// mockInvitationEmailManager records invitation emails
+type mockInvitationEmailManager struct {
+	emails []*emailmanager.SendCustomEmailRequest
+}
+
+func (m *mockInvitationEmailManager) SendCustomEmail(ctx context.Context, req *emailmanager.SendCustomEmailRequest) error {
+	m.emails = append(m.emails, req)
+	return nil
+}
+
+var invitationLinkPattern = regexp.MustCompile(`href="([^"]+)"`)
+
+// lastInvitationToken extracts the token from the accept link in the latest email
+func (m *mockInvitationEmailManager) lastInvitationToken(t *testing.T) string {
+	t.Helper()
+	require.NotEmpty(t, m.emails)
+
+	match := invitationLinkPattern.FindStringSubmatch(m.emails[len(m.emails)-1].EmailBody)
+	require.Len(t, match, 2)
+
+	link, err := url.Parse(match[1])
+	require.NoError(t, err)
+
+	return link.Query().Get(group.InvitationTokenQueryParam)
+}
+
+type invitationTestHarness struct {
+	service      *group.Service
+	groups       map[string]*group.UniversalGroup
+	emails       *mockInvitationEmailManager
+	timeProvider *mockInvitationTimeProvider
+	auditEvents  []*audit.LogAuditEventRequest
+}
+
+func newInvitationTestHarness(t *testing.T) *invitationTestHarness {
+	t.Helper()
+
+	harness := &invitationTestHarness{
+		groups: map[string]*group.UniversalGroup{
+			"group-1": {ID: "group-1", Name: "engineering", RawName: "Engineering", Type: group.GroupTypeTeam, Status: group.GroupStatusActive},
+		},
+		emails:       &mockInvitationEmailManager{},
+		timeProvider: &mockInvitationTimeProvider{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+	}
+
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			grp, ok := harness.groups[id]
+			if !ok {
+				return nil, group.ErrResourceNotFound
+			}
+			return grp, nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			harness.groups[grp.ID] = grp
+			return grp, nil
+		},
+		getGroupsWithPendingInvitesFunc: func(ctx context.Context) ([]group.UniversalGroup, error) {
+			results := []group.UniversalGroup{}
+			for _, grp := range harness.groups {
+				results = append(results, *grp)
+			}
+			return results, nil
+		},
+	}
+
+	auditService := &mockAuditService{
+		logAuditEventFunc: func(ctx context.Context, r *audit.LogAuditEventRequest) error {
+			harness.auditEvents = append(harness.auditEvents, r)
+			return nil
+		},
+	}
+
+	svc, err := group.NewService(
+		repo,
+		auditService,
+		group.DefaultGroupConfig(),
+		group.NewDefaultIDGenerator(),
+		harness.timeProvider,
+		group.NewDefaultStringUtils(),
+	)
+	require.NoError(t, err)
+
+	harness.service = svc.
+		WithInvitations(group.DefaultInvitationConfig("test-signing-secret", "https://app.example.com/invitations/accept")).
+		WithEmailManager(harness.emails)
+
+	return harness
+}
+
+func (h *invitationTestHarness) invite(t *testing.T, email string) string {
+	t.Helper()
+
+	resp, err := h.service.InviteUser(context.Background(), &group.InviteUserRequest{
+		GroupID:     "group-1",
+		InviteEmail: email,
+		InvitedByID: "admin-1",
+	})
+	require.NoError(t, err)
+	require.True(t, resp.EmailSent)
+	assert.Equal(t, "2026-03-08T09:00:00Z", resp.ExpiresAt)
+
+	return h.emails.lastInvitationToken(t)
+}
+
+func (h *invitationTestHarness) hasAuditAction(action string) bool {
+	for _, event := range h.auditEvents {
+		if string(event.Action) == action {
+			return true
+		}
+	}
+	return false
+}
+
+func TestService_InviteUserSendsInvitationEmail(t *testing.T) {
+	t.Parallel()
+
+	harness := newInvitationTestHarness(t)
+	token := harness.invite(t, "Invitee@Example.com")
+
+	require.Len(t, harness.emails.emails, 1)
+	email := harness.emails.emails[0]
+	assert.Equal(t, "invitee@example.com", email.EmailTo)
+	assert.Equal(t, "You have been invited to join Engineering", email.EmailSubject)
+	assert.Contains(t, email.EmailBody, "https://app.example.com/invitations/accept?token=")
+	assert.NotEmpty(t, token)
+}
+
+func TestService_AcceptInviteByToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mutateToken   func(token string) string
+		userEmail     string
+		advanceBy     time.Duration
+		expectedError error
+	}{
+		{
+			name:      "Success - accepts invitation from link",
+			userEmail: "invitee@example.com",
+		},
+		{
+			name:          "Failure - tampered token",
+			mutateToken:   func(token string) string { return token + "x" },
+			userEmail:     "invitee@example.com",
+			expectedError: group.ErrInvalidInvitationToken,
+		},
+		{
+			name:          "Failure - signed up with a different email",
+			userEmail:     "someone@example.com",
+			expectedError: group.ErrInvitationEmailMismatch,
+		},
+		{
+			name:          "Failure - invitation has expired",
+			userEmail:     "invitee@example.com",
+			advanceBy:     group.DefaultInvitationExpiry + time.Minute,
+			expectedError: group.ErrInvitationExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			harness := newInvitationTestHarness(t)
+			token := harness.invite(t, "invitee@example.com")
+			if tt.mutateToken != nil {
+				token = tt.mutateToken(token)
+			}
+			harness.timeProvider.now = harness.timeProvider.now.Add(tt.advanceBy)
+
+			resp, err := harness.service.AcceptInviteByToken(context.Background(), &group.AcceptInviteByTokenRequest{
+				Token:     token,
+				UserID:    "user-1",
+				UserEmail: tt.userEmail,
+			})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", resp.UserID)
+			assert.True(t, resp.Group.HasMember("user-1"))
+			assert.False(t, resp.Group.HasMember("invitee@example.com"))
+			assert.True(t, harness.hasAuditAction("group.member.invite.accepted"))
+		})
+	}
+}
+
+func TestService_AcceptInviteByTokenIsSingleUse(t *testing.T) {
+	t.Parallel()
+
+	harness := newInvitationTestHarness(t)
+	token := harness.invite(t, "invitee@example.com")
+
+	request := &group.AcceptInviteByTokenRequest{Token: token, UserID: "user-1", UserEmail: "invitee@example.com"}
+
+	_, err := harness.service.AcceptInviteByToken(context.Background(), request)
+	require.NoError(t, err)
+
+	_, err = harness.service.AcceptInviteByToken(context.Background(), request)
+	assert.ErrorIs(t, err, group.ErrInvalidInvitationToken)
+}
+
+func TestService_ResendInvite(t *testing.T) {
+	t.Parallel()
+
+	harness := newInvitationTestHarness(t)
+	firstToken := harness.invite(t, "invitee@example.com")
+
+	_, err := harness.service.ResendInvite(context.Background(), &group.ResendInviteRequest{GroupID: "group-1", InviteEmail: "invitee@example.com"})
+	assert.ErrorIs(t, err, group.ErrInvitationResendRateLimited)
+
+	harness.timeProvider.now = harness.timeProvider.now.Add(group.DefaultInvitationResendCooldown)
+
+	resp, err := harness.service.ResendInvite(context.Background(), &group.ResendInviteRequest{GroupID: "group-1", InviteEmail: "invitee@example.com", ResentByID: "admin-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.SendCount)
+	assert.Len(t, harness.emails.emails, 2)
+	assert.True(t, harness.hasAuditAction("group.member.invite.resent"))
+
+	// the earlier link stops working once a new one is sent
+	_, err = harness.service.AcceptInviteByToken(context.Background(), &group.AcceptInviteByTokenRequest{Token: firstToken, UserID: "user-1", UserEmail: "invitee@example.com"})
+	assert.ErrorIs(t, err, group.ErrInvalidInvitationToken)
+
+	_, err = harness.service.AcceptInviteByToken(context.Background(), &group.AcceptInviteByTokenRequest{Token: harness.emails.lastInvitationToken(t), UserID: "user-1", UserEmail: "invitee@example.com"})
+	assert.NoError(t, err)
+}
+
+func TestService_ResendInviteRequiresEmailDelivery(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(&mockGroupRepository{}, &mockAuditService{})
+
+	_, err := svc.ResendInvite(context.Background(), &group.ResendInviteRequest{GroupID: "group-1", InviteEmail: "invitee@example.com"})
+	assert.ErrorIs(t, err, group.ErrInvitationsNotEnabled)
+}
+
+func TestService_ExpireStaleInvites(t *testing.T) {
+	t.Parallel()
+
+	harness := newInvitationTestHarness(t)
+	harness.invite(t, "invitee@example.com")
+
+	resp, err := harness.service.ExpireStaleInvites(context.Background(), &group.ExpireStaleInvitesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.ExpiredCount)
+
+	harness.timeProvider.now = harness.timeProvider.now.Add(group.DefaultInvitationExpiry + time.Minute)
+
+	resp, err = harness.service.ExpireStaleInvites(context.Background(), &group.ExpireStaleInvitesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.ExpiredCount)
+	assert.Equal(t, []string{"group-1"}, resp.GroupIDs)
+	assert.True(t, harness.hasAuditAction("group.member.invite.expired"))
+	assert.Equal(t, group.MemberInvitationStateExpired, harness.groups["group-1"].Members[0].InvitationState)
+
+	// expired invitations can be resent and accepted with the new link
+	_, err = harness.service.ResendInvite(context.Background(), &group.ResendInviteRequest{GroupID: "group-1", InviteEmail: "invitee@example.com"})
+	require.NoError(t, err)
+
+	_, err = harness.service.AcceptInviteByToken(context.Background(), &group.AcceptInviteByTokenRequest{Token: harness.emails.lastInvitationToken(t), UserID: "user-1", UserEmail: "invitee@example.com"})
+	assert.NoError(t, err)
+}
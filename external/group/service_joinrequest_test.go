@@ -0,0 +1,359 @@
+package group_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// mockJoinRequestRepository implements group.JoinRequestRepository in memory
+type mockJoinRequestRepository struct {
+	joinRequests map[string]*group.GroupJoinRequest
+}
+
+func newMockJoinRequestRepository(joinRequests ...*group.GroupJoinRequest) *mockJoinRequestRepository {
+	repo := &mockJoinRequestRepository{joinRequests: map[string]*group.GroupJoinRequest{}}
+	for _, joinRequest := range joinRequests {
+		repo.joinRequests[joinRequest.ID] = joinRequest
+	}
+	return repo
+}
+
+func (m *mockJoinRequestRepository) CreateJoinRequest(ctx context.Context, joinRequest *group.GroupJoinRequest) (*group.GroupJoinRequest, error) {
+	m.joinRequests[joinRequest.ID] = joinRequest
+	return joinRequest, nil
+}
+
+func (m *mockJoinRequestRepository) GetJoinRequestByID(ctx context.Context, id string) (*group.GroupJoinRequest, error) {
+	joinRequest, ok := m.joinRequests[id]
+	if !ok {
+		return nil, group.ErrJoinRequestNotFound
+	}
+	return joinRequest, nil
+}
+
+func (m *mockJoinRequestRepository) GetJoinRequests(ctx context.Context, req *group.GetJoinRequestsRequest) ([]group.GroupJoinRequest, error) {
+	results := []group.GroupJoinRequest{}
+	for _, joinRequest := range m.joinRequests {
+		if (req.GroupID == "" || joinRequest.GroupID == req.GroupID) &&
+			(req.UserID == "" || joinRequest.UserID == req.UserID) &&
+			(req.Status == "" || joinRequest.Status == req.Status) {
+			results = append(results, *joinRequest)
+		}
+	}
+	return results, nil
+}
+
+func (m *mockJoinRequestRepository) UpdateJoinRequest(ctx context.Context, joinRequest *group.GroupJoinRequest) (*group.GroupJoinRequest, error) {
+	m.joinRequests[joinRequest.ID] = joinRequest
+	return joinRequest, nil
+}
+
+func newJoinRequestTestService(groups map[string]*group.UniversalGroup, joinRequestRepo *mockJoinRequestRepository) *group.Service {
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			grp, ok := groups[id]
+			if !ok {
+				return nil, group.ErrResourceNotFound
+			}
+			return grp, nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			groups[grp.ID] = grp
+			return grp, nil
+		},
+	}
+
+	return newTestService(repo, &mockAuditService{}).WithJoinRequestRepository(joinRequestRepo)
+}
+
+func TestService_RequestToJoinGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		groups         map[string]*group.UniversalGroup
+		existing       []*group.GroupJoinRequest
+		groupID        string
+		expectedErr    error
+		expectedStatus string
+	}{
+		{
+			name: "Success - open public group adds the requester straight away",
+			groups: map[string]*group.UniversalGroup{
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive},
+			},
+			groupID:        "group-1",
+			expectedStatus: group.JoinRequestStatusApproved,
+		},
+		{
+			name: "Success - group requiring approval keeps the request pending",
+			groups: map[string]*group.UniversalGroup{
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive, Settings: &group.GroupSettings{RequireApproval: true}},
+			},
+			groupID:        "group-1",
+			expectedStatus: group.JoinRequestStatusPending,
+		},
+		{
+			name: "Success - internal group is joinable by members of its root group",
+			groups: map[string]*group.UniversalGroup{
+				"root":    {ID: "root", Type: group.GroupTypeOrganisation, Status: group.GroupStatusActive, Members: []group.Member{{ID: testUserID, Type: group.MemberTypeUser}}},
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive, Lineage: []string{"root"}, Settings: &group.GroupSettings{Visibility: group.VisibilityInternal, RequireApproval: true}},
+			},
+			groupID:        "group-1",
+			expectedStatus: group.JoinRequestStatusPending,
+		},
+		{
+			name: "Failure - internal group is not joinable from outside its root group",
+			groups: map[string]*group.UniversalGroup{
+				"root":    {ID: "root", Type: group.GroupTypeOrganisation, Status: group.GroupStatusActive},
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive, Lineage: []string{"root"}, Settings: &group.GroupSettings{Visibility: group.VisibilityInternal}},
+			},
+			groupID:     "group-1",
+			expectedErr: group.ErrGroupNotJoinable,
+		},
+		{
+			name: "Failure - private group is not joinable",
+			groups: map[string]*group.UniversalGroup{
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive, Settings: &group.GroupSettings{Visibility: group.VisibilityPrivate}},
+			},
+			groupID:     "group-1",
+			expectedErr: group.ErrGroupNotJoinable,
+		},
+		{
+			name: "Failure - archived group is not joinable",
+			groups: map[string]*group.UniversalGroup{
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusArchived},
+			},
+			groupID:     "group-1",
+			expectedErr: group.ErrGroupNotJoinable,
+		},
+		{
+			name: "Failure - requester is already a member",
+			groups: map[string]*group.UniversalGroup{
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive, Members: []group.Member{{ID: testUserID, Type: group.MemberTypeUser}}},
+			},
+			groupID:     "group-1",
+			expectedErr: group.ErrMemberAlreadyExists,
+		},
+		{
+			name: "Failure - requester already has a pending request",
+			groups: map[string]*group.UniversalGroup{
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive, Settings: &group.GroupSettings{RequireApproval: true}},
+			},
+			existing:    []*group.GroupJoinRequest{{ID: "jr-1", GroupID: "group-1", UserID: testUserID, Status: group.JoinRequestStatusPending}},
+			groupID:     "group-1",
+			expectedErr: group.ErrJoinRequestAlreadyExists,
+		},
+		{
+			name: "Failure - open group is full",
+			groups: map[string]*group.UniversalGroup{
+				"group-1": {ID: "group-1", Type: group.GroupTypeTeam, Status: group.GroupStatusActive, Members: []group.Member{{ID: "other-user", Type: group.MemberTypeUser}}, Settings: &group.GroupSettings{MaxMembers: 1}},
+			},
+			groupID:     "group-1",
+			expectedErr: group.ErrMaxMembersReached,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			joinRequestRepo := newMockJoinRequestRepository(tt.existing...)
+			service := newJoinRequestTestService(tt.groups, joinRequestRepo)
+
+			response, err := service.RequestToJoinGroup(context.Background(), &group.RequestToJoinGroupRequest{
+				GroupID: tt.groupID,
+				UserID:  testUserID,
+				Message: "Please let me in",
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, response.JoinRequest.Status)
+			assert.Equal(t, "Please let me in", response.JoinRequest.Message)
+			assert.Equal(t, tt.expectedStatus == group.JoinRequestStatusApproved, tt.groups[tt.groupID].HasMember(testUserID))
+		})
+	}
+}
+
+func TestService_ReviewJoinRequest(t *testing.T) {
+	t.Parallel()
+
+	newGroups := func() map[string]*group.UniversalGroup {
+		return map[string]*group.UniversalGroup{
+			"group-1": {
+				ID:     "group-1",
+				Type:   group.GroupTypeTeam,
+				Status: group.GroupStatusActive,
+				Members: []group.Member{
+					{ID: "group-admin", Type: group.MemberTypeUser, Role: group.MemberRoleAdmin},
+					{ID: "group-member", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+				},
+				Settings: &group.GroupSettings{RequireApproval: true, MaxMembers: 3},
+			},
+		}
+	}
+	newPendingRequest := func() *group.GroupJoinRequest {
+		return &group.GroupJoinRequest{ID: "jr-1", GroupID: "group-1", UserID: testUserID, Status: group.JoinRequestStatusPending}
+	}
+
+	t.Run("Success - group admin approves and requester is added", func(t *testing.T) {
+		t.Parallel()
+
+		groups := newGroups()
+		service := newJoinRequestTestService(groups, newMockJoinRequestRepository(newPendingRequest()))
+
+		response, err := service.ApproveJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{GroupID: "group-1", JoinRequestID: "jr-1", ReviewerID: "group-admin"})
+		require.NoError(t, err)
+
+		assert.Equal(t, group.JoinRequestStatusApproved, response.JoinRequest.Status)
+		assert.Equal(t, "group-admin", response.JoinRequest.ReviewedByID)
+		assert.NotEmpty(t, response.JoinRequest.ReviewedAt)
+
+		member, err := groups["group-1"].GetMemberByID(testUserID)
+		require.NoError(t, err)
+		assert.Equal(t, group.MemberRoleMember, member.Role)
+
+		_, err = service.DenyJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "group-admin"})
+		assert.ErrorIs(t, err, group.ErrJoinRequestNotPending)
+	})
+
+	t.Run("Success - group admin denies with a reason", func(t *testing.T) {
+		t.Parallel()
+
+		groups := newGroups()
+		service := newJoinRequestTestService(groups, newMockJoinRequestRepository(newPendingRequest()))
+
+		response, err := service.DenyJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "group-admin", Reason: "Team is full for now"})
+		require.NoError(t, err)
+
+		assert.Equal(t, group.JoinRequestStatusDenied, response.JoinRequest.Status)
+		assert.Equal(t, "Team is full for now", response.JoinRequest.DecisionReason)
+		assert.False(t, groups["group-1"].HasMember(testUserID))
+	})
+
+	t.Run("Failure - non admin member cannot review", func(t *testing.T) {
+		t.Parallel()
+
+		service := newJoinRequestTestService(newGroups(), newMockJoinRequestRepository(newPendingRequest()))
+
+		_, err := service.ApproveJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "group-member"})
+		assert.ErrorIs(t, err, group.ErrInsufficientPermissions)
+	})
+
+	t.Run("Success - platform admin can review without group admin role", func(t *testing.T) {
+		t.Parallel()
+
+		service := newJoinRequestTestService(newGroups(), newMockJoinRequestRepository(newPendingRequest()))
+
+		_, err := service.DenyJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "platform-admin", ReviewerIsPlatformAdmin: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Failure - join request belongs to another group", func(t *testing.T) {
+		t.Parallel()
+
+		service := newJoinRequestTestService(newGroups(), newMockJoinRequestRepository(newPendingRequest()))
+
+		_, err := service.ApproveJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{GroupID: "group-2", JoinRequestID: "jr-1", ReviewerID: "group-admin"})
+		assert.ErrorIs(t, err, group.ErrJoinRequestNotFound)
+	})
+
+	t.Run("Failure - approval cannot grant ownership", func(t *testing.T) {
+		t.Parallel()
+
+		groups := newGroups()
+		service := newJoinRequestTestService(groups, newMockJoinRequestRepository(newPendingRequest()))
+
+		_, err := service.ApproveJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "platform-admin", ReviewerIsPlatformAdmin: true, Role: "owner"})
+		assert.ErrorIs(t, err, group.ErrInvalidMemberRole)
+		assert.False(t, groups["group-1"].HasMember(testUserID))
+	})
+
+	t.Run("Role assignment is limited by the reviewer's role and permissions", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			role        string
+			expectedErr error
+		}{
+			{name: "Success - moderator approves as a member", role: group.MemberRoleMember},
+			{name: "Failure - moderator cannot approve as an admin", role: group.MemberRoleAdmin, expectedErr: group.ErrInsufficientPermissions},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				groups := newGroups()
+				groups["group-1"].Members = append(groups["group-1"].Members, group.Member{ID: "group-moderator", Type: group.MemberTypeUser, Role: group.MemberRoleModerator})
+				groups["group-1"].Settings.MaxMembers = 0
+				service := newJoinRequestTestService(groups, newMockJoinRequestRepository(newPendingRequest()))
+				service.Config.WithRolePermissions(group.MemberRoleModerator, group.PermissionGroupView, group.PermissionMembersView, group.PermissionJoinRequestsReview)
+
+				_, err := service.ApproveJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "group-moderator", Role: tt.role})
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+					assert.False(t, groups["group-1"].HasMember(testUserID))
+					return
+				}
+
+				require.NoError(t, err)
+				member, err := groups["group-1"].GetMemberByID(testUserID)
+				require.NoError(t, err)
+				assert.Equal(t, tt.role, member.Role)
+			})
+		}
+
+		groups := newGroups()
+		service := newJoinRequestTestService(groups, newMockJoinRequestRepository(newPendingRequest()))
+
+		_, err := service.ApproveJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "group-admin", Role: group.MemberRoleAdmin})
+		require.NoError(t, err, "admins manage members so can approve any role but owner")
+	})
+
+	t.Run("Failure - approval respects max members", func(t *testing.T) {
+		t.Parallel()
+
+		groups := newGroups()
+		groups["group-1"].Settings.MaxMembers = 2
+		joinRequestRepo := newMockJoinRequestRepository(newPendingRequest())
+		service := newJoinRequestTestService(groups, joinRequestRepo)
+
+		_, err := service.ApproveJoinRequest(context.Background(), &group.ReviewJoinRequestRequest{JoinRequestID: "jr-1", ReviewerID: "group-admin"})
+		assert.ErrorIs(t, err, group.ErrMaxMembersReached)
+		assert.Equal(t, group.JoinRequestStatusPending, joinRequestRepo.joinRequests["jr-1"].Status)
+	})
+}
+
+func TestService_CancelJoinRequest(t *testing.T) {
+	t.Parallel()
+
+	joinRequestRepo := newMockJoinRequestRepository(&group.GroupJoinRequest{ID: "jr-1", GroupID: "group-1", UserID: testUserID, Status: group.JoinRequestStatusPending})
+	service := newJoinRequestTestService(map[string]*group.UniversalGroup{}, joinRequestRepo)
+
+	_, err := service.CancelJoinRequest(context.Background(), &group.CancelJoinRequestRequest{JoinRequestID: "jr-1", UserID: "someone-else"})
+	assert.ErrorIs(t, err, group.ErrJoinRequestNotFound)
+
+	response, err := service.CancelJoinRequest(context.Background(), &group.CancelJoinRequestRequest{JoinRequestID: "jr-1", UserID: testUserID})
+	require.NoError(t, err)
+	assert.Equal(t, group.JoinRequestStatusCancelled, response.JoinRequest.Status)
+	assert.Empty(t, response.JoinRequest.ReviewedByID)
+}
+
+func TestService_JoinRequestsNotEnabled(t *testing.T) {
+	t.Parallel()
+
+	service := newTestService(&mockGroupRepository{}, &mockAuditService{})
+
+	_, err := service.RequestToJoinGroup(context.Background(), &group.RequestToJoinGroupRequest{GroupID: "group-1", UserID: testUserID})
+	assert.ErrorIs(t, err, group.ErrJoinRequestsNotEnabled)
+}
@@ -0,0 +1,574 @@
+package group_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// mockMemberImportRepository implements group.MemberImportRepository in memory, storing copies
+// so imports processed in the background can be read safely
+type mockMemberImportRepository struct {
+	mu      sync.Mutex
+	imports map[string]group.MemberImportJob
+	claims  int
+}
+
+func newMockMemberImportRepository() *mockMemberImportRepository {
+	return &mockMemberImportRepository{imports: map[string]group.MemberImportJob{}}
+}
+
+func (m *mockMemberImportRepository) CreateMemberImport(ctx context.Context, memberImport *group.MemberImportJob) (*group.MemberImportJob, error) {
+	return m.UpdateMemberImport(ctx, memberImport)
+}
+
+func (m *mockMemberImportRepository) GetMemberImportByID(ctx context.Context, id string) (*group.MemberImportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	memberImport, ok := m.imports[id]
+	if !ok {
+		return nil, group.ErrMemberImportNotFound
+	}
+	memberImport.Rows = append([]group.MemberImportRow(nil), memberImport.Rows...)
+	return &memberImport, nil
+}
+
+func (m *mockMemberImportRepository) UpdateMemberImport(ctx context.Context, memberImport *group.MemberImportJob) (*group.MemberImportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// like the repository, claimed imports are only replaced while the claim is held
+	if existing, ok := m.imports[memberImport.ID]; ok && memberImport.ClaimedBy != "" && existing.ClaimedBy != memberImport.ClaimedBy {
+		return memberImport, nil
+	}
+
+	stored := *memberImport
+	stored.Rows = append([]group.MemberImportRow(nil), memberImport.Rows...)
+	m.imports[memberImport.ID] = stored
+	return memberImport, nil
+}
+
+func (m *mockMemberImportRepository) ClaimMemberImport(ctx context.Context, id, claimedBy, staleBefore, claimedAt string) (*group.MemberImportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	memberImport, ok := m.imports[id]
+	if !ok || !isClaimableMemberImport(memberImport, staleBefore) {
+		return nil, group.ErrMemberImportAlreadyClaimed
+	}
+
+	m.claims++
+	memberImport.Status = group.MemberImportStatusRunning
+	memberImport.ClaimedBy = claimedBy
+	memberImport.HeartbeatAt = claimedAt
+	memberImport.Rows = append([]group.MemberImportRow(nil), memberImport.Rows...)
+	m.imports[id] = memberImport
+
+	claimed := memberImport
+	claimed.Rows = append([]group.MemberImportRow(nil), memberImport.Rows...)
+	return &claimed, nil
+}
+
+func (m *mockMemberImportRepository) HeartbeatMemberImport(ctx context.Context, id, claimedBy, heartbeatAt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	memberImport, ok := m.imports[id]
+	if !ok || memberImport.ClaimedBy != claimedBy || memberImport.Status != group.MemberImportStatusRunning {
+		return group.ErrMemberImportAlreadyClaimed
+	}
+
+	memberImport.HeartbeatAt = heartbeatAt
+	m.imports[id] = memberImport
+	return nil
+}
+
+func (m *mockMemberImportRepository) GetStaleMemberImports(ctx context.Context, staleBefore string) ([]group.MemberImportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	staleImports := []group.MemberImportJob{}
+	for _, memberImport := range m.imports {
+		if memberImport.IsFinished() || (memberImport.HeartbeatAt != "" && memberImport.HeartbeatAt >= staleBefore) {
+			continue
+		}
+		memberImport.Rows = append([]group.MemberImportRow(nil), memberImport.Rows...)
+		staleImports = append(staleImports, memberImport)
+	}
+	return staleImports, nil
+}
+
+// isClaimableMemberImport mirrors the repository's claim filter: pending imports, or running
+// imports without a heartbeat since staleBefore
+func isClaimableMemberImport(memberImport group.MemberImportJob, staleBefore string) bool {
+	switch memberImport.Status {
+	case group.MemberImportStatusPending:
+		return true
+	case group.MemberImportStatusRunning:
+		return memberImport.HeartbeatAt == "" || memberImport.HeartbeatAt < staleBefore
+	default:
+		return false
+	}
+}
+
+// storeMemberImport saves an import straight into the repository, as a service that stopped
+// part way through would have left it
+func (m *mockMemberImportRepository) storeMemberImport(memberImport group.MemberImportJob) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.imports[memberImport.ID] = memberImport
+}
+
+// mockMemberImportUserFinder resolves emails from a fixed set of accounts
+type mockMemberImportUserFinder map[string]string
+
+func (m mockMemberImportUserFinder) FindUserIDByEmail(ctx context.Context, email string) (string, error) {
+	return m[email], nil
+}
+
+func newMemberImportTestService(groups map[string]*group.UniversalGroup, importRepo group.MemberImportRepository, finder group.MemberImportUserFinder) *group.Service {
+	var mu sync.Mutex
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			grp, ok := groups[id]
+			if !ok {
+				return nil, group.ErrResourceNotFound
+			}
+			clone := *grp
+			clone.Members = append([]group.Member(nil), grp.Members...)
+			return &clone, nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			groups[grp.ID] = grp
+			return grp, nil
+		},
+	}
+
+	svc := newTestService(repo, &mockAuditService{})
+	if importRepo != nil || finder != nil {
+		svc.WithMemberImports(importRepo, finder)
+	}
+	return svc
+}
+
+func TestService_ImportMembers_DryRunReport(t *testing.T) {
+	t.Parallel()
+
+	groups := map[string]*group.UniversalGroup{
+		testGroupID: {
+			ID:     testGroupID,
+			Type:   group.GroupTypeOrganisation,
+			Status: group.GroupStatusActive,
+			Members: []group.Member{
+				{ID: "user-existing", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+			},
+			Settings: &group.GroupSettings{
+				MaxMembers:                   4,
+				AutoJoinByEmailDomainEnabled: true,
+				AutoActionEmailDomains:       []string{"acme.com"},
+				AutoActionDefaultMemberRole:  group.MemberRoleMember,
+			},
+		},
+	}
+	finder := mockMemberImportUserFinder{
+		"known@acme.com":       "user-known",
+		"existing@example.com": "user-existing",
+	}
+	svc := newMemberImportTestService(groups, nil, finder)
+
+	response, err := svc.ImportMembers(context.Background(), &group.ImportMembersRequest{
+		GroupID: testGroupID,
+		ActorID: testUserID,
+		DryRun:  true,
+		Entries: []group.MemberImportEntry{
+			{Email: " Known@Acme.com ", Role: "admin"},
+			{Email: "new@example.com"},
+			{Email: "not-an-email"},
+			{Email: "NEW@example.com", Role: "ADMIN"},
+			{Email: "wizard@example.com", Role: "WIZARD"},
+			{Email: "existing@example.com"},
+			{Email: "third@example.com"},
+			{Email: "fourth@example.com"},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, response.Import)
+
+	report := response.Report
+	require.Len(t, report.Rows, 8)
+	assert.Equal(t, 8, report.Total)
+	assert.Equal(t, 4, report.Valid)
+	assert.Equal(t, 4, report.Invalid)
+	assert.Equal(t, 2, report.Warnings)
+
+	issueCodes := func(row group.MemberImportRow) []string {
+		codes := []string{}
+		for _, issue := range row.Issues {
+			codes = append(codes, issue.Code)
+		}
+		return codes
+	}
+
+	tests := []struct {
+		row            int
+		expectedEmail  string
+		expectedRole   string
+		expectedAction string
+		expectedStatus string
+		expectedIssues []string
+	}{
+		{1, "known@acme.com", group.MemberRoleAdmin, group.MemberImportActionAdd, group.MemberImportRowStatusValid, []string{group.MemberImportIssueAutoJoinDomainConflict}},
+		{2, "new@example.com", group.MemberRoleMember, group.MemberImportActionInvite, group.MemberImportRowStatusValid, []string{}},
+		{3, "not-an-email", group.MemberRoleMember, "", group.MemberImportRowStatusInvalid, []string{group.MemberImportIssueInvalidEmail}},
+		{4, "new@example.com", group.MemberRoleAdmin, "", group.MemberImportRowStatusInvalid, []string{group.MemberImportIssueDuplicate}},
+		{5, "wizard@example.com", "WIZARD", "", group.MemberImportRowStatusInvalid, []string{group.MemberImportIssueUnknownRole}},
+		{6, "existing@example.com", group.MemberRoleMember, group.MemberImportActionSkip, group.MemberImportRowStatusSkipped, []string{group.MemberImportIssueAlreadyMember}},
+		{7, "third@example.com", group.MemberRoleMember, group.MemberImportActionInvite, group.MemberImportRowStatusValid, []string{}},
+		{8, "fourth@example.com", group.MemberRoleMember, "", group.MemberImportRowStatusInvalid, []string{group.MemberImportIssueOverMaxMembers}},
+	}
+
+	for _, tt := range tests {
+		row := report.Rows[tt.row-1]
+		assert.Equal(t, tt.row, row.Row)
+		assert.Equal(t, tt.expectedEmail, row.Email, "row %d", tt.row)
+		assert.Equal(t, tt.expectedRole, row.Role, "row %d", tt.row)
+		assert.Equal(t, tt.expectedAction, row.Action, "row %d", tt.row)
+		assert.Equal(t, tt.expectedStatus, row.Status, "row %d", tt.row)
+		assert.Equal(t, tt.expectedIssues, issueCodes(row), "row %d", tt.row)
+	}
+
+	// a dry run leaves the group untouched
+	assert.Len(t, groups[testGroupID].Members, 1)
+}
+
+func TestService_ImportMembers_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		importRepo  group.MemberImportRepository
+		request     *group.ImportMembersRequest
+		expectedErr error
+	}{
+		{
+			name:        "Failure - imports are not enabled",
+			request:     &group.ImportMembersRequest{GroupID: testGroupID, Entries: []group.MemberImportEntry{{Email: "a@example.com"}}},
+			expectedErr: group.ErrMemberImportsNotEnabled,
+		},
+		{
+			name:        "Failure - import has no entries",
+			importRepo:  newMockMemberImportRepository(),
+			request:     &group.ImportMembersRequest{GroupID: testGroupID},
+			expectedErr: group.ErrInvalidMemberImport,
+		},
+		{
+			name:        "Failure - import has too many entries",
+			importRepo:  newMockMemberImportRepository(),
+			request:     &group.ImportMembersRequest{GroupID: testGroupID, Entries: make([]group.MemberImportEntry, group.DefaultMaxMemberImportRows+1)},
+			expectedErr: group.ErrMemberImportTooLarge,
+		},
+		{
+			name:        "Failure - group does not exist",
+			importRepo:  newMockMemberImportRepository(),
+			request:     &group.ImportMembersRequest{GroupID: "missing", Entries: []group.MemberImportEntry{{Email: "a@example.com"}}},
+			expectedErr: group.ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := map[string]*group.UniversalGroup{
+				testGroupID: {ID: testGroupID, Type: group.GroupTypeOrganisation, Status: group.GroupStatusActive},
+			}
+			svc := newMemberImportTestService(groups, tt.importRepo, nil)
+
+			_, err := svc.ImportMembers(context.Background(), tt.request)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestService_ImportMembers_ProcessesInBackground(t *testing.T) {
+	t.Parallel()
+
+	groups := map[string]*group.UniversalGroup{
+		testGroupID: {ID: testGroupID, Type: group.GroupTypeOrganisation, Status: group.GroupStatusActive},
+	}
+	importRepo := newMockMemberImportRepository()
+	svc := newMemberImportTestService(groups, importRepo, mockMemberImportUserFinder{"known@example.com": "user-known"})
+
+	response, err := svc.ImportMembers(context.Background(), &group.ImportMembersRequest{
+		GroupID: testGroupID,
+		ActorID: testUserID,
+		Entries: []group.MemberImportEntry{
+			{Email: "known@example.com", Role: group.MemberRoleAdmin},
+			{Email: "invitee@example.com"},
+			{Email: "broken"},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, response.Import)
+	assert.Equal(t, group.MemberImportStatusPending, response.Import.Status)
+	assert.Equal(t, 3, response.Import.Progress.Total)
+
+	var memberImport *group.MemberImportJob
+	require.Eventually(t, func() bool {
+		getResponse, getErr := svc.GetMemberImport(context.Background(), &group.GetMemberImportRequest{GroupID: testGroupID, ImportID: response.Import.ID})
+		if getErr != nil {
+			return false
+		}
+		memberImport = getResponse.Import
+		return memberImport.IsFinished()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, group.MemberImportStatusCompleted, memberImport.Status)
+	assert.Equal(t, group.MemberImportProgress{Total: 3, Processed: 3, Added: 1, Invited: 1, Invalid: 1}, memberImport.Progress)
+	assert.Equal(t, group.MemberImportRowStatusAdded, memberImport.Rows[0].Status)
+	assert.Equal(t, group.MemberImportRowStatusInvited, memberImport.Rows[1].Status)
+	assert.Equal(t, group.MemberImportRowStatusInvalid, memberImport.Rows[2].Status)
+	assert.NotEmpty(t, memberImport.CompletedAt)
+
+	updatedGroup, err := svc.GroupRepository.GetGroupByID(context.Background(), testGroupID)
+	require.NoError(t, err)
+	assert.True(t, updatedGroup.HasMember("user-known"))
+	assert.True(t, updatedGroup.HasMember("invitee@example.com"))
+
+	_, err = svc.GetMemberImport(context.Background(), &group.GetMemberImportRequest{GroupID: "other-group", ImportID: response.Import.ID})
+	assert.ErrorIs(t, err, group.ErrMemberImportNotFound)
+}
+
+// newInterruptedMemberImport returns a running import of two people that stopped after
+// its first row, with its last heartbeat at heartbeatAt
+func newInterruptedMemberImport(id, heartbeatAt string) group.MemberImportJob {
+	return group.MemberImportJob{
+		ID:            id,
+		GroupID:       testGroupID,
+		Status:        group.MemberImportStatusRunning,
+		RequestedByID: testUserID,
+		ClaimedBy:     "stopped-run",
+		HeartbeatAt:   heartbeatAt,
+		Rows: []group.MemberImportRow{
+			{Row: 1, Email: "first@example.com", Role: group.MemberRoleMember, Action: group.MemberImportActionInvite, Status: group.MemberImportRowStatusInvited},
+			{Row: 2, Email: "second@example.com", Role: group.MemberRoleMember, Action: group.MemberImportActionInvite, Status: group.MemberImportRowStatusValid},
+		},
+	}
+}
+
+func TestService_ResumeMemberImport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		heartbeatAt    string
+		expectedErr    error
+		expectedStatus string
+	}{
+		{
+			name:           "Success - import without a recent heartbeat is claimed and finished",
+			heartbeatAt:    "2026-01-01T00:00:00Z",
+			expectedStatus: group.MemberImportStatusCompleted,
+		},
+		{
+			name:        "Failure - import still being processed elsewhere",
+			heartbeatAt: time.Now().UTC().Format(time.RFC3339),
+			expectedErr: group.ErrMemberImportAlreadyClaimed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := map[string]*group.UniversalGroup{
+				testGroupID: {ID: testGroupID, Type: group.GroupTypeOrganisation, Status: group.GroupStatusActive},
+			}
+			importRepo := newMockMemberImportRepository()
+			importRepo.storeMemberImport(newInterruptedMemberImport("import-1", tt.heartbeatAt))
+			svc := newMemberImportTestService(groups, importRepo, nil)
+
+			request := &group.GetMemberImportRequest{GroupID: testGroupID, ImportID: "import-1"}
+			response, err := svc.ResumeMemberImport(context.Background(), request)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, group.MemberImportStatusRunning, response.Import.Status)
+			assert.NotEqual(t, "stopped-run", response.Import.ClaimedBy)
+
+			var memberImport *group.MemberImportJob
+			require.Eventually(t, func() bool {
+				getResponse, getErr := svc.GetMemberImport(context.Background(), request)
+				if getErr != nil {
+					return false
+				}
+				memberImport = getResponse.Import
+				return memberImport.IsFinished()
+			}, 2*time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, tt.expectedStatus, memberImport.Status)
+			assert.Equal(t, group.MemberImportRowStatusInvited, memberImport.Rows[1].Status)
+		})
+	}
+}
+
+func TestService_ResumeMemberImport_ClaimsOnce(t *testing.T) {
+	t.Parallel()
+
+	groups := map[string]*group.UniversalGroup{
+		testGroupID: {ID: testGroupID, Type: group.GroupTypeOrganisation, Status: group.GroupStatusActive},
+	}
+	importRepo := newMockMemberImportRepository()
+	importRepo.storeMemberImport(newInterruptedMemberImport("import-1", "2026-01-01T00:00:00Z"))
+	svc := newMemberImportTestService(groups, importRepo, nil)
+
+	// later calls either lose the claim or find the import already finished
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := svc.ResumeMemberImport(context.Background(), &group.GetMemberImportRequest{GroupID: testGroupID, ImportID: "import-1"})
+			if err != nil {
+				assert.ErrorIs(t, err, group.ErrMemberImportAlreadyClaimed)
+			}
+		}()
+	}
+	wg.Wait()
+
+	importRepo.mu.Lock()
+	defer importRepo.mu.Unlock()
+	assert.Equal(t, 1, importRepo.claims)
+}
+
+func TestService_ResumeStaleMemberImports(t *testing.T) {
+	t.Parallel()
+
+	groups := map[string]*group.UniversalGroup{
+		testGroupID: {ID: testGroupID, Type: group.GroupTypeOrganisation, Status: group.GroupStatusActive},
+	}
+	importRepo := newMockMemberImportRepository()
+	importRepo.storeMemberImport(newInterruptedMemberImport("stale-import", "2026-01-01T00:00:00Z"))
+	importRepo.storeMemberImport(newInterruptedMemberImport("active-import", time.Now().UTC().Format(time.RFC3339)))
+	svc := newMemberImportTestService(groups, importRepo, nil)
+
+	response, err := svc.ResumeStaleMemberImports(context.Background(), &group.ResumeStaleMemberImportsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stale-import"}, response.ImportIDs)
+
+	require.Eventually(t, func() bool {
+		getResponse, getErr := svc.GetMemberImport(context.Background(), &group.GetMemberImportRequest{ImportID: "stale-import"})
+		return getErr == nil && getResponse.Import.IsFinished()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	activeImport, err := svc.GetMemberImport(context.Background(), &group.GetMemberImportRequest{ImportID: "active-import"})
+	require.NoError(t, err)
+	assert.Equal(t, "stopped-run", activeImport.Import.ClaimedBy)
+	assert.Equal(t, group.MemberImportStatusRunning, activeImport.Import.Status)
+
+	_, err = newMemberImportTestService(groups, nil, nil).ResumeStaleMemberImports(context.Background(), &group.ResumeStaleMemberImportsRequest{})
+	assert.ErrorIs(t, err, group.ErrMemberImportsNotEnabled)
+}
+
+func TestParseMemberImportEntries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		format          string
+		body            string
+		expectedEntries []group.MemberImportEntry
+		expectedErr     error
+	}{
+		{
+			name:   "Success - CSV with header in any order and case",
+			format: "CSV",
+			body:   "Role,Name,EMAIL\nADMIN,Ada,ada@example.com\n,Bob,bob@example.com\n\n",
+			expectedEntries: []group.MemberImportEntry{
+				{Email: "ada@example.com", Role: "ADMIN"},
+				{Email: "bob@example.com", Role: ""},
+			},
+		},
+		{
+			name:   "Success - CSV without header is read as email and role",
+			format: group.MemberImportFormatCSV,
+			body:   "\ufeffada@example.com,ADMIN\nbob@example.com",
+			expectedEntries: []group.MemberImportEntry{
+				{Email: "ada@example.com", Role: "ADMIN"},
+				{Email: "bob@example.com"},
+			},
+		},
+		{
+			name:        "Failure - CSV header without an email column",
+			format:      group.MemberImportFormatCSV,
+			body:        "name,role\nAda,ADMIN",
+			expectedErr: group.ErrInvalidMemberImport,
+		},
+		{
+			name:        "Failure - CSV with only a header",
+			format:      group.MemberImportFormatCSV,
+			body:        "email,role\n",
+			expectedErr: group.ErrInvalidMemberImport,
+		},
+		{
+			name:   "Success - JSON array",
+			format: group.MemberImportFormatJSON,
+			body:   `[{"email":"ada@example.com","role":"ADMIN"},{"email":"bob@example.com"}]`,
+			expectedEntries: []group.MemberImportEntry{
+				{Email: "ada@example.com", Role: "ADMIN"},
+				{Email: "bob@example.com"},
+			},
+		},
+		{
+			name:            "Success - JSON object with members",
+			format:          group.MemberImportFormatJSON,
+			body:            `{"members":[{"email":"ada@example.com"}]}`,
+			expectedEntries: []group.MemberImportEntry{{Email: "ada@example.com"}},
+		},
+		{
+			name:        "Failure - malformed JSON",
+			format:      group.MemberImportFormatJSON,
+			body:        `[{"email":`,
+			expectedErr: group.ErrInvalidMemberImport,
+		},
+		{
+			name:        "Failure - unsupported format",
+			format:      "xlsx",
+			body:        "ada@example.com",
+			expectedErr: group.ErrUnsupportedMemberImportFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := group.ParseMemberImportEntries(tt.format, strings.NewReader(tt.body))
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedEntries, entries)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package group_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+type moveTestHarness struct {
+	service     *group.Service
+	repo        *mockGroupRepository
+	groups      map[string]*group.UniversalGroup
+	auditEvents []*audit.LogAuditEventRequest
+}
+
+// newMoveTestHarness builds two organisations:
+//
+//	org-1 (user-1, user-2) > dept-1 (user-1, user-2) > team-1 (user-2)
+//	org-2 (user-1)         > dept-2
+func newMoveTestHarness(t *testing.T) *moveTestHarness {
+	t.Helper()
+
+	members := func(ids ...string) []group.Member {
+		result := []group.Member{}
+		for _, id := range ids {
+			result = append(result, group.Member{ID: id, Type: group.MemberTypeUser, Role: group.MemberRoleMember})
+		}
+		return result
+	}
+
+	harness := &moveTestHarness{
+		groups: map[string]*group.UniversalGroup{
+			"org-1":  {ID: "org-1", Name: "org-one", Type: group.GroupTypeOrganisation, Members: members("user-1", "user-2")},
+			"dept-1": {ID: "dept-1", Name: "platform", Type: group.GroupTypeDepartment, ParentGroupID: "org-1", Lineage: []string{"org-1"}, Members: members("user-1", "user-2")},
+			"team-1": {ID: "team-1", Name: "api", Type: group.GroupTypeTeam, ParentGroupID: "dept-1", Lineage: []string{"org-1", "dept-1"}, OwnerID: "user-2", Members: members("user-2")},
+			"org-2":  {ID: "org-2", Name: "org-two", Type: group.GroupTypeOrganisation, Members: members("user-1")},
+			"dept-2": {ID: "dept-2", Name: "sales", Type: group.GroupTypeDepartment, ParentGroupID: "org-2", Lineage: []string{"org-2"}},
+		},
+	}
+
+	copyGroup := func(grp *group.UniversalGroup) *group.UniversalGroup {
+		copied := *grp
+		copied.Lineage = append([]string{}, grp.Lineage...)
+		copied.Members = append([]group.Member{}, grp.Members...)
+		return &copied
+	}
+
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			grp, ok := harness.groups[id]
+			if !ok {
+				return nil, group.ErrResourceNotFound
+			}
+			return copyGroup(grp), nil
+		},
+		getGroupByNameAndParentFunc: func(ctx context.Context, name, parentGroupID string, logError bool) (*group.UniversalGroup, error) {
+			for _, grp := range harness.groups {
+				if grp.Name == name && grp.ParentGroupID == parentGroupID {
+					return copyGroup(grp), nil
+				}
+			}
+			return nil, nil
+		},
+		getGroupsByLineageAncestorFunc: func(ctx context.Context, ancestorGroupID string) ([]group.UniversalGroup, error) {
+			results := []group.UniversalGroup{}
+			for _, grp := range harness.groups {
+				for _, lineageID := range grp.Lineage {
+					if lineageID == ancestorGroupID {
+						results = append(results, *copyGroup(grp))
+						break
+					}
+				}
+			}
+			return results, nil
+		},
+		moveGroupSubtreeFunc: func(ctx context.Context, groupID, parentGroupID string, lineage []string, updatedAt string) error {
+			for _, grp := range harness.groups {
+				for i, lineageID := range grp.Lineage {
+					if lineageID == groupID {
+						grp.Lineage = append(append([]string{}, lineage...), grp.Lineage[i:]...)
+						break
+					}
+				}
+			}
+			harness.groups[groupID].ParentGroupID = parentGroupID
+			harness.groups[groupID].Lineage = lineage
+			return nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			harness.groups[grp.ID] = copyGroup(grp)
+			return grp, nil
+		},
+		removeMemberFromGroupFunc: func(ctx context.Context, groupID, memberID string) error {
+			_, err := harness.groups[groupID].RemoveMember(memberID)
+			return err
+		},
+		clearOwnerFromGroupFunc: func(ctx context.Context, groupID, ownerID string) error {
+			harness.groups[groupID].OwnerID = ""
+			return nil
+		},
+	}
+
+	auditService := &mockAuditService{
+		logAuditEventFunc: func(ctx context.Context, r *audit.LogAuditEventRequest) error {
+			harness.auditEvents = append(harness.auditEvents, r)
+			return nil
+		},
+	}
+
+	harness.repo = repo
+	harness.service = newTestService(repo, auditService)
+	return harness
+}
+
+func TestService_MoveGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		request          *group.MoveGroupRequest
+		expectedError    error
+		expectedLineages map[string][]string
+		expectedMembers  map[string][]string
+		expectedRemoved  map[string][]string
+	}{
+		{
+			name:    "Success - moves team directly under its organisation",
+			request: &group.MoveGroupRequest{GroupID: "team-1", NewParentGroupID: "org-1"},
+			expectedLineages: map[string][]string{
+				"team-1": {"org-1"},
+			},
+			expectedMembers: map[string][]string{
+				"team-1": {"user-2"},
+			},
+		},
+		{
+			name:    "Success - moves department subtree to another organisation removing members outside the new root",
+			request: &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "org-2", MovedByID: "admin-1"},
+			expectedLineages: map[string][]string{
+				"dept-1": {"org-2"},
+				"team-1": {"org-2", "dept-1"},
+			},
+			expectedMembers: map[string][]string{
+				"dept-1": {"user-1"},
+				"team-1": {},
+				"org-2":  {"user-1"},
+			},
+			expectedRemoved: map[string][]string{
+				"dept-1": {"user-2"},
+				"team-1": {"user-2"},
+			},
+		},
+		{
+			name:    "Success - moves department subtree to another organisation propagating members to the new root",
+			request: &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "org-2", PropagateMembers: true},
+			expectedLineages: map[string][]string{
+				"dept-1": {"org-2"},
+				"team-1": {"org-2", "dept-1"},
+			},
+			expectedMembers: map[string][]string{
+				"dept-1": {"user-1", "user-2"},
+				"team-1": {"user-2"},
+				"org-2":  {"user-1", "user-2"},
+			},
+		},
+		{
+			name:    "Success - moves department to the top level",
+			request: &group.MoveGroupRequest{GroupID: "dept-1"},
+			expectedLineages: map[string][]string{
+				"dept-1": {},
+				"team-1": {"dept-1"},
+			},
+			expectedMembers: map[string][]string{
+				"team-1": {"user-2"},
+			},
+		},
+		{
+			name:          "Failure - moving under a descendant creates a cycle",
+			request:       &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "team-1"},
+			expectedError: group.ErrCircularReferenceDetected,
+		},
+		{
+			name:          "Failure - moving under itself creates a cycle",
+			request:       &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "dept-1"},
+			expectedError: group.ErrCircularReferenceDetected,
+		},
+		{
+			name:          "Failure - hierarchy tree does not allow the parent type",
+			request:       &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "dept-2"},
+			expectedError: group.ErrInvalidParentChildRelation,
+		},
+		{
+			name:          "Failure - group is already under the parent",
+			request:       &group.MoveGroupRequest{GroupID: "team-1", NewParentGroupID: "dept-1"},
+			expectedError: group.ErrNoChangesDetected,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			harness := newMoveTestHarness(t)
+
+			response, err := harness.service.MoveGroup(context.Background(), tt.request)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.request.NewParentGroupID, response.Group.ParentGroupID)
+
+			for groupID, expectedLineage := range tt.expectedLineages {
+				assert.Equal(t, expectedLineage, harness.groups[groupID].Lineage, groupID)
+			}
+
+			for groupID, expectedMemberIDs := range tt.expectedMembers {
+				memberIDs := []string{}
+				for _, member := range harness.groups[groupID].Members {
+					memberIDs = append(memberIDs, member.ID)
+				}
+				assert.ElementsMatch(t, expectedMemberIDs, memberIDs, groupID)
+			}
+
+			assert.Equal(t, tt.expectedRemoved, response.RemovedMembers)
+			assert.Empty(t, response.FailedGroupIDs)
+
+			require.NotEmpty(t, harness.auditEvents)
+			moveEvent := harness.auditEvents[len(harness.auditEvents)-1]
+			assert.Equal(t, audit.AuditAction("group.moved"), moveEvent.Action)
+			assert.Equal(t, tt.request.GroupID, moveEvent.TargetId)
+		})
+	}
+}
+
+func TestService_MoveGroupClearsOwnerRemovedFromSubtree(t *testing.T) {
+	t.Parallel()
+
+	harness := newMoveTestHarness(t)
+
+	_, err := harness.service.MoveGroup(context.Background(), &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "org-2"})
+	require.NoError(t, err)
+
+	assert.Empty(t, harness.groups["team-1"].OwnerID)
+}
+
+func TestService_MoveGroupPropagatesMembersWithDefaultRole(t *testing.T) {
+	t.Parallel()
+
+	harness := newMoveTestHarness(t)
+
+	_, err := harness.service.MoveGroup(context.Background(), &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "org-2", PropagateMembers: true, MovedByID: "admin-1"})
+	require.NoError(t, err)
+
+	propagated, err := harness.groups["org-2"].GetMemberByID("user-2")
+	require.NoError(t, err)
+	assert.Equal(t, group.MemberRoleMember, propagated.Role)
+
+	addedEvents := 0
+	for _, event := range harness.auditEvents {
+		if event.Action == audit.AuditAction("group.member.added") {
+			addedEvents++
+			assert.Equal(t, "admin-1", event.ActorId)
+		}
+	}
+	assert.Equal(t, 1, addedEvents)
+}
+
+func TestService_MoveGroupReturnsNameLookupErrors(t *testing.T) {
+	t.Parallel()
+
+	harness := newMoveTestHarness(t)
+	harness.repo.getGroupByNameAndParentFunc = func(ctx context.Context, name, parentGroupID string, logError bool) (*group.UniversalGroup, error) {
+		return nil, errors.New("connection reset")
+	}
+
+	_, err := harness.service.MoveGroup(context.Background(), &group.MoveGroupRequest{GroupID: "team-1", NewParentGroupID: "org-1"})
+	assert.ErrorIs(t, err, group.ErrDatabaseError)
+	assert.Equal(t, []string{"org-1", "dept-1"}, harness.groups["team-1"].Lineage)
+}
+
+func TestService_MoveGroupEnforcesMaxNestingDepth(t *testing.T) {
+	t.Parallel()
+
+	harness := newMoveTestHarness(t)
+	harness.service.Config.MaxNestingDepth = 2
+
+	// team-1 sits one level below dept-1, so dept-1 under org-2 > dept-2 would be 3 levels deep
+	harness.groups["dept-2"].Type = group.GroupTypeTribe
+	harness.groups["dept-1"].Type = group.GroupTypeTeam
+	harness.groups["team-1"].Type = group.GroupTypeSquad
+
+	_, err := harness.service.MoveGroup(context.Background(), &group.MoveGroupRequest{GroupID: "dept-1", NewParentGroupID: "dept-2"})
+	assert.ErrorIs(t, err, group.ErrMaxDepthExceeded)
+}
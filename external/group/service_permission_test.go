@@ -0,0 +1,394 @@
+package group_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+type permissionTestHarness struct {
+	service *group.Service
+	groups  map[string]*group.UniversalGroup
+}
+
+// newPermissionTestHarness builds a single organisation:
+//
+//	org-1 (owner-1, admin-1 ADMIN, member-1 MEMBER, releaser-1 RELEASER) > team-1 (member-1 MEMBER)
+func newPermissionTestHarness(t *testing.T) *permissionTestHarness {
+	t.Helper()
+
+	harness := &permissionTestHarness{
+		groups: map[string]*group.UniversalGroup{
+			"org-1": {
+				ID:      "org-1",
+				Type:    group.GroupTypeOrganisation,
+				OwnerID: "owner-1",
+				Members: []group.Member{
+					{ID: "admin-1", Type: group.MemberTypeUser, Role: group.MemberRoleAdmin},
+					{ID: "member-1", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+					{ID: "releaser-1", Type: group.MemberTypeUser, Role: "releaser"},
+				},
+				CustomRoles: []group.GroupRole{
+					{Name: "RELEASER", Permissions: []string{group.PermissionGroupView, group.PermissionSettingsUpdate}},
+				},
+			},
+			"team-1": {
+				ID:            "team-1",
+				Type:          group.GroupTypeTeam,
+				ParentGroupID: "org-1",
+				Lineage:       []string{"org-1"},
+				Members: []group.Member{
+					{ID: "member-1", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+				},
+			},
+		},
+	}
+
+	repo := &mockGroupRepository{
+		getGroupByIDFunc: func(ctx context.Context, id string) (*group.UniversalGroup, error) {
+			grp, ok := harness.groups[id]
+			if !ok {
+				return nil, group.ErrResourceNotFound
+			}
+			return grp, nil
+		},
+		updateGroupFunc: func(ctx context.Context, grp *group.UniversalGroup) (*group.UniversalGroup, error) {
+			harness.groups[grp.ID] = grp
+			return grp, nil
+		},
+	}
+
+	harness.service = newTestService(repo, &mockAuditService{})
+	return harness
+}
+
+func TestService_Can(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		inheritance   string
+		userID        string
+		groupID       string
+		permission    string
+		expected      bool
+		expectedError error
+	}{
+		{
+			name:       "Success - owner holds every permission",
+			userID:     "owner-1",
+			groupID:    "org-1",
+			permission: group.PermissionGroupDelete,
+			expected:   true,
+		},
+		{
+			name:       "Success - admin can manage members",
+			userID:     "admin-1",
+			groupID:    "org-1",
+			permission: group.PermissionMembersManage,
+			expected:   true,
+		},
+		{
+			name:       "Success - admin cannot delete the group",
+			userID:     "admin-1",
+			groupID:    "org-1",
+			permission: group.PermissionGroupDelete,
+		},
+		{
+			name:       "Success - member can view members",
+			userID:     "member-1",
+			groupID:    "org-1",
+			permission: group.PermissionMembersView,
+			expected:   true,
+		},
+		{
+			name:       "Success - member cannot update settings",
+			userID:     "member-1",
+			groupID:    "org-1",
+			permission: group.PermissionSettingsUpdate,
+		},
+		{
+			name:       "Success - custom role grants its permissions",
+			userID:     "releaser-1",
+			groupID:    "org-1",
+			permission: group.PermissionSettingsUpdate,
+			expected:   true,
+		},
+		{
+			name:       "Success - admin permissions apply to descendants",
+			userID:     "admin-1",
+			groupID:    "team-1",
+			permission: group.PermissionMembersManage,
+			expected:   true,
+		},
+		{
+			name:       "Success - custom role without members.manage does not apply to descendants",
+			userID:     "releaser-1",
+			groupID:    "team-1",
+			permission: group.PermissionSettingsUpdate,
+		},
+		{
+			name:        "Success - every role applies to descendants when inheriting all",
+			inheritance: group.PermissionInheritanceAll,
+			userID:      "releaser-1",
+			groupID:     "team-1",
+			permission:  group.PermissionSettingsUpdate,
+			expected:    true,
+		},
+		{
+			name:        "Success - no role applies to descendants when inheriting none",
+			inheritance: group.PermissionInheritanceNone,
+			userID:      "admin-1",
+			groupID:     "team-1",
+			permission:  group.PermissionMembersManage,
+		},
+		{
+			name:          "Failure - permission is not registered",
+			userID:        "owner-1",
+			groupID:       "org-1",
+			permission:    "reports.export",
+			expectedError: group.ErrUnknownPermission,
+		},
+		{
+			name:          "Failure - group not found",
+			userID:        "owner-1",
+			groupID:       "missing",
+			permission:    group.PermissionGroupView,
+			expectedError: group.ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			harness := newPermissionTestHarness(t)
+			harness.service.Config.PermissionInheritance = tt.inheritance
+
+			allowed, err := harness.service.Can(context.Background(), tt.userID, tt.groupID, tt.permission)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, allowed)
+		})
+	}
+}
+
+func TestService_CanWithHostPermissions(t *testing.T) {
+	t.Parallel()
+
+	harness := newPermissionTestHarness(t)
+	harness.service.Config.
+		WithPermissions("reports.export").
+		WithTypeRolePermissions(group.GroupTypeOrganisation, group.MemberRoleMember, group.PermissionGroupView, "reports.export")
+
+	allowed, err := harness.service.Can(context.Background(), "member-1", "org-1", "reports.export")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// the override only applies to organisations
+	allowed, err = harness.service.Can(context.Background(), "member-1", "team-1", "reports.export")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestService_SetGroupRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		request         *group.SetGroupRoleRequest
+		expectedError   error
+		expectedCreated bool
+	}{
+		{
+			name:            "Success - creates a custom role",
+			request:         &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "auditor", Permissions: []string{group.PermissionMembersView}},
+			expectedCreated: true,
+		},
+		{
+			name:    "Success - updates an existing custom role",
+			request: &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "RELEASER", Permissions: []string{group.PermissionGroupView}},
+		},
+		{
+			name:          "Failure - name is taken by a configured role",
+			request:       &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "admin", Permissions: []string{group.PermissionGroupView}},
+			expectedError: group.ErrCustomRoleConflict,
+		},
+		{
+			name:          "Failure - permission is not registered",
+			request:       &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "auditor", Permissions: []string{"reports.export"}},
+			expectedError: group.ErrUnknownPermission,
+		},
+		{
+			name:          "Failure - role must grant a permission",
+			request:       &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "auditor"},
+			expectedError: group.ErrInvalidCustomRole,
+		},
+		{
+			name:          "Failure - role name is invalid",
+			request:       &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "audit team", Permissions: []string{group.PermissionGroupView}},
+			expectedError: group.ErrInvalidCustomRole,
+		},
+		{
+			name:          "Failure - role grants a permission the actor does not hold",
+			request:       &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "deleter", Permissions: []string{group.PermissionGroupDelete}},
+			expectedError: group.ErrInsufficientPermissions,
+		},
+		{
+			name:          "Failure - role is defined without an actor",
+			request:       &group.SetGroupRoleRequest{GroupID: "org-1", RoleName: "auditor", Permissions: []string{group.PermissionMembersView}},
+			expectedError: group.ErrInsufficientPermissions,
+		},
+		{
+			name:            "Success - owner grants any permission",
+			request:         &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "owner-1", RoleName: "deleter", Permissions: []string{group.PermissionGroupDelete}},
+			expectedCreated: true,
+		},
+		{
+			name:            "Success - platform admin grants any permission",
+			request:         &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByIsPlatformAdmin: true, RoleName: "deleter", Permissions: []string{group.PermissionGroupDelete}},
+			expectedCreated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			harness := newPermissionTestHarness(t)
+
+			response, err := harness.service.SetGroupRole(context.Background(), tt.request)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCreated, response.Created)
+
+			role := harness.groups["org-1"].GetCustomRole(tt.request.RoleName)
+			require.NotNil(t, role)
+			assert.Equal(t, tt.request.Permissions, role.Permissions)
+		})
+	}
+}
+
+func TestService_SetGroupRoleCanBeAssigned(t *testing.T) {
+	t.Parallel()
+
+	harness := newPermissionTestHarness(t)
+
+	_, err := harness.service.SetGroupRole(context.Background(), &group.SetGroupRoleRequest{GroupID: "org-1", UpdatedByID: "admin-1", RoleName: "inviter", Permissions: []string{group.PermissionMembersInvite}})
+	require.NoError(t, err)
+
+	_, err = harness.service.UpdateMemberRole(context.Background(), &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "member-1", NewRole: "INVITER", ActorID: "admin-1"})
+	require.NoError(t, err)
+
+	allowed, err := harness.service.Can(context.Background(), "member-1", "org-1", group.PermissionMembersInvite)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	_, err = harness.service.DeleteGroupRole(context.Background(), &group.DeleteGroupRoleRequest{GroupID: "org-1", RoleName: "inviter"})
+	assert.ErrorIs(t, err, group.ErrCustomRoleInUse)
+}
+
+func TestService_UpdateMemberRoleBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		request       *group.UpdateMemberRoleRequest
+		expectedError error
+	}{
+		{
+			name:    "Success - actor assigns their own role",
+			request: &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "member-1", NewRole: group.MemberRoleAdmin, ActorID: "admin-1"},
+		},
+		{
+			name:          "Failure - ownership is never assigned",
+			request:       &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "member-1", NewRole: group.MemberRoleOwner, ActorID: "owner-1"},
+			expectedError: group.ErrInvalidMemberRole,
+		},
+		{
+			name:          "Failure - role above the actor's own",
+			request:       &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "member-1", NewRole: group.MemberRoleAdmin, ActorID: "releaser-1"},
+			expectedError: group.ErrInsufficientPermissions,
+		},
+		{
+			name:          "Failure - member ranks above the actor",
+			request:       &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "admin-1", NewRole: group.MemberRoleMember, ActorID: "releaser-1"},
+			expectedError: group.ErrInsufficientPermissions,
+		},
+		{
+			name:          "Failure - custom role grants a permission the actor does not hold",
+			request:       &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "admin-1", NewRole: "DELETER", ActorID: "admin-1"},
+			expectedError: group.ErrInsufficientPermissions,
+		},
+		{
+			name:    "Success - owner assigns a custom role with any permission",
+			request: &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "admin-1", NewRole: "DELETER", ActorID: "owner-1"},
+		},
+		{
+			name:    "Success - platform admin assigns a custom role with any permission",
+			request: &group.UpdateMemberRoleRequest{GroupID: "org-1", MemberID: "member-1", NewRole: "DELETER", ActorIsPlatformAdmin: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			harness := newPermissionTestHarness(t)
+			harness.groups["org-1"].CustomRoles = append(harness.groups["org-1"].CustomRoles, group.GroupRole{
+				Name:        "DELETER",
+				Permissions: []string{group.PermissionGroupView, group.PermissionGroupDelete},
+			})
+
+			_, err := harness.service.UpdateMemberRole(context.Background(), tt.request)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			member, err := harness.groups["org-1"].GetMemberByID(tt.request.MemberID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.request.NewRole, member.Role)
+		})
+	}
+}
+
+func TestService_DeleteGroupRole(t *testing.T) {
+	t.Parallel()
+
+	harness := newPermissionTestHarness(t)
+	harness.groups["org-1"].Members[2].Role = group.MemberRoleMember
+
+	_, err := harness.service.DeleteGroupRole(context.Background(), &group.DeleteGroupRoleRequest{GroupID: "org-1", RoleName: "releaser"})
+	require.NoError(t, err)
+	assert.Empty(t, harness.groups["org-1"].CustomRoles)
+
+	_, err = harness.service.DeleteGroupRole(context.Background(), &group.DeleteGroupRoleRequest{GroupID: "org-1", RoleName: "releaser"})
+	assert.ErrorIs(t, err, group.ErrCustomRoleNotFound)
+}
+
+func TestNewService_RejectsUnregisteredRolePermissions(t *testing.T) {
+	t.Parallel()
+
+	config := group.DefaultGroupConfig().WithRolePermissions(group.MemberRoleMember, "reports.export")
+
+	_, err := group.NewService(&mockGroupRepository{}, &mockAuditService{}, config, group.NewDefaultIDGenerator(), group.NewDefaultTimeProvider(), group.NewDefaultStringUtils())
+	assert.ErrorIs(t, err, group.ErrInvalidRolePermissions)
+}
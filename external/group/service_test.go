@@ -30,6 +30,9 @@ type mockGroupRepository struct {
 	getGroupsFunc                   func(ctx context.Context, req *group.GetGroupsRequest) ([]group.UniversalGroup, error)
 	getTotalGroupsFunc              func(ctx context.Context, req *group.GetGroupsRequest) (int64, error)
 	getGroupsByReferencedUserIDFunc func(ctx context.Context, userID string) ([]group.UniversalGroup, error)
+	getGroupsWithPendingInvitesFunc func(ctx context.Context) ([]group.UniversalGroup, error)
+	getGroupMembersFunc             func(ctx context.Context, req *group.GetGroupMembersRequest) ([]group.Member, int64, error)
+	moveGroupSubtreeFunc            func(ctx context.Context, groupID, parentGroupID string, lineage []string, updatedAt string) error
 	removeMemberFromGroupFunc       func(ctx context.Context, groupID, memberID string) error
 	clearOwnerFromGroupFunc         func(ctx context.Context, groupID, ownerID string) error
 }
@@ -139,6 +142,20 @@ func (m *mockGroupRepository) GetGroupsByLeaderID(ctx context.Context, leaderID
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockGroupRepository) MoveGroupSubtree(ctx context.Context, groupID, parentGroupID string, lineage []string, updatedAt string) error {
+	if m.moveGroupSubtreeFunc != nil {
+		return m.moveGroupSubtreeFunc(ctx, groupID, parentGroupID, lineage, updatedAt)
+	}
+	return errors.New("not implemented")
+}
+
+func (m *mockGroupRepository) GetGroupMembers(ctx context.Context, req *group.GetGroupMembersRequest) ([]group.Member, int64, error) {
+	if m.getGroupMembersFunc != nil {
+		return m.getGroupMembersFunc(ctx, req)
+	}
+	return nil, 0, errors.New("not implemented")
+}
+
 func (m *mockGroupRepository) SearchGroupsByExtension(ctx context.Context, key string, value interface{}, page, pageSize int) ([]group.UniversalGroup, error) {
 	return nil, errors.New("not implemented")
 }
@@ -177,6 +194,13 @@ func (m *mockGroupRepository) BulkUpdateGroupsStatus(ctx context.Context, groupI
 	return errors.New("not implemented")
 }
 
+func (m *mockGroupRepository) GetGroupsWithPendingInvitations(ctx context.Context) ([]group.UniversalGroup, error) {
+	if m.getGroupsWithPendingInvitesFunc != nil {
+		return m.getGroupsWithPendingInvitesFunc(ctx)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockGroupRepository) GetGroupsStatsCounts(ctx context.Context) (*group.AllGroupsStats, error) {
 	return nil, errors.New("not implemented")
 }
@@ -347,6 +371,95 @@ func TestService_GetGroupByID(t *testing.T) {
 	}
 }
 
+func TestService_GetGroupMembers(t *testing.T) {
+	t.Parallel()
+
+	members := []group.Member{
+		{ID: "user-1", Type: group.MemberTypeUser, Role: group.MemberRoleAdmin},
+		{ID: "user-2", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+		{ID: "user-3", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+	}
+
+	tests := []struct {
+		name               string
+		request            *group.GetGroupMembersRequest
+		expectedMemberIDs  []string
+		expectedTotal      int
+		expectedTotalPages int
+		expectedPage       int
+		expectedError      error
+	}{
+		{
+			name:              "Success - returns all members when no page is requested",
+			request:           &group.GetGroupMembersRequest{GroupID: testGroupID},
+			expectedMemberIDs: []string{"user-1", "user-2", "user-3"},
+		},
+		{
+			name:               "Success - returns requested page of members",
+			request:            &group.GetGroupMembersRequest{GroupID: testGroupID, PerPage: 2},
+			expectedMemberIDs:  []string{"user-1", "user-2"},
+			expectedTotal:      3,
+			expectedTotalPages: 2,
+			expectedPage:       1,
+		},
+		{
+			name:               "Success - returns last page of members",
+			request:            &group.GetGroupMembersRequest{GroupID: testGroupID, Page: 2, PerPage: 2},
+			expectedMemberIDs:  []string{"user-3"},
+			expectedTotal:      3,
+			expectedTotalPages: 2,
+			expectedPage:       2,
+		},
+		{
+			name:          "Failure - negative page size",
+			request:       &group.GetGroupMembersRequest{GroupID: testGroupID, PerPage: -1},
+			expectedError: group.ErrInvalidQueryParam,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockGroupRepository{
+				getGroupMembersFunc: func(ctx context.Context, req *group.GetGroupMembersRequest) ([]group.Member, int64, error) {
+					if req.PerPage == 0 {
+						return members, int64(len(members)), nil
+					}
+
+					start := (req.Page - 1) * req.PerPage
+					end := start + req.PerPage
+					if end > len(members) {
+						end = len(members)
+					}
+					return members[start:end], int64(len(members)), nil
+				},
+			}
+
+			svc := newTestService(repo, &mockAuditService{})
+
+			response, err := svc.GetGroupMembers(context.Background(), tt.request)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+
+			memberIDs := []string{}
+			for _, member := range response.Members {
+				memberIDs = append(memberIDs, member.ID)
+			}
+			assert.Equal(t, tt.expectedMemberIDs, memberIDs)
+			assert.Equal(t, len(tt.expectedMemberIDs), response.Count)
+			assert.Equal(t, tt.expectedTotal, response.Total)
+			assert.Equal(t, tt.expectedTotalPages, response.TotalPages)
+			assert.Equal(t, tt.expectedPage, response.Page)
+		})
+	}
+}
+
 func TestService_UpdateGroup(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,123 @@
+package group
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ParseMemberImportEntries reads the members to import from a CSV or JSON upload.
+//
+// CSV uploads have a header row with an email column and an optional role column,
+// in any order and case. A file whose first row starts with an email is read as
+// email and role columns without a header. JSON uploads are an array of
+// {"email", "role"} objects, or an object holding that array as "members"
+func ParseMemberImportEntries(format string, reader io.Reader) ([]MemberImportEntry, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case MemberImportFormatCSV:
+		return parseMemberImportCSV(reader)
+	case MemberImportFormatJSON:
+		return parseMemberImportJSON(reader)
+	default:
+		return nil, ErrUnsupportedMemberImportFormat
+	}
+}
+
+// parseMemberImportCSV reads import entries from CSV
+func parseMemberImportCSV(reader io.Reader) ([]MemberImportEntry, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	emailColumn, roleColumn := 0, 1
+	entries := []MemberImportEntry{}
+	for line := 0; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidMemberImport
+		}
+
+		if line == 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			if !strings.Contains(record[0], "@") {
+				emailColumn, roleColumn = memberImportCSVColumns(record)
+				if emailColumn < 0 {
+					return nil, ErrInvalidMemberImport
+				}
+				continue
+			}
+		}
+
+		entry := MemberImportEntry{}
+		if emailColumn < len(record) {
+			entry.Email = record[emailColumn]
+		}
+		if roleColumn >= 0 && roleColumn < len(record) {
+			entry.Role = record[roleColumn]
+		}
+		if strings.TrimSpace(entry.Email) == "" && strings.TrimSpace(entry.Role) == "" {
+			continue
+		}
+
+		if len(entries) == DefaultMaxMemberImportRows {
+			return nil, ErrMemberImportTooLarge
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrInvalidMemberImport
+	}
+
+	return entries, nil
+}
+
+// memberImportCSVColumns finds the email and role columns in a CSV header, -1 when
+// the column is missing
+func memberImportCSVColumns(header []string) (int, int) {
+	emailColumn, roleColumn := -1, -1
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "email", "email_address", "email address":
+			emailColumn = i
+		case "role":
+			roleColumn = i
+		}
+	}
+
+	return emailColumn, roleColumn
+}
+
+// parseMemberImportJSON reads import entries from JSON
+func parseMemberImportJSON(reader io.Reader) ([]MemberImportEntry, error) {
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, ErrInvalidMemberImport
+	}
+
+	entries := []MemberImportEntry{}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		wrapped := struct {
+			Members []MemberImportEntry `json:"members"`
+		}{}
+		err = json.Unmarshal(trimmed, &wrapped)
+		entries = wrapped.Members
+	} else {
+		err = json.Unmarshal(trimmed, &entries)
+	}
+	if err != nil || len(entries) == 0 {
+		return nil, ErrInvalidMemberImport
+	}
+
+	if len(entries) > DefaultMaxMemberImportRows {
+		return nil, ErrMemberImportTooLarge
+	}
+
+	return entries, nil
+}
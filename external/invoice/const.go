@@ -0,0 +1,63 @@
+// Package invoice issues sequentially numbered invoices for successful payments recorded as
+// billing events, keeps the invoice history of each user and renders invoices as HTML or PDF
+// documents without relying on the payment provider's receipts.
+package invoice
+
+// InvoiceStatus identifies where an invoice is in its lifecycle.
+type InvoiceStatus string
+
+const (
+	// InvoiceStatusPaid is an invoice issued for a payment that has been taken.
+	InvoiceStatusPaid InvoiceStatus = "paid"
+)
+
+// RenderFormat identifies the document format an invoice is rendered in.
+type RenderFormat string
+
+const (
+	// RenderFormatHTML renders the invoice as a standalone HTML page.
+	RenderFormatHTML RenderFormat = "html"
+	// RenderFormatPDF renders the invoice as a single page PDF document.
+	RenderFormatPDF RenderFormat = "pdf"
+)
+
+const (
+	// InvoiceCollection is the mongo collection name for invoices.
+	InvoiceCollection string = "invoices"
+
+	// InvoiceCounterCollection is the mongo collection name for the per business entity
+	// invoice number sequences.
+	InvoiceCounterCollection string = "invoice_counters"
+)
+
+const (
+	// DefaultBusinessEntityID is the ID given to the business entity passed to NewService
+	// when it does not set its own.
+	DefaultBusinessEntityID = "default"
+
+	// defaultInvoiceNumberPrefix is put in front of invoice numbers when the business entity
+	// does not set its own prefix.
+	defaultInvoiceNumberPrefix = "INV"
+
+	// invoiceNumberDigits is how many digits the sequence part of an invoice number is padded to.
+	invoiceNumberDigits = 6
+)
+
+const (
+	// ErrKeyBillingEventIsRequired is returned when an invoice is requested without a billing event.
+	ErrKeyBillingEventIsRequired = "InvoiceBillingEventIsRequired"
+	// ErrKeyBillingEventNotInvoiceable is returned when the billing event is not a successful payment with an amount.
+	ErrKeyBillingEventNotInvoiceable = "InvoiceBillingEventNotInvoiceable"
+	// ErrKeyUnknownBusinessEntity is returned when an invoice is requested for a business entity that is not configured.
+	ErrKeyUnknownBusinessEntity = "InvoiceUnknownBusinessEntity"
+	// ErrKeyInvoiceNotFound is returned when an invoice cannot be found.
+	ErrKeyInvoiceNotFound = "InvoiceNotFound"
+	// ErrKeyInvoiceAlreadyIssued is returned by repositories when the billing event already has an invoice.
+	ErrKeyInvoiceAlreadyIssued = "InvoiceAlreadyIssued"
+	// ErrKeyInvalidRenderFormat is returned when an invoice is rendered in an unsupported format.
+	ErrKeyInvalidRenderFormat = "InvoiceInvalidRenderFormat"
+	// ErrKeyInvoiceRenderFailed is returned when an invoice document cannot be produced.
+	ErrKeyInvoiceRenderFailed = "InvoiceRenderFailed"
+	// ErrKeyDatabaseError is returned when persistence fails unexpectedly.
+	ErrKeyDatabaseError = "InvoiceDatabaseError"
+)
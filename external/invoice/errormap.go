@@ -0,0 +1,59 @@
+package invoice
+
+import (
+	"net/http"
+
+	"github.com/ooaklee/reply/v2"
+)
+
+// InvoiceErrorMap maps invoice sentinel errors to API response metadata.
+var InvoiceErrorMap reply.ErrorManifest = reply.ErrorManifest{
+	ErrBillingEventIsRequired: {
+		Title:      "Missing Billing Event",
+		StatusCode: http.StatusBadRequest,
+		Code:       "INV00-001",
+		Detail:     "Please provide the billing event to invoice",
+	},
+	ErrBillingEventNotInvoiceable: {
+		Title:      "Billing Event Not Invoiceable",
+		StatusCode: http.StatusUnprocessableEntity,
+		Code:       "INV00-002",
+		Detail:     "Only successful payments with an amount can be invoiced",
+	},
+	ErrUnknownBusinessEntity: {
+		Title:      "Unknown Business Entity",
+		StatusCode: http.StatusBadRequest,
+		Code:       "INV00-003",
+		Detail:     "The business entity to invoice from is not configured",
+	},
+	ErrInvoiceNotFound: {
+		Title:      "Invoice Not Found",
+		StatusCode: http.StatusNotFound,
+		Code:       "INV00-004",
+		Detail:     "The requested invoice could not be found",
+	},
+	ErrInvoiceAlreadyIssued: {
+		Title:      "Invoice Already Issued",
+		StatusCode: http.StatusConflict,
+		Code:       "INV00-005",
+		Detail:     "An invoice has already been issued for this payment",
+	},
+	ErrInvalidRenderFormat: {
+		Title:      "Invalid Invoice Format",
+		StatusCode: http.StatusBadRequest,
+		Code:       "INV00-006",
+		Detail:     "Invoices can be downloaded as pdf or html",
+	},
+	ErrInvoiceRenderFailed: {
+		Title:      "Internal Error",
+		StatusCode: http.StatusInternalServerError,
+		Code:       "INV00-007",
+		Detail:     "Unable to produce the invoice document at this time",
+	},
+	ErrDatabaseError: {
+		Title:      "Internal Error",
+		StatusCode: http.StatusInternalServerError,
+		Code:       "INV00-008",
+		Detail:     "Unable to complete the invoice operation at this time",
+	},
+}
@@ -0,0 +1,22 @@
+package invoice
+
+import "errors"
+
+var (
+	// ErrBillingEventIsRequired means no billing event was given to invoice.
+	ErrBillingEventIsRequired = errors.New(ErrKeyBillingEventIsRequired)
+	// ErrBillingEventNotInvoiceable means the billing event is not a successful payment with an amount.
+	ErrBillingEventNotInvoiceable = errors.New(ErrKeyBillingEventNotInvoiceable)
+	// ErrDatabaseError means persistence failed unexpectedly.
+	ErrDatabaseError = errors.New(ErrKeyDatabaseError)
+	// ErrInvalidRenderFormat means the invoice cannot be rendered in the requested format.
+	ErrInvalidRenderFormat = errors.New(ErrKeyInvalidRenderFormat)
+	// ErrInvoiceAlreadyIssued means the billing event already has an invoice.
+	ErrInvoiceAlreadyIssued = errors.New(ErrKeyInvoiceAlreadyIssued)
+	// ErrInvoiceNotFound means the invoice could not be found.
+	ErrInvoiceNotFound = errors.New(ErrKeyInvoiceNotFound)
+	// ErrInvoiceRenderFailed means the invoice document could not be produced.
+	ErrInvoiceRenderFailed = errors.New(ErrKeyInvoiceRenderFailed)
+	// ErrUnknownBusinessEntity means the business entity is not configured.
+	ErrUnknownBusinessEntity = errors.New(ErrKeyUnknownBusinessEntity)
+)
@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitInvoicesIndexesUp creates indexes for invoices. The unique billing event index is what
+// stops a payment being invoiced twice when several instances process the same webhook.
+func InitInvoicesIndexesUp(db *mongo.Database) error {
+	log.SetFlags(0)
+	const mongoCollectionName = invoice.InvoiceCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-invoices-indexes"))
+
+	billingEventIndexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "billing_event_id", Value: 1}},
+		Options: options.Index().
+			SetName("idx_invoices_billing_event_id").
+			SetUnique(true),
+	}
+
+	businessEntityNumberIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "business_entity_id", Value: 1},
+			{Key: "number", Value: 1},
+		},
+		Options: options.Index().
+			SetName("idx_invoices_business_entity_number").
+			SetUnique(true),
+	}
+
+	userIssuedAtIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "issued_at", Value: -1},
+		},
+		Options: options.Index().SetName("idx_invoices_user_issued_at"),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			billingEventIndexModel,
+			businessEntityNumberIndexModel,
+			userIssuedAtIndexModel,
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-invoices-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-invoices-indexes"))
+	return nil
+}
+
+// InitInvoicesIndexesDown drops invoice indexes.
+func InitInvoicesIndexesDown(db *mongo.Database) error {
+	log.SetFlags(0)
+	const mongoCollectionName = invoice.InvoiceCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-invoices-indexes"))
+
+	indexNames := []string{
+		"idx_invoices_billing_event_id",
+		"idx_invoices_business_entity_number",
+		"idx_invoices_user_issued_at",
+	}
+
+	for _, indexName := range indexNames {
+		err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), indexName)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: "+indexName))
+			return err
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-invoices-indexes"))
+	return nil
+}
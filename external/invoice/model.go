@@ -0,0 +1,205 @@
+package invoice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/toolbox"
+)
+
+// Invoice is the document issued for one successful payment. Seller and customer details
+// are copied onto the invoice when it is issued so later changes to either do not alter
+// invoices already sent.
+type Invoice struct {
+	// ID is the internal unique identifier
+	ID string `json:"id" bson:"_id"`
+
+	// Number is the human readable invoice number, {prefix}-{sequence}
+	Number string `json:"number" bson:"number"`
+
+	// Sequence is the position of the invoice in its business entity's numbering
+	Sequence int64 `json:"sequence" bson:"sequence"`
+
+	// BusinessEntityID references the business entity that issued the invoice
+	BusinessEntityID string `json:"business_entity_id" bson:"business_entity_id"`
+
+	// Status is where the invoice is in its lifecycle
+	Status InvoiceStatus `json:"status" bson:"status"`
+
+	// UserID references the user the invoice was issued to
+	UserID string `json:"user_id" bson:"user_id"`
+
+	// BillingEventID references the billing event the invoice was issued for, an event is
+	// only ever invoiced once
+	BillingEventID string `json:"billing_event_id" bson:"billing_event_id"`
+
+	// SubscriptionID references the subscription the payment was for, if any
+	SubscriptionID string `json:"subscription_id,omitempty" bson:"subscription_id,omitempty"`
+
+	// Integrator is the payment provider that took the payment
+	Integrator string `json:"integrator" bson:"integrator"`
+
+	// IntegratorEventID is the provider's ID for the payment event
+	IntegratorEventID string `json:"integrator_event_id,omitempty" bson:"integrator_event_id,omitempty"`
+
+	// Seller is the business entity as it was when the invoice was issued
+	Seller BusinessEntity `json:"seller" bson:"seller"`
+
+	// Customer is who was billed, as they were when the invoice was issued
+	Customer Customer `json:"customer" bson:"customer"`
+
+	// Currency is the ISO 4217 currency code
+	Currency string `json:"currency" bson:"currency"`
+
+	// LineItems are the charges that make up the invoice
+	LineItems []LineItem `json:"line_items" bson:"line_items"`
+
+	// Subtotal is the total before tax (in cents)
+	Subtotal int64 `json:"subtotal" bson:"subtotal"`
+
+	// TaxName is the display name of the tax charged, e.g. VAT
+	TaxName string `json:"tax_name,omitempty" bson:"tax_name,omitempty"`
+
+	// TaxRateBps is the tax rate charged in basis points
+	TaxRateBps int64 `json:"tax_rate_bps,omitempty" bson:"tax_rate_bps,omitempty"`
+
+	// TaxAmount is the tax included in the total (in cents)
+	TaxAmount int64 `json:"tax_amount" bson:"tax_amount"`
+
+	// Total is the amount paid (in cents)
+	Total int64 `json:"total" bson:"total"`
+
+	// ProviderReceiptURL is the payment provider's own receipt, if it sent one
+	ProviderReceiptURL string `json:"provider_receipt_url,omitempty" bson:"provider_receipt_url,omitempty"`
+
+	// IssuedAt is when the invoice was issued
+	IssuedAt string `json:"issued_at" bson:"issued_at"`
+
+	// PaidAt is when the payment was taken
+	PaidAt string `json:"paid_at,omitempty" bson:"paid_at,omitempty"`
+
+	// EmailedAt is when the invoice was last emailed to the customer
+	EmailedAt string `json:"emailed_at,omitempty" bson:"emailed_at,omitempty"`
+
+	// CreatedAt is when the invoice was stored in internal system
+	CreatedAt string `json:"created_at" bson:"created_at"`
+
+	// UpdatedAt is when the invoice was last updated in internal system
+	UpdatedAt string `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// SetCreatedAtTimeToNow sets the created at date and time for the invoice to now
+func (i *Invoice) SetCreatedAtTimeToNow() *Invoice {
+	i.CreatedAt = toolbox.TimeNowUTC()
+	return i
+}
+
+// SetUpdatedAtTimeToNow sets the updated at date and time for the invoice to now
+func (i *Invoice) SetUpdatedAtTimeToNow() *Invoice {
+	i.UpdatedAt = toolbox.TimeNowUTC()
+	return i
+}
+
+// FileName returns the name the invoice is downloaded as in the given format
+func (i *Invoice) FileName(format RenderFormat) string {
+	return "invoice-" + i.Number + "." + string(format)
+}
+
+// LineItem is one charge on an invoice
+type LineItem struct {
+	// Description is what was charged for
+	Description string `json:"description" bson:"description"`
+
+	// Quantity is how many units were charged
+	Quantity int64 `json:"quantity" bson:"quantity"`
+
+	// UnitAmount is the price of one unit before tax (in cents)
+	UnitAmount int64 `json:"unit_amount" bson:"unit_amount"`
+
+	// Amount is the line total before tax (in cents)
+	Amount int64 `json:"amount" bson:"amount"`
+}
+
+// BusinessEntity is a legal entity invoices are issued from. Each entity numbers its
+// invoices in its own sequence.
+type BusinessEntity struct {
+	// ID identifies the entity. Default: default
+	ID string `json:"id" bson:"id"`
+
+	// Name is the legal name printed on invoices
+	Name string `json:"name" bson:"name"`
+
+	// Email is the contact address printed on invoices
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+
+	// Address is the registered address printed on invoices
+	Address *Address `json:"address,omitempty" bson:"address,omitempty"`
+
+	// TaxID is the entity's VAT or other tax registration number
+	TaxID string `json:"tax_id,omitempty" bson:"tax_id,omitempty"`
+
+	// InvoicePrefix is put in front of the entity's invoice numbers. Default: INV
+	InvoicePrefix string `json:"invoice_prefix,omitempty" bson:"invoice_prefix,omitempty"`
+
+	// Footer is printed at the bottom of invoices, e.g. payment terms or company number
+	Footer string `json:"footer,omitempty" bson:"footer,omitempty"`
+}
+
+// invoiceNumber returns the invoice number for the sequence
+func (e *BusinessEntity) invoiceNumber(sequence int64) string {
+	prefix := e.InvoicePrefix
+	if prefix == "" {
+		prefix = defaultInvoiceNumberPrefix
+	}
+
+	return fmt.Sprintf("%s-%0*d", prefix, invoiceNumberDigits, sequence)
+}
+
+// Customer is who an invoice is billed to
+type Customer struct {
+	// Name is the person or company billed
+	Name string `json:"name,omitempty" bson:"name,omitempty"`
+
+	// Email is the address the payment was made with
+	Email string `json:"email" bson:"email"`
+
+	// TaxID is the customer's VAT or other tax registration number
+	TaxID string `json:"tax_id,omitempty" bson:"tax_id,omitempty"`
+
+	// Address is the customer's billing address, if they gave one
+	Address *Address `json:"address,omitempty" bson:"address,omitempty"`
+}
+
+// Address is a postal address
+type Address struct {
+	Line1      string `json:"line1,omitempty" bson:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty" bson:"line2,omitempty"`
+	City       string `json:"city,omitempty" bson:"city,omitempty"`
+	Region     string `json:"region,omitempty" bson:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty" bson:"postal_code,omitempty"`
+
+	// Country is the ISO 3166-1 alpha-2 country code
+	Country string `json:"country,omitempty" bson:"country,omitempty"`
+}
+
+// Lines returns the non-empty lines of the address in the order they are printed
+func (a *Address) Lines() []string {
+	if a == nil {
+		return nil
+	}
+
+	locality := a.City
+	if a.City != "" && a.Region != "" {
+		locality += ", "
+	}
+	locality += a.Region
+
+	lines := []string{}
+	for _, line := range []string{a.Line1, a.Line2, locality, a.PostalCode, a.Country} {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,200 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/common"
+)
+
+// zeroDecimalCurrencies are the ISO 4217 currencies whose amounts have no minor unit
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true, "KMF": true,
+	"KRW": true, "PYG": true, "RWF": true, "UGX": true, "VND": true, "VUV": true, "XAF": true,
+	"XOF": true, "XPF": true,
+}
+
+// invoiceView is the invoice formatted for printing, shared by the HTML and PDF renderers
+type invoiceView struct {
+	Number        string
+	IssuedDate    string
+	PaidDate      string
+	SellerName    string
+	SellerLines   []string
+	CustomerName  string
+	CustomerLines []string
+	LineItems     []lineItemView
+	Subtotal      string
+	TaxLabel      string
+	Tax           string
+	Total         string
+	ReceiptURL    string
+	Footer        string
+}
+
+// lineItemView is a line item formatted for printing
+type lineItemView struct {
+	Description string
+	Quantity    string
+	UnitAmount  string
+	Amount      string
+}
+
+// newInvoiceView formats the invoice for printing
+func newInvoiceView(invoice *Invoice) *invoiceView {
+	view := &invoiceView{
+		Number:       invoice.Number,
+		IssuedDate:   formatDate(invoice.IssuedAt),
+		PaidDate:     formatDate(invoice.PaidAt),
+		SellerName:   invoice.Seller.Name,
+		SellerLines:  invoice.Seller.Address.Lines(),
+		CustomerName: invoice.Customer.Name,
+		Subtotal:     FormatAmount(invoice.Subtotal, invoice.Currency),
+		Total:        FormatAmount(invoice.Total, invoice.Currency),
+		ReceiptURL:   invoice.ProviderReceiptURL,
+		Footer:       invoice.Seller.Footer,
+	}
+
+	if invoice.Seller.Email != "" {
+		view.SellerLines = append(view.SellerLines, invoice.Seller.Email)
+	}
+	if invoice.Seller.TaxID != "" {
+		view.SellerLines = append(view.SellerLines, "Tax ID: "+invoice.Seller.TaxID)
+	}
+
+	if view.CustomerName == "" {
+		view.CustomerName = invoice.Customer.Email
+	} else if invoice.Customer.Email != "" {
+		view.CustomerLines = append(view.CustomerLines, invoice.Customer.Email)
+	}
+	view.CustomerLines = append(view.CustomerLines, invoice.Customer.Address.Lines()...)
+	if invoice.Customer.TaxID != "" {
+		view.CustomerLines = append(view.CustomerLines, "Tax ID: "+invoice.Customer.TaxID)
+	}
+
+	for _, item := range invoice.LineItems {
+		view.LineItems = append(view.LineItems, lineItemView{
+			Description: item.Description,
+			Quantity:    fmt.Sprintf("%d", item.Quantity),
+			UnitAmount:  FormatAmount(item.UnitAmount, invoice.Currency),
+			Amount:      FormatAmount(item.Amount, invoice.Currency),
+		})
+	}
+
+	if invoice.TaxRateBps > 0 {
+		taxName := invoice.TaxName
+		if taxName == "" {
+			taxName = "Tax"
+		}
+		view.TaxLabel = fmt.Sprintf("%s (%s%%, included)", taxName, formatRate(invoice.TaxRateBps))
+		view.Tax = FormatAmount(invoice.TaxAmount, invoice.Currency)
+	}
+
+	return view
+}
+
+// FormatAmount formats an amount in minor units with its currency, e.g. GBP 20.00
+func FormatAmount(amount int64, currency string) string {
+	currency = strings.ToUpper(currency)
+	if zeroDecimalCurrencies[currency] {
+		return fmt.Sprintf("%s %d", currency, amount)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s%d.%02d", currency, sign, amount/100, amount%100))
+}
+
+// formatRate formats a rate in basis points as a percentage without trailing zeros
+func formatRate(rateBps int64) string {
+	rate := fmt.Sprintf("%d.%02d", rateBps/100, rateBps%100)
+	return strings.TrimSuffix(strings.TrimRight(rate, "0"), ".")
+}
+
+// formatDate formats a stored timestamp as a date, returning it unchanged if it cannot be parsed
+func formatDate(timestamp string) string {
+	if timestamp == "" {
+		return ""
+	}
+
+	parsed, err := time.Parse(common.RFC3339NanoUTC, timestamp)
+	if err != nil {
+		return timestamp
+	}
+
+	return parsed.Format("02 January 2006")
+}
+
+// invoiceHTMLTemplate lays the invoice out as a standalone page that also works as an email body
+var invoiceHTMLTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}}</title>
+</head>
+<body style="font-family: Helvetica, Arial, sans-serif; color: #222; max-width: 640px; margin: 0 auto; padding: 24px;">
+<h1 style="font-size: 24px; margin: 0 0 4px;">Invoice</h1>
+<p style="margin: 0 0 24px; color: #555;">
+Number: {{.Number}}<br>
+Issued: {{.IssuedDate}}{{if .PaidDate}}<br>
+Paid: {{.PaidDate}}{{end}}
+</p>
+<table style="width: 100%; margin-bottom: 24px;" cellspacing="0" cellpadding="0">
+<tr>
+<td style="vertical-align: top; width: 50%;">
+<strong>From</strong><br>
+{{.SellerName}}{{range .SellerLines}}<br>
+{{.}}{{end}}
+</td>
+<td style="vertical-align: top; width: 50%;">
+<strong>Billed to</strong><br>
+{{.CustomerName}}{{range .CustomerLines}}<br>
+{{.}}{{end}}
+</td>
+</tr>
+</table>
+<table style="width: 100%; border-collapse: collapse;">
+<thead>
+<tr style="border-bottom: 1px solid #ccc; text-align: left;">
+<th style="padding: 8px 0;">Description</th>
+<th style="padding: 8px 0; text-align: right;">Qty</th>
+<th style="padding: 8px 0; text-align: right;">Unit price</th>
+<th style="padding: 8px 0; text-align: right;">Amount</th>
+</tr>
+</thead>
+<tbody>
+{{range .LineItems}}<tr style="border-bottom: 1px solid #eee;">
+<td style="padding: 8px 0;">{{.Description}}</td>
+<td style="padding: 8px 0; text-align: right;">{{.Quantity}}</td>
+<td style="padding: 8px 0; text-align: right;">{{.UnitAmount}}</td>
+<td style="padding: 8px 0; text-align: right;">{{.Amount}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+<table style="width: 100%; margin-top: 16px;">
+<tr><td style="text-align: right;">Subtotal</td><td style="text-align: right; width: 140px;">{{.Subtotal}}</td></tr>
+{{if .TaxLabel}}<tr><td style="text-align: right;">{{.TaxLabel}}</td><td style="text-align: right;">{{.Tax}}</td></tr>
+{{end}}<tr><td style="text-align: right;"><strong>Total paid</strong></td><td style="text-align: right;"><strong>{{.Total}}</strong></td></tr>
+</table>
+{{if .ReceiptURL}}<p style="margin-top: 24px;"><a href="{{.ReceiptURL}}">View the payment provider's receipt</a></p>
+{{end}}{{if .Footer}}<p style="margin-top: 32px; font-size: 12px; color: #777;">{{.Footer}}</p>
+{{end}}</body>
+</html>
+`))
+
+// renderInvoiceHTML renders the invoice as a standalone HTML page
+func renderInvoiceHTML(invoice *Invoice) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := invoiceHTMLTemplate.Execute(&buffer, newInvoiceView(invoice)); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,213 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// pdfPageWidth and pdfPageHeight are the A4 page size in points
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+
+	// pdfMargin is the space left around the page content in points
+	pdfMargin = 50
+)
+
+// pdfFont identifies one of the standard PDF fonts every reader provides, so no font data
+// has to be embedded
+type pdfFont string
+
+const (
+	pdfFontRegular pdfFont = "F1"
+	pdfFontBold    pdfFont = "F2"
+)
+
+// pdfDocument lays out lines of text on A4 pages, starting a new page when one is full
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// newPDFDocument returns a document with one empty page
+func newPDFDocument() *pdfDocument {
+	document := &pdfDocument{}
+	document.newPage()
+	return document
+}
+
+// newPage starts a new page and moves to its top
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// line moves down by the font size plus spacing and writes each text at its x position.
+// Empty texts are skipped.
+func (d *pdfDocument) line(font pdfFont, size float64, texts map[float64]string) {
+	d.y -= size * 1.4
+	if d.y < pdfMargin {
+		d.newPage()
+		d.y -= size * 1.4
+	}
+
+	page := d.pages[len(d.pages)-1]
+	for _, x := range sortedPositions(texts) {
+		if texts[x] == "" {
+			continue
+		}
+		fmt.Fprintf(page, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, x, d.y, pdfEscape(texts[x]))
+	}
+}
+
+// rule draws a horizontal line across the page below the current position
+func (d *pdfDocument) rule() {
+	d.y -= 6
+	fmt.Fprintf(d.pages[len(d.pages)-1], "0.8 G 0.5 w %d %.1f m %d %.1f l S 0 G\n", pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+}
+
+// space moves down without writing anything
+func (d *pdfDocument) space(points float64) {
+	d.y -= points
+}
+
+// bytes serialises the document, numbering objects so the cross-reference table points at
+// each one: catalog, page tree, the two fonts, then a page and its content for every page
+func (d *pdfDocument) bytes() []byte {
+	var (
+		output  bytes.Buffer
+		offsets []int
+	)
+
+	writeObject := func(body string) {
+		offsets = append(offsets, output.Len())
+		fmt.Fprintf(&output, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	const firstPageObject = 5
+	pageReferences := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageReferences[i] = fmt.Sprintf("%d 0 R", firstPageObject+i*2)
+	}
+
+	output.WriteString("%PDF-1.4\n")
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageReferences, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPageObject+i*2+1,
+		))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	crossReferenceOffset := output.Len()
+	fmt.Fprintf(&output, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&output, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&output, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, crossReferenceOffset)
+
+	return output.Bytes()
+}
+
+// renderInvoicePDF renders the invoice as an A4 PDF document
+func renderInvoicePDF(invoice *Invoice) ([]byte, error) {
+	view := newInvoiceView(invoice)
+	document := newPDFDocument()
+
+	const (
+		left         = pdfMargin
+		middle       = pdfPageWidth / 2
+		quantityX    = 330
+		unitAmountX  = 380
+		amountX      = 470
+		totalsLabelX = 330
+	)
+
+	document.line(pdfFontBold, 22, map[float64]string{left: "Invoice"})
+	document.space(4)
+	document.line(pdfFontRegular, 10, map[float64]string{left: "Number: " + view.Number})
+	document.line(pdfFontRegular, 10, map[float64]string{left: "Issued: " + view.IssuedDate})
+	if view.PaidDate != "" {
+		document.line(pdfFontRegular, 10, map[float64]string{left: "Paid: " + view.PaidDate})
+	}
+
+	document.space(16)
+	document.line(pdfFontBold, 10, map[float64]string{left: "From", middle: "Billed to"})
+	document.line(pdfFontRegular, 10, map[float64]string{left: view.SellerName, middle: view.CustomerName})
+	for i := 0; i < len(view.SellerLines) || i < len(view.CustomerLines); i++ {
+		document.line(pdfFontRegular, 10, map[float64]string{left: lineAt(view.SellerLines, i), middle: lineAt(view.CustomerLines, i)})
+	}
+
+	document.space(20)
+	document.line(pdfFontBold, 10, map[float64]string{left: "Description", quantityX: "Qty", unitAmountX: "Unit price", amountX: "Amount"})
+	document.rule()
+	for _, item := range view.LineItems {
+		document.line(pdfFontRegular, 10, map[float64]string{left: item.Description, quantityX: item.Quantity, unitAmountX: item.UnitAmount, amountX: item.Amount})
+	}
+	document.rule()
+
+	document.space(8)
+	document.line(pdfFontRegular, 10, map[float64]string{totalsLabelX: "Subtotal", amountX: view.Subtotal})
+	if view.TaxLabel != "" {
+		document.line(pdfFontRegular, 10, map[float64]string{totalsLabelX - 80: view.TaxLabel, amountX: view.Tax})
+	}
+	document.line(pdfFontBold, 11, map[float64]string{totalsLabelX: "Total paid", amountX: view.Total})
+
+	if view.Footer != "" {
+		document.space(30)
+		for _, footerLine := range strings.Split(view.Footer, "\n") {
+			document.line(pdfFontRegular, 8, map[float64]string{left: footerLine})
+		}
+	}
+
+	return document.bytes(), nil
+}
+
+// lineAt returns the line at the index, or an empty string past the end
+func lineAt(lines []string, index int) string {
+	if index < len(lines) {
+		return lines[index]
+	}
+	return ""
+}
+
+// sortedPositions returns the x positions of the texts from left to right
+func sortedPositions(texts map[float64]string) []float64 {
+	positions := make([]float64, 0, len(texts))
+	for x := range texts {
+		positions = append(positions, x)
+	}
+	sort.Float64s(positions)
+	return positions
+}
+
+// pdfEscape converts text to a WinAnsi encoded PDF string literal body, escaping the
+// characters PDF treats specially and replacing characters the encoding cannot show
+func pdfEscape(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r == '\t':
+			escaped.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			escaped.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&escaped, "\\%03o", r)
+		case r == '€':
+			escaped.WriteString("\\200")
+		default:
+			escaped.WriteByte('?')
+		}
+	}
+	return escaped.String()
+}
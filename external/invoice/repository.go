@@ -0,0 +1,246 @@
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const defaultCollectionInitMaxAttemptsLimit = 3
+
+// MongoDbStore describes the MongoDB helper operations the invoice repository uses.
+type MongoDbStore interface {
+	ExecuteCountDocuments(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error)
+	ExecuteFindCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
+	ExecuteInsertOneCommand(ctx context.Context, collection *mongo.Collection, document interface{}, resultObjectName string) (*mongo.InsertOneResult, error)
+	ExecuteFindOneCommandDecodeResult(ctx context.Context, collection *mongo.Collection, filter interface{}, result interface{}, resultObjectName string, logError bool, onFailureErr error) error
+	ExecuteUpdateOneCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, update interface{}, resultObjectName string) error
+
+	GetDatabase(ctx context.Context, dbName string) (*mongo.Database, error)
+	InitialiseClient(ctx context.Context) (*mongo.Client, error)
+	MapAllInCursorToResult(ctx context.Context, cursor *mongo.Cursor, result interface{}, resultObjectName string) error
+}
+
+// Repository manages invoices and invoice number sequences in MongoDB.
+type Repository struct {
+	Store                          MongoDbStore
+	collectionInitMaxAttemptsLimit int
+
+	collections     map[string]*mongo.Collection
+	collectionMutex sync.Mutex
+}
+
+var _ InvoiceRepository = (*Repository)(nil)
+
+// NewRepository returns an invoice repository backed by the provided MongoDB store.
+func NewRepository(store MongoDbStore) *Repository {
+	return &Repository{
+		Store:                          store,
+		collectionInitMaxAttemptsLimit: defaultCollectionInitMaxAttemptsLimit,
+		collections:                    make(map[string]*mongo.Collection),
+	}
+}
+
+// WithCollectionInitMaxAttemptsLimit overrides collection initialisation retry attempts.
+func (r *Repository) WithCollectionInitMaxAttemptsLimit(limit int) *Repository {
+	if limit > 0 {
+		r.collectionInitMaxAttemptsLimit = limit
+	}
+	return r
+}
+
+// GetInvoiceCollection returns the invoices collection, initialising it lazily.
+func (r *Repository) GetInvoiceCollection(ctx context.Context) (*mongo.Collection, error) {
+	return r.getCollection(ctx, InvoiceCollection)
+}
+
+// GetInvoiceCounterCollection returns the invoice number sequences collection, initialising it lazily.
+func (r *Repository) GetInvoiceCounterCollection(ctx context.Context) (*mongo.Collection, error) {
+	return r.getCollection(ctx, InvoiceCounterCollection)
+}
+
+// getCollection returns the named collection, initialising it lazily.
+func (r *Repository) getCollection(ctx context.Context, name string) (*mongo.Collection, error) {
+	r.collectionMutex.Lock()
+	defer r.collectionMutex.Unlock()
+
+	if collection, ok := r.collections[name]; ok {
+		return collection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if r.collections == nil {
+			r.collections = make(map[string]*mongo.Collection)
+		}
+		r.collections[name] = db.Collection(name)
+		return r.collections[name], nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, name, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// NextInvoiceSequence atomically increments and returns the business entity's invoice
+// number sequence, starting at 1.
+func (r *Repository) NextInvoiceSequence(ctx context.Context, businessEntityID string) (int64, error) {
+	collection, err := r.GetInvoiceCounterCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		Sequence int64 `bson:"sequence"`
+	}
+	err = collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": businessEntityID},
+		bson.M{"$inc": bson.M{"sequence": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&result)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return result.Sequence, nil
+}
+
+// CreateInvoice persists a new invoice. The billing event ID is uniquely indexed, so an
+// event that was already invoiced is reported as ErrInvoiceAlreadyIssued.
+func (r *Repository) CreateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error) {
+	collection, err := r.GetInvoiceCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.CreatedAt == "" {
+		invoice.SetCreatedAtTimeToNow()
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, invoice, "invoice")
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrInvoiceAlreadyIssued
+		}
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// GetInvoiceByID retrieves one invoice by its ID.
+func (r *Repository) GetInvoiceByID(ctx context.Context, id string) (*Invoice, error) {
+	return r.getInvoice(ctx, bson.M{"_id": id})
+}
+
+// GetInvoiceByBillingEventID retrieves the invoice issued for a billing event.
+func (r *Repository) GetInvoiceByBillingEventID(ctx context.Context, billingEventID string) (*Invoice, error) {
+	return r.getInvoice(ctx, bson.M{"billing_event_id": billingEventID})
+}
+
+// getInvoice retrieves the invoice matching the filter.
+func (r *Repository) getInvoice(ctx context.Context, filter bson.M) (*Invoice, error) {
+	collection, err := r.GetInvoiceCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Invoice
+	err = r.Store.ExecuteFindOneCommandDecodeResult(ctx, collection, filter, &result, "invoice", false, ErrInvoiceNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateInvoice replaces one invoice by ID.
+func (r *Repository) UpdateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error) {
+	collection, err := r.GetInvoiceCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.SetUpdatedAtTimeToNow()
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": invoice.ID}, bson.M{"$set": invoice}, "invoice")
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// GetInvoices returns a page of invoices matching the request filters.
+func (r *Repository) GetInvoices(ctx context.Context, req *GetInvoicesRequest) ([]Invoice, error) {
+	collection, err := r.GetInvoiceCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortDirection := -1
+	if req.Order == "issued_at_asc" {
+		sortDirection = 1
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((req.Page - 1) * req.PerPage)).
+		SetLimit(int64(req.PerPage)).
+		SetSort(bson.D{{Key: "issued_at", Value: sortDirection}, {Key: "sequence", Value: sortDirection}})
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, buildInvoiceFilter(req), findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := []Invoice{}
+	if err = r.Store.MapAllInCursorToResult(ctx, cursor, &invoices, "invoices"); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// GetTotalInvoices counts invoices matching the request filters.
+func (r *Repository) GetTotalInvoices(ctx context.Context, req *GetInvoicesRequest) (int64, error) {
+	collection, err := r.GetInvoiceCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Store.ExecuteCountDocuments(ctx, collection, buildInvoiceFilter(req))
+}
+
+// buildInvoiceFilter converts invoice list filters to a Mongo query.
+func buildInvoiceFilter(req *GetInvoicesRequest) bson.M {
+	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
+
+	if req.UserID != "" {
+		queryFilter["user_id"] = req.UserID
+	}
+
+	if req.BusinessEntityID != "" {
+		queryFilter["business_entity_id"] = req.BusinessEntityID
+	}
+
+	return queryFilter
+}
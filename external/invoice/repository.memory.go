@@ -0,0 +1,159 @@
+package invoice
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// InMemoryRepository is an in-memory implementation of the invoice repository
+// Useful for testing and development
+type InMemoryRepository struct {
+	invoices  map[string]*Invoice
+	sequences map[string]int64
+	mu        sync.RWMutex
+}
+
+var _ InvoiceRepository = (*InMemoryRepository)(nil)
+
+// NewInMemoryRepository creates a new in-memory invoice repository
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		invoices:  make(map[string]*Invoice),
+		sequences: make(map[string]int64),
+	}
+}
+
+// NextInvoiceSequence increments and returns the business entity's invoice number sequence
+func (m *InMemoryRepository) NextInvoiceSequence(ctx context.Context, businessEntityID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sequences[businessEntityID]++
+
+	return m.sequences[businessEntityID], nil
+}
+
+// CreateInvoice stores a new invoice
+func (m *InMemoryRepository) CreateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.invoices {
+		if existing.ID == invoice.ID || existing.BillingEventID == invoice.BillingEventID {
+			return nil, ErrInvoiceAlreadyIssued
+		}
+	}
+
+	if invoice.CreatedAt == "" {
+		invoice.SetCreatedAtTimeToNow()
+	}
+
+	m.invoices[invoice.ID] = copyInvoice(invoice)
+
+	return invoice, nil
+}
+
+// GetInvoiceByID retrieves one invoice by its ID
+func (m *InMemoryRepository) GetInvoiceByID(ctx context.Context, id string) (*Invoice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	invoice, ok := m.invoices[id]
+	if !ok {
+		return nil, ErrInvoiceNotFound
+	}
+
+	return copyInvoice(invoice), nil
+}
+
+// GetInvoiceByBillingEventID retrieves the invoice issued for a billing event
+func (m *InMemoryRepository) GetInvoiceByBillingEventID(ctx context.Context, billingEventID string) (*Invoice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, invoice := range m.invoices {
+		if invoice.BillingEventID == billingEventID {
+			return copyInvoice(invoice), nil
+		}
+	}
+
+	return nil, ErrInvoiceNotFound
+}
+
+// UpdateInvoice replaces one invoice by ID
+func (m *InMemoryRepository) UpdateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.invoices[invoice.ID]; !ok {
+		return nil, ErrInvoiceNotFound
+	}
+
+	invoice.SetUpdatedAtTimeToNow()
+	m.invoices[invoice.ID] = copyInvoice(invoice)
+
+	return invoice, nil
+}
+
+// GetInvoices returns a page of invoices matching the request filters
+func (m *InMemoryRepository) GetInvoices(ctx context.Context, req *GetInvoicesRequest) ([]Invoice, error) {
+	invoices := m.filterInvoices(req)
+
+	sort.Slice(invoices, func(i, j int) bool {
+		if invoices[i].IssuedAt == invoices[j].IssuedAt {
+			if req.Order == "issued_at_asc" {
+				return invoices[i].Sequence < invoices[j].Sequence
+			}
+			return invoices[i].Sequence > invoices[j].Sequence
+		}
+		if req.Order == "issued_at_asc" {
+			return invoices[i].IssuedAt < invoices[j].IssuedAt
+		}
+		return invoices[i].IssuedAt > invoices[j].IssuedAt
+	})
+
+	start := (req.Page - 1) * req.PerPage
+	if start >= len(invoices) {
+		return []Invoice{}, nil
+	}
+
+	end := start + req.PerPage
+	if end > len(invoices) {
+		end = len(invoices)
+	}
+
+	return invoices[start:end], nil
+}
+
+// GetTotalInvoices counts invoices matching the request filters
+func (m *InMemoryRepository) GetTotalInvoices(ctx context.Context, req *GetInvoicesRequest) (int64, error) {
+	return int64(len(m.filterInvoices(req))), nil
+}
+
+// filterInvoices returns copies of the stored invoices matching the request filters
+func (m *InMemoryRepository) filterInvoices(req *GetInvoicesRequest) []Invoice {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	invoices := []Invoice{}
+	for _, invoice := range m.invoices {
+		if req.UserID != "" && invoice.UserID != req.UserID {
+			continue
+		}
+		if req.BusinessEntityID != "" && invoice.BusinessEntityID != req.BusinessEntityID {
+			continue
+		}
+
+		invoices = append(invoices, *copyInvoice(invoice))
+	}
+
+	return invoices
+}
+
+// copyInvoice returns a copy of the invoice that shares no line items with it
+func copyInvoice(invoice *Invoice) *Invoice {
+	copied := *invoice
+	copied.LineItems = append([]LineItem(nil), invoice.LineItems...)
+	return &copied
+}
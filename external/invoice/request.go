@@ -0,0 +1,68 @@
+package invoice
+
+import "github.com/ooaklee/ghatd/external/billing"
+
+// CreateInvoiceFromBillingEventRequest holds everything needed to invoice a billing event
+type CreateInvoiceFromBillingEventRequest struct {
+	// BillingEvent is the successful payment to invoice
+	BillingEvent *billing.BillingEvent
+
+	// Customer is who is billed. Default: the billing event's email
+	Customer *Customer
+
+	// BusinessEntityID is the business entity issuing the invoice. Default: the entity
+	// passed to NewService
+	BusinessEntityID string
+}
+
+// GetInvoicesRequest holds everything needed to list invoices
+type GetInvoicesRequest struct {
+	// Order defines how should response be sorted. Default: newest -> oldest (issued_at_desc)
+	// Valid options: issued_at_asc, issued_at_desc
+	Order string `query:"order"`
+
+	// Total number of invoices to return per page, if available. Default 25.
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from. Default 1.
+	Page int `query:"page"`
+
+	// TotalCount specifies the total count of all invoices
+	TotalCount int
+
+	// TotalPages specifies the total pages of results
+	TotalPages int
+
+	// Meta whether response should contain meta information
+	Meta bool `query:"meta"`
+
+	// UserID is the user to list invoices for
+	UserID string
+
+	// BusinessEntityID is the business entity to filter by
+	BusinessEntityID string `query:"business_entity_id"`
+}
+
+// GetInvoiceByIDRequest holds everything needed to get an invoice
+type GetInvoiceByIDRequest struct {
+	// ID is the invoice ID
+	ID string
+}
+
+// RenderInvoiceRequest holds everything needed to render an invoice document
+type RenderInvoiceRequest struct {
+	// ID is the invoice to render, ignored when Invoice is set
+	ID string
+
+	// Invoice is an already loaded invoice to render
+	Invoice *Invoice
+
+	// Format is the document format. Default: pdf
+	Format RenderFormat
+}
+
+// MarkInvoiceEmailedRequest holds everything needed to record that an invoice was emailed
+type MarkInvoiceEmailedRequest struct {
+	// ID is the invoice ID
+	ID string
+}
@@ -0,0 +1,71 @@
+package invoice
+
+import "github.com/ooaklee/ghatd/external/toolbox"
+
+// CreateInvoiceFromBillingEventResponse holds the invoice issued for a billing event
+type CreateInvoiceFromBillingEventResponse struct {
+	// Invoice is the issued invoice, or the invoice previously issued for the billing event
+	Invoice *Invoice `json:"invoice"`
+
+	// Duplicate is true when the billing event had already been invoiced
+	Duplicate bool `json:"duplicate"`
+}
+
+// GetInvoicesResponse holds everything needed to return
+// the response to get invoices
+type GetInvoicesResponse struct {
+	Invoices []Invoice `json:"invoices"`
+
+	// Total number of invoices found that matched provided
+	// filters
+	Total int
+
+	// TotalPages total pages available, based on the provided
+	// filters and resources per page
+	TotalPages int
+
+	// PerPage number of invoices set to be returned per page
+	PerPage int
+
+	// Page specifies the page results were taken from. Default 1.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetInvoicesResponse,
+// including the number of resources per page, total resources, total pages,
+// and the current page.
+func (g *GetInvoicesResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
+
+// GetInvoiceByIDResponse holds the requested invoice
+type GetInvoiceByIDResponse struct {
+	Invoice *Invoice `json:"invoice"`
+}
+
+// RenderInvoiceResponse holds a rendered invoice document
+type RenderInvoiceResponse struct {
+	// Invoice is the invoice that was rendered
+	Invoice *Invoice
+
+	// Content is the document
+	Content []byte
+
+	// ContentType is the MIME type of the document
+	ContentType string
+
+	// FileName is the name the document should be downloaded as
+	FileName string
+}
+
+// MarkInvoiceEmailedResponse holds the invoice after recording it was emailed
+type MarkInvoiceEmailedResponse struct {
+	Invoice *Invoice `json:"invoice"`
+}
@@ -0,0 +1,374 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/pricer"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// InvoiceRepository describes the persistence operations needed for invoices.
+type InvoiceRepository interface {
+	NextInvoiceSequence(ctx context.Context, businessEntityID string) (int64, error)
+	CreateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error)
+	GetInvoiceByID(ctx context.Context, id string) (*Invoice, error)
+	GetInvoiceByBillingEventID(ctx context.Context, billingEventID string) (*Invoice, error)
+	UpdateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error)
+	GetInvoices(ctx context.Context, req *GetInvoicesRequest) ([]Invoice, error)
+	GetTotalInvoices(ctx context.Context, req *GetInvoicesRequest) (int64, error)
+}
+
+// Service issues invoices for billing events, lists them and renders them as documents.
+type Service struct {
+	InvoiceRepository InvoiceRepository
+
+	// TaxRules is optional; when set the tax included in each payment is shown on the
+	// invoice using the rate for the customer's billing country
+	TaxRules pricer.PriceTaxRules
+
+	defaultBusinessEntityID string
+	businessEntities        map[string]BusinessEntity
+	issueLocksMu            sync.Mutex
+	issueLocks              map[string]*issueLock
+	now                     func() time.Time
+}
+
+// NewService returns an invoice service issuing invoices from the given business entity
+// unless a request names another one.
+func NewService(invoiceRepository InvoiceRepository, businessEntity BusinessEntity) *Service {
+	if businessEntity.ID == "" {
+		businessEntity.ID = DefaultBusinessEntityID
+	}
+
+	return &Service{
+		InvoiceRepository:       invoiceRepository,
+		defaultBusinessEntityID: businessEntity.ID,
+		businessEntities:        map[string]BusinessEntity{businessEntity.ID: businessEntity},
+		issueLocks:              make(map[string]*issueLock),
+		now:                     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithBusinessEntity adds another business entity invoices can be issued from. Each entity
+// numbers its invoices separately.
+func (s *Service) WithBusinessEntity(businessEntity BusinessEntity) *Service {
+	s.businessEntities[businessEntity.ID] = businessEntity
+	return s
+}
+
+// WithTaxRules shows the tax included in each payment on its invoice.
+func (s *Service) WithTaxRules(taxRules pricer.PriceTaxRules) *Service {
+	s.TaxRules = taxRules
+	return s
+}
+
+// CreateInvoiceFromBillingEvent issues the invoice for a successful payment. Billing event
+// amounts are what the customer was charged, so any tax is shown as included in the total.
+// Invoicing the same billing event again returns the original invoice.
+func (s *Service) CreateInvoiceFromBillingEvent(ctx context.Context, req *CreateInvoiceFromBillingEventRequest) (*CreateInvoiceFromBillingEventResponse, error) {
+	if req.BillingEvent == nil || req.BillingEvent.ID == "" {
+		return nil, ErrBillingEventIsRequired
+	}
+
+	event := req.BillingEvent
+	logger := logger.AcquireOperationFrom(ctx, "external/invoice", "create-invoice-from-billing-event").With(
+		zap.String("billing-event-id", event.ID),
+		zap.String("user-id", event.UserID),
+	)
+
+	if event.EventType != paymentprovider.EventTypePaymentSucceeded || event.Amount <= 0 {
+		logger.Warn("billing-event-not-invoiceable", zap.String("event-type", event.EventType), zap.Int64("amount", event.Amount))
+		return nil, ErrBillingEventNotInvoiceable
+	}
+
+	businessEntityID := req.BusinessEntityID
+	if businessEntityID == "" {
+		businessEntityID = s.defaultBusinessEntityID
+	}
+
+	businessEntity, ok := s.businessEntities[businessEntityID]
+	if !ok {
+		logger.Warn("unknown-business-entity", zap.String("business-entity-id", businessEntityID))
+		return nil, ErrUnknownBusinessEntity
+	}
+
+	unlock := s.lockBillingEvent(event.ID)
+	defer unlock()
+
+	existing, err := s.InvoiceRepository.GetInvoiceByBillingEventID(ctx, event.ID)
+	if err == nil {
+		return &CreateInvoiceFromBillingEventResponse{Invoice: existing, Duplicate: true}, nil
+	}
+	if !errors.Is(err, ErrInvoiceNotFound) {
+		logger.Error("failed-to-check-for-existing-invoice", zap.Error(err))
+		return nil, err
+	}
+
+	customer := Customer{Email: event.Email}
+	if req.Customer != nil {
+		customer = *req.Customer
+		if customer.Email == "" {
+			customer.Email = event.Email
+		}
+	}
+
+	invoice := &Invoice{
+		ID:                 toolbox.GenerateUuidV4(),
+		BusinessEntityID:   businessEntity.ID,
+		Status:             InvoiceStatusPaid,
+		UserID:             event.UserID,
+		BillingEventID:     event.ID,
+		SubscriptionID:     event.SubscriptionID,
+		Integrator:         event.Integrator,
+		IntegratorEventID:  event.IntegratorEventID,
+		Seller:             businessEntity,
+		Customer:           customer,
+		Currency:           strings.ToUpper(event.Currency),
+		Total:              event.Amount,
+		ProviderReceiptURL: event.ReceiptURL,
+		IssuedAt:           s.now().Format(common.RFC3339NanoUTC),
+	}
+
+	if !event.ProviderEventTime.IsZero() {
+		invoice.PaidAt = event.ProviderEventTime.UTC().Format(common.RFC3339NanoUTC)
+	}
+
+	if err := s.applyTax(ctx, invoice); err != nil {
+		logger.Error("failed-to-get-invoice-tax-rate", zap.Error(err))
+		return nil, err
+	}
+
+	description := event.PlanName
+	if description == "" {
+		description = "Payment"
+	}
+	invoice.LineItems = []LineItem{{
+		Description: description,
+		Quantity:    1,
+		UnitAmount:  invoice.Subtotal,
+		Amount:      invoice.Subtotal,
+	}}
+
+	sequence, err := s.InvoiceRepository.NextInvoiceSequence(ctx, businessEntity.ID)
+	if err != nil {
+		logger.Error("failed-to-get-next-invoice-sequence", zap.String("business-entity-id", businessEntity.ID), zap.Error(err))
+		return nil, err
+	}
+	invoice.Sequence = sequence
+	invoice.Number = businessEntity.invoiceNumber(sequence)
+
+	created, err := s.InvoiceRepository.CreateInvoice(ctx, invoice)
+	if errors.Is(err, ErrInvoiceAlreadyIssued) {
+		// Another instance invoiced the event first; its invoice is the one to keep
+		existing, getErr := s.InvoiceRepository.GetInvoiceByBillingEventID(ctx, event.ID)
+		if getErr != nil {
+			return nil, err
+		}
+		return &CreateInvoiceFromBillingEventResponse{Invoice: existing, Duplicate: true}, nil
+	}
+	if err != nil {
+		logger.Error("failed-to-create-invoice", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("invoice-issued", zap.String("invoice-id", created.ID), zap.String("invoice-number", created.Number))
+
+	return &CreateInvoiceFromBillingEventResponse{Invoice: created}, nil
+}
+
+// GetInvoices returns a paginated list of invoices, newest first by default.
+func (s *Service) GetInvoices(ctx context.Context, req *GetInvoicesRequest) (*GetInvoicesResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/invoice")
+
+	// Set defaults
+	if req.Order == "" {
+		req.Order = "issued_at_desc"
+	}
+
+	if req.PerPage == 0 {
+		req.PerPage = 25
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	total, err := s.InvoiceRepository.GetTotalInvoices(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-invoices-total", zap.Error(err))
+		return nil, err
+	}
+	req.TotalCount = int(total)
+
+	invoices, err := s.InvoiceRepository.GetInvoices(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-invoices", zap.Error(err))
+		return nil, err
+	}
+
+	paginatedResponse, err := toolbox.Paginate(ctx, &toolbox.PaginationRequest{
+		PerPage: req.PerPage,
+		Page:    req.Page,
+	}, invoices, req.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetInvoicesResponse{
+		Invoices:   paginatedResponse.Resources,
+		Total:      paginatedResponse.Total,
+		TotalPages: paginatedResponse.TotalPages,
+		PerPage:    paginatedResponse.ResourcePerPage,
+		Page:       paginatedResponse.Page,
+	}, nil
+}
+
+// GetInvoiceByID returns one invoice.
+func (s *Service) GetInvoiceByID(ctx context.Context, req *GetInvoiceByIDRequest) (*GetInvoiceByIDResponse, error) {
+	invoice, err := s.InvoiceRepository.GetInvoiceByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetInvoiceByIDResponse{Invoice: invoice}, nil
+}
+
+// RenderInvoice renders an invoice as a PDF or HTML document.
+func (s *Service) RenderInvoice(ctx context.Context, req *RenderInvoiceRequest) (*RenderInvoiceResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/invoice", "render-invoice")
+
+	format := req.Format
+	if format == "" {
+		format = RenderFormatPDF
+	}
+	if format != RenderFormatPDF && format != RenderFormatHTML {
+		return nil, ErrInvalidRenderFormat
+	}
+
+	invoice := req.Invoice
+	if invoice == nil {
+		var err error
+		invoice, err = s.InvoiceRepository.GetInvoiceByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		content     []byte
+		contentType string
+		err         error
+	)
+	switch format {
+	case RenderFormatHTML:
+		content, err = renderInvoiceHTML(invoice)
+		contentType = "text/html; charset=utf-8"
+	case RenderFormatPDF:
+		content, err = renderInvoicePDF(invoice)
+		contentType = "application/pdf"
+	}
+	if err != nil {
+		logger.Error("failed-to-render-invoice", zap.String("invoice-id", invoice.ID), zap.String("format", string(format)), zap.Error(err))
+		return nil, ErrInvoiceRenderFailed
+	}
+
+	return &RenderInvoiceResponse{
+		Invoice:     invoice,
+		Content:     content,
+		ContentType: contentType,
+		FileName:    invoice.FileName(format),
+	}, nil
+}
+
+// MarkInvoiceEmailed records that the invoice was emailed to the customer.
+func (s *Service) MarkInvoiceEmailed(ctx context.Context, req *MarkInvoiceEmailedRequest) (*MarkInvoiceEmailedResponse, error) {
+	invoice, err := s.InvoiceRepository.GetInvoiceByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.EmailedAt = s.now().Format(common.RFC3339NanoUTC)
+
+	invoice, err = s.InvoiceRepository.UpdateInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MarkInvoiceEmailedResponse{Invoice: invoice}, nil
+}
+
+// applyTax splits the invoice total into its subtotal and the tax it includes, using the
+// rate for the customer's billing country
+func (s *Service) applyTax(ctx context.Context, invoice *Invoice) error {
+	invoice.Subtotal = invoice.Total
+
+	if s.TaxRules == nil || invoice.Customer.Address == nil || invoice.Customer.Address.Country == "" {
+		return nil
+	}
+
+	rate, err := s.TaxRules.GetPriceTaxRate(ctx, invoice.Customer.Address.Country, invoice.Currency)
+	if err != nil {
+		return err
+	}
+	if rate == nil || rate.RateBps <= 0 {
+		return nil
+	}
+
+	invoice.TaxName = rate.Name
+	invoice.TaxRateBps = rate.RateBps
+	invoice.TaxAmount = includedTax(invoice.Total, rate.RateBps)
+	invoice.Subtotal = invoice.Total - invoice.TaxAmount
+
+	return nil
+}
+
+// issueLock serialises invoicing of one billing event. holders counts the callers holding or
+// waiting for it, so it can be dropped once nobody needs it.
+type issueLock struct {
+	mu      sync.Mutex
+	holders int
+}
+
+// lockBillingEvent locks invoicing of the billing event, returning the unlock function. The
+// lock is removed once its last holder unlocks, so locks do not build up for every billing
+// event ever invoiced.
+func (s *Service) lockBillingEvent(billingEventID string) func() {
+	s.issueLocksMu.Lock()
+	if s.issueLocks == nil {
+		s.issueLocks = make(map[string]*issueLock)
+	}
+	lock, ok := s.issueLocks[billingEventID]
+	if !ok {
+		lock = &issueLock{}
+		s.issueLocks[billingEventID] = lock
+	}
+	lock.holders++
+	s.issueLocksMu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		s.issueLocksMu.Lock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(s.issueLocks, billingEventID)
+		}
+		s.issueLocksMu.Unlock()
+	}
+}
+
+// includedTax returns the tax included in a gross amount at the rate, rounded half up
+func includedTax(gross int64, rateBps int64) int64 {
+	divisor := 10000 + rateBps
+	return (gross*rateBps + divisor/2) / divisor
+}
@@ -0,0 +1,213 @@
+package invoice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+var testNow = time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+
+func newTestService() *Service {
+	service := NewService(NewInMemoryRepository(), BusinessEntity{
+		Name:          "Acme Ltd",
+		Email:         "billing@acme.test",
+		Address:       &Address{Line1: "1 High Street", City: "London", PostalCode: "N1 1AA", Country: "GB"},
+		TaxID:         "GB123456789",
+		InvoicePrefix: "ACME",
+	}).WithTaxRules(pricer.NewStaticPriceTaxRules(pricer.PriceTaxRate{Country: "GB", Name: "VAT", RateBps: 2000, Inclusive: true}))
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func testBillingEvent(id string) *billing.BillingEvent {
+	return &billing.BillingEvent{
+		ID:                id,
+		UserID:            "user-1",
+		Email:             "jane@example.com",
+		EventType:         paymentprovider.EventTypePaymentSucceeded,
+		Integrator:        "kofi",
+		IntegratorEventID: "evt-" + id,
+		Amount:            1200,
+		Currency:          "gbp",
+		PlanName:          "Pro (Monthly)",
+		ProviderEventTime: testNow.Add(-time.Minute),
+	}
+}
+
+func TestCreateInvoiceFromBillingEventNumbersSequentiallyPerBusinessEntity(t *testing.T) {
+	service := newTestService().WithBusinessEntity(BusinessEntity{ID: "eu", Name: "Acme BV", InvoicePrefix: "EU"})
+	ctx := context.Background()
+
+	numbers := []string{}
+	for _, req := range []*CreateInvoiceFromBillingEventRequest{
+		{BillingEvent: testBillingEvent("be-1")},
+		{BillingEvent: testBillingEvent("be-2")},
+		{BillingEvent: testBillingEvent("be-3"), BusinessEntityID: "eu"},
+	} {
+		response, err := service.CreateInvoiceFromBillingEvent(ctx, req)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		numbers = append(numbers, response.Invoice.Number)
+	}
+
+	if strings.Join(numbers, ",") != "ACME-000001,ACME-000002,EU-000001" {
+		t.Fatalf("unexpected invoice numbers %v", numbers)
+	}
+}
+
+func TestCreateInvoiceFromBillingEventIsIdempotent(t *testing.T) {
+	service := newTestService()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	responses := make([]*CreateInvoiceFromBillingEventResponse, 5)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], _ = service.CreateInvoiceFromBillingEvent(ctx, &CreateInvoiceFromBillingEventRequest{BillingEvent: testBillingEvent("be-1")})
+		}(i)
+	}
+	wg.Wait()
+
+	duplicates := 0
+	for _, response := range responses {
+		if response == nil || response.Invoice.Number != "ACME-000001" {
+			t.Fatalf("expected every call to return the first invoice, got %+v", response)
+		}
+		if response.Duplicate {
+			duplicates++
+		}
+	}
+	if duplicates != len(responses)-1 {
+		t.Fatalf("expected %d duplicates, got %d", len(responses)-1, duplicates)
+	}
+
+	list, _ := service.GetInvoices(ctx, &GetInvoicesRequest{UserID: "user-1"})
+	if list.Total != 1 {
+		t.Fatalf("expected one stored invoice, got %d", list.Total)
+	}
+	if len(service.issueLocks) != 0 {
+		t.Fatalf("expected released issue locks to be dropped, got %d", len(service.issueLocks))
+	}
+}
+
+func TestCreateInvoiceFromBillingEventShowsIncludedTaxForBillingCountry(t *testing.T) {
+	service := newTestService()
+
+	response, err := service.CreateInvoiceFromBillingEvent(context.Background(), &CreateInvoiceFromBillingEventRequest{
+		BillingEvent: testBillingEvent("be-1"),
+		Customer:     &Customer{Name: "Jane Doe", Address: &Address{Line1: "2 Low Road", Country: "GB"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	invoice := response.Invoice
+	if invoice.Total != 1200 || invoice.TaxAmount != 200 || invoice.Subtotal != 1000 || invoice.TaxName != "VAT" {
+		t.Fatalf("expected 1000 + 200 VAT = 1200, got %+v", invoice)
+	}
+	if invoice.LineItems[0].Amount != 1000 || invoice.Customer.Email != "jane@example.com" || invoice.Currency != "GBP" {
+		t.Fatalf("unexpected invoice %+v", invoice)
+	}
+
+	untaxed, _ := service.CreateInvoiceFromBillingEvent(context.Background(), &CreateInvoiceFromBillingEventRequest{
+		BillingEvent: testBillingEvent("be-2"),
+		Customer:     &Customer{Address: &Address{Country: "US"}},
+	})
+	if untaxed.Invoice.TaxAmount != 0 || untaxed.Invoice.Subtotal != 1200 {
+		t.Fatalf("expected no tax outside GB, got %+v", untaxed.Invoice)
+	}
+}
+
+func TestCreateInvoiceFromBillingEventRejectsNonPayments(t *testing.T) {
+	service := newTestService()
+
+	event := testBillingEvent("be-1")
+	event.EventType = paymentprovider.EventTypePaymentFailed
+
+	_, err := service.CreateInvoiceFromBillingEvent(context.Background(), &CreateInvoiceFromBillingEventRequest{BillingEvent: event})
+	if !errors.Is(err, ErrBillingEventNotInvoiceable) {
+		t.Fatalf("expected %v, got %v", ErrBillingEventNotInvoiceable, err)
+	}
+
+	_, err = service.CreateInvoiceFromBillingEvent(context.Background(), &CreateInvoiceFromBillingEventRequest{BillingEvent: testBillingEvent("be-2"), BusinessEntityID: "unknown"})
+	if !errors.Is(err, ErrUnknownBusinessEntity) {
+		t.Fatalf("expected %v, got %v", ErrUnknownBusinessEntity, err)
+	}
+}
+
+func TestRenderInvoice(t *testing.T) {
+	service := newTestService()
+	ctx := context.Background()
+
+	created, _ := service.CreateInvoiceFromBillingEvent(ctx, &CreateInvoiceFromBillingEventRequest{
+		BillingEvent: testBillingEvent("be-1"),
+		Customer:     &Customer{Name: "Zoë (Design) <Studio>", Address: &Address{Country: "GB"}},
+	})
+
+	htmlResponse, err := service.RenderInvoice(ctx, &RenderInvoiceRequest{ID: created.Invoice.ID, Format: RenderFormatHTML})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	html := string(htmlResponse.Content)
+	for _, expected := range []string{"ACME-000001", "Pro (Monthly)", "GBP 12.00", "VAT (20%, included)", "GBP 2.00", "&lt;Studio&gt;"} {
+		if !strings.Contains(html, expected) {
+			t.Fatalf("expected html to contain %q:\n%s", expected, html)
+		}
+	}
+	if htmlResponse.FileName != "invoice-ACME-000001.html" {
+		t.Fatalf("unexpected file name %q", htmlResponse.FileName)
+	}
+
+	pdfResponse, err := service.RenderInvoice(ctx, &RenderInvoiceRequest{Invoice: created.Invoice})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	pdf := pdfResponse.Content
+	if pdfResponse.ContentType != "application/pdf" || !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("expected a pdf document, got %q", pdfResponse.ContentType)
+	}
+	for _, expected := range []string{"(Number: ACME-000001)", "(Zo\\353 \\(Design\\) <Studio>)", "(GBP 12.00)"} {
+		if !bytes.Contains(pdf, []byte(expected)) {
+			t.Fatalf("expected pdf to contain %q", expected)
+		}
+	}
+
+	_, err = service.RenderInvoice(ctx, &RenderInvoiceRequest{Invoice: created.Invoice, Format: "docx"})
+	if !errors.Is(err, ErrInvalidRenderFormat) {
+		t.Fatalf("expected %v, got %v", ErrInvalidRenderFormat, err)
+	}
+}
+
+func TestPDFDocumentCrossReferencesObjects(t *testing.T) {
+	document := newPDFDocument()
+	for i := 0; i < 80; i++ {
+		document.line(pdfFontRegular, 10, map[float64]string{pdfMargin: "line"})
+	}
+	output := document.bytes()
+
+	if len(document.pages) != 2 || !bytes.Contains(output, []byte("/Count 2")) {
+		t.Fatalf("expected the document to overflow onto a second page, got %d pages", len(document.pages))
+	}
+
+	startXref := bytes.LastIndex(output, []byte("startxref\n"))
+	var offset int
+	if _, err := fmt.Sscan(string(output[startXref+len("startxref\n"):]), &offset); err != nil || !bytes.HasPrefix(output[offset:], []byte("xref\n")) {
+		t.Fatalf("expected startxref to point at the cross-reference table, got %d", offset)
+	}
+	if !bytes.Contains(output, []byte("\n7 0 obj\n<< /Type /Page ")) {
+		t.Fatalf("expected the second page to be object 7")
+	}
+}
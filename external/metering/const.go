@@ -0,0 +1,50 @@
+// Package metering records usage of metered pricer features, aggregates it per billing
+// period, enforces quotas from the subject's entitlements and can report usage to the
+// payment provider for metered billing.
+package metering
+
+// QuotaEnforcement identifies how a feature's quota is applied once it is used up.
+type QuotaEnforcement string
+
+const (
+	// QuotaEnforcementHard rejects usage that would exceed the quota.
+	QuotaEnforcementHard QuotaEnforcement = "hard"
+	// QuotaEnforcementSoft records usage beyond the quota and flags the subject as over it.
+	QuotaEnforcementSoft QuotaEnforcement = "soft"
+)
+
+const (
+	// UsageEventCollection is the mongo collection name for usage events.
+	UsageEventCollection string = "usage_events"
+)
+
+const (
+	// defaultWarningThresholdPercent is the share of a quota after which usage is flagged as nearing the limit.
+	defaultWarningThresholdPercent = 80
+
+	// lookupPageSize is the page size used when loading subscriptions to align usage periods with.
+	lookupPageSize = 100
+
+	// defaultReportBatchSize is how many unreported usage events are reported per run by default.
+	defaultReportBatchSize = 100
+
+	// IdempotencyKeyHeader is the request header the metering middleware reads the idempotency key from.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+const (
+	// ErrKeyIdempotencyKeyIsRequired is returned when usage is recorded without an idempotency key.
+	ErrKeyIdempotencyKeyIsRequired = "MeteringIdempotencyKeyIsRequired"
+	// ErrKeyInvalidUsageQuantity is returned when the usage quantity is zero or negative.
+	ErrKeyInvalidUsageQuantity = "MeteringInvalidUsageQuantity"
+	// ErrKeyInvalidOccurredAt is returned when the time the usage occurred cannot be parsed or is in the future.
+	ErrKeyInvalidOccurredAt = "MeteringInvalidOccurredAt"
+	// ErrKeyQuotaExceeded is returned when recording usage would exceed a hard quota.
+	ErrKeyQuotaExceeded = "MeteringQuotaExceeded"
+	// ErrKeyUsageEventNotFound is returned when a usage event cannot be found.
+	ErrKeyUsageEventNotFound = "MeteringUsageEventNotFound"
+	// ErrKeyUsageEventAlreadyRecorded is returned by repositories when the idempotency key was already used.
+	ErrKeyUsageEventAlreadyRecorded = "MeteringUsageEventAlreadyRecorded"
+	// ErrKeyDatabaseError is returned when persistence fails unexpectedly.
+	ErrKeyDatabaseError = "MeteringDatabaseError"
+)
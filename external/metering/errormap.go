@@ -0,0 +1,53 @@
+package metering
+
+import (
+	"net/http"
+
+	"github.com/ooaklee/reply/v2"
+)
+
+// MeteringErrorMap maps metering sentinel errors to API response metadata.
+var MeteringErrorMap reply.ErrorManifest = reply.ErrorManifest{
+	ErrIdempotencyKeyIsRequired: {
+		Title:      "Missing Idempotency Key",
+		StatusCode: http.StatusBadRequest,
+		Code:       "MTR00-001",
+		Detail:     "Please provide an idempotency key for the usage",
+	},
+	ErrInvalidUsageQuantity: {
+		Title:      "Invalid Usage Quantity",
+		StatusCode: http.StatusBadRequest,
+		Code:       "MTR00-002",
+		Detail:     "The usage quantity must be greater than zero",
+	},
+	ErrInvalidOccurredAt: {
+		Title:      "Invalid Usage Time",
+		StatusCode: http.StatusBadRequest,
+		Code:       "MTR00-003",
+		Detail:     "The usage time must be an RFC3339 timestamp that is not in the future",
+	},
+	ErrQuotaExceeded: {
+		Title:      "Quota Exceeded",
+		StatusCode: http.StatusTooManyRequests,
+		Code:       "MTR00-004",
+		Detail:     "You have used your plan's allowance for this feature in the current billing period",
+	},
+	ErrUsageEventNotFound: {
+		Title:      "Usage Not Found",
+		StatusCode: http.StatusNotFound,
+		Code:       "MTR00-005",
+		Detail:     "The requested usage event could not be found",
+	},
+	ErrUsageEventAlreadyRecorded: {
+		Title:      "Usage Already Recorded",
+		StatusCode: http.StatusConflict,
+		Code:       "MTR00-006",
+		Detail:     "Usage with this idempotency key has already been recorded",
+	},
+	ErrDatabaseError: {
+		Title:      "Internal Error",
+		StatusCode: http.StatusInternalServerError,
+		Code:       "MTR00-007",
+		Detail:     "Unable to complete the metering operation at this time",
+	},
+}
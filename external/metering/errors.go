@@ -0,0 +1,20 @@
+package metering
+
+import "errors"
+
+var (
+	// ErrDatabaseError means persistence failed unexpectedly.
+	ErrDatabaseError = errors.New(ErrKeyDatabaseError)
+	// ErrIdempotencyKeyIsRequired means usage was recorded without an idempotency key.
+	ErrIdempotencyKeyIsRequired = errors.New(ErrKeyIdempotencyKeyIsRequired)
+	// ErrInvalidOccurredAt means the time the usage occurred is invalid.
+	ErrInvalidOccurredAt = errors.New(ErrKeyInvalidOccurredAt)
+	// ErrInvalidUsageQuantity means the usage quantity is zero or negative.
+	ErrInvalidUsageQuantity = errors.New(ErrKeyInvalidUsageQuantity)
+	// ErrQuotaExceeded means recording the usage would exceed a hard quota.
+	ErrQuotaExceeded = errors.New(ErrKeyQuotaExceeded)
+	// ErrUsageEventAlreadyRecorded means a usage event with the same idempotency key exists.
+	ErrUsageEventAlreadyRecorded = errors.New(ErrKeyUsageEventAlreadyRecorded)
+	// ErrUsageEventNotFound means the usage event could not be found.
+	ErrUsageEventNotFound = errors.New(ErrKeyUsageEventNotFound)
+)
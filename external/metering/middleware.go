@@ -0,0 +1,126 @@
+package metering
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/errormanifest"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"github.com/ooaklee/reply/v2"
+	"go.uber.org/zap"
+)
+
+// usageRecorder defines the usage reservation used to meter routes
+type usageRecorder interface {
+	RecordUsage(ctx context.Context, req *RecordUsageRequest) (*RecordUsageResponse, error)
+	ConfirmUsage(ctx context.Context, req *ConfirmUsageRequest) (*RecordUsageResponse, error)
+	ReleaseUsage(ctx context.Context, req *ReleaseUsageRequest) error
+}
+
+// Middleware meters requests against the signed-in user's quotas
+type Middleware struct {
+	recorder  usageRecorder
+	errorMaps []reply.ErrorManifest
+}
+
+// NewMiddleware returns metering middleware. MeteringErrorMap and EntitlementErrorMap are always
+// the base layers, caller-supplied maps are applied as overrides.
+func NewMiddleware(recorder usageRecorder, errorMaps ...reply.ErrorManifest) *Middleware {
+	return &Middleware{
+		recorder:  recorder,
+		errorMaps: errorMaps,
+	}
+}
+
+// Meter returns a gorilla/mux middleware function that meters one unit of the feature for the
+// signed-in user. The unit is reserved before the handler runs, so requests are rejected up front
+// when the feature is not entitled or its hard quota is used up, including by requests still in
+// flight. The reservation is confirmed once the handler responds with a 2xx or 3xx status and
+// released otherwise, so failed requests are not charged. The Idempotency-Key request header is
+// used when present, so retried requests are only counted once. It must run after the
+// authentication middleware that places the user on the request context.
+func (m *Middleware) Meter(featureSlug string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := logger.AcquirePackageFrom(r.Context(), "external/metering")
+
+			userID := accessmanagerhelpers.AcquireFrom(r.Context())
+			if userID == "" {
+				logger.Warn("metered-request-without-user", zap.String("feature-slug", featureSlug), zap.String("path", r.URL.Path))
+				m.getBaseResponseHandler().NewHTTPErrorResponse(w, entitlement.ErrUnableToIdentifyUser)
+				return
+			}
+
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if idempotencyKey == "" {
+				idempotencyKey = toolbox.GenerateUuidV4()
+			}
+
+			reservation, err := m.recorder.RecordUsage(r.Context(), &RecordUsageRequest{
+				IdempotencyKey: featureSlug + ":" + idempotencyKey,
+				SubjectType:    entitlement.SubjectTypeUser,
+				SubjectID:      userID,
+				FeatureSlug:    featureSlug,
+				Quantity:       1,
+				Reserve:        true,
+			})
+			if err != nil {
+				logger.Info("request-blocked-by-metering", zap.String("feature-slug", featureSlug), zap.String("path", r.URL.Path), zap.Error(err))
+				m.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+				return
+			}
+
+			responseWriter := &statusResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(responseWriter, r)
+
+			// a retried request was already metered by the request it repeats
+			if reservation.Duplicate {
+				return
+			}
+
+			ctx := context.WithoutCancel(r.Context())
+			usageEventID := reservation.UsageEvent.ID
+
+			if responseWriter.statusCode >= http.StatusBadRequest {
+				logger.Debug("metered-request-failed-usage-released", zap.String("feature-slug", featureSlug), zap.String("path", r.URL.Path), zap.Int("status", responseWriter.statusCode))
+				if err := m.recorder.ReleaseUsage(ctx, &ReleaseUsageRequest{UsageEventID: usageEventID}); err != nil {
+					logger.Warn("failed-to-release-metered-request-usage", zap.String("feature-slug", featureSlug), zap.String("usage-event-id", usageEventID), zap.Error(err))
+				}
+				return
+			}
+
+			if _, err := m.recorder.ConfirmUsage(ctx, &ConfirmUsageRequest{UsageEventID: usageEventID}); err != nil {
+				logger.Warn("failed-to-confirm-metered-request-usage", zap.String("feature-slug", featureSlug), zap.String("usage-event-id", usageEventID), zap.Error(err))
+			}
+		})
+	}
+}
+
+// statusResponseWriter records the status code written by the metered handler. Handlers that
+// never call WriteHeader respond with 200 OK.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+// WriteHeader records the status code before writing it
+func (w *statusResponseWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// getBaseResponseHandler returns a response handler with MeteringErrorMap and
+// EntitlementErrorMap as the base layers
+func (m *Middleware) getBaseResponseHandler() *reply.Replier {
+	return reply.NewReplier(
+		errormanifest.NewComposer().
+			Add(MeteringErrorMap).
+			Add(entitlement.EntitlementErrorMap).
+			AddOverrides(m.errorMaps...).
+			Build(),
+	)
+}
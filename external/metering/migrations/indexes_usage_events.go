@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/metering"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitUsageEventsIndexesUp creates indexes for usage events.
+func InitUsageEventsIndexesUp(db *mongo.Database) error {
+	log.SetFlags(0)
+	const mongoCollectionName = metering.UsageEventCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-usage-events-indexes"))
+
+	subjectFeaturePeriodIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "subject_type", Value: 1},
+			{Key: "subject_id", Value: 1},
+			{Key: "feature_slug", Value: 1},
+			{Key: "occurred_at", Value: 1},
+		},
+		Options: options.Index().SetName("idx_usage_events_subject_feature_occurred"),
+	}
+
+	pendingReportIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "reported_at", Value: 1},
+			{Key: "meter_event_name", Value: 1},
+			{Key: "occurred_at", Value: 1},
+		},
+		Options: options.Index().SetName("idx_usage_events_pending_report"),
+	}
+
+	occurredAtIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "occurred_at", Value: -1}},
+		Options: options.Index().SetName("idx_usage_events_occurred_at"),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			subjectFeaturePeriodIndexModel,
+			pendingReportIndexModel,
+			occurredAtIndexModel,
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-usage-events-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-usage-events-indexes"))
+	return nil
+}
+
+// InitUsageEventsIndexesDown drops usage event indexes.
+func InitUsageEventsIndexesDown(db *mongo.Database) error {
+	log.SetFlags(0)
+	const mongoCollectionName = metering.UsageEventCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-usage-events-indexes"))
+
+	indexNames := []string{
+		"idx_usage_events_subject_feature_occurred",
+		"idx_usage_events_pending_report",
+		"idx_usage_events_occurred_at",
+	}
+
+	for _, indexName := range indexNames {
+		err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), indexName)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: "+indexName))
+			return err
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-usage-events-indexes"))
+	return nil
+}
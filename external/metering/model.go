@@ -0,0 +1,143 @@
+package metering
+
+import (
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/toolbox"
+)
+
+// UsageEvent is one recorded use of a metered feature by a user or group. The ID is derived
+// from the subject and idempotency key, so the same usage can only be recorded once.
+type UsageEvent struct {
+	// ID is the internal unique identifier, {subject_type}:{subject_id}:{idempotency_key}
+	ID string `json:"id" bson:"_id"`
+
+	// IdempotencyKey is the caller-supplied key identifying the usage
+	IdempotencyKey string `json:"idempotency_key" bson:"idempotency_key"`
+
+	// SubjectType is the kind of subject the usage belongs to (user or group)
+	SubjectType entitlement.SubjectType `json:"subject_type" bson:"subject_type"`
+
+	// SubjectID is the ID of the user or group the usage belongs to
+	SubjectID string `json:"subject_id" bson:"subject_id"`
+
+	// FeatureSlug references the pricer feature that was used
+	FeatureSlug string `json:"feature_slug" bson:"feature_slug"`
+
+	// Quantity is the amount used
+	Quantity int64 `json:"quantity" bson:"quantity"`
+
+	// OccurredAt is when the usage happened
+	OccurredAt string `json:"occurred_at" bson:"occurred_at"`
+
+	// PeriodStart is the start of the billing period the usage counted towards
+	PeriodStart string `json:"period_start" bson:"period_start"`
+
+	// PeriodEnd is the end of the billing period the usage counted towards
+	PeriodEnd string `json:"period_end" bson:"period_end"`
+
+	// Reserved is true while the usage is held for a request still being served. Reserved
+	// usage counts towards quotas but is not reported until it is confirmed
+	Reserved bool `json:"reserved,omitempty" bson:"reserved,omitempty"`
+
+	// OverQuota is true when the usage was recorded beyond a soft quota
+	OverQuota bool `json:"over_quota,omitempty" bson:"over_quota,omitempty"`
+
+	// SubscriptionID is the subscription the billing period was aligned to, if any
+	SubscriptionID string `json:"subscription_id,omitempty" bson:"subscription_id,omitempty"`
+
+	// MeterEventName is the provider meter the usage is reported to, empty when the
+	// usage is not reported
+	MeterEventName string `json:"meter_event_name,omitempty" bson:"meter_event_name,omitempty"`
+
+	// ProviderName is the payment provider the usage is reported to
+	ProviderName string `json:"provider_name,omitempty" bson:"provider_name,omitempty"`
+
+	// ProviderCustomerID is the provider customer the usage is reported for
+	ProviderCustomerID string `json:"provider_customer_id,omitempty" bson:"provider_customer_id,omitempty"`
+
+	// ReportedAt is when the usage was accepted by the payment provider
+	ReportedAt string `json:"reported_at,omitempty" bson:"reported_at,omitempty"`
+
+	// ReportAttempts is how many times reporting the usage has been attempted
+	ReportAttempts int `json:"report_attempts,omitempty" bson:"report_attempts,omitempty"`
+
+	// LastReportError is the error from the latest failed report attempt
+	LastReportError string `json:"last_report_error,omitempty" bson:"last_report_error,omitempty"`
+
+	// RecordedByUserID is the admin who recorded the usage on the subject's behalf, if any
+	RecordedByUserID string `json:"recorded_by_user_id,omitempty" bson:"recorded_by_user_id,omitempty"`
+
+	// CreatedAt is when the usage was stored in internal system
+	CreatedAt string `json:"created_at" bson:"created_at"`
+
+	// UpdatedAt is when the usage was last updated in internal system
+	UpdatedAt string `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// SetCreatedAtTimeToNow sets the created at date and time for the usage event to now
+func (e *UsageEvent) SetCreatedAtTimeToNow() *UsageEvent {
+	e.CreatedAt = toolbox.TimeNowUTC()
+	return e
+}
+
+// SetUpdatedAtTimeToNow sets the updated at date and time for the usage event to now
+func (e *UsageEvent) SetUpdatedAtTimeToNow() *UsageEvent {
+	e.UpdatedAt = toolbox.TimeNowUTC()
+	return e
+}
+
+// IsPendingReport returns true if the usage should be, but has not yet been, reported
+func (e *UsageEvent) IsPendingReport() bool {
+	return !e.Reserved && e.MeterEventName != "" && e.ProviderName != "" && e.ReportedAt == ""
+}
+
+// UsageEventID returns the ID of the usage event for the subject and idempotency key
+func UsageEventID(subjectType entitlement.SubjectType, subjectID string, idempotencyKey string) string {
+	return string(subjectType) + ":" + subjectID + ":" + idempotencyKey
+}
+
+// UsageSummary is a subject's consumption of one feature in the current billing period
+type UsageSummary struct {
+	// FeatureSlug references the pricer feature
+	FeatureSlug string `json:"feature_slug"`
+
+	// Unit indicates the unit usage is measured in
+	Unit string `json:"unit,omitempty"`
+
+	// Used is the total recorded in the period
+	Used int64 `json:"used"`
+
+	// Limit is the entitled allowance for the period, meaningful only when Unlimited is false
+	Limit int64 `json:"limit,omitempty"`
+
+	// Unlimited is true when the subject has no quota for the feature
+	Unlimited bool `json:"unlimited"`
+
+	// Remaining is what is left of the allowance, never below zero
+	Remaining int64 `json:"remaining,omitempty"`
+
+	// Enforcement is how the quota is applied once it is used up
+	Enforcement QuotaEnforcement `json:"enforcement"`
+
+	// WarningReached is true once usage passes the warning threshold of the quota
+	WarningReached bool `json:"warning_reached"`
+
+	// LimitReached is true once the quota is used up
+	LimitReached bool `json:"limit_reached"`
+
+	// PeriodStart is the start of the current billing period
+	PeriodStart string `json:"period_start"`
+
+	// PeriodEnd is the end of the current billing period
+	PeriodEnd string `json:"period_end"`
+}
+
+// QuotaPolicy describes how a feature's quota is enforced
+type QuotaPolicy struct {
+	// Enforcement is how the quota is applied once it is used up. Default: hard
+	Enforcement QuotaEnforcement
+
+	// WarningThresholdPercent is the share of the quota after which usage is flagged as
+	// nearing the limit. Default: 80
+	WarningThresholdPercent int
+}
@@ -0,0 +1,76 @@
+package metering
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/billing"
+)
+
+// maxPeriodSteps bounds how many billing intervals are walked to find the current period
+const maxPeriodSteps = 1200
+
+// billingPeriod returns the billing period containing at. Periods are aligned to the
+// subscription's next billing date and interval; without one, calendar months in UTC are used.
+func billingPeriod(subscription *billing.Subscription, at time.Time) (time.Time, time.Time) {
+	at = at.UTC()
+
+	if subscription == nil || subscription.NextBillingDate == nil || subscription.NextBillingDate.IsZero() {
+		return calendarMonth(at)
+	}
+
+	anchor := subscription.NextBillingDate.UTC()
+	interval := strings.ToLower(subscription.BillingInterval)
+
+	// Walk whole intervals from the anchor, so month lengths never make the periods drift
+	step := 0
+	for i := 0; i < maxPeriodSteps; i++ {
+		start := addIntervals(anchor, interval, step)
+		end := addIntervals(anchor, interval, step+1)
+
+		switch {
+		case at.Before(start):
+			step--
+		case !at.Before(end):
+			step++
+		default:
+			return start, end
+		}
+	}
+
+	return calendarMonth(at)
+}
+
+// calendarMonth returns the UTC calendar month containing at
+func calendarMonth(at time.Time) (time.Time, time.Time) {
+	start := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// addIntervals moves t by count billing intervals. Month and year steps keep the day of the
+// month, clamped to the last day of shorter months.
+func addIntervals(t time.Time, interval string, count int) time.Time {
+	switch interval {
+	case "day", "daily":
+		return t.AddDate(0, 0, count)
+	case "week", "weekly":
+		return t.AddDate(0, 0, 7*count)
+	case "year", "yearly", "annual", "annually":
+		return addMonthsClamped(t, 12*count)
+	default:
+		return addMonthsClamped(t, count)
+	}
+}
+
+// addMonthsClamped adds months to t without overflowing into the following month
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfTarget := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
@@ -0,0 +1,285 @@
+package metering
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const defaultCollectionInitMaxAttemptsLimit = 3
+
+// MongoDbStore describes the MongoDB helper operations the metering repository uses.
+type MongoDbStore interface {
+	ExecuteAggregateCommand(ctx context.Context, collection *mongo.Collection, mongoPipeline []bson.D) (*mongo.Cursor, error)
+	ExecuteDeleteOneCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, targetObjectName string) error
+	ExecuteCountDocuments(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error)
+	ExecuteFindCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
+	ExecuteInsertOneCommand(ctx context.Context, collection *mongo.Collection, document interface{}, resultObjectName string) (*mongo.InsertOneResult, error)
+	ExecuteFindOneCommandDecodeResult(ctx context.Context, collection *mongo.Collection, filter interface{}, result interface{}, resultObjectName string, logError bool, onFailureErr error) error
+	ExecuteUpdateOneCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, update interface{}, resultObjectName string) error
+
+	GetDatabase(ctx context.Context, dbName string) (*mongo.Database, error)
+	InitialiseClient(ctx context.Context) (*mongo.Client, error)
+	MapAllInCursorToResult(ctx context.Context, cursor *mongo.Cursor, result interface{}, resultObjectName string) error
+}
+
+// Repository manages usage events in MongoDB.
+type Repository struct {
+	Store                          MongoDbStore
+	collectionInitMaxAttemptsLimit int
+
+	collection      *mongo.Collection
+	collectionMutex sync.Mutex
+}
+
+var _ UsageRepository = (*Repository)(nil)
+
+// NewRepository returns a usage event repository backed by the provided MongoDB store.
+func NewRepository(store MongoDbStore) *Repository {
+	return &Repository{
+		Store:                          store,
+		collectionInitMaxAttemptsLimit: defaultCollectionInitMaxAttemptsLimit,
+	}
+}
+
+// WithCollectionInitMaxAttemptsLimit overrides collection initialisation retry attempts.
+func (r *Repository) WithCollectionInitMaxAttemptsLimit(limit int) *Repository {
+	if limit > 0 {
+		r.collectionInitMaxAttemptsLimit = limit
+	}
+	return r
+}
+
+// GetUsageEventCollection returns the usage events collection, initialising it lazily.
+func (r *Repository) GetUsageEventCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.collectionMutex.Lock()
+	defer r.collectionMutex.Unlock()
+
+	if r.collection != nil {
+		return r.collection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.collection = db.Collection(UsageEventCollection)
+		return r.collection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, UsageEventCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// CreateUsageEvent persists a new usage event. The ID is derived from the idempotency key, so
+// usage already recorded is reported as ErrUsageEventAlreadyRecorded.
+func (r *Repository) CreateUsageEvent(ctx context.Context, event *UsageEvent) (*UsageEvent, error) {
+	collection, err := r.GetUsageEventCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatedAt == "" {
+		event.SetCreatedAtTimeToNow()
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, event, "usage-event")
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrUsageEventAlreadyRecorded
+		}
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetUsageEventByID retrieves one usage event by its ID.
+func (r *Repository) GetUsageEventByID(ctx context.Context, id string) (*UsageEvent, error) {
+	collection, err := r.GetUsageEventCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result UsageEvent
+	err = r.Store.ExecuteFindOneCommandDecodeResult(ctx, collection, bson.M{"_id": id}, &result, "usage-event", false, ErrUsageEventNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateUsageEvent replaces one usage event by ID.
+func (r *Repository) UpdateUsageEvent(ctx context.Context, event *UsageEvent) (*UsageEvent, error) {
+	collection, err := r.GetUsageEventCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	event.SetUpdatedAtTimeToNow()
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": event.ID}, bson.M{"$set": event}, "usage-event")
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// DeleteUsageEvent removes one usage event by ID.
+func (r *Repository) DeleteUsageEvent(ctx context.Context, id string) error {
+	collection, err := r.GetUsageEventCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Store.ExecuteDeleteOneCommand(ctx, collection, bson.M{"_id": id}, "usage-event")
+}
+
+// SumUsage totals the quantity a subject used of a feature between from (inclusive) and
+// to (exclusive).
+func (r *Repository) SumUsage(ctx context.Context, subjectType entitlement.SubjectType, subjectID string, featureSlug string, from string, to string) (int64, error) {
+	collection, err := r.GetUsageEventCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pipeline := []bson.D{
+		{{Key: "$match", Value: bson.M{
+			"subject_type": subjectType,
+			"subject_id":   subjectID,
+			"feature_slug": featureSlug,
+			"occurred_at":  bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": "$quantity"},
+		}}},
+	}
+
+	cursor, err := r.Store.ExecuteAggregateCommand(ctx, collection, pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	var totals []struct {
+		Total int64 `bson:"total"`
+	}
+	if err = r.Store.MapAllInCursorToResult(ctx, cursor, &totals, "usage-totals"); err != nil {
+		return 0, err
+	}
+
+	if len(totals) == 0 {
+		return 0, nil
+	}
+
+	return totals[0].Total, nil
+}
+
+// GetUsageEvents returns a page of usage events matching the request filters.
+func (r *Repository) GetUsageEvents(ctx context.Context, req *GetUsageEventsRequest) ([]UsageEvent, error) {
+	collection, err := r.GetUsageEventCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortDirection := -1
+	if req.Order == "occurred_at_asc" {
+		sortDirection = 1
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((req.Page - 1) * req.PerPage)).
+		SetLimit(int64(req.PerPage)).
+		SetSort(bson.D{{Key: "occurred_at", Value: sortDirection}})
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, buildUsageEventFilter(req), findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []UsageEvent{}
+	if err = r.Store.MapAllInCursorToResult(ctx, cursor, &events, "usage-events"); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetTotalUsageEvents counts usage events matching the request filters.
+func (r *Repository) GetTotalUsageEvents(ctx context.Context, req *GetUsageEventsRequest) (int64, error) {
+	collection, err := r.GetUsageEventCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Store.ExecuteCountDocuments(ctx, collection, buildUsageEventFilter(req))
+}
+
+// GetPendingReportUsageEvents returns up to limit of the oldest usage events waiting to be
+// reported to the payment provider.
+func (r *Repository) GetPendingReportUsageEvents(ctx context.Context, limit int) ([]UsageEvent, error) {
+	return r.GetUsageEvents(ctx, &GetUsageEventsRequest{
+		Order:             "occurred_at_asc",
+		PerPage:           limit,
+		Page:              1,
+		PendingReportOnly: true,
+	})
+}
+
+// buildUsageEventFilter converts usage event list filters to a Mongo query. Timestamps are
+// stored in the package-wide RFC3339 nano UTC format so they compare correctly as strings.
+func buildUsageEventFilter(req *GetUsageEventsRequest) bson.M {
+	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
+
+	if req.SubjectType != "" {
+		queryFilter["subject_type"] = req.SubjectType
+	}
+
+	if req.SubjectID != "" {
+		queryFilter["subject_id"] = req.SubjectID
+	}
+
+	if req.FeatureSlug != "" {
+		queryFilter["feature_slug"] = req.FeatureSlug
+	}
+
+	occurredAtFilter := bson.M{}
+	if req.OccurredFrom != "" {
+		occurredAtFilter["$gte"] = req.OccurredFrom
+	}
+	if req.OccurredTo != "" {
+		occurredAtFilter["$lt"] = req.OccurredTo
+	}
+	if len(occurredAtFilter) > 0 {
+		queryFilter["occurred_at"] = occurredAtFilter
+	}
+
+	if req.PendingReportOnly {
+		queryFilter["meter_event_name"] = bson.M{"$nin": bson.A{nil, ""}}
+		queryFilter["provider_name"] = bson.M{"$nin": bson.A{nil, ""}}
+		queryFilter["reported_at"] = bson.M{"$in": bson.A{nil, ""}}
+		queryFilter["reserved"] = bson.M{"$ne": true}
+	}
+
+	return queryFilter
+}
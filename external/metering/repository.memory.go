@@ -0,0 +1,178 @@
+package metering
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ooaklee/ghatd/external/entitlement"
+)
+
+// InMemoryRepository is an in-memory implementation of the usage repository
+// Useful for testing and development
+type InMemoryRepository struct {
+	events map[string]*UsageEvent
+	mu     sync.RWMutex
+}
+
+var _ UsageRepository = (*InMemoryRepository)(nil)
+
+// NewInMemoryRepository creates a new in-memory usage repository
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		events: make(map[string]*UsageEvent),
+	}
+}
+
+// CreateUsageEvent stores a new usage event
+func (m *InMemoryRepository) CreateUsageEvent(ctx context.Context, event *UsageEvent) (*UsageEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.events[event.ID]; ok {
+		return nil, ErrUsageEventAlreadyRecorded
+	}
+
+	if event.CreatedAt == "" {
+		event.SetCreatedAtTimeToNow()
+	}
+
+	stored := *event
+	m.events[event.ID] = &stored
+
+	return event, nil
+}
+
+// GetUsageEventByID retrieves one usage event by its ID
+func (m *InMemoryRepository) GetUsageEventByID(ctx context.Context, id string) (*UsageEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	event, ok := m.events[id]
+	if !ok {
+		return nil, ErrUsageEventNotFound
+	}
+
+	result := *event
+	return &result, nil
+}
+
+// UpdateUsageEvent replaces one usage event by ID
+func (m *InMemoryRepository) UpdateUsageEvent(ctx context.Context, event *UsageEvent) (*UsageEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.events[event.ID]; !ok {
+		return nil, ErrUsageEventNotFound
+	}
+
+	event.SetUpdatedAtTimeToNow()
+
+	stored := *event
+	m.events[event.ID] = &stored
+
+	return event, nil
+}
+
+// DeleteUsageEvent removes one usage event by ID
+func (m *InMemoryRepository) DeleteUsageEvent(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.events[id]; !ok {
+		return ErrUsageEventNotFound
+	}
+
+	delete(m.events, id)
+
+	return nil
+}
+
+// SumUsage totals the quantity a subject used of a feature between from (inclusive) and
+// to (exclusive)
+func (m *InMemoryRepository) SumUsage(ctx context.Context, subjectType entitlement.SubjectType, subjectID string, featureSlug string, from string, to string) (int64, error) {
+	var total int64
+	for _, event := range m.filterUsageEvents(&GetUsageEventsRequest{
+		SubjectType:  subjectType,
+		SubjectID:    subjectID,
+		FeatureSlug:  featureSlug,
+		OccurredFrom: from,
+		OccurredTo:   to,
+	}) {
+		total += event.Quantity
+	}
+
+	return total, nil
+}
+
+// GetUsageEvents returns a page of usage events matching the request filters
+func (m *InMemoryRepository) GetUsageEvents(ctx context.Context, req *GetUsageEventsRequest) ([]UsageEvent, error) {
+	events := m.filterUsageEvents(req)
+
+	sort.Slice(events, func(i, j int) bool {
+		if req.Order == "occurred_at_asc" {
+			return events[i].OccurredAt < events[j].OccurredAt
+		}
+		return events[i].OccurredAt > events[j].OccurredAt
+	})
+
+	start := (req.Page - 1) * req.PerPage
+	if start >= len(events) {
+		return []UsageEvent{}, nil
+	}
+
+	end := start + req.PerPage
+	if end > len(events) {
+		end = len(events)
+	}
+
+	return events[start:end], nil
+}
+
+// GetTotalUsageEvents counts usage events matching the request filters
+func (m *InMemoryRepository) GetTotalUsageEvents(ctx context.Context, req *GetUsageEventsRequest) (int64, error) {
+	return int64(len(m.filterUsageEvents(req))), nil
+}
+
+// GetPendingReportUsageEvents returns up to limit of the oldest usage events waiting to be
+// reported to the payment provider
+func (m *InMemoryRepository) GetPendingReportUsageEvents(ctx context.Context, limit int) ([]UsageEvent, error) {
+	return m.GetUsageEvents(ctx, &GetUsageEventsRequest{
+		Order:             "occurred_at_asc",
+		PerPage:           limit,
+		Page:              1,
+		PendingReportOnly: true,
+	})
+}
+
+// filterUsageEvents returns copies of the stored usage events matching the request filters
+func (m *InMemoryRepository) filterUsageEvents(req *GetUsageEventsRequest) []UsageEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	events := []UsageEvent{}
+	for _, event := range m.events {
+		if req.SubjectType != "" && event.SubjectType != req.SubjectType {
+			continue
+		}
+		if req.SubjectID != "" && event.SubjectID != req.SubjectID {
+			continue
+		}
+		if req.FeatureSlug != "" && event.FeatureSlug != req.FeatureSlug {
+			continue
+		}
+		if req.OccurredFrom != "" && event.OccurredAt < req.OccurredFrom {
+			continue
+		}
+		if req.OccurredTo != "" && event.OccurredAt >= req.OccurredTo {
+			continue
+		}
+		if req.PendingReportOnly && !event.IsPendingReport() {
+			continue
+		}
+
+		events = append(events, *event)
+	}
+
+	return events
+}
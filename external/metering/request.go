@@ -0,0 +1,102 @@
+package metering
+
+import "github.com/ooaklee/ghatd/external/entitlement"
+
+// RecordUsageRequest holds everything needed to record usage of a metered feature
+type RecordUsageRequest struct {
+	// IdempotencyKey identifies the usage, recording it again with the same key is a no-op
+	IdempotencyKey string `json:"idempotency_key" validate:"required"`
+
+	// SubjectType is the kind of subject the usage belongs to. Default: user
+	SubjectType entitlement.SubjectType `json:"subject_type"`
+
+	// SubjectID is the ID of the user or group the usage belongs to
+	SubjectID string `json:"subject_id" validate:"required"`
+
+	// FeatureSlug references the pricer feature that was used
+	FeatureSlug string `json:"feature_slug" validate:"required"`
+
+	// Quantity is the amount used
+	Quantity int64 `json:"quantity" validate:"required"`
+
+	// OccurredAt is an optional RFC3339 timestamp of when the usage happened. Default: now
+	OccurredAt string `json:"occurred_at"`
+
+	// RecordedByUserID is the admin recording the usage on the subject's behalf, if any
+	RecordedByUserID string `json:"-"`
+
+	// Reserve holds the usage for a request still being served, it must then be confirmed
+	// with ConfirmUsage or given back with ReleaseUsage
+	Reserve bool `json:"-"`
+}
+
+// ConfirmUsageRequest holds everything needed to confirm reserved usage
+type ConfirmUsageRequest struct {
+	// UsageEventID is the ID of the reserved usage event
+	UsageEventID string
+}
+
+// ReleaseUsageRequest holds everything needed to give back reserved usage
+type ReleaseUsageRequest struct {
+	// UsageEventID is the ID of the reserved usage event
+	UsageEventID string
+}
+
+// GetUsageRequest holds everything needed to get a subject's usage in the current billing period
+type GetUsageRequest struct {
+	// SubjectType is the kind of subject. Default: user
+	SubjectType entitlement.SubjectType
+
+	// SubjectID is the user or group ID
+	SubjectID string
+
+	// FeatureSlug limits the usage to a single feature
+	FeatureSlug string
+}
+
+// GetUsageEventsRequest holds everything needed to list usage events
+type GetUsageEventsRequest struct {
+	// Order defines how should response be sorted. Default: newest -> oldest (occurred_at_desc)
+	// Valid options: occurred_at_asc, occurred_at_desc
+	Order string `query:"order"`
+
+	// Total number of usage events to return per page, if available. Default 25.
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from. Default 1.
+	Page int `query:"page"`
+
+	// TotalCount specifies the total count of all usage events
+	TotalCount int
+
+	// TotalPages specifies the total pages of results
+	TotalPages int
+
+	// Meta whether response should contain meta information
+	Meta bool `query:"meta"`
+
+	// SubjectType is the subject type to filter by
+	SubjectType entitlement.SubjectType `query:"subject_type"`
+
+	// SubjectID is the subject ID to filter by
+	SubjectID string `query:"subject_id"`
+
+	// FeatureSlug is the feature slug to filter by
+	FeatureSlug string `query:"feature_slug"`
+
+	// OccurredFrom only returns usage that happened at or after this RFC3339 timestamp
+	OccurredFrom string `query:"occurred_from"`
+
+	// OccurredTo only returns usage that happened before this RFC3339 timestamp
+	OccurredTo string `query:"occurred_to"`
+
+	// PendingReportOnly only returns usage waiting to be reported to the payment provider
+	PendingReportOnly bool `query:"pending_report_only"`
+}
+
+// ReportPendingUsageRequest holds everything needed to report outstanding usage to the
+// payment provider
+type ReportPendingUsageRequest struct {
+	// Limit is how many usage events to report in this run. Default: 100
+	Limit int
+}
@@ -0,0 +1,63 @@
+package metering
+
+import "github.com/ooaklee/ghatd/external/toolbox"
+
+// RecordUsageResponse holds the recorded usage and the resulting usage in its period
+type RecordUsageResponse struct {
+	// UsageEvent is the recorded usage, or the usage previously recorded with the same key
+	UsageEvent *UsageEvent `json:"usage_event"`
+
+	// Usage is the subject's usage of the feature after recording
+	Usage *UsageSummary `json:"usage"`
+
+	// Duplicate is true when the idempotency key had already been recorded
+	Duplicate bool `json:"duplicate"`
+}
+
+// GetUsageResponse holds a subject's usage in the current billing period
+type GetUsageResponse struct {
+	Usage []UsageSummary
+}
+
+// ReportPendingUsageResponse holds the outcome of reporting outstanding usage
+type ReportPendingUsageResponse struct {
+	// Reported is how many usage events the provider accepted
+	Reported int
+
+	// Failed is how many usage events could not be reported and will be retried
+	Failed int
+}
+
+// GetUsageEventsResponse holds everything needed to return
+// the response to get usage events
+type GetUsageEventsResponse struct {
+	UsageEvents []UsageEvent `json:"usage_events"`
+
+	// Total number of usage events found that matched provided
+	// filters
+	Total int
+
+	// TotalPages total pages available, based on the provided
+	// filters and resources per page
+	TotalPages int
+
+	// PerPage number of usage events set to be returned per page
+	PerPage int
+
+	// Page specifies the page results were taken from. Default 1.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetUsageEventsResponse,
+// including the number of resources per page, total resources, total pages,
+// and the current page.
+func (g *GetUsageEventsResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
@@ -0,0 +1,735 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/common"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// UsageRepository describes the persistence operations needed for usage events.
+type UsageRepository interface {
+	CreateUsageEvent(ctx context.Context, event *UsageEvent) (*UsageEvent, error)
+	GetUsageEventByID(ctx context.Context, id string) (*UsageEvent, error)
+	UpdateUsageEvent(ctx context.Context, event *UsageEvent) (*UsageEvent, error)
+	DeleteUsageEvent(ctx context.Context, id string) error
+	SumUsage(ctx context.Context, subjectType entitlement.SubjectType, subjectID string, featureSlug string, from string, to string) (int64, error)
+	GetUsageEvents(ctx context.Context, req *GetUsageEventsRequest) ([]UsageEvent, error)
+	GetTotalUsageEvents(ctx context.Context, req *GetUsageEventsRequest) (int64, error)
+	GetPendingReportUsageEvents(ctx context.Context, limit int) ([]UsageEvent, error)
+}
+
+// EntitlementService describes the entitlement lookups used to find quotas.
+type EntitlementService interface {
+	GetEntitlements(ctx context.Context, req *entitlement.GetEntitlementsRequest) (*entitlement.GetEntitlementsResponse, error)
+}
+
+// BillingService describes the subscription lookups used to align usage with billing periods.
+type BillingService interface {
+	GetSubscriptions(ctx context.Context, req *billing.GetSubscriptionsRequest) (*billing.GetSubscriptionsResponse, error)
+}
+
+// UsageReporter reports metered usage to a payment provider. *paymentprovider.ProviderRegistry
+// satisfies it.
+type UsageReporter interface {
+	ReportUsage(ctx context.Context, providerName string, req *paymentprovider.UsageReportRequest) (*paymentprovider.UsageReport, error)
+}
+
+// Service records usage of metered features, enforces quotas and reports usage to the
+// payment provider.
+type Service struct {
+	UsageRepository    UsageRepository
+	EntitlementService EntitlementService
+	BillingService     BillingService
+	UsageReporter      UsageReporter
+
+	defaultPolicy   QuotaPolicy
+	policies        map[string]QuotaPolicy
+	meterEventNames map[string]string
+	recordLocksMu   sync.Mutex
+	recordLocks     map[string]*recordLock
+	now             func() time.Time
+}
+
+// NewService returns a metering service that enforces hard quotas by default.
+func NewService(usageRepository UsageRepository, entitlementService EntitlementService, billingService BillingService) *Service {
+	return &Service{
+		UsageRepository:    usageRepository,
+		EntitlementService: entitlementService,
+		BillingService:     billingService,
+		defaultPolicy: QuotaPolicy{
+			Enforcement:             QuotaEnforcementHard,
+			WarningThresholdPercent: defaultWarningThresholdPercent,
+		},
+		policies:        make(map[string]QuotaPolicy),
+		meterEventNames: make(map[string]string),
+		recordLocks:     make(map[string]*recordLock),
+		now:             func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithDefaultQuotaPolicy overrides how quotas are enforced for features without their own policy.
+func (s *Service) WithDefaultQuotaPolicy(policy QuotaPolicy) *Service {
+	s.defaultPolicy = normalisePolicy(policy)
+	return s
+}
+
+// WithQuotaPolicy sets how the quota of a single feature is enforced.
+func (s *Service) WithQuotaPolicy(featureSlug string, policy QuotaPolicy) *Service {
+	s.policies[featureSlug] = normalisePolicy(policy)
+	return s
+}
+
+// WithUsageReporter enables reporting recorded usage to the subscriber's payment provider.
+// Only features listed in meterEventNames are reported, keyed by feature slug to the
+// provider's meter event name.
+func (s *Service) WithUsageReporter(reporter UsageReporter, meterEventNames map[string]string) *Service {
+	s.UsageReporter = reporter
+	for featureSlug, eventName := range meterEventNames {
+		s.meterEventNames[featureSlug] = eventName
+	}
+	return s
+}
+
+// RecordUsage records usage of a metered feature against the subject's current billing
+// period. Recording the same idempotency key again returns the original usage. Usage that
+// would exceed a hard quota is rejected with ErrQuotaExceeded.
+func (s *Service) RecordUsage(ctx context.Context, req *RecordUsageRequest) (*RecordUsageResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering").With(
+		zap.String("operation", "record-usage"),
+		zap.String("subject-type", string(req.SubjectType)),
+		zap.String("subject-id", req.SubjectID),
+		zap.String("feature-slug", req.FeatureSlug),
+	)
+
+	subjectType, err := normaliseSubject(req.SubjectType, req.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	featureSlug := strings.TrimSpace(req.FeatureSlug)
+	if featureSlug == "" {
+		return nil, entitlement.ErrFeatureSlugIsRequired
+	}
+
+	idempotencyKey := strings.TrimSpace(req.IdempotencyKey)
+	if idempotencyKey == "" {
+		return nil, ErrIdempotencyKeyIsRequired
+	}
+
+	if req.Quantity <= 0 {
+		return nil, ErrInvalidUsageQuantity
+	}
+
+	now := s.now()
+	occurredAt := now
+	if req.OccurredAt != "" {
+		occurredAt, err = time.Parse(time.RFC3339, req.OccurredAt)
+		if err != nil || occurredAt.After(now) {
+			logger.Warn("invalid-usage-occurred-at", zap.String("occurred-at", req.OccurredAt))
+			return nil, ErrInvalidOccurredAt
+		}
+		occurredAt = occurredAt.UTC()
+	}
+
+	eventID := UsageEventID(subjectType, req.SubjectID, idempotencyKey)
+
+	// Serialise recording per subject and feature, so concurrent requests in this process
+	// cannot both pass the quota check
+	unlock := s.lockSubjectFeature(subjectType, req.SubjectID, featureSlug)
+	defer unlock()
+
+	existing, err := s.UsageRepository.GetUsageEventByID(ctx, eventID)
+	if err == nil {
+		logger.Info("usage-already-recorded", zap.String("usage-event-id", eventID))
+		return s.duplicateUsageResponse(ctx, existing)
+	}
+	if !errors.Is(err, ErrUsageEventNotFound) {
+		logger.Error("failed-to-check-for-recorded-usage", zap.Error(err))
+		return nil, err
+	}
+
+	quota, err := s.getQuota(ctx, subjectType, req.SubjectID, featureSlug, occurredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.UsageRepository.SumUsage(ctx, subjectType, req.SubjectID, featureSlug, quota.periodStart, quota.periodEnd)
+	if err != nil {
+		logger.Error("failed-to-sum-usage", zap.Error(err))
+		return nil, err
+	}
+
+	event := &UsageEvent{
+		ID:               eventID,
+		IdempotencyKey:   idempotencyKey,
+		SubjectType:      subjectType,
+		SubjectID:        req.SubjectID,
+		FeatureSlug:      featureSlug,
+		Quantity:         req.Quantity,
+		OccurredAt:       occurredAt.Format(common.RFC3339NanoUTC),
+		PeriodStart:      quota.periodStart,
+		PeriodEnd:        quota.periodEnd,
+		RecordedByUserID: req.RecordedByUserID,
+		Reserved:         req.Reserve,
+	}
+
+	if !quota.entitlement.Unlimited && used+req.Quantity > quota.entitlement.Quantity {
+		if quota.policy.Enforcement == QuotaEnforcementHard {
+			logger.Info("usage-rejected-quota-exceeded", zap.Int64("used", used), zap.Int64("quantity", req.Quantity), zap.Int64("limit", quota.entitlement.Quantity))
+			return nil, ErrQuotaExceeded
+		}
+		event.OverQuota = true
+	}
+
+	if quota.subscription != nil {
+		event.SubscriptionID = quota.subscription.ID
+		if eventName := s.meterEventNames[featureSlug]; eventName != "" && s.UsageReporter != nil {
+			event.MeterEventName = eventName
+			event.ProviderName = quota.subscription.Integrator
+			event.ProviderCustomerID = quota.subscription.IntegratorCustomerID
+		}
+	}
+
+	event, err = s.UsageRepository.CreateUsageEvent(ctx, event)
+	if errors.Is(err, ErrUsageEventAlreadyRecorded) {
+		existing, err := s.UsageRepository.GetUsageEventByID(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		return s.duplicateUsageResponse(ctx, existing)
+	}
+	if err != nil {
+		logger.Error("failed-to-create-usage-event", zap.Error(err))
+		return nil, err
+	}
+
+	if event.IsPendingReport() {
+		s.reportUsageEvent(ctx, event)
+	}
+
+	logger.Info("usage-recorded", zap.String("usage-event-id", event.ID), zap.Int64("quantity", event.Quantity), zap.Bool("over-quota", event.OverQuota), zap.Bool("reserved", event.Reserved))
+
+	return &RecordUsageResponse{
+		UsageEvent: event,
+		Usage:      quota.summary(featureSlug, used+req.Quantity),
+	}, nil
+}
+
+// ConfirmUsage confirms usage reserved for a request that was served, reporting it to the
+// payment provider when the feature is metered there. Usage that is not reserved is left as is.
+func (s *Service) ConfirmUsage(ctx context.Context, req *ConfirmUsageRequest) (*RecordUsageResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering").With(
+		zap.String("operation", "confirm-usage"),
+		zap.String("usage-event-id", req.UsageEventID),
+	)
+
+	event, err := s.UsageRepository.GetUsageEventByID(ctx, req.UsageEventID)
+	if err != nil {
+		logger.Warn("failed-to-get-reserved-usage", zap.Error(err))
+		return nil, err
+	}
+
+	if !event.Reserved {
+		return &RecordUsageResponse{UsageEvent: event}, nil
+	}
+
+	event.Reserved = false
+	event, err = s.UsageRepository.UpdateUsageEvent(ctx, event)
+	if err != nil {
+		logger.Error("failed-to-confirm-reserved-usage", zap.Error(err))
+		return nil, err
+	}
+
+	if event.IsPendingReport() {
+		s.reportUsageEvent(ctx, event)
+	}
+
+	logger.Info("reserved-usage-confirmed")
+
+	return &RecordUsageResponse{UsageEvent: event}, nil
+}
+
+// ReleaseUsage gives back usage reserved for a request that was not served. Confirmed usage is
+// never released.
+func (s *Service) ReleaseUsage(ctx context.Context, req *ReleaseUsageRequest) error {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering").With(
+		zap.String("operation", "release-usage"),
+		zap.String("usage-event-id", req.UsageEventID),
+	)
+
+	event, err := s.UsageRepository.GetUsageEventByID(ctx, req.UsageEventID)
+	if err != nil {
+		logger.Warn("failed-to-get-reserved-usage", zap.Error(err))
+		return err
+	}
+
+	if !event.Reserved {
+		logger.Warn("usage-not-released-as-already-confirmed")
+		return nil
+	}
+
+	if err := s.UsageRepository.DeleteUsageEvent(ctx, event.ID); err != nil {
+		logger.Error("failed-to-release-reserved-usage", zap.Error(err))
+		return err
+	}
+
+	logger.Info("reserved-usage-released")
+
+	return nil
+}
+
+// GetUsage returns the subject's usage of its metered features in the current billing period.
+// A feature is included when it has a quota, a quota policy or a provider meter, or when it is
+// requested explicitly.
+func (s *Service) GetUsage(ctx context.Context, req *GetUsageRequest) (*GetUsageResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering").With(
+		zap.String("operation", "get-usage"),
+		zap.String("subject-type", string(req.SubjectType)),
+		zap.String("subject-id", req.SubjectID),
+	)
+
+	subjectType, err := normaliseSubject(req.SubjectType, req.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+
+	entitlementsResp, err := s.EntitlementService.GetEntitlements(ctx, &entitlement.GetEntitlementsRequest{
+		SubjectType: subjectType,
+		SubjectID:   req.SubjectID,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-entitlements-for-usage", zap.Error(err))
+		return nil, err
+	}
+
+	subscriptions, err := s.getSubjectSubscriptions(ctx, subjectType, req.SubjectID)
+	if err != nil {
+		logger.Error("failed-to-get-subscriptions-for-usage", zap.Error(err))
+		return nil, err
+	}
+
+	usage := []UsageSummary{}
+	for i := range entitlementsResp.EntitlementSet.Entitlements {
+		featureEntitlement := &entitlementsResp.EntitlementSet.Entitlements[i]
+
+		if req.FeatureSlug != "" && featureEntitlement.FeatureSlug != req.FeatureSlug {
+			continue
+		}
+		if req.FeatureSlug == "" && !s.isMetered(featureEntitlement) {
+			continue
+		}
+
+		quota := s.newQuota(featureEntitlement, subscriptions, now)
+
+		used, err := s.UsageRepository.SumUsage(ctx, subjectType, req.SubjectID, featureEntitlement.FeatureSlug, quota.periodStart, quota.periodEnd)
+		if err != nil {
+			logger.Error("failed-to-sum-usage", zap.String("feature-slug", featureEntitlement.FeatureSlug), zap.Error(err))
+			return nil, err
+		}
+
+		usage = append(usage, *quota.summary(featureEntitlement.FeatureSlug, used))
+	}
+
+	return &GetUsageResponse{Usage: usage}, nil
+}
+
+// GetUsageEvents returns a page of usage events matching the request filters
+func (s *Service) GetUsageEvents(ctx context.Context, req *GetUsageEventsRequest) (*GetUsageEventsResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering")
+
+	if req.SubjectType != "" && !entitlement.IsValidSubjectType(req.SubjectType) {
+		return nil, entitlement.ErrInvalidSubjectType
+	}
+
+	var err error
+	if req.OccurredFrom, err = normaliseTimestamp(req.OccurredFrom); err != nil {
+		return nil, ErrInvalidOccurredAt
+	}
+	if req.OccurredTo, err = normaliseTimestamp(req.OccurredTo); err != nil {
+		return nil, ErrInvalidOccurredAt
+	}
+
+	// Set defaults
+	if req.Order == "" {
+		req.Order = "occurred_at_desc"
+	}
+
+	if req.PerPage == 0 {
+		req.PerPage = 25
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	total, err := s.UsageRepository.GetTotalUsageEvents(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-usage-events-total", zap.Error(err))
+		return nil, err
+	}
+	req.TotalCount = int(total)
+
+	events, err := s.UsageRepository.GetUsageEvents(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-usage-events", zap.Error(err))
+		return nil, err
+	}
+
+	paginatedResponse, err := toolbox.Paginate(ctx, &toolbox.PaginationRequest{
+		PerPage: req.PerPage,
+		Page:    req.Page,
+	}, events, req.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetUsageEventsResponse{
+		UsageEvents: paginatedResponse.Resources,
+		Total:       paginatedResponse.Total,
+		TotalPages:  paginatedResponse.TotalPages,
+		PerPage:     paginatedResponse.ResourcePerPage,
+		Page:        paginatedResponse.Page,
+	}, nil
+}
+
+// ReportPendingUsage reports usage the payment provider has not yet accepted, oldest first.
+// Run it on a schedule to retry reports that failed when the usage was recorded.
+func (s *Service) ReportPendingUsage(ctx context.Context, req *ReportPendingUsageRequest) (*ReportPendingUsageResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering").With(zap.String("operation", "report-pending-usage"))
+
+	response := &ReportPendingUsageResponse{}
+	if s.UsageReporter == nil {
+		return response, nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultReportBatchSize
+	}
+
+	events, err := s.UsageRepository.GetPendingReportUsageEvents(ctx, limit)
+	if err != nil {
+		logger.Error("failed-to-get-pending-report-usage-events", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range events {
+		if s.reportUsageEvent(ctx, &events[i]) {
+			response.Reported++
+		} else {
+			response.Failed++
+		}
+	}
+
+	logger.Info("reported-pending-usage", zap.Int("reported", response.Reported), zap.Int("failed", response.Failed))
+
+	return response, nil
+}
+
+// reportUsageEvent reports the usage to its payment provider and records the outcome. It
+// returns true if the provider accepted the usage.
+func (s *Service) reportUsageEvent(ctx context.Context, event *UsageEvent) bool {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering").With(
+		zap.String("operation", "report-usage-event"),
+		zap.String("usage-event-id", event.ID),
+		zap.String("provider", event.ProviderName),
+	)
+
+	occurredAt, _ := time.Parse(common.RFC3339NanoUTC, event.OccurredAt)
+
+	event.ReportAttempts++
+	_, reportErr := s.UsageReporter.ReportUsage(ctx, event.ProviderName, &paymentprovider.UsageReportRequest{
+		EventName:  event.MeterEventName,
+		CustomerID: event.ProviderCustomerID,
+		Quantity:   event.Quantity,
+		Identifier: event.ID,
+		Timestamp:  occurredAt,
+	})
+	if reportErr != nil {
+		logger.Warn("failed-to-report-usage", zap.Int("attempts", event.ReportAttempts), zap.Error(reportErr))
+		event.LastReportError = reportErr.Error()
+	} else {
+		event.ReportedAt = s.now().Format(common.RFC3339NanoUTC)
+		event.LastReportError = ""
+	}
+
+	if _, err := s.UsageRepository.UpdateUsageEvent(ctx, event); err != nil {
+		logger.Error("failed-to-store-usage-report-outcome", zap.Error(err))
+	}
+
+	return reportErr == nil
+}
+
+// duplicateUsageResponse returns the previously recorded usage with the current usage of its period
+func (s *Service) duplicateUsageResponse(ctx context.Context, event *UsageEvent) (*RecordUsageResponse, error) {
+	used, err := s.UsageRepository.SumUsage(ctx, event.SubjectType, event.SubjectID, event.FeatureSlug, event.PeriodStart, event.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordUsageResponse{
+		UsageEvent: event,
+		Usage: &UsageSummary{
+			FeatureSlug: event.FeatureSlug,
+			Used:        used,
+			PeriodStart: event.PeriodStart,
+			PeriodEnd:   event.PeriodEnd,
+		},
+		Duplicate: true,
+	}, nil
+}
+
+// quota holds what is needed to enforce and summarise a feature's usage in one period
+type quota struct {
+	entitlement  *entitlement.Entitlement
+	policy       QuotaPolicy
+	subscription *billing.Subscription
+	periodStart  string
+	periodEnd    string
+}
+
+// summary returns the usage summary for the quota given what has been used
+func (q *quota) summary(featureSlug string, used int64) *UsageSummary {
+	summary := &UsageSummary{
+		FeatureSlug: featureSlug,
+		Unit:        q.entitlement.Unit,
+		Used:        used,
+		Unlimited:   q.entitlement.Unlimited,
+		Enforcement: q.policy.Enforcement,
+		PeriodStart: q.periodStart,
+		PeriodEnd:   q.periodEnd,
+	}
+
+	if summary.Unlimited {
+		return summary
+	}
+
+	summary.Limit = q.entitlement.Quantity
+	if used < summary.Limit {
+		summary.Remaining = summary.Limit - used
+	}
+	summary.LimitReached = used >= summary.Limit
+	summary.WarningReached = used*100 >= summary.Limit*int64(q.policy.WarningThresholdPercent)
+
+	return summary
+}
+
+// getQuota resolves the subject's quota for the feature in the period containing at
+func (s *Service) getQuota(ctx context.Context, subjectType entitlement.SubjectType, subjectID string, featureSlug string, at time.Time) (*quota, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/metering")
+
+	entitlementsResp, err := s.EntitlementService.GetEntitlements(ctx, &entitlement.GetEntitlementsRequest{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-entitlements-for-quota", zap.Error(err))
+		return nil, err
+	}
+
+	featureEntitlement, ok := entitlementsResp.EntitlementSet.Get(featureSlug)
+	if !ok {
+		logger.Info("usage-rejected-feature-not-entitled", zap.String("feature-slug", featureSlug))
+		return nil, entitlement.ErrFeatureNotEntitled
+	}
+
+	subscriptions, err := s.getSubjectSubscriptions(ctx, subjectType, subjectID)
+	if err != nil {
+		logger.Error("failed-to-get-subscriptions-for-quota", zap.Error(err))
+		return nil, err
+	}
+
+	return s.newQuota(featureEntitlement, subscriptions, at), nil
+}
+
+// newQuota builds the quota of an entitlement, aligning the period with the subscription the
+// entitlement comes from
+func (s *Service) newQuota(featureEntitlement *entitlement.Entitlement, subscriptions []billing.Subscription, at time.Time) *quota {
+	subscription := periodSubscription(featureEntitlement, subscriptions)
+	periodStart, periodEnd := billingPeriod(subscription, at)
+
+	return &quota{
+		entitlement:  featureEntitlement,
+		policy:       s.policyFor(featureEntitlement.FeatureSlug),
+		subscription: subscription,
+		periodStart:  periodStart.Format(common.RFC3339NanoUTC),
+		periodEnd:    periodEnd.Format(common.RFC3339NanoUTC),
+	}
+}
+
+// getSubjectSubscriptions returns the subject's current subscriptions, looking up group-owned
+// subscriptions by group ID for group subjects
+func (s *Service) getSubjectSubscriptions(ctx context.Context, subjectType entitlement.SubjectType, subjectID string) ([]billing.Subscription, error) {
+	if s.BillingService == nil {
+		return nil, nil
+	}
+
+	var subscriptions []billing.Subscription
+	for page := 1; ; page++ {
+		subscriptionsReq := &billing.GetSubscriptionsRequest{
+			Statuses: []string{billing.StatusActive, billing.StatusTrialing, billing.StatusCancelled},
+			PerPage:  lookupPageSize,
+			Page:     page,
+		}
+		if subjectType == entitlement.SubjectTypeGroup {
+			subscriptionsReq.ForGroupIDs = []string{subjectID}
+		} else {
+			// subscriptions the user bought for a group entitle the group, not the user
+			subscriptionsReq.ForUserIDs = []string{subjectID}
+			subscriptionsReq.ExcludeGroupOwned = true
+		}
+
+		subscriptionsResp, err := s.BillingService.GetSubscriptions(ctx, subscriptionsReq)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, subscriptionsResp.Subscriptions...)
+
+		if page >= subscriptionsResp.TotalPages {
+			return subscriptions, nil
+		}
+	}
+}
+
+// periodSubscription picks the subscription to align a feature's billing period with,
+// preferring a subscription the entitlement comes from
+func periodSubscription(featureEntitlement *entitlement.Entitlement, subscriptions []billing.Subscription) *billing.Subscription {
+	var fallback *billing.Subscription
+
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		if subscription.NextBillingDate == nil {
+			continue
+		}
+
+		for _, source := range featureEntitlement.Sources {
+			if source.Type != entitlement.SourceTypeGrant && source.ID == subscription.ID {
+				return subscription
+			}
+		}
+
+		if fallback == nil && subscription.IsActive() {
+			fallback = subscription
+		}
+	}
+
+	return fallback
+}
+
+// isMetered returns true if usage of the feature is tracked
+func (s *Service) isMetered(featureEntitlement *entitlement.Entitlement) bool {
+	if !featureEntitlement.Unlimited {
+		return true
+	}
+
+	if _, ok := s.policies[featureEntitlement.FeatureSlug]; ok {
+		return true
+	}
+
+	return s.meterEventNames[featureEntitlement.FeatureSlug] != ""
+}
+
+// policyFor returns the quota policy of the feature
+func (s *Service) policyFor(featureSlug string) QuotaPolicy {
+	if policy, ok := s.policies[featureSlug]; ok {
+		return policy
+	}
+
+	return s.defaultPolicy
+}
+
+// recordLock serialises recording for one subject and feature. holders counts the callers
+// holding or waiting for it, so it can be dropped once nobody needs it.
+type recordLock struct {
+	mu      sync.Mutex
+	holders int
+}
+
+// lockSubjectFeature locks recording for the subject and feature, returning the unlock function.
+// The lock is removed once its last holder unlocks, so locks do not build up for every subject
+// and feature ever metered.
+func (s *Service) lockSubjectFeature(subjectType entitlement.SubjectType, subjectID string, featureSlug string) func() {
+	key := string(subjectType) + ":" + subjectID + ":" + featureSlug
+
+	s.recordLocksMu.Lock()
+	if s.recordLocks == nil {
+		s.recordLocks = make(map[string]*recordLock)
+	}
+	lock, ok := s.recordLocks[key]
+	if !ok {
+		lock = &recordLock{}
+		s.recordLocks[key] = lock
+	}
+	lock.holders++
+	s.recordLocksMu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		s.recordLocksMu.Lock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(s.recordLocks, key)
+		}
+		s.recordLocksMu.Unlock()
+	}
+}
+
+// normalisePolicy fills in policy defaults
+func normalisePolicy(policy QuotaPolicy) QuotaPolicy {
+	if policy.Enforcement != QuotaEnforcementSoft {
+		policy.Enforcement = QuotaEnforcementHard
+	}
+
+	if policy.WarningThresholdPercent <= 0 || policy.WarningThresholdPercent > 100 {
+		policy.WarningThresholdPercent = defaultWarningThresholdPercent
+	}
+
+	return policy
+}
+
+// normaliseSubject defaults the subject type to user and validates the subject
+func normaliseSubject(subjectType entitlement.SubjectType, subjectID string) (entitlement.SubjectType, error) {
+	if subjectType == "" {
+		subjectType = entitlement.SubjectTypeUser
+	}
+
+	if !entitlement.IsValidSubjectType(subjectType) {
+		return "", entitlement.ErrInvalidSubjectType
+	}
+
+	if strings.TrimSpace(subjectID) == "" {
+		return "", entitlement.ErrSubjectIDIsRequired
+	}
+
+	return subjectType, nil
+}
+
+// normaliseTimestamp converts an RFC3339 timestamp to the stored timestamp format
+func normaliseTimestamp(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", err
+	}
+
+	return parsed.UTC().Format(common.RFC3339NanoUTC), nil
+}
@@ -0,0 +1,553 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/entitlement"
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+// fakeEntitlementService returns canned entitlements for every subject
+type fakeEntitlementService struct {
+	entitlements []entitlement.Entitlement
+}
+
+func (f *fakeEntitlementService) GetEntitlements(ctx context.Context, req *entitlement.GetEntitlementsRequest) (*entitlement.GetEntitlementsResponse, error) {
+	return &entitlement.GetEntitlementsResponse{
+		EntitlementSet: &entitlement.EntitlementSet{
+			SubjectType:  req.SubjectType,
+			SubjectID:    req.SubjectID,
+			Entitlements: f.entitlements,
+		},
+	}, nil
+}
+
+// fakeBillingService returns canned subscriptions
+type fakeBillingService struct {
+	subscriptions []billing.Subscription
+}
+
+func (f *fakeBillingService) GetSubscriptions(ctx context.Context, req *billing.GetSubscriptionsRequest) (*billing.GetSubscriptionsResponse, error) {
+	var subscriptions []billing.Subscription
+	for _, subscription := range f.subscriptions {
+		if len(req.ForUserIDs) > 0 && subscription.UserID != req.ForUserIDs[0] {
+			continue
+		}
+		if len(req.ForGroupIDs) > 0 && subscription.GroupID != req.ForGroupIDs[0] {
+			continue
+		}
+		if req.ExcludeGroupOwned && subscription.IsGroupOwned() {
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return &billing.GetSubscriptionsResponse{
+		Subscriptions: subscriptions,
+		Total:         len(subscriptions),
+		TotalPages:    1,
+	}, nil
+}
+
+// fakeUsageReporter records reports and fails while err is set
+type fakeUsageReporter struct {
+	reports []paymentprovider.UsageReportRequest
+	err     error
+}
+
+func (f *fakeUsageReporter) ReportUsage(ctx context.Context, providerName string, req *paymentprovider.UsageReportRequest) (*paymentprovider.UsageReport, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.reports = append(f.reports, *req)
+	return &paymentprovider.UsageReport{Identifier: req.Identifier}, nil
+}
+
+var testNow = time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+
+func testSubscription() billing.Subscription {
+	nextBillingDate := time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC)
+	return billing.Subscription{
+		ID:                   "sub-1",
+		UserID:               "user-1",
+		Status:               billing.StatusActive,
+		Integrator:           "stripe",
+		IntegratorCustomerID: "cus_123",
+		BillingInterval:      "month",
+		NextBillingDate:      &nextBillingDate,
+	}
+}
+
+func testEntitlements() []entitlement.Entitlement {
+	return []entitlement.Entitlement{
+		{
+			FeatureSlug: "api-calls",
+			Quantity:    10,
+			Unit:        "call",
+			Sources:     []entitlement.Source{{Type: entitlement.SourceTypeSubscription, ID: "sub-1", Quantity: 10}},
+		},
+		{
+			FeatureSlug: "exports",
+			Unlimited:   true,
+			Sources:     []entitlement.Source{{Type: entitlement.SourceTypeSubscription, ID: "sub-1"}},
+		},
+	}
+}
+
+func newTestService() (*Service, *InMemoryRepository) {
+	repo := NewInMemoryRepository()
+	billingService := &fakeBillingService{subscriptions: []billing.Subscription{testSubscription()}}
+	service := NewService(repo, &fakeEntitlementService{entitlements: testEntitlements()}, billingService)
+	service.now = func() time.Time { return testNow }
+	return service, repo
+}
+
+func recordRequest(key string, quantity int64) *RecordUsageRequest {
+	return &RecordUsageRequest{
+		IdempotencyKey: key,
+		SubjectID:      "user-1",
+		FeatureSlug:    "api-calls",
+		Quantity:       quantity,
+	}
+}
+
+func TestBillingPeriod(t *testing.T) {
+	monthly := testSubscription()
+	endOfMonth := monthly
+	endOfMonthBillingDate := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+	endOfMonth.NextBillingDate = &endOfMonthBillingDate
+
+	tests := []struct {
+		name          string
+		subscription  *billing.Subscription
+		at            time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "Success - period before the next billing date",
+			subscription:  &monthly,
+			at:            testNow,
+			expectedStart: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "Success - period after the next billing date",
+			subscription:  &monthly,
+			at:            time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, time.May, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "Success - month end anchor is clamped to shorter months",
+			subscription:  &endOfMonth,
+			at:            time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "Success - calendar month without a subscription",
+			at:            testNow,
+			expectedStart: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := billingPeriod(tt.subscription, tt.at)
+			if !start.Equal(tt.expectedStart) || !end.Equal(tt.expectedEnd) {
+				t.Fatalf("expected period %s - %s, got %s - %s", tt.expectedStart, tt.expectedEnd, start, end)
+			}
+		})
+	}
+}
+
+func TestService_RecordUsage(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        *QuotaPolicy
+		request       *RecordUsageRequest
+		expectedErr   error
+		expectedUsed  int64
+		expectedOver  bool
+		expectedLimit bool
+	}{
+		{
+			name:          "Success - usage up to the quota",
+			request:       recordRequest("req-2", 2),
+			expectedUsed:  10,
+			expectedLimit: true,
+		},
+		{
+			name:        "Failure - hard quota exceeded",
+			request:     recordRequest("req-2", 3),
+			expectedErr: ErrQuotaExceeded,
+		},
+		{
+			name:          "Success - soft quota records over quota usage",
+			policy:        &QuotaPolicy{Enforcement: QuotaEnforcementSoft},
+			request:       recordRequest("req-2", 3),
+			expectedUsed:  11,
+			expectedOver:  true,
+			expectedLimit: true,
+		},
+		{
+			name:        "Failure - feature not entitled",
+			request:     &RecordUsageRequest{IdempotencyKey: "req-2", SubjectID: "user-1", FeatureSlug: "beta", Quantity: 1},
+			expectedErr: entitlement.ErrFeatureNotEntitled,
+		},
+		{
+			name:        "Failure - missing idempotency key",
+			request:     recordRequest("", 1),
+			expectedErr: ErrIdempotencyKeyIsRequired,
+		},
+		{
+			name:        "Failure - invalid quantity",
+			request:     recordRequest("req-2", 0),
+			expectedErr: ErrInvalidUsageQuantity,
+		},
+		{
+			name:        "Failure - occurred in the future",
+			request:     &RecordUsageRequest{IdempotencyKey: "req-2", SubjectID: "user-1", FeatureSlug: "api-calls", Quantity: 1, OccurredAt: "2027-01-01T00:00:00Z"},
+			expectedErr: ErrInvalidOccurredAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService()
+			if tt.policy != nil {
+				service.WithQuotaPolicy("api-calls", *tt.policy)
+			}
+
+			// Use 8 of the 10 calls first, within quota
+			if _, err := service.RecordUsage(context.Background(), recordRequest("req-1", 8)); err != nil {
+				t.Fatalf("unexpected error seeding usage: %v", err)
+			}
+
+			resp, err := service.RecordUsage(context.Background(), tt.request)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+
+			if resp.Usage.Used != tt.expectedUsed {
+				t.Fatalf("expected used %d, got %d", tt.expectedUsed, resp.Usage.Used)
+			}
+			if resp.UsageEvent.OverQuota != tt.expectedOver {
+				t.Fatalf("expected over quota %v, got %v", tt.expectedOver, resp.UsageEvent.OverQuota)
+			}
+			if resp.Usage.LimitReached != tt.expectedLimit {
+				t.Fatalf("expected limit reached %v, got %v", tt.expectedLimit, resp.Usage.LimitReached)
+			}
+			if resp.UsageEvent.PeriodStart != "2026-03-05T00:00:00" {
+				t.Fatalf("expected usage to be aligned to the billing period, got %s", resp.UsageEvent.PeriodStart)
+			}
+		})
+	}
+}
+
+func TestService_RecordUsage_Idempotent(t *testing.T) {
+	service, _ := newTestService()
+	ctx := context.Background()
+
+	first, err := service.RecordUsage(ctx, recordRequest("req-1", 4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := service.RecordUsage(ctx, recordRequest("req-1", 4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !second.Duplicate || second.UsageEvent.ID != first.UsageEvent.ID {
+		t.Fatalf("expected the original usage event to be returned as a duplicate")
+	}
+	if second.Usage.Used != 4 {
+		t.Fatalf("expected usage to be counted once, got %d", second.Usage.Used)
+	}
+}
+
+func TestService_GetUsage(t *testing.T) {
+	service, _ := newTestService()
+	ctx := context.Background()
+
+	if _, err := service.RecordUsage(ctx, recordRequest("req-1", 9)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := service.GetUsage(ctx, &GetUsageRequest{SubjectID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Usage) != 1 {
+		t.Fatalf("expected only the limited feature to be summarised, got %d", len(resp.Usage))
+	}
+
+	usage := resp.Usage[0]
+	if usage.FeatureSlug != "api-calls" || usage.Used != 9 || usage.Remaining != 1 || !usage.WarningReached || usage.LimitReached {
+		t.Fatalf("unexpected usage summary: %+v", usage)
+	}
+}
+
+func TestService_GetUsage_GroupSubscriptionPeriod(t *testing.T) {
+	groupSubscription := testSubscription()
+	groupSubscription.ID = "sub-group"
+	groupSubscription.GroupID = "group-1"
+
+	service, _ := newTestService()
+	service.BillingService = &fakeBillingService{subscriptions: []billing.Subscription{groupSubscription}}
+	service.EntitlementService = &fakeEntitlementService{entitlements: []entitlement.Entitlement{
+		{FeatureSlug: "api-calls", Quantity: 10, Sources: []entitlement.Source{{Type: entitlement.SourceTypeSubscription, ID: "sub-group", Quantity: 10}}},
+	}}
+
+	resp, err := service.GetUsage(context.Background(), &GetUsageRequest{SubjectType: entitlement.SubjectTypeGroup, SubjectID: "group-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Usage) != 1 || resp.Usage[0].PeriodStart != "2026-03-05T00:00:00" {
+		t.Fatalf("expected group usage to be aligned to the group subscription's billing period, got %+v", resp.Usage)
+	}
+}
+
+func TestService_ReportUsage(t *testing.T) {
+	service, repo := newTestService()
+	reporter := &fakeUsageReporter{err: errors.New("provider unavailable")}
+	service.WithUsageReporter(reporter, map[string]string{"api-calls": "api_calls"})
+	ctx := context.Background()
+
+	resp, err := service.RecordUsage(ctx, recordRequest("req-1", 2))
+	if err != nil {
+		t.Fatalf("expected usage to be recorded when reporting fails, got %v", err)
+	}
+
+	stored, _ := repo.GetUsageEventByID(ctx, resp.UsageEvent.ID)
+	if !stored.IsPendingReport() || stored.ReportAttempts != 1 || stored.LastReportError == "" {
+		t.Fatalf("expected failed report to be pending retry: %+v", stored)
+	}
+
+	reporter.err = nil
+	reportResp, err := service.ReportPendingUsage(ctx, &ReportPendingUsageRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reportResp.Reported != 1 || reportResp.Failed != 0 {
+		t.Fatalf("expected one usage event to be reported, got %+v", reportResp)
+	}
+
+	if len(reporter.reports) != 1 {
+		t.Fatalf("expected one report, got %d", len(reporter.reports))
+	}
+	report := reporter.reports[0]
+	if report.EventName != "api_calls" || report.CustomerID != "cus_123" || report.Quantity != 2 || report.Identifier != resp.UsageEvent.ID {
+		t.Fatalf("unexpected usage report: %+v", report)
+	}
+
+	stored, _ = repo.GetUsageEventByID(ctx, resp.UsageEvent.ID)
+	if stored.IsPendingReport() || stored.ReportedAt == "" {
+		t.Fatalf("expected usage event to be marked reported: %+v", stored)
+	}
+}
+
+func TestMiddleware_Meter(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		used           int64
+		handlerStatus  int
+		expectedStatus int
+		expectedUsed   int64
+	}{
+		{
+			name:           "Success - within quota",
+			userID:         "user-1",
+			handlerStatus:  http.StatusOK,
+			expectedStatus: http.StatusOK,
+			expectedUsed:   1,
+		},
+		{
+			name:           "Success - failed request is not charged",
+			userID:         "user-1",
+			handlerStatus:  http.StatusInternalServerError,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Failure - quota used up",
+			userID:         "user-1",
+			used:           10,
+			handlerStatus:  http.StatusOK,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedUsed:   10,
+		},
+		{
+			name:           "Failure - no user",
+			handlerStatus:  http.StatusOK,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := newTestService()
+			if tt.used > 0 {
+				if _, err := service.RecordUsage(context.Background(), recordRequest("seed", tt.used)); err != nil {
+					t.Fatalf("unexpected error seeding usage: %v", err)
+				}
+			}
+
+			handlerCalled := false
+			handler := NewMiddleware(service).Meter("api-calls")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalled = true
+				w.WriteHeader(tt.handlerStatus)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			if tt.userID != "" {
+				req = req.WithContext(accessmanagerhelpers.TransitWith(req.Context(), tt.userID))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if handlerCalled != (tt.expectedStatus == tt.handlerStatus) {
+				t.Fatalf("expected handler called to be %v", !handlerCalled)
+			}
+
+			if tt.userID != "" {
+				used, err := repo.SumUsage(context.Background(), entitlement.SubjectTypeUser, tt.userID, "api-calls", "", "")
+				if err != nil {
+					t.Fatalf("unexpected error summing usage: %v", err)
+				}
+				if used != tt.expectedUsed {
+					t.Fatalf("expected %d units used, got %d", tt.expectedUsed, used)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ReservedUsage(t *testing.T) {
+	service, repo := newTestService()
+	reporter := &fakeUsageReporter{}
+	service.WithUsageReporter(reporter, map[string]string{"api-calls": "api_calls"})
+	ctx := context.Background()
+
+	reserve := func(key string) *RecordUsageResponse {
+		request := recordRequest(key, 5)
+		request.Reserve = true
+		resp, err := service.RecordUsage(ctx, request)
+		if err != nil {
+			t.Fatalf("unexpected error reserving usage: %v", err)
+		}
+		return resp
+	}
+
+	confirmed := reserve("req-1")
+	released := reserve("req-2")
+
+	// reservations count towards the quota before they are confirmed
+	request := recordRequest("req-3", 1)
+	request.Reserve = true
+	if _, err := service.RecordUsage(ctx, request); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected %v while the quota is reserved, got %v", ErrQuotaExceeded, err)
+	}
+	if len(reporter.reports) != 0 {
+		t.Fatalf("expected reserved usage not to be reported, got %+v", reporter.reports)
+	}
+
+	if _, err := service.ConfirmUsage(ctx, &ConfirmUsageRequest{UsageEventID: confirmed.UsageEvent.ID}); err != nil {
+		t.Fatalf("unexpected error confirming usage: %v", err)
+	}
+	if len(reporter.reports) != 1 || reporter.reports[0].Identifier != confirmed.UsageEvent.ID {
+		t.Fatalf("expected the confirmed usage to be reported, got %+v", reporter.reports)
+	}
+
+	if err := service.ReleaseUsage(ctx, &ReleaseUsageRequest{UsageEventID: released.UsageEvent.ID}); err != nil {
+		t.Fatalf("unexpected error releasing usage: %v", err)
+	}
+	// confirmed usage is never given back
+	if err := service.ReleaseUsage(ctx, &ReleaseUsageRequest{UsageEventID: confirmed.UsageEvent.ID}); err != nil {
+		t.Fatalf("unexpected error releasing confirmed usage: %v", err)
+	}
+
+	used, err := repo.SumUsage(ctx, entitlement.SubjectTypeUser, "user-1", "api-calls", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error summing usage: %v", err)
+	}
+	if used != 5 {
+		t.Fatalf("expected only the confirmed usage to remain, got %d", used)
+	}
+}
+
+func TestMiddleware_MeterReservesQuotaForRequestsInFlight(t *testing.T) {
+	service, repo := newTestService()
+	if _, err := service.RecordUsage(context.Background(), recordRequest("seed", 9)); err != nil {
+		t.Fatalf("unexpected error seeding usage: %v", err)
+	}
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	handler := NewMiddleware(service).Meter("api-calls")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+	}))
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+		req = req.WithContext(accessmanagerhelpers.TransitWith(req.Context(), "user-1"))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	inFlight := make(chan *httptest.ResponseRecorder)
+	go func() { inFlight <- request() }()
+	<-started
+
+	if rec := request(); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the last unit to be held by the request in flight, got status %d", rec.Code)
+	}
+
+	close(finish)
+	if rec := <-inFlight; rec.Code != http.StatusOK {
+		t.Fatalf("expected the request in flight to succeed, got status %d", rec.Code)
+	}
+
+	used, err := repo.SumUsage(context.Background(), entitlement.SubjectTypeUser, "user-1", "api-calls", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error summing usage: %v", err)
+	}
+	if used != 10 {
+		t.Fatalf("expected 10 units used, got %d", used)
+	}
+}
+
+func TestService_LockSubjectFeatureDropsReleasedLocks(t *testing.T) {
+	service, _ := newTestService()
+
+	unlock := service.lockSubjectFeature(entitlement.SubjectTypeUser, "user-1", "api-calls")
+	if len(service.recordLocks) != 1 {
+		t.Fatalf("expected 1 held lock, got %d", len(service.recordLocks))
+	}
+
+	unlock()
+	if len(service.recordLocks) != 0 {
+		t.Fatalf("expected released lock to be dropped, got %d", len(service.recordLocks))
+	}
+}
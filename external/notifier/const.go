@@ -1,5 +1,7 @@
 package notifier
 
+import "time"
+
 const (
 	// NotificationAddressesCollection is the MongoDB collection that stores every
 	// notification destination registered by a user – browser tabs, mobile phones,
@@ -18,11 +20,72 @@ const (
 	// document.
 	NotificationPreferencesCollection = "notification_preferences"
 
+	// NotificationTopicSubscriptionsCollection is the MongoDB collection that
+	// stores which users have subscribed to which broadcast topics.
+	//
+	// Each document links exactly one user to one topic, and a unique index on
+	// (topic, user_id) keeps repeated subscribe calls idempotent.
+	NotificationTopicSubscriptionsCollection = "notification_topic_subscriptions"
+
+	// NotificationDispatchJobsCollection is the MongoDB collection that stores
+	// the progress of every multi-user notification dispatch.
+	//
+	// A job remembers the cursor of the last fully delivered recipient batch
+	// so a dispatch interrupted by a crash or deploy can be resumed without
+	// resending to everyone who was already notified.
+	NotificationDispatchJobsCollection = "notification_dispatch_jobs"
+
 	// defaultCollectionInitMaxAttemptsLimit controls how many times the repository
 	// will retry its MongoDB collection initialisation before giving up.
 	// This makes the notifier package resilient to brief database connection
 	// hiccups during startup without looping forever.
 	defaultCollectionInitMaxAttemptsLimit = 3
+
+	// defaultDispatchBatchSize is how many recipients a dispatch job pulls
+	// from the database before checkpointing its progress.
+	defaultDispatchBatchSize = 100
+
+	// maxDispatchResponseResults caps the per-user results and errors a
+	// dispatch run keeps in memory, so broadcasts to large audiences stream
+	// their recipients without holding them all. Job progress still counts
+	// every recipient.
+	maxDispatchResponseResults = 1000
+
+	// defaultDispatchConcurrency is how many recipients in a batch are
+	// notified in parallel.
+	defaultDispatchConcurrency = 8
+
+	// dispatchJobHeartbeatInterval is how often the run delivering a
+	// dispatch job records that it is still working on it.
+	dispatchJobHeartbeatInterval = time.Minute
+
+	// dispatchJobStaleAfter is how long a running dispatch job can go
+	// without a heartbeat before another run may claim and resume it.
+	dispatchJobStaleAfter = 5 * time.Minute
+)
+
+const (
+	// NotificationTopicGroupPrefix prefixes topics that are scoped to a single
+	// group, for example "group:3f2a…". Use GroupTopic to build them.
+	NotificationTopicGroupPrefix = "group:"
+
+	// notificationTopicMaxLength caps how long a topic name may be.
+	notificationTopicMaxLength = 128
+)
+
+const (
+	// NotificationDispatchJobStatusRunning means the dispatch is in progress,
+	// or was interrupted before it could finish and is waiting to be resumed.
+	NotificationDispatchJobStatusRunning NotificationDispatchJobStatus = "RUNNING"
+
+	// NotificationDispatchJobStatusCompleted means every recipient batch has
+	// been processed.
+	NotificationDispatchJobStatusCompleted NotificationDispatchJobStatus = "COMPLETED"
+
+	// NotificationDispatchJobStatusFailed means the dispatch stopped because
+	// recipients could not be resolved. The job keeps its cursor and can be
+	// resumed once the underlying problem is fixed.
+	NotificationDispatchJobStatusFailed NotificationDispatchJobStatus = "FAILED"
 )
 
 const (
@@ -39,6 +102,13 @@ const (
 	// specific app installation. GHATD stores this token so it can route push
 	// notifications through Firebase to reach that device.
 	NotificationChannelFCM NotificationChannel = "FCM"
+
+	// NotificationChannelSlack represents a Slack conversation, usually the
+	// user's own Slack member ID so notifications arrive as direct messages.
+	//
+	// Slack delivery needs a bot token, incoming webhooks cannot reach
+	// individual users.
+	NotificationChannelSlack NotificationChannel = "SLACK"
 )
 
 const (
@@ -74,4 +144,10 @@ const (
 	ErrKeyNotificationSenderNotEnabled   = "NotificationSenderNotEnabled"
 	ErrKeyNotificationSendFailed         = "NotificationSendFailed"
 	ErrKeyNotificationUserIDRequired     = "NotificationUserIDRequired"
+	ErrKeyInvalidNotificationTopic       = "InvalidNotificationTopic"
+	ErrKeyInvalidNotificationTarget      = "InvalidNotificationTarget"
+	ErrKeySegmentResolverNotConfigured   = "NotificationSegmentResolverNotConfigured"
+	ErrKeyDispatchJobNotFound            = "NotificationDispatchJobNotFound"
+	ErrKeyDispatchJobAlreadyCompleted    = "NotificationDispatchJobAlreadyCompleted"
+	ErrKeyDispatchJobAlreadyClaimed      = "NotificationDispatchJobAlreadyClaimed"
 )
@@ -75,4 +75,40 @@ var NotifierErrorMap reply.ErrorManifest = reply.ErrorManifest{
 		StatusCode: http.StatusBadRequest,
 		Code:       "NTF00-009",
 	},
+	ErrInvalidNotificationTopic: {
+		Title:      "Bad Request",
+		Detail:     "The notification topic is invalid.",
+		StatusCode: http.StatusBadRequest,
+		Code:       "NTF00-010",
+	},
+	ErrInvalidNotificationTarget: {
+		Title:      "Bad Request",
+		Detail:     "Choose only one of user IDs, a topic, or a segment for a notification dispatch.",
+		StatusCode: http.StatusBadRequest,
+		Code:       "NTF00-011",
+	},
+	ErrSegmentResolverNotConfigured: {
+		Title:      "Service Unavailable",
+		Detail:     "Segment targeting is not enabled for notifications.",
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       "NTF00-012",
+	},
+	ErrDispatchJobNotFound: {
+		Title:      "Not Found",
+		Detail:     "The requested notification dispatch could not be found.",
+		StatusCode: http.StatusNotFound,
+		Code:       "NTF00-013",
+	},
+	ErrDispatchJobAlreadyCompleted: {
+		Title:      "Conflict",
+		Detail:     "The notification dispatch has already completed.",
+		StatusCode: http.StatusConflict,
+		Code:       "NTF00-014",
+	},
+	ErrDispatchJobAlreadyClaimed: {
+		Title:      "Conflict",
+		Detail:     "The notification dispatch is already being delivered.",
+		StatusCode: http.StatusConflict,
+		Code:       "NTF00-015",
+	},
 }
@@ -55,4 +55,29 @@ var (
 	// ErrNotificationUserIDRequired means a user ID was empty or missing
 	// from a request that requires one.
 	ErrNotificationUserIDRequired = errors.New(ErrKeyNotificationUserIDRequired)
+
+	// ErrInvalidNotificationTopic means a topic name was empty, too long, or
+	// contained characters outside lowercase letters, digits, and ":._-".
+	ErrInvalidNotificationTopic = errors.New(ErrKeyInvalidNotificationTopic)
+
+	// ErrInvalidNotificationTarget means a dispatch request mixed more than
+	// one way of choosing recipients (explicit user IDs, a topic, or a
+	// segment). Each dispatch must pick exactly one, or none for everyone.
+	ErrInvalidNotificationTarget = errors.New(ErrKeyInvalidNotificationTarget)
+
+	// ErrSegmentResolverNotConfigured means a dispatch asked for a segment
+	// but no SegmentResolver has been wired into the service.
+	ErrSegmentResolverNotConfigured = errors.New(ErrKeySegmentResolverNotConfigured)
+
+	// ErrDispatchJobNotFound means the requested dispatch job does not exist.
+	ErrDispatchJobNotFound = errors.New(ErrKeyDispatchJobNotFound)
+
+	// ErrDispatchJobAlreadyCompleted means a resume was requested for a
+	// dispatch job that has already delivered to every recipient.
+	ErrDispatchJobAlreadyCompleted = errors.New(ErrKeyDispatchJobAlreadyCompleted)
+
+	// ErrDispatchJobAlreadyClaimed means the dispatch job is being delivered
+	// by another run, which has recorded a heartbeat within the last five
+	// minutes.
+	ErrDispatchJobAlreadyClaimed = errors.New(ErrKeyDispatchJobAlreadyClaimed)
 )
@@ -24,6 +24,19 @@
 //
 //  1. An index on the enabled field for queries that need to find users
 //     who have opted in or out of notifications.
+//
+// The notification_topic_subscriptions collection has two indexes:
+//
+//  1. A unique compound index on (topic, user_id) that keeps a single
+//     subscription per user and topic and lets topic dispatches page
+//     through subscribers in user ID order.
+//
+//  2. An index on user_id for listing the topics a user follows.
+//
+// The notification_dispatch_jobs collection has one index:
+//
+//  1. A compound index on (status, metadata.created_at) for finding
+//     interrupted RUNNING jobs that need to be resumed.
 package migrations
 
 import (
@@ -38,9 +51,10 @@ import (
 )
 
 // InitNotifierIndexesUp creates all the indexes the notifier package needs.
-// It is called during migrations to set up the notification_addresses and
-// notification_preferences collections when deploying to a fresh database
-// or upgrading an existing one.
+// It is called during migrations to set up the notification_addresses,
+// notification_preferences, notification_topic_subscriptions, and
+// notification_dispatch_jobs collections when deploying to a fresh
+// database or upgrading an existing one.
 func InitNotifierIndexesUp(db *mongo.Database) error { //Up
 	log.SetFlags(0)
 	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-notifier-indexes"))
@@ -83,6 +97,40 @@ func InitNotifierIndexesUp(db *mongo.Database) error { //Up
 		return err
 	}
 
+	_, err = db.Collection(notifier.NotificationTopicSubscriptionsCollection).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "topic", Value: 1}, {Key: "user_id", Value: 1}},
+				Options: options.Index().
+					SetName("idx_notification_topic_subscriptions_topic_user").
+					SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}},
+				Options: options.Index().SetName("idx_notification_topic_subscriptions_user"),
+			},
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-notification-topic-subscriptions-indexes"))
+		return err
+	}
+
+	_, err = db.Collection(notifier.NotificationDispatchJobsCollection).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "metadata.created_at", Value: 1}},
+				Options: options.Index().SetName("idx_notification_dispatch_jobs_status_created_at"),
+			},
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-notification-dispatch-jobs-indexes"))
+		return err
+	}
+
 	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-notifier-indexes"))
 	return nil
 }
@@ -94,6 +142,22 @@ func InitNotifierIndexesDown(db *mongo.Database) error { //Down
 	log.SetFlags(0)
 	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-notifier-indexes"))
 
+	if err := db.Collection(notifier.NotificationDispatchJobsCollection).Indexes().DropOne(context.TODO(), "idx_notification_dispatch_jobs_status_created_at"); err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: idx_notification_dispatch_jobs_status_created_at"))
+		return err
+	}
+
+	topicIndexNames := []string{
+		"idx_notification_topic_subscriptions_topic_user",
+		"idx_notification_topic_subscriptions_user",
+	}
+	for _, indexName := range topicIndexNames {
+		if err := db.Collection(notifier.NotificationTopicSubscriptionsCollection).Indexes().DropOne(context.TODO(), indexName); err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: "+indexName))
+			return err
+		}
+	}
+
 	addressIndexNames := []string{
 		"idx_notification_addresses_channel_address_hash",
 		"idx_notification_addresses_user_status",
@@ -101,6 +101,15 @@ type FCMAddress struct {
 	Token string `json:"token" bson:"token"`
 }
 
+// SlackAddress stores the Slack conversation a user is notified in.
+//
+// ChannelID is usually the user's Slack member ID (e.g. U0123ABCD) so
+// notifications arrive as direct messages from the bot, but any channel
+// ID the bot can post to works.
+type SlackAddress struct {
+	ChannelID string `json:"channel_id" bson:"channel_id"`
+}
+
 // NotificationAddressMetadata records when an address was created, last
 // updated, and last seen.
 //
@@ -133,6 +142,7 @@ type NotificationAddress struct {
 	Platform    string                       `json:"platform,omitempty" bson:"platform,omitempty"`
 	WebPush     *WebPushAddress              `json:"webpush,omitempty" bson:"webpush,omitempty"`
 	FCM         *FCMAddress                  `json:"fcm,omitempty" bson:"fcm,omitempty"`
+	Slack       *SlackAddress                `json:"slack,omitempty" bson:"slack,omitempty"`
 	Metadata    *NotificationAddressMetadata `json:"metadata,omitempty" bson:"metadata,omitempty"`
 }
 
@@ -199,6 +209,7 @@ type NotifierConfig struct {
 	SupportedChannels []NotificationChannel `json:"supported_channels"`
 	WebPush           WebPushClientConfig   `json:"webpush"`
 	FCM               FCMClientConfig       `json:"fcm"`
+	Slack             SlackClientConfig     `json:"slack"`
 }
 
 // WebPushClientConfig contains the information a browser needs to
@@ -219,6 +230,12 @@ type FCMClientConfig struct {
 	Enabled bool `json:"enabled"`
 }
 
+// SlackClientConfig tells clients whether users can register a Slack
+// address on this server.
+type SlackClientConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
 // Sanitise returns a client-safe summary without endpoint or token secrets.
 //
 // The full NotificationAddress contains sensitive information like the
@@ -274,11 +291,11 @@ func (c NotificationChannel) Normalised() NotificationChannel {
 // IsSupported returns true when the notifier package knows how to
 // handle this channel.
 //
-// Currently supported channels are WEBPUSH and FCM. Unknown channels
+// Currently supported channels are WEBPUSH, FCM and SLACK. Unknown channels
 // are rejected early so invalid data never reaches the database.
 func (c NotificationChannel) IsSupported() bool {
 	switch c.Normalised() {
-	case NotificationChannelWebPush, NotificationChannelFCM:
+	case NotificationChannelWebPush, NotificationChannelFCM, NotificationChannelSlack:
 		return true
 	default:
 		return false
@@ -291,7 +308,7 @@ func (c NotificationChannel) IsSupported() bool {
 // A real user may not have any preferences document stored yet.
 // When that happens, the service returns these defaults:
 //   - Notifications are enabled globally.
-//   - All channels (Web Push and FCM) are enabled individually.
+//   - All channels (Web Push, FCM and Slack) are enabled individually.
 func DefaultNotificationPreferences(userID string) *NotificationPreferences {
 	return &NotificationPreferences{
 		UserID:  userID,
@@ -299,6 +316,166 @@ func DefaultNotificationPreferences(userID string) *NotificationPreferences {
 		Channels: map[string]bool{
 			string(NotificationChannelWebPush): true,
 			string(NotificationChannelFCM):     true,
+			string(NotificationChannelSlack):   true,
 		},
 	}
 }
+
+// NotificationTopicSubscription records that one user wants to hear about
+// one broadcast topic.
+//
+// Topics are short lowercase names such as "release-notes", or group-scoped
+// names such as "group:{id}" built with GroupTopic. A dispatch that targets
+// a topic streams its subscribers instead of every registered device.
+type NotificationTopicSubscription struct {
+	ID        string `json:"id" bson:"_id"`
+	UserID    string `json:"user_id" bson:"user_id"`
+	Topic     string `json:"topic" bson:"topic"`
+	CreatedAt string `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// NotificationSegment describes a slice of the user base to notify.
+//
+// Every populated field narrows the audience, so a segment with Roles and
+// GroupIDs only matches users who have one of the roles AND belong to one of
+// the groups. The notifier package does not know about users itself; it asks
+// the configured SegmentResolver to turn a segment into user IDs.
+type NotificationSegment struct {
+	Roles          []string    `json:"roles,omitempty" bson:"roles,omitempty"`
+	GroupIDs       []string    `json:"group_ids,omitempty" bson:"group_ids,omitempty"`
+	UserTypes      []string    `json:"user_types,omitempty" bson:"user_types,omitempty"`
+	ExtensionKey   string      `json:"extension_key,omitempty" bson:"extension_key,omitempty"`
+	ExtensionValue interface{} `json:"extension_value,omitempty" bson:"extension_value,omitempty"`
+}
+
+// NotificationDispatchJobStatus tells you where a dispatch job is in its
+// lifecycle.
+//
+//   - RUNNING means batches are still being delivered, or the process
+//     stopped before it finished and the job is waiting to be resumed.
+//   - COMPLETED means every recipient batch was processed.
+//   - FAILED means recipients could not be resolved; the job can be resumed.
+type NotificationDispatchJobStatus string
+
+// NotificationDispatchTarget records how a dispatch chooses its recipients.
+//
+// At most one of UserIDs, Topic, or Segment is set. When none are set the
+// dispatch goes to every user with at least one active address.
+type NotificationDispatchTarget struct {
+	UserIDs []string             `json:"user_ids,omitempty" bson:"user_ids,omitempty"`
+	Topic   string               `json:"topic,omitempty" bson:"topic,omitempty"`
+	Segment *NotificationSegment `json:"segment,omitempty" bson:"segment,omitempty"`
+}
+
+// NotificationDispatchProgress counts what a dispatch job has done so far.
+//
+//   - Batches: how many recipient batches have been fully processed.
+//   - TargetedUsers: how many users were picked up from those batches.
+//   - NotifiedUsers: users with at least one successful channel send.
+//   - SkippedUsers: users with no active addresses or whose preferences
+//     blocked every channel.
+//   - FailedUsers: users where at least one channel send failed.
+type NotificationDispatchProgress struct {
+	Batches       int `json:"batches" bson:"batches"`
+	TargetedUsers int `json:"targeted_users" bson:"targeted_users"`
+	NotifiedUsers int `json:"notified_users" bson:"notified_users"`
+	SkippedUsers  int `json:"skipped_users" bson:"skipped_users"`
+	FailedUsers   int `json:"failed_users" bson:"failed_users"`
+}
+
+// NotificationDispatchJobMetadata records when a dispatch job was created,
+// last checkpointed, and finished.
+type NotificationDispatchJobMetadata struct {
+	CreatedAt   string `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// NotificationDispatchJob is the resumable record of one multi-user
+// notification dispatch.
+//
+// Recipients are streamed in batches ordered by user ID. After each batch
+// has been delivered the job stores the batch's last user ID as its Cursor.
+// If the process dies mid-broadcast, resuming the job starts again from
+// the cursor, so at most one batch is delivered twice rather than the
+// entire audience.
+type NotificationDispatchJob struct {
+	ID          string                           `json:"id" bson:"_id"`
+	Status      NotificationDispatchJobStatus    `json:"status" bson:"status"`
+	RequestedBy string                           `json:"requested_by,omitempty" bson:"requested_by,omitempty"`
+	Title       string                           `json:"title" bson:"title"`
+	Message     string                           `json:"message" bson:"message"`
+	Channels    []NotificationChannel            `json:"channels,omitempty" bson:"channels,omitempty"`
+	Data        map[string]interface{}           `json:"data,omitempty" bson:"data,omitempty"`
+	Target      NotificationDispatchTarget       `json:"target" bson:"target"`
+	Cursor      string                           `json:"cursor,omitempty" bson:"cursor,omitempty"`
+	Progress    NotificationDispatchProgress     `json:"progress" bson:"progress"`
+	LastError   string                           `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	Metadata    *NotificationDispatchJobMetadata `json:"metadata,omitempty" bson:"metadata,omitempty"`
+
+	// ClaimedBy identifies the run delivering the job, which keeps
+	// HeartbeatAt up to date while it works. Another run can only claim
+	// the job once the heartbeat is stale.
+	ClaimedBy   string `json:"-" bson:"claimed_by,omitempty"`
+	HeartbeatAt string `json:"-" bson:"heartbeat_at,omitempty"`
+}
+
+// IsEmpty returns true when the segment has no filters at all.
+func (s *NotificationSegment) IsEmpty() bool {
+	return s == nil ||
+		(len(s.Roles) == 0 && len(s.GroupIDs) == 0 && len(s.UserTypes) == 0 && strings.TrimSpace(s.ExtensionKey) == "")
+}
+
+// GroupTopic returns the group-scoped topic name for a group ID.
+//
+// Subscribing a user to GroupTopic(id) lets admins or the group's own
+// features notify everyone who opted in to that group's announcements.
+func GroupTopic(groupID string) string {
+	return NotificationTopicGroupPrefix + strings.TrimSpace(groupID)
+}
+
+// GroupIDFromTopic returns the group ID from a group-scoped topic and
+// true, or an empty string and false for any other topic.
+func GroupIDFromTopic(topic string) (string, bool) {
+	if !strings.HasPrefix(topic, NotificationTopicGroupPrefix) {
+		return "", false
+	}
+	groupID := strings.TrimPrefix(topic, NotificationTopicGroupPrefix)
+	if groupID == "" {
+		return "", false
+	}
+	return groupID, true
+}
+
+// NormaliseTopic returns the canonical form of a topic name, or
+// ErrInvalidNotificationTopic if the name cannot be used.
+//
+// Topics are lowercased and trimmed. Only letters, digits, and the ":",
+// ".", "_", and "-" separators are allowed so topics stay safe to use in
+// URLs and log fields. Group IDs inside "group:{id}" topics keep their
+// original casing because IDs are case sensitive.
+func NormaliseTopic(topic string) (string, error) {
+	topic = strings.TrimSpace(topic)
+	prefixLength := len(NotificationTopicGroupPrefix)
+	if len(topic) >= prefixLength && strings.EqualFold(topic[:prefixLength], NotificationTopicGroupPrefix) {
+		topic = GroupTopic(topic[prefixLength:])
+		if _, ok := GroupIDFromTopic(topic); !ok {
+			return "", ErrInvalidNotificationTopic
+		}
+	} else {
+		topic = strings.ToLower(topic)
+	}
+
+	if topic == "" || len(topic) > notificationTopicMaxLength {
+		return "", ErrInvalidNotificationTopic
+	}
+	for _, r := range topic {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == ':', r == '.', r == '_', r == '-':
+		default:
+			return "", ErrInvalidNotificationTopic
+		}
+	}
+	return topic, nil
+}
@@ -24,6 +24,8 @@ type MongoDbStore interface {
 	ExecuteDeleteManyCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, targetObjectName string) error
 	ExecuteFindOneCommandDecodeResult(ctx context.Context, collection *mongo.Collection, filter interface{}, result interface{}, resultObjectName string, logError bool, onFailureErr error) error
 	ExecuteUpdateOneCommand(ctx context.Context, collection *mongo.Collection, filter interface{}, update interface{}, targetObjectName string) error
+	ExecuteInsertOneCommand(ctx context.Context, collection *mongo.Collection, document interface{}, resultObjectName string) (*mongo.InsertOneResult, error)
+	ExecuteAggregateCommand(ctx context.Context, collection *mongo.Collection, mongoPipeline []bson.D) (*mongo.Cursor, error)
 
 	GetDatabase(ctx context.Context, dbName string) (*mongo.Database, error)
 	InitialiseClient(ctx context.Context) (*mongo.Client, error)
@@ -32,10 +34,12 @@ type MongoDbStore interface {
 
 // Repository manages notifier data in MongoDB.
 //
-// A Repository owns two MongoDB collections:
+// A Repository owns four MongoDB collections:
 //
 //   - notification_addresses – stores each user's registered devices.
 //   - notification_preferences – stores each user's notification choices.
+//   - notification_topic_subscriptions – stores which topics each user follows.
+//   - notification_dispatch_jobs – stores resumable multi-user dispatch progress.
 //
 // Collection access is lazy: the first time a method needs a collection,
 // the repository connects to MongoDB. On transient failures, it retries
@@ -48,10 +52,14 @@ type Repository struct {
 	Store                          MongoDbStore
 	collectionInitMaxAttemptsLimit int
 
-	addressesCollection        *mongo.Collection
-	addressesCollectionMutex   sync.Mutex
-	preferencesCollection      *mongo.Collection
-	preferencesCollectionMutex sync.Mutex
+	addressesCollection         *mongo.Collection
+	addressesCollectionMutex    sync.Mutex
+	preferencesCollection       *mongo.Collection
+	preferencesCollectionMutex  sync.Mutex
+	topicsCollection            *mongo.Collection
+	topicsCollectionMutex       sync.Mutex
+	dispatchJobsCollection      *mongo.Collection
+	dispatchJobsCollectionMutex sync.Mutex
 }
 
 // NewRepository creates a notifier repository backed by the given MongoDB store.
@@ -110,6 +118,42 @@ func (r *Repository) GetNotificationPreferencesCollection(ctx context.Context) (
 	return r.preferencesCollection, nil
 }
 
+// GetNotificationTopicSubscriptionsCollection returns the topic
+// subscriptions MongoDB collection, initialising it on first access.
+func (r *Repository) GetNotificationTopicSubscriptionsCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.topicsCollectionMutex.Lock()
+	defer r.topicsCollectionMutex.Unlock()
+
+	if r.topicsCollection != nil {
+		return r.topicsCollection, nil
+	}
+
+	collection, err := r.getCollection(ctx, NotificationTopicSubscriptionsCollection)
+	if err != nil {
+		return nil, err
+	}
+	r.topicsCollection = collection
+	return r.topicsCollection, nil
+}
+
+// GetNotificationDispatchJobsCollection returns the dispatch jobs MongoDB
+// collection, initialising it on first access.
+func (r *Repository) GetNotificationDispatchJobsCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.dispatchJobsCollectionMutex.Lock()
+	defer r.dispatchJobsCollectionMutex.Unlock()
+
+	if r.dispatchJobsCollection != nil {
+		return r.dispatchJobsCollection, nil
+	}
+
+	collection, err := r.getCollection(ctx, NotificationDispatchJobsCollection)
+	if err != nil {
+		return nil, err
+	}
+	r.dispatchJobsCollection = collection
+	return r.dispatchJobsCollection, nil
+}
+
 // getCollection initialises a MongoDB collection by name with retry logic.
 // On transient failures (no client yet, database not available), it retries
 // up to collectionInitMaxAttemptsLimit times.
@@ -152,8 +196,8 @@ func (r *Repository) getCollection(ctx context.Context, name string) (*mongo.Col
 // created_at).
 //
 // Channel-specific payloads are exclusive: when upserting a WEBPUSH
-// address, any previous FCM payload on the document is unset, and
-// vice versa.
+// address, any previous FCM or Slack payload on the document is unset,
+// and likewise for the other channels.
 func (r *Repository) UpsertAddress(ctx context.Context, address *NotificationAddress) (*NotificationAddress, error) {
 	collection, err := r.GetNotificationAddressesCollection(ctx)
 	if err != nil {
@@ -176,9 +220,15 @@ func (r *Repository) UpsertAddress(ctx context.Context, address *NotificationAdd
 	case NotificationChannelWebPush:
 		setFields["webpush"] = address.WebPush
 		unsetFields["fcm"] = ""
+		unsetFields["slack"] = ""
 	case NotificationChannelFCM:
 		setFields["fcm"] = address.FCM
 		unsetFields["webpush"] = ""
+		unsetFields["slack"] = ""
+	case NotificationChannelSlack:
+		setFields["slack"] = address.Slack
+		unsetFields["webpush"] = ""
+		unsetFields["fcm"] = ""
 	}
 
 	update := bson.M{
@@ -245,6 +295,54 @@ func (r *Repository) GetAllActiveAddresses(ctx context.Context, channels ...Noti
 	return r.findAddresses(ctx, filter)
 }
 
+// GetActiveAddressUserIDs returns up to limit distinct user IDs that own
+// at least one active address, in ascending order and strictly after
+// afterUserID.
+//
+// The broadcast dispatch path pages through this method instead of loading
+// every address into memory. Passing the last returned ID back in as
+// afterUserID fetches the next page.
+func (r *Repository) GetActiveAddressUserIDs(ctx context.Context, afterUserID string, limit int, channels ...NotificationChannel) ([]string, error) {
+	collection, err := r.GetNotificationAddressesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	match := bson.D{{Key: "status", Value: NotificationAddressStatusActive}}
+	if afterUserID != "" {
+		match = append(match, bson.E{Key: "user_id", Value: bson.M{"$gt": afterUserID}})
+	}
+	if len(channels) > 0 {
+		match = append(match, bson.E{Key: "channel", Value: bson.M{"$in": channels}})
+	}
+
+	pipeline := []bson.D{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$user_id"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+
+	cursor, err := r.Store.ExecuteAggregateCommand(ctx, collection, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		UserID string `bson:"_id"`
+	}
+	if err := r.Store.MapAllInCursorToResult(ctx, cursor, &rows, "notification_address_user_ids"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	userIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		userIDs = append(userIDs, row.UserID)
+	}
+	return userIDs, nil
+}
+
 // GetAddressesByUserID returns all addresses for a user, regardless of
 // status (ACTIVE and DISABLED).
 //
@@ -480,3 +578,293 @@ func (r *Repository) DeletePreferencesByUserID(ctx context.Context, userID strin
 
 	return nil
 }
+
+// UpsertTopicSubscription links a user to a topic, keyed by (topic,
+// user_id) so repeated subscribe calls leave a single document behind.
+func (r *Repository) UpsertTopicSubscription(ctx context.Context, subscription *NotificationTopicSubscription) (*NotificationTopicSubscription, error) {
+	collection, err := r.GetNotificationTopicSubscriptionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"_id":        subscription.ID,
+			"created_at": subscription.CreatedAt,
+		},
+	}
+
+	var result NotificationTopicSubscription
+	err = collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"topic": subscription.Topic, "user_id": subscription.UserID},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return &result, nil
+}
+
+// DeleteTopicSubscription removes a user's subscription to a topic. It is
+// a no-op when the user is not subscribed.
+func (r *Repository) DeleteTopicSubscription(ctx context.Context, userID, topic string) error {
+	collection, err := r.GetNotificationTopicSubscriptionsCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Store.ExecuteDeleteManyCommand(ctx, collection, bson.M{"topic": topic, "user_id": userID}, "notification_topic_subscription"); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+// DeleteTopicSubscriptionsByUserID removes every topic subscription a user
+// holds. This is used during account cleanup when a user is deleted.
+func (r *Repository) DeleteTopicSubscriptionsByUserID(ctx context.Context, userID string) error {
+	collection, err := r.GetNotificationTopicSubscriptionsCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Store.ExecuteDeleteManyCommand(ctx, collection, bson.M{"user_id": userID}, "notification_topic_subscriptions"); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+// GetTopicSubscriptionsByUserID returns every topic a user follows, sorted
+// by topic name.
+func (r *Repository) GetTopicSubscriptionsByUserID(ctx context.Context, userID string) ([]NotificationTopicSubscription, error) {
+	collection, err := r.GetNotificationTopicSubscriptionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "topic", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []NotificationTopicSubscription
+	if err := r.Store.MapAllInCursorToResult(ctx, cursor, &results, "notification_topic_subscriptions"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return results, nil
+}
+
+// GetTopicSubscriberUserIDs returns up to limit subscriber user IDs for a
+// topic, in ascending order and strictly after afterUserID.
+func (r *Repository) GetTopicSubscriberUserIDs(ctx context.Context, topic, afterUserID string, limit int) ([]string, error) {
+	collection, err := r.GetNotificationTopicSubscriptionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"topic": topic}
+	if afterUserID != "" {
+		filter["user_id"] = bson.M{"$gt": afterUserID}
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "user_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"user_id": 1})
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	defer cursor.Close(ctx)
+
+	var subscriptions []NotificationTopicSubscription
+	if err := r.Store.MapAllInCursorToResult(ctx, cursor, &subscriptions, "notification_topic_subscriptions"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	userIDs := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		userIDs = append(userIDs, subscription.UserID)
+	}
+	return userIDs, nil
+}
+
+// CreateDispatchJob stores a new dispatch job record.
+func (r *Repository) CreateDispatchJob(ctx context.Context, job *NotificationDispatchJob) error {
+	collection, err := r.GetNotificationDispatchJobsCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.Store.ExecuteInsertOneCommand(ctx, collection, job, "notification_dispatch_job"); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+// GetDispatchJobByID returns a dispatch job, or ErrDispatchJobNotFound
+// when no job has the given ID.
+func (r *Repository) GetDispatchJobByID(ctx context.Context, jobID string) (*NotificationDispatchJob, error) {
+	collection, err := r.GetNotificationDispatchJobsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var job NotificationDispatchJob
+	if err := r.Store.ExecuteFindOneCommandDecodeResult(ctx, collection, bson.M{"_id": jobID}, &job, "notification_dispatch_job", false, ErrDispatchJobNotFound); err != nil {
+		if !errors.Is(err, ErrDispatchJobNotFound) {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// GetStaleDispatchJobs returns running dispatch jobs that have had no
+// heartbeat since staleBefore, oldest first. These are jobs whose run
+// stopped, usually because the process restarted mid-broadcast.
+func (r *Repository) GetStaleDispatchJobs(ctx context.Context, staleBefore string) ([]NotificationDispatchJob, error) {
+	collection, err := r.GetNotificationDispatchJobsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"status": NotificationDispatchJobStatusRunning,
+		"$or": bson.A{
+			bson.M{"heartbeat_at": bson.M{"$exists": false}},
+			bson.M{"heartbeat_at": bson.M{"$lt": staleBefore}},
+		},
+	}
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, filter, options.Find().SetSort(bson.D{{Key: "metadata.created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []NotificationDispatchJob
+	if err := r.Store.MapAllInCursorToResult(ctx, cursor, &jobs, "notification_dispatch_jobs"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimDispatchJob marks a dispatch job as running for claimedBy, as long
+// as it failed or its last heartbeat is before staleBefore. The claim is
+// atomic, so only one run delivers the job at a time. Jobs that cannot be
+// claimed return ErrDispatchJobAlreadyClaimed.
+func (r *Repository) ClaimDispatchJob(ctx context.Context, jobID, claimedBy, staleBefore, claimedAt string) (*NotificationDispatchJob, error) {
+	collection, err := r.GetNotificationDispatchJobsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"_id": jobID,
+		"$or": bson.A{
+			bson.M{"status": NotificationDispatchJobStatusFailed},
+			bson.M{
+				"status": NotificationDispatchJobStatusRunning,
+				"$or": bson.A{
+					bson.M{"heartbeat_at": bson.M{"$exists": false}},
+					bson.M{"heartbeat_at": bson.M{"$lt": staleBefore}},
+				},
+			},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       NotificationDispatchJobStatusRunning,
+			"claimed_by":   claimedBy,
+			"heartbeat_at": claimedAt,
+		},
+	}
+
+	var job NotificationDispatchJob
+	err = collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDispatchJobAlreadyClaimed
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return &job, nil
+}
+
+// HeartbeatDispatchJob records that claimedBy is still delivering a
+// dispatch job. It returns ErrDispatchJobAlreadyClaimed once the job has
+// been claimed by another run or is no longer running.
+func (r *Repository) HeartbeatDispatchJob(ctx context.Context, jobID, claimedBy, heartbeatAt string) error {
+	collection, err := r.GetNotificationDispatchJobsCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":        jobID,
+		"claimed_by": claimedBy,
+		"status":     NotificationDispatchJobStatusRunning,
+	}
+	update := bson.M{"$set": bson.M{"heartbeat_at": heartbeatAt}}
+
+	if err := collection.FindOneAndUpdate(ctx, filter, update).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrDispatchJobAlreadyClaimed
+		}
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
+
+// CheckpointDispatchJob persists a dispatch job's status, cursor,
+// progress, and last error.
+//
+// The service calls this after every fully delivered batch, so the stored
+// cursor always points at the last recipient that is known to be done.
+// Only the run that claimed the job can checkpoint it, so a run that lost
+// its claim cannot move the cursor of the run that took over.
+func (r *Repository) CheckpointDispatchJob(ctx context.Context, job *NotificationDispatchJob) error {
+	collection, err := r.GetNotificationDispatchJobsCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"status":     job.Status,
+		"cursor":     job.Cursor,
+		"progress":   job.Progress,
+		"last_error": job.LastError,
+	}
+	if job.HeartbeatAt != "" {
+		set["heartbeat_at"] = job.HeartbeatAt
+	}
+	if job.Metadata != nil {
+		set["metadata.updated_at"] = job.Metadata.UpdatedAt
+		if job.Metadata.CompletedAt != "" {
+			set["metadata.completed_at"] = job.Metadata.CompletedAt
+		}
+	}
+
+	filter := bson.M{"_id": job.ID}
+	if job.ClaimedBy != "" {
+		filter["claimed_by"] = job.ClaimedBy
+	}
+
+	if err := r.Store.ExecuteUpdateOneCommand(ctx, collection, filter, bson.M{"$set": set}, "notification_dispatch_job"); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return nil
+}
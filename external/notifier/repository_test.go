@@ -25,6 +25,8 @@ type mockNotifierMongoDbStore struct {
 	deleteManyFunc       func(ctx context.Context, collection *mongo.Collection, filter interface{}, targetObjectName string) error
 	updateOneFunc        func(ctx context.Context, collection *mongo.Collection, filter interface{}, update interface{}, targetObjectName string) error
 	mapAllFunc           func(ctx context.Context, cursor *mongo.Cursor, result interface{}, resultObjectName string) error
+	insertOneFunc        func(ctx context.Context, collection *mongo.Collection, document interface{}, resultObjectName string) (*mongo.InsertOneResult, error)
+	aggregateFunc        func(ctx context.Context, collection *mongo.Collection, mongoPipeline []bson.D) (*mongo.Cursor, error)
 }
 
 func materializeFindOptions(t *testing.T, lister options.Lister[options.FindOptions]) *options.FindOptions {
@@ -92,6 +94,20 @@ func (m *mockNotifierMongoDbStore) ExecuteUpdateOneCommand(ctx context.Context,
 	return errors.New("not implemented")
 }
 
+func (m *mockNotifierMongoDbStore) ExecuteInsertOneCommand(ctx context.Context, collection *mongo.Collection, document interface{}, resultObjectName string) (*mongo.InsertOneResult, error) {
+	if m.insertOneFunc != nil {
+		return m.insertOneFunc(ctx, collection, document, resultObjectName)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotifierMongoDbStore) ExecuteAggregateCommand(ctx context.Context, collection *mongo.Collection, mongoPipeline []bson.D) (*mongo.Cursor, error) {
+	if m.aggregateFunc != nil {
+		return m.aggregateFunc(ctx, collection, mongoPipeline)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockNotifierMongoDbStore) MapAllInCursorToResult(ctx context.Context, cursor *mongo.Cursor, result interface{}, resultObjectName string) error {
 	if m.mapAllFunc != nil {
 		return m.mapAllFunc(ctx, cursor, result, resultObjectName)
@@ -23,6 +23,7 @@ type RegisterAddressRequest struct {
 	Platform   string              `json:"platform,omitempty"`
 	WebPush    *WebPushAddress     `json:"webpush,omitempty"`
 	FCM        *FCMAddress         `json:"fcm,omitempty"`
+	Slack      *SlackAddress       `json:"slack,omitempty"`
 }
 
 // GetActiveNotificationAddressesRequest filters a lookup of a user's
@@ -95,20 +96,70 @@ type GetNotifierConfigRequest struct{}
 // NotifyUsersRequest is sent by an admin to create a notification dispatch
 // for zero or more users across zero or more channels.
 //
-// When UserIDs is empty, the service resolves every user that has at
-// least one active notification address and delivers to all of them.
+// Recipients are chosen by at most one of:
+//
+//   - UserIDs – an explicit list of users.
+//   - Topic – every user subscribed to the topic (e.g. "release-notes" or
+//     "group:{id}").
+//   - Segment – every user matching the segment filters, resolved through
+//     the service's SegmentResolver.
+//
+// When none are set, the service streams every user that has at least one
+// active notification address and delivers to all of them.
 //
 // When Channels is empty, the service delivers to every supported
 // channel (WEBPUSH and FCM) that the user has active addresses for.
 //
 // Title and Message are required. Data carries optional key-value pairs
-// forwarded to the push payload for client-side handling.
+// forwarded to the push payload for client-side handling. RequestedBy is
+// filled in by the caller (not the client body) and stored on the dispatch
+// job for auditing.
 type NotifyUsersRequest struct {
-	UserIDs  []string               `json:"user_ids,omitempty"`
-	Title    string                 `json:"title" validate:"required"`
-	Message  string                 `json:"message" validate:"required"`
-	Channels []NotificationChannel  `json:"channels,omitempty"`
-	Data     map[string]interface{} `json:"data,omitempty"`
+	RequestedBy string                 `json:"-"`
+	UserIDs     []string               `json:"user_ids,omitempty"`
+	Topic       string                 `json:"topic,omitempty"`
+	Segment     *NotificationSegment   `json:"segment,omitempty"`
+	Title       string                 `json:"title" validate:"required"`
+	Message     string                 `json:"message" validate:"required"`
+	Channels    []NotificationChannel  `json:"channels,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+}
+
+// ResumeDispatchJobRequest asks the service to continue a dispatch job
+// from its last checkpoint.
+type ResumeDispatchJobRequest struct {
+	JobID string `json:"-" validate:"required"`
+}
+
+// ResumeStaleDispatchJobsRequest asks the service to resume every dispatch
+// job whose run stopped.
+type ResumeStaleDispatchJobsRequest struct{}
+
+// GetDispatchJobRequest asks for the current state of a dispatch job.
+type GetDispatchJobRequest struct {
+	JobID string `json:"-" validate:"required"`
+}
+
+// SubscribeToTopicRequest subscribes a user to a broadcast topic.
+//
+// The UserID comes from the authenticated context, never from the client
+// body. Subscribing to a topic the user already follows is a no-op.
+type SubscribeToTopicRequest struct {
+	UserID string `json:"-" validate:"required"`
+	Topic  string `json:"topic" validate:"required"`
+}
+
+// UnsubscribeFromTopicRequest removes a user's subscription to a topic.
+//
+// Unsubscribing from a topic the user does not follow is a no-op.
+type UnsubscribeFromTopicRequest struct {
+	UserID string `json:"-" validate:"required"`
+	Topic  string `json:"-" validate:"required"`
+}
+
+// ListTopicSubscriptionsRequest asks for every topic a user follows.
+type ListTopicSubscriptionsRequest struct {
+	UserID string `json:"-" validate:"required"`
 }
 
 // NotifyUserRequest is sent by an admin or an internal service to send
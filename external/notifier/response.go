@@ -108,6 +108,35 @@ type NotifyUsersResult struct {
 //
 // A single NotifyUsersRequest targeting many users produces a list of
 // per-user results, each containing per-channel delivery outcomes.
+//
+// Results holds at most the first 1000 users, ResultsTruncated is set when
+// more were notified. Job is the dispatch job record after the run finished
+// (or stopped), so callers can inspect progress of every recipient or resume
+// it later by ID.
 type NotifyUsersResponse struct {
-	Results []NotifyUsersResult `json:"results"`
+	Results          []NotifyUsersResult      `json:"results"`
+	ResultsTruncated bool                     `json:"results_truncated,omitempty"`
+	Job              *NotificationDispatchJob `json:"job,omitempty"`
+}
+
+// ResumeStaleDispatchJobsResponse lists the dispatch jobs that were claimed
+// and are being resumed in the background.
+type ResumeStaleDispatchJobsResponse struct {
+	JobIDs []string `json:"job_ids"`
+}
+
+// GetDispatchJobResponse returns the current state of a dispatch job.
+type GetDispatchJobResponse struct {
+	Job *NotificationDispatchJob `json:"job"`
+}
+
+// SubscribeToTopicResponse returns the subscription that now links the
+// user to the topic.
+type SubscribeToTopicResponse struct {
+	Subscription *NotificationTopicSubscription `json:"subscription"`
+}
+
+// ListTopicSubscriptionsResponse returns every topic a user follows.
+type ListTopicSubscriptionsResponse struct {
+	Subscriptions []NotificationTopicSubscription `json:"subscriptions"`
 }
@@ -1,10 +1,13 @@
 package notifier
 
+import "github.com/ooaklee/ghatd/external/slack"
+
 // StandardSendersRequest holds the configuration for creating standard
-// notification channel senders (Web Push and FCM).
+// notification channel senders (Web Push, FCM and Slack).
 //
 // The factory always produces a Web Push sender. An FCM sender is
-// produced only when FCM is explicitly enabled.
+// produced only when FCM is explicitly enabled, and a Slack sender only
+// when a Slack sender is given.
 type StandardSendersRequest struct {
 	// WebPush configures the Web Push sender. May be nil; a sender is
 	// still created but will be disabled if neither explicitly enabled
@@ -20,6 +23,10 @@ type StandardSendersRequest struct {
 	// temporary file whose lifecycle is managed by the cleanup function
 	// returned from NewStandardSenders.
 	FCMCredentialsBase64 string
+
+	// Slack delivers notifications to users' Slack addresses. May be nil;
+	// no Slack sender is created. It must use slack.ModeBot to be enabled.
+	Slack *slack.Sender
 }
 
 // StandardSendersResult contains the senders created by NewStandardSenders
@@ -39,6 +46,9 @@ type StandardSendersResult struct {
 //   - Enabled is set to true when the config explicitly enables it OR
 //     when both VAPIDPublicKey and VAPIDPrivateKey are non-empty.
 //
+// Slack sender (only when req.Slack is set):
+//   - Wraps the given Slack sender, and is enabled when it uses bot mode.
+//
 // FCM sender (only when explicitly enabled):
 //   - Included only when req.FCM is non-nil and req.FCM.Enabled is true.
 //   - If req.FCMCredentialsBase64 is set, it takes precedence over
@@ -53,6 +63,10 @@ func NewStandardSenders(req *StandardSendersRequest) (*StandardSendersResult, er
 		NewWebPushSender(*webPushConfig),
 	}
 
+	if req != nil && req.Slack != nil {
+		senders = append(senders, NewSlackSender(req.Slack))
+	}
+
 	if req == nil || req.FCM == nil || !req.FCM.Enabled {
 		return &StandardSendersResult{
 			Senders: senders,
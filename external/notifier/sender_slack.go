@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/slack"
+	"go.uber.org/zap"
+)
+
+// SlackSender sends notifications to Slack conversations through the
+// slack package, usually as direct messages from the Slack bot.
+//
+// Only bot mode can post to a user's own conversation, so the sender
+// reports as unavailable when the Slack sender delivers through an
+// incoming webhook. Retries and rate limits are handled by the Slack
+// sender.
+type SlackSender struct {
+	sender *slack.Sender
+}
+
+// NewSlackSender creates a Slack channel sender from a Slack sender.
+func NewSlackSender(sender *slack.Sender) *SlackSender {
+	return &SlackSender{sender: sender}
+}
+
+// Channel always returns NotificationChannelSlack.
+func (s *SlackSender) Channel() NotificationChannel {
+	return NotificationChannelSlack
+}
+
+// Enabled returns true when the Slack sender is set and delivers with
+// a bot token.
+func (s *SlackSender) Enabled() bool {
+	return s != nil && s.sender != nil && s.sender.Mode() == slack.ModeBot
+}
+
+// Send delivers the notification to each Slack address.
+func (s *SlackSender) Send(ctx context.Context, subject, message string, addresses []NotificationAddress, data map[string]interface{}) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/notifier", "send")
+	logger.Debug("handling-send-request")
+
+	_, err := s.SendWithReport(ctx, subject, message, addresses, data)
+	return err
+}
+
+// SendWithReport delivers the notification to each Slack address and
+// reports how many were delivered. Failed addresses are returned as a
+// combined error, they are never disabled because Slack failures do not
+// tell a removed user apart from a misconfigured bot.
+func (s *SlackSender) SendWithReport(ctx context.Context, subject, message string, addresses []NotificationAddress, data map[string]interface{}) (channelSendReport, error) {
+	report := channelSendReport{}
+	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
+		zap.String("operation", "slack-send"),
+		zap.String("channel", string(NotificationChannelSlack)),
+	)
+
+	if !s.Enabled() {
+		logger.Warn(
+			"slack-sender-not-enabled",
+			zap.Bool("has-slack-sender", s != nil && s.sender != nil),
+			zap.Int("attempted-addresses", len(addresses)),
+			zap.Error(ErrNotificationSenderNotEnabled),
+		)
+		return report, ErrNotificationSenderNotEnabled
+	}
+
+	valid := make([]NotificationAddress, 0, len(addresses))
+	for _, address := range addresses {
+		if address.Channel == NotificationChannelSlack && address.Slack != nil && strings.TrimSpace(address.Slack.ChannelID) != "" {
+			valid = append(valid, address)
+		}
+	}
+	if len(valid) == 0 {
+		logger.Warn("slack-send-no-valid-addresses", zap.Int("attempted-addresses", len(addresses)), zap.Error(ErrNotificationNoActiveAddresses))
+		return report, ErrNotificationNoActiveAddresses
+	}
+
+	logger.Info("slack-send-started", zap.Int("attempted-addresses", len(addresses)), zap.Int("valid-addresses", len(valid)), zap.Strings("data-keys", notificationDataKeysForLog(data)))
+
+	var sendErrs []error
+	for _, address := range valid {
+		err := s.sender.PostMessage(ctx, &slack.PostMessageRequest{
+			Channel: address.Slack.ChannelID,
+			Message: slack.NewTextMessage(subject, message),
+		})
+		if err != nil {
+			logger.Error(
+				"slack-address-send-failed",
+				zap.String("address-id", address.ID),
+				zap.String("address-hash", address.AddressHash),
+				zap.Error(err),
+			)
+			sendErrs = append(sendErrs, err)
+			continue
+		}
+		report.Delivered++
+	}
+
+	if len(sendErrs) > 0 {
+		joinedErr := errors.Join(sendErrs...)
+		logger.Error("slack-send-failed", zap.Int("delivered", report.Delivered), zap.Int("failed-addresses", len(sendErrs)), zap.Error(joinedErr))
+		return report, joinedErr
+	}
+
+	logger.Info("slack-send-completed", zap.Int("delivered", report.Delivered))
+	return report, nil
+}
@@ -0,0 +1,130 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ooaklee/ghatd/external/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackSender_SendWithReport(t *testing.T) {
+	t.Parallel()
+
+	slackAddress := func(id, channelID string) NotificationAddress {
+		return NotificationAddress{
+			ID:      id,
+			Channel: NotificationChannelSlack,
+			Slack:   &SlackAddress{ChannelID: channelID},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		mode          slack.DeliveryMode
+		addresses     []NotificationAddress
+		failWith      string
+		wantErr       error
+		wantAnyErr    bool
+		wantDelivered int
+		wantChannels  []string
+	}{
+		{
+			name:          "SUCCESS - posts to each slack address",
+			mode:          slack.ModeBot,
+			addresses:     []NotificationAddress{slackAddress("addr-1", "U001"), slackAddress("addr-2", "U002")},
+			wantDelivered: 2,
+			wantChannels:  []string{"U001", "U002"},
+		},
+		{
+			name: "SUCCESS - skips addresses without a slack channel",
+			mode: slack.ModeBot,
+			addresses: []NotificationAddress{
+				slackAddress("addr-1", "U001"),
+				slackAddress("addr-2", ""),
+				{ID: "addr-3", Channel: NotificationChannelWebPush},
+			},
+			wantDelivered: 1,
+			wantChannels:  []string{"U001"},
+		},
+		{
+			name:      "FAIL - webhook mode cannot reach users",
+			mode:      slack.ModeWebhook,
+			addresses: []NotificationAddress{slackAddress("addr-1", "U001")},
+			wantErr:   ErrNotificationSenderNotEnabled,
+		},
+		{
+			name:      "FAIL - no valid addresses",
+			mode:      slack.ModeBot,
+			addresses: []NotificationAddress{slackAddress("addr-1", " ")},
+			wantErr:   ErrNotificationNoActiveAddresses,
+		},
+		{
+			name:          "FAIL - slack rejects the first address",
+			mode:          slack.ModeBot,
+			addresses:     []NotificationAddress{slackAddress("addr-1", "U001"), slackAddress("addr-2", "U002")},
+			failWith:      "channel_not_found",
+			wantAnyErr:    true,
+			wantDelivered: 1,
+			wantChannels:  []string{"U002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fakeServer := slack.NewFakeServer("xoxb-test")
+			defer fakeServer.Close()
+
+			if tt.failWith != "" {
+				fakeServer.FailNextWithError(tt.failWith)
+			}
+
+			slackSender, err := slack.NewSender(&slack.Config{
+				Mode:       tt.mode,
+				WebhookURL: fakeServer.WebhookURL(),
+				BotToken:   "xoxb-test",
+				APIBaseURL: fakeServer.APIBaseURL(),
+				MaxRetries: -1,
+			})
+			require.NoError(t, err)
+
+			sender := NewSlackSender(slackSender)
+			report, err := sender.SendWithReport(context.Background(), "Build finished", "All <checks> passed", tt.addresses, nil)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.True(t, errors.Is(err, tt.wantErr), "unexpected error: %v", err)
+			case tt.wantAnyErr:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantDelivered, report.Delivered)
+
+			messages := fakeServer.Messages()
+			channels := make([]string, 0, len(messages))
+			for _, message := range messages {
+				channels = append(channels, message.Message.Channel)
+				assert.Equal(t, "*Build finished*\nAll &lt;checks&gt; passed", message.Message.Text)
+			}
+			if len(tt.wantChannels) == 0 {
+				assert.Empty(t, channels)
+				return
+			}
+			assert.Equal(t, tt.wantChannels, channels)
+		})
+	}
+}
+
+func TestSlackSender_EnabledRequiresBotMode(t *testing.T) {
+	t.Parallel()
+
+	var nilSender *SlackSender
+	assert.False(t, nilSender.Enabled())
+	assert.False(t, NewSlackSender(nil).Enabled())
+	assert.Equal(t, NotificationChannelSlack, NewSlackSender(nil).Channel())
+}
@@ -0,0 +1,528 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// WithSegmentResolver sets the resolver used to turn NotifyUsersRequest
+// segments into user IDs. Without one, segment-targeted dispatches fail
+// with ErrSegmentResolverNotConfigured.
+func (s *Service) WithSegmentResolver(resolver SegmentResolver) *Service {
+	s.segmentResolver = resolver
+	return s
+}
+
+// WithDispatchBatchSize sets how many recipients are loaded and delivered
+// per dispatch batch. Values below one are ignored.
+func (s *Service) WithDispatchBatchSize(size int) *Service {
+	if size > 0 {
+		s.dispatchBatchSize = size
+	}
+	return s
+}
+
+// WithDispatchConcurrency sets how many users within a batch are notified
+// at the same time. Values below one are ignored.
+func (s *Service) WithDispatchConcurrency(concurrency int) *Service {
+	if concurrency > 0 {
+		s.dispatchConcurrency = concurrency
+	}
+	return s
+}
+
+// NotifyUsers delivers a push notification to multiple users across
+// multiple channels.
+//
+// This is the notify-many dispatch entry point. The method:
+//
+//  1. Validates that Title and Message are present and that at most one of
+//     UserIDs, Topic, or Segment is set.
+//  2. Normalises the requested channels (empty means all channels).
+//  3. Creates a RUNNING dispatch job that records the target, claimed by
+//     this run.
+//  4. Streams recipients in batches ordered by user ID – from the explicit
+//     list, the topic's subscribers, the segment resolver, or (when no
+//     target is given) every user with an active address.
+//  5. Notifies each batch with bounded concurrency and checkpoints the job
+//     cursor once the batch is done.
+//
+// Per-user preferences are honoured: users with notifications disabled
+// globally are skipped, and per-channel preferences filter out channels
+// the user has turned off.
+//
+// The response contains one result per targeted user, each with
+// per-channel delivery outcomes, plus the final job record. If the
+// process stops part way through, ResumeDispatchJob continues from the
+// last checkpoint instead of resending to everyone.
+func (s *Service) NotifyUsers(ctx context.Context, req *NotifyUsersRequest) (*NotifyUsersResponse, error) {
+	if req == nil || strings.TrimSpace(req.Title) == "" || strings.TrimSpace(req.Message) == "" {
+		return nil, ErrInvalidNotificationAddressBody
+	}
+
+	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
+		zap.String("operation", "notify-users"),
+	)
+
+	logger.Info(
+		"notification-dispatch-started",
+		zap.Int("requested-user-count", len(req.UserIDs)),
+		zap.String("requested-topic", req.Topic),
+		zap.Bool("segment", req.Segment != nil),
+		zap.Strings("requested-channels", notificationChannelsForLog(req.Channels)),
+		zap.Strings("data-keys", notificationDataKeysForLog(req.Data)),
+	)
+
+	channels, err := normaliseChannels(req.Channels)
+	if err != nil {
+		logger.Warn("notification-dispatch-invalid-channels", zap.Strings("requested-channels", notificationChannelsForLog(req.Channels)), zap.Error(err))
+		return nil, err
+	}
+
+	target, err := s.buildDispatchTarget(req)
+	if err != nil {
+		logger.Warn("notification-dispatch-invalid-target", zap.Error(err))
+		return nil, err
+	}
+
+	now := toolbox.TimeNowUTC()
+	job := &NotificationDispatchJob{
+		ID:          toolbox.GenerateUuidV4(),
+		Status:      NotificationDispatchJobStatusRunning,
+		RequestedBy: strings.TrimSpace(req.RequestedBy),
+		Title:       strings.TrimSpace(req.Title),
+		Message:     strings.TrimSpace(req.Message),
+		Channels:    channels,
+		Data:        req.Data,
+		Target:      target,
+		ClaimedBy:   toolbox.GenerateUuidV4(),
+		HeartbeatAt: dispatchJobHeartbeatNow(),
+		Metadata: &NotificationDispatchJobMetadata{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+	if err := s.Repository.CreateDispatchJob(ctx, job); err != nil {
+		logger.Error("notification-dispatch-job-create-failed", zap.Error(err))
+		return nil, err
+	}
+	logger.Info("notification-dispatch-job-created", zap.String("dispatch-job-id", job.ID))
+
+	return s.runDispatchJob(ctx, job)
+}
+
+// ResumeDispatchJob continues a dispatch job that did not complete, for
+// example because the process restarted mid-broadcast or recipient
+// resolution failed.
+//
+// Delivery restarts after the job's stored cursor, so only the batch that
+// was in flight when the job stopped can be delivered twice. Completed
+// jobs are rejected with ErrDispatchJobAlreadyCompleted. The job is claimed
+// first, so a job still being delivered elsewhere is rejected with
+// ErrDispatchJobAlreadyClaimed until it has gone without a heartbeat for
+// five minutes.
+func (s *Service) ResumeDispatchJob(ctx context.Context, req *ResumeDispatchJobRequest) (*NotifyUsersResponse, error) {
+	if req == nil || strings.TrimSpace(req.JobID) == "" {
+		return nil, ErrDispatchJobNotFound
+	}
+
+	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
+		zap.String("operation", "resume-dispatch-job"),
+		zap.String("dispatch-job-id", req.JobID),
+	)
+
+	job, err := s.Repository.GetDispatchJobByID(ctx, strings.TrimSpace(req.JobID))
+	if err != nil {
+		logger.Warn("notification-dispatch-job-lookup-failed", zap.Error(err))
+		return nil, err
+	}
+	if job.Status == NotificationDispatchJobStatusCompleted {
+		logger.Warn("notification-dispatch-job-already-completed")
+		return nil, ErrDispatchJobAlreadyCompleted
+	}
+
+	job, err = s.claimDispatchJob(ctx, job.ID)
+	if err != nil {
+		if errors.Is(err, ErrDispatchJobAlreadyClaimed) {
+			logger.Warn("notification-dispatch-job-already-claimed")
+		} else {
+			logger.Error("notification-dispatch-job-claim-failed", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	logger.Info("notification-dispatch-job-resuming", zap.String("cursor", job.Cursor), zap.Any("progress", job.Progress))
+	job.LastError = ""
+
+	return s.runDispatchJob(ctx, job)
+}
+
+// ResumeStaleDispatchJobs resumes every running dispatch job that has gone
+// without a heartbeat for five minutes, usually because the process
+// delivering it restarted. Hosts call it on startup. Jobs are delivered in
+// the background, and jobs claimed by another run in the meantime are
+// skipped.
+func (s *Service) ResumeStaleDispatchJobs(ctx context.Context, _ *ResumeStaleDispatchJobsRequest) (*ResumeStaleDispatchJobsResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
+		zap.String("operation", "resume-stale-dispatch-jobs"),
+	)
+
+	staleJobs, err := s.Repository.GetStaleDispatchJobs(ctx, dispatchJobStaleBefore())
+	if err != nil {
+		logger.Error("notification-stale-dispatch-jobs-lookup-failed", zap.Error(err))
+		return nil, err
+	}
+
+	response := &ResumeStaleDispatchJobsResponse{JobIDs: []string{}}
+	for _, staleJob := range staleJobs {
+		job, err := s.claimDispatchJob(ctx, staleJob.ID)
+		if err != nil {
+			if !errors.Is(err, ErrDispatchJobAlreadyClaimed) {
+				logger.Error("notification-dispatch-job-claim-failed", zap.String("dispatch-job-id", staleJob.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		go s.runDispatchJob(context.WithoutCancel(ctx), job)
+		response.JobIDs = append(response.JobIDs, job.ID)
+	}
+
+	logger.Info("notification-stale-dispatch-jobs-resumed", zap.Int("resumed-count", len(response.JobIDs)))
+
+	return response, nil
+}
+
+// claimDispatchJob claims a dispatch job for a new run, so no other run
+// delivers it while its heartbeat is kept up.
+func (s *Service) claimDispatchJob(ctx context.Context, jobID string) (*NotificationDispatchJob, error) {
+	return s.Repository.ClaimDispatchJob(ctx, jobID, toolbox.GenerateUuidV4(), dispatchJobStaleBefore(), dispatchJobHeartbeatNow())
+}
+
+// dispatchJobHeartbeatNow returns the current time as recorded in dispatch
+// job heartbeats.
+func dispatchJobHeartbeatNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// dispatchJobStaleBefore returns the heartbeat time before which running
+// dispatch jobs are treated as abandoned.
+func dispatchJobStaleBefore() string {
+	return time.Now().UTC().Add(-dispatchJobStaleAfter).Format(time.RFC3339)
+}
+
+// dispatchJobClaimLost returns ErrDispatchJobAlreadyClaimed once the
+// job's claim has been lost to another run.
+func dispatchJobClaimLost(ctx context.Context) error {
+	if errors.Is(context.Cause(ctx), ErrDispatchJobAlreadyClaimed) {
+		return ErrDispatchJobAlreadyClaimed
+	}
+	return nil
+}
+
+// keepDispatchJobClaim records a heartbeat for the job on every interval
+// until the context is done. Losing the claim to another run cancels the
+// context with ErrDispatchJobAlreadyClaimed, which stops delivery.
+func (s *Service) keepDispatchJobClaim(ctx context.Context, cancel context.CancelCauseFunc, jobID, claimedBy string, logger *zap.Logger) {
+	ticker := time.NewTicker(dispatchJobHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.Repository.HeartbeatDispatchJob(ctx, jobID, claimedBy, dispatchJobHeartbeatNow())
+		if errors.Is(err, ErrDispatchJobAlreadyClaimed) {
+			logger.Warn("notification-dispatch-job-claim-lost")
+			cancel(ErrDispatchJobAlreadyClaimed)
+			return
+		}
+		if err != nil {
+			logger.Error("notification-dispatch-job-heartbeat-failed", zap.Error(err))
+		}
+	}
+}
+
+// GetDispatchJob returns the stored state of a dispatch job.
+func (s *Service) GetDispatchJob(ctx context.Context, req *GetDispatchJobRequest) (*GetDispatchJobResponse, error) {
+	if req == nil || strings.TrimSpace(req.JobID) == "" {
+		return nil, ErrDispatchJobNotFound
+	}
+
+	job, err := s.Repository.GetDispatchJobByID(ctx, strings.TrimSpace(req.JobID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetDispatchJobResponse{Job: job}, nil
+}
+
+// buildDispatchTarget validates the request's recipient selection and
+// returns it in the form stored on the dispatch job.
+func (s *Service) buildDispatchTarget(req *NotifyUsersRequest) (NotificationDispatchTarget, error) {
+	target := NotificationDispatchTarget{}
+
+	targetKinds := 0
+	if len(req.UserIDs) > 0 {
+		targetKinds++
+	}
+	if strings.TrimSpace(req.Topic) != "" {
+		targetKinds++
+	}
+	if req.Segment != nil {
+		targetKinds++
+	}
+	if targetKinds > 1 {
+		return target, ErrInvalidNotificationTarget
+	}
+
+	switch {
+	case len(req.UserIDs) > 0:
+		seen := map[string]bool{}
+		for _, userID := range req.UserIDs {
+			userID = strings.TrimSpace(userID)
+			if userID == "" || seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			target.UserIDs = append(target.UserIDs, userID)
+		}
+		if len(target.UserIDs) == 0 {
+			return target, ErrInvalidNotificationTarget
+		}
+		sort.Strings(target.UserIDs)
+
+	case strings.TrimSpace(req.Topic) != "":
+		topic, err := NormaliseTopic(req.Topic)
+		if err != nil {
+			return target, err
+		}
+		target.Topic = topic
+
+	case req.Segment != nil:
+		if req.Segment.IsEmpty() {
+			return target, ErrInvalidNotificationTarget
+		}
+		if s.segmentResolver == nil {
+			return target, ErrSegmentResolverNotConfigured
+		}
+		target.Segment = req.Segment
+	}
+
+	return target, nil
+}
+
+// runDispatchJob streams the job's recipients batch by batch, notifying
+// each batch and checkpointing the job after it, until no recipients
+// remain or recipient resolution fails. The job's claim is kept up with a
+// heartbeat while it runs, and delivery stops with
+// ErrDispatchJobAlreadyClaimed if another run takes it over.
+func (s *Service) runDispatchJob(ctx context.Context, job *NotificationDispatchJob) (*NotifyUsersResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
+		zap.String("operation", "run-dispatch-job"),
+		zap.String("dispatch-job-id", job.ID),
+	)
+
+	if job.Metadata == nil {
+		job.Metadata = &NotificationDispatchJobMetadata{}
+	}
+
+	response := &NotifyUsersResponse{Results: []NotifyUsersResult{}, Job: job}
+	var sendErrs []error
+
+	deliveryCtx, cancelDelivery := context.WithCancelCause(ctx)
+	defer cancelDelivery(nil)
+	go s.keepDispatchJobClaim(deliveryCtx, cancelDelivery, job.ID, job.ClaimedBy, logger)
+
+	for {
+		userIDs, nextCursor, err := s.nextDispatchBatch(deliveryCtx, job)
+		if claimErr := dispatchJobClaimLost(deliveryCtx); claimErr != nil {
+			return response, claimErr
+		}
+		if err != nil {
+			logger.Error("notification-dispatch-target-resolution-failed", zap.String("cursor", job.Cursor), zap.Error(err))
+			job.Status = NotificationDispatchJobStatusFailed
+			job.LastError = err.Error()
+			job.Metadata.UpdatedAt = toolbox.TimeNowUTC()
+			if checkpointErr := s.Repository.CheckpointDispatchJob(ctx, job); checkpointErr != nil {
+				logger.Error("notification-dispatch-job-checkpoint-failed", zap.Error(checkpointErr))
+			}
+			return response, err
+		}
+
+		results, batchErrs := s.notifyDispatchBatch(deliveryCtx, job, userIDs)
+		if claimErr := dispatchJobClaimLost(deliveryCtx); claimErr != nil {
+			return response, claimErr
+		}
+		if keep := maxDispatchResponseResults - len(response.Results); keep < len(results) {
+			results = results[:max(keep, 0)]
+			response.ResultsTruncated = true
+		}
+		response.Results = append(response.Results, results...)
+		if keep := maxDispatchResponseResults - len(sendErrs); keep < len(batchErrs) {
+			batchErrs = batchErrs[:max(keep, 0)]
+		}
+		sendErrs = append(sendErrs, batchErrs...)
+
+		job.Cursor = nextCursor
+		if len(userIDs) > 0 {
+			job.Progress.Batches++
+		}
+		now := toolbox.TimeNowUTC()
+		job.Metadata.UpdatedAt = now
+		job.HeartbeatAt = dispatchJobHeartbeatNow()
+		if nextCursor == "" {
+			job.Status = NotificationDispatchJobStatusCompleted
+			job.Metadata.CompletedAt = now
+		}
+
+		if err := s.Repository.CheckpointDispatchJob(ctx, job); err != nil {
+			logger.Error("notification-dispatch-job-checkpoint-failed", zap.String("cursor", job.Cursor), zap.Error(err))
+			return response, err
+		}
+		logger.Info("notification-dispatch-batch-completed", zap.Int("batch-user-count", len(userIDs)), zap.Any("progress", job.Progress))
+
+		if job.Status == NotificationDispatchJobStatusCompleted {
+			break
+		}
+	}
+
+	if job.Progress.TargetedUsers == 0 {
+		logger.Warn("notification-dispatch-no-target-users", zap.Strings("channels", notificationChannelsForLog(job.Channels)))
+	}
+
+	if len(sendErrs) > 0 {
+		joinedErr := errors.Join(sendErrs...)
+		logger.Error(
+			"notification-dispatch-failed",
+			zap.Any("progress", job.Progress),
+			zap.Any("results", safeLogValue(response.Results)),
+			zap.Error(joinedErr),
+		)
+		return response, joinedErr
+	}
+
+	logger.Info("notification-dispatch-completed", zap.Any("progress", job.Progress), zap.Any("results", safeLogValue(response.Results)))
+	return response, nil
+}
+
+// nextDispatchBatch returns the next page of recipients after the job's
+// cursor and the cursor to store once that page has been delivered. An
+// empty next cursor means the page is the last one.
+func (s *Service) nextDispatchBatch(ctx context.Context, job *NotificationDispatchJob) ([]string, string, error) {
+	limit := s.dispatchBatchSize
+
+	if job.Target.Segment != nil {
+		if s.segmentResolver == nil {
+			return nil, "", ErrSegmentResolverNotConfigured
+		}
+		return s.segmentResolver.ResolveSegmentUserIDs(ctx, job.Target.Segment, job.Cursor, limit)
+	}
+
+	var (
+		userIDs []string
+		err     error
+	)
+	switch {
+	case len(job.Target.UserIDs) > 0:
+		start := sort.SearchStrings(job.Target.UserIDs, job.Cursor)
+		if start < len(job.Target.UserIDs) && job.Target.UserIDs[start] == job.Cursor {
+			start++
+		}
+		end := min(start+limit, len(job.Target.UserIDs))
+		userIDs = job.Target.UserIDs[start:end]
+	case job.Target.Topic != "":
+		userIDs, err = s.Repository.GetTopicSubscriberUserIDs(ctx, job.Target.Topic, job.Cursor, limit)
+	default:
+		userIDs, err = s.Repository.GetActiveAddressUserIDs(ctx, job.Cursor, limit, job.Channels...)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(userIDs) < limit {
+		return userIDs, "", nil
+	}
+	return userIDs, userIDs[len(userIDs)-1], nil
+}
+
+// notifyDispatchBatch notifies every user in the batch, running at most
+// dispatchConcurrency NotifyUser calls at once. Results keep the batch
+// order and the job's progress counters are updated in place.
+func (s *Service) notifyDispatchBatch(ctx context.Context, job *NotificationDispatchJob, userIDs []string) ([]NotifyUsersResult, []error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
+		zap.String("operation", "notify-dispatch-batch"),
+		zap.String("dispatch-job-id", job.ID),
+	)
+
+	results := make([]NotifyUsersResult, len(userIDs))
+	userErrs := make([]error, len(userIDs))
+
+	semaphore := make(chan struct{}, s.dispatchConcurrency)
+	var wg sync.WaitGroup
+	for i, userID := range userIDs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			notifyResponse, err := s.NotifyUser(ctx, &NotifyUserRequest{
+				UserID:   userID,
+				Title:    job.Title,
+				Message:  job.Message,
+				Channels: job.Channels,
+				Data:     job.Data,
+			})
+
+			results[i] = NotifyUsersResult{UserID: userID}
+			if notifyResponse != nil {
+				results[i].Results = notifyResponse.Results
+			}
+			userErrs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	var sendErrs []error
+	for i, result := range results {
+		job.Progress.TargetedUsers++
+		err := userErrs[i]
+		switch {
+		case errors.Is(err, ErrNotificationNoActiveAddresses):
+			logger.Warn("notification-dispatch-user-no-active-addresses", zap.String("user-id", result.UserID), zap.Strings("channels", notificationChannelsForLog(job.Channels)))
+			job.Progress.SkippedUsers++
+		case err != nil:
+			logger.Error("notification-dispatch-user-send-failed", zap.String("user-id", result.UserID), zap.Any("results", safeLogValue(result.Results)), zap.Error(err))
+			job.Progress.FailedUsers++
+			sendErrs = append(sendErrs, err)
+		case notifyUsersResultSent(result):
+			job.Progress.NotifiedUsers++
+		default:
+			job.Progress.SkippedUsers++
+		}
+	}
+
+	return results, sendErrs
+}
+
+// notifyUsersResultSent reports whether at least one channel delivered
+// the notification to the user.
+func notifyUsersResultSent(result NotifyUsersResult) bool {
+	for _, channelResult := range result.Results {
+		if channelResult.Sent {
+			return true
+		}
+	}
+	return false
+}
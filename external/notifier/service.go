@@ -42,11 +42,41 @@ type NotificationRepository interface {
 
 	// GetAllActiveAddresses returns every active notification address
 	// across all users, optionally filtered to specific channels.
+	GetAllActiveAddresses(ctx context.Context, channels ...NotificationChannel) ([]NotificationAddress, error)
+
+	// GetActiveAddressUserIDs returns one page of distinct user IDs that
+	// own an active address, ordered by user ID and strictly after
+	// afterUserID.
 	//
 	// This is used by the notify-many dispatch path when no explicit
-	// user IDs are provided so the notifier can deliver to everyone who
-	// has registered a device.
-	GetAllActiveAddresses(ctx context.Context, channels ...NotificationChannel) ([]NotificationAddress, error)
+	// target is provided so the notifier can stream everyone who has
+	// registered a device without loading every address into memory.
+	GetActiveAddressUserIDs(ctx context.Context, afterUserID string, limit int, channels ...NotificationChannel) ([]string, error)
+
+	UpsertTopicSubscription(ctx context.Context, subscription *NotificationTopicSubscription) (*NotificationTopicSubscription, error)
+	DeleteTopicSubscription(ctx context.Context, userID, topic string) error
+	DeleteTopicSubscriptionsByUserID(ctx context.Context, userID string) error
+	GetTopicSubscriptionsByUserID(ctx context.Context, userID string) ([]NotificationTopicSubscription, error)
+	GetTopicSubscriberUserIDs(ctx context.Context, topic, afterUserID string, limit int) ([]string, error)
+
+	CreateDispatchJob(ctx context.Context, job *NotificationDispatchJob) error
+	GetDispatchJobByID(ctx context.Context, jobID string) (*NotificationDispatchJob, error)
+	GetStaleDispatchJobs(ctx context.Context, staleBefore string) ([]NotificationDispatchJob, error)
+	ClaimDispatchJob(ctx context.Context, jobID, claimedBy, staleBefore, claimedAt string) (*NotificationDispatchJob, error)
+	HeartbeatDispatchJob(ctx context.Context, jobID, claimedBy, heartbeatAt string) error
+	CheckpointDispatchJob(ctx context.Context, job *NotificationDispatchJob) error
+}
+
+// SegmentResolver turns a NotificationSegment into pages of user IDs.
+//
+// The notifier does not own user, role, or group data, so segment
+// targeting is delegated to the host application (UMS provides one backed
+// by userv2 and group). Implementations return user IDs in a stable order
+// along with the cursor for the next page; an empty next cursor means
+// there are no more users. The cursor is opaque to the notifier and is
+// stored on the dispatch job so an interrupted dispatch can resume.
+type SegmentResolver interface {
+	ResolveSegmentUserIDs(ctx context.Context, segment *NotificationSegment, cursor string, limit int) (userIDs []string, nextCursor string, err error)
 }
 
 // Service is the core of the notifier package. It handles:
@@ -84,6 +114,10 @@ type NotificationRepository interface {
 type Service struct {
 	Repository NotificationRepository
 	senders    map[NotificationChannel]ChannelSender
+
+	segmentResolver     SegmentResolver
+	dispatchBatchSize   int
+	dispatchConcurrency int
 }
 
 // NewServiceRequest carries the dependencies needed to create a Service.
@@ -109,8 +143,10 @@ type NewServiceRequest struct {
 //	})
 func NewService(r *NewServiceRequest) *Service {
 	service := &Service{
-		Repository: r.Repository,
-		senders:    map[NotificationChannel]ChannelSender{},
+		Repository:          r.Repository,
+		senders:             map[NotificationChannel]ChannelSender{},
+		dispatchBatchSize:   defaultDispatchBatchSize,
+		dispatchConcurrency: defaultDispatchConcurrency,
 	}
 	for _, sender := range r.Senders {
 		service.WithSender(sender)
@@ -149,6 +185,7 @@ func (s *Service) WithSender(sender ChannelSender) *Service {
 //
 //   - WEBPUSH: the browser Push API subscription (endpoint + keys).
 //   - FCM: the mobile device's Firebase token.
+//   - SLACK: the Slack member or channel ID to post to.
 //
 // The UserID must come from the authenticated context – the service does
 // not trust a user ID from the client body. This is enforced by UMS,
@@ -185,6 +222,7 @@ func (s *Service) RegisterAddress(ctx context.Context, req *RegisterAddressReque
 		Platform:    strings.ToUpper(strings.TrimSpace(req.Platform)),
 		WebPush:     normaliseWebPushAddress(req.WebPush),
 		FCM:         normaliseFCMAddress(req.FCM),
+		Slack:       normaliseSlackAddress(req.Slack),
 		Metadata: &NotificationAddressMetadata{
 			CreatedAt:  now,
 			UpdatedAt:  now,
@@ -436,6 +474,7 @@ func (s *Service) GetConfig(ctx context.Context, req *GetNotifierConfigRequest)
 		SupportedChannels: []NotificationChannel{},
 		WebPush:           WebPushClientConfig{},
 		FCM:               FCMClientConfig{},
+		Slack:             SlackClientConfig{},
 	}
 
 	for channel, sender := range s.senders {
@@ -449,6 +488,8 @@ func (s *Service) GetConfig(ctx context.Context, req *GetNotifierConfigRequest)
 			config.WebPush.VAPIDPublicKey = typedSender.PublicKey()
 		case *FCMSender:
 			config.FCM.Enabled = true
+		case *SlackSender:
+			config.Slack.Enabled = true
 		}
 	}
 	sort.Slice(config.SupportedChannels, func(i, j int) bool {
@@ -624,148 +665,13 @@ func (s *Service) NotifyUser(ctx context.Context, req *NotifyUserRequest) (*Noti
 	return response, nil
 }
 
-// NotifyUsers delivers a push notification to multiple users across
-// multiple channels.
-//
-// This is the notify-many dispatch entry point. The method:
-//
-//  1. Validates that Title and Message are present.
-//  2. Normalises the requested channels (empty means all channels).
-//  3. If UserIDs is populated, fetches active addresses and preferences
-//     for each user individually and dispatches per-user.
-//  4. If UserIDs is empty, fetches all active addresses across the
-//     platform (optionally filtered by channel), groups them by user,
-//     and dispatches per-user.
-//
-// Per-user preferences are honoured: users with notifications disabled
-// globally are skipped, and per-channel preferences filter out channels
-// the user has turned off.
-//
-// The response contains one result per targeted user, each with
-// per-channel delivery outcomes. A user with no active addresses (or
-// with preferences blocking all delivery) produces an empty per-channel
-// result set.
-func (s *Service) NotifyUsers(ctx context.Context, req *NotifyUsersRequest) (*NotifyUsersResponse, error) {
-	if req == nil || strings.TrimSpace(req.Title) == "" || strings.TrimSpace(req.Message) == "" {
-		return nil, ErrInvalidNotificationAddressBody
-	}
-
-	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
-		zap.String("operation", "notify-users"),
-	)
-
-	logger.Info(
-		"notification-dispatch-started",
-		zap.Int("requested-user-count", len(req.UserIDs)),
-		zap.Bool("broadcast", len(req.UserIDs) == 0),
-		zap.Strings("requested-channels", notificationChannelsForLog(req.Channels)),
-		zap.Strings("data-keys", notificationDataKeysForLog(req.Data)),
-	)
-
-	channels, err := normaliseChannels(req.Channels)
-	if err != nil {
-		logger.Warn("notification-dispatch-invalid-channels", zap.Strings("requested-channels", notificationChannelsForLog(req.Channels)), zap.Error(err))
-		return nil, err
-	}
-
-	userIDs, err := s.resolveNotifyUsersTargetUserIDs(ctx, req.UserIDs, channels)
-	if err != nil {
-		logger.Error("notification-dispatch-target-resolution-failed", zap.Strings("channels", notificationChannelsForLog(channels)), zap.Error(err))
-		return nil, err
-	}
-	if len(userIDs) == 0 {
-		logger.Warn("notification-dispatch-no-target-users", zap.Bool("broadcast", len(req.UserIDs) == 0), zap.Strings("channels", notificationChannelsForLog(channels)))
-	}
-	logger.Info("notification-dispatch-targets-resolved", zap.Int("target-user-count", len(userIDs)), zap.Bool("broadcast", len(req.UserIDs) == 0))
-
-	response := &NotifyUsersResponse{Results: make([]NotifyUsersResult, 0, len(userIDs))}
-	var sendErrs []error
-	for _, userID := range userIDs {
-		notifyResponse, err := s.NotifyUser(ctx, &NotifyUserRequest{
-			UserID:   userID,
-			Title:    strings.TrimSpace(req.Title),
-			Message:  strings.TrimSpace(req.Message),
-			Channels: channels,
-			Data:     req.Data,
-		})
-
-		result := NotifyUsersResult{UserID: userID}
-		if notifyResponse != nil {
-			result.Results = notifyResponse.Results
-		}
-		response.Results = append(response.Results, result)
-
-		if err == nil || errors.Is(err, ErrNotificationNoActiveAddresses) {
-			if errors.Is(err, ErrNotificationNoActiveAddresses) {
-				logger.Warn("notification-dispatch-user-no-active-addresses", zap.String("user-id", userID), zap.Strings("channels", notificationChannelsForLog(channels)))
-			}
-			continue
-		}
-		logger.Error("notification-dispatch-user-send-failed", zap.String("user-id", userID), zap.Any("results", safeLogValue(result.Results)), zap.Error(err))
-		sendErrs = append(sendErrs, err)
-	}
-
-	if len(sendErrs) > 0 {
-		joinedErr := errors.Join(sendErrs...)
-		logger.Error(
-			"notification-dispatch-failed",
-			zap.Int("target-user-count", len(userIDs)),
-			zap.Int("failed-users", len(sendErrs)),
-			zap.Any("results", safeLogValue(response.Results)),
-			zap.Error(joinedErr),
-		)
-		return response, joinedErr
-	}
-
-	logger.Info("notification-dispatch-completed", zap.Int("target-user-count", len(userIDs)), zap.Any("results", safeLogValue(response.Results)))
-	return response, nil
-}
-
-func (s *Service) resolveNotifyUsersTargetUserIDs(ctx context.Context, userIDs []string, channels []NotificationChannel) ([]string, error) {
-	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
-		zap.String("operation", "resolve-notify-users-targets"),
-	)
-
-	seen := map[string]bool{}
-	resolved := []string{}
-	if len(userIDs) > 0 {
-		for _, userID := range userIDs {
-			userID = strings.TrimSpace(userID)
-			if userID == "" || seen[userID] {
-				continue
-			}
-			seen[userID] = true
-			resolved = append(resolved, userID)
-		}
-		logger.Info("notification-explicit-target-users-resolved", zap.Int("requested-user-count", len(userIDs)), zap.Int("target-user-count", len(resolved)))
-		return resolved, nil
-	}
-
-	addresses, err := s.Repository.GetAllActiveAddresses(ctx, channels...)
-	if err != nil {
-		logger.Error("notification-broadcast-active-address-lookup-failed", zap.Strings("channels", notificationChannelsForLog(channels)), zap.Error(err))
-		return nil, err
-	}
-	logger.Info("notification-broadcast-active-addresses-found", zap.Int("address-count", len(addresses)), zap.Strings("channels", notificationChannelsForLog(channels)))
-	for _, address := range addresses {
-		userID := strings.TrimSpace(address.UserID)
-		if userID == "" || seen[userID] {
-			continue
-		}
-		seen[userID] = true
-		resolved = append(resolved, userID)
-	}
-	sort.Strings(resolved)
-	logger.Info("notification-broadcast-target-users-resolved", zap.Int("target-user-count", len(resolved)))
-	return resolved, nil
-}
-
 // validateAddressIdentity checks that the request contains the right
 // channel-specific payload and returns the identity string used for
 // address hash calculation.
 //
 //   - For WEBPUSH: the identity is the subscription endpoint URL.
 //   - For FCM: the identity is the device token.
+//   - For SLACK: the identity is the channel ID.
 func validateAddressIdentity(channel NotificationChannel, req *RegisterAddressRequest) (string, error) {
 	switch channel {
 	case NotificationChannelWebPush:
@@ -781,6 +687,11 @@ func validateAddressIdentity(channel NotificationChannel, req *RegisterAddressRe
 			return "", ErrInvalidNotificationAddressBody
 		}
 		return strings.TrimSpace(req.FCM.Token), nil
+	case NotificationChannelSlack:
+		if req.Slack == nil || strings.TrimSpace(req.Slack.ChannelID) == "" {
+			return "", ErrInvalidNotificationAddressBody
+		}
+		return strings.TrimSpace(req.Slack.ChannelID), nil
 	default:
 		return "", ErrInvalidNotificationChannel
 	}
@@ -809,6 +720,14 @@ func normaliseFCMAddress(address *FCMAddress) *FCMAddress {
 	return &FCMAddress{Token: strings.TrimSpace(address.Token)}
 }
 
+// normaliseSlackAddress trims whitespace from the Slack channel ID.
+func normaliseSlackAddress(address *SlackAddress) *SlackAddress {
+	if address == nil {
+		return nil
+	}
+	return &SlackAddress{ChannelID: strings.TrimSpace(address.ChannelID)}
+}
+
 // normaliseChannels validates and deduplicates a list of channel names.
 //
 // If the list is empty, nil is returned (meaning "use all available
@@ -854,6 +773,9 @@ func ensurePreferenceDefaults(preferences *NotificationPreferences) {
 	if _, ok := preferences.Channels[string(NotificationChannelFCM)]; !ok {
 		preferences.Channels[string(NotificationChannelFCM)] = true
 	}
+	if _, ok := preferences.Channels[string(NotificationChannelSlack)]; !ok {
+		preferences.Channels[string(NotificationChannelSlack)] = true
+	}
 }
 
 // hashAddress creates a deterministic SHA-256 fingerprint from a channel
@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// SubscribeToTopic subscribes a user to a broadcast topic such as
+// "release-notes" or a group-scoped "group:{id}" topic.
+//
+// The topic is normalised with NormaliseTopic before it is stored.
+// Deciding who may follow a group topic is left to the caller (UMS checks
+// group membership first).
+func (s *Service) SubscribeToTopic(ctx context.Context, req *SubscribeToTopicRequest) (*SubscribeToTopicResponse, error) {
+	if req == nil || strings.TrimSpace(req.UserID) == "" {
+		return nil, ErrNotificationUserIDRequired
+	}
+
+	topic, err := NormaliseTopic(req.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logger.AcquirePackageFrom(ctx, "external/notifier").With(
+		zap.String("operation", "subscribe-to-topic"),
+		zap.String("user-id", req.UserID),
+		zap.String("topic", topic),
+	)
+
+	subscription, err := s.Repository.UpsertTopicSubscription(ctx, &NotificationTopicSubscription{
+		ID:        toolbox.GenerateUuidV4(),
+		UserID:    strings.TrimSpace(req.UserID),
+		Topic:     topic,
+		CreatedAt: toolbox.TimeNowUTC(),
+	})
+	if err != nil {
+		logger.Error("notification-topic-subscribe-failed", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("notification-topic-subscribed")
+	return &SubscribeToTopicResponse{Subscription: subscription}, nil
+}
+
+// UnsubscribeFromTopic removes a user's subscription to a topic.
+func (s *Service) UnsubscribeFromTopic(ctx context.Context, req *UnsubscribeFromTopicRequest) error {
+	if req == nil || strings.TrimSpace(req.UserID) == "" {
+		return ErrNotificationUserIDRequired
+	}
+
+	topic, err := NormaliseTopic(req.Topic)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repository.DeleteTopicSubscription(ctx, strings.TrimSpace(req.UserID), topic); err != nil {
+		logger.AcquireOperationFrom(ctx, "external/notifier", "unsubscribe-from-topic").Error(
+			"notification-topic-unsubscribe-failed",
+			zap.String("user-id", req.UserID),
+			zap.String("topic", topic),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ListTopicSubscriptions returns every topic a user follows.
+func (s *Service) ListTopicSubscriptions(ctx context.Context, req *ListTopicSubscriptionsRequest) (*ListTopicSubscriptionsResponse, error) {
+	if req == nil || strings.TrimSpace(req.UserID) == "" {
+		return nil, ErrNotificationUserIDRequired
+	}
+
+	subscriptions, err := s.Repository.GetTopicSubscriptionsByUserID(ctx, strings.TrimSpace(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+	if subscriptions == nil {
+		subscriptions = []NotificationTopicSubscription{}
+	}
+
+	return &ListTopicSubscriptionsResponse{Subscriptions: subscriptions}, nil
+}
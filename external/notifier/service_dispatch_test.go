@@ -0,0 +1,358 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeSegmentResolver pages through a fixed list of user IDs using the
+// last returned user ID as its cursor.
+type fakeSegmentResolver struct {
+	userIDs  []string
+	segments []*NotificationSegment
+}
+
+func (f *fakeSegmentResolver) ResolveSegmentUserIDs(ctx context.Context, segment *NotificationSegment, cursor string, limit int) ([]string, string, error) {
+	f.segments = append(f.segments, segment)
+
+	page := []string{}
+	for _, userID := range f.userIDs {
+		if userID > cursor && len(page) < limit {
+			page = append(page, userID)
+		}
+	}
+	if len(page) < limit {
+		return page, "", nil
+	}
+	return page, page[len(page)-1], nil
+}
+
+func dispatchTestAddresses(userIDs ...string) []NotificationAddress {
+	addresses := []NotificationAddress{}
+	for _, userID := range userIDs {
+		addresses = append(addresses, NotificationAddress{
+			ID: "addr-" + userID, UserID: userID, Channel: NotificationChannelWebPush, Status: NotificationAddressStatusActive,
+			WebPush: &WebPushAddress{Endpoint: "https://push.example/" + userID, Keys: WebPushKeys{Auth: "a", P256DH: "k"}},
+		})
+	}
+	return addresses
+}
+
+func dispatchResultUserIDs(response *NotifyUsersResponse) []string {
+	userIDs := []string{}
+	for _, result := range response.Results {
+		userIDs = append(userIDs, result.UserID)
+	}
+	return userIDs
+}
+
+func TestNotifyUsers_TopicStreamsSubscribersInBatches(t *testing.T) {
+	repository := &fakeRepository{
+		addresses: dispatchTestAddresses("u1", "u2", "u3", "u4"),
+		topicSubscriptions: []NotificationTopicSubscription{
+			{UserID: "u3", Topic: "release-notes"},
+			{UserID: "u1", Topic: "release-notes"},
+			{UserID: "u2", Topic: "release-notes"},
+			{UserID: "u4", Topic: "group:g1"},
+		},
+	}
+	service := NewService(&NewServiceRequest{
+		Repository: repository,
+		Senders:    []ChannelSender{&fakeSender{channel: NotificationChannelWebPush, enabled: true}},
+	}).WithDispatchBatchSize(2).WithDispatchConcurrency(2)
+
+	response, err := service.NotifyUsers(context.Background(), &NotifyUsersRequest{
+		RequestedBy: "admin-1",
+		Topic:       " Release-Notes ",
+		Title:       "Hi",
+		Message:     "There",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := dispatchResultUserIDs(response); !slices.Equal(got, []string{"u1", "u2", "u3"}) {
+		t.Fatalf("expected topic subscribers u1,u2,u3, got %v", got)
+	}
+
+	job := response.Job
+	if job == nil || job.Status != NotificationDispatchJobStatusCompleted {
+		t.Fatalf("expected completed job, got %#v", job)
+	}
+	if job.Target.Topic != "release-notes" || job.RequestedBy != "admin-1" {
+		t.Fatalf("unexpected job target or requester: %#v", job)
+	}
+	if job.Progress.Batches != 2 || job.Progress.TargetedUsers != 3 || job.Progress.NotifiedUsers != 3 {
+		t.Fatalf("unexpected progress: %#v", job.Progress)
+	}
+	if len(repository.checkpoints) != 2 || repository.checkpoints[0].Cursor != "u2" {
+		t.Fatalf("expected first checkpoint at u2, got %#v", repository.checkpoints)
+	}
+}
+
+func TestNotifyUsers_SegmentUsesResolver(t *testing.T) {
+	resolver := &fakeSegmentResolver{userIDs: []string{"u1", "u2", "u3"}}
+	service := NewService(&NewServiceRequest{
+		Repository: &fakeRepository{addresses: dispatchTestAddresses("u1", "u3")},
+		Senders:    []ChannelSender{&fakeSender{channel: NotificationChannelWebPush, enabled: true}},
+	}).WithSegmentResolver(resolver).WithDispatchBatchSize(2)
+
+	segment := &NotificationSegment{Roles: []string{"ADMIN"}}
+	response, err := service.NotifyUsers(context.Background(), &NotifyUsersRequest{
+		Segment: segment,
+		Title:   "Hi",
+		Message: "There",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := dispatchResultUserIDs(response); !slices.Equal(got, []string{"u1", "u2", "u3"}) {
+		t.Fatalf("expected segment users u1,u2,u3, got %v", got)
+	}
+	if response.Job.Progress.NotifiedUsers != 2 || response.Job.Progress.SkippedUsers != 1 {
+		t.Fatalf("expected 2 notified and 1 skipped, got %#v", response.Job.Progress)
+	}
+	if len(resolver.segments) != 2 || resolver.segments[0] != segment {
+		t.Fatalf("expected resolver to be called twice with the segment, got %d calls", len(resolver.segments))
+	}
+}
+
+func TestNotifyUsers_CapsResponseResults(t *testing.T) {
+	userIDs := []string{}
+	for i := 0; i < maxDispatchResponseResults+5; i++ {
+		userIDs = append(userIDs, fmt.Sprintf("u%05d", i))
+	}
+	resolver := &fakeSegmentResolver{userIDs: userIDs}
+	service := NewService(&NewServiceRequest{
+		Repository: &fakeRepository{},
+		Senders:    []ChannelSender{&fakeSender{channel: NotificationChannelWebPush, enabled: true}},
+	}).WithSegmentResolver(resolver)
+
+	response, err := service.NotifyUsers(context.Background(), &NotifyUsersRequest{
+		Segment: &NotificationSegment{Roles: []string{"READER"}},
+		Title:   "Hi",
+		Message: "There",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(response.Results) != maxDispatchResponseResults || !response.ResultsTruncated {
+		t.Fatalf("expected %d results marked truncated, got %d (truncated %v)", maxDispatchResponseResults, len(response.Results), response.ResultsTruncated)
+	}
+	if response.Job.Progress.TargetedUsers != len(userIDs) {
+		t.Fatalf("expected progress to count all %d users, got %#v", len(userIDs), response.Job.Progress)
+	}
+}
+
+func TestNotifyUsers_RejectsInvalidTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver SegmentResolver
+		req      *NotifyUsersRequest
+		wantErr  error
+	}{
+		{
+			name:    "BAD user ids and topic",
+			req:     &NotifyUsersRequest{UserIDs: []string{"u1"}, Topic: "release-notes", Title: "Hi", Message: "There"},
+			wantErr: ErrInvalidNotificationTarget,
+		},
+		{
+			name:    "BAD topic and segment",
+			req:     &NotifyUsersRequest{Topic: "release-notes", Segment: &NotificationSegment{Roles: []string{"ADMIN"}}, Title: "Hi", Message: "There"},
+			wantErr: ErrInvalidNotificationTarget,
+		},
+		{
+			name:    "BAD blank user ids",
+			req:     &NotifyUsersRequest{UserIDs: []string{" "}, Title: "Hi", Message: "There"},
+			wantErr: ErrInvalidNotificationTarget,
+		},
+		{
+			name:    "BAD invalid topic",
+			req:     &NotifyUsersRequest{Topic: "release notes!", Title: "Hi", Message: "There"},
+			wantErr: ErrInvalidNotificationTopic,
+		},
+		{
+			name:     "BAD empty segment",
+			resolver: &fakeSegmentResolver{},
+			req:      &NotifyUsersRequest{Segment: &NotificationSegment{}, Title: "Hi", Message: "There"},
+			wantErr:  ErrInvalidNotificationTarget,
+		},
+		{
+			name:    "BAD segment without resolver",
+			req:     &NotifyUsersRequest{Segment: &NotificationSegment{Roles: []string{"ADMIN"}}, Title: "Hi", Message: "There"},
+			wantErr: ErrSegmentResolverNotConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{}
+			service := NewService(&NewServiceRequest{Repository: repository})
+			if tt.resolver != nil {
+				service.WithSegmentResolver(tt.resolver)
+			}
+
+			_, err := service.NotifyUsers(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(repository.dispatchJobs) != 0 {
+				t.Fatalf("expected no dispatch job to be created, got %d", len(repository.dispatchJobs))
+			}
+		})
+	}
+}
+
+func TestResumeDispatchJob_ContinuesFromCursor(t *testing.T) {
+	sender := &fakeSender{channel: NotificationChannelWebPush, enabled: true}
+	repository := &fakeRepository{
+		addresses: dispatchTestAddresses("u1", "u2", "u3", "u4", "u5"),
+		dispatchJobs: map[string]NotificationDispatchJob{
+			"job-1": {
+				ID:       "job-1",
+				Status:   NotificationDispatchJobStatusRunning,
+				Title:    "Hi",
+				Message:  "There",
+				Cursor:   "u2",
+				Progress: NotificationDispatchProgress{Batches: 1, TargetedUsers: 2, NotifiedUsers: 2},
+			},
+		},
+	}
+	service := NewService(&NewServiceRequest{
+		Repository: repository,
+		Senders:    []ChannelSender{sender},
+	}).WithDispatchBatchSize(2)
+
+	response, err := service.ResumeDispatchJob(context.Background(), &ResumeDispatchJobRequest{JobID: "job-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := dispatchResultUserIDs(response); !slices.Equal(got, []string{"u3", "u4", "u5"}) {
+		t.Fatalf("expected only users after the cursor, got %v", got)
+	}
+	if sender.attempts != 3 {
+		t.Fatalf("expected 3 send attempts, got %d", sender.attempts)
+	}
+	if response.Job.Status != NotificationDispatchJobStatusCompleted || response.Job.Progress.TargetedUsers != 5 || response.Job.Progress.Batches != 3 {
+		t.Fatalf("unexpected resumed job: %#v", response.Job)
+	}
+
+	_, err = service.ResumeDispatchJob(context.Background(), &ResumeDispatchJobRequest{JobID: "job-1"})
+	if !errors.Is(err, ErrDispatchJobAlreadyCompleted) {
+		t.Fatalf("expected %v, got %v", ErrDispatchJobAlreadyCompleted, err)
+	}
+
+	_, err = service.ResumeDispatchJob(context.Background(), &ResumeDispatchJobRequest{JobID: "missing"})
+	if !errors.Is(err, ErrDispatchJobNotFound) {
+		t.Fatalf("expected %v, got %v", ErrDispatchJobNotFound, err)
+	}
+}
+
+func TestResumeDispatchJob_RejectsJobsStillBeingDelivered(t *testing.T) {
+	sender := &fakeSender{channel: NotificationChannelWebPush, enabled: true}
+	repository := &fakeRepository{
+		addresses: dispatchTestAddresses("u1", "u2", "u3"),
+		dispatchJobs: map[string]NotificationDispatchJob{
+			"job-1": {
+				ID:          "job-1",
+				Status:      NotificationDispatchJobStatusRunning,
+				Title:       "Hi",
+				Message:     "There",
+				Cursor:      "u1",
+				ClaimedBy:   "other-run",
+				HeartbeatAt: dispatchJobHeartbeatNow(),
+			},
+		},
+	}
+	service := NewService(&NewServiceRequest{
+		Repository: repository,
+		Senders:    []ChannelSender{sender},
+	})
+
+	_, err := service.ResumeDispatchJob(context.Background(), &ResumeDispatchJobRequest{JobID: "job-1"})
+	if !errors.Is(err, ErrDispatchJobAlreadyClaimed) {
+		t.Fatalf("expected %v, got %v", ErrDispatchJobAlreadyClaimed, err)
+	}
+	if sender.attempts != 0 {
+		t.Fatalf("expected no send attempts, got %d", sender.attempts)
+	}
+	if repository.dispatchJobs["job-1"].ClaimedBy != "other-run" {
+		t.Fatalf("expected the job to stay claimed by the other run, got %q", repository.dispatchJobs["job-1"].ClaimedBy)
+	}
+}
+
+func TestResumeDispatchJob_ClaimsStaleJobs(t *testing.T) {
+	sender := &fakeSender{channel: NotificationChannelWebPush, enabled: true}
+	repository := &fakeRepository{
+		addresses: dispatchTestAddresses("u1", "u2", "u3"),
+		dispatchJobs: map[string]NotificationDispatchJob{
+			"job-1": {
+				ID:          "job-1",
+				Status:      NotificationDispatchJobStatusRunning,
+				Title:       "Hi",
+				Message:     "There",
+				Cursor:      "u1",
+				ClaimedBy:   "stopped-run",
+				HeartbeatAt: time.Now().UTC().Add(-2 * dispatchJobStaleAfter).Format(time.RFC3339),
+			},
+		},
+	}
+	service := NewService(&NewServiceRequest{
+		Repository: repository,
+		Senders:    []ChannelSender{sender},
+	})
+
+	response, err := service.ResumeDispatchJob(context.Background(), &ResumeDispatchJobRequest{JobID: "job-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := dispatchResultUserIDs(response); !slices.Equal(got, []string{"u2", "u3"}) {
+		t.Fatalf("expected only users after the cursor, got %v", got)
+	}
+	claimedBy := repository.dispatchJobs["job-1"].ClaimedBy
+	if claimedBy == "" || claimedBy == "stopped-run" {
+		t.Fatalf("expected the job to be claimed by the resuming run, got %q", claimedBy)
+	}
+	for _, checkpoint := range repository.checkpoints {
+		if checkpoint.ClaimedBy != claimedBy {
+			t.Fatalf("expected checkpoints from the claiming run, got %q", checkpoint.ClaimedBy)
+		}
+	}
+}
+
+func TestTopicSubscriptions_SubscribeListUnsubscribe(t *testing.T) {
+	repository := &fakeRepository{}
+	service := NewService(&NewServiceRequest{Repository: repository})
+	ctx := context.Background()
+
+	for _, topic := range []string{"Release-Notes", "release-notes", "group:Abc-123"} {
+		if _, err := service.SubscribeToTopic(ctx, &SubscribeToTopicRequest{UserID: "u1", Topic: topic}); err != nil {
+			t.Fatalf("expected no error subscribing to %q, got %v", topic, err)
+		}
+	}
+	if _, err := service.SubscribeToTopic(ctx, &SubscribeToTopicRequest{UserID: "u1", Topic: "group:"}); !errors.Is(err, ErrInvalidNotificationTopic) {
+		t.Fatalf("expected %v, got %v", ErrInvalidNotificationTopic, err)
+	}
+
+	listResponse, err := service.ListTopicSubscriptions(ctx, &ListTopicSubscriptionsRequest{UserID: "u1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	topics := []string{}
+	for _, subscription := range listResponse.Subscriptions {
+		topics = append(topics, subscription.Topic)
+	}
+	if !slices.Equal(topics, []string{"release-notes", "group:Abc-123"}) {
+		t.Fatalf("unexpected topics: %v", topics)
+	}
+
+	if err := service.UnsubscribeFromTopic(ctx, &UnsubscribeFromTopicRequest{UserID: "u1", Topic: "RELEASE-NOTES"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(repository.topicSubscriptions) != 1 || repository.topicSubscriptions[0].Topic != "group:Abc-123" {
+		t.Fatalf("expected only the group topic to remain, got %#v", repository.topicSubscriptions)
+	}
+}
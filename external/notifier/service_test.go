@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"sort"
+	"sync"
 	"testing"
 )
 
@@ -20,6 +23,12 @@ type fakeRepository struct {
 	countRequest        *ListNotificationAddressesRequest
 
 	disableError error
+
+	mu                 sync.Mutex
+	topicSubscriptions []NotificationTopicSubscription
+	dispatchJobs       map[string]NotificationDispatchJob
+	checkpoints        []NotificationDispatchJob
+	checkpointError    error
 }
 
 func (r *fakeRepository) UpsertAddress(ctx context.Context, address *NotificationAddress) (*NotificationAddress, error) {
@@ -67,17 +76,28 @@ func (r *fakeRepository) DisableAddressByHash(ctx context.Context, hash string)
 }
 
 func (r *fakeRepository) GetPreferencesByUserID(ctx context.Context, userID string) (*NotificationPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.preferencesByUserID != nil {
 		preferences := r.preferencesByUserID[userID]
 		if preferences == nil {
 			return nil, ErrNotificationAddressNotFound
 		}
-		return preferences, nil
+		return copyFakePreferences(preferences), nil
 	}
 	if r.preferences == nil {
 		return nil, ErrNotificationAddressNotFound
 	}
-	return r.preferences, nil
+	return copyFakePreferences(r.preferences), nil
+}
+
+// copyFakePreferences mirrors the real repository, which decodes a fresh
+// document per call, so concurrent dispatch workers never share a map.
+func copyFakePreferences(preferences *NotificationPreferences) *NotificationPreferences {
+	copied := *preferences
+	copied.Channels = maps.Clone(preferences.Channels)
+	return &copied
 }
 
 func (r *fakeRepository) UpsertPreferences(ctx context.Context, preferences *NotificationPreferences) (*NotificationPreferences, error) {
@@ -89,6 +109,128 @@ func (r *fakeRepository) DeletePreferencesByUserID(ctx context.Context, userID s
 	return nil
 }
 
+func (r *fakeRepository) GetActiveAddressUserIDs(ctx context.Context, afterUserID string, limit int, channels ...NotificationChannel) ([]string, error) {
+	seen := map[string]bool{}
+	userIDs := []string{}
+	for _, address := range filterFakeActiveAddresses(r.addresses, "", channels...) {
+		if address.UserID <= afterUserID || seen[address.UserID] {
+			continue
+		}
+		seen[address.UserID] = true
+		userIDs = append(userIDs, address.UserID)
+	}
+	sort.Strings(userIDs)
+	return userIDs[:min(limit, len(userIDs))], nil
+}
+
+func (r *fakeRepository) UpsertTopicSubscription(ctx context.Context, subscription *NotificationTopicSubscription) (*NotificationTopicSubscription, error) {
+	for _, existing := range r.topicSubscriptions {
+		if existing.UserID == subscription.UserID && existing.Topic == subscription.Topic {
+			return &existing, nil
+		}
+	}
+	r.topicSubscriptions = append(r.topicSubscriptions, *subscription)
+	return subscription, nil
+}
+
+func (r *fakeRepository) DeleteTopicSubscription(ctx context.Context, userID, topic string) error {
+	kept := []NotificationTopicSubscription{}
+	for _, subscription := range r.topicSubscriptions {
+		if subscription.UserID == userID && subscription.Topic == topic {
+			continue
+		}
+		kept = append(kept, subscription)
+	}
+	r.topicSubscriptions = kept
+	return nil
+}
+
+func (r *fakeRepository) DeleteTopicSubscriptionsByUserID(ctx context.Context, userID string) error {
+	return nil
+}
+
+func (r *fakeRepository) GetTopicSubscriptionsByUserID(ctx context.Context, userID string) ([]NotificationTopicSubscription, error) {
+	subscriptions := []NotificationTopicSubscription{}
+	for _, subscription := range r.topicSubscriptions {
+		if subscription.UserID == userID {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *fakeRepository) GetTopicSubscriberUserIDs(ctx context.Context, topic, afterUserID string, limit int) ([]string, error) {
+	userIDs := []string{}
+	for _, subscription := range r.topicSubscriptions {
+		if subscription.Topic == topic && subscription.UserID > afterUserID {
+			userIDs = append(userIDs, subscription.UserID)
+		}
+	}
+	sort.Strings(userIDs)
+	return userIDs[:min(limit, len(userIDs))], nil
+}
+
+func (r *fakeRepository) CreateDispatchJob(ctx context.Context, job *NotificationDispatchJob) error {
+	if r.dispatchJobs == nil {
+		r.dispatchJobs = map[string]NotificationDispatchJob{}
+	}
+	r.dispatchJobs[job.ID] = *job
+	return nil
+}
+
+func (r *fakeRepository) GetDispatchJobByID(ctx context.Context, jobID string) (*NotificationDispatchJob, error) {
+	job, ok := r.dispatchJobs[jobID]
+	if !ok {
+		return nil, ErrDispatchJobNotFound
+	}
+	return &job, nil
+}
+
+func (r *fakeRepository) GetStaleDispatchJobs(ctx context.Context, staleBefore string) ([]NotificationDispatchJob, error) {
+	jobs := []NotificationDispatchJob{}
+	for _, job := range r.dispatchJobs {
+		if job.Status == NotificationDispatchJobStatusRunning && job.HeartbeatAt < staleBefore {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeRepository) ClaimDispatchJob(ctx context.Context, jobID, claimedBy, staleBefore, claimedAt string) (*NotificationDispatchJob, error) {
+	job, ok := r.dispatchJobs[jobID]
+	if !ok {
+		return nil, ErrDispatchJobAlreadyClaimed
+	}
+	stale := job.Status == NotificationDispatchJobStatusRunning && job.HeartbeatAt < staleBefore
+	if job.Status != NotificationDispatchJobStatusFailed && !stale {
+		return nil, ErrDispatchJobAlreadyClaimed
+	}
+	job.Status = NotificationDispatchJobStatusRunning
+	job.ClaimedBy = claimedBy
+	job.HeartbeatAt = claimedAt
+	r.dispatchJobs[jobID] = job
+	return &job, nil
+}
+
+func (r *fakeRepository) HeartbeatDispatchJob(ctx context.Context, jobID, claimedBy, heartbeatAt string) error {
+	job, ok := r.dispatchJobs[jobID]
+	if !ok || job.ClaimedBy != claimedBy || job.Status != NotificationDispatchJobStatusRunning {
+		return ErrDispatchJobAlreadyClaimed
+	}
+	job.HeartbeatAt = heartbeatAt
+	r.dispatchJobs[jobID] = job
+	return nil
+}
+
+func (r *fakeRepository) CheckpointDispatchJob(ctx context.Context, job *NotificationDispatchJob) error {
+	if r.checkpointError != nil {
+		return r.checkpointError
+	}
+	r.checkpoints = append(r.checkpoints, *job)
+	r.dispatchJobs[job.ID] = *job
+	return nil
+}
+
 func filterFakeActiveAddresses(addresses []NotificationAddress, userID string, channels ...NotificationChannel) []NotificationAddress {
 	channelSet := map[NotificationChannel]bool{}
 	for _, channel := range channels {
@@ -117,11 +259,14 @@ type fakeSender struct {
 	channel  NotificationChannel
 	enabled  bool
 	attempts int
+	mu       sync.Mutex
 }
 
 func (s *fakeSender) Channel() NotificationChannel { return s.channel }
 func (s *fakeSender) Enabled() bool                { return s.enabled }
 func (s *fakeSender) Send(ctx context.Context, subject, message string, addresses []NotificationAddress, data map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.attempts += len(addresses)
 	return nil
 }
@@ -55,6 +55,88 @@ const (
 
 	// ErrKeyPaymentProviderMissingPayloadCustomerEmail is returned when the customer's email cannot be attained from provider
 	ErrKeyPaymentProviderMissingPayloadCustomerEmail = "PaymentProviderMissingPayloadCustomerEmail"
+
+	// ErrKeyPaymentProviderCheckoutNotSupported is returned when the provider does not support
+	// creating checkout or customer portal sessions
+	ErrKeyPaymentProviderCheckoutNotSupported = "PaymentProviderCheckoutNotSupported"
+
+	// ErrKeyPaymentProviderMissingCheckoutPrice is returned when a checkout session is requested without a provider price
+	ErrKeyPaymentProviderMissingCheckoutPrice = "PaymentProviderMissingCheckoutPrice"
+
+	// ErrKeyPaymentProviderMissingCustomerID is returned when a customer portal session is requested without a provider customer ID
+	ErrKeyPaymentProviderMissingCustomerID = "PaymentProviderMissingCustomerID"
+
+	// ErrKeyPaymentProviderCheckoutRequestFailed is returned when the provider rejects a checkout or customer portal session request
+	ErrKeyPaymentProviderCheckoutRequestFailed = "PaymentProviderCheckoutRequestFailed"
+
+	// ErrKeyPaymentProviderUsageReportingNotSupported is returned when the provider does not support
+	// metered usage reporting
+	ErrKeyPaymentProviderUsageReportingNotSupported = "PaymentProviderUsageReportingNotSupported"
+
+	// ErrKeyPaymentProviderMissingUsageEventName is returned when usage is reported without the provider's meter event name
+	ErrKeyPaymentProviderMissingUsageEventName = "PaymentProviderMissingUsageEventName"
+
+	// ErrKeyPaymentProviderUsageReportRequestFailed is returned when the provider rejects a usage report
+	ErrKeyPaymentProviderUsageReportRequestFailed = "PaymentProviderUsageReportRequestFailed"
+
+	// ErrKeyPaymentProviderCatalogNotSupported is returned when the provider's products and prices
+	// cannot be read through its API
+	ErrKeyPaymentProviderCatalogNotSupported = "PaymentProviderCatalogNotSupported"
+
+	// ErrKeyPaymentProviderCatalogWriteNotSupported is returned when the provider's products and prices
+	// can only be created or changed in its dashboard
+	ErrKeyPaymentProviderCatalogWriteNotSupported = "PaymentProviderCatalogWriteNotSupported"
+
+	// ErrKeyPaymentProviderCatalogItemNotFound is returned when a product or price does not exist on the provider
+	ErrKeyPaymentProviderCatalogItemNotFound = "PaymentProviderCatalogItemNotFound"
+
+	// ErrKeyPaymentProviderCatalogRequestFailed is returned when the provider rejects a product or price change
+	ErrKeyPaymentProviderCatalogRequestFailed = "PaymentProviderCatalogRequestFailed"
+
+	// ErrKeyPaymentProviderSubscriptionManagementNotSupported is returned when the provider's subscriptions
+	// cannot be cancelled or paused through its API
+	ErrKeyPaymentProviderSubscriptionManagementNotSupported = "PaymentProviderSubscriptionManagementNotSupported"
+
+	// ErrKeyPaymentProviderMissingSubscriptionID is returned when a subscription change is requested without
+	// the provider's subscription ID
+	ErrKeyPaymentProviderMissingSubscriptionID = "PaymentProviderMissingSubscriptionID"
+
+	// ErrKeyPaymentProviderSubscriptionChangeRequestFailed is returned when the provider rejects a subscription
+	// cancellation or pause
+	ErrKeyPaymentProviderSubscriptionChangeRequestFailed = "PaymentProviderSubscriptionChangeRequestFailed"
+
+	// ErrKeyPaymentProviderRefundsNotSupported is returned when the provider's payments cannot be refunded
+	// through its API
+	ErrKeyPaymentProviderRefundsNotSupported = "PaymentProviderRefundsNotSupported"
+
+	// ErrKeyPaymentProviderMissingTransactionID is returned when a refund is requested without the provider's
+	// transaction ID
+	ErrKeyPaymentProviderMissingTransactionID = "PaymentProviderMissingTransactionID"
+
+	// ErrKeyPaymentProviderPartialRefundNotSupported is returned when a partial refund is requested from a
+	// provider that can only refund payments in full
+	ErrKeyPaymentProviderPartialRefundNotSupported = "PaymentProviderPartialRefundNotSupported"
+
+	// ErrKeyPaymentProviderRefundRequestFailed is returned when the provider rejects a refund
+	ErrKeyPaymentProviderRefundRequestFailed = "PaymentProviderRefundRequestFailed"
+)
+
+const (
+	// MetadataKeyUserID is the metadata key used to attach the platform user ID to
+	// checkout sessions, so resulting webhooks can be linked back to the user
+	MetadataKeyUserID = "user_id"
+
+	// MetadataKeyCustomerEmail is the metadata key used to attach the platform user's
+	// email to checkout sessions
+	MetadataKeyCustomerEmail = "customer_email"
+
+	// MetadataKeyGroupID is the metadata key used to attach the platform group ID to
+	// checkout sessions bought on behalf of a group, so the subscription is owned by the group
+	MetadataKeyGroupID = "group_id"
+
+	// MetadataKeyPromotionCode is the metadata key used to attach the platform promotion
+	// code to checkout sessions, so it can be redeemed once the subscription is created
+	MetadataKeyPromotionCode = "promotion_code"
 )
 
 // PaymentType constants for categorizing different types of payments
@@ -5,22 +5,40 @@ import "github.com/ooaklee/reply/v2"
 // PaymentProviderErrorMap holds Error keys, their corresponding human-friendly message, and response status code
 // nolint will be used later
 var PaymentProviderErrorMap reply.ErrorManifest = reply.ErrorManifest{
-	ErrPaymentProviderMissingConfiguration:           {Title: "Internal Server Error", Detail: "Provider name is required in configuration", StatusCode: 500, Code: "PP00-001"},
-	ErrPaymentProviderRequiredWebhookSecretIsMissing: {Title: "Internal Server Error", Detail: "Webhook secret is required in configuration", StatusCode: 500, Code: "PP00-002"},
-	ErrPaymentProviderInvalidConfigWebhookSecret:     {Title: "Internal Server Error", Detail: "Webhook secret in configuration is invalid", StatusCode: 500, Code: "PP00-003"},
-	ErrPaymentProviderInvalidConfiguration:           {Title: "Bad Request", Detail: "Provider configuration is invalid", StatusCode: 400, Code: "PP00-003"},
-	ErrPaymentProviderInvalidWebhookSignature:        {Title: "Bad Request", Detail: "Webhook signature verification failed", StatusCode: 400, Code: "PP00-004"},
-	ErrPaymentProviderMissingSignature:               {Title: "Bad Request", Detail: "Webhook signature is missing", StatusCode: 400, Code: "PP00-005"},
-	ErrPaymentProviderInvalidPayload:                 {Title: "Bad Request", Detail: "Webhook payload is invalid or malformed", StatusCode: 400, Code: "PP00-006"},
-	ErrPaymentProviderUnsupportedProvider:            {Title: "Bad Request", Detail: "Payment provider is not supported", StatusCode: 400, Code: "PP00-007"},
-	ErrPaymentProviderPayloadParsing:                 {Title: "Bad Request", Detail: "Failed to parse webhook payload", StatusCode: 400, Code: "PP00-008"},
-	ErrPaymentProviderMissingRequiredField:           {Title: "Bad Request", Detail: "Required field missing from webhook payload", StatusCode: 400, Code: "PP00-009"},
-	ErrPaymentProviderInvalidEventType:               {Title: "Bad Request", Detail: "Event type is not recognised", StatusCode: 400, Code: "PP00-010"},
-	ErrPaymentProviderAPIRequestFailed:               {Title: "Internal Server Error", Detail: "Failed to make API request to provider", StatusCode: 500, Code: "PP00-011"},
-	ErrPaymentProviderAPIResponseInvalid:             {Title: "Internal Server Error", Detail: "Provider API returned invalid response", StatusCode: 500, Code: "PP00-012"},
-	ErrPaymentProviderSubscriptionNotFound:           {Title: "Not Found", Detail: "Subscription not found", StatusCode: 404, Code: "PP00-013"},
-	ErrPaymentProviderKofiNoSubscriptionAPI:          {Title: "Not Implemented", Detail: "Ko-fi does not provide a subscription API", StatusCode: 501, Code: "PP00-014"},
-	ErrPaymentProviderWebhookTimestampTooOld:         {Title: "Bad Request", Detail: "Webhook is too old", StatusCode: 400, Code: "PP00-015"},
-	ErrPaymentProviderMissingPayloadCustomerEmail:    {Title: "Bad Request", Detail: "Customer email is missing from webhook payload", StatusCode: 400, Code: "PP00-016"},
-	ErrPaymentProviderNotFound:                       {Title: "Internal Server Error", Detail: "Payment provider not found in registry", StatusCode: 500, Code: "PP00-017"},
+	ErrPaymentProviderMissingConfiguration:               {Title: "Internal Server Error", Detail: "Provider name is required in configuration", StatusCode: 500, Code: "PP00-001"},
+	ErrPaymentProviderRequiredWebhookSecretIsMissing:     {Title: "Internal Server Error", Detail: "Webhook secret is required in configuration", StatusCode: 500, Code: "PP00-002"},
+	ErrPaymentProviderInvalidConfigWebhookSecret:         {Title: "Internal Server Error", Detail: "Webhook secret in configuration is invalid", StatusCode: 500, Code: "PP00-003"},
+	ErrPaymentProviderInvalidConfiguration:               {Title: "Bad Request", Detail: "Provider configuration is invalid", StatusCode: 400, Code: "PP00-003"},
+	ErrPaymentProviderInvalidWebhookSignature:            {Title: "Bad Request", Detail: "Webhook signature verification failed", StatusCode: 400, Code: "PP00-004"},
+	ErrPaymentProviderMissingSignature:                   {Title: "Bad Request", Detail: "Webhook signature is missing", StatusCode: 400, Code: "PP00-005"},
+	ErrPaymentProviderInvalidPayload:                     {Title: "Bad Request", Detail: "Webhook payload is invalid or malformed", StatusCode: 400, Code: "PP00-006"},
+	ErrPaymentProviderUnsupportedProvider:                {Title: "Bad Request", Detail: "Payment provider is not supported", StatusCode: 400, Code: "PP00-007"},
+	ErrPaymentProviderPayloadParsing:                     {Title: "Bad Request", Detail: "Failed to parse webhook payload", StatusCode: 400, Code: "PP00-008"},
+	ErrPaymentProviderMissingRequiredField:               {Title: "Bad Request", Detail: "Required field missing from webhook payload", StatusCode: 400, Code: "PP00-009"},
+	ErrPaymentProviderInvalidEventType:                   {Title: "Bad Request", Detail: "Event type is not recognised", StatusCode: 400, Code: "PP00-010"},
+	ErrPaymentProviderAPIRequestFailed:                   {Title: "Internal Server Error", Detail: "Failed to make API request to provider", StatusCode: 500, Code: "PP00-011"},
+	ErrPaymentProviderAPIResponseInvalid:                 {Title: "Internal Server Error", Detail: "Provider API returned invalid response", StatusCode: 500, Code: "PP00-012"},
+	ErrPaymentProviderSubscriptionNotFound:               {Title: "Not Found", Detail: "Subscription not found", StatusCode: 404, Code: "PP00-013"},
+	ErrPaymentProviderKofiNoSubscriptionAPI:              {Title: "Not Implemented", Detail: "Ko-fi does not provide a subscription API", StatusCode: 501, Code: "PP00-014"},
+	ErrPaymentProviderWebhookTimestampTooOld:             {Title: "Bad Request", Detail: "Webhook is too old", StatusCode: 400, Code: "PP00-015"},
+	ErrPaymentProviderMissingPayloadCustomerEmail:        {Title: "Bad Request", Detail: "Customer email is missing from webhook payload", StatusCode: 400, Code: "PP00-016"},
+	ErrPaymentProviderNotFound:                           {Title: "Internal Server Error", Detail: "Payment provider not found in registry", StatusCode: 500, Code: "PP00-017"},
+	ErrPaymentProviderCheckoutNotSupported:               {Title: "Not Implemented", Detail: "Payment provider does not support hosted checkout or customer portal sessions", StatusCode: 501, Code: "PP00-018"},
+	ErrPaymentProviderMissingCheckoutPrice:               {Title: "Bad Request", Detail: "A provider price is required to create a checkout session", StatusCode: 400, Code: "PP00-019"},
+	ErrPaymentProviderMissingCustomerID:                  {Title: "Bad Request", Detail: "A provider customer is required to create a customer portal session", StatusCode: 400, Code: "PP00-020"},
+	ErrPaymentProviderCheckoutRequestFailed:              {Title: "Bad Gateway", Detail: "Payment provider rejected the session request", StatusCode: 502, Code: "PP00-021"},
+	ErrPaymentProviderUsageReportingNotSupported:         {Title: "Not Implemented", Detail: "Payment provider does not support metered usage reporting", StatusCode: 501, Code: "PP00-022"},
+	ErrPaymentProviderMissingUsageEventName:              {Title: "Bad Request", Detail: "A provider meter event name is required to report usage", StatusCode: 400, Code: "PP00-023"},
+	ErrPaymentProviderUsageReportRequestFailed:           {Title: "Bad Gateway", Detail: "Payment provider rejected the usage report", StatusCode: 502, Code: "PP00-024"},
+	ErrPaymentProviderCatalogNotSupported:                {Title: "Not Implemented", Detail: "Payment provider does not support reading products and prices", StatusCode: 501, Code: "PP00-025"},
+	ErrPaymentProviderCatalogWriteNotSupported:           {Title: "Not Implemented", Detail: "Payment provider products and prices can only be changed in its dashboard", StatusCode: 501, Code: "PP00-026"},
+	ErrPaymentProviderCatalogItemNotFound:                {Title: "Not Found", Detail: "Product or price not found on payment provider", StatusCode: 404, Code: "PP00-027"},
+	ErrPaymentProviderCatalogRequestFailed:               {Title: "Bad Gateway", Detail: "Payment provider rejected the product or price change", StatusCode: 502, Code: "PP00-028"},
+	ErrPaymentProviderSubscriptionManagementNotSupported: {Title: "Not Implemented", Detail: "Payment provider does not support cancelling or pausing subscriptions", StatusCode: 501, Code: "PP00-029"},
+	ErrPaymentProviderMissingSubscriptionID:              {Title: "Bad Request", Detail: "A provider subscription is required to change the subscription", StatusCode: 400, Code: "PP00-030"},
+	ErrPaymentProviderSubscriptionChangeRequestFailed:    {Title: "Bad Gateway", Detail: "Payment provider rejected the subscription change", StatusCode: 502, Code: "PP00-031"},
+	ErrPaymentProviderRefundsNotSupported:                {Title: "Not Implemented", Detail: "Payment provider does not support refunds", StatusCode: 501, Code: "PP00-032"},
+	ErrPaymentProviderMissingTransactionID:               {Title: "Bad Request", Detail: "A provider transaction is required to refund a payment", StatusCode: 400, Code: "PP00-033"},
+	ErrPaymentProviderPartialRefundNotSupported:          {Title: "Not Implemented", Detail: "Payment provider can only refund payments in full", StatusCode: 501, Code: "PP00-034"},
+	ErrPaymentProviderRefundRequestFailed:                {Title: "Bad Gateway", Detail: "Payment provider rejected the refund", StatusCode: 502, Code: "PP00-035"},
 }
@@ -3,22 +3,40 @@ package paymentprovider
 import "errors"
 
 var (
-	ErrPaymentProviderAPIRequestFailed               = errors.New(ErrKeyPaymentProviderAPIRequestFailed)
-	ErrPaymentProviderAPIResponseInvalid             = errors.New(ErrKeyPaymentProviderAPIResponseInvalid)
-	ErrPaymentProviderInvalidConfigWebhookSecret     = errors.New(ErrKeyPaymentProviderInvalidConfigWebhookSecret)
-	ErrPaymentProviderInvalidConfiguration           = errors.New(ErrKeyPaymentProviderInvalidConfiguration)
-	ErrPaymentProviderInvalidEventType               = errors.New(ErrKeyPaymentProviderInvalidEventType)
-	ErrPaymentProviderInvalidPayload                 = errors.New(ErrKeyPaymentProviderInvalidPayload)
-	ErrPaymentProviderInvalidWebhookSignature        = errors.New(ErrKeyPaymentProviderInvalidWebhookSignature)
-	ErrPaymentProviderKofiNoSubscriptionAPI          = errors.New(ErrKeyPaymentProviderKofiNoSubscriptionAPI)
-	ErrPaymentProviderMissingConfiguration           = errors.New(ErrKeyPaymentProviderMissingConfiguration)
-	ErrPaymentProviderMissingPayloadCustomerEmail    = errors.New(ErrKeyPaymentProviderMissingPayloadCustomerEmail)
-	ErrPaymentProviderMissingRequiredField           = errors.New(ErrKeyPaymentProviderMissingRequiredField)
-	ErrPaymentProviderMissingSignature               = errors.New(ErrKeyPaymentProviderMissingSignature)
-	ErrPaymentProviderNotFound                       = errors.New(ErrKeyPaymentProviderNotFound)
-	ErrPaymentProviderPayloadParsing                 = errors.New(ErrKeyPaymentProviderPayloadParsing)
-	ErrPaymentProviderRequiredWebhookSecretIsMissing = errors.New(ErrKeyPaymentProviderRequiredWebhookSecretIsMissing)
-	ErrPaymentProviderSubscriptionNotFound           = errors.New(ErrKeyPaymentProviderSubscriptionNotFound)
-	ErrPaymentProviderUnsupportedProvider            = errors.New(ErrKeyPaymentProviderUnsupportedProvider)
-	ErrPaymentProviderWebhookTimestampTooOld         = errors.New(ErrKeyPaymentProviderWebhookTimestampTooOld)
+	ErrPaymentProviderAPIRequestFailed                   = errors.New(ErrKeyPaymentProviderAPIRequestFailed)
+	ErrPaymentProviderAPIResponseInvalid                 = errors.New(ErrKeyPaymentProviderAPIResponseInvalid)
+	ErrPaymentProviderCatalogItemNotFound                = errors.New(ErrKeyPaymentProviderCatalogItemNotFound)
+	ErrPaymentProviderCatalogNotSupported                = errors.New(ErrKeyPaymentProviderCatalogNotSupported)
+	ErrPaymentProviderCatalogRequestFailed               = errors.New(ErrKeyPaymentProviderCatalogRequestFailed)
+	ErrPaymentProviderCatalogWriteNotSupported           = errors.New(ErrKeyPaymentProviderCatalogWriteNotSupported)
+	ErrPaymentProviderCheckoutNotSupported               = errors.New(ErrKeyPaymentProviderCheckoutNotSupported)
+	ErrPaymentProviderCheckoutRequestFailed              = errors.New(ErrKeyPaymentProviderCheckoutRequestFailed)
+	ErrPaymentProviderInvalidConfigWebhookSecret         = errors.New(ErrKeyPaymentProviderInvalidConfigWebhookSecret)
+	ErrPaymentProviderInvalidConfiguration               = errors.New(ErrKeyPaymentProviderInvalidConfiguration)
+	ErrPaymentProviderInvalidEventType                   = errors.New(ErrKeyPaymentProviderInvalidEventType)
+	ErrPaymentProviderInvalidPayload                     = errors.New(ErrKeyPaymentProviderInvalidPayload)
+	ErrPaymentProviderInvalidWebhookSignature            = errors.New(ErrKeyPaymentProviderInvalidWebhookSignature)
+	ErrPaymentProviderKofiNoSubscriptionAPI              = errors.New(ErrKeyPaymentProviderKofiNoSubscriptionAPI)
+	ErrPaymentProviderMissingCheckoutPrice               = errors.New(ErrKeyPaymentProviderMissingCheckoutPrice)
+	ErrPaymentProviderMissingConfiguration               = errors.New(ErrKeyPaymentProviderMissingConfiguration)
+	ErrPaymentProviderMissingCustomerID                  = errors.New(ErrKeyPaymentProviderMissingCustomerID)
+	ErrPaymentProviderMissingPayloadCustomerEmail        = errors.New(ErrKeyPaymentProviderMissingPayloadCustomerEmail)
+	ErrPaymentProviderMissingRequiredField               = errors.New(ErrKeyPaymentProviderMissingRequiredField)
+	ErrPaymentProviderMissingSignature                   = errors.New(ErrKeyPaymentProviderMissingSignature)
+	ErrPaymentProviderMissingSubscriptionID              = errors.New(ErrKeyPaymentProviderMissingSubscriptionID)
+	ErrPaymentProviderMissingTransactionID               = errors.New(ErrKeyPaymentProviderMissingTransactionID)
+	ErrPaymentProviderMissingUsageEventName              = errors.New(ErrKeyPaymentProviderMissingUsageEventName)
+	ErrPaymentProviderNotFound                           = errors.New(ErrKeyPaymentProviderNotFound)
+	ErrPaymentProviderPartialRefundNotSupported          = errors.New(ErrKeyPaymentProviderPartialRefundNotSupported)
+	ErrPaymentProviderPayloadParsing                     = errors.New(ErrKeyPaymentProviderPayloadParsing)
+	ErrPaymentProviderRefundRequestFailed                = errors.New(ErrKeyPaymentProviderRefundRequestFailed)
+	ErrPaymentProviderRefundsNotSupported                = errors.New(ErrKeyPaymentProviderRefundsNotSupported)
+	ErrPaymentProviderRequiredWebhookSecretIsMissing     = errors.New(ErrKeyPaymentProviderRequiredWebhookSecretIsMissing)
+	ErrPaymentProviderSubscriptionChangeRequestFailed    = errors.New(ErrKeyPaymentProviderSubscriptionChangeRequestFailed)
+	ErrPaymentProviderSubscriptionManagementNotSupported = errors.New(ErrKeyPaymentProviderSubscriptionManagementNotSupported)
+	ErrPaymentProviderSubscriptionNotFound               = errors.New(ErrKeyPaymentProviderSubscriptionNotFound)
+	ErrPaymentProviderUnsupportedProvider                = errors.New(ErrKeyPaymentProviderUnsupportedProvider)
+	ErrPaymentProviderUsageReportRequestFailed           = errors.New(ErrKeyPaymentProviderUsageReportRequestFailed)
+	ErrPaymentProviderUsageReportingNotSupported         = errors.New(ErrKeyPaymentProviderUsageReportingNotSupported)
+	ErrPaymentProviderWebhookTimestampTooOld             = errors.New(ErrKeyPaymentProviderWebhookTimestampTooOld)
 )
@@ -82,6 +82,16 @@ func Example2_MultiProviderRegistry() {
 	kofiProvider, _ := paymentprovider.NewKofiProvider(kofiConfig)
 	registry.Register(kofiProvider)
 
+	// Add Paddle
+	paddleConfig := &paymentprovider.Config{
+		ProviderName:  "paddle",
+		WebhookSecret: "pdl_ntfset_your_paddle_notification_secret",
+		APIKey:        "pdl_sdbx_apikey_your_paddle_api_key",
+		Environment:   "sandbox",
+	}
+	paddleProvider, _ := paymentprovider.NewPaddleProvider(paddleConfig)
+	registry.Register(paddleProvider)
+
 	// List all providers
 	fmt.Println("Registered providers:")
 	for _, name := range registry.List() {
@@ -229,6 +239,11 @@ func Example6_CreateRegistryFromConfigs() {
 			ProviderName:  "kofi",
 			WebhookSecret: "kofi_verification_token",
 		},
+		{
+			ProviderName:  "paddle",
+			WebhookSecret: "paddle_notification_secret",
+			APIKey:        "paddle_api_key",
+		},
 	}
 
 	// Create registry from configs
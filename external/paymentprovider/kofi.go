@@ -255,6 +255,27 @@ func (k *KofiProvider) GetSubscriptionInfo(ctx context.Context, subscriptionID s
 	return nil, ErrPaymentProviderKofiNoSubscriptionAPI
 }
 
+// CreateCheckoutSession is not supported as Ko-fi does not provide a checkout API,
+// supporters use the creator's Ko-fi page instead
+func (k *KofiProvider) CreateCheckoutSession(ctx context.Context, req *CheckoutSessionRequest) (*CheckoutSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", k.name)).With(zap.String("operation", "create-checkout-session"))
+
+	logger.Warn("kofi-create-checkout-session-not-supported-there-is-no-checkout-api")
+
+	return nil, ErrPaymentProviderCheckoutNotSupported
+}
+
+// CreateCustomerPortalSession is not supported as Ko-fi does not provide a customer portal API
+func (k *KofiProvider) CreateCustomerPortalSession(ctx context.Context, req *CustomerPortalSessionRequest) (*CustomerPortalSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", k.name)).With(zap.String("operation", "create-customer-portal-session"))
+
+	logger.Warn("kofi-create-customer-portal-session-not-supported-there-is-no-portal-api")
+
+	return nil, ErrPaymentProviderCheckoutNotSupported
+}
+
 // getFormData extracts the 'data' field from the form-encoded request
 func (k *KofiProvider) getFormData(req *http.Request, logger *zap.Logger) (string, error) {
 
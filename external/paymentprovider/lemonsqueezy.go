@@ -1,6 +1,7 @@
 package paymentprovider
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
@@ -9,7 +10,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"slices"
+	"strconv"
 
 	"github.com/ooaklee/ghatd/external/logger"
 	"go.uber.org/zap"
@@ -97,6 +100,28 @@ func (l *LemonSqueezyProvider) ParsePayload(ctx context.Context, req *http.Reque
 		planName = fmt.Sprintf("%s - %s", attrs.ProductName, attrs.VariantName)
 	}
 
+	// Use the variant as the plan identifier, matching the variant IDs used for checkouts
+	var planID string
+	if attrs.VariantID != 0 {
+		planID = strconv.FormatInt(attrs.VariantID, 10)
+	}
+
+	// Get the platform user attached when the checkout was created
+	var userID string
+	if customUserID, ok := webhook.Meta.CustomData[MetadataKeyUserID].(string); ok {
+		userID = customUserID
+	}
+
+	var groupID string
+	if customGroupID, ok := webhook.Meta.CustomData[MetadataKeyGroupID].(string); ok {
+		groupID = customGroupID
+	}
+
+	var promotionCode string
+	if customPromotionCode, ok := webhook.Meta.CustomData[MetadataKeyPromotionCode].(string); ok {
+		promotionCode = customPromotionCode
+	}
+
 	// Determine next billing date
 	nextBillingDate := attrs.RenewsAt
 	if attrs.EndsAt != "" {
@@ -117,13 +142,18 @@ func (l *LemonSqueezyProvider) ParsePayload(ctx context.Context, req *http.Reque
 
 	return &WebhookPayload{
 		EventType:          eventType,
-		EventID:            webhook.Data.ID,
+		EventID:            lemonSqueezyEventID(eventType, webhook.Data.ID, attrs.UpdatedAt, body),
 		EventTime:          attrs.UpdatedAt,
 		SubscriptionID:     webhook.Data.ID,
 		CustomerID:         fmt.Sprintf("%d", attrs.CustomerID),
 		CustomerEmail:      attrs.UserEmail,
+		UserID:             userID,
+		GroupID:            groupID,
+		PromotionCode:      promotionCode,
+		Quantity:           attrs.FirstSubscriptionItem.Quantity,
 		Status:             status,
 		PlanName:           planName,
+		PlanID:             planID,
 		Amount:             priceInfo.UnitPrice,
 		Currency:           priceInfo.Currency,
 		NextBillingDate:    nextBillingDate,
@@ -134,6 +164,19 @@ func (l *LemonSqueezyProvider) ParsePayload(ctx context.Context, req *http.Reque
 	}, nil
 }
 
+// lemonSqueezyEventID returns a per-event key for a Lemon Squeezy webhook. Lemon Squeezy
+// sends no event ID and data.id is the subscription, so the key combines the event name,
+// subscription and the time it was updated. Webhooks without an update time fall back to
+// a hash of the signed body, which provider retries resend unchanged.
+func lemonSqueezyEventID(eventType, subscriptionID, updatedAt string, body []byte) string {
+	if updatedAt != "" {
+		return fmt.Sprintf("%s:%s:%s", eventType, subscriptionID, updatedAt)
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
 // GetSubscriptionInfo retrieves subscription information from Lemon Squeezy's API
 func (l *LemonSqueezyProvider) GetSubscriptionInfo(ctx context.Context, subscriptionID string) (*SubscriptionInfo, error) {
 
@@ -166,6 +209,10 @@ func (l *LemonSqueezyProvider) GetSubscriptionInfo(ctx context.Context, subscrip
 				EndsAt      string `json:"ends_at"`
 				CreatedAt   string `json:"created_at"`
 				UpdatedAt   string `json:"updated_at"`
+
+				FirstSubscriptionItem struct {
+					Quantity int64 `json:"quantity"`
+				} `json:"first_subscription_item"`
 			} `json:"attributes"`
 		} `json:"data"`
 	}
@@ -189,10 +236,269 @@ func (l *LemonSqueezyProvider) GetSubscriptionInfo(ctx context.Context, subscrip
 		Status:          lemonSqueezyStatusToStandard(attrs.Status),
 		PlanName:        planName,
 		PlanID:          fmt.Sprintf("%d", attrs.VariantID),
+		Quantity:        attrs.FirstSubscriptionItem.Quantity,
 		NextBillingDate: attrs.RenewsAt,
 	}, nil
 }
 
+// CreateCheckoutSession creates a Lemon Squeezy checkout for the requested variant in the
+// configured store (VendorID). The platform user is attached as custom checkout data, which
+// Lemon Squeezy returns in the meta of every related webhook.
+//
+// Note: Lemon Squeezy checkouts do not support cancel URLs or ad-hoc trial periods, trials
+// are configured on the variant instead.
+func (l *LemonSqueezyProvider) CreateCheckoutSession(ctx context.Context, req *CheckoutSessionRequest) (*CheckoutSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", l.name)).With(zap.String("operation", "create-checkout-session"))
+
+	logger.Info("handle-request-to-create-checkout-session")
+
+	if req.PriceID == "" {
+		logger.Warn("missing-variant-id-for-checkout-session")
+		return nil, ErrPaymentProviderMissingCheckoutPrice
+	}
+
+	if l.config.VendorID == "" {
+		logger.Error("missing-store-id-for-checkout-session")
+		return nil, ErrPaymentProviderInvalidConfiguration
+	}
+
+	custom := map[string]string{}
+	if req.UserID != "" {
+		custom[MetadataKeyUserID] = req.UserID
+	}
+	if req.GroupID != "" {
+		custom[MetadataKeyGroupID] = req.GroupID
+	}
+	if req.PromotionCode != "" {
+		custom[MetadataKeyPromotionCode] = req.PromotionCode
+	}
+	if req.CustomerEmail != "" {
+		custom[MetadataKeyCustomerEmail] = req.CustomerEmail
+	}
+
+	checkoutData := map[string]interface{}{
+		"custom": custom,
+	}
+	if req.CustomerEmail != "" {
+		checkoutData["email"] = req.CustomerEmail
+	}
+	if req.CouponCode != "" {
+		checkoutData["discount_code"] = req.CouponCode
+	}
+	if variantID, err := strconv.ParseInt(req.PriceID, 10, 64); err == nil && req.Quantity > 1 {
+		checkoutData["variant_quantities"] = []map[string]int64{
+			{"variant_id": variantID, "quantity": req.Quantity},
+		}
+	}
+
+	attributes := map[string]interface{}{
+		"checkout_data": checkoutData,
+	}
+	if req.SuccessURL != "" {
+		attributes["product_options"] = map[string]interface{}{
+			"redirect_url": req.SuccessURL,
+		}
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "checkouts",
+			"attributes": attributes,
+			"relationships": map[string]interface{}{
+				"store": map[string]interface{}{
+					"data": map[string]string{"type": "stores", "id": l.config.VendorID},
+				},
+				"variant": map[string]interface{}{
+					"data": map[string]string{"type": "variants", "id": req.PriceID},
+				},
+			},
+		},
+	})
+	if err != nil {
+		logger.Error("failed-to-encode-checkout-request", zap.Error(err))
+		return nil, ErrPaymentProviderAPIRequestFailed
+	}
+
+	apiURL := l.getAPIBaseURL() + "/v1/checkouts"
+	body, err := l.callLemonSqueezyEndpoint(logger, "POST", apiURL, bytes.NewReader(requestBody), []int{http.StatusCreated})
+	if err != nil {
+		return nil, sessionRequestError(err)
+	}
+
+	var apiResp struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				URL       string `json:"url"`
+				ExpiresAt string `json:"expires_at"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	if apiResp.Data.Attributes.URL == "" {
+		logger.Error("checkout-response-missing-url")
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("created-checkout-session", zap.String("checkout-id", apiResp.Data.ID))
+
+	return &CheckoutSession{
+		ID:        apiResp.Data.ID,
+		URL:       apiResp.Data.Attributes.URL,
+		ExpiresAt: apiResp.Data.Attributes.ExpiresAt,
+	}, nil
+}
+
+// CreateCustomerPortalSession retrieves the pre-signed customer portal URL for the customer
+// from Lemon Squeezy's API. The URL is valid for 24 hours and return URLs are not supported.
+func (l *LemonSqueezyProvider) CreateCustomerPortalSession(ctx context.Context, req *CustomerPortalSessionRequest) (*CustomerPortalSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", l.name)).With(zap.String("operation", "create-customer-portal-session")).With(zap.String("lemonsqueezy-customer-id", req.CustomerID))
+
+	logger.Info("handle-request-to-create-customer-portal-session")
+
+	if req.CustomerID == "" {
+		logger.Warn("missing-customer-id-for-customer-portal-session")
+		return nil, ErrPaymentProviderMissingCustomerID
+	}
+
+	apiURL := l.getAPIBaseURL() + "/v1/customers/" + req.CustomerID
+	body, err := l.callLemonSqueezyEndpoint(logger, "GET", apiURL, nil, []int{http.StatusOK})
+	if err != nil {
+		return nil, sessionRequestError(err)
+	}
+
+	var apiResp struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				URLs struct {
+					CustomerPortal string `json:"customer_portal"`
+				} `json:"urls"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	if apiResp.Data.Attributes.URLs.CustomerPortal == "" {
+		logger.Error("customer-response-missing-customer-portal-url")
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("retrieved-customer-portal-url")
+
+	return &CustomerPortalSession{
+		URL: apiResp.Data.Attributes.URLs.CustomerPortal,
+	}, nil
+}
+
+// GetCatalogProduct retrieves a product from Lemon Squeezy. Products can only be created
+// and changed in the Lemon Squeezy dashboard, so only reading is supported
+func (l *LemonSqueezyProvider) GetCatalogProduct(ctx context.Context, productID string) (*CatalogProduct, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", l.name)).With(zap.String("operation", "get-catalog-product")).With(zap.String("product-id", productID))
+
+	logger.Info("handle-request-to-get-catalog-product")
+
+	apiURL := l.getAPIBaseURL() + "/v1/products/" + url.PathEscape(productID)
+	body, err := l.callLemonSqueezyEndpoint(logger, "GET", apiURL, nil, []int{http.StatusOK})
+	if err != nil {
+		return nil, catalogRequestError(err, ErrPaymentProviderCatalogItemNotFound)
+	}
+
+	var apiResp struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Status      string `json:"status"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response-for-product", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("retrieved-catalog-product")
+
+	return &CatalogProduct{
+		ID:          apiResp.Data.ID,
+		Name:        apiResp.Data.Attributes.Name,
+		Description: apiResp.Data.Attributes.Description,
+		Active:      apiResp.Data.Attributes.Status == "published",
+	}, nil
+}
+
+// GetCatalogPrice retrieves a variant from Lemon Squeezy, which holds the price and
+// billing interval. Currency is left empty as variants are priced in the store currency
+func (l *LemonSqueezyProvider) GetCatalogPrice(ctx context.Context, variantID string) (*CatalogPrice, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", l.name)).With(zap.String("operation", "get-catalog-price")).With(zap.String("variant-id", variantID))
+
+	logger.Info("handle-request-to-get-catalog-price")
+
+	apiURL := l.getAPIBaseURL() + "/v1/variants/" + url.PathEscape(variantID)
+	body, err := l.callLemonSqueezyEndpoint(logger, "GET", apiURL, nil, []int{http.StatusOK})
+	if err != nil {
+		return nil, catalogRequestError(err, ErrPaymentProviderCatalogItemNotFound)
+	}
+
+	var apiResp struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				ProductID      int64  `json:"product_id"`
+				Price          int64  `json:"price"`
+				IsSubscription bool   `json:"is_subscription"`
+				Interval       string `json:"interval"`
+				IntervalCount  int64  `json:"interval_count"`
+				Status         string `json:"status"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response-for-variant", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	price := &CatalogPrice{
+		ID:        apiResp.Data.ID,
+		ProductID: strconv.FormatInt(apiResp.Data.Attributes.ProductID, 10),
+		Amount:    apiResp.Data.Attributes.Price,
+		Active:    apiResp.Data.Attributes.Status == "published",
+	}
+	if apiResp.Data.Attributes.IsSubscription {
+		price.Interval = apiResp.Data.Attributes.Interval
+		price.IntervalCount = apiResp.Data.Attributes.IntervalCount
+	}
+
+	logger.Info("retrieved-catalog-price", zap.Int64("price", price.Amount))
+
+	return price, nil
+}
+
+// getAPIBaseURL returns the configured API base URL or Lemon Squeezy's default
+func (l *LemonSqueezyProvider) getAPIBaseURL() string {
+	if l.config.APIBaseURL != "" {
+		return l.config.APIBaseURL
+	}
+	return "https://api.lemonsqueezy.com"
+}
+
 // getPriceByPriceID retrieves price details by price ID from Lemon Squeezy's API
 func (l *LemonSqueezyProvider) getPriceByPriceID(ctx context.Context, priceID string, quantity int64) (*PriceInfo, error) {
 
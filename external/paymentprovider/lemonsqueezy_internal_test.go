@@ -0,0 +1,20 @@
+package paymentprovider
+
+import "testing"
+
+func TestLemonSqueezyEventIDIsUniquePerEvent(t *testing.T) {
+	created := lemonSqueezyEventID(EventTypeSubscriptionCreated, "sub-1", "2026-01-01T10:00:00.000000Z", []byte(`{"a":1}`))
+	updated := lemonSqueezyEventID(EventTypeSubscriptionUpdated, "sub-1", "2026-01-01T10:00:00.000000Z", []byte(`{"a":1}`))
+	laterUpdate := lemonSqueezyEventID(EventTypeSubscriptionUpdated, "sub-1", "2026-02-01T10:00:00.000000Z", []byte(`{"a":1}`))
+
+	if created == updated || updated == laterUpdate {
+		t.Fatalf("expected distinct events on one subscription to get distinct IDs, got %q, %q and %q", created, updated, laterUpdate)
+	}
+	if retried := lemonSqueezyEventID(EventTypeSubscriptionUpdated, "sub-1", "2026-02-01T10:00:00.000000Z", []byte(`{"a":1}`)); retried != laterUpdate {
+		t.Fatalf("expected a retried event to keep its ID, got %q and %q", retried, laterUpdate)
+	}
+
+	if lemonSqueezyEventID(EventTypeSubscriptionUpdated, "sub-1", "", []byte(`{"a":1}`)) == lemonSqueezyEventID(EventTypeSubscriptionUpdated, "sub-1", "", []byte(`{"a":2}`)) {
+		t.Fatal("expected events without an update time to be keyed by their body")
+	}
+}
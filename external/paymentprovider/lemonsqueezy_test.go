@@ -0,0 +1,167 @@
+package paymentprovider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+func newTestLemonSqueezyProvider(t *testing.T, storeID string, handler http.HandlerFunc) *paymentprovider.LemonSqueezyProvider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := paymentprovider.NewLemonSqueezyProvider(&paymentprovider.Config{
+		ProviderName:  "lemonsqueezy",
+		APIKey:        "ls_test_123",
+		WebhookSecret: "secret",
+		VendorID:      storeID,
+		APIBaseURL:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating provider, got %v", err)
+	}
+
+	return provider
+}
+
+func TestLemonSqueezyCreateCheckoutSession(t *testing.T) {
+	provider := newTestLemonSqueezyProvider(t, "42", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/checkouts" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var body struct {
+			Data struct {
+				Attributes struct {
+					CheckoutData struct {
+						Email        string            `json:"email"`
+						DiscountCode string            `json:"discount_code"`
+						Custom       map[string]string `json:"custom"`
+					} `json:"checkout_data"`
+					ProductOptions struct {
+						RedirectURL string `json:"redirect_url"`
+					} `json:"product_options"`
+				} `json:"attributes"`
+				Relationships struct {
+					Store struct {
+						Data struct {
+							ID string `json:"id"`
+						} `json:"data"`
+					} `json:"store"`
+					Variant struct {
+						Data struct {
+							ID string `json:"id"`
+						} `json:"data"`
+					} `json:"variant"`
+				} `json:"relationships"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("expected json body, got %v", err)
+		}
+
+		attrs := body.Data.Attributes
+		if attrs.CheckoutData.Custom[paymentprovider.MetadataKeyUserID] != "user-1" {
+			t.Errorf("expected user ID in custom data, got %v", attrs.CheckoutData.Custom)
+		}
+		if attrs.CheckoutData.Email != "customer@example.com" || attrs.CheckoutData.DiscountCode != "LAUNCH" {
+			t.Errorf("unexpected checkout data %#v", attrs.CheckoutData)
+		}
+		if attrs.ProductOptions.RedirectURL != "https://app.example.com/success" {
+			t.Errorf("unexpected redirect url %q", attrs.ProductOptions.RedirectURL)
+		}
+		if body.Data.Relationships.Store.Data.ID != "42" || body.Data.Relationships.Variant.Data.ID != "1001" {
+			t.Errorf("unexpected relationships %#v", body.Data.Relationships)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"type":"checkouts","id":"chk-1","attributes":{"url":"https://store.lemonsqueezy.com/checkout/custom/chk-1","expires_at":null}}}`))
+	})
+
+	session, err := provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{
+		PriceID:       "1001",
+		UserID:        "user-1",
+		CustomerEmail: "customer@example.com",
+		SuccessURL:    "https://app.example.com/success",
+		CouponCode:    "LAUNCH",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.ID != "chk-1" || session.URL != "https://store.lemonsqueezy.com/checkout/custom/chk-1" {
+		t.Fatalf("unexpected session %#v", session)
+	}
+}
+
+func TestLemonSqueezyCreateCheckoutSessionRequiresStore(t *testing.T) {
+	provider := newTestLemonSqueezyProvider(t, "", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no request to be made")
+	})
+
+	_, err := provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{PriceID: "1001"})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderInvalidConfiguration) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderInvalidConfiguration, err)
+	}
+}
+
+func TestLemonSqueezyCreateCustomerPortalSession(t *testing.T) {
+	provider := newTestLemonSqueezyProvider(t, "42", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/customers/7" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{"data":{"type":"customers","id":"7","attributes":{"urls":{"customer_portal":"https://store.lemonsqueezy.com/billing?expires=1"}}}}`))
+	})
+
+	session, err := provider.CreateCustomerPortalSession(context.Background(), &paymentprovider.CustomerPortalSessionRequest{CustomerID: "7"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.URL != "https://store.lemonsqueezy.com/billing?expires=1" {
+		t.Fatalf("unexpected session %#v", session)
+	}
+
+	_, err = provider.CreateCustomerPortalSession(context.Background(), &paymentprovider.CustomerPortalSessionRequest{CustomerID: "8"})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderCheckoutRequestFailed) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderCheckoutRequestFailed, err)
+	}
+}
+
+func TestLemonSqueezyGetCatalogPrice(t *testing.T) {
+	provider := newTestLemonSqueezyProvider(t, "store-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/variants/42" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Write([]byte(`{"data":{"type":"variants","id":"42","attributes":{"product_id":7,"price":999,"is_subscription":true,"interval":"month","interval_count":1,"status":"published"}}}`))
+	})
+
+	price, err := provider.GetCatalogPrice(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if price.ProductID != "7" || price.Amount != 999 || price.Interval != "month" || !price.Active || price.Currency != "" {
+		t.Fatalf("unexpected price %+v", price)
+	}
+}
+
+func TestProviderRegistryCatalogWritesRequireSupport(t *testing.T) {
+	provider := newTestLemonSqueezyProvider(t, "store-1", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	registry := paymentprovider.NewProviderRegistry()
+	registry.Register(provider)
+
+	if _, err := registry.CreateCatalogPrice(context.Background(), "lemonsqueezy", &paymentprovider.CatalogPrice{}); !errors.Is(err, paymentprovider.ErrPaymentProviderCatalogWriteNotSupported) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderCatalogWriteNotSupported, err)
+	}
+}
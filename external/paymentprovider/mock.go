@@ -95,3 +95,35 @@ func (m *MockProvider) GetSubscriptionInfo(ctx context.Context, subscriptionID s
 
 	return &info, nil
 }
+
+// CreateCheckoutSession returns a mock checkout session for the requested price
+func (m *MockProvider) CreateCheckoutSession(ctx context.Context, req *CheckoutSessionRequest) (*CheckoutSession, error) {
+	if m.shouldFail {
+		return nil, ErrPaymentProviderCheckoutRequestFailed
+	}
+
+	if req.PriceID == "" {
+		return nil, ErrPaymentProviderMissingCheckoutPrice
+	}
+
+	return &CheckoutSession{
+		ID:  "mock-checkout-123",
+		URL: "https://checkout.example.com/mock-checkout-123?price=" + req.PriceID,
+	}, nil
+}
+
+// CreateCustomerPortalSession returns a mock customer portal session for the customer
+func (m *MockProvider) CreateCustomerPortalSession(ctx context.Context, req *CustomerPortalSessionRequest) (*CustomerPortalSession, error) {
+	if m.shouldFail {
+		return nil, ErrPaymentProviderCheckoutRequestFailed
+	}
+
+	if req.CustomerID == "" {
+		return nil, ErrPaymentProviderMissingCustomerID
+	}
+
+	return &CustomerPortalSession{
+		ID:  "mock-portal-123",
+		URL: "https://portal.example.com/" + req.CustomerID,
+	}, nil
+}
@@ -1,5 +1,7 @@
 package paymentprovider
 
+import "time"
+
 // WebhookPayload represents normalized webhook data from any payment provider
 // This common structure allows the billing system to handle webhooks uniformly
 type WebhookPayload struct {
@@ -33,6 +35,21 @@ type WebhookPayload struct {
 	// CustomerName is the customer's display name (e.g., for Ko-fi donations)
 	CustomerName string
 
+	// UserID is the platform user ID attached as metadata when the checkout session
+	// was created. Empty when the purchase did not start from a platform checkout
+	UserID string
+
+	// GroupID is the platform group ID attached as metadata when the checkout session
+	// was created for a group. Empty for subscriptions owned by a single user
+	GroupID string
+
+	// PromotionCode is the platform promotion code attached as metadata when the checkout
+	// session was created. Empty when no promotion code was used
+	PromotionCode string
+
+	// Quantity is the number of units (e.g., seats) on the subscription. Zero when not available
+	Quantity int64
+
 	// Status is the current status of the subscription (active, cancelled, past_due, trialing, etc.)
 	// Empty for one-off payments
 	Status string
@@ -40,6 +57,10 @@ type WebhookPayload struct {
 	// PlanName is the name/identifier of the subscription plan or tier
 	PlanName string
 
+	// PlanID is the provider's identifier for the purchased price or variant, used to
+	// match the subscription to a price plan's provider refs. Empty when not available
+	PlanID string
+
 	// Amount is the payment amount (in the smallest currency unit, e.g., cents)
 	Amount int64
 
@@ -99,6 +120,9 @@ type SubscriptionInfo struct {
 	// BillingInterval is the billing frequency (e.g., "month", "year")
 	BillingInterval string
 
+	// Quantity is the number of units (e.g., seats) on the subscription. Zero when not available
+	Quantity int64
+
 	// NextBillingDate is when the next payment will be attempted
 	NextBillingDate string
 
@@ -126,3 +150,211 @@ type PriceInfo struct {
 	// Currency is the currency code (e.g. USD)
 	Currency string
 }
+
+// CheckoutSessionRequest holds everything needed to create a hosted checkout
+// session with a payment provider
+type CheckoutSessionRequest struct {
+	// PriceID is the provider's identifier for the price being purchased
+	// (e.g., Stripe price ID, Lemon Squeezy variant ID)
+	PriceID string
+
+	// Quantity is the number of units being purchased. Defaults to 1
+	Quantity int64
+
+	// UserID is the platform user ID, attached to the checkout and resulting
+	// subscription as metadata so webhooks can be linked back to the user
+	UserID string
+
+	// GroupID is the platform group the subscription is bought for, if any. It is
+	// attached as metadata so the resulting subscription is owned by the group
+	GroupID string
+
+	// CustomerEmail is used to prefill the checkout and is also attached as metadata
+	CustomerEmail string
+
+	// CustomerID is the provider's customer identifier, if the user already has one
+	CustomerID string
+
+	// SuccessURL is where the customer is sent after completing the checkout
+	SuccessURL string
+
+	// CancelURL is where the customer is sent if they abandon the checkout
+	// (not supported by every provider)
+	CancelURL string
+
+	// TrialPeriodDays is the number of trial days to apply to the subscription
+	// (not supported by every provider)
+	TrialPeriodDays int
+
+	// CouponCode is the provider's coupon or discount code to apply
+	CouponCode string
+
+	// PromotionCode is the platform promotion code behind CouponCode, if any. It is
+	// attached as metadata so the code can be redeemed against the resulting subscription
+	PromotionCode string
+}
+
+// CheckoutSession represents a hosted checkout session created with a payment provider
+type CheckoutSession struct {
+	// ID is the provider's identifier for the checkout session
+	ID string `json:"id"`
+
+	// URL is the hosted checkout page the customer should be redirected to
+	URL string `json:"url"`
+
+	// ExpiresAt is when the checkout session expires (RFC3339 format), if known
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// CustomerPortalSessionRequest holds everything needed to create a customer
+// portal session with a payment provider
+type CustomerPortalSessionRequest struct {
+	// CustomerID is the provider's identifier for the customer
+	CustomerID string
+
+	// ReturnURL is where the customer is sent when leaving the portal
+	// (not supported by every provider)
+	ReturnURL string
+}
+
+// CustomerPortalSession represents a customer portal session created with a payment provider
+type CustomerPortalSession struct {
+	// ID is the provider's identifier for the portal session, if any
+	ID string `json:"id,omitempty"`
+
+	// URL is the portal page the customer should be redirected to
+	URL string `json:"url"`
+}
+
+// UsageReportRequest holds everything needed to report metered usage to a
+// payment provider
+type UsageReportRequest struct {
+	// EventName is the provider's meter event name the usage is recorded against
+	// (e.g., Stripe billing meter event name)
+	EventName string
+
+	// CustomerID is the provider's identifier for the customer being billed
+	CustomerID string
+
+	// Quantity is the amount of usage to report
+	Quantity int64
+
+	// Identifier uniquely identifies the usage, so the provider can ignore
+	// duplicate reports
+	Identifier string
+
+	// Timestamp is when the usage occurred. Defaults to now
+	Timestamp time.Time
+}
+
+// UsageReport represents usage accepted by a payment provider
+type UsageReport struct {
+	// Identifier is the provider's identifier for the recorded usage
+	Identifier string `json:"identifier"`
+}
+
+// CatalogProduct represents a product in a payment provider's catalog
+type CatalogProduct struct {
+	// ID is the provider's identifier for the product. Empty when it is yet to be created
+	ID string `json:"id,omitempty"`
+
+	// Name is the product name shown to customers
+	Name string `json:"name"`
+
+	// Description is the product description shown to customers
+	Description string `json:"description,omitempty"`
+
+	// Active is whether the product can be bought
+	Active bool `json:"active"`
+
+	// Metadata is attached to the product on the provider
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// CatalogPrice represents a price in a payment provider's catalog
+type CatalogPrice struct {
+	// ID is the provider's identifier for the price (for Lemon Squeezy, the variant).
+	// Empty when it is yet to be created
+	ID string `json:"id,omitempty"`
+
+	// ProductID is the provider's identifier for the product the price belongs to
+	ProductID string `json:"product_id,omitempty"`
+
+	// Amount is the unit price in the smallest currency unit
+	Amount int64 `json:"amount"`
+
+	// Currency is the ISO 4217 currency code. Empty when the provider prices in its
+	// store currency
+	Currency string `json:"currency,omitempty"`
+
+	// Interval is how often the price is charged (day, week, month or year). Empty
+	// for one-off prices
+	Interval string `json:"interval,omitempty"`
+
+	// IntervalCount is the number of intervals between charges
+	IntervalCount int64 `json:"interval_count,omitempty"`
+
+	// Active is whether the price can be used for new purchases
+	Active bool `json:"active"`
+
+	// Metadata is attached to the price on the provider
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// SubscriptionChangeRequest holds everything needed to cancel or pause a subscription
+// with a payment provider
+type SubscriptionChangeRequest struct {
+	// SubscriptionID is the provider's identifier for the subscription
+	SubscriptionID string
+
+	// AtPeriodEnd is whether the change takes effect at the end of the current billing
+	// period rather than straight away
+	AtPeriodEnd bool
+}
+
+// SubscriptionChange represents a cancellation or pause accepted by a payment provider
+type SubscriptionChange struct {
+	// SubscriptionID is the provider's identifier for the subscription
+	SubscriptionID string `json:"subscription_id"`
+
+	// Status is the normalised status of the subscription once the request was accepted.
+	// It is unchanged until the change takes effect when it is scheduled for the period end
+	Status string `json:"status"`
+
+	// EffectiveAt is when the change takes effect (RFC3339). Empty when the provider
+	// did not say
+	EffectiveAt string `json:"effective_at,omitempty"`
+}
+
+// RefundRequest holds everything needed to refund a payment with a payment provider
+type RefundRequest struct {
+	// TransactionID is the provider's identifier for the payment being refunded
+	// (e.g., Stripe payment intent or charge, Paddle transaction)
+	TransactionID string
+
+	// Amount is how much to refund in the smallest currency unit. Zero refunds the
+	// payment in full
+	Amount int64
+
+	// Reason is why the payment is being refunded, it is passed to the provider
+	Reason string
+}
+
+// Refund represents a refund accepted by a payment provider
+type Refund struct {
+	// ID is the provider's identifier for the refund
+	ID string `json:"id"`
+
+	// TransactionID is the provider's identifier for the refunded payment
+	TransactionID string `json:"transaction_id"`
+
+	// Amount is the amount refunded in the smallest currency unit. Zero when the
+	// provider has yet to calculate the refund
+	Amount int64 `json:"amount"`
+
+	// Currency is the ISO 4217 currency code of the refund
+	Currency string `json:"currency,omitempty"`
+
+	// Status is the provider's status for the refund (e.g., pending, succeeded)
+	Status string `json:"status"`
+}
@@ -0,0 +1,117 @@
+package paymentprovider
+
+// PaddleWebhookPayload represents the envelope of a Paddle Billing notification
+//
+// Example Paddle Billing Notification
+//
+//	{
+//	  "event_id": "evt_01hv8wptq8987qeep44cyrewp9",
+//	  "event_type": "subscription.created",
+//	  "occurred_at": "2026-04-12T10:18:49.621022Z",
+//	  "notification_id": "ntf_01hv8wptvd7dd8xkg40mwtp0ke",
+//	  "data": {
+//	    "id": "sub_01hv8x29kz0t586xy6zn1a62ny",
+//	    "status": "active",
+//	    "customer_id": "ctm_01hv6y1jedq4p1n0yqn5ba3ky4",
+//	    ...
+//	  }
+//	}
+type PaddleWebhookPayload struct {
+	EventID        string            `json:"event_id"`
+	EventType      string            `json:"event_type"`
+	OccurredAt     string            `json:"occurred_at"`
+	NotificationID string            `json:"notification_id"`
+	Data           PaddleWebhookData `json:"data"`
+}
+
+// PaddleWebhookData holds the fields used from the subscription or transaction entity
+// carried by a Paddle Billing notification. Subscription and transaction entities share
+// most fields, so one structure is used for both
+type PaddleWebhookData struct {
+	ID                   string                  `json:"id"`
+	Status               string                  `json:"status"`
+	CustomerID           string                  `json:"customer_id"`
+	SubscriptionID       string                  `json:"subscription_id"`
+	CurrencyCode         string                  `json:"currency_code"`
+	Origin               string                  `json:"origin"`
+	CustomData           map[string]interface{}  `json:"custom_data"`
+	Items                []PaddleItem            `json:"items"`
+	NextBilledAt         string                  `json:"next_billed_at"`
+	CanceledAt           string                  `json:"canceled_at"`
+	PausedAt             string                  `json:"paused_at"`
+	BilledAt             string                  `json:"billed_at"`
+	CurrentBillingPeriod *PaddleBillingPeriod    `json:"current_billing_period"`
+	BillingPeriod        *PaddleBillingPeriod    `json:"billing_period"`
+	BillingCycle         *PaddleBillingCycle     `json:"billing_cycle"`
+	ScheduledChange      *PaddleScheduledChange  `json:"scheduled_change"`
+	ManagementURLs       *PaddleManagementURLs   `json:"management_urls"`
+	Details              *PaddleTransactionTotal `json:"details"`
+}
+
+// PaddleItem represents a subscription or transaction line item
+type PaddleItem struct {
+	Quantity int64          `json:"quantity"`
+	Price    PaddlePrice    `json:"price"`
+	Product  *PaddleProduct `json:"product"`
+}
+
+// PaddlePrice represents a Paddle price
+type PaddlePrice struct {
+	ID           string              `json:"id"`
+	ProductID    string              `json:"product_id"`
+	Name         string              `json:"name"`
+	Description  string              `json:"description"`
+	UnitPrice    PaddleMoney         `json:"unit_price"`
+	BillingCycle *PaddleBillingCycle `json:"billing_cycle"`
+}
+
+// PaddleProduct represents a Paddle product
+type PaddleProduct struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PaddleMoney represents an amount in the lowest denomination of the currency. Paddle
+// sends amounts as strings
+type PaddleMoney struct {
+	Amount       string `json:"amount"`
+	CurrencyCode string `json:"currency_code"`
+}
+
+// PaddleBillingPeriod represents the start and end of a billing period
+type PaddleBillingPeriod struct {
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+// PaddleBillingCycle represents how often a subscription renews
+type PaddleBillingCycle struct {
+	Interval  string `json:"interval"`
+	Frequency int64  `json:"frequency"`
+}
+
+// PaddleScheduledChange represents a change that takes effect on a subscription at a later date
+type PaddleScheduledChange struct {
+	Action      string `json:"action"`
+	EffectiveAt string `json:"effective_at"`
+	ResumeAt    string `json:"resume_at"`
+}
+
+// PaddleManagementURLs holds the customer facing links to manage a subscription
+type PaddleManagementURLs struct {
+	UpdatePaymentMethod string `json:"update_payment_method"`
+	Cancel              string `json:"cancel"`
+}
+
+// PaddleTransactionTotal holds the totals and line items of a transaction
+type PaddleTransactionTotal struct {
+	Totals struct {
+		GrandTotal   string `json:"grand_total"`
+		CurrencyCode string `json:"currency_code"`
+	} `json:"totals"`
+	LineItems []struct {
+		PriceID  string         `json:"price_id"`
+		Quantity int64          `json:"quantity"`
+		Product  *PaddleProduct `json:"product"`
+	} `json:"line_items"`
+}
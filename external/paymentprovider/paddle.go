@@ -0,0 +1,793 @@
+package paymentprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// paddleWebhookToleranceInSeconds is how far a Paddle-Signature timestamp may be from now
+// before the webhook is rejected, to prevent replay attacks
+const paddleWebhookToleranceInSeconds int64 = 300
+
+// PaddleProvider implements the Provider interface for Paddle Billing
+type PaddleProvider struct {
+	config *Config
+	name   string
+}
+
+// NewPaddleProvider creates a new Paddle Billing payment provider. The webhook secret is
+// the notification destination's secret key, and the API key is a Paddle Billing API key
+func NewPaddleProvider(config *Config) (*PaddleProvider, error) {
+	if config.WebhookSecret == "" {
+		return nil, ErrPaymentProviderInvalidConfigWebhookSecret
+	}
+
+	return &PaddleProvider{
+		config: config,
+		name:   "paddle",
+	}, nil
+}
+
+// GetProviderName returns the name of the provider, i.e "paddle"
+func (p *PaddleProvider) GetProviderName() string {
+	return p.name
+}
+
+// VerifyWebhook verifies the Paddle-Signature header of a Paddle Billing notification. The
+// request body is restored afterwards so the payload can still be parsed
+func (p *PaddleProvider) VerifyWebhook(ctx context.Context, req *http.Request) error {
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "verify-webhook"))
+
+	logger.Debug("verifying-paddle-webhook")
+
+	signature := req.Header.Get("Paddle-Signature")
+	if signature == "" {
+		logger.Error("missing-signature-from-webhook")
+		return ErrPaymentProviderMissingSignature
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		logger.Error("failed-to-read-webhook-body", zap.Error(err))
+		return ErrPaymentProviderInvalidPayload
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Parse the signature header, Paddle sends several h1 signatures while a secret is rotated
+	var timestamp string
+	var h1Signatures []string
+
+	for _, part := range strings.Split(signature, ";") {
+		keyValue := strings.SplitN(part, "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		switch strings.TrimSpace(keyValue[0]) {
+		case "ts":
+			timestamp = strings.TrimSpace(keyValue[1])
+		case "h1":
+			h1Signatures = append(h1Signatures, strings.TrimSpace(keyValue[1]))
+		}
+	}
+
+	if timestamp == "" || len(h1Signatures) == 0 {
+		logger.Error("missing-timestamp-or-signature-from-webhook")
+		return ErrPaymentProviderInvalidWebhookSignature
+	}
+
+	timestampInt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		logger.Error("invalid-timestamp-in-signature", zap.Error(err))
+		return ErrPaymentProviderInvalidWebhookSignature
+	}
+
+	age := time.Now().Unix() - timestampInt
+	if age > paddleWebhookToleranceInSeconds {
+		logger.Error("timestamp-too-old", zap.Int64("timestamp", timestampInt), zap.Int64("allowed-age-in-seconds", paddleWebhookToleranceInSeconds))
+		return ErrPaymentProviderWebhookTimestampTooOld
+	}
+	if age < -paddleWebhookToleranceInSeconds {
+		logger.Error("timestamp-in-the-future", zap.Int64("timestamp", timestampInt), zap.Int64("allowed-age-in-seconds", paddleWebhookToleranceInSeconds))
+		return ErrPaymentProviderInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.config.WebhookSecret))
+	mac.Write([]byte(timestamp + ":" + string(body)))
+	expectedSignature := hex.EncodeToString(mac.Sum(nil))
+
+	for _, h1Signature := range h1Signatures {
+		if hmac.Equal([]byte(expectedSignature), []byte(h1Signature)) {
+			logger.Debug("paddle-webhook-verified-successfully")
+			return nil
+		}
+	}
+
+	logger.Error("invalid-signature", zap.Int("received-signatures", len(h1Signatures)))
+	return ErrPaymentProviderInvalidWebhookSignature
+}
+
+// ParsePayload extracts and normalises Paddle Billing subscription and transaction notifications
+func (p *PaddleProvider) ParsePayload(ctx context.Context, req *http.Request) (*WebhookPayload, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "parse-payload"))
+
+	logger.Debug("parsing-paddle-webhook-payload")
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		logger.Error("failed-to-parse-webhook-payload", zap.Error(err))
+		return nil, ErrPaymentProviderInvalidPayload
+	}
+
+	var webhook PaddleWebhookPayload
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		logger.Error("failed-to-parse-webhook-payload", zap.Error(err))
+		return nil, ErrPaymentProviderPayloadParsing
+	}
+
+	data := webhook.Data
+	eventType := paddleEventToStandard(webhook.EventType)
+	planID, planName := paddlePlan(&data)
+
+	payload := &WebhookPayload{
+		EventType:      eventType,
+		EventID:        webhook.EventID,
+		EventTime:      formatPaddleTime(webhook.OccurredAt),
+		PaymentType:    PaymentTypeSubscription,
+		SubscriptionID: data.ID,
+		CustomerID:     data.CustomerID,
+		UserID:         getStringField(data.CustomData, MetadataKeyUserID),
+		GroupID:        getStringField(data.CustomData, MetadataKeyGroupID),
+		PromotionCode:  getStringField(data.CustomData, MetadataKeyPromotionCode),
+		Quantity:       paddleQuantity(&data),
+		PlanName:       planName,
+		PlanID:         planID,
+		Amount:         paddleAmount(&data),
+		Currency:       strings.ToUpper(data.CurrencyCode),
+		RawPayload:     string(body),
+	}
+
+	switch {
+	case strings.HasPrefix(webhook.EventType, "transaction."):
+		payload.TransactionID = data.ID
+		payload.SubscriptionID = data.SubscriptionID
+		payload.Status = paddleTransactionEventToStatus(webhook.EventType)
+
+		switch {
+		case data.SubscriptionID == "":
+			payload.PaymentType = PaymentTypeShopOrder
+			payload.IsOneOff = true
+		case payload.Status == "":
+			// Other transaction notifications do not say anything about the subscription,
+			// so they are recorded without updating it
+			payload.PaymentType = ""
+		default:
+			// Transactions created by a checkout start the subscription, renewals have a subscription origin
+			payload.IsFirstSubscriptionPayment = data.Origin == "web" || data.Origin == "api"
+		}
+
+		if data.BillingPeriod != nil {
+			payload.NextBillingDate = formatPaddleTime(data.BillingPeriod.EndsAt)
+			payload.AvailableUntilDate = payload.NextBillingDate
+		}
+	case strings.HasPrefix(webhook.EventType, "subscription."):
+		payload.Status = paddleStatusToStandard(data.Status)
+		payload.NextBillingDate = formatPaddleTime(data.NextBilledAt)
+		payload.AvailableUntilDate = paddleAvailableUntilDate(&data)
+
+		if data.ManagementURLs != nil {
+			payload.CancelURL = data.ManagementURLs.Cancel
+			payload.UpdateURL = data.ManagementURLs.UpdatePaymentMethod
+		}
+	default:
+		// Other notifications, such as customer or adjustment events, are recorded as they are
+		payload.PaymentType = ""
+		payload.SubscriptionID = ""
+	}
+
+	if data.CustomerID != "" {
+		email, err := p.getCustomerEmailByCustomerID(ctx, data.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		payload.CustomerEmail = email
+	}
+
+	logger.Debug("parsed-paddle-webhook-payload",
+		zap.String("raw-event-type", webhook.EventType),
+		zap.String("event-type", eventType),
+		zap.Bool("email-present", emailPresentForLog(payload.CustomerEmail)),
+		zap.String("email-domain", emailDomainForLog(payload.CustomerEmail)),
+	)
+
+	return payload, nil
+}
+
+// GetSubscriptionInfo retrieves subscription information from Paddle's API
+func (p *PaddleProvider) GetSubscriptionInfo(ctx context.Context, subscriptionID string) (*SubscriptionInfo, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "get-subscription-info"))
+
+	logger.Info("handle-request-to-get-subscription-information")
+
+	apiURL := p.getAPIBaseURL() + "/subscriptions/" + subscriptionID
+	body, err := p.callPaddleEndpoint(logger, "GET", apiURL, nil, []int{http.StatusOK})
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp struct {
+		Data PaddleWebhookData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	data := apiResp.Data
+	planID, planName := paddlePlan(&data)
+
+	info := &SubscriptionInfo{
+		SubscriptionID:  data.ID,
+		CustomerID:      data.CustomerID,
+		Status:          paddleStatusToStandard(data.Status),
+		PlanName:        planName,
+		PlanID:          planID,
+		Quantity:        paddleQuantity(&data),
+		Amount:          float64(paddleAmount(&data)),
+		Currency:        strings.ToUpper(data.CurrencyCode),
+		NextBillingDate: formatPaddleTime(data.NextBilledAt),
+		CancelledAt:     formatPaddleTime(data.CanceledAt),
+	}
+
+	if data.BillingCycle != nil {
+		info.BillingInterval = data.BillingCycle.Interval
+	}
+
+	if data.CurrentBillingPeriod != nil {
+		info.CurrentPeriodStart = formatPaddleTime(data.CurrentBillingPeriod.StartsAt)
+		info.CurrentPeriodEnd = formatPaddleTime(data.CurrentBillingPeriod.EndsAt)
+	}
+
+	if data.ManagementURLs != nil {
+		info.CancelURL = data.ManagementURLs.Cancel
+		info.UpdateURL = data.ManagementURLs.UpdatePaymentMethod
+	}
+
+	logger.Info("retrieved-subscription-info")
+
+	return info, nil
+}
+
+// CreateCheckoutSession creates a Paddle transaction for the requested price and returns
+// its checkout link. The platform user is attached as custom data, which Paddle copies to
+// the resulting subscription and returns in every related webhook.
+//
+// Note: Paddle checkouts open on the account's default payment link, where redirects are
+// handled by Paddle.js, so success and cancel URLs are not supported. Trials are configured
+// on the price, and CouponCode must be a Paddle discount ID.
+func (p *PaddleProvider) CreateCheckoutSession(ctx context.Context, req *CheckoutSessionRequest) (*CheckoutSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "create-checkout-session"))
+
+	logger.Info("handle-request-to-create-checkout-session")
+
+	if req.PriceID == "" {
+		logger.Warn("missing-price-id-for-checkout-session")
+		return nil, ErrPaymentProviderMissingCheckoutPrice
+	}
+
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	customData := map[string]string{}
+	if req.UserID != "" {
+		customData[MetadataKeyUserID] = req.UserID
+	}
+	if req.GroupID != "" {
+		customData[MetadataKeyGroupID] = req.GroupID
+	}
+	if req.PromotionCode != "" {
+		customData[MetadataKeyPromotionCode] = req.PromotionCode
+	}
+	if req.CustomerEmail != "" {
+		customData[MetadataKeyCustomerEmail] = req.CustomerEmail
+	}
+
+	transaction := map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"price_id": req.PriceID, "quantity": quantity},
+		},
+		"collection_mode": "automatic",
+	}
+	if len(customData) > 0 {
+		transaction["custom_data"] = customData
+	}
+	if req.CustomerID != "" {
+		transaction["customer_id"] = req.CustomerID
+	}
+	if req.CouponCode != "" {
+		transaction["discount_id"] = req.CouponCode
+	}
+
+	requestBody, err := json.Marshal(transaction)
+	if err != nil {
+		logger.Error("failed-to-marshal-checkout-request", zap.Error(err))
+		return nil, ErrPaymentProviderAPIRequestFailed
+	}
+
+	apiURL := p.getAPIBaseURL() + "/transactions"
+	body, err := p.callPaddleEndpoint(logger, "POST", apiURL, bytes.NewReader(requestBody), []int{http.StatusOK, http.StatusCreated})
+	if err != nil {
+		return nil, sessionRequestError(err)
+	}
+
+	var apiResp struct {
+		Data struct {
+			ID       string `json:"id"`
+			Checkout *struct {
+				URL string `json:"url"`
+			} `json:"checkout"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	if apiResp.Data.Checkout == nil || apiResp.Data.Checkout.URL == "" {
+		logger.Error("transaction-response-missing-checkout-url")
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("created-checkout-session", zap.String("transaction-id", apiResp.Data.ID))
+
+	return &CheckoutSession{
+		ID:  apiResp.Data.ID,
+		URL: apiResp.Data.Checkout.URL,
+	}, nil
+}
+
+// CreateCustomerPortalSession creates a Paddle customer portal session for the customer. Return
+// URLs are not supported
+func (p *PaddleProvider) CreateCustomerPortalSession(ctx context.Context, req *CustomerPortalSessionRequest) (*CustomerPortalSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "create-customer-portal-session")).With(zap.String("paddle-customer-id", req.CustomerID))
+
+	logger.Info("handle-request-to-create-customer-portal-session")
+
+	if req.CustomerID == "" {
+		logger.Warn("missing-customer-id-for-customer-portal-session")
+		return nil, ErrPaymentProviderMissingCustomerID
+	}
+
+	apiURL := p.getAPIBaseURL() + "/customers/" + req.CustomerID + "/portal-sessions"
+	body, err := p.callPaddleEndpoint(logger, "POST", apiURL, strings.NewReader("{}"), []int{http.StatusOK, http.StatusCreated})
+	if err != nil {
+		return nil, sessionRequestError(err)
+	}
+
+	var apiResp struct {
+		Data struct {
+			ID   string `json:"id"`
+			URLs struct {
+				General struct {
+					Overview string `json:"overview"`
+				} `json:"general"`
+			} `json:"urls"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	if apiResp.Data.URLs.General.Overview == "" {
+		logger.Error("customer-portal-session-response-missing-url")
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("created-customer-portal-session")
+
+	return &CustomerPortalSession{
+		ID:  apiResp.Data.ID,
+		URL: apiResp.Data.URLs.General.Overview,
+	}, nil
+}
+
+// CancelSubscription cancels the Paddle subscription, straight away or from its next billing
+// period. A cancellation at the period end is returned by Paddle as a scheduled change
+func (p *PaddleProvider) CancelSubscription(ctx context.Context, req *SubscriptionChangeRequest) (*SubscriptionChange, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "cancel-subscription")).With(zap.String("paddle-subscription-id", req.SubscriptionID)).With(zap.Bool("at-period-end", req.AtPeriodEnd))
+
+	logger.Info("handle-request-to-cancel-subscription")
+
+	change, err := p.changeSubscription(logger, "cancel", req)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("cancelled-subscription", zap.String("status", change.Status))
+
+	return change, nil
+}
+
+// PauseSubscription pauses the Paddle subscription, straight away or from its next billing
+// period. Paused subscriptions stay paused until they are resumed
+func (p *PaddleProvider) PauseSubscription(ctx context.Context, req *SubscriptionChangeRequest) (*SubscriptionChange, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "pause-subscription")).With(zap.String("paddle-subscription-id", req.SubscriptionID)).With(zap.Bool("at-period-end", req.AtPeriodEnd))
+
+	logger.Info("handle-request-to-pause-subscription")
+
+	change, err := p.changeSubscription(logger, "pause", req)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("paused-subscription", zap.String("status", change.Status))
+
+	return change, nil
+}
+
+// RefundPayment creates a refund adjustment for a completed Paddle transaction. Paddle only
+// refunds part of a transaction by line item, so only full refunds are supported. Refunds
+// are approved by Paddle before they are paid out, so the refund starts as pending
+func (p *PaddleProvider) RefundPayment(ctx context.Context, req *RefundRequest) (*Refund, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "refund-payment")).With(zap.String("paddle-transaction-id", req.TransactionID)).With(zap.Int64("amount", req.Amount))
+
+	logger.Info("handle-request-to-refund-payment")
+
+	if req.TransactionID == "" {
+		logger.Warn("missing-transaction-id-for-refund")
+		return nil, ErrPaymentProviderMissingTransactionID
+	}
+
+	if req.Amount > 0 {
+		logger.Warn("partial-refund-not-supported")
+		return nil, ErrPaymentProviderPartialRefundNotSupported
+	}
+
+	// Paddle requires a reason for every adjustment
+	reason := req.Reason
+	if reason == "" {
+		reason = "Refunded by support"
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"action":         "refund",
+		"type":           "full",
+		"transaction_id": req.TransactionID,
+		"reason":         reason,
+	})
+	if err != nil {
+		logger.Error("failed-to-build-refund-request", zap.Error(err))
+		return nil, ErrPaymentProviderAPIRequestFailed
+	}
+
+	apiURL := p.getAPIBaseURL() + "/adjustments"
+	body, err := p.callPaddleEndpoint(logger, "POST", apiURL, bytes.NewReader(requestBody), []int{http.StatusOK, http.StatusCreated})
+	if err != nil {
+		return nil, refundRequestError(err)
+	}
+
+	var apiResp struct {
+		Data struct {
+			ID            string `json:"id"`
+			TransactionID string `json:"transaction_id"`
+			Status        string `json:"status"`
+			CurrencyCode  string `json:"currency_code"`
+			Totals        struct {
+				Total string `json:"total"`
+			} `json:"totals"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	amount, _ := strconv.ParseInt(apiResp.Data.Totals.Total, 10, 64)
+
+	refund := &Refund{
+		ID:            apiResp.Data.ID,
+		TransactionID: apiResp.Data.TransactionID,
+		Amount:        amount,
+		Currency:      strings.ToUpper(apiResp.Data.CurrencyCode),
+		Status:        apiResp.Data.Status,
+	}
+
+	logger.Info("refunded-payment", zap.String("refund-id", refund.ID), zap.String("status", refund.Status))
+
+	return refund, nil
+}
+
+// changeSubscription calls the Paddle subscription action (cancel or pause), effective
+// straight away or from the next billing period
+func (p *PaddleProvider) changeSubscription(logger *zap.Logger, action string, req *SubscriptionChangeRequest) (*SubscriptionChange, error) {
+
+	if req.SubscriptionID == "" {
+		logger.Warn("missing-subscription-id-for-subscription-change")
+		return nil, ErrPaymentProviderMissingSubscriptionID
+	}
+
+	effectiveFrom := "immediately"
+	if req.AtPeriodEnd {
+		effectiveFrom = "next_billing_period"
+	}
+
+	requestBody, err := json.Marshal(map[string]string{"effective_from": effectiveFrom})
+	if err != nil {
+		logger.Error("failed-to-build-subscription-change-request", zap.Error(err))
+		return nil, ErrPaymentProviderAPIRequestFailed
+	}
+
+	apiURL := p.getAPIBaseURL() + "/subscriptions/" + req.SubscriptionID + "/" + action
+	body, err := p.callPaddleEndpoint(logger, "POST", apiURL, bytes.NewReader(requestBody), []int{http.StatusOK})
+	if err != nil {
+		return nil, subscriptionChangeRequestError(err)
+	}
+
+	var apiResp struct {
+		Data PaddleWebhookData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	change := &SubscriptionChange{
+		SubscriptionID: apiResp.Data.ID,
+		Status:         paddleStatusToStandard(apiResp.Data.Status),
+	}
+
+	switch {
+	case apiResp.Data.ScheduledChange != nil:
+		change.EffectiveAt = formatPaddleTime(apiResp.Data.ScheduledChange.EffectiveAt)
+	case action == "cancel":
+		change.EffectiveAt = formatPaddleTime(apiResp.Data.CanceledAt)
+	case action == "pause":
+		change.EffectiveAt = formatPaddleTime(apiResp.Data.PausedAt)
+	}
+
+	return change, nil
+}
+
+// getAPIBaseURL returns the configured API base URL or Paddle's default for the environment
+func (p *PaddleProvider) getAPIBaseURL() string {
+	if p.config.APIBaseURL != "" {
+		return p.config.APIBaseURL
+	}
+	if p.config.IsSandbox() {
+		return "https://sandbox-api.paddle.com"
+	}
+	return "https://api.paddle.com"
+}
+
+// getCustomerEmailByCustomerID retrieves the customer's email from Paddle's API, as
+// subscription and transaction notifications only carry the customer ID
+func (p *PaddleProvider) getCustomerEmailByCustomerID(ctx context.Context, customerID string) (string, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", p.name)).With(zap.String("operation", "get-customer-email-by-customer-id")).With(zap.String("paddle-customer-id", customerID))
+
+	logger.Info("handle-request-to-get-customer-email-by-customer-id")
+
+	apiURL := p.getAPIBaseURL() + "/customers/" + customerID
+	body, err := p.callPaddleEndpoint(logger, "GET", apiURL, nil, []int{http.StatusOK})
+	if err != nil {
+		return "", err
+	}
+
+	var apiResp struct {
+		Data struct {
+			Email string `json:"email"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return "", ErrPaymentProviderAPIResponseInvalid
+	}
+
+	if apiResp.Data.Email == "" {
+		return "", ErrPaymentProviderMissingPayloadCustomerEmail
+	}
+
+	logger.Info("successfully-retrieved-customer-email")
+
+	return apiResp.Data.Email, nil
+}
+
+// callPaddleEndpoint is a helper to call Paddle Billing API endpoints
+func (p *PaddleProvider) callPaddleEndpoint(logger *zap.Logger, method, endpoint string, body io.Reader, validHttpStatusCodes []int) ([]byte, error) {
+	logger.Info("calling-paddle-endpoint", zap.String("http-method", method), zap.String("endpoint-host", endpointHostForLog(endpoint)))
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		logger.Error("failed-to-create-http-request", zap.Error(err))
+		return nil, ErrPaymentProviderAPIRequestFailed
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Paddle-Version", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logger.Error("http-request-failed", zap.Error(err))
+		return nil, ErrPaymentProviderAPIRequestFailed
+	}
+	defer resp.Body.Close()
+
+	if !slices.Contains(validHttpStatusCodes, resp.StatusCode) {
+		logger.Error("http-request-returned-invalid-status", zap.Int("status-code", resp.StatusCode), zap.Ints("valid-status-codes", validHttpStatusCodes))
+		return nil, ErrPaymentProviderSubscriptionNotFound
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error("failed-to-read-http-response-body", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("successfully-called-paddle-endpoint")
+	return responseBody, nil
+}
+
+// Helper functions
+
+// paddlePlan returns the price ID and product name of the first item, which is matched
+// against price plan provider refs
+func paddlePlan(data *PaddleWebhookData) (string, string) {
+	if len(data.Items) == 0 {
+		return "", ""
+	}
+
+	item := data.Items[0]
+	if item.Product != nil && item.Product.Name != "" {
+		return item.Price.ID, item.Product.Name
+	}
+
+	// Transactions carry the product on their line items rather than their items
+	if data.Details != nil {
+		for _, lineItem := range data.Details.LineItems {
+			if lineItem.PriceID == item.Price.ID && lineItem.Product != nil && lineItem.Product.Name != "" {
+				return item.Price.ID, lineItem.Product.Name
+			}
+		}
+	}
+
+	if item.Price.Name != "" {
+		return item.Price.ID, item.Price.Name
+	}
+
+	return item.Price.ID, item.Price.Description
+}
+
+// paddleQuantity returns the quantity of the first item, which holds the seats of the plan
+func paddleQuantity(data *PaddleWebhookData) int64 {
+	if len(data.Items) == 0 {
+		return 0
+	}
+
+	return data.Items[0].Quantity
+}
+
+// paddleAmount returns the transaction grand total, or the recurring total of the
+// subscription items, in the lowest denomination of the currency
+func paddleAmount(data *PaddleWebhookData) int64 {
+	if data.Details != nil && data.Details.Totals.GrandTotal != "" {
+		amount, _ := strconv.ParseInt(data.Details.Totals.GrandTotal, 10, 64)
+		return amount
+	}
+
+	var amount int64
+	for _, item := range data.Items {
+		unitAmount, _ := strconv.ParseInt(item.Price.UnitPrice.Amount, 10, 64)
+		quantity := item.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		amount += unitAmount * quantity
+	}
+
+	return amount
+}
+
+// paddleAvailableUntilDate returns when access to the subscription ends, taking a
+// scheduled cancellation or a cancellation into account
+func paddleAvailableUntilDate(data *PaddleWebhookData) string {
+	if data.ScheduledChange != nil && data.ScheduledChange.Action == "cancel" {
+		return formatPaddleTime(data.ScheduledChange.EffectiveAt)
+	}
+
+	if data.CurrentBillingPeriod != nil {
+		return formatPaddleTime(data.CurrentBillingPeriod.EndsAt)
+	}
+
+	return formatPaddleTime(data.CanceledAt)
+}
+
+// formatPaddleTime converts a Paddle timestamp to RFC3339 in UTC, returning an empty
+// string when the value is missing or invalid
+func formatPaddleTime(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.UTC().Format(time.RFC3339)
+}
+
+func paddleEventToStandard(eventType string) string {
+	switch eventType {
+	case "subscription.created":
+		return EventTypeSubscriptionCreated
+	case "subscription.updated", "subscription.activated", "subscription.trialing", "subscription.past_due":
+		return EventTypeSubscriptionUpdated
+	case "subscription.canceled":
+		return EventTypeSubscriptionCancelled
+	case "subscription.paused":
+		return EventTypeSubscriptionPaused
+	case "subscription.resumed":
+		return EventTypeSubscriptionResumed
+	case "transaction.completed":
+		return EventTypePaymentSucceeded
+	case "transaction.payment_failed", "transaction.past_due":
+		return EventTypePaymentFailed
+	default:
+		return eventType
+	}
+}
+
+// paddleTransactionEventToStatus returns the subscription status implied by a transaction
+// notification, as transaction statuses do not describe the subscription
+func paddleTransactionEventToStatus(eventType string) string {
+	switch eventType {
+	case "transaction.completed":
+		return SubscriptionStatusActive
+	case "transaction.payment_failed", "transaction.past_due":
+		return SubscriptionStatusPastDue
+	default:
+		return ""
+	}
+}
+
+func paddleStatusToStandard(status string) string {
+	switch status {
+	case "active":
+		return SubscriptionStatusActive
+	case "trialing":
+		return SubscriptionStatusTrialing
+	case "past_due":
+		return SubscriptionStatusPastDue
+	case "canceled":
+		return SubscriptionStatusCancelled
+	case "paused":
+		return SubscriptionStatusPaused
+	default:
+		return status
+	}
+}
@@ -0,0 +1,512 @@
+package paymentprovider_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+const paddleTestWebhookSecret = "pdl_ntfset_test_secret"
+
+func newTestPaddleProvider(t *testing.T, handler http.HandlerFunc) *paymentprovider.PaddleProvider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := paymentprovider.NewPaddleProvider(&paymentprovider.Config{
+		ProviderName:  "paddle",
+		APIKey:        "pdl_sdbx_apikey_123",
+		WebhookSecret: paddleTestWebhookSecret,
+		APIBaseURL:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating provider, got %v", err)
+	}
+
+	return provider
+}
+
+// paddleTestAPI serves the customer and subscription lookups made while parsing notifications
+func paddleTestAPI(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pdl_sdbx_apikey_123" {
+			t.Errorf("unexpected authorization header %q", r.Header.Get("Authorization"))
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/customers/ctm_01hv6y1jedq4p1n0yqn5ba3ky4":
+			w.Write(readPaddleFixture(t, "customer.json"))
+		case r.Method == http.MethodGet && r.URL.Path == "/subscriptions/sub_01hv8x29kz0t586xy6zn1a62ny":
+			// Paddle returns the subscription entity under data, like the notification
+			w.Write(readPaddleFixture(t, "subscription_created.json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func readPaddleFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", "paddle", name))
+	if err != nil {
+		t.Fatalf("expected fixture %s, got %v", name, err)
+	}
+
+	return body
+}
+
+func signPaddleBody(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + ":" + string(body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newPaddleWebhookRequest(body []byte, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bms/billing/paddle/webhooks", bytes.NewReader(body))
+	if signature != "" {
+		req.Header.Set("Paddle-Signature", signature)
+	}
+	return req
+}
+
+func TestPaddleVerifyWebhook(t *testing.T) {
+	provider := newTestPaddleProvider(t, paddleTestAPI(t))
+	body := readPaddleFixture(t, "subscription_created.json")
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name        string
+		signature   string
+		expectedErr error
+	}{
+		{
+			name:      "Success - valid signature",
+			signature: fmt.Sprintf("ts=%d;h1=%s", now, signPaddleBody(paddleTestWebhookSecret, now, body)),
+		},
+		{
+			name:      "Success - signature among several during secret rotation",
+			signature: fmt.Sprintf("ts=%d;h1=%s;h1=%s", now, signPaddleBody("old-secret", now, body), signPaddleBody(paddleTestWebhookSecret, now, body)),
+		},
+		{
+			name:        "Failure - missing signature header",
+			expectedErr: paymentprovider.ErrPaymentProviderMissingSignature,
+		},
+		{
+			name:        "Failure - signed with another secret",
+			signature:   fmt.Sprintf("ts=%d;h1=%s", now, signPaddleBody("another-secret", now, body)),
+			expectedErr: paymentprovider.ErrPaymentProviderInvalidWebhookSignature,
+		},
+		{
+			name:        "Failure - missing h1 signature",
+			signature:   fmt.Sprintf("ts=%d", now),
+			expectedErr: paymentprovider.ErrPaymentProviderInvalidWebhookSignature,
+		},
+		{
+			name:        "Failure - timestamp too old",
+			signature:   fmt.Sprintf("ts=%d;h1=%s", now-600, signPaddleBody(paddleTestWebhookSecret, now-600, body)),
+			expectedErr: paymentprovider.ErrPaymentProviderWebhookTimestampTooOld,
+		},
+		{
+			name:        "Failure - timestamp in the future",
+			signature:   fmt.Sprintf("ts=%d;h1=%s", now+600, signPaddleBody(paddleTestWebhookSecret, now+600, body)),
+			expectedErr: paymentprovider.ErrPaymentProviderInvalidWebhookSignature,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := provider.VerifyWebhook(context.Background(), newPaddleWebhookRequest(body, test.signature))
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("expected %v, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPaddleVerifyWebhookKeepsBodyReadable(t *testing.T) {
+	provider := newTestPaddleProvider(t, paddleTestAPI(t))
+	body := readPaddleFixture(t, "transaction_completed.json")
+	now := time.Now().Unix()
+
+	req := newPaddleWebhookRequest(body, fmt.Sprintf("ts=%d;h1=%s", now, signPaddleBody(paddleTestWebhookSecret, now, body)))
+	if err := provider.VerifyWebhook(context.Background(), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	remaining, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("expected readable body, got %v", err)
+	}
+	if !bytes.Equal(remaining, body) {
+		t.Fatal("expected body to be restored after verification")
+	}
+}
+
+func TestPaddleParsePayload(t *testing.T) {
+	provider := newTestPaddleProvider(t, paddleTestAPI(t))
+
+	tests := []struct {
+		name     string
+		fixture  string
+		expected paymentprovider.WebhookPayload
+	}{
+		{
+			name:    "Success - subscription created",
+			fixture: "subscription_created.json",
+			expected: paymentprovider.WebhookPayload{
+				EventType:          paymentprovider.EventTypeSubscriptionCreated,
+				EventID:            "evt_01hv8wptq8987qeep44cyrewp9",
+				EventTime:          "2026-04-12T10:18:49Z",
+				PaymentType:        paymentprovider.PaymentTypeSubscription,
+				SubscriptionID:     "sub_01hv8x29kz0t586xy6zn1a62ny",
+				CustomerID:         "ctm_01hv6y1jedq4p1n0yqn5ba3ky4",
+				CustomerEmail:      "sam@example.com",
+				UserID:             "user-123",
+				GroupID:            "group-42",
+				Quantity:           2,
+				Status:             paymentprovider.SubscriptionStatusActive,
+				PlanID:             "pri_01gsz8x8sawmvhz1pv30nge1ke",
+				PlanName:           "AeroEdit Pro",
+				Amount:             6000,
+				Currency:           "USD",
+				NextBillingDate:    "2026-05-12T10:18:47Z",
+				AvailableUntilDate: "2026-05-12T10:18:47Z",
+				CancelURL:          "https://buyer-portal.paddle.com/subscriptions/sub_01hv8x29kz0t586xy6zn1a62ny/cancel",
+				UpdateURL:          "https://buyer-portal.paddle.com/subscriptions/sub_01hv8x29kz0t586xy6zn1a62ny/update-payment-method",
+			},
+		},
+		{
+			name:    "Success - subscription canceled",
+			fixture: "subscription_canceled.json",
+			expected: paymentprovider.WebhookPayload{
+				EventType:          paymentprovider.EventTypeSubscriptionCancelled,
+				EventID:            "evt_01hvc2cmrsg2wb6hfd0fcpq2f3",
+				EventTime:          "2026-05-12T10:18:51Z",
+				PaymentType:        paymentprovider.PaymentTypeSubscription,
+				SubscriptionID:     "sub_01hv8x29kz0t586xy6zn1a62ny",
+				CustomerID:         "ctm_01hv6y1jedq4p1n0yqn5ba3ky4",
+				CustomerEmail:      "sam@example.com",
+				UserID:             "user-123",
+				Quantity:           2,
+				Status:             paymentprovider.SubscriptionStatusCancelled,
+				PlanID:             "pri_01gsz8x8sawmvhz1pv30nge1ke",
+				PlanName:           "AeroEdit Pro",
+				Amount:             6000,
+				Currency:           "USD",
+				AvailableUntilDate: "2026-05-12T10:18:47Z",
+				CancelURL:          "https://buyer-portal.paddle.com/subscriptions/sub_01hv8x29kz0t586xy6zn1a62ny/cancel",
+			},
+		},
+		{
+			name:    "Success - first transaction completed",
+			fixture: "transaction_completed.json",
+			expected: paymentprovider.WebhookPayload{
+				EventType:                  paymentprovider.EventTypePaymentSucceeded,
+				EventID:                    "evt_01hv8wpwh8qh5wqvv0bmrxmj5p",
+				EventTime:                  "2026-04-12T10:18:50Z",
+				PaymentType:                paymentprovider.PaymentTypeSubscription,
+				SubscriptionID:             "sub_01hv8x29kz0t586xy6zn1a62ny",
+				TransactionID:              "txn_01hv8wnv8s4f1tkb0n16b6sx6z",
+				CustomerID:                 "ctm_01hv6y1jedq4p1n0yqn5ba3ky4",
+				CustomerEmail:              "sam@example.com",
+				UserID:                     "user-123",
+				Quantity:                   2,
+				Status:                     paymentprovider.SubscriptionStatusActive,
+				PlanID:                     "pri_01gsz8x8sawmvhz1pv30nge1ke",
+				PlanName:                   "AeroEdit Pro",
+				Amount:                     7000,
+				Currency:                   "USD",
+				IsFirstSubscriptionPayment: true,
+				NextBillingDate:            "2026-05-12T10:18:47Z",
+				AvailableUntilDate:         "2026-05-12T10:18:47Z",
+			},
+		},
+		{
+			name:    "Success - renewal payment failed",
+			fixture: "transaction_payment_failed.json",
+			expected: paymentprovider.WebhookPayload{
+				EventType:          paymentprovider.EventTypePaymentFailed,
+				EventID:            "evt_01hvc2d3a4h5c4wqpr2k3d1m9s",
+				EventTime:          "2026-05-12T10:19:02Z",
+				PaymentType:        paymentprovider.PaymentTypeSubscription,
+				SubscriptionID:     "sub_01hv8x29kz0t586xy6zn1a62ny",
+				TransactionID:      "txn_01hvc2cq5f6h1ntrn4wvdrstm6",
+				CustomerID:         "ctm_01hv6y1jedq4p1n0yqn5ba3ky4",
+				CustomerEmail:      "sam@example.com",
+				Quantity:           2,
+				Status:             paymentprovider.SubscriptionStatusPastDue,
+				PlanID:             "pri_01gsz8x8sawmvhz1pv30nge1ke",
+				PlanName:           "Monthly (per seat)",
+				Amount:             7000,
+				Currency:           "USD",
+				NextBillingDate:    "2026-06-12T10:18:47Z",
+				AvailableUntilDate: "2026-06-12T10:18:47Z",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := readPaddleFixture(t, test.fixture)
+
+			payload, err := provider.ParsePayload(context.Background(), newPaddleWebhookRequest(body, ""))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if payload.RawPayload != string(body) {
+				t.Error("expected raw payload to be kept")
+			}
+
+			payload.RawPayload = ""
+			if *payload != test.expected {
+				t.Fatalf("unexpected payload\n got: %#v\nwant: %#v", *payload, test.expected)
+			}
+		})
+	}
+}
+
+func TestPaddleParsePayloadRequiresCustomerEmail(t *testing.T) {
+	provider := newTestPaddleProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"ctm_01hv6y1jedq4p1n0yqn5ba3ky4","email":""}}`))
+	})
+
+	_, err := provider.ParsePayload(context.Background(), newPaddleWebhookRequest(readPaddleFixture(t, "subscription_created.json"), ""))
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderMissingPayloadCustomerEmail) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingPayloadCustomerEmail, err)
+	}
+}
+
+func TestPaddleGetSubscriptionInfo(t *testing.T) {
+	provider := newTestPaddleProvider(t, paddleTestAPI(t))
+
+	info, err := provider.GetSubscriptionInfo(context.Background(), "sub_01hv8x29kz0t586xy6zn1a62ny")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := paymentprovider.SubscriptionInfo{
+		SubscriptionID:     "sub_01hv8x29kz0t586xy6zn1a62ny",
+		CustomerID:         "ctm_01hv6y1jedq4p1n0yqn5ba3ky4",
+		Status:             paymentprovider.SubscriptionStatusActive,
+		PlanName:           "AeroEdit Pro",
+		PlanID:             "pri_01gsz8x8sawmvhz1pv30nge1ke",
+		Amount:             6000,
+		Currency:           "USD",
+		BillingInterval:    "month",
+		Quantity:           2,
+		NextBillingDate:    "2026-05-12T10:18:47Z",
+		CurrentPeriodStart: "2026-04-12T10:18:47Z",
+		CurrentPeriodEnd:   "2026-05-12T10:18:47Z",
+		CancelURL:          "https://buyer-portal.paddle.com/subscriptions/sub_01hv8x29kz0t586xy6zn1a62ny/cancel",
+		UpdateURL:          "https://buyer-portal.paddle.com/subscriptions/sub_01hv8x29kz0t586xy6zn1a62ny/update-payment-method",
+	}
+	if *info != expected {
+		t.Fatalf("unexpected subscription info\n got: %#v\nwant: %#v", *info, expected)
+	}
+
+	_, err = provider.GetSubscriptionInfo(context.Background(), "sub_unknown")
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderSubscriptionNotFound) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderSubscriptionNotFound, err)
+	}
+}
+
+func TestPaddleCreateCheckoutSession(t *testing.T) {
+	provider := newTestPaddleProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/transactions" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Paddle-Version") != "1" {
+			t.Errorf("expected Paddle-Version header, got %q", r.Header.Get("Paddle-Version"))
+		}
+
+		var body struct {
+			Items []struct {
+				PriceID  string `json:"price_id"`
+				Quantity int64  `json:"quantity"`
+			} `json:"items"`
+			CustomData map[string]string `json:"custom_data"`
+			CustomerID string            `json:"customer_id"`
+			DiscountID string            `json:"discount_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("expected json body, got %v", err)
+		}
+
+		if len(body.Items) != 1 || body.Items[0].PriceID != "pri_01gsz8x8sawmvhz1pv30nge1ke" || body.Items[0].Quantity != 2 {
+			t.Errorf("unexpected items %#v", body.Items)
+		}
+		if body.CustomData[paymentprovider.MetadataKeyUserID] != "user-1" || body.CustomData[paymentprovider.MetadataKeyGroupID] != "group-1" {
+			t.Errorf("expected user and group IDs in custom data, got %v", body.CustomData)
+		}
+		if body.CustomerID != "ctm_01hv6y1jedq4p1n0yqn5ba3ky4" || body.DiscountID != "dsc_01gtgztp8fpchantd5g1wrksa3" {
+			t.Errorf("unexpected customer or discount %q %q", body.CustomerID, body.DiscountID)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":"txn_01hv8wnv8s4f1tkb0n16b6sx6z","status":"ready","checkout":{"url":"https://app.example.com/pay?_ptxn=txn_01hv8wnv8s4f1tkb0n16b6sx6z"}}}`))
+	})
+
+	session, err := provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{
+		PriceID:    "pri_01gsz8x8sawmvhz1pv30nge1ke",
+		Quantity:   2,
+		UserID:     "user-1",
+		GroupID:    "group-1",
+		CustomerID: "ctm_01hv6y1jedq4p1n0yqn5ba3ky4",
+		CouponCode: "dsc_01gtgztp8fpchantd5g1wrksa3",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.ID != "txn_01hv8wnv8s4f1tkb0n16b6sx6z" || session.URL != "https://app.example.com/pay?_ptxn=txn_01hv8wnv8s4f1tkb0n16b6sx6z" {
+		t.Fatalf("unexpected session %#v", session)
+	}
+}
+
+func TestPaddleCreateCheckoutSessionErrors(t *testing.T) {
+	provider := newTestPaddleProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"type":"request_error","code":"bad_request"}}`))
+	})
+
+	_, err := provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderMissingCheckoutPrice) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingCheckoutPrice, err)
+	}
+
+	_, err = provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{PriceID: "pri_unknown"})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderCheckoutRequestFailed) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderCheckoutRequestFailed, err)
+	}
+}
+
+func TestPaddleCreateCustomerPortalSession(t *testing.T) {
+	provider := newTestPaddleProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/customers/ctm_01hv6y1jedq4p1n0yqn5ba3ky4/portal-sessions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":"cpls_01h4ge9r64c22exjsx0fy8b48b","customer_id":"ctm_01hv6y1jedq4p1n0yqn5ba3ky4","urls":{"general":{"overview":"https://customer-portal.paddle.com/cpl_01j7zbyqs3vah3aafp4jf62qaw?action=overview&token=pga_123"}}}}`))
+	})
+
+	session, err := provider.CreateCustomerPortalSession(context.Background(), &paymentprovider.CustomerPortalSessionRequest{CustomerID: "ctm_01hv6y1jedq4p1n0yqn5ba3ky4"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.ID != "cpls_01h4ge9r64c22exjsx0fy8b48b" || session.URL != "https://customer-portal.paddle.com/cpl_01j7zbyqs3vah3aafp4jf62qaw?action=overview&token=pga_123" {
+		t.Fatalf("unexpected session %#v", session)
+	}
+
+	_, err = provider.CreateCustomerPortalSession(context.Background(), &paymentprovider.CustomerPortalSessionRequest{})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderMissingCustomerID) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingCustomerID, err)
+	}
+
+	_, err = provider.CreateCustomerPortalSession(context.Background(), &paymentprovider.CustomerPortalSessionRequest{CustomerID: "ctm_unknown"})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderCheckoutRequestFailed) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderCheckoutRequestFailed, err)
+	}
+}
+
+func TestPaddlePauseSubscription(t *testing.T) {
+	provider := newTestPaddleProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/subscriptions/sub_01hv8x29kz0t586xy6zn1a62ny/pause" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("expected json body, got %v", err)
+		}
+		if body["effective_from"] != "next_billing_period" {
+			t.Errorf("expected pause from the next billing period, got %q", body["effective_from"])
+		}
+
+		w.Write([]byte(`{"data":{"id":"sub_01hv8x29kz0t586xy6zn1a62ny","status":"active","scheduled_change":{"action":"pause","effective_at":"2026-05-01T00:00:00.000000Z","resume_at":null}}}`))
+	})
+
+	change, err := provider.PauseSubscription(context.Background(), &paymentprovider.SubscriptionChangeRequest{SubscriptionID: "sub_01hv8x29kz0t586xy6zn1a62ny", AtPeriodEnd: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if change.Status != paymentprovider.SubscriptionStatusActive || change.EffectiveAt != "2026-05-01T00:00:00Z" {
+		t.Fatalf("expected pause scheduled for the period end, got %#v", change)
+	}
+
+	_, err = provider.PauseSubscription(context.Background(), &paymentprovider.SubscriptionChangeRequest{SubscriptionID: "sub_unknown"})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderSubscriptionChangeRequestFailed) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderSubscriptionChangeRequestFailed, err)
+	}
+}
+
+func TestPaddleRefundPayment(t *testing.T) {
+	provider := newTestPaddleProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/adjustments" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("expected json body, got %v", err)
+		}
+		if body["action"] != "refund" || body["type"] != "full" || body["transaction_id"] != "txn_01hv8wnv8s4f1tkb0n16b6sx6z" || body["reason"] != "Refunded by support" {
+			t.Errorf("unexpected adjustment %v", body)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":"adj_01hvgf2s84dr6reszzg29zbvcm","action":"refund","transaction_id":"txn_01hv8wnv8s4f1tkb0n16b6sx6z","status":"pending_approval","currency_code":"USD","totals":{"subtotal":"2000","tax":"0","total":"2000"}}}`))
+	})
+
+	refund, err := provider.RefundPayment(context.Background(), &paymentprovider.RefundRequest{TransactionID: "txn_01hv8wnv8s4f1tkb0n16b6sx6z"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refund.ID != "adj_01hvgf2s84dr6reszzg29zbvcm" || refund.Amount != 2000 || refund.Currency != "USD" || refund.Status != "pending_approval" {
+		t.Fatalf("unexpected refund %#v", refund)
+	}
+
+	_, err = provider.RefundPayment(context.Background(), &paymentprovider.RefundRequest{TransactionID: "txn_01hv8wnv8s4f1tkb0n16b6sx6z", Amount: 500})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderPartialRefundNotSupported) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderPartialRefundNotSupported, err)
+	}
+}
+
+func TestCreateProviderFromConfigPaddle(t *testing.T) {
+	provider, err := paymentprovider.CreateProviderFromConfig(&paymentprovider.Config{
+		ProviderName:  "paddle",
+		APIKey:        "pdl_live_apikey_123",
+		WebhookSecret: paddleTestWebhookSecret,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if provider.GetProviderName() != "paddle" {
+		t.Fatalf("expected paddle provider, got %q", provider.GetProviderName())
+	}
+
+	_, err = paymentprovider.NewPaddleProvider(&paymentprovider.Config{ProviderName: "paddle", APIKey: "pdl_live_apikey_123"})
+	if !errors.Is(err, paymentprovider.ErrPaymentProviderInvalidConfigWebhookSecret) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderInvalidConfigWebhookSecret, err)
+	}
+}
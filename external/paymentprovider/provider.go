@@ -28,6 +28,67 @@ type Provider interface {
 	// GetSubscriptionInfo retrieves current subscription details from the provider's API
 	// This is useful for syncing state or retrieving information not in webhooks
 	GetSubscriptionInfo(ctx context.Context, subscriptionID string) (*SubscriptionInfo, error)
+
+	// CreateCheckoutSession creates a hosted checkout for the requested price. The platform
+	// user ID is attached as metadata so the resulting webhooks can be linked to the user
+	CreateCheckoutSession(ctx context.Context, req *CheckoutSessionRequest) (*CheckoutSession, error)
+
+	// CreateCustomerPortalSession creates a session for the provider's hosted customer portal,
+	// where customers can manage payment details and their subscription
+	CreateCustomerPortalSession(ctx context.Context, req *CustomerPortalSessionRequest) (*CustomerPortalSession, error)
+}
+
+// UsageReporter is implemented by providers that support metered billing. It is optional,
+// callers should check for it with a type assertion
+type UsageReporter interface {
+	// ReportUsage records metered usage against the customer's subscription with the provider
+	ReportUsage(ctx context.Context, req *UsageReportRequest) (*UsageReport, error)
+}
+
+// CatalogReader is implemented by providers whose products and prices can be read through
+// their API. It is optional, callers should check for it with a type assertion
+type CatalogReader interface {
+	// GetCatalogProduct retrieves a product from the provider
+	GetCatalogProduct(ctx context.Context, productID string) (*CatalogProduct, error)
+
+	// GetCatalogPrice retrieves a price from the provider
+	GetCatalogPrice(ctx context.Context, priceID string) (*CatalogPrice, error)
+}
+
+// CatalogWriter is implemented by providers whose products and prices can be created and
+// changed through their API. It is optional, callers should check for it with a type assertion
+type CatalogWriter interface {
+	CatalogReader
+
+	// SaveCatalogProduct creates the product when it has no ID, otherwise updates it
+	SaveCatalogProduct(ctx context.Context, product *CatalogProduct) (*CatalogProduct, error)
+
+	// CreateCatalogPrice creates a price. Prices cannot be changed once created, so a new
+	// price is created whenever the amount or interval changes
+	CreateCatalogPrice(ctx context.Context, price *CatalogPrice) (*CatalogPrice, error)
+
+	// ArchiveCatalogPrice stops a price being used for new purchases. Existing subscriptions
+	// keep it
+	ArchiveCatalogPrice(ctx context.Context, priceID string) error
+}
+
+// SubscriptionManager is implemented by providers whose subscriptions can be cancelled and
+// paused through their API. It is optional, callers should check for it with a type assertion
+type SubscriptionManager interface {
+	// CancelSubscription cancels the subscription straight away or at the end of the
+	// current billing period
+	CancelSubscription(ctx context.Context, req *SubscriptionChangeRequest) (*SubscriptionChange, error)
+
+	// PauseSubscription stops the subscription being charged, straight away or at the end
+	// of the current billing period
+	PauseSubscription(ctx context.Context, req *SubscriptionChangeRequest) (*SubscriptionChange, error)
+}
+
+// Refunder is implemented by providers whose payments can be refunded through their API.
+// It is optional, callers should check for it with a type assertion
+type Refunder interface {
+	// RefundPayment refunds all or part of a payment
+	RefundPayment(ctx context.Context, req *RefundRequest) (*Refund, error)
 }
 
 func endpointHostForLog(endpoint string) string {
@@ -84,6 +84,245 @@ func (r *ProviderRegistry) VerifyAndParseWebhookPayload(ctx context.Context, pro
 	return payload, nil
 }
 
+// GetSubscriptionInfo is a convenience method that identifies the provider and
+// retrieves its current view of a subscription
+func (r *ProviderRegistry) GetSubscriptionInfo(ctx context.Context, providerName string, subscriptionID string) (*SubscriptionInfo, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "get-subscription-info", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	return provider.GetSubscriptionInfo(ctx, subscriptionID)
+}
+
+// CreateCheckoutSession is a convenience method that identifies the provider and
+// creates a hosted checkout session with it
+func (r *ProviderRegistry) CreateCheckoutSession(ctx context.Context, providerName string, req *CheckoutSessionRequest) (*CheckoutSession, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "create-checkout-session", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	return provider.CreateCheckoutSession(ctx, req)
+}
+
+// CreateCustomerPortalSession is a convenience method that identifies the provider and
+// creates a customer portal session with it
+func (r *ProviderRegistry) CreateCustomerPortalSession(ctx context.Context, providerName string, req *CustomerPortalSessionRequest) (*CustomerPortalSession, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "create-customer-portal-session", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	return provider.CreateCustomerPortalSession(ctx, req)
+}
+
+// ReportUsage is a convenience method that identifies the provider and reports metered
+// usage to it, when the provider supports usage reporting
+func (r *ProviderRegistry) ReportUsage(ctx context.Context, providerName string, req *UsageReportRequest) (*UsageReport, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "report-usage", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	reporter, ok := provider.(UsageReporter)
+	if !ok {
+		logger.Warn("payment-provider-does-not-support-usage-reporting")
+		return nil, ErrPaymentProviderUsageReportingNotSupported
+	}
+
+	return reporter.ReportUsage(ctx, req)
+}
+
+// SupportsCatalog returns true when the named provider's products and prices can be read
+func (r *ProviderRegistry) SupportsCatalog(providerName string) bool {
+	provider, err := r.Get(providerName)
+	if err != nil {
+		return false
+	}
+
+	_, ok := provider.(CatalogReader)
+	return ok
+}
+
+// SupportsCatalogWrites returns true when the named provider's products and prices can be
+// created and changed
+func (r *ProviderRegistry) SupportsCatalogWrites(providerName string) bool {
+	provider, err := r.Get(providerName)
+	if err != nil {
+		return false
+	}
+
+	_, ok := provider.(CatalogWriter)
+	return ok
+}
+
+// GetCatalogProduct is a convenience method that identifies the provider and retrieves a
+// product from its catalog, when the provider supports reading its catalog
+func (r *ProviderRegistry) GetCatalogProduct(ctx context.Context, providerName string, productID string) (*CatalogProduct, error) {
+	reader, err := r.getCatalogReader(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader.GetCatalogProduct(ctx, productID)
+}
+
+// GetCatalogPrice is a convenience method that identifies the provider and retrieves a
+// price from its catalog, when the provider supports reading its catalog
+func (r *ProviderRegistry) GetCatalogPrice(ctx context.Context, providerName string, priceID string) (*CatalogPrice, error) {
+	reader, err := r.getCatalogReader(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader.GetCatalogPrice(ctx, priceID)
+}
+
+// SaveCatalogProduct is a convenience method that identifies the provider and creates or
+// updates a product in its catalog, when the provider supports changing its catalog
+func (r *ProviderRegistry) SaveCatalogProduct(ctx context.Context, providerName string, product *CatalogProduct) (*CatalogProduct, error) {
+	writer, err := r.getCatalogWriter(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return writer.SaveCatalogProduct(ctx, product)
+}
+
+// CreateCatalogPrice is a convenience method that identifies the provider and creates a
+// price in its catalog, when the provider supports changing its catalog
+func (r *ProviderRegistry) CreateCatalogPrice(ctx context.Context, providerName string, price *CatalogPrice) (*CatalogPrice, error) {
+	writer, err := r.getCatalogWriter(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return writer.CreateCatalogPrice(ctx, price)
+}
+
+// ArchiveCatalogPrice is a convenience method that identifies the provider and archives a
+// price in its catalog, when the provider supports changing its catalog
+func (r *ProviderRegistry) ArchiveCatalogPrice(ctx context.Context, providerName string, priceID string) error {
+	writer, err := r.getCatalogWriter(ctx, providerName)
+	if err != nil {
+		return err
+	}
+
+	return writer.ArchiveCatalogPrice(ctx, priceID)
+}
+
+// CancelSubscription is a convenience method that identifies the provider and cancels a
+// subscription with it, when the provider supports managing subscriptions
+func (r *ProviderRegistry) CancelSubscription(ctx context.Context, providerName string, req *SubscriptionChangeRequest) (*SubscriptionChange, error) {
+	manager, err := r.getSubscriptionManager(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.CancelSubscription(ctx, req)
+}
+
+// PauseSubscription is a convenience method that identifies the provider and pauses a
+// subscription with it, when the provider supports managing subscriptions
+func (r *ProviderRegistry) PauseSubscription(ctx context.Context, providerName string, req *SubscriptionChangeRequest) (*SubscriptionChange, error) {
+	manager, err := r.getSubscriptionManager(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.PauseSubscription(ctx, req)
+}
+
+// RefundPayment is a convenience method that identifies the provider and refunds a payment
+// with it, when the provider supports refunds
+func (r *ProviderRegistry) RefundPayment(ctx context.Context, providerName string, req *RefundRequest) (*Refund, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "refund-payment", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	refunder, ok := provider.(Refunder)
+	if !ok {
+		logger.Warn("payment-provider-does-not-support-refunds")
+		return nil, ErrPaymentProviderRefundsNotSupported
+	}
+
+	return refunder.RefundPayment(ctx, req)
+}
+
+// getSubscriptionManager returns the named provider when it supports managing subscriptions
+func (r *ProviderRegistry) getSubscriptionManager(ctx context.Context, providerName string) (SubscriptionManager, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "get-subscription-manager", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	manager, ok := provider.(SubscriptionManager)
+	if !ok {
+		logger.Warn("payment-provider-does-not-support-subscription-management")
+		return nil, ErrPaymentProviderSubscriptionManagementNotSupported
+	}
+
+	return manager, nil
+}
+
+// getCatalogReader returns the named provider when it supports reading its catalog
+func (r *ProviderRegistry) getCatalogReader(ctx context.Context, providerName string) (CatalogReader, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "get-catalog-reader", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	reader, ok := provider.(CatalogReader)
+	if !ok {
+		logger.Warn("payment-provider-does-not-support-catalog")
+		return nil, ErrPaymentProviderCatalogNotSupported
+	}
+
+	return reader, nil
+}
+
+// getCatalogWriter returns the named provider when it supports changing its catalog
+func (r *ProviderRegistry) getCatalogWriter(ctx context.Context, providerName string) (CatalogWriter, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/paymentprovider", "get-catalog-writer", zap.String("provider", providerName))
+
+	provider, err := r.Get(providerName)
+	if err != nil {
+		logger.Warn("payment-provider-not-registered", zap.Error(err))
+		return nil, err
+	}
+
+	writer, ok := provider.(CatalogWriter)
+	if !ok {
+		logger.Warn("payment-provider-does-not-support-catalog-writes")
+		return nil, ErrPaymentProviderCatalogWriteNotSupported
+	}
+
+	return writer, nil
+}
+
 // CreateProviderFromConfig creates a provider instance from configuration
 func CreateProviderFromConfig(config *Config) (Provider, error) {
 	if err := config.Validate(); err != nil {
@@ -95,6 +334,8 @@ func CreateProviderFromConfig(config *Config) (Provider, error) {
 		return NewStripeProvider(config)
 	case "lemonsqueezy":
 		return NewLemonSqueezyProvider(config)
+	case "paddle":
+		return NewPaddleProvider(config)
 	case "kofi":
 		return NewKofiProvider(config)
 	default:
@@ -6,8 +6,10 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
@@ -144,6 +146,14 @@ func (s *StripeProvider) ParsePayload(ctx context.Context, req *http.Request) (*
 	customerID := getStringField(obj, "customer")
 	status := getStringField(obj, "status")
 
+	// Get the platform user attached when the checkout session was created
+	var userID, groupID, promotionCode string
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		userID = getStringField(metadata, MetadataKeyUserID)
+		groupID = getStringField(metadata, MetadataKeyGroupID)
+		promotionCode = getStringField(metadata, MetadataKeyPromotionCode)
+	}
+
 	// Get customer email
 	email, err := s.getCustomerDetailsByCustomerID(ctx, customerID)
 	if err != nil {
@@ -171,10 +181,12 @@ func (s *StripeProvider) ParsePayload(ctx context.Context, req *http.Request) (*
 	var amount int64
 	var currency string
 	var quantity int64
+	var priceID string
 	if items, ok := obj["items"].(map[string]interface{}); ok {
 		if data, ok := items["data"].([]interface{}); ok && len(data) > 0 {
 			if item, ok := data[0].(map[string]interface{}); ok {
 				if price, ok := item["price"].(map[string]interface{}); ok {
+					priceID = getStringField(price, "id")
 					amount = int64(getFloatField(price, "unit_amount"))
 					currency = getStringField(price, "currency")
 				}
@@ -201,10 +213,16 @@ func (s *StripeProvider) ParsePayload(ctx context.Context, req *http.Request) (*
 		EventID:            event.ID,
 		EventTime:          time.Unix(event.Created, 0).Format(time.RFC3339),
 		SubscriptionID:     subscriptionID,
+		TransactionID:      getStringField(obj, "payment_intent"),
 		CustomerID:         customerID,
 		CustomerEmail:      email,
+		UserID:             userID,
+		GroupID:            groupID,
+		PromotionCode:      promotionCode,
+		Quantity:           quantity,
 		Status:             stripeStatusToStandard(status),
 		PlanName:           planName,
+		PlanID:             priceID,
 		Amount:             amount * quantity,
 		Currency:           strings.ToUpper(currency),
 		NextBillingDate:    nextBillingDate,
@@ -272,11 +290,498 @@ func (s *StripeProvider) GetSubscriptionInfo(ctx context.Context, subscriptionID
 		}
 	}
 
+	if items, ok := obj["items"].(map[string]interface{}); ok {
+		if data, ok := items["data"].([]interface{}); ok && len(data) > 0 {
+			if item, ok := data[0].(map[string]interface{}); ok {
+				info.Quantity = int64(getFloatField(item, "quantity"))
+			}
+		}
+	}
+
 	logger.Info("retrieved-subscription-info")
 
 	return info, nil
 }
 
+// CreateCheckoutSession creates a Stripe Checkout session in subscription mode. The platform
+// user is attached to both the session and the resulting subscription as metadata
+func (s *StripeProvider) CreateCheckoutSession(ctx context.Context, req *CheckoutSessionRequest) (*CheckoutSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "create-checkout-session"))
+
+	logger.Info("handle-request-to-create-checkout-session")
+
+	if req.PriceID == "" {
+		logger.Warn("missing-price-id-for-checkout-session")
+		return nil, ErrPaymentProviderMissingCheckoutPrice
+	}
+
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	form := url.Values{}
+	form.Set("mode", "subscription")
+	form.Set("line_items[0][price]", req.PriceID)
+	form.Set("line_items[0][quantity]", strconv.FormatInt(quantity, 10))
+	form.Set("success_url", req.SuccessURL)
+	form.Set("cancel_url", req.CancelURL)
+
+	if req.CustomerID != "" {
+		form.Set("customer", req.CustomerID)
+	} else if req.CustomerEmail != "" {
+		form.Set("customer_email", req.CustomerEmail)
+	}
+
+	if req.UserID != "" {
+		form.Set("client_reference_id", req.UserID)
+		form.Set("metadata["+MetadataKeyUserID+"]", req.UserID)
+		form.Set("subscription_data[metadata]["+MetadataKeyUserID+"]", req.UserID)
+	}
+
+	if req.GroupID != "" {
+		form.Set("metadata["+MetadataKeyGroupID+"]", req.GroupID)
+		form.Set("subscription_data[metadata]["+MetadataKeyGroupID+"]", req.GroupID)
+	}
+
+	if req.PromotionCode != "" {
+		form.Set("metadata["+MetadataKeyPromotionCode+"]", req.PromotionCode)
+		form.Set("subscription_data[metadata]["+MetadataKeyPromotionCode+"]", req.PromotionCode)
+	}
+
+	if req.CustomerEmail != "" {
+		form.Set("metadata["+MetadataKeyCustomerEmail+"]", req.CustomerEmail)
+		form.Set("subscription_data[metadata]["+MetadataKeyCustomerEmail+"]", req.CustomerEmail)
+	}
+
+	if req.TrialPeriodDays > 0 {
+		form.Set("subscription_data[trial_period_days]", strconv.Itoa(req.TrialPeriodDays))
+	}
+
+	if req.CouponCode != "" {
+		form.Set("discounts[0][coupon]", req.CouponCode)
+	}
+
+	apiURL := s.getAPIBaseURL() + "/v1/checkout/sessions"
+	body, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	if err != nil {
+		return nil, sessionRequestError(err)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	session := &CheckoutSession{
+		ID:  getStringField(obj, "id"),
+		URL: getStringField(obj, "url"),
+	}
+
+	if expiresAt, ok := obj["expires_at"].(float64); ok {
+		session.ExpiresAt = time.Unix(int64(expiresAt), 0).UTC().Format(time.RFC3339)
+	}
+
+	if session.URL == "" {
+		logger.Error("checkout-session-response-missing-url")
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("created-checkout-session", zap.String("checkout-session-id", session.ID))
+
+	return session, nil
+}
+
+// CreateCustomerPortalSession creates a Stripe billing portal session for the customer
+func (s *StripeProvider) CreateCustomerPortalSession(ctx context.Context, req *CustomerPortalSessionRequest) (*CustomerPortalSession, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "create-customer-portal-session")).With(zap.String("stripe-customer-id", req.CustomerID))
+
+	logger.Info("handle-request-to-create-customer-portal-session")
+
+	if req.CustomerID == "" {
+		logger.Warn("missing-customer-id-for-customer-portal-session")
+		return nil, ErrPaymentProviderMissingCustomerID
+	}
+
+	form := url.Values{}
+	form.Set("customer", req.CustomerID)
+	if req.ReturnURL != "" {
+		form.Set("return_url", req.ReturnURL)
+	}
+
+	apiURL := s.getAPIBaseURL() + "/v1/billing_portal/sessions"
+	body, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	if err != nil {
+		return nil, sessionRequestError(err)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	session := &CustomerPortalSession{
+		ID:  getStringField(obj, "id"),
+		URL: getStringField(obj, "url"),
+	}
+
+	if session.URL == "" {
+		logger.Error("customer-portal-session-response-missing-url")
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("created-customer-portal-session")
+
+	return session, nil
+}
+
+// ReportUsage sends a billing meter event to Stripe for the customer. The identifier is
+// passed through so Stripe ignores duplicate reports of the same usage
+func (s *StripeProvider) ReportUsage(ctx context.Context, req *UsageReportRequest) (*UsageReport, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "report-usage")).With(zap.String("stripe-customer-id", req.CustomerID)).With(zap.String("meter-event-name", req.EventName))
+
+	logger.Info("handle-request-to-report-usage")
+
+	if req.EventName == "" {
+		logger.Warn("missing-event-name-for-usage-report")
+		return nil, ErrPaymentProviderMissingUsageEventName
+	}
+
+	if req.CustomerID == "" {
+		logger.Warn("missing-customer-id-for-usage-report")
+		return nil, ErrPaymentProviderMissingCustomerID
+	}
+
+	timestamp := req.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	form := url.Values{}
+	form.Set("event_name", req.EventName)
+	form.Set("payload[stripe_customer_id]", req.CustomerID)
+	form.Set("payload[value]", strconv.FormatInt(req.Quantity, 10))
+	form.Set("timestamp", strconv.FormatInt(timestamp.Unix(), 10))
+	if req.Identifier != "" {
+		form.Set("identifier", req.Identifier)
+	}
+
+	apiURL := s.getAPIBaseURL() + "/v1/billing/meter_events"
+	body, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	if err != nil {
+		if errors.Is(err, ErrPaymentProviderSubscriptionNotFound) {
+			return nil, ErrPaymentProviderUsageReportRequestFailed
+		}
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("reported-usage", zap.Int64("quantity", req.Quantity))
+
+	return &UsageReport{Identifier: getStringField(obj, "identifier")}, nil
+}
+
+// GetCatalogProduct retrieves a product from Stripe
+func (s *StripeProvider) GetCatalogProduct(ctx context.Context, productID string) (*CatalogProduct, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "get-catalog-product")).With(zap.String("stripe-product-id", productID))
+
+	logger.Info("handle-request-to-get-catalog-product")
+
+	apiURL := s.getAPIBaseURL() + "/v1/products/" + url.PathEscape(productID)
+	body, err := s.callStripeEndpoint(logger, "GET", apiURL, nil, []int{http.StatusOK})
+	if err != nil {
+		return nil, catalogRequestError(err, ErrPaymentProviderCatalogItemNotFound)
+	}
+
+	product, err := stripeCatalogProductFromResponse(body)
+	if err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("retrieved-catalog-product")
+
+	return product, nil
+}
+
+// SaveCatalogProduct creates the product on Stripe when it has no ID, otherwise updates
+// its name, description, status and metadata
+func (s *StripeProvider) SaveCatalogProduct(ctx context.Context, product *CatalogProduct) (*CatalogProduct, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "save-catalog-product")).With(zap.String("stripe-product-id", product.ID))
+
+	logger.Info("handle-request-to-save-catalog-product")
+
+	form := url.Values{}
+	form.Set("name", product.Name)
+	form.Set("description", product.Description)
+	form.Set("active", strconv.FormatBool(product.Active))
+	for key, value := range product.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	apiURL := s.getAPIBaseURL() + "/v1/products"
+	if product.ID != "" {
+		apiURL += "/" + url.PathEscape(product.ID)
+	}
+
+	body, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	if err != nil {
+		return nil, catalogRequestError(err, ErrPaymentProviderCatalogRequestFailed)
+	}
+
+	saved, err := stripeCatalogProductFromResponse(body)
+	if err != nil || saved.ID == "" {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("saved-catalog-product", zap.String("saved-stripe-product-id", saved.ID))
+
+	return saved, nil
+}
+
+// GetCatalogPrice retrieves a price from Stripe
+func (s *StripeProvider) GetCatalogPrice(ctx context.Context, priceID string) (*CatalogPrice, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "get-catalog-price")).With(zap.String("stripe-price-id", priceID))
+
+	logger.Info("handle-request-to-get-catalog-price")
+
+	apiURL := s.getAPIBaseURL() + "/v1/prices/" + url.PathEscape(priceID)
+	body, err := s.callStripeEndpoint(logger, "GET", apiURL, nil, []int{http.StatusOK})
+	if err != nil {
+		return nil, catalogRequestError(err, ErrPaymentProviderCatalogItemNotFound)
+	}
+
+	price, err := stripeCatalogPriceFromResponse(body)
+	if err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("retrieved-catalog-price")
+
+	return price, nil
+}
+
+// CreateCatalogPrice creates a price on Stripe for an existing product. Stripe does not
+// allow the amount or interval of a price to change after it is created
+func (s *StripeProvider) CreateCatalogPrice(ctx context.Context, price *CatalogPrice) (*CatalogPrice, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "create-catalog-price")).With(zap.String("stripe-product-id", price.ProductID))
+
+	logger.Info("handle-request-to-create-catalog-price")
+
+	if price.ProductID == "" || price.Currency == "" {
+		logger.Warn("missing-product-or-currency-for-catalog-price")
+		return nil, ErrPaymentProviderCatalogRequestFailed
+	}
+
+	form := url.Values{}
+	form.Set("product", price.ProductID)
+	form.Set("unit_amount", strconv.FormatInt(price.Amount, 10))
+	form.Set("currency", strings.ToLower(price.Currency))
+	if price.Interval != "" {
+		intervalCount := price.IntervalCount
+		if intervalCount <= 0 {
+			intervalCount = 1
+		}
+		form.Set("recurring[interval]", price.Interval)
+		form.Set("recurring[interval_count]", strconv.FormatInt(intervalCount, 10))
+	}
+	for key, value := range price.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	apiURL := s.getAPIBaseURL() + "/v1/prices"
+	body, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	if err != nil {
+		return nil, catalogRequestError(err, ErrPaymentProviderCatalogRequestFailed)
+	}
+
+	created, err := stripeCatalogPriceFromResponse(body)
+	if err != nil || created.ID == "" {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("created-catalog-price", zap.String("stripe-price-id", created.ID))
+
+	return created, nil
+}
+
+// ArchiveCatalogPrice deactivates a price on Stripe so it can no longer be used for
+// new checkouts
+func (s *StripeProvider) ArchiveCatalogPrice(ctx context.Context, priceID string) error {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "archive-catalog-price")).With(zap.String("stripe-price-id", priceID))
+
+	logger.Info("handle-request-to-archive-catalog-price")
+
+	form := url.Values{}
+	form.Set("active", "false")
+
+	apiURL := s.getAPIBaseURL() + "/v1/prices/" + url.PathEscape(priceID)
+	if _, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK}); err != nil {
+		return catalogRequestError(err, ErrPaymentProviderCatalogRequestFailed)
+	}
+
+	logger.Info("archived-catalog-price")
+
+	return nil
+}
+
+// CancelSubscription cancels the Stripe subscription. Cancelling at the period end keeps the
+// subscription active until then, otherwise it is cancelled straight away
+func (s *StripeProvider) CancelSubscription(ctx context.Context, req *SubscriptionChangeRequest) (*SubscriptionChange, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "cancel-subscription")).With(zap.String("stripe-subscription-id", req.SubscriptionID)).With(zap.Bool("at-period-end", req.AtPeriodEnd))
+
+	logger.Info("handle-request-to-cancel-subscription")
+
+	if req.SubscriptionID == "" {
+		logger.Warn("missing-subscription-id-for-subscription-change")
+		return nil, ErrPaymentProviderMissingSubscriptionID
+	}
+
+	var (
+		apiURL = s.getAPIBaseURL() + "/v1/subscriptions/" + url.PathEscape(req.SubscriptionID)
+		body   []byte
+		err    error
+	)
+
+	if req.AtPeriodEnd {
+		form := url.Values{}
+		form.Set("cancel_at_period_end", "true")
+		body, err = s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	} else {
+		body, err = s.callStripeEndpoint(logger, "DELETE", apiURL, nil, []int{http.StatusOK})
+	}
+	if err != nil {
+		return nil, subscriptionChangeRequestError(err)
+	}
+
+	effectiveAtField := "canceled_at"
+	if req.AtPeriodEnd {
+		effectiveAtField = "current_period_end"
+	}
+
+	change, err := stripeSubscriptionChangeFromResponse(body, effectiveAtField)
+	if err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("cancelled-subscription", zap.String("status", change.Status))
+
+	return change, nil
+}
+
+// PauseSubscription pauses payment collection for the Stripe subscription, voiding the invoices
+// raised while it is paused. Stripe only pauses collection from the next invoice, so the current
+// period is honoured whether or not the pause is requested for the period end
+func (s *StripeProvider) PauseSubscription(ctx context.Context, req *SubscriptionChangeRequest) (*SubscriptionChange, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "pause-subscription")).With(zap.String("stripe-subscription-id", req.SubscriptionID)).With(zap.Bool("at-period-end", req.AtPeriodEnd))
+
+	logger.Info("handle-request-to-pause-subscription")
+
+	if req.SubscriptionID == "" {
+		logger.Warn("missing-subscription-id-for-subscription-change")
+		return nil, ErrPaymentProviderMissingSubscriptionID
+	}
+
+	form := url.Values{}
+	form.Set("pause_collection[behavior]", "void")
+
+	apiURL := s.getAPIBaseURL() + "/v1/subscriptions/" + url.PathEscape(req.SubscriptionID)
+	body, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	if err != nil {
+		return nil, subscriptionChangeRequestError(err)
+	}
+
+	change, err := stripeSubscriptionChangeFromResponse(body, "current_period_end")
+	if err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	logger.Info("paused-subscription", zap.String("status", change.Status))
+
+	return change, nil
+}
+
+// RefundPayment refunds a Stripe payment intent or charge, in full when no amount is given.
+// The reason is attached as refund metadata because Stripe only accepts its own reason codes
+func (s *StripeProvider) RefundPayment(ctx context.Context, req *RefundRequest) (*Refund, error) {
+
+	logger := logger.AcquirePackageFrom(ctx, "external/paymentprovider").With(zap.String("provider", s.name)).With(zap.String("operation", "refund-payment")).With(zap.String("stripe-transaction-id", req.TransactionID)).With(zap.Int64("amount", req.Amount))
+
+	logger.Info("handle-request-to-refund-payment")
+
+	if req.TransactionID == "" {
+		logger.Warn("missing-transaction-id-for-refund")
+		return nil, ErrPaymentProviderMissingTransactionID
+	}
+
+	form := url.Values{}
+	if strings.HasPrefix(req.TransactionID, "ch_") {
+		form.Set("charge", req.TransactionID)
+	} else {
+		form.Set("payment_intent", req.TransactionID)
+	}
+	if req.Amount > 0 {
+		form.Set("amount", strconv.FormatInt(req.Amount, 10))
+	}
+	if req.Reason != "" {
+		form.Set("metadata[reason]", req.Reason)
+	}
+
+	apiURL := s.getAPIBaseURL() + "/v1/refunds"
+	body, err := s.callStripeEndpoint(logger, "POST", apiURL, strings.NewReader(form.Encode()), []int{http.StatusOK})
+	if err != nil {
+		return nil, refundRequestError(err)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		logger.Error("failed-to-parse-api-response", zap.Error(err))
+		return nil, ErrPaymentProviderAPIResponseInvalid
+	}
+
+	refund := &Refund{
+		ID:            getStringField(obj, "id"),
+		TransactionID: req.TransactionID,
+		Amount:        int64(getFloatField(obj, "amount")),
+		Currency:      strings.ToUpper(getStringField(obj, "currency")),
+		Status:        getStringField(obj, "status"),
+	}
+
+	logger.Info("refunded-payment", zap.String("refund-id", refund.ID), zap.String("status", refund.Status))
+
+	return refund, nil
+}
+
+// getAPIBaseURL returns the configured API base URL or Stripe's default
+func (s *StripeProvider) getAPIBaseURL() string {
+	if s.config.APIBaseURL != "" {
+		return s.config.APIBaseURL
+	}
+	return "https://api.stripe.com"
+}
+
 // getProductDetailsByProductID retrieves product details from Stripe's API
 func (s *StripeProvider) getProductDetailsByProductID(ctx context.Context, productID string) (string, error) {
 
@@ -383,6 +888,11 @@ func (s *StripeProvider) callStripeEndpoint(logger *zap.Logger, method, endpoint
 	req.Header.Set("Accept", "application/vnd.api+json")
 	req.Header.Set("Content-Type", "application/vnd.api+json")
 
+	// Stripe only accepts form encoded request bodies
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		logger.Error("http-request-failed", zap.Error(err))
@@ -407,6 +917,118 @@ func (s *StripeProvider) callStripeEndpoint(logger *zap.Logger, method, endpoint
 
 // Helper functions
 
+// sessionRequestError converts an unexpected status from a provider endpoint into
+// the error returned for rejected checkout and customer portal session requests
+func sessionRequestError(err error) error {
+	if errors.Is(err, ErrPaymentProviderSubscriptionNotFound) {
+		return ErrPaymentProviderCheckoutRequestFailed
+	}
+	return err
+}
+
+// catalogRequestError converts an unexpected status from a provider catalog endpoint
+// into the given catalog error
+func catalogRequestError(err error, catalogErr error) error {
+	if errors.Is(err, ErrPaymentProviderSubscriptionNotFound) {
+		return catalogErr
+	}
+	return err
+}
+
+// subscriptionChangeRequestError converts an unexpected status from a provider endpoint into
+// the error returned for rejected subscription cancellations and pauses
+func subscriptionChangeRequestError(err error) error {
+	if errors.Is(err, ErrPaymentProviderSubscriptionNotFound) {
+		return ErrPaymentProviderSubscriptionChangeRequestFailed
+	}
+	return err
+}
+
+// refundRequestError converts an unexpected status from a provider endpoint into the
+// error returned for rejected refunds
+func refundRequestError(err error) error {
+	if errors.Is(err, ErrPaymentProviderSubscriptionNotFound) {
+		return ErrPaymentProviderRefundRequestFailed
+	}
+	return err
+}
+
+// stripeSubscriptionChangeFromResponse parses a Stripe subscription object, taking when the
+// change takes effect from the given timestamp field
+func stripeSubscriptionChangeFromResponse(body []byte, effectiveAtField string) (*SubscriptionChange, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, err
+	}
+
+	change := &SubscriptionChange{
+		SubscriptionID: getStringField(obj, "id"),
+		Status:         stripeStatusToStandard(getStringField(obj, "status")),
+	}
+	if effectiveAt, ok := obj[effectiveAtField].(float64); ok {
+		change.EffectiveAt = time.Unix(int64(effectiveAt), 0).UTC().Format(time.RFC3339)
+	}
+
+	return change, nil
+}
+
+// stripeCatalogProductFromResponse parses a Stripe product object
+func stripeCatalogProductFromResponse(body []byte) (*CatalogProduct, error) {
+	var obj struct {
+		ID          string            `json:"id"`
+		Name        string            `json:"name"`
+		Description string            `json:"description"`
+		Active      bool              `json:"active"`
+		Metadata    map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, err
+	}
+
+	return &CatalogProduct{
+		ID:          obj.ID,
+		Name:        obj.Name,
+		Description: obj.Description,
+		Active:      obj.Active,
+		Metadata:    obj.Metadata,
+	}, nil
+}
+
+// stripeCatalogPriceFromResponse parses a Stripe price object. Currency is returned in
+// upper case to match the pricer catalog
+func stripeCatalogPriceFromResponse(body []byte) (*CatalogPrice, error) {
+	var obj struct {
+		ID         string `json:"id"`
+		Product    string `json:"product"`
+		UnitAmount int64  `json:"unit_amount"`
+		Currency   string `json:"currency"`
+		Active     bool   `json:"active"`
+		Recurring  *struct {
+			Interval      string `json:"interval"`
+			IntervalCount int64  `json:"interval_count"`
+		} `json:"recurring"`
+		Metadata map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, err
+	}
+
+	price := &CatalogPrice{
+		ID:        obj.ID,
+		ProductID: obj.Product,
+		Amount:    obj.UnitAmount,
+		Currency:  strings.ToUpper(obj.Currency),
+		Active:    obj.Active,
+		Metadata:  obj.Metadata,
+	}
+	if obj.Recurring != nil {
+		price.Interval = obj.Recurring.Interval
+		price.IntervalCount = obj.Recurring.IntervalCount
+	}
+
+	return price, nil
+}
+
 func stripeEventToStandard(eventType string) string {
 	switch eventType {
 	case "customer.subscription.created":
@@ -0,0 +1,386 @@
+package paymentprovider_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/paymentprovider"
+)
+
+func newTestStripeProvider(t *testing.T, handler http.HandlerFunc) *paymentprovider.StripeProvider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := paymentprovider.NewStripeProvider(&paymentprovider.Config{
+		ProviderName:  "stripe",
+		APIKey:        "sk_test_123",
+		WebhookSecret: "whsec_123",
+		APIBaseURL:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating provider, got %v", err)
+	}
+
+	return provider
+}
+
+func TestStripeCreateCheckoutSession(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/checkout/sessions" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk_test_123" {
+			t.Errorf("expected bearer api key, got %q", got)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("expected form body, got %v", err)
+		}
+
+		expected := map[string]string{
+			"mode":                                        "subscription",
+			"line_items[0][price]":                        "price_123",
+			"line_items[0][quantity]":                     "1",
+			"success_url":                                 "https://app.example.com/success",
+			"cancel_url":                                  "https://app.example.com/cancel",
+			"customer_email":                              "customer@example.com",
+			"client_reference_id":                         "user-1",
+			"metadata[user_id]":                           "user-1",
+			"subscription_data[metadata][user_id]":        "user-1",
+			"subscription_data[trial_period_days]":        "14",
+			"discounts[0][coupon]":                        "LAUNCH",
+			"subscription_data[metadata][customer_email]": "customer@example.com",
+		}
+		for key, value := range expected {
+			if got := r.PostForm.Get(key); got != value {
+				t.Errorf("expected %s=%q, got %q", key, value, got)
+			}
+		}
+
+		w.Write([]byte(`{"id":"cs_test_1","url":"https://checkout.stripe.com/c/pay/cs_test_1","expires_at":1767268800}`))
+	})
+
+	session, err := provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{
+		PriceID:         "price_123",
+		UserID:          "user-1",
+		CustomerEmail:   "customer@example.com",
+		SuccessURL:      "https://app.example.com/success",
+		CancelURL:       "https://app.example.com/cancel",
+		TrialPeriodDays: 14,
+		CouponCode:      "LAUNCH",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if session.ID != "cs_test_1" || session.URL != "https://checkout.stripe.com/c/pay/cs_test_1" {
+		t.Fatalf("unexpected session %#v", session)
+	}
+	if session.ExpiresAt != "2026-01-01T12:00:00Z" {
+		t.Fatalf("expected expiry to be converted, got %q", session.ExpiresAt)
+	}
+}
+
+func TestStripeCreateCheckoutSessionErrors(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"No such price"}}`))
+	})
+
+	if _, err := provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{}); !errors.Is(err, paymentprovider.ErrPaymentProviderMissingCheckoutPrice) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingCheckoutPrice, err)
+	}
+
+	if _, err := provider.CreateCheckoutSession(context.Background(), &paymentprovider.CheckoutSessionRequest{PriceID: "price_missing"}); !errors.Is(err, paymentprovider.ErrPaymentProviderCheckoutRequestFailed) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderCheckoutRequestFailed, err)
+	}
+}
+
+func TestStripeCreateCustomerPortalSession(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/billing_portal/sessions" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("expected form body, got %v", err)
+		}
+		if r.PostForm.Get("customer") != "cus_123" || r.PostForm.Get("return_url") != "https://app.example.com/billing" {
+			t.Errorf("unexpected form %v", r.PostForm)
+		}
+
+		w.Write([]byte(`{"id":"bps_1","url":"https://billing.stripe.com/p/session/bps_1"}`))
+	})
+
+	if _, err := provider.CreateCustomerPortalSession(context.Background(), &paymentprovider.CustomerPortalSessionRequest{}); !errors.Is(err, paymentprovider.ErrPaymentProviderMissingCustomerID) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingCustomerID, err)
+	}
+
+	session, err := provider.CreateCustomerPortalSession(context.Background(), &paymentprovider.CustomerPortalSessionRequest{
+		CustomerID: "cus_123",
+		ReturnURL:  "https://app.example.com/billing",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.URL != "https://billing.stripe.com/p/session/bps_1" {
+		t.Fatalf("unexpected session %#v", session)
+	}
+}
+
+func TestStripeReportUsage(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/billing/meter_events" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("expected form body, got %v", err)
+		}
+
+		expected := map[string]string{
+			"event_name":                  "api_calls",
+			"payload[stripe_customer_id]": "cus_123",
+			"payload[value]":              "25",
+			"identifier":                  "usage-1",
+			"timestamp":                   "1767268800",
+		}
+		for key, value := range expected {
+			if got := r.PostForm.Get(key); got != value {
+				t.Errorf("expected %s=%q, got %q", key, value, got)
+			}
+		}
+
+		w.Write([]byte(`{"object":"billing.meter_event","identifier":"usage-1"}`))
+	})
+
+	report, err := provider.ReportUsage(context.Background(), &paymentprovider.UsageReportRequest{
+		EventName:  "api_calls",
+		CustomerID: "cus_123",
+		Quantity:   25,
+		Identifier: "usage-1",
+		Timestamp:  time.Unix(1767268800, 0),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Identifier != "usage-1" {
+		t.Fatalf("unexpected report %#v", report)
+	}
+
+	if _, err := provider.ReportUsage(context.Background(), &paymentprovider.UsageReportRequest{CustomerID: "cus_123"}); !errors.Is(err, paymentprovider.ErrPaymentProviderMissingUsageEventName) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingUsageEventName, err)
+	}
+}
+
+func TestProviderRegistryReportUsageRequiresSupport(t *testing.T) {
+	registry := paymentprovider.NewProviderRegistry()
+	lemonSqueezy, err := paymentprovider.NewLemonSqueezyProvider(&paymentprovider.Config{
+		ProviderName:  "lemonsqueezy",
+		APIKey:        "ls_test",
+		WebhookSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating provider, got %v", err)
+	}
+	registry.Register(lemonSqueezy)
+
+	if _, err := registry.ReportUsage(context.Background(), "lemonsqueezy", &paymentprovider.UsageReportRequest{}); !errors.Is(err, paymentprovider.ErrPaymentProviderUsageReportingNotSupported) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderUsageReportingNotSupported, err)
+	}
+}
+
+func TestStripeSaveCatalogProduct(t *testing.T) {
+	var paths []string
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("expected form body, got %v", err)
+		}
+		if got := r.PostForm.Get("name"); got != "Pro" {
+			t.Errorf("expected name Pro, got %q", got)
+		}
+		if got := r.PostForm.Get("metadata[price_plan_id]"); got != "plan-1" {
+			t.Errorf("expected price plan metadata, got %q", got)
+		}
+
+		w.Write([]byte(`{"id":"prod_123","name":"Pro","active":true,"metadata":{"price_plan_id":"plan-1"}}`))
+	})
+
+	product := &paymentprovider.CatalogProduct{Name: "Pro", Active: true, Metadata: map[string]string{"price_plan_id": "plan-1"}}
+
+	created, err := provider.SaveCatalogProduct(context.Background(), product)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.ID != "prod_123" {
+		t.Fatalf("expected product id prod_123, got %q", created.ID)
+	}
+
+	if _, err := provider.SaveCatalogProduct(context.Background(), created); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(paths) != 2 || paths[0] != "/v1/products" || paths[1] != "/v1/products/prod_123" {
+		t.Fatalf("expected create then update, got %v", paths)
+	}
+}
+
+func TestStripeCreateCatalogPrice(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/prices" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("expected form body, got %v", err)
+		}
+
+		expected := map[string]string{
+			"product":                   "prod_123",
+			"unit_amount":               "1500",
+			"currency":                  "gbp",
+			"recurring[interval]":       "month",
+			"recurring[interval_count]": "1",
+		}
+		for key, value := range expected {
+			if got := r.PostForm.Get(key); got != value {
+				t.Errorf("expected %s=%q, got %q", key, value, got)
+			}
+		}
+
+		w.Write([]byte(`{"id":"price_123","product":"prod_123","unit_amount":1500,"currency":"gbp","active":true,"recurring":{"interval":"month","interval_count":1}}`))
+	})
+
+	price, err := provider.CreateCatalogPrice(context.Background(), &paymentprovider.CatalogPrice{
+		ProductID: "prod_123",
+		Amount:    1500,
+		Currency:  "GBP",
+		Interval:  "month",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if price.ID != "price_123" || price.Currency != "GBP" || price.Interval != "month" || price.IntervalCount != 1 {
+		t.Fatalf("unexpected price %+v", price)
+	}
+}
+
+func TestStripeGetCatalogPriceNotFound(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if _, err := provider.GetCatalogPrice(context.Background(), "price_missing"); !errors.Is(err, paymentprovider.ErrPaymentProviderCatalogItemNotFound) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderCatalogItemNotFound, err)
+	}
+}
+
+func TestStripeCancelSubscription(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/subscriptions/sub_123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("expected form body, got %v", err)
+			}
+			if got := r.PostForm.Get("cancel_at_period_end"); got != "true" {
+				t.Errorf("expected cancel_at_period_end=true, got %q", got)
+			}
+			w.Write([]byte(`{"id":"sub_123","object":"subscription","status":"active","cancel_at_period_end":true,"current_period_end":1767268800}`))
+		case http.MethodDelete:
+			w.Write([]byte(`{"id":"sub_123","object":"subscription","status":"canceled","canceled_at":1764590400}`))
+		}
+	})
+
+	change, err := provider.CancelSubscription(context.Background(), &paymentprovider.SubscriptionChangeRequest{SubscriptionID: "sub_123", AtPeriodEnd: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if change.Status != paymentprovider.SubscriptionStatusActive || change.EffectiveAt != "2026-01-01T12:00:00Z" {
+		t.Fatalf("expected cancellation scheduled for the period end, got %#v", change)
+	}
+
+	change, err = provider.CancelSubscription(context.Background(), &paymentprovider.SubscriptionChangeRequest{SubscriptionID: "sub_123"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if change.Status != paymentprovider.SubscriptionStatusCancelled || change.EffectiveAt != "2025-12-01T12:00:00Z" {
+		t.Fatalf("expected immediate cancellation, got %#v", change)
+	}
+
+	if _, err := provider.CancelSubscription(context.Background(), &paymentprovider.SubscriptionChangeRequest{}); !errors.Is(err, paymentprovider.ErrPaymentProviderMissingSubscriptionID) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingSubscriptionID, err)
+	}
+}
+
+func TestStripeRefundPayment(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/refunds" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("expected form body, got %v", err)
+		}
+
+		if r.PostForm.Get("payment_intent") == "pi_declined" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		expected := map[string]string{
+			"payment_intent":   "pi_123",
+			"amount":           "500",
+			"metadata[reason]": "Goodwill",
+		}
+		for key, value := range expected {
+			if got := r.PostForm.Get(key); got != value {
+				t.Errorf("expected %s=%q, got %q", key, value, got)
+			}
+		}
+
+		w.Write([]byte(`{"id":"re_123","object":"refund","amount":500,"currency":"gbp","payment_intent":"pi_123","status":"succeeded"}`))
+	})
+
+	refund, err := provider.RefundPayment(context.Background(), &paymentprovider.RefundRequest{TransactionID: "pi_123", Amount: 500, Reason: "Goodwill"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refund.ID != "re_123" || refund.Amount != 500 || refund.Currency != "GBP" || refund.Status != "succeeded" {
+		t.Fatalf("unexpected refund %#v", refund)
+	}
+
+	if _, err := provider.RefundPayment(context.Background(), &paymentprovider.RefundRequest{TransactionID: "pi_declined"}); !errors.Is(err, paymentprovider.ErrPaymentProviderRefundRequestFailed) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderRefundRequestFailed, err)
+	}
+
+	if _, err := provider.RefundPayment(context.Background(), &paymentprovider.RefundRequest{}); !errors.Is(err, paymentprovider.ErrPaymentProviderMissingTransactionID) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderMissingTransactionID, err)
+	}
+}
+
+func TestProviderRegistryRefundPaymentRequiresSupport(t *testing.T) {
+	registry := paymentprovider.NewProviderRegistry()
+	lemonSqueezy, err := paymentprovider.NewLemonSqueezyProvider(&paymentprovider.Config{
+		ProviderName:  "lemonsqueezy",
+		APIKey:        "ls_test",
+		WebhookSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating provider, got %v", err)
+	}
+	registry.Register(lemonSqueezy)
+
+	if _, err := registry.RefundPayment(context.Background(), "lemonsqueezy", &paymentprovider.RefundRequest{TransactionID: "1"}); !errors.Is(err, paymentprovider.ErrPaymentProviderRefundsNotSupported) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderRefundsNotSupported, err)
+	}
+
+	if _, err := registry.CancelSubscription(context.Background(), "lemonsqueezy", &paymentprovider.SubscriptionChangeRequest{SubscriptionID: "1"}); !errors.Is(err, paymentprovider.ErrPaymentProviderSubscriptionManagementNotSupported) {
+		t.Fatalf("expected %v, got %v", paymentprovider.ErrPaymentProviderSubscriptionManagementNotSupported, err)
+	}
+}
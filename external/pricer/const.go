@@ -71,6 +71,36 @@ const (
 	// ErrKeyInvalidPriceDate is returned when a price date value cannot be parsed.
 	ErrKeyInvalidPriceDate = "InvalidPriceDate"
 
+	// ErrKeyPricePlanVersionNotFound is returned when a price plan version cannot be found.
+	ErrKeyPricePlanVersionNotFound = "PricePlanVersionNotFound"
+
+	// ErrKeyInvalidPricePlanVersionMigration is returned when a grandfathering rule or migration is invalid.
+	ErrKeyInvalidPricePlanVersionMigration = "InvalidPricePlanVersionMigration"
+
+	// ErrKeyInvalidPriceQuote is returned when a price quote request is invalid.
+	ErrKeyInvalidPriceQuote = "InvalidPriceQuote"
+
+	// ErrKeyPriceCostNotFound is returned when a plan has no cost matching a quote request.
+	ErrKeyPriceCostNotFound = "PriceCostNotFound"
+
+	// ErrKeyInvalidPriceCoupon is returned when a coupon code is unknown or cannot be used.
+	ErrKeyInvalidPriceCoupon = "InvalidPriceCoupon"
+
+	// ErrKeyPricePromotionCodeNotFound is returned when a promotion code cannot be found.
+	ErrKeyPricePromotionCodeNotFound = "PricePromotionCodeNotFound"
+
+	// ErrKeyInvalidPricePromotionCodePayload is returned when a promotion code payload fails validation.
+	ErrKeyInvalidPricePromotionCodePayload = "InvalidPricePromotionCodePayload"
+
+	// ErrKeyDuplicatePricePromotionCode is returned when a promotion code is already in use.
+	ErrKeyDuplicatePricePromotionCode = "DuplicatePricePromotionCode"
+
+	// ErrKeyDuplicatePricePromotionCodeRedemption is returned when a promotion code redemption is already recorded.
+	ErrKeyDuplicatePricePromotionCodeRedemption = "DuplicatePricePromotionCodeRedemption"
+
+	// ErrKeyPricePromotionCodeNotRedeemable is returned when a promotion code cannot be redeemed.
+	ErrKeyPricePromotionCodeNotRedeemable = "PricePromotionCodeNotRedeemable"
+
 	// ErrKeyDatabaseError is returned when a database operation fails.
 	ErrKeyDatabaseError = "PricerDatabaseError"
 )
@@ -143,6 +173,9 @@ const (
 
 	// PriceProviderKofi represents kofi provider references.
 	PriceProviderKofi PriceProvider = "kofi"
+
+	// PriceProviderLemonSqueezy represents Lemon Squeezy provider references.
+	PriceProviderLemonSqueezy PriceProvider = "lemonsqueezy"
 )
 
 const (
@@ -180,3 +213,48 @@ const (
 	// PricePaymentCollectionMethodInvoice represents invoice-based collection.
 	PricePaymentCollectionMethodInvoice PricePaymentCollectionMethod = "invoice"
 )
+
+// PriceGrandfatherPolicy represents what happens to a superseded version's subscribers.
+type PriceGrandfatherPolicy string
+
+const (
+	// PriceGrandfatherPolicyKeep keeps subscribers on the superseded version's terms until a
+	// migration is scheduled.
+	PriceGrandfatherPolicyKeep PriceGrandfatherPolicy = "keep"
+
+	// PriceGrandfatherPolicyMigrate migrates subscribers to a newer version at a set time.
+	PriceGrandfatherPolicyMigrate PriceGrandfatherPolicy = "migrate"
+)
+
+// PricePromotionCodeReason represents why a promotion code cannot be redeemed.
+type PricePromotionCodeReason string
+
+const (
+	// PricePromotionCodeReasonNotFound indicates no promotion code matches the code.
+	PricePromotionCodeReasonNotFound PricePromotionCodeReason = "not_found"
+
+	// PricePromotionCodeReasonInactive indicates the promotion code is switched off or deleted.
+	PricePromotionCodeReasonInactive PricePromotionCodeReason = "inactive"
+
+	// PricePromotionCodeReasonExpired indicates the promotion code or its discount has expired.
+	PricePromotionCodeReasonExpired PricePromotionCodeReason = "expired"
+
+	// PricePromotionCodeReasonMaxRedemptionsReached indicates the promotion code has been redeemed
+	// as many times as allowed.
+	PricePromotionCodeReasonMaxRedemptionsReached PricePromotionCodeReason = "max_redemptions_reached"
+
+	// PricePromotionCodeReasonUserLimitReached indicates the user has redeemed the promotion code
+	// as many times as allowed.
+	PricePromotionCodeReasonUserLimitReached PricePromotionCodeReason = "user_limit_reached"
+
+	// PricePromotionCodeReasonFirstTimeCustomersOnly indicates the promotion code is for users
+	// who have never subscribed.
+	PricePromotionCodeReasonFirstTimeCustomersOnly PricePromotionCodeReason = "first_time_customers_only"
+
+	// PricePromotionCodeReasonPlanNotEligible indicates the promotion code cannot be used for the plan.
+	PricePromotionCodeReasonPlanNotEligible PricePromotionCodeReason = "plan_not_eligible"
+
+	// PricePromotionCodeReasonCadenceNotEligible indicates the promotion code cannot be used for
+	// the billing cadence.
+	PricePromotionCodeReasonCadenceNotEligible PricePromotionCodeReason = "cadence_not_eligible"
+)
@@ -37,4 +37,34 @@ var PricerErrorMap reply.ErrorManifest = reply.ErrorManifest{
 	ErrInvalidPriceDate:         {Title: "Bad Request", Detail: "Invalid price date value", StatusCode: 400, Code: "PRC0-21"},
 	ErrInvalidPriceDiscount:     {Title: "Bad Request", Detail: "Invalid price discount", StatusCode: 400, Code: "PRC0-22"},
 	ErrInvalidPricePaymentTerms: {Title: "Bad Request", Detail: "Invalid price payment terms", StatusCode: 400, Code: "PRC0-23"},
+	ErrPricePlanVersionNotFound: {Title: "Not Found", Detail: "Price plan version not found", StatusCode: 404, Code: "PRC0-24"},
+	ErrInvalidPricePlanVersionMigration: {
+		Title:      "Bad Request",
+		Detail:     "Invalid price plan version migration",
+		StatusCode: 400,
+		Code:       "PRC0-25",
+	},
+	ErrInvalidPriceQuote:          {Title: "Bad Request", Detail: "Invalid price quote request", StatusCode: 400, Code: "PRC0-26"},
+	ErrPriceCostNotFound:          {Title: "Not Found", Detail: "Price cost not found", StatusCode: 404, Code: "PRC0-27"},
+	ErrInvalidPriceCoupon:         {Title: "Bad Request", Detail: "Invalid or expired coupon code", StatusCode: 400, Code: "PRC0-28"},
+	ErrPricePromotionCodeNotFound: {Title: "Not Found", Detail: "Promotion code not found", StatusCode: 404, Code: "PRC0-29"},
+	ErrInvalidPricePromotionCodePayload: {
+		Title:      "Bad Request",
+		Detail:     "Invalid promotion code payload",
+		StatusCode: 400,
+		Code:       "PRC0-30",
+	},
+	ErrDuplicatePricePromotionCode: {Title: "Conflict", Detail: "Promotion code already exists", StatusCode: 409, Code: "PRC0-31"},
+	ErrPricePromotionCodeNotRedeemable: {
+		Title:      "Bad Request",
+		Detail:     "Promotion code cannot be redeemed",
+		StatusCode: 400,
+		Code:       "PRC0-32",
+	},
+	ErrDuplicatePricePromotionCodeRedemption: {
+		Title:      "Conflict",
+		Detail:     "Promotion code redemption already exists",
+		StatusCode: 409,
+		Code:       "PRC0-33",
+	},
 }
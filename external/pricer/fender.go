@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 
 	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
 	"github.com/ooaklee/ghatd/external/toolbox"
@@ -285,6 +286,253 @@ func MapRequestToDeleteFeatureRequest(request *http.Request, validator PricerVal
 	return parsedRequest, nil
 }
 
+// MapRequestToGetPricePlanVersionsRequest maps incoming GetPricePlanVersions request to correct struct.
+func MapRequestToGetPricePlanVersionsRequest(request *http.Request, validator PricerValidator) (*GetPricePlanVersionsRequest, error) {
+	parsedRequest := &GetPricePlanVersionsRequest{}
+	if err := decodeQuery(request, parsedRequest); err != nil {
+		return nil, err
+	}
+
+	id, err := toolbox.GetVariableValueFromUri(request, "id")
+	if err != nil {
+		return nil, ErrPricePlanIDRequired
+	}
+	parsedRequest.PricePlanID = id
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPriceQueryParam
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToGetPricePlanVersionRequest maps incoming GetPricePlanVersion request to correct struct.
+func MapRequestToGetPricePlanVersionRequest(request *http.Request, validator PricerValidator) (*GetPricePlanVersionRequest, error) {
+	parsedRequest := &GetPricePlanVersionRequest{}
+
+	id, err := toolbox.GetVariableValueFromUri(request, "id")
+	if err != nil {
+		return nil, ErrPricePlanIDRequired
+	}
+	parsedRequest.PricePlanID = id
+
+	version, err := getPricePlanVersionFromUri(request)
+	if err != nil {
+		return nil, err
+	}
+	parsedRequest.Version = version
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPriceQueryParam
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToSchedulePricePlanVersionMigrationRequest maps incoming SchedulePricePlanVersionMigration request to correct struct.
+func MapRequestToSchedulePricePlanVersionMigrationRequest(request *http.Request, validator PricerValidator) (*SchedulePricePlanVersionMigrationRequest, error) {
+	parsedRequest := &SchedulePricePlanVersionMigrationRequest{}
+
+	if err := decodeOptionalBody(request, parsedRequest); err != nil {
+		return nil, ErrInvalidPricePlanVersionMigration
+	}
+
+	id, err := toolbox.GetVariableValueFromUri(request, "id")
+	if err != nil {
+		return nil, ErrPricePlanIDRequired
+	}
+	parsedRequest.PricePlanID = id
+
+	version, err := getPricePlanVersionFromUri(request)
+	if err != nil {
+		return nil, err
+	}
+	parsedRequest.Version = version
+
+	parsedRequest.UserID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.UserID == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPricePlanVersionMigration
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToQuotePricePlanRequest maps incoming QuotePricePlan request to correct struct. The
+// plan is taken from the `id` URI variable, or the `slug` one when there is no ID.
+func MapRequestToQuotePricePlanRequest(request *http.Request, validator PricerValidator) (*QuotePricePlanRequest, error) {
+	parsedRequest := &QuotePricePlanRequest{}
+	if err := decodeQuery(request, parsedRequest); err != nil {
+		return nil, err
+	}
+
+	if id, err := toolbox.GetVariableValueFromUri(request, "id"); err == nil {
+		parsedRequest.PricePlanID = id
+	} else if slug, err := toolbox.GetVariableValueFromUri(request, "slug"); err == nil {
+		parsedRequest.PricePlanSlug = slug
+	} else {
+		return nil, ErrPricePlanIDRequired
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPriceQuote
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToCreatePromotionCodeRequest maps incoming CreatePromotionCode request to correct struct.
+func MapRequestToCreatePromotionCodeRequest(request *http.Request, validator PricerValidator) (*CreatePromotionCodeRequest, error) {
+	parsedRequest := &CreatePromotionCodeRequest{}
+	if err := toolbox.DecodeRequestBody(request, parsedRequest); err != nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+
+	parsedRequest.UserID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.UserID == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToUpdatePromotionCodeRequest maps incoming UpdatePromotionCode request to correct struct.
+func MapRequestToUpdatePromotionCodeRequest(request *http.Request, validator PricerValidator) (*UpdatePromotionCodeRequest, error) {
+	parsedRequest := &UpdatePromotionCodeRequest{}
+
+	id, err := toolbox.GetVariableValueFromUri(request, "id")
+	if err != nil {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+
+	if err := toolbox.DecodeRequestBody(request, parsedRequest); err != nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+	parsedRequest.ID = id
+
+	parsedRequest.UserID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.UserID == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToGetPromotionCodeByIDRequest maps incoming GetPromotionCodeByID request to correct struct.
+func MapRequestToGetPromotionCodeByIDRequest(request *http.Request, validator PricerValidator) (*GetPromotionCodeByIDRequest, error) {
+	parsedRequest := &GetPromotionCodeByIDRequest{}
+
+	id, err := toolbox.GetVariableValueFromUri(request, "id")
+	if err != nil {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+	parsedRequest.ID = id
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToGetPromotionCodesRequest maps incoming GetPromotionCodes request to correct struct.
+func MapRequestToGetPromotionCodesRequest(request *http.Request, validator PricerValidator) (*GetPromotionCodesRequest, error) {
+	parsedRequest := &GetPromotionCodesRequest{}
+	if err := decodeQuery(request, parsedRequest); err != nil {
+		return nil, err
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPriceQueryParam
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToDeletePromotionCodeRequest maps incoming DeletePromotionCode request to correct struct.
+func MapRequestToDeletePromotionCodeRequest(request *http.Request, validator PricerValidator) (*DeletePromotionCodeRequest, error) {
+	parsedRequest := &DeletePromotionCodeRequest{}
+
+	id, err := toolbox.GetVariableValueFromUri(request, "id")
+	if err != nil {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+	parsedRequest.ID = id
+
+	parsedRequest.UserID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.UserID == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToGetPromotionCodeRedemptionsRequest maps incoming GetPromotionCodeRedemptions request to correct struct.
+func MapRequestToGetPromotionCodeRedemptionsRequest(request *http.Request, validator PricerValidator) (*GetPromotionCodeRedemptionsRequest, error) {
+	parsedRequest := &GetPromotionCodeRedemptionsRequest{}
+	if err := decodeQuery(request, parsedRequest); err != nil {
+		return nil, err
+	}
+
+	id, err := toolbox.GetVariableValueFromUri(request, "id")
+	if err != nil {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+	parsedRequest.PromotionCodeID = id
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPriceQueryParam
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToValidatePromotionCodeRequest maps incoming ValidatePromotionCode request to correct
+// struct. The user is optional, as codes can be checked before signing in.
+func MapRequestToValidatePromotionCodeRequest(request *http.Request, validator PricerValidator) (*ValidatePromotionCodeRequest, error) {
+	parsedRequest := &ValidatePromotionCodeRequest{}
+	if err := decodeQuery(request, parsedRequest); err != nil {
+		return nil, err
+	}
+
+	parsedRequest.UserID = accessmanagerhelpers.AcquireFrom(request.Context())
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+
+	return parsedRequest, nil
+}
+
+func getPricePlanVersionFromUri(request *http.Request) (int, error) {
+	value, err := toolbox.GetVariableValueFromUri(request, "version")
+	if err != nil {
+		return 0, ErrPricePlanVersionNotFound
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, ErrPricePlanVersionNotFound
+	}
+
+	return version, nil
+}
+
 func validateParsedRequest(request interface{}, validator PricerValidator) error {
 	if validator == nil {
 		return nil
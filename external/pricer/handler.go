@@ -25,6 +25,17 @@ type PriceService interface {
 	UpdateFeature(ctx context.Context, r *UpdateFeatureRequest) (*UpdateFeatureResponse, error)
 	GetFeatures(ctx context.Context, r *GetFeaturesRequest) (*GetFeaturesResponse, error)
 	DeleteFeature(ctx context.Context, r *DeleteFeatureRequest) (*DeleteFeatureResponse, error)
+	GetPricePlanVersions(ctx context.Context, r *GetPricePlanVersionsRequest) (*GetPricePlanVersionsResponse, error)
+	GetPricePlanVersion(ctx context.Context, r *GetPricePlanVersionRequest) (*GetPricePlanVersionResponse, error)
+	SchedulePricePlanVersionMigration(ctx context.Context, r *SchedulePricePlanVersionMigrationRequest) (*SchedulePricePlanVersionMigrationResponse, error)
+	QuotePricePlan(ctx context.Context, r *QuotePricePlanRequest) (*QuotePricePlanResponse, error)
+	CreatePromotionCode(ctx context.Context, r *CreatePromotionCodeRequest) (*CreatePromotionCodeResponse, error)
+	UpdatePromotionCode(ctx context.Context, r *UpdatePromotionCodeRequest) (*UpdatePromotionCodeResponse, error)
+	GetPromotionCodeByID(ctx context.Context, r *GetPromotionCodeByIDRequest) (*GetPromotionCodeByIDResponse, error)
+	GetPromotionCodes(ctx context.Context, r *GetPromotionCodesRequest) (*GetPromotionCodesResponse, error)
+	DeletePromotionCode(ctx context.Context, r *DeletePromotionCodeRequest) (*DeletePromotionCodeResponse, error)
+	GetPromotionCodeRedemptions(ctx context.Context, r *GetPromotionCodeRedemptionsRequest) (*GetPromotionCodeRedemptionsResponse, error)
+	ValidatePromotionCode(ctx context.Context, r *ValidatePromotionCodeRequest) (*ValidatePromotionCodeResponse, error)
 }
 
 // Handler manages pricer requests.
@@ -313,6 +324,242 @@ func (h *Handler) DeleteFeature(w http.ResponseWriter, r *http.Request) {
 	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Feature)
 }
 
+// GetPricePlanVersions handles getting a price plan's version history.
+func (h *Handler) GetPricePlanVersions(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-get-price-plan-versions")
+	request, err := MapRequestToGetPricePlanVersionsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetPricePlanVersions(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PricePlanVersions, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PricePlanVersions)
+}
+
+// GetPricePlanVersion handles getting a version of a price plan.
+func (h *Handler) GetPricePlanVersion(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-get-price-plan-version")
+	request, err := MapRequestToGetPricePlanVersionRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetPricePlanVersion(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PricePlanVersion)
+}
+
+// SchedulePricePlanVersionMigration handles setting the grandfathering rule of a superseded
+// price plan version.
+func (h *Handler) SchedulePricePlanVersionMigration(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-schedule-price-plan-version-migration")
+	request, err := MapRequestToSchedulePricePlanVersionMigrationRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.SchedulePricePlanVersionMigration(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PricePlanVersion)
+}
+
+// QuotePricePlan handles quoting one of a price plan's costs.
+func (h *Handler) QuotePricePlan(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-quote-price-plan")
+	request, err := MapRequestToQuotePricePlanRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.QuotePricePlan(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Quote)
+}
+
+// CreatePromotionCode handles promotion code creation.
+func (h *Handler) CreatePromotionCode(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-create-promotion-code")
+	request, err := MapRequestToCreatePromotionCodeRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.CreatePromotionCode(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.PromotionCode)
+}
+
+// UpdatePromotionCode handles promotion code updates.
+func (h *Handler) UpdatePromotionCode(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-update-promotion-code")
+	request, err := MapRequestToUpdatePromotionCodeRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.UpdatePromotionCode(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PromotionCode)
+}
+
+// GetPromotionCodeByID handles getting a promotion code by ID.
+func (h *Handler) GetPromotionCodeByID(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-get-promotion-code-by-id")
+	request, err := MapRequestToGetPromotionCodeByIDRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetPromotionCodeByID(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PromotionCode)
+}
+
+// GetPromotionCodes handles getting promotion codes.
+func (h *Handler) GetPromotionCodes(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-get-promotion-codes")
+	request, err := MapRequestToGetPromotionCodesRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetPromotionCodes(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PromotionCodes, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PromotionCodes)
+}
+
+// DeletePromotionCode handles promotion code soft deletion.
+func (h *Handler) DeletePromotionCode(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-delete-promotion-code")
+	request, err := MapRequestToDeletePromotionCodeRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.DeletePromotionCode(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.PromotionCode)
+}
+
+// GetPromotionCodeRedemptions handles getting a promotion code's redemptions.
+func (h *Handler) GetPromotionCodeRedemptions(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-get-promotion-code-redemptions")
+	request, err := MapRequestToGetPromotionCodeRedemptionsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetPromotionCodeRedemptions(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if request.Meta {
+		h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Redemptions, reply.WithMeta(response.GetMetaData()))
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Redemptions)
+}
+
+// ValidatePromotionCode handles checking whether a promotion code can be redeemed.
+func (h *Handler) ValidatePromotionCode(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/pricer", "handle-validate-promotion-code")
+	request, err := MapRequestToValidatePromotionCodeRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ValidatePromotionCode(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response)
+}
+
 func (h *Handler) getBaseResponseHandler() *reply.Replier {
 	return reply.NewReplier(
 		errormanifest.NewComposer().
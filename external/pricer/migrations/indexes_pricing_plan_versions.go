@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/pricer"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func InitPricingPlanVersionsIndexesUp(db *mongo.Database) error { //Up
+
+	log.SetFlags(0)
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-pricing-plan-versions-indexes"))
+
+	planVersionUniqueIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "price_plan_id", Value: 1}, {Key: "version", Value: -1}},
+		Options: options.Index().SetName("idx_pricing_plan_versions_price_plan_id_version").SetUnique(true),
+	}
+
+	grandfatheringMigrateAtIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "grandfathering.policy", Value: 1}, {Key: "grandfathering.migrate_at", Value: 1}},
+		Options: options.Index().SetName("idx_pricing_plan_versions_grandfathering_migrate_at").SetSparse(true),
+	}
+
+	_, err := db.Collection(pricer.PricePlanVersionsCollection).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			planVersionUniqueIndexModel,
+			grandfatheringMigrateAtIndexModel,
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-pricing-plan-versions-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-pricing-plan-versions-indexes"))
+	return nil
+
+}
+
+func InitPricingPlanVersionsIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-pricing-plan-versions-indexes"))
+
+	indexNames := []string{
+		"idx_pricing_plan_versions_price_plan_id_version",
+		"idx_pricing_plan_versions_grandfathering_migrate_at",
+	}
+
+	for _, indexName := range indexNames {
+		err := db.Collection(pricer.PricePlanVersionsCollection).Indexes().DropOne(context.TODO(), indexName)
+		if err != nil {
+			log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: "+indexName))
+			return err
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-pricing-plan-versions-indexes"))
+	return nil
+}
@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/pricer"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func InitPricingPromotionCodesIndexesUp(db *mongo.Database) error { //Up
+
+	log.SetFlags(0)
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-pricing-promotion-codes-indexes"))
+
+	// Codes are never reused, so the unique index also covers soft deleted codes
+	promotionCodeUniqueIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetName("idx_pricing_promotion_codes_code").SetUnique(true),
+	}
+
+	_, err := db.Collection(pricer.PricePromotionCodesCollection).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			promotionCodeUniqueIndexModel,
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-pricing-promotion-codes-indexes"))
+		return err
+	}
+
+	redemptionSubscriptionUniqueIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "promotion_code_id", Value: 1}, {Key: "subscription_id", Value: 1}},
+		Options: options.Index().SetName("idx_pricing_promotion_code_redemptions_code_subscription").SetUnique(true),
+	}
+
+	redemptionUserIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "promotion_code_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetName("idx_pricing_promotion_code_redemptions_code_user"),
+	}
+
+	// Each of a user's allowed redemptions takes its own slot, so concurrent redemptions cannot
+	// go over the per user limit
+	redemptionUserSlotUniqueIndexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "promotion_code_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "user_slot", Value: 1}},
+		Options: options.Index().
+			SetName("idx_pricing_promotion_code_redemptions_code_user_slot").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"user_slot": bson.M{"$gt": 0}}),
+	}
+
+	_, err = db.Collection(pricer.PricePromotionCodeRedemptionsCollection).Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			redemptionSubscriptionUniqueIndexModel,
+			redemptionUserIndexModel,
+			redemptionUserSlotUniqueIndexModel,
+		},
+	)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-pricing-promotion-code-redemptions-indexes"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-pricing-promotion-codes-indexes"))
+	return nil
+
+}
+
+func InitPricingPromotionCodesIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-pricing-promotion-codes-indexes"))
+
+	collectionIndexNames := map[string][]string{
+		pricer.PricePromotionCodesCollection: {
+			"idx_pricing_promotion_codes_code",
+		},
+		pricer.PricePromotionCodeRedemptionsCollection: {
+			"idx_pricing_promotion_code_redemptions_code_subscription",
+			"idx_pricing_promotion_code_redemptions_code_user",
+			"idx_pricing_promotion_code_redemptions_code_user_slot",
+		},
+	}
+
+	for collectionName, indexNames := range collectionIndexNames {
+		for _, indexName := range indexNames {
+			err := db.Collection(collectionName).Indexes().DropOne(context.TODO(), indexName)
+			if err != nil {
+				log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: "+indexName))
+				return err
+			}
+		}
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-pricing-promotion-codes-indexes"))
+	return nil
+}
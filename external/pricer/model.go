@@ -86,6 +86,98 @@ type PricePlan struct {
 
 	// DeletedByID is the ID of the user who soft deleted the price plan.
 	DeletedByID string `json:"deleted_by_id,omitempty" bson:"deleted_by_id,omitempty"`
+
+	// CurrentVersion is the number of the most recently published version of the price plan.
+	CurrentVersion int `json:"current_version,omitempty" bson:"current_version,omitempty"`
+
+	// CurrentVersionID is the ID of the most recently published version of the price plan.
+	CurrentVersionID string `json:"current_version_id,omitempty" bson:"current_version_id,omitempty"`
+}
+
+// PricePlanVersion is an immutable snapshot of a price plan's terms, taken each time the
+// plan is published. Subscriptions keep the terms of the version they bought.
+type PricePlanVersion struct {
+	// ID is the unique identifier for the price plan version.
+	ID string `json:"id" bson:"_id"`
+
+	// PricePlanID is the ID of the price plan the version belongs to.
+	PricePlanID string `json:"price_plan_id" bson:"price_plan_id"`
+
+	// PricePlanSlug is the slug of the price plan when the version was published.
+	PricePlanSlug string `json:"price_plan_slug" bson:"price_plan_slug"`
+
+	// Version is the version number, starting at 1 for the first publish.
+	Version int `json:"version" bson:"version"`
+
+	// Name is the display name of the price plan when the version was published.
+	Name string `json:"name" bson:"name"`
+
+	// Description describes the price plan when the version was published.
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+
+	// Features is the ordered feature catalog references included in this version.
+	Features []PlanFeatureRef `json:"features,omitempty" bson:"features,omitempty"`
+
+	// Costs are the prices of this version.
+	Costs []PriceCost `json:"costs,omitempty" bson:"costs,omitempty"`
+
+	// Discounts are the typed discount rules of this version.
+	Discounts []PriceDiscount `json:"discounts,omitempty" bson:"discounts,omitempty"`
+
+	// PaymentTerms defines how this version should be paid for.
+	PaymentTerms *PricePaymentTerms `json:"payment_terms,omitempty" bson:"payment_terms,omitempty"`
+
+	// ProviderRefs links this version to provider-side plan or product identifiers.
+	ProviderRefs []PriceProviderRef `json:"provider_refs,omitempty" bson:"provider_refs,omitempty"`
+
+	// Metadata stores additional project-specific data.
+	Metadata map[string]interface{} `json:"metadata,omitempty" bson:"metadata,omitempty"`
+
+	// PublishedAt is the date and time the version was published.
+	PublishedAt string `json:"published_at" bson:"published_at"`
+
+	// PublishedByID is the ID of the user who published the version.
+	PublishedByID string `json:"published_by_id,omitempty" bson:"published_by_id,omitempty"`
+
+	// SupersededAt is the date and time a newer version was published.
+	SupersededAt string `json:"superseded_at,omitempty" bson:"superseded_at,omitempty"`
+
+	// SupersededByVersionID is the ID of the version published after this one.
+	SupersededByVersionID string `json:"superseded_by_version_id,omitempty" bson:"superseded_by_version_id,omitempty"`
+
+	// Grandfathering decides what happens to the version's subscribers once it is superseded.
+	Grandfathering *PricePlanGrandfathering `json:"grandfathering,omitempty" bson:"grandfathering,omitempty"`
+
+	// CreatedAt is the date and time the version was created.
+	CreatedAt string `json:"created_at" bson:"created_at"`
+
+	// UpdatedAt is the date and time the version's grandfathering was last changed.
+	UpdatedAt string `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// PricePlanGrandfathering decides whether subscribers of a superseded version keep its
+// terms or are migrated to a newer version.
+type PricePlanGrandfathering struct {
+	// Policy is whether subscribers keep the version's terms or are migrated.
+	Policy PriceGrandfatherPolicy `json:"policy" bson:"policy"`
+
+	// MigrateAt is when subscribers are migrated, for the migrate policy.
+	MigrateAt string `json:"migrate_at,omitempty" bson:"migrate_at,omitempty"`
+
+	// MigrateToVersionID is the ID of the version subscribers are migrated to.
+	MigrateToVersionID string `json:"migrate_to_version_id,omitempty" bson:"migrate_to_version_id,omitempty"`
+
+	// MigrateToVersion is the number of the version subscribers are migrated to.
+	MigrateToVersion int `json:"migrate_to_version,omitempty" bson:"migrate_to_version,omitempty"`
+
+	// MigratedAt is when the subscribers were migrated.
+	MigratedAt string `json:"migrated_at,omitempty" bson:"migrated_at,omitempty"`
+
+	// MigratedCount is the number of subscriptions migrated.
+	MigratedCount int `json:"migrated_count,omitempty" bson:"migrated_count,omitempty"`
+
+	// UpdatedByID is the ID of the user who last set the grandfathering rule.
+	UpdatedByID string `json:"updated_by_id,omitempty" bson:"updated_by_id,omitempty"`
 }
 
 // PriceFeature represents a reusable v1 feature catalog item.
@@ -289,6 +381,84 @@ func (f *PriceFeature) NormaliseSlug() *PriceFeature {
 	return f
 }
 
+// NewPricePlanVersion snapshots the plan's current terms as the given version.
+func NewPricePlanVersion(pricePlan *PricePlan, version int, publishedAt string, publishedByID string) *PricePlanVersion {
+	return &PricePlanVersion{
+		PricePlanID:   pricePlan.ID,
+		PricePlanSlug: pricePlan.Slug,
+		Version:       version,
+		Name:          pricePlan.Name,
+		Description:   pricePlan.Description,
+		Features:      append([]PlanFeatureRef(nil), pricePlan.Features...),
+		Costs:         append([]PriceCost(nil), pricePlan.Costs...),
+		Discounts:     append([]PriceDiscount(nil), pricePlan.Discounts...),
+		PaymentTerms:  pricePlan.PaymentTerms,
+		ProviderRefs:  append([]PriceProviderRef(nil), pricePlan.ProviderRefs...),
+		Metadata:      pricePlan.Metadata,
+		PublishedAt:   publishedAt,
+		PublishedByID: publishedByID,
+	}
+}
+
+// AsPricePlan returns the version's terms as a price plan, so it can be used wherever
+// the plan's current terms would be.
+func (v *PricePlanVersion) AsPricePlan() *PricePlan {
+	return &PricePlan{
+		ID:               v.PricePlanID,
+		Slug:             v.PricePlanSlug,
+		Name:             v.Name,
+		Description:      v.Description,
+		Status:           PricePlanStatusPublished,
+		Features:         v.Features,
+		Costs:            v.Costs,
+		Discounts:        v.Discounts,
+		PaymentTerms:     v.PaymentTerms,
+		ProviderRefs:     v.ProviderRefs,
+		Metadata:         v.Metadata,
+		PublishedAt:      v.PublishedAt,
+		PublishedByID:    v.PublishedByID,
+		CreatedAt:        v.CreatedAt,
+		CurrentVersion:   v.Version,
+		CurrentVersionID: v.ID,
+	}
+}
+
+// IsSuperseded returns true once a newer version of the plan has been published.
+func (v *PricePlanVersion) IsSuperseded() bool {
+	return v.SupersededAt != ""
+}
+
+// IsMigrationDue returns true if the version's subscribers are due to be migrated to a
+// newer version and have not been yet.
+func (v *PricePlanVersion) IsMigrationDue(now time.Time) bool {
+	if v.Grandfathering == nil || v.Grandfathering.Policy != PriceGrandfatherPolicyMigrate || v.Grandfathering.MigratedAt != "" {
+		return false
+	}
+
+	migrateAt, err := parseOptionalPriceDate(v.Grandfathering.MigrateAt)
+	if err != nil {
+		return false
+	}
+
+	return !migrateAt.After(now)
+}
+
+// HasProviderPrice returns true if the version's plan or any of its costs is linked to the
+// provider-side price, plan or product ID.
+func (v *PricePlanVersion) HasProviderPrice(provider string, providerPriceID string) bool {
+	if hasPriceProviderRef(v.ProviderRefs, provider, providerPriceID) {
+		return true
+	}
+
+	for _, cost := range v.Costs {
+		if hasPriceProviderRef(cost.ProviderRefs, provider, providerPriceID) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Validate checks whether the plan uses valid v1 enum values and references.
 func (p *PricePlan) Validate() error {
 	if p == nil {
@@ -519,6 +689,31 @@ func IsValidPriceCurrency(currency string) bool {
 	return true
 }
 
+// IsValidPriceGrandfatherPolicy checks whether a grandfathering policy is supported.
+func IsValidPriceGrandfatherPolicy(policy string) bool {
+	switch PriceGrandfatherPolicy(policy) {
+	case PriceGrandfatherPolicyKeep, PriceGrandfatherPolicyMigrate:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasPriceProviderRef returns true if any of the refs for the provider carries the provider-side ID.
+func hasPriceProviderRef(refs []PriceProviderRef, provider string, providerID string) bool {
+	for _, ref := range refs {
+		if string(ref.Provider) != provider {
+			continue
+		}
+
+		if ref.ProviderPriceID == providerID || ref.ProviderID == providerID || ref.ProviderProductID == providerID {
+			return true
+		}
+	}
+
+	return false
+}
+
 // IsValidPricePlanStatus returns true when status is a supported plan status.
 func IsValidPricePlanStatus(status string) bool {
 	switch PricePlanStatus(status) {
@@ -562,7 +757,7 @@ func IsValidPriceBillingCadence(cadence string) bool {
 // IsValidPriceProvider returns true when provider is supported.
 func IsValidPriceProvider(provider string) bool {
 	switch PriceProvider(provider) {
-	case PriceProviderManual, PriceProviderStripe, PriceProviderPaddle, PriceProviderKofi:
+	case PriceProviderManual, PriceProviderStripe, PriceProviderPaddle, PriceProviderKofi, PriceProviderLemonSqueezy:
 		return true
 	default:
 		return false
@@ -610,6 +610,7 @@ func TestIsValidPriceProvider(t *testing.T) {
 	assert.True(t, pricer.IsValidPriceProvider(string(pricer.PriceProviderStripe)))
 	assert.True(t, pricer.IsValidPriceProvider(string(pricer.PriceProviderPaddle)))
 	assert.True(t, pricer.IsValidPriceProvider(string(pricer.PriceProviderKofi)))
+	assert.True(t, pricer.IsValidPriceProvider(string(pricer.PriceProviderLemonSqueezy)))
 	assert.False(t, pricer.IsValidPriceProvider("paypal"))
 }
 
@@ -0,0 +1,263 @@
+package pricer
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PricePromotionCode is a customer-facing code that claims a discount, with limits on
+// how often, by whom and for which plans it can be redeemed.
+type PricePromotionCode struct {
+	// ID is the unique identifier for the promotion code.
+	ID string `json:"id" bson:"_id"`
+
+	// Code is the code customers enter, stored in upper case.
+	Code string `json:"code" bson:"code"`
+
+	// Description is the admin-facing description of the promotion.
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+
+	// Discount is the discount the code claims.
+	Discount PriceDiscount `json:"discount" bson:"discount"`
+
+	// Active is whether the code can currently be redeemed.
+	Active bool `json:"active" bson:"active"`
+
+	// MaxRedemptions is the number of times the code can be redeemed in total. Zero is unlimited
+	MaxRedemptions int64 `json:"max_redemptions,omitempty" bson:"max_redemptions,omitempty"`
+
+	// MaxRedemptionsPerUser is the number of times a user can redeem the code. Zero is unlimited
+	MaxRedemptionsPerUser int64 `json:"max_redemptions_per_user,omitempty" bson:"max_redemptions_per_user,omitempty"`
+
+	// FirstTimeCustomersOnly restricts the code to users who have never subscribed.
+	FirstTimeCustomersOnly bool `json:"first_time_customers_only,omitempty" bson:"first_time_customers_only,omitempty"`
+
+	// EligiblePricePlanIDs restricts the code to these price plans. Empty is every plan
+	EligiblePricePlanIDs []string `json:"eligible_price_plan_ids,omitempty" bson:"eligible_price_plan_ids,omitempty"`
+
+	// EligibleBillingCadences restricts the code to these billing cadences. Empty is every cadence
+	EligibleBillingCadences []PriceBillingCadence `json:"eligible_billing_cadences,omitempty" bson:"eligible_billing_cadences,omitempty"`
+
+	// ExpiresAt is the date and time the code can no longer be redeemed.
+	ExpiresAt string `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+
+	// TimesRedeemed is the number of times the code has been redeemed.
+	TimesRedeemed int64 `json:"times_redeemed" bson:"times_redeemed"`
+
+	// ProviderRefs links this code to provider-side coupon, discount or promotion code identifiers.
+	ProviderRefs []PriceProviderRef `json:"provider_refs,omitempty" bson:"provider_refs,omitempty"`
+
+	// Metadata stores additional project-specific data.
+	Metadata map[string]interface{} `json:"metadata,omitempty" bson:"metadata,omitempty"`
+
+	// CreatedAt is the date and time the promotion code was created.
+	CreatedAt string `json:"created_at" bson:"created_at"`
+
+	// CreatedByID is the ID of the user who created the promotion code.
+	CreatedByID string `json:"created_by_id,omitempty" bson:"created_by_id,omitempty"`
+
+	// UpdatedAt is the date and time the promotion code was updated.
+	UpdatedAt string `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+
+	// UpdatedByID is the ID of the user who most recently updated the promotion code.
+	UpdatedByID string `json:"updated_by_id,omitempty" bson:"updated_by_id,omitempty"`
+
+	// DeletedAt is the date and time the promotion code was soft deleted.
+	DeletedAt string `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+
+	// DeletedByID is the ID of the user who soft deleted the promotion code.
+	DeletedByID string `json:"deleted_by_id,omitempty" bson:"deleted_by_id,omitempty"`
+}
+
+// PricePromotionCodeRedemption records a promotion code being redeemed for a subscription.
+type PricePromotionCodeRedemption struct {
+	// ID is the unique identifier for the redemption.
+	ID string `json:"id" bson:"_id"`
+
+	// PromotionCodeID is the ID of the redeemed promotion code.
+	PromotionCodeID string `json:"promotion_code_id" bson:"promotion_code_id"`
+
+	// Code is the redeemed code.
+	Code string `json:"code" bson:"code"`
+
+	// UserID is the ID of the user who redeemed the code.
+	UserID string `json:"user_id,omitempty" bson:"user_id,omitempty"`
+
+	// SubscriptionID is the ID of the billing subscription the code was redeemed for.
+	SubscriptionID string `json:"subscription_id" bson:"subscription_id"`
+
+	// PricePlanID is the ID of the price plan the subscription was bought on.
+	PricePlanID string `json:"price_plan_id,omitempty" bson:"price_plan_id,omitempty"`
+
+	// BillingCadence is how often the subscription is charged.
+	BillingCadence PriceBillingCadence `json:"billing_cadence,omitempty" bson:"billing_cadence,omitempty"`
+
+	// UserSlot is which of the user's allowed redemptions this is, starting at one. It is only
+	// set when the code limits redemptions per user, so the unique index can enforce the limit
+	UserSlot int64 `json:"user_slot,omitempty" bson:"user_slot,omitempty"`
+
+	// RedeemedAt is the date and time the code was redeemed.
+	RedeemedAt string `json:"redeemed_at" bson:"redeemed_at"`
+}
+
+// PricePromotionCodeCheck holds what a promotion code is checked against before it is redeemed.
+type PricePromotionCodeCheck struct {
+	// PricePlanID is the ID of the plan being bought. Empty skips the plan check
+	PricePlanID string
+
+	// BillingCadence is the cadence being bought. Empty skips the cadence check
+	BillingCadence PriceBillingCadence
+
+	// UserRedemptions is the number of times the user has already redeemed the code.
+	UserRedemptions int64
+
+	// IsReturningCustomer is whether the user has subscribed before.
+	IsReturningCustomer bool
+
+	// At is when the code is redeemed.
+	At time.Time
+}
+
+var pricePromotionCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{2,63}$`)
+
+// NormalisePromotionCode trims and upper-cases a promotion code, so codes match regardless
+// of how customers type them.
+func NormalisePromotionCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValidPromotionCode returns true when code is 3 to 64 letters, digits, dashes or underscores.
+func IsValidPromotionCode(code string) bool {
+	return pricePromotionCodePattern.MatchString(NormalisePromotionCode(code))
+}
+
+// Validate checks whether the promotion code uses valid v1 values.
+func (p *PricePromotionCode) Validate() error {
+	if p == nil || !IsValidPromotionCode(p.Code) {
+		return ErrInvalidPricePromotionCodePayload
+	}
+
+	if p.MaxRedemptions < 0 || p.MaxRedemptionsPerUser < 0 {
+		return ErrInvalidPricePromotionCodePayload
+	}
+
+	for _, cadence := range p.EligibleBillingCadences {
+		if !IsValidPriceBillingCadence(string(cadence)) {
+			return ErrInvalidPriceBillingCadence
+		}
+	}
+
+	if _, err := parseOptionalPriceDate(p.ExpiresAt); err != nil {
+		return ErrInvalidPricePromotionCodePayload
+	}
+
+	if err := p.Discount.Validate(); err != nil {
+		return err
+	}
+
+	return ValidatePriceProviderRefs(p.ProviderRefs)
+}
+
+// UnredeemableReason returns why the code cannot be redeemed for the check, or an empty
+// reason when it can.
+func (p *PricePromotionCode) UnredeemableReason(check *PricePromotionCodeCheck) PricePromotionCodeReason {
+	if check == nil {
+		check = &PricePromotionCodeCheck{}
+	}
+
+	at := check.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	if !p.Active || p.DeletedAt != "" {
+		return PricePromotionCodeReasonInactive
+	}
+
+	expiresAt, err := parseOptionalPriceDate(p.ExpiresAt)
+	if err != nil || (!expiresAt.IsZero() && !at.Before(expiresAt)) || !p.Discount.IsActiveAt(at) {
+		return PricePromotionCodeReasonExpired
+	}
+
+	if p.MaxRedemptions > 0 && p.TimesRedeemed >= p.MaxRedemptions {
+		return PricePromotionCodeReasonMaxRedemptionsReached
+	}
+
+	if p.MaxRedemptionsPerUser > 0 && check.UserRedemptions >= p.MaxRedemptionsPerUser {
+		return PricePromotionCodeReasonUserLimitReached
+	}
+
+	if p.FirstTimeCustomersOnly && check.IsReturningCustomer {
+		return PricePromotionCodeReasonFirstTimeCustomersOnly
+	}
+
+	if check.PricePlanID != "" && !p.IsEligiblePricePlan(check.PricePlanID) {
+		return PricePromotionCodeReasonPlanNotEligible
+	}
+
+	if check.BillingCadence != "" && !p.IsEligibleBillingCadence(check.BillingCadence) {
+		return PricePromotionCodeReasonCadenceNotEligible
+	}
+
+	return ""
+}
+
+// IsEligiblePricePlan returns true if the code can be redeemed for the price plan.
+func (p *PricePromotionCode) IsEligiblePricePlan(pricePlanID string) bool {
+	if len(p.EligiblePricePlanIDs) == 0 {
+		return true
+	}
+
+	for _, eligiblePricePlanID := range p.EligiblePricePlanIDs {
+		if eligiblePricePlanID == pricePlanID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsEligibleBillingCadence returns true if the code can be redeemed for the billing cadence.
+func (p *PricePromotionCode) IsEligibleBillingCadence(cadence PriceBillingCadence) bool {
+	if len(p.EligibleBillingCadences) == 0 {
+		return true
+	}
+
+	for _, eligibleCadence := range p.EligibleBillingCadences {
+		if eligibleCadence == cadence {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetProviderID returns the provider-side coupon or discount ID the code is mapped to for the
+// provider, or an empty string when the code is not mapped to it.
+func (p *PricePromotionCode) GetProviderID(provider string) string {
+	for _, ref := range p.ProviderRefs {
+		if string(ref.Provider) == provider && ref.ProviderID != "" {
+			return ref.ProviderID
+		}
+	}
+
+	return ""
+}
+
+// AsPriceDiscount returns the code's discount, identified by the promotion code so quotes
+// show which code was applied.
+func (p *PricePromotionCode) AsPriceDiscount() *PriceDiscount {
+	discount := p.Discount
+	if discount.ID == "" {
+		discount.ID = p.ID
+	}
+	if discount.Label == "" {
+		discount.Label = p.Code
+	}
+	if len(discount.ProviderRefs) == 0 {
+		discount.ProviderRefs = p.ProviderRefs
+	}
+
+	return &discount
+}
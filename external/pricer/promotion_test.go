@@ -0,0 +1,441 @@
+package pricer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+var testPromotionCodeAt = time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+type fakeCustomerHistory struct {
+	hasPreviousSubscription bool
+	excludedSubscriptionID  string
+}
+
+func (f *fakeCustomerHistory) HasPreviousSubscription(ctx context.Context, userID, excludeSubscriptionID string) (bool, error) {
+	f.excludedSubscriptionID = excludeSubscriptionID
+	return f.hasPreviousSubscription, nil
+}
+
+func makeValidPromotionCode() *pricer.PricePromotionCode {
+	return &pricer.PricePromotionCode{
+		ID:     "promo-1",
+		Code:   "SPRING25",
+		Active: true,
+		Discount: pricer.PriceDiscount{
+			Label:      "Spring sale",
+			Type:       pricer.PriceDiscountTypePercent,
+			PercentBps: 2500,
+		},
+		ProviderRefs: []pricer.PriceProviderRef{
+			{Provider: pricer.PriceProviderStripe, ProviderID: "coupon_spring"},
+		},
+	}
+}
+
+func TestPricePromotionCode_UnredeemableReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(p *pricer.PricePromotionCode)
+		check  pricer.PricePromotionCodeCheck
+		want   pricer.PricePromotionCodeReason
+	}{
+		{
+			name: "Success - active code is redeemable",
+			want: "",
+		},
+		{
+			name:   "Failure - inactive code",
+			modify: func(p *pricer.PricePromotionCode) { p.Active = false },
+			want:   pricer.PricePromotionCodeReasonInactive,
+		},
+		{
+			name:   "Failure - deleted code",
+			modify: func(p *pricer.PricePromotionCode) { p.DeletedAt = "2026-03-01T00:00:00" },
+			want:   pricer.PricePromotionCodeReasonInactive,
+		},
+		{
+			name:   "Failure - code has expired",
+			modify: func(p *pricer.PricePromotionCode) { p.ExpiresAt = "2026-03-10" },
+			want:   pricer.PricePromotionCodeReasonExpired,
+		},
+		{
+			name:   "Failure - discount window has ended",
+			modify: func(p *pricer.PricePromotionCode) { p.Discount.EndsAt = "2026-03-01" },
+			want:   pricer.PricePromotionCodeReasonExpired,
+		},
+		{
+			name: "Failure - max redemptions reached",
+			modify: func(p *pricer.PricePromotionCode) {
+				p.MaxRedemptions = 10
+				p.TimesRedeemed = 10
+			},
+			want: pricer.PricePromotionCodeReasonMaxRedemptionsReached,
+		},
+		{
+			name:   "Failure - user limit reached",
+			modify: func(p *pricer.PricePromotionCode) { p.MaxRedemptionsPerUser = 1 },
+			check:  pricer.PricePromotionCodeCheck{UserRedemptions: 1},
+			want:   pricer.PricePromotionCodeReasonUserLimitReached,
+		},
+		{
+			name:   "Failure - first-time customers only",
+			modify: func(p *pricer.PricePromotionCode) { p.FirstTimeCustomersOnly = true },
+			check:  pricer.PricePromotionCodeCheck{IsReturningCustomer: true},
+			want:   pricer.PricePromotionCodeReasonFirstTimeCustomersOnly,
+		},
+		{
+			name:   "Failure - plan not eligible",
+			modify: func(p *pricer.PricePromotionCode) { p.EligiblePricePlanIDs = []string{"plan-pro"} },
+			check:  pricer.PricePromotionCodeCheck{PricePlanID: "plan-basic"},
+			want:   pricer.PricePromotionCodeReasonPlanNotEligible,
+		},
+		{
+			name:   "Success - plan check skipped without a plan",
+			modify: func(p *pricer.PricePromotionCode) { p.EligiblePricePlanIDs = []string{"plan-pro"} },
+			want:   "",
+		},
+		{
+			name: "Failure - cadence not eligible",
+			modify: func(p *pricer.PricePromotionCode) {
+				p.EligibleBillingCadences = []pricer.PriceBillingCadence{pricer.PriceBillingCadenceYearly}
+			},
+			check: pricer.PricePromotionCodeCheck{BillingCadence: pricer.PriceBillingCadenceMonthly},
+			want:  pricer.PricePromotionCodeReasonCadenceNotEligible,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			promotionCode := makeValidPromotionCode()
+			if tt.modify != nil {
+				tt.modify(promotionCode)
+			}
+
+			check := tt.check
+			check.At = testPromotionCodeAt
+
+			assert.Equal(t, tt.want, promotionCode.UnredeemableReason(&check))
+		})
+	}
+}
+
+func TestService_CreatePromotionCode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success - code is normalised and active by default", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTestService(&mockPricerRepository{})
+
+		resp, err := svc.CreatePromotionCode(context.Background(), &pricer.CreatePromotionCodeRequest{
+			UserID:   testUserID,
+			Code:     " spring25 ",
+			Discount: pricer.PriceDiscount{Type: pricer.PriceDiscountTypePercent, PercentBps: 2500},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "SPRING25", resp.PromotionCode.Code)
+		assert.True(t, resp.PromotionCode.Active)
+	})
+
+	t.Run("Failure - code already exists", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTestService(&mockPricerRepository{
+			getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+				return makeValidPromotionCode(), nil
+			},
+		})
+
+		_, err := svc.CreatePromotionCode(context.Background(), &pricer.CreatePromotionCodeRequest{
+			UserID:   testUserID,
+			Code:     "SPRING25",
+			Discount: pricer.PriceDiscount{Type: pricer.PriceDiscountTypePercent, PercentBps: 2500},
+		})
+		assert.ErrorIs(t, err, pricer.ErrDuplicatePricePromotionCode)
+	})
+}
+
+func TestService_ValidatePromotionCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		promotionCode func() *pricer.PricePromotionCode
+		history       *fakeCustomerHistory
+		request       *pricer.ValidatePromotionCodeRequest
+		wantValid     bool
+		wantReason    pricer.PricePromotionCodeReason
+	}{
+		{
+			name:          "Success - valid code returns its discount",
+			promotionCode: makeValidPromotionCode,
+			request:       &pricer.ValidatePromotionCodeRequest{Code: "spring25"},
+			wantValid:     true,
+		},
+		{
+			name:       "Failure - unknown code",
+			request:    &pricer.ValidatePromotionCodeRequest{Code: "UNKNOWN"},
+			wantReason: pricer.PricePromotionCodeReasonNotFound,
+		},
+		{
+			name: "Failure - returning customer",
+			promotionCode: func() *pricer.PricePromotionCode {
+				p := makeValidPromotionCode()
+				p.FirstTimeCustomersOnly = true
+				return p
+			},
+			history:    &fakeCustomerHistory{hasPreviousSubscription: true},
+			request:    &pricer.ValidatePromotionCodeRequest{Code: "SPRING25", UserID: testUserID},
+			wantReason: pricer.PricePromotionCodeReasonFirstTimeCustomersOnly,
+		},
+		{
+			name: "Success - first-time check skipped without a user",
+			promotionCode: func() *pricer.PricePromotionCode {
+				p := makeValidPromotionCode()
+				p.FirstTimeCustomersOnly = true
+				return p
+			},
+			history:   &fakeCustomerHistory{hasPreviousSubscription: true},
+			request:   &pricer.ValidatePromotionCodeRequest{Code: "SPRING25"},
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockPricerRepository{
+				getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+					if tt.promotionCode == nil {
+						return nil, pricer.ErrPricePromotionCodeNotFound
+					}
+					return tt.promotionCode(), nil
+				},
+			}
+
+			svc := newTestService(repo)
+			if tt.history != nil {
+				svc.WithCustomerHistory(tt.history)
+			}
+
+			tt.request.At = testPromotionCodeAt
+			resp, err := svc.ValidatePromotionCode(context.Background(), tt.request)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValid, resp.Valid)
+			assert.Equal(t, tt.wantReason, resp.Reason)
+
+			if tt.wantValid {
+				require.NotNil(t, resp.Discount)
+				assert.Equal(t, int64(2500), resp.Discount.PercentBps)
+				assert.Empty(t, resp.Discount.ProviderRefs)
+			}
+		})
+	}
+}
+
+func TestService_RedeemPromotionCode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success - redemption is recorded and counted", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			incremented int
+			created     *pricer.PricePromotionCodeRedemption
+			history     = &fakeCustomerHistory{}
+		)
+
+		promotionCode := makeValidPromotionCode()
+		promotionCode.FirstTimeCustomersOnly = true
+
+		svc := newTestService(&mockPricerRepository{
+			getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+				return promotionCode, nil
+			},
+			incrementPromotionCodeRedemptionsFunc: func(ctx context.Context, id string) (bool, error) {
+				incremented++
+				return true, nil
+			},
+			createPromotionCodeRedemptionFunc: func(ctx context.Context, r *pricer.PricePromotionCodeRedemption) (*pricer.PricePromotionCodeRedemption, error) {
+				created = r
+				return r, nil
+			},
+		}).WithCustomerHistory(history)
+
+		resp, err := svc.RedeemPromotionCode(context.Background(), &pricer.RedeemPromotionCodeRequest{
+			Code:           "SPRING25",
+			UserID:         testUserID,
+			SubscriptionID: "sub-1",
+			PricePlanID:    testPlanID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, incremented)
+		require.NotNil(t, created)
+		assert.Equal(t, "promo-1", resp.Redemption.PromotionCodeID)
+		assert.Equal(t, "sub-1", resp.Redemption.SubscriptionID)
+		assert.Equal(t, "sub-1", history.excludedSubscriptionID)
+	})
+
+	t.Run("Success - redeeming again for the same subscription is not counted twice", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newTestService(&mockPricerRepository{
+			getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+				return makeValidPromotionCode(), nil
+			},
+			getPromotionCodeRedemptionsFunc: func(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) ([]pricer.PricePromotionCodeRedemption, error) {
+				return []pricer.PricePromotionCodeRedemption{{ID: "redemption-1", PromotionCodeID: req.PromotionCodeID, SubscriptionID: req.SubscriptionID}}, nil
+			},
+			incrementPromotionCodeRedemptionsFunc: func(ctx context.Context, id string) (bool, error) {
+				t.Fatal("redemptions should not be incremented again")
+				return false, nil
+			},
+		})
+
+		resp, err := svc.RedeemPromotionCode(context.Background(), &pricer.RedeemPromotionCodeRequest{Code: "SPRING25", SubscriptionID: "sub-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "redemption-1", resp.Redemption.ID)
+	})
+
+	t.Run("Success - redemption recorded concurrently for the same subscription is returned", func(t *testing.T) {
+		t.Parallel()
+
+		var lookups int
+
+		svc := newTestService(&mockPricerRepository{
+			getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+				return makeValidPromotionCode(), nil
+			},
+			getPromotionCodeRedemptionsFunc: func(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) ([]pricer.PricePromotionCodeRedemption, error) {
+				lookups++
+				if lookups == 1 {
+					return []pricer.PricePromotionCodeRedemption{}, nil
+				}
+				return []pricer.PricePromotionCodeRedemption{{ID: "redemption-1", PromotionCodeID: req.PromotionCodeID, SubscriptionID: req.SubscriptionID}}, nil
+			},
+			createPromotionCodeRedemptionFunc: func(ctx context.Context, r *pricer.PricePromotionCodeRedemption) (*pricer.PricePromotionCodeRedemption, error) {
+				return nil, pricer.ErrDuplicatePricePromotionCodeRedemption
+			},
+			incrementPromotionCodeRedemptionsFunc: func(ctx context.Context, id string) (bool, error) {
+				t.Fatal("redemptions should not be incremented for a duplicate redemption")
+				return false, nil
+			},
+		})
+
+		resp, err := svc.RedeemPromotionCode(context.Background(), &pricer.RedeemPromotionCodeRequest{Code: "SPRING25", SubscriptionID: "sub-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "redemption-1", resp.Redemption.ID)
+	})
+
+	t.Run("Success - redemption takes the user's lowest free slot", func(t *testing.T) {
+		t.Parallel()
+
+		var created *pricer.PricePromotionCodeRedemption
+
+		promotionCode := makeValidPromotionCode()
+		promotionCode.MaxRedemptionsPerUser = 3
+
+		svc := newTestService(&mockPricerRepository{
+			getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+				return promotionCode, nil
+			},
+			getPromotionCodeRedemptionsFunc: func(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) ([]pricer.PricePromotionCodeRedemption, error) {
+				if req.UserID == "" {
+					return []pricer.PricePromotionCodeRedemption{}, nil
+				}
+				return []pricer.PricePromotionCodeRedemption{{ID: "redemption-2", UserID: req.UserID, UserSlot: 2}}, nil
+			},
+			getTotalPromotionCodeRedemptionsFunc: func(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) (int64, error) {
+				return 1, nil
+			},
+			createPromotionCodeRedemptionFunc: func(ctx context.Context, r *pricer.PricePromotionCodeRedemption) (*pricer.PricePromotionCodeRedemption, error) {
+				created = r
+				return r, nil
+			},
+		})
+
+		_, err := svc.RedeemPromotionCode(context.Background(), &pricer.RedeemPromotionCodeRequest{Code: "SPRING25", UserID: testUserID, SubscriptionID: "sub-1"})
+		require.NoError(t, err)
+		require.NotNil(t, created)
+		assert.Equal(t, int64(1), created.UserSlot)
+	})
+
+	t.Run("Failure - user's slots taken concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			userLookups int
+			created     int
+		)
+
+		promotionCode := makeValidPromotionCode()
+		promotionCode.MaxRedemptionsPerUser = 1
+
+		svc := newTestService(&mockPricerRepository{
+			getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+				return promotionCode, nil
+			},
+			getPromotionCodeRedemptionsFunc: func(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) ([]pricer.PricePromotionCodeRedemption, error) {
+				if req.UserID == "" {
+					return []pricer.PricePromotionCodeRedemption{}, nil
+				}
+				userLookups++
+				if userLookups == 1 {
+					return []pricer.PricePromotionCodeRedemption{}, nil
+				}
+				return []pricer.PricePromotionCodeRedemption{{ID: "redemption-1", UserID: req.UserID, UserSlot: 1}}, nil
+			},
+			createPromotionCodeRedemptionFunc: func(ctx context.Context, r *pricer.PricePromotionCodeRedemption) (*pricer.PricePromotionCodeRedemption, error) {
+				created++
+				return nil, pricer.ErrDuplicatePricePromotionCodeRedemption
+			},
+			incrementPromotionCodeRedemptionsFunc: func(ctx context.Context, id string) (bool, error) {
+				t.Fatal("redemptions should not be incremented for a duplicate redemption")
+				return false, nil
+			},
+		})
+
+		_, err := svc.RedeemPromotionCode(context.Background(), &pricer.RedeemPromotionCodeRequest{Code: "SPRING25", UserID: testUserID, SubscriptionID: "sub-1"})
+		assert.ErrorIs(t, err, pricer.ErrPricePromotionCodeNotRedeemable)
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("Failure - limit reached between check and increment removes the redemption", func(t *testing.T) {
+		t.Parallel()
+
+		var deletedID string
+
+		svc := newTestService(&mockPricerRepository{
+			getPromotionCodeByCodeFunc: func(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+				return makeValidPromotionCode(), nil
+			},
+			createPromotionCodeRedemptionFunc: func(ctx context.Context, r *pricer.PricePromotionCodeRedemption) (*pricer.PricePromotionCodeRedemption, error) {
+				r.ID = "redemption-1"
+				return r, nil
+			},
+			incrementPromotionCodeRedemptionsFunc: func(ctx context.Context, id string) (bool, error) {
+				return false, nil
+			},
+			deletePromotionCodeRedemptionFunc: func(ctx context.Context, id string) error {
+				deletedID = id
+				return nil
+			},
+		})
+
+		_, err := svc.RedeemPromotionCode(context.Background(), &pricer.RedeemPromotionCodeRequest{Code: "SPRING25", SubscriptionID: "sub-1"})
+		assert.ErrorIs(t, err, pricer.ErrPricePromotionCodeNotRedeemable)
+		assert.Equal(t, "redemption-1", deletedID)
+	})
+}
@@ -0,0 +1,589 @@
+package pricer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/common"
+)
+
+// PriceQuoteLineItemType represents what a quote line item charges for.
+type PriceQuoteLineItemType string
+
+const (
+	// PriceQuoteLineItemTypeCharge represents the plan cost for the quoted quantity.
+	PriceQuoteLineItemTypeCharge PriceQuoteLineItemType = "charge"
+
+	// PriceQuoteLineItemTypeTrial represents the free trial period before the first charge.
+	PriceQuoteLineItemTypeTrial PriceQuoteLineItemType = "trial"
+
+	// PriceQuoteLineItemTypeSetupFee represents the one-off setup fee.
+	PriceQuoteLineItemTypeSetupFee PriceQuoteLineItemType = "setup_fee"
+)
+
+// MaxPriceQuoteQuantity is the largest number of units a quote can be worked out for.
+const MaxPriceQuoteQuantity = 1000000
+
+// PriceTaxRate is the tax that applies to a quote.
+type PriceTaxRate struct {
+	// Country is the ISO 3166-1 alpha-2 country code the rate applies to.
+	Country string `json:"country"`
+
+	// Name is the display name for the tax, e.g. VAT.
+	Name string `json:"name,omitempty"`
+
+	// RateBps is the tax rate in basis points.
+	RateBps int64 `json:"rate_bps"`
+
+	// Inclusive is true when prices already include the tax.
+	Inclusive bool `json:"inclusive"`
+}
+
+// PriceTaxRules looks up the tax rate for a quote. A nil rate means no tax applies.
+type PriceTaxRules interface {
+	GetPriceTaxRate(ctx context.Context, country string, currency string) (*PriceTaxRate, error)
+}
+
+// StaticPriceTaxRules applies a fixed tax rate per country.
+type StaticPriceTaxRules struct {
+	rates map[string]PriceTaxRate
+}
+
+// NewStaticPriceTaxRules returns tax rules using the given rates, keyed by their country.
+func NewStaticPriceTaxRules(rates ...PriceTaxRate) *StaticPriceTaxRules {
+	rules := &StaticPriceTaxRules{rates: make(map[string]PriceTaxRate, len(rates))}
+	for _, rate := range rates {
+		rate.Country = NormaliseCountry(rate.Country)
+		rules.rates[rate.Country] = rate
+	}
+
+	return rules
+}
+
+// GetPriceTaxRate returns the rate for the country, or nil if there is none.
+func (r *StaticPriceTaxRules) GetPriceTaxRate(ctx context.Context, country string, currency string) (*PriceTaxRate, error) {
+	rate, ok := r.rates[NormaliseCountry(country)]
+	if !ok {
+		return nil, nil
+	}
+
+	return &rate, nil
+}
+
+// PriceQuote is a line-itemised breakdown of what a customer pays for a plan cost.
+type PriceQuote struct {
+	// PricePlanID is the ID of the quoted price plan.
+	PricePlanID string `json:"price_plan_id"`
+
+	// PricePlanSlug is the slug of the quoted price plan.
+	PricePlanSlug string `json:"price_plan_slug"`
+
+	// PricePlanName is the display name of the quoted price plan.
+	PricePlanName string `json:"price_plan_name"`
+
+	// CostID is the ID of the quoted cost.
+	CostID string `json:"cost_id,omitempty"`
+
+	// Currency is the ISO 4217 currency code of every amount in the quote.
+	Currency string `json:"currency"`
+
+	// BillingCadence indicates how often the cost is charged.
+	BillingCadence PriceBillingCadence `json:"billing_cadence"`
+
+	// Quantity is the number of units quoted, e.g. seats.
+	Quantity int64 `json:"quantity"`
+
+	// Country is the ISO 3166-1 alpha-2 country code used for tax.
+	Country string `json:"country,omitempty"`
+
+	// TrialEndsAt is when the free trial ends and the first charge is made.
+	TrialEndsAt string `json:"trial_ends_at,omitempty"`
+
+	// Discount is the discount applied to the quote, if any. It is the first invoice's discount
+	// or, when the first invoice is not discounted, the recurring discount.
+	Discount *PriceQuoteDiscount `json:"discount,omitempty"`
+
+	// RecurringDiscount is the discount applied to the next recurring charge, if any. It differs
+	// from Discount when the first invoice's discount ends before the next charge.
+	RecurringDiscount *PriceQuoteDiscount `json:"recurring_discount,omitempty"`
+
+	// Tax is the tax rate applied to the quote, if any.
+	Tax *PriceTaxRate `json:"tax,omitempty"`
+
+	// FirstInvoice is what is charged when the customer subscribes.
+	FirstInvoice *PriceQuoteInvoice `json:"first_invoice"`
+
+	// Recurring is the next recurring charge. It is not set for one-off costs.
+	Recurring *PriceQuoteInvoice `json:"recurring,omitempty"`
+
+	// RecurringAfterDiscount is the recurring charge once the discount has ended. It is only set
+	// when the recurring charge is discounted and the discount ends.
+	RecurringAfterDiscount *PriceQuoteInvoice `json:"recurring_after_discount,omitempty"`
+
+	// QuotedAt is when the quote was calculated.
+	QuotedAt string `json:"quoted_at"`
+}
+
+// PriceQuoteDiscount describes the discount applied to a quote.
+type PriceQuoteDiscount struct {
+	// DiscountID is the ID of the applied plan discount.
+	DiscountID string `json:"discount_id,omitempty"`
+
+	// Label is the display label for the discount.
+	Label string `json:"label,omitempty"`
+
+	// Code is the coupon code that claimed the discount, if any.
+	Code string `json:"code,omitempty"`
+
+	// Type indicates whether the discount is fixed amount or percent based.
+	Type PriceDiscountType `json:"type"`
+
+	// Amount is the fixed discount in the lowest currency unit.
+	Amount int64 `json:"amount,omitempty"`
+
+	// PercentBps is the percentage discount in basis points.
+	PercentBps int64 `json:"percent_bps,omitempty"`
+
+	// EndsAt is when the discount stops applying. Empty means it does not end.
+	EndsAt string `json:"ends_at,omitempty"`
+}
+
+// PriceQuoteInvoice is a single charge within a quote.
+type PriceQuoteInvoice struct {
+	// DueAt is when the charge is made.
+	DueAt string `json:"due_at"`
+
+	// LineItems are the items charged.
+	LineItems []PriceQuoteLineItem `json:"line_items"`
+
+	// Subtotal is the sum of the line items before discount and exclusive tax.
+	Subtotal int64 `json:"subtotal"`
+
+	// DiscountAmount is the amount taken off the subtotal.
+	DiscountAmount int64 `json:"discount_amount"`
+
+	// TaxAmount is the tax charged, or included in the total for inclusive tax.
+	TaxAmount int64 `json:"tax_amount"`
+
+	// Total is the amount charged.
+	Total int64 `json:"total"`
+}
+
+// PriceQuoteLineItem is a single item charged on a quote invoice.
+type PriceQuoteLineItem struct {
+	// Type indicates what the line item charges for.
+	Type PriceQuoteLineItemType `json:"type"`
+
+	// Description is the display description for the line item.
+	Description string `json:"description"`
+
+	// Quantity is the number of units charged.
+	Quantity int64 `json:"quantity"`
+
+	// UnitAmount is the price per unit in the lowest currency unit.
+	UnitAmount int64 `json:"unit_amount"`
+
+	// Amount is the line total in the lowest currency unit.
+	Amount int64 `json:"amount"`
+}
+
+// PriceQuoteInput holds everything needed to calculate a quote for a plan cost.
+type PriceQuoteInput struct {
+	// Quantity is the number of units quoted. Default 1
+	Quantity int64
+
+	// Country is the ISO 3166-1 alpha-2 country code used for tax.
+	Country string
+
+	// Discounts are the discounts that apply to everyone, e.g. the plan's discounts.
+	Discounts []PriceDiscount
+
+	// CouponDiscount is the discount claimed with a coupon code, if any. Only one discount
+	// applies to a charge: the one saving the most.
+	CouponDiscount *PriceDiscount
+
+	// CouponCode is the coupon code that claimed CouponDiscount.
+	CouponCode string
+
+	// Tax is the tax rate to apply, if any.
+	Tax *PriceTaxRate
+
+	// At is when the quote is calculated. Default now
+	At time.Time
+}
+
+// CalculatePriceQuote calculates a quote for the cost of the plan. All amounts are in the lowest
+// currency unit, and percentages are rounded half up to the nearest unit.
+//
+// Discounts apply to the plan charge but not the setup fee, and only to charges made inside the
+// discount's date window. Tax is worked out on each invoice's discounted subtotal. Trials delay
+// the first charge; the setup fee is still charged straight away.
+func CalculatePriceQuote(pricePlan *PricePlan, cost *PriceCost, input *PriceQuoteInput) (*PriceQuote, error) {
+	if pricePlan == nil || cost == nil {
+		return nil, ErrInvalidPriceQuote
+	}
+
+	quoteInput := PriceQuoteInput{}
+	if input != nil {
+		quoteInput = *input
+	}
+	if quoteInput.Quantity == 0 {
+		quoteInput.Quantity = 1
+	}
+	if quoteInput.Quantity < 0 || quoteInput.Quantity > MaxPriceQuoteQuantity {
+		return nil, ErrInvalidPriceQuote
+	}
+	if quoteInput.At.IsZero() {
+		quoteInput.At = time.Now()
+	}
+	at := quoteInput.At.UTC()
+
+	if err := cost.Validate(); err != nil {
+		return nil, err
+	}
+
+	// the charge for every unit and the setup fee must add up without overflowing
+	if cost.Amount > (math.MaxInt64-cost.SetupFeeAmount)/quoteInput.Quantity {
+		return nil, ErrInvalidPriceQuote
+	}
+
+	currency := NormaliseCurrency(cost.Currency)
+
+	quote := &PriceQuote{
+		PricePlanID:    pricePlan.ID,
+		PricePlanSlug:  pricePlan.Slug,
+		PricePlanName:  pricePlan.Name,
+		CostID:         cost.ID,
+		Currency:       currency,
+		BillingCadence: cost.BillingCadence,
+		Quantity:       quoteInput.Quantity,
+		Country:        NormaliseCountry(quoteInput.Country),
+		Tax:            quoteInput.Tax,
+		QuotedAt:       at.Format(common.RFC3339NanoUTC),
+	}
+
+	discounts := append([]PriceDiscount(nil), quoteInput.Discounts...)
+	couponIndex := -1
+	if quoteInput.CouponDiscount != nil {
+		discounts = append(discounts, *quoteInput.CouponDiscount)
+		couponIndex = len(discounts) - 1
+	}
+
+	recurring := cost.BillingCadence != PriceBillingCadenceOneTime
+	chargeLine := PriceQuoteLineItem{
+		Type:        PriceQuoteLineItemTypeCharge,
+		Description: describePriceQuoteCharge(pricePlan, cost),
+		Quantity:    quoteInput.Quantity,
+		UnitAmount:  cost.Amount,
+		Amount:      cost.Amount * quoteInput.Quantity,
+	}
+
+	firstChargeAt := at
+	if recurring && cost.TrialPeriodDays > 0 {
+		firstChargeAt = at.AddDate(0, 0, cost.TrialPeriodDays)
+		quote.TrialEndsAt = firstChargeAt.Format(common.RFC3339NanoUTC)
+	}
+
+	// First invoice, charged straight away
+	firstInvoice := &PriceQuoteInvoice{DueAt: quote.QuotedAt}
+	var firstChargeDiscount int64
+	if firstChargeAt.Equal(at) {
+		firstInvoice.LineItems = append(firstInvoice.LineItems, chargeLine)
+		index, amount := bestPriceQuoteDiscount(discounts, currency, chargeLine.Amount, at)
+		if index >= 0 {
+			quote.Discount = newPriceQuoteDiscount(&discounts[index], index == couponIndex, quoteInput.CouponCode)
+			firstChargeDiscount = amount
+		}
+	} else {
+		firstInvoice.LineItems = append(firstInvoice.LineItems, PriceQuoteLineItem{
+			Type:        PriceQuoteLineItemTypeTrial,
+			Description: fmt.Sprintf("%d day free trial", cost.TrialPeriodDays),
+			Quantity:    quoteInput.Quantity,
+		})
+	}
+	if cost.SetupFeeAmount > 0 {
+		firstInvoice.LineItems = append(firstInvoice.LineItems, PriceQuoteLineItem{
+			Type:        PriceQuoteLineItemTypeSetupFee,
+			Description: "Setup fee",
+			Quantity:    1,
+			UnitAmount:  cost.SetupFeeAmount,
+			Amount:      cost.SetupFeeAmount,
+		})
+	}
+	totalPriceQuoteInvoice(firstInvoice, firstChargeDiscount, quoteInput.Tax)
+	quote.FirstInvoice = firstInvoice
+
+	if !recurring {
+		return quote, nil
+	}
+
+	// Next recurring charge, at the end of the trial or after the first period. Every charge
+	// is worked out from the first one so month-end dates do not drift
+	nextPeriod := 0
+	if firstChargeAt.Equal(at) {
+		nextPeriod = 1
+	}
+	nextChargeAt := addPriceBillingPeriods(firstChargeAt, cost.BillingCadence, nextPeriod)
+
+	recurringInvoice := &PriceQuoteInvoice{
+		DueAt:     nextChargeAt.Format(common.RFC3339NanoUTC),
+		LineItems: []PriceQuoteLineItem{chargeLine},
+	}
+	index, recurringDiscount := bestPriceQuoteDiscount(discounts, currency, chargeLine.Amount, nextChargeAt)
+	if index >= 0 {
+		quote.RecurringDiscount = newPriceQuoteDiscount(&discounts[index], index == couponIndex, quoteInput.CouponCode)
+		if quote.Discount == nil {
+			quote.Discount = quote.RecurringDiscount
+		}
+	}
+	totalPriceQuoteInvoice(recurringInvoice, recurringDiscount, quoteInput.Tax)
+	quote.Recurring = recurringInvoice
+
+	// Recurring charge once the recurring discount has ended
+	if recurringDiscount == 0 || quote.RecurringDiscount.EndsAt == "" {
+		return quote, nil
+	}
+	endsAt, err := parseOptionalPriceDate(quote.RecurringDiscount.EndsAt)
+	if err != nil {
+		return nil, ErrInvalidPriceDiscount
+	}
+	afterDiscountAt := nextChargeAt
+	for period := nextPeriod + 1; afterDiscountAt.Before(endsAt); period++ {
+		afterDiscountAt = addPriceBillingPeriods(firstChargeAt, cost.BillingCadence, period)
+	}
+
+	afterDiscountInvoice := &PriceQuoteInvoice{
+		DueAt:     afterDiscountAt.Format(common.RFC3339NanoUTC),
+		LineItems: []PriceQuoteLineItem{chargeLine},
+	}
+	totalPriceQuoteInvoice(afterDiscountInvoice, 0, quoteInput.Tax)
+	quote.RecurringAfterDiscount = afterDiscountInvoice
+
+	return quote, nil
+}
+
+// FindPriceCost returns the plan's first cost matching the given cost ID, currency and billing
+// cadence. Empty values match any cost.
+func (p *PricePlan) FindPriceCost(costID string, currency string, cadence PriceBillingCadence) *PriceCost {
+	currency = NormaliseCurrency(currency)
+	for i := range p.Costs {
+		cost := &p.Costs[i]
+		if costID != "" && cost.ID != costID {
+			continue
+		}
+		if currency != "" && NormaliseCurrency(cost.Currency) != currency {
+			continue
+		}
+		if cadence != "" && cost.BillingCadence != cadence {
+			continue
+		}
+
+		return cost
+	}
+
+	return nil
+}
+
+// FindPriceDiscountByCode returns the plan discount claimed by the coupon code, matched against
+// the discount ID and its provider-side coupon IDs.
+func (p *PricePlan) FindPriceDiscountByCode(code string) *PriceDiscount {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil
+	}
+
+	for i := range p.Discounts {
+		discount := &p.Discounts[i]
+		if discount.ID != "" && strings.EqualFold(discount.ID, code) {
+			return discount
+		}
+		for _, ref := range discount.ProviderRefs {
+			if ref.ProviderID != "" && strings.EqualFold(ref.ProviderID, code) {
+				return discount
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsActiveAt returns true if the discount's date window includes the given time.
+func (d *PriceDiscount) IsActiveAt(at time.Time) bool {
+	startsAt, err := parseOptionalPriceDate(d.StartsAt)
+	if err != nil {
+		return false
+	}
+	endsAt, err := parseOptionalPriceDate(d.EndsAt)
+	if err != nil {
+		return false
+	}
+
+	if !startsAt.IsZero() && at.Before(startsAt) {
+		return false
+	}
+
+	return endsAt.IsZero() || at.Before(endsAt)
+}
+
+// DiscountAmount returns how much the discount takes off the subtotal, in the lowest currency
+// unit. Amount discounts in another currency take nothing off.
+func (d *PriceDiscount) DiscountAmount(currency string, subtotal int64) int64 {
+	var amount int64
+	switch d.Type {
+	case PriceDiscountTypeAmount:
+		if NormaliseCurrency(d.Currency) != NormaliseCurrency(currency) {
+			return 0
+		}
+		amount = d.Amount
+	case PriceDiscountTypePercent:
+		amount = scalePriceAmount(subtotal, d.PercentBps, 10000)
+	}
+
+	if amount > subtotal {
+		return subtotal
+	}
+
+	return amount
+}
+
+// NormaliseCountry converts a country code to the package-standard format.
+func NormaliseCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// IsValidPriceCountry checks whether the country is an ISO 3166-1 alpha-2 style code.
+func IsValidPriceCountry(country string) bool {
+	country = NormaliseCountry(country)
+	if len(country) != 2 {
+		return false
+	}
+
+	for _, char := range country {
+		if char < 'A' || char > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bestPriceQuoteDiscount returns the index of the active discount saving the most on the
+// subtotal, or -1 if none does, and how much it saves
+func bestPriceQuoteDiscount(discounts []PriceDiscount, currency string, subtotal int64, at time.Time) (int, int64) {
+	best := -1
+	var bestAmount int64
+
+	for i := range discounts {
+		if !discounts[i].IsActiveAt(at) {
+			continue
+		}
+
+		amount := discounts[i].DiscountAmount(currency, subtotal)
+		if amount > bestAmount {
+			best = i
+			bestAmount = amount
+		}
+	}
+
+	return best, bestAmount
+}
+
+// newPriceQuoteDiscount describes the discount for the quote
+func newPriceQuoteDiscount(discount *PriceDiscount, isCoupon bool, couponCode string) *PriceQuoteDiscount {
+	quoteDiscount := &PriceQuoteDiscount{
+		DiscountID: discount.ID,
+		Label:      discount.Label,
+		Type:       discount.Type,
+		Amount:     discount.Amount,
+		PercentBps: discount.PercentBps,
+		EndsAt:     discount.EndsAt,
+	}
+	if isCoupon {
+		quoteDiscount.Code = strings.TrimSpace(couponCode)
+	}
+
+	return quoteDiscount
+}
+
+// totalPriceQuoteInvoice works out the invoice subtotal, discount, tax and total
+func totalPriceQuoteInvoice(invoice *PriceQuoteInvoice, discountAmount int64, tax *PriceTaxRate) {
+	for _, lineItem := range invoice.LineItems {
+		invoice.Subtotal += lineItem.Amount
+	}
+	invoice.DiscountAmount = discountAmount
+
+	taxable := invoice.Subtotal - invoice.DiscountAmount
+	invoice.Total = taxable
+
+	if tax == nil || tax.RateBps <= 0 {
+		return
+	}
+
+	if tax.Inclusive {
+		invoice.TaxAmount = taxable - scalePriceAmount(taxable, 10000, 10000+tax.RateBps)
+		return
+	}
+
+	invoice.TaxAmount = scalePriceAmount(taxable, tax.RateBps, 10000)
+	invoice.Total = taxable + invoice.TaxAmount
+}
+
+// scalePriceAmount multiplies a non-negative amount by numerator/denominator, rounding half up to
+// the nearest unit. Only the remainder is multiplied before dividing, so large amounts do not
+// overflow on the way.
+func scalePriceAmount(amount int64, numerator int64, denominator int64) int64 {
+	return amount/denominator*numerator + roundPriceAmount(amount%denominator*numerator, denominator)
+}
+
+// roundPriceAmount divides a non-negative amount, rounding half up to the nearest unit
+func roundPriceAmount(amount int64, divisor int64) int64 {
+	return (amount + divisor/2) / divisor
+}
+
+// addPriceBillingPeriods returns the time the given number of billing periods after the anchor.
+// Monthly and yearly periods keep the anchor's day of the month, falling back to the last day
+// of shorter months, so a 31 January anchor renews on 28 February and then 31 March.
+func addPriceBillingPeriods(anchor time.Time, cadence PriceBillingCadence, periods int) time.Time {
+	switch cadence {
+	case PriceBillingCadenceWeekly:
+		return anchor.AddDate(0, 0, 7*periods)
+	case PriceBillingCadenceYearly:
+		return addPriceMonths(anchor, 12*periods)
+	default:
+		return addPriceMonths(anchor, periods)
+	}
+}
+
+// addPriceMonths returns the time the given number of months after the anchor, clamping the day
+// to the last day of the target month
+func addPriceMonths(anchor time.Time, months int) time.Time {
+	year, month, day := anchor.Date()
+	targetMonth := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, anchor.Location())
+	if lastDay := targetMonth.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(targetMonth.Year(), targetMonth.Month(), day, anchor.Hour(), anchor.Minute(), anchor.Second(), anchor.Nanosecond(), anchor.Location())
+}
+
+// describePriceQuoteCharge returns the display description for the plan charge
+func describePriceQuoteCharge(pricePlan *PricePlan, cost *PriceCost) string {
+	name := pricePlan.Name
+	if name == "" {
+		name = pricePlan.Slug
+	}
+
+	switch cost.BillingCadence {
+	case PriceBillingCadenceWeekly:
+		return name + " (weekly)"
+	case PriceBillingCadenceMonthly:
+		return name + " (monthly)"
+	case PriceBillingCadenceYearly:
+		return name + " (yearly)"
+	default:
+		return name
+	}
+}
@@ -0,0 +1,369 @@
+package pricer_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+var testQuoteAt = time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func TestCalculatePriceQuote(t *testing.T) {
+	t.Parallel()
+
+	monthly := func(amount int64) pricer.PriceCost {
+		return pricer.PriceCost{ID: "cost-monthly", Amount: amount, Currency: "USD", BillingCadence: pricer.PriceBillingCadenceMonthly}
+	}
+
+	tests := []struct {
+		name        string
+		cost        pricer.PriceCost
+		input       pricer.PriceQuoteInput
+		expectError error
+		assert      func(t *testing.T, quote *pricer.PriceQuote)
+	}{
+		{
+			name:  "Success - monthly cost multiplied by quantity",
+			cost:  monthly(1000),
+			input: pricer.PriceQuoteInput{Quantity: 3},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, int64(3), quote.Quantity)
+				assert.Equal(t, "2026-03-10T00:00:00", quote.FirstInvoice.DueAt)
+				assert.Equal(t, int64(3000), quote.FirstInvoice.Total)
+				require.Len(t, quote.FirstInvoice.LineItems, 1)
+				assert.Equal(t, int64(1000), quote.FirstInvoice.LineItems[0].UnitAmount)
+				require.NotNil(t, quote.Recurring)
+				assert.Equal(t, "2026-04-10T00:00:00", quote.Recurring.DueAt)
+				assert.Equal(t, int64(3000), quote.Recurring.Total)
+				assert.Nil(t, quote.Discount)
+				assert.Nil(t, quote.RecurringAfterDiscount)
+			},
+		},
+		{
+			name: "Success - trial delays first charge but setup fee is charged straight away",
+			cost: pricer.PriceCost{Amount: 1000, Currency: "USD", BillingCadence: pricer.PriceBillingCadenceMonthly, TrialPeriodDays: 14, SetupFeeAmount: 2500},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, "2026-03-24T00:00:00", quote.TrialEndsAt)
+				require.Len(t, quote.FirstInvoice.LineItems, 2)
+				assert.Equal(t, pricer.PriceQuoteLineItemTypeTrial, quote.FirstInvoice.LineItems[0].Type)
+				assert.Equal(t, pricer.PriceQuoteLineItemTypeSetupFee, quote.FirstInvoice.LineItems[1].Type)
+				assert.Equal(t, int64(2500), quote.FirstInvoice.Total)
+				assert.Equal(t, "2026-03-24T00:00:00", quote.Recurring.DueAt)
+				assert.Equal(t, int64(1000), quote.Recurring.Total)
+			},
+		},
+		{
+			name: "Success - percent discount rounds half up and ends",
+			cost: monthly(999),
+			input: pricer.PriceQuoteInput{Discounts: []pricer.PriceDiscount{
+				{ID: "launch", Type: pricer.PriceDiscountTypePercent, PercentBps: 1250, EndsAt: "2026-05-01"},
+			}},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				require.NotNil(t, quote.Discount)
+				assert.Equal(t, "launch", quote.Discount.DiscountID)
+				assert.Equal(t, int64(125), quote.FirstInvoice.DiscountAmount)
+				assert.Equal(t, int64(874), quote.FirstInvoice.Total)
+				assert.Equal(t, int64(874), quote.Recurring.Total)
+				require.NotNil(t, quote.RecurringAfterDiscount)
+				assert.Equal(t, "2026-05-10T00:00:00", quote.RecurringAfterDiscount.DueAt)
+				assert.Equal(t, int64(999), quote.RecurringAfterDiscount.Total)
+			},
+		},
+		{
+			name: "Success - recurring discount differs from the first invoice discount",
+			cost: monthly(1000),
+			input: pricer.PriceQuoteInput{Discounts: []pricer.PriceDiscount{
+				{ID: "launch", Type: pricer.PriceDiscountTypePercent, PercentBps: 5000, EndsAt: "2026-03-31"},
+				{ID: "loyal", Type: pricer.PriceDiscountTypePercent, PercentBps: 1000, StartsAt: "2026-04-01", EndsAt: "2026-07-01"},
+			}},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				require.NotNil(t, quote.Discount)
+				assert.Equal(t, "launch", quote.Discount.DiscountID)
+				assert.Equal(t, int64(500), quote.FirstInvoice.Total)
+				require.NotNil(t, quote.RecurringDiscount)
+				assert.Equal(t, "loyal", quote.RecurringDiscount.DiscountID)
+				assert.Equal(t, int64(900), quote.Recurring.Total)
+				require.NotNil(t, quote.RecurringAfterDiscount)
+				assert.Equal(t, "2026-07-10T00:00:00", quote.RecurringAfterDiscount.DueAt)
+				assert.Equal(t, int64(1000), quote.RecurringAfterDiscount.Total)
+			},
+		},
+		{
+			name: "Success - amount discount is capped and not applied to the setup fee",
+			cost: pricer.PriceCost{Amount: 1000, Currency: "USD", BillingCadence: pricer.PriceBillingCadenceMonthly, SetupFeeAmount: 500},
+			input: pricer.PriceQuoteInput{Discounts: []pricer.PriceDiscount{
+				{Type: pricer.PriceDiscountTypeAmount, Amount: 1500, Currency: "usd"},
+			}},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, int64(1500), quote.FirstInvoice.Subtotal)
+				assert.Equal(t, int64(1000), quote.FirstInvoice.DiscountAmount)
+				assert.Equal(t, int64(500), quote.FirstInvoice.Total)
+				assert.Equal(t, int64(0), quote.Recurring.Total)
+			},
+		},
+		{
+			name: "Success - amount discount in another currency is ignored",
+			cost: monthly(1000),
+			input: pricer.PriceQuoteInput{Discounts: []pricer.PriceDiscount{
+				{Type: pricer.PriceDiscountTypeAmount, Amount: 100, Currency: "EUR"},
+			}},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Nil(t, quote.Discount)
+				assert.Equal(t, int64(1000), quote.FirstInvoice.Total)
+			},
+		},
+		{
+			name: "Success - discount starting later applies from the first charge inside its window",
+			cost: monthly(1000),
+			input: pricer.PriceQuoteInput{Discounts: []pricer.PriceDiscount{
+				{Type: pricer.PriceDiscountTypePercent, PercentBps: 5000, StartsAt: "2026-04-01"},
+			}},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				require.NotNil(t, quote.Discount)
+				assert.Equal(t, int64(1000), quote.FirstInvoice.Total)
+				assert.Equal(t, int64(500), quote.Recurring.Total)
+			},
+		},
+		{
+			name: "Success - coupon discount used when it saves the most",
+			cost: monthly(1000),
+			input: pricer.PriceQuoteInput{
+				Discounts:      []pricer.PriceDiscount{{ID: "everyone", Type: pricer.PriceDiscountTypePercent, PercentBps: 1000}},
+				CouponDiscount: &pricer.PriceDiscount{ID: "friends", Type: pricer.PriceDiscountTypePercent, PercentBps: 2000},
+				CouponCode:     "FRIENDS",
+			},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				require.NotNil(t, quote.Discount)
+				assert.Equal(t, "friends", quote.Discount.DiscountID)
+				assert.Equal(t, "FRIENDS", quote.Discount.Code)
+				assert.Equal(t, int64(800), quote.FirstInvoice.Total)
+			},
+		},
+		{
+			name: "Success - plan discount used when it beats the coupon",
+			cost: monthly(1000),
+			input: pricer.PriceQuoteInput{
+				Discounts:      []pricer.PriceDiscount{{ID: "everyone", Type: pricer.PriceDiscountTypePercent, PercentBps: 3000}},
+				CouponDiscount: &pricer.PriceDiscount{ID: "friends", Type: pricer.PriceDiscountTypePercent, PercentBps: 2000},
+				CouponCode:     "FRIENDS",
+			},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				require.NotNil(t, quote.Discount)
+				assert.Equal(t, "everyone", quote.Discount.DiscountID)
+				assert.Empty(t, quote.Discount.Code)
+				assert.Equal(t, int64(700), quote.FirstInvoice.Total)
+			},
+		},
+		{
+			name:  "Success - exclusive tax is added to the discounted subtotal",
+			cost:  monthly(999),
+			input: pricer.PriceQuoteInput{Country: "us", Tax: &pricer.PriceTaxRate{Country: "US", RateBps: 2000}},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, "US", quote.Country)
+				assert.Equal(t, int64(200), quote.FirstInvoice.TaxAmount)
+				assert.Equal(t, int64(1199), quote.FirstInvoice.Total)
+			},
+		},
+		{
+			name:  "Success - inclusive tax is taken out of the total",
+			cost:  monthly(1000),
+			input: pricer.PriceQuoteInput{Tax: &pricer.PriceTaxRate{Country: "GB", Name: "VAT", RateBps: 2000, Inclusive: true}},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, int64(167), quote.FirstInvoice.TaxAmount)
+				assert.Equal(t, int64(1000), quote.FirstInvoice.Total)
+			},
+		},
+		{
+			name: "Success - one-time cost has no trial or recurring charge",
+			cost: pricer.PriceCost{Amount: 4900, Currency: "USD", BillingCadence: pricer.PriceBillingCadenceOneTime, TrialPeriodDays: 7},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Empty(t, quote.TrialEndsAt)
+				assert.Equal(t, int64(4900), quote.FirstInvoice.Total)
+				assert.Nil(t, quote.Recurring)
+			},
+		},
+		{
+			name: "Success - month-end charges fall on the last day of shorter months",
+			cost: monthly(1000),
+			input: pricer.PriceQuoteInput{
+				At: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+				Discounts: []pricer.PriceDiscount{
+					{Type: pricer.PriceDiscountTypePercent, PercentBps: 1000, EndsAt: "2026-04-15"},
+				},
+			},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, "2026-01-31T00:00:00", quote.FirstInvoice.DueAt)
+				assert.Equal(t, "2026-02-28T00:00:00", quote.Recurring.DueAt)
+				require.NotNil(t, quote.RecurringAfterDiscount)
+				assert.Equal(t, "2026-04-30T00:00:00", quote.RecurringAfterDiscount.DueAt)
+			},
+		},
+		{
+			name: "Success - percentages of large amounts do not overflow",
+			cost: monthly(1_000_000_000_000_001),
+			input: pricer.PriceQuoteInput{
+				Discounts: []pricer.PriceDiscount{{Type: pricer.PriceDiscountTypePercent, PercentBps: 5000}},
+				Tax:       &pricer.PriceTaxRate{Country: "US", RateBps: 1000},
+			},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, int64(500_000_000_000_001), quote.FirstInvoice.DiscountAmount)
+				assert.Equal(t, int64(50_000_000_000_000), quote.FirstInvoice.TaxAmount)
+				assert.Equal(t, int64(550_000_000_000_000), quote.FirstInvoice.Total)
+			},
+		},
+		{
+			name:        "Failure - quantity above the maximum",
+			cost:        monthly(1000),
+			input:       pricer.PriceQuoteInput{Quantity: pricer.MaxPriceQuoteQuantity + 1},
+			expectError: pricer.ErrInvalidPriceQuote,
+		},
+		{
+			name:        "Failure - charge overflows",
+			cost:        monthly(math.MaxInt64 / 2),
+			input:       pricer.PriceQuoteInput{Quantity: 3},
+			expectError: pricer.ErrInvalidPriceQuote,
+		},
+		{
+			name:        "Failure - negative quantity",
+			cost:        monthly(1000),
+			input:       pricer.PriceQuoteInput{Quantity: -1},
+			expectError: pricer.ErrInvalidPriceQuote,
+		},
+		{
+			name:        "Failure - invalid cost currency",
+			cost:        pricer.PriceCost{Amount: 1000, Currency: "DOLLARS", BillingCadence: pricer.PriceBillingCadenceMonthly},
+			expectError: pricer.ErrInvalidPriceCurrency,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.input.At.IsZero() {
+				tt.input.At = testQuoteAt
+			}
+			quote, err := pricer.CalculatePriceQuote(makeValidPlan(), &tt.cost, &tt.input)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.assert(t, quote)
+		})
+	}
+}
+
+func TestService_QuotePricePlan(t *testing.T) {
+	t.Parallel()
+
+	publishedPlan := func() *pricer.PricePlan {
+		plan := makeValidPlan()
+		plan.Status = pricer.PricePlanStatusPublished
+		plan.PublishedAt = "2026-01-01T00:00:00"
+		plan.Costs = append(plan.Costs, pricer.PriceCost{ID: "cost-yearly", Amount: 10000, Currency: "USD", BillingCadence: pricer.PriceBillingCadenceYearly})
+		plan.Discounts = []pricer.PriceDiscount{
+			{
+				ID:           "spring",
+				Type:         pricer.PriceDiscountTypePercent,
+				PercentBps:   2500,
+				StartsAt:     "2026-03-01",
+				EndsAt:       "2026-04-01",
+				ProviderRefs: []pricer.PriceProviderRef{{Provider: pricer.PriceProviderStripe, ProviderID: "SPRING25"}},
+			},
+		}
+		return plan
+	}
+
+	tests := []struct {
+		name        string
+		plan        *pricer.PricePlan
+		req         *pricer.QuotePricePlanRequest
+		expectError error
+		assert      func(t *testing.T, quote *pricer.PriceQuote)
+	}{
+		{
+			name: "Success - cost chosen by cadence with coupon and tax",
+			plan: publishedPlan(),
+			req:  &pricer.QuotePricePlanRequest{PricePlanSlug: testPlanSlug, BillingCadence: pricer.PriceBillingCadenceYearly, CouponCode: "spring25", Country: "gb"},
+			assert: func(t *testing.T, quote *pricer.PriceQuote) {
+				assert.Equal(t, "cost-yearly", quote.CostID)
+				require.NotNil(t, quote.Discount)
+				assert.Equal(t, "spring25", quote.Discount.Code)
+				require.NotNil(t, quote.Tax)
+				assert.Equal(t, int64(7500), quote.FirstInvoice.Subtotal-quote.FirstInvoice.DiscountAmount)
+				assert.Equal(t, int64(1500), quote.FirstInvoice.TaxAmount)
+				assert.Equal(t, int64(9000), quote.FirstInvoice.Total)
+				assert.Equal(t, int64(12000), quote.Recurring.Total)
+				assert.Nil(t, quote.RecurringAfterDiscount)
+			},
+		},
+		{
+			name:        "Failure - unknown coupon code",
+			plan:        publishedPlan(),
+			req:         &pricer.QuotePricePlanRequest{PricePlanID: testPlanID, CouponCode: "NOPE"},
+			expectError: pricer.ErrInvalidPriceCoupon,
+		},
+		{
+			name:        "Failure - no cost in currency",
+			plan:        publishedPlan(),
+			req:         &pricer.QuotePricePlanRequest{PricePlanID: testPlanID, Currency: "EUR"},
+			expectError: pricer.ErrPriceCostNotFound,
+		},
+		{
+			name:        "Failure - draft plan when only published plans can be quoted",
+			plan:        makeValidPlan(),
+			req:         &pricer.QuotePricePlanRequest{PricePlanID: testPlanID, PublishedOnly: true},
+			expectError: pricer.ErrPricePlanNotFound,
+		},
+		{
+			name:        "Failure - invalid country",
+			plan:        publishedPlan(),
+			req:         &pricer.QuotePricePlanRequest{PricePlanID: testPlanID, Country: "GBR"},
+			expectError: pricer.ErrInvalidPriceQuote,
+		},
+		{
+			name:        "Failure - plan is required",
+			plan:        publishedPlan(),
+			req:         &pricer.QuotePricePlanRequest{},
+			expectError: pricer.ErrPricePlanIDRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repository := &mockPricerRepository{
+				getPricePlanByIDFunc: func(ctx context.Context, id string, req *pricer.GetPricePlanByIDRequest) (*pricer.PricePlan, error) {
+					return tt.plan, nil
+				},
+				getPricePlanBySlugFunc: func(ctx context.Context, slug string, req *pricer.GetPricePlanBySlugRequest) (*pricer.PricePlan, error) {
+					return tt.plan, nil
+				},
+			}
+			service := pricer.NewService(repository).WithTaxRules(pricer.NewStaticPriceTaxRules(
+				pricer.PriceTaxRate{Country: "GB", Name: "VAT", RateBps: 2000},
+			))
+
+			tt.req.At = testQuoteAt
+			response, err := service.QuotePricePlan(context.Background(), tt.req)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.assert(t, response.Quote)
+		})
+	}
+}
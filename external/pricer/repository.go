@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/ooaklee/ghatd/external/repository"
@@ -20,6 +21,15 @@ const (
 	// PriceFeaturesCollection collection name for price features.
 	PriceFeaturesCollection string = "pricing_features"
 
+	// PricePlanVersionsCollection collection name for price plan versions.
+	PricePlanVersionsCollection string = "pricing_plan_versions"
+
+	// PricePromotionCodesCollection collection name for promotion codes.
+	PricePromotionCodesCollection string = "pricing_promotion_codes"
+
+	// PricePromotionCodeRedemptionsCollection collection name for promotion code redemptions.
+	PricePromotionCodeRedemptionsCollection string = "pricing_promotion_code_redemptions"
+
 	defaultCollectionInitMaxAttemptsLimit = 3
 )
 
@@ -66,6 +76,34 @@ type PriceFeatureRepository interface {
 	SoftDeleteFeature(ctx context.Context, id, deletedByID, deletedAt string) error
 }
 
+// PricePlanVersionRepository describes price plan version persistence operations.
+type PricePlanVersionRepository interface {
+	CreatePricePlanVersion(ctx context.Context, pricePlanVersion *PricePlanVersion) (*PricePlanVersion, error)
+	UpdatePricePlanVersion(ctx context.Context, pricePlanVersion *PricePlanVersion) (*PricePlanVersion, error)
+	GetPricePlanVersionByID(ctx context.Context, id string) (*PricePlanVersion, error)
+	GetPricePlanVersion(ctx context.Context, pricePlanID string, version int) (*PricePlanVersion, error)
+	GetPricePlanVersions(ctx context.Context, req *GetPricePlanVersionsRequest) ([]PricePlanVersion, error)
+	GetTotalPricePlanVersions(ctx context.Context, req *GetPricePlanVersionsRequest) (int64, error)
+	GetDuePricePlanVersionMigrations(ctx context.Context, now string) ([]PricePlanVersion, error)
+	SetPricePlanCurrentVersion(ctx context.Context, id, versionID string, version int) error
+}
+
+// PricePromotionCodeRepository describes promotion code persistence operations.
+type PricePromotionCodeRepository interface {
+	CreatePromotionCode(ctx context.Context, promotionCode *PricePromotionCode) (*PricePromotionCode, error)
+	UpdatePromotionCode(ctx context.Context, promotionCode *PricePromotionCode) (*PricePromotionCode, error)
+	GetPromotionCodeByID(ctx context.Context, id string) (*PricePromotionCode, error)
+	GetPromotionCodeByCode(ctx context.Context, code string) (*PricePromotionCode, error)
+	GetPromotionCodes(ctx context.Context, req *GetPromotionCodesRequest) ([]PricePromotionCode, error)
+	GetTotalPromotionCodes(ctx context.Context, req *GetPromotionCodesRequest) (int64, error)
+	SoftDeletePromotionCode(ctx context.Context, id, deletedByID, deletedAt string) error
+	IncrementPromotionCodeRedemptions(ctx context.Context, id string) (bool, error)
+	CreatePromotionCodeRedemption(ctx context.Context, redemption *PricePromotionCodeRedemption) (*PricePromotionCodeRedemption, error)
+	DeletePromotionCodeRedemption(ctx context.Context, id string) error
+	GetPromotionCodeRedemptions(ctx context.Context, req *GetPromotionCodeRedemptionsRequest) ([]PricePromotionCodeRedemption, error)
+	GetTotalPromotionCodeRedemptions(ctx context.Context, req *GetPromotionCodeRedemptionsRequest) (int64, error)
+}
+
 // Repository represents the datastore to hold pricer data.
 type Repository struct {
 	Store                          MongoDbStore
@@ -75,6 +113,14 @@ type Repository struct {
 	pricePlansCollectionMutex    sync.Mutex
 	priceFeaturesCollection      *mongo.Collection
 	priceFeaturesCollectionMutex sync.Mutex
+
+	pricePlanVersionsCollection      *mongo.Collection
+	pricePlanVersionsCollectionMutex sync.Mutex
+
+	pricePromotionCodesCollection                *mongo.Collection
+	pricePromotionCodesCollectionMutex           sync.Mutex
+	pricePromotionCodeRedemptionsCollection      *mongo.Collection
+	pricePromotionCodeRedemptionsCollectionMutex sync.Mutex
 }
 
 // NewRepository initiates new instance of repository.
@@ -162,6 +208,108 @@ func (r *Repository) GetPriceFeaturesCollection(ctx context.Context) (*mongo.Col
 	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, PriceFeaturesCollection, collectionInitMaxAttemptsLimit, lastErr)
 }
 
+// GetPricePlanVersionsCollection returns collection used for price plan versions.
+func (r *Repository) GetPricePlanVersionsCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.pricePlanVersionsCollectionMutex.Lock()
+	defer r.pricePlanVersionsCollectionMutex.Unlock()
+
+	if r.pricePlanVersionsCollection != nil {
+		return r.pricePlanVersionsCollection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.pricePlanVersionsCollection = db.Collection(PricePlanVersionsCollection)
+		return r.pricePlanVersionsCollection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, PricePlanVersionsCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// GetPricePromotionCodesCollection returns collection used for promotion codes.
+func (r *Repository) GetPricePromotionCodesCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.pricePromotionCodesCollectionMutex.Lock()
+	defer r.pricePromotionCodesCollectionMutex.Unlock()
+
+	if r.pricePromotionCodesCollection != nil {
+		return r.pricePromotionCodesCollection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.pricePromotionCodesCollection = db.Collection(PricePromotionCodesCollection)
+		return r.pricePromotionCodesCollection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, PricePromotionCodesCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
+// GetPricePromotionCodeRedemptionsCollection returns collection used for promotion code redemptions.
+func (r *Repository) GetPricePromotionCodeRedemptionsCollection(ctx context.Context) (*mongo.Collection, error) {
+	r.pricePromotionCodeRedemptionsCollectionMutex.Lock()
+	defer r.pricePromotionCodeRedemptionsCollectionMutex.Unlock()
+
+	if r.pricePromotionCodeRedemptionsCollection != nil {
+		return r.pricePromotionCodeRedemptionsCollection, nil
+	}
+
+	var lastErr error
+	collectionInitMaxAttemptsLimit := r.collectionInitMaxAttemptsLimit
+	if collectionInitMaxAttemptsLimit <= 0 {
+		collectionInitMaxAttemptsLimit = defaultCollectionInitMaxAttemptsLimit
+	}
+	for attempt := 1; attempt <= collectionInitMaxAttemptsLimit; attempt++ {
+		_, err := r.Store.InitialiseClient(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		db, err := r.Store.GetDatabase(ctx, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.pricePromotionCodeRedemptionsCollection = db.Collection(PricePromotionCodeRedemptionsCollection)
+		return r.pricePromotionCodeRedemptionsCollection, nil
+	}
+
+	return nil, fmt.Errorf("%w: unable to initialise %s collection after %d attempts: %w", ErrDatabaseError, PricePromotionCodeRedemptionsCollection, collectionInitMaxAttemptsLimit, lastErr)
+}
+
 // CreatePricePlan creates a price plan in repository.
 func (r *Repository) CreatePricePlan(ctx context.Context, pricePlan *PricePlan) (*PricePlan, error) {
 	collection, err := r.GetPricePlansCollection(ctx)
@@ -395,6 +543,463 @@ func (r *Repository) SoftDeletePricePlan(ctx context.Context, id, deletedByID, d
 	return r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": id}, update, "price_plan")
 }
 
+// CreatePricePlanVersion creates a price plan version in repository.
+func (r *Repository) CreatePricePlanVersion(ctx context.Context, pricePlanVersion *PricePlanVersion) (*PricePlanVersion, error) {
+	collection, err := r.GetPricePlanVersionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pricePlanVersion.ID == "" {
+		pricePlanVersion.ID = toolbox.GenerateUuidV4()
+	}
+	if pricePlanVersion.CreatedAt == "" {
+		pricePlanVersion.CreatedAt = toolbox.TimeNowUTC()
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, pricePlanVersion, "price_plan_version")
+	if err != nil {
+		return nil, err
+	}
+
+	return pricePlanVersion, nil
+}
+
+// UpdatePricePlanVersion updates the supersession and grandfathering details of a price plan
+// version. The version's terms are immutable and never changed.
+func (r *Repository) UpdatePricePlanVersion(ctx context.Context, pricePlanVersion *PricePlanVersion) (*PricePlanVersion, error) {
+	collection, err := r.GetPricePlanVersionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pricePlanVersion.UpdatedAt = toolbox.TimeNowUTC()
+
+	update := bson.M{
+		"$set": bson.M{
+			"superseded_at":            pricePlanVersion.SupersededAt,
+			"superseded_by_version_id": pricePlanVersion.SupersededByVersionID,
+			"grandfathering":           pricePlanVersion.Grandfathering,
+			"updated_at":               pricePlanVersion.UpdatedAt,
+		},
+	}
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": pricePlanVersion.ID}, update, "price_plan_version")
+	if err != nil {
+		return nil, err
+	}
+
+	return pricePlanVersion, nil
+}
+
+// GetPricePlanVersionByID retrieves a price plan version by ID.
+func (r *Repository) GetPricePlanVersionByID(ctx context.Context, id string) (*PricePlanVersion, error) {
+	return r.getPricePlanVersion(ctx, bson.M{"_id": id})
+}
+
+// GetPricePlanVersion retrieves a price plan version by its plan and version number.
+func (r *Repository) GetPricePlanVersion(ctx context.Context, pricePlanID string, version int) (*PricePlanVersion, error) {
+	return r.getPricePlanVersion(ctx, bson.M{"price_plan_id": pricePlanID, "version": version})
+}
+
+// GetPricePlanVersions retrieves price plan versions with filters and pagination.
+func (r *Repository) GetPricePlanVersions(ctx context.Context, req *GetPricePlanVersionsRequest) ([]PricePlanVersion, error) {
+	collection, err := r.GetPricePlanVersionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		req = &GetPricePlanVersionsRequest{}
+	}
+
+	var results []PricePlanVersion
+	findOptions := options.Find()
+	paginationLimit := repository.GetPaginationLimit(int64(req.PerPage))
+	findOptions.SetLimit(*paginationLimit)
+	findOptions.SetSkip(*repository.GetPaginationSkip(int64(req.Page), paginationLimit))
+	findOptions.SetSort(buildPricePlanVersionSortOptions(req.Order))
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, buildPricePlanVersionQueryFilter(req), findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &results, "price_plan_versions")
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTotalPricePlanVersions retrieves the total count of price plan versions matching filters.
+func (r *Repository) GetTotalPricePlanVersions(ctx context.Context, req *GetPricePlanVersionsRequest) (int64, error) {
+	collection, err := r.GetPricePlanVersionsCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if req == nil {
+		req = &GetPricePlanVersionsRequest{}
+	}
+
+	return r.Store.ExecuteCountDocuments(ctx, collection, buildPricePlanVersionQueryFilter(req))
+}
+
+// GetDuePricePlanVersionMigrations retrieves the superseded versions whose subscribers are due
+// to be migrated by now.
+func (r *Repository) GetDuePricePlanVersionMigrations(ctx context.Context, now string) ([]PricePlanVersion, error) {
+	collection, err := r.GetPricePlanVersionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if now == "" {
+		now = toolbox.TimeNowUTC()
+	}
+
+	queryFilter := bson.M{
+		"grandfathering.policy": PriceGrandfatherPolicyMigrate,
+		"$and": []bson.M{
+			{"$or": []bson.M{
+				{"grandfathering.migrated_at": bson.M{"$exists": false}},
+				{"grandfathering.migrated_at": ""},
+			}},
+			{"$or": []bson.M{
+				{"grandfathering.migrate_at": bson.M{"$exists": false}},
+				{"grandfathering.migrate_at": ""},
+				{"grandfathering.migrate_at": bson.M{"$lte": now}},
+			}},
+		},
+	}
+
+	var results []PricePlanVersion
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, options.Find().SetSort(bson.D{{Key: "grandfathering.migrate_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &results, "price_plan_versions")
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// SetPricePlanCurrentVersion records the version a price plan currently sells.
+func (r *Repository) SetPricePlanCurrentVersion(ctx context.Context, id, versionID string, version int) error {
+	collection, err := r.GetPricePlansCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"current_version":    version,
+			"current_version_id": versionID,
+		},
+	}
+
+	return r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": id}, update, "price_plan")
+}
+
+func (r *Repository) getPricePlanVersion(ctx context.Context, queryFilter bson.M) (*PricePlanVersion, error) {
+	collection, err := r.GetPricePlanVersionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PricePlanVersion
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, options.Find().SetLimit(1))
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Store.MapOneInCursorToResult(ctx, cursor, &result, "price_plan_version")
+	if err != nil {
+		if errors.Is(err, repository.NewRepositoryError(repository.ErrResourceNotFound, "")) {
+			return nil, ErrPricePlanVersionNotFound
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreatePromotionCode creates a promotion code in repository.
+func (r *Repository) CreatePromotionCode(ctx context.Context, promotionCode *PricePromotionCode) (*PricePromotionCode, error) {
+	collection, err := r.GetPricePromotionCodesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if promotionCode.ID == "" {
+		promotionCode.ID = toolbox.GenerateUuidV4()
+	}
+	if promotionCode.CreatedAt == "" {
+		promotionCode.CreatedAt = toolbox.TimeNowUTC()
+	}
+	promotionCode.Code = NormalisePromotionCode(promotionCode.Code)
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, promotionCode, "price_promotion_code")
+	if err != nil {
+		return nil, err
+	}
+
+	return promotionCode, nil
+}
+
+// UpdatePromotionCode updates a promotion code in repository. The redemption count is only
+// changed by IncrementPromotionCodeRedemptions.
+func (r *Repository) UpdatePromotionCode(ctx context.Context, promotionCode *PricePromotionCode) (*PricePromotionCode, error) {
+	collection, err := r.GetPricePromotionCodesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	promotionCode.UpdatedAt = toolbox.TimeNowUTC()
+
+	update := bson.M{
+		"$set": bson.M{
+			"description":               promotionCode.Description,
+			"discount":                  promotionCode.Discount,
+			"active":                    promotionCode.Active,
+			"max_redemptions":           promotionCode.MaxRedemptions,
+			"max_redemptions_per_user":  promotionCode.MaxRedemptionsPerUser,
+			"first_time_customers_only": promotionCode.FirstTimeCustomersOnly,
+			"eligible_price_plan_ids":   promotionCode.EligiblePricePlanIDs,
+			"eligible_billing_cadences": promotionCode.EligibleBillingCadences,
+			"expires_at":                promotionCode.ExpiresAt,
+			"provider_refs":             promotionCode.ProviderRefs,
+			"metadata":                  promotionCode.Metadata,
+			"updated_at":                promotionCode.UpdatedAt,
+			"updated_by_id":             promotionCode.UpdatedByID,
+		},
+	}
+
+	err = r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": promotionCode.ID}, update, "price_promotion_code")
+	if err != nil {
+		return nil, err
+	}
+
+	return promotionCode, nil
+}
+
+// GetPromotionCodeByID retrieves a promotion code by ID.
+func (r *Repository) GetPromotionCodeByID(ctx context.Context, id string) (*PricePromotionCode, error) {
+	return r.getPromotionCode(ctx, bson.M{"_id": id})
+}
+
+// GetPromotionCodeByCode retrieves a promotion code by its code, including deleted codes,
+// as codes are never reused.
+func (r *Repository) GetPromotionCodeByCode(ctx context.Context, code string) (*PricePromotionCode, error) {
+	return r.getPromotionCode(ctx, bson.M{"code": NormalisePromotionCode(code)})
+}
+
+// GetPromotionCodes retrieves promotion codes with filters and pagination.
+func (r *Repository) GetPromotionCodes(ctx context.Context, req *GetPromotionCodesRequest) ([]PricePromotionCode, error) {
+	collection, err := r.GetPricePromotionCodesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		req = &GetPromotionCodesRequest{}
+	}
+
+	var results []PricePromotionCode
+	findOptions := options.Find()
+	paginationLimit := repository.GetPaginationLimit(int64(req.PerPage))
+	findOptions.SetLimit(*paginationLimit)
+	findOptions.SetSkip(*repository.GetPaginationSkip(int64(req.Page), paginationLimit))
+	findOptions.SetSort(buildPricerSortOptions(req.Order))
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, buildPromotionCodeQueryFilter(req), findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &results, "price_promotion_codes")
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTotalPromotionCodes retrieves the total count of promotion codes matching filters.
+func (r *Repository) GetTotalPromotionCodes(ctx context.Context, req *GetPromotionCodesRequest) (int64, error) {
+	collection, err := r.GetPricePromotionCodesCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if req == nil {
+		req = &GetPromotionCodesRequest{}
+	}
+
+	return r.Store.ExecuteCountDocuments(ctx, collection, buildPromotionCodeQueryFilter(req))
+}
+
+// SoftDeletePromotionCode marks a promotion code as deleted.
+func (r *Repository) SoftDeletePromotionCode(ctx context.Context, id, deletedByID, deletedAt string) error {
+	collection, err := r.GetPricePromotionCodesCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"active":        false,
+			"deleted_at":    deletedAt,
+			"deleted_by_id": deletedByID,
+			"updated_at":    toolbox.TimeNowUTC(),
+			"updated_by_id": deletedByID,
+		},
+	}
+
+	return r.Store.ExecuteUpdateOneCommand(ctx, collection, bson.M{"_id": id}, update, "price_promotion_code")
+}
+
+// IncrementPromotionCodeRedemptions adds one to the promotion code's redemption count, unless
+// the code has been deleted or has reached its redemption limit. It returns false when the
+// count was not changed, so concurrent redemptions cannot go over the limit.
+func (r *Repository) IncrementPromotionCodeRedemptions(ctx context.Context, id string) (bool, error) {
+	collection, err := r.GetPricePromotionCodesCollection(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	queryFilter := bson.M{"_id": id}
+	addDeletedFilter(queryFilter, false, true)
+	appendAndFilter(queryFilter, bson.M{
+		"$or": []bson.M{
+			{"max_redemptions": bson.M{"$exists": false}},
+			{"max_redemptions": bson.M{"$lte": 0}},
+			{"$expr": bson.M{"$lt": bson.A{"$times_redeemed", "$max_redemptions"}}},
+		},
+	})
+	update := bson.M{
+		"$inc": bson.M{"times_redeemed": 1},
+		"$set": bson.M{"updated_at": toolbox.TimeNowUTC()},
+	}
+
+	err = collection.FindOneAndUpdate(ctx, queryFilter, update).Err()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return true, nil
+}
+
+// CreatePromotionCodeRedemption records a promotion code redemption in repository.
+func (r *Repository) CreatePromotionCodeRedemption(ctx context.Context, redemption *PricePromotionCodeRedemption) (*PricePromotionCodeRedemption, error) {
+	collection, err := r.GetPricePromotionCodeRedemptionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if redemption.ID == "" {
+		redemption.ID = toolbox.GenerateUuidV4()
+	}
+	if redemption.RedeemedAt == "" {
+		redemption.RedeemedAt = toolbox.TimeNowUTC()
+	}
+
+	_, err = r.Store.ExecuteInsertOneCommand(ctx, collection, redemption, "price_promotion_code_redemption")
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrDuplicatePricePromotionCodeRedemption
+		}
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// DeletePromotionCodeRedemption removes a promotion code redemption from repository.
+func (r *Repository) DeletePromotionCodeRedemption(ctx context.Context, id string) error {
+	collection, err := r.GetPricePromotionCodeRedemptionsCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Store.ExecuteDeleteOneCommand(ctx, collection, bson.M{"_id": id}, "price_promotion_code_redemption")
+}
+
+// GetPromotionCodeRedemptions retrieves promotion code redemptions with filters and pagination.
+func (r *Repository) GetPromotionCodeRedemptions(ctx context.Context, req *GetPromotionCodeRedemptionsRequest) ([]PricePromotionCodeRedemption, error) {
+	collection, err := r.GetPricePromotionCodeRedemptionsCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		req = &GetPromotionCodeRedemptionsRequest{}
+	}
+
+	var results []PricePromotionCodeRedemption
+	findOptions := options.Find()
+	paginationLimit := repository.GetPaginationLimit(int64(req.PerPage))
+	findOptions.SetLimit(*paginationLimit)
+	findOptions.SetSkip(*repository.GetPaginationSkip(int64(req.Page), paginationLimit))
+	findOptions.SetSort(buildPromotionCodeRedemptionSortOptions(req.Order))
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, buildPromotionCodeRedemptionQueryFilter(req), findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Store.MapAllInCursorToResult(ctx, cursor, &results, "price_promotion_code_redemptions")
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTotalPromotionCodeRedemptions retrieves the total count of redemptions matching filters.
+func (r *Repository) GetTotalPromotionCodeRedemptions(ctx context.Context, req *GetPromotionCodeRedemptionsRequest) (int64, error) {
+	collection, err := r.GetPricePromotionCodeRedemptionsCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if req == nil {
+		req = &GetPromotionCodeRedemptionsRequest{}
+	}
+
+	return r.Store.ExecuteCountDocuments(ctx, collection, buildPromotionCodeRedemptionQueryFilter(req))
+}
+
+func (r *Repository) getPromotionCode(ctx context.Context, queryFilter bson.M) (*PricePromotionCode, error) {
+	collection, err := r.GetPricePromotionCodesCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PricePromotionCode
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, options.Find().SetLimit(1))
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Store.MapOneInCursorToResult(ctx, cursor, &result, "price_promotion_code")
+	if err != nil {
+		if errors.Is(err, repository.NewRepositoryError(repository.ErrResourceNotFound, "")) {
+			return nil, ErrPricePromotionCodeNotFound
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // CreateFeature creates a feature catalog item in repository.
 func (r *Repository) CreateFeature(ctx context.Context, feature *PriceFeature) (*PriceFeature, error) {
 	collection, err := r.GetPriceFeaturesCollection(ctx)
@@ -541,6 +1146,110 @@ type pricePlanIncludeOptions struct {
 	includeProviders bool
 }
 
+func buildPricePlanVersionQueryFilter(req *GetPricePlanVersionsRequest) bson.M {
+	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
+
+	if req.PricePlanID != "" {
+		queryFilter["price_plan_id"] = req.PricePlanID
+	}
+
+	if req.Provider != "" && req.ProviderPriceID != "" {
+		providerRefFilter := bson.M{
+			"$elemMatch": bson.M{
+				"provider": req.Provider,
+				"$or": []bson.M{
+					{"provider_price_id": req.ProviderPriceID},
+					{"provider_id": req.ProviderPriceID},
+					{"provider_product_id": req.ProviderPriceID},
+				},
+			},
+		}
+		appendAndFilter(queryFilter, bson.M{
+			"$or": []bson.M{
+				{"provider_refs": providerRefFilter},
+				{"costs.provider_refs": providerRefFilter},
+			},
+		})
+	}
+
+	return queryFilter
+}
+
+func buildPricePlanVersionSortOptions(order string) bson.D {
+	switch order {
+	case "version_asc":
+		return bson.D{{Key: "version", Value: 1}}
+	case "published_at_asc":
+		return bson.D{{Key: "published_at", Value: 1}}
+	case "published_at_desc":
+		return bson.D{{Key: "published_at", Value: -1}}
+	default:
+		return bson.D{{Key: "version", Value: -1}}
+	}
+}
+
+func buildPromotionCodeQueryFilter(req *GetPromotionCodesRequest) bson.M {
+	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
+
+	if req.Codes != "" {
+		var codes []string
+		for _, code := range strings.Split(req.Codes, ",") {
+			if code = NormalisePromotionCode(code); code != "" {
+				codes = append(codes, code)
+			}
+		}
+		if len(codes) > 0 {
+			queryFilter["code"] = bson.M{"$in": codes}
+		}
+	}
+
+	if req.PricePlanID != "" {
+		appendAndFilter(queryFilter, bson.M{
+			"$or": []bson.M{
+				{"eligible_price_plan_ids": bson.M{"$exists": false}},
+				{"eligible_price_plan_ids": bson.M{"$size": 0}},
+				{"eligible_price_plan_ids": req.PricePlanID},
+			},
+		})
+	}
+
+	if req.IsActive && !req.IsNotActive {
+		queryFilter["active"] = true
+	}
+	if req.IsNotActive && !req.IsActive {
+		queryFilter["active"] = false
+	}
+
+	addDeletedFilter(queryFilter, req.IsDeleted, req.IsNotDeleted)
+
+	return queryFilter
+}
+
+func buildPromotionCodeRedemptionQueryFilter(req *GetPromotionCodeRedemptionsRequest) bson.M {
+	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
+
+	if req.PromotionCodeID != "" {
+		queryFilter["promotion_code_id"] = req.PromotionCodeID
+	}
+	if req.UserID != "" {
+		queryFilter["user_id"] = req.UserID
+	}
+	if req.SubscriptionID != "" {
+		queryFilter["subscription_id"] = req.SubscriptionID
+	}
+
+	return queryFilter
+}
+
+func buildPromotionCodeRedemptionSortOptions(order string) bson.D {
+	switch order {
+	case "redeemed_at_asc":
+		return bson.D{{Key: "redeemed_at", Value: 1}}
+	default:
+		return bson.D{{Key: "redeemed_at", Value: -1}}
+	}
+}
+
 func buildPricePlanQueryFilter(req *GetPricePlansRequest) bson.M {
 	queryFilter := bson.M{"_id": bson.M{"$exists": true}}
 
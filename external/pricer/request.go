@@ -1,5 +1,7 @@
 package pricer
 
+import "time"
+
 // DeletePricePlanRequest represents the request payload for soft-deleting a price plan by its ID.
 type DeletePricePlanRequest struct {
 	// ID is the ID of the price plan to delete.
@@ -259,6 +261,14 @@ type PublishPricePlanRequest struct {
 
 	// PublishAtUtc is the target publish at date for the price plan.
 	PublishAtUtc string `json:"publish_at_utc,omitempty"`
+
+	// GrandfatherPolicy decides what happens to subscribers of the version being superseded.
+	// Default keep
+	GrandfatherPolicy PriceGrandfatherPolicy `json:"grandfather_policy,omitempty"`
+
+	// MigrateAtUtc is when subscribers of the superseded version are migrated to the new
+	// version, for the migrate policy. Default now
+	MigrateAtUtc string `json:"migrate_at_utc,omitempty"`
 }
 
 // ArchivePricePlanRequest holds everything needed to archive a price plan.
@@ -418,3 +428,335 @@ func (g *GetFeaturesRequest) GetMetaData() map[string]interface{} {
 
 	return responseMap
 }
+
+// GetPricePlanVersionsRequest holds everything needed to get the version history of a price plan.
+type GetPricePlanVersionsRequest struct {
+	// PricePlanID is the ID of the price plan whose versions are returned.
+	PricePlanID string `validate:"omitempty,uuid"`
+
+	// Provider limits the versions to those linked to a provider-side price, with ProviderPriceID.
+	Provider string
+
+	// ProviderPriceID limits the versions to those linked to this provider-side price, plan or product ID.
+	ProviderPriceID string
+
+	// Order defines how the response should be sorted. Default: newest -> oldest (version_desc)
+	Order string `query:"order"`
+
+	// PerPage is the total number of versions to return per page.
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from.
+	Page int `query:"page"`
+
+	// TotalCount specifies the total count of all matching versions.
+	TotalCount int
+
+	// Meta determines whether the response should contain meta information.
+	Meta bool `query:"meta"`
+}
+
+// GetPricePlanVersionRequest holds everything needed to get a version of a price plan.
+type GetPricePlanVersionRequest struct {
+	// PricePlanID is the ID of the price plan.
+	PricePlanID string `validate:"required,uuid"`
+
+	// Version is the version number.
+	Version int `validate:"required,min=1"`
+}
+
+// GetPricePlanVersionByIDRequest holds everything needed to get a price plan version by ID.
+type GetPricePlanVersionByIDRequest struct {
+	// ID is the ID of the price plan version.
+	ID string
+}
+
+// FindPricePlanVersionByProviderPriceRequest holds everything needed to find the newest
+// published version linked to a provider-side price.
+type FindPricePlanVersionByProviderPriceRequest struct {
+	// Provider is the payment provider.
+	Provider string
+
+	// ProviderPriceID is the provider-side price, plan or product ID.
+	ProviderPriceID string
+}
+
+// SchedulePricePlanVersionMigrationRequest holds everything needed to set the grandfathering
+// rule of a superseded price plan version.
+type SchedulePricePlanVersionMigrationRequest struct {
+	// PricePlanID is the ID of the price plan.
+	PricePlanID string `validate:"required,uuid"`
+
+	// Version is the number of the superseded version.
+	Version int `validate:"required,min=1"`
+
+	// UserID is the ID of the user setting the rule.
+	UserID string `validate:"required,uuid"`
+
+	// Policy is whether subscribers keep the version's terms or are migrated. Default migrate
+	Policy PriceGrandfatherPolicy `json:"policy,omitempty"`
+
+	// ToVersion is the version subscribers are migrated to. Default the plan's current version
+	ToVersion int `json:"to_version,omitempty"`
+
+	// MigrateAtUtc is when subscribers are migrated. Default now
+	MigrateAtUtc string `json:"migrate_at_utc,omitempty"`
+}
+
+// GetDuePricePlanVersionMigrationsRequest holds everything needed to get the versions whose
+// subscribers are due to be migrated.
+type GetDuePricePlanVersionMigrationsRequest struct {
+	// Now is the time migrations are due by. Default the current time
+	Now time.Time
+}
+
+// CompletePricePlanVersionMigrationRequest holds everything needed to record that a version's
+// subscribers have been migrated.
+type CompletePricePlanVersionMigrationRequest struct {
+	// ID is the ID of the migrated price plan version.
+	ID string
+
+	// MigratedCount is the number of subscriptions migrated.
+	MigratedCount int
+}
+
+// QuotePricePlanRequest holds everything needed to quote a price plan cost.
+type QuotePricePlanRequest struct {
+	// PricePlanID is the ID of the price plan to quote. Either it or PricePlanSlug is required.
+	PricePlanID string
+
+	// PricePlanSlug is the slug of the price plan to quote.
+	PricePlanSlug string
+
+	// CostID is the ID of the cost to quote. Default the first cost matching Currency and BillingCadence
+	CostID string `query:"cost_id"`
+
+	// Currency is the ISO 4217 currency code of the cost to quote.
+	Currency string `query:"currency"`
+
+	// BillingCadence is how often the cost to quote is charged.
+	BillingCadence PriceBillingCadence `query:"billing_cadence"`
+
+	// Quantity is the number of units to quote, e.g. seats. Default 1, at most MaxPriceQuoteQuantity
+	Quantity int64 `query:"quantity" validate:"omitempty,min=1,max=1000000"`
+
+	// CouponCode is a coupon code claiming one of the plan's discounts, or a promotion code.
+	CouponCode string `query:"coupon"`
+
+	// UserID is the ID of the user the quote is for, used to check promotion code limits.
+	UserID string
+
+	// Country is the ISO 3166-1 alpha-2 country code used for tax.
+	Country string `query:"country"`
+
+	// PublishedOnly only quotes plans that are published and not deleted.
+	PublishedOnly bool
+
+	// At is when the quote is calculated. Default now
+	At time.Time
+}
+
+// CreatePromotionCodeRequest holds everything needed to create a promotion code.
+type CreatePromotionCodeRequest struct {
+	// UserID is the ID of the user making the request.
+	UserID string `validate:"required,uuid"`
+
+	// Code is the code customers enter.
+	Code string `json:"code" validate:"required"`
+
+	// Description is the admin-facing description of the promotion.
+	Description string `json:"description,omitempty"`
+
+	// Discount is the discount the code claims.
+	Discount PriceDiscount `json:"discount"`
+
+	// Active is whether the code can be redeemed. Default true
+	Active *bool `json:"active,omitempty"`
+
+	// MaxRedemptions is the number of times the code can be redeemed in total. Zero is unlimited
+	MaxRedemptions int64 `json:"max_redemptions,omitempty" validate:"omitempty,min=0"`
+
+	// MaxRedemptionsPerUser is the number of times a user can redeem the code. Zero is unlimited
+	MaxRedemptionsPerUser int64 `json:"max_redemptions_per_user,omitempty" validate:"omitempty,min=0"`
+
+	// FirstTimeCustomersOnly restricts the code to users who have never subscribed.
+	FirstTimeCustomersOnly bool `json:"first_time_customers_only,omitempty"`
+
+	// EligiblePricePlanIDs restricts the code to these price plans.
+	EligiblePricePlanIDs []string `json:"eligible_price_plan_ids,omitempty"`
+
+	// EligibleBillingCadences restricts the code to these billing cadences.
+	EligibleBillingCadences []PriceBillingCadence `json:"eligible_billing_cadences,omitempty"`
+
+	// ExpiresAtUtc is when the code can no longer be redeemed.
+	ExpiresAtUtc string `json:"expires_at_utc,omitempty"`
+
+	// ProviderRefs are the provider-side coupon, discount or promotion code references.
+	ProviderRefs []PriceProviderRef `json:"provider_refs,omitempty"`
+
+	// Metadata stores additional project-specific data.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// UpdatePromotionCodeRequest holds everything needed to update a promotion code. The code
+// itself cannot be changed.
+type UpdatePromotionCodeRequest struct {
+	// UserID is the ID of the user making the request.
+	UserID string `validate:"required,uuid"`
+
+	// ID is the ID of the promotion code to update.
+	ID string `validate:"required,uuid"`
+
+	// Description is the updated description of the promotion.
+	Description *string `json:"description,omitempty"`
+
+	// Discount is the updated discount the code claims.
+	Discount *PriceDiscount `json:"discount,omitempty"`
+
+	// Active is whether the code can be redeemed.
+	Active *bool `json:"active,omitempty"`
+
+	// MaxRedemptions is the updated total redemption limit.
+	MaxRedemptions *int64 `json:"max_redemptions,omitempty"`
+
+	// MaxRedemptionsPerUser is the updated per-user redemption limit.
+	MaxRedemptionsPerUser *int64 `json:"max_redemptions_per_user,omitempty"`
+
+	// FirstTimeCustomersOnly is the updated first-time customer restriction.
+	FirstTimeCustomersOnly *bool `json:"first_time_customers_only,omitempty"`
+
+	// EligiblePricePlanIDs are the updated eligible price plans.
+	EligiblePricePlanIDs []string `json:"eligible_price_plan_ids,omitempty"`
+
+	// EligibleBillingCadences are the updated eligible billing cadences.
+	EligibleBillingCadences []PriceBillingCadence `json:"eligible_billing_cadences,omitempty"`
+
+	// ExpiresAtUtc is the updated expiry. An empty string removes it
+	ExpiresAtUtc *string `json:"expires_at_utc,omitempty"`
+
+	// ProviderRefs are the updated provider-side references.
+	ProviderRefs []PriceProviderRef `json:"provider_refs,omitempty"`
+
+	// Metadata stores updated project-specific data.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// GetPromotionCodeByIDRequest holds everything needed to get a promotion code by ID.
+type GetPromotionCodeByIDRequest struct {
+	// ID is the ID of the promotion code to retrieve.
+	ID string `validate:"required,uuid"`
+}
+
+// GetPromotionCodesRequest holds everything needed to get promotion codes.
+type GetPromotionCodesRequest struct {
+	// Order defines how the response should be sorted. Default: newest -> oldest (created_at_desc)
+	Order string `query:"order"`
+
+	// PerPage is the total number of promotion codes to return per page.
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from.
+	Page int `query:"page"`
+
+	// TotalCount specifies the total count of all matching promotion codes.
+	TotalCount int
+
+	// Meta determines whether the response should contain meta information.
+	Meta bool `query:"meta"`
+
+	// Codes filters promotion codes by code.
+	// comma-separated list of codes
+	Codes string `query:"codes"`
+
+	// PricePlanID filters for promotion codes that can be used for the price plan.
+	PricePlanID string `query:"price_plan_id"`
+
+	// IsActive filters for promotion codes that are switched on.
+	IsActive bool `query:"is_active"`
+
+	// IsNotActive filters for promotion codes that are switched off.
+	IsNotActive bool `query:"is_not_active"`
+
+	// IsDeleted filters for promotion codes that are deleted.
+	IsDeleted bool `query:"is_deleted"`
+
+	// IsNotDeleted filters for promotion codes that are not deleted.
+	IsNotDeleted bool `query:"is_not_deleted"`
+}
+
+// DeletePromotionCodeRequest holds everything needed to soft-delete a promotion code.
+type DeletePromotionCodeRequest struct {
+	// ID is the ID of the promotion code to delete.
+	ID string `validate:"required,uuid"`
+
+	// UserID is the ID of the user performing the deletion.
+	UserID string `validate:"required,uuid"`
+}
+
+// ValidatePromotionCodeRequest holds everything needed to check whether a promotion code
+// can be redeemed.
+type ValidatePromotionCodeRequest struct {
+	// Code is the code to check.
+	Code string `query:"code" validate:"required"`
+
+	// UserID is the ID of the user redeeming the code. Empty skips the per-user and
+	// first-time customer checks
+	UserID string
+
+	// PricePlanID is the ID of the plan being bought.
+	PricePlanID string `query:"price_plan_id"`
+
+	// PricePlanSlug is the slug of the plan being bought, used when there is no PricePlanID.
+	PricePlanSlug string `query:"price_plan_slug"`
+
+	// BillingCadence is the cadence being bought.
+	BillingCadence PriceBillingCadence `query:"billing_cadence"`
+
+	// At is when the code is checked. Default now
+	At time.Time
+}
+
+// RedeemPromotionCodeRequest holds everything needed to redeem a promotion code for a subscription.
+type RedeemPromotionCodeRequest struct {
+	// Code is the code to redeem.
+	Code string
+
+	// UserID is the ID of the user redeeming the code.
+	UserID string
+
+	// SubscriptionID is the ID of the billing subscription the code is redeemed for.
+	SubscriptionID string
+
+	// PricePlanID is the ID of the plan the subscription was bought on.
+	PricePlanID string
+
+	// BillingCadence is how often the subscription is charged.
+	BillingCadence PriceBillingCadence
+}
+
+// GetPromotionCodeRedemptionsRequest holds everything needed to get promotion code redemptions.
+type GetPromotionCodeRedemptionsRequest struct {
+	// PromotionCodeID limits the redemptions to one promotion code.
+	PromotionCodeID string `validate:"omitempty,uuid"`
+
+	// UserID limits the redemptions to one user.
+	UserID string `query:"user_id"`
+
+	// SubscriptionID limits the redemptions to one subscription.
+	SubscriptionID string `query:"subscription_id"`
+
+	// Order defines how the response should be sorted. Default: newest -> oldest (redeemed_at_desc)
+	Order string `query:"order"`
+
+	// PerPage is the total number of redemptions to return per page.
+	PerPage int `query:"per_page"`
+
+	// Page specifies the page results should be taken from.
+	Page int `query:"page"`
+
+	// TotalCount specifies the total count of all matching redemptions.
+	TotalCount int
+
+	// Meta determines whether the response should contain meta information.
+	Meta bool `query:"meta"`
+}
@@ -162,3 +162,168 @@ func (g *GetFeaturesResponse) GetMetaData() map[string]interface{} {
 
 	return responseMap
 }
+
+// GetPricePlanVersionsResponse holds everything needed to return a price plan's version history.
+type GetPricePlanVersionsResponse struct {
+	// PricePlanVersions is the list of versions found.
+	PricePlanVersions []PricePlanVersion `json:"price_plan_versions"`
+
+	// Total is the number of versions found that matched provided filters.
+	Total int
+
+	// TotalPages is the total pages available, based on the provided filters and resources per page.
+	TotalPages int
+
+	// PerPage is the number of versions set to be returned per page.
+	PerPage int
+
+	// Page specifies the page results were taken from.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetPricePlanVersionsResponse.
+func (g *GetPricePlanVersionsResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
+
+// GetPricePlanVersionResponse holds everything needed to return a price plan version.
+type GetPricePlanVersionResponse struct {
+	// PricePlanVersion is the requested version.
+	PricePlanVersion *PricePlanVersion `json:"price_plan_version"`
+}
+
+// SchedulePricePlanVersionMigrationResponse holds everything needed to return the version
+// whose grandfathering rule was set.
+type SchedulePricePlanVersionMigrationResponse struct {
+	// PricePlanVersion is the updated version.
+	PricePlanVersion *PricePlanVersion `json:"price_plan_version"`
+}
+
+// GetDuePricePlanVersionMigrationsResponse holds the versions whose subscribers are due to be migrated.
+type GetDuePricePlanVersionMigrationsResponse struct {
+	// PricePlanVersions is the list of versions due to be migrated.
+	PricePlanVersions []PricePlanVersion `json:"price_plan_versions"`
+}
+
+// QuotePricePlanResponse holds everything needed to return a price plan quote.
+type QuotePricePlanResponse struct {
+	// Quote is the calculated quote.
+	Quote *PriceQuote `json:"quote"`
+}
+
+// CreatePromotionCodeResponse holds everything needed to return a created promotion code.
+type CreatePromotionCodeResponse struct {
+	// PromotionCode is the created promotion code.
+	PromotionCode *PricePromotionCode `json:"promotion_code"`
+}
+
+// UpdatePromotionCodeResponse holds everything needed to return an updated promotion code.
+type UpdatePromotionCodeResponse struct {
+	// PromotionCode is the updated promotion code.
+	PromotionCode *PricePromotionCode `json:"promotion_code"`
+}
+
+// GetPromotionCodeByIDResponse holds everything needed to return a promotion code.
+type GetPromotionCodeByIDResponse struct {
+	// PromotionCode is the requested promotion code.
+	PromotionCode *PricePromotionCode `json:"promotion_code"`
+}
+
+// DeletePromotionCodeResponse holds everything needed to return a deleted promotion code.
+type DeletePromotionCodeResponse struct {
+	// PromotionCode is the deleted promotion code.
+	PromotionCode *PricePromotionCode `json:"promotion_code"`
+}
+
+// GetPromotionCodesResponse holds everything needed to return promotion codes.
+type GetPromotionCodesResponse struct {
+	// PromotionCodes is the list of promotion codes found.
+	PromotionCodes []PricePromotionCode `json:"promotion_codes"`
+
+	// Total is the number of promotion codes found that matched provided filters.
+	Total int
+
+	// TotalPages is the total pages available, based on the provided filters and resources per page.
+	TotalPages int
+
+	// PerPage is the number of promotion codes set to be returned per page.
+	PerPage int
+
+	// Page specifies the page results were taken from.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetPromotionCodesResponse.
+func (g *GetPromotionCodesResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
+
+// ValidatePromotionCodeResponse holds whether a promotion code can be redeemed, and the
+// discount it claims when it can.
+type ValidatePromotionCodeResponse struct {
+	// Code is the checked code.
+	Code string `json:"code"`
+
+	// Valid is whether the code can be redeemed.
+	Valid bool `json:"valid"`
+
+	// Reason is why the code cannot be redeemed.
+	Reason PricePromotionCodeReason `json:"reason,omitempty"`
+
+	// Discount is the discount the code claims, when it can be redeemed.
+	Discount *PriceDiscount `json:"discount,omitempty"`
+
+	// PromotionCode is the matched promotion code, for callers inside the platform. It is
+	// never returned to customers
+	PromotionCode *PricePromotionCode `json:"-"`
+}
+
+// RedeemPromotionCodeResponse holds everything needed to return a promotion code redemption.
+type RedeemPromotionCodeResponse struct {
+	// Redemption is the recorded redemption.
+	Redemption *PricePromotionCodeRedemption `json:"redemption"`
+}
+
+// GetPromotionCodeRedemptionsResponse holds everything needed to return promotion code redemptions.
+type GetPromotionCodeRedemptionsResponse struct {
+	// Redemptions is the list of redemptions found.
+	Redemptions []PricePromotionCodeRedemption `json:"redemptions"`
+
+	// Total is the number of redemptions found that matched provided filters.
+	Total int
+
+	// TotalPages is the total pages available, based on the provided filters and resources per page.
+	TotalPages int
+
+	// PerPage is the number of redemptions set to be returned per page.
+	PerPage int
+
+	// Page specifies the page results were taken from.
+	Page int
+}
+
+// GetMetaData returns a map containing metadata about the GetPromotionCodeRedemptionsResponse.
+func (g *GetPromotionCodeRedemptionsResponse) GetMetaData() map[string]interface{} {
+	var responseMap = make(map[string]interface{})
+
+	responseMap[string(toolbox.ResponseMetaKeyResourcePerPage)] = g.PerPage
+	responseMap[string(toolbox.ResponseMetaKeyTotalResources)] = g.Total
+	responseMap[string(toolbox.ResponseMetaKeyTotalPages)] = g.TotalPages
+	responseMap[string(toolbox.ResponseMetaKeyPage)] = g.Page
+
+	return responseMap
+}
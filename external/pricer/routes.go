@@ -22,6 +22,17 @@ type PriceHandler interface {
 	UpdateFeature(w http.ResponseWriter, r *http.Request)
 	GetFeatures(w http.ResponseWriter, r *http.Request)
 	DeleteFeature(w http.ResponseWriter, r *http.Request)
+	GetPricePlanVersions(w http.ResponseWriter, r *http.Request)
+	GetPricePlanVersion(w http.ResponseWriter, r *http.Request)
+	SchedulePricePlanVersionMigration(w http.ResponseWriter, r *http.Request)
+	QuotePricePlan(w http.ResponseWriter, r *http.Request)
+	CreatePromotionCode(w http.ResponseWriter, r *http.Request)
+	UpdatePromotionCode(w http.ResponseWriter, r *http.Request)
+	GetPromotionCodeByID(w http.ResponseWriter, r *http.Request)
+	GetPromotionCodes(w http.ResponseWriter, r *http.Request)
+	DeletePromotionCode(w http.ResponseWriter, r *http.Request)
+	GetPromotionCodeRedemptions(w http.ResponseWriter, r *http.Request)
+	ValidatePromotionCode(w http.ResponseWriter, r *http.Request)
 }
 
 // APIPricesV1Prefix base URI prefix for all v1 price routes.
@@ -43,9 +54,17 @@ type AttachRoutesRequest struct {
 func AttachRoutes(request *AttachRoutesRequest) {
 	httpRouter := request.Router.GetRouter()
 
+	// Open routes are registered first so the admin-only subrouter does not claim them
+	openRoutes := httpRouter.PathPrefix(APIPricesV1Prefix).Subrouter()
+	openRoutes.HandleFunc("/promotion-codes/validate", request.Handler.ValidatePromotionCode).Methods(http.MethodGet, http.MethodOptions)
+
 	groupsAdminOnlyRoutes := httpRouter.PathPrefix(APIPricesV1Prefix).Subrouter()
 	groupsAdminOnlyRoutes.HandleFunc("/plans/{id}/publish", request.Handler.PublishPricePlan).Methods(http.MethodPost, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/plans/{id}/archive", request.Handler.ArchivePricePlan).Methods(http.MethodPost, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/plans/{id}/versions", request.Handler.GetPricePlanVersions).Methods(http.MethodGet, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/plans/{id}/versions/{version:[0-9]+}", request.Handler.GetPricePlanVersion).Methods(http.MethodGet, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/plans/{id}/versions/{version:[0-9]+}/migration", request.Handler.SchedulePricePlanVersionMigration).Methods(http.MethodPut, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/plans/{id}/quote", request.Handler.QuotePricePlan).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/plans/{id:[0-9a-fA-F-]{36}}", request.Handler.GetPricePlanByID).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/plans/{slug:[A-Za-z0-9][A-Za-z0-9_-]*}", request.Handler.GetPricePlanBySlug).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/plans/{id}", request.Handler.UpdatePricePlan).Methods(http.MethodPut, http.MethodOptions)
@@ -57,6 +76,12 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	groupsAdminOnlyRoutes.HandleFunc("/features/{id}", request.Handler.DeleteFeature).Methods(http.MethodDelete, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/features", request.Handler.GetFeatures).Methods(http.MethodGet, http.MethodOptions)
 	groupsAdminOnlyRoutes.HandleFunc("/features", request.Handler.CreateFeature).Methods(http.MethodPost, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/promotion-codes/{id}/redemptions", request.Handler.GetPromotionCodeRedemptions).Methods(http.MethodGet, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/promotion-codes/{id}", request.Handler.GetPromotionCodeByID).Methods(http.MethodGet, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/promotion-codes/{id}", request.Handler.UpdatePromotionCode).Methods(http.MethodPut, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/promotion-codes/{id}", request.Handler.DeletePromotionCode).Methods(http.MethodDelete, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/promotion-codes", request.Handler.GetPromotionCodes).Methods(http.MethodGet, http.MethodOptions)
+	groupsAdminOnlyRoutes.HandleFunc("/promotion-codes", request.Handler.CreatePromotionCode).Methods(http.MethodPost, http.MethodOptions)
 
 	if request.AdminOnlyMiddleware != nil {
 		groupsAdminOnlyRoutes.Use(request.AdminOnlyMiddleware)
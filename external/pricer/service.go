@@ -18,6 +18,14 @@ import (
 type PricerRepository interface {
 	PricePlanRepository
 	PriceFeatureRepository
+	PricePlanVersionRepository
+	PricePromotionCodeRepository
+}
+
+// PriceCustomerHistory reports whether a user has subscribed before, so promotion codes
+// can be restricted to first-time customers.
+type PriceCustomerHistory interface {
+	HasPreviousSubscription(ctx context.Context, userID string, excludeSubscriptionID string) (bool, error)
 }
 
 // PricerService describes pricing business operations.
@@ -35,11 +43,33 @@ type PricerService interface {
 	UpdateFeature(ctx context.Context, req *UpdateFeatureRequest) (*UpdateFeatureResponse, error)
 	GetFeatures(ctx context.Context, req *GetFeaturesRequest) (*GetFeaturesResponse, error)
 	DeleteFeature(ctx context.Context, req *DeleteFeatureRequest) (*DeleteFeatureResponse, error)
+	GetPricePlanVersions(ctx context.Context, req *GetPricePlanVersionsRequest) (*GetPricePlanVersionsResponse, error)
+	GetPricePlanVersion(ctx context.Context, req *GetPricePlanVersionRequest) (*GetPricePlanVersionResponse, error)
+	GetPricePlanVersionByID(ctx context.Context, req *GetPricePlanVersionByIDRequest) (*GetPricePlanVersionResponse, error)
+	FindPricePlanVersionByProviderPrice(ctx context.Context, req *FindPricePlanVersionByProviderPriceRequest) (*GetPricePlanVersionResponse, error)
+	SchedulePricePlanVersionMigration(ctx context.Context, req *SchedulePricePlanVersionMigrationRequest) (*SchedulePricePlanVersionMigrationResponse, error)
+	GetDuePricePlanVersionMigrations(ctx context.Context, req *GetDuePricePlanVersionMigrationsRequest) (*GetDuePricePlanVersionMigrationsResponse, error)
+	CompletePricePlanVersionMigration(ctx context.Context, req *CompletePricePlanVersionMigrationRequest) (*GetPricePlanVersionResponse, error)
+	QuotePricePlan(ctx context.Context, req *QuotePricePlanRequest) (*QuotePricePlanResponse, error)
+	CreatePromotionCode(ctx context.Context, req *CreatePromotionCodeRequest) (*CreatePromotionCodeResponse, error)
+	UpdatePromotionCode(ctx context.Context, req *UpdatePromotionCodeRequest) (*UpdatePromotionCodeResponse, error)
+	GetPromotionCodeByID(ctx context.Context, req *GetPromotionCodeByIDRequest) (*GetPromotionCodeByIDResponse, error)
+	GetPromotionCodes(ctx context.Context, req *GetPromotionCodesRequest) (*GetPromotionCodesResponse, error)
+	DeletePromotionCode(ctx context.Context, req *DeletePromotionCodeRequest) (*DeletePromotionCodeResponse, error)
+	ValidatePromotionCode(ctx context.Context, req *ValidatePromotionCodeRequest) (*ValidatePromotionCodeResponse, error)
+	RedeemPromotionCode(ctx context.Context, req *RedeemPromotionCodeRequest) (*RedeemPromotionCodeResponse, error)
+	GetPromotionCodeRedemptions(ctx context.Context, req *GetPromotionCodeRedemptionsRequest) (*GetPromotionCodeRedemptionsResponse, error)
 }
 
 // Service represents the pricer service.
 type Service struct {
 	PricerRepository PricerRepository
+
+	// TaxRules looks up the tax applied to quotes (optional)
+	TaxRules PriceTaxRules
+
+	// CustomerHistory checks first-time customer promotion codes (optional)
+	CustomerHistory PriceCustomerHistory
 }
 
 // NewService returns a new instance of the pricer service.
@@ -49,6 +79,19 @@ func NewService(pricerRepository PricerRepository) *Service {
 	}
 }
 
+// WithTaxRules adds tax to price quotes.
+func (s *Service) WithTaxRules(taxRules PriceTaxRules) *Service {
+	s.TaxRules = taxRules
+	return s
+}
+
+// WithCustomerHistory enforces first-time customer restrictions on promotion codes. Without
+// it the restriction cannot be checked and is skipped.
+func (s *Service) WithCustomerHistory(customerHistory PriceCustomerHistory) *Service {
+	s.CustomerHistory = customerHistory
+	return s
+}
+
 // CreatePricePlan creates a new price plan.
 func (s *Service) CreatePricePlan(ctx context.Context, req *CreatePricePlanRequest) (*CreatePricePlanResponse, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/pricer")
@@ -116,6 +159,14 @@ func (s *Service) CreatePricePlan(ctx context.Context, req *CreatePricePlanReque
 		return &CreatePricePlanResponse{}, err
 	}
 
+	if createdPricePlan.Status == PricePlanStatusPublished {
+		err = s.publishPricePlanVersion(ctx, createdPricePlan, &PublishPricePlanRequest{UserID: req.UserID, GrandfatherPolicy: PriceGrandfatherPolicyKeep}, "")
+		if err != nil {
+			logger.Error("failed-to-publish-price-plan-version", zap.String("price-plan-id", createdPricePlan.ID), zap.Error(err))
+			return &CreatePricePlanResponse{}, err
+		}
+	}
+
 	return &CreatePricePlanResponse{PricePlan: createdPricePlan}, nil
 }
 
@@ -409,6 +460,17 @@ func (s *Service) PublishPricePlan(ctx context.Context, req *PublishPricePlanReq
 		return nil, ErrInvalidPricePlanPayload
 	}
 
+	if req.GrandfatherPolicy == "" {
+		req.GrandfatherPolicy = PriceGrandfatherPolicyKeep
+	}
+	if !IsValidPriceGrandfatherPolicy(string(req.GrandfatherPolicy)) {
+		return nil, ErrInvalidPricePlanVersionMigration
+	}
+	migrateAt, err := normaliseDateParam(req.MigrateAtUtc)
+	if err != nil {
+		return nil, err
+	}
+
 	pricePlan, err := s.PricerRepository.GetPricePlanByID(ctx, req.ID, &GetPricePlanByIDRequest{
 		IncludeFeatures:  true,
 		IncludeCosts:     true,
@@ -441,6 +503,12 @@ func (s *Service) PublishPricePlan(ctx context.Context, req *PublishPricePlanReq
 		return &PublishPricePlanResponse{}, err
 	}
 
+	err = s.publishPricePlanVersion(ctx, pricePlan, req, migrateAt)
+	if err != nil {
+		logger.Error("failed-to-publish-price-plan-version", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &PublishPricePlanResponse{}, err
+	}
+
 	publishedPricePlan, err := s.PricerRepository.GetPricePlanByID(ctx, req.ID, &GetPricePlanByIDRequest{
 		IncludeFeatures:  true,
 		IncludeCosts:     true,
@@ -710,6 +778,383 @@ func (s *Service) DeleteFeature(ctx context.Context, req *DeleteFeatureRequest)
 	return &DeleteFeatureResponse{Feature: feature}, nil
 }
 
+// publishPricePlanVersion snapshots the plan's terms as its next version, makes it the plan's
+// current version and applies the requested grandfathering rule to the version it supersedes.
+func (s *Service) publishPricePlanVersion(ctx context.Context, pricePlan *PricePlan, req *PublishPricePlanRequest, migrateAt string) error {
+	var previousVersion *PricePlanVersion
+	if pricePlan.CurrentVersionID != "" {
+		version, err := s.PricerRepository.GetPricePlanVersionByID(ctx, pricePlan.CurrentVersionID)
+		if err != nil && !errors.Is(err, ErrPricePlanVersionNotFound) {
+			return err
+		}
+		previousVersion = version
+	}
+
+	pricePlanVersion, err := s.PricerRepository.CreatePricePlanVersion(ctx, NewPricePlanVersion(pricePlan, pricePlan.CurrentVersion+1, pricePlan.PublishedAt, req.UserID))
+	if err != nil {
+		return err
+	}
+
+	err = s.PricerRepository.SetPricePlanCurrentVersion(ctx, pricePlan.ID, pricePlanVersion.ID, pricePlanVersion.Version)
+	if err != nil {
+		return err
+	}
+	pricePlan.CurrentVersion = pricePlanVersion.Version
+	pricePlan.CurrentVersionID = pricePlanVersion.ID
+
+	if previousVersion == nil {
+		return nil
+	}
+
+	previousVersion.SupersededAt = toolbox.TimeNowUTC()
+	previousVersion.SupersededByVersionID = pricePlanVersion.ID
+	previousVersion.Grandfathering = &PricePlanGrandfathering{
+		Policy:      req.GrandfatherPolicy,
+		UpdatedByID: req.UserID,
+	}
+	if req.GrandfatherPolicy == PriceGrandfatherPolicyMigrate {
+		previousVersion.Grandfathering.MigrateAt = migrateAt
+		previousVersion.Grandfathering.MigrateToVersionID = pricePlanVersion.ID
+		previousVersion.Grandfathering.MigrateToVersion = pricePlanVersion.Version
+	}
+
+	_, err = s.PricerRepository.UpdatePricePlanVersion(ctx, previousVersion)
+	return err
+}
+
+// GetPricePlanVersions returns the published versions of a price plan, newest first.
+func (s *Service) GetPricePlanVersions(ctx context.Context, req *GetPricePlanVersionsRequest) (*GetPricePlanVersionsResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/pricer")
+
+	if req == nil {
+		req = &GetPricePlanVersionsRequest{}
+	}
+	if req.Order == "" {
+		req.Order = "version_desc"
+	}
+	if req.PerPage == 0 {
+		req.PerPage = 25
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if _, ok := validPricePlanVersionSortOrders[req.Order]; !ok {
+		return nil, ErrInvalidPriceQueryParam
+	}
+
+	total, err := s.PricerRepository.GetTotalPricePlanVersions(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-price-plan-versions-total", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetPricePlanVersionsResponse{}, err
+	}
+	req.TotalCount = int(total)
+
+	pricePlanVersions, err := s.PricerRepository.GetPricePlanVersions(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-price-plan-versions", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetPricePlanVersionsResponse{}, err
+	}
+
+	paginatedResponse, err := toolbox.Paginate(ctx, &toolbox.PaginationRequest{
+		PerPage: req.PerPage,
+		Page:    req.Page,
+	}, pricePlanVersions, req.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPricePlanVersionsResponse{
+		Total:             paginatedResponse.Total,
+		TotalPages:        paginatedResponse.TotalPages,
+		PricePlanVersions: paginatedResponse.Resources,
+		Page:              paginatedResponse.Page,
+		PerPage:           paginatedResponse.ResourcePerPage,
+	}, nil
+}
+
+// GetPricePlanVersion returns a version of a price plan by its number.
+func (s *Service) GetPricePlanVersion(ctx context.Context, req *GetPricePlanVersionRequest) (*GetPricePlanVersionResponse, error) {
+	if req == nil || strings.TrimSpace(req.PricePlanID) == "" {
+		return nil, ErrPricePlanIDRequired
+	}
+
+	pricePlanVersion, err := s.PricerRepository.GetPricePlanVersion(ctx, req.PricePlanID, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPricePlanVersionResponse{PricePlanVersion: pricePlanVersion}, nil
+}
+
+// GetPricePlanVersionByID returns a price plan version by its ID.
+func (s *Service) GetPricePlanVersionByID(ctx context.Context, req *GetPricePlanVersionByIDRequest) (*GetPricePlanVersionResponse, error) {
+	if req == nil || strings.TrimSpace(req.ID) == "" {
+		return nil, ErrPricePlanVersionNotFound
+	}
+
+	pricePlanVersion, err := s.PricerRepository.GetPricePlanVersionByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPricePlanVersionResponse{PricePlanVersion: pricePlanVersion}, nil
+}
+
+// FindPricePlanVersionByProviderPrice returns the newest version linked to the provider-side
+// price, plan or product ID. This is the version a subscription bought through that price.
+func (s *Service) FindPricePlanVersionByProviderPrice(ctx context.Context, req *FindPricePlanVersionByProviderPriceRequest) (*GetPricePlanVersionResponse, error) {
+	if req == nil || strings.TrimSpace(req.Provider) == "" || strings.TrimSpace(req.ProviderPriceID) == "" {
+		return nil, ErrPricePlanVersionNotFound
+	}
+
+	pricePlanVersions, err := s.PricerRepository.GetPricePlanVersions(ctx, &GetPricePlanVersionsRequest{
+		Provider:        req.Provider,
+		ProviderPriceID: req.ProviderPriceID,
+		Order:           "published_at_desc",
+		PerPage:         1,
+		Page:            1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pricePlanVersions) == 0 {
+		return nil, ErrPricePlanVersionNotFound
+	}
+
+	return &GetPricePlanVersionResponse{PricePlanVersion: &pricePlanVersions[0]}, nil
+}
+
+// SchedulePricePlanVersionMigration sets whether subscribers of a superseded version keep its
+// terms or are migrated to a newer version, and when.
+func (s *Service) SchedulePricePlanVersionMigration(ctx context.Context, req *SchedulePricePlanVersionMigrationRequest) (*SchedulePricePlanVersionMigrationResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/pricer")
+
+	if req == nil || strings.TrimSpace(req.PricePlanID) == "" {
+		return nil, ErrPricePlanIDRequired
+	}
+	if strings.TrimSpace(req.UserID) == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+	if req.Policy == "" {
+		req.Policy = PriceGrandfatherPolicyMigrate
+	}
+	if !IsValidPriceGrandfatherPolicy(string(req.Policy)) {
+		return nil, ErrInvalidPricePlanVersionMigration
+	}
+
+	migrateAt, err := normaliseDateParam(req.MigrateAtUtc)
+	if err != nil {
+		return nil, err
+	}
+
+	pricePlan, err := s.PricerRepository.GetPricePlanByID(ctx, req.PricePlanID, &GetPricePlanByIDRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	pricePlanVersion, err := s.PricerRepository.GetPricePlanVersion(ctx, req.PricePlanID, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	if !pricePlanVersion.IsSuperseded() {
+		logger.Warn("attempt-made-to-migrate-current-price-plan-version", zap.String("price-plan-id", req.PricePlanID), zap.Int("version", req.Version))
+		return nil, ErrInvalidPricePlanVersionMigration
+	}
+	if pricePlanVersion.Grandfathering != nil && pricePlanVersion.Grandfathering.MigratedAt != "" {
+		logger.Warn("attempt-made-to-reschedule-migrated-price-plan-version", zap.String("price-plan-version-id", pricePlanVersion.ID))
+		return nil, ErrInvalidPricePlanVersionMigration
+	}
+
+	grandfathering := &PricePlanGrandfathering{
+		Policy:      req.Policy,
+		UpdatedByID: req.UserID,
+	}
+
+	if req.Policy == PriceGrandfatherPolicyMigrate {
+		toVersion := req.ToVersion
+		if toVersion == 0 {
+			toVersion = pricePlan.CurrentVersion
+		}
+		if toVersion <= pricePlanVersion.Version {
+			return nil, ErrInvalidPricePlanVersionMigration
+		}
+
+		targetVersion, err := s.PricerRepository.GetPricePlanVersion(ctx, req.PricePlanID, toVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		grandfathering.MigrateAt = migrateAt
+		grandfathering.MigrateToVersionID = targetVersion.ID
+		grandfathering.MigrateToVersion = targetVersion.Version
+	}
+
+	pricePlanVersion.Grandfathering = grandfathering
+	pricePlanVersion, err = s.PricerRepository.UpdatePricePlanVersion(ctx, pricePlanVersion)
+	if err != nil {
+		logger.Error("failed-to-schedule-price-plan-version-migration", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return nil, err
+	}
+
+	return &SchedulePricePlanVersionMigrationResponse{PricePlanVersion: pricePlanVersion}, nil
+}
+
+// GetDuePricePlanVersionMigrations returns the superseded versions whose subscribers are due
+// to be migrated to a newer version.
+func (s *Service) GetDuePricePlanVersionMigrations(ctx context.Context, req *GetDuePricePlanVersionMigrationsRequest) (*GetDuePricePlanVersionMigrationsResponse, error) {
+	now := time.Now()
+	if req != nil && !req.Now.IsZero() {
+		now = req.Now
+	}
+
+	pricePlanVersions, err := s.PricerRepository.GetDuePricePlanVersionMigrations(ctx, now.UTC().Format(common.RFC3339NanoUTC))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetDuePricePlanVersionMigrationsResponse{PricePlanVersions: pricePlanVersions}, nil
+}
+
+// CompletePricePlanVersionMigration records that a version's subscribers have been migrated.
+func (s *Service) CompletePricePlanVersionMigration(ctx context.Context, req *CompletePricePlanVersionMigrationRequest) (*GetPricePlanVersionResponse, error) {
+	if req == nil || strings.TrimSpace(req.ID) == "" {
+		return nil, ErrPricePlanVersionNotFound
+	}
+
+	pricePlanVersion, err := s.PricerRepository.GetPricePlanVersionByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pricePlanVersion.Grandfathering == nil || pricePlanVersion.Grandfathering.Policy != PriceGrandfatherPolicyMigrate {
+		return nil, ErrInvalidPricePlanVersionMigration
+	}
+
+	pricePlanVersion.Grandfathering.MigratedAt = toolbox.TimeNowUTC()
+	pricePlanVersion.Grandfathering.MigratedCount = req.MigratedCount
+
+	pricePlanVersion, err = s.PricerRepository.UpdatePricePlanVersion(ctx, pricePlanVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPricePlanVersionResponse{PricePlanVersion: pricePlanVersion}, nil
+}
+
+// QuotePricePlan returns a line-itemised quote for one of the plan's costs, including its trial,
+// setup fee, active discounts, the coupon code's discount and tax for the country. A coupon code
+// that matches none of the plan's discounts is looked up as a promotion code.
+func (s *Service) QuotePricePlan(ctx context.Context, req *QuotePricePlanRequest) (*QuotePricePlanResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/pricer", "quote-price-plan")
+	logger.Debug("handling-quote-price-plan-request")
+
+	if req == nil || (strings.TrimSpace(req.PricePlanID) == "" && strings.TrimSpace(req.PricePlanSlug) == "") {
+		return nil, ErrPricePlanIDRequired
+	}
+	if req.Quantity < 0 {
+		return nil, ErrInvalidPriceQuote
+	}
+	if req.Currency != "" && !IsValidPriceCurrency(req.Currency) {
+		return nil, ErrInvalidPriceCurrency
+	}
+	if req.BillingCadence != "" && !IsValidPriceBillingCadence(string(req.BillingCadence)) {
+		return nil, ErrInvalidPriceBillingCadence
+	}
+	if req.Country != "" && !IsValidPriceCountry(req.Country) {
+		return nil, ErrInvalidPriceQuote
+	}
+
+	var pricePlan *PricePlan
+	var err error
+	if strings.TrimSpace(req.PricePlanID) != "" {
+		pricePlan, err = s.PricerRepository.GetPricePlanByID(ctx, req.PricePlanID, &GetPricePlanByIDRequest{ID: req.PricePlanID, IncludeCosts: true, IncludeProviders: true})
+	} else {
+		slug := NormalisePriceSlug(req.PricePlanSlug)
+		pricePlan, err = s.PricerRepository.GetPricePlanBySlug(ctx, slug, &GetPricePlanBySlugRequest{Slug: slug, IncludeCosts: true, IncludeProviders: true})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	if req.PublishedOnly && !isPricePlanQuotable(pricePlan, at) {
+		logger.Debug("price-plan-not-published-for-quote", zap.String("price-plan-id", pricePlan.ID))
+		return nil, ErrPricePlanNotFound
+	}
+
+	cost := pricePlan.FindPriceCost(req.CostID, req.Currency, req.BillingCadence)
+	if cost == nil {
+		return nil, ErrPriceCostNotFound
+	}
+
+	input := &PriceQuoteInput{
+		Quantity:   req.Quantity,
+		Country:    req.Country,
+		CouponCode: req.CouponCode,
+		At:         at,
+	}
+
+	couponDiscount := pricePlan.FindPriceDiscountByCode(req.CouponCode)
+	if couponDiscount == nil && strings.TrimSpace(req.CouponCode) != "" {
+		couponDiscount, err = s.getQuotePromotionCodeDiscount(ctx, req, pricePlan.ID, cost.BillingCadence, at)
+		if err != nil {
+			logger.Error("failed-to-get-promotion-code-for-quote", zap.String("price-plan-id", pricePlan.ID), zap.Error(err))
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(req.CouponCode) != "" && (couponDiscount == nil || !couponDiscount.IsActiveAt(at)) {
+		logger.Debug("coupon-code-cannot-be-used", zap.String("price-plan-id", pricePlan.ID))
+		return nil, ErrInvalidPriceCoupon
+	}
+	for i := range pricePlan.Discounts {
+		if couponDiscount != nil && &pricePlan.Discounts[i] == couponDiscount {
+			continue
+		}
+		input.Discounts = append(input.Discounts, pricePlan.Discounts[i])
+	}
+	input.CouponDiscount = couponDiscount
+
+	if s.TaxRules != nil && req.Country != "" {
+		input.Tax, err = s.TaxRules.GetPriceTaxRate(ctx, NormaliseCountry(req.Country), cost.Currency)
+		if err != nil {
+			logger.Error("failed-to-get-price-tax-rate", zap.String("country", req.Country), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	quote, err := CalculatePriceQuote(pricePlan, cost, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotePricePlanResponse{Quote: quote}, nil
+}
+
+// isPricePlanQuotable returns true if the plan is published, not deleted and its publish
+// date has passed.
+func isPricePlanQuotable(pricePlan *PricePlan, at time.Time) bool {
+	if pricePlan.Status != PricePlanStatusPublished || pricePlan.DeletedAt != "" {
+		return false
+	}
+
+	publishedAt, err := parseOptionalPriceDate(pricePlan.PublishedAt)
+	if err != nil || publishedAt.IsZero() {
+		return false
+	}
+
+	return !publishedAt.After(at)
+}
+
+var validPricePlanVersionSortOrders = map[string]struct{}{
+	"version_asc":       {},
+	"version_desc":      {},
+	"published_at_asc":  {},
+	"published_at_desc": {},
+}
+
 // validatePricePlanCanPublish validates that a price plan is ready for publishing.
 // It checks that the plan is not nil, has at least one cost, all costs are valid,
 // and has at least one valid provider reference at the plan or cost level.
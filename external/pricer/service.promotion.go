@@ -0,0 +1,546 @@
+package pricer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.uber.org/zap"
+)
+
+// redeemPromotionCodeAttemptsLimit is how many times a redemption is retried when a
+// concurrent redemption records it first.
+const redeemPromotionCodeAttemptsLimit = 3
+
+// CreatePromotionCode creates a promotion code. Codes are unique, including against deleted codes.
+func (s *Service) CreatePromotionCode(ctx context.Context, req *CreatePromotionCodeRequest) (*CreatePromotionCodeResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/pricer", "create-promotion-code")
+	logger.Debug("handling-create-promotion-code-request")
+
+	if req == nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+	if strings.TrimSpace(req.UserID) == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+
+	expiresAt, err := normaliseDateParam(req.ExpiresAtUtc)
+	if err != nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+
+	promotionCode := &PricePromotionCode{
+		Code:                    NormalisePromotionCode(req.Code),
+		Description:             strings.TrimSpace(req.Description),
+		Discount:                req.Discount,
+		Active:                  req.Active == nil || *req.Active,
+		MaxRedemptions:          req.MaxRedemptions,
+		MaxRedemptionsPerUser:   req.MaxRedemptionsPerUser,
+		FirstTimeCustomersOnly:  req.FirstTimeCustomersOnly,
+		EligiblePricePlanIDs:    req.EligiblePricePlanIDs,
+		EligibleBillingCadences: req.EligibleBillingCadences,
+		ExpiresAt:               expiresAt,
+		ProviderRefs:            req.ProviderRefs,
+		Metadata:                req.Metadata,
+		CreatedByID:             req.UserID,
+		UpdatedByID:             req.UserID,
+	}
+
+	if err := promotionCode.Validate(); err != nil {
+		logger.Warn("attempt-made-to-create-invalid-promotion-code", zap.String("code", promotionCode.Code), zap.Error(err))
+		return nil, err
+	}
+
+	_, err = s.PricerRepository.GetPromotionCodeByCode(ctx, promotionCode.Code)
+	if err == nil {
+		logger.Warn("attempt-made-to-create-duplicate-promotion-code", zap.String("code", promotionCode.Code))
+		return nil, ErrDuplicatePricePromotionCode
+	}
+	if !errors.Is(err, ErrPricePromotionCodeNotFound) {
+		logger.Error("failed-to-check-for-duplicate-promotion-code", zap.String("code", promotionCode.Code), zap.Error(err))
+		return nil, err
+	}
+
+	createdPromotionCode, err := s.PricerRepository.CreatePromotionCode(ctx, promotionCode)
+	if err != nil {
+		logger.Error("failed-to-create-promotion-code", zap.String("code", promotionCode.Code), zap.Error(err))
+		return &CreatePromotionCodeResponse{}, err
+	}
+
+	return &CreatePromotionCodeResponse{PromotionCode: createdPromotionCode}, nil
+}
+
+// UpdatePromotionCode updates a promotion code's discount, limits and eligibility.
+func (s *Service) UpdatePromotionCode(ctx context.Context, req *UpdatePromotionCodeRequest) (*UpdatePromotionCodeResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/pricer", "update-promotion-code")
+	logger.Debug("handling-update-promotion-code-request")
+
+	if req == nil {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+	if strings.TrimSpace(req.UserID) == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+	if strings.TrimSpace(req.ID) == "" {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+
+	promotionCode, err := s.PricerRepository.GetPromotionCodeByID(ctx, req.ID)
+	if err != nil {
+		logger.Warn("attempt-made-to-update-missing-promotion-code", zap.String("promotion-code-id", req.ID), zap.Error(err))
+		return nil, err
+	}
+	if promotionCode.DeletedAt != "" {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+
+	if req.Description != nil {
+		promotionCode.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Discount != nil {
+		promotionCode.Discount = *req.Discount
+	}
+	if req.Active != nil {
+		promotionCode.Active = *req.Active
+	}
+	if req.MaxRedemptions != nil {
+		promotionCode.MaxRedemptions = *req.MaxRedemptions
+	}
+	if req.MaxRedemptionsPerUser != nil {
+		promotionCode.MaxRedemptionsPerUser = *req.MaxRedemptionsPerUser
+	}
+	if req.FirstTimeCustomersOnly != nil {
+		promotionCode.FirstTimeCustomersOnly = *req.FirstTimeCustomersOnly
+	}
+	if req.EligiblePricePlanIDs != nil {
+		promotionCode.EligiblePricePlanIDs = req.EligiblePricePlanIDs
+	}
+	if req.EligibleBillingCadences != nil {
+		promotionCode.EligibleBillingCadences = req.EligibleBillingCadences
+	}
+	if req.ExpiresAtUtc != nil {
+		promotionCode.ExpiresAt, err = normaliseDateParam(*req.ExpiresAtUtc)
+		if err != nil {
+			return nil, ErrInvalidPricePromotionCodePayload
+		}
+	}
+	if req.ProviderRefs != nil {
+		promotionCode.ProviderRefs = req.ProviderRefs
+	}
+	if req.Metadata != nil {
+		promotionCode.Metadata = req.Metadata
+	}
+	promotionCode.UpdatedByID = req.UserID
+
+	if err := promotionCode.Validate(); err != nil {
+		logger.Warn("attempt-made-to-update-invalid-promotion-code", zap.String("promotion-code-id", req.ID), zap.Error(err))
+		return nil, err
+	}
+
+	updatedPromotionCode, err := s.PricerRepository.UpdatePromotionCode(ctx, promotionCode)
+	if err != nil {
+		logger.Error("failed-to-update-promotion-code", zap.String("promotion-code-id", req.ID), zap.Error(err))
+		return &UpdatePromotionCodeResponse{}, err
+	}
+
+	return &UpdatePromotionCodeResponse{PromotionCode: updatedPromotionCode}, nil
+}
+
+// GetPromotionCodeByID returns a promotion code.
+func (s *Service) GetPromotionCodeByID(ctx context.Context, req *GetPromotionCodeByIDRequest) (*GetPromotionCodeByIDResponse, error) {
+	if req == nil || strings.TrimSpace(req.ID) == "" {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+
+	promotionCode, err := s.PricerRepository.GetPromotionCodeByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPromotionCodeByIDResponse{PromotionCode: promotionCode}, nil
+}
+
+// GetPromotionCodes returns promotion codes.
+func (s *Service) GetPromotionCodes(ctx context.Context, req *GetPromotionCodesRequest) (*GetPromotionCodesResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/pricer")
+
+	if req == nil {
+		req = &GetPromotionCodesRequest{}
+	}
+	if req.Order == "" {
+		req.Order = "created_at_desc"
+	}
+	if req.PerPage == 0 {
+		req.PerPage = 25
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if !isValidPriceSortOrder(req.Order) {
+		return nil, ErrInvalidPriceQueryParam
+	}
+
+	total, err := s.PricerRepository.GetTotalPromotionCodes(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-promotion-codes-total", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetPromotionCodesResponse{}, err
+	}
+	req.TotalCount = int(total)
+
+	promotionCodes, err := s.PricerRepository.GetPromotionCodes(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-promotion-codes", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetPromotionCodesResponse{}, err
+	}
+
+	paginatedResponse, err := toolbox.Paginate(ctx, &toolbox.PaginationRequest{
+		PerPage: req.PerPage,
+		Page:    req.Page,
+	}, promotionCodes, req.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPromotionCodesResponse{
+		Total:          paginatedResponse.Total,
+		TotalPages:     paginatedResponse.TotalPages,
+		PromotionCodes: paginatedResponse.Resources,
+		Page:           paginatedResponse.Page,
+		PerPage:        paginatedResponse.ResourcePerPage,
+	}, nil
+}
+
+// DeletePromotionCode soft-deletes a promotion code, so it can no longer be redeemed. Its
+// redemptions are kept.
+func (s *Service) DeletePromotionCode(ctx context.Context, req *DeletePromotionCodeRequest) (*DeletePromotionCodeResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/pricer", "delete-promotion-code")
+	logger.Debug("handling-delete-promotion-code-request")
+
+	if req == nil || strings.TrimSpace(req.ID) == "" {
+		return nil, ErrPricePromotionCodeNotFound
+	}
+	if strings.TrimSpace(req.UserID) == "" {
+		return nil, ErrPriceUserIDRequired
+	}
+
+	if _, err := s.PricerRepository.GetPromotionCodeByID(ctx, req.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.PricerRepository.SoftDeletePromotionCode(ctx, req.ID, req.UserID, toolbox.TimeNowUTC()); err != nil {
+		return &DeletePromotionCodeResponse{}, err
+	}
+
+	promotionCode, err := s.PricerRepository.GetPromotionCodeByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeletePromotionCodeResponse{PromotionCode: promotionCode}, nil
+}
+
+// ValidatePromotionCode checks whether a promotion code can be redeemed for the plan, cadence
+// and user. A code that cannot be redeemed is not an error; the response gives the reason.
+func (s *Service) ValidatePromotionCode(ctx context.Context, req *ValidatePromotionCodeRequest) (*ValidatePromotionCodeResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/pricer", "validate-promotion-code")
+	logger.Debug("handling-validate-promotion-code-request")
+
+	if req == nil || strings.TrimSpace(req.Code) == "" {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+	if req.BillingCadence != "" && !IsValidPriceBillingCadence(string(req.BillingCadence)) {
+		return nil, ErrInvalidPriceBillingCadence
+	}
+
+	response := &ValidatePromotionCodeResponse{Code: NormalisePromotionCode(req.Code)}
+
+	promotionCode, err := s.PricerRepository.GetPromotionCodeByCode(ctx, response.Code)
+	if err != nil {
+		if errors.Is(err, ErrPricePromotionCodeNotFound) {
+			response.Reason = PricePromotionCodeReasonNotFound
+			return response, nil
+		}
+		logger.Error("failed-to-get-promotion-code", zap.Error(err))
+		return nil, err
+	}
+
+	pricePlanID := strings.TrimSpace(req.PricePlanID)
+	if pricePlanID == "" && strings.TrimSpace(req.PricePlanSlug) != "" {
+		slug := NormalisePriceSlug(req.PricePlanSlug)
+		pricePlan, err := s.PricerRepository.GetPricePlanBySlug(ctx, slug, &GetPricePlanBySlugRequest{Slug: slug})
+		if err != nil {
+			return nil, err
+		}
+		pricePlanID = pricePlan.ID
+	}
+
+	check, err := s.newPricePromotionCodeCheck(ctx, promotionCode, req.UserID, "", pricePlanID, req.BillingCadence, req.At)
+	if err != nil {
+		logger.Error("failed-to-check-promotion-code", zap.String("promotion-code-id", promotionCode.ID), zap.Error(err))
+		return nil, err
+	}
+
+	if reason := promotionCode.UnredeemableReason(check); reason != "" {
+		logger.Debug("promotion-code-cannot-be-redeemed", zap.String("promotion-code-id", promotionCode.ID), zap.String("reason", string(reason)))
+		response.Reason = reason
+		return response, nil
+	}
+
+	response.Valid = true
+	response.Discount = &PriceDiscount{
+		Label:      promotionCode.Discount.Label,
+		Type:       promotionCode.Discount.Type,
+		Amount:     promotionCode.Discount.Amount,
+		Currency:   promotionCode.Discount.Currency,
+		PercentBps: promotionCode.Discount.PercentBps,
+		StartsAt:   promotionCode.Discount.StartsAt,
+		EndsAt:     promotionCode.Discount.EndsAt,
+	}
+	response.PromotionCode = promotionCode
+
+	return response, nil
+}
+
+// RedeemPromotionCode records a promotion code being redeemed for a subscription, counting it
+// against the code's limits. Redeeming a code again for the same subscription returns the
+// existing redemption, so retried webhooks are only counted once.
+//
+// The redemption is recorded before the code's count is incremented, so the unique indexes
+// reject a second redemption for the same subscription or user slot. If the increment then
+// fails, the redemption is removed again.
+func (s *Service) RedeemPromotionCode(ctx context.Context, req *RedeemPromotionCodeRequest) (*RedeemPromotionCodeResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/pricer", "redeem-promotion-code")
+	logger.Debug("handling-redeem-promotion-code-request")
+
+	if req == nil || strings.TrimSpace(req.Code) == "" || strings.TrimSpace(req.SubscriptionID) == "" {
+		return nil, ErrInvalidPricePromotionCodePayload
+	}
+
+	promotionCode, err := s.PricerRepository.GetPromotionCodeByCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; attempt <= redeemPromotionCodeAttemptsLimit; attempt++ {
+		existingRedemptions, err := s.PricerRepository.GetPromotionCodeRedemptions(ctx, &GetPromotionCodeRedemptionsRequest{
+			PromotionCodeID: promotionCode.ID,
+			SubscriptionID:  req.SubscriptionID,
+			PerPage:         1,
+			Page:            1,
+		})
+		if err != nil {
+			logger.Error("failed-to-get-existing-promotion-code-redemption", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID), zap.Error(err))
+			return nil, err
+		}
+		if len(existingRedemptions) > 0 {
+			return &RedeemPromotionCodeResponse{Redemption: &existingRedemptions[0]}, nil
+		}
+
+		check, err := s.newPricePromotionCodeCheck(ctx, promotionCode, req.UserID, req.SubscriptionID, req.PricePlanID, req.BillingCadence, time.Time{})
+		if err != nil {
+			logger.Error("failed-to-check-promotion-code", zap.String("promotion-code-id", promotionCode.ID), zap.Error(err))
+			return nil, err
+		}
+
+		if reason := promotionCode.UnredeemableReason(check); reason != "" {
+			logger.Warn("attempt-made-to-redeem-unredeemable-promotion-code", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID), zap.String("reason", string(reason)))
+			return nil, ErrPricePromotionCodeNotRedeemable
+		}
+
+		userSlot, err := s.nextPromotionCodeUserSlot(ctx, promotionCode, req.UserID)
+		if err != nil {
+			logger.Error("failed-to-get-promotion-code-user-slot", zap.String("promotion-code-id", promotionCode.ID), zap.Error(err))
+			return nil, err
+		}
+		if userSlot < 0 {
+			logger.Warn("promotion-code-user-redemption-limit-reached", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID))
+			return nil, ErrPricePromotionCodeNotRedeemable
+		}
+
+		redemption, err := s.PricerRepository.CreatePromotionCodeRedemption(ctx, &PricePromotionCodeRedemption{
+			PromotionCodeID: promotionCode.ID,
+			Code:            promotionCode.Code,
+			UserID:          req.UserID,
+			SubscriptionID:  req.SubscriptionID,
+			PricePlanID:     req.PricePlanID,
+			BillingCadence:  req.BillingCadence,
+			UserSlot:        userSlot,
+		})
+		if errors.Is(err, ErrDuplicatePricePromotionCodeRedemption) {
+			// Another request redeemed the code for this subscription or took the user's slot
+			// first, so check again against what it recorded
+			logger.Debug("promotion-code-redemption-recorded-concurrently", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID), zap.Int("attempt", attempt))
+			continue
+		}
+		if err != nil {
+			logger.Error("failed-to-create-promotion-code-redemption", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID), zap.Error(err))
+			return nil, err
+		}
+
+		incremented, err := s.PricerRepository.IncrementPromotionCodeRedemptions(ctx, promotionCode.ID)
+		if err != nil || !incremented {
+			if deleteErr := s.PricerRepository.DeletePromotionCodeRedemption(ctx, redemption.ID); deleteErr != nil {
+				logger.Error("failed-to-remove-uncounted-promotion-code-redemption", zap.String("promotion-code-id", promotionCode.ID), zap.String("redemption-id", redemption.ID), zap.Error(deleteErr))
+			}
+		}
+		if err != nil {
+			logger.Error("failed-to-increment-promotion-code-redemptions", zap.String("promotion-code-id", promotionCode.ID), zap.Error(err))
+			return nil, err
+		}
+		if !incremented {
+			logger.Warn("promotion-code-redemption-limit-reached", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID))
+			return nil, ErrPricePromotionCodeNotRedeemable
+		}
+
+		logger.Info("promotion-code-redeemed", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID))
+
+		return &RedeemPromotionCodeResponse{Redemption: redemption}, nil
+	}
+
+	logger.Warn("failed-to-redeem-contended-promotion-code", zap.String("promotion-code-id", promotionCode.ID), zap.String("subscription-id", req.SubscriptionID))
+	return nil, ErrPricePromotionCodeNotRedeemable
+}
+
+// nextPromotionCodeUserSlot returns the lowest of the user's redemption slots that is not
+// taken yet, or -1 when all of them are. It returns zero when the code does not limit
+// redemptions per user, as there is no slot to take.
+func (s *Service) nextPromotionCodeUserSlot(ctx context.Context, promotionCode *PricePromotionCode, userID string) (int64, error) {
+	if promotionCode.MaxRedemptionsPerUser <= 0 || strings.TrimSpace(userID) == "" {
+		return 0, nil
+	}
+
+	userRedemptions, err := s.PricerRepository.GetPromotionCodeRedemptions(ctx, &GetPromotionCodeRedemptionsRequest{
+		PromotionCodeID: promotionCode.ID,
+		UserID:          userID,
+		PerPage:         int(promotionCode.MaxRedemptionsPerUser),
+		Page:            1,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	takenSlots := make(map[int64]bool, len(userRedemptions))
+	for _, redemption := range userRedemptions {
+		takenSlots[redemption.UserSlot] = true
+	}
+
+	for slot := int64(1); slot <= promotionCode.MaxRedemptionsPerUser; slot++ {
+		if !takenSlots[slot] {
+			return slot, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// GetPromotionCodeRedemptions returns promotion code redemptions.
+func (s *Service) GetPromotionCodeRedemptions(ctx context.Context, req *GetPromotionCodeRedemptionsRequest) (*GetPromotionCodeRedemptionsResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/pricer")
+
+	if req == nil {
+		req = &GetPromotionCodeRedemptionsRequest{}
+	}
+	if req.Order == "" {
+		req.Order = "redeemed_at_desc"
+	}
+	if req.PerPage == 0 {
+		req.PerPage = 25
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Order != "redeemed_at_asc" && req.Order != "redeemed_at_desc" {
+		return nil, ErrInvalidPriceQueryParam
+	}
+
+	total, err := s.PricerRepository.GetTotalPromotionCodeRedemptions(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-promotion-code-redemptions-total", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetPromotionCodeRedemptionsResponse{}, err
+	}
+	req.TotalCount = int(total)
+
+	redemptions, err := s.PricerRepository.GetPromotionCodeRedemptions(ctx, req)
+	if err != nil {
+		logger.Error("failed-to-get-promotion-code-redemptions", zap.Any("request", safeLogValue(req)), zap.Error(err))
+		return &GetPromotionCodeRedemptionsResponse{}, err
+	}
+
+	paginatedResponse, err := toolbox.Paginate(ctx, &toolbox.PaginationRequest{
+		PerPage: req.PerPage,
+		Page:    req.Page,
+	}, redemptions, req.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPromotionCodeRedemptionsResponse{
+		Total:       paginatedResponse.Total,
+		TotalPages:  paginatedResponse.TotalPages,
+		Redemptions: paginatedResponse.Resources,
+		Page:        paginatedResponse.Page,
+		PerPage:     paginatedResponse.ResourcePerPage,
+	}, nil
+}
+
+// getQuotePromotionCodeDiscount returns the discount of the promotion code a quote's coupon
+// code matches, or nil when no promotion code matches or it cannot be redeemed for the quote.
+func (s *Service) getQuotePromotionCodeDiscount(ctx context.Context, req *QuotePricePlanRequest, pricePlanID string, cadence PriceBillingCadence, at time.Time) (*PriceDiscount, error) {
+	promotionCode, err := s.PricerRepository.GetPromotionCodeByCode(ctx, req.CouponCode)
+	if err != nil {
+		if errors.Is(err, ErrPricePromotionCodeNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	check, err := s.newPricePromotionCodeCheck(ctx, promotionCode, req.UserID, "", pricePlanID, cadence, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason := promotionCode.UnredeemableReason(check); reason != "" {
+		logger.AcquirePackageFrom(ctx, "external/pricer").Debug("promotion-code-cannot-be-quoted", zap.String("promotion-code-id", promotionCode.ID), zap.String("reason", string(reason)))
+		return nil, nil
+	}
+
+	return promotionCode.AsPriceDiscount(), nil
+}
+
+// newPricePromotionCodeCheck gathers the user's redemptions and subscription history needed to
+// check the promotion code. Without a user, the per-user and first-time customer checks are skipped.
+func (s *Service) newPricePromotionCodeCheck(ctx context.Context, promotionCode *PricePromotionCode, userID string, excludeSubscriptionID string, pricePlanID string, cadence PriceBillingCadence, at time.Time) (*PricePromotionCodeCheck, error) {
+	check := &PricePromotionCodeCheck{
+		PricePlanID:    pricePlanID,
+		BillingCadence: cadence,
+		At:             at,
+	}
+
+	if strings.TrimSpace(userID) == "" {
+		return check, nil
+	}
+
+	if promotionCode.MaxRedemptionsPerUser > 0 {
+		userRedemptions, err := s.PricerRepository.GetTotalPromotionCodeRedemptions(ctx, &GetPromotionCodeRedemptionsRequest{
+			PromotionCodeID: promotionCode.ID,
+			UserID:          userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		check.UserRedemptions = userRedemptions
+	}
+
+	if promotionCode.FirstTimeCustomersOnly && s.CustomerHistory != nil {
+		isReturningCustomer, err := s.CustomerHistory.HasPreviousSubscription(ctx, userID, excludeSubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		check.IsReturningCustomer = isReturningCustomer
+	}
+
+	return check, nil
+}
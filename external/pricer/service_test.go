@@ -41,6 +41,28 @@ type mockPricerRepository struct {
 	getFeaturesFunc         func(ctx context.Context, req *pricer.GetFeaturesRequest) ([]pricer.PriceFeature, error)
 	getTotalFeaturesFunc    func(ctx context.Context, req *pricer.GetFeaturesRequest) (int64, error)
 	softDeleteFeatureFunc   func(ctx context.Context, id, deletedByID, deletedAt string) error
+
+	createPricePlanVersionFunc           func(ctx context.Context, v *pricer.PricePlanVersion) (*pricer.PricePlanVersion, error)
+	updatePricePlanVersionFunc           func(ctx context.Context, v *pricer.PricePlanVersion) (*pricer.PricePlanVersion, error)
+	getPricePlanVersionByIDFunc          func(ctx context.Context, id string) (*pricer.PricePlanVersion, error)
+	getPricePlanVersionFunc              func(ctx context.Context, pricePlanID string, version int) (*pricer.PricePlanVersion, error)
+	getPricePlanVersionsFunc             func(ctx context.Context, req *pricer.GetPricePlanVersionsRequest) ([]pricer.PricePlanVersion, error)
+	getTotalPricePlanVersionsFunc        func(ctx context.Context, req *pricer.GetPricePlanVersionsRequest) (int64, error)
+	getDuePricePlanVersionMigrationsFunc func(ctx context.Context, now string) ([]pricer.PricePlanVersion, error)
+	setPricePlanCurrentVersionFunc       func(ctx context.Context, id, versionID string, version int) error
+
+	createPromotionCodeFunc               func(ctx context.Context, pc *pricer.PricePromotionCode) (*pricer.PricePromotionCode, error)
+	updatePromotionCodeFunc               func(ctx context.Context, pc *pricer.PricePromotionCode) (*pricer.PricePromotionCode, error)
+	getPromotionCodeByIDFunc              func(ctx context.Context, id string) (*pricer.PricePromotionCode, error)
+	getPromotionCodeByCodeFunc            func(ctx context.Context, code string) (*pricer.PricePromotionCode, error)
+	getPromotionCodesFunc                 func(ctx context.Context, req *pricer.GetPromotionCodesRequest) ([]pricer.PricePromotionCode, error)
+	getTotalPromotionCodesFunc            func(ctx context.Context, req *pricer.GetPromotionCodesRequest) (int64, error)
+	softDeletePromotionCodeFunc           func(ctx context.Context, id, deletedByID, deletedAt string) error
+	incrementPromotionCodeRedemptionsFunc func(ctx context.Context, id string) (bool, error)
+	createPromotionCodeRedemptionFunc     func(ctx context.Context, r *pricer.PricePromotionCodeRedemption) (*pricer.PricePromotionCodeRedemption, error)
+	deletePromotionCodeRedemptionFunc     func(ctx context.Context, id string) error
+	getPromotionCodeRedemptionsFunc       func(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) ([]pricer.PricePromotionCodeRedemption, error)
+	getTotalPromotionCodeRedemptionsFunc  func(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) (int64, error)
 }
 
 func (m *mockPricerRepository) CreatePricePlan(ctx context.Context, pp *pricer.PricePlan) (*pricer.PricePlan, error) {
@@ -148,6 +170,146 @@ func (m *mockPricerRepository) SoftDeleteFeature(ctx context.Context, id, delete
 	return nil
 }
 
+func (m *mockPricerRepository) CreatePricePlanVersion(ctx context.Context, v *pricer.PricePlanVersion) (*pricer.PricePlanVersion, error) {
+	if m.createPricePlanVersionFunc != nil {
+		return m.createPricePlanVersionFunc(ctx, v)
+	}
+	return v, nil
+}
+
+func (m *mockPricerRepository) UpdatePricePlanVersion(ctx context.Context, v *pricer.PricePlanVersion) (*pricer.PricePlanVersion, error) {
+	if m.updatePricePlanVersionFunc != nil {
+		return m.updatePricePlanVersionFunc(ctx, v)
+	}
+	return v, nil
+}
+
+func (m *mockPricerRepository) GetPricePlanVersionByID(ctx context.Context, id string) (*pricer.PricePlanVersion, error) {
+	if m.getPricePlanVersionByIDFunc != nil {
+		return m.getPricePlanVersionByIDFunc(ctx, id)
+	}
+	return nil, pricer.ErrPricePlanVersionNotFound
+}
+
+func (m *mockPricerRepository) GetPricePlanVersion(ctx context.Context, pricePlanID string, version int) (*pricer.PricePlanVersion, error) {
+	if m.getPricePlanVersionFunc != nil {
+		return m.getPricePlanVersionFunc(ctx, pricePlanID, version)
+	}
+	return nil, pricer.ErrPricePlanVersionNotFound
+}
+
+func (m *mockPricerRepository) GetPricePlanVersions(ctx context.Context, req *pricer.GetPricePlanVersionsRequest) ([]pricer.PricePlanVersion, error) {
+	if m.getPricePlanVersionsFunc != nil {
+		return m.getPricePlanVersionsFunc(ctx, req)
+	}
+	return []pricer.PricePlanVersion{}, nil
+}
+
+func (m *mockPricerRepository) GetTotalPricePlanVersions(ctx context.Context, req *pricer.GetPricePlanVersionsRequest) (int64, error) {
+	if m.getTotalPricePlanVersionsFunc != nil {
+		return m.getTotalPricePlanVersionsFunc(ctx, req)
+	}
+	return 0, nil
+}
+
+func (m *mockPricerRepository) GetDuePricePlanVersionMigrations(ctx context.Context, now string) ([]pricer.PricePlanVersion, error) {
+	if m.getDuePricePlanVersionMigrationsFunc != nil {
+		return m.getDuePricePlanVersionMigrationsFunc(ctx, now)
+	}
+	return []pricer.PricePlanVersion{}, nil
+}
+
+func (m *mockPricerRepository) SetPricePlanCurrentVersion(ctx context.Context, id, versionID string, version int) error {
+	if m.setPricePlanCurrentVersionFunc != nil {
+		return m.setPricePlanCurrentVersionFunc(ctx, id, versionID, version)
+	}
+	return nil
+}
+
+func (m *mockPricerRepository) CreatePromotionCode(ctx context.Context, pc *pricer.PricePromotionCode) (*pricer.PricePromotionCode, error) {
+	if m.createPromotionCodeFunc != nil {
+		return m.createPromotionCodeFunc(ctx, pc)
+	}
+	return pc, nil
+}
+
+func (m *mockPricerRepository) UpdatePromotionCode(ctx context.Context, pc *pricer.PricePromotionCode) (*pricer.PricePromotionCode, error) {
+	if m.updatePromotionCodeFunc != nil {
+		return m.updatePromotionCodeFunc(ctx, pc)
+	}
+	return pc, nil
+}
+
+func (m *mockPricerRepository) GetPromotionCodeByID(ctx context.Context, id string) (*pricer.PricePromotionCode, error) {
+	if m.getPromotionCodeByIDFunc != nil {
+		return m.getPromotionCodeByIDFunc(ctx, id)
+	}
+	return nil, pricer.ErrPricePromotionCodeNotFound
+}
+
+func (m *mockPricerRepository) GetPromotionCodeByCode(ctx context.Context, code string) (*pricer.PricePromotionCode, error) {
+	if m.getPromotionCodeByCodeFunc != nil {
+		return m.getPromotionCodeByCodeFunc(ctx, code)
+	}
+	return nil, pricer.ErrPricePromotionCodeNotFound
+}
+
+func (m *mockPricerRepository) GetPromotionCodes(ctx context.Context, req *pricer.GetPromotionCodesRequest) ([]pricer.PricePromotionCode, error) {
+	if m.getPromotionCodesFunc != nil {
+		return m.getPromotionCodesFunc(ctx, req)
+	}
+	return []pricer.PricePromotionCode{}, nil
+}
+
+func (m *mockPricerRepository) GetTotalPromotionCodes(ctx context.Context, req *pricer.GetPromotionCodesRequest) (int64, error) {
+	if m.getTotalPromotionCodesFunc != nil {
+		return m.getTotalPromotionCodesFunc(ctx, req)
+	}
+	return 0, nil
+}
+
+func (m *mockPricerRepository) SoftDeletePromotionCode(ctx context.Context, id, deletedByID, deletedAt string) error {
+	if m.softDeletePromotionCodeFunc != nil {
+		return m.softDeletePromotionCodeFunc(ctx, id, deletedByID, deletedAt)
+	}
+	return nil
+}
+
+func (m *mockPricerRepository) IncrementPromotionCodeRedemptions(ctx context.Context, id string) (bool, error) {
+	if m.incrementPromotionCodeRedemptionsFunc != nil {
+		return m.incrementPromotionCodeRedemptionsFunc(ctx, id)
+	}
+	return true, nil
+}
+
+func (m *mockPricerRepository) CreatePromotionCodeRedemption(ctx context.Context, r *pricer.PricePromotionCodeRedemption) (*pricer.PricePromotionCodeRedemption, error) {
+	if m.createPromotionCodeRedemptionFunc != nil {
+		return m.createPromotionCodeRedemptionFunc(ctx, r)
+	}
+	return r, nil
+}
+
+func (m *mockPricerRepository) DeletePromotionCodeRedemption(ctx context.Context, id string) error {
+	if m.deletePromotionCodeRedemptionFunc != nil {
+		return m.deletePromotionCodeRedemptionFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockPricerRepository) GetPromotionCodeRedemptions(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) ([]pricer.PricePromotionCodeRedemption, error) {
+	if m.getPromotionCodeRedemptionsFunc != nil {
+		return m.getPromotionCodeRedemptionsFunc(ctx, req)
+	}
+	return []pricer.PricePromotionCodeRedemption{}, nil
+}
+
+func (m *mockPricerRepository) GetTotalPromotionCodeRedemptions(ctx context.Context, req *pricer.GetPromotionCodeRedemptionsRequest) (int64, error) {
+	if m.getTotalPromotionCodeRedemptionsFunc != nil {
+		return m.getTotalPromotionCodeRedemptionsFunc(ctx, req)
+	}
+	return 0, nil
+}
+
 func newTestService(repo pricer.PricerRepository) *pricer.Service {
 	return pricer.NewService(repo)
 }
@@ -0,0 +1,158 @@
+package pricer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/pricer"
+)
+
+const (
+	testVersionOneID = "test-version-id-001"
+	testVersionTwoID = "test-version-id-002"
+)
+
+func TestService_PublishPricePlan_CreatesVersionAndGrandfathersPrevious(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := makeValidPlan()
+	existingPlan.Status = pricer.PricePlanStatusPublished
+	existingPlan.CurrentVersion = 1
+	existingPlan.CurrentVersionID = testVersionOneID
+
+	previousVersion := pricer.NewPricePlanVersion(existingPlan, 1, "2026-01-01T00:00:00", testUserID)
+	previousVersion.ID = testVersionOneID
+
+	var createdVersion, supersededVersion *pricer.PricePlanVersion
+	var currentVersionID string
+	repo := &mockPricerRepository{
+		getPricePlanByIDFunc: func(ctx context.Context, id string, req *pricer.GetPricePlanByIDRequest) (*pricer.PricePlan, error) {
+			return existingPlan, nil
+		},
+		getPricePlanVersionByIDFunc: func(ctx context.Context, id string) (*pricer.PricePlanVersion, error) {
+			return previousVersion, nil
+		},
+		createPricePlanVersionFunc: func(ctx context.Context, v *pricer.PricePlanVersion) (*pricer.PricePlanVersion, error) {
+			v.ID = testVersionTwoID
+			createdVersion = v
+			return v, nil
+		},
+		updatePricePlanVersionFunc: func(ctx context.Context, v *pricer.PricePlanVersion) (*pricer.PricePlanVersion, error) {
+			supersededVersion = v
+			return v, nil
+		},
+		setPricePlanCurrentVersionFunc: func(ctx context.Context, id, versionID string, version int) error {
+			currentVersionID = versionID
+			return nil
+		},
+	}
+
+	svc := newTestService(repo)
+	_, err := svc.PublishPricePlan(context.Background(), &pricer.PublishPricePlanRequest{
+		ID:                testPlanID,
+		UserID:            testUserID,
+		GrandfatherPolicy: pricer.PriceGrandfatherPolicyMigrate,
+		MigrateAtUtc:      "2026-03-01",
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, createdVersion)
+	assert.Equal(t, 2, createdVersion.Version)
+	assert.Equal(t, testPlanID, createdVersion.PricePlanID)
+	assert.Equal(t, existingPlan.Costs, createdVersion.Costs)
+	assert.Equal(t, testVersionTwoID, currentVersionID)
+
+	require.NotNil(t, supersededVersion)
+	assert.True(t, supersededVersion.IsSuperseded())
+	assert.Equal(t, testVersionTwoID, supersededVersion.SupersededByVersionID)
+	require.NotNil(t, supersededVersion.Grandfathering)
+	assert.Equal(t, pricer.PriceGrandfatherPolicyMigrate, supersededVersion.Grandfathering.Policy)
+	assert.Equal(t, testVersionTwoID, supersededVersion.Grandfathering.MigrateToVersionID)
+	assert.Contains(t, supersededVersion.Grandfathering.MigrateAt, "2026-03-01")
+}
+
+func TestService_PublishPricePlan_VersionIsNotChangedByLaterEdits(t *testing.T) {
+	t.Parallel()
+
+	plan := makeValidPlan()
+	version := pricer.NewPricePlanVersion(plan, 1, "", testUserID)
+
+	plan.Costs[0].Amount = 2000
+
+	assert.Equal(t, int64(1000), version.Costs[0].Amount)
+	assert.Equal(t, int64(1000), version.AsPricePlan().Costs[0].Amount)
+}
+
+func TestService_SchedulePricePlanVersionMigration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *pricer.SchedulePricePlanVersionMigrationRequest
+		superseded  bool
+		expectedErr error
+	}{
+		{
+			name:       "Success - migrate to current version",
+			req:        &pricer.SchedulePricePlanVersionMigrationRequest{PricePlanID: testPlanID, Version: 1, UserID: testUserID},
+			superseded: true,
+		},
+		{
+			name:        "Failure - current version cannot be migrated",
+			req:         &pricer.SchedulePricePlanVersionMigrationRequest{PricePlanID: testPlanID, Version: 1, UserID: testUserID},
+			expectedErr: pricer.ErrInvalidPricePlanVersionMigration,
+		},
+		{
+			name:        "Failure - target version must be newer",
+			req:         &pricer.SchedulePricePlanVersionMigrationRequest{PricePlanID: testPlanID, Version: 1, UserID: testUserID, ToVersion: 1},
+			superseded:  true,
+			expectedErr: pricer.ErrInvalidPricePlanVersionMigration,
+		},
+		{
+			name:        "Failure - invalid policy",
+			req:         &pricer.SchedulePricePlanVersionMigrationRequest{PricePlanID: testPlanID, Version: 1, UserID: testUserID, Policy: "drop"},
+			superseded:  true,
+			expectedErr: pricer.ErrInvalidPricePlanVersionMigration,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockPricerRepository{
+				getPricePlanByIDFunc: func(ctx context.Context, id string, req *pricer.GetPricePlanByIDRequest) (*pricer.PricePlan, error) {
+					plan := makeValidPlan()
+					plan.CurrentVersion = 2
+					plan.CurrentVersionID = testVersionTwoID
+					return plan, nil
+				},
+				getPricePlanVersionFunc: func(ctx context.Context, pricePlanID string, version int) (*pricer.PricePlanVersion, error) {
+					v := pricer.NewPricePlanVersion(makeValidPlan(), version, "", testUserID)
+					v.ID = map[int]string{1: testVersionOneID, 2: testVersionTwoID}[version]
+					if version == 1 && tt.superseded {
+						v.SupersededAt = "2026-01-01T00:00:00"
+					}
+					return v, nil
+				},
+			}
+
+			svc := newTestService(repo)
+			response, err := svc.SchedulePricePlanVersionMigration(context.Background(), tt.req)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, response.PricePlanVersion.Grandfathering)
+			assert.Equal(t, pricer.PriceGrandfatherPolicyMigrate, response.PricePlanVersion.Grandfathering.Policy)
+			assert.Equal(t, testVersionTwoID, response.PricePlanVersion.Grandfathering.MigrateToVersionID)
+			assert.Equal(t, 2, response.PricePlanVersion.Grandfathering.MigrateToVersion)
+		})
+	}
+}
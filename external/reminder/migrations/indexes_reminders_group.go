@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/reminder"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitRemindersGroupIndexUp adds the index used to list group-owned reminders.
+func InitRemindersGroupIndexUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	const mongoCollectionName = reminder.ReminderCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-reminders-group-index"))
+
+	groupStatusIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "group_id", Value: 1},
+			{Key: "status", Value: 1},
+		},
+		Options: options.Index().SetName("idx_reminders_group_status"),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateOne(context.Background(), groupStatusIndexModel)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-reminders-group-index"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-reminders-group-index"))
+	return nil
+}
+
+// InitRemindersGroupIndexDown rolls back the group reminders index.
+func InitRemindersGroupIndexDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = reminder.ReminderCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-reminders-group-index"))
+
+	err := db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), "idx_reminders_group_status")
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-reminders-group-index"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-reminders-group-index"))
+	return nil
+}
@@ -17,6 +17,7 @@ type Reminder struct {
 	Id              string                 `json:"id" bson:"_id"`
 	NanoId          string                 `json:"nano_id" bson:"_nano_id"`
 	UserID          string                 `json:"user_id" bson:"user_id"`
+	GroupID         string                 `json:"group_id,omitempty" bson:"group_id,omitempty"`
 	TargetType      string                 `json:"target_type,omitempty" bson:"target_type,omitempty"`
 	TargetId        string                 `json:"target_id,omitempty" bson:"target_id,omitempty"`
 	Title           string                 `json:"title" bson:"title"`
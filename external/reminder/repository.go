@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/ooaklee/ghatd/external/tenancy"
 	"github.com/ooaklee/ghatd/external/toolbox"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -278,6 +279,17 @@ func buildReminderPaginationOptions(page, perPage int) *options.FindOptionsBuild
 		SetSort(bson.D{{Key: "target_time", Value: 1}, {Key: "created_at", Value: -1}})
 }
 
+// scopeReminderFilter scopes a reminder query to the tenant on the context when it is
+// made for a user or from inside a tenant. Unscoped admin listings keep seeing every
+// reminder.
+func scopeReminderFilter(ctx context.Context, filter bson.M, userID string) bson.M {
+	if strings.TrimSpace(userID) == "" && tenancy.GroupIDFrom(ctx) == "" {
+		return filter
+	}
+
+	return tenancy.ScopeFilter(ctx, filter)
+}
+
 // ListReminders returns reminder declarations matching the supplied filters.
 func (r *Repository) ListReminders(ctx context.Context, userID string, status string, targetType string, targetId string, page, perPage int) ([]*Reminder, error) {
 	collection, err := r.GetReminderCollection(ctx)
@@ -291,6 +303,7 @@ func (r *Repository) ListReminders(ctx context.Context, userID string, status st
 		TargetType: targetType,
 		TargetId:   targetId,
 	})
+	filter = scopeReminderFilter(ctx, filter, userID)
 
 	findOptions := buildReminderPaginationOptions(page, perPage)
 
@@ -392,7 +405,9 @@ func (r *Repository) listReminders(ctx context.Context, filter *ReminderFilter,
 
 	findOptions := buildReminderPaginationOptions(page, perPage)
 
-	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, buildReminderListFilter(filter), findOptions)
+	queryFilter := scopeReminderFilter(ctx, buildReminderListFilter(filter), filter.UserID)
+
+	cursor, err := r.Store.ExecuteFindCommand(ctx, collection, queryFilter, findOptions)
 	if err != nil {
 		return nil, err
 	}
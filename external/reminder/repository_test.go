@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ooaklee/ghatd/external/tenancy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -196,6 +197,26 @@ func TestBuildReminderListFilter(t *testing.T) {
 	})
 }
 
+func TestScopeReminderFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unscoped admin listing", func(t *testing.T) {
+		f := scopeReminderFilter(context.Background(), buildReminderListFilter(&ReminderFilter{}), "")
+		assert.NotContains(t, f, "group_id")
+	})
+
+	t.Run("user listing excludes group-owned reminders", func(t *testing.T) {
+		f := scopeReminderFilter(context.Background(), buildReminderListFilter(&ReminderFilter{UserID: "user-1"}), "user-1")
+		assert.Equal(t, bson.M{"$exists": false}, f["group_id"])
+	})
+
+	t.Run("tenant listing is limited to the group", func(t *testing.T) {
+		ctx := tenancy.TransitWith(context.Background(), &tenancy.Tenant{GroupID: "group-1"})
+		f := scopeReminderFilter(ctx, buildReminderListFilter(&ReminderFilter{}), "")
+		assert.Equal(t, "group-1", f["group_id"])
+	})
+}
+
 func TestBuildReminderExecutionFilter(t *testing.T) {
 	t.Parallel()
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/tenancy"
 	"github.com/ooaklee/ghatd/external/toolbox"
 	"go.uber.org/zap"
 )
@@ -82,6 +83,7 @@ func (s *Service) CreateReminder(ctx context.Context, req *CreateReminderRequest
 
 	reminder := &Reminder{
 		UserID:          userID,
+		GroupID:         tenancy.GroupIDFrom(ctx),
 		TargetType:      strings.TrimSpace(req.TargetType),
 		TargetId:        strings.TrimSpace(req.TargetId),
 		Title:           title,
@@ -106,6 +108,7 @@ func (s *Service) CreateReminder(ctx context.Context, req *CreateReminderRequest
 }
 
 // GetReminderByID retrieves one reminder declaration and enforces optional user ownership.
+// Group-owned reminders can be read by any member of their tenant.
 func (s *Service) GetReminderByID(ctx context.Context, req *GetReminderByIDRequest) (*GetReminderByIDResponse, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/reminder")
 
@@ -121,14 +124,15 @@ func (s *Service) GetReminderByID(ctx context.Context, req *GetReminderByIDReque
 		logger.Error("failed-to-get-reminder-error", zap.String("reminder-id", req.Id), zap.Error(err))
 		return nil, err
 	}
-	if strings.TrimSpace(req.UserID) != "" && reminder.UserID != strings.TrimSpace(req.UserID) {
+	if strings.TrimSpace(req.UserID) != "" && !canReadReminder(ctx, reminder, strings.TrimSpace(req.UserID)) {
 		return nil, ErrNotAuthorized
 	}
 
 	return &GetReminderByIDResponse{Reminder: reminder}, nil
 }
 
-// ListReminders returns reminder declarations matching the provided filters.
+// ListReminders returns reminder declarations matching the provided filters. Inside a
+// tenant the reminders of every member of the group are returned.
 func (s *Service) ListReminders(ctx context.Context, req *ListRemindersRequest) (*ListRemindersResponse, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/reminder")
 
@@ -153,9 +157,14 @@ func (s *Service) ListReminders(ctx context.Context, req *ListRemindersRequest)
 		}
 	}
 
+	userID := strings.TrimSpace(req.UserID)
+	if tenancy.GroupIDFrom(ctx) != "" {
+		userID = ""
+	}
+
 	reminders, err := s.ReminderRepository.ListReminders(
 		ctx,
-		strings.TrimSpace(req.UserID),
+		userID,
 		status,
 		strings.TrimSpace(req.TargetType),
 		strings.TrimSpace(req.TargetId),
@@ -196,7 +205,7 @@ func (s *Service) UpdateReminderByID(ctx context.Context, req *UpdateReminderByI
 		return nil, err
 	}
 
-	if existing.UserID != req.UserID {
+	if !canModifyReminder(ctx, existing, req.UserID) {
 		return nil, ErrNotAuthorized
 	}
 
@@ -286,7 +295,7 @@ func (s *Service) DeleteReminderByID(ctx context.Context, req *DeleteReminderByI
 		return err
 	}
 
-	if existing.UserID != req.UserID {
+	if !canModifyReminder(ctx, existing, req.UserID) {
 		return ErrNotAuthorized
 	}
 
@@ -319,7 +328,7 @@ func (s *Service) DisableReminderByID(ctx context.Context, req *DisableReminderB
 		return nil, err
 	}
 
-	if existing.UserID != req.UserID {
+	if !canModifyReminder(ctx, existing, req.UserID) {
 		return nil, ErrNotAuthorized
 	}
 
@@ -523,6 +532,33 @@ func reminderPaginationMeta(page, perPage int) map[string]interface{} {
 	}
 }
 
+// canReadReminder returns true when the user may read the reminder. Reminders are only
+// reachable from their own scope, where personal reminders need their owner and
+// group-owned reminders are shared with every member of the tenant.
+func canReadReminder(ctx context.Context, reminder *Reminder, userID string) bool {
+	if !tenancy.MatchesScope(ctx, reminder.GroupID) {
+		return false
+	}
+
+	return reminder.GroupID != "" || reminder.UserID == userID
+}
+
+// canModifyReminder returns true when the user may change the reminder. Reminders are only
+// reachable from their own scope, where they can be changed by their owner or, for
+// group-owned reminders, by an admin of the tenant.
+func canModifyReminder(ctx context.Context, reminder *Reminder, userID string) bool {
+	if !tenancy.MatchesScope(ctx, reminder.GroupID) {
+		return false
+	}
+
+	if reminder.UserID == userID {
+		return true
+	}
+
+	tenant := tenancy.AcquireFrom(ctx)
+	return tenant != nil && tenant.IsAdmin
+}
+
 // RecordReminderExecution stores the result of one reminder scheduler or notification attempt.
 func (s *Service) RecordReminderExecution(ctx context.Context, req *RecordReminderExecutionRequest) (*RecordReminderExecutionResponse, error) {
 	logger := logger.AcquireOperationFrom(ctx, "external/reminder", "record-reminder-execution")
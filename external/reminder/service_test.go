@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ooaklee/ghatd/external/reminder"
+	"github.com/ooaklee/ghatd/external/tenancy"
 )
 
 type mockReminderRepository struct {
@@ -254,6 +255,68 @@ func TestService_GetReminderByID(t *testing.T) {
 	})
 }
 
+func TestService_GroupOwnedReminders(t *testing.T) {
+	t.Parallel()
+
+	tenantCtx := func(isAdmin bool) context.Context {
+		return tenancy.TransitWith(context.Background(), &tenancy.Tenant{GroupID: "group-1", IsAdmin: isAdmin})
+	}
+	repo := &mockReminderRepository{
+		getReminderByIDFunc: func(ctx context.Context, id string) (*reminder.Reminder, error) {
+			if id == "personal-1" {
+				return &reminder.Reminder{Id: id, UserID: "user-1", Status: reminder.ReminderStatusActive}, nil
+			}
+			return &reminder.Reminder{Id: id, UserID: "user-1", GroupID: "group-1", Status: reminder.ReminderStatusActive}, nil
+		},
+	}
+	svc := reminder.NewService(repo)
+
+	t.Run("created inside a tenant are group-owned", func(t *testing.T) {
+		t.Parallel()
+		res, err := svc.CreateReminder(tenantCtx(false), &reminder.CreateReminderRequest{UserID: "user-1", Title: "Stand-up", TargetTime: "09:00"})
+		require.NoError(t, err)
+		assert.Equal(t, "group-1", res.Reminder.GroupID)
+	})
+
+	t.Run("readable by other tenant members only inside the tenant", func(t *testing.T) {
+		t.Parallel()
+		_, err := svc.GetReminderByID(tenantCtx(false), &reminder.GetReminderByIDRequest{Id: "group-reminder-1", UserID: "user-2"})
+		require.NoError(t, err)
+
+		_, err = svc.GetReminderByID(context.Background(), &reminder.GetReminderByIDRequest{Id: "group-reminder-1", UserID: "user-1"})
+		assert.ErrorIs(t, err, reminder.ErrNotAuthorized)
+	})
+
+	t.Run("personal reminders are not reachable inside a tenant", func(t *testing.T) {
+		t.Parallel()
+		_, err := svc.GetReminderByID(tenantCtx(false), &reminder.GetReminderByIDRequest{Id: "personal-1", UserID: "user-1"})
+		assert.ErrorIs(t, err, reminder.ErrNotAuthorized)
+	})
+
+	t.Run("modifiable by tenant admins but not other members", func(t *testing.T) {
+		t.Parallel()
+		_, err := svc.DisableReminderByID(tenantCtx(false), &reminder.DisableReminderByIDRequest{Id: "group-reminder-1", UserID: "user-2"})
+		assert.ErrorIs(t, err, reminder.ErrNotAuthorized)
+
+		res, err := svc.DisableReminderByID(tenantCtx(true), &reminder.DisableReminderByIDRequest{Id: "group-reminder-1", UserID: "user-2"})
+		require.NoError(t, err)
+		assert.Equal(t, reminder.ReminderStatusDisabled, res.Reminder.Status)
+	})
+
+	t.Run("listing inside a tenant covers every member", func(t *testing.T) {
+		t.Parallel()
+		listSvc := reminder.NewService(&mockReminderRepository{
+			listRemindersFunc: func(ctx context.Context, userID string, status string, targetType string, targetId string, page, perPage int) ([]*reminder.Reminder, error) {
+				assert.Empty(t, userID)
+				assert.Equal(t, "group-1", tenancy.GroupIDFrom(ctx))
+				return []*reminder.Reminder{}, nil
+			},
+		})
+		_, err := listSvc.ListReminders(tenantCtx(false), &reminder.ListRemindersRequest{UserID: "user-1"})
+		require.NoError(t, err)
+	})
+}
+
 func TestService_ListReminders(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,143 @@
+// Package scim serves a SCIM 2.0 provisioning API that lets an organisation's identity provider
+// (Okta, Entra ID, ...) create, update and deactivate its users and keep its groups in sync.
+// Each organisation is a root group, and every request is scoped to the organisation the
+// bearer token was issued for.
+package scim
+
+import "github.com/ooaklee/ghatd/external/toolbox"
+
+const (
+	// SchemaUser is the core schema of SCIM user resources
+	SchemaUser = "urn:ietf:params:scim:schemas:core:2.0:User"
+
+	// SchemaGroup is the core schema of SCIM group resources
+	SchemaGroup = "urn:ietf:params:scim:schemas:core:2.0:Group"
+
+	// SchemaServiceProviderConfig is the schema of the service provider configuration resource
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// SchemaResourceType is the schema of resource type resources
+	SchemaResourceType = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+
+	// SchemaSchema is the schema of schema resources
+	SchemaSchema = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+
+	// SchemaListResponse is the schema of list responses
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	// SchemaPatchOp is the schema of PATCH request bodies
+	SchemaPatchOp = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+
+	// SchemaError is the schema of error responses
+	SchemaError = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+const (
+	// ResourceTypeUser is the name of the user resource type
+	ResourceTypeUser = "User"
+
+	// ResourceTypeGroup is the name of the group resource type
+	ResourceTypeGroup = "Group"
+)
+
+const (
+	// PatchOpAdd adds values to an attribute
+	PatchOpAdd = "add"
+
+	// PatchOpReplace replaces the value of an attribute
+	PatchOpReplace = "replace"
+
+	// PatchOpRemove removes values from an attribute
+	PatchOpRemove = "remove"
+)
+
+const (
+	// ScimTypeInvalidFilter is returned when a filter cannot be parsed or evaluated
+	ScimTypeInvalidFilter = "invalidFilter"
+
+	// ScimTypeInvalidSyntax is returned when a request body cannot be parsed
+	ScimTypeInvalidSyntax = "invalidSyntax"
+
+	// ScimTypeInvalidPath is returned when a PATCH path cannot be parsed
+	ScimTypeInvalidPath = "invalidPath"
+
+	// ScimTypeInvalidValue is returned when a required value is missing or not compatible
+	ScimTypeInvalidValue = "invalidValue"
+
+	// ScimTypeUniqueness is returned when a value is already in use
+	ScimTypeUniqueness = "uniqueness"
+
+	// ScimTypeNoTarget is returned when a PATCH path matches nothing to remove
+	ScimTypeNoTarget = "noTarget"
+
+	// ScimTypeMutability is returned when a read-only attribute is changed
+	ScimTypeMutability = "mutability"
+)
+
+const (
+	// ContentType is the media type of SCIM requests and responses
+	ContentType = "application/scim+json"
+
+	// DefaultListCount is the page size used when a list request does not give a count
+	DefaultListCount = 100
+
+	// MaxListCount is the largest page size a list request can ask for
+	MaxListCount = 500
+
+	// userBatchSize is how many users are fetched per user service call, the most it allows
+	userBatchSize = 100
+
+	// TokenDescription is the description given to SCIM tokens issued without one
+	TokenDescription = "SCIM provisioning"
+
+	// UserExtensionKeyExternalIDPrefix prefixes the organisation ID to form the user extension
+	// key holding the identity provider's ID for the user, so a user can be provisioned by
+	// more than one organisation
+	UserExtensionKeyExternalIDPrefix = "scim_external_id_"
+
+	// UserExtensionKeyProvisionedBy is the user extension key holding the ID of the organisation
+	// whose identity provider created the account. Only that organisation can change the
+	// account's identity fields and status
+	UserExtensionKeyProvisionedBy = "scim_provisioned_by"
+
+	// GroupExtensionKeyExternalID is the group extension key holding the identity provider's
+	// ID for the group
+	GroupExtensionKeyExternalID = "scim_external_id"
+
+	// CtxKeyOrganisationID is the context key the authenticated organisation's ID is carried under
+	CtxKeyOrganisationID toolbox.CtxKey = "scim-organisation-id"
+)
+
+const (
+	// SCIMURIVariableGroupID holds the organisation's group ID in token management routes
+	SCIMURIVariableGroupID = "groupID"
+
+	// SCIMURIVariableTokenID holds the SCIM token ID in token management routes
+	SCIMURIVariableTokenID = "tokenID"
+
+	// SCIMURIVariableResourceID holds the user or group ID in provisioning routes
+	SCIMURIVariableResourceID = "resourceID"
+)
+
+const (
+	// Error Keys
+	ErrKeyUnauthorised              = "SCIMUnauthorised"
+	ErrKeyForbidden                 = "SCIMForbidden"
+	ErrKeyOrganisationNotFound      = "SCIMOrganisationNotFound"
+	ErrKeyOrganisationNotRootGroup  = "SCIMOrganisationNotRootGroup"
+	ErrKeyTokenNotFound             = "SCIMTokenNotFound"
+	ErrKeyResourceNotFound          = "SCIMResourceNotFound"
+	ErrKeyUserAlreadyProvisioned    = "SCIMUserAlreadyProvisioned"
+	ErrKeyGroupAlreadyExists        = "SCIMGroupAlreadyExists"
+	ErrKeyInvalidFilter             = "SCIMInvalidFilter"
+	ErrKeyInvalidSyntax             = "SCIMInvalidSyntax"
+	ErrKeyInvalidPath               = "SCIMInvalidPath"
+	ErrKeyInvalidValue              = "SCIMInvalidValue"
+	ErrKeyNoTarget                  = "SCIMNoTarget"
+	ErrKeyMutability                = "SCIMMutability"
+	ErrKeyMemberNotInOrganisation   = "SCIMMemberNotInOrganisation"
+	ErrKeyInvalidStatusTransition   = "SCIMInvalidStatusTransition"
+	ErrKeyOrganisationOwnerRequired = "SCIMOrganisationOwnerRequired"
+	ErrKeyRequestFailedValidation   = "SCIMRequestFailedValidation"
+	ErrKeyUnableToIdentifyUser      = "SCIMUnableToIdentifyUser"
+)
@@ -0,0 +1,190 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/ooaklee/ghatd/external/apitoken"
+	"github.com/ooaklee/ghatd/external/group"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"github.com/ooaklee/reply/v2"
+)
+
+// SCIMErrorMap holds Error keys, their corresponding human-friendly message, and response status code.
+// Provisioning endpoints render these as SCIM error responses, token endpoints as standard replies.
+var SCIMErrorMap reply.ErrorManifest = reply.ErrorManifest{
+	ErrUnauthorised: {
+		Title:      "Unauthorized",
+		StatusCode: http.StatusUnauthorized,
+		Code:       "SCM00-001",
+		Detail:     "A valid SCIM bearer token is required",
+	},
+	ErrForbidden: {
+		Title:      "Forbidden",
+		StatusCode: http.StatusForbidden,
+		Code:       "SCM00-002",
+		Detail:     "You do not have permission to manage SCIM provisioning for this organisation",
+	},
+	ErrOrganisationNotFound: {
+		Title:      "Not Found",
+		StatusCode: http.StatusNotFound,
+		Code:       "SCM00-003",
+		Detail:     "The organisation could not be found",
+	},
+	ErrOrganisationNotRootGroup: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-004",
+		Detail:     "SCIM provisioning can only be enabled on a top-level group",
+	},
+	ErrTokenNotFound: {
+		Title:      "Not Found",
+		StatusCode: http.StatusNotFound,
+		Code:       "SCM00-005",
+		Detail:     "The SCIM token could not be found",
+	},
+	ErrResourceNotFound: {
+		Title:      "Not Found",
+		StatusCode: http.StatusNotFound,
+		Code:       "SCM00-006",
+		Detail:     "The resource could not be found",
+	},
+	ErrUserAlreadyProvisioned: {
+		Title:      "Conflict",
+		StatusCode: http.StatusConflict,
+		Code:       "SCM00-007",
+		Detail:     "A user with this userName is already provisioned",
+	},
+	ErrGroupAlreadyExists: {
+		Title:      "Conflict",
+		StatusCode: http.StatusConflict,
+		Code:       "SCM00-008",
+		Detail:     "A group with this displayName already exists",
+	},
+	ErrInvalidFilter: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-009",
+		Detail:     "The filter is invalid or not supported",
+	},
+	ErrInvalidSyntax: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-010",
+		Detail:     "The request body could not be parsed",
+	},
+	ErrInvalidPath: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-011",
+		Detail:     "The PATCH path is invalid or not supported",
+	},
+	ErrInvalidValue: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-012",
+		Detail:     "A required value is missing or has the wrong type",
+	},
+	ErrNoTarget: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-013",
+		Detail:     "The PATCH path did not match anything",
+	},
+	ErrMutability: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-014",
+		Detail:     "The request tried to change a read-only attribute",
+	},
+	ErrMemberNotInOrganisation: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-015",
+		Detail:     "Group members must be users provisioned to the organisation",
+	},
+	ErrInvalidStatusTransition: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-016",
+		Detail:     "The user's status cannot be changed to the requested active state",
+	},
+	ErrOrganisationOwnerRequired: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-017",
+		Detail:     "The organisation's owner cannot be deprovisioned",
+	},
+	ErrRequestFailedValidation: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-018",
+		Detail:     "Check submitted parameters",
+	},
+	ErrUnableToIdentifyUser: {
+		Title:      "Unauthorized",
+		StatusCode: http.StatusUnauthorized,
+		Code:       "SCM00-019",
+		Detail:     "Unable to identify the requesting user",
+	},
+
+	// Errors surfaced by the services SCIM provisions through
+	apitoken.ErrInvalidAPIFormatDetected: {
+		Title:      "Unauthorized",
+		StatusCode: http.StatusUnauthorized,
+		Code:       "SCM00-020",
+		Detail:     "A valid SCIM bearer token is required",
+	},
+	apitoken.ErrUnableToValidateUserAPIToken: {
+		Title:      "Unauthorized",
+		StatusCode: http.StatusUnauthorized,
+		Code:       "SCM00-021",
+		Detail:     "A valid SCIM bearer token is required",
+	},
+	userv2.ErrEmailAlreadyExists: {
+		Title:      "Conflict",
+		StatusCode: http.StatusConflict,
+		Code:       "SCM00-022",
+		Detail:     "The userName is already used by another user",
+	},
+	userv2.ErrValidationFailed: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-023",
+		Detail:     "The user is missing required attributes",
+	},
+	group.ErrNameAlreadyExists: {
+		Title:      "Conflict",
+		StatusCode: http.StatusConflict,
+		Code:       "SCM00-024",
+		Detail:     "A group with this displayName already exists",
+	},
+	group.ErrInvalidParentChildRelation: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-025",
+		Detail:     "The configured SCIM group type cannot be created under the organisation",
+	},
+	group.ErrMaxMembersReached: {
+		Title:      "Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Code:       "SCM00-026",
+		Detail:     "The organisation has reached its member limit",
+	},
+}
+
+// scimTypeByError holds the SCIM error type returned alongside the status for errors that have one
+var scimTypeByError = map[error]string{
+	ErrUserAlreadyProvisioned:    ScimTypeUniqueness,
+	ErrGroupAlreadyExists:        ScimTypeUniqueness,
+	userv2.ErrEmailAlreadyExists: ScimTypeUniqueness,
+	group.ErrNameAlreadyExists:   ScimTypeUniqueness,
+	ErrInvalidFilter:             ScimTypeInvalidFilter,
+	ErrInvalidSyntax:             ScimTypeInvalidSyntax,
+	ErrInvalidPath:               ScimTypeInvalidPath,
+	ErrInvalidValue:              ScimTypeInvalidValue,
+	ErrMemberNotInOrganisation:   ScimTypeInvalidValue,
+	ErrInvalidStatusTransition:   ScimTypeInvalidValue,
+	userv2.ErrValidationFailed:   ScimTypeInvalidValue,
+	ErrNoTarget:                  ScimTypeNoTarget,
+	ErrMutability:                ScimTypeMutability,
+}
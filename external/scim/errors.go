@@ -0,0 +1,25 @@
+package scim
+
+import "errors"
+
+var (
+	ErrForbidden                 = errors.New(ErrKeyForbidden)
+	ErrGroupAlreadyExists        = errors.New(ErrKeyGroupAlreadyExists)
+	ErrInvalidFilter             = errors.New(ErrKeyInvalidFilter)
+	ErrInvalidPath               = errors.New(ErrKeyInvalidPath)
+	ErrInvalidStatusTransition   = errors.New(ErrKeyInvalidStatusTransition)
+	ErrInvalidSyntax             = errors.New(ErrKeyInvalidSyntax)
+	ErrInvalidValue              = errors.New(ErrKeyInvalidValue)
+	ErrMemberNotInOrganisation   = errors.New(ErrKeyMemberNotInOrganisation)
+	ErrMutability                = errors.New(ErrKeyMutability)
+	ErrNoTarget                  = errors.New(ErrKeyNoTarget)
+	ErrOrganisationNotFound      = errors.New(ErrKeyOrganisationNotFound)
+	ErrOrganisationNotRootGroup  = errors.New(ErrKeyOrganisationNotRootGroup)
+	ErrOrganisationOwnerRequired = errors.New(ErrKeyOrganisationOwnerRequired)
+	ErrRequestFailedValidation   = errors.New(ErrKeyRequestFailedValidation)
+	ErrResourceNotFound          = errors.New(ErrKeyResourceNotFound)
+	ErrTokenNotFound             = errors.New(ErrKeyTokenNotFound)
+	ErrUnableToIdentifyUser      = errors.New(ErrKeyUnableToIdentifyUser)
+	ErrUnauthorised              = errors.New(ErrKeyUnauthorised)
+	ErrUserAlreadyProvisioned    = errors.New(ErrKeyUserAlreadyProvisioned)
+)
@@ -0,0 +1,368 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"github.com/ritwickdey/querydecoder"
+)
+
+// MapRequestToIssueTokenRequest maps incoming IssueToken request to correct struct
+func MapRequestToIssueTokenRequest(request *http.Request, validator SCIMValidator) (*IssueTokenRequest, error) {
+	var err error
+	parsedRequest := &IssueTokenRequest{}
+
+	if request.Body != nil && request.ContentLength != 0 {
+		if err := toolbox.DecodeRequestBody(request, parsedRequest); err != nil {
+			return nil, ErrRequestFailedValidation
+		}
+	}
+
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, SCIMURIVariableGroupID)
+	if err != nil {
+		return nil, ErrOrganisationNotFound
+	}
+
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.ActorID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	if parsedRequest.TokenTtl < 0 {
+		return nil, ErrRequestFailedValidation
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToGetTokensRequest maps incoming GetTokens request to correct struct
+func MapRequestToGetTokensRequest(request *http.Request, validator SCIMValidator) (*GetTokensRequest, error) {
+	var err error
+	parsedRequest := &GetTokensRequest{}
+
+	if err := querydecoder.New(request.URL.Query()).Decode(parsedRequest); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, SCIMURIVariableGroupID)
+	if err != nil {
+		return nil, ErrOrganisationNotFound
+	}
+
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.ActorID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToRevokeTokenRequest maps incoming RevokeToken request to correct struct
+func MapRequestToRevokeTokenRequest(request *http.Request, validator SCIMValidator) (*RevokeTokenRequest, error) {
+	var err error
+	parsedRequest := &RevokeTokenRequest{}
+
+	parsedRequest.GroupID, err = toolbox.GetVariableValueFromUri(request, SCIMURIVariableGroupID)
+	if err != nil {
+		return nil, ErrOrganisationNotFound
+	}
+
+	parsedRequest.TokenID, err = toolbox.GetVariableValueFromUri(request, SCIMURIVariableTokenID)
+	if err != nil {
+		return nil, ErrTokenNotFound
+	}
+
+	parsedRequest.ActorID = accessmanagerhelpers.AcquireFrom(request.Context())
+	if parsedRequest.ActorID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	return parsedRequest, nil
+}
+
+// MapRequestToAuthenticateRequest maps the bearer token of an incoming SCIM request to correct struct
+func MapRequestToAuthenticateRequest(request *http.Request) (*AuthenticateRequest, error) {
+	authorization := strings.TrimSpace(request.Header.Get("Authorization"))
+
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, ErrUnauthorised
+	}
+
+	return &AuthenticateRequest{
+		Token: strings.TrimSpace(token),
+	}, nil
+}
+
+// MapRequestToGetUsersRequest maps incoming GetUsers request to correct struct
+func MapRequestToGetUsersRequest(request *http.Request) (*GetUsersRequest, error) {
+	listRequest, err := mapRequestToListResourcesRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetUsersRequest{ListResourcesRequest: *listRequest}, nil
+}
+
+// MapRequestToGetUserRequest maps incoming GetUser request to correct struct
+func MapRequestToGetUserRequest(request *http.Request) (*GetUserRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &GetUserRequest{
+		OrganisationID: acquireOrganisationIDFrom(request),
+		ID:             resourceID,
+	}, nil
+}
+
+// MapRequestToCreateUserRequest maps incoming CreateUser request to correct struct
+func MapRequestToCreateUserRequest(request *http.Request) (*CreateUserRequest, error) {
+	user := &User{}
+	if err := toolbox.DecodeRequestBody(request, user); err != nil {
+		return nil, ErrInvalidSyntax
+	}
+
+	return &CreateUserRequest{
+		OrganisationID: acquireOrganisationIDFrom(request),
+		User:           user,
+	}, nil
+}
+
+// MapRequestToReplaceUserRequest maps incoming ReplaceUser request to correct struct
+func MapRequestToReplaceUserRequest(request *http.Request) (*ReplaceUserRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	user := &User{}
+	if err := toolbox.DecodeRequestBody(request, user); err != nil {
+		return nil, ErrInvalidSyntax
+	}
+
+	return &ReplaceUserRequest{
+		OrganisationID: acquireOrganisationIDFrom(request),
+		ID:             resourceID,
+		User:           user,
+	}, nil
+}
+
+// MapRequestToPatchUserRequest maps incoming PatchUser request to correct struct
+func MapRequestToPatchUserRequest(request *http.Request) (*PatchUserRequest, error) {
+	var err error
+	parsedRequest := &PatchUserRequest{}
+
+	if err := toolbox.DecodeRequestBody(request, parsedRequest); err != nil {
+		return nil, ErrInvalidSyntax
+	}
+
+	if !hasPatchOpSchema(parsedRequest.Schemas) {
+		return nil, ErrInvalidSyntax
+	}
+
+	parsedRequest.ID, err = toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	parsedRequest.OrganisationID = acquireOrganisationIDFrom(request)
+
+	return parsedRequest, nil
+}
+
+// MapRequestToDeleteUserRequest maps incoming DeleteUser request to correct struct
+func MapRequestToDeleteUserRequest(request *http.Request) (*DeleteUserRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &DeleteUserRequest{
+		OrganisationID: acquireOrganisationIDFrom(request),
+		ID:             resourceID,
+	}, nil
+}
+
+// MapRequestToGetGroupsRequest maps incoming GetGroups request to correct struct
+func MapRequestToGetGroupsRequest(request *http.Request) (*GetGroupsRequest, error) {
+	listRequest, err := mapRequestToListResourcesRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetGroupsRequest{ListResourcesRequest: *listRequest}, nil
+}
+
+// MapRequestToGetGroupRequest maps incoming GetGroup request to correct struct
+func MapRequestToGetGroupRequest(request *http.Request) (*GetGroupRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &GetGroupRequest{
+		OrganisationID:     acquireOrganisationIDFrom(request),
+		ID:                 resourceID,
+		ExcludedAttributes: request.URL.Query().Get("excludedAttributes"),
+	}, nil
+}
+
+// MapRequestToCreateGroupRequest maps incoming CreateGroup request to correct struct
+func MapRequestToCreateGroupRequest(request *http.Request) (*CreateGroupRequest, error) {
+	scimGroup := &Group{}
+	if err := toolbox.DecodeRequestBody(request, scimGroup); err != nil {
+		return nil, ErrInvalidSyntax
+	}
+
+	return &CreateGroupRequest{
+		OrganisationID: acquireOrganisationIDFrom(request),
+		Group:          scimGroup,
+	}, nil
+}
+
+// MapRequestToReplaceGroupRequest maps incoming ReplaceGroup request to correct struct
+func MapRequestToReplaceGroupRequest(request *http.Request) (*ReplaceGroupRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	scimGroup := &Group{}
+	if err := toolbox.DecodeRequestBody(request, scimGroup); err != nil {
+		return nil, ErrInvalidSyntax
+	}
+
+	return &ReplaceGroupRequest{
+		OrganisationID: acquireOrganisationIDFrom(request),
+		ID:             resourceID,
+		Group:          scimGroup,
+	}, nil
+}
+
+// MapRequestToPatchGroupRequest maps incoming PatchGroup request to correct struct
+func MapRequestToPatchGroupRequest(request *http.Request) (*PatchGroupRequest, error) {
+	var err error
+	parsedRequest := &PatchGroupRequest{}
+
+	if err := toolbox.DecodeRequestBody(request, parsedRequest); err != nil {
+		return nil, ErrInvalidSyntax
+	}
+
+	if !hasPatchOpSchema(parsedRequest.Schemas) {
+		return nil, ErrInvalidSyntax
+	}
+
+	parsedRequest.ID, err = toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	parsedRequest.OrganisationID = acquireOrganisationIDFrom(request)
+
+	return parsedRequest, nil
+}
+
+// MapRequestToDeleteGroupRequest maps incoming DeleteGroup request to correct struct
+func MapRequestToDeleteGroupRequest(request *http.Request) (*DeleteGroupRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &DeleteGroupRequest{
+		OrganisationID: acquireOrganisationIDFrom(request),
+		ID:             resourceID,
+	}, nil
+}
+
+// MapRequestToGetSchemaRequest maps incoming GetSchema request to correct struct
+func MapRequestToGetSchemaRequest(request *http.Request) (*GetSchemaRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &GetSchemaRequest{ID: resourceID}, nil
+}
+
+// MapRequestToGetResourceTypeRequest maps incoming GetResourceType request to correct struct
+func MapRequestToGetResourceTypeRequest(request *http.Request) (*GetResourceTypeRequest, error) {
+	resourceID, err := toolbox.GetVariableValueFromUri(request, SCIMURIVariableResourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	return &GetResourceTypeRequest{ID: resourceID}, nil
+}
+
+// mapRequestToListResourcesRequest maps the query parameters shared by SCIM list endpoints.
+// They are camel cased by the specification, so are read by hand
+func mapRequestToListResourcesRequest(request *http.Request) (*ListResourcesRequest, error) {
+	query := request.URL.Query()
+
+	parsedRequest := &ListResourcesRequest{
+		OrganisationID:     acquireOrganisationIDFrom(request),
+		Filter:             query.Get("filter"),
+		ExcludedAttributes: query.Get("excludedAttributes"),
+	}
+
+	if startIndex := query.Get("startIndex"); startIndex != "" {
+		parsedStartIndex, err := strconv.Atoi(startIndex)
+		if err != nil {
+			return nil, ErrInvalidValue
+		}
+		parsedRequest.StartIndex = parsedStartIndex
+	}
+
+	if count := query.Get("count"); count != "" {
+		parsedCount, err := strconv.Atoi(count)
+		if err != nil {
+			return nil, ErrInvalidValue
+		}
+		parsedRequest.Count = &parsedCount
+	}
+
+	return parsedRequest, nil
+}
+
+// acquireOrganisationIDFrom returns the organisation the request's SCIM token was issued for
+func acquireOrganisationIDFrom(request *http.Request) string {
+	organisationID, _ := toolbox.AcquireFromCtxByKey[string](request.Context(), CtxKeyOrganisationID)
+	return organisationID
+}
+
+// hasPatchOpSchema reports whether a PATCH request declares the PatchOp schema
+func hasPatchOpSchema(schemas []string) bool {
+	for _, schema := range schemas {
+		if schema == SchemaPatchOp {
+			return true
+		}
+	}
+	return false
+}
+
+// validateParsedRequest validates based on tags. On failure an error is returned
+func validateParsedRequest(request interface{}, validator SCIMValidator) error {
+	if validator == nil {
+		return nil
+	}
+
+	return validator.Validate(request)
+}
@@ -0,0 +1,538 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// filterExpression is a parsed SCIM filter that can be matched against a resource
+// in its JSON map form
+type filterExpression interface {
+	matches(resource map[string]interface{}) bool
+}
+
+// logicalExpression joins two expressions with "and" or "or"
+type logicalExpression struct {
+	operator string
+	left     filterExpression
+	right    filterExpression
+}
+
+func (e *logicalExpression) matches(resource map[string]interface{}) bool {
+	if e.operator == "and" {
+		return e.left.matches(resource) && e.right.matches(resource)
+	}
+	return e.left.matches(resource) || e.right.matches(resource)
+}
+
+// notExpression negates an expression
+type notExpression struct {
+	expression filterExpression
+}
+
+func (e *notExpression) matches(resource map[string]interface{}) bool {
+	return !e.expression.matches(resource)
+}
+
+// valuePathExpression matches when any entry of a multi-valued attribute matches the
+// inner filter, e.g. emails[type eq "work"]
+type valuePathExpression struct {
+	path   []string
+	filter filterExpression
+}
+
+func (e *valuePathExpression) matches(resource map[string]interface{}) bool {
+	for _, value := range resolveAttribute(resource, e.path) {
+		entry, ok := value.(map[string]interface{})
+		if ok && e.filter.matches(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeExpression compares an attribute with a value, e.g. userName eq "jane@example.com"
+type attributeExpression struct {
+	path     []string
+	operator string
+	value    interface{}
+}
+
+func (e *attributeExpression) matches(resource map[string]interface{}) bool {
+	values := resolveAttribute(resource, e.path)
+
+	// complex multi-valued attributes compare on their "value" sub-attribute
+	for i, value := range values {
+		if entry, ok := value.(map[string]interface{}); ok {
+			values[i] = lookupKey(entry, "value")
+		}
+	}
+
+	switch e.operator {
+	case "pr":
+		for _, value := range values {
+			if isPresent(value) {
+				return true
+			}
+		}
+		return false
+	case "ne":
+		for _, value := range values {
+			if compareFilterValues(value, "eq", e.value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if e.value == nil && e.operator == "eq" {
+		for _, value := range values {
+			if isPresent(value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, value := range values {
+		if compareFilterValues(value, e.operator, e.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFilter parses a SCIM filter expression such as
+// `userName eq "jane@example.com" and (active eq true or emails[type eq "work"])`
+func parseFilter(filter string) (filterExpression, error) {
+	tokens, err := tokeniseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, ErrInvalidFilter
+	}
+
+	parser := &filterParser{tokens: tokens}
+	expression, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.position != len(parser.tokens) {
+		return nil, ErrInvalidFilter
+	}
+
+	return expression, nil
+}
+
+// filterToken is a lexical token of a filter, literals keep their quoted form
+type filterToken struct {
+	text     string
+	isString bool
+}
+
+// tokeniseFilter splits a filter into words, string literals, brackets and parentheses
+func tokeniseFilter(filter string) ([]filterToken, error) {
+	tokens := []filterToken{}
+
+	for i := 0; i < len(filter); {
+		char := filter[i]
+
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			i++
+		case char == '(' || char == ')' || char == '[' || char == ']':
+			tokens = append(tokens, filterToken{text: string(char)})
+			i++
+		case char == '"':
+			end := i + 1
+			for end < len(filter) && filter[end] != '"' {
+				if filter[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(filter) {
+				return nil, ErrInvalidFilter
+			}
+
+			var literal string
+			if err := json.Unmarshal([]byte(filter[i:end+1]), &literal); err != nil {
+				return nil, ErrInvalidFilter
+			}
+
+			tokens = append(tokens, filterToken{text: literal, isString: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(filter) && !strings.ContainsRune(" \t\n\r()[]\"", rune(filter[end])) {
+				end++
+			}
+			tokens = append(tokens, filterToken{text: filter[i:end]})
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser over filter tokens, "or" binds loosest,
+// then "and", then "not" and grouping
+type filterParser struct {
+	tokens   []filterToken
+	position int
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.position >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.position], true
+}
+
+func (p *filterParser) peekKeyword(keyword string) bool {
+	token, ok := p.peek()
+	return ok && !token.isString && strings.EqualFold(token.text, keyword)
+}
+
+func (p *filterParser) expect(text string) error {
+	token, ok := p.peek()
+	if !ok || token.isString || token.text != text {
+		return ErrInvalidFilter
+	}
+	p.position++
+	return nil
+}
+
+func (p *filterParser) parseOr() (filterExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekKeyword("or") {
+		p.position++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{operator: "or", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekKeyword("and") {
+		p.position++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{operator: "and", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterExpression, error) {
+	if p.peekKeyword("not") {
+		p.position++
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &notExpression{expression: expression}, nil
+	}
+
+	token, ok := p.peek()
+	if !ok {
+		return nil, ErrInvalidFilter
+	}
+
+	if !token.isString && token.text == "(" {
+		p.position++
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expression, nil
+	}
+
+	return p.parseAttributeExpression()
+}
+
+func (p *filterParser) parseAttributeExpression() (filterExpression, error) {
+	token, ok := p.peek()
+	if !ok || token.isString {
+		return nil, ErrInvalidFilter
+	}
+	p.position++
+
+	path := splitAttributePath(token.text)
+	if len(path) == 0 {
+		return nil, ErrInvalidFilter
+	}
+
+	if next, ok := p.peek(); ok && !next.isString && next.text == "[" {
+		p.position++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &valuePathExpression{path: path, filter: inner}, nil
+	}
+
+	operatorToken, ok := p.peek()
+	if !ok || operatorToken.isString {
+		return nil, ErrInvalidFilter
+	}
+	p.position++
+
+	operator := strings.ToLower(operatorToken.text)
+	if operator == "pr" {
+		return &attributeExpression{path: path, operator: operator}, nil
+	}
+
+	switch operator {
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, ErrInvalidFilter
+	}
+
+	valueToken, ok := p.peek()
+	if !ok {
+		return nil, ErrInvalidFilter
+	}
+	p.position++
+
+	value, err := parseFilterValue(valueToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attributeExpression{path: path, operator: operator, value: value}, nil
+}
+
+// parseFilterValue converts a comparison value token into a string, bool, number or nil
+func parseFilterValue(token filterToken) (interface{}, error) {
+	if token.isString {
+		return token.text, nil
+	}
+
+	switch strings.ToLower(token.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(token.text, 64)
+	if err != nil {
+		return nil, ErrInvalidFilter
+	}
+	return number, nil
+}
+
+// splitAttributePath strips any core schema URN prefix from an attribute path and
+// splits it into its attribute and sub-attribute names
+func splitAttributePath(path string) []string {
+	trimmedPath := strings.TrimSpace(path)
+	lowerPath := strings.ToLower(trimmedPath)
+
+	for _, schema := range []string{SchemaUser, SchemaGroup} {
+		prefix := strings.ToLower(schema) + ":"
+		if strings.HasPrefix(lowerPath, prefix) {
+			trimmedPath = trimmedPath[len(prefix):]
+			break
+		}
+	}
+
+	if trimmedPath == "" {
+		return nil
+	}
+
+	segments := strings.Split(trimmedPath, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil
+		}
+	}
+
+	return segments
+}
+
+// resolveAttribute returns every value found at the attribute path, flattening
+// multi-valued attributes along the way
+func resolveAttribute(resource map[string]interface{}, path []string) []interface{} {
+	current := []interface{}{resource}
+
+	for _, segment := range path {
+		next := []interface{}{}
+		for _, item := range current {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			value := lookupKey(entry, segment)
+			if values, ok := value.([]interface{}); ok {
+				next = append(next, values...)
+				continue
+			}
+			if value != nil {
+				next = append(next, value)
+			}
+		}
+		current = next
+	}
+
+	return current
+}
+
+// lookupKey returns the value of a key matched case-insensitively, as SCIM attribute
+// names are case-insensitive
+func lookupKey(entry map[string]interface{}, key string) interface{} {
+	if value, ok := entry[key]; ok {
+		return value
+	}
+
+	for existingKey, value := range entry {
+		if strings.EqualFold(existingKey, key) {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// isPresent reports whether a value counts as present for the "pr" operator
+func isPresent(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case nil:
+		return false
+	case string:
+		return typedValue != ""
+	case []interface{}:
+		return len(typedValue) > 0
+	case map[string]interface{}:
+		return len(typedValue) > 0
+	}
+	return true
+}
+
+// compareFilterValues compares a resource value against a filter value. Strings compare
+// case-insensitively, booleans only support equality
+func compareFilterValues(resourceValue interface{}, operator string, filterValue interface{}) bool {
+	switch typedFilterValue := filterValue.(type) {
+	case string:
+		resourceString, ok := resourceValue.(string)
+		if !ok {
+			return false
+		}
+
+		left := strings.ToLower(resourceString)
+		right := strings.ToLower(typedFilterValue)
+
+		switch operator {
+		case "eq":
+			return left == right
+		case "co":
+			return strings.Contains(left, right)
+		case "sw":
+			return strings.HasPrefix(left, right)
+		case "ew":
+			return strings.HasSuffix(left, right)
+		case "gt":
+			return left > right
+		case "ge":
+			return left >= right
+		case "lt":
+			return left < right
+		case "le":
+			return left <= right
+		}
+	case bool:
+		resourceBool, ok := resourceValue.(bool)
+		return ok && operator == "eq" && resourceBool == typedFilterValue
+	case float64:
+		resourceNumber, ok := resourceValue.(float64)
+		if !ok {
+			return false
+		}
+
+		switch operator {
+		case "eq":
+			return resourceNumber == typedFilterValue
+		case "gt":
+			return resourceNumber > typedFilterValue
+		case "ge":
+			return resourceNumber >= typedFilterValue
+		case "lt":
+			return resourceNumber < typedFilterValue
+		case "le":
+			return resourceNumber <= typedFilterValue
+		}
+	}
+
+	return false
+}
+
+// toResourceMap converts a SCIM resource into the JSON map form filters and patches work on
+func toResourceMap(resource interface{}) (map[string]interface{}, error) {
+	resourceAsJSON, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceMap := map[string]interface{}{}
+	if err := json.Unmarshal(resourceAsJSON, &resourceMap); err != nil {
+		return nil, err
+	}
+
+	return resourceMap, nil
+}
+
+// fromResourceMap converts a resource's JSON map form back into a SCIM resource
+func fromResourceMap(resourceMap map[string]interface{}, resource interface{}) error {
+	resourceAsJSON, err := json.Marshal(resourceMap)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(resourceAsJSON, resource)
+}
+
+// equalityFilterValue returns the value of a filter that is a single `eq` comparison on the
+// given attribute, so callers can look the resource up directly instead of scanning
+func equalityFilterValue(expression filterExpression, attribute string) (string, bool) {
+	comparison, ok := expression.(*attributeExpression)
+	if !ok || comparison.operator != "eq" || len(comparison.path) != 1 || !strings.EqualFold(comparison.path[0], attribute) {
+		return "", false
+	}
+
+	value, ok := comparison.value.(string)
+	return value, ok
+}
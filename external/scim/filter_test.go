@@ -0,0 +1,91 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	active := true
+	user := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          "user-1",
+		ExternalID:  "00u1abc",
+		UserName:    "Jane.Doe@example.com",
+		DisplayName: "Jane Doe",
+		Name:        &Name{GivenName: "Jane", FamilyName: "Doe"},
+		Emails: []MultiValuedAttribute{
+			{Value: "jane.doe@example.com", Type: "work", Primary: true},
+			{Value: "jane@home.example", Type: "home"},
+		},
+		Active: &active,
+		Meta:   &Meta{Created: "2026-01-02T10:00:00Z"},
+	}
+
+	resource, err := toResourceMap(user)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		filter        string
+		expectMatch   bool
+		expectedError error
+	}{
+		{name: "Success - equality is case-insensitive on values", filter: `userName eq "jane.doe@example.com"`, expectMatch: true},
+		{name: "Success - attribute names are case-insensitive", filter: `USERNAME Eq "jane.doe@example.com"`, expectMatch: true},
+		{name: "Success - schema URN prefixed attribute", filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "jane.doe@example.com"`, expectMatch: true},
+		{name: "Success - not equal", filter: `externalId ne "00u1abc"`, expectMatch: false},
+		{name: "Success - sub-attribute", filter: `name.familyName sw "do"`, expectMatch: true},
+		{name: "Success - multi-valued attribute compares each value", filter: `emails co "home"`, expectMatch: true},
+		{name: "Success - value path filter", filter: `emails[type eq "work" and value ew "@example.com"]`, expectMatch: true},
+		{name: "Success - value path filter without a match", filter: `emails[type eq "other"]`, expectMatch: false},
+		{name: "Success - boolean", filter: `active eq true`, expectMatch: true},
+		{name: "Success - present", filter: `externalId pr`, expectMatch: true},
+		{name: "Success - absent attribute is not present", filter: `nickName pr`, expectMatch: false},
+		{name: "Success - date comparison", filter: `meta.created gt "2026-01-01T00:00:00Z"`, expectMatch: true},
+		{name: "Success - and binds tighter than or", filter: `userName eq "nobody" or displayName eq "Jane Doe" and active eq true`, expectMatch: true},
+		{name: "Success - parentheses and not", filter: `not (userName eq "nobody" or active eq false)`, expectMatch: true},
+		{name: "Success - escaped quotes in literals", filter: `displayName ne "Jane \"JD\" Doe"`, expectMatch: true},
+		{name: "Failed - unknown operator", filter: `userName like "jane"`, expectedError: ErrInvalidFilter},
+		{name: "Failed - unterminated string", filter: `userName eq "jane`, expectedError: ErrInvalidFilter},
+		{name: "Failed - trailing tokens", filter: `userName eq "jane" active`, expectedError: ErrInvalidFilter},
+		{name: "Failed - unbalanced parentheses", filter: `(userName eq "jane"`, expectedError: ErrInvalidFilter},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := parseFilter(test.filter)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectMatch, expression.matches(resource))
+		})
+	}
+}
+
+func TestEqualityFilterValue(t *testing.T) {
+	t.Parallel()
+
+	expression, err := parseFilter(`userName eq "jane@example.com"`)
+	require.NoError(t, err)
+
+	value, ok := equalityFilterValue(expression, "username")
+	assert.True(t, ok)
+	assert.Equal(t, "jane@example.com", value)
+
+	expression, err = parseFilter(`userName eq "jane@example.com" and active eq true`)
+	require.NoError(t, err)
+
+	_, ok = equalityFilterValue(expression, "userName")
+	assert.False(t, ok)
+}
@@ -0,0 +1,499 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ooaklee/ghatd/external/errormanifest"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"github.com/ooaklee/reply/v2"
+	"go.uber.org/zap"
+)
+
+// SCIMService expected methods of a valid SCIM service
+type SCIMService interface {
+	IssueToken(ctx context.Context, r *IssueTokenRequest) (*IssueTokenResponse, error)
+	GetTokens(ctx context.Context, r *GetTokensRequest) (*GetTokensResponse, error)
+	RevokeToken(ctx context.Context, r *RevokeTokenRequest) error
+	Authenticate(ctx context.Context, r *AuthenticateRequest) (*AuthenticateResponse, error)
+	GetUsers(ctx context.Context, r *GetUsersRequest) (*ListResponse, error)
+	GetUser(ctx context.Context, r *GetUserRequest) (*UserResponse, error)
+	CreateUser(ctx context.Context, r *CreateUserRequest) (*UserResponse, error)
+	ReplaceUser(ctx context.Context, r *ReplaceUserRequest) (*UserResponse, error)
+	PatchUser(ctx context.Context, r *PatchUserRequest) (*UserResponse, error)
+	DeleteUser(ctx context.Context, r *DeleteUserRequest) error
+	GetGroups(ctx context.Context, r *GetGroupsRequest) (*ListResponse, error)
+	GetGroup(ctx context.Context, r *GetGroupRequest) (*GroupResponse, error)
+	CreateGroup(ctx context.Context, r *CreateGroupRequest) (*GroupResponse, error)
+	ReplaceGroup(ctx context.Context, r *ReplaceGroupRequest) (*GroupResponse, error)
+	PatchGroup(ctx context.Context, r *PatchGroupRequest) (*GroupResponse, error)
+	DeleteGroup(ctx context.Context, r *DeleteGroupRequest) error
+	GetServiceProviderConfig() *ServiceProviderConfig
+	GetSchemas() *ListResponse
+	GetSchema(r *GetSchemaRequest) (*Schema, error)
+	GetResourceTypes() *ListResponse
+	GetResourceType(r *GetResourceTypeRequest) (*ResourceType, error)
+}
+
+// SCIMValidator expected methods of a valid validator
+type SCIMValidator interface {
+	Validate(s interface{}) error
+}
+
+// Handler manages SCIM requests
+type Handler struct {
+	Service   SCIMService
+	Validator SCIMValidator
+	ErrorMaps []reply.ErrorManifest
+}
+
+// NewHandler returns a new SCIM handler
+func NewHandler(service SCIMService, validator SCIMValidator, errorMaps ...reply.ErrorManifest) *Handler {
+	return &Handler{
+		Service:   service,
+		Validator: validator,
+		ErrorMaps: errorMaps,
+	}
+}
+
+// IssueToken handles issuing a SCIM token for an organisation
+func (h *Handler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-issue-token")
+
+	request, err := MapRequestToIssueTokenRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.IssueToken(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.Token)
+}
+
+// GetTokens handles listing an organisation's SCIM tokens
+func (h *Handler) GetTokens(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-get-tokens")
+
+	request, err := MapRequestToGetTokensRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetTokens(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Tokens)
+}
+
+// RevokeToken handles revoking one of an organisation's SCIM tokens
+func (h *Handler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-revoke-token")
+
+	request, err := MapRequestToRevokeTokenRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if err := h.Service.RevokeToken(r.Context(), request); err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.getBaseResponseHandler().NewHTTPBlankResponse(w, http.StatusOK)
+}
+
+// BearerTokenMiddleware authenticates SCIM requests by their bearer token and carries the
+// organisation the token was issued for in the request context
+func (h *Handler) BearerTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "authenticate-scim-request")
+
+		request, err := MapRequestToAuthenticateRequest(r)
+		if err != nil {
+			logger.Debug("scim-request-missing-bearer-token")
+			h.writeSCIMError(w, err)
+			return
+		}
+
+		response, err := h.Service.Authenticate(r.Context(), request)
+		if err != nil {
+			logger.Warn("scim-request-unauthorised", zap.Error(err))
+			h.writeSCIMError(w, err)
+			return
+		}
+
+		ctx := toolbox.TransitWithCtxByKey[string](r.Context(), CtxKeyOrganisationID, response.OrganisationID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetServiceProviderConfig handles describing the supported SCIM features
+func (h *Handler) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	h.writeSCIMResponse(w, http.StatusOK, h.Service.GetServiceProviderConfig())
+}
+
+// GetSchemas handles listing the supported resource schemas
+func (h *Handler) GetSchemas(w http.ResponseWriter, r *http.Request) {
+	h.writeSCIMResponse(w, http.StatusOK, h.Service.GetSchemas())
+}
+
+// GetSchema handles getting a resource schema by its URN
+func (h *Handler) GetSchema(w http.ResponseWriter, r *http.Request) {
+	request, err := MapRequestToGetSchemaRequest(r)
+	if err != nil {
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.GetSchema(request)
+	if err != nil {
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response)
+}
+
+// GetResourceTypes handles listing the supported resource types
+func (h *Handler) GetResourceTypes(w http.ResponseWriter, r *http.Request) {
+	h.writeSCIMResponse(w, http.StatusOK, h.Service.GetResourceTypes())
+}
+
+// GetResourceType handles getting a resource type by its name
+func (h *Handler) GetResourceType(w http.ResponseWriter, r *http.Request) {
+	request, err := MapRequestToGetResourceTypeRequest(r)
+	if err != nil {
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.GetResourceType(request)
+	if err != nil {
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response)
+}
+
+// GetUsers handles querying the organisation's users
+func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-get-users")
+
+	request, err := MapRequestToGetUsersRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.GetUsers(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response)
+}
+
+// GetUser handles getting one of the organisation's users
+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-get-user")
+
+	request, err := MapRequestToGetUserRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.GetUser(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response.User)
+}
+
+// CreateUser handles provisioning a user into the organisation
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-create-user")
+
+	request, err := MapRequestToCreateUserRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.CreateUser(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusCreated, response.User)
+}
+
+// ReplaceUser handles replacing one of the organisation's users
+func (h *Handler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-replace-user")
+
+	request, err := MapRequestToReplaceUserRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.ReplaceUser(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response.User)
+}
+
+// PatchUser handles patching one of the organisation's users
+func (h *Handler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-patch-user")
+
+	request, err := MapRequestToPatchUserRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.PatchUser(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response.User)
+}
+
+// DeleteUser handles deprovisioning a user from the organisation
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-delete-user")
+
+	request, err := MapRequestToDeleteUserRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	if err := h.Service.DeleteUser(r.Context(), request); err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetGroups handles querying the organisation's groups
+func (h *Handler) GetGroups(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-get-groups")
+
+	request, err := MapRequestToGetGroupsRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.GetGroups(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response)
+}
+
+// GetGroup handles getting one of the organisation's groups
+func (h *Handler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-get-group")
+
+	request, err := MapRequestToGetGroupRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.GetGroup(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response.Group)
+}
+
+// CreateGroup handles creating a group in the organisation
+func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-create-group")
+
+	request, err := MapRequestToCreateGroupRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.CreateGroup(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusCreated, response.Group)
+}
+
+// ReplaceGroup handles replacing one of the organisation's groups
+func (h *Handler) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-replace-group")
+
+	request, err := MapRequestToReplaceGroupRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.ReplaceGroup(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response.Group)
+}
+
+// PatchGroup handles patching one of the organisation's groups
+func (h *Handler) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-patch-group")
+
+	request, err := MapRequestToPatchGroupRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	response, err := h.Service.PatchGroup(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	h.writeSCIMResponse(w, http.StatusOK, response.Group)
+}
+
+// DeleteGroup handles deleting one of the organisation's groups
+func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/scim", "handle-delete-group")
+
+	request, err := MapRequestToDeleteGroupRequest(r)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	if err := h.Service.DeleteGroup(r.Context(), request); err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.writeSCIMError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeSCIMResponse writes a SCIM resource as application/scim+json
+func (h *Handler) writeSCIMResponse(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeSCIMError writes an error in the SCIM error format, taking the status and detail from
+// the handler's error manifests so hosts can override them as they do for other packages
+func (h *Handler) writeSCIMError(w http.ResponseWriter, err error) {
+	errorResponse := &ErrorResponse{
+		Schemas: []string{SchemaError},
+		Status:  strconv.Itoa(http.StatusInternalServerError),
+		Detail:  "An unexpected error occurred",
+	}
+
+	// later manifests override earlier ones
+	for _, manifest := range h.getErrorManifests() {
+		for manifestErr, item := range manifest {
+			if !errors.Is(err, manifestErr) {
+				continue
+			}
+
+			errorResponse.Status = strconv.Itoa(item.StatusCode)
+			errorResponse.Detail = item.Detail
+		}
+	}
+
+	for scimTypeErr, scimType := range scimTypeByError {
+		if errors.Is(err, scimTypeErr) {
+			errorResponse.ScimType = scimType
+			break
+		}
+	}
+
+	statusCode, _ := strconv.Atoi(errorResponse.Status)
+	h.writeSCIMResponse(w, statusCode, errorResponse)
+}
+
+// getErrorManifests returns the SCIM error map followed by the handler's overrides
+func (h *Handler) getErrorManifests() []reply.ErrorManifest {
+	return errormanifest.NewComposer().
+		Add(SCIMErrorMap).
+		AddOverrides(h.ErrorMaps...).
+		Build()
+}
+
+// getBaseResponseHandler returns response handler configured with SCIM error maps
+func (h *Handler) getBaseResponseHandler() *reply.Replier {
+	return reply.NewReplier(h.getErrorManifests())
+}
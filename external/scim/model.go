@@ -0,0 +1,216 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Config holds configuration for how SCIM resources map onto users and groups
+type Config struct {
+	// BaseURL is the public URL the SCIM API is served from, e.g. "https://app.example.com/scim/v2".
+	// It is used to build resource locations and left out of responses when empty
+	BaseURL string
+
+	// GroupType is the group type given to groups the identity provider creates. It must be
+	// allowed under the organisation's group type by the group hierarchy tree
+	GroupType string
+
+	// MemberRole is the role provisioned users and group members are given
+	MemberRole string
+
+	// UserType is the user configuration type provisioned users are created with, the user
+	// service's default is used when empty
+	UserType string
+}
+
+// Meta holds the resource metadata returned with every SCIM resource
+type Meta struct {
+	ResourceType string `json:"resourceType,omitempty"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// Name holds the components of a user's name
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+// MultiValuedAttribute is an entry of a multi-valued attribute such as emails or phoneNumbers
+type MultiValuedAttribute struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// User is a SCIM user resource. The userName is the user's email address
+type User struct {
+	Schemas      []string               `json:"schemas"`
+	ID           string                 `json:"id,omitempty"`
+	ExternalID   string                 `json:"externalId,omitempty"`
+	UserName     string                 `json:"userName"`
+	Name         *Name                  `json:"name,omitempty"`
+	DisplayName  string                 `json:"displayName,omitempty"`
+	Emails       []MultiValuedAttribute `json:"emails,omitempty"`
+	PhoneNumbers []MultiValuedAttribute `json:"phoneNumbers,omitempty"`
+	Active       *bool                  `json:"active,omitempty"`
+	Meta         *Meta                  `json:"meta,omitempty"`
+}
+
+// IsActive returns whether the user should be active, users are active unless told otherwise
+func (u *User) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+// PrimaryEmail returns the user's primary email, falling back to the first email and then
+// the userName
+func (u *User) PrimaryEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary && strings.TrimSpace(email.Value) != "" {
+			return strings.TrimSpace(email.Value)
+		}
+	}
+
+	for _, email := range u.Emails {
+		if strings.TrimSpace(email.Value) != "" {
+			return strings.TrimSpace(email.Value)
+		}
+	}
+
+	return strings.TrimSpace(u.UserName)
+}
+
+// PrimaryPhoneNumber returns the user's primary phone number, falling back to the first one
+func (u *User) PrimaryPhoneNumber() string {
+	for _, phoneNumber := range u.PhoneNumbers {
+		if phoneNumber.Primary {
+			return strings.TrimSpace(phoneNumber.Value)
+		}
+	}
+
+	if len(u.PhoneNumbers) > 0 {
+		return strings.TrimSpace(u.PhoneNumbers[0].Value)
+	}
+
+	return ""
+}
+
+// GroupMember is a member of a SCIM group
+type GroupMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// Group is a SCIM group resource, backed by a group nested under the organisation
+type Group struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []GroupMember `json:"members,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+// ListResponse is the SCIM envelope for query results
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// PatchOperation is a single operation of a SCIM PATCH request
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ErrorResponse is the SCIM error envelope
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// Supported reports whether a service provider feature is supported
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+// BulkSupport describes the service provider's bulk operation support
+type BulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+// FilterSupport describes the service provider's filtering support
+type FilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// AuthenticationScheme describes how clients authenticate with the service provider
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary,omitempty"`
+}
+
+// ServiceProviderConfig describes the SCIM features the service provider supports
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	DocumentationURI      string                 `json:"documentationUri,omitempty"`
+	Patch                 Supported              `json:"patch"`
+	Bulk                  BulkSupport            `json:"bulk"`
+	Filter                FilterSupport          `json:"filter"`
+	ChangePassword        Supported              `json:"changePassword"`
+	Sort                  Supported              `json:"sort"`
+	ETag                  Supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  *Meta                  `json:"meta,omitempty"`
+}
+
+// SchemaAttribute describes an attribute of a resource schema
+type SchemaAttribute struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type"`
+	MultiValued   bool              `json:"multiValued"`
+	Description   string            `json:"description,omitempty"`
+	Required      bool              `json:"required"`
+	CaseExact     bool              `json:"caseExact"`
+	Mutability    string            `json:"mutability"`
+	Returned      string            `json:"returned"`
+	Uniqueness    string            `json:"uniqueness"`
+	SubAttributes []SchemaAttribute `json:"subAttributes,omitempty"`
+}
+
+// Schema describes the attributes of a resource type
+type Schema struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Attributes  []SchemaAttribute `json:"attributes"`
+	Meta        *Meta             `json:"meta,omitempty"`
+}
+
+// ResourceType describes a resource type the service provider serves
+type ResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description,omitempty"`
+	Schema      string   `json:"schema"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
@@ -0,0 +1,387 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// readOnlyAttributes are attributes PATCH operations cannot change
+var readOnlyAttributes = []string{"id", "meta", "schemas"}
+
+// patchPath is a parsed PATCH path such as `displayName`, `name.givenName`,
+// `members[value eq "2819c223"]` or `emails[type eq "work"].value`
+type patchPath struct {
+	attribute    string
+	filter       filterExpression
+	subAttribute string
+}
+
+// parsePatchPath parses a PATCH path into its attribute, value filter and sub-attribute
+func parsePatchPath(path string) (*patchPath, error) {
+	trimmedPath := strings.TrimSpace(path)
+
+	openIndex := strings.Index(trimmedPath, "[")
+	if openIndex < 0 {
+		segments := splitAttributePath(trimmedPath)
+		if len(segments) == 0 || len(segments) > 2 {
+			return nil, ErrInvalidPath
+		}
+
+		parsedPath := &patchPath{attribute: segments[0]}
+		if len(segments) == 2 {
+			parsedPath.subAttribute = segments[1]
+		}
+		return parsedPath, nil
+	}
+
+	closeIndex := strings.LastIndex(trimmedPath, "]")
+	if closeIndex < openIndex {
+		return nil, ErrInvalidPath
+	}
+
+	segments := splitAttributePath(trimmedPath[:openIndex])
+	if len(segments) != 1 {
+		return nil, ErrInvalidPath
+	}
+
+	filter, err := parseFilter(trimmedPath[openIndex+1 : closeIndex])
+	if err != nil {
+		return nil, ErrInvalidPath
+	}
+
+	parsedPath := &patchPath{attribute: segments[0], filter: filter}
+
+	remainder := trimmedPath[closeIndex+1:]
+	if remainder != "" {
+		if !strings.HasPrefix(remainder, ".") || len(remainder) == 1 || strings.Contains(remainder[1:], ".") {
+			return nil, ErrInvalidPath
+		}
+		parsedPath.subAttribute = remainder[1:]
+	}
+
+	return parsedPath, nil
+}
+
+// applyPatchOperations applies SCIM PATCH operations to a resource in its JSON map form
+func applyPatchOperations(resource map[string]interface{}, operations []PatchOperation) error {
+	if len(operations) == 0 {
+		return ErrInvalidValue
+	}
+
+	for _, operation := range operations {
+		op := strings.ToLower(strings.TrimSpace(operation.Op))
+		if op != PatchOpAdd && op != PatchOpReplace && op != PatchOpRemove {
+			return ErrInvalidSyntax
+		}
+
+		var value interface{}
+		if len(operation.Value) > 0 {
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				return ErrInvalidSyntax
+			}
+		}
+
+		if strings.TrimSpace(operation.Path) == "" {
+			if op == PatchOpRemove {
+				return ErrNoTarget
+			}
+
+			attributes, ok := value.(map[string]interface{})
+			if !ok {
+				return ErrInvalidValue
+			}
+
+			// identity providers resend read-only attributes in path-less operations, so they are skipped
+			for attribute, attributeValue := range attributes {
+				path, err := parsePatchPath(attribute)
+				if err != nil {
+					return err
+				}
+
+				if isReadOnlyAttribute(path.attribute) {
+					continue
+				}
+
+				if err := applyPatchPath(resource, op, path, attributeValue); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		path, err := parsePatchPath(operation.Path)
+		if err != nil {
+			return err
+		}
+
+		if isReadOnlyAttribute(path.attribute) {
+			return ErrMutability
+		}
+
+		if op != PatchOpRemove && value == nil {
+			return ErrInvalidValue
+		}
+
+		if err := applyPatchPath(resource, op, path, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPatchPath applies a single operation at a parsed path
+func applyPatchPath(resource map[string]interface{}, op string, path *patchPath, value interface{}) error {
+	key := resolveKey(resource, path.attribute)
+	existing := resource[key]
+
+	if path.filter != nil {
+		return applyFilteredPatchPath(resource, key, op, path, value)
+	}
+
+	if path.subAttribute != "" {
+		if existing != nil {
+			if _, ok := existing.(map[string]interface{}); !ok {
+				return ErrInvalidPath
+			}
+		}
+
+		complexValue, _ := existing.(map[string]interface{})
+		if complexValue == nil {
+			if op == PatchOpRemove {
+				return nil
+			}
+			complexValue = map[string]interface{}{}
+		}
+
+		subKey := resolveKey(complexValue, path.subAttribute)
+		if op == PatchOpRemove {
+			delete(complexValue, subKey)
+		} else {
+			complexValue[subKey] = value
+		}
+		resource[key] = complexValue
+		return nil
+	}
+
+	switch op {
+	case PatchOpRemove:
+		existingValues, isMultiValued := existing.([]interface{})
+		if !isMultiValued || value == nil {
+			delete(resource, key)
+			return nil
+		}
+
+		// some identity providers name the entries to remove in the value instead of a path filter
+		resource[key] = withoutEntries(existingValues, valuesOf(value))
+	case PatchOpAdd:
+		if existingValues, isMultiValued := existing.([]interface{}); isMultiValued {
+			resource[key] = withEntries(existingValues, asSlice(value))
+			return nil
+		}
+		resource[key] = mergeComplexValue(existing, value)
+	case PatchOpReplace:
+		resource[key] = mergeComplexValue(existing, value)
+	}
+
+	return nil
+}
+
+// applyFilteredPatchPath applies an operation to the entries of a multi-valued attribute
+// matched by the path's value filter
+func applyFilteredPatchPath(resource map[string]interface{}, key, op string, path *patchPath, value interface{}) error {
+	var entries []interface{}
+	if existing := resource[key]; existing != nil {
+		existingValues, ok := existing.([]interface{})
+		if !ok {
+			return ErrInvalidPath
+		}
+		entries = existingValues
+	}
+
+	matched := false
+	updatedEntries := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		complexValue, ok := entry.(map[string]interface{})
+		if !ok || !path.filter.matches(complexValue) {
+			updatedEntries = append(updatedEntries, entry)
+			continue
+		}
+		matched = true
+
+		switch {
+		case op == PatchOpRemove && path.subAttribute == "":
+			continue
+		case op == PatchOpRemove:
+			delete(complexValue, resolveKey(complexValue, path.subAttribute))
+		case path.subAttribute != "":
+			complexValue[resolveKey(complexValue, path.subAttribute)] = value
+		default:
+			entry = mergeComplexValue(complexValue, value)
+		}
+
+		updatedEntries = append(updatedEntries, entry)
+	}
+
+	if !matched && op != PatchOpRemove {
+		// e.g. `emails[type eq "work"].value` on a user without a work email adds one
+		newEntry := entryFromFilter(path.filter)
+		if newEntry == nil {
+			return ErrNoTarget
+		}
+
+		if path.subAttribute != "" {
+			newEntry[path.subAttribute] = value
+		} else if complexValue, ok := value.(map[string]interface{}); ok {
+			for subKey, subValue := range complexValue {
+				newEntry[subKey] = subValue
+			}
+		} else {
+			return ErrInvalidValue
+		}
+
+		updatedEntries = append(updatedEntries, newEntry)
+	}
+
+	resource[key] = updatedEntries
+	return nil
+}
+
+// entryFromFilter builds a new multi-valued entry from a filter made only of `eq`
+// comparisons joined by "and", returning nil for any other filter
+func entryFromFilter(filter filterExpression) map[string]interface{} {
+	switch expression := filter.(type) {
+	case *attributeExpression:
+		if expression.operator != "eq" || len(expression.path) != 1 {
+			return nil
+		}
+		return map[string]interface{}{expression.path[0]: expression.value}
+	case *logicalExpression:
+		if expression.operator != "and" {
+			return nil
+		}
+
+		left := entryFromFilter(expression.left)
+		right := entryFromFilter(expression.right)
+		if left == nil || right == nil {
+			return nil
+		}
+
+		for key, value := range right {
+			left[key] = value
+		}
+		return left
+	}
+
+	return nil
+}
+
+// resolveKey returns the existing key matching the attribute case-insensitively, or the
+// attribute itself when the resource does not have it yet
+func resolveKey(resource map[string]interface{}, attribute string) string {
+	if _, ok := resource[attribute]; ok {
+		return attribute
+	}
+
+	for existingKey := range resource {
+		if strings.EqualFold(existingKey, attribute) {
+			return existingKey
+		}
+	}
+
+	return attribute
+}
+
+// isReadOnlyAttribute reports whether the attribute cannot be changed by PATCH operations
+func isReadOnlyAttribute(attribute string) bool {
+	for _, readOnlyAttribute := range readOnlyAttributes {
+		if strings.EqualFold(readOnlyAttribute, attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeComplexValue merges complex values sub-attribute by sub-attribute and otherwise
+// returns the new value
+func mergeComplexValue(existing, value interface{}) interface{} {
+	existingComplexValue, existingIsComplex := existing.(map[string]interface{})
+	newComplexValue, newIsComplex := value.(map[string]interface{})
+	if !existingIsComplex || !newIsComplex {
+		return value
+	}
+
+	for subKey, subValue := range newComplexValue {
+		existingComplexValue[resolveKey(existingComplexValue, subKey)] = subValue
+	}
+	return existingComplexValue
+}
+
+// asSlice wraps a single value in a slice so it can be added to a multi-valued attribute
+func asSlice(value interface{}) []interface{} {
+	if values, ok := value.([]interface{}); ok {
+		return values
+	}
+	return []interface{}{value}
+}
+
+// valuesOf returns the "value" of each entry given, or the entries themselves when they
+// are not complex
+func valuesOf(value interface{}) []interface{} {
+	values := []interface{}{}
+	for _, entry := range asSlice(value) {
+		values = append(values, entryValueOf(entry))
+	}
+	return values
+}
+
+// entryValueOf returns the "value" of a multi-valued entry, or the entry itself when it is
+// not complex
+func entryValueOf(entry interface{}) interface{} {
+	if complexValue, ok := entry.(map[string]interface{}); ok {
+		return lookupKey(complexValue, "value")
+	}
+	return entry
+}
+
+// withEntries appends entries whose "value" is not already present
+func withEntries(existing []interface{}, entries []interface{}) []interface{} {
+	presentValues := valuesOf(existing)
+
+	for _, entry := range entries {
+		entryValue := entryValueOf(entry)
+		if entryValue != nil && containsValue(presentValues, entryValue) {
+			continue
+		}
+
+		existing = append(existing, entry)
+		presentValues = append(presentValues, entryValue)
+	}
+
+	return existing
+}
+
+// withoutEntries removes entries whose "value" is one of the given values
+func withoutEntries(existing []interface{}, values []interface{}) []interface{} {
+	remaining := make([]interface{}, 0, len(existing))
+	for _, entry := range existing {
+		if containsValue(values, entryValueOf(entry)) {
+			continue
+		}
+		remaining = append(remaining, entry)
+	}
+	return remaining
+}
+
+// containsValue reports whether value is in values
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		switch candidate.(type) {
+		case string, bool, float64:
+			if candidate == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatchOperations(t *testing.T) {
+	t.Parallel()
+
+	newUserResource := func(t *testing.T) map[string]interface{} {
+		active := true
+		resource, err := toResourceMap(&User{
+			Schemas:  []string{SchemaUser},
+			ID:       "user-1",
+			UserName: "jane@example.com",
+			Name:     &Name{GivenName: "Jane", FamilyName: "Doe"},
+			Emails: []MultiValuedAttribute{
+				{Value: "jane@example.com", Type: "work", Primary: true},
+			},
+			Active: &active,
+		})
+		require.NoError(t, err)
+		return resource
+	}
+
+	newGroupResource := func(t *testing.T) map[string]interface{} {
+		resource, err := toResourceMap(&Group{
+			Schemas:     []string{SchemaGroup},
+			ID:          "group-1",
+			DisplayName: "Engineering",
+			Members: []GroupMember{
+				{Value: "user-1", Type: ResourceTypeUser},
+				{Value: "user-2", Type: ResourceTypeUser},
+			},
+		})
+		require.NoError(t, err)
+		return resource
+	}
+
+	operation := func(op, path, value string) PatchOperation {
+		patchOperation := PatchOperation{Op: op, Path: path}
+		if value != "" {
+			patchOperation.Value = json.RawMessage(value)
+		}
+		return patchOperation
+	}
+
+	tests := []struct {
+		name          string
+		resource      func(t *testing.T) map[string]interface{}
+		operations    []PatchOperation
+		assertResult  func(t *testing.T, resource map[string]interface{})
+		expectedError error
+	}{
+		{
+			name:       "Success - replace a simple attribute with a capitalised op",
+			resource:   newUserResource,
+			operations: []PatchOperation{operation("Replace", "active", "false")},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				assert.Equal(t, false, resource["active"])
+			},
+		},
+		{
+			name:       "Success - path-less replace with dotted keys",
+			resource:   newUserResource,
+			operations: []PatchOperation{operation("replace", "", `{"name.givenName":"Janet","active":false,"id":"ignored"}`)},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				assert.Equal(t, "Janet", resource["name"].(map[string]interface{})["givenName"])
+				assert.Equal(t, "Doe", resource["name"].(map[string]interface{})["familyName"])
+				assert.Equal(t, false, resource["active"])
+				assert.Equal(t, "user-1", resource["id"])
+			},
+		},
+		{
+			name:       "Success - replace a filtered sub-attribute",
+			resource:   newUserResource,
+			operations: []PatchOperation{operation("replace", `emails[type eq "work"].value`, `"janet@example.com"`)},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				emails := resource["emails"].([]interface{})
+				require.Len(t, emails, 1)
+				assert.Equal(t, "janet@example.com", emails[0].(map[string]interface{})["value"])
+			},
+		},
+		{
+			name:       "Success - filtered add creates a missing entry",
+			resource:   newUserResource,
+			operations: []PatchOperation{operation("add", `phoneNumbers[type eq "work"].value`, `"+441234567890"`)},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				phoneNumbers := resource["phoneNumbers"].([]interface{})
+				require.Len(t, phoneNumbers, 1)
+				assert.Equal(t, map[string]interface{}{"type": "work", "value": "+441234567890"}, phoneNumbers[0])
+			},
+		},
+		{
+			name:       "Success - add members without duplicating existing ones",
+			resource:   newGroupResource,
+			operations: []PatchOperation{operation("add", "members", `[{"value":"user-2"},{"value":"user-3"}]`)},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				assert.Equal(t, []interface{}{"user-1", "user-2", "user-3"}, valuesOf(resource["members"]))
+			},
+		},
+		{
+			name:       "Success - remove a member by filter",
+			resource:   newGroupResource,
+			operations: []PatchOperation{operation("remove", `members[value eq "user-1"]`, "")},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				assert.Equal(t, []interface{}{"user-2"}, valuesOf(resource["members"]))
+			},
+		},
+		{
+			name:       "Success - remove members listed in the value",
+			resource:   newGroupResource,
+			operations: []PatchOperation{operation("remove", "members", `[{"value":"user-2"}]`)},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				assert.Equal(t, []interface{}{"user-1"}, valuesOf(resource["members"]))
+			},
+		},
+		{
+			name:       "Success - remove every member",
+			resource:   newGroupResource,
+			operations: []PatchOperation{operation("remove", "members", "")},
+			assertResult: func(t *testing.T, resource map[string]interface{}) {
+				assert.NotContains(t, resource, "members")
+			},
+		},
+		{
+			name:          "Failed - unknown op",
+			resource:      newUserResource,
+			operations:    []PatchOperation{operation("move", "active", "false")},
+			expectedError: ErrInvalidSyntax,
+		},
+		{
+			name:          "Failed - read-only attribute",
+			resource:      newUserResource,
+			operations:    []PatchOperation{operation("replace", "id", `"user-2"`)},
+			expectedError: ErrMutability,
+		},
+		{
+			name:          "Failed - remove without a path",
+			resource:      newUserResource,
+			operations:    []PatchOperation{operation("remove", "", "")},
+			expectedError: ErrNoTarget,
+		},
+		{
+			name:          "Failed - malformed path",
+			resource:      newUserResource,
+			operations:    []PatchOperation{operation("replace", `emails[type eq "work"`, `"x"`)},
+			expectedError: ErrInvalidPath,
+		},
+		{
+			name:          "Failed - no operations",
+			resource:      newUserResource,
+			operations:    []PatchOperation{},
+			expectedError: ErrInvalidValue,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			resource := test.resource(t)
+
+			err := applyPatchOperations(resource, test.operations)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			test.assertResult(t, resource)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package scim
+
+// IssueTokenRequest holds everything needed to issue a SCIM token for an organisation
+type IssueTokenRequest struct {
+	// GroupID is the ID of the organisation's root group
+	GroupID string
+
+	// ActorID is the user issuing the token, they need the scim.manage permission
+	ActorID string
+
+	// Description is the token's description, defaults to TokenDescription
+	Description string `json:"description,omitempty"`
+
+	// TokenTtl is how long the token is valid for in seconds, 0 means forever
+	TokenTtl int64 `json:"token_ttl,omitempty"`
+}
+
+// GetTokensRequest holds everything needed to list an organisation's SCIM tokens
+type GetTokensRequest struct {
+	// GroupID is the ID of the organisation's root group
+	GroupID string
+
+	// ActorID is the user listing the tokens, they need the scim.manage permission
+	ActorID string
+
+	// Status filters tokens by status, e.g. ACTIVE or REVOKED
+	Status string `query:"status"`
+}
+
+// RevokeTokenRequest holds everything needed to revoke one of an organisation's SCIM tokens
+type RevokeTokenRequest struct {
+	// GroupID is the ID of the organisation's root group
+	GroupID string
+
+	// TokenID is the ID of the token to revoke
+	TokenID string
+
+	// ActorID is the user revoking the token, they need the scim.manage permission
+	ActorID string
+}
+
+// AuthenticateRequest holds the bearer token presented by an identity provider
+type AuthenticateRequest struct {
+	// Token is the full `<prefix>.<secret>` SCIM token
+	Token string
+}
+
+// ListResourcesRequest holds the query parameters shared by SCIM list endpoints
+type ListResourcesRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// Filter is a SCIM filter expression, e.g. `userName eq "jane@example.com"`
+	Filter string
+
+	// StartIndex is the 1-based index of the first result, defaults to 1
+	StartIndex int
+
+	// Count is the maximum number of results, defaults to DefaultListCount
+	Count *int
+
+	// ExcludedAttributes lists attributes to leave out, only "members" is honoured
+	ExcludedAttributes string
+}
+
+// GetUsersRequest holds everything needed to query the organisation's users
+type GetUsersRequest struct {
+	ListResourcesRequest
+}
+
+// GetUserRequest holds everything needed to get one of the organisation's users
+type GetUserRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the user's ID
+	ID string
+}
+
+// CreateUserRequest holds everything needed to provision a user into the organisation
+type CreateUserRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// User is the SCIM user to provision
+	User *User
+}
+
+// ReplaceUserRequest holds everything needed to replace one of the organisation's users
+type ReplaceUserRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the user's ID
+	ID string
+
+	// User is the user's new representation
+	User *User
+}
+
+// PatchUserRequest holds everything needed to patch one of the organisation's users
+type PatchUserRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the user's ID
+	ID string
+
+	// Schemas should hold SchemaPatchOp
+	Schemas []string `json:"schemas"`
+
+	// Operations are applied in order
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// DeleteUserRequest holds everything needed to deprovision a user from the organisation
+type DeleteUserRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the user's ID
+	ID string
+}
+
+// GetGroupsRequest holds everything needed to query the organisation's groups
+type GetGroupsRequest struct {
+	ListResourcesRequest
+}
+
+// GetGroupRequest holds everything needed to get one of the organisation's groups
+type GetGroupRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the group's ID
+	ID string
+
+	// ExcludedAttributes lists attributes to leave out, only "members" is honoured
+	ExcludedAttributes string
+}
+
+// CreateGroupRequest holds everything needed to create a group in the organisation
+type CreateGroupRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// Group is the SCIM group to create
+	Group *Group
+}
+
+// ReplaceGroupRequest holds everything needed to replace one of the organisation's groups
+type ReplaceGroupRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the group's ID
+	ID string
+
+	// Group is the group's new representation
+	Group *Group
+}
+
+// PatchGroupRequest holds everything needed to patch one of the organisation's groups
+type PatchGroupRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the group's ID
+	ID string
+
+	// Schemas should hold SchemaPatchOp
+	Schemas []string `json:"schemas"`
+
+	// Operations are applied in order
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// DeleteGroupRequest holds everything needed to delete one of the organisation's groups
+type DeleteGroupRequest struct {
+	// OrganisationID is the ID of the authenticated organisation
+	OrganisationID string
+
+	// ID is the group's ID
+	ID string
+}
+
+// GetSchemaRequest holds everything needed to get a schema
+type GetSchemaRequest struct {
+	// ID is the schema URN
+	ID string
+}
+
+// GetResourceTypeRequest holds everything needed to get a resource type
+type GetResourceTypeRequest struct {
+	// ID is the resource type name, e.g. User
+	ID string
+}
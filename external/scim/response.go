@@ -0,0 +1,28 @@
+package scim
+
+import "github.com/ooaklee/ghatd/external/apitoken"
+
+// IssueTokenResponse holds the issued SCIM token, its value is only returned here
+type IssueTokenResponse struct {
+	Token apitoken.UserAPIToken `json:"token"`
+}
+
+// GetTokensResponse holds an organisation's SCIM tokens
+type GetTokensResponse struct {
+	Tokens []apitoken.UserAPIToken `json:"tokens"`
+}
+
+// AuthenticateResponse holds the organisation a SCIM token was issued for
+type AuthenticateResponse struct {
+	OrganisationID string
+}
+
+// UserResponse holds a SCIM user
+type UserResponse struct {
+	User *User
+}
+
+// GroupResponse holds a SCIM group
+type GroupResponse struct {
+	Group *Group
+}
@@ -0,0 +1,98 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ooaklee/ghatd/external/router"
+)
+
+// SCIMHandler expected methods for valid SCIM handler
+type SCIMHandler interface {
+	IssueToken(w http.ResponseWriter, r *http.Request)
+	GetTokens(w http.ResponseWriter, r *http.Request)
+	RevokeToken(w http.ResponseWriter, r *http.Request)
+	BearerTokenMiddleware(next http.Handler) http.Handler
+	GetServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+	GetSchemas(w http.ResponseWriter, r *http.Request)
+	GetSchema(w http.ResponseWriter, r *http.Request)
+	GetResourceTypes(w http.ResponseWriter, r *http.Request)
+	GetResourceType(w http.ResponseWriter, r *http.Request)
+	GetUsers(w http.ResponseWriter, r *http.Request)
+	GetUser(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	ReplaceUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
+	DeleteUser(w http.ResponseWriter, r *http.Request)
+	GetGroups(w http.ResponseWriter, r *http.Request)
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	ReplaceGroup(w http.ResponseWriter, r *http.Request)
+	PatchGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+}
+
+const (
+	// SCIMV2Prefix base URI prefix for all SCIM 2.0 provisioning routes
+	SCIMV2Prefix = "/scim/v2"
+
+	// APISCIMTokensV1Prefix base URI prefix for an organisation's SCIM token routes
+	APISCIMTokensV1Prefix = "/api/v1/groups/{" + SCIMURIVariableGroupID + "}/scim/tokens"
+)
+
+// AttachRoutesRequest holds everything needed to attach SCIM routes to router
+type AttachRoutesRequest struct {
+	// Router main router being served by API
+	Router *router.Router
+
+	// Handler valid SCIM handler
+	Handler SCIMHandler
+
+	// AuthenticatedMiddleware middleware used for the token management routes. The
+	// service checks the requester holds the scim.manage permission in the organisation
+	AuthenticatedMiddleware mux.MiddlewareFunc
+}
+
+// AttachRoutes attaches SCIM handler to corresponding routes on router. Provisioning
+// routes are authenticated by the handler's bearer token middleware
+func AttachRoutes(request *AttachRoutesRequest) {
+	httpRouter := request.Router.GetRouter()
+
+	tokenRoutes := httpRouter.PathPrefix(APISCIMTokensV1Prefix).Subrouter()
+	tokenRoutes.HandleFunc("", request.Handler.IssueToken).Methods(http.MethodPost, http.MethodOptions)
+	tokenRoutes.HandleFunc("", request.Handler.GetTokens).Methods(http.MethodGet, http.MethodOptions)
+	tokenRoutes.HandleFunc("/{"+SCIMURIVariableTokenID+"}", request.Handler.RevokeToken).Methods(http.MethodDelete, http.MethodOptions)
+
+	if request.AuthenticatedMiddleware != nil {
+		tokenRoutes.Use(request.AuthenticatedMiddleware)
+	}
+
+	resourceIDPath := "/{" + SCIMURIVariableResourceID + "}"
+
+	scimRoutes := httpRouter.PathPrefix(SCIMV2Prefix).Subrouter()
+
+	// Discovery
+	scimRoutes.HandleFunc("/ServiceProviderConfig", request.Handler.GetServiceProviderConfig).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/Schemas", request.Handler.GetSchemas).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/Schemas"+resourceIDPath, request.Handler.GetSchema).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/ResourceTypes", request.Handler.GetResourceTypes).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/ResourceTypes"+resourceIDPath, request.Handler.GetResourceType).Methods(http.MethodGet)
+
+	// Users
+	scimRoutes.HandleFunc("/Users", request.Handler.GetUsers).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/Users", request.Handler.CreateUser).Methods(http.MethodPost)
+	scimRoutes.HandleFunc("/Users"+resourceIDPath, request.Handler.GetUser).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/Users"+resourceIDPath, request.Handler.ReplaceUser).Methods(http.MethodPut)
+	scimRoutes.HandleFunc("/Users"+resourceIDPath, request.Handler.PatchUser).Methods(http.MethodPatch)
+	scimRoutes.HandleFunc("/Users"+resourceIDPath, request.Handler.DeleteUser).Methods(http.MethodDelete)
+
+	// Groups
+	scimRoutes.HandleFunc("/Groups", request.Handler.GetGroups).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/Groups", request.Handler.CreateGroup).Methods(http.MethodPost)
+	scimRoutes.HandleFunc("/Groups"+resourceIDPath, request.Handler.GetGroup).Methods(http.MethodGet)
+	scimRoutes.HandleFunc("/Groups"+resourceIDPath, request.Handler.ReplaceGroup).Methods(http.MethodPut)
+	scimRoutes.HandleFunc("/Groups"+resourceIDPath, request.Handler.PatchGroup).Methods(http.MethodPatch)
+	scimRoutes.HandleFunc("/Groups"+resourceIDPath, request.Handler.DeleteGroup).Methods(http.MethodDelete)
+
+	scimRoutes.Use(request.Handler.BearerTokenMiddleware)
+}
@@ -0,0 +1,162 @@
+package scim
+
+import "strings"
+
+// GetServiceProviderConfig returns the SCIM features this service provider supports
+func (s *Service) GetServiceProviderConfig() *ServiceProviderConfig {
+	return &ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProviderConfig},
+		Patch:   Supported{Supported: true},
+		Bulk:    BulkSupport{Supported: false},
+		Filter: FilterSupport{
+			Supported:  true,
+			MaxResults: MaxListCount,
+		},
+		ChangePassword: Supported{Supported: false},
+		Sort:           Supported{Supported: false},
+		ETag:           Supported{Supported: false},
+		AuthenticationSchemes: []AuthenticationScheme{
+			{
+				Type:        "oauthbearertoken",
+				Name:        "OAuth Bearer Token",
+				Description: "Authentication with an organisation's SCIM token sent as a bearer token",
+				Primary:     true,
+			},
+		},
+		Meta: s.discoveryMeta("ServiceProviderConfig", "/ServiceProviderConfig"),
+	}
+}
+
+// GetSchemas returns the schemas of the resources this service provider serves
+func (s *Service) GetSchemas() *ListResponse {
+	schemas := s.schemas()
+	return newListResponse(schemas, len(schemas), 1)
+}
+
+// GetSchema returns the schema with the given URN
+func (s *Service) GetSchema(r *GetSchemaRequest) (*Schema, error) {
+	for _, schema := range s.schemas() {
+		if strings.EqualFold(schema.ID, r.ID) {
+			return schema, nil
+		}
+	}
+
+	return nil, ErrResourceNotFound
+}
+
+// GetResourceTypes returns the resource types this service provider serves
+func (s *Service) GetResourceTypes() *ListResponse {
+	resourceTypes := s.resourceTypes()
+	return newListResponse(resourceTypes, len(resourceTypes), 1)
+}
+
+// GetResourceType returns the resource type with the given name
+func (s *Service) GetResourceType(r *GetResourceTypeRequest) (*ResourceType, error) {
+	for _, resourceType := range s.resourceTypes() {
+		if strings.EqualFold(resourceType.ID, r.ID) {
+			return resourceType, nil
+		}
+	}
+
+	return nil, ErrResourceNotFound
+}
+
+// resourceTypes describes the User and Group resource types
+func (s *Service) resourceTypes() []*ResourceType {
+	return []*ResourceType{
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeUser,
+			Name:        ResourceTypeUser,
+			Endpoint:    "/Users",
+			Description: "Users of the organisation",
+			Schema:      SchemaUser,
+			Meta:        s.discoveryMeta("ResourceType", "/ResourceTypes/"+ResourceTypeUser),
+		},
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeGroup,
+			Name:        ResourceTypeGroup,
+			Endpoint:    "/Groups",
+			Description: "Groups nested under the organisation",
+			Schema:      SchemaGroup,
+			Meta:        s.discoveryMeta("ResourceType", "/ResourceTypes/"+ResourceTypeGroup),
+		},
+	}
+}
+
+// schemas describes the attributes of the User and Group resources that are supported
+func (s *Service) schemas() []*Schema {
+	multiValuedSubAttributes := []SchemaAttribute{
+		stringAttribute("value", "The attribute's value", "readWrite", false),
+		stringAttribute("type", "A label indicating the attribute's function, e.g. work", "readWrite", false),
+		{Name: "primary", Type: "boolean", Description: "Whether this is the primary value", Mutability: "readWrite", Returned: "default", Uniqueness: "none"},
+	}
+
+	return []*Schema{
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaUser,
+			Name:        ResourceTypeUser,
+			Description: "User Account",
+			Attributes: []SchemaAttribute{
+				{Name: "userName", Type: "string", Description: "The user's email address", Required: true, Mutability: "readWrite", Returned: "default", Uniqueness: "server"},
+				stringAttribute("externalId", "The identity provider's identifier for the user", "readWrite", true),
+				{
+					Name: "name", Type: "complex", Description: "The components of the user's name", Mutability: "readWrite", Returned: "default", Uniqueness: "none",
+					SubAttributes: []SchemaAttribute{
+						stringAttribute("formatted", "The user's full name", "readWrite", false),
+						stringAttribute("familyName", "The user's family name", "readWrite", false),
+						stringAttribute("givenName", "The user's given name", "readWrite", false),
+					},
+				},
+				stringAttribute("displayName", "The name of the user, suitable for display", "readWrite", false),
+				{Name: "emails", Type: "complex", MultiValued: true, Description: "Email addresses for the user", Mutability: "readWrite", Returned: "default", Uniqueness: "none", SubAttributes: multiValuedSubAttributes},
+				{Name: "phoneNumbers", Type: "complex", MultiValued: true, Description: "Phone numbers for the user", Mutability: "readWrite", Returned: "default", Uniqueness: "none", SubAttributes: multiValuedSubAttributes},
+				{Name: "active", Type: "boolean", Description: "Whether the user can sign in, false deactivates them", Mutability: "readWrite", Returned: "default", Uniqueness: "none"},
+			},
+			Meta: s.discoveryMeta("Schema", "/Schemas/"+SchemaUser),
+		},
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaGroup,
+			Name:        ResourceTypeGroup,
+			Description: "Group",
+			Attributes: []SchemaAttribute{
+				{Name: "displayName", Type: "string", Description: "The group's name", Required: true, Mutability: "readWrite", Returned: "default", Uniqueness: "server"},
+				stringAttribute("externalId", "The identity provider's identifier for the group", "readWrite", true),
+				{
+					Name: "members", Type: "complex", MultiValued: true, Description: "The group's users and groups", Mutability: "readWrite", Returned: "default", Uniqueness: "none",
+					SubAttributes: []SchemaAttribute{
+						{Name: "value", Type: "string", Description: "The member's ID", Mutability: "immutable", Returned: "default", Uniqueness: "none"},
+						{Name: "$ref", Type: "reference", Description: "The member's URI", Mutability: "immutable", Returned: "default", Uniqueness: "none"},
+						{Name: "type", Type: "string", Description: "User or Group", Mutability: "immutable", Returned: "default", Uniqueness: "none"},
+					},
+				},
+			},
+			Meta: s.discoveryMeta("Schema", "/Schemas/"+SchemaGroup),
+		},
+	}
+}
+
+// discoveryMeta returns the metadata of a discovery resource
+func (s *Service) discoveryMeta(resourceType, path string) *Meta {
+	meta := &Meta{ResourceType: resourceType}
+	if s.Config.BaseURL != "" {
+		meta.Location = s.Config.BaseURL + path
+	}
+	return meta
+}
+
+// stringAttribute describes an optional string attribute
+func stringAttribute(name, description, mutability string, caseExact bool) SchemaAttribute {
+	return SchemaAttribute{
+		Name:        name,
+		Type:        "string",
+		Description: description,
+		CaseExact:   caseExact,
+		Mutability:  mutability,
+		Returned:    "default",
+		Uniqueness:  "none",
+	}
+}
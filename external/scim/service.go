@@ -0,0 +1,338 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/apitoken"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
+)
+
+// UserService expected methods of a valid user service
+type UserService interface {
+	CreateUser(ctx context.Context, req *userv2.CreateUserRequest) (*userv2.CreateUserResponse, error)
+	GetUserByID(ctx context.Context, req *userv2.GetUserByIDRequest) (*userv2.GetUserByIDResponse, error)
+	FindUserByEmail(ctx context.Context, req *userv2.GetUserByEmailRequest) (*userv2.GetUserByEmailResponse, error)
+	GetUsers(ctx context.Context, req *userv2.GetUsersRequest) (*userv2.GetUsersResponse, error)
+	UpdateUser(ctx context.Context, req *userv2.UpdateUserRequest) (*userv2.UpdateUserResponse, error)
+}
+
+// GroupService expected methods of a valid group service
+type GroupService interface {
+	GetGroupByID(ctx context.Context, req *group.GetGroupByIDRequest) (*group.GetGroupByIDResponse, error)
+	GetGroupMembers(ctx context.Context, req *group.GetGroupMembersRequest) (*group.GetGroupMembersResponse, error)
+	GetGroupDescendants(ctx context.Context, req *group.GetGroupDescendantsRequest) (*group.GetGroupDescendantsResponse, error)
+	GetGroupsByUserID(ctx context.Context, req *group.GetGroupsByUserIDRequest) (*group.GetGroupsByUserIDResponse, error)
+	CreateGroup(ctx context.Context, req *group.CreateGroupRequest) (*group.CreateGroupResponse, error)
+	UpdateGroup(ctx context.Context, req *group.UpdateGroupRequest) (*group.UpdateGroupResponse, error)
+	DeleteGroup(ctx context.Context, req *group.DeleteGroupRequest) (*group.DeleteGroupResponse, error)
+	AddMember(ctx context.Context, req *group.AddMemberRequest) (*group.AddMemberResponse, error)
+	RemoveMember(ctx context.Context, req *group.RemoveMemberRequest) (*group.RemoveMemberResponse, error)
+	Can(ctx context.Context, userID, groupID, permission string) (bool, error)
+}
+
+// ApiTokenService expected methods of a valid api token service
+type ApiTokenService interface {
+	CreateAPIToken(ctx context.Context, r *apitoken.CreateAPITokenRequest) (*apitoken.CreateAPITokenResponse, error)
+	ValidateAPIToken(ctx context.Context, userFullToken string) (*apitoken.APITokenRequester, error)
+	UpdateAPITokenLastUsedAt(ctx context.Context, r *apitoken.UpdateAPITokenLastUsedAtRequest) error
+	GetAPITokensFor(ctx context.Context, r *apitoken.GetAPITokensForRequest) (*apitoken.GetAPITokensForResponse, error)
+	GetAPIToken(ctx context.Context, r *apitoken.GetAPITokenRequest) (*apitoken.GetAPITokenResponse, error)
+	RevokeAPIToken(ctx context.Context, r *apitoken.RevokeAPITokenRequest) error
+}
+
+// Service holds and manages SCIM provisioning business logic
+type Service struct {
+	UserService     UserService
+	GroupService    GroupService
+	ApiTokenService ApiTokenService
+	Config          *Config
+}
+
+// NewService creates a SCIM service. Provisioned groups default to the TEAM type and
+// provisioned members to the MEMBER role
+func NewService(userService UserService, groupService GroupService, apiTokenService ApiTokenService, config *Config) *Service {
+	if config == nil {
+		config = &Config{}
+	}
+
+	if config.GroupType == "" {
+		config.GroupType = group.GroupTypeTeam
+	}
+
+	if config.MemberRole == "" {
+		config.MemberRole = group.MemberRoleMember
+	}
+
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return &Service{
+		UserService:     userService,
+		GroupService:    groupService,
+		ApiTokenService: apiTokenService,
+		Config:          config,
+	}
+}
+
+// IssueToken issues a SCIM bearer token for an organisation. The token is owned by the
+// organisation's root group rather than the user issuing it
+func (s *Service) IssueToken(ctx context.Context, r *IssueTokenRequest) (*IssueTokenResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "issue-token")
+
+	organisation, err := s.getManagedOrganisation(ctx, r.GroupID, r.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(r.Description)
+	if description == "" {
+		description = TokenDescription
+	}
+
+	createdToken, err := s.ApiTokenService.CreateAPIToken(ctx, &apitoken.CreateAPITokenRequest{
+		UserID:      organisation.ID,
+		UserNanoId:  organisationTokenPrefix(organisation),
+		TokenTtl:    r.TokenTtl,
+		Description: description,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-scim-token", zap.String("group-id", organisation.ID), zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("scim-token-issued", zap.String("group-id", organisation.ID), zap.String("token-id", createdToken.APIToken.ID), zap.String("actor-id", r.ActorID))
+
+	return &IssueTokenResponse{
+		Token: createdToken.APIToken,
+	}, nil
+}
+
+// GetTokens returns the SCIM tokens issued for an organisation
+func (s *Service) GetTokens(ctx context.Context, r *GetTokensRequest) (*GetTokensResponse, error) {
+	organisation, err := s.getManagedOrganisation(ctx, r.GroupID, r.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []apitoken.UserAPIToken{}
+	for page := 1; ; page++ {
+		tokensPage, err := s.ApiTokenService.GetAPITokensFor(ctx, &apitoken.GetAPITokensForRequest{
+			ID:      organisation.ID,
+			NanoId:  organisationTokenPrefix(organisation),
+			Status:  r.Status,
+			PerPage: 100,
+			Page:    page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, tokensPage.APITokens...)
+		if page >= tokensPage.TotalPages || len(tokensPage.APITokens) == 0 {
+			break
+		}
+	}
+
+	return &GetTokensResponse{
+		Tokens: tokens,
+	}, nil
+}
+
+// RevokeToken revokes one of an organisation's SCIM tokens
+func (s *Service) RevokeToken(ctx context.Context, r *RevokeTokenRequest) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "revoke-token")
+
+	organisation, err := s.getManagedOrganisation(ctx, r.GroupID, r.ActorID)
+	if err != nil {
+		return err
+	}
+
+	existingToken, err := s.ApiTokenService.GetAPIToken(ctx, &apitoken.GetAPITokenRequest{ID: r.TokenID})
+	if err != nil {
+		logger.Warn("unable-to-get-scim-token", zap.String("token-id", r.TokenID), zap.Error(err))
+		return ErrTokenNotFound
+	}
+
+	if existingToken.APIToken.CreatedByID != organisation.ID {
+		return ErrTokenNotFound
+	}
+
+	if err := s.ApiTokenService.RevokeAPIToken(ctx, &apitoken.RevokeAPITokenRequest{ID: r.TokenID}); err != nil {
+		logger.Error("failed-to-revoke-scim-token", zap.String("token-id", r.TokenID), zap.Error(err))
+		return err
+	}
+
+	logger.Info("scim-token-revoked", zap.String("group-id", organisation.ID), zap.String("token-id", r.TokenID), zap.String("actor-id", r.ActorID))
+
+	return nil
+}
+
+// Authenticate validates a SCIM bearer token and returns the organisation it was issued for.
+// Tokens of archived or deleted organisations are rejected
+func (s *Service) Authenticate(ctx context.Context, r *AuthenticateRequest) (*AuthenticateResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "authenticate")
+
+	if strings.TrimSpace(r.Token) == "" {
+		return nil, ErrUnauthorised
+	}
+
+	requester, err := s.ApiTokenService.ValidateAPIToken(ctx, r.Token)
+	if err != nil || requester == nil || !requester.IsValid {
+		logger.Debug("scim-token-rejected", zap.Error(err))
+		return nil, ErrUnauthorised
+	}
+
+	organisation, err := s.getOrganisation(ctx, requester.UserID)
+	if err != nil {
+		logger.Warn("scim-token-organisation-unavailable", zap.String("group-id", requester.UserID), zap.Error(err))
+		return nil, ErrUnauthorised
+	}
+
+	if err := s.ApiTokenService.UpdateAPITokenLastUsedAt(ctx, &apitoken.UpdateAPITokenLastUsedAtRequest{
+		APITokenEncoded: requester.UserAPITokenEncoded,
+		ClientID:        organisation.ID,
+	}); err != nil {
+		logger.Warn("unable-to-update-scim-token-last-used-at", zap.String("group-id", organisation.ID), zap.Error(err))
+	}
+
+	return &AuthenticateResponse{
+		OrganisationID: organisation.ID,
+	}, nil
+}
+
+// getManagedOrganisation returns the organisation if the actor may manage its SCIM provisioning
+func (s *Service) getManagedOrganisation(ctx context.Context, groupID, actorID string) (*group.UniversalGroup, error) {
+	if actorID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	organisation, err := s.getOrganisation(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.GroupService.Can(ctx, actorID, organisation.ID, group.PermissionSCIMManage)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed {
+		return nil, ErrForbidden
+	}
+
+	return organisation, nil
+}
+
+// getOrganisation returns the active root group with the given ID
+func (s *Service) getOrganisation(ctx context.Context, groupID string) (*group.UniversalGroup, error) {
+	if groupID == "" {
+		return nil, ErrOrganisationNotFound
+	}
+
+	existingGroup, err := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: groupID})
+	if err != nil {
+		if errors.Is(err, group.ErrResourceNotFound) {
+			return nil, ErrOrganisationNotFound
+		}
+		return nil, err
+	}
+
+	organisation := existingGroup.Group
+	if organisation == nil || !isLiveGroup(organisation) {
+		return nil, ErrOrganisationNotFound
+	}
+
+	if organisation.ParentGroupID != "" || len(organisation.Lineage) > 0 {
+		return nil, ErrOrganisationNotRootGroup
+	}
+
+	return organisation, nil
+}
+
+// isLiveGroup reports whether the group is active and not deleted
+func isLiveGroup(g *group.UniversalGroup) bool {
+	if g.Metadata != nil && g.Metadata.DeletedAt != "" {
+		return false
+	}
+	return g.Status == "" || g.Status == group.GroupStatusActive
+}
+
+// organisationTokenPrefix returns the prefix SCIM tokens for the organisation are issued under
+func organisationTokenPrefix(organisation *group.UniversalGroup) string {
+	if organisation.NanoID != "" {
+		return organisation.NanoID
+	}
+	return organisation.ID
+}
+
+// paginate returns the page of resources described by the 1-based start index and count
+func paginate[T any](resources []T, startIndex int, count *int) ([]T, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	pageSize := DefaultListCount
+	if count != nil {
+		pageSize = *count
+	}
+	if pageSize < 0 {
+		pageSize = 0
+	}
+	if pageSize > MaxListCount {
+		pageSize = MaxListCount
+	}
+
+	if startIndex > len(resources) || pageSize == 0 {
+		return []T{}, startIndex
+	}
+
+	end := startIndex - 1 + pageSize
+	if end > len(resources) {
+		end = len(resources)
+	}
+
+	return resources[startIndex-1 : end], startIndex
+}
+
+// newListResponse wraps a page of resources in the SCIM list envelope
+func newListResponse[T any](resources []T, totalResults, startIndex int) *ListResponse {
+	listedResources := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		listedResources = append(listedResources, resource)
+	}
+
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(listedResources),
+		Resources:    listedResources,
+	}
+}
+
+// matchesFilter reports whether a resource matches a parsed filter, a nil filter matches everything
+func matchesFilter(filter filterExpression, resource interface{}) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+
+	resourceMap, err := toResourceMap(resource)
+	if err != nil {
+		return false, err
+	}
+
+	return filter.matches(resourceMap), nil
+}
+
+// parseListFilter parses an optional list filter
+func parseListFilter(filter string) (filterExpression, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+
+	return parseFilter(filter)
+}
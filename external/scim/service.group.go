@@ -0,0 +1,486 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// GetGroups returns the organisation's groups matching the request's filter
+func (s *Service) GetGroups(ctx context.Context, r *GetGroupsRequest) (*ListResponse, error) {
+	filter, err := parseListFilter(r.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	organisation, err := s.getOrganisation(ctx, r.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	organisationGroups, err := s.getOrganisationGroups(ctx, organisation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	matchedGroups := []*Group{}
+	for _, organisationGroup := range organisationGroups {
+		scimGroup := s.toSCIMGroup(organisationGroup)
+
+		matched, err := matchesFilter(filter, scimGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matched {
+			continue
+		}
+
+		if excludesMembers(r.ExcludedAttributes) {
+			scimGroup.Members = nil
+		}
+		matchedGroups = append(matchedGroups, scimGroup)
+	}
+
+	page, startIndex := paginate(matchedGroups, r.StartIndex, r.Count)
+
+	return newListResponse(page, len(matchedGroups), startIndex), nil
+}
+
+// GetGroup returns one of the organisation's groups
+func (s *Service) GetGroup(ctx context.Context, r *GetGroupRequest) (*GroupResponse, error) {
+	organisationGroup, err := s.getOrganisationGroup(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	scimGroup := s.toSCIMGroup(organisationGroup)
+	if excludesMembers(r.ExcludedAttributes) {
+		scimGroup.Members = nil
+	}
+
+	return &GroupResponse{
+		Group: scimGroup,
+	}, nil
+}
+
+// CreateGroup creates a group directly under the organisation along with its members
+func (s *Service) CreateGroup(ctx context.Context, r *CreateGroupRequest) (*GroupResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "create-group")
+
+	if r.Group == nil || strings.TrimSpace(r.Group.DisplayName) == "" {
+		return nil, ErrInvalidValue
+	}
+
+	organisation, err := s.getOrganisation(ctx, r.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// members are resolved first so a bad member does not leave a half-provisioned group behind
+	members, err := s.resolveGroupMembers(ctx, organisation.ID, "", r.Group.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	var extensions map[string]interface{}
+	if r.Group.ExternalID != "" {
+		extensions = map[string]interface{}{
+			GroupExtensionKeyExternalID: r.Group.ExternalID,
+		}
+	}
+
+	createdGroup, err := s.GroupService.CreateGroup(ctx, &group.CreateGroupRequest{
+		Name:          strings.TrimSpace(r.Group.DisplayName),
+		Type:          s.Config.GroupType,
+		ParentGroupID: organisation.ID,
+		Extensions:    extensions,
+	})
+	if err != nil {
+		if errors.Is(err, group.ErrNameAlreadyExists) {
+			return nil, ErrGroupAlreadyExists
+		}
+
+		logger.Error("failed-to-create-group", zap.String("group-id", organisation.ID), zap.Error(err))
+		return nil, err
+	}
+
+	for _, member := range members {
+		if err := s.addGroupMember(ctx, createdGroup.Group.ID, member); err != nil {
+			logger.Error("failed-to-add-group-member", zap.String("group-id", createdGroup.Group.ID), zap.String("member-id", member.ID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	logger.Info("group-provisioned", zap.String("organisation-id", organisation.ID), zap.String("group-id", createdGroup.Group.ID), zap.Int("members", len(members)))
+
+	return s.GetGroup(ctx, &GetGroupRequest{OrganisationID: organisation.ID, ID: createdGroup.Group.ID})
+}
+
+// ReplaceGroup replaces one of the organisation's groups, including its full member list
+func (s *Service) ReplaceGroup(ctx context.Context, r *ReplaceGroupRequest) (*GroupResponse, error) {
+	if r.Group == nil || strings.TrimSpace(r.Group.DisplayName) == "" {
+		return nil, ErrInvalidValue
+	}
+
+	existingGroup, err := s.getOrganisationGroup(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyGroupChanges(ctx, r.OrganisationID, existingGroup, r.Group); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, &GetGroupRequest{OrganisationID: r.OrganisationID, ID: r.ID})
+}
+
+// PatchGroup applies PATCH operations to one of the organisation's groups. Identity providers
+// mostly use it to add and remove members
+func (s *Service) PatchGroup(ctx context.Context, r *PatchGroupRequest) (*GroupResponse, error) {
+	existingGroup, err := s.getOrganisationGroup(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := toResourceMap(s.toSCIMGroup(existingGroup))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyPatchOperations(resource, r.Operations); err != nil {
+		return nil, err
+	}
+
+	patchedGroup := &Group{}
+	if err := fromResourceMap(resource, patchedGroup); err != nil {
+		return nil, ErrInvalidValue
+	}
+
+	if strings.TrimSpace(patchedGroup.DisplayName) == "" {
+		return nil, ErrInvalidValue
+	}
+
+	if err := s.applyGroupChanges(ctx, r.OrganisationID, existingGroup, patchedGroup); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(ctx, &GetGroupRequest{OrganisationID: r.OrganisationID, ID: r.ID})
+}
+
+// DeleteGroup soft deletes one of the organisation's groups along with its subgroups
+func (s *Service) DeleteGroup(ctx context.Context, r *DeleteGroupRequest) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "delete-group")
+
+	existingGroup, err := s.getOrganisationGroup(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.GroupService.DeleteGroup(ctx, &group.DeleteGroupRequest{ID: existingGroup.ID}); err != nil {
+		logger.Error("failed-to-delete-group", zap.String("group-id", existingGroup.ID), zap.Error(err))
+		return err
+	}
+
+	logger.Info("group-deprovisioned", zap.String("organisation-id", r.OrganisationID), zap.String("group-id", existingGroup.ID))
+
+	return nil
+}
+
+// applyGroupChanges renames the group, records its external ID and reconciles its members
+// with the SCIM representation
+func (s *Service) applyGroupChanges(ctx context.Context, organisationID string, existingGroup *group.UniversalGroup, scimGroup *Group) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "apply-group-changes")
+
+	desiredMembers, err := s.resolveGroupMembers(ctx, organisationID, existingGroup.ID, scimGroup.Members)
+	if err != nil {
+		return err
+	}
+
+	updateRequest := &group.UpdateGroupRequest{ID: existingGroup.ID}
+	hasChanges := false
+
+	displayName := strings.TrimSpace(scimGroup.DisplayName)
+	if displayName != groupDisplayName(existingGroup) {
+		updateRequest.Name = &displayName
+		hasChanges = true
+	}
+
+	if scimGroup.ExternalID != "" && scimGroup.ExternalID != groupExternalID(existingGroup) {
+		updateRequest.Extensions = map[string]interface{}{
+			GroupExtensionKeyExternalID: scimGroup.ExternalID,
+		}
+		hasChanges = true
+	}
+
+	if hasChanges {
+		if _, err := s.GroupService.UpdateGroup(ctx, updateRequest); err != nil {
+			if errors.Is(err, group.ErrNameAlreadyExists) {
+				return ErrGroupAlreadyExists
+			}
+			return err
+		}
+	}
+
+	currentMembers := map[string]bool{}
+	for _, member := range existingGroup.Members {
+		if isActiveMember(member) {
+			currentMembers[member.ID] = true
+		}
+	}
+
+	desiredMemberIDs := map[string]bool{}
+	for _, member := range desiredMembers {
+		desiredMemberIDs[member.ID] = true
+		if currentMembers[member.ID] {
+			continue
+		}
+
+		if err := s.addGroupMember(ctx, existingGroup.ID, member); err != nil {
+			logger.Error("failed-to-add-group-member", zap.String("group-id", existingGroup.ID), zap.String("member-id", member.ID), zap.Error(err))
+			return err
+		}
+	}
+
+	for memberID := range currentMembers {
+		if desiredMemberIDs[memberID] {
+			continue
+		}
+
+		_, err := s.GroupService.RemoveMember(ctx, &group.RemoveMemberRequest{
+			GroupID:  existingGroup.ID,
+			MemberID: memberID,
+		})
+		if err != nil {
+			if errors.Is(err, group.ErrOwnerRemovalRequiresConfirm) {
+				return ErrOrganisationOwnerRequired
+			}
+
+			logger.Error("failed-to-remove-group-member", zap.String("group-id", existingGroup.ID), zap.String("member-id", memberID), zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveGroupMembers works out whether each SCIM member is one of the organisation's users
+// or groups, rejecting members from outside the organisation
+func (s *Service) resolveGroupMembers(ctx context.Context, organisationID, groupID string, scimMembers []GroupMember) ([]group.Member, error) {
+	if len(scimMembers) == 0 {
+		return []group.Member{}, nil
+	}
+
+	var organisationGroupIDs map[string]bool
+
+	members := []group.Member{}
+	seenMembers := map[string]bool{}
+	for _, scimMember := range scimMembers {
+		memberID := strings.TrimSpace(scimMember.Value)
+		if memberID == "" {
+			return nil, ErrInvalidValue
+		}
+
+		if seenMembers[memberID] {
+			continue
+		}
+		seenMembers[memberID] = true
+
+		if !strings.EqualFold(scimMember.Type, ResourceTypeGroup) {
+			isMember, err := s.isOrganisationMember(ctx, organisationID, memberID)
+			if err != nil {
+				return nil, err
+			}
+
+			if isMember {
+				members = append(members, group.Member{ID: memberID, Type: group.MemberTypeUser})
+				continue
+			}
+		}
+
+		if organisationGroupIDs == nil {
+			descendantIDs, err := s.getOrganisationGroupIDs(ctx, organisationID)
+			if err != nil {
+				return nil, err
+			}
+
+			organisationGroupIDs = map[string]bool{}
+			for _, descendantID := range descendantIDs {
+				organisationGroupIDs[descendantID] = true
+			}
+		}
+
+		if memberID == groupID || !organisationGroupIDs[memberID] {
+			return nil, ErrMemberNotInOrganisation
+		}
+
+		members = append(members, group.Member{ID: memberID, Type: group.MemberTypeGroup})
+	}
+
+	return members, nil
+}
+
+// addGroupMember adds a resolved member to a group with the configured member role
+func (s *Service) addGroupMember(ctx context.Context, groupID string, member group.Member) error {
+	_, err := s.GroupService.AddMember(ctx, &group.AddMemberRequest{
+		GroupID:  groupID,
+		MemberID: member.ID,
+		Type:     member.Type,
+		Role:     s.Config.MemberRole,
+	})
+	if errors.Is(err, group.ErrMemberAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+// getOrganisationGroup returns the group if it is a live descendant of the organisation
+func (s *Service) getOrganisationGroup(ctx context.Context, organisationID, groupID string) (*group.UniversalGroup, error) {
+	if groupID == "" || groupID == organisationID {
+		return nil, ErrResourceNotFound
+	}
+
+	if _, err := s.getOrganisation(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	existingGroup, err := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: groupID})
+	if err != nil {
+		if errors.Is(err, group.ErrResourceNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	if existingGroup.Group == nil || !isLiveGroup(existingGroup.Group) || !isDescendantOf(existingGroup.Group, organisationID) {
+		return nil, ErrResourceNotFound
+	}
+
+	return existingGroup.Group, nil
+}
+
+// getOrganisationGroupIDs returns the IDs of every group nested under the organisation
+func (s *Service) getOrganisationGroupIDs(ctx context.Context, organisationID string) ([]string, error) {
+	descendants, err := s.GroupService.GetGroupDescendants(ctx, &group.GetGroupDescendantsRequest{ID: organisationID})
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs := []string{}
+	for _, level := range descendants.Descendants {
+		for _, node := range level {
+			groupIDs = append(groupIDs, node.ID)
+		}
+	}
+
+	return groupIDs, nil
+}
+
+// getOrganisationGroups returns every live group nested under the organisation
+func (s *Service) getOrganisationGroups(ctx context.Context, organisationID string) ([]*group.UniversalGroup, error) {
+	groupIDs, err := s.getOrganisationGroupIDs(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	organisationGroups := []*group.UniversalGroup{}
+	for _, groupID := range groupIDs {
+		existingGroup, err := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: groupID})
+		if err != nil {
+			if errors.Is(err, group.ErrResourceNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		if existingGroup.Group == nil || !isLiveGroup(existingGroup.Group) {
+			continue
+		}
+
+		organisationGroups = append(organisationGroups, existingGroup.Group)
+	}
+
+	return organisationGroups, nil
+}
+
+// toSCIMGroup maps a group onto its SCIM representation
+func (s *Service) toSCIMGroup(g *group.UniversalGroup) *Group {
+	scimGroup := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          g.ID,
+		ExternalID:  groupExternalID(g),
+		DisplayName: groupDisplayName(g),
+		Members:     []GroupMember{},
+		Meta: &Meta{
+			ResourceType: ResourceTypeGroup,
+		},
+	}
+
+	for _, member := range g.Members {
+		if !isActiveMember(member) {
+			continue
+		}
+
+		scimMember := GroupMember{Value: member.ID}
+		switch member.Type {
+		case group.MemberTypeUser:
+			scimMember.Type = ResourceTypeUser
+			if s.Config.BaseURL != "" {
+				scimMember.Ref = s.Config.BaseURL + "/Users/" + member.ID
+			}
+		case group.MemberTypeGroup:
+			scimMember.Type = ResourceTypeGroup
+			if s.Config.BaseURL != "" {
+				scimMember.Ref = s.Config.BaseURL + "/Groups/" + member.ID
+			}
+		default:
+			continue
+		}
+
+		scimGroup.Members = append(scimGroup.Members, scimMember)
+	}
+
+	if g.Metadata != nil {
+		scimGroup.Meta.Created = g.Metadata.CreatedAt
+		scimGroup.Meta.LastModified = g.Metadata.UpdatedAt
+	}
+
+	if s.Config.BaseURL != "" {
+		scimGroup.Meta.Location = s.Config.BaseURL + "/Groups/" + g.ID
+	}
+
+	return scimGroup
+}
+
+// groupDisplayName returns the name the group was given, before normalisation
+func groupDisplayName(g *group.UniversalGroup) string {
+	if g.RawName != "" {
+		return g.RawName
+	}
+	return g.Name
+}
+
+// groupExternalID returns the identity provider's ID for the group, if it has one
+func groupExternalID(g *group.UniversalGroup) string {
+	externalID, _ := g.Extensions[GroupExtensionKeyExternalID].(string)
+	return externalID
+}
+
+// isDescendantOf reports whether the group is nested under the organisation
+func isDescendantOf(g *group.UniversalGroup, organisationID string) bool {
+	return len(g.Lineage) > 0 && g.Lineage[0] == organisationID
+}
+
+// excludesMembers reports whether the excludedAttributes parameter leaves out members
+func excludesMembers(excludedAttributes string) bool {
+	for _, attribute := range strings.Split(excludedAttributes, ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			return true
+		}
+	}
+	return false
+}
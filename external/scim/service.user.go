@@ -0,0 +1,549 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
+)
+
+// GetUsers returns the organisation's users matching the request's filter
+func (s *Service) GetUsers(ctx context.Context, r *GetUsersRequest) (*ListResponse, error) {
+	filter, err := parseListFilter(r.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	organisation, err := s.getOrganisation(ctx, r.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*User
+
+	// identity providers look users up by userName before provisioning them, so that
+	// lookup avoids loading the whole organisation
+	if email, ok := equalityFilterValue(filter, "userName"); ok {
+		users, err = s.getUsersByEmail(ctx, organisation.ID, email)
+	} else {
+		users, err = s.getOrganisationUsers(ctx, organisation.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matchedUsers := []*User{}
+	for _, user := range users {
+		matched, err := matchesFilter(filter, user)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			matchedUsers = append(matchedUsers, user)
+		}
+	}
+
+	page, startIndex := paginate(matchedUsers, r.StartIndex, r.Count)
+
+	return newListResponse(page, len(matchedUsers), startIndex), nil
+}
+
+// GetUser returns one of the organisation's users
+func (s *Service) GetUser(ctx context.Context, r *GetUserRequest) (*UserResponse, error) {
+	existingUser, err := s.getOrganisationUser(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserResponse{
+		User: s.toSCIMUser(existingUser, r.OrganisationID),
+	}, nil
+}
+
+// CreateUser provisions a user into the organisation. Users that already have an account
+// are added to the organisation instead of being created again, and their account is left
+// unchanged unless the organisation provisioned it
+func (s *Service) CreateUser(ctx context.Context, r *CreateUserRequest) (*UserResponse, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "create-user")
+
+	if r.User == nil || strings.TrimSpace(r.User.UserName) == "" {
+		return nil, ErrInvalidValue
+	}
+
+	organisation, err := s.getOrganisation(ctx, r.OrganisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingUser, err := s.UserService.FindUserByEmail(ctx, &userv2.GetUserByEmailRequest{Email: r.User.PrimaryEmail()})
+	if err != nil && !errors.Is(err, userv2.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if existingUser != nil && existingUser.User != nil {
+		isMember, err := s.isOrganisationMember(ctx, organisation.ID, existingUser.User.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if isMember {
+			return nil, ErrUserAlreadyProvisioned
+		}
+
+		// inactive shared accounts are kept out of the organisation rather than deactivated
+		if !r.User.IsActive() && !isProvisionedBy(existingUser.User, organisation.ID) {
+			return &UserResponse{
+				User: s.toRemovedSCIMUser(existingUser.User, organisation.ID),
+			}, nil
+		}
+
+		if err := s.addOrganisationMember(ctx, organisation.ID, existingUser.User.ID); err != nil {
+			return nil, err
+		}
+
+		provisionedUser, err := s.applyUserChanges(ctx, organisation.ID, existingUser.User, r.User)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("existing-user-provisioned", zap.String("group-id", organisation.ID), zap.String("user-id", existingUser.User.ID))
+
+		return &UserResponse{
+			User: provisionedUser,
+		}, nil
+	}
+
+	status := userv2.AccountStatusKeyActive
+	if !r.User.IsActive() {
+		status = userv2.AccountStatusKeyDeactivated
+	}
+
+	firstName, lastName, fullName := nameFromSCIMUser(r.User)
+
+	extensions := map[string]interface{}{
+		UserExtensionKeyProvisionedBy: organisation.ID,
+	}
+	if r.User.ExternalID != "" {
+		extensions[userExternalIDKey(organisation.ID)] = r.User.ExternalID
+	}
+
+	createdUser, err := s.UserService.CreateUser(ctx, &userv2.CreateUserRequest{
+		Email:          r.User.PrimaryEmail(),
+		Type:           s.Config.UserType,
+		FirstName:      firstName,
+		LastName:       lastName,
+		FullName:       fullName,
+		Phone:          r.User.PrimaryPhoneNumber(),
+		Status:         status,
+		Extensions:     extensions,
+		GenerateUUID:   true,
+		GenerateNanoID: true,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-user", zap.String("group-id", organisation.ID), zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.addOrganisationMember(ctx, organisation.ID, createdUser.User.ID); err != nil {
+		return nil, err
+	}
+
+	logger.Info("user-provisioned", zap.String("group-id", organisation.ID), zap.String("user-id", createdUser.User.ID))
+
+	return &UserResponse{
+		User: s.toSCIMUser(createdUser.User, organisation.ID),
+	}, nil
+}
+
+// ReplaceUser replaces one of the organisation's users with the given representation
+func (s *Service) ReplaceUser(ctx context.Context, r *ReplaceUserRequest) (*UserResponse, error) {
+	if r.User == nil {
+		return nil, ErrInvalidValue
+	}
+
+	existingUser, err := s.getOrganisationUser(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedUser, err := s.applyUserChanges(ctx, r.OrganisationID, existingUser, r.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserResponse{
+		User: updatedUser,
+	}, nil
+}
+
+// PatchUser applies PATCH operations to one of the organisation's users. Setting active to
+// false deactivates users the organisation provisioned and removes other users from the
+// organisation
+func (s *Service) PatchUser(ctx context.Context, r *PatchUserRequest) (*UserResponse, error) {
+	existingUser, err := s.getOrganisationUser(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := toResourceMap(s.toSCIMUser(existingUser, r.OrganisationID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyPatchOperations(resource, r.Operations); err != nil {
+		return nil, err
+	}
+
+	// some identity providers send active as the string "False"
+	activeKey := resolveKey(resource, "active")
+	if active, ok := resource[activeKey].(string); ok {
+		resource[activeKey] = strings.EqualFold(active, "true")
+	}
+
+	patchedUser := &User{}
+	if err := fromResourceMap(resource, patchedUser); err != nil {
+		return nil, ErrInvalidValue
+	}
+
+	updatedUser, err := s.applyUserChanges(ctx, r.OrganisationID, existingUser, patchedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserResponse{
+		User: updatedUser,
+	}, nil
+}
+
+// DeleteUser deprovisions a user by removing them from the organisation and its groups.
+// Their account is left in place as it may belong to other organisations
+func (s *Service) DeleteUser(ctx context.Context, r *DeleteUserRequest) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "delete-user")
+
+	existingUser, err := s.getOrganisationUser(ctx, r.OrganisationID, r.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.removeOrganisationMember(ctx, r.OrganisationID, existingUser.ID); err != nil {
+		return err
+	}
+
+	logger.Info("user-deprovisioned", zap.String("group-id", r.OrganisationID), zap.String("user-id", existingUser.ID))
+
+	return nil
+}
+
+// applyUserChanges updates a user from its SCIM representation and returns the result.
+//
+// Accounts the organisation provisioned have their identity fields updated and move between
+// the active and deactivated statuses when needed. Other accounts are shared with the rest of
+// the platform, so only the organisation's external ID for them is kept and setting active to
+// false removes them from the organisation instead of deactivating them
+func (s *Service) applyUserChanges(ctx context.Context, organisationID string, existingUser *userv2.UniversalUser, scimUser *User) (*User, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "apply-user-changes")
+
+	if !isProvisionedBy(existingUser, organisationID) {
+		if !scimUser.IsActive() {
+			if err := s.removeOrganisationMember(ctx, organisationID, existingUser.ID); err != nil {
+				return nil, err
+			}
+
+			logger.Info("shared-user-removed-from-organisation", zap.String("group-id", organisationID), zap.String("user-id", existingUser.ID))
+
+			return s.toRemovedSCIMUser(existingUser, organisationID), nil
+		}
+
+		currentExternalID, _ := existingUser.Extensions[userExternalIDKey(organisationID)].(string)
+		if scimUser.ExternalID == "" || scimUser.ExternalID == currentExternalID {
+			return s.toSCIMUser(existingUser, organisationID), nil
+		}
+
+		updatedUser, err := s.UserService.UpdateUser(ctx, &userv2.UpdateUserRequest{
+			ID: existingUser.ID,
+			Extensions: map[string]interface{}{
+				userExternalIDKey(organisationID): scimUser.ExternalID,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return s.toSCIMUser(updatedUser.User, organisationID), nil
+	}
+
+	firstName, lastName, fullName := nameFromSCIMUser(scimUser)
+
+	updateRequest := &userv2.UpdateUserRequest{
+		ID:        existingUser.ID,
+		Email:     scimUser.PrimaryEmail(),
+		FirstName: firstName,
+		LastName:  lastName,
+		FullName:  fullName,
+		Phone:     scimUser.PrimaryPhoneNumber(),
+	}
+
+	if scimUser.ExternalID != "" {
+		updateRequest.Extensions = map[string]interface{}{
+			userExternalIDKey(organisationID): scimUser.ExternalID,
+		}
+	}
+
+	switch {
+	case !scimUser.IsActive() && existingUser.Status != userv2.AccountStatusKeyDeactivated:
+		updateRequest.Status = userv2.AccountStatusKeyDeactivated
+	case scimUser.IsActive() && existingUser.Status == userv2.AccountStatusKeyDeactivated:
+		updateRequest.Status = userv2.AccountStatusKeyActive
+	}
+
+	updatedUser, err := s.UserService.UpdateUser(ctx, updateRequest)
+	if err != nil {
+		if errors.Is(err, userv2.ErrUserInvalidStatusTransition) || errors.Is(err, userv2.ErrUserInvalidTargetStatus) {
+			return nil, ErrInvalidStatusTransition
+		}
+		return nil, err
+	}
+
+	return s.toSCIMUser(updatedUser.User, organisationID), nil
+}
+
+// addOrganisationMember adds a user to the organisation's root group
+func (s *Service) addOrganisationMember(ctx context.Context, organisationID, userID string) error {
+	_, err := s.GroupService.AddMember(ctx, &group.AddMemberRequest{
+		GroupID:  organisationID,
+		MemberID: userID,
+		Type:     group.MemberTypeUser,
+		Role:     s.Config.MemberRole,
+	})
+	return err
+}
+
+// removeOrganisationMember removes a user from the organisation's root group, which removes
+// them from its groups too
+func (s *Service) removeOrganisationMember(ctx context.Context, organisationID, userID string) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/scim", "remove-organisation-member")
+
+	_, err := s.GroupService.RemoveMember(ctx, &group.RemoveMemberRequest{
+		GroupID:  organisationID,
+		MemberID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, group.ErrOwnerRemovalRequiresConfirm) {
+			return ErrOrganisationOwnerRequired
+		}
+
+		logger.Error("failed-to-remove-user-from-organisation", zap.String("group-id", organisationID), zap.String("user-id", userID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// getOrganisationUser returns the user if they are an active member of the organisation
+func (s *Service) getOrganisationUser(ctx context.Context, organisationID, userID string) (*userv2.UniversalUser, error) {
+	if userID == "" {
+		return nil, ErrResourceNotFound
+	}
+
+	isMember, err := s.isOrganisationMember(ctx, organisationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, ErrResourceNotFound
+	}
+
+	existingUser, err := s.UserService.GetUserByID(ctx, &userv2.GetUserByIDRequest{ID: userID})
+	if err != nil {
+		if errors.Is(err, userv2.ErrUserNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return existingUser.User, nil
+}
+
+// isOrganisationMember reports whether the user is an active member of the organisation
+func (s *Service) isOrganisationMember(ctx context.Context, organisationID, userID string) (bool, error) {
+	userGroups, err := s.GroupService.GetGroupsByUserID(ctx, &group.GetGroupsByUserIDRequest{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+
+	for _, userGroup := range userGroups.Groups {
+		if userGroup == nil || userGroup.ID != organisationID {
+			continue
+		}
+
+		for _, member := range userGroup.Members {
+			if member.ID == userID && member.Type == group.MemberTypeUser && isActiveMember(member) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// getUsersByEmail returns the organisation user with the email, if there is one
+func (s *Service) getUsersByEmail(ctx context.Context, organisationID, email string) ([]*User, error) {
+	existingUser, err := s.UserService.FindUserByEmail(ctx, &userv2.GetUserByEmailRequest{Email: email})
+	if err != nil {
+		if errors.Is(err, userv2.ErrUserNotFound) {
+			return []*User{}, nil
+		}
+		return nil, err
+	}
+
+	isMember, err := s.isOrganisationMember(ctx, organisationID, existingUser.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return []*User{}, nil
+	}
+
+	return []*User{s.toSCIMUser(existingUser.User, organisationID)}, nil
+}
+
+// getOrganisationUsers returns every active user member of the organisation
+func (s *Service) getOrganisationUsers(ctx context.Context, organisationID string) ([]*User, error) {
+	members, err := s.GroupService.GetGroupMembers(ctx, &group.GetGroupMembersRequest{
+		GroupID:    organisationID,
+		MemberType: group.MemberTypeUser,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := []string{}
+	for _, member := range members.Members {
+		if isActiveMember(member) {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+
+	users := []*User{}
+	for start := 0; start < len(userIDs); start += userBatchSize {
+		end := start + userBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		batch, err := s.UserService.GetUsers(ctx, &userv2.GetUsersRequest{
+			Page:      1,
+			PerPage:   userBatchSize,
+			IDsFilter: userIDs[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range batch.Users {
+			users = append(users, s.toSCIMUser(&batch.Users[i], organisationID))
+		}
+	}
+
+	return users, nil
+}
+
+// toSCIMUser maps a user's account onto its SCIM representation
+func (s *Service) toSCIMUser(u *userv2.UniversalUser, organisationID string) *User {
+	active := u.Status == userv2.AccountStatusKeyActive || u.Status == userv2.AccountStatusKeyProvisioned
+
+	scimUser := &User{
+		Schemas:  []string{SchemaUser},
+		ID:       u.ID,
+		UserName: u.Email,
+		Active:   &active,
+		Emails: []MultiValuedAttribute{
+			{Value: u.Email, Type: "work", Primary: true},
+		},
+		Meta: &Meta{
+			ResourceType: ResourceTypeUser,
+		},
+	}
+
+	if externalID, ok := u.Extensions[userExternalIDKey(organisationID)].(string); ok {
+		scimUser.ExternalID = externalID
+	}
+
+	if u.PersonalInfo != nil {
+		if u.PersonalInfo.FirstName != "" || u.PersonalInfo.LastName != "" || u.PersonalInfo.FullName != "" {
+			scimUser.Name = &Name{
+				Formatted:  u.PersonalInfo.FullName,
+				GivenName:  u.PersonalInfo.FirstName,
+				FamilyName: u.PersonalInfo.LastName,
+			}
+		}
+
+		scimUser.DisplayName = u.PersonalInfo.FullName
+
+		if u.PersonalInfo.Phone != "" {
+			scimUser.PhoneNumbers = []MultiValuedAttribute{
+				{Value: u.PersonalInfo.Phone, Type: "work", Primary: true},
+			}
+		}
+	}
+
+	if u.Metadata != nil {
+		scimUser.Meta.Created = u.Metadata.CreatedAt
+		scimUser.Meta.LastModified = u.Metadata.UpdatedAt
+	}
+
+	if s.Config.BaseURL != "" {
+		scimUser.Meta.Location = s.Config.BaseURL + "/Users/" + u.ID
+	}
+
+	return scimUser
+}
+
+// toRemovedSCIMUser maps a user's account onto the SCIM representation of a user who is no
+// longer active in the organisation, without their account being deactivated
+func (s *Service) toRemovedSCIMUser(u *userv2.UniversalUser, organisationID string) *User {
+	scimUser := s.toSCIMUser(u, organisationID)
+
+	active := false
+	scimUser.Active = &active
+
+	return scimUser
+}
+
+// nameFromSCIMUser returns the first, last and full name given in a SCIM user
+func nameFromSCIMUser(scimUser *User) (string, string, string) {
+	fullName := strings.TrimSpace(scimUser.DisplayName)
+	if scimUser.Name == nil {
+		return "", "", fullName
+	}
+
+	if formatted := strings.TrimSpace(scimUser.Name.Formatted); formatted != "" {
+		fullName = formatted
+	}
+
+	return strings.TrimSpace(scimUser.Name.GivenName), strings.TrimSpace(scimUser.Name.FamilyName), fullName
+}
+
+// userExternalIDKey returns the user extension key holding the organisation's external ID
+// for the user, users can be provisioned by more than one organisation
+func userExternalIDKey(organisationID string) string {
+	return UserExtensionKeyExternalIDPrefix + organisationID
+}
+
+// isProvisionedBy reports whether the organisation's identity provider created the account
+func isProvisionedBy(u *userv2.UniversalUser, organisationID string) bool {
+	provisionedBy, _ := u.Extensions[UserExtensionKeyProvisionedBy].(string)
+	return provisionedBy != "" && provisionedBy == organisationID
+}
+
+// isActiveMember reports whether the member has joined rather than been invited
+func isActiveMember(member group.Member) bool {
+	return member.InvitationState == "" && member.InvitedAt == ""
+}
@@ -0,0 +1,708 @@
+package scim_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/apitoken"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/scim"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+)
+
+const testOrganisationID = "org-1"
+
+// fakeUserService keeps users in memory the way the user service would
+type fakeUserService struct {
+	users map[string]*userv2.UniversalUser
+}
+
+func (f *fakeUserService) CreateUser(ctx context.Context, req *userv2.CreateUserRequest) (*userv2.CreateUserResponse, error) {
+	for _, existingUser := range f.users {
+		if existingUser.Email == req.Email {
+			return nil, userv2.ErrEmailAlreadyExists
+		}
+	}
+
+	createdUser := &userv2.UniversalUser{
+		ID:         fmt.Sprintf("user-%d", len(f.users)+1),
+		Email:      req.Email,
+		Status:     req.Status,
+		Extensions: req.Extensions,
+		PersonalInfo: &userv2.PersonalInfo{
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+			FullName:  req.FullName,
+			Phone:     req.Phone,
+		},
+	}
+	f.users[createdUser.ID] = createdUser
+
+	return &userv2.CreateUserResponse{User: createdUser}, nil
+}
+
+func (f *fakeUserService) GetUserByID(ctx context.Context, req *userv2.GetUserByIDRequest) (*userv2.GetUserByIDResponse, error) {
+	existingUser, ok := f.users[req.ID]
+	if !ok {
+		return nil, userv2.ErrUserNotFound
+	}
+	return &userv2.GetUserByIDResponse{User: existingUser}, nil
+}
+
+func (f *fakeUserService) FindUserByEmail(ctx context.Context, req *userv2.GetUserByEmailRequest) (*userv2.GetUserByEmailResponse, error) {
+	// the user service normalises emails before looking them up
+	for _, existingUser := range f.users {
+		if strings.EqualFold(existingUser.Email, req.Email) {
+			return &userv2.GetUserByEmailResponse{User: existingUser}, nil
+		}
+	}
+	return nil, userv2.ErrUserNotFound
+}
+
+func (f *fakeUserService) GetUsers(ctx context.Context, req *userv2.GetUsersRequest) (*userv2.GetUsersResponse, error) {
+	users := []userv2.UniversalUser{}
+	for _, id := range req.IDsFilter {
+		if existingUser, ok := f.users[id]; ok {
+			users = append(users, *existingUser)
+		}
+	}
+	return &userv2.GetUsersResponse{Users: users}, nil
+}
+
+func (f *fakeUserService) UpdateUser(ctx context.Context, req *userv2.UpdateUserRequest) (*userv2.UpdateUserResponse, error) {
+	existingUser, ok := f.users[req.ID]
+	if !ok {
+		return nil, userv2.ErrUserNotFound
+	}
+
+	if req.Status == userv2.AccountStatusKeyActive && existingUser.Status == userv2.AccountStatusKeyDeactivated {
+		// the default user configuration does not allow reactivation
+		return nil, userv2.ErrUserInvalidStatusTransition
+	}
+
+	if req.Status != "" {
+		existingUser.Status = req.Status
+	}
+	if req.Email != "" {
+		existingUser.Email = req.Email
+	}
+	if req.FirstName != "" {
+		existingUser.PersonalInfo.FirstName = req.FirstName
+	}
+	for key, value := range req.Extensions {
+		if existingUser.Extensions == nil {
+			existingUser.Extensions = map[string]interface{}{}
+		}
+		existingUser.Extensions[key] = value
+	}
+
+	return &userv2.UpdateUserResponse{User: existingUser}, nil
+}
+
+// fakeGroupService keeps groups in memory the way the group service would
+type fakeGroupService struct {
+	groups        map[string]*group.UniversalGroup
+	permissions   map[string]bool
+	removedMember []string
+}
+
+func (f *fakeGroupService) GetGroupByID(ctx context.Context, req *group.GetGroupByIDRequest) (*group.GetGroupByIDResponse, error) {
+	existingGroup, ok := f.groups[req.ID]
+	if !ok {
+		return nil, group.ErrResourceNotFound
+	}
+	return &group.GetGroupByIDResponse{Group: existingGroup}, nil
+}
+
+func (f *fakeGroupService) GetGroupMembers(ctx context.Context, req *group.GetGroupMembersRequest) (*group.GetGroupMembersResponse, error) {
+	members := []group.Member{}
+	for _, member := range f.groups[req.GroupID].Members {
+		if req.MemberType == "" || member.Type == req.MemberType {
+			members = append(members, member)
+		}
+	}
+	return &group.GetGroupMembersResponse{Members: members, Count: len(members)}, nil
+}
+
+func (f *fakeGroupService) GetGroupDescendants(ctx context.Context, req *group.GetGroupDescendantsRequest) (*group.GetGroupDescendantsResponse, error) {
+	level := []group.GroupDescendantsNode{}
+	for _, existingGroup := range f.groups {
+		if existingGroup.ParentGroupID == req.ID {
+			level = append(level, group.GroupDescendantsNode{ID: existingGroup.ID, ParentGroupID: req.ID, Name: existingGroup.Name})
+		}
+	}
+	return &group.GetGroupDescendantsResponse{Descendants: [][]group.GroupDescendantsNode{level}}, nil
+}
+
+func (f *fakeGroupService) GetGroupsByUserID(ctx context.Context, req *group.GetGroupsByUserIDRequest) (*group.GetGroupsByUserIDResponse, error) {
+	groups := []*group.UniversalGroup{}
+	for _, existingGroup := range f.groups {
+		for _, member := range existingGroup.Members {
+			if member.ID == req.UserID {
+				groups = append(groups, existingGroup)
+				break
+			}
+		}
+	}
+	return &group.GetGroupsByUserIDResponse{Groups: groups}, nil
+}
+
+func (f *fakeGroupService) CreateGroup(ctx context.Context, req *group.CreateGroupRequest) (*group.CreateGroupResponse, error) {
+	createdGroup := &group.UniversalGroup{
+		ID:            fmt.Sprintf("group-%d", len(f.groups)+1),
+		Name:          req.Name,
+		RawName:       req.Name,
+		Type:          req.Type,
+		Status:        group.GroupStatusActive,
+		ParentGroupID: req.ParentGroupID,
+		Lineage:       []string{req.ParentGroupID},
+		Extensions:    req.Extensions,
+	}
+	f.groups[createdGroup.ID] = createdGroup
+	return &group.CreateGroupResponse{Group: createdGroup}, nil
+}
+
+func (f *fakeGroupService) UpdateGroup(ctx context.Context, req *group.UpdateGroupRequest) (*group.UpdateGroupResponse, error) {
+	existingGroup := f.groups[req.ID]
+	if req.Name != nil {
+		existingGroup.RawName = *req.Name
+	}
+	return &group.UpdateGroupResponse{Group: existingGroup}, nil
+}
+
+func (f *fakeGroupService) DeleteGroup(ctx context.Context, req *group.DeleteGroupRequest) (*group.DeleteGroupResponse, error) {
+	f.groups[req.ID].Metadata = &group.GroupMetadata{DeletedAt: "2026-10-18T00:00:00Z"}
+	return &group.DeleteGroupResponse{Success: true}, nil
+}
+
+func (f *fakeGroupService) AddMember(ctx context.Context, req *group.AddMemberRequest) (*group.AddMemberResponse, error) {
+	existingGroup := f.groups[req.GroupID]
+	for _, member := range existingGroup.Members {
+		if member.ID == req.MemberID {
+			return nil, group.ErrMemberAlreadyExists
+		}
+	}
+	existingGroup.Members = append(existingGroup.Members, group.Member{ID: req.MemberID, Type: req.Type, Role: req.Role})
+	return &group.AddMemberResponse{Group: existingGroup}, nil
+}
+
+func (f *fakeGroupService) RemoveMember(ctx context.Context, req *group.RemoveMemberRequest) (*group.RemoveMemberResponse, error) {
+	existingGroup := f.groups[req.GroupID]
+	if existingGroup.OwnerID == req.MemberID && !req.ConfirmOwnerRemoval {
+		return nil, group.ErrOwnerRemovalRequiresConfirm
+	}
+
+	remainingMembers := []group.Member{}
+	for _, member := range existingGroup.Members {
+		if member.ID != req.MemberID {
+			remainingMembers = append(remainingMembers, member)
+		}
+	}
+	existingGroup.Members = remainingMembers
+	f.removedMember = append(f.removedMember, req.GroupID+"/"+req.MemberID)
+
+	return &group.RemoveMemberResponse{}, nil
+}
+
+func (f *fakeGroupService) Can(ctx context.Context, userID, groupID, permission string) (bool, error) {
+	return f.permissions[userID+"/"+groupID+"/"+permission], nil
+}
+
+// fakeApiTokenService issues tokens in the `<prefix>.<secret>` form the api token service uses
+type fakeApiTokenService struct {
+	tokens map[string]apitoken.UserAPIToken
+}
+
+func (f *fakeApiTokenService) CreateAPIToken(ctx context.Context, r *apitoken.CreateAPITokenRequest) (*apitoken.CreateAPITokenResponse, error) {
+	token := apitoken.UserAPIToken{
+		ID:              fmt.Sprintf("token-%d", len(f.tokens)+1),
+		Value:           r.UserNanoId + ".secret",
+		Status:          apitoken.UserTokenStatusKeyActive,
+		Description:     r.Description,
+		CreatedByID:     r.UserID,
+		CreatedByNanoId: r.UserNanoId,
+	}
+	f.tokens[token.Value] = token
+	return &apitoken.CreateAPITokenResponse{APIToken: token}, nil
+}
+
+func (f *fakeApiTokenService) ValidateAPIToken(ctx context.Context, userFullToken string) (*apitoken.APITokenRequester, error) {
+	token, ok := f.tokens[userFullToken]
+	if !ok || token.Status != apitoken.UserTokenStatusKeyActive {
+		return nil, apitoken.ErrUnableToValidateUserAPIToken
+	}
+	return &apitoken.APITokenRequester{UserID: token.CreatedByID, NanoId: token.CreatedByNanoId, IsValid: true}, nil
+}
+
+func (f *fakeApiTokenService) UpdateAPITokenLastUsedAt(ctx context.Context, r *apitoken.UpdateAPITokenLastUsedAtRequest) error {
+	return nil
+}
+
+func (f *fakeApiTokenService) GetAPITokensFor(ctx context.Context, r *apitoken.GetAPITokensForRequest) (*apitoken.GetAPITokensForResponse, error) {
+	tokens := []apitoken.UserAPIToken{}
+	for _, token := range f.tokens {
+		if token.CreatedByID == r.ID {
+			tokens = append(tokens, token)
+		}
+	}
+	return &apitoken.GetAPITokensForResponse{APITokens: tokens, TotalPages: 1}, nil
+}
+
+func (f *fakeApiTokenService) GetAPIToken(ctx context.Context, r *apitoken.GetAPITokenRequest) (*apitoken.GetAPITokenResponse, error) {
+	for _, token := range f.tokens {
+		if token.ID == r.ID {
+			return &apitoken.GetAPITokenResponse{APIToken: token}, nil
+		}
+	}
+	return nil, apitoken.ErrUnableToValidateUserAPIToken
+}
+
+func (f *fakeApiTokenService) RevokeAPIToken(ctx context.Context, r *apitoken.RevokeAPITokenRequest) error {
+	for value, token := range f.tokens {
+		if token.ID == r.ID {
+			token.Status = apitoken.UserTokenStatusKeyRevoked
+			f.tokens[value] = token
+		}
+	}
+	return nil
+}
+
+// newTestService returns a SCIM service over an organisation with an owner, a member and
+// a team, alongside a user from outside the organisation
+func newTestService() (*scim.Service, *fakeUserService, *fakeGroupService, *fakeApiTokenService) {
+	userService := &fakeUserService{
+		users: map[string]*userv2.UniversalUser{
+			"owner":    {ID: "owner", Email: "owner@example.com", Status: userv2.AccountStatusKeyActive, PersonalInfo: &userv2.PersonalInfo{}},
+			"jane":     {ID: "jane", Email: "jane@example.com", Status: userv2.AccountStatusKeyActive, PersonalInfo: &userv2.PersonalInfo{FirstName: "Jane", FullName: "Jane Doe"}},
+			"invitee":  {ID: "invitee", Email: "invitee@example.com", Status: userv2.AccountStatusKeyProvisioned, PersonalInfo: &userv2.PersonalInfo{}},
+			"outsider": {ID: "outsider", Email: "outsider@example.com", Status: userv2.AccountStatusKeyActive, PersonalInfo: &userv2.PersonalInfo{}},
+		},
+	}
+
+	groupService := &fakeGroupService{
+		groups: map[string]*group.UniversalGroup{
+			testOrganisationID: {
+				ID:      testOrganisationID,
+				NanoID:  "orgnano",
+				Name:    "acme",
+				Status:  group.GroupStatusActive,
+				OwnerID: "owner",
+				Members: []group.Member{
+					{ID: "owner", Type: group.MemberTypeUser, Role: group.MemberRoleOwner},
+					{ID: "jane", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+					{ID: "invitee", Type: group.MemberTypeUser, Role: group.MemberRoleMember, InvitationState: group.MemberInvitationStateInvited, InvitedAt: "2026-10-01T00:00:00Z"},
+				},
+			},
+			"team-1": {
+				ID:            "team-1",
+				Name:          "engineering",
+				RawName:       "Engineering",
+				Status:        group.GroupStatusActive,
+				ParentGroupID: testOrganisationID,
+				Lineage:       []string{testOrganisationID},
+				Members: []group.Member{
+					{ID: "jane", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+				},
+			},
+		},
+		permissions: map[string]bool{
+			"owner/" + testOrganisationID + "/" + group.PermissionSCIMManage: true,
+		},
+	}
+
+	apiTokenService := &fakeApiTokenService{tokens: map[string]apitoken.UserAPIToken{}}
+
+	service := scim.NewService(userService, groupService, apiTokenService, &scim.Config{BaseURL: "https://app.example.com/scim/v2/"})
+
+	return service, userService, groupService, apiTokenService
+}
+
+func TestService_TokenLifecycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, _, groupService, _ := newTestService()
+
+	_, err := service.IssueToken(ctx, &scim.IssueTokenRequest{GroupID: testOrganisationID, ActorID: "jane"})
+	assert.ErrorIs(t, err, scim.ErrForbidden)
+
+	_, err = service.IssueToken(ctx, &scim.IssueTokenRequest{GroupID: "team-1", ActorID: "owner"})
+	assert.ErrorIs(t, err, scim.ErrOrganisationNotRootGroup)
+
+	issued, err := service.IssueToken(ctx, &scim.IssueTokenRequest{GroupID: testOrganisationID, ActorID: "owner"})
+	require.NoError(t, err)
+	assert.Equal(t, "orgnano.secret", issued.Token.Value)
+	assert.Equal(t, scim.TokenDescription, issued.Token.Description)
+
+	authenticated, err := service.Authenticate(ctx, &scim.AuthenticateRequest{Token: issued.Token.Value})
+	require.NoError(t, err)
+	assert.Equal(t, testOrganisationID, authenticated.OrganisationID)
+
+	_, err = service.Authenticate(ctx, &scim.AuthenticateRequest{Token: "orgnano.wrong"})
+	assert.ErrorIs(t, err, scim.ErrUnauthorised)
+
+	tokens, err := service.GetTokens(ctx, &scim.GetTokensRequest{GroupID: testOrganisationID, ActorID: "owner"})
+	require.NoError(t, err)
+	assert.Len(t, tokens.Tokens, 1)
+
+	// tokens stop working once their organisation is deleted
+	groupService.groups[testOrganisationID].Metadata = &group.GroupMetadata{DeletedAt: "2026-10-18T00:00:00Z"}
+	_, err = service.Authenticate(ctx, &scim.AuthenticateRequest{Token: issued.Token.Value})
+	assert.ErrorIs(t, err, scim.ErrUnauthorised)
+	groupService.groups[testOrganisationID].Metadata = nil
+
+	require.NoError(t, service.RevokeToken(ctx, &scim.RevokeTokenRequest{GroupID: testOrganisationID, TokenID: issued.Token.ID, ActorID: "owner"}))
+	_, err = service.Authenticate(ctx, &scim.AuthenticateRequest{Token: issued.Token.Value})
+	assert.ErrorIs(t, err, scim.ErrUnauthorised)
+}
+
+func TestService_GetUsers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, _, _, _ := newTestService()
+
+	count := 1
+	tests := []struct {
+		name            string
+		request         *scim.GetUsersRequest
+		expectedTotal   int
+		expectedUserIDs []string
+		expectedError   error
+	}{
+		{
+			name:            "Success - active members only",
+			request:         &scim.GetUsersRequest{ListResourcesRequest: scim.ListResourcesRequest{OrganisationID: testOrganisationID}},
+			expectedTotal:   2,
+			expectedUserIDs: []string{"owner", "jane"},
+		},
+		{
+			name:            "Success - userName lookup",
+			request:         &scim.GetUsersRequest{ListResourcesRequest: scim.ListResourcesRequest{OrganisationID: testOrganisationID, Filter: `userName eq "JANE@example.com"`}},
+			expectedTotal:   1,
+			expectedUserIDs: []string{"jane"},
+		},
+		{
+			name:            "Success - userName lookup outside the organisation",
+			request:         &scim.GetUsersRequest{ListResourcesRequest: scim.ListResourcesRequest{OrganisationID: testOrganisationID, Filter: `userName eq "outsider@example.com"`}},
+			expectedTotal:   0,
+			expectedUserIDs: []string{},
+		},
+		{
+			name:            "Success - paginated",
+			request:         &scim.GetUsersRequest{ListResourcesRequest: scim.ListResourcesRequest{OrganisationID: testOrganisationID, StartIndex: 2, Count: &count}},
+			expectedTotal:   2,
+			expectedUserIDs: []string{"jane"},
+		},
+		{
+			name:          "Failed - invalid filter",
+			request:       &scim.GetUsersRequest{ListResourcesRequest: scim.ListResourcesRequest{OrganisationID: testOrganisationID, Filter: `userName is "jane"`}},
+			expectedError: scim.ErrInvalidFilter,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			response, err := service.GetUsers(ctx, test.request)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedTotal, response.TotalResults)
+
+			userIDs := []string{}
+			for _, resource := range response.Resources {
+				userIDs = append(userIDs, resource.(*scim.User).ID)
+			}
+			assert.ElementsMatch(t, test.expectedUserIDs, userIDs)
+		})
+	}
+}
+
+func TestService_CreateUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Success - new user is created and joins the organisation", func(t *testing.T) {
+		t.Parallel()
+
+		service, userService, groupService, _ := newTestService()
+
+		response, err := service.CreateUser(ctx, &scim.CreateUserRequest{
+			OrganisationID: testOrganisationID,
+			User: &scim.User{
+				UserName:   "new@example.com",
+				ExternalID: "00u-new",
+				Name:       &scim.Name{GivenName: "New", FamilyName: "Starter"},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "new@example.com", response.User.UserName)
+		assert.Equal(t, "00u-new", response.User.ExternalID)
+		assert.True(t, *response.User.Active)
+		assert.Equal(t, "https://app.example.com/scim/v2/Users/"+response.User.ID, response.User.Meta.Location)
+
+		createdUser := userService.users[response.User.ID]
+		assert.Equal(t, userv2.AccountStatusKeyActive, createdUser.Status)
+		assert.Equal(t, "00u-new", createdUser.Extensions[scim.UserExtensionKeyExternalIDPrefix+testOrganisationID])
+		assert.Equal(t, testOrganisationID, createdUser.Extensions[scim.UserExtensionKeyProvisionedBy])
+
+		assert.Contains(t, groupService.groups[testOrganisationID].Members, group.Member{ID: response.User.ID, Type: group.MemberTypeUser, Role: group.MemberRoleMember})
+	})
+
+	t.Run("Success - existing account joins the organisation without being changed", func(t *testing.T) {
+		t.Parallel()
+
+		service, userService, groupService, _ := newTestService()
+
+		response, err := service.CreateUser(ctx, &scim.CreateUserRequest{
+			OrganisationID: testOrganisationID,
+			User: &scim.User{
+				UserName:   "outsider@example.com",
+				ExternalID: "00u-outsider",
+				Name:       &scim.Name{GivenName: "Renamed"},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "outsider", response.User.ID)
+		assert.Equal(t, "00u-outsider", response.User.ExternalID)
+		assert.Len(t, userService.users, 4)
+
+		outsider := userService.users["outsider"]
+		assert.Empty(t, outsider.PersonalInfo.FirstName)
+		assert.Nil(t, outsider.Extensions[scim.UserExtensionKeyProvisionedBy])
+		assert.Contains(t, groupService.groups[testOrganisationID].Members, group.Member{ID: "outsider", Type: group.MemberTypeUser, Role: group.MemberRoleMember})
+	})
+
+	t.Run("Success - inactive existing account is not added", func(t *testing.T) {
+		t.Parallel()
+
+		service, userService, groupService, _ := newTestService()
+
+		active := false
+		response, err := service.CreateUser(ctx, &scim.CreateUserRequest{
+			OrganisationID: testOrganisationID,
+			User:           &scim.User{UserName: "outsider@example.com", Active: &active},
+		})
+		require.NoError(t, err)
+
+		assert.False(t, *response.User.Active)
+		assert.Equal(t, userv2.AccountStatusKeyActive, userService.users["outsider"].Status)
+		assert.Len(t, groupService.groups[testOrganisationID].Members, 3)
+	})
+
+	t.Run("Failed - already provisioned", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, _, _ := newTestService()
+
+		_, err := service.CreateUser(ctx, &scim.CreateUserRequest{
+			OrganisationID: testOrganisationID,
+			User:           &scim.User{UserName: "jane@example.com"},
+		})
+		assert.ErrorIs(t, err, scim.ErrUserAlreadyProvisioned)
+	})
+}
+
+func TestService_PatchUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Success - deactivate a provisioned user with a string value", func(t *testing.T) {
+		t.Parallel()
+
+		service, userService, _, _ := newTestService()
+		userService.users["jane"].Extensions = map[string]interface{}{scim.UserExtensionKeyProvisionedBy: testOrganisationID}
+
+		response, err := service.PatchUser(ctx, &scim.PatchUserRequest{
+			OrganisationID: testOrganisationID,
+			ID:             "jane",
+			Operations:     []scim.PatchOperation{{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)}},
+		})
+		require.NoError(t, err)
+
+		assert.False(t, *response.User.Active)
+		assert.Equal(t, userv2.AccountStatusKeyDeactivated, userService.users["jane"].Status)
+	})
+
+	t.Run("Success - deactivating a shared user removes them from the organisation", func(t *testing.T) {
+		t.Parallel()
+
+		service, userService, groupService, _ := newTestService()
+
+		response, err := service.PatchUser(ctx, &scim.PatchUserRequest{
+			OrganisationID: testOrganisationID,
+			ID:             "jane",
+			Operations:     []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}},
+		})
+		require.NoError(t, err)
+
+		assert.False(t, *response.User.Active)
+		assert.Equal(t, userv2.AccountStatusKeyActive, userService.users["jane"].Status)
+		assert.Equal(t, []string{testOrganisationID + "/jane"}, groupService.removedMember)
+	})
+
+	t.Run("Success - shared user identity is left unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		service, userService, _, _ := newTestService()
+
+		response, err := service.PatchUser(ctx, &scim.PatchUserRequest{
+			OrganisationID: testOrganisationID,
+			ID:             "jane",
+			Operations: []scim.PatchOperation{
+				{Op: "replace", Path: "userName", Value: json.RawMessage(`"taken@example.com"`)},
+				{Op: "replace", Path: "externalId", Value: json.RawMessage(`"00u-jane"`)},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "jane@example.com", response.User.UserName)
+		assert.Equal(t, "00u-jane", response.User.ExternalID)
+		assert.Equal(t, "jane@example.com", userService.users["jane"].Email)
+	})
+
+	t.Run("Failed - reactivation not allowed by user configuration", func(t *testing.T) {
+		t.Parallel()
+
+		service, userService, _, _ := newTestService()
+		userService.users["jane"].Status = userv2.AccountStatusKeyDeactivated
+		userService.users["jane"].Extensions = map[string]interface{}{scim.UserExtensionKeyProvisionedBy: testOrganisationID}
+
+		_, err := service.PatchUser(ctx, &scim.PatchUserRequest{
+			OrganisationID: testOrganisationID,
+			ID:             "jane",
+			Operations:     []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`true`)}},
+		})
+		assert.ErrorIs(t, err, scim.ErrInvalidStatusTransition)
+	})
+
+	t.Run("Failed - user outside the organisation", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, _, _ := newTestService()
+
+		_, err := service.PatchUser(ctx, &scim.PatchUserRequest{
+			OrganisationID: testOrganisationID,
+			ID:             "outsider",
+			Operations:     []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}},
+		})
+		assert.ErrorIs(t, err, scim.ErrResourceNotFound)
+	})
+}
+
+func TestService_DeleteUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, _, groupService, _ := newTestService()
+
+	require.NoError(t, service.DeleteUser(ctx, &scim.DeleteUserRequest{OrganisationID: testOrganisationID, ID: "jane"}))
+	assert.Equal(t, []string{testOrganisationID + "/jane"}, groupService.removedMember)
+
+	err := service.DeleteUser(ctx, &scim.DeleteUserRequest{OrganisationID: testOrganisationID, ID: "owner"})
+	assert.ErrorIs(t, err, scim.ErrOrganisationOwnerRequired)
+}
+
+func TestService_Groups(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Success - create with members", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, groupService, _ := newTestService()
+
+		response, err := service.CreateGroup(ctx, &scim.CreateGroupRequest{
+			OrganisationID: testOrganisationID,
+			Group: &scim.Group{
+				DisplayName: "Design",
+				ExternalID:  "grp-design",
+				Members:     []scim.GroupMember{{Value: "jane"}, {Value: "team-1", Type: "Group"}},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Design", response.Group.DisplayName)
+		assert.Equal(t, "grp-design", response.Group.ExternalID)
+		assert.ElementsMatch(t, []scim.GroupMember{
+			{Value: "jane", Type: scim.ResourceTypeUser, Ref: "https://app.example.com/scim/v2/Users/jane"},
+			{Value: "team-1", Type: scim.ResourceTypeGroup, Ref: "https://app.example.com/scim/v2/Groups/team-1"},
+		}, response.Group.Members)
+		assert.Equal(t, testOrganisationID, groupService.groups[response.Group.ID].ParentGroupID)
+		assert.Equal(t, group.GroupTypeTeam, groupService.groups[response.Group.ID].Type)
+	})
+
+	t.Run("Failed - member outside the organisation", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, groupService, _ := newTestService()
+
+		_, err := service.CreateGroup(ctx, &scim.CreateGroupRequest{
+			OrganisationID: testOrganisationID,
+			Group:          &scim.Group{DisplayName: "Design", Members: []scim.GroupMember{{Value: "outsider"}}},
+		})
+		assert.ErrorIs(t, err, scim.ErrMemberNotInOrganisation)
+		assert.Len(t, groupService.groups, 2)
+	})
+
+	t.Run("Success - patch renames and reconciles members", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, groupService, _ := newTestService()
+
+		response, err := service.PatchGroup(ctx, &scim.PatchGroupRequest{
+			OrganisationID: testOrganisationID,
+			ID:             "team-1",
+			Operations: []scim.PatchOperation{
+				{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Platform"`)},
+				{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"owner"}]`)},
+				{Op: "remove", Path: `members[value eq "jane"]`},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Platform", response.Group.DisplayName)
+		assert.Equal(t, []scim.GroupMember{{Value: "owner", Type: scim.ResourceTypeUser, Ref: "https://app.example.com/scim/v2/Users/owner"}}, response.Group.Members)
+		assert.Equal(t, []string{"team-1/jane"}, groupService.removedMember)
+	})
+
+	t.Run("Success - list and delete", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, _, _ := newTestService()
+
+		listed, err := service.GetGroups(ctx, &scim.GetGroupsRequest{ListResourcesRequest: scim.ListResourcesRequest{
+			OrganisationID:     testOrganisationID,
+			Filter:             `displayName eq "engineering"`,
+			ExcludedAttributes: "members",
+		}})
+		require.NoError(t, err)
+		require.Equal(t, 1, listed.TotalResults)
+		assert.Empty(t, listed.Resources[0].(*scim.Group).Members)
+
+		require.NoError(t, service.DeleteGroup(ctx, &scim.DeleteGroupRequest{OrganisationID: testOrganisationID, ID: "team-1"}))
+
+		_, err = service.GetGroup(ctx, &scim.GetGroupRequest{OrganisationID: testOrganisationID, ID: "team-1"})
+		assert.ErrorIs(t, err, scim.ErrResourceNotFound)
+
+		_, err = service.GetGroup(ctx, &scim.GetGroupRequest{OrganisationID: testOrganisationID, ID: testOrganisationID})
+		assert.ErrorIs(t, err, scim.ErrResourceNotFound)
+	})
+}
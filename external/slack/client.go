@@ -0,0 +1,253 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// maxResponseBodySize caps how much of a Slack response is read
+const maxResponseBodySize = 64 * 1024
+
+// retryableWebAPIErrors are the chat.postMessage error codes worth retrying
+var retryableWebAPIErrors = map[string]bool{
+	"ratelimited":         true,
+	"internal_error":      true,
+	"fatal_error":         true,
+	"request_timeout":     true,
+	"service_unavailable": true,
+}
+
+// webAPIResponse is the envelope every Slack Web API method responds with
+type webAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// attemptResult describes the outcome of one delivery attempt
+type attemptResult struct {
+	err        error
+	retryable  bool
+	retryAfter time.Duration
+}
+
+// client posts messages to Slack, retrying deliveries that fail for transient reasons
+type client struct {
+	httpClient    *http.Client
+	maxRetries    int
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+}
+
+// newClient creates a client from the sender's config, applying defaults
+func newClient(config *Config) *client {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultRequestTimeout}
+	}
+
+	maxRetries := config.MaxRetries
+	switch {
+	case maxRetries == 0:
+		maxRetries = DefaultMaxRetries
+	case maxRetries < 0:
+		maxRetries = 0
+	}
+
+	retryDelay := config.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = DefaultRetryDelay
+	}
+
+	maxRetryDelay := config.MaxRetryDelay
+	if maxRetryDelay <= 0 {
+		maxRetryDelay = DefaultMaxRetryDelay
+	}
+
+	return &client{
+		httpClient:    httpClient,
+		maxRetries:    maxRetries,
+		retryDelay:    retryDelay,
+		maxRetryDelay: maxRetryDelay,
+	}
+}
+
+// postWebhook posts a message to an incoming webhook
+func (c *client) postWebhook(ctx context.Context, webhookURL string, message *Message) error {
+	webhookMessage := *message
+	webhookMessage.Channel = ""
+
+	body, err := json.Marshal(&webhookMessage)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, func() (*http.Request, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		return request, nil
+	}, readWebhookResponse)
+}
+
+// postMessage posts a message to its channel with the chat.postMessage API
+func (c *client) postMessage(ctx context.Context, apiBaseURL, botToken string, message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, func() (*http.Request, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBaseURL+postMessagePath, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Content-Type", "application/json; charset=utf-8")
+		request.Header.Set("Authorization", "Bearer "+botToken)
+		return request, nil
+	}, readWebAPIResponse)
+}
+
+// send delivers a request, retrying with exponential backoff while the failure
+// is transient and retries remain
+func (c *client) send(ctx context.Context, newRequest func() (*http.Request, error), readResponse func(*http.Response) attemptResult) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/slack", "send")
+
+	for attempt := 0; ; attempt++ {
+		request, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		result := c.attempt(request, readResponse)
+		if result.err == nil {
+			return nil
+		}
+
+		if !result.retryable || attempt >= c.maxRetries {
+			logger.Warn("slack-delivery-failed", zap.Int("attempts", attempt+1), zap.Error(result.err))
+			return result.err
+		}
+
+		delay := c.retryDelayFor(attempt, result.retryAfter)
+		logger.Debug("slack-delivery-attempt-failed", zap.Int("attempt", attempt+1), zap.Duration("retry-in", delay), zap.Error(result.err))
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return errors.Join(result.err, err)
+		}
+	}
+}
+
+// attempt makes one request to Slack
+func (c *client) attempt(request *http.Request, readResponse func(*http.Response) attemptResult) attemptResult {
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return attemptResult{
+			err:       fmt.Errorf("%w: %v", ErrDeliveryFailed, err),
+			retryable: request.Context().Err() == nil,
+		}
+	}
+	defer response.Body.Close()
+
+	return readResponse(response)
+}
+
+// retryDelayFor returns how long to wait before retrying, preferring the delay
+// Slack asked for
+func (c *client) retryDelayFor(attempt int, retryAfter time.Duration) time.Duration {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = c.retryDelay << attempt
+	}
+
+	if delay <= 0 || delay > c.maxRetryDelay {
+		delay = c.maxRetryDelay
+	}
+
+	return delay
+}
+
+// readWebhookResponse interprets an incoming webhook response, which is the plain
+// text "ok" on success and an error code otherwise
+func readWebhookResponse(response *http.Response) attemptResult {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBodySize))
+
+	if response.StatusCode == http.StatusOK {
+		return attemptResult{}
+	}
+
+	return attemptResult{
+		err:        fmt.Errorf("%w: status %d: %s", ErrDeliveryFailed, response.StatusCode, strings.TrimSpace(string(body))),
+		retryable:  isRetryableStatus(response.StatusCode),
+		retryAfter: parseRetryAfter(response),
+	}
+}
+
+// readWebAPIResponse interprets a Web API response, which reports failures in its
+// body even when the status is 200
+func readWebAPIResponse(response *http.Response) attemptResult {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBodySize))
+
+	if response.StatusCode != http.StatusOK {
+		return attemptResult{
+			err:        fmt.Errorf("%w: status %d: %s", ErrDeliveryFailed, response.StatusCode, strings.TrimSpace(string(body))),
+			retryable:  isRetryableStatus(response.StatusCode),
+			retryAfter: parseRetryAfter(response),
+		}
+	}
+
+	apiResponse := webAPIResponse{}
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return attemptResult{err: fmt.Errorf("%w: invalid response: %v", ErrDeliveryFailed, err)}
+	}
+
+	if apiResponse.OK {
+		return attemptResult{}
+	}
+
+	return attemptResult{
+		err:        fmt.Errorf("%w: %s", ErrDeliveryFailed, apiResponse.Error),
+		retryable:  retryableWebAPIErrors[apiResponse.Error],
+		retryAfter: parseRetryAfter(response),
+	}
+}
+
+// isRetryableStatus reports whether a failed status is worth retrying
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter reads the number of seconds Slack asked clients to wait
+func parseRetryAfter(response *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(response.Header.Get("Retry-After")))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// sleepContext waits for the delay or until the context is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+// Package slack delivers group notifications to Slack.
+//
+// Messages are sent either through an incoming webhook, which always posts to the
+// channel the webhook was created for, or with a bot token, which posts to each
+// group's own channel (Integrations.Slack.DisplayID) and additional recipients.
+// Failed deliveries are retried with exponential backoff, honouring Slack's
+// rate limits.
+package slack
+
+import "time"
+
+// DeliveryMode names how messages are delivered to Slack
+type DeliveryMode string
+
+const (
+	// ModeWebhook delivers messages to a single channel through an incoming webhook
+	ModeWebhook DeliveryMode = "WEBHOOK"
+
+	// ModeBot delivers messages to each group's channels with a bot token
+	// through the chat.postMessage API
+	ModeBot DeliveryMode = "BOT"
+)
+
+const (
+	// DefaultAPIBaseURL is the base URL of the Slack Web API
+	DefaultAPIBaseURL = "https://slack.com/api"
+
+	// DefaultMaxRetries is how many times a failed delivery is retried when no
+	// limit is configured
+	DefaultMaxRetries = 3
+
+	// DefaultRetryDelay is the delay before the first retry, it doubles on every
+	// following retry
+	DefaultRetryDelay = 500 * time.Millisecond
+
+	// DefaultMaxRetryDelay caps the delay between retries, including delays
+	// requested by Slack's Retry-After header
+	DefaultMaxRetryDelay = 30 * time.Second
+
+	// DefaultRequestTimeout is the timeout of each request to Slack when no HTTP
+	// client is configured
+	DefaultRequestTimeout = 10 * time.Second
+
+	// postMessagePath is the Web API method used to post messages in bot mode
+	postMessagePath = "/chat.postMessage"
+)
+
+const (
+	// Block types
+	blockTypeSection = "section"
+	blockTypeContext = "context"
+
+	// Text object types
+	textTypeMarkdown = "mrkdwn"
+)
+
+const (
+	ErrKeyInvalidDeliveryMode = "SlackInvalidDeliveryMode"
+	ErrKeyMissingWebhookURL   = "SlackMissingWebhookURL"
+	ErrKeyMissingBotToken     = "SlackMissingBotToken"
+	ErrKeyMissingChannel      = "SlackMissingChannel"
+	ErrKeyUnsupportedEvent    = "SlackUnsupportedEvent"
+	ErrKeyDeliveryFailed      = "SlackDeliveryFailed"
+	ErrKeyGroupServiceNotSet  = "SlackGroupServiceNotSet"
+)
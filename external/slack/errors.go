@@ -0,0 +1,27 @@
+package slack
+
+import "errors"
+
+var (
+	// ErrInvalidDeliveryMode returned when the configured delivery mode is not supported
+	ErrInvalidDeliveryMode = errors.New(ErrKeyInvalidDeliveryMode)
+
+	// ErrMissingWebhookURL returned when webhook mode is configured without a webhook URL
+	ErrMissingWebhookURL = errors.New(ErrKeyMissingWebhookURL)
+
+	// ErrMissingBotToken returned when bot mode is configured without a bot token
+	ErrMissingBotToken = errors.New(ErrKeyMissingBotToken)
+
+	// ErrMissingChannel returned when a bot message has no channel to be posted to
+	ErrMissingChannel = errors.New(ErrKeyMissingChannel)
+
+	// ErrUnsupportedEvent returned when an event cannot be formatted as a Slack message
+	ErrUnsupportedEvent = errors.New(ErrKeyUnsupportedEvent)
+
+	// ErrDeliveryFailed returned when Slack rejects a message or it cannot be
+	// delivered after all retries
+	ErrDeliveryFailed = errors.New(ErrKeyDeliveryFailed)
+
+	// ErrGroupServiceNotSet returned when notifying a group by ID without a group service
+	ErrGroupServiceNotSet = errors.New(ErrKeyGroupServiceNotSet)
+)
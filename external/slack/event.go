@@ -0,0 +1,39 @@
+package slack
+
+import (
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/reminder"
+	"github.com/ooaklee/ghatd/external/vision"
+)
+
+// NewReminderDueEvent creates the event posted when a reminder owned by or
+// targeting a group is due
+func NewReminderDueEvent(dueReminder *reminder.Reminder, link string) *Event {
+	dueAt := dueReminder.NextDueAt
+	if dueAt == "" {
+		dueAt = dueReminder.TargetTime
+	}
+	if dueAt != "" && dueReminder.Timezone != "" {
+		dueAt += " (" + dueReminder.Timezone + ")"
+	}
+
+	return &Event{
+		Type:        group.IntegrationEventReminderDue,
+		Title:       dueReminder.Title,
+		Description: dueReminder.Description,
+		DueAt:       dueAt,
+		Link:        link,
+	}
+}
+
+// NewVisionStatusChangedEvent creates the event posted when a roadmap item moves
+// from its previous status to its current one
+func NewVisionStatusChangedEvent(changedVision *vision.Vision, previousStatus vision.VisionStatus, link string) *Event {
+	return &Event{
+		Type:           group.IntegrationEventVisionStatusChanged,
+		Title:          changedVision.Title,
+		Status:         string(changedVision.Status),
+		PreviousStatus: string(previousStatus),
+		Link:           link,
+	}
+}
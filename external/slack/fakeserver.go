@@ -0,0 +1,228 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// fakeWebhookPath is the path of the fake server's incoming webhook
+	fakeWebhookPath = "/webhook"
+
+	// fakeAPIPath is the path the fake server's Web API is served under
+	fakeAPIPath = "/api"
+)
+
+// FakeMessage is a message received by the fake Slack server
+type FakeMessage struct {
+	// Mode is how the message was delivered
+	Mode DeliveryMode
+
+	// Message is the message as posted, Channel is empty for webhook messages
+	Message Message
+}
+
+// fakeFailure is a failure the fake server responds with instead of accepting a message
+type fakeFailure struct {
+	statusCode int
+	retryAfter int
+	apiError   string
+}
+
+// FakeServer is a local stand in for Slack's incoming webhooks and chat.postMessage
+// API. It records the messages it accepts and can be told to fail upcoming requests,
+// for tests and local development
+type FakeServer struct {
+	server   *httptest.Server
+	botToken string
+
+	mu       sync.Mutex
+	messages []FakeMessage
+	failures []fakeFailure
+	requests int
+}
+
+// NewFakeServer starts a fake Slack server that accepts bot requests made with the
+// bot token. Close it when it is no longer needed
+func NewFakeServer(botToken string) *FakeServer {
+	fakeServer := &FakeServer{botToken: botToken}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(fakeWebhookPath, fakeServer.handleWebhook)
+	mux.HandleFunc(fakeAPIPath+postMessagePath, fakeServer.handlePostMessage)
+	fakeServer.server = httptest.NewServer(mux)
+
+	return fakeServer
+}
+
+// WebhookURL returns the URL of the fake incoming webhook
+func (f *FakeServer) WebhookURL() string {
+	return f.server.URL + fakeWebhookPath
+}
+
+// APIBaseURL returns the base URL of the fake Web API, for Config.APIBaseURL
+func (f *FakeServer) APIBaseURL() string {
+	return f.server.URL + fakeAPIPath
+}
+
+// Close shuts the fake server down
+func (f *FakeServer) Close() {
+	f.server.Close()
+}
+
+// Messages returns the messages accepted so far, oldest first
+func (f *FakeServer) Messages() []FakeMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeMessage(nil), f.messages...)
+}
+
+// Requests returns how many requests the fake server received, including failed ones
+func (f *FakeServer) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests
+}
+
+// FailNext makes the next request fail with the HTTP status. A positive retryAfter
+// is sent as the Retry-After header, in seconds
+func (f *FakeServer) FailNext(statusCode, retryAfter int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = append(f.failures, fakeFailure{statusCode: statusCode, retryAfter: retryAfter})
+}
+
+// FailNextWithError makes the next request fail with a Slack error code, such as
+// channel_not_found. Web API requests respond with ok false, webhooks with a 400
+func (f *FakeServer) FailNextWithError(code string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = append(f.failures, fakeFailure{apiError: code})
+}
+
+// nextFailure counts the request and pops the failure it should respond with, if any
+func (f *FakeServer) nextFailure() (fakeFailure, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	if len(f.failures) == 0 {
+		return fakeFailure{}, false
+	}
+
+	failure := f.failures[0]
+	f.failures = f.failures[1:]
+
+	return failure, true
+}
+
+// record stores an accepted message
+func (f *FakeServer) record(mode DeliveryMode, message Message) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = append(f.messages, FakeMessage{Mode: mode, Message: message})
+}
+
+// handleWebhook responds like an incoming webhook, plain text "ok" or an error code
+func (f *FakeServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "invalid_method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if failure, ok := f.nextFailure(); ok {
+		if failure.apiError != "" {
+			http.Error(w, failure.apiError, http.StatusBadRequest)
+			return
+		}
+		writeFakeHTTPFailure(w, failure)
+		return
+	}
+
+	message := Message{}
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(message.Text) == "" && len(message.Blocks) == 0 {
+		http.Error(w, "no_text", http.StatusBadRequest)
+		return
+	}
+
+	f.record(ModeWebhook, message)
+
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, "ok")
+}
+
+// handlePostMessage responds like chat.postMessage, with an ok envelope
+func (f *FakeServer) handlePostMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeFakeAPIResponse(w, map[string]interface{}{"ok": false, "error": "invalid_method"})
+		return
+	}
+
+	if failure, ok := f.nextFailure(); ok {
+		if failure.apiError != "" {
+			writeFakeAPIResponse(w, map[string]interface{}{"ok": false, "error": failure.apiError})
+			return
+		}
+		writeFakeHTTPFailure(w, failure)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+f.botToken {
+		writeFakeAPIResponse(w, map[string]interface{}{"ok": false, "error": "invalid_auth"})
+		return
+	}
+
+	message := Message{}
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		writeFakeAPIResponse(w, map[string]interface{}{"ok": false, "error": "invalid_json"})
+		return
+	}
+
+	if strings.TrimSpace(message.Channel) == "" {
+		writeFakeAPIResponse(w, map[string]interface{}{"ok": false, "error": "channel_not_found"})
+		return
+	}
+
+	if strings.TrimSpace(message.Text) == "" && len(message.Blocks) == 0 {
+		writeFakeAPIResponse(w, map[string]interface{}{"ok": false, "error": "no_text"})
+		return
+	}
+
+	f.record(ModeBot, message)
+
+	writeFakeAPIResponse(w, map[string]interface{}{
+		"ok":      true,
+		"channel": message.Channel,
+		"ts":      strconv.Itoa(len(f.Messages())) + ".000000",
+	})
+}
+
+// writeFakeHTTPFailure responds with a failed HTTP status
+func writeFakeHTTPFailure(w http.ResponseWriter, failure fakeFailure) {
+	if failure.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(failure.retryAfter))
+	}
+
+	http.Error(w, strings.ToLower(strings.ReplaceAll(http.StatusText(failure.statusCode), " ", "_")), failure.statusCode)
+}
+
+// writeFakeAPIResponse writes a Web API response envelope
+func writeFakeAPIResponse(w http.ResponseWriter, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
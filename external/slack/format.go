@@ -0,0 +1,161 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// slackTextEscaper escapes the characters Slack reserves for mentions and links
+var slackTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// formatMessage builds the message posted to a group's Slack channels for an event
+func formatMessage(targetGroup *group.UniversalGroup, integration *group.SlackIntegration, event *Event) (*Message, error) {
+	if event == nil {
+		return nil, ErrUnsupportedEvent
+	}
+
+	groupName := escapeText(groupDisplayName(targetGroup))
+	details := []string{}
+
+	var headline string
+	switch event.Type {
+	case group.IntegrationEventMemberJoined:
+		memberName := valueOrDefault(event.MemberName, "A new member")
+		headline = fmt.Sprintf("*%s* joined *%s*", escapeText(memberName), groupName)
+		if event.Role != "" {
+			details = append(details, "Role: "+escapeText(humanise(event.Role)))
+		}
+		if event.ActorName != "" && event.ActorName != event.MemberName {
+			details = append(details, "Added by "+escapeText(event.ActorName))
+		}
+
+	case group.IntegrationEventInviteAccepted:
+		memberName := valueOrDefault(event.MemberName, "An invitee")
+		headline = fmt.Sprintf("*%s* accepted their invitation to *%s*", escapeText(memberName), groupName)
+		if event.Role != "" {
+			details = append(details, "Role: "+escapeText(humanise(event.Role)))
+		}
+
+	case group.IntegrationEventReminderDue:
+		title := valueOrDefault(event.Title, "Untitled reminder")
+		headline = fmt.Sprintf("Reminder due in *%s*: *%s*", groupName, escapeText(title))
+		if event.Description != "" {
+			details = append(details, escapeText(event.Description))
+		}
+		if event.DueAt != "" {
+			details = append(details, "Due "+escapeText(event.DueAt))
+		}
+		if onDuty := onDutyMention(integration); onDuty != "" {
+			details = append(details, "On duty: "+onDuty)
+		}
+
+	case group.IntegrationEventVisionStatusChanged:
+		title := valueOrDefault(event.Title, "A roadmap item")
+		headline = fmt.Sprintf("Roadmap update for *%s*: *%s* is now *%s*", groupName, escapeText(title), escapeText(humanise(event.Status)))
+		if event.PreviousStatus != "" {
+			headline = fmt.Sprintf("Roadmap update for *%s*: *%s* moved from *%s* to *%s*", groupName, escapeText(title), escapeText(humanise(event.PreviousStatus)), escapeText(humanise(event.Status)))
+		}
+
+	default:
+		return nil, ErrUnsupportedEvent
+	}
+
+	if event.Link != "" {
+		details = append(details, fmt.Sprintf("<%s|View>", escapeText(event.Link)))
+	}
+
+	emoji := normaliseEmoji(integration.Emoji)
+	text := headline
+	if emoji != "" {
+		text = emoji + " " + headline
+	}
+
+	message := &Message{
+		Text:      text,
+		IconEmoji: emoji,
+		Blocks: []Block{
+			{Type: blockTypeSection, Text: &TextObject{Type: textTypeMarkdown, Text: text}},
+		},
+	}
+
+	if len(details) > 0 {
+		elements := make([]TextObject, 0, len(details))
+		for _, detail := range details {
+			elements = append(elements, TextObject{Type: textTypeMarkdown, Text: detail})
+		}
+		message.Blocks = append(message.Blocks, Block{Type: blockTypeContext, Elements: elements})
+	}
+
+	return message, nil
+}
+
+// onDutyMention mentions the group's on duty member, falling back to their name
+// when their Slack ID is unknown
+func onDutyMention(integration *group.SlackIntegration) string {
+	if id := strings.TrimSpace(integration.OnDutyDisplayID); id != "" {
+		return "<@" + id + ">"
+	}
+
+	return escapeText(strings.TrimSpace(integration.OnDutyDisplayName))
+}
+
+// groupDisplayName returns the name the group was created with
+func groupDisplayName(targetGroup *group.UniversalGroup) string {
+	if targetGroup.RawName != "" {
+		return targetGroup.RawName
+	}
+
+	return targetGroup.Name
+}
+
+// normaliseEmoji returns the emoji in Slack's :name: form
+func normaliseEmoji(emoji string) string {
+	name := strings.Trim(strings.TrimSpace(emoji), ":")
+	if name == "" {
+		return ""
+	}
+
+	return ":" + name + ":"
+}
+
+// humanise turns a key such as IN_PROGRESS into "In progress"
+func humanise(key string) string {
+	words := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(key), "_", " "))
+	if words == "" {
+		return ""
+	}
+
+	return strings.ToUpper(words[:1]) + words[1:]
+}
+
+// escapeText escapes text so Slack does not read it as markup
+func escapeText(text string) string {
+	return slackTextEscaper.Replace(text)
+}
+
+// valueOrDefault returns the value, or the fallback when it is blank
+func valueOrDefault(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// NewTextMessage creates a plain message with a bold title followed by the body,
+// escaping both
+func NewTextMessage(title, body string) *Message {
+	text := escapeText(strings.TrimSpace(body))
+	if title = strings.TrimSpace(title); title != "" {
+		text = "*" + escapeText(title) + "*\n" + text
+	}
+
+	return &Message{
+		Text: text,
+		Blocks: []Block{
+			{Type: blockTypeSection, Text: &TextObject{Type: textTypeMarkdown, Text: text}},
+		},
+	}
+}
@@ -0,0 +1,115 @@
+package slack
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// DefaultEvents are the events groups are notified about when their Slack
+// integration does not list any
+var DefaultEvents = []string{
+	group.IntegrationEventMemberJoined,
+	group.IntegrationEventInviteAccepted,
+	group.IntegrationEventReminderDue,
+	group.IntegrationEventVisionStatusChanged,
+}
+
+// Config holds the settings of the Slack sender
+type Config struct {
+	// Mode is how messages are delivered, ModeWebhook or ModeBot
+	Mode DeliveryMode
+
+	// WebhookURL is the incoming webhook messages are posted to in webhook mode
+	WebhookURL string
+
+	// BotToken is the bot user OAuth token (xoxb-...) used in bot mode. The bot
+	// needs the chat:write scope, and chat:write.customize for group emojis
+	BotToken string
+
+	// APIBaseURL overrides the Slack Web API base URL, mainly for tests.
+	// Defaults to DefaultAPIBaseURL
+	APIBaseURL string
+
+	// DefaultEvents are the events groups are notified about when their Slack
+	// integration does not list any. Defaults to DefaultEvents
+	DefaultEvents []string
+
+	// MaxRetries is how many times a failed delivery is retried.
+	// Defaults to DefaultMaxRetries, a negative value disables retries
+	MaxRetries int
+
+	// RetryDelay is the delay before the first retry. Defaults to DefaultRetryDelay
+	RetryDelay time.Duration
+
+	// MaxRetryDelay caps the delay between retries. Defaults to DefaultMaxRetryDelay
+	MaxRetryDelay time.Duration
+
+	// HTTPClient is the client used to call Slack. Defaults to a client with
+	// DefaultRequestTimeout
+	HTTPClient *http.Client
+}
+
+// Message is a Slack message, accepted by both incoming webhooks and chat.postMessage
+type Message struct {
+	// Channel is the conversation the message is posted to in bot mode,
+	// webhooks ignore it
+	Channel string `json:"channel,omitempty"`
+
+	// Text is the message's fallback text, used in notifications
+	Text string `json:"text"`
+
+	// IconEmoji overrides the sender's icon
+	IconEmoji string `json:"icon_emoji,omitempty"`
+
+	// Blocks is the message's rich layout
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// Block is a Block Kit layout block
+type Block struct {
+	Type     string       `json:"type"`
+	Text     *TextObject  `json:"text,omitempty"`
+	Elements []TextObject `json:"elements,omitempty"`
+}
+
+// TextObject is a Block Kit text object
+type TextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Event is something that happened in or for a group that can be posted to the
+// group's Slack channels
+type Event struct {
+	// Type is one of the group.IntegrationEvent* keys
+	Type string
+
+	// ActorName is who caused the event
+	ActorName string
+
+	// MemberName is the member the event is about
+	MemberName string
+
+	// Role is the member's role in the group
+	Role string
+
+	// Title is the title of the reminder or roadmap item the event is about
+	Title string
+
+	// Description adds detail to the reminder the event is about
+	Description string
+
+	// Status is the roadmap item's new status
+	Status string
+
+	// PreviousStatus is the roadmap item's status before the change
+	PreviousStatus string
+
+	// DueAt is when the reminder is due
+	DueAt string
+
+	// Link points to where the event can be viewed
+	Link string
+}
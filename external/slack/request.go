@@ -0,0 +1,20 @@
+package slack
+
+// NotifyGroupRequest holds what is needed to post an event to a group's Slack channels
+type NotifyGroupRequest struct {
+	// GroupID is the group the event is posted for
+	GroupID string
+
+	// Event is what happened, see NewReminderDueEvent and NewVisionStatusChangedEvent
+	Event *Event
+}
+
+// PostMessageRequest holds a message to post as is
+type PostMessageRequest struct {
+	// Channel is the conversation the message is posted to in bot mode, a channel
+	// ID or a user ID for a direct message. It is ignored in webhook mode
+	Channel string
+
+	// Message is the message to post
+	Message *Message
+}
@@ -0,0 +1,246 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
+)
+
+// UserService resolves the names of the members and actors mentioned in messages (optional)
+type UserService interface {
+	GetUserByID(ctx context.Context, req *userv2.GetUserByIDRequest) (*userv2.GetUserByIDResponse, error)
+}
+
+// GroupService loads groups notified by ID (optional)
+type GroupService interface {
+	GetGroupByID(ctx context.Context, req *group.GetGroupByIDRequest) (*group.GetGroupByIDResponse, error)
+}
+
+// Sender posts group events to Slack. It implements group.IntegrationNotifier
+// so it can be given to the group service with WithIntegrationNotifier
+type Sender struct {
+	Config *Config
+
+	// UserService names members and actors in messages when set, otherwise
+	// members are named by their invite email where known
+	UserService UserService
+
+	// GroupService enables NotifyGroup when set
+	GroupService GroupService
+
+	client *client
+}
+
+// NewSender creates a Slack sender, validating the delivery mode has the
+// credentials it needs
+func NewSender(config *Config) (*Sender, error) {
+	if config == nil {
+		return nil, ErrInvalidDeliveryMode
+	}
+
+	senderConfig := *config
+	senderConfig.Mode = DeliveryMode(strings.ToUpper(strings.TrimSpace(string(senderConfig.Mode))))
+
+	switch senderConfig.Mode {
+	case ModeWebhook:
+		if strings.TrimSpace(senderConfig.WebhookURL) == "" {
+			return nil, ErrMissingWebhookURL
+		}
+	case ModeBot:
+		if strings.TrimSpace(senderConfig.BotToken) == "" {
+			return nil, ErrMissingBotToken
+		}
+	default:
+		return nil, ErrInvalidDeliveryMode
+	}
+
+	if senderConfig.APIBaseURL == "" {
+		senderConfig.APIBaseURL = DefaultAPIBaseURL
+	}
+	senderConfig.APIBaseURL = strings.TrimSuffix(senderConfig.APIBaseURL, "/")
+
+	if len(senderConfig.DefaultEvents) == 0 {
+		senderConfig.DefaultEvents = DefaultEvents
+	}
+
+	return &Sender{
+		Config: &senderConfig,
+		client: newClient(&senderConfig),
+	}, nil
+}
+
+// WithUserService enables members and actors to be named in messages
+func (s *Sender) WithUserService(userService UserService) *Sender {
+	s.UserService = userService
+	return s
+}
+
+// WithGroupService enables groups to be notified by ID
+func (s *Sender) WithGroupService(groupService GroupService) *Sender {
+	s.GroupService = groupService
+	return s
+}
+
+// Mode returns how the sender delivers messages
+func (s *Sender) Mode() DeliveryMode {
+	return s.Config.Mode
+}
+
+// NotifyGroupEvent posts a membership event raised by the group service to the
+// group's Slack channels
+func (s *Sender) NotifyGroupEvent(ctx context.Context, targetGroup *group.UniversalGroup, event *group.IntegrationEvent) error {
+	if event == nil {
+		return ErrUnsupportedEvent
+	}
+
+	slackEvent := &Event{
+		Type:      event.Type,
+		ActorName: s.userName(ctx, event.ActorID),
+	}
+
+	if event.Member != nil {
+		slackEvent.Role = event.Member.Role
+		if event.Member.Type == group.MemberTypeUser {
+			slackEvent.MemberName = s.userName(ctx, event.Member.ID)
+		}
+	}
+
+	if slackEvent.MemberName == "" {
+		slackEvent.MemberName = event.InviteEmail
+	}
+
+	return s.Notify(ctx, targetGroup, slackEvent)
+}
+
+// NotifyGroup loads a group and posts the event to its Slack channels
+func (s *Sender) NotifyGroup(ctx context.Context, req *NotifyGroupRequest) error {
+	if s.GroupService == nil {
+		return ErrGroupServiceNotSet
+	}
+
+	response, err := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: req.GroupID})
+	if err != nil {
+		return err
+	}
+
+	return s.Notify(ctx, response.Group, req.Event)
+}
+
+// Notify posts an event to a group's Slack channels. Groups without a Slack
+// integration, or that are not subscribed to the event, are skipped
+func (s *Sender) Notify(ctx context.Context, targetGroup *group.UniversalGroup, event *Event) error {
+	logger := logger.AcquireOperationFrom(ctx, "external/slack", "notify")
+
+	if event == nil {
+		return ErrUnsupportedEvent
+	}
+
+	if targetGroup == nil || targetGroup.Integrations == nil || targetGroup.Integrations.Slack == nil {
+		logger.Debug("group-has-no-slack-integration", zap.String("event-type", event.Type))
+		return nil
+	}
+
+	integration := targetGroup.Integrations.Slack
+	if !integration.IsSubscribedTo(event.Type, s.Config.DefaultEvents) {
+		logger.Debug("group-not-subscribed-to-event", zap.String("group-id", targetGroup.ID), zap.String("event-type", event.Type))
+		return nil
+	}
+
+	message, err := formatMessage(targetGroup, integration, event)
+	if err != nil {
+		return err
+	}
+
+	if s.Config.Mode == ModeWebhook {
+		return s.client.postWebhook(ctx, s.Config.WebhookURL, message)
+	}
+
+	channels := integrationChannels(integration)
+	if len(channels) == 0 {
+		logger.Warn("group-slack-integration-has-no-channel", zap.String("group-id", targetGroup.ID))
+		return ErrMissingChannel
+	}
+
+	var sendErrs []error
+	for _, channel := range channels {
+		channelMessage := *message
+		channelMessage.Channel = channel
+
+		if err := s.client.postMessage(ctx, s.Config.APIBaseURL, s.Config.BotToken, &channelMessage); err != nil {
+			logger.Error("failed-to-post-group-event-to-slack", zap.Error(err), zap.String("group-id", targetGroup.ID), zap.String("channel", channel), zap.String("event-type", event.Type))
+			sendErrs = append(sendErrs, err)
+		}
+	}
+
+	return errors.Join(sendErrs...)
+}
+
+// PostMessage posts a message as is. In bot mode the channel can be a channel ID
+// or a user ID for a direct message
+func (s *Sender) PostMessage(ctx context.Context, req *PostMessageRequest) error {
+	if req.Message == nil {
+		return ErrUnsupportedEvent
+	}
+
+	if s.Config.Mode == ModeWebhook {
+		return s.client.postWebhook(ctx, s.Config.WebhookURL, req.Message)
+	}
+
+	channel := strings.TrimSpace(req.Channel)
+	if channel == "" {
+		return ErrMissingChannel
+	}
+
+	message := *req.Message
+	message.Channel = channel
+
+	return s.client.postMessage(ctx, s.Config.APIBaseURL, s.Config.BotToken, &message)
+}
+
+// userName returns how a user is named in messages, empty when the user cannot
+// be resolved
+func (s *Sender) userName(ctx context.Context, userID string) string {
+	if s.UserService == nil || strings.TrimSpace(userID) == "" {
+		return ""
+	}
+
+	response, err := s.UserService.GetUserByID(ctx, &userv2.GetUserByIDRequest{ID: userID})
+	if err != nil || response == nil || response.User == nil {
+		return ""
+	}
+
+	user := response.User
+	if user.PersonalInfo != nil {
+		if fullName := strings.TrimSpace(user.PersonalInfo.FullName); fullName != "" {
+			return fullName
+		}
+		if name := strings.TrimSpace(user.PersonalInfo.FirstName + " " + user.PersonalInfo.LastName); name != "" {
+			return name
+		}
+	}
+
+	return user.Email
+}
+
+// integrationChannels returns the group's channel followed by its additional
+// recipients, without blanks or duplicates
+func integrationChannels(integration *group.SlackIntegration) []string {
+	channels := []string{}
+	seen := map[string]bool{}
+
+	for _, channel := range append([]string{integration.DisplayID}, integration.AdditionalRecipients...) {
+		channel = strings.TrimSpace(channel)
+		if channel == "" || seen[channel] {
+			continue
+		}
+		seen[channel] = true
+		channels = append(channels, channel)
+	}
+
+	return channels
+}
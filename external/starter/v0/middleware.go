@@ -5,6 +5,7 @@ import (
 	"time"
 
 	accessmiddleware "github.com/ooaklee/ghatd/external/accessmanager/middleware"
+	"github.com/ooaklee/ghatd/external/tenancy"
 	"github.com/ooaklee/reply/v2"
 )
 
@@ -20,6 +21,10 @@ type HardenedRateLimitStore interface {
 // functions remain exposed by their owning suite.
 type Middleware struct {
 	AccessManager *accessmiddleware.Suite
+
+	// Tenancy resolves the group reminder and streak requests act for. It is
+	// nil when the group service is not enabled.
+	Tenancy *tenancy.Middleware
 }
 
 // NewMiddlewareRequest holds dependencies for middleware construction.
@@ -71,8 +76,14 @@ func NewMiddleware(r *NewMiddlewareRequest) (*Middleware, error) {
 		return nil, err
 	}
 
+	var tenancyMiddleware *tenancy.Middleware
+	if r.Services.Group != nil {
+		tenancyMiddleware = tenancy.NewMiddleware(r.Services.Group, nil, r.ErrorMaps...)
+	}
+
 	return &Middleware{
 		AccessManager: suite,
+		Tenancy:       tenancyMiddleware,
 	}, nil
 }
 
@@ -5,7 +5,10 @@ import (
 	"github.com/ooaklee/ghatd/external/audit"
 	"github.com/ooaklee/ghatd/external/billing"
 	"github.com/ooaklee/ghatd/external/contacter"
+	"github.com/ooaklee/ghatd/external/entitlement"
 	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/metering"
 	"github.com/ooaklee/ghatd/external/notifier"
 	"github.com/ooaklee/ghatd/external/post"
 	"github.com/ooaklee/ghatd/external/pricer"
@@ -18,38 +21,44 @@ import (
 
 // Repositories groups the standard GHATD Mongo-backed repositories.
 type Repositories struct {
-	Core      *repository.MongoDbRepository
-	APIToken  *apitoken.Repository
-	Audit     *audit.Repository
-	Billing   *billing.Repository
-	Contacter *contacter.Repository
-	Group     *group.Repository
-	Notifier  *notifier.Repository
-	Post      *post.Repository
-	Pricer    *pricer.Repository
-	Reminder  *reminder.Repository
-	Streaker  *streaker.Repository
-	User      *userv2.Repository
-	Vision    *vision.Repository
+	Core        *repository.MongoDbRepository
+	APIToken    *apitoken.Repository
+	Audit       *audit.Repository
+	Billing     *billing.Repository
+	Contacter   *contacter.Repository
+	Entitlement *entitlement.Repository
+	Group       *group.Repository
+	Invoice     *invoice.Repository
+	Metering    *metering.Repository
+	Notifier    *notifier.Repository
+	Post        *post.Repository
+	Pricer      *pricer.Repository
+	Reminder    *reminder.Repository
+	Streaker    *streaker.Repository
+	User        *userv2.Repository
+	Vision      *vision.Repository
 }
 
 // NewRepositoriesRequest holds the dependencies and optional overrides for
 // repository construction. When a package repository is nil, starter/v0 builds
 // it from Core.
 type NewRepositoriesRequest struct {
-	Core      *repository.MongoDbRepository
-	APIToken  *apitoken.Repository
-	Audit     *audit.Repository
-	Billing   *billing.Repository
-	Contacter *contacter.Repository
-	Group     *group.Repository
-	Notifier  *notifier.Repository
-	Post      *post.Repository
-	Pricer    *pricer.Repository
-	Reminder  *reminder.Repository
-	Streaker  *streaker.Repository
-	User      *userv2.Repository
-	Vision    *vision.Repository
+	Core        *repository.MongoDbRepository
+	APIToken    *apitoken.Repository
+	Audit       *audit.Repository
+	Billing     *billing.Repository
+	Contacter   *contacter.Repository
+	Entitlement *entitlement.Repository
+	Group       *group.Repository
+	Invoice     *invoice.Repository
+	Metering    *metering.Repository
+	Notifier    *notifier.Repository
+	Post        *post.Repository
+	Pricer      *pricer.Repository
+	Reminder    *reminder.Repository
+	Streaker    *streaker.Repository
+	User        *userv2.Repository
+	Vision      *vision.Repository
 }
 
 // NewRepositories creates the standard repository container. Core is required;
@@ -63,19 +72,22 @@ func NewRepositories(r *NewRepositoriesRequest) (*Repositories, error) {
 	}
 
 	repos := &Repositories{
-		Core:      r.Core,
-		APIToken:  r.APIToken,
-		Audit:     r.Audit,
-		Billing:   r.Billing,
-		Contacter: r.Contacter,
-		Group:     r.Group,
-		Notifier:  r.Notifier,
-		Post:      r.Post,
-		Pricer:    r.Pricer,
-		Reminder:  r.Reminder,
-		Streaker:  r.Streaker,
-		User:      r.User,
-		Vision:    r.Vision,
+		Core:        r.Core,
+		APIToken:    r.APIToken,
+		Audit:       r.Audit,
+		Billing:     r.Billing,
+		Contacter:   r.Contacter,
+		Entitlement: r.Entitlement,
+		Group:       r.Group,
+		Invoice:     r.Invoice,
+		Metering:    r.Metering,
+		Notifier:    r.Notifier,
+		Post:        r.Post,
+		Pricer:      r.Pricer,
+		Reminder:    r.Reminder,
+		Streaker:    r.Streaker,
+		User:        r.User,
+		Vision:      r.Vision,
 	}
 
 	if repos.APIToken == nil {
@@ -90,9 +102,18 @@ func NewRepositories(r *NewRepositoriesRequest) (*Repositories, error) {
 	if repos.Contacter == nil {
 		repos.Contacter = contacter.NewRepository(r.Core)
 	}
+	if repos.Entitlement == nil {
+		repos.Entitlement = entitlement.NewRepository(r.Core)
+	}
 	if repos.Group == nil {
 		repos.Group = group.NewRepository(r.Core)
 	}
+	if repos.Invoice == nil {
+		repos.Invoice = invoice.NewRepository(r.Core)
+	}
+	if repos.Metering == nil {
+		repos.Metering = metering.NewRepository(r.Core)
+	}
 	if repos.Notifier == nil {
 		repos.Notifier = notifier.NewRepository(r.Core)
 	}
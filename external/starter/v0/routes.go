@@ -138,6 +138,11 @@ func AttachDefaultRoutes(r *AttachDefaultRoutesRequest) error {
 	}
 
 	if !skip[RouteGroupUserManager] {
+		var tenantMiddleware mux.MiddlewareFunc
+		if r.Stack.Middleware != nil && r.Stack.Middleware.Tenancy != nil {
+			tenantMiddleware = r.Stack.Middleware.Tenancy.ResolveTenant()
+		}
+
 		usermanager.AttachRoutes(&usermanager.AttachRoutesRequest{
 			Router:                                       r.Router,
 			Handler:                                      r.Stack.Handlers.UserManager,
@@ -149,6 +154,7 @@ func AttachDefaultRoutes(r *AttachDefaultRoutesRequest) error {
 			ValidApiTokenOrJWTMiddleware:                 mw.ActiveValidApiTokenOrAuthenticated,
 			RateLimitOrActiveMiddleware:                  mw.RateLimitOrActive,
 			CustomMeEndpointValidApiTokenOrJWTMiddleware: mw.CustomMeEndpointValidApiTokenOrJWT,
+			TenantMiddleware:                             tenantMiddleware,
 		})
 	}
 
@@ -268,7 +274,7 @@ func newRouteMiddlewareRequirements(skip map[RouteGroup]bool) routeMiddlewareReq
 		activeOnly:                         !skip[RouteGroupAccessManager] || !skip[RouteGroupUserManager],
 		activeValidApiTokenOrJWT:           !skip[RouteGroupAccessManager] || !skip[RouteGroupUserManager] || !skip[RouteGroupContentManager] || !skip[RouteGroupBillingManager],
 		hardenedRateLimit:                  !skip[RouteGroupAccessManager],
-		adminApiTokenOrJWT:                 !skip[RouteGroupUserManager] || !skip[RouteGroupContentManager],
+		adminApiTokenOrJWT:                 !skip[RouteGroupUserManager] || !skip[RouteGroupContentManager] || !skip[RouteGroupBillingManager],
 		activeValidApiTokenOrAuthenticated: !skip[RouteGroupUserManager],
 		rateLimitOrActive:                  !skip[RouteGroupUserManager] || !skip[RouteGroupContentManager],
 		customMeEndpointValidApiTokenOrJWT: !skip[RouteGroupUserManager],
@@ -12,7 +12,10 @@ import (
 	"github.com/ooaklee/ghatd/external/billingmanager"
 	"github.com/ooaklee/ghatd/external/contacter"
 	"github.com/ooaklee/ghatd/external/contentmanager"
+	"github.com/ooaklee/ghatd/external/entitlement"
 	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/invoice"
+	"github.com/ooaklee/ghatd/external/metering"
 	"github.com/ooaklee/ghatd/external/notifier"
 	"github.com/ooaklee/ghatd/external/paymentprovider"
 	"github.com/ooaklee/ghatd/external/policy"
@@ -35,7 +38,10 @@ type Services struct {
 	BillingManager          *billingmanager.Service
 	Contacter               *contacter.Service
 	ContentManager          *contentmanager.Service
+	Entitlement             *entitlement.Service
 	Group                   *group.Service
+	Invoice                 *invoice.Service
+	Metering                *metering.Service
 	Notifier                *notifier.Service
 	Policy                  *policy.Service
 	Post                    *post.Service
@@ -78,7 +84,10 @@ type NewServicesRequest struct {
 	UserConfig                 *userv2.UserConfig
 	UserConfigs                []*userv2.UserConfig
 	GroupConfig                *group.GroupConfig
-	VisionConfig               *vision.VisionConfig
+	// GroupInvitationConfig enables signed, expiring group invitation links
+	// emailed through EmailManager. Invitations are not emailed when nil.
+	GroupInvitationConfig *group.InvitationConfig
+	VisionConfig          *vision.VisionConfig
 	// CommsTypes uses contacter.DefaultCommsTypeMap when nil. Supply a map to
 	// select or extend the communication types accepted by this application.
 	CommsTypes contacter.CommsTypeMap
@@ -97,6 +106,12 @@ type NewServicesRequest struct {
 	PolicyStore  policy.PolicyStore
 	PolicyConfig *PolicyConfig
 
+	// InvoiceBusinessEntity is the seller printed on invoices. When set, every
+	// successful payment is invoiced and users can download their invoices.
+	InvoiceBusinessEntity *invoice.BusinessEntity
+	// EmailInvoices emails invoices to customers as soon as they are issued.
+	EmailInvoices bool
+
 	// PaymentProviderRegistry and PaymentProviders are mutually exclusive. When
 	// no registry is supplied, starter creates one and registers PaymentProviders.
 	PaymentProviderRegistry billingmanager.ProviderRegistry
@@ -155,8 +170,20 @@ func NewServices(r *NewServicesRequest) (*Services, error) {
 	apiTokenService := apitoken.NewService(r.Repositories.APIToken)
 	contacterService := contacter.NewService(r.Repositories.Contacter, r.CommsTypes)
 	postService := post.NewService(r.Repositories.Post, resolvePostTags(r.ValidPostTags))
-	billingService := billing.NewService(r.Repositories.Billing, r.Repositories.Billing)
-	pricerService := pricer.NewService(r.Repositories.Pricer)
+	billingService := billing.NewService(r.Repositories.Billing, r.Repositories.Billing).WithWebhookDeliveryRepository(r.Repositories.Billing)
+	pricerService := pricer.NewService(r.Repositories.Pricer).WithCustomerHistory(billingService)
+	var entitlementService *entitlement.Service
+	if r.Repositories.Entitlement != nil {
+		entitlementService = entitlement.NewService(r.Repositories.Entitlement, billingService, pricerService)
+	}
+	var meteringService *metering.Service
+	if r.Repositories.Metering != nil && entitlementService != nil {
+		meteringService = metering.NewService(r.Repositories.Metering, entitlementService, billingService)
+	}
+	var invoiceService *invoice.Service
+	if r.Repositories.Invoice != nil && r.InvoiceBusinessEntity != nil {
+		invoiceService = invoice.NewService(r.Repositories.Invoice, *r.InvoiceBusinessEntity)
+	}
 	var reminderService *reminder.Service
 	if r.Repositories.Reminder != nil {
 		reminderService = reminder.NewService(r.Repositories.Reminder)
@@ -186,6 +213,9 @@ func NewServices(r *NewServicesRequest) (*Services, error) {
 	if err != nil {
 		return nil, fmt.Errorf("starter/group-service: %w", err)
 	}
+	if r.GroupInvitationConfig != nil {
+		groupService.WithInvitations(r.GroupInvitationConfig).WithEmailManager(r.EmailManager)
+	}
 	visionService, err := vision.NewService(r.Repositories.Vision, r.VisionConfig)
 	if err != nil {
 		return nil, fmt.Errorf("starter/vision-service: %w", err)
@@ -198,8 +228,11 @@ func NewServices(r *NewServicesRequest) (*Services, error) {
 		ContacterService: contacterService,
 	}).
 		WithGroupService(groupService).
+		WithBillingService(billingService).
 		WithNotifierService(notifierService).
 		WithVisionService(visionService)
+	notifierService.WithSegmentResolver(userManagerService.NotificationSegmentResolver())
+	groupService.WithMemberImports(r.Repositories.Group, userManagerService.MemberImportUserFinder())
 	if reminderService != nil {
 		userManagerService.WithReminderService(reminderService)
 	}
@@ -226,7 +259,20 @@ func NewServices(r *NewServicesRequest) (*Services, error) {
 
 	contentManagerService := contentmanager.NewService(postService, userService)
 	billingManagerService := billingmanager.NewService(paymentProviderRegistry, billingService)
-	billingManagerService.WithAuditService(auditService).WithUserService(userService).WithPricerService(pricerService)
+	billingManagerService.WithAuditService(auditService).WithUserService(userService).WithPricerService(pricerService).WithWebhookInboxService(billingService).WithGroupService(groupService)
+	billingManagerService.WithEmailManager(r.EmailManager).WithNotifierService(notifierService).WithDunning(billingmanager.DefaultDunningConfig())
+	if entitlementService != nil {
+		billingManagerService.WithEntitlementService(entitlementService)
+	}
+	if meteringService != nil {
+		billingManagerService.WithMeteringService(meteringService)
+	}
+	if invoiceService != nil {
+		billingManagerService.WithInvoiceService(invoiceService)
+		if r.EmailInvoices {
+			billingManagerService.WithInvoiceEmails()
+		}
+	}
 
 	return &Services{
 		AccessManager:           accessManagerService,
@@ -237,7 +283,10 @@ func NewServices(r *NewServicesRequest) (*Services, error) {
 		BillingManager:          billingManagerService,
 		Contacter:               contacterService,
 		ContentManager:          contentManagerService,
+		Entitlement:             entitlementService,
 		Group:                   groupService,
+		Invoice:                 invoiceService,
+		Metering:                meteringService,
 		Notifier:                notifierService,
 		Policy:                  policyService,
 		Post:                    postService,
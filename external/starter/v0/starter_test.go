@@ -13,6 +13,7 @@ import (
 	"github.com/ooaklee/ghatd/external/contacter"
 	"github.com/ooaklee/ghatd/external/emailmanager"
 	"github.com/ooaklee/ghatd/external/ephemeral"
+	"github.com/ooaklee/ghatd/external/invoice"
 	"github.com/ooaklee/ghatd/external/paymentprovider"
 	"github.com/ooaklee/ghatd/external/policy"
 	"github.com/ooaklee/ghatd/external/post"
@@ -229,9 +230,9 @@ func TestNewRepositories(t *testing.T) {
 					t.Fatalf("expected core repository to be preserved")
 				}
 				if got.APIToken == nil || got.Audit == nil || got.Billing == nil ||
-					got.Contacter == nil || got.Group == nil || got.Notifier == nil ||
-					got.Post == nil || got.Pricer == nil || got.Reminder == nil ||
-					got.Streaker == nil || got.User == nil {
+					got.Contacter == nil || got.Entitlement == nil || got.Group == nil || got.Invoice == nil ||
+					got.Metering == nil || got.Notifier == nil || got.Post == nil || got.Pricer == nil ||
+					got.Reminder == nil || got.Streaker == nil || got.User == nil {
 					t.Fatalf("expected all repositories to be populated: %#v", got)
 				}
 			},
@@ -418,6 +419,33 @@ func TestNewServices(t *testing.T) {
 				if got.UserManager.StreakService != got.Streaker {
 					t.Fatalf("expected user manager to receive starter streak service")
 				}
+				if got.Entitlement == nil || got.BillingManager.EntitlementService != got.Entitlement {
+					t.Fatalf("expected billing manager to receive entitlement service")
+				}
+				if got.Metering == nil || got.BillingManager.MeteringService != got.Metering {
+					t.Fatalf("expected billing manager to receive metering service")
+				}
+				if got.Invoice != nil || got.BillingManager.InvoiceService != nil {
+					t.Fatalf("expected invoices to stay disabled without a business entity")
+				}
+			},
+		},
+		{
+			name: "Success - invoice business entity enables invoices",
+			request: func(t *testing.T) *NewServicesRequest {
+				req := validServicesRequest(t)
+				req.InvoiceBusinessEntity = &invoice.BusinessEntity{Name: "Acme Ltd"}
+				req.EmailInvoices = true
+				return req
+			},
+			assert: func(t *testing.T, got *Services) {
+				t.Helper()
+				if got.Invoice == nil || got.BillingManager.InvoiceService != got.Invoice {
+					t.Fatalf("expected billing manager to receive invoice service")
+				}
+				if !got.BillingManager.EmailInvoices {
+					t.Fatalf("expected billing manager to email invoices")
+				}
 			},
 		},
 		{
@@ -1316,6 +1344,12 @@ func (fakePaymentProvider) ParsePayload(ctx context.Context, req *http.Request)
 func (fakePaymentProvider) GetSubscriptionInfo(ctx context.Context, subscriptionID string) (*paymentprovider.SubscriptionInfo, error) {
 	return nil, nil
 }
+func (fakePaymentProvider) CreateCheckoutSession(ctx context.Context, req *paymentprovider.CheckoutSessionRequest) (*paymentprovider.CheckoutSession, error) {
+	return nil, nil
+}
+func (fakePaymentProvider) CreateCustomerPortalSession(ctx context.Context, req *paymentprovider.CustomerPortalSessionRequest) (*paymentprovider.CustomerPortalSession, error) {
+	return nil, nil
+}
 
 type fakePolicyStore struct {
 	policies []policy.WebAppPolicy
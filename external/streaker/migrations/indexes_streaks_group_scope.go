@@ -0,0 +1,90 @@
+package migrations
+
+import (
+	"context"
+	"log"
+
+	"github.com/ooaklee/ghatd/external/streaker"
+	"github.com/ooaklee/ghatd/external/toolbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// InitStreaksGroupScopeIndexesUp replaces the unique scope period index with one that
+// includes the owning group, so personal and group-owned streaks of the same scope can
+// record the same period.
+func InitStreaksGroupScopeIndexesUp(db *mongo.Database) error { //Up
+	log.SetFlags(0)
+	const mongoCollectionName = streaker.StreakCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "starting-task-to-add-streaks-group-scope-indexes"))
+
+	groupScopePeriodIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "group_id", Value: 1},
+			{Key: "streak_type", Value: 1},
+			{Key: "owner_id", Value: 1},
+			{Key: "target_type", Value: 1},
+			{Key: "target_id", Value: 1},
+			{Key: "period_type", Value: 1},
+			{Key: "period_key", Value: 1},
+		},
+		Options: options.Index().
+			SetName("idx_streaks_group_scope_period").
+			SetUnique(true),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateOne(context.Background(), groupScopePeriodIndexModel)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-add-streaks-group-scope-indexes"))
+		return err
+	}
+
+	err = db.Collection(mongoCollectionName).Indexes().DropOne(context.Background(), "idx_streaks_scope_period")
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-task-to-drop-streaks-scope-period-index"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-task-to-add-streaks-group-scope-indexes"))
+	return nil
+}
+
+// InitStreaksGroupScopeIndexesDown restores the unique scope period index. It fails
+// while personal and group-owned streaks share a scope and period.
+func InitStreaksGroupScopeIndexesDown(db *mongo.Database) error { //Down
+	log.SetFlags(0)
+	const mongoCollectionName = streaker.StreakCollection
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "rolling-back-task-to-add-streaks-group-scope-indexes"))
+
+	scopePeriodIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "streak_type", Value: 1},
+			{Key: "owner_id", Value: 1},
+			{Key: "target_type", Value: 1},
+			{Key: "target_id", Value: 1},
+			{Key: "period_type", Value: 1},
+			{Key: "period_key", Value: 1},
+		},
+		Options: options.Index().
+			SetName("idx_streaks_scope_period").
+			SetUnique(true),
+	}
+
+	_, err := db.Collection(mongoCollectionName).Indexes().CreateOne(context.TODO(), scopePeriodIndexModel)
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-streaks-group-scope-indexes"))
+		return err
+	}
+
+	err = db.Collection(mongoCollectionName).Indexes().DropOne(context.TODO(), "idx_streaks_group_scope_period")
+	if err != nil {
+		log.Default().Println(toolbox.OutputBasicLogString("error", "failed-rolling-back-index: idx_streaks_group_scope_period"))
+		return err
+	}
+
+	log.Default().Println(toolbox.OutputBasicLogString("info", "completed-rolling-back-task-to-add-streaks-group-scope-indexes"))
+	return nil
+}
@@ -41,6 +41,7 @@ type Streak struct {
 
 	StreakType string `json:"streak_type" bson:"streak_type"`
 	OwnerId    string `json:"owner_id" bson:"owner_id"`
+	GroupID    string `json:"group_id,omitempty" bson:"group_id,omitempty"`
 	TargetType string `json:"target_type" bson:"target_type"`
 	TargetId   string `json:"target_id" bson:"target_id"`
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/ooaklee/ghatd/external/tenancy"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -129,7 +130,7 @@ func (r *Repository) GetStreakByScopeAndPeriod(ctx context.Context, req *GetLate
 		return nil, err
 	}
 
-	queryFilter := buildStreakQueryFilter(&req.StreakStatsRequest)
+	queryFilter := scopeStreakFilter(ctx, buildStreakQueryFilter(&req.StreakStatsRequest), &req.StreakStatsRequest)
 	queryFilter["period_key"] = req.PeriodKey
 
 	var result Streak
@@ -148,7 +149,7 @@ func (r *Repository) GetLatestStreak(ctx context.Context, req *GetLatestStreakRe
 		return nil, err
 	}
 
-	queryFilter := buildStreakQueryFilter(&req.StreakStatsRequest)
+	queryFilter := scopeStreakFilter(ctx, buildStreakQueryFilter(&req.StreakStatsRequest), &req.StreakStatsRequest)
 	findOptions := options.Find().
 		SetSort(bson.D{
 			{Key: "occurred_at", Value: -1},
@@ -179,7 +180,7 @@ func (r *Repository) GetLongestStreak(ctx context.Context, req *GetLongestStreak
 		return nil, err
 	}
 
-	queryFilter := buildStreakQueryFilter(&req.StreakStatsRequest)
+	queryFilter := scopeStreakFilter(ctx, buildStreakQueryFilter(&req.StreakStatsRequest), &req.StreakStatsRequest)
 	findOptions := options.Find().
 		SetSort(bson.D{
 			{Key: "current_count", Value: -1},
@@ -211,7 +212,7 @@ func (r *Repository) GetTotalStreaks(ctx context.Context, req *GetNumberOfStreak
 		return 0, err
 	}
 
-	queryFilter := buildStreakQueryFilter(&req.StreakStatsRequest)
+	queryFilter := scopeStreakFilter(ctx, buildStreakQueryFilter(&req.StreakStatsRequest), &req.StreakStatsRequest)
 	return r.Store.ExecuteCountDocuments(ctx, collection, queryFilter)
 }
 
@@ -222,7 +223,7 @@ func (r *Repository) ListStreaks(ctx context.Context, req *ListStreaksRequest) (
 		return nil, err
 	}
 
-	queryFilter := buildStreakQueryFilter(&req.StreakStatsRequest)
+	queryFilter := scopeStreakFilter(ctx, buildStreakQueryFilter(&req.StreakStatsRequest), &req.StreakStatsRequest)
 	addStreakListFilter(queryFilter, req)
 
 	sortDirection := -1
@@ -297,6 +298,17 @@ func buildStreakQueryFilter(req *StreakStatsRequest) bson.M {
 	return queryFilter
 }
 
+// scopeStreakFilter scopes a streak query to the tenant on the context when it is made
+// for an owner or from inside a tenant, so personal and group-owned streaks count
+// separately. Unscoped admin listings keep seeing every streak.
+func scopeStreakFilter(ctx context.Context, queryFilter bson.M, req *StreakStatsRequest) bson.M {
+	if (req == nil || req.OwnerId == "") && tenancy.GroupIDFrom(ctx) == "" {
+		return queryFilter
+	}
+
+	return tenancy.ScopeFilter(ctx, queryFilter)
+}
+
 func addStreakListFilter(queryFilter bson.M, req *ListStreaksRequest) {
 	if req == nil {
 		return
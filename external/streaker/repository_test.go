@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ooaklee/ghatd/external/tenancy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -196,7 +197,31 @@ func TestRepository_ListStreaksBuildsHistoryFilter(t *testing.T) {
 	assert.Equal(t, "saved-words", capturedFilter["target_id"])
 	assert.Equal(t, StreakPeriodTypeDaily, capturedFilter["period_type"])
 	assert.Equal(t, bson.M{"$gte": "2026-05-20", "$lte": "2026-05-22"}, capturedFilter["period_key"])
+	assert.Equal(t, bson.M{"$exists": false}, capturedFilter["group_id"])
 	require.NotNil(t, capturedOptions)
 	assert.Equal(t, int64(25), *capturedOptions.Limit)
 	assert.Equal(t, int64(25), *capturedOptions.Skip)
 }
+
+func TestScopeStreakFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unscoped admin listing", func(t *testing.T) {
+		req := &StreakStatsRequest{StreakType: "app"}
+		f := scopeStreakFilter(context.Background(), buildStreakQueryFilter(req), req)
+		assert.NotContains(t, f, "group_id")
+	})
+
+	t.Run("owner lookup excludes group-owned streaks", func(t *testing.T) {
+		req := &StreakStatsRequest{OwnerId: "user-1"}
+		f := scopeStreakFilter(context.Background(), buildStreakQueryFilter(req), req)
+		assert.Equal(t, bson.M{"$exists": false}, f["group_id"])
+	})
+
+	t.Run("tenant lookup is limited to the group", func(t *testing.T) {
+		ctx := tenancy.TransitWith(context.Background(), &tenancy.Tenant{GroupID: "group-1"})
+		req := &StreakStatsRequest{OwnerId: "user-1"}
+		f := scopeStreakFilter(ctx, buildStreakQueryFilter(req), req)
+		assert.Equal(t, "group-1", f["group_id"])
+	})
+}
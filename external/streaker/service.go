@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/tenancy"
 	"github.com/ooaklee/ghatd/external/toolbox"
 	"go.uber.org/zap"
 )
@@ -185,6 +186,7 @@ func (s *Service) createResolvedStreak(ctx context.Context, req *RecordStreakReq
 		StreakName:      strings.TrimSpace(req.StreakName),
 		StreakType:      resolvedReq.scope.StreakType,
 		OwnerId:         resolvedReq.scope.OwnerId,
+		GroupID:         tenancy.GroupIDFrom(ctx),
 		TargetType:      resolvedReq.scope.TargetType,
 		TargetId:        resolvedReq.scope.TargetId,
 		PeriodType:      resolvedReq.periodType,
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ooaklee/ghatd/external/streaker"
+	"github.com/ooaklee/ghatd/external/tenancy"
 )
 
 type mockStreakRepository struct {
@@ -105,6 +106,33 @@ func TestService_RecordStreakCreatesFirstEntry(t *testing.T) {
 	assert.Nil(t, created.Previous)
 }
 
+func TestService_RecordStreakInsideTenantIsGroupOwned(t *testing.T) {
+	t.Parallel()
+
+	var created *streaker.Streak
+	repo := &mockStreakRepository{
+		createStreakFunc: func(ctx context.Context, st *streaker.Streak) (*streaker.Streak, error) {
+			created = st
+			return st, nil
+		},
+	}
+	svc := streaker.NewService(repo)
+
+	ctx := tenancy.TransitWith(context.Background(), &tenancy.Tenant{GroupID: "group-1"})
+	_, err := svc.RecordStreak(ctx, &streaker.RecordStreakRequest{
+		StreakType:      "team-check-in",
+		OwnerId:         "user-1",
+		TargetType:      "App",
+		TargetId:        "platform",
+		OccurredAt:      "2026-05-08T09:00:00",
+		CreatedByUserId: "user-1",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, "group-1", created.GroupID)
+}
+
 func TestService_RecordStreakUsesPeriodTimezone(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,30 @@
+// Package tenancy lets host packages own records on behalf of a group. It resolves the
+// group a request is acting for, verifies the caller belongs to it, carries it on the
+// request context and scopes MongoDB queries to it.
+package tenancy
+
+const (
+	// DefaultTenantHeader is the request header the tenant group ID is read from by default.
+	DefaultTenantHeader = "X-Tenant-ID"
+
+	// DefaultTenantPathVariable is the route variable the tenant group ID is read from by default.
+	DefaultTenantPathVariable = "tenantID"
+
+	// TenantField is the document field holding the ID of the group that owns a record.
+	TenantField = "group_id"
+)
+
+const (
+	// ErrKeyTenantIsRequired is returned when a route needs a tenant but none was given.
+	ErrKeyTenantIsRequired = "TenancyTenantIsRequired"
+
+	// ErrKeyTenantAccessDenied is returned when the caller is not a member of the tenant
+	// or lacks a permission the route requires.
+	ErrKeyTenantAccessDenied = "TenancyTenantAccessDenied"
+
+	// ErrKeyTenantAccessUnavailable is returned when the caller's group access could not be resolved.
+	ErrKeyTenantAccessUnavailable = "TenancyTenantAccessUnavailable"
+
+	// ErrKeyUnableToIdentifyUser is returned when no signed-in user is on the request context.
+	ErrKeyUnableToIdentifyUser = "TenancyUnableToIdentifyUser"
+)
@@ -0,0 +1,45 @@
+package tenancy
+
+import (
+	"context"
+	"strings"
+)
+
+// contextKey defines a distinct type used as keys when storing values in a
+// request context.
+type contextKey string
+
+// TenantKey stores the verified tenant in the request context.
+const TenantKey contextKey = "ContextTenant"
+
+// TransitWith returns a new context derived from ctx that carries the verified tenant.
+func TransitWith(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, TenantKey, tenant)
+}
+
+// AcquireFrom returns the verified tenant on ctx, or nil when the request is not
+// acting for a group.
+func AcquireFrom(ctx context.Context) *Tenant {
+	tenant, ok := ctx.Value(TenantKey).(*Tenant)
+	if ok && tenant != nil {
+		return tenant
+	}
+	return nil
+}
+
+// GroupIDFrom returns the ID of the tenant group on ctx, or an empty string when
+// the request is not acting for a group. New records stamped with it are owned
+// by the tenant.
+func GroupIDFrom(ctx context.Context) string {
+	if tenant := AcquireFrom(ctx); tenant != nil {
+		return tenant.GroupID
+	}
+	return ""
+}
+
+// MatchesScope returns true when a record owned by groupID can be reached from
+// ctx: group-owned records only from their own tenant, and personal records,
+// with no group ID, only outside of a tenant.
+func MatchesScope(ctx context.Context, groupID string) bool {
+	return strings.TrimSpace(groupID) == GroupIDFrom(ctx)
+}
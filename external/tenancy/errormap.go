@@ -0,0 +1,35 @@
+package tenancy
+
+import (
+	"net/http"
+
+	"github.com/ooaklee/reply/v2"
+)
+
+// TenancyErrorMap maps tenancy sentinel errors to API response metadata.
+var TenancyErrorMap reply.ErrorManifest = reply.ErrorManifest{
+	ErrTenantIsRequired: {
+		Title:      "Missing Tenant",
+		StatusCode: http.StatusBadRequest,
+		Code:       "TEN00-001",
+		Detail:     "Please provide the group this request is for",
+	},
+	ErrUnableToIdentifyUser: {
+		Title:      "Unauthorized",
+		StatusCode: http.StatusUnauthorized,
+		Code:       "TEN00-002",
+		Detail:     "Unable to identify the signed-in user",
+	},
+	ErrTenantAccessDenied: {
+		Title:      "Forbidden",
+		StatusCode: http.StatusForbidden,
+		Code:       "TEN00-003",
+		Detail:     "You do not have access to this group",
+	},
+	ErrTenantAccessUnavailable: {
+		Title:      "Internal Error",
+		StatusCode: http.StatusInternalServerError,
+		Code:       "TEN00-004",
+		Detail:     "Unable to check your access to this group. Please try again",
+	},
+}
@@ -0,0 +1,18 @@
+package tenancy
+
+import "errors"
+
+// Sentinel errors for the tenancy package.
+//
+// Callers can use errors.Is() against them, and TenancyErrorMap turns them
+// into consistent HTTP responses at the middleware layer.
+var (
+	// ErrTenantAccessDenied means the caller is not a member of the tenant or lacks a required permission.
+	ErrTenantAccessDenied = errors.New(ErrKeyTenantAccessDenied)
+	// ErrTenantAccessUnavailable means the caller's group access could not be resolved.
+	ErrTenantAccessUnavailable = errors.New(ErrKeyTenantAccessUnavailable)
+	// ErrTenantIsRequired means a route that must act for a tenant was called without one.
+	ErrTenantIsRequired = errors.New(ErrKeyTenantIsRequired)
+	// ErrUnableToIdentifyUser means no signed-in user was found on the request context.
+	ErrUnableToIdentifyUser = errors.New(ErrKeyUnableToIdentifyUser)
+)
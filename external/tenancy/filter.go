@@ -0,0 +1,28 @@
+package tenancy
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ScopeFilter scopes a MongoDB query filter to the tenant on ctx. Inside a tenant
+// only records owned by the tenant group match. Outside of a tenant only personal
+// records, without a group ID, match, so group-owned records never leak into
+// personal views.
+//
+// Apply it to queries made for a caller. Scheduler and admin queries that must see
+// every record should be left unscoped.
+func ScopeFilter(ctx context.Context, filter bson.M) bson.M {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	if groupID := GroupIDFrom(ctx); groupID != "" {
+		filter[TenantField] = groupID
+	} else {
+		filter[TenantField] = bson.M{"$exists": false}
+	}
+
+	return filter
+}
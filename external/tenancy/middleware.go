@@ -0,0 +1,124 @@
+package tenancy
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/errormanifest"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/reply/v2"
+	"go.uber.org/zap"
+)
+
+// groupAccessMapper defines the group access lookup used to verify tenant membership
+type groupAccessMapper interface {
+	GetUserGroupAccessMap(ctx context.Context, userID string) (map[string]group.UserGroupAccessSummary, error)
+}
+
+// Middleware resolves the tenant a request is acting for and verifies the signed-in
+// user's membership of it
+type Middleware struct {
+	accessMapper groupAccessMapper
+	resolver     Resolver
+	errorMaps    []reply.ErrorManifest
+}
+
+// NewMiddleware returns tenancy middleware backed by the group service's access map. A nil
+// resolver reads the tenant from DefaultTenantHeader, then DefaultTenantPathVariable.
+// TenancyErrorMap is always the base layer, caller-supplied maps are applied as overrides.
+func NewMiddleware(accessMapper groupAccessMapper, resolver Resolver, errorMaps ...reply.ErrorManifest) *Middleware {
+	if resolver == nil {
+		resolver = FirstOf(HeaderResolver(DefaultTenantHeader), PathResolver(DefaultTenantPathVariable))
+	}
+
+	return &Middleware{
+		accessMapper: accessMapper,
+		resolver:     resolver,
+		errorMaps:    errorMaps,
+	}
+}
+
+// RequireTenant returns a gorilla/mux middleware function that only lets requests through
+// when they name a tenant the signed-in user can access and holds every listed permission
+// in. The verified tenant is placed on the request context. It must run after the
+// authentication middleware that places the user on the request context.
+func (m *Middleware) RequireTenant(permissions ...string) mux.MiddlewareFunc {
+	return m.tenantMiddleware(true, permissions)
+}
+
+// ResolveTenant returns a gorilla/mux middleware function that verifies and places the
+// tenant on the request context when the request names one, and lets requests without
+// a tenant through unchanged, so routes can serve both personal and group-owned records.
+func (m *Middleware) ResolveTenant() mux.MiddlewareFunc {
+	return m.tenantMiddleware(false, nil)
+}
+
+// tenantMiddleware builds the middleware shared by RequireTenant and ResolveTenant
+func (m *Middleware) tenantMiddleware(required bool, permissions []string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			groupID := m.resolver(r)
+			if groupID == "" && !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tenant, err := m.verifyTenant(r.Context(), groupID, permissions)
+			if err != nil {
+				logger := logger.AcquirePackageFrom(r.Context(), "external/tenancy")
+				logger.Info("request-blocked-by-tenancy-check", zap.String("group-id", groupID), zap.String("path", r.URL.Path), zap.Error(err))
+				m.getBaseResponseHandler().NewHTTPErrorResponse(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(TransitWith(r.Context(), tenant)))
+		})
+	}
+}
+
+// verifyTenant returns the tenant when the signed-in user can access the group and holds
+// the permissions
+func (m *Middleware) verifyTenant(ctx context.Context, groupID string, permissions []string) (*Tenant, error) {
+	if groupID == "" {
+		return nil, ErrTenantIsRequired
+	}
+
+	userID := accessmanagerhelpers.AcquireFrom(ctx)
+	if userID == "" {
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	accessMap, err := m.accessMapper.GetUserGroupAccessMap(ctx, userID)
+	if err != nil {
+		logger := logger.AcquirePackageFrom(ctx, "external/tenancy")
+		logger.Error("failed-to-get-user-group-access-map", zap.String("user-id", userID), zap.Error(err))
+		return nil, ErrTenantAccessUnavailable
+	}
+
+	access, ok := accessMap[groupID]
+	if !ok || !access.IsAccessible {
+		return nil, ErrTenantAccessDenied
+	}
+
+	tenant := NewTenant(groupID, access)
+	for _, permission := range permissions {
+		if !tenant.Can(permission) {
+			return nil, ErrTenantAccessDenied
+		}
+	}
+
+	return tenant, nil
+}
+
+// getBaseResponseHandler returns a response handler with TenancyErrorMap as the base layer
+func (m *Middleware) getBaseResponseHandler() *reply.Replier {
+	return reply.NewReplier(
+		errormanifest.NewComposer().
+			Add(TenancyErrorMap).
+			AddOverrides(m.errorMaps...).
+			Build(),
+	)
+}
@@ -0,0 +1,150 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	accessmanagerhelpers "github.com/ooaklee/ghatd/external/accessmanager/helpers"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// fakeAccessMapper returns a canned group access map for every user
+type fakeAccessMapper struct {
+	accessMap map[string]group.UserGroupAccessSummary
+	err       error
+}
+
+func (f *fakeAccessMapper) GetUserGroupAccessMap(ctx context.Context, userID string) (map[string]group.UserGroupAccessSummary, error) {
+	return f.accessMap, f.err
+}
+
+// serveThroughMiddleware runs a request for the user through the middleware and returns
+// the response with the tenant the next handler saw
+func serveThroughMiddleware(middleware mux.MiddlewareFunc, userID, tenantHeader string) (*httptest.ResponseRecorder, *Tenant) {
+	var seen *Tenant
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = AcquireFrom(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ums/me/reminders", nil)
+	if tenantHeader != "" {
+		req.Header.Set(DefaultTenantHeader, tenantHeader)
+	}
+	if userID != "" {
+		req = req.WithContext(accessmanagerhelpers.TransitWith(req.Context(), userID))
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder, seen
+}
+
+func TestMiddleware_RequireTenant(t *testing.T) {
+	accessMapper := &fakeAccessMapper{accessMap: map[string]group.UserGroupAccessSummary{
+		"group-1": {MaxRole: group.MemberRoleMember, IsAccessible: true, Permissions: []string{group.PermissionMembersView}},
+		"group-2": {MaxRole: group.MemberRoleMember, IsAccessible: false},
+	}}
+	middleware := NewMiddleware(accessMapper, nil)
+
+	tests := []struct {
+		name           string
+		permissions    []string
+		userID         string
+		tenantHeader   string
+		expectedStatus int
+	}{
+		{name: "member is let through", userID: "user-1", tenantHeader: "group-1", expectedStatus: http.StatusNoContent},
+		{name: "member holding permission is let through", permissions: []string{group.PermissionMembersView}, userID: "user-1", tenantHeader: "group-1", expectedStatus: http.StatusNoContent},
+		{name: "missing permission is denied", permissions: []string{group.PermissionMembersManage}, userID: "user-1", tenantHeader: "group-1", expectedStatus: http.StatusForbidden},
+		{name: "inaccessible group is denied", userID: "user-1", tenantHeader: "group-2", expectedStatus: http.StatusForbidden},
+		{name: "unknown group is denied", userID: "user-1", tenantHeader: "group-3", expectedStatus: http.StatusForbidden},
+		{name: "missing tenant is rejected", userID: "user-1", expectedStatus: http.StatusBadRequest},
+		{name: "missing user is rejected", tenantHeader: "group-1", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder, tenant := serveThroughMiddleware(middleware.RequireTenant(test.permissions...), test.userID, test.tenantHeader)
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus == http.StatusNoContent {
+				require.NotNil(t, tenant)
+				assert.Equal(t, test.tenantHeader, tenant.GroupID)
+				assert.Equal(t, group.MemberRoleMember, tenant.Role)
+			} else {
+				assert.Nil(t, tenant)
+			}
+		})
+	}
+}
+
+func TestMiddleware_ResolveTenant(t *testing.T) {
+	middleware := NewMiddleware(&fakeAccessMapper{accessMap: map[string]group.UserGroupAccessSummary{
+		"group-1": {IsAccessible: true, IsAdmin: true},
+	}}, nil)
+
+	recorder, tenant := serveThroughMiddleware(middleware.ResolveTenant(), "user-1", "")
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Nil(t, tenant)
+
+	recorder, tenant = serveThroughMiddleware(middleware.ResolveTenant(), "user-1", "group-1")
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	require.NotNil(t, tenant)
+	assert.True(t, tenant.IsAdmin)
+
+	recorder, _ = serveThroughMiddleware(middleware.ResolveTenant(), "user-1", "group-2")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestMiddleware_AccessMapFailure(t *testing.T) {
+	middleware := NewMiddleware(&fakeAccessMapper{err: errors.New("store offline")}, nil)
+
+	recorder, tenant := serveThroughMiddleware(middleware.RequireTenant(), "user-1", "group-1")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Nil(t, tenant)
+}
+
+func TestResolvers(t *testing.T) {
+	router := mux.NewRouter()
+	resolver := FirstOf(HeaderResolver(""), PathResolver(""))
+
+	var resolved string
+	router.HandleFunc("/groups/{tenantID}/reminders", func(w http.ResponseWriter, r *http.Request) {
+		resolved = resolver(r)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/groups/group-path/reminders", nil))
+	assert.Equal(t, "group-path", resolved)
+
+	req := httptest.NewRequest(http.MethodGet, "/groups/group-path/reminders", nil)
+	req.Header.Set(DefaultTenantHeader, " group-header ")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "group-header", resolved)
+}
+
+func TestScopeFilter(t *testing.T) {
+	personal := ScopeFilter(context.Background(), bson.M{"user_id": "user-1"})
+	assert.Equal(t, bson.M{"user_id": "user-1", TenantField: bson.M{"$exists": false}}, personal)
+
+	ctx := TransitWith(context.Background(), &Tenant{GroupID: "group-1"})
+	assert.Equal(t, bson.M{TenantField: "group-1"}, ScopeFilter(ctx, nil))
+}
+
+func TestMatchesScope(t *testing.T) {
+	ctx := TransitWith(context.Background(), &Tenant{GroupID: "group-1"})
+
+	assert.True(t, MatchesScope(ctx, "group-1"))
+	assert.False(t, MatchesScope(ctx, "group-2"))
+	assert.False(t, MatchesScope(ctx, ""))
+	assert.True(t, MatchesScope(context.Background(), ""))
+	assert.False(t, MatchesScope(context.Background(), "group-1"))
+}
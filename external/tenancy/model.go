@@ -0,0 +1,41 @@
+package tenancy
+
+import (
+	"slices"
+
+	"github.com/ooaklee/ghatd/external/group"
+)
+
+// Tenant is the group a request acts for, with the caller's verified access to it
+type Tenant struct {
+	// GroupID is the ID of the tenant group.
+	GroupID string `json:"group_id"`
+
+	// Role is the caller's highest role in the group.
+	Role string `json:"role,omitempty"`
+
+	// IsAdmin is true when the caller has effective admin privileges in the group.
+	IsAdmin bool `json:"is_admin"`
+
+	// Permissions are the caller's effective permissions in the group, including inherited ones.
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// NewTenant returns the tenant for a group from the caller's group access summary
+func NewTenant(groupID string, access group.UserGroupAccessSummary) *Tenant {
+	return &Tenant{
+		GroupID:     groupID,
+		Role:        access.MaxRole,
+		IsAdmin:     access.IsAdmin,
+		Permissions: access.Permissions,
+	}
+}
+
+// Can returns true when the caller holds the permission in the tenant group
+func (t *Tenant) Can(permission string) bool {
+	if t == nil {
+		return false
+	}
+
+	return slices.Contains(t.Permissions, permission)
+}
@@ -0,0 +1,48 @@
+package tenancy
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Resolver reads the ID of the group a request is acting for, returning an empty
+// string when the request names none
+type Resolver func(r *http.Request) string
+
+// HeaderResolver reads the tenant group ID from a request header, DefaultTenantHeader
+// when header is empty
+func HeaderResolver(header string) Resolver {
+	if header == "" {
+		header = DefaultTenantHeader
+	}
+
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(header))
+	}
+}
+
+// PathResolver reads the tenant group ID from a route variable, DefaultTenantPathVariable
+// when variable is empty
+func PathResolver(variable string) Resolver {
+	if variable == "" {
+		variable = DefaultTenantPathVariable
+	}
+
+	return func(r *http.Request) string {
+		return strings.TrimSpace(mux.Vars(r)[variable])
+	}
+}
+
+// FirstOf returns the first tenant group ID found by the resolvers, in order
+func FirstOf(resolvers ...Resolver) Resolver {
+	return func(r *http.Request) string {
+		for _, resolver := range resolvers {
+			if groupID := resolver(r); groupID != "" {
+				return groupID
+			}
+		}
+		return ""
+	}
+}
@@ -60,6 +60,7 @@ const (
 	GetUserOrderStatusChangedAtAsc  = "status_changed_at_asc"
 	GetUserOrderEmailVerifiedAtDesc = "email_verified_at_desc"
 	GetUserOrderEmailVerifiedAtAsc  = "email_verified_at_asc"
+	GetUserOrderIDAsc               = "id_asc"
 )
 
 const (
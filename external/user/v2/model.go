@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/PaesslerAG/jsonpath"
@@ -165,6 +166,9 @@ type UniversalUser struct {
 	// Optional personal information
 	PersonalInfo *PersonalInfo `json:"personal_info,omitempty" bson:"personal_info,omitempty" db:"personal_info"`
 
+	// Optional billing address, printed on invoices
+	BillingAddress *BillingAddress `json:"billing_address,omitempty" bson:"billing_address,omitempty" db:"billing_address"`
+
 	// Flexible roles system
 	Roles []string `json:"roles" bson:"roles" db:"roles"`
 
@@ -194,6 +198,26 @@ type PersonalInfo struct {
 	Phone  string `json:"phone,omitempty" bson:"phone,omitempty" db:"phone"`
 }
 
+// BillingAddress holds the address a user is billed at
+type BillingAddress struct {
+	// Name is the person or company billed, when it differs from the user's full name
+	Name       string `json:"name,omitempty" bson:"name,omitempty" db:"name"`
+	Line1      string `json:"line1,omitempty" bson:"line1,omitempty" db:"line1"`
+	Line2      string `json:"line2,omitempty" bson:"line2,omitempty" db:"line2"`
+	City       string `json:"city,omitempty" bson:"city,omitempty" db:"city"`
+	Region     string `json:"region,omitempty" bson:"region,omitempty" db:"region"`
+	PostalCode string `json:"postal_code,omitempty" bson:"postal_code,omitempty" db:"postal_code"`
+	// Country is the ISO 3166-1 alpha-2 country code, used to pick the invoice tax rate
+	Country string `json:"country,omitempty" bson:"country,omitempty" db:"country"`
+	// TaxID is the customer's VAT or other tax registration number
+	TaxID string `json:"tax_id,omitempty" bson:"tax_id,omitempty" db:"tax_id"`
+}
+
+// IsEmpty returns true if no part of the billing address is set
+func (a *BillingAddress) IsEmpty() bool {
+	return a == nil || *a == BillingAddress{}
+}
+
 // VerificationStatus holds verification information
 type VerificationStatus struct {
 	EmailVerified   bool   `json:"email_verified" bson:"email_verified" db:"email_verified"`
@@ -321,6 +345,27 @@ func (u *UniversalUser) SetFullName() *UniversalUser {
 	return u
 }
 
+// SetBillingAddress replaces the user's billing address, trimming each part and
+// upper-casing the country. An empty address clears it
+func (u *UniversalUser) SetBillingAddress(address *BillingAddress) *UniversalUser {
+	if address.IsEmpty() {
+		u.BillingAddress = nil
+		return u
+	}
+
+	u.BillingAddress = &BillingAddress{
+		Name:       strings.TrimSpace(address.Name),
+		Line1:      strings.TrimSpace(address.Line1),
+		Line2:      strings.TrimSpace(address.Line2),
+		City:       strings.TrimSpace(address.City),
+		Region:     strings.TrimSpace(address.Region),
+		PostalCode: strings.TrimSpace(address.PostalCode),
+		Country:    strings.ToUpper(strings.TrimSpace(address.Country)),
+		TaxID:      strings.TrimSpace(address.TaxID),
+	}
+	return u
+}
+
 // GenerateNewUUID creates a new UUID for the user
 func (u *UniversalUser) GenerateNewUUID() *UniversalUser {
 	if u.idGenerator != nil {
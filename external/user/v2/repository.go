@@ -218,7 +218,15 @@ func (r *Repository) GetUsers(ctx context.Context, req *GetUsersRequest) ([]Univ
 	}
 
 	// Build query filter
-	queryFilter := r.buildUserQueryFilter(req.EmailFilter, "", req.FirstNameFilter, req.LastNameFilter, req.StatusFilter, req.RoleFilter, req.IDsFilter, req.RolesFilter, req.OnlyAdmin, req.EmailVerified, req.PhoneVerified, req.ExtensionKey, req.ExtensionValue)
+	queryFilter := r.buildUserQueryFilter(req.EmailFilter, "", req.FirstNameFilter, req.LastNameFilter, req.StatusFilter, req.RoleFilter, req.IDsFilter, req.RolesFilter, req.TypesFilter, req.OnlyAdmin, req.EmailVerified, req.PhoneVerified, req.ExtensionKey, req.ExtensionValue)
+	if req.AfterID != "" {
+		idFilter, ok := queryFilter["_id"].(bson.M)
+		if !ok {
+			idFilter = bson.M{}
+		}
+		idFilter["$gt"] = req.AfterID
+		queryFilter["_id"] = idFilter
+	}
 
 	// Build sort options
 	sortOptions := r.buildSortOptions(req.Order)
@@ -253,7 +261,7 @@ func (r *Repository) GetTotalUsers(ctx context.Context, req *GetTotalUsersReques
 		return 0, err
 	}
 
-	queryFilter := r.buildUserQueryFilter(req.EmailFilter, req.EmailRegex, req.FirstNameFilter, req.LastNameFilter, req.StatusFilter, req.RoleFilter, req.IDsFilter, req.RolesFilter, req.OnlyAdmin, req.EmailVerified, req.PhoneVerified, req.ExtensionKey, req.ExtensionValue)
+	queryFilter := r.buildUserQueryFilter(req.EmailFilter, req.EmailRegex, req.FirstNameFilter, req.LastNameFilter, req.StatusFilter, req.RoleFilter, req.IDsFilter, req.RolesFilter, req.TypesFilter, req.OnlyAdmin, req.EmailVerified, req.PhoneVerified, req.ExtensionKey, req.ExtensionValue)
 
 	count, err := r.Store.ExecuteCountDocuments(ctx, collection, queryFilter)
 	if err != nil {
@@ -344,7 +352,7 @@ func (r *Repository) GetUserStatsCounts(ctx context.Context, req *GetUserStatsRe
 // Helper methods
 
 // buildUserQueryFilter builds a query filter for user searches
-func (r *Repository) buildUserQueryFilter(emailFilter, emailRegex, firstNameFilter, lastNameFilter, statusFilter, roleFilter string, idsFilter, rolesFilter []string, onlyAdmin bool, emailVerified, phoneVerified *bool, extensionKey string, extensionValue interface{}) bson.M {
+func (r *Repository) buildUserQueryFilter(emailFilter, emailRegex, firstNameFilter, lastNameFilter, statusFilter, roleFilter string, idsFilter, rolesFilter, typesFilter []string, onlyAdmin bool, emailVerified, phoneVerified *bool, extensionKey string, extensionValue interface{}) bson.M {
 	queryFilter := bson.M{}
 
 	if emailRegex != "" {
@@ -388,6 +396,10 @@ func (r *Repository) buildUserQueryFilter(emailFilter, emailRegex, firstNameFilt
 		queryFilter["roles"] = bson.M{"$in": rolesFilter}
 	}
 
+	if len(typesFilter) > 0 {
+		queryFilter["type"] = bson.M{"$in": typesFilter}
+	}
+
 	if onlyAdmin {
 		queryFilter["roles"] = UserRoleAdmin
 	}
@@ -438,6 +450,8 @@ func (r *Repository) buildSortOptions(order string) bson.D {
 		return bson.D{{Key: "verification.email_verified_at", Value: 1}}
 	case GetUserOrderEmailVerifiedAtDesc:
 		return bson.D{{Key: "verification.email_verified_at", Value: -1}}
+	case GetUserOrderIDAsc:
+		return bson.D{{Key: "_id", Value: 1}}
 	default:
 		return bson.D{{Key: "metadata.created_at", Value: -1}}
 	}
@@ -29,6 +29,10 @@ type UpdateUserRequest struct {
 	Status     string                 `json:"status,omitempty"`
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
 
+	// BillingAddress replaces the user's billing address when set. An empty
+	// address clears it
+	BillingAddress *BillingAddress `json:"billing_address,omitempty"`
+
 	User *UniversalUser `json:"-"`
 }
 
@@ -74,11 +78,17 @@ type GetUsersRequest struct {
 	RoleFilter      string      `query:"with_role"`
 	IDsFilter       []string    `query:"with_ids"`
 	RolesFilter     []string    `query:"with_roles"`
+	TypesFilter     []string    `query:"with_types"`
 	OnlyAdmin       bool        `query:"only_admin"`
 	EmailVerified   *bool       `query:"email_verified"`
 	PhoneVerified   *bool       `query:"phone_verified"`
 	ExtensionKey    string      `query:"with_extension_key"`
 	ExtensionValue  interface{} `query:"with_extension_value"`
+
+	// AfterID is an internal-only keyset cursor that limits results to users
+	// whose ID sorts after it. Pair it with GetUserOrderIDAsc and page 1 so
+	// batches stay stable while users change between requests.
+	AfterID string
 }
 
 // GetTotalUsersRequest holds filters for counting total users
@@ -90,6 +100,7 @@ type GetTotalUsersRequest struct {
 	RoleFilter      string      `query:"with_role"`
 	IDsFilter       []string    `query:"with_ids"`
 	RolesFilter     []string    `query:"with_roles"`
+	TypesFilter     []string    `query:"with_types"`
 	OnlyAdmin       bool        `query:"only_admin"`
 	EmailVerified   *bool       `query:"email_verified"`
 	PhoneVerified   *bool       `query:"phone_verified"`
@@ -396,6 +396,11 @@ func (s *Service) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*Upda
 			hasChanges = true
 		}
 
+		if req.BillingAddress != nil {
+			user.SetBillingAddress(req.BillingAddress)
+			hasChanges = true
+		}
+
 		if !hasChanges {
 			return &UpdateUserResponse{User: user}, nil
 		}
@@ -491,6 +496,7 @@ func (s *Service) GetUsers(ctx context.Context, req *GetUsersRequest) (*GetUsers
 		RoleFilter:      req.RoleFilter,
 		IDsFilter:       req.IDsFilter,
 		RolesFilter:     req.RolesFilter,
+		TypesFilter:     req.TypesFilter,
 		OnlyAdmin:       req.OnlyAdmin,
 		EmailVerified:   req.EmailVerified,
 		PhoneVerified:   req.PhoneVerified,
@@ -76,11 +76,29 @@ const (
 	// ErrKeyInvalidMemberID returned when the provided member ID is invalid or empty
 	ErrKeyInvalidMemberID = "InvalidMemberID"
 
+	// ErrKeyBillingServiceNotEnabled is returned when group billing is requested but BillingService is not configured
+	ErrKeyBillingServiceNotEnabled = "BillingServiceNotEnabled"
+
 	// UserManagerURIVariableAddressID is the URI variable for notification address ID
 	UserManagerURIVariableAddressID = "addressID"
 
+	// UserManagerURIVariableNotificationTopic is the URI variable for a notification topic
+	UserManagerURIVariableNotificationTopic = "topic"
+
+	// UserManagerURIVariableDispatchID is the URI variable for a notification dispatch job ID
+	UserManagerURIVariableDispatchID = "dispatchID"
+
 	// UserManagerURIVariableReminderID is the URI variable for reminder ID
 	UserManagerURIVariableReminderID = "reminderID"
+
+	// UserManagerURIVariableJoinRequestID is the URI variable for a group join request ID
+	UserManagerURIVariableJoinRequestID = "joinRequestID"
+
+	// UserManagerURIVariableImportID is the URI variable for a group member import ID
+	UserManagerURIVariableImportID = "importID"
+
+	// MaxGroupMemberImportUploadBytes is the largest group member import upload that is read
+	MaxGroupMemberImportUploadBytes = 5 << 20
 )
 
 const (
@@ -31,6 +31,7 @@ var UsermanagerErrorMap reply.ErrorManifest = reply.ErrorManifest{
 	ErrVisionServiceNotEnabled:     {Title: "Service Unavailable", Detail: "Vision features have not been enabled for this service.", StatusCode: 503, Code: "USM00-021"},
 	ErrVisionEditForbidden:         {Title: "Forbidden", Detail: "Only the feedback owner or a platform administrator can edit this item.", StatusCode: 403, Code: "USM00-022"},
 	ErrVisionDeleteForbidden:       {Title: "Forbidden", Detail: "Only the feedback owner or a platform administrator can delete this item.", StatusCode: 403, Code: "USM00-023"},
+	ErrBillingServiceNotEnabled:    {Title: "Service Unavailable", Detail: "Billing features have not been enabled for this service.", StatusCode: 503, Code: "USM00-024"},
 	ErrFailedToResolveGroupAccessMap: {
 		Title:      "Internal Error",
 		Detail:     "Failed to resolve user access for the requested group. Please try again.",
@@ -3,6 +3,7 @@ package usermanager
 import "errors"
 
 var (
+	ErrBillingServiceNotEnabled      = errors.New(ErrKeyBillingServiceNotEnabled)
 	ErrBulkOperationPartialFailure   = errors.New(ErrKeyBulkOperationPartialFailure)
 	ErrFailedToAddUserToGroup        = errors.New(ErrKeyFailedToAddUserToGroup)
 	ErrFailedToRemoveUserFromGroup   = errors.New(ErrKeyFailedToRemoveUserFromGroup)
@@ -774,6 +774,28 @@ func (m *MockGroupService) GetUserGroupAccessMap(ctx context.Context, userID str
 	return accessMap, nil
 }
 
+func (m *MockGroupService) Can(ctx context.Context, userID, groupID, permission string) (bool, error) {
+	groupResp, err := m.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: groupID})
+	if err != nil {
+		return false, err
+	}
+
+	role := ""
+	if groupResp.Group.OwnerID == userID {
+		role = group.MemberRoleOwner
+	} else if member, memberErr := groupResp.Group.GetMemberByID(userID); memberErr == nil {
+		role = strings.ToUpper(member.Role)
+	}
+
+	for _, rolePermission := range group.DefaultRolePermissions()[role] {
+		if rolePermission == group.PermissionAll || rolePermission == permission {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (m *MockGroupService) GetGroupsConfig(_ context.Context, _ *group.GetGroupsConfigRequest) (*group.GetGroupsConfigResponse, error) {
 	return &group.GetGroupsConfigResponse{}, nil
 }
@@ -838,6 +860,10 @@ func (m *MockGroupService) AcceptInvite(ctx context.Context, req *group.AcceptIn
 	}, nil
 }
 
+func (m *MockGroupService) AcceptInviteByToken(ctx context.Context, req *group.AcceptInviteByTokenRequest) (*group.AcceptInviteByTokenResponse, error) {
+	return nil, group.ErrInvalidInvitationToken
+}
+
 func (m *MockGroupService) RejectInvite(ctx context.Context, req *group.RejectInviteRequest) (*group.RejectInviteResponse, error) {
 	groupResp, err := m.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: req.GroupID})
 	if err != nil {
@@ -850,6 +876,52 @@ func (m *MockGroupService) RejectInvite(ctx context.Context, req *group.RejectIn
 	}, nil
 }
 
+func (m *MockGroupService) RequestToJoinGroup(ctx context.Context, req *group.RequestToJoinGroupRequest) (*group.RequestToJoinGroupResponse, error) {
+	return &group.RequestToJoinGroupResponse{
+		JoinRequest: &group.GroupJoinRequest{
+			ID:      "mock-join-request-id",
+			GroupID: req.GroupID,
+			UserID:  req.UserID,
+			Status:  group.JoinRequestStatusPending,
+			Message: req.Message,
+		},
+	}, nil
+}
+
+func (m *MockGroupService) GetJoinRequests(ctx context.Context, req *group.GetJoinRequestsRequest) (*group.GetJoinRequestsResponse, error) {
+	return &group.GetJoinRequestsResponse{JoinRequests: []group.GroupJoinRequest{}}, nil
+}
+
+func (m *MockGroupService) ApproveJoinRequest(ctx context.Context, req *group.ReviewJoinRequestRequest) (*group.ReviewJoinRequestResponse, error) {
+	return &group.ReviewJoinRequestResponse{
+		JoinRequest: &group.GroupJoinRequest{ID: req.JoinRequestID, GroupID: req.GroupID, Status: group.JoinRequestStatusApproved, ReviewedByID: req.ReviewerID},
+	}, nil
+}
+
+func (m *MockGroupService) DenyJoinRequest(ctx context.Context, req *group.ReviewJoinRequestRequest) (*group.ReviewJoinRequestResponse, error) {
+	return &group.ReviewJoinRequestResponse{
+		JoinRequest: &group.GroupJoinRequest{ID: req.JoinRequestID, GroupID: req.GroupID, Status: group.JoinRequestStatusDenied, ReviewedByID: req.ReviewerID, DecisionReason: req.Reason},
+	}, nil
+}
+
+func (m *MockGroupService) CancelJoinRequest(ctx context.Context, req *group.CancelJoinRequestRequest) (*group.CancelJoinRequestResponse, error) {
+	return &group.CancelJoinRequestResponse{
+		JoinRequest: &group.GroupJoinRequest{ID: req.JoinRequestID, UserID: req.UserID, Status: group.JoinRequestStatusCancelled},
+	}, nil
+}
+
+func (m *MockGroupService) GetGroupActivity(ctx context.Context, req *group.GetGroupActivityRequest) (*group.GetGroupActivityResponse, error) {
+	return &group.GetGroupActivityResponse{Activities: []group.GroupActivity{}}, nil
+}
+
+func (m *MockGroupService) ImportMembers(ctx context.Context, req *group.ImportMembersRequest) (*group.ImportMembersResponse, error) {
+	return nil, group.ErrMemberImportsNotEnabled
+}
+
+func (m *MockGroupService) GetMemberImport(ctx context.Context, req *group.GetMemberImportRequest) (*group.GetMemberImportResponse, error) {
+	return nil, group.ErrMemberImportNotFound
+}
+
 func createMockGroup(id, name, groupType string, memberCount int) *group.UniversalGroup {
 	config := group.DefaultGroupConfig()
 	idGen := group.NewDefaultIDGenerator()
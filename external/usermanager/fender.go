@@ -2,6 +2,7 @@ package usermanager
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -713,6 +714,113 @@ func MapRequestToNotifyUsersRequest(r *http.Request, validator UsermanagerValida
 	return &parsedRequest, nil
 }
 
+// MapRequestToGetNotificationDispatchRequest maps incoming notification dispatch lookup request to the correct struct.
+func MapRequestToGetNotificationDispatchRequest(r *http.Request, validator UsermanagerValidator) (*GetNotificationDispatchRequest, error) {
+	var parsedRequest GetNotificationDispatchRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	dispatchID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableDispatchID)
+	if err != nil {
+		logger.Error("unable-get-notification-dispatch-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+
+	parsedRequest.GetDispatchJobRequest = &notifier.GetDispatchJobRequest{JobID: dispatchID}
+	return &parsedRequest, nil
+}
+
+// MapRequestToResumeNotificationDispatchRequest maps incoming notification dispatch resume request to the correct struct.
+func MapRequestToResumeNotificationDispatchRequest(r *http.Request, validator UsermanagerValidator) (*ResumeNotificationDispatchRequest, error) {
+	var parsedRequest ResumeNotificationDispatchRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	dispatchID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableDispatchID)
+	if err != nil {
+		logger.Error("unable-get-notification-dispatch-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+
+	parsedRequest.ResumeDispatchJobRequest = &notifier.ResumeDispatchJobRequest{JobID: dispatchID}
+	return &parsedRequest, nil
+}
+
+// MapRequestToSubscribeNotificationTopicRequest maps incoming notification topic subscription to the correct struct.
+func MapRequestToSubscribeNotificationTopicRequest(r *http.Request, validator UsermanagerValidator) (*SubscribeNotificationTopicRequest, error) {
+	var parsedRequest SubscribeNotificationTopicRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	baseRequest := notifier.SubscribeToTopicRequest{}
+	if err := toolbox.DecodeRequestBody(r, &baseRequest); err != nil {
+		return nil, notifier.ErrInvalidNotificationTopic
+	}
+	baseRequest.UserID = parsedRequest.UserId
+
+	parsedRequest.SubscribeToTopicRequest = &baseRequest
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("subscribe-notification-topic-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToUnsubscribeNotificationTopicRequest maps incoming notification topic unsubscription to the correct struct.
+func MapRequestToUnsubscribeNotificationTopicRequest(r *http.Request, validator UsermanagerValidator) (*UnsubscribeNotificationTopicRequest, error) {
+	var parsedRequest UnsubscribeNotificationTopicRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	topic, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableNotificationTopic)
+	if err != nil {
+		logger.Error("unable-get-notification-topic-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+
+	parsedRequest.UnsubscribeFromTopicRequest = &notifier.UnsubscribeFromTopicRequest{
+		UserID: parsedRequest.UserId,
+		Topic:  topic,
+	}
+	return &parsedRequest, nil
+}
+
+// MapRequestToListNotificationTopicsRequest maps incoming notification topic list request to the correct struct.
+func MapRequestToListNotificationTopicsRequest(r *http.Request, validator UsermanagerValidator) (*ListNotificationTopicsRequest, error) {
+	var parsedRequest ListNotificationTopicsRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	parsedRequest.ListTopicSubscriptionsRequest = &notifier.ListTopicSubscriptionsRequest{UserID: parsedRequest.UserId}
+	return &parsedRequest, nil
+}
+
 // MapRequestToGetMyGroupInvitationsRequest maps incoming my-group-invitations request to the correct struct.
 func MapRequestToGetMyGroupInvitationsRequest(r *http.Request, validator UsermanagerValidator) (*GetMyGroupInvitationsRequest, error) {
 	var parsedRequest GetMyGroupInvitationsRequest
@@ -761,6 +869,29 @@ func MapRequestToAcceptMyGroupInvitationRequest(r *http.Request, validator Userm
 	return &parsedRequest, nil
 }
 
+// MapRequestToAcceptMyGroupInvitationByTokenRequest maps incoming accept-my-group-invitation-by-token request to the correct struct.
+func MapRequestToAcceptMyGroupInvitationByTokenRequest(r *http.Request, validator UsermanagerValidator) (*AcceptMyGroupInvitationByTokenRequest, error) {
+	var parsedRequest AcceptMyGroupInvitationByTokenRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	if err := toolbox.DecodeRequestBody(r, &parsedRequest); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("accept-my-group-invitation-by-token-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
 // MapRequestToRejectMyGroupInvitationRequest maps incoming reject-my-group-invitation request to the correct struct.
 func MapRequestToRejectMyGroupInvitationRequest(r *http.Request, validator UsermanagerValidator) (*RejectMyGroupInvitationRequest, error) {
 	var parsedRequest RejectMyGroupInvitationRequest
@@ -786,6 +917,307 @@ func MapRequestToRejectMyGroupInvitationRequest(r *http.Request, validator Userm
 	return &parsedRequest, nil
 }
 
+// MapRequestToGetMyJoinRequestsRequest maps incoming my-join-requests request to the correct struct.
+func MapRequestToGetMyJoinRequestsRequest(r *http.Request, validator UsermanagerValidator) (*GetMyJoinRequestsRequest, error) {
+	var parsedRequest GetMyJoinRequestsRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	if err := querydecoder.New(r.URL.Query()).Decode(&parsedRequest); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("get-my-join-requests-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToCreateMyJoinRequestRequest maps incoming create-my-join-request request to the correct struct.
+func MapRequestToCreateMyJoinRequestRequest(r *http.Request, validator UsermanagerValidator) (*CreateMyJoinRequestRequest, error) {
+	var parsedRequest CreateMyJoinRequestRequest = CreateMyJoinRequestRequest{
+		RequestToJoinGroupRequest: &group.RequestToJoinGroupRequest{},
+	}
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	if err := toolbox.DecodeRequestBody(r, &parsedRequest); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+	parsedRequest.RequestToJoinGroupRequest.UserID = parsedRequest.UserId
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("create-my-join-request-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToCancelMyJoinRequestRequest maps incoming cancel-my-join-request request to the correct struct.
+func MapRequestToCancelMyJoinRequestRequest(r *http.Request, validator UsermanagerValidator) (*CancelMyJoinRequestRequest, error) {
+	var parsedRequest CancelMyJoinRequestRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	joinRequestID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableJoinRequestID)
+	if err != nil {
+		logger.Error("unable-get-join-request-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.JoinRequestID = joinRequestID
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("cancel-my-join-request-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToGetGroupJoinRequestsRequest maps incoming group join requests listing request to the correct struct.
+func MapRequestToGetGroupJoinRequestsRequest(r *http.Request, validator UsermanagerValidator) (*GetGroupJoinRequestsRequest, error) {
+	var parsedRequest GetGroupJoinRequestsRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	groupID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableGroupID)
+	if err != nil {
+		logger.Error("unable-get-group-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.GroupID = groupID
+
+	if err := querydecoder.New(r.URL.Query()).Decode(&parsedRequest); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("get-group-join-requests-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToGetGroupActivityRequest maps incoming group activity feed request to the correct struct.
+func MapRequestToGetGroupActivityRequest(r *http.Request, validator UsermanagerValidator) (*GetGroupActivityRequest, error) {
+	var parsedRequest GetGroupActivityRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	groupID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableGroupID)
+	if err != nil {
+		logger.Error("unable-get-group-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.GroupID = groupID
+
+	if err := querydecoder.New(r.URL.Query()).Decode(&parsedRequest); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("get-group-activity-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToImportGroupMembersRequest maps incoming group member import request to the correct struct.
+// The upload is read as CSV or JSON from the format query parameter, falling back to the Content-Type.
+func MapRequestToImportGroupMembersRequest(r *http.Request, validator UsermanagerValidator) (*ImportGroupMembersRequest, error) {
+	var parsedRequest ImportGroupMembersRequest = ImportGroupMembersRequest{
+		ImportMembersRequest: &group.ImportMembersRequest{},
+	}
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	groupID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableGroupID)
+	if err != nil {
+		logger.Error("unable-get-group-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.GroupID = groupID
+
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		parsedRequest.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			return nil, ErrRequestFailedValidation
+		}
+	}
+
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil, group.ErrInvalidMemberImport
+	}
+
+	entries, err := group.ParseMemberImportEntries(memberImportFormatFromRequest(r), http.MaxBytesReader(nil, r.Body, MaxGroupMemberImportUploadBytes))
+	if err != nil {
+		logger.Warn("unable-to-parse-group-member-import", zap.String("group-id", groupID), zap.Error(err))
+		return nil, err
+	}
+	parsedRequest.Entries = entries
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("import-group-members-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// memberImportFormatFromRequest returns the format query parameter, otherwise csv for CSV
+// uploads and json for everything else
+func memberImportFormatFromRequest(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	if strings.Contains(strings.ToLower(r.Header.Get("Content-Type")), group.MemberImportFormatCSV) {
+		return group.MemberImportFormatCSV
+	}
+
+	return group.MemberImportFormatJSON
+}
+
+// MapRequestToGetGroupMemberImportRequest maps incoming group member import progress request to the correct struct.
+func MapRequestToGetGroupMemberImportRequest(r *http.Request, validator UsermanagerValidator) (*GetGroupMemberImportRequest, error) {
+	var parsedRequest GetGroupMemberImportRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	groupID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableGroupID)
+	if err != nil {
+		logger.Error("unable-get-group-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.GroupID = groupID
+
+	importID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableImportID)
+	if err != nil {
+		logger.Error("unable-get-import-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.ImportID = importID
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("get-group-member-import-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToExportGroupMembersRequest maps incoming group member export request to the correct struct.
+func MapRequestToExportGroupMembersRequest(r *http.Request, validator UsermanagerValidator) (*ExportGroupMembersRequest, error) {
+	var parsedRequest ExportGroupMembersRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	groupID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableGroupID)
+	if err != nil {
+		logger.Error("unable-get-group-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.GroupID = groupID
+
+	if err := querydecoder.New(r.URL.Query()).Decode(&parsedRequest); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("export-group-members-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
+// MapRequestToReviewGroupJoinRequestRequest maps incoming approve or deny join request requests to the correct struct.
+func MapRequestToReviewGroupJoinRequestRequest(r *http.Request, validator UsermanagerValidator) (*ReviewGroupJoinRequestRequest, error) {
+	var parsedRequest ReviewGroupJoinRequestRequest = ReviewGroupJoinRequestRequest{
+		ReviewJoinRequestRequest: &group.ReviewJoinRequestRequest{},
+	}
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := toolbox.DecodeRequestBody(r, &parsedRequest); err != nil {
+			return nil, ErrRequestFailedValidation
+		}
+	}
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+	parsedRequest.ReviewerID = parsedRequest.UserId
+
+	groupID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableGroupID)
+	if err != nil {
+		logger.Error("unable-get-group-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.GroupID = groupID
+
+	joinRequestID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableJoinRequestID)
+	if err != nil {
+		logger.Error("unable-get-join-request-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+	parsedRequest.JoinRequestID = joinRequestID
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		logger.Error("review-group-join-request-request-validation-failed", zap.Error(err))
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
 // MapRequestToGetUserGroupMembershipsRequestRequest maps incoming memberships request to correct struct.
 func MapRequestToGetUserGroupMembershipsRequestRequest(r *http.Request, validator UsermanagerValidator) (*GetUserGroupMembershipsRequest, error) {
 	parsedRequest := GetUserGroupMembershipsRequest{
@@ -878,6 +1310,32 @@ func MapRequestToGetGroupStatsRequest(r *http.Request, validator UsermanagerVali
 	return &parsedRequest, nil
 }
 
+// MapRequestToGetGroupBillingRequest maps incoming GetGroupBilling request to correct struct
+func MapRequestToGetGroupBillingRequest(r *http.Request, validator UsermanagerValidator) (*GetGroupBillingRequest, error) {
+	var parsedRequest GetGroupBillingRequest
+	logger := logger.AcquirePackageFrom(r.Context(), "external/usermanager")
+
+	parsedRequest.UserId = accessmanagerhelpers.AcquireFrom(r.Context())
+	if parsedRequest.UserId == "" {
+		logger.Error("unable-get-user-id")
+		return nil, ErrUnableToIdentifyUser
+	}
+
+	groupID, err := toolbox.GetVariableValueFromUri(r, UserManagerURIVariableGroupID)
+	if err != nil {
+		logger.Error("unable-get-group-id-from-uri")
+		return nil, ErrRequestFailedValidation
+	}
+
+	parsedRequest.GroupID = groupID
+
+	if err := validateParsedRequest(parsedRequest, validator); err != nil {
+		return nil, ErrRequestFailedValidation
+	}
+
+	return &parsedRequest, nil
+}
+
 // MapRequestToCreateGroupRequest maps incoming CreateGroup request to correct struct
 func MapRequestToCreateGroupRequest(r *http.Request, validator UsermanagerValidator) (*CreateGroupRequest, error) {
 	var parsedRequest CreateGroupRequest
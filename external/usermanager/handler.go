@@ -38,11 +38,28 @@ type UsermanagerService interface {
 	UpdateNotificationPreferences(ctx context.Context, r *UpdateNotificationPreferencesRequest) (*UpdateNotificationPreferencesResponse, error)
 	NotifyUser(ctx context.Context, r *NotifyUserRequest) (*NotifyUserResponse, error)
 	NotifyUsers(ctx context.Context, r *NotifyUsersRequest) (*NotifyUsersResponse, error)
+	GetNotificationDispatch(ctx context.Context, r *GetNotificationDispatchRequest) (*GetNotificationDispatchResponse, error)
+	ResumeNotificationDispatch(ctx context.Context, r *ResumeNotificationDispatchRequest) (*NotifyUsersResponse, error)
+	SubscribeNotificationTopic(ctx context.Context, r *SubscribeNotificationTopicRequest) (*SubscribeNotificationTopicResponse, error)
+	UnsubscribeNotificationTopic(ctx context.Context, r *UnsubscribeNotificationTopicRequest) error
+	ListNotificationTopics(ctx context.Context, r *ListNotificationTopicsRequest) (*ListNotificationTopicsResponse, error)
 	GetMyGroupInvitations(ctx context.Context, r *GetMyGroupInvitationsRequest) (*GetMyGroupInvitationsResponse, error)
 	AcceptMyGroupInvitation(ctx context.Context, r *AcceptMyGroupInvitationRequest) (*AcceptMyGroupInvitationResponse, error)
+	AcceptMyGroupInvitationByToken(ctx context.Context, r *AcceptMyGroupInvitationByTokenRequest) (*AcceptMyGroupInvitationByTokenResponse, error)
 	RejectMyGroupInvitation(ctx context.Context, r *RejectMyGroupInvitationRequest) (*RejectMyGroupInvitationResponse, error)
+	GetMyJoinRequests(ctx context.Context, r *GetMyJoinRequestsRequest) (*GetMyJoinRequestsResponse, error)
+	CreateMyJoinRequest(ctx context.Context, r *CreateMyJoinRequestRequest) (*CreateMyJoinRequestResponse, error)
+	CancelMyJoinRequest(ctx context.Context, r *CancelMyJoinRequestRequest) (*CancelMyJoinRequestResponse, error)
+	GetGroupJoinRequests(ctx context.Context, r *GetGroupJoinRequestsRequest) (*GetGroupJoinRequestsResponse, error)
+	GetGroupActivity(ctx context.Context, r *GetGroupActivityRequest) (*GetGroupActivityResponse, error)
+	ImportGroupMembers(ctx context.Context, r *ImportGroupMembersRequest) (*ImportGroupMembersResponse, error)
+	GetGroupMemberImport(ctx context.Context, r *GetGroupMemberImportRequest) (*GetGroupMemberImportResponse, error)
+	ExportGroupMembers(ctx context.Context, r *ExportGroupMembersRequest) (*ExportGroupMembersResponse, error)
+	ApproveGroupJoinRequest(ctx context.Context, r *ReviewGroupJoinRequestRequest) (*ReviewGroupJoinRequestResponse, error)
+	DenyGroupJoinRequest(ctx context.Context, r *ReviewGroupJoinRequestRequest) (*ReviewGroupJoinRequestResponse, error)
 	GetGroupDetail(ctx context.Context, r *GetGroupDetailRequest) (*GetGroupDetailResponse, error)
 	GetGroupStats(ctx context.Context, r *GetGroupStatsRequest) (*GetGroupStatsResponse, error)
+	GetGroupBilling(ctx context.Context, r *GetGroupBillingRequest) (*GetGroupBillingResponse, error)
 	CreateGroup(ctx context.Context, r *CreateGroupRequest) (*CreateGroupResponse, error)
 	UpdateGroup(ctx context.Context, r *UpdateGroupRequest) (*UpdateGroupResponse, error)
 	DeleteGroup(ctx context.Context, r *DeleteGroupRequest) (*DeleteGroupResponse, error)
@@ -754,7 +771,121 @@ func (h *Handler) NotifyUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Results)
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Results, reply.WithMeta(notificationDispatchMeta(response)))
+}
+
+// GetNotificationDispatch handles admin notification dispatch job lookups.
+func (h *Handler) GetNotificationDispatch(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-get-notification-dispatch")
+	request, err := MapRequestToGetNotificationDispatchRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetNotificationDispatch(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Job)
+}
+
+// ResumeNotificationDispatch handles resuming an interrupted admin notification dispatch.
+func (h *Handler) ResumeNotificationDispatch(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-resume-notification-dispatch")
+	request, err := MapRequestToResumeNotificationDispatchRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ResumeNotificationDispatch(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Results, reply.WithMeta(notificationDispatchMeta(response)))
+}
+
+// notificationDispatchMeta exposes the dispatch job behind a notify-many
+// response so admins can poll or resume it.
+func notificationDispatchMeta(response *NotifyUsersResponse) map[string]interface{} {
+	meta := map[string]interface{}{}
+	if response != nil && response.NotifyUsersResponse != nil && response.Job != nil {
+		meta["dispatch_id"] = response.Job.ID
+		meta["dispatch_status"] = response.Job.Status
+		meta["dispatch_progress"] = response.Job.Progress
+		if response.ResultsTruncated {
+			meta["results_truncated"] = true
+		}
+	}
+	return meta
+}
+
+// ListNotificationTopics handles listing the current user's notification topics.
+func (h *Handler) ListNotificationTopics(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-list-notification-topics")
+	request, err := MapRequestToListNotificationTopicsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ListNotificationTopics(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Subscriptions)
+}
+
+// SubscribeNotificationTopic handles subscribing the current user to a notification topic.
+func (h *Handler) SubscribeNotificationTopic(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-subscribe-notification-topic")
+	request, err := MapRequestToSubscribeNotificationTopicRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.SubscribeNotificationTopic(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.Subscription)
+}
+
+// UnsubscribeNotificationTopic handles unsubscribing the current user from a notification topic.
+func (h *Handler) UnsubscribeNotificationTopic(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-unsubscribe-notification-topic")
+	request, err := MapRequestToUnsubscribeNotificationTopicRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	if err := h.Service.UnsubscribeNotificationTopic(r.Context(), request); err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPBlankResponse(w, http.StatusOK)
 }
 
 // GetMyGroupInvitations handles the request to get the current user's outstanding group invitations.
@@ -797,6 +928,26 @@ func (h *Handler) AcceptMyGroupInvitation(w http.ResponseWriter, r *http.Request
 	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.AcceptInviteResponse)
 }
 
+// AcceptMyGroupInvitationByToken handles the request to accept a group invitation from an emailed link.
+func (h *Handler) AcceptMyGroupInvitationByToken(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-accept-my-group-invitation-by-token")
+	request, err := MapRequestToAcceptMyGroupInvitationByTokenRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.AcceptMyGroupInvitationByToken(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.AcceptInviteByTokenResponse)
+}
+
 // RejectMyGroupInvitation handles the request to reject one of the current user's group invitations.
 func (h *Handler) RejectMyGroupInvitation(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-reject-my-group-invitation")
@@ -817,6 +968,214 @@ func (h *Handler) RejectMyGroupInvitation(w http.ResponseWriter, r *http.Request
 	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.RejectInviteResponse)
 }
 
+// GetMyJoinRequests handles the request to get the current user's group join requests.
+func (h *Handler) GetMyJoinRequests(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-get-my-join-requests")
+	request, err := MapRequestToGetMyJoinRequestsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetMyJoinRequests(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.JoinRequests)
+}
+
+// CreateMyJoinRequest handles the request to ask to join a group as the current user.
+func (h *Handler) CreateMyJoinRequest(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-create-my-join-request")
+	request, err := MapRequestToCreateMyJoinRequestRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.CreateMyJoinRequest(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusCreated, response.RequestToJoinGroupResponse)
+}
+
+// CancelMyJoinRequest handles the request to withdraw one of the current user's group join requests.
+func (h *Handler) CancelMyJoinRequest(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-cancel-my-join-request")
+	request, err := MapRequestToCancelMyJoinRequestRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.CancelMyJoinRequest(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.JoinRequest)
+}
+
+// GetGroupJoinRequests handles the request to get a group's join requests for review.
+func (h *Handler) GetGroupJoinRequests(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-get-group-join-requests")
+	request, err := MapRequestToGetGroupJoinRequestsRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetGroupJoinRequests(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.JoinRequests)
+}
+
+// GetGroupActivity handles the request to get a group's activity feed.
+func (h *Handler) GetGroupActivity(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-get-group-activity")
+	request, err := MapRequestToGetGroupActivityRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetGroupActivity(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response)
+}
+
+// ImportGroupMembers handles the request to import members into a group from a CSV or JSON upload.
+func (h *Handler) ImportGroupMembers(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-import-group-members")
+	request, err := MapRequestToImportGroupMembersRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ImportGroupMembers(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	statusCode := http.StatusAccepted
+	if response.Import == nil {
+		statusCode = http.StatusOK
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, statusCode, response.ImportMembersResponse)
+}
+
+// GetGroupMemberImport handles the request to get the progress of a group member import.
+func (h *Handler) GetGroupMemberImport(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-get-group-member-import")
+	request, err := MapRequestToGetGroupMemberImportRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetGroupMemberImport(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Import)
+}
+
+// ExportGroupMembers handles the request to download a group's members as a CSV or JSON file.
+func (h *Handler) ExportGroupMembers(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-export-group-members")
+	request, err := MapRequestToExportGroupMembersRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ExportGroupMembers(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", response.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+response.FileName+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(response.Content)
+}
+
+// ApproveGroupJoinRequest handles the request to approve a group join request.
+func (h *Handler) ApproveGroupJoinRequest(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-approve-group-join-request")
+	request, err := MapRequestToReviewGroupJoinRequestRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.ApproveGroupJoinRequest(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.JoinRequest)
+}
+
+// DenyGroupJoinRequest handles the request to deny a group join request.
+func (h *Handler) DenyGroupJoinRequest(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-deny-group-join-request")
+	request, err := MapRequestToReviewGroupJoinRequestRequest(r, h.Validator)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.DenyGroupJoinRequest(r.Context(), request)
+	if err != nil {
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.JoinRequest)
+}
+
 // GetGroupDetail handles the request to fetch a group's details for the requester
 func (h *Handler) GetGroupDetail(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-get-group-detail")
@@ -863,6 +1222,29 @@ func (h *Handler) GetGroupStats(w http.ResponseWriter, r *http.Request) {
 	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Stats)
 }
 
+// GetGroupBilling handles the request to fetch a group's subscriptions and seats for the requester
+func (h *Handler) GetGroupBilling(w http.ResponseWriter, r *http.Request) {
+	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-get-group-billing")
+	request, err := MapRequestToGetGroupBillingRequest(r, h.Validator)
+	if err != nil {
+		//nolint will set up default fallback later
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	response, err := h.Service.GetGroupBilling(r.Context(), request)
+	if err != nil {
+		//nolint will set up default fallback later
+		logger.Warn("handler-returning-error-response", zap.Error(err))
+		h.GetBaseResponseHandler().NewHTTPErrorResponse(w, err)
+		return
+	}
+
+	//nolint will set up default fallback later
+	h.GetBaseResponseHandler().NewHTTPDataResponse(w, http.StatusOK, response.Billing)
+}
+
 // ValidateGroupName handles the request to validate a proposed group name
 func (h *Handler) ValidateGroupName(w http.ResponseWriter, r *http.Request) {
 	logger := logger.AcquireOperationFrom(r.Context(), "external/usermanager", "handle-validate-group-name")
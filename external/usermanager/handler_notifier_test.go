@@ -37,6 +37,11 @@ type mockUmsService struct {
 	getAvailableCommsTypesFunc         func(ctx context.Context) (*usermanager.GetAvailableCommsTypesResponse, error)
 	notifyUserFunc                     func(ctx context.Context, r *usermanager.NotifyUserRequest) (*usermanager.NotifyUserResponse, error)
 	notifyUsersFunc                    func(ctx context.Context, r *usermanager.NotifyUsersRequest) (*usermanager.NotifyUsersResponse, error)
+	getNotificationDispatchFunc        func(ctx context.Context, r *usermanager.GetNotificationDispatchRequest) (*usermanager.GetNotificationDispatchResponse, error)
+	resumeNotificationDispatchFunc     func(ctx context.Context, r *usermanager.ResumeNotificationDispatchRequest) (*usermanager.NotifyUsersResponse, error)
+	subscribeNotificationTopicFunc     func(ctx context.Context, r *usermanager.SubscribeNotificationTopicRequest) (*usermanager.SubscribeNotificationTopicResponse, error)
+	unsubscribeNotificationTopicFunc   func(ctx context.Context, r *usermanager.UnsubscribeNotificationTopicRequest) error
+	listNotificationTopicsFunc         func(ctx context.Context, r *usermanager.ListNotificationTopicsRequest) (*usermanager.ListNotificationTopicsResponse, error)
 }
 
 // stubErr is returned when a mockUmsService method is called without a matching *Func field.
@@ -98,6 +103,41 @@ func (m *mockUmsService) NotifyUsers(ctx context.Context, r *usermanager.NotifyU
 	return nil, stubErr
 }
 
+func (m *mockUmsService) GetNotificationDispatch(ctx context.Context, r *usermanager.GetNotificationDispatchRequest) (*usermanager.GetNotificationDispatchResponse, error) {
+	if m.getNotificationDispatchFunc != nil {
+		return m.getNotificationDispatchFunc(ctx, r)
+	}
+	return nil, stubErr
+}
+
+func (m *mockUmsService) ResumeNotificationDispatch(ctx context.Context, r *usermanager.ResumeNotificationDispatchRequest) (*usermanager.NotifyUsersResponse, error) {
+	if m.resumeNotificationDispatchFunc != nil {
+		return m.resumeNotificationDispatchFunc(ctx, r)
+	}
+	return nil, stubErr
+}
+
+func (m *mockUmsService) SubscribeNotificationTopic(ctx context.Context, r *usermanager.SubscribeNotificationTopicRequest) (*usermanager.SubscribeNotificationTopicResponse, error) {
+	if m.subscribeNotificationTopicFunc != nil {
+		return m.subscribeNotificationTopicFunc(ctx, r)
+	}
+	return nil, stubErr
+}
+
+func (m *mockUmsService) UnsubscribeNotificationTopic(ctx context.Context, r *usermanager.UnsubscribeNotificationTopicRequest) error {
+	if m.unsubscribeNotificationTopicFunc != nil {
+		return m.unsubscribeNotificationTopicFunc(ctx, r)
+	}
+	return stubErr
+}
+
+func (m *mockUmsService) ListNotificationTopics(ctx context.Context, r *usermanager.ListNotificationTopicsRequest) (*usermanager.ListNotificationTopicsResponse, error) {
+	if m.listNotificationTopicsFunc != nil {
+		return m.listNotificationTopicsFunc(ctx, r)
+	}
+	return nil, stubErr
+}
+
 // remaining UsermanagerService methods — not used by notifier tests
 func (m *mockUmsService) GetUserMicroProfile(ctx context.Context, r *usermanager.GetUserMicroProfileRequest) (*usermanager.GetUserMicroProfileResponse, error) {
 	return nil, stubErr
@@ -156,15 +196,51 @@ func (m *mockUmsService) GetMyGroupInvitations(ctx context.Context, r *usermanag
 func (m *mockUmsService) AcceptMyGroupInvitation(ctx context.Context, r *usermanager.AcceptMyGroupInvitationRequest) (*usermanager.AcceptMyGroupInvitationResponse, error) {
 	return nil, stubErr
 }
+func (m *mockUmsService) AcceptMyGroupInvitationByToken(ctx context.Context, r *usermanager.AcceptMyGroupInvitationByTokenRequest) (*usermanager.AcceptMyGroupInvitationByTokenResponse, error) {
+	return nil, stubErr
+}
 func (m *mockUmsService) RejectMyGroupInvitation(ctx context.Context, r *usermanager.RejectMyGroupInvitationRequest) (*usermanager.RejectMyGroupInvitationResponse, error) {
 	return nil, stubErr
 }
+func (m *mockUmsService) GetMyJoinRequests(ctx context.Context, r *usermanager.GetMyJoinRequestsRequest) (*usermanager.GetMyJoinRequestsResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) CreateMyJoinRequest(ctx context.Context, r *usermanager.CreateMyJoinRequestRequest) (*usermanager.CreateMyJoinRequestResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) CancelMyJoinRequest(ctx context.Context, r *usermanager.CancelMyJoinRequestRequest) (*usermanager.CancelMyJoinRequestResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) GetGroupJoinRequests(ctx context.Context, r *usermanager.GetGroupJoinRequestsRequest) (*usermanager.GetGroupJoinRequestsResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) GetGroupActivity(ctx context.Context, r *usermanager.GetGroupActivityRequest) (*usermanager.GetGroupActivityResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) ImportGroupMembers(ctx context.Context, r *usermanager.ImportGroupMembersRequest) (*usermanager.ImportGroupMembersResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) GetGroupMemberImport(ctx context.Context, r *usermanager.GetGroupMemberImportRequest) (*usermanager.GetGroupMemberImportResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) ExportGroupMembers(ctx context.Context, r *usermanager.ExportGroupMembersRequest) (*usermanager.ExportGroupMembersResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) ApproveGroupJoinRequest(ctx context.Context, r *usermanager.ReviewGroupJoinRequestRequest) (*usermanager.ReviewGroupJoinRequestResponse, error) {
+	return nil, stubErr
+}
+func (m *mockUmsService) DenyGroupJoinRequest(ctx context.Context, r *usermanager.ReviewGroupJoinRequestRequest) (*usermanager.ReviewGroupJoinRequestResponse, error) {
+	return nil, stubErr
+}
 func (m *mockUmsService) GetGroupDetail(ctx context.Context, r *usermanager.GetGroupDetailRequest) (*usermanager.GetGroupDetailResponse, error) {
 	return nil, stubErr
 }
 func (m *mockUmsService) GetGroupStats(ctx context.Context, r *usermanager.GetGroupStatsRequest) (*usermanager.GetGroupStatsResponse, error) {
 	return nil, stubErr
 }
+func (m *mockUmsService) GetGroupBilling(ctx context.Context, r *usermanager.GetGroupBillingRequest) (*usermanager.GetGroupBillingResponse, error) {
+	return nil, stubErr
+}
 func (m *mockUmsService) CreateGroup(ctx context.Context, r *usermanager.CreateGroupRequest) (*usermanager.CreateGroupResponse, error) {
 	return nil, stubErr
 }
@@ -1323,3 +1399,105 @@ func TestHandler_NotifyUsers_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	assert.Equal(t, "NTF00-002", responseErrorCode(t, rec))
 }
+
+// ---------------------------------------------------------------------------
+// Notification topics and dispatch jobs
+// ---------------------------------------------------------------------------
+
+func TestHandler_SubscribeNotificationTopic_UsesAuthenticatedUser(t *testing.T) {
+	t.Parallel()
+
+	svc := &mockUmsService{
+		subscribeNotificationTopicFunc: func(ctx context.Context, r *usermanager.SubscribeNotificationTopicRequest) (*usermanager.SubscribeNotificationTopicResponse, error) {
+			require.Equal(t, "user-1", r.UserId)
+			require.Equal(t, "user-1", r.SubscribeToTopicRequest.UserID)
+			require.Equal(t, "release-notes", r.SubscribeToTopicRequest.Topic)
+			return &usermanager.SubscribeNotificationTopicResponse{
+				SubscribeToTopicResponse: &notifier.SubscribeToTopicResponse{
+					Subscription: &notifier.NotificationTopicSubscription{ID: "sub-1", UserID: "user-1", Topic: "release-notes"},
+				},
+			}, nil
+		},
+	}
+
+	h := newTestHandler(svc)
+	req := authenticatedRequest(http.MethodPost, "/api/v1/ums/me/notifications/topics", []byte(`{"topic":"release-notes","user_id":"someone-else"}`), "user-1")
+	rec := httptest.NewRecorder()
+
+	require.NotPanics(t, func() { h.SubscribeNotificationTopic(rec, req) })
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var subscription notifier.NotificationTopicSubscription
+	responseData(t, rec, &subscription)
+	assert.Equal(t, "release-notes", subscription.Topic)
+}
+
+func TestHandler_UnsubscribeNotificationTopic_Success(t *testing.T) {
+	t.Parallel()
+
+	svc := &mockUmsService{
+		unsubscribeNotificationTopicFunc: func(ctx context.Context, r *usermanager.UnsubscribeNotificationTopicRequest) error {
+			require.Equal(t, "user-1", r.UnsubscribeFromTopicRequest.UserID)
+			require.Equal(t, "group:g-1", r.UnsubscribeFromTopicRequest.Topic)
+			return nil
+		},
+	}
+
+	h := newTestHandler(svc)
+	req := authenticatedRequest(http.MethodDelete, "/api/v1/ums/me/notifications/topics/group:g-1", nil, "user-1")
+	req = mux.SetURLVars(req, map[string]string{usermanager.UserManagerURIVariableNotificationTopic: "group:g-1"})
+	rec := httptest.NewRecorder()
+
+	require.NotPanics(t, func() { h.UnsubscribeNotificationTopic(rec, req) })
+	assert.Equal(t, http.StatusOK, rec.Code)
+	responseBlank(t, rec)
+}
+
+func TestHandler_NotifyUsers_IncludesDispatchMeta(t *testing.T) {
+	t.Parallel()
+
+	svc := &mockUmsService{
+		notifyUsersFunc: func(ctx context.Context, r *usermanager.NotifyUsersRequest) (*usermanager.NotifyUsersResponse, error) {
+			require.Equal(t, "release-notes", r.NotifyUsersRequest.Topic)
+			return &usermanager.NotifyUsersResponse{
+				NotifyUsersResponse: &notifier.NotifyUsersResponse{
+					Results: []notifier.NotifyUsersResult{},
+					Job:     &notifier.NotificationDispatchJob{ID: "job-1", Status: notifier.NotificationDispatchJobStatusCompleted},
+				},
+			}, nil
+		},
+	}
+
+	h := newTestHandler(svc)
+	req := authenticatedRequest(http.MethodPost, "/api/v1/ums/notifications", []byte(`{"topic":"release-notes","title":"Hi","message":"There"}`), "admin-id")
+	rec := httptest.NewRecorder()
+
+	require.NotPanics(t, func() { h.NotifyUsers(rec, req) })
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "job-1", body.Meta["dispatch_id"])
+	assert.Equal(t, "COMPLETED", body.Meta["dispatch_status"])
+}
+
+func TestHandler_ResumeNotificationDispatch_AlreadyCompleted(t *testing.T) {
+	t.Parallel()
+
+	svc := &mockUmsService{
+		resumeNotificationDispatchFunc: func(ctx context.Context, r *usermanager.ResumeNotificationDispatchRequest) (*usermanager.NotifyUsersResponse, error) {
+			require.Equal(t, "job-1", r.ResumeDispatchJobRequest.JobID)
+			return nil, notifier.ErrDispatchJobAlreadyCompleted
+		},
+	}
+
+	h := newTestHandler(svc)
+	req := authenticatedRequest(http.MethodPost, "/api/v1/ums/notifications/dispatches/job-1/resume", nil, "admin-id")
+	req = mux.SetURLVars(req, map[string]string{usermanager.UserManagerURIVariableDispatchID: "job-1"})
+	rec := httptest.NewRecorder()
+
+	require.NotPanics(t, func() { h.ResumeNotificationDispatch(rec, req) })
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
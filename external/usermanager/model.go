@@ -3,6 +3,7 @@ package usermanager
 import (
 	"time"
 
+	"github.com/ooaklee/ghatd/external/billing"
 	"github.com/ooaklee/ghatd/external/group"
 )
 
@@ -86,6 +87,42 @@ type GroupStats struct {
 	Meta       GroupStatsMeta `json:"meta"`
 }
 
+// GroupBillingSeats holds the seats purchased by a group and how many are in use
+type GroupBillingSeats struct {
+	// Purchased is the total seats across the group's active subscriptions
+	Purchased int `json:"purchased"`
+
+	// Used is the number of unique users counted against the group's seats
+	Used int `json:"used"`
+
+	// Available is the number of purchased seats not yet in use
+	Available int `json:"available"`
+
+	// Limit is the member limit currently enforced on the group, 0 if unlimited
+	Limit int `json:"limit"`
+}
+
+// GroupBilling represents the subscriptions owned by a group and its seat usage.
+// Subscriptions are only included for requesters who can manage the group's billing
+type GroupBilling struct {
+	GroupID          string                 `json:"group_id"`
+	Seats            GroupBillingSeats      `json:"seats"`
+	CanManageBilling bool                   `json:"can_manage_billing"`
+	Subscriptions    []billing.Subscription `json:"subscriptions,omitempty"`
+}
+
+// GroupActivityEntry represents a group activity enriched with the profiles of
+// the users who carried it out and were acted on
+type GroupActivityEntry struct {
+	group.GroupActivity
+
+	// Actor is the user who carried out the activity
+	Actor *EnrichedMember `json:"actor,omitempty"`
+
+	// Subject is the member, invitee or requester the activity is about
+	Subject *EnrichedMember `json:"subject,omitempty"`
+}
+
 // UserGroupMembership represents a user's membership in a specific group
 type UserGroupMembership struct {
 	*GroupSummary
@@ -309,6 +309,57 @@ type NotifyUsersRequest struct {
 	*notifier.NotifyUsersRequest
 }
 
+// GetNotificationDispatchRequest holds the data needed to fetch a notification
+// dispatch job.
+type GetNotificationDispatchRequest struct {
+	// UserId is the authenticated requester/actor ID.
+	UserId string
+
+	// GetDispatchJobRequest carries the dispatch job ID from the URL path.
+	*notifier.GetDispatchJobRequest
+}
+
+// ResumeNotificationDispatchRequest holds the data needed to resume an
+// interrupted notification dispatch job.
+type ResumeNotificationDispatchRequest struct {
+	// UserId is the authenticated requester/actor ID.
+	UserId string
+
+	// ResumeDispatchJobRequest carries the dispatch job ID from the URL path.
+	*notifier.ResumeDispatchJobRequest
+}
+
+// SubscribeNotificationTopicRequest holds the data needed to subscribe the
+// current user to a notification topic.
+type SubscribeNotificationTopicRequest struct {
+	// UserId is the ID of the requester. The subscriber is always the
+	// requester, so SubscribeToTopicRequest.UserID is set to the same value.
+	UserId string
+
+	// SubscribeToTopicRequest carries the topic being subscribed to.
+	*notifier.SubscribeToTopicRequest
+}
+
+// UnsubscribeNotificationTopicRequest holds the data needed to unsubscribe
+// the current user from a notification topic.
+type UnsubscribeNotificationTopicRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// UnsubscribeFromTopicRequest carries the topic from the URL path.
+	*notifier.UnsubscribeFromTopicRequest
+}
+
+// ListNotificationTopicsRequest holds the data needed to list the current
+// user's notification topic subscriptions.
+type ListNotificationTopicsRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// ListTopicSubscriptionsRequest carries the subscriber ID.
+	*notifier.ListTopicSubscriptionsRequest
+}
+
 // GetMyGroupInvitationsRequest holds the data needed to fetch the current user's group invitations.
 type GetMyGroupInvitationsRequest struct {
 	// UserId is the ID of the requester.
@@ -326,6 +377,14 @@ type AcceptMyGroupInvitationRequest struct {
 	GroupID string `path:"groupID"`
 }
 
+// AcceptMyGroupInvitationByTokenRequest holds the data needed to accept a group invitation from an emailed link.
+type AcceptMyGroupInvitationByTokenRequest struct {
+	// UserId is the ID of the requester.
+	UserId string `json:"-"`
+	// Token is the signed invitation token from the link.
+	Token string `json:"token" validate:"required"`
+}
+
 // RejectMyGroupInvitationRequest holds the data needed to reject one of the current user's group invitations.
 type RejectMyGroupInvitationRequest struct {
 	// UserId is the ID of the requester.
@@ -334,6 +393,114 @@ type RejectMyGroupInvitationRequest struct {
 	GroupID string `path:"groupID"`
 }
 
+// GetMyJoinRequestsRequest holds the data needed to fetch the current user's group join requests.
+type GetMyJoinRequestsRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// Status optionally filters join requests by status.
+	Status string `query:"status"`
+}
+
+// CreateMyJoinRequestRequest holds the data needed for the current user to ask to join a group.
+type CreateMyJoinRequestRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// RequestToJoinGroupRequest carries the underlying join request payload.
+	*group.RequestToJoinGroupRequest
+}
+
+// CancelMyJoinRequestRequest holds the data needed to withdraw one of the current user's join requests.
+type CancelMyJoinRequestRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+	// JoinRequestID is the ID of the join request to withdraw.
+	JoinRequestID string `path:"joinRequestID"`
+}
+
+// GetGroupJoinRequestsRequest holds the data needed to fetch a group's join requests for review.
+type GetGroupJoinRequestsRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// GroupID is the ID of the group to fetch join requests for.
+	GroupID string
+
+	// Status optionally filters join requests by status.
+	Status string `query:"status"`
+}
+
+// GetGroupActivityRequest holds the data needed to fetch a group's activity feed.
+type GetGroupActivityRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// GroupID is the ID of the group to fetch activity for.
+	GroupID string
+
+	// IncludeDescendants also returns activity recorded against the group's descendants.
+	IncludeDescendants bool `query:"include_descendants"`
+
+	// ActorID optionally filters activity by the user who carried it out.
+	ActorID string `query:"actor_id"`
+
+	// Actions optionally filters activity by a comma-separated list of actions.
+	Actions string `query:"actions"`
+
+	// From and To optionally bound when the activity occurred, as RFC 3339 timestamps.
+	From string `query:"from"`
+	To   string `query:"to"`
+
+	// Cursor is the next_cursor of the previous page.
+	Cursor string `query:"cursor"`
+
+	// Limit is the page size, it defaults to 50 and is capped at 100.
+	Limit int `query:"limit"`
+}
+
+// ImportGroupMembersRequest holds the data needed to import members into a group from an upload.
+type ImportGroupMembersRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// ImportMembersRequest carries the parsed upload and whether it is a dry run.
+	*group.ImportMembersRequest
+}
+
+// GetGroupMemberImportRequest holds the data needed to fetch the progress of a group member import.
+type GetGroupMemberImportRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// GroupID is the ID of the group the members are imported into.
+	GroupID string
+
+	// ImportID is the ID of the import.
+	ImportID string
+}
+
+// ExportGroupMembersRequest holds the data needed to export a group's members.
+type ExportGroupMembersRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// GroupID is the ID of the group to export members from.
+	GroupID string
+
+	// Format is the export file format, csv or json, it defaults to csv.
+	Format string `query:"format"`
+}
+
+// ReviewGroupJoinRequestRequest holds the data needed to approve or deny a group join request.
+type ReviewGroupJoinRequestRequest struct {
+	// UserId is the ID of the requester.
+	UserId string
+
+	// ReviewJoinRequestRequest carries the underlying review payload.
+	*group.ReviewJoinRequestRequest
+}
+
 // UpdateGroupRequest holds the data needed to update a group
 type UpdateGroupRequest struct {
 
@@ -400,6 +567,16 @@ type GetGroupStatsRequest struct {
 	PrefixName bool `query:"prefix_name"`
 }
 
+// GetGroupBillingRequest holds the data needed to fetch a group's subscriptions and seats
+type GetGroupBillingRequest struct {
+
+	// UserId is the ID of the requester
+	UserId string
+
+	// GroupID is the ID of the group to fetch billing for
+	GroupID string
+}
+
 // GetGroupsConfigRequest holds the data needed to retrieve the group service config
 type GetGroupsConfigRequest struct {
 
@@ -147,6 +147,21 @@ type NotifyUsersResponse struct {
 	*notifier.NotifyUsersResponse
 }
 
+// GetNotificationDispatchResponse holds a notification dispatch job.
+type GetNotificationDispatchResponse struct {
+	*notifier.GetDispatchJobResponse
+}
+
+// SubscribeNotificationTopicResponse holds the created or existing topic subscription.
+type SubscribeNotificationTopicResponse struct {
+	*notifier.SubscribeToTopicResponse
+}
+
+// ListNotificationTopicsResponse holds the current user's topic subscriptions.
+type ListNotificationTopicsResponse struct {
+	*notifier.ListTopicSubscriptionsResponse
+}
+
 // PendingGroupInvitation holds the response for a pending group invitation
 type PendingGroupInvitation struct {
 
@@ -196,11 +211,78 @@ type AcceptMyGroupInvitationResponse struct {
 	*group.AcceptInviteResponse
 }
 
+// AcceptMyGroupInvitationByTokenResponse holds the response for accepting a group invitation from an emailed link
+type AcceptMyGroupInvitationByTokenResponse struct {
+	*group.AcceptInviteByTokenResponse
+}
+
 // RejectMyGroupInvitationResponse holds the response for rejecting a group invitation
 type RejectMyGroupInvitationResponse struct {
 	*group.RejectInviteResponse
 }
 
+// GetMyJoinRequestsResponse holds the response for fetching a user's group join requests
+type GetMyJoinRequestsResponse struct {
+	*group.GetJoinRequestsResponse
+}
+
+// CreateMyJoinRequestResponse holds the response for asking to join a group
+type CreateMyJoinRequestResponse struct {
+	*group.RequestToJoinGroupResponse
+}
+
+// CancelMyJoinRequestResponse holds the response for withdrawing a group join request
+type CancelMyJoinRequestResponse struct {
+	*group.CancelJoinRequestResponse
+}
+
+// GetGroupJoinRequestsResponse holds the response for fetching a group's join requests
+type GetGroupJoinRequestsResponse struct {
+	*group.GetJoinRequestsResponse
+}
+
+// GetGroupActivityResponse holds the response for fetching a group's activity feed
+type GetGroupActivityResponse struct {
+	Activities []GroupActivityEntry `json:"activities"`
+
+	// NextCursor fetches the next page of older activity, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ImportGroupMembersResponse holds the response for importing members into a group
+type ImportGroupMembersResponse struct {
+	*group.ImportMembersResponse
+}
+
+// GetGroupMemberImportResponse holds the response for fetching the progress of a group member import
+type GetGroupMemberImportResponse struct {
+	*group.GetMemberImportResponse
+}
+
+// ExportGroupMembersResponse holds an exported group member file
+type ExportGroupMembersResponse struct {
+	Content     []byte
+	ContentType string
+	FileName    string
+}
+
+// GroupMemberExport is a group member as written to a member export
+type GroupMemberExport struct {
+	ID              string `json:"id"`
+	Email           string `json:"email,omitempty"`
+	FullName        string `json:"full_name,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Role            string `json:"role,omitempty"`
+	JoinedAt        string `json:"joined_at,omitempty"`
+	InvitedAt       string `json:"invited_at,omitempty"`
+	InvitationState string `json:"invitation_state,omitempty"`
+}
+
+// ReviewGroupJoinRequestResponse holds the response for approving or denying a group join request
+type ReviewGroupJoinRequestResponse struct {
+	*group.ReviewJoinRequestResponse
+}
+
 // GetMetaData returns formatted metadata for pagination
 func (r *GetUserGroupsResponse) GetMetaData() map[string]interface{} {
 	if r.Meta != nil {
@@ -236,6 +318,11 @@ type GetGroupStatsResponse struct {
 	Stats GroupStats `json:"stats"`
 }
 
+// GetGroupBillingResponse holds the response for a group's billing
+type GetGroupBillingResponse struct {
+	Billing GroupBilling `json:"billing"`
+}
+
 // GetGroupsConfigResponse holds the response for the groups service config
 type GetGroupsConfigResponse struct {
 	*group.GetGroupsConfigResponse
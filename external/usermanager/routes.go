@@ -33,11 +33,28 @@ type UsermanagerHandler interface {
 	UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request)
 	NotifyUser(w http.ResponseWriter, r *http.Request)
 	NotifyUsers(w http.ResponseWriter, r *http.Request)
+	GetNotificationDispatch(w http.ResponseWriter, r *http.Request)
+	ResumeNotificationDispatch(w http.ResponseWriter, r *http.Request)
+	SubscribeNotificationTopic(w http.ResponseWriter, r *http.Request)
+	UnsubscribeNotificationTopic(w http.ResponseWriter, r *http.Request)
+	ListNotificationTopics(w http.ResponseWriter, r *http.Request)
 	GetMyGroupInvitations(w http.ResponseWriter, r *http.Request)
 	AcceptMyGroupInvitation(w http.ResponseWriter, r *http.Request)
+	AcceptMyGroupInvitationByToken(w http.ResponseWriter, r *http.Request)
 	RejectMyGroupInvitation(w http.ResponseWriter, r *http.Request)
+	GetMyJoinRequests(w http.ResponseWriter, r *http.Request)
+	CreateMyJoinRequest(w http.ResponseWriter, r *http.Request)
+	CancelMyJoinRequest(w http.ResponseWriter, r *http.Request)
+	GetGroupJoinRequests(w http.ResponseWriter, r *http.Request)
+	GetGroupActivity(w http.ResponseWriter, r *http.Request)
+	ImportGroupMembers(w http.ResponseWriter, r *http.Request)
+	GetGroupMemberImport(w http.ResponseWriter, r *http.Request)
+	ExportGroupMembers(w http.ResponseWriter, r *http.Request)
+	ApproveGroupJoinRequest(w http.ResponseWriter, r *http.Request)
+	DenyGroupJoinRequest(w http.ResponseWriter, r *http.Request)
 	GetGroupDetail(w http.ResponseWriter, r *http.Request)
 	GetGroupStats(w http.ResponseWriter, r *http.Request)
+	GetGroupBilling(w http.ResponseWriter, r *http.Request)
 	CreateGroup(w http.ResponseWriter, r *http.Request)
 	UpdateGroup(w http.ResponseWriter, r *http.Request)
 	DeleteGroup(w http.ResponseWriter, r *http.Request)
@@ -123,6 +140,10 @@ type AttachRoutesRequest struct {
 	// return of soft-4XX (401) status, which was stopping Google from indexing
 	// pages on their search engine
 	CustomMeEndpointValidApiTokenOrJWTMiddleware mux.MiddlewareFunc
+
+	// TenantMiddleware is optional middleware that resolves and verifies the group
+	// reminder and streak requests act for, such as tenancy.Middleware.ResolveTenant
+	TenantMiddleware mux.MiddlewareFunc
 }
 
 // AttachRoutes attaches usermanager handler to corresponding
@@ -164,19 +185,11 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/memberships", request.Handler.GetUserGroupMembershipsRequest).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/groups", request.Handler.GetUserGroups).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/invitations", request.Handler.GetMyGroupInvitations).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/me/invitations/accept-token", request.Handler.AcceptMyGroupInvitationByToken).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/invitations/{groupID}/accept", request.Handler.AcceptMyGroupInvitation).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/invitations/{groupID}/reject", request.Handler.RejectMyGroupInvitation).Methods(http.MethodPost, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/reminders", request.Handler.ListReminders).Methods(http.MethodGet, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/reminders", request.Handler.CreateReminder).Methods(http.MethodPost, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/reminders/{reminderID}", request.Handler.GetReminderByID).Methods(http.MethodGet, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/reminders/{reminderID}", request.Handler.UpdateReminderByID).Methods(http.MethodPatch, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/reminders/{reminderID}", request.Handler.DeleteReminderByID).Methods(http.MethodDelete, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/reminders/{reminderID}/disable", request.Handler.DisableReminderByID).Methods(http.MethodPost, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/streaks", request.Handler.ListStreaks).Methods(http.MethodGet, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/streaks/record", request.Handler.RecordStreak).Methods(http.MethodPost, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/streaks/current", request.Handler.GetCurrentStreak).Methods(http.MethodGet, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/streaks/longest", request.Handler.GetLongestStreak).Methods(http.MethodGet, http.MethodOptions)
-	usermanagerAuthenticatedRoutes.HandleFunc("/me/streaks/count", request.Handler.GetNumberOfStreaks).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/me/join-requests", request.Handler.GetMyJoinRequests).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/me/join-requests/{joinRequestID}/cancel", request.Handler.CancelMyJoinRequest).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/latest", request.Handler.GetLatestNotificationOverviews).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/config", request.Handler.GetNotifierConfig).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/addresses", request.Handler.ListNotificationAddresses).Methods(http.MethodGet, http.MethodOptions)
@@ -184,6 +197,9 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/addresses/{addressID}", request.Handler.DeleteNotificationAddress).Methods(http.MethodDelete, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/preferences", request.Handler.GetNotificationPreferences).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/preferences", request.Handler.UpdateNotificationPreferences).Methods(http.MethodPatch, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/topics", request.Handler.ListNotificationTopics).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/topics", request.Handler.SubscribeNotificationTopic).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/me/notifications/topics/{topic}", request.Handler.UnsubscribeNotificationTopic).Methods(http.MethodDelete, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/users", request.Handler.GetUsers).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/users/{userId}", request.Handler.GetUserByID).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/users/{userId}/groups", request.Handler.GetGroupsByUserID).Methods(http.MethodGet, http.MethodOptions)
@@ -191,7 +207,13 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}", request.Handler.GetGroupDetail).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/lineage", request.Handler.GetGroupLineage).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/stats", request.Handler.GetGroupStats).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/billing", request.Handler.GetGroupBilling).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/descendants", request.Handler.GetGroupDescendants).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/join-requests", request.Handler.GetGroupJoinRequests).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/activity", request.Handler.GetGroupActivity).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/members/import", request.Handler.ImportGroupMembers).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/members/import/{importID}", request.Handler.GetGroupMemberImport).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAuthenticatedRoutes.HandleFunc("/groups/{groupID}/members/export", request.Handler.ExportGroupMembers).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/visions", request.Handler.CreateVision).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/visions/{visionNanoID}", request.Handler.UpdateVision).Methods(http.MethodPatch, http.MethodOptions)
 	usermanagerAuthenticatedRoutes.HandleFunc("/visions/{visionNanoID}", request.Handler.DeleteVision).Methods(http.MethodDelete, http.MethodOptions)
@@ -204,6 +226,27 @@ func AttachRoutes(request *AttachRoutesRequest) {
 		usermanagerAuthenticatedRoutes.Use(request.ValidApiTokenOrJWTMiddleware)
 	}
 
+	// Reminders and streaks can be group-owned, so their routes run the tenant
+	// middleware after authentication to act for the group the request names
+	usermanagerTenantRoutes := httpRouter.PathPrefix(APIUserManagerV1Prefix).Subrouter()
+	usermanagerTenantRoutes.HandleFunc("/me/reminders", request.Handler.ListReminders).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/reminders", request.Handler.CreateReminder).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/reminders/{reminderID}", request.Handler.GetReminderByID).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/reminders/{reminderID}", request.Handler.UpdateReminderByID).Methods(http.MethodPatch, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/reminders/{reminderID}", request.Handler.DeleteReminderByID).Methods(http.MethodDelete, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/reminders/{reminderID}/disable", request.Handler.DisableReminderByID).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/streaks", request.Handler.ListStreaks).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/streaks/record", request.Handler.RecordStreak).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/streaks/current", request.Handler.GetCurrentStreak).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/streaks/longest", request.Handler.GetLongestStreak).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerTenantRoutes.HandleFunc("/me/streaks/count", request.Handler.GetNumberOfStreaks).Methods(http.MethodGet, http.MethodOptions)
+	if request.ValidApiTokenOrJWTMiddleware != nil {
+		usermanagerTenantRoutes.Use(request.ValidApiTokenOrJWTMiddleware)
+	}
+	if request.TenantMiddleware != nil {
+		usermanagerTenantRoutes.Use(request.TenantMiddleware)
+	}
+
 	usermanagerAdminRoutes := httpRouter.PathPrefix(APIUserManagerV1Prefix).Subrouter()
 	usermanagerAdminRoutes.HandleFunc("/comms", request.Handler.GetComms).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAdminRoutes.HandleFunc("/comms/stats", request.Handler.GetCommsStats).Methods(http.MethodGet, http.MethodOptions)
@@ -225,6 +268,8 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	usermanagerAdminServiceRoutes := httpRouter.PathPrefix(APIUserManagerV1Prefix).Subrouter()
 	usermanagerAdminServiceRoutes.HandleFunc("/users/{userId}/notifications", request.Handler.NotifyUser).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerAdminServiceRoutes.HandleFunc("/notifications", request.Handler.NotifyUsers).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerAdminServiceRoutes.HandleFunc("/notifications/dispatches/{dispatchID}", request.Handler.GetNotificationDispatch).Methods(http.MethodGet, http.MethodOptions)
+	usermanagerAdminServiceRoutes.HandleFunc("/notifications/dispatches/{dispatchID}/resume", request.Handler.ResumeNotificationDispatch).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerAdminServiceRoutes.HandleFunc("/reminders", request.Handler.ListReminders).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAdminServiceRoutes.HandleFunc("/reminders/stats", request.Handler.GetReminderStats).Methods(http.MethodGet, http.MethodOptions)
 	usermanagerAdminServiceRoutes.HandleFunc("/reminders/due", request.Handler.GetDueReminders).Methods(http.MethodGet, http.MethodOptions)
@@ -246,6 +291,9 @@ func AttachRoutes(request *AttachRoutesRequest) {
 	usermanagerActiveOnlyRoutes.HandleFunc("/groups/{groupID}/members", request.Handler.AddGroupMember).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerActiveOnlyRoutes.HandleFunc("/groups/{groupID}/members/{memberID}", request.Handler.RemoveGroupMember).Methods(http.MethodDelete, http.MethodOptions)
 	usermanagerActiveOnlyRoutes.HandleFunc("/groups/{groupID}/members/{memberID}", request.Handler.UpdateGroupMember).Methods(http.MethodPatch, http.MethodOptions)
+	usermanagerActiveOnlyRoutes.HandleFunc("/groups/{groupID}/join-requests/{joinRequestID}/approve", request.Handler.ApproveGroupJoinRequest).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerActiveOnlyRoutes.HandleFunc("/groups/{groupID}/join-requests/{joinRequestID}/deny", request.Handler.DenyGroupJoinRequest).Methods(http.MethodPost, http.MethodOptions)
+	usermanagerActiveOnlyRoutes.HandleFunc("/me/join-requests", request.Handler.CreateMyJoinRequest).Methods(http.MethodPost, http.MethodOptions)
 	usermanagerActiveOnlyRoutes.HandleFunc("/me", request.Handler.UpdateUserProfile).Methods(http.MethodPatch, http.MethodOptions)
 	if request.ActiveValidApiTokenOrJWTMiddleware != nil {
 		usermanagerActiveOnlyRoutes.Use(request.ActiveValidApiTokenOrJWTMiddleware)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ooaklee/ghatd/external/apitoken"
 	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/billing"
 	"github.com/ooaklee/ghatd/external/common"
 	"github.com/ooaklee/ghatd/external/contacter"
 	"github.com/ooaklee/ghatd/external/group"
@@ -67,6 +68,7 @@ type GroupService interface {
 	GetGroupsByUserID(ctx context.Context, req *group.GetGroupsByUserIDRequest) (*group.GetGroupsByUserIDResponse, error)
 	GetGroupsAwaitingAnswerForInvitationsByMemberID(ctx context.Context, req *group.GetGroupsAwaitingAnswerForInvitationsByMemberIDRequest) (*group.GetGroupsAwaitingAnswerForInvitationsByMemberIDResponse, error)
 	GetUserGroupAccessMap(ctx context.Context, userID string) (map[string]group.UserGroupAccessSummary, error)
+	Can(ctx context.Context, userID, groupID, permission string) (bool, error)
 	GetGroupsConfig(ctx context.Context, req *group.GetGroupsConfigRequest) (*group.GetGroupsConfigResponse, error)
 	GetGroupLineage(ctx context.Context, req *group.GetGroupLineageRequest) (*group.GetGroupLineageResponse, error)
 	DeleteGroup(ctx context.Context, req *group.DeleteGroupRequest) (*group.DeleteGroupResponse, error)
@@ -74,7 +76,21 @@ type GroupService interface {
 	ValidateGroupName(ctx context.Context, req *group.ValidateGroupNameRequest) (*group.ValidateGroupNameResponse, error)
 	GetLatestNotificationOverviews(ctx context.Context, req *common.GetLatestNotificationOverviewsRequest) (*common.GetLatestNotificationOverviewsResponse, error)
 	AcceptInvite(ctx context.Context, req *group.AcceptInviteRequest) (*group.AcceptInviteResponse, error)
+	AcceptInviteByToken(ctx context.Context, req *group.AcceptInviteByTokenRequest) (*group.AcceptInviteByTokenResponse, error)
 	RejectInvite(ctx context.Context, req *group.RejectInviteRequest) (*group.RejectInviteResponse, error)
+	RequestToJoinGroup(ctx context.Context, req *group.RequestToJoinGroupRequest) (*group.RequestToJoinGroupResponse, error)
+	GetJoinRequests(ctx context.Context, req *group.GetJoinRequestsRequest) (*group.GetJoinRequestsResponse, error)
+	GetGroupActivity(ctx context.Context, req *group.GetGroupActivityRequest) (*group.GetGroupActivityResponse, error)
+	ImportMembers(ctx context.Context, req *group.ImportMembersRequest) (*group.ImportMembersResponse, error)
+	GetMemberImport(ctx context.Context, req *group.GetMemberImportRequest) (*group.GetMemberImportResponse, error)
+	ApproveJoinRequest(ctx context.Context, req *group.ReviewJoinRequestRequest) (*group.ReviewJoinRequestResponse, error)
+	DenyJoinRequest(ctx context.Context, req *group.ReviewJoinRequestRequest) (*group.ReviewJoinRequestResponse, error)
+	CancelJoinRequest(ctx context.Context, req *group.CancelJoinRequestRequest) (*group.CancelJoinRequestResponse, error)
+}
+
+// BillingService expected methods of a valid billing service.
+type BillingService interface {
+	GetSubscriptions(ctx context.Context, req *billing.GetSubscriptionsRequest) (*billing.GetSubscriptionsResponse, error)
 }
 
 // ReminderService expected methods of a valid reminder service.
@@ -128,6 +144,11 @@ type NotifierService interface {
 	GetConfig(ctx context.Context, r *notifier.GetNotifierConfigRequest) (*notifier.GetNotifierConfigResponse, error)
 	NotifyUser(ctx context.Context, r *notifier.NotifyUserRequest) (*notifier.NotifyUserResponse, error)
 	NotifyUsers(ctx context.Context, r *notifier.NotifyUsersRequest) (*notifier.NotifyUsersResponse, error)
+	ResumeDispatchJob(ctx context.Context, r *notifier.ResumeDispatchJobRequest) (*notifier.NotifyUsersResponse, error)
+	GetDispatchJob(ctx context.Context, r *notifier.GetDispatchJobRequest) (*notifier.GetDispatchJobResponse, error)
+	SubscribeToTopic(ctx context.Context, r *notifier.SubscribeToTopicRequest) (*notifier.SubscribeToTopicResponse, error)
+	UnsubscribeFromTopic(ctx context.Context, r *notifier.UnsubscribeFromTopicRequest) error
+	ListTopicSubscriptions(ctx context.Context, r *notifier.ListTopicSubscriptionsRequest) (*notifier.ListTopicSubscriptionsResponse, error)
 }
 
 // Service holds and manages usermanager business logic
@@ -135,6 +156,7 @@ type Service struct {
 	UserService      UserService
 	ApiTokenService  ApiTokenService
 	AuditService     AuditService
+	BillingService   BillingService
 	ContacterService ContacterService
 	GroupService     GroupService
 	NotifierService  NotifierService
@@ -175,6 +197,12 @@ func (s *Service) WithGroupService(groupSvc GroupService) *Service {
 	return s
 }
 
+// WithBillingService adds billing integration for group-owned subscriptions.
+func (s *Service) WithBillingService(billingSvc BillingService) *Service {
+	s.BillingService = billingSvc
+	return s
+}
+
 // WithReminderService adds reminder service integration.
 func (s *Service) WithReminderService(reminderSvc ReminderService) *Service {
 	s.ReminderService = reminderSvc
@@ -205,9 +233,10 @@ func (s *Service) UpdateUserProfile(ctx context.Context, r *UpdateUserProfileReq
 	logger.Debug("handling-update-user-profile-request")
 
 	serviceResponse, err := s.UserService.UpdateUser(ctx, &userv2.UpdateUserRequest{
-		ID:        r.UserId,
-		FirstName: r.FirstName,
-		LastName:  r.LastName,
+		ID:             r.UserId,
+		FirstName:      r.FirstName,
+		LastName:       r.LastName,
+		BillingAddress: r.BillingAddress,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,111 @@
+package usermanager
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// GetGroupActivity returns a group's activity feed for platform admins and members holding
+// activity.view, with the profiles of the users who carried out and were acted on by each activity.
+func (s *Service) GetGroupActivity(ctx context.Context, r *GetGroupActivityRequest) (*GetGroupActivityResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	if !s.isRequesterAdmin(ctx, r.UserId, logger) {
+		canViewActivity, accessErr := s.hasRequesterGroupPermission(ctx, r.UserId, r.GroupID, group.PermissionActivityView)
+		if accessErr != nil {
+			logger.Error(
+				"failed-to-resolve-requester-group-permission",
+				zap.String("requester-user-id", r.UserId),
+				zap.String("group-id", r.GroupID),
+				zap.Error(accessErr),
+			)
+			return nil, ErrFailedToResolveGroupAccessMap
+		}
+
+		if !canViewActivity {
+			return nil, group.ErrInsufficientPermissions
+		}
+	}
+
+	actions := []string{}
+	for _, action := range strings.Split(r.Actions, ",") {
+		if trimmedAction := strings.TrimSpace(action); trimmedAction != "" {
+			actions = append(actions, trimmedAction)
+		}
+	}
+
+	resp, err := s.GroupService.GetGroupActivity(ctx, &group.GetGroupActivityRequest{
+		GroupID:            r.GroupID,
+		IncludeDescendants: r.IncludeDescendants,
+		ActorID:            r.ActorID,
+		Actions:            actions,
+		From:               r.From,
+		To:                 r.To,
+		Cursor:             r.Cursor,
+		Limit:              r.Limit,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-group-activity", zap.String("group-id", r.GroupID), zap.Error(err))
+		return nil, err
+	}
+
+	return &GetGroupActivityResponse{
+		Activities: s.enrichGroupActivities(ctx, resp.Activities),
+		NextCursor: resp.NextCursor,
+	}, nil
+}
+
+// enrichGroupActivities resolves the actor and subject profiles of each activity and
+// re-renders its summary with their names. Invitees and the system are not users, so
+// they are left without a profile, while missing or unavailable users become ID-only stubs.
+func (s *Service) enrichGroupActivities(ctx context.Context, activities []group.GroupActivity) []GroupActivityEntry {
+	isUserID := func(id string) bool {
+		return id != "" && id != audit.AuditActorIdSystem && !strings.Contains(id, "@")
+	}
+
+	userIDs := make([]string, 0, len(activities)*2)
+	for _, activity := range activities {
+		for _, id := range []string{activity.ActorID, activity.SubjectID} {
+			if isUserID(id) {
+				userIDs = append(userIDs, id)
+			}
+		}
+	}
+	usersByID := s.loadUsersForEnrichment(ctx, userIDs, "group-activity-user-enrichment")
+
+	displayNames := make(map[string]string, len(usersByID))
+	profileOf := func(id string) *EnrichedMember {
+		if !isUserID(id) {
+			return nil
+		}
+
+		profile := enrichedMemberFromUser(id, usersByID[strings.TrimSpace(id)])
+		if profile != nil && profile.FullName != "" {
+			displayNames[id] = profile.FullName
+		}
+		return profile
+	}
+
+	entries := make([]GroupActivityEntry, 0, len(activities))
+	for _, activity := range activities {
+		entry := GroupActivityEntry{
+			GroupActivity: activity,
+			Actor:         profileOf(activity.ActorID),
+			Subject:       profileOf(activity.SubjectID),
+		}
+		entry.Summary = group.DescribeGroupActivity(&entry.GroupActivity, displayNames)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
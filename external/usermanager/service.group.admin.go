@@ -75,8 +75,8 @@ func (s *Service) ValidateGroupName(ctx context.Context, r *ValidateGroupNameReq
 	return &ValidateGroupNameResponse{ValidateGroupNameResponse: resp}, nil
 }
 
-// CreateGroup handles creating a new group with the provided details. Only admins or users with
-// access to the parent group (if specified) can create groups.
+// CreateGroup handles creating a new group with the provided details. Only admins or users
+// permitted to create subgroups of the parent group (if specified) can create groups.
 func (s *Service) CreateGroup(ctx context.Context, r *CreateGroupRequest) (*CreateGroupResponse, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
 
@@ -95,10 +95,10 @@ func (s *Service) CreateGroup(ctx context.Context, r *CreateGroupRequest) (*Crea
 			return nil, group.ErrInsufficientPermissions
 		}
 
-		hasGroupAccess, accessErr := s.hasRequesterGroupAccess(ctx, r.UserID, r.ParentGroupID)
+		canCreateSubgroups, accessErr := s.hasRequesterGroupPermission(ctx, r.UserID, r.ParentGroupID, group.PermissionSubgroupsCreate)
 		if accessErr != nil {
 			logger.Error(
-				"failed-to-resolve-requester-group-access-map",
+				"failed-to-resolve-requester-group-permission",
 				zap.String("requester-user-id", r.UserID),
 				zap.String("group-id", r.ParentGroupID),
 				zap.Error(accessErr),
@@ -106,7 +106,7 @@ func (s *Service) CreateGroup(ctx context.Context, r *CreateGroupRequest) (*Crea
 			return nil, ErrFailedToResolveGroupAccessMap
 		}
 
-		if !hasGroupAccess.IsAdmin {
+		if !canCreateSubgroups {
 			return nil, group.ErrInsufficientPermissions
 		}
 
@@ -119,6 +119,7 @@ func (s *Service) CreateGroup(ctx context.Context, r *CreateGroupRequest) (*Crea
 	}
 
 	// Create the group via group service
+	r.CreateGroupRequest.ActorID = r.UserID
 	groupResp, err := s.GroupService.CreateGroup(ctx, r.CreateGroupRequest)
 	if err != nil {
 		logger.Error("failed-to-create-group", zap.String("name", r.CreateGroupRequest.Name), zap.String("type", r.CreateGroupRequest.Type), zap.Error(err))
@@ -131,8 +132,8 @@ func (s *Service) CreateGroup(ctx context.Context, r *CreateGroupRequest) (*Crea
 }
 
 // UpdateGroup handles updating an existing group. Admin users can update any group.
-// Non-admin users must have effective admin-level access to the target group,
-// which also covers admin/owner permissions inherited from parent groups.
+// Non-admin users must hold the settings.update permission in the target group,
+// which also covers permissions inherited from parent groups.
 func (s *Service) UpdateGroup(ctx context.Context, r *UpdateGroupRequest) (*UpdateGroupResponse, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
 
@@ -147,10 +148,10 @@ func (s *Service) UpdateGroup(ctx context.Context, r *UpdateGroupRequest) (*Upda
 
 	isAdmin := s.isRequesterAdmin(ctx, r.UserId, logger)
 	if !isAdmin {
-		accessMap, accessErr := s.GroupService.GetUserGroupAccessMap(ctx, r.UserId)
+		canUpdateSettings, accessErr := s.hasRequesterGroupPermission(ctx, r.UserId, r.UpdateGroupRequest.ID, group.PermissionSettingsUpdate)
 		if accessErr != nil {
 			logger.Error(
-				"failed-to-resolve-requester-group-access-map",
+				"failed-to-resolve-requester-group-permission",
 				zap.String("requester-user-id", r.UserId),
 				zap.String("group-id", r.UpdateGroupRequest.ID),
 				zap.Error(accessErr),
@@ -158,12 +159,12 @@ func (s *Service) UpdateGroup(ctx context.Context, r *UpdateGroupRequest) (*Upda
 			return nil, ErrFailedToResolveGroupAccessMap
 		}
 
-		groupAccess, ok := accessMap[r.UpdateGroupRequest.ID]
-		if !ok || !groupAccess.IsAccessible || !groupAccess.IsAdmin {
+		if !canUpdateSettings {
 			return nil, group.ErrInsufficientPermissions
 		}
 	}
 
+	r.UpdateGroupRequest.ActorID = r.UserId
 	resp, err := s.GroupService.UpdateGroup(ctx, r.UpdateGroupRequest)
 	if err != nil {
 		logger.Error("failed-to-update-group", zap.String("group-id", r.UpdateGroupRequest.ID), zap.Error(err))
@@ -174,7 +175,8 @@ func (s *Service) UpdateGroup(ctx context.Context, r *UpdateGroupRequest) (*Upda
 }
 
 // DeleteGroup handles deleting a group. Admin users may request hard/soft delete;
-// non-admin users must be the owner of the target group and are forced to hard-delete.
+// non-admin users must hold the group.delete permission, which by default only owners
+// hold, and are forced to hard-delete.
 func (s *Service) DeleteGroup(ctx context.Context, r *DeleteGroupRequest) (*DeleteGroupResponse, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
 
@@ -185,10 +187,10 @@ func (s *Service) DeleteGroup(ctx context.Context, r *DeleteGroupRequest) (*Dele
 
 	isAdmin := s.isRequesterAdmin(ctx, r.UserID, logger)
 	if !isAdmin {
-		accessMap, accessErr := s.GroupService.GetUserGroupAccessMap(ctx, r.UserID)
+		canDelete, accessErr := s.hasRequesterGroupPermission(ctx, r.UserID, r.DeleteGroupRequest.ID, group.PermissionGroupDelete)
 		if accessErr != nil {
 			logger.Error(
-				"failed-to-resolve-requester-group-access-map",
+				"failed-to-resolve-requester-group-permission",
 				zap.String("requester-user-id", r.UserID),
 				zap.String("group-id", r.DeleteGroupRequest.ID),
 				zap.Error(accessErr),
@@ -196,23 +198,7 @@ func (s *Service) DeleteGroup(ctx context.Context, r *DeleteGroupRequest) (*Dele
 			return nil, ErrFailedToResolveGroupAccessMap
 		}
 
-		groupAccess, ok := accessMap[r.DeleteGroupRequest.ID]
-		if !ok || !groupAccess.IsAccessible || !groupAccess.IsAdmin {
-			return nil, group.ErrInsufficientPermissions
-		}
-
-		groupResp, groupErr := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: r.DeleteGroupRequest.ID})
-		if groupErr != nil {
-			logger.Error(
-				"failed-to-resolve-group-for-delete-ownership-check",
-				zap.String("requester-user-id", r.UserID),
-				zap.String("group-id", r.DeleteGroupRequest.ID),
-				zap.Error(groupErr),
-			)
-			return nil, groupErr
-		}
-
-		if strings.TrimSpace(groupResp.Group.OwnerID) != strings.TrimSpace(r.UserID) {
+		if !canDelete {
 			return nil, group.ErrInsufficientPermissions
 		}
 
@@ -242,14 +228,14 @@ func (s *Service) AddGroupMember(ctx context.Context, r *AddGroupMemberRequest)
 	}
 
 	// Auth check: only allow if requester is admin or
-	// has access to parent group (if specified)
+	// can manage the group's members
 	isAdmin := s.isRequesterAdmin(ctx, r.UserID, logger)
 	if !isAdmin {
 
-		hasGroupAccess, accessErr := s.hasRequesterGroupAccess(ctx, r.UserID, r.GroupID)
+		canManageMembers, accessErr := s.hasRequesterGroupPermission(ctx, r.UserID, r.GroupID, group.PermissionMembersManage)
 		if accessErr != nil {
 			logger.Error(
-				"failed-to-resolve-requester-group-access-map",
+				"failed-to-resolve-requester-group-permission",
 				zap.String("requester-user-id", r.UserID),
 				zap.String("group-id", r.GroupID),
 				zap.Error(accessErr),
@@ -257,12 +243,13 @@ func (s *Service) AddGroupMember(ctx context.Context, r *AddGroupMemberRequest)
 			return nil, ErrFailedToResolveGroupAccessMap
 		}
 
-		if !hasGroupAccess.IsAdmin {
+		if !canManageMembers {
 			return nil, group.ErrInsufficientPermissions
 		}
 	}
 
 	r.AddMemberRequest.Type = group.MemberTypeUser
+	r.AddMemberRequest.ActorID = r.UserID
 	_, err := s.GroupService.AddMember(ctx, r.AddMemberRequest)
 	if err != nil {
 		logger.Error("add-group-member-failed",
@@ -286,14 +273,14 @@ func (s *Service) RemoveGroupMember(ctx context.Context, r *RemoveGroupMemberReq
 	}
 
 	// Auth check: only allow if requester is admin or
-	// has access to parent group (if specified)
+	// can manage the group's members
 	isAdmin := s.isRequesterAdmin(ctx, r.UserID, logger)
 	if !isAdmin {
 
-		hasGroupAccess, accessErr := s.hasRequesterGroupAccess(ctx, r.UserID, r.GroupID)
+		canManageMembers, accessErr := s.hasRequesterGroupPermission(ctx, r.UserID, r.GroupID, group.PermissionMembersManage)
 		if accessErr != nil {
 			logger.Error(
-				"failed-to-resolve-requester-group-access-map",
+				"failed-to-resolve-requester-group-permission",
 				zap.String("requester-user-id", r.UserID),
 				zap.String("group-id", r.GroupID),
 				zap.Error(accessErr),
@@ -301,11 +288,12 @@ func (s *Service) RemoveGroupMember(ctx context.Context, r *RemoveGroupMemberReq
 			return nil, ErrFailedToResolveGroupAccessMap
 		}
 
-		if !hasGroupAccess.IsAdmin {
+		if !canManageMembers {
 			return nil, group.ErrInsufficientPermissions
 		}
 	}
 
+	r.RemoveMemberRequest.ActorID = r.UserID
 	_, err := s.GroupService.RemoveMember(ctx, r.RemoveMemberRequest)
 	if err != nil {
 		logger.Error("remove-group-member-failed",
@@ -331,10 +319,10 @@ func (s *Service) UpdateGroupMember(ctx context.Context, r *UpdateGroupMemberReq
 	isAdmin := s.isRequesterAdmin(ctx, r.UserID, logger)
 	if !isAdmin {
 
-		hasGroupAccess, accessErr := s.hasRequesterGroupAccess(ctx, r.UserID, r.GroupID)
+		canManageMembers, accessErr := s.hasRequesterGroupPermission(ctx, r.UserID, r.GroupID, group.PermissionMembersManage)
 		if accessErr != nil {
 			logger.Error(
-				"failed-to-resolve-requester-group-access-map",
+				"failed-to-resolve-requester-group-permission",
 				zap.String("requester-user-id", r.UserID),
 				zap.String("group-id", r.GroupID),
 				zap.Error(accessErr),
@@ -342,11 +330,13 @@ func (s *Service) UpdateGroupMember(ctx context.Context, r *UpdateGroupMemberReq
 			return nil, ErrFailedToResolveGroupAccessMap
 		}
 
-		if !hasGroupAccess.IsAdmin {
+		if !canManageMembers {
 			return nil, group.ErrInsufficientPermissions
 		}
 	}
 
+	r.UpdateMemberRoleRequest.ActorID = r.UserID
+	r.UpdateMemberRoleRequest.ActorIsPlatformAdmin = isAdmin
 	_, err := s.GroupService.UpdateMemberRole(ctx, r.UpdateMemberRoleRequest)
 	if err != nil {
 		logger.Error("update-group-member-failed",
@@ -371,14 +361,14 @@ func (s *Service) UpdateGroupOwner(ctx context.Context, r *UpdateGroupOwnerReque
 	}
 
 	// Auth check: only allow if requester is admin or
-	// has access to parent group (if specified)
+	// can transfer the group's ownership
 	isAdmin := s.isRequesterAdmin(ctx, r.UserID, logger)
 	if !isAdmin {
 
-		hasGroupAccess, accessErr := s.hasRequesterGroupAccess(ctx, r.UserID, r.GroupID)
+		canTransferOwnership, accessErr := s.hasRequesterGroupPermission(ctx, r.UserID, r.GroupID, group.PermissionOwnerTransfer)
 		if accessErr != nil {
 			logger.Error(
-				"failed-to-resolve-requester-group-access-map",
+				"failed-to-resolve-requester-group-permission",
 				zap.String("requester-user-id", r.UserID),
 				zap.String("group-id", r.GroupID),
 				zap.Error(accessErr),
@@ -386,7 +376,7 @@ func (s *Service) UpdateGroupOwner(ctx context.Context, r *UpdateGroupOwnerReque
 			return nil, ErrFailedToResolveGroupAccessMap
 		}
 
-		if !hasGroupAccess.IsAdmin {
+		if !canTransferOwnership {
 			return nil, group.ErrInsufficientPermissions
 		}
 	}
@@ -394,6 +384,7 @@ func (s *Service) UpdateGroupOwner(ctx context.Context, r *UpdateGroupOwnerReque
 	_, err := s.GroupService.UpdateOwner(ctx, &group.UpdateOwnerRequest{
 		GroupID: r.GroupID,
 		OwnerID: r.OwnerID,
+		ActorID: r.UserID,
 	})
 	if err != nil {
 		logger.Error("update-group-owner-failed",
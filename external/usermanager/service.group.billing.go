@@ -0,0 +1,102 @@
+package usermanager
+
+import (
+	"context"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	"go.uber.org/zap"
+)
+
+// GetGroupBilling handles fetching the seats purchased and used by a group. Members with access
+// to the group see the seat summary, while the group's owner, admins and billing admins, as well
+// as platform admins, also see the group-owned subscriptions.
+func (s *Service) GetGroupBilling(ctx context.Context, r *GetGroupBillingRequest) (*GetGroupBillingResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	if s.BillingService == nil {
+		logger.Error("billing-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrBillingServiceNotEnabled
+	}
+
+	groupResp, err := s.GroupService.GetGroupByID(ctx, &group.GetGroupByIDRequest{ID: r.GroupID})
+	if err != nil || groupResp == nil || groupResp.Group == nil {
+		logger.Warn("failed-to-get-group-for-billing", zap.String("group-id", r.GroupID), zap.Error(err))
+		return nil, ErrGroupNotFound
+	}
+
+	canManageBilling := groupResp.Group.IsBillingAdmin(r.UserId) || s.isRequesterAdmin(ctx, r.UserId, logger)
+	if !canManageBilling {
+		hasBillingPermission, permissionErr := s.hasRequesterGroupPermission(ctx, r.UserId, r.GroupID, group.PermissionBillingManage)
+		if permissionErr != nil {
+			logger.Warn(
+				"failed-to-resolve-requester-billing-permission",
+				zap.String("requester-user-id", r.UserId),
+				zap.String("group-id", r.GroupID),
+				zap.Error(permissionErr),
+			)
+		}
+		canManageBilling = hasBillingPermission
+	}
+
+	if !canManageBilling {
+		hasGroupAccess, accessErr := s.hasRequesterGroupAccess(ctx, r.UserId, r.GroupID)
+		if accessErr != nil {
+			logger.Warn(
+				"failed-to-resolve-requester-group-access-for-billing",
+				zap.String("requester-user-id", r.UserId),
+				zap.String("group-id", r.GroupID),
+				zap.Error(accessErr),
+			)
+			return nil, ErrGroupNotFound
+		}
+
+		if !hasGroupAccess.IsAccessible {
+			return nil, ErrGroupNotFound
+		}
+	}
+
+	subscriptionsResp, err := s.BillingService.GetSubscriptions(ctx, &billing.GetSubscriptionsRequest{
+		ForGroupIDs: []string{r.GroupID},
+		Order:       "created_at_desc",
+		PerPage:     100,
+		Page:        1,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-group-subscriptions", zap.String("group-id", r.GroupID), zap.Error(err))
+		return nil, err
+	}
+
+	usedSeats, _ := s.calculateGroupSeatUsage(ctx, groupResp.Group, logger)
+	purchasedSeats := billing.GroupSeats(subscriptionsResp.Subscriptions)
+
+	limit := 0
+	if groupResp.Group.Settings != nil {
+		limit = groupResp.Group.Settings.MaxMembers
+	}
+
+	groupBilling := GroupBilling{
+		GroupID: groupResp.Group.ID,
+		Seats: GroupBillingSeats{
+			Purchased: purchasedSeats,
+			Used:      usedSeats,
+			Available: max(purchasedSeats-usedSeats, 0),
+			Limit:     limit,
+		},
+		CanManageBilling: canManageBilling,
+	}
+
+	if canManageBilling {
+		groupBilling.Subscriptions = subscriptionsResp.Subscriptions
+	}
+
+	logger.Debug("group-billing-retrieved", zap.String("group-id", r.GroupID), zap.Int("purchased-seats", purchasedSeats), zap.Int("used-seats", usedSeats))
+
+	return &GetGroupBillingResponse{Billing: groupBilling}, nil
+}
@@ -374,6 +374,35 @@ func (s *Service) AcceptMyGroupInvitation(ctx context.Context, r *AcceptMyGroupI
 	return &AcceptMyGroupInvitationResponse{AcceptInviteResponse: resp}, nil
 }
 
+// AcceptMyGroupInvitationByToken accepts the group invitation an emailed link was issued for.
+// The invitation must have been sent to the requester's email.
+func (s *Service) AcceptMyGroupInvitationByToken(ctx context.Context, r *AcceptMyGroupInvitationByTokenRequest) (*AcceptMyGroupInvitationByTokenResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	userResponse, err := s.UserService.GetUserByID(ctx, &userv2.GetUserByIDRequest{ID: r.UserId})
+	if err != nil {
+		logger.Error("failed-to-resolve-user-for-accept-group-invitation-by-token", zap.String("user-id", r.UserId), zap.Error(err))
+		return nil, err
+	}
+
+	resp, err := s.GroupService.AcceptInviteByToken(ctx, &group.AcceptInviteByTokenRequest{
+		Token:     r.Token,
+		UserID:    r.UserId,
+		UserEmail: strings.TrimSpace(userResponse.User.Email),
+	})
+	if err != nil {
+		logger.Error("failed-to-accept-my-group-invitation-by-token", zap.String("user-id", r.UserId), zap.Error(err))
+		return nil, err
+	}
+
+	return &AcceptMyGroupInvitationByTokenResponse{AcceptInviteByTokenResponse: resp}, nil
+}
+
 // RejectMyGroupInvitation rejects a pending group invitation for the requester.
 func (s *Service) RejectMyGroupInvitation(ctx context.Context, r *RejectMyGroupInvitationRequest) (*RejectMyGroupInvitationResponse, error) {
 	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
@@ -719,6 +748,20 @@ func (s *Service) hasRequesterGroupAccess(ctx context.Context, userID, groupID s
 	return &groupAccess, nil
 }
 
+// hasRequesterGroupPermission checks if the requester holds the permission in the group,
+// either through their own roles in it or roles inherited from its ancestors
+func (s *Service) hasRequesterGroupPermission(ctx context.Context, userID, groupID, permission string) (bool, error) {
+
+	var logger *zap.Logger = logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", userID))
+		return false, ErrGroupServiceNotEnabled
+	}
+
+	return s.GroupService.Can(ctx, userID, groupID, permission)
+}
+
 // calculateGroupSeatUsage computes total unique users including nested group members and owner
 func (s *Service) calculateGroupSeatUsage(ctx context.Context, grp *group.UniversalGroup, logger *zap.Logger) (int, map[string]int) {
 	seenGroups := make(map[string]struct{})
@@ -0,0 +1,197 @@
+package usermanager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	"github.com/ooaklee/ghatd/external/notifier"
+	"go.uber.org/zap"
+)
+
+// GetMyJoinRequests returns the requester's group join requests.
+func (s *Service) GetMyJoinRequests(ctx context.Context, r *GetMyJoinRequestsRequest) (*GetMyJoinRequestsResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	resp, err := s.GroupService.GetJoinRequests(ctx, &group.GetJoinRequestsRequest{
+		UserID: r.UserId,
+		Status: r.Status,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-my-join-requests", zap.String("user-id", r.UserId), zap.Error(err))
+		return nil, err
+	}
+
+	return &GetMyJoinRequestsResponse{GetJoinRequestsResponse: resp}, nil
+}
+
+// CreateMyJoinRequest asks to join a group on behalf of the requester. The requester
+// is added straight away when the group does not require approval.
+func (s *Service) CreateMyJoinRequest(ctx context.Context, r *CreateMyJoinRequestRequest) (*CreateMyJoinRequestResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	r.RequestToJoinGroupRequest.UserID = r.UserId
+	resp, err := s.GroupService.RequestToJoinGroup(ctx, r.RequestToJoinGroupRequest)
+	if err != nil {
+		logger.Error("failed-to-create-my-join-request", zap.String("user-id", r.UserId), zap.String("group-id", r.GroupID), zap.Error(err))
+		return nil, err
+	}
+
+	return &CreateMyJoinRequestResponse{RequestToJoinGroupResponse: resp}, nil
+}
+
+// CancelMyJoinRequest withdraws one of the requester's pending join requests.
+func (s *Service) CancelMyJoinRequest(ctx context.Context, r *CancelMyJoinRequestRequest) (*CancelMyJoinRequestResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	resp, err := s.GroupService.CancelJoinRequest(ctx, &group.CancelJoinRequestRequest{
+		JoinRequestID: r.JoinRequestID,
+		UserID:        r.UserId,
+	})
+	if err != nil {
+		logger.Error("failed-to-cancel-my-join-request", zap.String("user-id", r.UserId), zap.String("join-request-id", r.JoinRequestID), zap.Error(err))
+		return nil, err
+	}
+
+	return &CancelMyJoinRequestResponse{CancelJoinRequestResponse: resp}, nil
+}
+
+// GetGroupJoinRequests returns a group's join requests for platform admins and group admins.
+func (s *Service) GetGroupJoinRequests(ctx context.Context, r *GetGroupJoinRequestsRequest) (*GetGroupJoinRequestsResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	if !s.isRequesterAdmin(ctx, r.UserId, logger) {
+		canReview, accessErr := s.hasRequesterGroupPermission(ctx, r.UserId, r.GroupID, group.PermissionJoinRequestsReview)
+		if accessErr != nil {
+			logger.Error(
+				"failed-to-resolve-requester-group-permission",
+				zap.String("requester-user-id", r.UserId),
+				zap.String("group-id", r.GroupID),
+				zap.Error(accessErr),
+			)
+			return nil, ErrFailedToResolveGroupAccessMap
+		}
+
+		if !canReview {
+			return nil, group.ErrInsufficientPermissions
+		}
+	}
+
+	resp, err := s.GroupService.GetJoinRequests(ctx, &group.GetJoinRequestsRequest{
+		GroupID: r.GroupID,
+		Status:  r.Status,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-group-join-requests", zap.String("group-id", r.GroupID), zap.Error(err))
+		return nil, err
+	}
+
+	return &GetGroupJoinRequestsResponse{GetJoinRequestsResponse: resp}, nil
+}
+
+// ApproveGroupJoinRequest approves a join request and notifies the requester.
+// Group admins are resolved by the group service, platform admins may review any group.
+func (s *Service) ApproveGroupJoinRequest(ctx context.Context, r *ReviewGroupJoinRequestRequest) (*ReviewGroupJoinRequestResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	r.ReviewerID = r.UserId
+	r.ReviewerIsPlatformAdmin = s.isRequesterAdmin(ctx, r.UserId, logger)
+
+	resp, err := s.GroupService.ApproveJoinRequest(ctx, r.ReviewJoinRequestRequest)
+	if err != nil {
+		logger.Error("failed-to-approve-group-join-request", zap.String("group-id", r.GroupID), zap.String("join-request-id", r.JoinRequestID), zap.Error(err))
+		return nil, err
+	}
+
+	s.notifyJoinRequestRequester(ctx, resp, logger)
+
+	return &ReviewGroupJoinRequestResponse{ReviewJoinRequestResponse: resp}, nil
+}
+
+// DenyGroupJoinRequest denies a join request and notifies the requester.
+// Group admins are resolved by the group service, platform admins may review any group.
+func (s *Service) DenyGroupJoinRequest(ctx context.Context, r *ReviewGroupJoinRequestRequest) (*ReviewGroupJoinRequestResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	r.ReviewerID = r.UserId
+	r.ReviewerIsPlatformAdmin = s.isRequesterAdmin(ctx, r.UserId, logger)
+
+	resp, err := s.GroupService.DenyJoinRequest(ctx, r.ReviewJoinRequestRequest)
+	if err != nil {
+		logger.Error("failed-to-deny-group-join-request", zap.String("group-id", r.GroupID), zap.String("join-request-id", r.JoinRequestID), zap.Error(err))
+		return nil, err
+	}
+
+	s.notifyJoinRequestRequester(ctx, resp, logger)
+
+	return &ReviewGroupJoinRequestResponse{ReviewJoinRequestResponse: resp}, nil
+}
+
+// notifyJoinRequestRequester lets the requester know their join request was reviewed.
+// Notification failures are logged and do not undo the review.
+func (s *Service) notifyJoinRequestRequester(ctx context.Context, resp *group.ReviewJoinRequestResponse, logger *zap.Logger) {
+	if s.NotifierService == nil || resp == nil || resp.JoinRequest == nil {
+		return
+	}
+
+	joinRequest := resp.JoinRequest
+	groupName := "the group"
+	if resp.Group != nil && resp.Group.Name != "" {
+		groupName = resp.Group.Name
+	}
+
+	title := "Join request approved"
+	message := fmt.Sprintf("Your request to join %s has been approved.", groupName)
+	if joinRequest.Status == group.JoinRequestStatusDenied {
+		title = "Join request declined"
+		message = fmt.Sprintf("Your request to join %s has been declined.", groupName)
+		if joinRequest.DecisionReason != "" {
+			message = fmt.Sprintf("%s Reason: %s", message, joinRequest.DecisionReason)
+		}
+	}
+
+	_, err := s.NotifierService.NotifyUser(ctx, &notifier.NotifyUserRequest{
+		UserID:  joinRequest.UserID,
+		Title:   title,
+		Message: message,
+		Data: map[string]interface{}{
+			"group_id":        joinRequest.GroupID,
+			"join_request_id": joinRequest.ID,
+			"status":          joinRequest.Status,
+		},
+	})
+	if err != nil {
+		logger.Warn("failed-to-notify-join-request-requester", zap.String("join-request-id", joinRequest.ID), zap.Error(err))
+	}
+}
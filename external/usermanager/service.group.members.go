@@ -0,0 +1,220 @@
+package usermanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
+)
+
+// groupMemberExportCSVHeader is the header row of a CSV member export, its email and
+// role columns can be uploaded unchanged as a member import
+var groupMemberExportCSVHeader = []string{"email", "role", "full_name", "id", "type", "joined_at", "invited_at", "invitation_state"}
+
+// ImportGroupMembers validates an uploaded list of members against a group for platform admins
+// and members holding members.manage. Dry runs return the validation report only, otherwise the
+// valid rows are imported in the background and the import is returned for progress tracking.
+func (s *Service) ImportGroupMembers(ctx context.Context, r *ImportGroupMembersRequest) (*ImportGroupMembersResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	if err := s.requireGroupPermission(ctx, r.UserId, r.GroupID, group.PermissionMembersManage, logger); err != nil {
+		return nil, err
+	}
+
+	r.ActorID = r.UserId
+	resp, err := s.GroupService.ImportMembers(ctx, r.ImportMembersRequest)
+	if err != nil {
+		logger.Error("failed-to-import-group-members", zap.String("group-id", r.GroupID), zap.Bool("dry-run", r.DryRun), zap.Error(err))
+		return nil, err
+	}
+
+	return &ImportGroupMembersResponse{ImportMembersResponse: resp}, nil
+}
+
+// GetGroupMemberImport returns the progress and per-row results of a group member import for
+// platform admins and members holding members.manage.
+func (s *Service) GetGroupMemberImport(ctx context.Context, r *GetGroupMemberImportRequest) (*GetGroupMemberImportResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	if err := s.requireGroupPermission(ctx, r.UserId, r.GroupID, group.PermissionMembersManage, logger); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.GroupService.GetMemberImport(ctx, &group.GetMemberImportRequest{GroupID: r.GroupID, ImportID: r.ImportID})
+	if err != nil {
+		logger.Error("failed-to-get-group-member-import", zap.String("group-id", r.GroupID), zap.String("import-id", r.ImportID), zap.Error(err))
+		return nil, err
+	}
+
+	return &GetGroupMemberImportResponse{GetMemberImportResponse: resp}, nil
+}
+
+// ExportGroupMembers writes a group's members with their resolved profiles to a CSV or JSON
+// file for platform admins and members holding members.view. Pending invitations are
+// included with the invited email address.
+func (s *Service) ExportGroupMembers(ctx context.Context, r *ExportGroupMembersRequest) (*ExportGroupMembersResponse, error) {
+	logger := logger.AcquirePackageFrom(ctx, "external/usermanager")
+
+	if s.GroupService == nil {
+		logger.Error("group-service-not-enabled", zap.String("user-id", r.UserId))
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	if err := s.requireGroupPermission(ctx, r.UserId, r.GroupID, group.PermissionMembersView, logger); err != nil {
+		return nil, err
+	}
+
+	membersResp, err := s.GroupService.GetGroupMembers(ctx, &group.GetGroupMembersRequest{GroupID: r.GroupID})
+	if err != nil {
+		logger.Error("failed-to-get-group-members-for-export", zap.String("group-id", r.GroupID), zap.Error(err))
+		return nil, err
+	}
+
+	exports := s.buildGroupMemberExports(ctx, membersResp.Members)
+
+	response := &ExportGroupMembersResponse{}
+	switch strings.ToLower(strings.TrimSpace(r.Format)) {
+	case group.MemberImportFormatJSON:
+		response.Content, err = json.MarshalIndent(exports, "", "  ")
+		response.ContentType = "application/json"
+		response.FileName = "group-members-" + r.GroupID + ".json"
+	case "", group.MemberImportFormatCSV:
+		response.Content, err = groupMemberExportsToCSV(exports)
+		response.ContentType = "text/csv"
+		response.FileName = "group-members-" + r.GroupID + ".csv"
+	default:
+		return nil, ErrRequestFailedValidation
+	}
+	if err != nil {
+		logger.Error("failed-to-write-group-member-export", zap.String("group-id", r.GroupID), zap.String("format", r.Format), zap.Error(err))
+		return nil, ErrUserManagerError
+	}
+
+	return response, nil
+}
+
+// MemberImportUserFinder returns a group.MemberImportUserFinder backed by
+// the user service, so member imports add people who already have an
+// account and invite everyone else.
+func (s *Service) MemberImportUserFinder() group.MemberImportUserFinder {
+	return &memberImportUserFinder{service: s}
+}
+
+// memberImportUserFinder resolves member import emails to user IDs
+type memberImportUserFinder struct {
+	service *Service
+}
+
+// FindUserIDByEmail returns the ID of the user with the email, or an empty
+// ID when no user has it.
+func (f *memberImportUserFinder) FindUserIDByEmail(ctx context.Context, email string) (string, error) {
+	if f.service.UserService == nil {
+		return "", nil
+	}
+
+	resp, err := f.service.UserService.GetUserByEmail(ctx, &userv2.GetUserByEmailRequest{Email: email})
+	if errors.Is(err, userv2.ErrUserNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || resp.User == nil {
+		return "", nil
+	}
+
+	return resp.User.ID, nil
+}
+
+// requireGroupPermission returns nil when the requester is a platform admin or holds the
+// permission on the group
+func (s *Service) requireGroupPermission(ctx context.Context, userID, groupID, permission string, logger *zap.Logger) error {
+	if s.isRequesterAdmin(ctx, userID, logger) {
+		return nil
+	}
+
+	allowed, err := s.hasRequesterGroupPermission(ctx, userID, groupID, permission)
+	if err != nil {
+		logger.Error(
+			"failed-to-resolve-requester-group-permission",
+			zap.String("requester-user-id", userID),
+			zap.String("group-id", groupID),
+			zap.String("permission", permission),
+			zap.Error(err),
+		)
+		return ErrFailedToResolveGroupAccessMap
+	}
+
+	if !allowed {
+		return group.ErrInsufficientPermissions
+	}
+
+	return nil
+}
+
+// buildGroupMemberExports resolves the profiles of a group's members for export. Pending
+// invitations are keyed by the invited email, so they keep it rather than being enriched.
+func (s *Service) buildGroupMemberExports(ctx context.Context, members []group.Member) []GroupMemberExport {
+	enriched, _ := s.enrichGroupDetailUsers(ctx, members, "")
+
+	exports := make([]GroupMemberExport, 0, len(members))
+	for i, member := range members {
+		export := GroupMemberExport{
+			ID:              member.ID,
+			Type:            member.Type,
+			Role:            member.Role,
+			JoinedAt:        member.JoinedAt,
+			InvitedAt:       member.InvitedAt,
+			InvitationState: member.InvitationState,
+		}
+		if member.InvitationState != "" && strings.Contains(member.ID, "@") {
+			export.Email = member.ID
+		} else {
+			export.Email = enriched[i].Email
+			export.FullName = enriched[i].FullName
+		}
+		exports = append(exports, export)
+	}
+
+	return exports
+}
+
+// groupMemberExportsToCSV writes member exports as CSV with a header row
+func groupMemberExportsToCSV(exports []GroupMemberExport) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write(groupMemberExportCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, export := range exports {
+		record := []string{export.Email, export.Role, export.FullName, export.ID, export.Type, export.JoinedAt, export.InvitedAt, export.InvitationState}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -2,10 +2,14 @@ package usermanager
 
 import (
 	"context"
+	"sort"
 	"strings"
 
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/logger"
 	"github.com/ooaklee/ghatd/external/notifier"
 	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"go.uber.org/zap"
 )
 
 // ensureNotifierService returns an error if the notifier service has not been
@@ -225,8 +229,9 @@ func (s *Service) NotifyUser(ctx context.Context, r *NotifyUserRequest) (*Notify
 // This is an admin-only endpoint under POST /notifications. The
 // request specifies:
 //
-//   - UserIDs (optional) — which users to notify. An empty list means
-//     every user with at least one active notification address.
+//   - At most one of UserIDs, Topic, or Segment — who to notify. When
+//     none are set, every user with at least one active notification
+//     address is notified.
 //   - Title and Message — the notification headline and body text.
 //   - Channels (optional) — limit delivery to specific channels. Empty
 //     means all supported channels.
@@ -239,6 +244,10 @@ func (s *Service) NotifyUsers(ctx context.Context, r *NotifyUsersRequest) (*Noti
 		return nil, err
 	}
 
+	if r.NotifyUsersRequest != nil {
+		r.NotifyUsersRequest.RequestedBy = r.UserId
+	}
+
 	response, err := s.NotifierService.NotifyUsers(ctx, r.NotifyUsersRequest)
 	if err != nil {
 		if response != nil {
@@ -250,6 +259,203 @@ func (s *Service) NotifyUsers(ctx context.Context, r *NotifyUsersRequest) (*Noti
 	return &NotifyUsersResponse{NotifyUsersResponse: response}, nil
 }
 
+// GetNotificationDispatch returns the stored state of a notification
+// dispatch job, including its progress counters and resume cursor.
+func (s *Service) GetNotificationDispatch(ctx context.Context, r *GetNotificationDispatchRequest) (*GetNotificationDispatchResponse, error) {
+	if err := s.ensureNotifierService(); err != nil {
+		return nil, err
+	}
+
+	response, err := s.NotifierService.GetDispatchJob(ctx, r.GetDispatchJobRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetNotificationDispatchResponse{GetDispatchJobResponse: response}, nil
+}
+
+// ResumeNotificationDispatch continues a notification dispatch job that
+// stopped before it completed. Users in batches that were already
+// checkpointed are not notified again.
+func (s *Service) ResumeNotificationDispatch(ctx context.Context, r *ResumeNotificationDispatchRequest) (*NotifyUsersResponse, error) {
+	if err := s.ensureNotifierService(); err != nil {
+		return nil, err
+	}
+
+	response, err := s.NotifierService.ResumeDispatchJob(ctx, r.ResumeDispatchJobRequest)
+	if err != nil {
+		if response != nil {
+			return &NotifyUsersResponse{NotifyUsersResponse: response}, err
+		}
+		return nil, err
+	}
+
+	return &NotifyUsersResponse{NotifyUsersResponse: response}, nil
+}
+
+// SubscribeNotificationTopic subscribes the current user to a notification
+// topic.
+//
+// Group-scoped topics ("group:{id}") are only available to users who can
+// access the group, so a user cannot listen in on another group's
+// announcements.
+func (s *Service) SubscribeNotificationTopic(ctx context.Context, r *SubscribeNotificationTopicRequest) (*SubscribeNotificationTopicResponse, error) {
+	if err := s.ensureNotifierService(); err != nil {
+		return nil, err
+	}
+
+	topic, err := notifier.NormaliseTopic(r.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupID, ok := notifier.GroupIDFromTopic(topic); ok {
+		groupAccess, err := s.hasRequesterGroupAccess(ctx, r.UserId, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if !groupAccess.IsAccessible {
+			return nil, group.ErrInsufficientPermissions
+		}
+	}
+
+	response, err := s.NotifierService.SubscribeToTopic(ctx, r.SubscribeToTopicRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubscribeNotificationTopicResponse{SubscribeToTopicResponse: response}, nil
+}
+
+// UnsubscribeNotificationTopic removes the current user's subscription to
+// a notification topic. Unsubscribing never requires group access so users
+// can always opt out, even after leaving a group.
+func (s *Service) UnsubscribeNotificationTopic(ctx context.Context, r *UnsubscribeNotificationTopicRequest) error {
+	if err := s.ensureNotifierService(); err != nil {
+		return err
+	}
+
+	return s.NotifierService.UnsubscribeFromTopic(ctx, r.UnsubscribeFromTopicRequest)
+}
+
+// ListNotificationTopics returns every notification topic the current user
+// is subscribed to.
+func (s *Service) ListNotificationTopics(ctx context.Context, r *ListNotificationTopicsRequest) (*ListNotificationTopicsResponse, error) {
+	if err := s.ensureNotifierService(); err != nil {
+		return nil, err
+	}
+
+	response, err := s.NotifierService.ListTopicSubscriptions(ctx, r.ListTopicSubscriptionsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListNotificationTopicsResponse{ListTopicSubscriptionsResponse: response}, nil
+}
+
+// NotificationSegmentResolver returns a notifier.SegmentResolver backed by
+// the UMS user and group services, so admins can target notification
+// dispatches by role, group membership, user type, or extension value.
+//
+// Wire it into the notifier service after both services are created:
+//
+//	notifierService.WithSegmentResolver(umsService.NotificationSegmentResolver())
+func (s *Service) NotificationSegmentResolver() notifier.SegmentResolver {
+	return &notificationSegmentResolver{service: s}
+}
+
+// notificationSegmentResolver pages through active users matching a
+// notification segment. Users are ordered by ID and its cursor is the last
+// user ID returned, so users changing status or role while a dispatch is
+// running never shift a resumed batch.
+type notificationSegmentResolver struct {
+	service *Service
+}
+
+// ResolveSegmentUserIDs returns one page of active user IDs matching the
+// segment. Group IDs are expanded to their accepted user members first and
+// then combined with the other filters.
+func (r *notificationSegmentResolver) ResolveSegmentUserIDs(ctx context.Context, segment *notifier.NotificationSegment, cursor string, limit int) ([]string, string, error) {
+	logger := logger.AcquireOperationFrom(ctx, "external/usermanager", "resolve-notification-segment")
+
+	if r.service.UserService == nil {
+		logger.Error("user-service-not-enabled")
+		return nil, "", notifier.ErrSegmentResolverNotConfigured
+	}
+
+	var memberIDs []string
+	if len(segment.GroupIDs) > 0 {
+		var err error
+		memberIDs, err = r.resolveGroupMemberIDs(ctx, segment.GroupIDs)
+		if err != nil {
+			logger.Error("failed-to-resolve-notification-segment-group-members", zap.Strings("group-ids", segment.GroupIDs), zap.Error(err))
+			return nil, "", err
+		}
+		if len(memberIDs) == 0 {
+			return []string{}, "", nil
+		}
+	}
+
+	usersResponse, err := r.service.UserService.GetUsers(ctx, &userv2.GetUsersRequest{
+		Page:           1,
+		PerPage:        limit,
+		Order:          userv2.GetUserOrderIDAsc,
+		AfterID:        cursor,
+		StatusFilter:   userv2.AccountStatusKeyActive,
+		IDsFilter:      memberIDs,
+		RolesFilter:    segment.Roles,
+		TypesFilter:    segment.UserTypes,
+		ExtensionKey:   strings.TrimSpace(segment.ExtensionKey),
+		ExtensionValue: segment.ExtensionValue,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-notification-segment-users", zap.String("cursor", cursor), zap.Error(err))
+		return nil, "", err
+	}
+
+	userIDs := make([]string, 0, len(usersResponse.Users))
+	for _, user := range usersResponse.Users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	// a full batch may have more users after it, the user service caps the batch
+	// size so it is read back from the response
+	nextCursor := ""
+	if len(userIDs) > 0 && usersResponse.Meta != nil && len(userIDs) >= usersResponse.Meta.PerPage {
+		nextCursor = userIDs[len(userIDs)-1]
+	}
+	return userIDs, nextCursor, nil
+}
+
+// resolveGroupMemberIDs returns the sorted, de-duplicated IDs of users who
+// have accepted membership of any of the given groups.
+func (r *notificationSegmentResolver) resolveGroupMemberIDs(ctx context.Context, groupIDs []string) ([]string, error) {
+	if r.service.GroupService == nil {
+		return nil, ErrGroupServiceNotEnabled
+	}
+
+	seen := map[string]bool{}
+	memberIDs := []string{}
+	for _, groupID := range groupIDs {
+		membersResponse, err := r.service.GroupService.GetGroupMembers(ctx, &group.GetGroupMembersRequest{
+			GroupID:    groupID,
+			MemberType: group.MemberTypeUser,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range membersResponse.Members {
+			if member.InvitationState == group.MemberInvitationStateInvited || seen[member.ID] {
+				continue
+			}
+			seen[member.ID] = true
+			memberIDs = append(memberIDs, member.ID)
+		}
+	}
+	sort.Strings(memberIDs)
+	return memberIDs, nil
+}
+
 func (s *Service) wrapNotificationPreferences(ctx context.Context, preferences *notifier.NotificationPreferences, includeUser bool) *NotificationPreferencesWithUser {
 	if preferences == nil {
 		return nil
@@ -0,0 +1,135 @@
+package usermanager_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/audit"
+	"github.com/ooaklee/ghatd/external/group"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"github.com/ooaklee/ghatd/external/usermanager"
+)
+
+// mockActivityGroupService serves a fixed activity feed, embedding the interface so
+// only the methods used by the activity feed need implementing
+type mockActivityGroupService struct {
+	usermanager.GroupService
+	viewers     map[string]bool
+	activities  []group.GroupActivity
+	lastRequest *group.GetGroupActivityRequest
+}
+
+func (m *mockActivityGroupService) Can(ctx context.Context, userID, groupID, permission string) (bool, error) {
+	return permission == group.PermissionActivityView && m.viewers[userID], nil
+}
+
+func (m *mockActivityGroupService) GetGroupActivity(ctx context.Context, req *group.GetGroupActivityRequest) (*group.GetGroupActivityResponse, error) {
+	m.lastRequest = req
+	return &group.GetGroupActivityResponse{Activities: m.activities, NextCursor: "next"}, nil
+}
+
+// mockActivityUserService resolves users by ID, embedding the interface so only the
+// lookups used by the activity feed need implementing
+type mockActivityUserService struct {
+	usermanager.UserService
+	users map[string]userv2.UniversalUser
+}
+
+func (m *mockActivityUserService) GetUserByID(ctx context.Context, r *userv2.GetUserByIDRequest) (*userv2.GetUserByIDResponse, error) {
+	user, ok := m.users[r.ID]
+	if !ok {
+		return nil, userv2.ErrUserNotFound
+	}
+	return &userv2.GetUserByIDResponse{User: &user}, nil
+}
+
+func (m *mockActivityUserService) GetUsers(ctx context.Context, r *userv2.GetUsersRequest) (*userv2.GetUsersResponse, error) {
+	users := []userv2.UniversalUser{}
+	for _, id := range r.IDsFilter {
+		if user, ok := m.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return &userv2.GetUsersResponse{Users: users}, nil
+}
+
+func newActivityTestService(groupService *mockActivityGroupService) *usermanager.Service {
+	return (&usermanager.Service{
+		UserService: &mockActivityUserService{
+			users: map[string]userv2.UniversalUser{
+				"owner-1":  {ID: "owner-1", PersonalInfo: &userv2.PersonalInfo{FullName: "Ada Lovelace"}},
+				"member-1": {ID: "member-1", PersonalInfo: &userv2.PersonalInfo{FirstName: "Grace", LastName: "Hopper"}},
+			},
+		},
+	}).WithGroupService(groupService)
+}
+
+func TestServiceGetGroupActivityEnrichesActivities(t *testing.T) {
+	groupService := &mockActivityGroupService{
+		viewers: map[string]bool{"owner-1": true},
+		activities: []group.GroupActivity{
+			{
+				Action:    "group.member.role_updated",
+				GroupName: "acme",
+				ActorID:   "owner-1",
+				SubjectID: "member-1",
+				Details:   map[string]interface{}{"member": map[string]interface{}{"id": "member-1", "role": "ADMIN"}},
+			},
+			{
+				Action:    "group.member.invited",
+				GroupName: "acme",
+				ActorID:   "member-1",
+				SubjectID: "new@example.com",
+			},
+			{
+				Action:    "group.member.invite.expired",
+				GroupName: "acme",
+				ActorID:   audit.AuditActorIdSystem,
+				SubjectID: "new@example.com",
+			},
+		},
+	}
+	svc := newActivityTestService(groupService)
+
+	response, err := svc.GetGroupActivity(context.Background(), &usermanager.GetGroupActivityRequest{
+		UserId:  "owner-1",
+		GroupID: "org-1",
+		Actions: "group.member.invited, group.member.role_updated,",
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Activities, 3)
+	assert.Equal(t, "next", response.NextCursor)
+	assert.Equal(t, []string{"group.member.invited", "group.member.role_updated"}, groupService.lastRequest.Actions)
+
+	roleUpdated := response.Activities[0]
+	require.NotNil(t, roleUpdated.Actor)
+	require.NotNil(t, roleUpdated.Subject)
+	assert.Equal(t, "Ada Lovelace", roleUpdated.Actor.FullName)
+	assert.Equal(t, "Grace Hopper", roleUpdated.Subject.FullName)
+	assert.Equal(t, "Ada Lovelace changed the role of Grace Hopper in acme to ADMIN", roleUpdated.Summary)
+
+	invited := response.Activities[1]
+	assert.Nil(t, invited.Subject)
+	assert.Equal(t, "Grace Hopper invited new@example.com to acme", invited.Summary)
+
+	expired := response.Activities[2]
+	assert.Nil(t, expired.Actor)
+	assert.Equal(t, "The invitation for new@example.com to acme expired", expired.Summary)
+}
+
+func TestServiceGetGroupActivityRequiresActivityViewPermission(t *testing.T) {
+	groupService := &mockActivityGroupService{viewers: map[string]bool{}}
+	svc := newActivityTestService(groupService)
+
+	_, err := svc.GetGroupActivity(context.Background(), &usermanager.GetGroupActivityRequest{UserId: "member-1", GroupID: "org-1"})
+	assert.ErrorIs(t, err, group.ErrInsufficientPermissions)
+	assert.Nil(t, groupService.lastRequest)
+}
+
+func TestServiceGetGroupActivityRequiresGroupService(t *testing.T) {
+	_, err := (&usermanager.Service{}).GetGroupActivity(context.Background(), &usermanager.GetGroupActivityRequest{UserId: "owner-1", GroupID: "org-1"})
+	assert.ErrorIs(t, err, usermanager.ErrGroupServiceNotEnabled)
+}
@@ -0,0 +1,135 @@
+package usermanager_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/billing"
+	"github.com/ooaklee/ghatd/external/group"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"github.com/ooaklee/ghatd/external/usermanager"
+)
+
+// mockBillingGroupService serves a fixed set of groups, embedding the interface so
+// only the methods used by group billing need implementing
+type mockBillingGroupService struct {
+	usermanager.GroupService
+	groups map[string]*group.UniversalGroup
+}
+
+func (m *mockBillingGroupService) GetGroupByID(ctx context.Context, r *group.GetGroupByIDRequest) (*group.GetGroupByIDResponse, error) {
+	grp, ok := m.groups[r.ID]
+	if !ok {
+		return nil, group.ErrResourceNotFound
+	}
+	return &group.GetGroupByIDResponse{Group: grp}, nil
+}
+
+func (m *mockBillingGroupService) GetUserGroupAccessMap(ctx context.Context, userID string) (map[string]group.UserGroupAccessSummary, error) {
+	accessMap := map[string]group.UserGroupAccessSummary{}
+	for _, grp := range m.groups {
+		if grp.OwnerID == userID || grp.HasMember(userID) {
+			accessMap[grp.ID] = group.UserGroupAccessSummary{IsAccessible: true}
+		}
+	}
+	return accessMap, nil
+}
+
+func (m *mockBillingGroupService) Can(ctx context.Context, userID, groupID, permission string) (bool, error) {
+	grp, ok := m.groups[groupID]
+	if !ok {
+		return false, group.ErrResourceNotFound
+	}
+
+	member, err := grp.GetMemberByID(userID)
+	if err != nil {
+		return false, nil
+	}
+
+	return permission == group.PermissionBillingManage && member.Role == group.MemberRoleBillingAdmin, nil
+}
+
+func newGroupBillingTestService(t *testing.T) *usermanager.Service {
+	t.Helper()
+
+	store := &billing.InMemoryRepositoryStore{
+		Subscriptions: map[string]*billing.Subscription{
+			"sub-1": {ID: "sub-1", GroupID: "group-1", Status: billing.StatusActive, Quantity: 5, CreatedAt: "2026-01-01T00:00:00Z"},
+			"sub-2": {ID: "sub-2", GroupID: "group-1", Status: billing.StatusCancelled, Quantity: 10, CreatedAt: "2025-01-01T00:00:00Z"},
+			"sub-3": {ID: "sub-3", UserID: "owner-1", Status: billing.StatusActive, CreatedAt: "2026-01-01T00:00:00Z"},
+		},
+		Events: map[string]*billing.BillingEvent{},
+	}
+	repository := billing.NewInMemoryRepository(store)
+
+	return (&usermanager.Service{
+		UserService: &mockReminderUserService{
+			users: map[string]*userv2.UniversalUser{
+				"owner-1":  {ID: "owner-1"},
+				"member-1": {ID: "member-1"},
+			},
+		},
+	}).WithGroupService(&mockBillingGroupService{
+		groups: map[string]*group.UniversalGroup{
+			"group-1": {
+				ID:       "group-1",
+				OwnerID:  "owner-1",
+				Settings: &group.GroupSettings{MaxMembers: 5},
+				Members: []group.Member{
+					{ID: "owner-1", Type: group.MemberTypeUser, Role: group.MemberRoleOwner},
+					{ID: "member-1", Type: group.MemberTypeUser, Role: group.MemberRoleMember},
+					{ID: "billing-1", Type: group.MemberTypeUser, Role: group.MemberRoleBillingAdmin},
+				},
+			},
+		},
+	}).WithBillingService(billing.NewService(repository, repository))
+}
+
+func TestServiceGetGroupBilling(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                  string
+		userID                string
+		expectedErr           error
+		expectedCanManage     bool
+		expectedSubscriptions int
+	}{
+		{name: "Success - owner sees group subscriptions", userID: "owner-1", expectedCanManage: true, expectedSubscriptions: 2},
+		{name: "Success - billing admin sees group subscriptions", userID: "billing-1", expectedCanManage: true, expectedSubscriptions: 2},
+		{name: "Success - member only sees seats", userID: "member-1"},
+		{name: "Failure - outsider cannot see group billing", userID: "outsider-1", expectedErr: usermanager.ErrGroupNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := newGroupBillingTestService(t)
+
+			res, err := svc.GetGroupBilling(context.Background(), &usermanager.GetGroupBillingRequest{
+				UserId:  test.userID,
+				GroupID: "group-1",
+			})
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedCanManage, res.Billing.CanManageBilling)
+			assert.Len(t, res.Billing.Subscriptions, test.expectedSubscriptions)
+			assert.Equal(t, usermanager.GroupBillingSeats{Purchased: 5, Used: 3, Available: 2, Limit: 5}, res.Billing.Seats)
+		})
+	}
+}
+
+func TestServiceGetGroupBillingRequiresBillingService(t *testing.T) {
+	t.Parallel()
+
+	svc := (&usermanager.Service{}).WithGroupService(&mockBillingGroupService{})
+
+	_, err := svc.GetGroupBilling(context.Background(), &usermanager.GetGroupBillingRequest{UserId: "owner-1", GroupID: "group-1"})
+	assert.ErrorIs(t, err, usermanager.ErrBillingServiceNotEnabled)
+}
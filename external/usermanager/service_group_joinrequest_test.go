@@ -0,0 +1,141 @@
+package usermanager_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/group"
+	"github.com/ooaklee/ghatd/external/notifier"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"github.com/ooaklee/ghatd/external/usermanager"
+)
+
+// mockJoinRequestGroupService records review requests, embedding the interface so
+// only the methods used by join request review need implementing
+type mockJoinRequestGroupService struct {
+	usermanager.GroupService
+	reviews []*group.ReviewJoinRequestRequest
+	err     error
+}
+
+func (m *mockJoinRequestGroupService) review(req *group.ReviewJoinRequestRequest, status string) (*group.ReviewJoinRequestResponse, error) {
+	m.reviews = append(m.reviews, req)
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &group.ReviewJoinRequestResponse{
+		JoinRequest: &group.GroupJoinRequest{
+			ID:             req.JoinRequestID,
+			GroupID:        req.GroupID,
+			UserID:         "requester-1",
+			Status:         status,
+			DecisionReason: req.Reason,
+			ReviewedByID:   req.ReviewerID,
+		},
+		Group: &group.UniversalGroup{ID: req.GroupID, Name: "Engineering"},
+	}, nil
+}
+
+func (m *mockJoinRequestGroupService) ApproveJoinRequest(ctx context.Context, req *group.ReviewJoinRequestRequest) (*group.ReviewJoinRequestResponse, error) {
+	return m.review(req, group.JoinRequestStatusApproved)
+}
+
+func (m *mockJoinRequestGroupService) DenyJoinRequest(ctx context.Context, req *group.ReviewJoinRequestRequest) (*group.ReviewJoinRequestResponse, error) {
+	return m.review(req, group.JoinRequestStatusDenied)
+}
+
+// mockJoinRequestNotifierService records notifications sent to users
+type mockJoinRequestNotifierService struct {
+	usermanager.NotifierService
+	notifications []*notifier.NotifyUserRequest
+}
+
+func (m *mockJoinRequestNotifierService) NotifyUser(ctx context.Context, r *notifier.NotifyUserRequest) (*notifier.NotifyUserResponse, error) {
+	m.notifications = append(m.notifications, r)
+	return &notifier.NotifyUserResponse{}, nil
+}
+
+func newJoinRequestTestService(groupService *mockJoinRequestGroupService, notifierService *mockJoinRequestNotifierService) *usermanager.Service {
+	return (&usermanager.Service{
+		UserService: &mockReminderUserService{
+			users: map[string]*userv2.UniversalUser{
+				"platform-admin": {ID: "platform-admin", Roles: []string{"ADMIN"}},
+				"group-admin":    {ID: "group-admin"},
+			},
+		},
+	}).WithGroupService(groupService).WithNotifierService(notifierService)
+}
+
+func TestServiceReviewGroupJoinRequestNotifiesRequester(t *testing.T) {
+	tests := []struct {
+		name                    string
+		reviewerID              string
+		deny                    bool
+		expectedTitle           string
+		expectedMessage         string
+		expectedReviewerIsAdmin bool
+	}{
+		{
+			name:            "group admin approves",
+			reviewerID:      "group-admin",
+			expectedTitle:   "Join request approved",
+			expectedMessage: "Your request to join Engineering has been approved.",
+		},
+		{
+			name:                    "platform admin denies with a reason",
+			reviewerID:              "platform-admin",
+			deny:                    true,
+			expectedTitle:           "Join request declined",
+			expectedMessage:         "Your request to join Engineering has been declined. Reason: Invite only",
+			expectedReviewerIsAdmin: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groupService := &mockJoinRequestGroupService{}
+			notifierService := &mockJoinRequestNotifierService{}
+			svc := newJoinRequestTestService(groupService, notifierService)
+
+			request := &usermanager.ReviewGroupJoinRequestRequest{
+				UserId:                   tt.reviewerID,
+				ReviewJoinRequestRequest: &group.ReviewJoinRequestRequest{GroupID: "group-1", JoinRequestID: "jr-1", Reason: "Invite only"},
+			}
+
+			var err error
+			if tt.deny {
+				_, err = svc.DenyGroupJoinRequest(context.Background(), request)
+			} else {
+				_, err = svc.ApproveGroupJoinRequest(context.Background(), request)
+			}
+			require.NoError(t, err)
+
+			require.Len(t, groupService.reviews, 1)
+			assert.Equal(t, tt.reviewerID, groupService.reviews[0].ReviewerID)
+			assert.Equal(t, tt.expectedReviewerIsAdmin, groupService.reviews[0].ReviewerIsPlatformAdmin)
+
+			require.Len(t, notifierService.notifications, 1)
+			assert.Equal(t, "requester-1", notifierService.notifications[0].UserID)
+			assert.Equal(t, tt.expectedTitle, notifierService.notifications[0].Title)
+			assert.Equal(t, tt.expectedMessage, notifierService.notifications[0].Message)
+		})
+	}
+}
+
+func TestServiceReviewGroupJoinRequestDoesNotNotifyOnFailure(t *testing.T) {
+	groupService := &mockJoinRequestGroupService{err: group.ErrInsufficientPermissions}
+	notifierService := &mockJoinRequestNotifierService{}
+	svc := newJoinRequestTestService(groupService, notifierService)
+
+	_, err := svc.ApproveGroupJoinRequest(context.Background(), &usermanager.ReviewGroupJoinRequestRequest{
+		UserId:                   "group-admin",
+		ReviewJoinRequestRequest: &group.ReviewJoinRequestRequest{GroupID: "group-1", JoinRequestID: "jr-1"},
+	})
+	assert.True(t, errors.Is(err, group.ErrInsufficientPermissions))
+	assert.Empty(t, notifierService.notifications)
+}
@@ -0,0 +1,170 @@
+package usermanager_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/group"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"github.com/ooaklee/ghatd/external/usermanager"
+)
+
+// mockMembersGroupService serves a fixed member list and records imports, embedding the
+// interface so only the methods used by member import and export need implementing
+type mockMembersGroupService struct {
+	usermanager.GroupService
+	permissions map[string]string
+	members     []group.Member
+	lastImport  *group.ImportMembersRequest
+}
+
+func (m *mockMembersGroupService) Can(ctx context.Context, userID, groupID, permission string) (bool, error) {
+	return m.permissions[userID] == permission, nil
+}
+
+func (m *mockMembersGroupService) GetGroupMembers(ctx context.Context, req *group.GetGroupMembersRequest) (*group.GetGroupMembersResponse, error) {
+	return &group.GetGroupMembersResponse{Members: m.members, Count: len(m.members)}, nil
+}
+
+func (m *mockMembersGroupService) ImportMembers(ctx context.Context, req *group.ImportMembersRequest) (*group.ImportMembersResponse, error) {
+	m.lastImport = req
+	return &group.ImportMembersResponse{Report: &group.MemberImportReport{GroupID: req.GroupID, Total: len(req.Entries)}}, nil
+}
+
+// mockMembersUserService resolves users by ID and email, embedding the interface so only
+// the lookups used by member import and export need implementing
+type mockMembersUserService struct {
+	usermanager.UserService
+	users map[string]userv2.UniversalUser
+}
+
+func (m *mockMembersUserService) GetUserByID(ctx context.Context, r *userv2.GetUserByIDRequest) (*userv2.GetUserByIDResponse, error) {
+	user, ok := m.users[r.ID]
+	if !ok {
+		return nil, userv2.ErrUserNotFound
+	}
+	return &userv2.GetUserByIDResponse{User: &user}, nil
+}
+
+func (m *mockMembersUserService) GetUserByEmail(ctx context.Context, r *userv2.GetUserByEmailRequest) (*userv2.GetUserByEmailResponse, error) {
+	for _, user := range m.users {
+		if user.Email == r.Email {
+			return &userv2.GetUserByEmailResponse{User: &user}, nil
+		}
+	}
+	return nil, userv2.ErrUserNotFound
+}
+
+func (m *mockMembersUserService) GetUsers(ctx context.Context, r *userv2.GetUsersRequest) (*userv2.GetUsersResponse, error) {
+	users := []userv2.UniversalUser{}
+	for _, id := range r.IDsFilter {
+		if user, ok := m.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return &userv2.GetUsersResponse{Users: users}, nil
+}
+
+func newMembersTestService(groupService *mockMembersGroupService) *usermanager.Service {
+	return (&usermanager.Service{
+		UserService: &mockMembersUserService{
+			users: map[string]userv2.UniversalUser{
+				"owner-1":  {ID: "owner-1", Email: "ada@example.com", PersonalInfo: &userv2.PersonalInfo{FullName: "Ada Lovelace"}},
+				"member-1": {ID: "member-1", Email: "grace@example.com", PersonalInfo: &userv2.PersonalInfo{FirstName: "Grace", LastName: "Hopper"}},
+			},
+		},
+	}).WithGroupService(groupService)
+}
+
+func TestServiceImportGroupMembers(t *testing.T) {
+	groupService := &mockMembersGroupService{
+		permissions: map[string]string{"owner-1": group.PermissionMembersManage, "member-1": group.PermissionMembersView},
+	}
+	svc := newMembersTestService(groupService)
+
+	_, err := svc.ImportGroupMembers(context.Background(), &usermanager.ImportGroupMembersRequest{
+		UserId:               "member-1",
+		ImportMembersRequest: &group.ImportMembersRequest{GroupID: "org-1", Entries: []group.MemberImportEntry{{Email: "new@example.com"}}},
+	})
+	assert.ErrorIs(t, err, group.ErrInsufficientPermissions)
+	assert.Nil(t, groupService.lastImport)
+
+	response, err := svc.ImportGroupMembers(context.Background(), &usermanager.ImportGroupMembersRequest{
+		UserId:               "owner-1",
+		ImportMembersRequest: &group.ImportMembersRequest{GroupID: "org-1", DryRun: true, Entries: []group.MemberImportEntry{{Email: "new@example.com"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Report.Total)
+	require.NotNil(t, groupService.lastImport)
+	assert.Equal(t, "owner-1", groupService.lastImport.ActorID)
+	assert.True(t, groupService.lastImport.DryRun)
+}
+
+func TestServiceExportGroupMembers(t *testing.T) {
+	groupService := &mockMembersGroupService{
+		permissions: map[string]string{"member-1": group.PermissionMembersView},
+		members: []group.Member{
+			{ID: "owner-1", Type: group.MemberTypeUser, Role: group.MemberRoleOwner, JoinedAt: "2026-01-02T00:00:00Z"},
+			{ID: "member-1", Type: group.MemberTypeUser, Role: group.MemberRoleMember, JoinedAt: "2026-01-03T00:00:00Z"},
+			{ID: "new@example.com", Type: group.MemberTypeUser, Role: group.MemberRoleAdmin, InvitedAt: "2026-01-04T00:00:00Z", InvitationState: group.MemberInvitationStateInvited},
+		},
+	}
+	svc := newMembersTestService(groupService)
+
+	t.Run("csv", func(t *testing.T) {
+		response, err := svc.ExportGroupMembers(context.Background(), &usermanager.ExportGroupMembersRequest{UserId: "member-1", GroupID: "org-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "text/csv", response.ContentType)
+		assert.Equal(t, "group-members-org-1.csv", response.FileName)
+		assert.Equal(t, ""+
+			"email,role,full_name,id,type,joined_at,invited_at,invitation_state\n"+
+			"ada@example.com,OWNER,Ada Lovelace,owner-1,USER,2026-01-02T00:00:00Z,,\n"+
+			"grace@example.com,MEMBER,Grace Hopper,member-1,USER,2026-01-03T00:00:00Z,,\n"+
+			"new@example.com,ADMIN,,new@example.com,USER,,2026-01-04T00:00:00Z,INVITED\n",
+			string(response.Content))
+
+		// the export can be uploaded again as an import
+		entries, err := group.ParseMemberImportEntries(group.MemberImportFormatCSV, bytes.NewReader(response.Content))
+		require.NoError(t, err)
+		assert.Equal(t, group.MemberImportEntry{Email: "grace@example.com", Role: group.MemberRoleMember}, entries[1])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		response, err := svc.ExportGroupMembers(context.Background(), &usermanager.ExportGroupMembersRequest{UserId: "member-1", GroupID: "org-1", Format: "JSON"})
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", response.ContentType)
+
+		exports := []usermanager.GroupMemberExport{}
+		require.NoError(t, json.Unmarshal(response.Content, &exports))
+		require.Len(t, exports, 3)
+		assert.Equal(t, "Grace Hopper", exports[1].FullName)
+		assert.Equal(t, "new@example.com", exports[2].Email)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := svc.ExportGroupMembers(context.Background(), &usermanager.ExportGroupMembersRequest{UserId: "member-1", GroupID: "org-1", Format: "xlsx"})
+		assert.ErrorIs(t, err, usermanager.ErrRequestFailedValidation)
+	})
+
+	t.Run("requires members.view", func(t *testing.T) {
+		_, err := svc.ExportGroupMembers(context.Background(), &usermanager.ExportGroupMembersRequest{UserId: "someone-else", GroupID: "org-1"})
+		assert.ErrorIs(t, err, group.ErrInsufficientPermissions)
+	})
+}
+
+func TestServiceMemberImportUserFinder(t *testing.T) {
+	finder := newMembersTestService(&mockMembersGroupService{}).MemberImportUserFinder()
+
+	userID, err := finder.FindUserIDByEmail(context.Background(), "grace@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "member-1", userID)
+
+	userID, err = finder.FindUserIDByEmail(context.Background(), "nobody@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, userID)
+}
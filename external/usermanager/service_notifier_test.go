@@ -0,0 +1,74 @@
+package usermanager_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooaklee/ghatd/external/notifier"
+	userv2 "github.com/ooaklee/ghatd/external/user/v2"
+	"github.com/ooaklee/ghatd/external/usermanager"
+)
+
+// mockSegmentUserService serves active users ordered by ID after the keyset cursor,
+// embedding the interface so only GetUsers needs implementing
+type mockSegmentUserService struct {
+	usermanager.UserService
+	users    map[string]string
+	requests []userv2.GetUsersRequest
+}
+
+func (m *mockSegmentUserService) GetUsers(ctx context.Context, r *userv2.GetUsersRequest) (*userv2.GetUsersResponse, error) {
+	m.requests = append(m.requests, *r)
+
+	ids := []string{}
+	for id, status := range m.users {
+		if status == r.StatusFilter && id > r.AfterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	users := []userv2.UniversalUser{}
+	for _, id := range ids[:min(r.PerPage, len(ids))] {
+		users = append(users, userv2.UniversalUser{ID: id})
+	}
+	return &userv2.GetUsersResponse{Users: users, Meta: &userv2.PaginationMetadata{Page: 1, PerPage: r.PerPage}}, nil
+}
+
+func TestNotificationSegmentResolverPagesByUserID(t *testing.T) {
+	userService := &mockSegmentUserService{users: map[string]string{
+		"u1": userv2.AccountStatusKeyActive,
+		"u2": userv2.AccountStatusKeyActive,
+		"u3": userv2.AccountStatusKeyActive,
+		"u4": userv2.AccountStatusKeyActive,
+	}}
+	resolver := (&usermanager.Service{UserService: userService}).NotificationSegmentResolver()
+	segment := &notifier.NotificationSegment{Roles: []string{"READER"}}
+
+	userIDs, cursor, err := resolver.ResolveSegmentUserIDs(context.Background(), segment, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, userIDs)
+	assert.Equal(t, "u2", cursor)
+
+	// a user leaving the segment between batches does not shift the next one
+	userService.users["u1"] = userv2.AccountStatusKeyDeactivated
+
+	userIDs, cursor, err = resolver.ResolveSegmentUserIDs(context.Background(), segment, cursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u4"}, userIDs)
+
+	userIDs, cursor, err = resolver.ResolveSegmentUserIDs(context.Background(), segment, cursor, 2)
+	require.NoError(t, err)
+	assert.Empty(t, userIDs)
+	assert.Empty(t, cursor)
+
+	for _, request := range userService.requests {
+		assert.Equal(t, userv2.GetUserOrderIDAsc, request.Order)
+		assert.Equal(t, 1, request.Page)
+		assert.Equal(t, []string{"READER"}, request.RolesFilter)
+	}
+}